@@ -20,6 +20,9 @@ type ChatResult struct {
 	Answer   string // Final answer (if complete)
 	AskCommander  string // Question for commander (if agent needs input)
 	Complete bool   // True if task is done
+	Handoff  *Handoff // Set when the agent handed its task to another agent
+	Output   map[string]any // Submitted structured output (agents with an output schema)
+	HandedOffTo string // Agent that produced this result after a handoff (set by AgentManager)
 }
 
 // Agent represents a fully initialized agent ready to chat
@@ -35,24 +38,32 @@ type Agent struct {
 	resultStore    *aitools.MemoryResultStore
 	interceptor    *aitools.ResultInterceptor
 	pruningManager *llm.PruningManager
-	compaction     *CompactionConfig // Compaction settings (nil if disabled)
+	summaryProvider llm.Provider     // Compaction summary provider we created and must close (nil if none)
 	eventLogger    EventLogger
 	turnLogger     *llm.TurnLogger   // Persists across Chat() calls for consistent turn numbering
 	secretInfos    []SecretInfo      // Secret names and descriptions (for prompts)
 	secretValues   map[string]string // Actual secret values (for tool call injection)
+	redactor       *llm.Redactor     // Scrubs secrets from debug output
 	onCompaction   func(inputTokens int, tokenLimit int, messagesCompacted int, turnRetention int)
 	onSessionTurn  func(protocol.SessionTurnData)
+	onRetry        func(llm.RetryEvent)
+	onParserRecovery func(ParserRecoveryEvent)
 	sessionLogger    SessionLogger    // Optional session logger for tool result auditing
 	sessionID        string           // Session ID for tool result auditing
 	taskID           string           // Task ID for tool result auditing
 	pricingOverrides map[string]*llm.ModelPricing
 	budget           BudgetChecker
+	toolEnv          *aitools.ToolEnv // Agent's env / working_dir for plugin tool calls (nil if unset)
+	summarizeBetweenTasks bool       // Summarize the conversation when a new task arrives
+	submitOutput     *aitools.SubmitOutputTool // Set when the agent has an output schema (mission mode)
+	outputMark       int                       // Outputs submitted before the current task
 }
 
 // CompactionConfig holds settings for context compaction
 type CompactionConfig struct {
 	TokenLimit    int // Trigger compaction when input tokens exceed this threshold
 	TurnRetention int // Keep this many recent turns uncompacted
+	SummaryModel  string // Model key that writes the summary (empty = built-in digest)
 }
 
 // Options for creating an agent
@@ -63,6 +74,12 @@ type Options struct {
 	Config *config.Config
 	// AgentName is the name of the agent to load
 	AgentName string
+	// Model overrides the agent's configured model key (optional, from a
+	// task's models block)
+	Model string
+	// Sampling tunes the agent's LLM calls over its model's sampling
+	// settings (optional, from a task's sampling block)
+	Sampling *config.Sampling
 	// Mode overrides the agent's configured mode (optional)
 	Mode *config.AgentMode
 	// DebugFile enables debug logging to the specified file (optional)
@@ -79,15 +96,28 @@ type Options struct {
 	SecretValues map[string]string
 	// MemoryStore provides file memory access for the mission (optional)
 	MemoryStore aitools.MemoryStore
+	// Knowledge is the mission's semantic memory for memory_put / memory_search (optional)
+	Knowledge aitools.KnowledgeBase
+	// Artifacts stores the mission's files for artifact_put / artifact_get (optional)
+	Artifacts aitools.Artifacts
 	// OnCompaction is called when context compaction occurs (optional, mission context only)
 	OnCompaction func(inputTokens int, tokenLimit int, messagesCompacted int, turnRetention int)
 	// OnSessionTurn is called after each LLM turn with telemetry data (optional)
 	OnSessionTurn func(data protocol.SessionTurnData)
+	// OnRetry is called before a failed LLM call is retried (optional)
+	OnRetry func(ev llm.RetryEvent)
+	// OnParserRecovery is called when a mission-mode agent is re-prompted
+	// after a response with no tool call and no answer (optional)
+	OnParserRecovery func(ev ParserRecoveryEvent)
 	// PricingOverrides maps API model names to custom pricing (optional, from config)
 	PricingOverrides map[string]*llm.ModelPricing
 	// Budget is an optional per-task budget checker shared with the commander so that
 	// agent turns contribute to the same cumulative totals.
 	Budget BudgetChecker
+	// CallLimiter caps concurrent LLM calls across the mission (optional)
+	CallLimiter *llm.CallLimiter
+	// ResponseCache replays responses to identical LLM requests (optional)
+	ResponseCache llm.ResponseCache
 	// AgentConfig is a pre-resolved agent config (optional, used for mission-scoped agents)
 	AgentConfig *config.Agent
 	// Provider is an optional pre-created LLM provider. When set, agent creation
@@ -98,6 +128,12 @@ type Options struct {
 	// tool is still registered but returns "[no human available]" instead of
 	// blocking (e.g. standalone squadron with no commander attached).
 	HumanBridge aitools.HumanInputBridge
+	// ToolFilter narrows the agent's configured tools to what the current
+	// task allows (optional, from tools_allow / tools_deny).
+	ToolFilter *config.ToolFilter
+	// Policies are the mission's tool-call rules, checked before every call
+	// to a configured tool (optional).
+	Policies *config.Policies
 }
 
 // New creates a new agent from config
@@ -130,6 +166,11 @@ func New(ctx context.Context, opts Options) (*Agent, error) {
 	if agentCfg == nil {
 		return nil, fmt.Errorf("agent '%s' not found", opts.AgentName)
 	}
+	if opts.Model != "" && opts.Model != agentCfg.Model {
+		overridden := *agentCfg
+		overridden.Model = opts.Model
+		agentCfg = &overridden
+	}
 
 	// Resolve model from config
 	modelConfig, actualModelName, err := agentCfg.ResolveModel(cfg.Models)
@@ -144,7 +185,7 @@ func New(ctx context.Context, opts Options) (*Agent, error) {
 		provider = opts.Provider
 		ownsProvider = false
 	} else {
-		if modelConfig.Provider != config.ProviderOllama && modelConfig.APIKey == "" {
+		if modelConfig.Provider.RequiresAPIKey() && modelConfig.APIKey == "" {
 			return nil, fmt.Errorf("API key not set for model '%s'", modelConfig.Name)
 		}
 		provider, ownsProvider, err = createProvider(ctx, modelConfig)
@@ -155,12 +196,19 @@ func New(ctx context.Context, opts Options) (*Agent, error) {
 
 	// Build tools map and add sanitized aliases so LLM tool calls
 	// (which use API-safe names like "plugins_shell_echo") resolve correctly
-	tools := config.BuildToolsMap(agentCfg.Tools, cfg.CustomTools, cfg.LoadedPlugins, cfg.LoadedMCPClients, opts.DatasetStore, opts.HumanBridge)
+	redactor := llm.NewRedactor(opts.SecretValues)
+	tools := config.BuildToolsMap(agentCfg.Tools, cfg.CustomTools, cfg.LoadedPlugins, cfg.LoadedMCPClients, cfg.Builtins(opts.SecretValues), opts.DatasetStore, opts.HumanBridge)
+	opts.ToolFilter.Apply(tools)
+	applyToolPolicy(tools, opts.Policies, agentCfg.Name, opts.HumanBridge, redactor)
 	aitools.AddSanitizedAliases(tools)
 
 	// Create result store and interceptor for large results
 	resultStore := aitools.NewMemoryResultStore()
-	resultConfig := aitools.LargeResultConfigWithMaxSize(agentCfg.GetToolResponseMaxBytes())
+	toolNames := make([]string, 0, len(tools))
+	for name := range tools {
+		toolNames = append(toolNames, name)
+	}
+	resultConfig := cfg.ToolResultLimits(agentCfg.ToolResponse, toolNames)
 	interceptor := aitools.NewResultInterceptor(resultStore, resultConfig)
 
 	// Add result tools to agent's tool map
@@ -185,8 +233,18 @@ func New(ctx context.Context, opts Options) (*Agent, error) {
 		tools["file_create"] = &aitools.MemoryCreateTool{Store: opts.MemoryStore}
 		tools["file_delete"] = &aitools.MemoryDeleteTool{Store: opts.MemoryStore}
 		tools["file_search"] = &aitools.MemorySearchTool{Store: opts.MemoryStore}
+		tools["file_glob"] = &aitools.MemoryGlobTool{Store: opts.MemoryStore}
 		tools["file_grep"] = &aitools.MemoryGrepTool{Store: opts.MemoryStore}
 	}
+	if opts.Knowledge != nil {
+		tools["memory_put"] = &aitools.KnowledgePutTool{KB: opts.Knowledge}
+		tools["memory_search"] = &aitools.KnowledgeSearchTool{KB: opts.Knowledge}
+	}
+	if opts.Artifacts != nil {
+		tools["artifact_put"] = &aitools.ArtifactPutTool{Artifacts: opts.Artifacts, Store: opts.MemoryStore}
+		tools["artifact_get"] = &aitools.ArtifactGetTool{Artifacts: opts.Artifacts, Store: opts.MemoryStore}
+		tools["artifact_list"] = &aitools.ArtifactListTool{Artifacts: opts.Artifacts}
+	}
 
 	// Resolve skills and add load_skill tool
 	availableSkills := resolveSkills(agentCfg, cfg)
@@ -197,7 +255,9 @@ func New(ctx context.Context, opts Options) (*Agent, error) {
 			AvailableSkills: availableSkills,
 			AgentTools:      tools,
 			ToolBuilder: func(toolRefs []string) map[string]aitools.Tool {
-				t := config.BuildToolsMap(toolRefs, cfg.CustomTools, cfg.LoadedPlugins, cfg.LoadedMCPClients, opts.DatasetStore, opts.HumanBridge)
+				t := config.BuildToolsMap(toolRefs, cfg.CustomTools, cfg.LoadedPlugins, cfg.LoadedMCPClients, cfg.Builtins(opts.SecretValues), opts.DatasetStore, opts.HumanBridge)
+				opts.ToolFilter.Apply(t)
+				applyToolPolicy(t, opts.Policies, agentCfg.Name, opts.HumanBridge, redactor)
 				aitools.AddSanitizedAliases(t)
 				return t
 			},
@@ -230,11 +290,33 @@ func New(ctx context.Context, opts Options) (*Agent, error) {
 			Description: s.Description,
 		})
 	}
-	systemPrompts = append(systemPrompts, prompts.GetAgentPrompt(mode, promptSecrets, promptSkills))
+	agentPrompt := prompts.GetAgentPrompt(mode, promptSecrets, promptSkills)
+	if agentCfg.PromptTemplate != "" {
+		agentPrompt, err = prompts.RenderAgentTemplate(agentCfg.PromptTemplate, prompts.AgentTemplateData{
+			Default:     agentPrompt,
+			Name:        agentCfg.Name,
+			Personality: agentCfg.Personality,
+			Mode:        string(mode),
+			Secrets:     promptSecrets,
+			Skills:      promptSkills,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("agent '%s' prompt_template: %w", agentCfg.Name, err)
+		}
+	}
+	systemPrompts = append(systemPrompts, agentPrompt)
 	systemPrompts = append(systemPrompts,
 		fmt.Sprintf("Personality: %s", agentCfg.Personality),
 	)
 
+	// A structured output schema applies to the agent's answers to commanders
+	var submitOutput *aitools.SubmitOutputTool
+	if outputSchema := OutputFieldsFromConfig(agentCfg.Output); len(outputSchema) > 0 && mode == config.ModeMission {
+		submitOutput = newSubmitOutputTool(outputSchema, opts.Artifacts)
+		tools["submit_output"] = submitOutput
+		systemPrompts = append(systemPrompts, agentOutputPrompt(outputSchema))
+	}
+
 	// Add dataset info if running in mission context
 	if opts.DatasetStore != nil {
 		if datasetPrompt := formatDatasetInfo(opts.DatasetStore.GetDatasetInfo()); datasetPrompt != "" {
@@ -253,6 +335,9 @@ func New(ctx context.Context, opts Options) (*Agent, error) {
 	session := llm.NewSession(provider, actualModelName, systemPrompts...)
 	conversationCaching := modelConfig.IsPromptCachingEnabled() && (agentCfg.GetPruneOn() == 0 || (agentCfg.GetPruneOn()-agentCfg.GetPruneTo()) >= 3)
 	session.SetPromptCaching(modelConfig.IsPromptCachingEnabled(), conversationCaching)
+	session.SetRetryPolicy(retryPolicy(modelConfig))
+	session.SetContextWindow(modelConfig.ContextWindow(actualModelName))
+	session.SetSampling(SamplingFor(modelConfig, agentCfg.Model, opts.Sampling))
 
 	if agentCfg.Reasoning != "" {
 		if config.ModelSupportsReasoning(modelConfig, actualModelName) {
@@ -270,6 +355,9 @@ func New(ctx context.Context, opts Options) (*Agent, error) {
 		skillMgr.Session = session
 	}
 
+	session.SetCallLimiter(opts.CallLimiter)
+	session.SetResponseCache(opts.ResponseCache)
+	session.SetRedactor(redactor)
 	if opts.DebugFile != "" {
 		if err := session.EnableDebug(opts.DebugFile); err != nil {
 			// Non-fatal, just log warning
@@ -284,24 +372,24 @@ func New(ctx context.Context, opts Options) (*Agent, error) {
 		agentCfg.GetPruneTo(),
 	)
 
-	// Extract compaction settings from config (if present)
-	var compaction *CompactionConfig
-	if agentCfg.Compaction != nil {
-		compaction = &CompactionConfig{
-			TokenLimit:    agentCfg.Compaction.TokenLimit,
-			TurnRetention: agentCfg.Compaction.TurnRetention,
-		}
+	// Compaction: the agent's own block, else the model config's defaults
+	compaction := compactionSettings(compactionFromConfig(agentCfg.Compaction), modelConfig, agentCfg.Model)
+	compactionPol, summaryProvider, err := compactionPolicy(ctx, cfg, compaction, modelConfig, provider, opts.Provider != nil)
+	if err != nil {
+		return nil, err
 	}
+	session.SetCompaction(compactionPol)
 
 	// Create turn logger if path provided (persists across Chat() calls)
 	var turnLogger *llm.TurnLogger
 	if opts.TurnLogFile != "" {
 		if tl, err := llm.NewTurnLogger(opts.TurnLogFile); err == nil {
+			tl.SetRedactor(redactor)
 			turnLogger = tl
 		}
 	}
 
-	return &Agent{
+	a := &Agent{
 		Name:           agentCfg.Name,
 		ModelName:      actualModelName,
 		Mode:           mode,
@@ -312,7 +400,7 @@ func New(ctx context.Context, opts Options) (*Agent, error) {
 		resultStore:    resultStore,
 		interceptor:    interceptor,
 		pruningManager: pruningManager,
-		compaction:     compaction,
+		summaryProvider: summaryProvider,
 		eventLogger:    opts.EventLogger,
 		onCompaction:   opts.OnCompaction,
 		onSessionTurn:    opts.OnSessionTurn,
@@ -320,8 +408,17 @@ func New(ctx context.Context, opts Options) (*Agent, error) {
 		secretInfos:      opts.SecretInfos,
 		pricingOverrides: opts.PricingOverrides,
 		secretValues:   opts.SecretValues,
+		redactor:         redactor,
 		budget:           opts.Budget,
-	}, nil
+		onRetry:          opts.OnRetry,
+		onParserRecovery: opts.OnParserRecovery,
+		toolEnv:          agentCfg.GetToolEnv(),
+		summarizeBetweenTasks: agentCfg.SummarizeBetweenTasks,
+		submitOutput:     submitOutput,
+	}
+	session.SetRetryObserver(a.onProviderRetry)
+	session.SetCompactionObserver(a.onSessionCompaction)
+	return a, nil
 }
 
 // RestoreAgent creates a new agent and loads persisted session messages into it.
@@ -357,8 +454,7 @@ func (a *Agent) NeedsResume() bool {
 // Used when a restored agent needs to pick up where it left off.
 func (a *Agent) Resume(ctx context.Context, streamer streamers.ChatHandler) (ChatResult, error) {
 	sessionAdapter := llm.NewSessionAdapter(a.session)
-	orch := newOrchestrator(sessionAdapter, streamer, a.tools, a.interceptor, a.pruningManager, a.eventLogger, a.turnLogger, a.secretValues, a.compaction)
-	orch.onCompaction = a.onCompaction
+	orch := newOrchestrator(sessionAdapter, streamer, a.tools, a.interceptor, a.pruningManager, a.eventLogger, a.turnLogger, a.secretValues)
 	orch.onSessionTurn = a.onSessionTurn
 	orch.modelName = a.ModelName
 	orch.sessionLogger = a.sessionLogger
@@ -366,7 +462,77 @@ func (a *Agent) Resume(ctx context.Context, streamer streamers.ChatHandler) (Cha
 	orch.taskID = a.taskID
 	orch.pricingOverrides = a.pricingOverrides
 	orch.budget = a.budget
-	return orch.processTurn(ctx,"", true)
+	orch.parserRecovery = a.Mode == config.ModeMission
+	orch.onParserRecovery = a.onParserRecoveryEvent
+	orch.submitOutput = a.submitOutput
+	orch.outputMark = a.outputMark
+	result, err := orch.processTurn(a.withToolEnv(ctx), "", true)
+	a.markOutput(result)
+	return result, err
+}
+
+// markOutput starts a new task's outputs after a completed answer, so the
+// next task needs its own submit_output call.
+func (a *Agent) markOutput(result ChatResult) {
+	if a.submitOutput != nil && result.Complete {
+		a.outputMark = a.submitOutput.ResultCount()
+	}
+}
+
+// withToolEnv attaches the agent's env and working directory to ctx for its
+// tool calls. A task's ToolEnv already on ctx takes precedence.
+func (a *Agent) withToolEnv(ctx context.Context) context.Context {
+	return aitools.WithToolEnv(ctx, a.toolEnv.Merge(aitools.ToolEnvFromContext(ctx)))
+}
+
+// onProviderRetry reports a retried LLM call to the event log and the
+// OnRetry callback.
+func (a *Agent) onProviderRetry(ev llm.RetryEvent) {
+	if a.eventLogger != nil {
+		a.eventLogger.LogEvent("agent_llm_retry", retryEventData(ev))
+	}
+	if a.onRetry != nil {
+		a.onRetry(ev)
+	}
+}
+
+// onParserRecoveryEvent reports a format correction to the event log and
+// the OnParserRecovery callback.
+func (a *Agent) onParserRecoveryEvent(ev ParserRecoveryEvent) {
+	if a.eventLogger != nil {
+		a.eventLogger.LogEvent("parser_recovery", parserRecoveryEventData(ev))
+	}
+	if a.onParserRecovery != nil {
+		a.onParserRecovery(ev)
+	}
+}
+
+// onSessionCompaction reports an automatic compaction to the event log and
+// the OnCompaction callback.
+func (a *Agent) onSessionCompaction(ev llm.CompactionEvent) {
+	if a.onCompaction != nil {
+		a.onCompaction(ev.InputTokens, ev.TokenLimit, ev.MessagesCompacted, ev.TurnRetention)
+	}
+	if a.eventLogger != nil {
+		a.eventLogger.LogEvent("compaction", compactionEventData(ev))
+	}
+}
+
+// summarizeForNewTask replaces the conversation with a summary of the
+// earlier tasks before the agent starts a new one, when the agent sets
+// summarize_between_tasks.
+func (a *Agent) summarizeForNewTask(ctx context.Context) {
+	if !a.summarizeBetweenTasks {
+		return
+	}
+	n, summaryModel := a.session.SummarizeForNewTask(ctx)
+	if n > 0 && a.eventLogger != nil {
+		data := map[string]any{"messages_summarized": n}
+		if summaryModel != "" {
+			data["summary_model"] = summaryModel
+		}
+		a.eventLogger.LogEvent("task_summary", data)
+	}
 }
 
 // EnableDebug sets up debug logging on the agent.
@@ -379,6 +545,7 @@ func (a *Agent) EnableDebug(debugFile, turnLogFile string, eventLogger EventLogg
 	}
 	if turnLogFile != "" {
 		if tl, err := llm.NewTurnLogger(turnLogFile); err == nil {
+			tl.SetRedactor(a.redactor)
 			a.turnLogger = tl
 		}
 	}
@@ -400,14 +567,16 @@ func (a *Agent) Close() {
 			closer.Close()
 		}
 	}
+	if a.summaryProvider != nil {
+		closeProvider(a.summaryProvider)
+	}
 }
 
 // Chat processes a single message and returns a ChatResult
 // The streamer receives real-time updates during processing
 func (a *Agent) Chat(ctx context.Context, input string, streamer streamers.ChatHandler) (ChatResult, error) {
 	sessionAdapter := llm.NewSessionAdapter(a.session)
-	orch := newOrchestrator(sessionAdapter, streamer, a.tools, a.interceptor, a.pruningManager, a.eventLogger, a.turnLogger, a.secretValues, a.compaction)
-	orch.onCompaction = a.onCompaction
+	orch := newOrchestrator(sessionAdapter, streamer, a.tools, a.interceptor, a.pruningManager, a.eventLogger, a.turnLogger, a.secretValues)
 	orch.onSessionTurn = a.onSessionTurn
 	orch.modelName = a.ModelName
 	orch.sessionLogger = a.sessionLogger
@@ -415,7 +584,13 @@ func (a *Agent) Chat(ctx context.Context, input string, streamer streamers.ChatH
 	orch.taskID = a.taskID
 	orch.pricingOverrides = a.pricingOverrides
 	orch.budget = a.budget
-	return orch.processTurn(ctx,input, false)
+	orch.parserRecovery = a.Mode == config.ModeMission
+	orch.onParserRecovery = a.onParserRecoveryEvent
+	orch.submitOutput = a.submitOutput
+	orch.outputMark = a.outputMark
+	result, err := orch.processTurn(a.withToolEnv(ctx), input, false)
+	a.markOutput(result)
+	return result, err
 }
 
 // AnswerFollowUp handles a follow-up question using the agent's existing conversation context.
@@ -448,26 +623,49 @@ func (a *Agent) GetTools() map[string]aitools.Tool {
 	return a.tools
 }
 
-// createProvider creates the appropriate LLM provider based on config
+// NewProvider creates the LLM provider for a model config, for one-off calls
+// made outside an agent or commander session. Call closeFn when done with it.
+func NewProvider(ctx context.Context, modelConfig *config.Model) (provider llm.Provider, closeFn func(), err error) {
+	provider, needsClose, err := createProvider(ctx, modelConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !needsClose {
+		return provider, func() {}, nil
+	}
+	return provider, func() { closeProvider(provider) }, nil
+}
+
+// createProvider creates the appropriate LLM provider based on config,
+// wrapped by wrapProvider
 func createProvider(ctx context.Context, modelConfig *config.Model) (llm.Provider, bool, error) {
 	switch modelConfig.Provider {
 	case config.ProviderOpenAI:
-		return llm.NewOpenAIProvider(modelConfig.APIKey, modelConfig.BaseURL), false, nil
+		return wrapProvider(llm.NewOpenAIProvider(modelConfig.APIKey, modelConfig.BaseURL), modelConfig), false, nil
 	case config.ProviderAnthropic:
-		return llm.NewAnthropicProvider(modelConfig.APIKey, modelConfig.BaseURL), false, nil
+		return wrapProvider(llm.NewAnthropicProvider(modelConfig.APIKey, modelConfig.BaseURL), modelConfig), false, nil
 	case config.ProviderGemini:
 		provider, err := llm.NewGeminiProvider(ctx, modelConfig.APIKey, modelConfig.BaseURL)
 		if err != nil {
 			return nil, false, err
 		}
-		return provider, true, nil // Gemini provider needs to be closed
-	case config.ProviderOllama:
-		return llm.NewOpenAICompatibleProvider(modelConfig.BaseURL), false, nil
+		return wrapProvider(provider, modelConfig), true, nil // Gemini provider needs to be closed
+	case config.ProviderOllama, config.ProviderOpenAICompatible:
+		return wrapProvider(llm.NewOpenAICompatibleProvider(modelConfig.BaseURL, modelConfig.APIKey), modelConfig), false, nil
+	case config.ProviderScripted:
+		return wrapProvider(scriptedProvider(modelConfig), modelConfig), false, nil
 	default:
 		return nil, false, fmt.Errorf("unknown provider: %s", modelConfig.Provider)
 	}
 }
 
+// wrapProvider applies the middleware registered for the model's provider
+// (see llm.Use) and the model config's shared rate limiter, when it sets one.
+func wrapProvider(p llm.Provider, modelConfig *config.Model) llm.Provider {
+	p = llm.WithMiddleware(p, llm.MiddlewareFor(string(modelConfig.Provider)))
+	return llm.WithRateLimits(p, rateLimiters(modelConfig))
+}
+
 // formatDatasetInfo creates a system prompt section describing available datasets
 func formatDatasetInfo(datasets []aitools.DatasetInfo) string {
 	if len(datasets) == 0 {
@@ -7,11 +7,14 @@ import (
 	"time"
 
 	"github.com/mlund01/squadron-wire/protocol"
+	"go.opentelemetry.io/otel/attribute"
 
 	"squadron/aitools"
 	"squadron/config"
+	"squadron/failure"
 	"squadron/llm"
 	"squadron/streamers"
+	"squadron/tracing"
 )
 
 // AgentManager owns agent creation, session tracking, and lifecycle for a commander.
@@ -28,6 +31,10 @@ type AgentManager struct {
 	// Agent name → store session ID
 	sessionIDs map[string]string
 
+	// Agent name → lock held while that agent runs, so parallel calls (and
+	// handoffs out of them) never drive one agent from two goroutines
+	running map[string]*sync.Mutex
+
 	// Dependencies from commander
 	agents         map[string]*config.Agent
 	configPath     string
@@ -35,6 +42,8 @@ type AgentManager struct {
 	secretInfos    []SecretInfo
 	secretValues   map[string]string
 	memoryStore    aitools.MemoryStore
+	knowledge      aitools.KnowledgeBase
+	artifacts      aitools.Artifacts
 	sessionLogger  SessionLogger
 	taskID         string
 	missionID      string
@@ -45,7 +54,13 @@ type AgentManager struct {
 	pricingOverrides map[string]*llm.ModelPricing
 	provider         llm.Provider // optional injected provider for agents
 	budget           BudgetChecker
+	callLimiter      *llm.CallLimiter
+	responseCache    llm.ResponseCache
 	humanBridge      aitools.HumanInputBridge // bridge for builtins.human.ask on spawned agents
+	toolFilter       *config.ToolFilter       // task-level tools_allow / tools_deny
+	agentModels      map[string]string        // task-level model overrides by agent name
+	sampling         *config.Sampling         // task-level sampling settings
+	policies         *config.Policies         // mission tool-call policies
 }
 
 // AgentManagerConfig holds the dependencies needed to create an AgentManager.
@@ -56,6 +71,8 @@ type AgentManagerConfig struct {
 	SecretInfos    []SecretInfo
 	SecretValues   map[string]string
 	MemoryStore    aitools.MemoryStore
+	Knowledge      aitools.KnowledgeBase
+	Artifacts      aitools.Artifacts
 	SessionLogger  SessionLogger
 	TaskID         string
 	MissionID      string
@@ -68,8 +85,20 @@ type AgentManagerConfig struct {
 	Provider llm.Provider
 	// Budget flows to spawned agents so their LLM turns contribute to the same tally.
 	Budget BudgetChecker
+	// CallLimiter is shared with spawned agents (nil = no limit).
+	CallLimiter *llm.CallLimiter
+	// ResponseCache is shared with spawned agents (nil = no caching).
+	ResponseCache llm.ResponseCache
 	// HumanBridge — nil disables builtins.human.ask on spawned agents.
 	HumanBridge aitools.HumanInputBridge
+	// ToolFilter narrows spawned agents' tools for this task (nil = no filter).
+	ToolFilter *config.ToolFilter
+	// AgentModels overrides spawned agents' models for this task, by agent name.
+	AgentModels map[string]string
+	// Sampling tunes spawned agents' LLM calls for this task (nil = model defaults).
+	Sampling *config.Sampling
+	// Policies are the mission's tool-call rules for spawned agents (nil = none).
+	Policies *config.Policies
 }
 
 // NewAgentManager creates a new AgentManager.
@@ -84,6 +113,8 @@ func NewAgentManager(cfg AgentManagerConfig) *AgentManager {
 		secretInfos:    cfg.SecretInfos,
 		secretValues:   cfg.SecretValues,
 		memoryStore:    cfg.MemoryStore,
+		knowledge:      cfg.Knowledge,
+		artifacts:      cfg.Artifacts,
 		sessionLogger:  cfg.SessionLogger,
 		taskID:         cfg.TaskID,
 		missionID:      cfg.MissionID,
@@ -94,14 +125,50 @@ func NewAgentManager(cfg AgentManagerConfig) *AgentManager {
 		pricingOverrides: cfg.PricingOverrides,
 		provider:         cfg.Provider,
 		budget:           cfg.Budget,
+		callLimiter:      cfg.CallLimiter,
+		responseCache:    cfg.ResponseCache,
 		humanBridge:      cfg.HumanBridge,
+		toolFilter:       cfg.ToolFilter,
+		agentModels:      cfg.AgentModels,
+		sampling:         cfg.Sampling,
+		policies:         cfg.Policies,
+		running:          make(map[string]*sync.Mutex),
 	}
 }
 
 // RunAgent runs an agent by name with a task or response. Blocks until the agent completes or errors.
+// When the agent hands off to another agent, the receiving agent runs next and
+// its result is returned with HandedOffTo set.
 // Returns the ChatResult and any error.
 func (m *AgentManager) RunAgent(ctx context.Context, name, task, response string) (ChatResult, error) {
-	agentCfg, ok := m.agents[name]
+	result, err := m.runAgent(ctx, name, task, response, nil)
+	for hops := 0; err == nil && result.Handoff != nil; hops++ {
+		h := result.Handoff
+		if hops == maxAgentHandoffs {
+			return ChatResult{}, fmt.Errorf("agent '%s' tried to hand off to '%s' after %d handoffs (limit reached)", h.From, h.To, maxAgentHandoffs)
+		}
+		if m.debugLogger != nil {
+			m.debugLogger.LogEvent("agent_handoff", map[string]any{
+				"task":    m.taskName,
+				"from":    h.From,
+				"to":      h.To,
+				"context": h.Mode,
+			})
+		}
+		result, err = m.runAgent(ctx, h.To, h.Task, "", h)
+		if err == nil && result.Handoff == nil {
+			result.HandedOffTo = h.To
+		}
+	}
+	return result, err
+}
+
+// runAgent runs a single agent without following handoffs. handoff is set
+// when the agent takes over another agent's task.
+func (m *AgentManager) runAgent(ctx context.Context, name, task, response string, handoff *Handoff) (ChatResult, error) {
+	// An instance started by call_agents_parallel ("researcher#2") runs its
+	// agent's config under its own session
+	agentCfg, ok := m.agents[AgentConfigName(name)]
 	if !ok {
 		var available []string
 		for n := range m.agents {
@@ -110,6 +177,16 @@ func (m *AgentManager) RunAgent(ctx context.Context, name, task, response string
 		return ChatResult{}, fmt.Errorf("agent '%s' not found. Available agents: %v", name, available)
 	}
 
+	m.mu.Lock()
+	lock, ok := m.running[name]
+	if !ok {
+		lock = &sync.Mutex{}
+		m.running[name] = lock
+	}
+	m.mu.Unlock()
+	lock.Lock()
+	defer lock.Unlock()
+
 	m.mu.Lock()
 	a, exists := m.active[name]
 	m.mu.Unlock()
@@ -122,6 +199,7 @@ func (m *AgentManager) RunAgent(ctx context.Context, name, task, response string
 		if err != nil {
 			return ChatResult{}, fmt.Errorf("creating agent '%s': %w", name, err)
 		}
+		a.enableHandoff(m.handoffPeers())
 		m.mu.Lock()
 		m.active[name] = a
 		m.mu.Unlock()
@@ -152,6 +230,12 @@ func (m *AgentManager) RunAgent(ctx context.Context, name, task, response string
 	// invocation path.
 	ctx = aitools.WithMissionContext(ctx, m.missionID, m.taskID)
 
+	ctx, span := tracing.Start(ctx, "agent "+name,
+		attribute.String("squadron.agent", name),
+		attribute.String("squadron.task", m.taskName),
+		attribute.Bool("squadron.agent.resumed", exists),
+	)
+
 	// Execute
 	var result ChatResult
 	var err error
@@ -160,7 +244,13 @@ func (m *AgentManager) RunAgent(ctx context.Context, name, task, response string
 		result, err = a.Resume(ctx, handler)
 	} else {
 		var agentInput string
-		if response != "" {
+		if exists && response == "" {
+			// A new task (or a handoff) for an agent that already worked
+			a.summarizeForNewTask(ctx)
+		}
+		if handoff != nil {
+			agentInput = handoff.message()
+		} else if response != "" {
 			agentInput = fmt.Sprintf("<COMMANDER_RESPONSE>\n%s\n</COMMANDER_RESPONSE>", response)
 			if handler != nil {
 				handler.CommanderResponse(response)
@@ -170,15 +260,24 @@ func (m *AgentManager) RunAgent(ctx context.Context, name, task, response string
 		}
 		result, err = a.Chat(ctx, agentInput, handler)
 	}
+	if result.Handoff != nil {
+		span.SetAttributes(attribute.String("squadron.agent.handoff_to", result.Handoff.To))
+	}
+	tracing.End(span, err)
 
 	if err != nil {
 		m.notifyComplete(name)
 		m.completeSession(name, err)
-		return ChatResult{}, err
+		return ChatResult{}, failure.Wrap(failure.ErrToolFailure, err)
+	}
+
+	// A handing-off agent is done; verbatim handoffs carry its conversation
+	if result.Handoff != nil && result.Handoff.Mode == HandoffVerbatim {
+		result.Handoff.Context = a.handoffTranscript()
 	}
 
-	// If agent completed, move to completed map and notify
-	if result.Complete {
+	// If agent completed (or handed off), move to completed map and notify
+	if result.Complete || result.Handoff != nil {
 		m.notifyComplete(name)
 		m.completeSession(name, nil)
 		m.mu.Lock()
@@ -209,6 +308,7 @@ func (m *AgentManager) AddRestoredCompleted(name string, a *Agent) {
 
 // AddRestoredActive adds a restored active agent (for resume).
 func (m *AgentManager) AddRestoredActive(name string, a *Agent, sessionID string) {
+	a.enableHandoff(m.handoffPeers())
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	m.active[name] = a
@@ -228,8 +328,19 @@ func (m *AgentManager) GetSessionID(name string) (string, bool) {
 
 // --- internal helpers ---
 
+// handoffPeers returns the agents available as handoff targets (name → personality).
+func (m *AgentManager) handoffPeers() map[string]string {
+	peers := make(map[string]string, len(m.agents))
+	for name, cfg := range m.agents {
+		peers[name] = cfg.Personality
+	}
+	return peers
+}
+
 func (m *AgentManager) reopenSession(name string) {
+	m.mu.Lock()
 	sid, ok := m.sessionIDs[name]
+	m.mu.Unlock()
 	if !ok {
 		return
 	}
@@ -269,21 +380,57 @@ func (m *AgentManager) createAgent(ctx context.Context, agentCfg *config.Agent)
 		}
 	}
 
+	var onRetry func(llm.RetryEvent)
+	if m.callbacks != nil && m.callbacks.OnProviderRetry != nil {
+		taskName := m.taskName
+		agentName := agentCfg.Name
+		cb := m.callbacks.OnProviderRetry
+		onRetry = func(ev llm.RetryEvent) {
+			cb(taskName, agentName, ev)
+		}
+	}
+
+	var onParserRecovery func(ParserRecoveryEvent)
+	if m.callbacks != nil && m.callbacks.OnParserRecovery != nil {
+		taskName := m.taskName
+		agentName := agentCfg.Name
+		cb := m.callbacks.OnParserRecovery
+		onParserRecovery = func(ev ParserRecoveryEvent) {
+			cb(taskName, agentName, ev)
+		}
+	}
+
+	var budget BudgetChecker
+	if m.budget != nil {
+		budget = m.budget.ForAgent(agentCfg.Name)
+	}
+
 	return New(ctx, Options{
 		Config:           m.cfg,
 		ConfigPath:       m.configPath,
 		AgentConfig:      agentCfg,
 		AgentName:        agentCfg.Name,
+		Model:            m.agentModels[agentCfg.Name],
+		Sampling:         m.sampling,
 		Mode:             &mode,
 		DatasetStore:     datasetStore,
 		SecretInfos:      m.secretInfos,
 		SecretValues:     m.secretValues,
 		MemoryStore:      m.memoryStore,
+		Knowledge:        aitools.ScopeKnowledgeBase(m.knowledge, m.taskName, agentCfg.Name),
+		Artifacts:        aitools.ScopeArtifacts(m.artifacts, m.taskName, agentCfg.Name),
 		OnCompaction:     onCompaction,
 		OnSessionTurn:    onSessionTurn,
+		OnRetry:          onRetry,
+		OnParserRecovery: onParserRecovery,
 		PricingOverrides: m.pricingOverrides,
-		Budget:           m.budget,
+		Provider:         m.provider,
+		Budget:           budget,
+		CallLimiter:      m.callLimiter,
+		ResponseCache:    m.responseCache,
 		HumanBridge:      m.humanBridge,
+		ToolFilter:       m.toolFilter,
+		Policies:         m.policies,
 	})
 }
 
@@ -320,10 +467,10 @@ func (m *AgentManager) setupDebugLogging(name string, a *Agent) {
 	if a.eventLogger != nil || m.debugLogger == nil {
 		return
 	}
-	debugName := fmt.Sprintf("%s_%s", m.taskName, name)
+	scope := debugScope(m.taskName, m.iterationIndex)
 	a.EnableDebug(
-		m.debugLogger.GetMessageFile("agent", debugName),
-		m.debugLogger.GetTurnLogFile("agent", debugName),
+		m.debugLogger.GetMessageFile(scope, "agent", name),
+		m.debugLogger.GetTurnLogFile(scope, "agent", name),
 		newContextEventLogger(m.debugLogger, map[string]any{
 			"task":  m.taskName,
 			"agent": name,
@@ -344,7 +491,9 @@ func (m *AgentManager) notifyComplete(name string) {
 }
 
 func (m *AgentManager) completeSession(name string, err error) {
+	m.mu.Lock()
 	sid, ok := m.sessionIDs[name]
+	m.mu.Unlock()
 	if !ok || m.sessionLogger == nil {
 		return
 	}
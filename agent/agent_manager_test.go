@@ -0,0 +1,49 @@
+package agent
+
+import (
+	"context"
+	"testing"
+
+	"squadron/config"
+	"squadron/llm"
+)
+
+type nopProvider struct{}
+
+func (nopProvider) Chat(context.Context, *llm.ChatRequest) (*llm.ChatResponse, error) {
+	return &llm.ChatResponse{}, nil
+}
+
+func (nopProvider) ChatStream(context.Context, *llm.ChatRequest) (<-chan llm.StreamChunk, error) {
+	ch := make(chan llm.StreamChunk)
+	close(ch)
+	return ch, nil
+}
+
+func TestAgentManagerPassesProviderToAgents(t *testing.T) {
+	// No API key: without the injected provider, agent.New would refuse
+	// to create one of its own.
+	agentCfg := config.Agent{Name: "worker", Model: "claude_sonnet_4", Personality: "x"}
+	cfg := &config.Config{
+		Models: []config.Model{{Name: "anthropic", Provider: config.ProviderAnthropic}},
+		Agents: []config.Agent{agentCfg},
+	}
+	provider := &nopProvider{}
+	m := NewAgentManager(AgentManagerConfig{
+		Agents:   map[string]*config.Agent{"worker": &cfg.Agents[0]},
+		Config:   cfg,
+		Provider: provider,
+	})
+
+	a, err := m.createAgent(context.Background(), &cfg.Agents[0])
+	if err != nil {
+		t.Fatalf("createAgent: %v", err)
+	}
+	defer a.Close()
+	if a.provider != provider {
+		t.Errorf("agent provider = %#v, want the manager's provider", a.provider)
+	}
+	if a.ownsProvider {
+		t.Error("agent owns the shared provider and would close it")
+	}
+}
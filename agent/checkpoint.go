@@ -0,0 +1,96 @@
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"squadron/aitools"
+	"squadron/llm"
+	"squadron/store"
+)
+
+// maybeCheckpoint counts a finished turn and saves a checkpoint every
+// checkpointInterval turns. Called only at a consistent point: the turn's
+// tool results are already in the session, so a restored commander
+// continues with ContinueStream and nothing has to be healed.
+func (s *Commander) maybeCheckpoint() {
+	if s.checkpoints == nil || s.checkpointInterval <= 0 || s.sessionID == "" {
+		return
+	}
+	s.turns++
+	if s.turns%s.checkpointInterval != 0 {
+		return
+	}
+	if err := s.saveCheckpoint(); err != nil {
+		log.Printf("Commander %s: failed to save checkpoint: %v", s.TaskName, err)
+		return
+	}
+	if s.debugLogger != nil {
+		s.debugLogger.LogEvent("commander_checkpoint", map[string]any{"task": s.TaskName, "turn": s.turns})
+	}
+}
+
+// saveCheckpoint stores the session's system prompts and messages (as
+// compacted or pruned so far), the large tool results the LLM can still
+// reference, and the dataset cursor position.
+func (s *Commander) saveCheckpoint() error {
+	history := s.session.GetHistory()
+	msgs := make([]llm.Message, 0, len(s.session.GetSystemPrompts())+len(history))
+	for _, sp := range s.session.GetSystemPrompts() {
+		msgs = append(msgs, llm.NewTextMessage(llm.RoleSystem, sp))
+	}
+	msgs = append(msgs, history...)
+	msgsJSON, err := json.Marshal(msgs)
+	if err != nil {
+		return fmt.Errorf("encode messages: %w", err)
+	}
+	resultsJSON, err := json.Marshal(s.resultStore.Snapshot())
+	if err != nil {
+		return fmt.Errorf("encode results: %w", err)
+	}
+	cp := store.SessionCheckpoint{
+		SessionID:    s.sessionID,
+		Turn:         s.turns,
+		MessagesJSON: s.redactor.Redact(string(msgsJSON)),
+		ResultsJSON:  s.redactor.Redact(string(resultsJSON)),
+	}
+	if s.datasetCursor != nil {
+		position := s.datasetOffset + s.datasetCursor.Position()
+		cp.DatasetPosition = &position
+	}
+	return s.checkpoints.SaveCheckpoint(cp)
+}
+
+// RestoreCheckpoint replaces the commander's session, result store, and
+// dataset cursor with a stored checkpoint. Used on resume instead of
+// LoadSessionMessages when the session has a checkpoint; turns run after
+// the checkpoint are redone.
+func (s *Commander) RestoreCheckpoint(cp *store.SessionCheckpoint) error {
+	var msgs []llm.Message
+	if err := json.Unmarshal([]byte(cp.MessagesJSON), &msgs); err != nil {
+		return fmt.Errorf("decode checkpoint messages: %w", err)
+	}
+	var results aitools.ResultStoreSnapshot
+	if err := json.Unmarshal([]byte(cp.ResultsJSON), &results); err != nil {
+		return fmt.Errorf("decode checkpoint results: %w", err)
+	}
+	s.LoadSessionMessages(msgs)
+	s.resultStore.Restore(results)
+	if s.datasetCursor != nil && cp.DatasetPosition != nil {
+		s.datasetCursor.Seek(*cp.DatasetPosition - s.datasetOffset)
+	}
+	s.turns = cp.Turn
+	return nil
+}
+
+// clearCheckpoint drops the session's checkpoint once the session is
+// complete, so a finished commander is always rebuilt from its full log.
+func (s *Commander) clearCheckpoint() {
+	if s.checkpoints == nil || s.sessionID == "" {
+		return
+	}
+	if err := s.checkpoints.DeleteCheckpoint(s.sessionID); err != nil {
+		log.Printf("Commander %s: failed to delete checkpoint: %v", s.TaskName, err)
+	}
+}
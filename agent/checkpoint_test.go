@@ -0,0 +1,131 @@
+package agent
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"squadron/aitools"
+	"squadron/llm"
+	"squadron/store"
+)
+
+// memCheckpoints is an in-memory store.CheckpointStore.
+type memCheckpoints struct {
+	saved map[string]store.SessionCheckpoint
+}
+
+func (m *memCheckpoints) SaveCheckpoint(cp store.SessionCheckpoint) error {
+	m.saved[cp.SessionID] = cp
+	return nil
+}
+
+func (m *memCheckpoints) GetCheckpoint(sessionID string) (*store.SessionCheckpoint, error) {
+	cp, ok := m.saved[sessionID]
+	if !ok {
+		return nil, nil
+	}
+	return &cp, nil
+}
+
+func (m *memCheckpoints) DeleteCheckpoint(sessionID string) error {
+	delete(m.saved, sessionID)
+	return nil
+}
+
+func newCheckpointingCommander(cps *memCheckpoints, items []cty.Value, offset int) *Commander {
+	c := &Commander{
+		TaskName:           "t",
+		session:            llm.NewSession(nil, "m", "be a commander"),
+		resultStore:        aitools.NewMemoryResultStore(),
+		taskComplete:       &aitools.TaskCompleteTool{},
+		sessionID:          "sess-1",
+		checkpoints:        cps,
+		checkpointInterval: 2,
+		datasetOffset:      offset,
+		redactor:           llm.NewRedactor(map[string]string{"api_key": "hunter2-secret"}),
+	}
+	if len(items) > 0 {
+		c.datasetCursor = aitools.NewDatasetCursor("t", items)
+	}
+	return c
+}
+
+func TestCheckpointEveryIntervalTurns(t *testing.T) {
+	cps := &memCheckpoints{saved: map[string]store.SessionCheckpoint{}}
+	c := newCheckpointingCommander(cps, nil, 0)
+
+	c.maybeCheckpoint()
+	if len(cps.saved) != 0 {
+		t.Fatal("no checkpoint expected after the first turn")
+	}
+	c.maybeCheckpoint()
+	cp, ok := cps.saved["sess-1"]
+	if !ok || cp.Turn != 2 {
+		t.Fatalf("expected a checkpoint at turn 2, got %+v", cps.saved)
+	}
+
+	c.clearCheckpoint()
+	if len(cps.saved) != 0 {
+		t.Fatal("clearCheckpoint should delete the session's checkpoint")
+	}
+}
+
+func TestCheckpointRoundTrip(t *testing.T) {
+	items := []cty.Value{cty.StringVal("a"), cty.StringVal("b"), cty.StringVal("c"), cty.StringVal("d")}
+	cps := &memCheckpoints{saved: map[string]store.SessionCheckpoint{}}
+
+	c := newCheckpointingCommander(cps, items, 1)
+	c.session.LoadMessages([]llm.Message{
+		{Role: llm.RoleUser, Content: "use hunter2-secret"},
+		{Role: llm.RoleAssistant, Parts: []llm.ContentBlock{
+			{Type: llm.ContentTypeToolUse, ToolUse: &llm.ToolUseBlock{ID: "c1", Name: "dataset_next", Input: json.RawMessage(`{}`)}},
+		}},
+		{Role: llm.RoleUser, Parts: []llm.ContentBlock{
+			{Type: llm.ContentTypeToolResult, ToolResult: &llm.ToolResultBlock{ToolUseID: "c1", Content: "item b"}},
+		}},
+	})
+	resultID := c.resultStore.Store("http.get", aitools.StoredResult{Type: aitools.ResultTypeText, RawData: "big body", Size: 8})
+	c.datasetCursor.Seek(2)
+	if err := c.saveCheckpoint(); err != nil {
+		t.Fatal(err)
+	}
+	cp := cps.saved["sess-1"]
+	if cp.DatasetPosition == nil || *cp.DatasetPosition != 3 {
+		t.Fatalf("dataset position should count from the start of the full dataset, got %v", cp.DatasetPosition)
+	}
+
+	// A resumed commander sees only the items after the two completed ones.
+	restored := newCheckpointingCommander(cps, items[2:], 2)
+	restored.session = llm.NewSession(nil, "m")
+	if err := restored.RestoreCheckpoint(&cp); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := restored.session.GetSystemPrompts(); len(got) != 1 || got[0] != "be a commander" {
+		t.Fatalf("system prompts not restored: %v", got)
+	}
+	history := restored.session.GetHistory()
+	if len(history) != 3 {
+		t.Fatalf("expected 3 restored messages, got %d", len(history))
+	}
+	if history[0].Content != "use ${secrets.api_key}" {
+		t.Fatalf("checkpointed messages should be redacted, got %q", history[0].Content)
+	}
+	if tr := history[2].Parts[0].ToolResult; tr == nil || tr.Content != "item b" {
+		t.Fatalf("tool result not restored: %+v", history[2])
+	}
+	if r, ok := restored.resultStore.Get(resultID); !ok || r.RawData != "big body" {
+		t.Fatalf("stored result %s not restored", resultID)
+	}
+	if next := restored.resultStore.Store("http.get", aitools.StoredResult{}); next == resultID {
+		t.Fatal("new result IDs should continue the restored sequence")
+	}
+	if pos := restored.datasetCursor.Position(); pos != 1 {
+		t.Fatalf("cursor should resume past the in-flight item, got position %d", pos)
+	}
+	if restored.turns != cp.Turn {
+		t.Fatalf("turn count not restored: %d", restored.turns)
+	}
+}
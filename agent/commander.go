@@ -10,13 +10,16 @@ import (
 
 	"github.com/mlund01/squadron-wire/protocol"
 	"github.com/zclconf/go-cty/cty"
+	"go.opentelemetry.io/otel/attribute"
 
 	"squadron/agent/internal/prompts"
 	"squadron/aitools"
 	"squadron/config"
+	"squadron/failure"
 	"squadron/llm"
 	"squadron/store"
 	"squadron/streamers"
+	"squadron/tracing"
 )
 
 // DependencySummary holds the summary from a completed dependency task
@@ -25,6 +28,14 @@ type DependencySummary struct {
 	Summary  string
 }
 
+// PriorMissionInfo identifies a completed mission whose commanders can be
+// queried via ask_prior_commander.
+type PriorMissionInfo struct {
+	ID    string
+	Name  string
+	Tasks []string
+}
+
 // SecretInfo contains name and description for a secret (passed to prompts)
 type SecretInfo struct {
 	Name        string
@@ -34,10 +45,15 @@ type SecretInfo struct {
 // BudgetChecker is implemented by anything that tracks cumulative token/dollar
 // usage against a configured budget. Commanders and agents call CheckBudget
 // before each LLM turn and RecordUsage after each turn; a non-nil error from
-// either method causes the owning task to fail immediately.
+// either method causes the owning task to fail immediately. CheckBudget
+// reserves the call, so a turn that fails without a response calls
+// ReleaseCall instead of RecordUsage. ForAgent returns a checker sharing the
+// same tally whose usage is attributed to the named agent.
 type BudgetChecker interface {
 	CheckBudget() error
 	RecordUsage(tokens int, cost float64) error
+	ReleaseCall()
+	ForAgent(agentName string) BudgetChecker
 }
 
 // CommanderOptions holds configuration for creating a commander
@@ -54,6 +70,9 @@ type CommanderOptions struct {
 	Commander string
 	// AgentNames is the list of agents available to this commander
 	AgentNames []string
+	// AgentReasons explains, per agent name, why an agent was picked for
+	// the task (set for agents matched by the task's requires)
+	AgentReasons map[string]string
 	// DepSummaries contains summaries from completed dependency tasks
 	DepSummaries []DependencySummary
 	// DepOutputSchemas contains output schema info for completed dependency tasks
@@ -75,27 +94,59 @@ type CommanderOptions struct {
 	// SequentialDataset contains all items for sequential iteration processing
 	// When set, the commander handles all items in a single session using dataset_next/submit_output tools
 	SequentialDataset []cty.Value
+	// DatasetOffset is the index of SequentialDataset[0] in the task's full
+	// dataset (non-zero when a resumed task skips already-completed items).
+	DatasetOffset int
 	// MemoryStore provides file memory access for the mission (optional)
 	MemoryStore aitools.MemoryStore
+	// Knowledge is the mission's semantic memory for memory_put / memory_search (optional)
+	Knowledge aitools.KnowledgeBase
+	// Artifacts stores the mission's files for artifact_put / artifact_get (optional)
+	Artifacts aitools.Artifacts
 	// Compaction settings for the commander session (nil if disabled)
 	Compaction *CompactionConfig
 	// PruneOn triggers pruning when conversation reaches this many turns (0 = disabled)
 	PruneOn int
 	// PruneTo reduces conversation to this many turns when pruning triggers
 	PruneTo int
+	// CheckpointInterval saves a checkpoint of the session, result store,
+	// and dataset cursor every this many turns (0 = disabled). Requires
+	// Checkpoints and a SessionLogger.
+	CheckpointInterval int
+	// Checkpoints stores commander checkpoints (optional).
+	Checkpoints store.CheckpointStore
 	// Reasoning is the abstract reasoning level ("low"/"medium"/"high"/"")
 	// requested for the commander. Silently no-op on unsupported models.
 	Reasoning string
+	// PromptTemplate replaces the built-in commander prompt (see
+	// prompts.CommanderTemplateData). Ignored for interactive chats.
+	PromptTemplate string
+	// MaxTurns stops the task as failed once the commander has taken this
+	// many turns of tool calls without completing it (0 = no limit).
+	MaxTurns int
+	// MaxParallelAgents caps how many agents one call_agents_parallel call
+	// runs at once (0 = config.DefaultMaxParallelAgents).
+	MaxParallelAgents int
 	// Routes contains conditional routing options for this task (nil if no router)
 	Routes []aitools.RouteOption
-	// ToolResponseMaxSize overrides the default tool response size limit (0 = default)
-	ToolResponseMaxSize int
+	// Verify has the commander check its work when it first calls
+	// task_complete, and give a confidence score when it calls it again.
+	Verify bool
+	// ToolResponse is the commander's tool_response block, overriding the
+	// config-wide limits on tool results (nil = none)
+	ToolResponse *config.ToolResponseConfig
 	// PricingOverrides maps API model names to custom pricing (optional, from config)
 	PricingOverrides map[string]*llm.ModelPricing
 	// Budget is an optional per-task budget checker. When set, the commander checks it
 	// before each LLM call and records usage after each turn. Returning an error from
 	// either call fails the task immediately.
 	Budget BudgetChecker
+	// CallLimiter caps concurrent LLM calls across the mission (optional). It is
+	// shared with the agents this commander spawns.
+	CallLimiter *llm.CallLimiter
+	// ResponseCache replays responses to identical LLM requests (optional). It is
+	// shared with the agents this commander spawns.
+	ResponseCache llm.ResponseCache
 	// MissionLocalAgents are agents scoped to this mission (checked before global agents)
 	MissionLocalAgents []config.Agent
 	// Provider is an optional pre-created LLM provider. When set, commander creation
@@ -106,6 +157,21 @@ type CommanderOptions struct {
 	// spawns. Nil disables HITL — the tool then returns
 	// "[no human available]" instead of blocking.
 	HumanBridge aitools.HumanInputBridge
+	// ToolFilter narrows the tools of agents this commander spawns to what
+	// the task allows (nil = no filter).
+	ToolFilter *config.ToolFilter
+	// Policies are the mission's tool-call rules for spawned agents (optional).
+	Policies *config.Policies
+	// AgentModels overrides the models of agents this commander spawns, by
+	// agent name (from the task's models block).
+	AgentModels map[string]string
+	// Sampling tunes the LLM calls of this commander and the agents it
+	// spawns (from the task's sampling block, optional).
+	Sampling *config.Sampling
+	// Interactive runs the commander as a chat with a human outside any
+	// mission (see Chat): each message is a request and each task_complete
+	// summary is the reply.
+	Interactive bool
 }
 
 // DependencyOutputSchema describes a completed dependency task's output schema
@@ -113,6 +179,7 @@ type DependencyOutputSchema struct {
 	TaskName     string
 	IsIterated   bool
 	ItemCount    int
+	Aggregated   bool // iterated task whose aggregate block stored a task-level output
 	OutputFields []OutputFieldSchema
 }
 
@@ -159,6 +226,15 @@ type CommanderToolCallbacks struct {
 	// For iterated tasks, pass the iteration index (0+). For regular tasks, pass -1.
 	AskCommanderWithCache func(targetTask string, iterationIndex int, question string) (string, error)
 
+	// AskPriorCommander queries a commander from a completed prior mission that
+	// this run references. The runner revives the commander from the store on
+	// first use. For iterated tasks, pass the iteration index (0+). For regular
+	// tasks, pass -1.
+	AskPriorCommander func(missionID, taskName string, iterationIndex int, question string) (string, error)
+	// PriorMissions lists the missions AskPriorCommander can reach. Shown to the
+	// LLM in the ask_prior_commander tool description.
+	PriorMissions []PriorMissionInfo
+
 	// OnSubmitOutput is called each time the LLM submits output via submit_output tool.
 	// Used to persist task outputs incrementally.
 	OnSubmitOutput aitools.SubmitOutputCallback
@@ -190,6 +266,15 @@ type CommanderToolCallbacks struct {
 	// OnAgentSessionTurn is called after each agent LLM turn with telemetry data.
 	OnAgentSessionTurn func(taskName, agentName string, data protocol.SessionTurnData)
 
+	// OnProviderRetry is called before the commander or one of its agents
+	// retries a failed LLM call. entity is "commander" or the agent name.
+	OnProviderRetry func(taskName, entity string, ev llm.RetryEvent)
+
+	// OnParserRecovery is called when the commander or one of its agents
+	// is re-prompted after a response with no tool call (and, for agents,
+	// no answer). entity is "commander" or the agent name.
+	OnParserRecovery func(taskName, entity string, ev ParserRecoveryEvent)
+
 	// Subtask management callbacks (optional). When set, the commander gets
 	// set_subtasks, get_subtasks, and complete_subtask tools.
 	SetSubtasks     func(titles []string) error
@@ -200,13 +285,31 @@ type CommanderToolCallbacks struct {
 // DebugLogger is the interface for debug logging during mission execution
 type DebugLogger interface {
 	// GetMessageFile returns a file path for logging LLM messages for a specific entity
-	GetMessageFile(entityType, entityName string) string
+	GetMessageFile(scope DebugScope, entityType, entityName string) string
 	// GetTurnLogFile returns a .jsonl file path for per-turn session snapshots
-	GetTurnLogFile(entityType, entityName string) string
+	GetTurnLogFile(scope DebugScope, entityType, entityName string) string
 	// LogEvent logs a programmatic event
 	LogEvent(eventType string, data map[string]any)
 }
 
+// DebugScope is the task, and for parallel iterations the iteration, whose
+// commander or agent a debug log belongs to. Each scope gets its own
+// directory, so concurrent iterations never share a file.
+type DebugScope struct {
+	Task      string
+	Iteration *int
+}
+
+// debugScope returns the scope of a commander or agent. A parallel
+// iteration's task name carries its index ("score[3]"), which the scope
+// holds separately.
+func debugScope(taskName string, iteration *int) DebugScope {
+	if iteration != nil {
+		taskName = strings.TrimSuffix(taskName, fmt.Sprintf("[%d]", *iteration))
+	}
+	return DebugScope{Task: taskName, Iteration: iteration}
+}
+
 // KnowledgeStore provides query access to completed task outputs
 type KnowledgeStore interface {
 	// GetTaskOutput returns a task's output by name
@@ -215,6 +318,8 @@ type KnowledgeStore interface {
 	Query(taskName string, query TaskQuery) TaskQueryResult
 	// Aggregate performs an aggregate operation on iterations
 	Aggregate(taskName string, query AggregateQuery) AggregateResult
+	// Search ranks completed task summaries and outputs against keywords
+	Search(query TaskSearchQuery) TaskSearchResult
 }
 
 // TaskOutputInfo represents stored task output information
@@ -274,6 +379,29 @@ type AggregateResult struct {
 	Groups map[string]any
 }
 
+// TaskSearchQuery represents a keyword search over completed task results
+type TaskSearchQuery struct {
+	Text  string
+	Tasks []string // empty searches every completed task
+	Limit int
+}
+
+// TaskSearchHit is one matching summary or output
+type TaskSearchHit struct {
+	TaskName string
+	Kind     string // summary, iteration_summary, output, iteration_output
+	Index    *int   // iteration index, for iteration hits
+	ItemID   string
+	Score    float64
+	Snippet  string
+}
+
+// TaskSearchResult represents the ranked result of a search
+type TaskSearchResult struct {
+	TotalMatches int
+	Hits         []TaskSearchHit
+}
+
 // SessionLogger provides session tracking for persistence.
 // Implementations should be safe for concurrent use.
 type SessionLogger interface {
@@ -327,12 +455,20 @@ type Commander struct {
 	queryClones     map[string]*Commander // Cached clones for ask_commander queries (keyed by target task name)
 	secretInfos     []SecretInfo           // Secret names and descriptions for agent prompts
 	secretValues    map[string]string      // Actual secret values for tool call injection
+	redactor        *llm.Redactor          // Scrubs secrets from persisted messages and debug output
 	datasetCursor      *aitools.DatasetCursor      // Cursor for sequential dataset iteration (nil if not sequential)
 	submitOutput       *aitools.SubmitOutputTool   // Universal output submission tool
 	taskComplete       *aitools.TaskCompleteTool   // Tool to signal task completion
 	loopExitReason     string                     // Why the commander loop exited (for failure diagnostics)
+	loopExitClass      *failure.Class             // Failure class of loopExitReason, if not a provider error
+	lastAgentErr       error                      // Error from the last call_agent, if it failed (for failure classification)
 	noToolCallRetries  int                        // Count of consecutive no-tool-call retries
 	maxTokensRetries   int                        // Count of consecutive max_tokens truncation retries
+	maxTurns           int                        // Turn limit for the task (0 = no limit)
+	maxParallelAgents  int                       // call_agents_parallel fan-out limit
+	taskTurns          int                        // Turns of tool calls taken so far, for max_turns
+	lastTurnCalls      string                     // Tool calls of the previous turn, for loop detection
+	turnRepeats        int                        // Consecutive turns making exactly lastTurnCalls
 	sessionLogger      SessionLogger               // Session persistence (nil if not tracking)
 	sessionID          string                 // Store session ID (empty if not tracking)
 	agentSessionIDs    map[string]string      // Agent name → store session ID (for agent session tracking)
@@ -343,11 +479,24 @@ type Commander struct {
 	pricingOverrides   map[string]*llm.ModelPricing
 	subtasksSet        bool                   // Whether set_subtasks has been called
 	memoryStore        aitools.MemoryStore    // Memory access for missions (nil if not configured)
-	compaction         *CompactionConfig      // Compaction settings (nil if disabled)
+	knowledge          aitools.KnowledgeBase  // Mission knowledge base (nil if not configured)
+	artifacts          aitools.Artifacts      // Mission artifact store (nil if not configured)
+	summaryProvider    llm.Provider           // Compaction summary provider we created and must close (nil if none)
 	pruneOn            int                    // Trigger pruning at this many turns (0 = disabled)
 	pruneTo            int                    // Prune down to this many turns
+	checkpoints        store.CheckpointStore  // Checkpoint persistence (nil if not checkpointing)
+	checkpointInterval int                    // Save a checkpoint every this many turns (0 = disabled)
+	turns              int                    // Turns completed in this session (counted only when checkpointing)
+	datasetOffset      int                    // Index of the cursor's first item in the full dataset
 	budget             BudgetChecker          // Optional token/dollar budget enforcer
+	callLimiter        *llm.CallLimiter       // Optional mission-wide cap on concurrent LLM calls
+	responseCache      llm.ResponseCache      // Optional cache of LLM responses, shared with agents
 	humanBridge        aitools.HumanInputBridge // Optional bridge for builtins.human.ask
+	toolFilter         *config.ToolFilter       // Optional task-level filter for spawned agents' tools
+	policies           *config.Policies         // Optional mission tool-call policies for spawned agents
+	agentModels        map[string]string        // Optional task-level model overrides for spawned agents
+	sampling           *config.Sampling         // Optional task-level sampling settings, shared with spawned agents
+	interactive        bool                     // Chat with a human: the session stays open between replies
 }
 
 // NewCommander creates a new commander for a mission task
@@ -365,7 +514,7 @@ func NewCommander(ctx context.Context, opts CommanderOptions) (*Commander, error
 		provider = opts.Provider
 		ownsProvider = false
 	} else {
-		if modelConfig.Provider != config.ProviderOllama && modelConfig.APIKey == "" {
+		if modelConfig.Provider.RequiresAPIKey() && modelConfig.APIKey == "" {
 			return nil, fmt.Errorf("API key not set for model '%s'", modelConfig.Name)
 		}
 		provider, ownsProvider, err = createCommanderProvider(ctx, modelConfig)
@@ -386,6 +535,7 @@ func NewCommander(ctx context.Context, opts CommanderOptions) (*Commander, error
 				agentInfos = append(agentInfos, prompts.AgentInfo{
 					Name:        agentName,
 					Description: opts.MissionLocalAgents[i].Personality,
+					Reason:      opts.AgentReasons[agentName],
 				})
 				found = true
 				break
@@ -400,6 +550,7 @@ func NewCommander(ctx context.Context, opts CommanderOptions) (*Commander, error
 				agentInfos = append(agentInfos, prompts.AgentInfo{
 					Name:        agentName,
 					Description: opts.Config.Agents[i].Personality,
+					Reason:      opts.AgentReasons[agentName],
 				})
 				break
 			}
@@ -414,13 +565,34 @@ func NewCommander(ctx context.Context, opts CommanderOptions) (*Commander, error
 		IsIteration: opts.IsIteration,
 		IsParallel:  opts.IsParallel,
 	}
-	systemPrompts = append(systemPrompts, prompts.GetCommanderPrompt(agentInfos, iterationOpts))
+	if opts.Interactive {
+		systemPrompts = append(systemPrompts, prompts.GetCommanderChatPrompt(agentInfos))
+	} else {
+		commanderPrompt := prompts.GetCommanderPrompt(agentInfos, iterationOpts)
+		if opts.PromptTemplate != "" {
+			var schema strings.Builder
+			writeFieldList(&schema, opts.TaskOutputSchema, 0)
+			commanderPrompt, err = prompts.RenderCommanderTemplate(opts.PromptTemplate, prompts.CommanderTemplateData{
+				Default:     commanderPrompt,
+				Mission:     opts.MissionName,
+				Task:        opts.TaskName,
+				Agents:      agentInfos,
+				IsIteration: opts.IsIteration,
+				IsParallel:  opts.IsParallel,
+				Schema:      schema.String(),
+			})
+			if err != nil {
+				return nil, fmt.Errorf("commander prompt_template: %w", err)
+			}
+		}
+		systemPrompts = append(systemPrompts, commanderPrompt)
 
-	// Add context about mission and task
-	systemPrompts = append(systemPrompts, fmt.Sprintf(
-		"You are executing task '%s' in mission '%s'.",
-		opts.TaskName, opts.MissionName,
-	))
+		// Add context about mission and task
+		systemPrompts = append(systemPrompts, fmt.Sprintf(
+			"You are executing task '%s' in mission '%s'.",
+			opts.TaskName, opts.MissionName,
+		))
+	}
 
 	// Create session
 	session := llm.NewSession(provider, actualModelName, systemPrompts...)
@@ -428,6 +600,9 @@ func NewCommander(ctx context.Context, opts CommanderOptions) (*Commander, error
 	// message window invalidates the cache every turn, wasting cache creation tokens.
 	conversationCaching := modelConfig.IsPromptCachingEnabled() && (opts.PruneOn == 0 || (opts.PruneOn-opts.PruneTo) >= 3)
 	session.SetPromptCaching(modelConfig.IsPromptCachingEnabled(), conversationCaching)
+	session.SetRetryPolicy(retryPolicy(modelConfig))
+	session.SetContextWindow(modelConfig.ContextWindow(actualModelName))
+	session.SetSampling(SamplingFor(modelConfig, opts.Commander, opts.Sampling))
 
 	if opts.Reasoning != "" {
 		if config.ModelSupportsReasoning(modelConfig, actualModelName) {
@@ -439,6 +614,10 @@ func NewCommander(ctx context.Context, opts CommanderOptions) (*Commander, error
 
 	// Note: tools are set on the session in SetToolCallbacks after all tools are registered
 
+	session.SetCallLimiter(opts.CallLimiter)
+	session.SetResponseCache(opts.ResponseCache)
+	redactor := llm.NewRedactor(opts.SecretValues)
+	session.SetRedactor(redactor)
 	if opts.DebugFile != "" {
 		if err := session.EnableDebug(opts.DebugFile); err != nil {
 			fmt.Printf("Warning: could not enable debug logging: %v\n", err)
@@ -447,7 +626,7 @@ func NewCommander(ctx context.Context, opts CommanderOptions) (*Commander, error
 
 	// Create result store and interceptor for large results
 	resultStore := aitools.NewMemoryResultStore()
-	resultConfig := aitools.LargeResultConfigWithMaxSize(opts.ToolResponseMaxSize)
+	resultConfig := opts.Config.ToolResultLimits(opts.ToolResponse, nil)
 	interceptor := aitools.NewResultInterceptor(resultStore, resultConfig)
 
 	sup := &Commander{
@@ -467,13 +646,38 @@ func NewCommander(ctx context.Context, opts CommanderOptions) (*Commander, error
 		agentSessions:   make(map[string]*Agent),
 		secretInfos:      opts.SecretInfos,
 		secretValues:     opts.SecretValues,
-		compaction:       opts.Compaction,
+		redactor:         redactor,
 		pruneOn:          opts.PruneOn,
 		pruneTo:          opts.PruneTo,
+		maxTurns:         opts.MaxTurns,
+		maxParallelAgents:  opts.MaxParallelAgents,
+		checkpoints:      opts.Checkpoints,
+		checkpointInterval: opts.CheckpointInterval,
+		datasetOffset:    opts.DatasetOffset,
 		pricingOverrides: opts.PricingOverrides,
 		budget:           opts.Budget,
+		callLimiter:      opts.CallLimiter,
+		responseCache:    opts.ResponseCache,
 		humanBridge:      opts.HumanBridge,
+		toolFilter:       opts.ToolFilter,
+		policies:         opts.Policies,
+		agentModels:      opts.AgentModels,
+		sampling:         opts.Sampling,
+		interactive:      opts.Interactive,
 	}
+	session.SetRetryObserver(sup.onProviderRetry)
+
+	// Compaction: the commander's own block, else the model config's defaults.
+	// The observer is registered per run, since events go to that run's streamer.
+	compactionPol, summaryProvider, err := compactionPolicy(ctx, opts.Config, compactionSettings(opts.Compaction, modelConfig, opts.Commander), modelConfig, provider, opts.Provider != nil)
+	if err != nil {
+		return nil, err
+	}
+	if compactionPol != nil {
+		compactionPol.Context = sup.buildCompactionContext
+	}
+	session.SetCompaction(compactionPol)
+	sup.summaryProvider = summaryProvider
 
 	// Add result tools to commander's tool map
 	sup.tools["result_info"] = &aitools.ResultInfoTool{Store: resultStore}
@@ -490,12 +694,30 @@ func NewCommander(ctx context.Context, opts CommanderOptions) (*Commander, error
 		sup.tools["file_create"] = &aitools.MemoryCreateTool{Store: opts.MemoryStore}
 		sup.tools["file_delete"] = &aitools.MemoryDeleteTool{Store: opts.MemoryStore}
 		sup.tools["file_search"] = &aitools.MemorySearchTool{Store: opts.MemoryStore}
+		sup.tools["file_glob"] = &aitools.MemoryGlobTool{Store: opts.MemoryStore}
 		sup.tools["file_grep"] = &aitools.MemoryGrepTool{Store: opts.MemoryStore}
 		if memoryPrompt := prompts.FormatMemoryContext(opts.MemoryStore); memoryPrompt != "" {
 			session.AddSystemPrompt(memoryPrompt)
 		}
 	}
 
+	// Add knowledge tools if the mission has a knowledge base
+	if opts.Knowledge != nil {
+		sup.knowledge = opts.Knowledge
+		kb := aitools.ScopeKnowledgeBase(opts.Knowledge, opts.TaskName, "commander")
+		sup.tools["memory_put"] = &aitools.KnowledgePutTool{KB: kb}
+		sup.tools["memory_search"] = &aitools.KnowledgeSearchTool{KB: kb}
+	}
+
+	// Add artifact tools if the mission stores artifacts
+	if opts.Artifacts != nil {
+		sup.artifacts = opts.Artifacts
+		artifacts := aitools.ScopeArtifacts(opts.Artifacts, opts.TaskName, "commander")
+		sup.tools["artifact_put"] = &aitools.ArtifactPutTool{Artifacts: artifacts, Store: opts.MemoryStore}
+		sup.tools["artifact_get"] = &aitools.ArtifactGetTool{Artifacts: artifacts, Store: opts.MemoryStore}
+		sup.tools["artifact_list"] = &aitools.ArtifactListTool{Artifacts: artifacts}
+	}
+
 	// If there are dependency summaries or output schemas, add them as a secondary system prompt
 	if len(opts.DepSummaries) > 0 || len(opts.DepOutputSchemas) > 0 {
 		sup.injectDependencyContext(opts.DepSummaries, opts.DepOutputSchemas)
@@ -503,15 +725,7 @@ func NewCommander(ctx context.Context, opts CommanderOptions) (*Commander, error
 
 	// Create submit_output tool only if task has an explicit output schema
 	if len(opts.TaskOutputSchema) > 0 {
-		var outputFields []aitools.OutputField
-		for _, f := range opts.TaskOutputSchema {
-			outputFields = append(outputFields, aitools.OutputField{
-				Name:     f.Name,
-				Type:     f.Type,
-				Required: f.Required,
-			})
-		}
-		sup.submitOutput = aitools.NewSubmitOutputTool(outputFields)
+		sup.submitOutput = newSubmitOutputTool(opts.TaskOutputSchema, opts.Artifacts)
 		sup.tools["submit_output"] = sup.submitOutput
 		sup.injectOutputSchemaInstructions(opts.TaskOutputSchema)
 	}
@@ -519,6 +733,7 @@ func NewCommander(ctx context.Context, opts CommanderOptions) (*Commander, error
 	// Register task_complete tool (always available)
 	sup.taskComplete = &aitools.TaskCompleteTool{
 		Routes: opts.Routes,
+		Verify: opts.Verify,
 	}
 	sup.tools["task_complete"] = sup.taskComplete
 
@@ -527,6 +742,10 @@ func NewCommander(ctx context.Context, opts CommanderOptions) (*Commander, error
 		sup.injectRouteOptions(opts.Routes)
 	}
 
+	// Everything up to here is the same for every iteration of the task;
+	// let providers cache it apart from what follows.
+	session.MarkCachePrefix()
+
 	// If there's previous iteration output (sequential iterations), inject it
 	if len(opts.PrevIterationOutput) > 0 {
 		sup.injectPrevIterationOutput(opts.PrevIterationOutput)
@@ -547,6 +766,32 @@ func NewCommander(ctx context.Context, opts CommanderOptions) (*Commander, error
 	return sup, nil
 }
 
+// onProviderRetry reports a retried LLM call to the debug log and the
+// mission callbacks.
+func (s *Commander) onProviderRetry(ev llm.RetryEvent) {
+	if s.debugLogger != nil {
+		data := retryEventData(ev)
+		data["task"] = s.TaskName
+		s.debugLogger.LogEvent("commander_llm_retry", data)
+	}
+	if s.callbacks != nil && s.callbacks.OnProviderRetry != nil {
+		s.callbacks.OnProviderRetry(s.TaskName, "commander", ev)
+	}
+}
+
+// onParserRecovery reports a format correction to the debug log and the
+// mission callbacks.
+func (s *Commander) onParserRecovery(ev ParserRecoveryEvent) {
+	if s.debugLogger != nil {
+		data := parserRecoveryEventData(ev)
+		data["task"] = s.TaskName
+		s.debugLogger.LogEvent("parser_recovery", data)
+	}
+	if s.callbacks != nil && s.callbacks.OnParserRecovery != nil {
+		s.callbacks.OnParserRecovery(s.TaskName, "commander", ev)
+	}
+}
+
 // SetToolCallbacks configures the callbacks for commander tools
 // This must be called before ExecuteTask to enable call_agent and ask_agent
 func (s *Commander) SetToolCallbacks(callbacks *CommanderToolCallbacks, depSummaries []DependencySummary) {
@@ -556,7 +801,7 @@ func (s *Commander) SetToolCallbacks(callbacks *CommanderToolCallbacks, depSumma
 
 	// Create or reuse session record if session logger is available
 	if callbacks.SessionLogger != nil {
-		s.sessionLogger = callbacks.SessionLogger
+		s.sessionLogger = withRedaction(callbacks.SessionLogger, s.redactor)
 		s.callbacksTaskID = callbacks.TaskID
 		s.callbacksMissionID = callbacks.MissionID
 		s.agentSessionIDs = make(map[string]string)
@@ -588,9 +833,10 @@ func (s *Commander) SetToolCallbacks(callbacks *CommanderToolCallbacks, depSumma
 
 	// Create turn logger for commander session snapshots
 	if s.debugLogger != nil {
-		turnLogFile := s.debugLogger.GetTurnLogFile("commander", s.TaskName)
+		turnLogFile := s.debugLogger.GetTurnLogFile(debugScope(s.TaskName, s.iterationIndex), "commander", "")
 		if turnLogFile != "" {
 			if tl, err := llm.NewTurnLogger(turnLogFile); err == nil {
+				tl.SetRedactor(s.redactor)
 				s.turnLogger = tl
 			}
 		}
@@ -600,6 +846,9 @@ func (s *Commander) SetToolCallbacks(callbacks *CommanderToolCallbacks, depSumma
 	s.tools["call_agent"] = &callAgentTool{
 		commander: s,
 	}
+	s.tools["call_agents_parallel"] = &callAgentsParallelTool{
+		commander: s,
+	}
 
 	// Build ask_agent tool for querying completed agents
 	s.tools["ask_agent"] = &askAgentTool{
@@ -617,11 +866,14 @@ func (s *Commander) SetToolCallbacks(callbacks *CommanderToolCallbacks, depSumma
 		}
 	}
 
-	// Add query_task_output tool if KnowledgeStore is available
+	// Add query_task_output and search_task_outputs tools if KnowledgeStore is available
 	if callbacks.KnowledgeStore != nil {
 		s.tools["query_task_output"] = &queryTaskOutputTool{
 			store: callbacks.KnowledgeStore,
 		}
+		s.tools["search_task_outputs"] = &searchTaskOutputsTool{
+			store: callbacks.KnowledgeStore,
+		}
 	}
 
 	// Wire OnSubmitOutput callback to submit_output tool if set
@@ -636,6 +888,14 @@ func (s *Commander) SetToolCallbacks(callbacks *CommanderToolCallbacks, depSumma
 		}
 	}
 
+	// Add ask_prior_commander tool if this run references prior missions
+	if callbacks.AskPriorCommander != nil && len(callbacks.PriorMissions) > 0 {
+		s.tools["ask_prior_commander"] = &askPriorCommanderTool{
+			ask:      callbacks.AskPriorCommander,
+			missions: callbacks.PriorMissions,
+		}
+	}
+
 	// Add iteration-specific tools if callbacks are available
 	if callbacks.ListCommanderQuestions != nil {
 		s.tools["list_commander_questions"] = &listCommanderQuestionsTool{
@@ -726,6 +986,8 @@ func (s *Commander) SetToolCallbacks(callbacks *CommanderToolCallbacks, depSumma
 		SecretInfos:      s.secretInfos,
 		SecretValues:     s.secretValues,
 		MemoryStore:      s.memoryStore,
+		Knowledge:        s.knowledge,
+		Artifacts:        s.artifacts,
 		SessionLogger:    s.sessionLogger,
 		TaskID:           s.callbacksTaskID,
 		MissionID:        s.callbacksMissionID,
@@ -736,7 +998,13 @@ func (s *Commander) SetToolCallbacks(callbacks *CommanderToolCallbacks, depSumma
 		PricingOverrides: s.pricingOverrides,
 		Provider:         s.provider,
 		Budget:           s.budget,
+		CallLimiter:      s.callLimiter,
+		ResponseCache:    s.responseCache,
 		HumanBridge:      s.humanBridge,
+		ToolFilter:       s.toolFilter,
+		Policies:         s.policies,
+		AgentModels:      s.agentModels,
+		Sampling:         s.sampling,
 	})
 }
 
@@ -783,6 +1051,9 @@ func (s *Commander) appendOutputSchemaInfo(sb *strings.Builder, schema *Dependen
 	if schema.IsIterated {
 		sb.WriteString(fmt.Sprintf("Type: iterated (%d items)\n", schema.ItemCount))
 	}
+	if schema.Aggregated {
+		sb.WriteString("Aggregated: {\"task\": \"" + schema.TaskName + "\"} returns the aggregate of all iterations; pass filters, item_ids or limit to read individual iterations. The fields below describe each iteration.\n")
+	}
 	if len(schema.OutputFields) > 0 {
 		sb.WriteString("Fields:\n")
 		for _, field := range schema.OutputFields {
@@ -818,6 +1089,8 @@ func formatFieldType(field OutputFieldSchema) string {
 			return "object"
 		}
 		return "object"
+	case "file":
+		return "file (artifact reference from artifact_put)"
 	default:
 		return field.Type
 	}
@@ -856,6 +1129,8 @@ func writeExampleJSON(field OutputFieldSchema) string {
 		return "false"
 	case "string":
 		return "\"...\""
+	case "file":
+		return "\"artifact:...\""
 	case "array", "list":
 		if field.Items != nil {
 			return "[" + writeExampleJSON(*field.Items) + "]"
@@ -999,6 +1274,30 @@ func (s *Commander) TaskFailureReason() string {
 	return s.loopExitReason
 }
 
+// TaskFailureClass returns the failure class of a task the commander did not
+// succeed at: ErrMaxTurns or ErrLoopDetected when the loop stopped it, that
+// of the last agent call if it failed, otherwise ErrProviderError when the
+// loop gave up on the model's responses, or ErrMarkedFailed when the
+// commander failed the task through task_complete.
+func (s *Commander) TaskFailureClass() *failure.Class {
+	if s.taskComplete.FailureReason() == "" && s.loopExitClass != nil {
+		return s.loopExitClass
+	}
+	if class := failure.ClassFor(failure.CodeOf(s.lastAgentErr)); class != nil {
+		return class
+	}
+	if s.taskComplete.FailureReason() == "" && s.loopExitReason != "" {
+		return failure.ErrProviderError
+	}
+	return failure.ErrMarkedFailed
+}
+
+// Confidence returns the confidence score the commander gave after checking
+// its work on a verified task, and false when there is none.
+func (s *Commander) Confidence() (float64, bool) {
+	return s.taskComplete.Confidence()
+}
+
 // ChosenRoute returns the route chosen by the commander, or "" if none.
 func (s *Commander) ChosenRoute() string {
 	return s.taskComplete.ChosenRoute()
@@ -1014,6 +1313,12 @@ func (s *Commander) MissionInputs() map[string]string {
 	return s.taskComplete.MissionInputs()
 }
 
+// MemoryStore returns the memory store the commander and its agents use,
+// or nil if none.
+func (s *Commander) MemoryStore() aitools.MemoryStore {
+	return s.memoryStore
+}
+
 // HasSequentialDataset returns true if this commander is processing a sequential dataset
 func (s *Commander) HasSequentialDataset() bool {
 	return s.datasetCursor != nil
@@ -1140,6 +1445,7 @@ func (s *Commander) ResumeTask(ctx context.Context, streamer CommanderStreamer)
 		if s.sessionLogger != nil && s.sessionID != "" {
 			s.sessionLogger.CompleteSession(s.sessionID, nil)
 		}
+		s.clearCheckpoint()
 		return nil
 	}
 
@@ -1167,7 +1473,7 @@ func (s *Commander) ResumeTask(ctx context.Context, streamer CommanderStreamer)
 				toolRecordID, _ = s.sessionLogger.StartToolCall(s.callbacksTaskID, s.sessionID, tc.ID, tc.Name, actionInput)
 			}
 
-			rawResult := tool.Call(ctx, actionInput)
+			rawResult := callToolTraced(ctx, tool, tc.Name, actionInput, attribute.String("squadron.tool_call_id", tc.ID))
 
 			// Same call_agent invariant as the main tool loop: if ctx
 			// canceled mid-call, leave no misleading "Tool call was
@@ -1185,7 +1491,7 @@ func (s *Commander) ResumeTask(ctx context.Context, streamer CommanderStreamer)
 
 			resultContent := result
 			if s.interceptor != nil {
-				ir := s.interceptor.Intercept(tc.Name, result)
+				ir := s.interceptor.InterceptCall(tc.Name, actionInput, result)
 				resultContent = ir.Data
 				if ir.Metadata != "" {
 					resultContent += "\n\n---\n" + ir.Metadata
@@ -1345,7 +1651,20 @@ func (s *Commander) AddRestoredActiveAgent(agentName string, a *Agent, sessionID
 // runLoop is the core execution loop shared by ExecuteTask and ResumeTask.
 // When resume=true, the first LLM call uses ContinueStream (no new user message,
 // no store logging) because the session already has a pending user message.
-func (s *Commander) runLoop(ctx context.Context, currentInput string, resume bool, streamer CommanderStreamer) error {
+func (s *Commander) runLoop(ctx context.Context, currentInput string, resume bool, streamer CommanderStreamer) (err error) {
+	ctx, span := tracing.Start(ctx, "commander "+s.TaskName,
+		attribute.String("squadron.task", s.TaskName),
+		attribute.String("squadron.model", s.ModelName),
+		attribute.Bool("squadron.commander.resumed", resume),
+	)
+	if s.iterationIndex != nil {
+		span.SetAttributes(attribute.Int("squadron.iteration", *s.iterationIndex))
+	}
+	defer func() { tracing.End(span, err) }()
+
+	s.session.SetCompactionObserver(func(ev llm.CompactionEvent) {
+		s.onSessionCompaction(ev, streamer)
+	})
 	firstTurn := true
 	for {
 		select {
@@ -1418,12 +1737,8 @@ func (s *Commander) runLoop(ctx context.Context, currentInput string, resume boo
 			s.debugLogger.LogEvent("commander_llm_end", eventData)
 		}
 
-		// Check if compaction is needed after response
-		if resp != nil {
-			s.checkAndCompact(resp.Usage.InputTokens, streamer)
-		}
-
-		// Apply turn limit pruning after compaction
+		// Apply turn limit pruning (compaction, if configured, already ran
+		// inside the session)
 		s.applyTurnPruning(streamer)
 
 		// Emit session turn telemetry
@@ -1457,6 +1772,8 @@ func (s *Commander) runLoop(ctx context.Context, currentInput string, resume boo
 					return err
 				}
 			}
+		} else if s.budget != nil {
+			s.budget.ReleaseCall()
 		}
 
 		relay.Close()
@@ -1535,7 +1852,7 @@ func (s *Commander) runLoop(ctx context.Context, currentInput string, resume boo
 			}
 			log.Printf("[Commander] Response hit max_tokens for task '%s' (attempt %d/3), sending correction...", s.TaskName, s.maxTokensRetries)
 			correction := "Your previous response hit the maximum output token limit and was truncated. Be more concise: shorten your reasoning, split the work into smaller tool calls, or call task_complete with a summary if you have enough context."
-			resp, err = s.session.SendStream(ctx, correction, onChunk)
+			resp, err = s.sendCorrection(ctx, correction, onChunk)
 			if err != nil {
 				return err
 			}
@@ -1553,19 +1870,33 @@ func (s *Commander) runLoop(ctx context.Context, currentInput string, resume boo
 		s.maxTokensRetries = 0
 
 		// If no tool calls, the commander produced text instead of calling a tool.
-		// Send a correction and retry, up to 3 times.
+		// Send a correction and retry, up to maxParserRecoveries times.
 		if len(toolUses) == 0 {
 			s.noToolCallRetries++
-			if s.noToolCallRetries > 3 {
-				s.loopExitReason = "commander failed to make a tool call after 3 correction attempts"
+			if s.noToolCallRetries > maxParserRecoveries {
+				s.loopExitReason = parserRecoveryError("commander").Error()
 				break
 			}
-			log.Printf("[Commander] No tool call on turn for task '%s' (attempt %d/3), sending correction...", s.TaskName, s.noToolCallRetries)
-			correction := "Invalid response. You must make a tool call. Either call additional tools to continue your work, or call task_complete if you are done."
-			resp, err = s.session.SendStream(ctx, correction, onChunk)
+			var content string
+			if resp != nil {
+				content = resp.Content
+			}
+			s.onParserRecovery(newParserRecoveryEvent(s.noToolCallRetries, content))
+			log.Printf("[Commander] No tool call on turn for task '%s' (attempt %d/%d), sending correction...", s.TaskName, s.noToolCallRetries, maxParserRecoveries)
+			correctionStart := time.Now()
+			resp, err = s.sendCorrection(ctx, commanderFormatCorrection, onChunk)
 			if err != nil {
 				return err
 			}
+			// Persist the exchange so a resume replays the same history
+			if s.sessionLogger != nil && s.sessionID != "" {
+				msg := llm.NewTextMessage(llm.RoleUser, commanderFormatCorrection)
+				s.sessionLogger.AppendStructuredMessage(s.sessionID, "user", commanderFormatCorrection, PartsFromMessage(msg), correctionStart, correctionStart)
+				if resp != nil {
+					asstMsg := llm.Message{Role: llm.RoleAssistant, Parts: resp.ContentBlocks, Content: resp.Content}
+					s.sessionLogger.AppendStructuredMessage(s.sessionID, "assistant", AuditContentForMessage(asstMsg), PartsFromMessage(asstMsg), correctionStart, time.Now())
+				}
+			}
 			// Re-extract tool calls from the correction response
 			toolUses = nil
 			if resp != nil {
@@ -1594,7 +1925,7 @@ func (s *Commander) runLoop(ctx context.Context, currentInput string, resume boo
 				s.debugLogger.LogEvent("tool_call", map[string]any{
 					"task":  s.TaskName,
 					"tool":  tc.Name,
-					"input": actionInput,
+					"input": s.redactor.Redact(actionInput),
 				})
 			}
 
@@ -1619,7 +1950,7 @@ func (s *Commander) runLoop(ctx context.Context, currentInput string, resume boo
 
 			// Execute the tool
 			toolStart := time.Now()
-			rawResult := tool.Call(ctx, actionInput)
+			rawResult := callToolTraced(ctx, tool, tc.Name, actionInput, attribute.String("squadron.tool_call_id", tc.ID))
 
 			// call_agent is special: when ctx is canceled mid-call, the agent's
 			// session is preserved and ResumeTask re-executes the call_agent on
@@ -1641,7 +1972,7 @@ func (s *Commander) runLoop(ctx context.Context, currentInput string, resume boo
 			// Apply result interception for large results
 			resultContent := result
 			if s.interceptor != nil {
-				ir := s.interceptor.Intercept(tc.Name, result)
+				ir := s.interceptor.InterceptCall(tc.Name, actionInput, result)
 				resultContent = ir.Data
 				if ir.Metadata != "" {
 					resultContent += "\n\n---\n" + ir.Metadata
@@ -1660,7 +1991,7 @@ func (s *Commander) runLoop(ctx context.Context, currentInput string, resume boo
 				s.debugLogger.LogEvent("tool_result", map[string]any{
 					"task":        s.TaskName,
 					"tool":        tc.Name,
-					"result":      result,
+					"result":      s.redactor.Redact(result),
 					"duration_ms": time.Since(toolStart).Milliseconds(),
 				})
 			}
@@ -1671,6 +2002,17 @@ func (s *Commander) runLoop(ctx context.Context, currentInput string, resume boo
 			}
 		}
 
+		// Count the turn against max_turns and the loop detector. A note for
+		// the model rides on the turn's last tool result.
+		stop := false
+		if ctx.Err() == nil && len(toolResults) > 0 && !s.isFullyCompleted() {
+			var note string
+			note, stop = s.checkTurn(toolUses)
+			if note != "" {
+				toolResults[len(toolResults)-1].Content += "\n\n" + note
+			}
+		}
+
 		// Send tool results back to the in-memory session for the next turn.
 		s.session.AddToolResults(toolResults)
 
@@ -1701,6 +2043,19 @@ func (s *Commander) runLoop(ctx context.Context, currentInput string, resume boo
 		if s.taskComplete.IsCompleted() {
 			break
 		}
+		if stop {
+			log.Printf("[Commander] Stopping task '%s': %s", s.TaskName, s.loopExitReason)
+			break
+		}
+
+		if ctx.Err() == nil {
+			s.maybeCheckpoint()
+		}
+	}
+
+	// A chat goes on after each reply; Close completes its session.
+	if s.interactive {
+		return nil
 	}
 
 	if s.turnLogger != nil {
@@ -1711,32 +2066,67 @@ func (s *Commander) runLoop(ctx context.Context, currentInput string, resume boo
 	if s.sessionLogger != nil && s.sessionID != "" {
 		s.sessionLogger.CompleteSession(s.sessionID, nil)
 	}
+	s.clearCheckpoint()
 
 	return nil
 }
 
-// checkAndCompact checks if compaction is needed and performs it if so
-func (s *Commander) checkAndCompact(inputTokens int, streamer CommanderStreamer) {
-	if s.compaction == nil || s.compaction.TokenLimit <= 0 {
-		return
+// A commander making exactly the same tool calls this many turns in a row is
+// told to change course, and stopped if it keeps going.
+const (
+	loopWarnRepeats = 3
+	loopStopRepeats = 5
+)
+
+// checkTurn counts a turn of tool calls against max_turns and looks for the
+// commander repeating itself. It returns a note to pass on to the model with
+// the turn's results, and reports whether the loop must stop, in which case
+// loopExitReason and loopExitClass say why.
+func (s *Commander) checkTurn(toolUses []llm.ToolUseBlock) (note string, stop bool) {
+	s.taskTurns++
+
+	var sb strings.Builder
+	for _, tc := range toolUses {
+		sb.WriteString(tc.Name)
+		sb.WriteByte(0)
+		sb.Write(tc.Input)
+		sb.WriteByte(0)
+	}
+	calls := sb.String()
+	if calls == s.lastTurnCalls {
+		s.turnRepeats++
+	} else {
+		s.lastTurnCalls = calls
+		s.turnRepeats = 1
 	}
 
-	if inputTokens <= s.compaction.TokenLimit {
-		return
+	if s.turnRepeats >= loopStopRepeats {
+		s.loopExitReason = fmt.Sprintf("commander repeated the same %s call %d turns in a row", toolUses[0].Name, s.turnRepeats)
+		s.loopExitClass = failure.ErrLoopDetected
+		return "", true
+	}
+	if s.maxTurns > 0 && s.taskTurns >= s.maxTurns {
+		s.loopExitReason = fmt.Sprintf("commander reached max_turns (%d) without completing the task", s.maxTurns)
+		s.loopExitClass = failure.ErrMaxTurns
+		return "", true
 	}
 
-	extraContext := s.buildCompactionContext()
-	compacted := s.session.CompactWithContext(s.compaction.TurnRetention, extraContext)
-	if compacted > 0 {
-		streamer.Compaction(inputTokens, s.compaction.TokenLimit, compacted, s.compaction.TurnRetention)
-		if s.debugLogger != nil {
-			s.debugLogger.LogEvent("commander_compaction", map[string]any{
-				"input_tokens":       inputTokens,
-				"token_limit":        s.compaction.TokenLimit,
-				"messages_compacted": compacted,
-				"turn_retention":     s.compaction.TurnRetention,
-			})
-		}
+	var notes []string
+	if s.turnRepeats >= loopWarnRepeats {
+		notes = append(notes, fmt.Sprintf("[SYSTEM] You have made exactly this call %d turns in a row. Repeating it will not change the outcome: try a different approach, or call task_complete with succeed=false if the task cannot be done. The task will be stopped if you repeat it again %d more times.", s.turnRepeats, loopStopRepeats-s.turnRepeats))
+	}
+	if s.maxTurns > 0 && s.taskTurns == s.maxTurns-1 {
+		notes = append(notes, fmt.Sprintf("[SYSTEM] This task is limited to %d turns and you have one left. Call task_complete in your next turn.", s.maxTurns))
+	}
+	return strings.Join(notes, "\n\n"), false
+}
+
+// onSessionCompaction reports an automatic session compaction to the
+// streamer and the debug log.
+func (s *Commander) onSessionCompaction(ev llm.CompactionEvent, streamer CommanderStreamer) {
+	streamer.Compaction(ev.InputTokens, ev.TokenLimit, ev.MessagesCompacted, ev.TurnRetention)
+	if s.debugLogger != nil {
+		s.debugLogger.LogEvent("commander_compaction", compactionEventData(ev))
 	}
 }
 
@@ -1780,6 +2170,41 @@ func (s *Commander) buildCompactionContext() string {
 	return sb.String()
 }
 
+// sendCorrection sends a corrective user message mid-turn. It is an LLM call
+// like any other, so it is checked against and charged to the budget.
+func (s *Commander) sendCorrection(ctx context.Context, text string, onChunk func(llm.StreamChunk)) (*llm.ChatResponse, error) {
+	return s.budgetedCall(func() (*llm.ChatResponse, error) {
+		return s.session.SendStream(ctx, text, onChunk)
+	})
+}
+
+// budgetedCall makes one LLM call through send, checking the budget before
+// it and recording its usage after (or releasing the reserved call when no
+// response came back).
+func (s *Commander) budgetedCall(send func() (*llm.ChatResponse, error)) (*llm.ChatResponse, error) {
+	if s.budget != nil {
+		if err := s.budget.CheckBudget(); err != nil {
+			return nil, err
+		}
+	}
+	resp, err := send()
+	if s.budget == nil {
+		return resp, err
+	}
+	if resp == nil {
+		s.budget.ReleaseCall()
+		return resp, err
+	}
+	var cost float64
+	if pricing := llm.GetPricing(s.ModelName, s.pricingOverrides); pricing != nil {
+		cost = llm.ComputeTurnCost(pricing, resp.Usage.InputTokens, resp.Usage.OutputTokens, resp.Usage.CacheReadTokens, resp.Usage.CacheWriteTokens).TotalCost
+	}
+	if budgetErr := s.budget.RecordUsage(resp.Usage.Total(), cost); budgetErr != nil {
+		return nil, budgetErr
+	}
+	return resp, err
+}
+
 // applyTurnPruning drops old messages when the conversation reaches the prune_on threshold,
 // reducing it down to prune_to turns.
 func (s *Commander) applyTurnPruning(streamer CommanderStreamer) {
@@ -1805,6 +2230,37 @@ func (s *Commander) applyTurnPruning(streamer CommanderStreamer) {
 	}
 }
 
+// Chat handles one message in an interactive chat (see
+// CommanderOptions.Interactive) and returns the commander's reply: the
+// summary it passes to task_complete. The conversation, and the agents the
+// commander has called, carry over from one message to the next.
+func (s *Commander) Chat(ctx context.Context, message string, streamer CommanderStreamer) (string, error) {
+	if !s.interactive {
+		return "", fmt.Errorf("commander %s is not interactive", s.Name)
+	}
+	s.taskComplete.Reset()
+	s.loopExitReason = ""
+	s.loopExitClass = nil
+	s.noToolCallRetries = 0
+	s.taskTurns = 0
+	s.lastTurnCalls = ""
+	s.turnRepeats = 0
+	if err := s.runLoop(ctx, message, false, streamer); err != nil {
+		return "", err
+	}
+	if !s.taskComplete.IsCompleted() {
+		return "", fmt.Errorf("no reply: %s", s.loopExitReason)
+	}
+	if !s.taskComplete.IsSucceeded() {
+		reply := "Could not complete the request: " + s.taskComplete.FailureReason()
+		streamer.Answer(reply)
+		return reply, nil
+	}
+	reply := s.taskComplete.Summary()
+	streamer.Answer(reply)
+	return reply, nil
+}
+
 // AnswerQuestion handles a follow-up question from another commander (via ask_commander)
 func (s *Commander) AnswerQuestion(ctx context.Context, question string) (string, error) {
 	prompt := fmt.Sprintf(`<QUESTION>
@@ -1822,6 +2278,29 @@ Please provide a concise, factual answer based on what you learned during your t
 	return resp.Content, nil
 }
 
+// PartialSummary asks the commander to sum up what it got done before the
+// mission was cancelled. It works on a copy of the session, with any tool
+// call cut off by the cancellation marked interrupted, so the stored
+// conversation stays as it was and the task can still be resumed. Returns
+// "" when the commander never got a turn.
+func (s *Commander) PartialSummary(ctx context.Context, reason string) (string, error) {
+	if s.session == nil || len(s.session.GetHistory()) == 0 {
+		return "", nil
+	}
+	session := s.session.Clone()
+	session.LoadMessages(HealSessionMessages(session.GetHistory()))
+
+	prompt := fmt.Sprintf(`<CANCELLED>
+The mission was cancelled: %s
+Do not call any tools. In a few sentences, summarize what you accomplished on your task so far, including any results worth keeping, and what remains unfinished.
+</CANCELLED>`, reason)
+	resp, err := session.Send(ctx, prompt)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(resp.Content), nil
+}
+
 // Close releases resources held by the commander
 func (s *Commander) Close() {
 	// Close any cached query clones (from ask_commander)
@@ -1847,6 +2326,16 @@ func (s *Commander) Close() {
 	}
 	s.completedAgents = nil
 
+	// runLoop leaves a chat's session open between replies
+	if s.interactive {
+		if s.turnLogger != nil {
+			s.turnLogger.Close()
+		}
+		if s.sessionLogger != nil && s.sessionID != "" {
+			s.sessionLogger.CompleteSession(s.sessionID, nil)
+		}
+	}
+
 	if s.session != nil {
 		s.session.Close()
 	}
@@ -1855,6 +2344,9 @@ func (s *Commander) Close() {
 			closer.Close()
 		}
 	}
+	if s.summaryProvider != nil {
+		closeProvider(s.summaryProvider)
+	}
 }
 
 // CloneForQuery creates an isolated copy of this commander for answering a question.
@@ -1895,6 +2387,8 @@ func (s *Commander) CloneForQuery() *Commander {
 		completedAgents: completedAgentsCopy,
 		debugLogger:     nil, // No debug logging for query clones
 	}
+	// Budgeted queries price their turns like the original commander
+	clone.pricingOverrides = s.pricingOverrides
 
 	// Add result tools
 	clone.tools["result_info"] = &aitools.ResultInfoTool{Store: resultStore}
@@ -1909,6 +2403,13 @@ func (s *Commander) CloneForQuery() *Commander {
 	return clone
 }
 
+// SetBudget sets the budget the commander's LLM calls are checked against and
+// charged to. Query clones start unbudgeted; whoever asks the question sets
+// its budget so usage lands on the asking task.
+func (s *Commander) SetBudget(budget BudgetChecker) {
+	s.budget = budget
+}
+
 // AnswerQueryIsolated answers a follow-up question using an isolated session.
 // This is called on a cloned commander and does not affect the original.
 // It runs an execution loop to handle any tool calls (like ask_agent) before returning.
@@ -1923,7 +2424,9 @@ Wrap your final answer in <ANSWER> tags.
 </QUESTION>`, question)
 
 	// First turn: send the question
-	resp, err := s.session.Send(ctx, currentInput)
+	resp, err := s.budgetedCall(func() (*llm.ChatResponse, error) {
+		return s.session.Send(ctx, currentInput)
+	})
 	if err != nil {
 		return "", err
 	}
@@ -1961,10 +2464,10 @@ Wrap your final answer in <ANSWER> tags.
 				continue
 			}
 
-			result := MaybeInterrupted(ctx, tool.Call(ctx, string(tc.Input)))
+			result := MaybeInterrupted(ctx, callToolTraced(ctx, tool, tc.Name, string(tc.Input), attribute.String("squadron.tool_call_id", tc.ID)))
 			resultContent := result
 			if s.interceptor != nil {
-				ir := s.interceptor.Intercept(tc.Name, result)
+				ir := s.interceptor.InterceptCall(tc.Name, string(tc.Input), result)
 				resultContent = ir.Data
 				if ir.Metadata != "" {
 					resultContent += "\n\n---\n" + ir.Metadata
@@ -1978,7 +2481,9 @@ Wrap your final answer in <ANSWER> tags.
 
 		// Send tool results and get next response
 		s.session.AddToolResults(toolResults)
-		resp, err = s.session.ContinueStream(ctx, func(chunk llm.StreamChunk) {})
+		resp, err = s.budgetedCall(func() (*llm.ChatResponse, error) {
+			return s.session.ContinueStream(ctx, func(chunk llm.StreamChunk) {})
+		})
 		if err != nil {
 			return "", err
 		}
@@ -2018,21 +2523,24 @@ func resolveCommander(cfg *config.Config, modelKey string) (*config.Model, strin
 	return nil, "", fmt.Errorf("no model config found for model '%s'", modelKey)
 }
 
-// createCommanderProvider creates the appropriate LLM provider based on config
+// createCommanderProvider creates the appropriate LLM provider based on
+// config, wrapped by wrapProvider
 func createCommanderProvider(ctx context.Context, modelConfig *config.Model) (llm.Provider, bool, error) {
 	switch modelConfig.Provider {
 	case config.ProviderOpenAI:
-		return llm.NewOpenAIProvider(modelConfig.APIKey, modelConfig.BaseURL), false, nil
+		return wrapProvider(llm.NewOpenAIProvider(modelConfig.APIKey, modelConfig.BaseURL), modelConfig), false, nil
 	case config.ProviderAnthropic:
-		return llm.NewAnthropicProvider(modelConfig.APIKey, modelConfig.BaseURL), false, nil
+		return wrapProvider(llm.NewAnthropicProvider(modelConfig.APIKey, modelConfig.BaseURL), modelConfig), false, nil
 	case config.ProviderGemini:
 		provider, err := llm.NewGeminiProvider(ctx, modelConfig.APIKey, modelConfig.BaseURL)
 		if err != nil {
 			return nil, false, err
 		}
-		return provider, true, nil
-	case config.ProviderOllama:
-		return llm.NewOpenAICompatibleProvider(modelConfig.BaseURL), false, nil
+		return wrapProvider(provider, modelConfig), true, nil
+	case config.ProviderOllama, config.ProviderOpenAICompatible:
+		return wrapProvider(llm.NewOpenAICompatibleProvider(modelConfig.BaseURL, modelConfig.APIKey), modelConfig), false, nil
+	case config.ProviderScripted:
+		return wrapProvider(scriptedProvider(modelConfig), modelConfig), false, nil
 	default:
 		return nil, false, fmt.Errorf("unknown provider: %s", modelConfig.Provider)
 	}
@@ -2100,16 +2608,30 @@ func (t *callAgentTool) Call(ctx context.Context, input string) string {
 	}
 
 	result, err := t.commander.agentMgr.RunAgent(ctx, params.Name, params.Task, params.Response)
+	t.commander.lastAgentErr = err
 	if err != nil {
 		return fmt.Sprintf("Error: %v", err)
 	}
 
+	// After a handoff, tell the commander which agent to talk to from now on
+	var prefix string
+	if result.HandedOffTo != "" {
+		prefix = fmt.Sprintf("[Handed off to agent '%s' — use that name for further call_agent or ask_agent calls]\n\n", result.HandedOffTo)
+	}
+
 	if result.AskCommander != "" {
-		return result.AskCommander
+		return prefix + result.AskCommander
 	}
 
 	if result.Complete {
-		return result.Answer
+		if result.Output != nil {
+			out, err := json.MarshalIndent(result.Output, "", "  ")
+			if err != nil {
+				return fmt.Sprintf("Error: %v", err)
+			}
+			return prefix + string(out)
+		}
+		return prefix + result.Answer
 	}
 
 	return "Agent did not produce a result. Call again to continue."
@@ -2276,6 +2798,84 @@ func (t *askCommanderTool) Call(ctx context.Context, input string) string {
 	return answer
 }
 
+// =============================================================================
+// askPriorCommanderTool - queries commanders from completed prior missions
+// =============================================================================
+
+// askPriorCommanderTool is the tool for querying commanders of a completed
+// mission referenced by the current run
+type askPriorCommanderTool struct {
+	ask      func(missionID, taskName string, iterationIndex int, question string) (string, error)
+	missions []PriorMissionInfo
+}
+
+func (t *askPriorCommanderTool) ToolName() string {
+	return "ask_prior_commander"
+}
+
+func (t *askPriorCommanderTool) ToolDescription() string {
+	var sb strings.Builder
+	sb.WriteString(`Ask a question to a commander from a completed prior mission. Use this to recover details from earlier runs that are not part of this mission's dependency chain.
+
+The queried commander answers from its stored context and can use ask_agent to query its own agents. Each question is answered from a fresh clone, so include any needed context in the question itself.
+
+**For iterated tasks:** Use the "index" parameter to query a specific iteration's commander.
+
+Available prior missions:`)
+	for _, m := range t.missions {
+		sb.WriteString(fmt.Sprintf("\n- %s (mission '%s'): tasks %s", m.ID, m.Name, strings.Join(m.Tasks, ", ")))
+	}
+	return sb.String()
+}
+
+func (t *askPriorCommanderTool) ToolPayloadSchema() aitools.Schema {
+	return aitools.Schema{
+		Type: aitools.TypeObject,
+		Properties: aitools.PropertyMap{
+			"mission_id": {
+				Type:        aitools.TypeString,
+				Description: "The ID of the prior mission",
+			},
+			"task_name": {
+				Type:        aitools.TypeString,
+				Description: "The name of the completed task in that mission",
+			},
+			"question": {
+				Type:        aitools.TypeString,
+				Description: "The question to ask the task's commander",
+			},
+			"index": {
+				Type:        aitools.TypeInteger,
+				Description: "For iterated tasks: the iteration index to query. Omit for regular tasks.",
+			},
+		},
+		Required: []string{"mission_id", "task_name", "question"},
+	}
+}
+
+func (t *askPriorCommanderTool) Call(ctx context.Context, input string) string {
+	var params struct {
+		MissionID string `json:"mission_id"`
+		TaskName  string `json:"task_name"`
+		Question  string `json:"question"`
+		Index     *int   `json:"index"`
+	}
+	if err := json.Unmarshal([]byte(input), &params); err != nil {
+		return fmt.Sprintf("Error: Invalid input: %v", err)
+	}
+
+	iterIndex := -1
+	if params.Index != nil {
+		iterIndex = *params.Index
+	}
+
+	answer, err := t.ask(params.MissionID, params.TaskName, iterIndex, params.Question)
+	if err != nil {
+		return fmt.Sprintf("Error: %v", err)
+	}
+	return answer
+}
+
 // =============================================================================
 // listCommanderQuestionsTool - lists questions asked to dependency commanders
 // =============================================================================
@@ -2408,7 +3008,7 @@ func (t *queryTaskOutputTool) ToolDescription() string {
 **Note:** For narrative summaries or detailed explanations, use ask_commander instead.
 
 **Query modes:**
-1. Get structured output: {"task": "task_name"}
+1. Get structured output: {"task": "task_name"} (for an iterated task with an aggregate block, returns the aggregate)
 2. Filter iterations: {"task": "task_name", "filters": [{"field": "temperature", "op": "lt", "value": 32}]}
 3. Get specific items: {"task": "task_name", "item_ids": ["Chicago_IL", "Detroit_MI"]}
 4. Aggregate: {"task": "task_name", "aggregate": {"op": "avg", "field": "temperature"}}
@@ -2520,6 +3120,13 @@ func (t *queryTaskOutputTool) Call(ctx context.Context, input string) string {
 		return formatTaskOutput(output)
 	}
 
+	// Iterated tasks with an aggregate block return the aggregate unless the
+	// call asks for individual iterations
+	if len(output.Output) > 0 && len(params.Filters) == 0 && len(params.ItemIDs) == 0 &&
+		params.Limit == 0 && params.Offset == 0 && params.OrderBy == "" {
+		return formatTaskOutput(output) + fmt.Sprintf("\n\nAggregated from %d iterations. Pass filters, item_ids or limit to read individual iterations.", output.TotalIterations)
+	}
+
 	// For iterated tasks, handle query/filter
 	if len(params.ItemIDs) > 0 {
 		// Return specific items by ID
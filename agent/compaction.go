@@ -0,0 +1,95 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+
+	"squadron/config"
+	"squadron/llm"
+)
+
+// compactionSettings picks the compaction settings for a session: the
+// agent's or commander's own compaction block wins, otherwise the model
+// config's `compaction "<model>"` defaults for the session model apply.
+func compactionSettings(own *CompactionConfig, modelConfig *config.Model, modelKey string) *CompactionConfig {
+	if own != nil {
+		return own
+	}
+	if c := modelConfig.CompactionFor(modelKey); c != nil {
+		return compactionFromConfig(c)
+	}
+	return nil
+}
+
+// compactionFromConfig converts a config compaction block (nil-safe).
+func compactionFromConfig(c *config.Compaction) *CompactionConfig {
+	if c == nil {
+		return nil
+	}
+	return &CompactionConfig{
+		TokenLimit:    c.TokenLimit,
+		TurnRetention: c.TurnRetention,
+		SummaryModel:  c.SummaryModel,
+	}
+}
+
+// compactionPolicy builds the session compaction policy. The summary model
+// reuses the session provider when it belongs to the same model config (or
+// when a provider was injected); otherwise a provider is created for it and
+// returned as owned so the caller can close it.
+func compactionPolicy(ctx context.Context, cfg *config.Config, settings *CompactionConfig, modelConfig *config.Model, provider llm.Provider, injected bool) (*llm.CompactionPolicy, llm.Provider, error) {
+	if settings == nil || settings.TokenLimit <= 0 {
+		return nil, nil, nil
+	}
+	policy := &llm.CompactionPolicy{
+		TokenLimit:    settings.TokenLimit,
+		TurnRetention: settings.TurnRetention,
+	}
+	if settings.SummaryModel == "" {
+		return policy, nil, nil
+	}
+
+	summaryConfig, apiName, err := resolveCommander(cfg, settings.SummaryModel)
+	if err != nil {
+		return nil, nil, fmt.Errorf("compaction summary model: %w", err)
+	}
+	policy.SummaryModel = apiName
+	if injected || summaryConfig == modelConfig {
+		policy.SummaryProvider = provider
+		return policy, nil, nil
+	}
+
+	if summaryConfig.Provider.RequiresAPIKey() && summaryConfig.APIKey == "" {
+		return nil, nil, fmt.Errorf("API key not set for model '%s'", summaryConfig.Name)
+	}
+	summaryProvider, owns, err := createProvider(ctx, summaryConfig)
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating compaction summary provider: %w", err)
+	}
+	policy.SummaryProvider = summaryProvider
+	if owns {
+		return policy, summaryProvider, nil
+	}
+	return policy, nil, nil
+}
+
+// closeProvider closes a provider that holds resources (e.g. Gemini).
+func closeProvider(p llm.Provider) {
+	if closer, ok := p.(interface{ Close() }); ok {
+		closer.Close()
+	}
+}
+
+// compactionEventData formats a compaction for the debug event log.
+func compactionEventData(ev llm.CompactionEvent) map[string]any {
+	data := map[string]any{
+		"input_tokens":       ev.InputTokens,
+		"token_limit":        ev.TokenLimit,
+		"messages_compacted": ev.MessagesCompacted,
+		"turn_retention":     ev.TurnRetention,
+	}
+	if ev.SummaryModel != "" {
+		data["summary_model"] = ev.SummaryModel
+	}
+	return data
+}
@@ -0,0 +1,182 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"squadron/aitools"
+	"squadron/llm"
+)
+
+// maxAgentHandoffs caps how many times one call_agent can be handed on, so
+// agents cannot pass work back and forth forever.
+const maxAgentHandoffs = 5
+
+// Handoff context modes
+const (
+	HandoffSummary  = "summary"  // the handing-off agent writes a summary
+	HandoffVerbatim = "verbatim" // the agent's conversation is passed as a transcript
+)
+
+// Handoff is an agent's request to pass its work to another agent under the
+// same commander.
+type Handoff struct {
+	From    string // Agent handing off
+	To      string // Agent taking over
+	Task    string // What the receiving agent should do
+	Mode    string // HandoffSummary or HandoffVerbatim
+	Context string // Summary, or the transcript once the manager fills it in
+}
+
+// message is the NEW_TASK input for the receiving agent.
+func (h *Handoff) message() string {
+	return fmt.Sprintf("<NEW_TASK>\n<HANDOFF from=%q context=%q>\n%s\n</HANDOFF>\n\n%s\n</NEW_TASK>", h.From, h.Mode, h.Context, h.Task)
+}
+
+// handoffTool lets a mission agent hand its task to a peer agent. The call
+// only records the request; the orchestrator ends the agent's turn after the
+// tool results are stored and the AgentManager runs the receiving agent.
+type handoffTool struct {
+	from  string
+	peers map[string]string // agent name → personality
+
+	mu      sync.Mutex
+	pending *Handoff
+}
+
+func (t *handoffTool) ToolName() string {
+	return "handoff_to_agent"
+}
+
+func (t *handoffTool) ToolDescription() string {
+	var sb strings.Builder
+	sb.WriteString(`Hand your task over to another agent, passing along your working context so the commander doesn't have to brief them. Your work ends when the handoff is accepted; the other agent's answer is returned to the commander.
+
+Use context "summary" (default) and write a summary of everything the other agent needs, or context "verbatim" to pass your whole conversation. Call this tool on its own, not alongside other tools.
+
+Available agents:`)
+	for _, name := range t.peerNames() {
+		fmt.Fprintf(&sb, "\n- %s: %s", name, t.peers[name])
+	}
+	return sb.String()
+}
+
+func (t *handoffTool) ToolPayloadSchema() aitools.Schema {
+	return aitools.Schema{
+		Type: aitools.TypeObject,
+		Properties: aitools.PropertyMap{
+			"agent": {
+				Type:        aitools.TypeString,
+				Description: "Name of the agent to hand off to",
+			},
+			"task": {
+				Type:        aitools.TypeString,
+				Description: "What the other agent should do next",
+			},
+			"context": {
+				Type:        aitools.TypeString,
+				Description: `How to pass your context: "summary" (default) or "verbatim"`,
+			},
+			"summary": {
+				Type:        aitools.TypeString,
+				Description: `Everything the other agent needs to know (required for "summary")`,
+			},
+		},
+		Required: []string{"agent", "task"},
+	}
+}
+
+func (t *handoffTool) Call(ctx context.Context, input string) string {
+	var params struct {
+		Agent   string `json:"agent"`
+		Task    string `json:"task"`
+		Context string `json:"context"`
+		Summary string `json:"summary"`
+	}
+	if err := json.Unmarshal([]byte(input), &params); err != nil {
+		return fmt.Sprintf("Error: Invalid input: %v", err)
+	}
+	if params.Agent == t.from {
+		return "Error: Cannot hand off to yourself"
+	}
+	if _, ok := t.peers[params.Agent]; !ok {
+		return fmt.Sprintf("Error: Agent '%s' not found. Available agents: %v", params.Agent, t.peerNames())
+	}
+	if params.Task == "" {
+		return "Error: 'task' is required"
+	}
+	if params.Context == "" {
+		params.Context = HandoffSummary
+	}
+	switch params.Context {
+	case HandoffSummary:
+		if params.Summary == "" {
+			return `Error: 'summary' is required when context is "summary"`
+		}
+	case HandoffVerbatim:
+	default:
+		return fmt.Sprintf(`Error: context must be "summary" or "verbatim", got '%s'`, params.Context)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pending = &Handoff{
+		From:    t.from,
+		To:      params.Agent,
+		Task:    params.Task,
+		Mode:    params.Context,
+		Context: params.Summary,
+	}
+	return fmt.Sprintf("Handoff to '%s' accepted. Stop working; '%s' takes over.", params.Agent, params.Agent)
+}
+
+// take returns and clears the pending handoff, if any.
+func (t *handoffTool) take() *Handoff {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	h := t.pending
+	t.pending = nil
+	return h
+}
+
+func (t *handoffTool) peerNames() []string {
+	names := make([]string, 0, len(t.peers))
+	for name := range t.peers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// takeHandoff returns a handoff requested during the last tool round.
+func takeHandoff(tools map[string]aitools.Tool) *Handoff {
+	if t, ok := tools["handoff_to_agent"].(*handoffTool); ok {
+		return t.take()
+	}
+	return nil
+}
+
+// enableHandoff gives the agent the handoff_to_agent tool for the given
+// peers (name → personality). The agent itself is never a target.
+func (a *Agent) enableHandoff(peers map[string]string) {
+	targets := make(map[string]string, len(peers))
+	for name, personality := range peers {
+		if name != a.Name {
+			targets[name] = personality
+		}
+	}
+	if len(targets) == 0 {
+		return
+	}
+	a.tools["handoff_to_agent"] = &handoffTool{from: a.Name, peers: targets}
+	a.session.SetTools(aitools.ToolsToDefinitions(a.tools))
+}
+
+// handoffTranscript renders the agent's conversation for a verbatim handoff.
+func (a *Agent) handoffTranscript() string {
+	return llm.RenderTranscript(a.session.SnapshotMessages())
+}
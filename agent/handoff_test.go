@@ -0,0 +1,98 @@
+package agent
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"squadron/aitools"
+	"squadron/llm"
+)
+
+func newTestHandoffTool() *handoffTool {
+	return &handoffTool{
+		from:  "researcher",
+		peers: map[string]string{"writer": "Writes reports"},
+	}
+}
+
+func TestHandoffTool_ValidatesInput(t *testing.T) {
+	cases := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"self", `{"agent":"researcher","task":"t","summary":"s"}`, "Cannot hand off to yourself"},
+		{"unknown agent", `{"agent":"nobody","task":"t","summary":"s"}`, "Agent 'nobody' not found"},
+		{"missing task", `{"agent":"writer","summary":"s"}`, "'task' is required"},
+		{"missing summary", `{"agent":"writer","task":"t"}`, "'summary' is required"},
+		{"bad context", `{"agent":"writer","task":"t","context":"all"}`, "context must be"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tool := newTestHandoffTool()
+			got := tool.Call(context.Background(), tc.input)
+			if !strings.Contains(got, tc.want) {
+				t.Fatalf("expected %q in %q", tc.want, got)
+			}
+			if tool.take() != nil {
+				t.Fatal("invalid input must not record a handoff")
+			}
+		})
+	}
+}
+
+func TestHandoffTool_RecordsHandoff(t *testing.T) {
+	tool := newTestHandoffTool()
+	got := tool.Call(context.Background(), `{"agent":"writer","task":"write it up","summary":"found 3 sources"}`)
+	if !strings.Contains(got, "accepted") {
+		t.Fatalf("expected acceptance, got %q", got)
+	}
+
+	h := tool.take()
+	if h == nil {
+		t.Fatal("expected a pending handoff")
+	}
+	if h.From != "researcher" || h.To != "writer" || h.Mode != HandoffSummary || h.Context != "found 3 sources" {
+		t.Fatalf("unexpected handoff: %+v", h)
+	}
+	if tool.take() != nil {
+		t.Fatal("take should clear the pending handoff")
+	}
+
+	msg := h.message()
+	for _, want := range []string{"<NEW_TASK>", `<HANDOFF from="researcher" context="summary">`, "found 3 sources", "write it up"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("handoff message missing %q:\n%s", want, msg)
+		}
+	}
+}
+
+func TestOrchestrator_HandoffEndsTurn(t *testing.T) {
+	session := &fakeSession{
+		responses: []*llm.ChatResponse{
+			toolUseResponse("tc_1", "handoff_to_agent", `{"agent":"writer","task":"write it up","context":"verbatim"}`, "tool_use"),
+			textResponse("<ANSWER>should not be reached</ANSWER>", "end_turn"),
+		},
+	}
+	o := newTestOrchestrator(session, &mockStreamer{})
+	o.tools = map[string]aitools.Tool{"handoff_to_agent": newTestHandoffTool()}
+
+	result, err := o.processTurn(context.Background(), "go", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Handoff == nil || result.Handoff.To != "writer" || result.Handoff.Mode != HandoffVerbatim {
+		t.Fatalf("expected verbatim handoff to writer, got %+v", result)
+	}
+	if result.Complete {
+		t.Fatal("handoff result should not be marked complete")
+	}
+	// The handoff tool call gets its result so the session stays well-formed
+	if len(session.toolResults) != 1 || !strings.Contains(session.toolResults[0][0].Content, "accepted") {
+		t.Fatalf("expected the handoff tool result in the session, got %+v", session.toolResults)
+	}
+	if len(session.calls) != 1 {
+		t.Fatalf("expected a single LLM call, got %v", session.calls)
+	}
+}
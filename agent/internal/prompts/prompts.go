@@ -149,6 +149,7 @@ func getRules(mode config.AgentMode) string {
 type AgentInfo struct {
 	Name        string
 	Description string
+	Reason      string // why the agent was picked for the task, if by capability
 }
 
 // SecretInfo contains name and description for a secret (passed to prompts)
@@ -166,7 +167,42 @@ type IterationOptions struct {
 // GetCommanderPrompt returns the commander system prompt with available agents injected
 func GetCommanderPrompt(agents []AgentInfo, iterOpts IterationOptions) string {
 	prompt := commanderPromptTemplate
+	prompt = strings.Replace(prompt, "{{MODE_INSTRUCTIONS}}", commanderMissionModeInstructions, 1)
+	prompt = strings.Replace(prompt, "{{SUBTASK_PLANNING}}", commanderSubtaskPlanning, 1)
+	return injectCommanderContext(prompt, agents, iterOpts)
+}
+
+// GetCommanderChatPrompt returns the commander system prompt for an
+// interactive chat with a human, outside any mission.
+func GetCommanderChatPrompt(agents []AgentInfo) string {
+	prompt := commanderPromptTemplate
+	prompt = strings.Replace(prompt, "{{MODE_INSTRUCTIONS}}", commanderChatModeInstructions, 1)
+	prompt = strings.Replace(prompt, "{{SUBTASK_PLANNING}}", "", 1)
+	return injectCommanderContext(prompt, agents, IterationOptions{})
+}
+
+const commanderMissionModeInstructions = "**MISSION MODE:** You are running as part of an automated mission. Continue until the task is fully complete, then call `task_complete` with a `summary`. Be autonomous — make reasonable assumptions, don't ask clarifying questions. Use `ask_commander` if dependency summaries lack detail."
+
+const commanderChatModeInstructions = `**CHAT MODE:** You are chatting interactively with a human operator, outside any mission. Each user message is a new request in the same conversation.
+- Handle each request by calling agents as needed, then call ` + "`task_complete`" + ` with a ` + "`summary`" + ` — the summary is your reply to the human and is shown to them as your answer.
+- ` + "`task_complete`" + ` ends your turn, not the conversation. The human may follow up; earlier agent results stay available through ` + "`ask_agent`" + `.
+- When a request is ambiguous, reply with a clarifying question in the summary instead of guessing.
+- There are no dependency tasks or downstream tasks in chat mode.`
 
+const commanderSubtaskPlanning = `## Mandatory Subtask Planning
+
+Before doing ANY work, you MUST call ` + "`set_subtasks`" + ` to break the task into 1-10 ordered subtasks.
+
+1. Your FIRST action must ALWAYS be ` + "`set_subtasks`" + `
+2. Subtasks are solved sequentially — finish one before the next
+3. **ALL subtasks must be completed before ` + "`task_complete`" + `.** Always call ` + "`complete_subtask`" + ` for every subtask, including the last one.
+4. Once you complete the first subtask, the plan is locked
+
+`
+
+// injectCommanderContext fills in the agents and iteration sections of a
+// commander prompt.
+func injectCommanderContext(prompt string, agents []AgentInfo, iterOpts IterationOptions) string {
 	// Inject agents
 	agentsDescription := formatAgents(agents)
 	prompt = strings.Replace(prompt, "{{AGENTS}}", agentsDescription, 1)
@@ -225,6 +261,10 @@ func formatAgents(agents []AgentInfo) string {
 
 	var sb strings.Builder
 	for _, agent := range agents {
+		if agent.Reason != "" {
+			sb.WriteString(fmt.Sprintf("- **%s**: %s (%s)\n", agent.Name, agent.Description, agent.Reason))
+			continue
+		}
 		sb.WriteString(fmt.Sprintf("- **%s**: %s\n", agent.Name, agent.Description))
 	}
 
@@ -243,7 +283,7 @@ func FormatMemoryContext(store aitools.MemoryStore) string {
 
 	var sb strings.Builder
 	sb.WriteString("## Available Slots\n\n")
-	sb.WriteString("You have access to file storage slots via the file_list, file_read, file_create, file_delete, file_search, and file_grep tools.\n")
+	sb.WriteString("You have access to file storage slots via the file_list, file_read, file_create, file_delete, file_search, file_glob, and file_grep tools.\n")
 	sb.WriteString("The `slot` parameter is required on every call — pick one of the slot names below.\n\n")
 
 	for _, info := range infos {
@@ -253,6 +293,8 @@ func FormatMemoryContext(store aitools.MemoryStore) string {
 			label = " (persistent mission memory — survives across runs)"
 		case info.Name == aitools.ScratchpadSlotName:
 			label = " (ephemeral per-run scratchpad — fresh for this mission run)"
+		case info.Name == aitools.WorkspaceSlotName:
+			label = " (private workspace for this task — not visible to other tasks, cleared when the task completes)"
 		case aitools.IsPacketSlot(info.Name):
 			label = " (packet bundle — read-only reference data, UTF-8 text files only)"
 		}
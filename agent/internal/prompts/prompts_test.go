@@ -82,3 +82,64 @@ var _ = Describe("FormatMemoryContext", func() {
 		Expect(zIdx).To(BeNumerically("<", aIdx), "expected store order preserved (zeta first)")
 	})
 })
+
+var _ = Describe("Commander prompts", func() {
+	agents := []prompts.AgentInfo{{Name: "scout", Description: "Finds things"}}
+
+	It("requires subtask planning in mission mode", func() {
+		got := prompts.GetCommanderPrompt(agents, prompts.IterationOptions{})
+		Expect(got).To(ContainSubstring("MISSION MODE"))
+		Expect(got).To(ContainSubstring("## Mandatory Subtask Planning"))
+		Expect(got).To(ContainSubstring("**scout**: Finds things"))
+		Expect(got).NotTo(ContainSubstring("{{"))
+	})
+
+	It("drops subtask planning and explains replies in chat mode", func() {
+		got := prompts.GetCommanderChatPrompt(agents)
+		Expect(got).To(ContainSubstring("CHAT MODE"))
+		Expect(got).NotTo(ContainSubstring("MISSION MODE"))
+		Expect(got).NotTo(ContainSubstring("set_subtasks"))
+		Expect(got).To(ContainSubstring("**scout**: Finds things"))
+		Expect(got).NotTo(ContainSubstring("{{"))
+	})
+
+	It("says why an agent was picked by capability", func() {
+		got := prompts.GetCommanderPrompt([]prompts.AgentInfo{
+			{Name: "scout", Description: "Finds things", Reason: "chosen because it declares the required capabilities web"},
+		}, prompts.IterationOptions{})
+		Expect(got).To(ContainSubstring("- **scout**: Finds things (chosen because it declares the required capabilities web)"))
+	})
+})
+
+var _ = Describe("Prompt templates", func() {
+	It("renders a commander template with the built-in prompt and context", func() {
+		got, err := prompts.RenderCommanderTemplate(
+			"{{.Default}}\nMission {{.Mission}}, task {{.Task}}.\n{{range .Agents}}[{{.Name}}]{{end}}\n{{.AgentList}}{{if .IsIteration}}iterated{{end}}\n{{.Schema}}",
+			prompts.CommanderTemplateData{
+				Default:     "BUILT-IN",
+				Mission:     "research",
+				Task:        "fetch",
+				Agents:      []prompts.AgentInfo{{Name: "scout", Description: "Finds things"}},
+				IsIteration: true,
+				Schema:      "- **title** (string)",
+			})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(got).To(HavePrefix("BUILT-IN\nMission research, task fetch.\n[scout]\n- **scout**: Finds things\niterated\n- **title** (string)"))
+	})
+
+	It("renders an agent template", func() {
+		got, err := prompts.RenderAgentTemplate("{{.Name}} ({{.Mode}}): {{.Personality}}{{range .Skills}} +{{.Name}}{{end}}", prompts.AgentTemplateData{
+			Name:        "scout",
+			Personality: "Curious",
+			Mode:        "mission",
+			Skills:      []prompts.SkillInfo{{Name: "search"}},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(got).To(Equal("scout (mission): Curious +search"))
+	})
+
+	It("fails on a field the template data doesn't have", func() {
+		_, err := prompts.RenderAgentTemplate("{{.Objective}}", prompts.AgentTemplateData{Name: "scout"})
+		Expect(err).To(MatchError(ContainSubstring("Objective")))
+	})
+})
@@ -0,0 +1,60 @@
+package prompts
+
+import (
+	"strings"
+	"text/template"
+)
+
+// CommanderTemplateData is what a mission commander's prompt_template is
+// executed with.
+type CommanderTemplateData struct {
+	// Default is the built-in prompt. {{.Default}} extends it instead of
+	// replacing it.
+	Default string
+	Mission string
+	Task    string
+	Agents  []AgentInfo
+	// AgentList is Agents formatted the way the built-in prompt lists them.
+	AgentList   string
+	IsIteration bool
+	IsParallel  bool
+	// Schema lists the task's output fields, or is empty if it has none.
+	Schema string
+}
+
+// AgentTemplateData is what an agent's prompt_template is executed with.
+type AgentTemplateData struct {
+	// Default is the built-in prompt. {{.Default}} extends it instead of
+	// replacing it.
+	Default     string
+	Name        string
+	Personality string
+	Mode        string // "mission" or "chat"
+	Secrets     []SecretInfo
+	Skills      []SkillInfo
+}
+
+// RenderCommanderTemplate executes a commander prompt_template in place of
+// the built-in prompt. AgentList is filled in from Agents.
+func RenderCommanderTemplate(text string, data CommanderTemplateData) (string, error) {
+	data.AgentList = formatAgents(data.Agents)
+	return renderTemplate("commander", text, data)
+}
+
+// RenderAgentTemplate executes an agent prompt_template in place of the
+// built-in prompt.
+func RenderAgentTemplate(text string, data AgentTemplateData) (string, error) {
+	return renderTemplate("agent "+data.Name, text, data)
+}
+
+func renderTemplate(name, text string, data any) (string, error) {
+	t, err := template.New(name).Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	if err := t.Execute(&sb, data); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
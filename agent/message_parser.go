@@ -116,3 +116,18 @@ func (p *MessageParser) processBuffer() {
 		}
 	}
 }
+
+// answerFromContent extracts the <ANSWER> text from a complete response. It
+// backs up the streaming parser, which sees nothing when a stream was retried
+// (the session suppresses chunks on retries). Returns "" without the tag.
+func answerFromContent(content string) string {
+	start := strings.Index(content, "<ANSWER>")
+	if start == -1 {
+		return ""
+	}
+	content = content[start+len("<ANSWER>"):]
+	if end := strings.Index(content, "</ANSWER>"); end != -1 {
+		content = content[:end]
+	}
+	return strings.TrimSpace(content)
+}
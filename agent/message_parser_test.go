@@ -185,3 +185,16 @@ func TestLongAnswerStreaming(t *testing.T) {
 		t.Fatalf("answer too short: %d chars, expected ~%d", len(got), len(expected))
 	}
 }
+
+func TestAnswerFromContent(t *testing.T) {
+	cases := map[string]string{
+		"Done.\n<ANSWER>\n42\n</ANSWER>": "42",
+		"<ANSWER>cut off":                "cut off",
+		"no tags here":                   "",
+	}
+	for content, want := range cases {
+		if got := answerFromContent(content); got != want {
+			t.Errorf("answerFromContent(%q) = %q, want %q", content, got, want)
+		}
+	}
+}
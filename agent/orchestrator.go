@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/mlund01/squadron-wire/protocol"
+	"go.opentelemetry.io/otel/attribute"
 
 	"squadron/aitools"
 	"squadron/llm"
@@ -76,8 +77,6 @@ type orchestrator struct {
 	eventLogger    EventLogger
 	turnLogger     *llm.TurnLogger
 	secretInjector *secretInjector
-	compaction     *CompactionConfig
-	onCompaction   func(inputTokens int, tokenLimit int, messagesCompacted int, turnRetention int)
 	onSessionTurn  func(data protocol.SessionTurnData)
 	modelName      string
 	sessionLogger    SessionLogger
@@ -86,10 +85,20 @@ type orchestrator struct {
 	pricingOverrides map[string]*llm.ModelPricing
 	budget           BudgetChecker
 	maxTokensRetries int // Count of consecutive max_tokens truncation retries
+	// parserRecovery re-prompts a turn that ends with neither a tool call nor
+	// an answer (mission mode only — chat replies need no ANSWER tags).
+	parserRecovery   bool
+	parserRecoveries int // Count of consecutive format corrections
+	onParserRecovery func(ParserRecoveryEvent)
+	// submitOutput is set for agents with an output schema; an answer only
+	// completes the turn once it holds more than outputMark results.
+	submitOutput      *aitools.SubmitOutputTool
+	outputMark        int
+	outputCorrections int // Count of answers given without submit_output
 }
 
 // newOrchestrator creates a new chat orchestrator
-func newOrchestrator(session llmSession, streamer streamers.ChatHandler, tools map[string]aitools.Tool, interceptor *aitools.ResultInterceptor, pruningManager *llm.PruningManager, eventLogger EventLogger, turnLogger *llm.TurnLogger, secretValues map[string]string, compaction *CompactionConfig) *orchestrator {
+func newOrchestrator(session llmSession, streamer streamers.ChatHandler, tools map[string]aitools.Tool, interceptor *aitools.ResultInterceptor, pruningManager *llm.PruningManager, eventLogger EventLogger, turnLogger *llm.TurnLogger, secretValues map[string]string) *orchestrator {
 	return &orchestrator{
 		session:        session,
 		streamer:       streamer,
@@ -99,7 +108,6 @@ func newOrchestrator(session llmSession, streamer streamers.ChatHandler, tools m
 		eventLogger:    eventLogger,
 		turnLogger:     turnLogger,
 		secretInjector: newSecretInjector(secretValues),
-		compaction:     compaction,
 	}
 }
 
@@ -200,12 +208,8 @@ func (o *orchestrator) processTurn(ctx context.Context, input string, resume boo
 			resp, err = o.session.ContinueStream(ctx, onChunk)
 		}
 
-		// Check if compaction is needed after response
-		if resp != nil {
-			o.checkAndCompact(resp.Usage.InputTokens)
-		}
-
-		// Apply threshold-based pruning after each response
+		// Apply threshold-based pruning after each response (compaction, if
+		// configured, already ran inside the session)
 		o.applyTurnPruning()
 
 		// Emit session turn telemetry
@@ -248,6 +252,8 @@ func (o *orchestrator) processTurn(ctx context.Context, input string, resume boo
 				o.streamer.Error(err)
 				return ChatResult{}, err
 			}
+		} else if o.budget != nil {
+			o.budget.ReleaseCall()
 		}
 
 		if o.eventLogger != nil {
@@ -308,6 +314,10 @@ func (o *orchestrator) processTurn(ctx context.Context, input string, resume boo
 		// Capture the answer if one was provided via <ANSWER> tag
 		if answer := parser.GetAnswer(); answer != "" {
 			finalAnswer = answer
+		} else if resp != nil {
+			if answer := answerFromContent(resp.Content); answer != "" {
+				finalAnswer = answer
+			}
 		}
 
 		// Handle max_tokens truncation before processing tool uses. Any partial
@@ -350,7 +360,7 @@ func (o *orchestrator) processTurn(ctx context.Context, input string, resume boo
 			}
 			log.Printf("[Agent] Response hit max_tokens (attempt %d/3), sending correction...", o.maxTokensRetries)
 			correction := "Your previous response hit the maximum output token limit and was truncated. Be more concise: shorten your reasoning, split the work into smaller tool calls, or finish with <ANSWER>...</ANSWER> if you have enough context."
-			resp, err = o.session.SendStream(ctx, correction, onChunk)
+			resp, err = o.sendCorrection(ctx, correction, onChunk)
 			if err != nil {
 				o.streamer.Error(err)
 				return ChatResult{}, err
@@ -368,10 +378,48 @@ func (o *orchestrator) processTurn(ctx context.Context, input string, resume boo
 		}
 		o.maxTokensRetries = 0
 
-		// If no tool calls, we're done with this turn
+		// If no tool calls, we're done with this turn — unless a mission agent
+		// gave no answer either, which would leave the commander with nothing.
+		// Re-prompt it with a format correction, up to maxParserRecoveries times.
 		if len(toolUses) == 0 {
-			break
+			if o.missingOutput(finalAnswer) && ctx.Err() == nil {
+				o.outputCorrections++
+				if o.outputCorrections > maxParserRecoveries {
+					err := fmt.Errorf("agent answered without calling submit_output after %d corrections", maxParserRecoveries)
+					o.streamer.Error(err)
+					return ChatResult{}, err
+				}
+				log.Printf("[Agent] Answer without submit_output (attempt %d/%d), sending correction...", o.outputCorrections, maxParserRecoveries)
+				finalAnswer = ""
+				input = agentOutputCorrection
+				currentParts = []llm.ContentBlock{{Type: llm.ContentTypeText, Text: agentOutputCorrection}}
+				firstTurn = true
+				continue
+			}
+			if !o.parserRecovery || finalAnswer != "" || ctx.Err() != nil {
+				o.parserRecoveries = 0
+				break
+			}
+			o.parserRecoveries++
+			if o.parserRecoveries > maxParserRecoveries {
+				err := parserRecoveryError("agent")
+				o.streamer.Error(err)
+				return ChatResult{}, err
+			}
+			var content string
+			if resp != nil {
+				content = resp.Content
+			}
+			if o.onParserRecovery != nil {
+				o.onParserRecovery(newParserRecoveryEvent(o.parserRecoveries, content))
+			}
+			log.Printf("[Agent] No tool call and no answer (attempt %d/%d), sending correction...", o.parserRecoveries, maxParserRecoveries)
+			input = agentFormatCorrection
+			currentParts = []llm.ContentBlock{{Type: llm.ContentTypeText, Text: agentFormatCorrection}}
+			firstTurn = true
+			continue
 		}
+		o.parserRecoveries = 0
 
 		// Check for cancellation before executing tools
 		if ctx.Err() != nil {
@@ -457,8 +505,16 @@ func (o *orchestrator) processTurn(ctx context.Context, input string, resume boo
 				})
 			}
 
+			toolCtx := ctx
+			if ph, ok := o.streamer.(streamers.ToolProgressHandler); ok {
+				toolCallID, toolName := tc.ID, tc.Name
+				toolCtx = aitools.WithToolProgress(ctx, func(content string) {
+					ph.ToolProgress(toolCallID, toolName, content)
+				})
+			}
+
 			toolStart := time.Now()
-			result := MaybeInterrupted(ctx, tool.Call(ctx, injectedInput))
+			result := MaybeInterrupted(ctx, callToolTraced(toolCtx, tool, tc.Name, injectedInput, attribute.String("squadron.tool_call_id", tc.ID)))
 
 			if o.eventLogger != nil {
 				o.eventLogger.LogEvent("agent_tool_result", map[string]any{
@@ -477,7 +533,7 @@ func (o *orchestrator) processTurn(ctx context.Context, input string, resume boo
 			// Apply result interception for large results
 			resultContent := result
 			if o.interceptor != nil {
-				ir := o.interceptor.Intercept(tc.Name, result)
+				ir := o.interceptor.InterceptCall(tc.Name, actionInput, result)
 				resultContent = ir.Data
 				if ir.Metadata != "" {
 					resultContent += "\n\n---\n" + ir.Metadata
@@ -515,11 +571,29 @@ func (o *orchestrator) processTurn(ctx context.Context, input string, resume boo
 			o.sessionLogger.AppendStructuredMessage(o.sessionID, "user", AuditContentForMessage(msg), PartsFromMessage(msg), now, now)
 		}
 
+		// A handoff ends this agent's turn; the AgentManager runs the
+		// receiving agent.
+		if h := takeHandoff(o.tools); h != nil {
+			return ChatResult{Handoff: h}, nil
+		}
+
 		// Reset for next iteration
 		currentParts = nil
 	}
 
-	return ChatResult{Answer: finalAnswer, Complete: finalAnswer != ""}, nil
+	result := ChatResult{Answer: finalAnswer, Complete: finalAnswer != ""}
+	if result.Complete && o.submitOutput != nil {
+		if results := o.submitOutput.GetResults(); len(results) > 0 {
+			result.Output = results[len(results)-1].Output
+		}
+	}
+	return result, nil
+}
+
+// missingOutput reports whether answer ends a turn of an agent with an
+// output schema that hasn't submitted its output for the task yet.
+func (o *orchestrator) missingOutput(answer string) bool {
+	return answer != "" && o.submitOutput != nil && o.submitOutput.ResultCount() <= o.outputMark
 }
 
 // getSessionMessages retrieves the current message history from the underlying session.
@@ -531,38 +605,6 @@ func (o *orchestrator) getSessionMessages() []llm.Message {
 }
 
 
-// checkAndCompact checks if compaction is needed and performs it if so
-func (o *orchestrator) checkAndCompact(inputTokens int) {
-	if o.compaction == nil || o.compaction.TokenLimit <= 0 {
-		return
-	}
-
-	if inputTokens <= o.compaction.TokenLimit {
-		return
-	}
-
-	// Get the underlying session to perform compaction
-	adapter, ok := o.session.(*llm.SessionAdapter)
-	if !ok {
-		return
-	}
-
-	compacted := adapter.GetSession().Compact(o.compaction.TurnRetention)
-	if compacted > 0 {
-		if o.onCompaction != nil {
-			o.onCompaction(inputTokens, o.compaction.TokenLimit, compacted, o.compaction.TurnRetention)
-		}
-		if o.eventLogger != nil {
-			o.eventLogger.LogEvent("compaction", map[string]any{
-				"input_tokens":       inputTokens,
-				"token_limit":        o.compaction.TokenLimit,
-				"messages_compacted": compacted,
-				"turn_retention":     o.compaction.TurnRetention,
-			})
-		}
-	}
-}
-
 // applyTurnPruning applies threshold-based pruning if configured
 func (o *orchestrator) applyTurnPruning() {
 	if o.pruningManager == nil {
@@ -586,3 +628,28 @@ func (o *orchestrator) lookupTool(name string) aitools.Tool {
 	return nil
 }
 
+// sendCorrection sends a corrective user message mid-turn, checking it
+// against and charging it to the budget like any other LLM call.
+func (o *orchestrator) sendCorrection(ctx context.Context, text string, onChunk func(llm.StreamChunk)) (*llm.ChatResponse, error) {
+	if o.budget != nil {
+		if err := o.budget.CheckBudget(); err != nil {
+			return nil, err
+		}
+	}
+	resp, err := o.session.SendStream(ctx, text, onChunk)
+	if o.budget == nil {
+		return resp, err
+	}
+	if resp == nil {
+		o.budget.ReleaseCall()
+		return resp, err
+	}
+	var cost float64
+	if pricing := llm.GetPricing(o.modelName, o.pricingOverrides); pricing != nil {
+		cost = llm.ComputeTurnCost(pricing, resp.Usage.InputTokens, resp.Usage.OutputTokens, resp.Usage.CacheReadTokens, resp.Usage.CacheWriteTokens).TotalCost
+	}
+	if budgetErr := o.budget.RecordUsage(resp.Usage.Total(), cost); budgetErr != nil {
+		return nil, budgetErr
+	}
+	return resp, err
+}
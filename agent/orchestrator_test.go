@@ -215,3 +215,116 @@ func TestOrchestrator_MaxTokensCounterResetsOnSuccess(t *testing.T) {
 		t.Fatalf("expected counter reset to 0 after successful recovery, got %d", o.maxTokensRetries)
 	}
 }
+
+func TestOrchestrator_ParserRecoveryRepromptsMissionAgent(t *testing.T) {
+	// A mission agent that replies with plain text (no tool call, no ANSWER)
+	// gets a format correction, and its next well-formed reply is used.
+	session := &fakeSession{
+		responses: []*llm.ChatResponse{
+			textResponse("I think the answer is 42", "end_turn"),
+			textResponse("<ANSWER>42</ANSWER>", "end_turn"),
+		},
+	}
+	o := newTestOrchestrator(session, &mockStreamer{})
+	o.parserRecovery = true
+	var events []ParserRecoveryEvent
+	o.onParserRecovery = func(ev ParserRecoveryEvent) { events = append(events, ev) }
+
+	result, err := o.processTurn(context.Background(), "go", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Complete || result.Answer != "42" {
+		t.Fatalf("expected complete answer=42, got %+v", result)
+	}
+	if len(session.calls) != 2 || session.calls[1] != "send" {
+		t.Fatalf("expected the correction to be sent as a new message, got %v", session.calls)
+	}
+	if len(events) != 1 || events[0].Attempt != 1 || events[0].MaxAttempts != maxParserRecoveries || events[0].Response != "I think the answer is 42" {
+		t.Fatalf("unexpected parser_recovery events: %+v", events)
+	}
+	if o.parserRecoveries != 0 {
+		t.Fatalf("expected parserRecoveries reset after recovery, got %d", o.parserRecoveries)
+	}
+}
+
+func TestOrchestrator_ParserRecoveryFailsAfterMaxAttempts(t *testing.T) {
+	session := &fakeSession{
+		responses: []*llm.ChatResponse{
+			textResponse("r1", "end_turn"),
+			textResponse("r2", "end_turn"),
+			textResponse("r3", "end_turn"),
+			textResponse("r4", "end_turn"),
+		},
+	}
+	o := newTestOrchestrator(session, &mockStreamer{})
+	o.parserRecovery = true
+	recoveries := 0
+	o.onParserRecovery = func(ParserRecoveryEvent) { recoveries++ }
+
+	_, err := o.processTurn(context.Background(), "go", false)
+	if err == nil || !strings.Contains(err.Error(), "no tool call and no answer after 3 format corrections") {
+		t.Fatalf("expected parser recovery failure, got %v", err)
+	}
+	if recoveries != maxParserRecoveries {
+		t.Fatalf("expected %d parser_recovery events, got %d", maxParserRecoveries, recoveries)
+	}
+	if len(session.calls) != 4 {
+		t.Fatalf("expected 1 initial send + 3 corrections, got %v", session.calls)
+	}
+}
+
+func TestOrchestrator_ParserRecoveryOffInChatMode(t *testing.T) {
+	// Chat replies are plain text; they end the turn as before.
+	session := &fakeSession{
+		responses: []*llm.ChatResponse{textResponse("Hi! How can I help?", "end_turn")},
+	}
+	o := newTestOrchestrator(session, &mockStreamer{})
+
+	result, err := o.processTurn(context.Background(), "hello", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Complete || len(session.calls) != 1 {
+		t.Fatalf("expected the turn to end without a correction, got %+v after %v", result, session.calls)
+	}
+}
+
+func TestOrchestrator_OutputSchemaRequiresSubmitOutput(t *testing.T) {
+	// An agent with an output schema that answers without submit_output is
+	// sent back to submit it; the submitted output comes back with the answer.
+	session := &fakeSession{
+		responses: []*llm.ChatResponse{
+			textResponse("<ANSWER>Found 2 vendors</ANSWER>", "end_turn"),
+			toolUseResponse("tc_1", "submit_output", `{"output":{"count":2}}`, "tool_use"),
+			textResponse("<ANSWER>Submitted</ANSWER>", "end_turn"),
+		},
+	}
+	o := newTestOrchestrator(session, &mockStreamer{})
+	o.parserRecovery = true
+	o.submitOutput = aitools.NewSubmitOutputTool([]aitools.OutputField{{Name: "count", Type: "integer", Required: true}})
+	o.tools["submit_output"] = o.submitOutput
+
+	result, err := o.processTurn(context.Background(), "go", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Complete || result.Answer != "Submitted" || result.Output["count"] != 2.0 {
+		t.Fatalf("expected the submitted output with the answer, got %+v", result)
+	}
+	if len(session.calls) != 3 || session.calls[1] != "send" {
+		t.Fatalf("expected the correction to be sent as a new message, got %v", session.calls)
+	}
+
+	// Output submitted for an earlier task doesn't count for the next one
+	session.responses = []*llm.ChatResponse{
+		textResponse("<ANSWER>r1</ANSWER>", "end_turn"),
+		textResponse("<ANSWER>r2</ANSWER>", "end_turn"),
+		textResponse("<ANSWER>r3</ANSWER>", "end_turn"),
+		textResponse("<ANSWER>r4</ANSWER>", "end_turn"),
+	}
+	o.outputMark = o.submitOutput.ResultCount()
+	if _, err := o.processTurn(context.Background(), "again", false); err == nil || !strings.Contains(err.Error(), "without calling submit_output") {
+		t.Fatalf("expected an error after the corrections ran out, got %v", err)
+	}
+}
@@ -0,0 +1,79 @@
+package agent
+
+import (
+	"fmt"
+	"strings"
+
+	"squadron/aitools"
+	"squadron/config"
+)
+
+// OutputFieldsFromConfig converts a task or agent output schema, which may
+// be nil, to the fields commanders and agents are prompted with.
+func OutputFieldsFromConfig(schema *config.OutputSchema) []OutputFieldSchema {
+	if schema == nil || len(schema.Fields) == 0 {
+		return nil
+	}
+	fields := make([]OutputFieldSchema, 0, len(schema.Fields))
+	for _, f := range schema.Fields {
+		fields = append(fields, outputFieldFromConfig(f))
+	}
+	return fields
+}
+
+func outputFieldFromConfig(field config.OutputField) OutputFieldSchema {
+	s := OutputFieldSchema{
+		Name:        field.Name,
+		Type:        field.Type,
+		Description: field.Description,
+		Required:    field.Required,
+	}
+	if field.Items != nil {
+		items := outputFieldFromConfig(*field.Items)
+		s.Items = &items
+	}
+	for _, prop := range field.Properties {
+		s.Properties = append(s.Properties, outputFieldFromConfig(prop))
+	}
+	return s
+}
+
+// newSubmitOutputTool creates the submit_output tool validating schema
+func newSubmitOutputTool(schema []OutputFieldSchema, artifacts aitools.Artifacts) *aitools.SubmitOutputTool {
+	var fields []aitools.OutputField
+	for _, f := range schema {
+		fields = append(fields, aitools.OutputField{
+			Name:     f.Name,
+			Type:     f.Type,
+			Required: f.Required,
+		})
+	}
+	tool := aitools.NewSubmitOutputTool(fields)
+	tool.Artifacts = artifacts
+	return tool
+}
+
+// agentOutputPrompt tells an agent with an output schema to submit its
+// result through submit_output before answering.
+func agentOutputPrompt(schema []OutputFieldSchema) string {
+	var sb strings.Builder
+	sb.WriteString("## Required Structured Output\n\n")
+	sb.WriteString("Your results go back to the commander as structured data. Before giving your final ANSWER to a task, you MUST call the `submit_output` tool with an object holding the fields below.\n\n")
+
+	sb.WriteString("**Output fields:**\n")
+	writeFieldList(&sb, schema, 0)
+
+	sb.WriteString("\n**Example submit_output call:**\n")
+	sb.WriteString("```json\n")
+	sb.WriteString("{\"output\": {")
+	for i, field := range schema {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(fmt.Sprintf("\"%s\": %s", field.Name, writeExampleJSON(field)))
+	}
+	sb.WriteString("}}\n")
+	sb.WriteString("```\n\n")
+	sb.WriteString("If submit_output returns an error, fix the output and call it again. Once it succeeds, give a short ANSWER; the commander receives the submitted output.\n")
+	return sb.String()
+}
@@ -0,0 +1,200 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"squadron/aitools"
+	"squadron/config"
+)
+
+// callAgentsParallelTool runs several agent calls at once and returns their
+// results together. The same agent can be given several sub-tasks: each extra
+// copy runs as its own instance ("researcher#2"), with its own session.
+type callAgentsParallelTool struct {
+	commander *Commander
+}
+
+// parallelAgentCall is one entry of a call_agents_parallel request.
+type parallelAgentCall struct {
+	Name string `json:"name"`
+	Task string `json:"task"`
+}
+
+// parallelAgentResult is one entry of a call_agents_parallel result, in the
+// order of the calls.
+type parallelAgentResult struct {
+	Agent       string         `json:"agent"`
+	Status      string         `json:"status"` // completed, question, incomplete, or failed
+	Result      string         `json:"result,omitempty"`
+	Output      map[string]any `json:"output,omitempty"`
+	Question    string         `json:"question,omitempty"`
+	Error       string         `json:"error,omitempty"`
+	HandedOffTo string         `json:"handed_off_to,omitempty"`
+}
+
+func (t *callAgentsParallelTool) ToolName() string {
+	return "call_agents_parallel"
+}
+
+func (t *callAgentsParallelTool) ToolDescription() string {
+	return fmt.Sprintf(`Run several agent tasks at the same time and get all their results back together. Use it for independent sub-tasks that don't need each other's results — it is much faster than calling call_agent for each in turn.
+
+The same agent can appear more than once: each extra copy runs as its own instance, named like "researcher#2". Results come back in the order of "calls", each with the agent (instance) name that produced it. If an agent asks a question, answer it with call_agent using that name and "response". Use ask_agent with that name for follow-ups.
+
+At most %d agents run at once; the rest start as others finish.`, t.commander.parallelAgentLimit())
+}
+
+func (t *callAgentsParallelTool) ToolPayloadSchema() aitools.Schema {
+	return aitools.Schema{
+		Type: aitools.TypeObject,
+		Properties: aitools.PropertyMap{
+			"calls": {
+				Type:        aitools.TypeArray,
+				Description: "The agent tasks to run concurrently",
+				Items: &aitools.Property{
+					Type: aitools.TypeObject,
+					Properties: aitools.PropertyMap{
+						"name": {
+							Type:        aitools.TypeString,
+							Description: "The name of the agent to call",
+						},
+						"task": {
+							Type:        aitools.TypeString,
+							Description: "A new task for the agent",
+						},
+					},
+					Required: []string{"name", "task"},
+				},
+			},
+		},
+		Required: []string{"calls"},
+	}
+}
+
+func (t *callAgentsParallelTool) Call(ctx context.Context, input string) string {
+	var params struct {
+		Calls []parallelAgentCall `json:"calls"`
+	}
+	if err := json.Unmarshal([]byte(input), &params); err != nil {
+		return fmt.Sprintf("Error: Invalid input: %v", err)
+	}
+	if len(params.Calls) == 0 {
+		return "Error: Provide at least one call in 'calls'"
+	}
+	for i, c := range params.Calls {
+		if c.Name == "" || c.Task == "" {
+			return fmt.Sprintf("Error: calls[%d] needs both 'name' and 'task'", i)
+		}
+	}
+
+	names := agentInstanceNames(params.Calls)
+	results := make([]parallelAgentResult, len(params.Calls))
+	errs := make([]error, len(params.Calls))
+
+	sem := make(chan struct{}, t.commander.parallelAgentLimit())
+	var wg sync.WaitGroup
+	for i, c := range params.Calls {
+		wg.Add(1)
+		go func(i int, c parallelAgentCall) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				errs[i] = ctx.Err()
+				results[i] = parallelAgentResult{Agent: names[i], Status: "failed", Error: ctx.Err().Error()}
+				return
+			}
+			result, err := t.commander.agentMgr.RunAgent(ctx, names[i], c.Task, "")
+			errs[i] = err
+			results[i] = parallelAgentOutcome(names[i], result, err)
+		}(i, c)
+	}
+	wg.Wait()
+
+	// The first failure feeds failure classification, as with call_agent
+	t.commander.lastAgentErr = nil
+	for _, err := range errs {
+		if err != nil {
+			t.commander.lastAgentErr = err
+			break
+		}
+	}
+
+	out, err := json.MarshalIndent(map[string]any{"results": results}, "", "  ")
+	if err != nil {
+		return fmt.Sprintf("Error: %v", err)
+	}
+	return string(out)
+}
+
+// parallelAgentOutcome turns one agent run into its entry in the results.
+func parallelAgentOutcome(name string, result ChatResult, err error) parallelAgentResult {
+	r := parallelAgentResult{Agent: name, HandedOffTo: result.HandedOffTo}
+	switch {
+	case err != nil:
+		r.Status = "failed"
+		r.Error = err.Error()
+	case result.AskCommander != "":
+		r.Status = "question"
+		r.Question = result.AskCommander
+	case result.Complete:
+		r.Status = "completed"
+		if result.Output != nil {
+			r.Output = result.Output
+		} else {
+			r.Result = result.Answer
+		}
+	default:
+		r.Status = "incomplete"
+		r.Error = "Agent did not produce a result. Call it again with call_agent to continue."
+	}
+	return r
+}
+
+// parallelAgentLimit is the commander's call_agents_parallel fan-out limit.
+func (s *Commander) parallelAgentLimit() int {
+	if s.maxParallelAgents > 0 {
+		return s.maxParallelAgents
+	}
+	return config.DefaultMaxParallelAgents
+}
+
+// agentInstanceNames names the agent instance each call runs on. The first
+// call to an agent uses its own name, so it picks up that agent's session
+// like call_agent would; later calls to the same agent get "name#2",
+// "name#3", and so on. A call naming an instance directly keeps it.
+func agentInstanceNames(calls []parallelAgentCall) []string {
+	names := make([]string, len(calls))
+	used := make(map[string]bool, len(calls))
+	for i, c := range calls {
+		name := c.Name
+		if used[name] {
+			base := AgentConfigName(name)
+			for n := 2; used[name]; n++ {
+				name = base + "#" + strconv.Itoa(n)
+			}
+		}
+		used[name] = true
+		names[i] = name
+	}
+	return names
+}
+
+// AgentConfigName returns the configured agent an agent instance runs
+// ("researcher#2" -> "researcher"). Other names are returned unchanged.
+func AgentConfigName(name string) string {
+	i := strings.LastIndex(name, "#")
+	if i <= 0 {
+		return name
+	}
+	if n, err := strconv.Atoi(name[i+1:]); err != nil || n < 2 {
+		return name
+	}
+	return name[:i]
+}
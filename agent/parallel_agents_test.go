@@ -0,0 +1,93 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAgentInstanceNames(t *testing.T) {
+	calls := []parallelAgentCall{
+		{Name: "researcher"},
+		{Name: "writer"},
+		{Name: "researcher"},
+		{Name: "researcher#3"},
+		{Name: "researcher"},
+	}
+	got := agentInstanceNames(calls)
+	want := []string{"researcher", "writer", "researcher#2", "researcher#3", "researcher#4"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("agentInstanceNames = %v, want %v", got, want)
+	}
+}
+
+func TestAgentConfigName(t *testing.T) {
+	cases := map[string]string{
+		"researcher":    "researcher",
+		"researcher#2":  "researcher",
+		"researcher#12": "researcher",
+		"researcher#1":  "researcher#1",
+		"researcher#x":  "researcher#x",
+		"#2":            "#2",
+	}
+	for in, want := range cases {
+		if got := AgentConfigName(in); got != want {
+			t.Errorf("AgentConfigName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestParallelAgentOutcome(t *testing.T) {
+	cases := []struct {
+		name   string
+		result ChatResult
+		err    error
+		want   parallelAgentResult
+	}{
+		{"completed", ChatResult{Complete: true, Answer: "done"}, nil,
+			parallelAgentResult{Agent: "a", Status: "completed", Result: "done"}},
+		{"question", ChatResult{AskCommander: "which year?"}, nil,
+			parallelAgentResult{Agent: "a", Status: "question", Question: "which year?"}},
+		{"failed", ChatResult{}, errors.New("boom"),
+			parallelAgentResult{Agent: "a", Status: "failed", Error: "boom"}},
+		{"handed off", ChatResult{Complete: true, Answer: "report", HandedOffTo: "writer"}, nil,
+			parallelAgentResult{Agent: "a", Status: "completed", Result: "report", HandedOffTo: "writer"}},
+		{"structured output", ChatResult{Complete: true, Answer: "found 2", Output: map[string]any{"count": 2.0}}, nil,
+			parallelAgentResult{Agent: "a", Status: "completed", Output: map[string]any{"count": 2.0}}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := parallelAgentOutcome("a", tc.result, tc.err); !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("got %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCallAgentsParallelTool_ValidatesInput(t *testing.T) {
+	tool := &callAgentsParallelTool{commander: &Commander{}}
+	cases := map[string]string{
+		`{"calls":[]}`:                                     "at least one call",
+		`{"calls":[{"name":"researcher"}]}`:                "calls[0] needs both 'name' and 'task'",
+		`{"calls":[{"name":"a","task":"t"},{"task":"t"}]}`: "calls[1] needs both",
+		`not json`: "Invalid input",
+	}
+	for input, want := range cases {
+		if got := tool.Call(context.Background(), input); !strings.Contains(got, want) {
+			t.Errorf("Call(%s) = %q, want it to contain %q", input, got, want)
+		}
+	}
+}
+
+func TestCallAgentsParallelTool_DescribesLimit(t *testing.T) {
+	tool := &callAgentsParallelTool{commander: &Commander{maxParallelAgents: 7}}
+	if !strings.Contains(tool.ToolDescription(), "At most 7 agents run at once") {
+		t.Fatalf("description should state the fan-out limit:\n%s", tool.ToolDescription())
+	}
+	tool = &callAgentsParallelTool{commander: &Commander{}}
+	if !strings.Contains(tool.ToolDescription(), "At most 4 agents run at once") {
+		t.Fatalf("description should state the default fan-out limit:\n%s", tool.ToolDescription())
+	}
+}
@@ -0,0 +1,49 @@
+package agent
+
+import "fmt"
+
+// maxParserRecoveries is how many times in a row a commander or mission agent
+// is re-prompted after a response the loop can't act on — no tool call, and
+// for agents no <ANSWER> either — before it fails.
+const maxParserRecoveries = 3
+
+// ParserRecoveryEvent describes one format correction sent after a
+// malformed response.
+type ParserRecoveryEvent struct {
+	Attempt     int
+	MaxAttempts int
+	// Response is the start of the text the model produced instead
+	Response string
+}
+
+// Correction messages sent back to the model.
+const (
+	commanderFormatCorrection = "Invalid response. You must make a tool call. Either call additional tools to continue your work, or call task_complete if you are done."
+	agentFormatCorrection     = "Invalid response: it had no tool call and no <ANSWER> tags, so it can't be used. Either call a tool via native function calling to continue your work, or give your final result wrapped in <ANSWER>...</ANSWER>. If you need information from the commander, call ask_commander."
+	agentOutputCorrection     = "Your answer can't be accepted yet: this agent has a required output schema and you haven't called submit_output for this task. Call submit_output with your structured result, then give your final <ANSWER>."
+)
+
+// parserRecoveryResponseLimit caps how much of the malformed response an
+// event carries.
+const parserRecoveryResponseLimit = 200
+
+func newParserRecoveryEvent(attempt int, response string) ParserRecoveryEvent {
+	if len(response) > parserRecoveryResponseLimit {
+		response = response[:parserRecoveryResponseLimit] + "..."
+	}
+	return ParserRecoveryEvent{Attempt: attempt, MaxAttempts: maxParserRecoveries, Response: response}
+}
+
+// parserRecoveryEventData is the debug log payload of a parser_recovery event.
+func parserRecoveryEventData(ev ParserRecoveryEvent) map[string]any {
+	return map[string]any{
+		"attempt":      ev.Attempt,
+		"max_attempts": ev.MaxAttempts,
+		"response":     ev.Response,
+	}
+}
+
+// parserRecoveryError is returned once the corrections run out.
+func parserRecoveryError(entity string) error {
+	return fmt.Errorf("%s produced no tool call and no answer after %d format corrections", entity, maxParserRecoveries)
+}
@@ -0,0 +1,100 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"squadron/aitools"
+	"squadron/config"
+	"squadron/llm"
+)
+
+// Reviewer choices for tool calls that need approval.
+const (
+	policyChoiceAllow = "Allow"
+	policyChoiceDeny  = "Deny"
+)
+
+// policyTool checks a mission's tool-call policies before calling the
+// wrapped tool. Denied calls, and calls a reviewer rejects, never reach the
+// tool; the agent gets an error result instead.
+type policyTool struct {
+	aitools.Tool
+	ref      string // tool reference the rules match on
+	agent    string
+	policies *config.Policies
+	bridge   aitools.HumanInputBridge
+	redactor *llm.Redactor // scrubs injected secrets from inputs shown to reviewers
+}
+
+// applyToolPolicy wraps every tool in the map with the mission's policies.
+// Call before adding sanitized aliases so aliases share the wrapper.
+func applyToolPolicy(tools map[string]aitools.Tool, policies *config.Policies, agentName string, bridge aitools.HumanInputBridge, redactor *llm.Redactor) {
+	if policies == nil || len(policies.Rules) == 0 {
+		return
+	}
+	for ref, tool := range tools {
+		tools[ref] = &policyTool{Tool: tool, ref: ref, agent: agentName, policies: policies, bridge: bridge, redactor: redactor}
+	}
+}
+
+func (t *policyTool) ToolOutputSchema() json.RawMessage {
+	return aitools.ToolOutputSchemaOf(t.Tool)
+}
+
+func (t *policyTool) Call(ctx context.Context, input string) string {
+	rule := t.policies.Match(t.agent, t.ref, input)
+	if rule == nil || rule.Action == config.PolicyAllow {
+		return t.Tool.Call(ctx, input)
+	}
+	if rule.Action == config.PolicyRequireApproval {
+		approved, err := t.requestApproval(ctx, rule, input)
+		if err != nil {
+			return fmt.Sprintf("Error: tool call needs approval under policy '%s' but none was given: %v", rule.Name, err)
+		}
+		if approved {
+			return t.Tool.Call(ctx, input)
+		}
+		return fmt.Sprintf("Error: tool call rejected by a reviewer (policy '%s'). Do not retry it; find another approach.", rule.Name)
+	}
+
+	msg := fmt.Sprintf("Error: tool call blocked by policy '%s'", rule.Name)
+	if rule.Reason != "" {
+		msg += ": " + rule.Reason
+	}
+	return msg + ". Do not retry it; find another approach."
+}
+
+// requestApproval asks a human, through the same bridge as
+// builtins.human.ask, whether the call may proceed.
+func (t *policyTool) requestApproval(ctx context.Context, rule *config.PolicyRule, input string) (bool, error) {
+	if t.bridge == nil {
+		return false, fmt.Errorf("no human available")
+	}
+	missionID, taskID := aitools.MissionContextFromContext(ctx)
+
+	var details strings.Builder
+	fmt.Fprintf(&details, "Agent **%s** wants to call `%s`", t.agent, t.ref)
+	if rule.Reason != "" {
+		fmt.Fprintf(&details, "\n\nPolicy '%s': %s", rule.Name, rule.Reason)
+	}
+	fmt.Fprintf(&details, "\n\n**Input**\n\n```json\n%s\n```", t.redactor.Redact(input))
+
+	resp, err := t.bridge.AskHuman(ctx, aitools.HumanInputRequest{
+		ToolCallID:        uuid.NewString(),
+		MissionID:         missionID,
+		TaskID:            taskID,
+		Question:          fmt.Sprintf("Allow agent '%s' to call %s?", t.agent, t.ref),
+		ShortSummary:      fmt.Sprintf("Tool approval: %s", t.ref),
+		AdditionalContext: details.String(),
+		Choices:           []string{policyChoiceAllow, policyChoiceDeny},
+	})
+	if err != nil {
+		return false, err
+	}
+	return strings.EqualFold(strings.TrimSpace(resp), policyChoiceAllow), nil
+}
@@ -0,0 +1,113 @@
+package agent
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"squadron/aitools"
+	"squadron/config"
+	"squadron/llm"
+)
+
+// countingTool records how often it is called.
+type countingTool struct {
+	calls int
+}
+
+func (t *countingTool) ToolName() string        { return "get" }
+func (t *countingTool) ToolDescription() string { return "test tool" }
+func (t *countingTool) ToolPayloadSchema() aitools.Schema {
+	return aitools.Schema{Type: aitools.TypeObject}
+}
+func (t *countingTool) Call(ctx context.Context, input string) string {
+	t.calls++
+	return "ok"
+}
+
+// fakeBridge answers every human input request with reply.
+type fakeBridge struct {
+	reply string
+	last  aitools.HumanInputRequest
+}
+
+func (b *fakeBridge) AskHuman(ctx context.Context, req aitools.HumanInputRequest) (string, error) {
+	b.last = req
+	return b.reply, nil
+}
+
+var testPolicies = &config.Policies{Rules: []config.PolicyRule{
+	{Name: "no_http", Action: config.PolicyDeny, Tools: []string{"builtins.http.get"}, Agents: []string{"scraper"}, Reason: "scrapers stay offline"},
+	{Name: "http_allowlist", Action: config.PolicyRequireApproval, Tools: []string{"builtins.http.all"}, AllowedHosts: []string{"api.example.com"}},
+}}
+
+func policyWrapped(agentName string, bridge aitools.HumanInputBridge) (map[string]aitools.Tool, *countingTool) {
+	inner := &countingTool{}
+	tools := map[string]aitools.Tool{"builtins.http.get": inner}
+	applyToolPolicy(tools, testPolicies, agentName, bridge, llm.NewRedactor(map[string]string{"token": "s3cret"}))
+	aitools.AddSanitizedAliases(tools)
+	return tools, inner
+}
+
+func TestToolPolicy_DeniesMatchingCall(t *testing.T) {
+	tools, inner := policyWrapped("scraper", nil)
+
+	got := tools["builtins_http_get"].Call(context.Background(), `{"url":"https://api.example.com"}`)
+	if !strings.Contains(got, "blocked by policy 'no_http': scrapers stay offline") {
+		t.Fatalf("expected policy denial, got %q", got)
+	}
+	if inner.calls != 0 {
+		t.Fatal("denied call must not reach the tool")
+	}
+}
+
+func TestToolPolicy_AllowsUnmatchedCall(t *testing.T) {
+	tools, inner := policyWrapped("writer", nil)
+
+	if got := tools["builtins.http.get"].Call(context.Background(), `{"url":"https://api.example.com"}`); got != "ok" {
+		t.Fatalf("expected tool result, got %q", got)
+	}
+	if inner.calls != 1 {
+		t.Fatalf("expected 1 call, got %d", inner.calls)
+	}
+}
+
+func TestToolPolicy_RequiresApproval(t *testing.T) {
+	input := `{"url":"https://elsewhere.io","headers":{"Authorization":"s3cret"}}`
+
+	bridge := &fakeBridge{reply: "Allow"}
+	tools, inner := policyWrapped("writer", bridge)
+	ctx := aitools.WithMissionContext(context.Background(), "m1", "t1")
+	if got := tools["builtins.http.get"].Call(ctx, input); got != "ok" {
+		t.Fatalf("approved call should run, got %q", got)
+	}
+	if inner.calls != 1 {
+		t.Fatalf("expected 1 call, got %d", inner.calls)
+	}
+	if bridge.last.MissionID != "m1" || bridge.last.TaskID != "t1" {
+		t.Fatalf("approval request should carry mission context, got %+v", bridge.last)
+	}
+	if strings.Contains(bridge.last.AdditionalContext, "s3cret") {
+		t.Fatal("secrets must be redacted from the approval request")
+	}
+
+	bridge.reply = "Deny"
+	if got := tools["builtins.http.get"].Call(ctx, input); !strings.Contains(got, "rejected by a reviewer") {
+		t.Fatalf("expected rejection, got %q", got)
+	}
+	if inner.calls != 1 {
+		t.Fatal("rejected call must not reach the tool")
+	}
+}
+
+func TestToolPolicy_ApprovalWithoutHumanDenies(t *testing.T) {
+	tools, inner := policyWrapped("writer", nil)
+
+	got := tools["builtins.http.get"].Call(context.Background(), `{"url":"https://elsewhere.io"}`)
+	if !strings.Contains(got, "needs approval") {
+		t.Fatalf("expected approval error, got %q", got)
+	}
+	if inner.calls != 0 {
+		t.Fatal("unapproved call must not reach the tool")
+	}
+}
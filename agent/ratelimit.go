@@ -0,0 +1,41 @@
+package agent
+
+import (
+	"fmt"
+	"sync"
+
+	"squadron/config"
+	"squadron/llm"
+)
+
+// modelRateLimiters holds one limiter set per rate-limited model config
+// for the life of the process, so every commander, agent and mission using
+// the same credentials draws from the same budget.
+var (
+	modelRateLimitersMu sync.Mutex
+	modelRateLimiters   = make(map[string]*llm.RateLimiters)
+)
+
+// rateLimiters returns the shared limiter set for a model config, or nil
+// when it has no rate_limit block. Configs are keyed by provider,
+// endpoint, credentials and limits, so reloading a config keeps its budget
+// while changing the limits starts a fresh one.
+func rateLimiters(m *config.Model) *llm.RateLimiters {
+	if m.RateLimit == nil {
+		return nil
+	}
+	limit := llm.RateLimit{
+		RequestsPerMinute: m.RateLimit.RequestsPerMinute,
+		TokensPerMinute:   m.RateLimit.TokensPerMinute,
+	}
+	key := fmt.Sprintf("%s\x00%s\x00%s\x00%d\x00%d", m.Provider, m.BaseURL, m.APIKey, limit.RequestsPerMinute, limit.TokensPerMinute)
+
+	modelRateLimitersMu.Lock()
+	defer modelRateLimitersMu.Unlock()
+	l, ok := modelRateLimiters[key]
+	if !ok {
+		l = llm.NewRateLimiters(limit)
+		modelRateLimiters[key] = l
+	}
+	return l
+}
@@ -0,0 +1,54 @@
+package agent
+
+import (
+	"time"
+
+	"squadron/llm"
+	"squadron/store"
+)
+
+// redactingSessionLogger scrubs secrets from everything it forwards to the
+// wrapped SessionLogger, so persisted messages and tool call records never
+// hold secret values even though tools receive them after injection.
+type redactingSessionLogger struct {
+	SessionLogger
+	redactor *llm.Redactor
+}
+
+// withRedaction wraps logger so its message and tool call writes are
+// redacted. A nil logger stays nil.
+func withRedaction(logger SessionLogger, redactor *llm.Redactor) SessionLogger {
+	if logger == nil {
+		return nil
+	}
+	if rl, ok := logger.(*redactingSessionLogger); ok {
+		logger = rl.SessionLogger
+	}
+	return &redactingSessionLogger{SessionLogger: logger, redactor: redactor}
+}
+
+func (l *redactingSessionLogger) AppendMessage(sessionID, role, content string, createdAt, completedAt time.Time) error {
+	return l.SessionLogger.AppendMessage(sessionID, role, l.redactor.Redact(content), createdAt, completedAt)
+}
+
+func (l *redactingSessionLogger) AppendStructuredMessage(sessionID, role, content string, parts []store.MessagePart, createdAt, completedAt time.Time) error {
+	redacted := make([]store.MessagePart, len(parts))
+	for i, p := range parts {
+		p.Text = l.redactor.Redact(p.Text)
+		p.ToolInputJSON = l.redactor.Redact(p.ToolInputJSON)
+		redacted[i] = p
+	}
+	return l.SessionLogger.AppendStructuredMessage(sessionID, role, l.redactor.Redact(content), redacted, createdAt, completedAt)
+}
+
+func (l *redactingSessionLogger) StoreToolResult(taskID, sessionID, toolCallId, toolName, inputParams, rawData string, startedAt, finishedAt time.Time) error {
+	return l.SessionLogger.StoreToolResult(taskID, sessionID, toolCallId, toolName, l.redactor.Redact(inputParams), l.redactor.Redact(rawData), startedAt, finishedAt)
+}
+
+func (l *redactingSessionLogger) StartToolCall(taskID, sessionID, toolCallId, toolName, inputParams string) (string, error) {
+	return l.SessionLogger.StartToolCall(taskID, sessionID, toolCallId, toolName, l.redactor.Redact(inputParams))
+}
+
+func (l *redactingSessionLogger) CompleteToolCall(id, rawData string) error {
+	return l.SessionLogger.CompleteToolCall(id, l.redactor.Redact(rawData))
+}
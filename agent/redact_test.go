@@ -0,0 +1,85 @@
+package agent
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"squadron/llm"
+	"squadron/store"
+)
+
+// recordingSessionLogger records what reaches the store.
+type recordingSessionLogger struct {
+	SessionLogger
+	contents []string
+	parts    []store.MessagePart
+	inputs   []string
+	results  []string
+}
+
+func (l *recordingSessionLogger) AppendMessage(sessionID, role, content string, createdAt, completedAt time.Time) error {
+	l.contents = append(l.contents, content)
+	return nil
+}
+
+func (l *recordingSessionLogger) AppendStructuredMessage(sessionID, role, content string, parts []store.MessagePart, createdAt, completedAt time.Time) error {
+	l.contents = append(l.contents, content)
+	l.parts = append(l.parts, parts...)
+	return nil
+}
+
+func (l *recordingSessionLogger) StoreToolResult(taskID, sessionID, toolCallId, toolName, inputParams, rawData string, startedAt, finishedAt time.Time) error {
+	l.inputs = append(l.inputs, inputParams)
+	l.results = append(l.results, rawData)
+	return nil
+}
+
+func (l *recordingSessionLogger) StartToolCall(taskID, sessionID, toolCallId, toolName, inputParams string) (string, error) {
+	l.inputs = append(l.inputs, inputParams)
+	return "call-1", nil
+}
+
+func (l *recordingSessionLogger) CompleteToolCall(id, rawData string) error {
+	l.results = append(l.results, rawData)
+	return nil
+}
+
+func TestRedactingSessionLogger(t *testing.T) {
+	inner := &recordingSessionLogger{}
+	logger := withRedaction(inner, llm.NewRedactor(map[string]string{"token": "tok-s3cr3t"}))
+	now := time.Now()
+
+	logger.AppendMessage("s", "user", "token is tok-s3cr3t", now, now)
+	logger.AppendStructuredMessage("s", "assistant", "calling with tok-s3cr3t", []store.MessagePart{
+		{Type: "text", Text: "tok-s3cr3t"},
+		{Type: "tool_use", ToolInputJSON: `{"auth":"tok-s3cr3t"}`},
+	}, now, now)
+	logger.StoreToolResult("t", "s", "c", "http_get", `{"auth":"tok-s3cr3t"}`, "echo tok-s3cr3t", now, now)
+	logger.StartToolCall("t", "s", "c", "http_get", `{"auth":"tok-s3cr3t"}`)
+	logger.CompleteToolCall("call-1", "echo tok-s3cr3t")
+
+	var all []string
+	all = append(all, inner.contents...)
+	all = append(all, inner.inputs...)
+	all = append(all, inner.results...)
+	for _, p := range inner.parts {
+		all = append(all, p.Text, p.ToolInputJSON)
+	}
+	for _, s := range all {
+		if s != "" && !strings.Contains(s, "${secrets.token}") {
+			t.Errorf("write not redacted: %q", s)
+		}
+	}
+	if inner.inputs[0] != `{"auth":"${secrets.token}"}` {
+		t.Errorf("input = %q", inner.inputs[0])
+	}
+
+	// Wrapping twice doesn't redact twice
+	if rl := withRedaction(logger, nil).(*redactingSessionLogger); rl.SessionLogger != inner {
+		t.Error("re-wrapping should replace the previous redactor")
+	}
+	if withRedaction(nil, nil) != nil {
+		t.Error("nil logger should stay nil")
+	}
+}
@@ -0,0 +1,29 @@
+package agent
+
+import (
+	"squadron/config"
+	"squadron/llm"
+)
+
+// retryPolicy converts a model's retry block to the session retry policy.
+// Unset fields keep the llm defaults.
+func retryPolicy(m *config.Model) llm.RetryPolicy {
+	if m.Retry == nil {
+		return llm.DefaultRetryPolicy()
+	}
+	return llm.RetryPolicy{
+		MaxAttempts:    m.Retry.MaxAttempts,
+		InitialBackoff: m.Retry.GetInitialBackoff(),
+		MaxBackoff:     m.Retry.GetMaxBackoff(),
+	}
+}
+
+// retryEventData formats a provider retry for the debug event log.
+func retryEventData(ev llm.RetryEvent) map[string]any {
+	return map[string]any{
+		"attempt":      ev.Attempt,
+		"max_attempts": ev.MaxAttempts,
+		"backoff_ms":   ev.Backoff.Milliseconds(),
+		"error":        ev.Err.Error(),
+	}
+}
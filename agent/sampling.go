@@ -0,0 +1,31 @@
+package agent
+
+import (
+	"squadron/config"
+	"squadron/llm"
+)
+
+// SamplingFor builds the sampling settings of LLM calls to a model key: each
+// setting from the task when it sets it, else from the model config's
+// sampling block for the key.
+func SamplingFor(m *config.Model, modelKey string, task *config.Sampling) llm.Sampling {
+	var s llm.Sampling
+	for _, c := range []*config.Sampling{m.SamplingFor(modelKey), task} {
+		if c == nil {
+			continue
+		}
+		if c.MaxOutputTokens > 0 {
+			s.MaxTokens = c.MaxOutputTokens
+		}
+		if c.Temperature != nil {
+			s.Temperature = c.Temperature
+		}
+		if c.TopP != nil {
+			s.TopP = c.TopP
+		}
+		if c.Seed != nil {
+			s.Seed = c.Seed
+		}
+	}
+	return s
+}
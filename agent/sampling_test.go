@@ -0,0 +1,28 @@
+package agent
+
+import (
+	"testing"
+
+	"squadron/config"
+)
+
+func TestSamplingFor_TaskOverridesModel(t *testing.T) {
+	modelTemp, taskTemp, topP, seed := 0.7, 0.0, 0.9, int64(3)
+	m := &config.Model{Sampling: map[string]*config.Sampling{
+		"claude_sonnet_4": {Temperature: &modelTemp, TopP: &topP, MaxOutputTokens: 8192},
+	}}
+
+	s := SamplingFor(m, "claude_sonnet_4", &config.Sampling{Temperature: &taskTemp, Seed: &seed})
+	if *s.Temperature != 0 || *s.TopP != 0.9 || s.MaxTokens != 8192 || *s.Seed != 3 {
+		t.Errorf("unexpected sampling: %+v", s)
+	}
+
+	s = SamplingFor(m, "claude_opus_4", nil)
+	if s.Temperature != nil || s.TopP != nil || s.MaxTokens != 0 || s.Seed != nil {
+		t.Errorf("expected no sampling for a model without settings, got %+v", s)
+	}
+
+	if s = SamplingFor(nil, "claude_sonnet_4", &config.Sampling{MaxOutputTokens: 100}); s.MaxTokens != 100 {
+		t.Errorf("expected task settings without a model config, got %+v", s)
+	}
+}
@@ -0,0 +1,48 @@
+package agent
+
+import (
+	"encoding/json"
+	"regexp"
+	"sync"
+
+	"squadron/config"
+	"squadron/llm"
+)
+
+// scriptedProviders holds one provider per loaded scripted model config, so
+// the commanders and agents of a mission work through a single script
+// instead of each replaying it from the start.
+var (
+	scriptedProvidersMu sync.Mutex
+	scriptedProviders   = make(map[*config.Model]*llm.ScriptedProvider)
+)
+
+// scriptedProvider returns the shared provider for a scripted model config.
+// The config's match patterns were checked by Validate.
+func scriptedProvider(m *config.Model) *llm.ScriptedProvider {
+	scriptedProvidersMu.Lock()
+	defer scriptedProvidersMu.Unlock()
+	if p, ok := scriptedProviders[m]; ok {
+		return p
+	}
+	responses := make([]llm.ScriptedResponse, len(m.Responses))
+	for i, r := range m.Responses {
+		responses[i] = llm.ScriptedResponse{
+			Content: r.Content,
+			Error:   r.Error,
+			Repeat:  r.Repeat,
+		}
+		if r.Match != "" {
+			responses[i].Match = regexp.MustCompile(r.Match)
+		}
+		for _, tc := range r.ToolCalls {
+			responses[i].ToolCalls = append(responses[i].ToolCalls, llm.ScriptedToolCall{
+				Name:  tc.Name,
+				Input: json.RawMessage(tc.Input),
+			})
+		}
+	}
+	p := llm.NewScriptedProvider(responses)
+	scriptedProviders[m] = p
+	return p
+}
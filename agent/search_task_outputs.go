@@ -0,0 +1,97 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"squadron/aitools"
+)
+
+// maxSearchLimit caps how many hits one search_task_outputs call returns.
+const maxSearchLimit = 50
+
+// searchTaskOutputsTool runs a keyword search across the summaries and
+// structured outputs of every completed task in the mission. It complements
+// query_task_output, which needs the task and exact field to look in.
+type searchTaskOutputsTool struct {
+	store KnowledgeStore
+}
+
+func (t *searchTaskOutputsTool) ToolName() string {
+	return "search_task_outputs"
+}
+
+func (t *searchTaskOutputsTool) ToolDescription() string {
+	return `Keyword search across everything completed tasks in this mission produced: task summaries, iteration summaries, and structured outputs (including each iteration's output). Returns the best matches first, each with a snippet around the match.
+
+Use it when you don't know which task or field holds what you need, e.g. {"query": "acme funding round"}. Restrict it to some tasks with "tasks". Once you know where the data is, use query_task_output for exact filters and aggregates, or ask_commander for detail behind a summary.`
+}
+
+func (t *searchTaskOutputsTool) ToolPayloadSchema() aitools.Schema {
+	return aitools.Schema{
+		Type: aitools.TypeObject,
+		Properties: aitools.PropertyMap{
+			"query": {
+				Type:        aitools.TypeString,
+				Description: "Keywords to search for. Results containing the exact phrase rank highest.",
+			},
+			"tasks": {
+				Type:        aitools.TypeArray,
+				Description: "Only search these completed tasks (default: all)",
+				Items:       &aitools.Property{Type: aitools.TypeString},
+			},
+			"limit": {
+				Type:        aitools.TypeInteger,
+				Description: fmt.Sprintf("Maximum number of matches to return (default: 10, max: %d)", maxSearchLimit),
+			},
+		},
+		Required: []string{"query"},
+	}
+}
+
+func (t *searchTaskOutputsTool) Call(ctx context.Context, input string) string {
+	var params struct {
+		Query string   `json:"query"`
+		Tasks []string `json:"tasks"`
+		Limit int      `json:"limit"`
+	}
+	if err := json.Unmarshal([]byte(input), &params); err != nil {
+		return fmt.Sprintf("Error: invalid input: %v", err)
+	}
+	if strings.TrimSpace(params.Query) == "" {
+		return "Error: 'query' is required"
+	}
+	if params.Limit > maxSearchLimit {
+		params.Limit = maxSearchLimit
+	}
+
+	result := t.store.Search(TaskSearchQuery{
+		Text:  params.Query,
+		Tasks: params.Tasks,
+		Limit: params.Limit,
+	})
+	return formatSearchResults(params.Query, result)
+}
+
+// formatSearchResults lists search hits, best first
+func formatSearchResults(query string, result TaskSearchResult) string {
+	if len(result.Hits) == 0 {
+		return fmt.Sprintf("No completed task results match %q.", query)
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Found %d matches for %q (showing %d):\n", result.TotalMatches, query, len(result.Hits))
+	for i, hit := range result.Hits {
+		fmt.Fprintf(&sb, "\n%d. task %s", i+1, hit.TaskName)
+		if hit.Index != nil {
+			fmt.Fprintf(&sb, ", iteration %d", *hit.Index)
+			if hit.ItemID != "" {
+				fmt.Fprintf(&sb, " (item %s)", hit.ItemID)
+			}
+		}
+		fmt.Fprintf(&sb, " — %s, score %.2f\n   %s\n", strings.ReplaceAll(hit.Kind, "_", " "), hit.Score, hit.Snippet)
+	}
+	return sb.String()
+}
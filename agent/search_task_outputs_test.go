@@ -0,0 +1,59 @@
+package agent
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// searchOnlyStore is a KnowledgeStore that only answers Search.
+type searchOnlyStore struct {
+	KnowledgeStore
+	got    TaskSearchQuery
+	result TaskSearchResult
+}
+
+func (s *searchOnlyStore) Search(query TaskSearchQuery) TaskSearchResult {
+	s.got = query
+	return s.result
+}
+
+func TestSearchTaskOutputsTool(t *testing.T) {
+	idx := 3
+	store := &searchOnlyStore{result: TaskSearchResult{
+		TotalMatches: 4,
+		Hits: []TaskSearchHit{
+			{TaskName: "research", Kind: "summary", Score: 2.5, Snippet: "Acme closed a funding round"},
+			{TaskName: "score", Kind: "iteration_output", Index: &idx, ItemID: "acme", Score: 1.25, Snippet: "note: Acme is hiring"},
+		},
+	}}
+	tool := &searchTaskOutputsTool{store: store}
+
+	got := tool.Call(context.Background(), `{"query": "acme", "tasks": ["research", "score"], "limit": 500}`)
+	if store.got.Text != "acme" || len(store.got.Tasks) != 2 || store.got.Limit != maxSearchLimit {
+		t.Errorf("store got %+v, want the query, tasks and a capped limit", store.got)
+	}
+	for _, want := range []string{
+		`Found 4 matches for "acme" (showing 2)`,
+		"1. task research — summary, score 2.50\n   Acme closed a funding round",
+		"2. task score, iteration 3 (item acme) — iteration output, score 1.25\n   note: Acme is hiring",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("result should contain %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestSearchTaskOutputsTool_NoMatchesAndBadInput(t *testing.T) {
+	tool := &searchTaskOutputsTool{store: &searchOnlyStore{}}
+	cases := map[string]string{
+		`{"query": "zeppelin"}`: `No completed task results match "zeppelin"`,
+		`{"query": "  "}`:       "'query' is required",
+		`not json`:              "invalid input",
+	}
+	for input, want := range cases {
+		if got := tool.Call(context.Background(), input); !strings.Contains(got, want) {
+			t.Errorf("Call(%s) = %q, want it to contain %q", input, got, want)
+		}
+	}
+}
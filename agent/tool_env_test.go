@@ -0,0 +1,27 @@
+package agent
+
+import (
+	"context"
+	"testing"
+
+	"squadron/aitools"
+)
+
+func TestAgentToolEnvYieldsToTaskEnv(t *testing.T) {
+	a := &Agent{toolEnv: &aitools.ToolEnv{Env: map[string]string{"REGION": "us", "USER": "bot"}, Dir: "/srv"}}
+
+	env := aitools.ToolEnvFromContext(a.withToolEnv(context.Background()))
+	if env == nil || env.Dir != "/srv" || env.Env["REGION"] != "us" {
+		t.Fatalf("expected the agent's env without a task env, got %+v", env)
+	}
+
+	ctx := aitools.WithToolEnv(context.Background(), &aitools.ToolEnv{Env: map[string]string{"REGION": "eu"}, Dir: "/repos/api"})
+	env = aitools.ToolEnvFromContext(a.withToolEnv(ctx))
+	if env.Dir != "/repos/api" || env.Env["REGION"] != "eu" || env.Env["USER"] != "bot" {
+		t.Fatalf("expected task env over agent env, got %+v", env)
+	}
+
+	if aitools.ToolEnvFromContext((&Agent{}).withToolEnv(context.Background())) != nil {
+		t.Fatalf("expected no env when neither agent nor task sets one")
+	}
+}
@@ -0,0 +1,33 @@
+package agent
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+
+	"squadron/aitools"
+	"squadron/tracing"
+)
+
+// callToolTraced calls tool inside a "tool <name>" span. Results starting
+// with "Error" mark the span as failed, matching how tools report errors to
+// the model. A ToolFault on ctx can fail the call before the tool runs.
+func callToolTraced(ctx context.Context, tool aitools.Tool, name, input string, attrs ...attribute.KeyValue) string {
+	ctx, span := tracing.Start(ctx, "tool "+name, append(attrs, attribute.String("squadron.tool", name))...)
+	defer span.End()
+
+	var result string
+	if fault := aitools.ToolFaultFromContext(ctx); fault != nil {
+		result = fault(ctx, name)
+	}
+	if result == "" {
+		result = tool.Call(ctx, input)
+	}
+	if strings.HasPrefix(result, "Error") {
+		msg, _, _ := strings.Cut(result, "\n")
+		span.SetStatus(codes.Error, msg)
+	}
+	return result
+}
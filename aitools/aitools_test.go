@@ -116,6 +116,61 @@ func TestInterceptLargeJSONArray(t *testing.T) {
 	}
 }
 
+func TestInterceptPerToolLimits(t *testing.T) {
+	store := NewMemoryResultStore()
+	config := LargeResultConfigWithMaxSize(65536)
+	config.Tools = map[string]ToolLimits{
+		"browser_get_html": {ByteThreshold: 1024},
+		"sql_query":        {ItemThreshold: 101},
+	}
+	interceptor := NewResultInterceptor(store, config)
+
+	html := strings.Repeat("<div>row</div>", 200)
+	if got := interceptor.Intercept("browser_get_html", html); got.ID == "" {
+		t.Error("browser_get_html result over its 1KB limit should be intercepted")
+	}
+	if got := interceptor.Intercept("http_get", html); got.ID != "" {
+		t.Error("other tools should keep the 64KB limit")
+	}
+
+	rows := make([]map[string]int, 60)
+	for i := range rows {
+		rows[i] = map[string]int{"id": i}
+	}
+	data, _ := json.Marshal(rows)
+	if got := interceptor.Intercept("sql_query", string(data)); got.ID != "" {
+		t.Error("sql_query should pass up to 100 rows through")
+	}
+	if got := interceptor.Intercept("my_tool", string(data)); got.ID == "" {
+		t.Error("other tools should sample arrays of 20 or more items")
+	}
+}
+
+func TestInterceptJSONArrayAfterHeader(t *testing.T) {
+	store := NewMemoryResultStore()
+	interceptor := NewResultInterceptor(store, LargeResultConfigWithMaxSize(8192))
+
+	items := make([]int, 30)
+	data, _ := json.Marshal(items)
+	result := interceptor.Intercept("http_get", "Status: 200 200 OK\n\n"+string(data))
+
+	if result.ID == "" {
+		t.Fatal("expected JSON body after the header to be intercepted")
+	}
+	if !strings.HasPrefix(result.Data, "Status: 200 200 OK\n\n[") {
+		t.Errorf("expected header kept in front of the sample, got %q", result.Data)
+	}
+	stored, _ := store.Get(result.ID)
+	if stored.Type != ResultTypeArray || stored.Size != 30 {
+		t.Errorf("expected stored array of 30 items, got %s of %d", stored.Type, stored.Size)
+	}
+
+	small := "Status: 200 200 OK\n\n[1, 2]"
+	if got := interceptor.Intercept("http_get", small); got.ID != "" || got.Data != small {
+		t.Errorf("expected small body to pass through unchanged, got %+v", got)
+	}
+}
+
 func TestInterceptLargeJSONObject(t *testing.T) {
 	store := NewMemoryResultStore()
 	interceptor := NewResultInterceptor(store, LargeResultConfigWithMaxSize(8192))
@@ -178,6 +233,78 @@ func TestInterceptNilStorePassesThrough(t *testing.T) {
 	}
 }
 
+func TestInterceptCallDeduplicatesLargeResult(t *testing.T) {
+	store := NewMemoryResultStore()
+	interceptor := NewResultInterceptor(store, LargeResultConfigWithMaxSize(8192))
+
+	largeText := strings.Repeat("x", 10000)
+	first := interceptor.InterceptCall("fetch", `{"url":"a"}`, largeText)
+	if first.ID == "" {
+		t.Fatal("expected the first result to be intercepted")
+	}
+
+	second := interceptor.InterceptCall("fetch", `{"url":"a"}`, largeText)
+	if second.ID != first.ID {
+		t.Errorf("expected a reference to %q, got ID %q", first.ID, second.ID)
+	}
+	if !strings.Contains(second.Metadata, "duplicate: true") {
+		t.Errorf("expected duplicate metadata, got %q", second.Metadata)
+	}
+	if strings.Contains(second.Data, "xxx") {
+		t.Error("expected the repeated result not to be resent")
+	}
+	if len(store.GetInfo()) != 1 {
+		t.Errorf("expected 1 stored result, got %d", len(store.GetInfo()))
+	}
+
+	// A different input is a different call, even with the same result.
+	if other := interceptor.InterceptCall("fetch", `{"url":"b"}`, largeText); other.ID == first.ID {
+		t.Error("expected a call with different input not to be deduplicated")
+	}
+}
+
+func TestInterceptCallStoresRepeatedMidSizeResult(t *testing.T) {
+	store := NewMemoryResultStore()
+	interceptor := NewResultInterceptor(store, LargeResultConfigWithMaxSize(8192))
+
+	body := `{"text":"` + strings.Repeat("y", 2000) + `"}`
+	first := interceptor.InterceptCall("fetch", `{"url":"a"}`, body)
+	if first.ID != "" || first.Data != body {
+		t.Fatal("expected the first result to pass through unchanged")
+	}
+
+	second := interceptor.InterceptCall("fetch", `{"url":"a"}`, body)
+	if second.ID == "" {
+		t.Fatal("expected the repeated result to be stored and referenced")
+	}
+	r, ok := store.Get(second.ID)
+	if !ok || r.Type != ResultTypeObject || r.RawData != body {
+		t.Errorf("expected the stored copy to be the object result, got %+v", r)
+	}
+	if third := interceptor.InterceptCall("fetch", `{"url":"a"}`, body); third.ID != second.ID {
+		t.Errorf("expected later repeats to reference %q, got %q", second.ID, third.ID)
+	}
+
+	// Small results are cheaper to resend than to reference.
+	interceptor.InterceptCall("fetch", `{"url":"c"}`, "ok")
+	if small := interceptor.InterceptCall("fetch", `{"url":"c"}`, "ok"); small.Data != "ok" {
+		t.Errorf("expected a small repeated result to pass through, got %q", small.Data)
+	}
+}
+
+func TestResultStoreRestoreKeepsHashIndex(t *testing.T) {
+	store := NewMemoryResultStore()
+	interceptor := NewResultInterceptor(store, LargeResultConfigWithMaxSize(8192))
+	first := interceptor.InterceptCall("fetch", `{}`, strings.Repeat("x", 10000))
+
+	restored := NewMemoryResultStore()
+	restored.Restore(store.Snapshot())
+	again := NewResultInterceptor(restored, LargeResultConfigWithMaxSize(8192)).InterceptCall("fetch", `{}`, strings.Repeat("x", 10000))
+	if again.ID != first.ID || !strings.Contains(again.Metadata, "duplicate: true") {
+		t.Errorf("expected a reference to %q after restore, got %+v", first.ID, again)
+	}
+}
+
 func TestInterceptArrayBelowItemThresholdButLargeBytes(t *testing.T) {
 	store := NewMemoryResultStore()
 	config := LargeResultConfigWithMaxSize(8192)
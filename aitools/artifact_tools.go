@@ -0,0 +1,347 @@
+package aitools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// ArtifactRefPrefix starts every artifact reference, e.g. "artifact:k3j9x0a1b2c4".
+// A task's `file` output fields hold these references.
+const ArtifactRefPrefix = "artifact:"
+
+// Artifacts stores the files a mission produces, so a task can hand a file
+// to the tasks after it: an agent registers the file with artifact_put, the
+// commander submits the reference in a `file` output field, and a downstream
+// task fetches it with artifact_get.
+type Artifacts interface {
+	// Put stores the content read from r. Name and MediaType come from a;
+	// Task and Author are stamped by the scoped view.
+	Put(ctx context.Context, a Artifact, r io.Reader) (Artifact, error)
+	// Stat returns the artifact a reference points to.
+	Stat(ctx context.Context, ref string) (Artifact, error)
+	// Open returns the artifact and a reader for its content.
+	Open(ctx context.Context, ref string) (io.ReadCloser, Artifact, error)
+	// List returns the mission's artifacts. A non-empty task restricts the
+	// list to artifacts registered by that task.
+	List(ctx context.Context, task string) ([]Artifact, error)
+}
+
+// Artifact describes one stored artifact.
+type Artifact struct {
+	Ref       string `json:"ref"`
+	Name      string `json:"name"`
+	MediaType string `json:"mediaType,omitempty"`
+	Size      int64  `json:"size"`
+	Task      string `json:"task,omitempty"`
+	Author    string `json:"author,omitempty"`
+}
+
+// ScopeArtifacts returns a view of a that stamps every artifact it stores
+// with the given task and author. Returns nil when a is nil.
+func ScopeArtifacts(a Artifacts, task, author string) Artifacts {
+	if a == nil {
+		return nil
+	}
+	return &scopedArtifacts{Artifacts: a, task: task, author: author}
+}
+
+type scopedArtifacts struct {
+	Artifacts
+	task   string
+	author string
+}
+
+func (s *scopedArtifacts) Put(ctx context.Context, a Artifact, r io.Reader) (Artifact, error) {
+	a.Task = s.task
+	a.Author = s.author
+	return s.Artifacts.Put(ctx, a, r)
+}
+
+// artifactMediaType guesses a media type from the file name, then from the
+// first bytes of the content.
+func artifactMediaType(name string, head []byte) string {
+	if t := mime.TypeByExtension(filepath.Ext(name)); t != "" {
+		return t
+	}
+	return http.DetectContentType(head)
+}
+
+func artifactJSON(v any) string {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "Error: " + err.Error()
+	}
+	return string(b)
+}
+
+// =============================================================================
+// artifact_put — Register a file as an artifact
+// =============================================================================
+
+// ArtifactPutTool registers a file from a slot, or inline text, as an
+// artifact and returns its reference.
+type ArtifactPutTool struct {
+	Artifacts Artifacts
+	Store     MemoryStore // nil when the mission has no slots; only content is accepted then
+}
+
+func (t *ArtifactPutTool) ToolName() string { return "artifact_put" }
+
+func (t *ArtifactPutTool) ToolDescription() string {
+	return "Register a file as a mission artifact so later tasks can fetch it with artifact_get. Pass a slot and path to register a file you wrote (any format, including binary), or content to register text directly. Returns the artifact reference (\"artifact:...\") to put in a `file` output field or hand to your commander."
+}
+
+func (t *ArtifactPutTool) ToolPayloadSchema() Schema {
+	return Schema{
+		Type: TypeObject,
+		Properties: PropertyMap{
+			"name": {
+				Type:        TypeString,
+				Description: "File name for the artifact, e.g. \"report.pdf\". Defaults to the base name of path.",
+			},
+			"slot": {
+				Type:        TypeString,
+				Description: slotParamDescription,
+			},
+			"path": {
+				Type:        TypeString,
+				Description: "Relative path of the file within the slot.",
+			},
+			"content": {
+				Type:        TypeString,
+				Description: "Text content to register instead of a file.",
+			},
+			"media_type": {
+				Type:        TypeString,
+				Description: "Optional media type, e.g. \"application/pdf\". Guessed from the name and content when omitted.",
+			},
+		},
+	}
+}
+
+type artifactPutParams struct {
+	Name      string  `json:"name"`
+	Slot      string  `json:"slot"`
+	Path      string  `json:"path"`
+	Content   *string `json:"content"`
+	MediaType string  `json:"media_type"`
+}
+
+func (t *ArtifactPutTool) Call(ctx context.Context, params string) string {
+	var p artifactPutParams
+	if err := json.Unmarshal([]byte(params), &p); err != nil {
+		return "Error: invalid parameters - " + err.Error()
+	}
+	if (p.Path == "") == (p.Content == nil) {
+		return "Error: pass either path (with slot) or content"
+	}
+
+	var r io.Reader
+	if p.Content != nil {
+		if p.Name == "" {
+			return "Error: name is required with content"
+		}
+		r = strings.NewReader(*p.Content)
+	} else {
+		if t.Store == nil {
+			return "Error: no slots are available; pass content instead"
+		}
+		absPath, err := resolveSlotPath(t.Store, p.Slot, p.Path)
+		if err != nil {
+			return "Error: " + err.Error()
+		}
+		f, err := os.Open(absPath)
+		if err != nil {
+			return "Error: " + err.Error()
+		}
+		defer f.Close()
+		if info, err := f.Stat(); err == nil && info.IsDir() {
+			return "Error: path is a directory, not a file"
+		}
+		if p.Name == "" {
+			p.Name = filepath.Base(p.Path)
+		}
+		r = f
+	}
+
+	// Peek at the start of the content to guess a media type
+	if p.MediaType == "" {
+		head := make([]byte, 512)
+		n, err := io.ReadFull(r, head)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return "Error: " + err.Error()
+		}
+		head = head[:n]
+		p.MediaType = artifactMediaType(p.Name, head)
+		r = io.MultiReader(bytes.NewReader(head), r)
+	}
+
+	a, err := t.Artifacts.Put(ctx, Artifact{Name: p.Name, MediaType: p.MediaType}, r)
+	if err != nil {
+		return "Error: " + err.Error()
+	}
+	return artifactJSON(a)
+}
+
+// =============================================================================
+// artifact_get — Fetch an artifact
+// =============================================================================
+
+// ArtifactGetTool fetches an artifact by reference, returning text content
+// inline or saving the file to a slot.
+type ArtifactGetTool struct {
+	Artifacts Artifacts
+	Store     MemoryStore // nil when the mission has no slots
+}
+
+func (t *ArtifactGetTool) ToolName() string { return "artifact_get" }
+
+func (t *ArtifactGetTool) ToolDescription() string {
+	return "Fetch a mission artifact by its reference (\"artifact:...\"), e.g. from an upstream task's `file` output field. Pass a slot and path to save the file there (needed for binary files); otherwise text content is returned inline."
+}
+
+func (t *ArtifactGetTool) ToolPayloadSchema() Schema {
+	return Schema{
+		Type: TypeObject,
+		Properties: PropertyMap{
+			"ref": {
+				Type:        TypeString,
+				Description: "The artifact reference, e.g. \"artifact:k3j9x0a1b2c4\".",
+			},
+			"slot": {
+				Type:        TypeString,
+				Description: slotParamDescription,
+			},
+			"path": {
+				Type:        TypeString,
+				Description: "Relative path within the slot to save the file to. Overwrites an existing file.",
+			},
+		},
+		Required: []string{"ref"},
+	}
+}
+
+type artifactGetParams struct {
+	Ref  string `json:"ref"`
+	Slot string `json:"slot"`
+	Path string `json:"path"`
+}
+
+func (t *ArtifactGetTool) Call(ctx context.Context, params string) string {
+	var p artifactGetParams
+	if err := json.Unmarshal([]byte(params), &p); err != nil {
+		return "Error: invalid parameters - " + err.Error()
+	}
+	if p.Ref == "" {
+		return "Error: ref is required"
+	}
+
+	if p.Path != "" {
+		if t.Store == nil {
+			return "Error: no slots are available to save to"
+		}
+		if IsPacketSlot(p.Slot) {
+			return "Error: slot is read-only (packet bundles are immutable)"
+		}
+		absPath, err := resolveSlotPath(t.Store, p.Slot, p.Path)
+		if err != nil {
+			return "Error: " + err.Error()
+		}
+		rc, a, err := t.Artifacts.Open(ctx, p.Ref)
+		if err != nil {
+			return "Error: " + err.Error()
+		}
+		defer rc.Close()
+		if err := os.MkdirAll(filepath.Dir(absPath), 0755); err != nil {
+			return "Error: " + err.Error()
+		}
+		f, err := os.Create(absPath)
+		if err != nil {
+			return "Error: " + err.Error()
+		}
+		if _, err := io.Copy(f, rc); err != nil {
+			f.Close()
+			return "Error: " + err.Error()
+		}
+		if err := f.Close(); err != nil {
+			return "Error: " + err.Error()
+		}
+		return fmt.Sprintf("Saved %s (%s, %s) to %s", a.Name, a.MediaType, formatSize(a.Size), p.Path)
+	}
+
+	a, err := t.Artifacts.Stat(ctx, p.Ref)
+	if err != nil {
+		return "Error: " + err.Error()
+	}
+	if a.Size > maxReadSize {
+		return fmt.Sprintf("Error: artifact too large to return inline (%s). Pass slot and path to save it.", formatSize(a.Size))
+	}
+	rc, _, err := t.Artifacts.Open(ctx, p.Ref)
+	if err != nil {
+		return "Error: " + err.Error()
+	}
+	defer rc.Close()
+	content, err := io.ReadAll(rc)
+	if err != nil {
+		return "Error: " + err.Error()
+	}
+	if !utf8.Valid(content) {
+		return fmt.Sprintf("%s is binary (%s, %s). Pass slot and path to save it to a file.", a.Name, a.MediaType, formatSize(a.Size))
+	}
+	return string(content)
+}
+
+// =============================================================================
+// artifact_list — List the mission's artifacts
+// =============================================================================
+
+// ArtifactListTool lists the mission's artifacts.
+type ArtifactListTool struct {
+	Artifacts Artifacts
+}
+
+func (t *ArtifactListTool) ToolName() string { return "artifact_list" }
+
+func (t *ArtifactListTool) ToolDescription() string {
+	return "List the artifacts registered so far in this mission, with their references, names, media types, sizes, and the task and author that registered them."
+}
+
+func (t *ArtifactListTool) ToolPayloadSchema() Schema {
+	return Schema{
+		Type: TypeObject,
+		Properties: PropertyMap{
+			"task": {
+				Type:        TypeString,
+				Description: "Optional task name to only list artifacts registered by that task.",
+			},
+		},
+	}
+}
+
+func (t *ArtifactListTool) Call(ctx context.Context, params string) string {
+	var p struct {
+		Task string `json:"task"`
+	}
+	if params != "" {
+		if err := json.Unmarshal([]byte(params), &p); err != nil {
+			return "Error: invalid parameters - " + err.Error()
+		}
+	}
+	list, err := t.Artifacts.List(ctx, p.Task)
+	if err != nil {
+		return "Error: " + err.Error()
+	}
+	if len(list) == 0 {
+		return "No artifacts found."
+	}
+	return artifactJSON(list)
+}
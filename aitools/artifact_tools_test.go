@@ -0,0 +1,140 @@
+package aitools_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"squadron/aitools"
+)
+
+// fakeArtifacts keeps artifacts in memory, numbering references in order.
+type fakeArtifacts struct {
+	items   []aitools.Artifact
+	content map[string][]byte
+}
+
+func newFakeArtifacts() *fakeArtifacts {
+	return &fakeArtifacts{content: map[string][]byte{}}
+}
+
+func (f *fakeArtifacts) Put(_ context.Context, a aitools.Artifact, r io.Reader) (aitools.Artifact, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return aitools.Artifact{}, err
+	}
+	a.Ref = fmt.Sprintf("%sa%d", aitools.ArtifactRefPrefix, len(f.items)+1)
+	a.Size = int64(len(data))
+	f.items = append(f.items, a)
+	f.content[a.Ref] = data
+	return a, nil
+}
+
+func (f *fakeArtifacts) Stat(_ context.Context, ref string) (aitools.Artifact, error) {
+	for _, a := range f.items {
+		if a.Ref == ref {
+			return a, nil
+		}
+	}
+	return aitools.Artifact{}, fmt.Errorf("artifact %q not found", ref)
+}
+
+func (f *fakeArtifacts) Open(ctx context.Context, ref string) (io.ReadCloser, aitools.Artifact, error) {
+	a, err := f.Stat(ctx, ref)
+	if err != nil {
+		return nil, aitools.Artifact{}, err
+	}
+	return io.NopCloser(bytes.NewReader(f.content[ref])), a, nil
+}
+
+func (f *fakeArtifacts) List(_ context.Context, task string) ([]aitools.Artifact, error) {
+	var out []aitools.Artifact
+	for _, a := range f.items {
+		if task == "" || a.Task == task {
+			out = append(out, a)
+		}
+	}
+	return out, nil
+}
+
+var _ = Describe("Artifact tools", func() {
+	var (
+		store     *fakeStore
+		artifacts *fakeArtifacts
+		ctx       = context.Background()
+	)
+
+	BeforeEach(func() {
+		store = newFakeStore("scratch")
+		artifacts = newFakeArtifacts()
+	})
+	AfterEach(func() { store.cleanup() })
+
+	It("artifact_put registers a slot file stamped with the scoped task and author", func() {
+		png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+		Expect(os.WriteFile(filepath.Join(store.slots["scratch"], "chart.png"), png, 0644)).To(Succeed())
+		tool := &aitools.ArtifactPutTool{Artifacts: aitools.ScopeArtifacts(artifacts, "render", "designer"), Store: store}
+
+		out := tool.Call(ctx, `{"slot":"scratch","path":"chart.png"}`)
+		Expect(out).To(ContainSubstring(`"ref": "artifact:a1"`))
+		Expect(artifacts.items).To(Equal([]aitools.Artifact{{
+			Ref: "artifact:a1", Name: "chart.png", MediaType: "image/png", Size: int64(len(png)), Task: "render", Author: "designer",
+		}}))
+		Expect(artifacts.content["artifact:a1"]).To(Equal(png))
+	})
+
+	It("artifact_put accepts inline content and rejects ambiguous input", func() {
+		tool := &aitools.ArtifactPutTool{Artifacts: artifacts}
+
+		Expect(tool.Call(ctx, `{"name":"notes.md","content":"# Notes"}`)).To(ContainSubstring(`"ref": "artifact:a1"`))
+		Expect(artifacts.items[0].MediaType).To(HavePrefix("text/"))
+
+		Expect(tool.Call(ctx, `{"content":"x"}`)).To(Equal("Error: name is required with content"))
+		Expect(tool.Call(ctx, `{"name":"a","path":"a","content":"x"}`)).To(Equal("Error: pass either path (with slot) or content"))
+		Expect(tool.Call(ctx, `{"slot":"scratch","path":"a.txt"}`)).To(Equal("Error: no slots are available; pass content instead"))
+	})
+
+	It("artifact_get returns text inline and saves binary files to a slot", func() {
+		put := &aitools.ArtifactPutTool{Artifacts: artifacts}
+		put.Call(ctx, `{"name":"notes.md","content":"# Notes"}`)
+		put.Call(ctx, `{"name":"blob.bin","content":"\u0000ÿ"}`)
+		artifacts.content["artifact:a2"] = []byte{0xff, 0xfe, 0x00}
+
+		get := &aitools.ArtifactGetTool{Artifacts: artifacts, Store: store}
+		Expect(get.Call(ctx, `{"ref":"artifact:a1"}`)).To(Equal("# Notes"))
+		Expect(get.Call(ctx, `{"ref":"artifact:a2"}`)).To(ContainSubstring("is binary"))
+
+		Expect(get.Call(ctx, `{"ref":"artifact:a2","slot":"scratch","path":"in/blob.bin"}`)).To(HavePrefix("Saved blob.bin"))
+		Expect(os.ReadFile(filepath.Join(store.slots["scratch"], "in", "blob.bin"))).To(Equal([]byte{0xff, 0xfe, 0x00}))
+
+		Expect(get.Call(ctx, `{"ref":"artifact:nope"}`)).To(HavePrefix("Error: "))
+	})
+
+	It("artifact_list filters by task", func() {
+		(&aitools.ArtifactPutTool{Artifacts: aitools.ScopeArtifacts(artifacts, "a", "commander")}).Call(ctx, `{"name":"x.txt","content":"x"}`)
+		(&aitools.ArtifactPutTool{Artifacts: aitools.ScopeArtifacts(artifacts, "b", "commander")}).Call(ctx, `{"name":"y.txt","content":"y"}`)
+		tool := &aitools.ArtifactListTool{Artifacts: artifacts}
+
+		out := tool.Call(ctx, `{"task":"b"}`)
+		Expect(out).To(ContainSubstring(`"name": "y.txt"`))
+		Expect(out).NotTo(ContainSubstring(`"name": "x.txt"`))
+		Expect(tool.Call(ctx, `{"task":"c"}`)).To(Equal("No artifacts found."))
+	})
+
+	It("submit_output requires known artifact references in file fields", func() {
+		(&aitools.ArtifactPutTool{Artifacts: artifacts}).Call(ctx, `{"name":"report.pdf","content":"%PDF"}`)
+		tool := aitools.NewSubmitOutputTool([]aitools.OutputField{{Name: "report", Type: "file", Required: true}})
+		tool.Artifacts = artifacts
+
+		Expect(tool.Call(ctx, `{"output":{"report":"/tmp/report.pdf"}}`)).To(ContainSubstring("must be an artifact reference"))
+		Expect(tool.Call(ctx, `{"output":{"report":"artifact:a9"}}`)).To(ContainSubstring("not found"))
+		Expect(tool.Call(ctx, `{"output":{"report":"artifact:a1"}}`)).To(ContainSubstring(`"status": "ok"`))
+		Expect(tool.GetResults()).To(HaveLen(1))
+	})
+})
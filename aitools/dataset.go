@@ -129,12 +129,19 @@ func (t *SetDatasetTool) Call(ctx context.Context, params string) string {
 		if err := t.Store.AppendDataset(input.Name, ctyItems); err != nil {
 			return fmt.Sprintf("Error: %v", err)
 		}
+		// The dataset's transforms may have dropped some of the items
+		if count, err := t.Store.GetDatasetCount(input.Name); err == nil {
+			return fmt.Sprintf("Successfully appended %d items to dataset '%s' (%d items total)", len(ctyItems), input.Name, count)
+		}
 		return fmt.Sprintf("Successfully appended %d items to dataset '%s'", len(ctyItems), input.Name)
 	}
 
 	if err := t.Store.SetDataset(input.Name, ctyItems); err != nil {
 		return fmt.Sprintf("Error: %v", err)
 	}
+	if count, err := t.Store.GetDatasetCount(input.Name); err == nil {
+		return fmt.Sprintf("Successfully set dataset '%s' with %d items", input.Name, count)
+	}
 	return fmt.Sprintf("Successfully set dataset '%s' with %d items", input.Name, len(ctyItems))
 }
 
@@ -41,6 +41,22 @@ func (c *DatasetCursor) CurrentIndex() int {
 	return c.index - 1
 }
 
+// Position returns how many items Next has handed out so far.
+func (c *DatasetCursor) Position() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.index
+}
+
+// Seek moves the cursor so the next item returned is items[position].
+// Used to restore a checkpointed cursor; position is clamped to the
+// dataset bounds.
+func (c *DatasetCursor) Seek(position int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.index = max(0, min(position, len(c.items)))
+}
+
 // =============================================================================
 // DatasetNextTool - advances to the next item in the dataset
 // =============================================================================
@@ -19,14 +19,16 @@ var httpClient = &http.Client{
 }
 
 // HTTPGetTool performs HTTP GET requests
-type HTTPGetTool struct{}
+type HTTPGetTool struct {
+	Auth *HTTPAuthenticator
+}
 
 func (t *HTTPGetTool) ToolName() string {
 	return "http_get"
 }
 
 func (t *HTTPGetTool) ToolDescription() string {
-	return "Performs an HTTP GET request to the specified URL and returns the response body."
+	return "Performs an HTTP GET request to the specified URL and returns the response body." + t.Auth.describe()
 }
 
 func (t *HTTPGetTool) ToolPayloadSchema() Schema {
@@ -70,18 +72,20 @@ func (t *HTTPGetTool) Call(ctx context.Context, params string) string {
 		req.Header.Set(k, v)
 	}
 
-	return executeRequest(req)
+	return executeRequest(req, t.Auth)
 }
 
 // HTTPPostTool performs HTTP POST requests
-type HTTPPostTool struct{}
+type HTTPPostTool struct {
+	Auth *HTTPAuthenticator
+}
 
 func (t *HTTPPostTool) ToolName() string {
 	return "http_post"
 }
 
 func (t *HTTPPostTool) ToolDescription() string {
-	return "Performs an HTTP POST request to the specified URL with a body and returns the response. Supports JSON, form data, and plain text content types."
+	return "Performs an HTTP POST request to the specified URL with a body and returns the response. Supports JSON, form data, and plain text content types." + t.Auth.describe()
 }
 
 func (t *HTTPPostTool) ToolPayloadSchema() Schema {
@@ -126,18 +130,20 @@ func (t *HTTPPostTool) Call(ctx context.Context, params string) string {
 		return "Error: url is required"
 	}
 
-	return executeBodyRequest(ctx, "POST", p)
+	return executeBodyRequest(ctx, "POST", p, t.Auth)
 }
 
 // HTTPPutTool performs HTTP PUT requests
-type HTTPPutTool struct{}
+type HTTPPutTool struct {
+	Auth *HTTPAuthenticator
+}
 
 func (t *HTTPPutTool) ToolName() string {
 	return "http_put"
 }
 
 func (t *HTTPPutTool) ToolDescription() string {
-	return "Performs an HTTP PUT request to the specified URL with a body and returns the response. Supports JSON, form data, and plain text content types."
+	return "Performs an HTTP PUT request to the specified URL with a body and returns the response. Supports JSON, form data, and plain text content types." + t.Auth.describe()
 }
 
 func (t *HTTPPutTool) ToolPayloadSchema() Schema {
@@ -175,18 +181,20 @@ func (t *HTTPPutTool) Call(ctx context.Context, params string) string {
 		return "Error: url is required"
 	}
 
-	return executeBodyRequest(ctx, "PUT", p)
+	return executeBodyRequest(ctx, "PUT", p, t.Auth)
 }
 
 // HTTPPatchTool performs HTTP PATCH requests
-type HTTPPatchTool struct{}
+type HTTPPatchTool struct {
+	Auth *HTTPAuthenticator
+}
 
 func (t *HTTPPatchTool) ToolName() string {
 	return "http_patch"
 }
 
 func (t *HTTPPatchTool) ToolDescription() string {
-	return "Performs an HTTP PATCH request to the specified URL with a body and returns the response. Supports JSON, form data, and plain text content types."
+	return "Performs an HTTP PATCH request to the specified URL with a body and returns the response. Supports JSON, form data, and plain text content types." + t.Auth.describe()
 }
 
 func (t *HTTPPatchTool) ToolPayloadSchema() Schema {
@@ -224,18 +232,20 @@ func (t *HTTPPatchTool) Call(ctx context.Context, params string) string {
 		return "Error: url is required"
 	}
 
-	return executeBodyRequest(ctx, "PATCH", p)
+	return executeBodyRequest(ctx, "PATCH", p, t.Auth)
 }
 
 // HTTPDeleteTool performs HTTP DELETE requests
-type HTTPDeleteTool struct{}
+type HTTPDeleteTool struct {
+	Auth *HTTPAuthenticator
+}
 
 func (t *HTTPDeleteTool) ToolName() string {
 	return "http_delete"
 }
 
 func (t *HTTPDeleteTool) ToolDescription() string {
-	return "Performs an HTTP DELETE request to the specified URL and returns the response."
+	return "Performs an HTTP DELETE request to the specified URL and returns the response." + t.Auth.describe()
 }
 
 func (t *HTTPDeleteTool) ToolPayloadSchema() Schema {
@@ -274,12 +284,12 @@ func (t *HTTPDeleteTool) Call(ctx context.Context, params string) string {
 		req.Header.Set(k, v)
 	}
 
-	return executeRequest(req)
+	return executeRequest(req, t.Auth)
 }
 
 // Helper functions
 
-func executeBodyRequest(ctx context.Context, method string, p httpBodyParams) string {
+func executeBodyRequest(ctx context.Context, method string, p httpBodyParams, auth *HTTPAuthenticator) string {
 	var bodyReader io.Reader
 	var contentType string
 
@@ -339,22 +349,34 @@ func executeBodyRequest(ctx context.Context, method string, p httpBodyParams) st
 		req.Header.Set(k, v)
 	}
 
-	return executeRequest(req)
+	return executeRequest(req, auth)
 }
 
-func executeRequest(req *http.Request) string {
+func executeRequest(req *http.Request, auth *HTTPAuthenticator) string {
+	resp, body, err := doRequest(req, auth)
+	if err != nil {
+		return "Error: " + err.Error()
+	}
+	return fmt.Sprintf("Status: %d %s\n\n%s", resp.StatusCode, resp.Status, string(body))
+}
+
+// doRequest sends req with the user agent and any matching auth headers
+// set, and reads the whole response body.
+func doRequest(req *http.Request, auth *HTTPAuthenticator) (*http.Response, []byte, error) {
 	req.Header.Set("User-Agent", userAgent)
+	if err := auth.apply(req); err != nil {
+		return nil, nil, err
+	}
 
 	resp, err := httpClient.Do(req)
 	if err != nil {
-		return "Error: request failed - " + err.Error()
+		return nil, nil, fmt.Errorf("request failed - %w", err)
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "Error: failed to read response - " + err.Error()
+		return nil, nil, fmt.Errorf("failed to read response - %w", err)
 	}
-
-	return fmt.Sprintf("Status: %d %s\n\n%s", resp.StatusCode, resp.Status, string(body))
+	return resp, body, nil
 }
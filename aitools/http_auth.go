@@ -0,0 +1,103 @@
+package aitools
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// httpSecretPattern matches ${secrets.name} placeholders in auth header
+// templates. Same syntax the agent uses in tool inputs.
+var httpSecretPattern = regexp.MustCompile(`\$\{secrets\.([a-zA-Z_][a-zA-Z0-9_]*)\}`)
+
+// HTTPAuth is one auth rule: requests to a host in Hosts get Headers.
+// Header values are templates that may reference ${secrets.name}.
+type HTTPAuth struct {
+	Name    string
+	Hosts   []string // Exact host names, or "*.example.com" for any subdomain
+	Headers map[string]string
+}
+
+// matches reports whether host is covered by the rule.
+func (a *HTTPAuth) matches(host string) bool {
+	host = strings.ToLower(host)
+	for _, h := range a.Hosts {
+		h = strings.ToLower(h)
+		if suffix, ok := strings.CutPrefix(h, "*."); ok {
+			if strings.HasSuffix(host, "."+suffix) {
+				return true
+			}
+			continue
+		}
+		if host == h {
+			return true
+		}
+	}
+	return false
+}
+
+// HTTPAuthenticator adds configured auth headers to outgoing requests so
+// credentials never pass through the model. The zero value and nil are
+// both valid and add nothing.
+type HTTPAuthenticator struct {
+	Rules   []HTTPAuth
+	Secrets map[string]string // Secret name → value, for the header templates
+}
+
+// apply sets the headers of the first rule matching req's host. Headers
+// the agent already set are left alone.
+func (a *HTTPAuthenticator) apply(req *http.Request) error {
+	if a == nil {
+		return nil
+	}
+	host := req.URL.Hostname()
+	for _, rule := range a.Rules {
+		if !rule.matches(host) {
+			continue
+		}
+		for k, tmpl := range rule.Headers {
+			if req.Header.Get(k) != "" {
+				continue
+			}
+			v, err := a.render(tmpl)
+			if err != nil {
+				return fmt.Errorf("auth '%s': header %s: %w", rule.Name, k, err)
+			}
+			req.Header.Set(k, v)
+		}
+		return nil
+	}
+	return nil
+}
+
+// render substitutes ${secrets.name} placeholders in tmpl.
+func (a *HTTPAuthenticator) render(tmpl string) (string, error) {
+	var missing string
+	out := httpSecretPattern.ReplaceAllStringFunc(tmpl, func(match string) string {
+		name := httpSecretPattern.FindStringSubmatch(match)[1]
+		v, ok := a.Secrets[name]
+		if !ok {
+			missing = name
+			return match
+		}
+		return v
+	})
+	if missing != "" {
+		return "", fmt.Errorf("unknown secret: %s", missing)
+	}
+	return out, nil
+}
+
+// describe lists the hosts that get auth headers, for tool descriptions.
+// The header values are never included.
+func (a *HTTPAuthenticator) describe() string {
+	if a == nil || len(a.Rules) == 0 {
+		return ""
+	}
+	var hosts []string
+	for _, r := range a.Rules {
+		hosts = append(hosts, r.Hosts...)
+	}
+	return " Auth headers are added automatically for: " + strings.Join(hosts, ", ") + " — don't set them yourself."
+}
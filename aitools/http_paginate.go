@@ -0,0 +1,217 @@
+package aitools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const (
+	defaultPaginateMaxPages = 10
+	maxPaginateMaxPages     = 100
+)
+
+// HTTPPaginateTool follows a paginated JSON API and returns every page's
+// items as one JSON array. Large results are picked up by the result
+// interceptor like any other array, so the agent gets a sample plus a
+// result ID to page through with the result_* tools.
+type HTTPPaginateTool struct {
+	Auth *HTTPAuthenticator
+}
+
+func (t *HTTPPaginateTool) ToolName() string {
+	return "http_paginate"
+}
+
+func (t *HTTPPaginateTool) ToolDescription() string {
+	return "Fetches every page of a paginated JSON API with GET requests and returns all items as one JSON array. Follows the next page via a cursor field in the response body (cursor_path + cursor_param), a next-page URL in the body (next_url_path), or the Link response header (rel=\"next\"), in that order of preference." + t.Auth.describe()
+}
+
+func (t *HTTPPaginateTool) ToolPayloadSchema() Schema {
+	return Schema{
+		Type: TypeObject,
+		Properties: PropertyMap{
+			"url": {
+				Type:        TypeString,
+				Description: "URL of the first page",
+			},
+			"headers": {
+				Type:        TypeObject,
+				Description: "Optional headers to include in every request (key-value pairs)",
+			},
+			"items_path": {
+				Type:        TypeString,
+				Description: "Dot-separated path to the items array in each response (e.g. 'data' or 'result.items'). Omit when the response body is the array itself.",
+			},
+			"cursor_path": {
+				Type:        TypeString,
+				Description: "Dot-separated path to the next-page cursor in the response (e.g. 'meta.next_cursor'). Requires cursor_param.",
+			},
+			"cursor_param": {
+				Type:        TypeString,
+				Description: "Query parameter the cursor is sent back in (e.g. 'cursor' or 'page_token').",
+			},
+			"next_url_path": {
+				Type:        TypeString,
+				Description: "Dot-separated path to the next page's URL in the response (e.g. 'next' or 'links.next').",
+			},
+			"max_pages": {
+				Type:        TypeInteger,
+				Description: fmt.Sprintf("Maximum pages to fetch. Default %d, max %d.", defaultPaginateMaxPages, maxPaginateMaxPages),
+			},
+		},
+		Required: []string{"url"},
+	}
+}
+
+type httpPaginateParams struct {
+	URL         string            `json:"url"`
+	Headers     map[string]string `json:"headers"`
+	ItemsPath   string            `json:"items_path"`
+	CursorPath  string            `json:"cursor_path"`
+	CursorParam string            `json:"cursor_param"`
+	NextURLPath string            `json:"next_url_path"`
+	MaxPages    int               `json:"max_pages"`
+}
+
+func (t *HTTPPaginateTool) Call(ctx context.Context, params string) string {
+	var p httpPaginateParams
+	if err := json.Unmarshal([]byte(params), &p); err != nil {
+		return "Error: invalid parameters - " + err.Error()
+	}
+	if p.URL == "" {
+		return "Error: url is required"
+	}
+	if p.CursorPath != "" && p.CursorParam == "" {
+		return "Error: cursor_param is required with cursor_path"
+	}
+	if p.MaxPages <= 0 {
+		p.MaxPages = defaultPaginateMaxPages
+	}
+	if p.MaxPages > maxPaginateMaxPages {
+		p.MaxPages = maxPaginateMaxPages
+	}
+
+	items := []any{}
+	next := p.URL
+	pages := 0
+	for next != "" && pages < p.MaxPages {
+		req, err := http.NewRequestWithContext(ctx, "GET", next, nil)
+		if err != nil {
+			return "Error: failed to create request - " + err.Error()
+		}
+		for k, v := range p.Headers {
+			req.Header.Set(k, v)
+		}
+		resp, body, err := doRequest(req, t.Auth)
+		if err != nil {
+			return fmt.Sprintf("Error: page %d: %s", pages+1, err.Error())
+		}
+		pages++
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return fmt.Sprintf("Error: page %d: status %s\n\n%s", pages, resp.Status, string(body))
+		}
+
+		var doc any
+		if err := json.Unmarshal(body, &doc); err != nil {
+			return fmt.Sprintf("Error: page %d: response is not JSON - %s", pages, err.Error())
+		}
+		pageItems, ok := jsonPath(doc, p.ItemsPath).([]any)
+		if !ok {
+			return fmt.Sprintf("Error: page %d: no array at items_path %q", pages, p.ItemsPath)
+		}
+		items = append(items, pageItems...)
+		if len(pageItems) == 0 {
+			break
+		}
+
+		next, err = nextPageURL(req.URL, resp, doc, p)
+		if err != nil {
+			return fmt.Sprintf("Error: page %d: %s", pages, err.Error())
+		}
+	}
+
+	out, err := json.Marshal(items)
+	if err != nil {
+		return "Error: failed to encode items - " + err.Error()
+	}
+	more := ""
+	if next != "" {
+		more = fmt.Sprintf(" (stopped at max_pages; next page: %s)", next)
+	}
+	return fmt.Sprintf("Fetched %d pages, %d items%s\n\n%s", pages, len(items), more, string(out))
+}
+
+// nextPageURL works out the URL of the page after cur, or "" on the last
+// page.
+func nextPageURL(cur *url.URL, resp *http.Response, doc any, p httpPaginateParams) (string, error) {
+	switch {
+	case p.CursorPath != "":
+		cursor := jsonPath(doc, p.CursorPath)
+		if cursor == nil || cursor == "" || cursor == false {
+			return "", nil
+		}
+		u := *cur
+		q := u.Query()
+		q.Set(p.CursorParam, fmt.Sprint(cursor))
+		u.RawQuery = q.Encode()
+		return u.String(), nil
+	case p.NextURLPath != "":
+		s, _ := jsonPath(doc, p.NextURLPath).(string)
+		if s == "" {
+			return "", nil
+		}
+		return resolveNextURL(cur, s)
+	default:
+		s := linkNext(resp.Header.Values("Link"))
+		if s == "" {
+			return "", nil
+		}
+		return resolveNextURL(cur, s)
+	}
+}
+
+// resolveNextURL resolves a possibly relative next-page reference against
+// the current page's URL.
+func resolveNextURL(cur *url.URL, ref string) (string, error) {
+	u, err := cur.Parse(ref)
+	if err != nil {
+		return "", fmt.Errorf("invalid next page URL %q - %w", ref, err)
+	}
+	return u.String(), nil
+}
+
+var linkNextPattern = regexp.MustCompile(`<([^>]*)>\s*;[^,]*\brel="?next"?`)
+
+// linkNext returns the rel="next" target of RFC 8288 Link headers.
+func linkNext(headers []string) string {
+	for _, h := range headers {
+		for _, part := range strings.Split(h, ",") {
+			if m := linkNextPattern.FindStringSubmatch(part); m != nil {
+				return m[1]
+			}
+		}
+	}
+	return ""
+}
+
+// jsonPath walks a dot-separated path of object keys. An empty path
+// returns doc itself; a missing key returns nil.
+func jsonPath(doc any, path string) any {
+	if path == "" {
+		return doc
+	}
+	cur := doc
+	for _, key := range strings.Split(path, ".") {
+		obj, ok := cur.(map[string]any)
+		if !ok {
+			return nil
+		}
+		cur = obj[key]
+	}
+	return cur
+}
@@ -0,0 +1,142 @@
+package aitools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHTTPAuthAddsHeadersForMatchingHosts(t *testing.T) {
+	var got []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Get("Authorization"))
+	}))
+	defer srv.Close()
+
+	auth := &HTTPAuthenticator{
+		Rules: []HTTPAuth{{
+			Name:    "local",
+			Hosts:   []string{"127.0.0.1"},
+			Headers: map[string]string{"Authorization": "Bearer ${secrets.token}"},
+		}},
+		Secrets: map[string]string{"token": "s3cret"},
+	}
+	tool := &HTTPGetTool{Auth: auth}
+
+	tool.Call(context.Background(), fmt.Sprintf(`{"url": %q}`, srv.URL))
+	tool.Call(context.Background(), fmt.Sprintf(`{"url": %q, "headers": {"Authorization": "Basic mine"}}`, srv.URL))
+
+	if len(got) != 2 || got[0] != "Bearer s3cret" || got[1] != "Basic mine" {
+		t.Errorf("expected configured header, then the agent's own, got %v", got)
+	}
+	if strings.Contains(tool.ToolDescription(), "s3cret") {
+		t.Error("tool description must not include secret values")
+	}
+}
+
+func TestHTTPAuthHostMatching(t *testing.T) {
+	a := HTTPAuth{Hosts: []string{"api.github.com", "*.example.com"}}
+	for host, want := range map[string]bool{
+		"api.github.com":  true,
+		"API.GitHub.com":  true,
+		"github.com":      false,
+		"a.example.com":   true,
+		"a.b.example.com": true,
+		"example.com":     false,
+		"evilexample.com": false,
+	} {
+		if got := a.matches(host); got != want {
+			t.Errorf("matches(%q) = %v, want %v", host, got, want)
+		}
+	}
+}
+
+func TestHTTPAuthUnknownSecretFailsRequest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request should not be sent")
+	}))
+	defer srv.Close()
+
+	tool := &HTTPGetTool{Auth: &HTTPAuthenticator{Rules: []HTTPAuth{{
+		Name:    "local",
+		Hosts:   []string{"127.0.0.1"},
+		Headers: map[string]string{"X-Api-Key": "${secrets.missing}"},
+	}}}}
+	out := tool.Call(context.Background(), fmt.Sprintf(`{"url": %q}`, srv.URL))
+	if !strings.Contains(out, "unknown secret: missing") {
+		t.Errorf("expected unknown secret error, got %q", out)
+	}
+}
+
+func TestHTTPPaginateCursor(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("cursor") {
+		case "":
+			fmt.Fprint(w, `{"data": [1, 2], "meta": {"next": "p2"}}`)
+		case "p2":
+			fmt.Fprint(w, `{"data": [3], "meta": {"next": null}}`)
+		}
+	}))
+	defer srv.Close()
+
+	out := (&HTTPPaginateTool{}).Call(context.Background(), fmt.Sprintf(
+		`{"url": %q, "items_path": "data", "cursor_path": "meta.next", "cursor_param": "cursor"}`, srv.URL+"/items?limit=2"))
+	assertPaginated(t, out, 2, "[1,2,3]")
+}
+
+func TestHTTPPaginateLinkHeader(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("page") {
+		case "":
+			w.Header().Set("Link", `</items?page=2>; rel="next", </items?page=3>; rel="last"`)
+			fmt.Fprint(w, `["a"]`)
+		case "2":
+			w.Header().Set("Link", fmt.Sprintf(`<%s/items?page=3>; rel="next"`, srv.URL))
+			fmt.Fprint(w, `["b"]`)
+		case "3":
+			fmt.Fprint(w, `["c"]`)
+		}
+	}))
+	defer srv.Close()
+
+	out := (&HTTPPaginateTool{}).Call(context.Background(), fmt.Sprintf(`{"url": %q}`, srv.URL+"/items"))
+	assertPaginated(t, out, 3, `["a","b","c"]`)
+}
+
+func TestHTTPPaginateStopsAtMaxPages(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"items": [1], "next": "/more"}`)
+	}))
+	defer srv.Close()
+
+	out := (&HTTPPaginateTool{}).Call(context.Background(), fmt.Sprintf(
+		`{"url": %q, "items_path": "items", "next_url_path": "next", "max_pages": 3}`, srv.URL))
+	assertPaginated(t, out, 3, "[1,1,1]")
+	if !strings.Contains(out, "stopped at max_pages") {
+		t.Errorf("expected max_pages note, got %q", out)
+	}
+}
+
+func assertPaginated(t *testing.T, out string, pages int, items string) {
+	t.Helper()
+	head, body, ok := strings.Cut(out, "\n\n")
+	if !ok {
+		t.Fatalf("unexpected output %q", out)
+	}
+	if !strings.HasPrefix(head, fmt.Sprintf("Fetched %d pages", pages)) {
+		t.Errorf("expected %d pages, got %q", pages, head)
+	}
+	var want, got any
+	json.Unmarshal([]byte(items), &want)
+	if err := json.Unmarshal([]byte(body), &got); err != nil {
+		t.Fatalf("body is not JSON: %q", body)
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("items = %v, want %v", got, want)
+	}
+}
@@ -1,9 +1,12 @@
 package aitools
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"strings"
+	"sync"
 )
 
 // LargeResultConfig configures when results are considered "large"
@@ -12,15 +15,30 @@ type LargeResultConfig struct {
 	ItemThreshold int // Min array items to trigger (default: 20)
 	SampleSize    int // Items to show in sample (default: 5)
 	PreviewLength int // Chars to show in text preview (default: 500)
+	// DedupThreshold is the min bytes before a repeated result of an
+	// identical call is replaced by a reference to the stored copy
+	// (default: 1KB, 0 = never)
+	DedupThreshold int
+	// Tools overrides the thresholds for single tools, keyed by the name
+	// the tool is called by
+	Tools map[string]ToolLimits
+}
+
+// ToolLimits overrides when one tool's results count as large. Zero keeps
+// the config's threshold.
+type ToolLimits struct {
+	ByteThreshold int
+	ItemThreshold int
 }
 
 // DefaultLargeResultConfig returns the default configuration
 func DefaultLargeResultConfig() LargeResultConfig {
 	return LargeResultConfig{
-		ByteThreshold: 65536,
-		ItemThreshold: 20,
-		SampleSize:    5,
-		PreviewLength: 2000,
+		ByteThreshold:  65536,
+		ItemThreshold:  20,
+		SampleSize:     5,
+		PreviewLength:  2000,
+		DedupThreshold: 1024,
 	}
 }
 
@@ -30,7 +48,18 @@ func LargeResultConfigWithMaxSize(maxSize int) LargeResultConfig {
 	if maxSize <= 0 {
 		return DefaultLargeResultConfig()
 	}
-	// Preview length: ~3% of max size, clamped between 500 and 8000
+	return LargeResultConfig{
+		ByteThreshold:  maxSize,
+		ItemThreshold:  20,
+		SampleSize:     5,
+		PreviewLength:  previewLengthFor(maxSize),
+		DedupThreshold: 1024,
+	}
+}
+
+// previewLengthFor scales the text preview with the max size: ~3%, clamped
+// between 500 and 8000.
+func previewLengthFor(maxSize int) int {
 	preview := maxSize / 32
 	if preview < 500 {
 		preview = 500
@@ -38,23 +67,37 @@ func LargeResultConfigWithMaxSize(maxSize int) LargeResultConfig {
 	if preview > 8000 {
 		preview = 8000
 	}
-	return LargeResultConfig{
-		ByteThreshold: maxSize,
-		ItemThreshold: 20,
-		SampleSize:    5,
-		PreviewLength: preview,
+	return preview
+}
+
+// ForTool returns the config that applies to the named tool's results
+func (c LargeResultConfig) ForTool(toolName string) LargeResultConfig {
+	limits, ok := c.Tools[toolName]
+	if !ok {
+		return c
 	}
+	if limits.ByteThreshold > 0 {
+		c.ByteThreshold = limits.ByteThreshold
+		c.PreviewLength = previewLengthFor(limits.ByteThreshold)
+	}
+	if limits.ItemThreshold > 0 {
+		c.ItemThreshold = limits.ItemThreshold
+	}
+	return c
 }
 
 // ResultInterceptor processes tool results before sending to LLM
 type ResultInterceptor struct {
 	store  ResultStore
 	config LargeResultConfig
+
+	mu   sync.Mutex
+	seen map[string]bool // Hashes of calls whose results were passed through unstored
 }
 
 // NewResultInterceptor creates a new result interceptor
 func NewResultInterceptor(store ResultStore, config LargeResultConfig) *ResultInterceptor {
-	return &ResultInterceptor{store: store, config: config}
+	return &ResultInterceptor{store: store, config: config, seen: make(map[string]bool)}
 }
 
 // InterceptResult contains the result of interception
@@ -66,6 +109,82 @@ type InterceptResult struct {
 
 // Intercept checks if result is large and stores if so
 func (i *ResultInterceptor) Intercept(toolName, result string) InterceptResult {
+	return i.intercept(toolName, result, "")
+}
+
+// InterceptCall is Intercept for the result of a call with the given input.
+// When an identical call already returned the same result, it returns a
+// reference to the stored copy instead of sending the result again. A
+// result too small to have been stored the first time is stored on its
+// first repeat.
+func (i *ResultInterceptor) InterceptCall(toolName, input, result string) InterceptResult {
+	if i.store == nil || i.config.DedupThreshold <= 0 || len(result) < i.config.DedupThreshold || strings.HasPrefix(toolName, "result_") {
+		return i.Intercept(toolName, result)
+	}
+
+	hash := callHash(toolName, input, result)
+	if r, ok := i.store.FindByHash(hash); ok {
+		return duplicateResult(r)
+	}
+
+	i.mu.Lock()
+	repeated := i.seen[hash]
+	i.mu.Unlock()
+	if repeated {
+		stored := parseStoredResult(result)
+		stored.Hash = hash
+		r, _ := i.store.Get(i.store.Store(toolName, stored))
+		return duplicateResult(r)
+	}
+
+	ir := i.intercept(toolName, result, hash)
+	if ir.ID == "" {
+		i.mu.Lock()
+		i.seen[hash] = true
+		i.mu.Unlock()
+	}
+	return ir
+}
+
+// callHash identifies a tool call by its tool, input, and result.
+func callHash(toolName, input, result string) string {
+	h := sha256.New()
+	for _, s := range []string{toolName, input, result} {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// parseStoredResult classifies a result the way intercept would store it.
+func parseStoredResult(result string) StoredResult {
+	var arr []any
+	if json.Unmarshal([]byte(result), &arr) == nil {
+		return StoredResult{Type: ResultTypeArray, Size: len(arr), RawData: result, Array: arr}
+	}
+	var obj map[string]any
+	if json.Unmarshal([]byte(result), &obj) == nil {
+		return StoredResult{Type: ResultTypeObject, Size: len(result), RawData: result, Object: obj}
+	}
+	return StoredResult{Type: ResultTypeText, Size: len(result), RawData: result}
+}
+
+// duplicateResult refers the LLM to the stored copy of a repeated result.
+func duplicateResult(r *StoredResult) InterceptResult {
+	size := fmt.Sprintf("total_bytes: %d", r.Size)
+	if r.Type == ResultTypeArray {
+		size = fmt.Sprintf("total_items: %d", r.Size)
+	}
+	data := fmt.Sprintf("Same result as an earlier %s call with identical input, so it is not repeated here. Use the result_* tools with id %s to read it.", r.ToolName, r.ID)
+	metadata := fmt.Sprintf(`type: %s
+id: %s
+duplicate: true
+%s`, r.Type, r.ID, size)
+	return InterceptResult{Data: data, Metadata: metadata, ID: r.ID}
+}
+
+// intercept is Intercept, tagging anything it stores with hash.
+func (i *ResultInterceptor) intercept(toolName, result, hash string) InterceptResult {
 	if i.store == nil {
 		return InterceptResult{Data: result}
 	}
@@ -75,14 +194,28 @@ func (i *ResultInterceptor) Intercept(toolName, result string) InterceptResult {
 		return InterceptResult{Data: result}
 	}
 
+	// Tools like http_get put a short header ("Status: 200 OK") in front of
+	// a JSON body. Intercept the body alone and keep the header in front.
+	if head, body, ok := splitJSONBody(result); ok {
+		ir := i.intercept(toolName, body, hash)
+		if ir.ID == "" {
+			return InterceptResult{Data: result}
+		}
+		ir.Data = head + "\n\n" + ir.Data
+		return ir
+	}
+
+	config := i.config.ForTool(toolName)
+
 	// Try JSON array first - check item count regardless of byte size
 	var arr []any
-	if json.Unmarshal([]byte(result), &arr) == nil && len(arr) >= i.config.ItemThreshold {
+	if json.Unmarshal([]byte(result), &arr) == nil && len(arr) >= config.ItemThreshold {
 		stored := StoredResult{
 			Type:    ResultTypeArray,
 			Size:    len(arr),
 			RawData: result,
 			Array:   arr,
+			Hash:    hash,
 		}
 		id := i.store.Store(toolName, stored)
 		data, metadata := i.buildArrayResult(id, arr)
@@ -90,7 +223,7 @@ func (i *ResultInterceptor) Intercept(toolName, result string) InterceptResult {
 	}
 
 	// For non-arrays, apply byte threshold
-	if len(result) < i.config.ByteThreshold {
+	if len(result) < config.ByteThreshold {
 		return InterceptResult{Data: result}
 	}
 
@@ -102,6 +235,7 @@ func (i *ResultInterceptor) Intercept(toolName, result string) InterceptResult {
 			Size:    len(result),
 			RawData: result,
 			Object:  obj,
+			Hash:    hash,
 		}
 		id := i.store.Store(toolName, stored)
 		data, metadata := i.buildObjectResult(id, obj, result)
@@ -113,12 +247,34 @@ func (i *ResultInterceptor) Intercept(toolName, result string) InterceptResult {
 		Type:    ResultTypeText,
 		Size:    len(result),
 		RawData: result,
+		Hash:    hash,
 	}
 	id := i.store.Store(toolName, stored)
-	data, metadata := i.buildTextResult(id, result)
+	data, metadata := i.buildTextResult(id, result, config.PreviewLength)
 	return InterceptResult{Data: data, Metadata: metadata, ID: id}
 }
 
+// maxResultHeaderLen bounds the header splitJSONBody will accept.
+const maxResultHeaderLen = 512
+
+// splitJSONBody splits "header\n\n<json>" into its header and JSON body.
+// ok is false when result is bare JSON or the body isn't JSON.
+func splitJSONBody(result string) (head, body string, ok bool) {
+	idx := strings.Index(result, "\n\n")
+	if idx <= 0 || idx > maxResultHeaderLen {
+		return "", "", false
+	}
+	head = result[:idx]
+	if strings.HasPrefix(head, "[") || strings.HasPrefix(head, "{") {
+		return "", "", false
+	}
+	body = strings.TrimSpace(result[idx+2:])
+	if !strings.HasPrefix(body, "[") && !strings.HasPrefix(body, "{") || !json.Valid([]byte(body)) {
+		return "", "", false
+	}
+	return head, body, true
+}
+
 func (i *ResultInterceptor) buildArrayResult(id string, arr []any) (data, metadata string) {
 	sampleSize := i.config.SampleSize
 	if len(arr) < sampleSize {
@@ -154,8 +310,7 @@ total_keys: %d`, id, len(raw), len(keys))
 	return data, metadata
 }
 
-func (i *ResultInterceptor) buildTextResult(id string, text string) (data, metadata string) {
-	previewLen := i.config.PreviewLength
+func (i *ResultInterceptor) buildTextResult(id string, text string, previewLen int) (data, metadata string) {
 	if len(text) < previewLen {
 		previewLen = len(text)
 	}
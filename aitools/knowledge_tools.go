@@ -0,0 +1,171 @@
+package aitools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// KnowledgeBase is a mission's semantic memory. Commanders and agents write
+// findings with memory_put and retrieve them by meaning with memory_search,
+// instead of asking the commander of an upstream task.
+type KnowledgeBase interface {
+	// Put embeds and stores an entry, returning its ID.
+	Put(ctx context.Context, entry KnowledgeEntry) (string, error)
+	// Search returns up to limit entries most similar to query, best first.
+	// A non-empty task restricts results to entries written by that task.
+	Search(ctx context.Context, query string, limit int, task string) ([]KnowledgeHit, error)
+	// DefaultLimit is the number of results returned when a call omits limit.
+	DefaultLimit() int
+}
+
+// KnowledgeEntry is one piece of knowledge. Task and Author record where it
+// came from; the scoped view returned by ScopeKnowledgeBase fills them in.
+type KnowledgeEntry struct {
+	Content string   `json:"content"`
+	Tags    []string `json:"tags,omitempty"`
+	Task    string   `json:"task,omitempty"`
+	Author  string   `json:"author,omitempty"`
+}
+
+// KnowledgeHit is a search result. Score is the cosine similarity to the
+// query, from -1 to 1.
+type KnowledgeHit struct {
+	ID string `json:"id"`
+	KnowledgeEntry
+	Score float64 `json:"score"`
+}
+
+// ScopeKnowledgeBase returns a view of kb that stamps every entry it stores
+// with the given task and author. Returns nil when kb is nil.
+func ScopeKnowledgeBase(kb KnowledgeBase, task, author string) KnowledgeBase {
+	if kb == nil {
+		return nil
+	}
+	return &scopedKnowledgeBase{KnowledgeBase: kb, task: task, author: author}
+}
+
+type scopedKnowledgeBase struct {
+	KnowledgeBase
+	task   string
+	author string
+}
+
+func (s *scopedKnowledgeBase) Put(ctx context.Context, entry KnowledgeEntry) (string, error) {
+	entry.Task = s.task
+	entry.Author = s.author
+	return s.KnowledgeBase.Put(ctx, entry)
+}
+
+// KnowledgePutTool stores an entry in the mission knowledge base.
+type KnowledgePutTool struct {
+	KB KnowledgeBase
+}
+
+func (t *KnowledgePutTool) ToolName() string { return "memory_put" }
+
+func (t *KnowledgePutTool) ToolDescription() string {
+	return "Save a piece of knowledge (a finding, fact, or decision) to the mission's shared knowledge base so any later task, commander, or agent can find it with memory_search. Write self-contained text: it is retrieved by meaning without the surrounding conversation."
+}
+
+func (t *KnowledgePutTool) ToolPayloadSchema() Schema {
+	return Schema{
+		Type: TypeObject,
+		Properties: PropertyMap{
+			"content": {
+				Type:        TypeString,
+				Description: "The knowledge to save, written so it makes sense on its own.",
+			},
+			"tags": {
+				Type:        TypeArray,
+				Description: "Optional short labels returned with the entry (e.g. [\"pricing\", \"competitor\"]).",
+				Items:       &Property{Type: TypeString},
+			},
+		},
+		Required: []string{"content"},
+	}
+}
+
+type knowledgePutParams struct {
+	Content string   `json:"content"`
+	Tags    []string `json:"tags"`
+}
+
+func (t *KnowledgePutTool) Call(ctx context.Context, params string) string {
+	var p knowledgePutParams
+	if err := json.Unmarshal([]byte(params), &p); err != nil {
+		return "Error: invalid parameters - " + err.Error()
+	}
+	if strings.TrimSpace(p.Content) == "" {
+		return "Error: content is required"
+	}
+	id, err := t.KB.Put(ctx, KnowledgeEntry{Content: p.Content, Tags: p.Tags})
+	if err != nil {
+		return "Error: " + err.Error()
+	}
+	return fmt.Sprintf("Saved to knowledge base (id: %s)", id)
+}
+
+// KnowledgeSearchTool queries the mission knowledge base by meaning.
+type KnowledgeSearchTool struct {
+	KB KnowledgeBase
+}
+
+func (t *KnowledgeSearchTool) ToolName() string { return "memory_search" }
+
+func (t *KnowledgeSearchTool) ToolDescription() string {
+	return "Search the mission's shared knowledge base for entries saved with memory_put, by meaning rather than exact wording. Returns the closest entries with the task and author that saved them. Check here before asking another task's commander."
+}
+
+func (t *KnowledgeSearchTool) ToolPayloadSchema() Schema {
+	return Schema{
+		Type: TypeObject,
+		Properties: PropertyMap{
+			"query": {
+				Type:        TypeString,
+				Description: "What you are looking for, in natural language.",
+			},
+			"limit": {
+				Type:        TypeInteger,
+				Description: fmt.Sprintf("Max results to return. Default %d.", t.KB.DefaultLimit()),
+			},
+			"task": {
+				Type:        TypeString,
+				Description: "Optional task name to only search entries saved by that task.",
+			},
+		},
+		Required: []string{"query"},
+	}
+}
+
+type knowledgeSearchParams struct {
+	Query string `json:"query"`
+	Limit int    `json:"limit"`
+	Task  string `json:"task"`
+}
+
+func (t *KnowledgeSearchTool) Call(ctx context.Context, params string) string {
+	var p knowledgeSearchParams
+	if err := json.Unmarshal([]byte(params), &p); err != nil {
+		return "Error: invalid parameters - " + err.Error()
+	}
+	if strings.TrimSpace(p.Query) == "" {
+		return "Error: query is required"
+	}
+	if p.Limit <= 0 {
+		p.Limit = t.KB.DefaultLimit()
+	}
+	hits, err := t.KB.Search(ctx, p.Query, p.Limit, p.Task)
+	if err != nil {
+		return "Error: " + err.Error()
+	}
+	if len(hits) == 0 {
+		return "No matching knowledge found."
+	}
+	b, err := json.MarshalIndent(hits, "", "  ")
+	if err != nil {
+		return "Error: " + err.Error()
+	}
+	return string(b)
+}
@@ -0,0 +1,60 @@
+package aitools_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"squadron/aitools"
+)
+
+// fakeKnowledgeBase records puts and returns canned hits.
+type fakeKnowledgeBase struct {
+	puts      []aitools.KnowledgeEntry
+	hits      []aitools.KnowledgeHit
+	lastLimit int
+	lastTask  string
+}
+
+func (f *fakeKnowledgeBase) Put(_ context.Context, e aitools.KnowledgeEntry) (string, error) {
+	f.puts = append(f.puts, e)
+	return "k1", nil
+}
+
+func (f *fakeKnowledgeBase) Search(_ context.Context, _ string, limit int, task string) ([]aitools.KnowledgeHit, error) {
+	f.lastLimit, f.lastTask = limit, task
+	return f.hits, nil
+}
+
+func (f *fakeKnowledgeBase) DefaultLimit() int { return 5 }
+
+var _ = Describe("Knowledge tools", func() {
+	ctx := context.Background()
+
+	It("memory_put stamps entries with the scoped task and author", func() {
+		kb := &fakeKnowledgeBase{}
+		tool := &aitools.KnowledgePutTool{KB: aitools.ScopeKnowledgeBase(kb, "research", "scout")}
+
+		Expect(tool.Call(ctx, `{"content":"Acme raised prices","tags":["pricing"]}`)).To(Equal("Saved to knowledge base (id: k1)"))
+		Expect(kb.puts).To(Equal([]aitools.KnowledgeEntry{{Content: "Acme raised prices", Tags: []string{"pricing"}, Task: "research", Author: "scout"}}))
+
+		Expect(tool.Call(ctx, `{"content":"  "}`)).To(Equal("Error: content is required"))
+	})
+
+	It("memory_search applies the default limit and formats hits", func() {
+		kb := &fakeKnowledgeBase{hits: []aitools.KnowledgeHit{{ID: "k1", KnowledgeEntry: aitools.KnowledgeEntry{Content: "Acme raised prices", Task: "research"}, Score: 0.8}}}
+		tool := &aitools.KnowledgeSearchTool{KB: kb}
+
+		out := tool.Call(ctx, `{"query":"acme pricing","task":"research"}`)
+		Expect(kb.lastLimit).To(Equal(5))
+		Expect(kb.lastTask).To(Equal("research"))
+		Expect(out).To(ContainSubstring(`"content": "Acme raised prices"`))
+		Expect(out).To(ContainSubstring(`"score": 0.8`))
+
+		kb.hits = nil
+		Expect(tool.Call(ctx, `{"query":"nothing","limit":2}`)).To(Equal("No matching knowledge found."))
+		Expect(kb.lastLimit).To(Equal(2))
+		Expect(tool.Call(ctx, `{}`)).To(Equal("Error: query is required"))
+	})
+})
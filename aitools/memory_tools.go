@@ -18,6 +18,7 @@ import (
 const (
 	MemorySlotName     = "memory"
 	ScratchpadSlotName = "scratchpad"
+	WorkspaceSlotName  = "workspace"
 )
 
 // PacketSlotPrefix marks a slot as belonging to a read-only packet bundle.
@@ -91,7 +92,7 @@ func resolveSlotPath(store MemoryStore, name, relPath string) (string, error) {
 
 // slotParamDescription is reused across every file tool's `slot` parameter
 // so the agent sees a consistent description.
-const slotParamDescription = "Slot to operate in. Use \"memory\" for the mission's persistent memory, \"scratchpad\" for its ephemeral per-run scratchpad, or a shared memory name."
+const slotParamDescription = "Slot to operate in. Use \"memory\" for the mission's persistent memory, \"scratchpad\" for its ephemeral per-run scratchpad, \"workspace\" for this task's private workspace, or a shared memory name."
 
 // =============================================================================
 // file_list — List files and directories
@@ -731,6 +732,170 @@ func (t *MemorySearchTool) Call(ctx context.Context, params string) string {
 	return sb.String()
 }
 
+// =============================================================================
+// file_glob — Match file paths against a glob pattern
+// =============================================================================
+
+type MemoryGlobTool struct {
+	Store MemoryStore
+}
+
+func (t *MemoryGlobTool) ToolName() string { return "file_glob" }
+
+func (t *MemoryGlobTool) ToolDescription() string {
+	return "Find files in a slot whose relative path matches a glob pattern. '*' matches within one path segment, '**' across segments, '?' one character, and '{a,b}' either alternative. Results are paginated (default 50)."
+}
+
+func (t *MemoryGlobTool) ToolPayloadSchema() Schema {
+	return Schema{
+		Type: TypeObject,
+		Properties: PropertyMap{
+			"slot": {
+				Type:        TypeString,
+				Description: slotParamDescription,
+			},
+			"pattern": {
+				Type:        TypeString,
+				Description: "Glob matched against paths relative to the slot root. Examples: '*.md', 'reports/**/*.json', 'data/{raw,clean}/*.csv'.",
+			},
+			"limit": {
+				Type:        TypeInteger,
+				Description: "Max results to return. Default 50.",
+			},
+			"offset": {
+				Type:        TypeInteger,
+				Description: "Number of results to skip (for pagination). Default 0.",
+			},
+		},
+		Required: []string{"slot", "pattern"},
+	}
+}
+
+type memoryGlobParams struct {
+	Slot    string `json:"slot"`
+	Pattern string `json:"pattern"`
+	Limit   int    `json:"limit"`
+	Offset  int    `json:"offset"`
+}
+
+func (t *MemoryGlobTool) Call(ctx context.Context, params string) string {
+	var p memoryGlobParams
+	if err := json.Unmarshal([]byte(params), &p); err != nil {
+		return "Error: invalid parameters - " + err.Error()
+	}
+
+	if p.Pattern == "" {
+		return "Error: pattern is required"
+	}
+	re, err := globToRegexp(p.Pattern)
+	if err != nil {
+		return "Error: invalid glob pattern - " + err.Error()
+	}
+
+	if p.Limit <= 0 {
+		p.Limit = defaultSearchLimit
+	}
+
+	root, err := t.Store.ResolvePath(p.Slot, ".")
+	if err != nil {
+		return "Error: " + err.Error()
+	}
+
+	var results []string
+	filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if strings.HasPrefix(d.Name(), ".") && path != root {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, _ := filepath.Rel(root, path)
+		if !re.MatchString(filepath.ToSlash(rel)) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		results = append(results, fmt.Sprintf("[file] %s (%s)", rel, formatSize(info.Size())))
+		return nil
+	})
+
+	total := len(results)
+	if total == 0 {
+		return "No files found matching pattern."
+	}
+
+	// Apply pagination
+	start := p.Offset
+	if start >= total {
+		return fmt.Sprintf("(no results at offset %d — total: %d)", start, total)
+	}
+	end := start + p.Limit
+	if end > total {
+		end = total
+	}
+
+	var sb strings.Builder
+	for _, entry := range results[start:end] {
+		sb.WriteString(entry)
+		sb.WriteByte('\n')
+	}
+
+	remaining := total - end
+	fmt.Fprintf(&sb, "\n--- %d-%d of %d files", start+1, end, total)
+	if remaining > 0 {
+		fmt.Fprintf(&sb, " (%d more — use offset: %d to continue)", remaining, end)
+	}
+	fmt.Fprintf(&sb, " ---")
+
+	return sb.String()
+}
+
+// globToRegexp compiles a slash-separated glob into an anchored regexp.
+// "**/" matches zero or more directories, a trailing "**" everything below.
+func globToRegexp(pattern string) (*regexp.Regexp, error) {
+	var sb strings.Builder
+	sb.WriteString("^")
+	inAlt := false
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case c == '*' && strings.HasPrefix(pattern[i:], "**/"):
+			sb.WriteString("(?:.*/)?")
+			i += 2
+		case c == '*' && strings.HasPrefix(pattern[i:], "**"):
+			sb.WriteString(".*")
+			i++
+		case c == '*':
+			sb.WriteString("[^/]*")
+		case c == '?':
+			sb.WriteString("[^/]")
+		case c == '{' && !inAlt:
+			sb.WriteString("(?:")
+			inAlt = true
+		case c == '}' && inAlt:
+			sb.WriteString(")")
+			inAlt = false
+		case c == ',' && inAlt:
+			sb.WriteString("|")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	if inAlt {
+		return nil, fmt.Errorf("unclosed '{' in %q", pattern)
+	}
+	sb.WriteString("$")
+	return regexp.Compile(sb.String())
+}
+
 // =============================================================================
 // file_grep — Search file contents with regex
 // =============================================================================
@@ -78,6 +78,7 @@ var _ = Describe("Memory file tools", func() {
 				&aitools.MemoryCreateTool{Store: store},
 				&aitools.MemoryDeleteTool{Store: store},
 				&aitools.MemorySearchTool{Store: store},
+				&aitools.MemoryGlobTool{Store: store},
 				&aitools.MemoryGrepTool{Store: store},
 			}
 			for _, tl := range tools {
@@ -258,6 +259,46 @@ var _ = Describe("Memory file tools", func() {
 		})
 	})
 
+	Describe("file_glob", func() {
+		write := func(rel string) {
+			full := filepath.Join(store.slots["memory"], rel)
+			Expect(os.MkdirAll(filepath.Dir(full), 0755)).To(Succeed())
+			Expect(os.WriteFile(full, []byte("x"), 0644)).To(Succeed())
+		}
+
+		It("matches relative paths, with ** spanning directories", func() {
+			for _, f := range []string{"top.json", "reports/a.json", "reports/2026/b.json", "reports/c.md"} {
+				write(f)
+			}
+			glob := &aitools.MemoryGlobTool{Store: store}
+
+			out := glob.Call(ctx, `{"slot": "memory", "pattern": "reports/**/*.json"}`)
+			Expect(out).To(ContainSubstring("reports/a.json"))
+			Expect(out).To(ContainSubstring(filepath.Join("reports", "2026", "b.json")))
+			Expect(out).NotTo(ContainSubstring("top.json"))
+			Expect(out).NotTo(ContainSubstring("c.md"))
+
+			out = glob.Call(ctx, `{"slot": "memory", "pattern": "*.json"}`)
+			Expect(out).To(ContainSubstring("top.json"))
+			Expect(out).NotTo(ContainSubstring("a.json"))
+		})
+
+		It("supports {a,b} alternatives", func() {
+			for _, f := range []string{"data/raw/x.csv", "data/clean/y.csv", "data/tmp/z.csv"} {
+				write(f)
+			}
+			out := (&aitools.MemoryGlobTool{Store: store}).Call(ctx, `{"slot": "memory", "pattern": "data/{raw,clean}/*.csv"}`)
+			Expect(out).To(ContainSubstring("x.csv"))
+			Expect(out).To(ContainSubstring("y.csv"))
+			Expect(out).NotTo(ContainSubstring("z.csv"))
+		})
+
+		It("errors on an unclosed alternative", func() {
+			out := (&aitools.MemoryGlobTool{Store: store}).Call(ctx, `{"slot": "memory", "pattern": "{a,b"}`)
+			Expect(out).To(ContainSubstring("invalid glob"))
+		})
+	})
+
 	Describe("file_grep", func() {
 		It("returns matching lines with paths and line numbers (flat)", func() {
 			Expect(os.WriteFile(filepath.Join(store.slots["memory"], "f.txt"),
@@ -2,6 +2,7 @@ package aitools
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -25,6 +26,7 @@ type StoredResult struct {
 	RawData  string         // Original string data
 	Array    []any          // Parsed array (if Type == array)
 	Object   map[string]any // Parsed object (if Type == object)
+	Hash     string         // Content hash of the call that produced it (empty if not indexed)
 }
 
 // ResultStore stores large tool results for later retrieval
@@ -37,6 +39,9 @@ type ResultStore interface {
 
 	// GetInfo returns metadata about all stored results
 	GetInfo() []ResultInfo
+
+	// FindByHash returns the stored result with the given content hash
+	FindByHash(hash string) (*StoredResult, bool)
 }
 
 // ResultInfo provides metadata about a stored result
@@ -50,6 +55,7 @@ type ResultInfo struct {
 type MemoryResultStore struct {
 	mu      sync.RWMutex
 	results map[string]*StoredResult
+	hashes  map[string]string // Content hash -> result ID
 	seqNum  int64
 }
 
@@ -57,6 +63,7 @@ type MemoryResultStore struct {
 func NewMemoryResultStore() *MemoryResultStore {
 	return &MemoryResultStore{
 		results: make(map[string]*StoredResult),
+		hashes:  make(map[string]string),
 	}
 }
 
@@ -70,6 +77,9 @@ func (s *MemoryResultStore) Store(toolName string, result StoredResult) string {
 	result.ID = id
 	result.ToolName = toolName
 	s.results[id] = &result
+	if result.Hash != "" {
+		s.hashes[result.Hash] = id
+	}
 	return id
 }
 
@@ -81,6 +91,18 @@ func (s *MemoryResultStore) Get(id string) (*StoredResult, bool) {
 	return r, ok
 }
 
+// FindByHash retrieves a stored result by its content hash
+func (s *MemoryResultStore) FindByHash(hash string) (*StoredResult, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	id, ok := s.hashes[hash]
+	if !ok {
+		return nil, false
+	}
+	r, ok := s.results[id]
+	return r, ok
+}
+
 // GetInfo returns metadata about all stored results
 func (s *MemoryResultStore) GetInfo() []ResultInfo {
 	s.mu.RLock()
@@ -109,6 +131,40 @@ func (s *MemoryResultStore) GetAll() []*StoredResult {
 	return results
 }
 
+// ResultStoreSnapshot is the serializable state of a MemoryResultStore.
+type ResultStoreSnapshot struct {
+	Results []*StoredResult `json:"results"`
+	SeqNum  int64           `json:"seqNum"`
+}
+
+// Snapshot returns a copy of the store's contents, for checkpointing.
+func (s *MemoryResultStore) Snapshot() ResultStoreSnapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	snap := ResultStoreSnapshot{SeqNum: atomic.LoadInt64(&s.seqNum)}
+	for _, r := range s.results {
+		snap.Results = append(snap.Results, r)
+	}
+	sort.Slice(snap.Results, func(i, j int) bool { return snap.Results[i].ID < snap.Results[j].ID })
+	return snap
+}
+
+// Restore replaces the store's contents with snap. Result IDs already
+// shown to the LLM keep resolving, and new IDs continue the sequence.
+func (s *MemoryResultStore) Restore(snap ResultStoreSnapshot) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.results = make(map[string]*StoredResult, len(snap.Results))
+	s.hashes = make(map[string]string)
+	for _, r := range snap.Results {
+		s.results[r.ID] = r
+		if r.Hash != "" {
+			s.hashes[r.Hash] = r.ID
+		}
+	}
+	atomic.StoreInt64(&s.seqNum, snap.SeqNum)
+}
+
 // sanitizeName replaces characters that shouldn't appear in result IDs
 func sanitizeName(s string) string {
 	return strings.ReplaceAll(strings.ReplaceAll(s, ".", "_"), "-", "_")
@@ -0,0 +1,345 @@
+package aitools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DefaultShellTimeout bounds a run_command call when the shell block sets
+// no timeout.
+const DefaultShellTimeout = 60 * time.Second
+
+// maxShellOutputBytes caps each of stdout and stderr in a run_command
+// observation. Larger output is cut, and the large-result interceptor
+// takes over from there.
+const maxShellOutputBytes = 64 * 1024
+
+// ShellPolicy is the allowlist behind the shell tools. Commands must match
+// an AllowedCommands entry exactly, and every path the tools touch —
+// read_file/write_file paths and run_command's working directory — must
+// resolve inside one of AllowedPaths (absolute, cleaned).
+type ShellPolicy struct {
+	AllowedCommands []string
+	AllowedPaths    []string
+	WorkingDir      string // Default working directory; must be inside AllowedPaths
+	Timeout         time.Duration
+}
+
+// allowsCommand reports whether name is on the command allowlist.
+func (p *ShellPolicy) allowsCommand(name string) bool {
+	for _, c := range p.AllowedCommands {
+		if c == name {
+			return true
+		}
+	}
+	return false
+}
+
+// resolvePath turns a tool-supplied path into an absolute one and checks it
+// against AllowedPaths. Relative paths are taken from the working
+// directory. Symlinks are resolved before the check so a link can't point
+// outside the allowed roots.
+func (p *ShellPolicy) resolvePath(path string) (string, error) {
+	if path == "" {
+		return "", fmt.Errorf("path is required")
+	}
+	if !filepath.IsAbs(path) {
+		if p.WorkingDir == "" {
+			return "", fmt.Errorf("relative path %q given but no working_dir is configured", path)
+		}
+		path = filepath.Join(p.WorkingDir, path)
+	}
+	path = filepath.Clean(path)
+
+	real := evalExistingPrefix(path)
+	for _, root := range p.AllowedPaths {
+		rootReal := evalExistingPrefix(root)
+		if real == rootReal || strings.HasPrefix(real, rootReal+string(filepath.Separator)) {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("path %q is outside the allowed paths", path)
+}
+
+// evalExistingPrefix resolves symlinks in the longest existing prefix of
+// path and re-appends the rest, so paths that don't exist yet (write_file
+// targets) can still be checked.
+func evalExistingPrefix(path string) string {
+	var rest []string
+	for cur := path; ; cur = filepath.Dir(cur) {
+		if real, err := filepath.EvalSymlinks(cur); err == nil {
+			return filepath.Join(append([]string{real}, rest...)...)
+		}
+		if filepath.Dir(cur) == cur {
+			return path
+		}
+		rest = append([]string{filepath.Base(cur)}, rest...)
+	}
+}
+
+func (p *ShellPolicy) timeout() time.Duration {
+	if p.Timeout <= 0 {
+		return DefaultShellTimeout
+	}
+	return p.Timeout
+}
+
+// shellPolicyMissing is returned when a shell tool is built without a
+// policy, i.e. the config has no shell block.
+const shellPolicyMissing = "Error: shell tools are disabled - add a shell { allowed_commands = [...] } block to the config"
+
+// =============================================================================
+// run_command — Run an allowlisted binary
+// =============================================================================
+
+// ShellRunCommandTool runs an allowlisted binary directly (no shell), so
+// pipes, redirects, and globbing are not interpreted.
+type ShellRunCommandTool struct {
+	Policy *ShellPolicy
+}
+
+func (t *ShellRunCommandTool) ToolName() string { return "run_command" }
+
+func (t *ShellRunCommandTool) ToolDescription() string {
+	desc := "Run a command on the local machine and return its exit code, stdout, and stderr. The command is executed directly, not through a shell: pass arguments as a list, and pipes, redirects, and globs are not interpreted."
+	if t.Policy != nil {
+		desc += " Allowed commands: " + strings.Join(t.Policy.AllowedCommands, ", ") + "."
+	}
+	return desc
+}
+
+func (t *ShellRunCommandTool) ToolPayloadSchema() Schema {
+	return Schema{
+		Type: TypeObject,
+		Properties: PropertyMap{
+			"command": {
+				Type:        TypeString,
+				Description: "The command to run. Must be one of the allowed commands.",
+			},
+			"args": {
+				Type:        TypeArray,
+				Description: "Arguments passed to the command, one per element.",
+				Items:       &Property{Type: TypeString},
+			},
+			"cwd": {
+				Type:        TypeString,
+				Description: "Working directory. Must be inside an allowed path. Defaults to the configured working directory.",
+			},
+		},
+		Required: []string{"command"},
+	}
+}
+
+type shellRunParams struct {
+	Command string   `json:"command"`
+	Args    []string `json:"args"`
+	Cwd     string   `json:"cwd"`
+}
+
+func (t *ShellRunCommandTool) Call(ctx context.Context, params string) string {
+	if t.Policy == nil {
+		return shellPolicyMissing
+	}
+	var p shellRunParams
+	if err := json.Unmarshal([]byte(params), &p); err != nil {
+		return "Error: invalid parameters - " + err.Error()
+	}
+	if p.Command == "" {
+		return "Error: command is required"
+	}
+	if !t.Policy.allowsCommand(p.Command) {
+		return fmt.Sprintf("Error: command %q is not allowed. Allowed commands: %s", p.Command, strings.Join(t.Policy.AllowedCommands, ", "))
+	}
+
+	dir := p.Cwd
+	if dir == "" {
+		dir = t.Policy.WorkingDir
+	}
+	if dir != "" {
+		resolved, err := t.Policy.resolvePath(dir)
+		if err != nil {
+			return "Error: " + err.Error()
+		}
+		dir = resolved
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, t.Policy.timeout())
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, p.Command, p.Args...)
+	cmd.Dir = dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	exitCode := 0
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Sprintf("Error: command timed out after %s\n\nstdout:\n%s\nstderr:\n%s",
+				t.Policy.timeout(), truncateShellOutput(stdout.String()), truncateShellOutput(stderr.String()))
+		}
+		exitErr, ok := err.(*exec.ExitError)
+		if !ok {
+			return "Error: " + err.Error()
+		}
+		exitCode = exitErr.ExitCode()
+	}
+
+	return fmt.Sprintf("Exit code: %d\n\nstdout:\n%s\nstderr:\n%s",
+		exitCode, truncateShellOutput(stdout.String()), truncateShellOutput(stderr.String()))
+}
+
+func truncateShellOutput(s string) string {
+	if len(s) <= maxShellOutputBytes {
+		return s
+	}
+	return s[:maxShellOutputBytes] + fmt.Sprintf("\n... [truncated, %d bytes total]", len(s))
+}
+
+// =============================================================================
+// read_file — Read a file under an allowed path
+// =============================================================================
+
+type ShellReadFileTool struct {
+	Policy *ShellPolicy
+}
+
+func (t *ShellReadFileTool) ToolName() string { return "read_file" }
+
+func (t *ShellReadFileTool) ToolDescription() string {
+	desc := "Read a text file from the local machine."
+	if t.Policy != nil {
+		desc += " Only paths inside these directories are allowed: " + strings.Join(t.Policy.AllowedPaths, ", ") + "."
+	}
+	return desc
+}
+
+func (t *ShellReadFileTool) ToolPayloadSchema() Schema {
+	return Schema{
+		Type: TypeObject,
+		Properties: PropertyMap{
+			"path": {
+				Type:        TypeString,
+				Description: "Absolute path, or a path relative to the working directory.",
+			},
+		},
+		Required: []string{"path"},
+	}
+}
+
+type shellReadParams struct {
+	Path string `json:"path"`
+}
+
+func (t *ShellReadFileTool) Call(ctx context.Context, params string) string {
+	if t.Policy == nil {
+		return shellPolicyMissing
+	}
+	var p shellReadParams
+	if err := json.Unmarshal([]byte(params), &p); err != nil {
+		return "Error: invalid parameters - " + err.Error()
+	}
+	path, err := t.Policy.resolvePath(p.Path)
+	if err != nil {
+		return "Error: " + err.Error()
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return "Error: " + err.Error()
+	}
+	if info.IsDir() {
+		return fmt.Sprintf("Error: %s is a directory", p.Path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "Error: " + err.Error()
+	}
+	return string(data)
+}
+
+// =============================================================================
+// write_file — Write a file under an allowed path
+// =============================================================================
+
+type ShellWriteFileTool struct {
+	Policy *ShellPolicy
+}
+
+func (t *ShellWriteFileTool) ToolName() string { return "write_file" }
+
+func (t *ShellWriteFileTool) ToolDescription() string {
+	desc := "Write a text file on the local machine, creating parent directories as needed. Overwrites the file unless append is true."
+	if t.Policy != nil {
+		desc += " Only paths inside these directories are allowed: " + strings.Join(t.Policy.AllowedPaths, ", ") + "."
+	}
+	return desc
+}
+
+func (t *ShellWriteFileTool) ToolPayloadSchema() Schema {
+	return Schema{
+		Type: TypeObject,
+		Properties: PropertyMap{
+			"path": {
+				Type:        TypeString,
+				Description: "Absolute path, or a path relative to the working directory.",
+			},
+			"content": {
+				Type:        TypeString,
+				Description: "Content to write.",
+			},
+			"append": {
+				Type:        TypeBoolean,
+				Description: "Append to the file instead of overwriting it. Default false.",
+			},
+		},
+		Required: []string{"path", "content"},
+	}
+}
+
+type shellWriteParams struct {
+	Path    string `json:"path"`
+	Content string `json:"content"`
+	Append  bool   `json:"append"`
+}
+
+func (t *ShellWriteFileTool) Call(ctx context.Context, params string) string {
+	if t.Policy == nil {
+		return shellPolicyMissing
+	}
+	var p shellWriteParams
+	if err := json.Unmarshal([]byte(params), &p); err != nil {
+		return "Error: invalid parameters - " + err.Error()
+	}
+	path, err := t.Policy.resolvePath(p.Path)
+	if err != nil {
+		return "Error: " + err.Error()
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "Error: " + err.Error()
+	}
+
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if p.Append {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	}
+	f, err := os.OpenFile(path, flags, 0644)
+	if err != nil {
+		return "Error: " + err.Error()
+	}
+	if _, err := f.WriteString(p.Content); err != nil {
+		f.Close()
+		return "Error: " + err.Error()
+	}
+	if err := f.Close(); err != nil {
+		return "Error: " + err.Error()
+	}
+	return fmt.Sprintf("Wrote %d bytes to %s", len(p.Content), path)
+}
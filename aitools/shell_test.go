@@ -0,0 +1,86 @@
+package aitools_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"squadron/aitools"
+)
+
+var _ = Describe("Shell tools", func() {
+	var (
+		ctx    context.Context
+		root   string
+		policy *aitools.ShellPolicy
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		root = GinkgoT().TempDir()
+		policy = &aitools.ShellPolicy{
+			AllowedCommands: []string{"echo", "sh"},
+			AllowedPaths:    []string{root},
+			WorkingDir:      root,
+		}
+	})
+
+	Describe("run_command", func() {
+		It("runs an allowed command in the working directory", func() {
+			out := (&aitools.ShellRunCommandTool{Policy: policy}).Call(ctx, `{"command": "echo", "args": ["hello", "world"]}`)
+			Expect(out).To(ContainSubstring("Exit code: 0"))
+			Expect(out).To(ContainSubstring("hello world"))
+		})
+
+		It("reports a non-zero exit code with stderr", func() {
+			out := (&aitools.ShellRunCommandTool{Policy: policy}).Call(ctx, `{"command": "sh", "args": ["-c", "echo oops >&2; exit 3"]}`)
+			Expect(out).To(ContainSubstring("Exit code: 3"))
+			Expect(out).To(ContainSubstring("oops"))
+		})
+
+		It("refuses commands not on the allowlist", func() {
+			out := (&aitools.ShellRunCommandTool{Policy: policy}).Call(ctx, `{"command": "rm", "args": ["-rf", "x"]}`)
+			Expect(out).To(ContainSubstring(`command "rm" is not allowed`))
+		})
+
+		It("refuses a cwd outside the allowed paths", func() {
+			out := (&aitools.ShellRunCommandTool{Policy: policy}).Call(ctx, `{"command": "echo", "cwd": "/"}`)
+			Expect(out).To(ContainSubstring("outside the allowed paths"))
+		})
+
+		It("refuses every call without a policy", func() {
+			out := (&aitools.ShellRunCommandTool{}).Call(ctx, `{"command": "echo"}`)
+			Expect(out).To(ContainSubstring("shell tools are disabled"))
+		})
+	})
+
+	Describe("read_file and write_file", func() {
+		It("round-trips a file under the working directory", func() {
+			w := &aitools.ShellWriteFileTool{Policy: policy}
+			Expect(w.Call(ctx, `{"path": "out/notes.txt", "content": "one"}`)).To(ContainSubstring("Wrote 3 bytes"))
+			Expect(w.Call(ctx, `{"path": "out/notes.txt", "content": "two", "append": true}`)).To(ContainSubstring("Wrote"))
+
+			out := (&aitools.ShellReadFileTool{Policy: policy}).Call(ctx, `{"path": "out/notes.txt"}`)
+			Expect(out).To(Equal("onetwo"))
+		})
+
+		It("rejects paths that escape the allowed roots", func() {
+			out := (&aitools.ShellReadFileTool{Policy: policy}).Call(ctx, `{"path": "../outside.txt"}`)
+			Expect(out).To(ContainSubstring("outside the allowed paths"))
+			out = (&aitools.ShellWriteFileTool{Policy: policy}).Call(ctx, `{"path": "/etc/squadron.txt", "content": "x"}`)
+			Expect(out).To(ContainSubstring("outside the allowed paths"))
+		})
+
+		It("rejects symlinks pointing outside the allowed roots", func() {
+			outside := GinkgoT().TempDir()
+			Expect(os.WriteFile(filepath.Join(outside, "secret.txt"), []byte("s"), 0644)).To(Succeed())
+			Expect(os.Symlink(outside, filepath.Join(root, "link"))).To(Succeed())
+
+			out := (&aitools.ShellReadFileTool{Policy: policy}).Call(ctx, `{"path": "link/secret.txt"}`)
+			Expect(out).To(ContainSubstring("outside the allowed paths"))
+		})
+	})
+})
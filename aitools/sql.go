@@ -0,0 +1,490 @@
+package aitools
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	_ "modernc.org/sqlite"
+)
+
+// DefaultSQLMaxRows caps a sql_query result when the database block sets
+// no max_rows.
+const DefaultSQLMaxRows = 1000
+
+// SQLDatabase is one database the sql tools can reach. Driver is
+// "postgres", "mysql" or "sqlite". When ReadOnly is set, every statement
+// runs in a read-only transaction that is always rolled back; SQLite
+// connections are additionally opened with query_only.
+type SQLDatabase struct {
+	Name        string
+	Driver      string
+	DSN         string
+	Description string
+	ReadOnly    bool
+	MaxRows     int
+}
+
+// SQLDatabases is the set of databases behind the sql tools. nil means no
+// database blocks are configured and the tools refuse every call.
+type SQLDatabases []SQLDatabase
+
+const sqlDatabasesMissing = "Error: sql tools are disabled — no database blocks are configured"
+
+// sqlDriverNames maps config driver names to database/sql driver names.
+var sqlDriverNames = map[string]string{
+	"postgres": "pgx",
+	"mysql":    "mysql",
+	"sqlite":   "sqlite",
+}
+
+// sqlPools shares one *sql.DB per driver and DSN across every agent and
+// task in the process; database/sql pools connections underneath.
+var (
+	sqlPoolsMu sync.Mutex
+	sqlPools   = make(map[string]*sql.DB)
+)
+
+// open returns the shared connection pool for the database.
+func (d *SQLDatabase) open() (*sql.DB, error) {
+	driver, ok := sqlDriverNames[d.Driver]
+	if !ok {
+		return nil, fmt.Errorf("unsupported driver %q", d.Driver)
+	}
+	dsn := d.DSN
+	if d.ReadOnly && d.Driver == "sqlite" {
+		sep := "?"
+		if strings.Contains(dsn, "?") {
+			sep = "&"
+		}
+		dsn += sep + "_pragma=query_only(1)"
+	}
+
+	key := driver + "\x00" + dsn
+	sqlPoolsMu.Lock()
+	defer sqlPoolsMu.Unlock()
+	if db, ok := sqlPools[key]; ok {
+		return db, nil
+	}
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, err
+	}
+	sqlPools[key] = db
+	return db, nil
+}
+
+// maxRows is the row cap for the database.
+func (d *SQLDatabase) maxRows() int {
+	if d.MaxRows > 0 {
+		return d.MaxRows
+	}
+	return DefaultSQLMaxRows
+}
+
+// query runs stmt and returns the column names and up to limit+1 rows, so
+// callers can tell a full page from a truncated one. Read-only databases
+// run it in a read-only transaction that is rolled back afterwards.
+func (d *SQLDatabase) query(ctx context.Context, limit int, stmt string, args ...any) ([]string, [][]any, error) {
+	db, err := d.open()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var rows *sql.Rows
+	if d.ReadOnly {
+		tx, err := db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+		if err != nil {
+			return nil, nil, err
+		}
+		defer tx.Rollback()
+		rows, err = tx.QueryContext(ctx, stmt, args...)
+		if err != nil {
+			return nil, nil, err
+		}
+	} else {
+		rows, err = db.QueryContext(ctx, stmt, args...)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	defer rows.Close()
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, nil, err
+	}
+	var out [][]any
+	for rows.Next() && len(out) <= limit {
+		vals := make([]any, len(cols))
+		ptrs := make([]any, len(cols))
+		for i := range vals {
+			ptrs[i] = &vals[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return nil, nil, err
+		}
+		for i, v := range vals {
+			vals[i] = sqlJSONValue(v)
+		}
+		out = append(out, vals)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+	return cols, out, nil
+}
+
+// sqlJSONValue converts a scanned column value into something that
+// marshals readably: byte slices become strings and times RFC 3339.
+func sqlJSONValue(v any) any {
+	switch x := v.(type) {
+	case []byte:
+		return string(x)
+	case time.Time:
+		return x.Format(time.RFC3339Nano)
+	default:
+		return x
+	}
+}
+
+// sqlRowObjects zips column names onto each row.
+func sqlRowObjects(cols []string, rows [][]any) []map[string]any {
+	objs := make([]map[string]any, len(rows))
+	for i, row := range rows {
+		obj := make(map[string]any, len(cols))
+		for j, c := range cols {
+			obj[c] = row[j]
+		}
+		objs[i] = obj
+	}
+	return objs
+}
+
+// lookup resolves the database parameter. It may be omitted when only one
+// database is configured.
+func (dbs SQLDatabases) lookup(name string) (*SQLDatabase, error) {
+	if name == "" && len(dbs) == 1 {
+		return &dbs[0], nil
+	}
+	for i := range dbs {
+		if dbs[i].Name == name {
+			return &dbs[i], nil
+		}
+	}
+	if name == "" {
+		return nil, fmt.Errorf("database is required. Available databases: %s", dbs.names())
+	}
+	return nil, fmt.Errorf("unknown database %q. Available databases: %s", name, dbs.names())
+}
+
+func (dbs SQLDatabases) names() string {
+	names := make([]string, len(dbs))
+	for i, d := range dbs {
+		names[i] = d.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+// describe lists the databases for tool descriptions. DSNs are never
+// included.
+func (dbs SQLDatabases) describe() string {
+	if len(dbs) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(" Available databases:")
+	for _, d := range dbs {
+		mode := "read-only"
+		if !d.ReadOnly {
+			mode = "read-write"
+		}
+		fmt.Fprintf(&b, "\n- %s (%s, %s)", d.Name, d.Driver, mode)
+		if d.Description != "" {
+			b.WriteString(": " + d.Description)
+		}
+	}
+	return b.String()
+}
+
+var sqlDatabaseProperty = Property{
+	Type:        TypeString,
+	Description: "Name of the database to use. May be omitted when only one database is configured.",
+}
+
+// SQLQueryTool runs a SQL statement and returns the rows as a JSON array,
+// capped at the database's max_rows. Large results are picked up by the
+// result interceptor like any other array.
+type SQLQueryTool struct {
+	Databases SQLDatabases
+}
+
+func (t *SQLQueryTool) ToolName() string {
+	return "sql_query"
+}
+
+func (t *SQLQueryTool) ToolDescription() string {
+	return "Runs a SQL query against a configured database and returns the rows as a JSON array of objects. Read-only databases reject writes. Use sql_schema first to discover tables and columns." + t.Databases.describe()
+}
+
+func (t *SQLQueryTool) ToolPayloadSchema() Schema {
+	return Schema{
+		Type: TypeObject,
+		Properties: PropertyMap{
+			"database": sqlDatabaseProperty,
+			"query": {
+				Type:        TypeString,
+				Description: "The SQL statement to run. Use placeholders ($1 for postgres, ? for mysql and sqlite) for values passed in args.",
+			},
+			"args": {
+				Type:        TypeArray,
+				Description: "Optional positional values for the query's placeholders.",
+			},
+			"limit": {
+				Type:        TypeInteger,
+				Description: "Maximum rows to return. Defaults to, and may not exceed, the database's max_rows.",
+			},
+		},
+		Required: []string{"query"},
+	}
+}
+
+type sqlQueryParams struct {
+	Database string `json:"database"`
+	Query    string `json:"query"`
+	Args     []any  `json:"args"`
+	Limit    int    `json:"limit"`
+}
+
+func (t *SQLQueryTool) Call(ctx context.Context, params string) string {
+	if len(t.Databases) == 0 {
+		return sqlDatabasesMissing
+	}
+	var p sqlQueryParams
+	if err := json.Unmarshal([]byte(params), &p); err != nil {
+		return "Error: invalid parameters - " + err.Error()
+	}
+	if strings.TrimSpace(p.Query) == "" {
+		return "Error: query is required"
+	}
+	db, err := t.Databases.lookup(p.Database)
+	if err != nil {
+		return "Error: " + err.Error()
+	}
+	limit := db.maxRows()
+	if p.Limit > 0 && p.Limit < limit {
+		limit = p.Limit
+	}
+
+	cols, rows, err := db.query(ctx, limit, p.Query, p.Args...)
+	if err != nil {
+		return "Error: " + err.Error()
+	}
+	if len(cols) == 0 {
+		return "Statement executed (no rows returned)"
+	}
+	head := fmt.Sprintf("Rows: %d", len(rows))
+	if len(rows) > limit {
+		rows = rows[:limit]
+		head = fmt.Sprintf("Rows: %d (truncated at limit %d; narrow the query or aggregate to see the rest)", limit, limit)
+	}
+	out, err := json.Marshal(sqlRowObjects(cols, rows))
+	if err != nil {
+		return "Error: failed to encode rows - " + err.Error()
+	}
+	return head + "\n\n" + string(out)
+}
+
+// SQLSchemaTool lists a database's tables, or one table's columns.
+type SQLSchemaTool struct {
+	Databases SQLDatabases
+}
+
+func (t *SQLSchemaTool) ToolName() string {
+	return "sql_schema"
+}
+
+func (t *SQLSchemaTool) ToolDescription() string {
+	return "Describes a configured database. Without table, lists its tables and views; with table, lists that table's columns, types and nullability." + t.Databases.describe()
+}
+
+func (t *SQLSchemaTool) ToolPayloadSchema() Schema {
+	return Schema{
+		Type: TypeObject,
+		Properties: PropertyMap{
+			"database": sqlDatabaseProperty,
+			"table": {
+				Type:        TypeString,
+				Description: "Table to describe. For postgres, may be qualified as schema.table.",
+			},
+		},
+	}
+}
+
+type sqlSchemaParams struct {
+	Database string `json:"database"`
+	Table    string `json:"table"`
+}
+
+func (t *SQLSchemaTool) Call(ctx context.Context, params string) string {
+	if len(t.Databases) == 0 {
+		return sqlDatabasesMissing
+	}
+	var p sqlSchemaParams
+	if err := json.Unmarshal([]byte(params), &p); err != nil {
+		return "Error: invalid parameters - " + err.Error()
+	}
+	db, err := t.Databases.lookup(p.Database)
+	if err != nil {
+		return "Error: " + err.Error()
+	}
+
+	stmt, args := sqlSchemaQuery(db.Driver, p.Table)
+	cols, rows, err := db.query(ctx, maxSQLSchemaRows, stmt, args...)
+	if err != nil {
+		return "Error: " + err.Error()
+	}
+	if p.Table != "" && len(rows) == 0 {
+		return fmt.Sprintf("Error: table %q not found", p.Table)
+	}
+	if len(rows) > maxSQLSchemaRows {
+		rows = rows[:maxSQLSchemaRows]
+	}
+	out, err := json.Marshal(sqlRowObjects(cols, rows))
+	if err != nil {
+		return "Error: failed to encode schema - " + err.Error()
+	}
+	return string(out)
+}
+
+// maxSQLSchemaRows bounds a sql_schema listing.
+const maxSQLSchemaRows = 5000
+
+// sqlSchemaQuery builds the catalog query for the driver. Tables come back
+// as {schema, name, type}; columns as {name, type, nullable}.
+func sqlSchemaQuery(driver, table string) (string, []any) {
+	switch driver {
+	case "postgres":
+		if table == "" {
+			return `SELECT table_schema AS schema, table_name AS name, table_type AS type
+FROM information_schema.tables
+WHERE table_schema NOT IN ('pg_catalog', 'information_schema')
+ORDER BY table_schema, table_name`, nil
+		}
+		schema, name, ok := strings.Cut(table, ".")
+		if !ok {
+			return `SELECT column_name AS name, data_type AS type, is_nullable AS nullable
+FROM information_schema.columns
+WHERE table_name = $1 AND table_schema = ANY(current_schemas(false))
+ORDER BY ordinal_position`, []any{table}
+		}
+		return `SELECT column_name AS name, data_type AS type, is_nullable AS nullable
+FROM information_schema.columns
+WHERE table_schema = $1 AND table_name = $2
+ORDER BY ordinal_position`, []any{schema, name}
+	case "mysql":
+		if table == "" {
+			return `SELECT table_schema AS ` + "`schema`" + `, table_name AS name, table_type AS type
+FROM information_schema.tables
+WHERE table_schema = DATABASE()
+ORDER BY table_name`, nil
+		}
+		return `SELECT column_name AS name, column_type AS type, is_nullable AS nullable
+FROM information_schema.columns
+WHERE table_schema = DATABASE() AND table_name = ?
+ORDER BY ordinal_position`, []any{table}
+	default: // sqlite
+		if table == "" {
+			return `SELECT 'main' AS schema, name, type
+FROM sqlite_master
+WHERE type IN ('table', 'view') AND name NOT LIKE 'sqlite_%'
+ORDER BY name`, nil
+		}
+		return `SELECT name, type, CASE WHEN "notnull" = 1 THEN 'NO' ELSE 'YES' END AS nullable
+FROM pragma_table_info(?)
+ORDER BY cid`, []any{table}
+	}
+}
+
+// SQLExplainTool shows the database's query plan for a statement without
+// running it.
+type SQLExplainTool struct {
+	Databases SQLDatabases
+}
+
+func (t *SQLExplainTool) ToolName() string {
+	return "sql_explain"
+}
+
+func (t *SQLExplainTool) ToolDescription() string {
+	return "Shows the query plan for a SQL statement without running it. Useful for checking that a query on a large table uses an index before calling sql_query." + t.Databases.describe()
+}
+
+func (t *SQLExplainTool) ToolPayloadSchema() Schema {
+	return Schema{
+		Type: TypeObject,
+		Properties: PropertyMap{
+			"database": sqlDatabaseProperty,
+			"query": {
+				Type:        TypeString,
+				Description: "The SQL statement to explain.",
+			},
+		},
+		Required: []string{"query"},
+	}
+}
+
+type sqlExplainParams struct {
+	Database string `json:"database"`
+	Query    string `json:"query"`
+}
+
+func (t *SQLExplainTool) Call(ctx context.Context, params string) string {
+	if len(t.Databases) == 0 {
+		return sqlDatabasesMissing
+	}
+	var p sqlExplainParams
+	if err := json.Unmarshal([]byte(params), &p); err != nil {
+		return "Error: invalid parameters - " + err.Error()
+	}
+	if strings.TrimSpace(p.Query) == "" {
+		return "Error: query is required"
+	}
+	db, err := t.Databases.lookup(p.Database)
+	if err != nil {
+		return "Error: " + err.Error()
+	}
+
+	prefix := "EXPLAIN "
+	if db.Driver == "sqlite" {
+		prefix = "EXPLAIN QUERY PLAN "
+	}
+	// Always explain inside a read-only transaction, whatever the database
+	// allows, so "EXPLAIN ANALYZE DELETE ..." can't sneak a write through.
+	ro := *db
+	ro.ReadOnly = true
+	cols, rows, err := ro.query(ctx, maxSQLSchemaRows, prefix+p.Query)
+	if err != nil {
+		return "Error: " + err.Error()
+	}
+
+	// Postgres returns the plan as one text column, a line per row.
+	if len(cols) == 1 {
+		lines := make([]string, len(rows))
+		for i, r := range rows {
+			lines[i] = fmt.Sprint(r[0])
+		}
+		return strings.Join(lines, "\n")
+	}
+	out, err := json.Marshal(sqlRowObjects(cols, rows))
+	if err != nil {
+		return "Error: failed to encode plan - " + err.Error()
+	}
+	return string(out)
+}
@@ -0,0 +1,111 @@
+package aitools_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"squadron/aitools"
+)
+
+var _ = Describe("SQL tools", func() {
+	var (
+		ctx context.Context
+		dsn string
+		dbs aitools.SQLDatabases
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		dsn = filepath.Join(GinkgoT().TempDir(), "shop.db")
+		db, err := sql.Open("sqlite", dsn)
+		Expect(err).NotTo(HaveOccurred())
+		defer db.Close()
+		_, err = db.Exec(`CREATE TABLE orders (id INTEGER PRIMARY KEY, customer TEXT NOT NULL, total REAL)`)
+		Expect(err).NotTo(HaveOccurred())
+		for i := 1; i <= 30; i++ {
+			_, err = db.Exec(`INSERT INTO orders (customer, total) VALUES (?, ?)`, fmt.Sprintf("c%d", i), float64(i)*1.5)
+			Expect(err).NotTo(HaveOccurred())
+		}
+		dbs = aitools.SQLDatabases{{Name: "shop", Driver: "sqlite", DSN: dsn, ReadOnly: true, MaxRows: 25}}
+	})
+
+	Describe("sql_query", func() {
+		It("returns rows as a JSON array after a row count header", func() {
+			out := (&aitools.SQLQueryTool{Databases: dbs}).Call(ctx, `{"query": "SELECT id, customer FROM orders WHERE id <= ? ORDER BY id", "args": [2]}`)
+			Expect(out).To(Equal("Rows: 2\n\n" + `[{"customer":"c1","id":1},{"customer":"c2","id":2}]`))
+		})
+
+		It("caps rows at max_rows and says so", func() {
+			out := (&aitools.SQLQueryTool{Databases: dbs}).Call(ctx, `{"database": "shop", "query": "SELECT id FROM orders"}`)
+			Expect(out).To(HavePrefix("Rows: 25 (truncated at limit 25"))
+		})
+
+		It("honors a smaller limit", func() {
+			out := (&aitools.SQLQueryTool{Databases: dbs}).Call(ctx, `{"query": "SELECT id FROM orders ORDER BY id", "limit": 1}`)
+			Expect(out).To(HavePrefix("Rows: 1 (truncated"))
+			Expect(out).To(HaveSuffix(`[{"id":1}]`))
+		})
+
+		It("rejects writes on a read-only database", func() {
+			out := (&aitools.SQLQueryTool{Databases: dbs}).Call(ctx, `{"query": "DELETE FROM orders"}`)
+			Expect(out).To(HavePrefix("Error:"))
+			count := (&aitools.SQLQueryTool{Databases: dbs}).Call(ctx, `{"query": "SELECT COUNT(*) AS n FROM orders"}`)
+			Expect(count).To(HaveSuffix(`[{"n":30}]`))
+		})
+
+		It("allows writes when the database is read-write", func() {
+			dbs[0].ReadOnly = false
+			out := (&aitools.SQLQueryTool{Databases: dbs}).Call(ctx, `{"query": "DELETE FROM orders WHERE id = 1"}`)
+			Expect(out).To(Equal("Statement executed (no rows returned)"))
+		})
+
+		It("feeds large results to the interceptor with the header kept", func() {
+			out := (&aitools.SQLQueryTool{Databases: dbs}).Call(ctx, `{"query": "SELECT id FROM orders"}`)
+			ir := aitools.NewResultInterceptor(aitools.NewMemoryResultStore(), aitools.DefaultLargeResultConfig()).Intercept("sql_query", out)
+			Expect(ir.ID).NotTo(BeEmpty())
+			Expect(ir.Data).To(HavePrefix("Rows: 25"))
+			Expect(ir.Metadata).To(ContainSubstring("total_items: 25"))
+		})
+
+		It("names the databases when the one asked for doesn't exist", func() {
+			out := (&aitools.SQLQueryTool{Databases: dbs}).Call(ctx, `{"database": "nope", "query": "SELECT 1"}`)
+			Expect(out).To(ContainSubstring(`unknown database "nope". Available databases: shop`))
+		})
+
+		It("refuses every call without databases", func() {
+			out := (&aitools.SQLQueryTool{}).Call(ctx, `{"query": "SELECT 1"}`)
+			Expect(out).To(ContainSubstring("no database blocks are configured"))
+		})
+	})
+
+	Describe("sql_schema", func() {
+		It("lists tables", func() {
+			out := (&aitools.SQLSchemaTool{Databases: dbs}).Call(ctx, `{}`)
+			Expect(out).To(Equal(`[{"name":"orders","schema":"main","type":"table"}]`))
+		})
+
+		It("describes a table's columns", func() {
+			out := (&aitools.SQLSchemaTool{Databases: dbs}).Call(ctx, `{"table": "orders"}`)
+			Expect(out).To(ContainSubstring(`{"name":"customer","nullable":"NO","type":"TEXT"}`))
+			Expect(out).To(ContainSubstring(`{"name":"total","nullable":"YES","type":"REAL"}`))
+		})
+
+		It("reports an unknown table", func() {
+			out := (&aitools.SQLSchemaTool{Databases: dbs}).Call(ctx, `{"table": "missing"}`)
+			Expect(out).To(Equal(`Error: table "missing" not found`))
+		})
+	})
+
+	Describe("sql_explain", func() {
+		It("shows the query plan", func() {
+			out := (&aitools.SQLExplainTool{Databases: dbs}).Call(ctx, `{"query": "SELECT * FROM orders WHERE id = 3"}`)
+			Expect(out).To(ContainSubstring("orders"))
+			Expect(out).NotTo(HavePrefix("Error:"))
+		})
+	})
+})
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"sync"
 )
 
@@ -29,6 +30,9 @@ type SubmitOutputTool struct {
 	OnSubmit SubmitOutputCallback
 	results  []SubmitResult
 	mu       sync.Mutex
+
+	// Artifacts, when set, resolves the references in `file` fields
+	Artifacts Artifacts
 }
 
 // NewSubmitOutputTool creates a new submit_output tool with optional schema validation
@@ -92,6 +96,9 @@ func (t *SubmitOutputTool) Call(ctx context.Context, params string) string {
 		if len(missing) > 0 {
 			return fmt.Sprintf(`{"status": "error", "message": "missing required output fields: %v"}`, missing)
 		}
+		if msg := t.checkFileFields(ctx, input.Output); msg != "" {
+			return fmt.Sprintf(`{"status": "error", "message": %q}`, msg)
+		}
 	}
 
 	t.mu.Lock()
@@ -109,6 +116,31 @@ func (t *SubmitOutputTool) Call(ctx context.Context, params string) string {
 	return fmt.Sprintf(`{"status": "ok", "index": %d}`, index)
 }
 
+// checkFileFields returns a message for the first `file` field that doesn't
+// hold a known artifact reference, or "".
+func (t *SubmitOutputTool) checkFileFields(ctx context.Context, output map[string]any) string {
+	for _, field := range t.schema {
+		if field.Type != "file" {
+			continue
+		}
+		val, exists := output[field.Name]
+		if !exists || val == nil {
+			continue
+		}
+		ref, ok := val.(string)
+		if !ok || !strings.HasPrefix(ref, ArtifactRefPrefix) {
+			return fmt.Sprintf("field %s must be an artifact reference (\"%s...\") from artifact_put", field.Name, ArtifactRefPrefix)
+		}
+		if t.Artifacts == nil {
+			continue
+		}
+		if _, err := t.Artifacts.Stat(ctx, ref); err != nil {
+			return fmt.Sprintf("field %s: %v", field.Name, err)
+		}
+	}
+	return ""
+}
+
 // ResultCount returns the number of outputs submitted so far
 func (t *SubmitOutputTool) ResultCount() int {
 	t.mu.Lock()
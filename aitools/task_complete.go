@@ -36,8 +36,22 @@ type TaskCompleteTool struct {
 	chosenRoute   string
 	isMissionRoute bool
 	missionInputs map[string]string
+
+	// Verify turns the first successful call into a self-check: the
+	// commander is asked to review its work, and the call that follows must
+	// carry a confidence score.
+	Verify          bool
+	verifyRequested bool
+	confidence      *float64
 }
 
+// verifyInstructions is returned in place of completing a task with Verify
+// set, the first time the commander calls task_complete.
+const verifyInstructions = `Before this task completes, check your work:
+1. Does the result satisfy every part of the objective? List anything missing.
+2. Is your summary (and your submitted output, if any) consistent with what the agents actually returned? Flag any claim you cannot trace to an agent or tool result.
+If you find problems, fix them now: call agents again, or call submit_output with corrected output. Then call task_complete again with your summary and a confidence between 0 and 1 that the result is correct and complete, or with succeed=false and a reason if the task cannot be done.`
+
 func (t *TaskCompleteTool) ToolName() string {
 	return "task_complete"
 }
@@ -64,6 +78,12 @@ func (t *TaskCompleteTool) ToolPayloadSchema() Schema {
 			Description: "Explanation of why the task failed. Required when succeed=false.",
 		},
 	}
+	if t.Verify {
+		props["confidence"] = Property{
+			Type:        TypeNumber,
+			Description: "Your confidence, between 0 and 1, that the result is correct and complete. Required once you have checked your work.",
+		}
+	}
 	if len(t.Routes) > 0 {
 		props["route"] = Property{
 			Type:        TypeString,
@@ -94,6 +114,7 @@ func (t *TaskCompleteTool) Call(ctx context.Context, params string) string {
 	reason := ""
 	route := ""
 	var missionInputs map[string]string
+	var confidence *float64
 
 	if params != "" && params != "{}" {
 		var input struct {
@@ -102,6 +123,7 @@ func (t *TaskCompleteTool) Call(ctx context.Context, params string) string {
 			Reason        string            `json:"reason"`
 			Route         string            `json:"route"`
 			MissionInputs map[string]string `json:"mission_inputs"`
+			Confidence    *float64          `json:"confidence"`
 		}
 		if err := json.Unmarshal([]byte(params), &input); err == nil {
 			if input.Succeed != nil {
@@ -111,6 +133,7 @@ func (t *TaskCompleteTool) Call(ctx context.Context, params string) string {
 			reason = input.Reason
 			route = input.Route
 			missionInputs = input.MissionInputs
+			confidence = input.Confidence
 		}
 	}
 
@@ -127,6 +150,17 @@ func (t *TaskCompleteTool) Call(ctx context.Context, params string) string {
 				return fmt.Sprintf(`{"status": "error", "error": "Cannot complete task: %d of %d subtasks are not yet complete. Call complete_subtask for each remaining subtask before calling task_complete."}`, incomplete, total)
 			}
 		}
+		if t.Verify {
+			if !t.verifyRequested {
+				t.verifyRequested = true
+				resp, _ := json.Marshal(map[string]string{"status": "verify", "message": verifyInstructions})
+				return string(resp)
+			}
+			if confidence == nil || *confidence < 0 || *confidence > 1 {
+				return `{"status": "error", "error": "Include a confidence between 0 and 1 that the result is correct and complete."}`
+			}
+			t.confidence = confidence
+		}
 	}
 
 	// Handle failure
@@ -183,6 +217,15 @@ func (t *TaskCompleteTool) IsCompleted() bool             { return t.completed }
 func (t *TaskCompleteTool) IsSucceeded() bool             { return t.succeeded }
 func (t *TaskCompleteTool) FailureReason() string         { return t.failureReason }
 
+// Confidence returns the confidence score the commander gave after checking
+// its work, and false when it gave none (Verify is off, or the task failed).
+func (t *TaskCompleteTool) Confidence() (float64, bool) {
+	if t.confidence == nil || !t.succeeded {
+		return 0, false
+	}
+	return *t.confidence, true
+}
+
 // ApplyStateFromSuccessfulInput rebuilds in-memory completion state from a
 // task_complete tool_use input JSON that we already know succeeded (the
 // caller has already inspected the matching tool_result). Used on resume so
@@ -200,6 +243,7 @@ func (t *TaskCompleteTool) ApplyStateFromSuccessfulInput(params string) {
 		Reason        string            `json:"reason"`
 		Route         string            `json:"route"`
 		MissionInputs map[string]string `json:"mission_inputs"`
+		Confidence    *float64          `json:"confidence"`
 	}
 	if err := json.Unmarshal([]byte(params), &input); err != nil {
 		return
@@ -211,6 +255,7 @@ func (t *TaskCompleteTool) ApplyStateFromSuccessfulInput(params string) {
 	t.summary = input.Summary
 	t.completed = true
 	t.succeeded = succeed
+	t.confidence = input.Confidence
 	if !succeed {
 		t.failureReason = input.Reason
 		return
@@ -232,3 +277,17 @@ func (t *TaskCompleteTool) Summary() string               { return t.summary }
 func (t *TaskCompleteTool) ChosenRoute() string           { return t.chosenRoute }
 func (t *TaskCompleteTool) IsMissionRoute() bool          { return t.isMissionRoute }
 func (t *TaskCompleteTool) MissionInputs() map[string]string { return t.missionInputs }
+
+// Reset clears the completion state so the tool can end another turn. Used
+// by interactive chats, where each reply ends with task_complete.
+func (t *TaskCompleteTool) Reset() {
+	t.completed = false
+	t.succeeded = false
+	t.failureReason = ""
+	t.summary = ""
+	t.chosenRoute = ""
+	t.isMissionRoute = false
+	t.missionInputs = nil
+	t.verifyRequested = false
+	t.confidence = nil
+}
@@ -155,3 +155,54 @@ func TestTaskComplete_EmptyParams(t *testing.T) {
 		t.Fatal("expected IsSucceeded() to be true for empty params")
 	}
 }
+
+func TestTaskComplete_Reset(t *testing.T) {
+	tc := &TaskCompleteTool{}
+	tc.Call(context.Background(), `{"succeed": false, "reason": "no access"}`)
+	tc.Reset()
+
+	if tc.IsCompleted() || tc.FailureReason() != "" {
+		t.Fatal("expected Reset to clear the completion state")
+	}
+	tc.Call(context.Background(), `{"summary": "second reply"}`)
+	if !tc.IsSucceeded() || tc.Summary() != "second reply" {
+		t.Fatalf("expected a fresh success after Reset, got succeeded=%v summary=%q", tc.IsSucceeded(), tc.Summary())
+	}
+}
+
+func TestTaskComplete_Verify(t *testing.T) {
+	tc := &TaskCompleteTool{Verify: true}
+	if _, ok := tc.ToolPayloadSchema().Properties["confidence"]; !ok {
+		t.Fatal("expected a confidence property when Verify is set")
+	}
+
+	var resp map[string]any
+	json.Unmarshal([]byte(tc.Call(context.Background(), `{"summary": "done"}`)), &resp)
+	if resp["status"] != "verify" || tc.IsCompleted() {
+		t.Fatalf("expected the first call to ask for a self-check, got %v", resp)
+	}
+
+	json.Unmarshal([]byte(tc.Call(context.Background(), `{"summary": "done", "confidence": 1.5}`)), &resp)
+	if resp["status"] != "error" || tc.IsCompleted() {
+		t.Fatalf("expected an out-of-range confidence to be rejected, got %v", resp)
+	}
+
+	json.Unmarshal([]byte(tc.Call(context.Background(), `{"summary": "checked", "confidence": 0.8}`)), &resp)
+	if resp["status"] != "ok" || !tc.IsSucceeded() {
+		t.Fatalf("expected the task to complete, got %v", resp)
+	}
+	if c, ok := tc.Confidence(); !ok || c != 0.8 {
+		t.Fatalf("expected confidence 0.8, got %v (%v)", c, ok)
+	}
+}
+
+func TestTaskComplete_VerifySkippedOnFailure(t *testing.T) {
+	tc := &TaskCompleteTool{Verify: true}
+	tc.Call(context.Background(), `{"succeed": false, "reason": "site is down"}`)
+	if !tc.IsCompleted() || tc.IsSucceeded() {
+		t.Fatal("expected failing a verified task to complete it without a self-check")
+	}
+	if _, ok := tc.Confidence(); ok {
+		t.Fatal("expected no confidence on a failed task")
+	}
+}
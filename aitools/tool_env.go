@@ -0,0 +1,59 @@
+package aitools
+
+import "context"
+
+// Plugins are shared across every task that uses them, so settings that
+// differ per task (which repository to work in, which credentials to use)
+// can't go through Configure. Instead the runner and agents attach a
+// ToolEnv to the context of each tool call, and the plugin client sends it
+// along with the call.
+
+type toolEnvKey struct{}
+
+// ToolEnv holds the environment variables and working directory a tool
+// call should run with.
+type ToolEnv struct {
+	Env map[string]string
+	Dir string
+}
+
+// IsEmpty reports whether e sets nothing.
+func (e *ToolEnv) IsEmpty() bool {
+	return e == nil || (len(e.Env) == 0 && e.Dir == "")
+}
+
+// Merge returns e overlaid with over: over's variables replace e's with the
+// same name, and over's directory replaces e's when set. Either may be nil.
+func (e *ToolEnv) Merge(over *ToolEnv) *ToolEnv {
+	if over.IsEmpty() {
+		return e
+	}
+	if e.IsEmpty() {
+		return over
+	}
+	merged := &ToolEnv{Env: make(map[string]string, len(e.Env)+len(over.Env)), Dir: e.Dir}
+	for k, v := range e.Env {
+		merged.Env[k] = v
+	}
+	for k, v := range over.Env {
+		merged.Env[k] = v
+	}
+	if over.Dir != "" {
+		merged.Dir = over.Dir
+	}
+	return merged
+}
+
+// WithToolEnv returns ctx carrying env. An empty env leaves ctx unchanged.
+func WithToolEnv(ctx context.Context, env *ToolEnv) context.Context {
+	if env.IsEmpty() {
+		return ctx
+	}
+	return context.WithValue(ctx, toolEnvKey{}, env)
+}
+
+// ToolEnvFromContext returns nil if no ToolEnv is attached.
+func ToolEnvFromContext(ctx context.Context) *ToolEnv {
+	env, _ := ctx.Value(toolEnvKey{}).(*ToolEnv)
+	return env
+}
@@ -0,0 +1,43 @@
+package aitools
+
+import (
+	"context"
+	"testing"
+)
+
+func TestToolEnvMergeOverridesVariablesAndDir(t *testing.T) {
+	agent := &ToolEnv{Env: map[string]string{"REGION": "us", "USER": "bot"}, Dir: "/srv"}
+	task := &ToolEnv{Env: map[string]string{"REGION": "eu"}}
+
+	got := agent.Merge(task)
+	if got.Env["REGION"] != "eu" || got.Env["USER"] != "bot" {
+		t.Errorf("expected task variables over agent variables, got %v", got.Env)
+	}
+	if got.Dir != "/srv" {
+		t.Errorf("expected agent dir to be kept when the task sets none, got %q", got.Dir)
+	}
+	if agent.Env["REGION"] != "us" {
+		t.Errorf("Merge must not modify its receiver")
+	}
+
+	got = agent.Merge(&ToolEnv{Dir: "/repos/web"})
+	if got.Dir != "/repos/web" {
+		t.Errorf("expected task dir to win, got %q", got.Dir)
+	}
+
+	var none *ToolEnv
+	if none.Merge(task) != task || task.Merge(nil) != task {
+		t.Errorf("merging with nil should return the other env")
+	}
+}
+
+func TestWithToolEnvSkipsEmptyEnv(t *testing.T) {
+	ctx := WithToolEnv(context.Background(), &ToolEnv{})
+	if ToolEnvFromContext(ctx) != nil {
+		t.Fatalf("empty env should not be attached")
+	}
+	env := &ToolEnv{Dir: "/repos/api"}
+	if ToolEnvFromContext(WithToolEnv(ctx, env)) != env {
+		t.Fatalf("expected env to be attached")
+	}
+}
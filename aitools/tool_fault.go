@@ -0,0 +1,27 @@
+package aitools
+
+import "context"
+
+// A mission run in chaos mode (failure injection for tests) attaches a
+// ToolFault to its context. Commanders and agents consult it before every
+// tool call, so injected failures look exactly like tools that failed.
+
+type toolFaultKey struct{}
+
+// ToolFault decides whether a tool call fails without running. It returns
+// the result the call should produce instead, or "" to run the tool.
+type ToolFault func(ctx context.Context, toolName string) string
+
+// WithToolFault returns ctx carrying fault. A nil fault leaves ctx unchanged.
+func WithToolFault(ctx context.Context, fault ToolFault) context.Context {
+	if fault == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, toolFaultKey{}, fault)
+}
+
+// ToolFaultFromContext returns nil if no ToolFault is attached.
+func ToolFaultFromContext(ctx context.Context) ToolFault {
+	fault, _ := ctx.Value(toolFaultKey{}).(ToolFault)
+	return fault
+}
@@ -0,0 +1,23 @@
+package aitools
+
+import "context"
+
+// Long-running tools (browser automation, builds) can report progress
+// while they run. The orchestrator installs a callback on ctx before
+// calling a tool; tools that support progress read it with
+// ToolProgressFromContext and call it as updates arrive.
+
+type toolProgressKey struct{}
+
+// ToolProgressFunc receives one progress update from a running tool.
+type ToolProgressFunc func(content string)
+
+func WithToolProgress(ctx context.Context, fn ToolProgressFunc) context.Context {
+	return context.WithValue(ctx, toolProgressKey{}, fn)
+}
+
+// ToolProgressFromContext returns nil if no callback is installed.
+func ToolProgressFromContext(ctx context.Context) ToolProgressFunc {
+	fn, _ := ctx.Value(toolProgressKey{}).(ToolProgressFunc)
+	return fn
+}
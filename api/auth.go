@@ -0,0 +1,146 @@
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"squadron/config"
+	"squadron/store"
+)
+
+// sessionCookie carries the session key issued by an OIDC login.
+const sessionCookie = "squadron_session"
+
+// Principal is who a request acts as.
+type Principal struct {
+	UserID string // empty for the shared token and for servers without auth
+	Name   string
+	Role   string
+	// KeyID and KeyKind identify the API key or login session the request
+	// authenticated with, if any.
+	KeyID   string
+	KeyKind string
+}
+
+// adminPrincipal is used for the shared --token, and for every request
+// when the server has no authentication configured.
+var adminPrincipal = &Principal{Name: "admin", Role: config.RoleAdmin}
+
+var roleRank = map[string]int{
+	config.RoleViewer:   1,
+	config.RoleOperator: 2,
+	config.RoleAdmin:    3,
+}
+
+// can reports whether p has at least role.
+func (p *Principal) can(role string) bool {
+	return roleRank[p.Role] >= roleRank[role]
+}
+
+type principalKey struct{}
+
+// principalFrom returns the principal ServeHTTP attached to the request.
+func principalFrom(r *http.Request) *Principal {
+	if p, ok := r.Context().Value(principalKey{}).(*Principal); ok {
+		return p
+	}
+	return adminPrincipal
+}
+
+// authEnabled reports whether requests must authenticate.
+func (s *Server) authEnabled() bool {
+	return s.opts.Token != "" || s.opts.Users != nil
+}
+
+// authenticate resolves the request's credentials: the shared token, a
+// user's API key (Bearer or ?token=), or a login session cookie. It
+// returns nil when none of them is valid.
+func (s *Server) authenticate(r *http.Request) (*Principal, error) {
+	if !s.authEnabled() {
+		return adminPrincipal, nil
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		token = r.URL.Query().Get("token")
+	}
+	if token == "" && s.opts.Users != nil {
+		if c, err := r.Cookie(sessionCookie); err == nil {
+			token = c.Value
+		}
+	}
+	if token == "" {
+		return nil, nil
+	}
+	if s.opts.Token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(s.opts.Token)) == 1 {
+		return adminPrincipal, nil
+	}
+	if s.opts.Users == nil {
+		return nil, nil
+	}
+	user, key, err := s.opts.Users.LookupAPIKey(token)
+	if err != nil || user == nil {
+		return nil, err
+	}
+	return &Principal{UserID: user.ID, Name: user.Name, Role: user.Role, KeyID: key.ID, KeyKind: key.Kind}, nil
+}
+
+// require wraps h so it only runs for principals with at least role.
+func require(role string, h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !principalFrom(r).can(role) {
+			writeError(w, http.StatusForbidden, "requires the "+role+" role")
+			return
+		}
+		h(w, r)
+	}
+}
+
+// withPrincipal returns r carrying p.
+func withPrincipal(r *http.Request, p *Principal) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), principalKey{}, p))
+}
+
+// ownsMission reports whether p may stop or cancel the mission: admins
+// may stop any run, operators the runs they started.
+func (s *Server) ownsMission(p *Principal, missionID string) (bool, error) {
+	if p.Role == config.RoleAdmin || s.opts.Users == nil {
+		return true, nil
+	}
+	owner, err := s.opts.Users.GetMissionOwner(missionID)
+	if err != nil {
+		return false, err
+	}
+	return owner != "" && owner == p.UserID, nil
+}
+
+// ownerName returns the name of the user who started the mission, or ""
+// when it has none.
+func (s *Server) ownerName(missionID string) string {
+	if s.opts.Users == nil {
+		return ""
+	}
+	id, err := s.opts.Users.GetMissionOwner(missionID)
+	if err != nil || id == "" {
+		return ""
+	}
+	user, err := s.opts.Users.GetUser(id)
+	if err != nil || user == nil {
+		return ""
+	}
+	return user.Name
+}
+
+// userResponse is how users appear in API responses.
+type userResponse struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Email   string `json:"email,omitempty"`
+	Role    string `json:"role"`
+	Subject string `json:"subject,omitempty"`
+}
+
+func toUserResponse(u *store.UserRecord) userResponse {
+	return userResponse{ID: u.ID, Name: u.Name, Email: u.Email, Role: u.Role, Subject: u.Subject}
+}
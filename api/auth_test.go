@@ -0,0 +1,275 @@
+package api
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"squadron/config"
+	"squadron/store"
+)
+
+// addUser creates a user with an API key and returns the user's ID and key.
+func addUser(t *testing.T, users store.UserStore, name, role string) (string, string) {
+	t.Helper()
+	id, err := users.CreateUser(store.UserRecord{Name: name, Role: role})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, key, err := users.CreateAPIKey(id, "test", store.KeyKindAPI, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return id, key
+}
+
+func TestRolesAndMissionOwnership(t *testing.T) {
+	var adminKey, aliceID, aliceKey, bobKey, viewerKey string
+	_, ts, _ := newTestServerWith(t, &fakeProvider{hang: true}, func(opts *Options, stores *store.Bundle) {
+		opts.Token = ""
+		opts.Users = stores.Users
+		_, adminKey = addUser(t, stores.Users, "root", config.RoleAdmin)
+		aliceID, aliceKey = addUser(t, stores.Users, "alice", config.RoleOperator)
+		_, bobKey = addUser(t, stores.Users, "bob", config.RoleOperator)
+		_, viewerKey = addUser(t, stores.Users, "vic", config.RoleViewer)
+	})
+
+	if resp, _ := doAs(t, ts, "", "GET", "/me", ""); resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without credentials, got %d", resp.StatusCode)
+	}
+	if resp, _ := doAs(t, ts, "sqd_bogus", "GET", "/me", ""); resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for an unknown key, got %d", resp.StatusCode)
+	}
+	if resp, me := doAs(t, ts, aliceKey, "GET", "/me", ""); resp.StatusCode != http.StatusOK || me["name"] != "alice" || me["role"] != "operator" {
+		t.Fatalf("me: %d %v", resp.StatusCode, me)
+	}
+
+	if resp, _ := doAs(t, ts, viewerKey, "POST", "/missions", `{"mission":"hello"}`); resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("expected 403 for a viewer starting a mission, got %d", resp.StatusCode)
+	}
+	resp, body := doAs(t, ts, aliceKey, "POST", "/missions", `{"mission":"hello"}`)
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("start: %d %v", resp.StatusCode, body)
+	}
+	id := body["missionId"].(string)
+
+	if resp, m := doAs(t, ts, viewerKey, "GET", "/missions/"+id, ""); resp.StatusCode != http.StatusOK || m["owner"] != "alice" {
+		t.Fatalf("viewer get: %d %v", resp.StatusCode, m)
+	}
+	if resp, _ := doAs(t, ts, bobKey, "DELETE", "/missions/"+id, ""); resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("expected 403 stopping another operator's run, got %d", resp.StatusCode)
+	}
+	if resp, _ := doAs(t, ts, viewerKey, "POST", "/missions/"+id+"/cancel", ""); resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("expected 403 for a viewer cancelling, got %d", resp.StatusCode)
+	}
+	if resp, body := doAs(t, ts, aliceKey, "DELETE", "/missions/"+id, ""); resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("owner stop: %d %v", resp.StatusCode, body)
+	}
+
+	// User management is admin-only; keys are self-service
+	if resp, _ := doAs(t, ts, aliceKey, "POST", "/users", `{"name":"mallory","role":"admin"}`); resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("expected 403 for an operator creating users, got %d", resp.StatusCode)
+	}
+	resp, created := doAs(t, ts, adminKey, "POST", "/users", `{"name":"carol","role":"viewer"}`)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("create user: %d %v", resp.StatusCode, created)
+	}
+	if resp, _ := doAs(t, ts, adminKey, "POST", "/users", `{"name":"carol","role":"viewer"}`); resp.StatusCode != http.StatusConflict {
+		t.Fatalf("expected 409 for a duplicate user, got %d", resp.StatusCode)
+	}
+	if resp, u := doAs(t, ts, adminKey, "PATCH", "/users/"+created["id"].(string), `{"role":"operator"}`); resp.StatusCode != http.StatusOK || u["role"] != "operator" {
+		t.Fatalf("update user: %d %v", resp.StatusCode, u)
+	}
+	if resp, _ := doAs(t, ts, bobKey, "POST", "/users/"+aliceID+"/keys", ""); resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("expected 403 creating another user's key, got %d", resp.StatusCode)
+	}
+	resp, key := doAs(t, ts, aliceKey, "POST", "/users/"+aliceID+"/keys", `{"name":"ci"}`)
+	if resp.StatusCode != http.StatusCreated || key["key"] == nil {
+		t.Fatalf("create key: %d %v", resp.StatusCode, key)
+	}
+	newKey := key["key"].(string)
+	if resp, _ := doAs(t, ts, newKey, "GET", "/me", ""); resp.StatusCode != http.StatusOK {
+		t.Fatalf("new key rejected: %d", resp.StatusCode)
+	}
+	if resp, _ := doAs(t, ts, bobKey, "DELETE", "/keys/"+key["id"].(string), ""); resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404 revoking another user's key, got %d", resp.StatusCode)
+	}
+	if resp, _ := doAs(t, ts, aliceKey, "DELETE", "/keys/"+key["id"].(string), ""); resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("revoke key: %d", resp.StatusCode)
+	}
+	if resp, _ := doAs(t, ts, newKey, "GET", "/me", ""); resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for a revoked key, got %d", resp.StatusCode)
+	}
+}
+
+// fakeIdP is a minimal OpenID provider whose token endpoint returns an ID
+// token for the current email and nonce.
+type fakeIdP struct {
+	*httptest.Server
+	email    string
+	nonce    string
+	verified *bool // email_verified claim; left out when nil
+}
+
+func newFakeIdP(t *testing.T) *fakeIdP {
+	idp := &fakeIdP{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 idp.URL,
+			"authorization_endpoint": idp.URL + "/authorize",
+			"token_endpoint":         idp.URL + "/token",
+		})
+	})
+	mux.HandleFunc("POST /token", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("code") != "good-code" || r.FormValue("code_verifier") == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+		claims := map[string]any{
+			"iss":   idp.URL,
+			"sub":   "sub-" + idp.email,
+			"aud":   "squadron",
+			"exp":   time.Now().Add(time.Hour).Unix(),
+			"nonce": idp.nonce,
+			"email": idp.email,
+		}
+		if idp.verified != nil {
+			claims["email_verified"] = *idp.verified
+		}
+		payload, _ := json.Marshal(claims)
+		idToken := "e30." + base64.RawURLEncoding.EncodeToString(payload) + ".sig"
+		json.NewEncoder(w).Encode(map[string]string{"id_token": idToken})
+	})
+	idp.Server = httptest.NewServer(mux)
+	t.Cleanup(idp.Close)
+	return idp
+}
+
+func TestOIDCLogin(t *testing.T) {
+	idp := newFakeIdP(t)
+	verified := true
+	idp.verified = &verified
+	_, ts, _ := newTestServerWith(t, &fakeProvider{}, func(opts *Options, stores *store.Bundle) {
+		oidc := &config.OIDCConfig{
+			Issuer:         idp.URL,
+			ClientID:       "squadron",
+			RedirectURL:    "http://squadron.test/auth/callback",
+			AllowedDomains: []string{"example.com"},
+		}
+		oidc.Defaults()
+		opts.Users = stores.Users
+		opts.OIDC = oidc
+		if _, err := stores.Users.CreateUser(store.UserRecord{Name: "pat@partner.com", Role: config.RoleOperator}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+
+	// login goes through the provider and returns the callback response
+	login := func(email string) *http.Response {
+		t.Helper()
+		resp, err := client.Get(ts.URL + "/auth/login?return_to=/me")
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		loc, _ := url.Parse(resp.Header.Get("Location"))
+		if resp.StatusCode != http.StatusFound || !strings.HasPrefix(loc.String(), idp.URL+"/authorize") {
+			t.Fatalf("login: %d %s", resp.StatusCode, loc)
+		}
+		q := loc.Query()
+		if q.Get("code_challenge_method") != "S256" || q.Get("client_id") != "squadron" {
+			t.Fatalf("unexpected authorization request: %s", loc)
+		}
+		idp.email, idp.nonce = email, q.Get("nonce")
+		resp, err = client.Get(ts.URL + "/auth/callback?code=good-code&state=" + url.QueryEscape(q.Get("state")))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+	withCookie := func(method, path string, c *http.Cookie) *http.Response {
+		t.Helper()
+		req, _ := http.NewRequest(method, ts.URL+path, nil)
+		req.AddCookie(c)
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+
+	resp := login("dana@example.com")
+	if resp.StatusCode != http.StatusFound || resp.Header.Get("Location") != "/me" {
+		t.Fatalf("callback: %d %s", resp.StatusCode, resp.Header.Get("Location"))
+	}
+	var session *http.Cookie
+	for _, c := range resp.Cookies() {
+		if c.Name == sessionCookie {
+			session = c
+		}
+	}
+	if session == nil || !session.HttpOnly {
+		t.Fatalf("no session cookie: %v", resp.Cookies())
+	}
+	me := withCookie("GET", "/me", session)
+	var user map[string]any
+	json.NewDecoder(me.Body).Decode(&user)
+	me.Body.Close()
+	if user["name"] != "dana@example.com" || user["role"] != config.RoleViewer {
+		t.Fatalf("unexpected user after signup: %v", user)
+	}
+
+	// An unverified email can't claim a pre-created user, whether the
+	// provider says so or leaves the claim out
+	unverified := false
+	for _, v := range []*bool{&unverified, nil} {
+		idp.verified = v
+		if resp := login("pat@partner.com"); resp.StatusCode != http.StatusForbidden {
+			t.Fatalf("expected 403 for email_verified=%v, got %d", v, resp.StatusCode)
+		}
+	}
+	idp.verified = &verified
+
+	// Users added ahead of time are linked and keep their role
+	resp = login("pat@partner.com")
+	if resp.StatusCode != http.StatusFound {
+		t.Fatalf("expected a pre-created user to log in, got %d", resp.StatusCode)
+	}
+	if resp := login("eve@other.com"); resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("expected 403 for a domain outside allowed_domains, got %d", resp.StatusCode)
+	}
+
+	if resp, err := client.Get(ts.URL + "/auth/callback?code=good-code&state=forged"); err != nil || resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected 400 for an unknown state, got %v %v", resp, err)
+	}
+
+	if resp := withCookie("POST", "/auth/logout", session); resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("logout: %d", resp.StatusCode)
+	}
+	if resp := withCookie("GET", "/me", session); resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected 401 after logout, got %d", resp.StatusCode)
+	}
+}
+
+func TestLocalPath(t *testing.T) {
+	for in, want := range map[string]string{
+		"/missions/1":        "/missions/1",
+		"":                   "",
+		"https://evil.test/": "",
+		"//evil.test/":       "",
+		"/\\evil.test":       "",
+	} {
+		if got := localPath(in); got != want {
+			t.Errorf("localPath(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"squadron/streamers/ndjson"
+)
+
+// eventPageSize is how many stored events are read per query when
+// replaying a finished run.
+const eventPageSize = 500
+
+// eventLog collects a live run's NDJSON event lines so any number of SSE
+// clients can replay them from the start and then follow along. It is the
+// io.Writer behind the run's ndjson.MissionHandler, which writes each
+// event in a single call.
+type eventLog struct {
+	mu      sync.Mutex
+	types   []string
+	raw     [][]byte
+	changed chan struct{} // closed and replaced on every append
+	closed  bool
+
+	// started receives the mission ID from the mission_started event
+	started     chan string
+	startedOnce sync.Once
+}
+
+func newEventLog() *eventLog {
+	return &eventLog{
+		changed: make(chan struct{}),
+		started: make(chan string, 1),
+	}
+}
+
+func (l *eventLog) Write(p []byte) (int, error) {
+	var line ndjson.Line
+	if err := json.Unmarshal(p, &line); err != nil {
+		return 0, err
+	}
+	if line.MissionID != "" {
+		l.startedOnce.Do(func() { l.started <- line.MissionID })
+	}
+	raw := append([]byte(nil), p...)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.types = append(l.types, string(line.Type))
+	l.raw = append(l.raw, raw)
+	close(l.changed)
+	l.changed = make(chan struct{})
+	return len(p), nil
+}
+
+// close marks the run finished; subscribers stop once they've caught up.
+func (l *eventLog) close() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.closed = true
+	close(l.changed)
+	l.changed = make(chan struct{})
+}
+
+func (l *eventLog) isClosed() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.closed
+}
+
+// since returns the event types and lines from index from on, a channel
+// closed when more arrive, and whether the run has finished.
+func (l *eventLog) since(from int) ([]string, [][]byte, <-chan struct{}, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.types[from:], l.raw[from:], l.changed, l.closed
+}
+
+// handleEvents streams a run's events as Server-Sent Events, one SSE event
+// per mission event, named after its type and carrying the NDJSON line as
+// data. A run in progress in this server is replayed from the start and
+// followed until it finishes; any other run is replayed from the event
+// store.
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	run := s.liveRun(id)
+	if run == nil {
+		if _, err := s.opts.Stores.Missions.GetMission(id); err != nil {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("mission %s not found", id))
+			return
+		}
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming not supported")
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	if run == nil {
+		s.replayStoredEvents(w, id)
+		flusher.Flush()
+		return
+	}
+
+	next := 0
+	for {
+		types, raw, changed, closed := run.events.since(next)
+		for i := range types {
+			writeSSE(w, types[i], raw[i])
+		}
+		next += len(types)
+		flusher.Flush()
+		if closed {
+			return
+		}
+		select {
+		case <-changed:
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// storedLine mirrors ndjson.Line for an event read back from the store.
+type storedLine struct {
+	Type      string          `json:"type"`
+	MissionID string          `json:"missionId"`
+	Timestamp time.Time       `json:"timestamp"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// replayStoredEvents writes a finished run's events from the event store,
+// shaped like the live NDJSON lines.
+func (s *Server) replayStoredEvents(w http.ResponseWriter, missionID string) {
+	for offset := 0; ; offset += eventPageSize {
+		events, err := s.opts.Stores.Events.GetEventsByMission(missionID, eventPageSize, offset)
+		if err != nil {
+			writeSSE(w, "error", []byte(fmt.Sprintf("%q", err.Error())))
+			return
+		}
+		for _, e := range events {
+			payload := json.RawMessage(e.DataJSON)
+			if !json.Valid(payload) {
+				payload = json.RawMessage("null")
+			}
+			data, _ := json.Marshal(storedLine{e.EventType, e.MissionID, e.CreatedAt.UTC(), payload})
+			writeSSE(w, e.EventType, data)
+		}
+		if len(events) < eventPageSize {
+			return
+		}
+	}
+}
+
+func writeSSE(w http.ResponseWriter, event string, data []byte) {
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, trimNewline(data))
+}
+
+func trimNewline(b []byte) []byte {
+	for len(b) > 0 && (b[len(b)-1] == '\n' || b[len(b)-1] == '\r') {
+		b = b[:len(b)-1]
+	}
+	return b
+}
@@ -0,0 +1,395 @@
+package api
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"squadron/config"
+	"squadron/store"
+)
+
+// OIDC login, available when Options.OIDC is set:
+//
+//	GET  /auth/login      redirect to the provider (?return_to=/path)
+//	GET  /auth/callback   finish the login and set the session cookie
+//	POST /auth/logout     end the session
+//
+// Users are matched on the ID token's subject. On first login a user is
+// linked to the pre-created user named by their email, or created with
+// default_role when their email domain is in allowed_domains.
+
+const (
+	// loginTimeout is how long a user has to finish a login at the provider.
+	loginTimeout = 10 * time.Minute
+	// maxPendingLogins bounds logins in progress, so unauthenticated
+	// clients can't grow the pending map without limit.
+	maxPendingLogins = 1000
+)
+
+// oidcLogin runs the authorization code flow against the configured
+// provider.
+type oidcLogin struct {
+	cfg    *config.OIDCConfig
+	client *http.Client
+
+	mu        sync.Mutex
+	discovery *oidcDiscovery           // fetched on first use
+	pending   map[string]*pendingLogin // by state
+}
+
+// oidcDiscovery is the part of the provider metadata the login uses.
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+}
+
+// pendingLogin is a login redirected to the provider that hasn't come back.
+type pendingLogin struct {
+	nonce    string
+	verifier string // PKCE code verifier
+	returnTo string
+	expires  time.Time
+}
+
+// idTokenClaims are the ID token claims the login checks and uses.
+type idTokenClaims struct {
+	Issuer        string   `json:"iss"`
+	Subject       string   `json:"sub"`
+	Audience      audience `json:"aud"`
+	Expiry        int64    `json:"exp"`
+	Nonce         string   `json:"nonce"`
+	Email         string   `json:"email"`
+	EmailVerified *bool    `json:"email_verified"`
+}
+
+// audience is the aud claim, which is a string or an array of strings.
+type audience []string
+
+func (a *audience) UnmarshalJSON(b []byte) error {
+	var one string
+	if err := json.Unmarshal(b, &one); err == nil {
+		*a = audience{one}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(b, &many); err != nil {
+		return err
+	}
+	*a = many
+	return nil
+}
+
+func newOIDCLogin(cfg *config.OIDCConfig) *oidcLogin {
+	return &oidcLogin{
+		cfg:     cfg,
+		client:  &http.Client{Timeout: 10 * time.Second},
+		pending: make(map[string]*pendingLogin),
+	}
+}
+
+// metadata returns the provider's discovery document.
+func (o *oidcLogin) metadata() (*oidcDiscovery, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.discovery != nil {
+		return o.discovery, nil
+	}
+	resp, err := o.client.Get(o.cfg.Issuer + "/.well-known/openid-configuration")
+	if err != nil {
+		return nil, fmt.Errorf("oidc discovery: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("oidc discovery: %s", resp.Status)
+	}
+	var d oidcDiscovery
+	if err := json.NewDecoder(resp.Body).Decode(&d); err != nil {
+		return nil, fmt.Errorf("oidc discovery: %w", err)
+	}
+	if d.AuthorizationEndpoint == "" || d.TokenEndpoint == "" {
+		return nil, fmt.Errorf("oidc discovery: provider metadata has no authorization or token endpoint")
+	}
+	o.discovery = &d
+	return &d, nil
+}
+
+// begin records a login in progress and returns its state.
+func (o *oidcLogin) begin(returnTo string) (string, *pendingLogin, error) {
+	state, nonce, verifier := randomToken(), randomToken(), randomToken()
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	now := time.Now()
+	for k, p := range o.pending {
+		if now.After(p.expires) {
+			delete(o.pending, k)
+		}
+	}
+	if len(o.pending) >= maxPendingLogins {
+		return "", nil, fmt.Errorf("too many logins in progress")
+	}
+	p := &pendingLogin{nonce: nonce, verifier: verifier, returnTo: returnTo, expires: now.Add(loginTimeout)}
+	o.pending[state] = p
+	return state, p, nil
+}
+
+// finish removes and returns the login with the given state, or nil when
+// there is none or it expired.
+func (o *oidcLogin) finish(state string) *pendingLogin {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	p := o.pending[state]
+	delete(o.pending, state)
+	if p == nil || time.Now().After(p.expires) {
+		return nil
+	}
+	return p
+}
+
+// exchange trades an authorization code for the ID token's claims.
+func (o *oidcLogin) exchange(d *oidcDiscovery, code string, login *pendingLogin) (*idTokenClaims, error) {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {o.cfg.RedirectURL},
+		"client_id":     {o.cfg.ClientID},
+		"code_verifier": {login.verifier},
+	}
+	if o.cfg.ClientSecret != "" {
+		form.Set("client_secret", o.cfg.ClientSecret)
+	}
+	resp, err := o.client.PostForm(d.TokenEndpoint, form)
+	if err != nil {
+		return nil, fmt.Errorf("token exchange: %w", err)
+	}
+	defer resp.Body.Close()
+	var body struct {
+		IDToken string `json:"id_token"`
+		Error   string `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("token exchange: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK || body.IDToken == "" {
+		return nil, fmt.Errorf("token exchange: %s %s", resp.Status, body.Error)
+	}
+
+	// The ID token came straight from the token endpoint over the client's
+	// own TLS connection, so its signature need not be checked (OpenID
+	// Connect Core 3.1.3.7); the claims still are.
+	parts := strings.Split(body.IDToken, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("malformed id_token")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("malformed id_token: %w", err)
+	}
+	var claims idTokenClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, fmt.Errorf("malformed id_token: %w", err)
+	}
+	if err := o.checkClaims(d, &claims, login.nonce); err != nil {
+		return nil, err
+	}
+	return &claims, nil
+}
+
+func (o *oidcLogin) checkClaims(d *oidcDiscovery, c *idTokenClaims, nonce string) error {
+	issuer := d.Issuer
+	if issuer == "" {
+		issuer = o.cfg.Issuer
+	}
+	if strings.TrimSuffix(c.Issuer, "/") != strings.TrimSuffix(issuer, "/") {
+		return fmt.Errorf("id_token issuer %q does not match %q", c.Issuer, issuer)
+	}
+	audOK := false
+	for _, a := range c.Audience {
+		audOK = audOK || a == o.cfg.ClientID
+	}
+	if !audOK {
+		return fmt.Errorf("id_token is not for client %q", o.cfg.ClientID)
+	}
+	if time.Now().Unix() >= c.Expiry {
+		return fmt.Errorf("id_token expired")
+	}
+	if c.Nonce != nonce {
+		return fmt.Errorf("id_token nonce mismatch")
+	}
+	if c.Subject == "" {
+		return fmt.Errorf("id_token has no subject")
+	}
+	return nil
+}
+
+// domainAllowed reports whether email may sign up on first login.
+func (o *oidcLogin) domainAllowed(email string) bool {
+	_, domain, ok := strings.Cut(email, "@")
+	if !ok {
+		return false
+	}
+	for _, d := range o.cfg.AllowedDomains {
+		if strings.EqualFold(d, domain) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) handleLogin(w http.ResponseWriter, r *http.Request) {
+	d, err := s.oidc.metadata()
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+	state, login, err := s.oidc.begin(localPath(r.URL.Query().Get("return_to")))
+	if err != nil {
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	challenge := sha256.Sum256([]byte(login.verifier))
+	q := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {s.oidc.cfg.ClientID},
+		"redirect_uri":          {s.oidc.cfg.RedirectURL},
+		"scope":                 {strings.Join(s.oidc.cfg.Scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {login.nonce},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(challenge[:])},
+		"code_challenge_method": {"S256"},
+	}
+	sep := "?"
+	if strings.Contains(d.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	http.Redirect(w, r, d.AuthorizationEndpoint+sep+q.Encode(), http.StatusFound)
+}
+
+func (s *Server) handleCallback(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if e := q.Get("error"); e != "" {
+		writeError(w, http.StatusUnauthorized, fmt.Sprintf("login failed: %s %s", e, q.Get("error_description")))
+		return
+	}
+	login := s.oidc.finish(q.Get("state"))
+	if login == nil {
+		writeError(w, http.StatusBadRequest, "unknown or expired login; start again at /auth/login")
+		return
+	}
+	d, err := s.oidc.metadata()
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+	claims, err := s.oidc.exchange(d, q.Get("code"), login)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+	user, status, err := s.loginUser(claims)
+	if err != nil {
+		writeError(w, status, err.Error())
+		return
+	}
+
+	expires := time.Now().UTC().Add(time.Duration(s.oidc.cfg.SessionHours) * time.Hour)
+	_, key, err := s.opts.Users.CreateAPIKey(user.ID, "login", store.KeyKindSession, &expires)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookie,
+		Value:    key,
+		Path:     "/",
+		Expires:  expires,
+		HttpOnly: true,
+		Secure:   strings.HasPrefix(s.oidc.cfg.RedirectURL, "https://"),
+		SameSite: http.SameSiteLaxMode,
+	})
+	if login.returnTo != "" {
+		http.Redirect(w, r, login.returnTo, http.StatusFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"user": toUserResponse(user), "expiresAt": expires})
+}
+
+// loginUser finds or creates the user for a verified ID token. On failure
+// it also returns the HTTP status to respond with.
+func (s *Server) loginUser(c *idTokenClaims) (*store.UserRecord, int, error) {
+	users := s.opts.Users
+	user, err := users.GetUserBySubject(c.Subject)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if user != nil {
+		return user, 0, nil
+	}
+	// Only an email the provider vouches for may claim a pre-created user
+	// or sign up; a missing email_verified claim counts as unverified.
+	if c.Email == "" || c.EmailVerified == nil || !*c.EmailVerified {
+		return nil, http.StatusForbidden, fmt.Errorf("login requires a verified email")
+	}
+
+	// A user an admin added ahead of time by email is linked on first login
+	user, err = users.GetUserByName(c.Email)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if user != nil {
+		if user.Subject != "" {
+			return nil, http.StatusForbidden, fmt.Errorf("user %q is linked to another identity", user.Name)
+		}
+		if err := users.SetUserSubject(user.ID, c.Subject); err != nil {
+			return nil, http.StatusInternalServerError, err
+		}
+		user.Subject = c.Subject
+		return user, 0, nil
+	}
+
+	if !s.oidc.domainAllowed(c.Email) {
+		return nil, http.StatusForbidden, fmt.Errorf("%s is not allowed to sign up; ask an admin to add you", c.Email)
+	}
+	rec := store.UserRecord{Name: c.Email, Email: c.Email, Subject: c.Subject, Role: s.oidc.cfg.DefaultRole}
+	id, err := users.CreateUser(rec)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	rec.ID = id
+	return &rec, 0, nil
+}
+
+func (s *Server) handleLogout(w http.ResponseWriter, r *http.Request) {
+	if p := principalFrom(r); p.KeyKind == store.KeyKindSession {
+		if err := s.opts.Users.RevokeAPIKey(p.KeyID); err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+	http.SetCookie(w, &http.Cookie{Name: sessionCookie, Value: "", Path: "/", MaxAge: -1, HttpOnly: true})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// localPath returns p when it is a path on this server, so return_to
+// can't redirect to another site, and "" otherwise.
+func localPath(p string) string {
+	if !strings.HasPrefix(p, "/") || strings.HasPrefix(p, "//") || strings.HasPrefix(p, "/\\") {
+		return ""
+	}
+	return p
+}
+
+// randomToken returns 32 random bytes, base64url-encoded.
+func randomToken() string {
+	b := make([]byte, 32)
+	rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
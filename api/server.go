@@ -0,0 +1,474 @@
+// Package api serves a REST API for launching and monitoring missions, so
+// other systems can drive squadron over HTTP:
+//
+//	POST   /missions               start a mission run (async)
+//	GET    /missions/{id}          run status
+//	GET    /missions/{id}/tasks    task statuses and outputs
+//	GET    /missions/{id}/events   mission events as Server-Sent Events
+//	POST   /missions/{id}/cancel   cancel a run started by this server
+//	DELETE /missions/{id}          stop a run started by this server
+//
+// With a user store, requests authenticate as users and are authorized by
+// role; see auth.go, users.go and oidc.go.
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"squadron/config"
+	"squadron/mission"
+	"squadron/store"
+	"squadron/streamers"
+	"squadron/streamers/ndjson"
+)
+
+// startTimeout bounds how long POST /missions waits for the run to create
+// its mission record.
+const startTimeout = 30 * time.Second
+
+// Options configures a Server.
+type Options struct {
+	// Config is the validated config whose missions can be started.
+	Config *config.Config
+	// ConfigPath is the config directory, passed to each mission runner.
+	ConfigPath string
+	// Stores is read by the GET endpoints. Each run opens its own bundle
+	// from Config.Storage, the same way the command center bridge does.
+	Stores *store.Bundle
+	// Token, when set, must be sent as a Bearer token (or ?token= for
+	// EventSource clients, which can't set headers). It acts as an admin.
+	Token string
+	// Users, when set, turns on multi-user mode: requests authenticate
+	// with a user's API key or login session and are authorized by the
+	// user's role. Nil leaves Token as the only credential.
+	Users store.UserStore
+	// OIDC enables browser login against an OpenID Connect provider.
+	// Requires Users.
+	OIDC *config.OIDCConfig
+	// RunnerOptions are passed to every mission runner.
+	RunnerOptions []mission.RunnerOption
+}
+
+// Server implements the REST API. Create one with NewServer.
+type Server struct {
+	opts Options
+	mux  *http.ServeMux
+	oidc *oidcLogin // nil without Options.OIDC
+
+	mu      sync.Mutex
+	runs    map[string]*liveRun // runs in progress, by mission ID
+	running map[string]int      // runs in progress, by mission name (max_parallel)
+	wg      sync.WaitGroup
+}
+
+// liveRun is a mission run started by this server that hasn't finished.
+type liveRun struct {
+	runner *mission.Runner
+	cancel context.CancelFunc
+	events *eventLog
+}
+
+// NewServer creates a Server. Serve it with any http.Server.
+func NewServer(opts Options) *Server {
+	s := &Server{
+		opts:    opts,
+		mux:     http.NewServeMux(),
+		runs:    make(map[string]*liveRun),
+		running: make(map[string]int),
+	}
+	s.mux.HandleFunc("POST /missions", require(config.RoleOperator, s.handleStart))
+	s.mux.HandleFunc("GET /missions/{id}", require(config.RoleViewer, s.handleGetMission))
+	s.mux.HandleFunc("GET /missions/{id}/tasks", require(config.RoleViewer, s.handleGetTasks))
+	s.mux.HandleFunc("GET /missions/{id}/events", require(config.RoleViewer, s.handleEvents))
+	s.mux.HandleFunc("POST /missions/{id}/cancel", require(config.RoleOperator, s.handleCancel))
+	s.mux.HandleFunc("DELETE /missions/{id}", require(config.RoleOperator, s.handleStop))
+	if opts.Users != nil {
+		s.registerUserRoutes()
+		if opts.OIDC != nil {
+			s.oidc = newOIDCLogin(opts.OIDC)
+			s.mux.HandleFunc("GET /auth/login", s.handleLogin)
+			s.mux.HandleFunc("GET /auth/callback", s.handleCallback)
+			s.mux.HandleFunc("POST /auth/logout", require(config.RoleViewer, s.handleLogout))
+		}
+	}
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// The login flow is how unauthenticated users get credentials
+	if s.oidc != nil && (r.URL.Path == "/auth/login" || r.URL.Path == "/auth/callback") {
+		s.mux.ServeHTTP(w, r)
+		return
+	}
+	p, err := s.authenticate(r)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if p == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	s.mux.ServeHTTP(w, withPrincipal(r, p))
+}
+
+// Shutdown stops every run started by this server and waits for them to
+// record their final status.
+func (s *Server) Shutdown() {
+	s.mu.Lock()
+	for _, run := range s.runs {
+		run.runner.Drain()
+		run.cancel()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+}
+
+// =============================================================================
+// POST /missions
+// =============================================================================
+
+// StartRequest is the body of POST /missions. Inputs that aren't strings
+// are passed to the mission JSON-encoded.
+type StartRequest struct {
+	Mission string         `json:"mission"`
+	Inputs  map[string]any `json:"inputs,omitempty"`
+}
+
+// StartResponse is returned by POST /missions once the run has started.
+type StartResponse struct {
+	MissionID string `json:"missionId"`
+	Mission   string `json:"mission"`
+	Status    string `json:"status"`
+}
+
+func (s *Server) handleStart(w http.ResponseWriter, r *http.Request) {
+	var req StartRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+		return
+	}
+	if req.Mission == "" {
+		writeError(w, http.StatusBadRequest, "mission is required")
+		return
+	}
+	missionCfg := s.findMission(req.Mission)
+	if missionCfg == nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("mission %q not found", req.Mission))
+		return
+	}
+	inputs, err := inputValues(req.Inputs)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !s.acquire(missionCfg) {
+		writeError(w, http.StatusTooManyRequests, fmt.Sprintf("mission %q is at max parallel capacity (%d)", req.Mission, maxParallel(missionCfg)))
+		return
+	}
+
+	runner, err := mission.NewRunner(s.opts.Config, s.opts.ConfigPath, req.Mission, inputs, s.opts.RunnerOptions...)
+	if err != nil {
+		s.release(req.Mission)
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	events := newEventLog()
+	handler := streamers.NewStoringMissionHandler(ndjson.NewMissionHandler(events), runner.EventStore(), runner.CostStore())
+	ctx, cancel := context.WithCancel(context.Background())
+	run := &liveRun{runner: runner, cancel: cancel, events: events}
+
+	runErr := make(chan error, 1)
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer s.release(req.Mission)
+		defer cancel()
+		err := runner.Run(ctx, handler)
+		// Unregister and wake up live event subscribers before closing the
+		// stores, so readers fall back to the shared bundle.
+		s.mu.Lock()
+		delete(s.runs, runner.MissionID())
+		events.close()
+		s.mu.Unlock()
+		runner.CloseStores()
+		runErr <- err
+	}()
+
+	// The mission ID exists once the runner has created the mission record
+	// and emitted mission_started.
+	select {
+	case missionID := <-events.started:
+		s.mu.Lock()
+		if !events.isClosed() {
+			s.runs[missionID] = run
+		}
+		s.mu.Unlock()
+		s.writeStarted(w, r, missionID, req.Mission)
+	case err := <-runErr:
+		// A short mission can finish before we get here; it still started
+		select {
+		case missionID := <-events.started:
+			s.writeStarted(w, r, missionID, req.Mission)
+			return
+		default:
+		}
+		if err == nil {
+			err = fmt.Errorf("mission finished without starting")
+		}
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("mission failed to start: %v", err))
+	case <-time.After(startTimeout):
+		runner.Drain()
+		cancel()
+		writeError(w, http.StatusGatewayTimeout, "mission failed to start in time")
+	}
+}
+
+// writeStarted records the requesting user as the mission's owner and
+// writes the StartResponse.
+func (s *Server) writeStarted(w http.ResponseWriter, r *http.Request, missionID, name string) {
+	if p := principalFrom(r); s.opts.Users != nil && p.UserID != "" {
+		if err := s.opts.Users.SetMissionOwner(missionID, p.UserID); err != nil {
+			writeError(w, http.StatusInternalServerError, fmt.Sprintf("record mission owner: %v", err))
+			return
+		}
+	}
+	writeJSON(w, http.StatusAccepted, StartResponse{MissionID: missionID, Mission: name, Status: "running"})
+}
+
+func (s *Server) findMission(name string) *config.Mission {
+	for i := range s.opts.Config.Missions {
+		if s.opts.Config.Missions[i].Name == name {
+			return &s.opts.Config.Missions[i]
+		}
+	}
+	return nil
+}
+
+// maxParallel is the mission's max_parallel, defaulting to 3 as the
+// scheduler does.
+func maxParallel(m *config.Mission) int {
+	if m.MaxParallel <= 0 {
+		return 3
+	}
+	return m.MaxParallel
+}
+
+func (s *Server) acquire(m *config.Mission) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.running[m.Name] >= maxParallel(m) {
+		return false
+	}
+	s.running[m.Name]++
+	return true
+}
+
+func (s *Server) release(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.running[name]--
+}
+
+// inputValues converts JSON inputs to the string form the runner takes:
+// strings as-is, everything else JSON-encoded.
+func inputValues(in map[string]any) (map[string]string, error) {
+	inputs := make(map[string]string, len(in))
+	for k, v := range in {
+		if str, ok := v.(string); ok {
+			inputs[k] = str
+			continue
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("input '%s': %w", k, err)
+		}
+		inputs[k] = string(b)
+	}
+	return inputs, nil
+}
+
+// =============================================================================
+// GET /missions/{id}, GET /missions/{id}/tasks
+// =============================================================================
+
+// Mission is the body of GET /missions/{id}.
+type Mission struct {
+	ID         string         `json:"id"`
+	Mission    string         `json:"mission"`
+	Status     string         `json:"status"`
+	Inputs     map[string]any `json:"inputs,omitempty"`
+	StartedAt  time.Time      `json:"startedAt"`
+	FinishedAt *time.Time     `json:"finishedAt,omitempty"`
+	// Owner is the name of the user who started the run, in multi-user mode.
+	Owner string `json:"owner,omitempty"`
+	// Live is true while the run is in progress in this server, so it can
+	// be stopped with DELETE.
+	Live bool `json:"live"`
+}
+
+// Task is one entry of GET /missions/{id}/tasks.
+type Task struct {
+	Name       string     `json:"name"`
+	Status     string     `json:"status"`
+	StartedAt  *time.Time `json:"startedAt,omitempty"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+	Summary    *string    `json:"summary,omitempty"`
+	Output     any        `json:"output,omitempty"`
+	Error      *string    `json:"error,omitempty"`
+	ErrorCode  *string    `json:"errorCode,omitempty"`
+	Confidence *float64   `json:"confidence,omitempty"`
+}
+
+func (s *Server) handleGetMission(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	rec, err := s.opts.Stores.Missions.GetMission(id)
+	if err != nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("mission %s not found", id))
+		return
+	}
+	m := Mission{
+		ID:         rec.ID,
+		Mission:    rec.MissionName,
+		Status:     rec.Status,
+		StartedAt:  rec.StartedAt,
+		FinishedAt: rec.FinishedAt,
+		Owner:      s.ownerName(id),
+		Live:       s.liveRun(id) != nil,
+	}
+	json.Unmarshal([]byte(rec.InputValuesJSON), &m.Inputs)
+	writeJSON(w, http.StatusOK, m)
+}
+
+func (s *Server) handleGetTasks(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if _, err := s.opts.Stores.Missions.GetMission(id); err != nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("mission %s not found", id))
+		return
+	}
+	rows, err := s.opts.Stores.Missions.GetTasksByMission(id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	tasks := make([]Task, 0, len(rows))
+	for _, t := range rows {
+		task := Task{
+			Name:       t.TaskName,
+			Status:     t.Status,
+			StartedAt:  t.StartedAt,
+			FinishedAt: t.FinishedAt,
+			Summary:    t.Summary,
+			Error:      t.Error,
+			ErrorCode:  t.ErrorCode,
+			Confidence: t.Confidence,
+		}
+		if t.OutputJSON != nil && *t.OutputJSON != "" {
+			var output any
+			if json.Unmarshal([]byte(*t.OutputJSON), &output) == nil {
+				task.Output = output
+			}
+		}
+		tasks = append(tasks, task)
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"tasks": tasks})
+}
+
+func (s *Server) liveRun(id string) *liveRun {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.runs[id]
+}
+
+// =============================================================================
+// DELETE /missions/{id}
+// =============================================================================
+
+func (s *Server) handleStop(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	run := s.controllableOrError(w, r, id)
+	if run == nil {
+		return
+	}
+	// Drain first (graceful), then cancel the context as a hard backstop
+	run.runner.Drain()
+	run.cancel()
+	writeJSON(w, http.StatusAccepted, map[string]string{"missionId": id, "status": "stopping"})
+}
+
+// runningOrError returns the live run with the given ID, or writes a 404 or
+// 409 and returns nil.
+func (s *Server) runningOrError(w http.ResponseWriter, id string) *liveRun {
+	run := s.liveRun(id)
+	if run == nil {
+		rec, err := s.opts.Stores.Missions.GetMission(id)
+		if err != nil {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("mission %s not found", id))
+			return nil
+		}
+		writeError(w, http.StatusConflict, fmt.Sprintf("mission %s is %s and not running in this server", id, rec.Status))
+	}
+	return run
+}
+
+// controllableOrError is runningOrError for runs the requester may stop:
+// it also writes a 403 and returns nil for runs they don't own.
+func (s *Server) controllableOrError(w http.ResponseWriter, r *http.Request, id string) *liveRun {
+	run := s.runningOrError(w, id)
+	if run == nil {
+		return nil
+	}
+	owns, err := s.ownsMission(principalFrom(r), id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return nil
+	}
+	if !owns {
+		writeError(w, http.StatusForbidden, fmt.Sprintf("mission %s was started by another user", id))
+		return nil
+	}
+	return run
+}
+
+// =============================================================================
+// POST /missions/{id}/cancel
+// =============================================================================
+
+// CancelRequest is the optional body of POST /missions/{id}/cancel.
+type CancelRequest struct {
+	Reason string `json:"reason,omitempty"`
+}
+
+func (s *Server) handleCancel(w http.ResponseWriter, r *http.Request) {
+	var req CancelRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+			return
+		}
+	}
+	id := r.PathValue("id")
+	run := s.controllableOrError(w, r, id)
+	if run == nil {
+		return
+	}
+	// Running commanders get a grace period to summarize their work
+	run.runner.Cancel(req.Reason)
+	writeJSON(w, http.StatusAccepted, map[string]string{"missionId": id, "status": "cancelling"})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
@@ -0,0 +1,263 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+
+	"squadron/config"
+	"squadron/llm"
+	"squadron/mission"
+	"squadron/store"
+)
+
+// fakeProvider completes every task on the first commander turn, or blocks
+// until the call is canceled when hang is set.
+type fakeProvider struct {
+	hang bool
+}
+
+func (p *fakeProvider) Chat(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+	return nil, nil
+}
+
+func (p *fakeProvider) ChatStream(ctx context.Context, req *llm.ChatRequest) (<-chan llm.StreamChunk, error) {
+	if p.hang {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	ch := make(chan llm.StreamChunk, 1)
+	ch <- llm.StreamChunk{
+		Done:  true,
+		Usage: &llm.Usage{InputTokens: 10, OutputTokens: 5},
+		ContentBlocks: []llm.ContentBlock{{
+			Type:    llm.ContentTypeToolUse,
+			ToolUse: &llm.ToolUseBlock{ID: "tc_1", Name: "task_complete", Input: json.RawMessage(`{"summary":"done"}`)},
+		}},
+	}
+	close(ch)
+	return ch, nil
+}
+
+func newTestServer(t *testing.T, provider llm.Provider) (*Server, *httptest.Server) {
+	t.Helper()
+	srv, ts, _ := newTestServerWith(t, provider, nil)
+	return srv, ts
+}
+
+// newTestServerWith is newTestServer with configure applied to the options.
+func newTestServerWith(t *testing.T, provider llm.Provider, configure func(*Options, *store.Bundle)) (*Server, *httptest.Server, *store.Bundle) {
+	t.Helper()
+	promptCaching := false
+	cfg := &config.Config{
+		Models: []config.Model{{Name: "test", Provider: config.ProviderAnthropic, APIKey: "test-key", PromptCaching: &promptCaching}},
+		Agents: []config.Agent{{Name: "worker", Model: "claude_sonnet_4", Personality: "Test agent"}},
+		Missions: []config.Mission{{
+			Name:        "hello",
+			MaxParallel: 1,
+			Commander:   &config.MissionCommander{Model: "claude_sonnet_4"},
+			Agents:      []string{"worker"},
+			Tasks: []config.Task{{
+				Name:          "greet",
+				ObjectiveExpr: hcl.StaticExpr(cty.StringVal("Say hello"), hcl.Range{}),
+				RawObjective:  "Say hello",
+			}},
+		}},
+		Storage: &config.StorageConfig{Backend: "sqlite", Path: filepath.Join(t.TempDir(), "store.db")},
+	}
+	stores, err := store.NewBundle(cfg.Storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := Options{
+		Config:        cfg,
+		Stores:        stores,
+		Token:         "secret",
+		RunnerOptions: []mission.RunnerOption{mission.WithProviderFactory(func() llm.Provider { return provider })},
+	}
+	if configure != nil {
+		configure(&opts, stores)
+	}
+	srv := NewServer(opts)
+	ts := httptest.NewServer(srv)
+	t.Cleanup(func() {
+		ts.Close()
+		srv.Shutdown()
+		stores.Close()
+	})
+	return srv, ts, stores
+}
+
+func do(t *testing.T, ts *httptest.Server, method, path, body string) (*http.Response, map[string]any) {
+	t.Helper()
+	return doAs(t, ts, "secret", method, path, body)
+}
+
+// doAs sends a request authenticated with token, or with no credentials
+// when token is empty.
+func doAs(t *testing.T, ts *httptest.Server, token, method, path, body string) (*http.Response, map[string]any) {
+	t.Helper()
+	req, _ := http.NewRequest(method, ts.URL+path, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var out map[string]any
+	json.NewDecoder(resp.Body).Decode(&out)
+	return resp, out
+}
+
+func waitForStatus(t *testing.T, ts *httptest.Server, id, status string) {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		_, m := do(t, ts, "GET", "/missions/"+id, "")
+		if m["status"] == status && m["live"] == false {
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatalf("mission %s never reached status %s", id, status)
+}
+
+func TestStartAndMonitorMission(t *testing.T) {
+	_, ts := newTestServer(t, &fakeProvider{})
+
+	resp, body := do(t, ts, "POST", "/missions", `{"mission":"hello"}`)
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("start: %d %v", resp.StatusCode, body)
+	}
+	id, _ := body["missionId"].(string)
+	if id == "" {
+		t.Fatalf("start returned no mission ID: %v", body)
+	}
+	waitForStatus(t, ts, id, "completed")
+
+	_, tasks := do(t, ts, "GET", "/missions/"+id+"/tasks", "")
+	list, _ := tasks["tasks"].([]any)
+	if len(list) != 1 || list[0].(map[string]any)["status"] != "completed" {
+		t.Fatalf("unexpected tasks: %v", tasks)
+	}
+
+	// A finished run's events are replayed from the store
+	req, _ := http.NewRequest("GET", ts.URL+"/missions/"+id+"/events?token=secret", nil)
+	eresp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer eresp.Body.Close()
+	if ct := eresp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+	var events []string
+	scanner := bufio.NewScanner(eresp.Body)
+	for scanner.Scan() {
+		if name, ok := strings.CutPrefix(scanner.Text(), "event: "); ok {
+			events = append(events, name)
+		}
+	}
+	if len(events) == 0 || events[0] != "mission_started" || events[len(events)-1] != "mission_completed" {
+		t.Fatalf("unexpected events: %v", events)
+	}
+}
+
+func TestStopMissionAndErrors(t *testing.T) {
+	_, ts := newTestServer(t, &fakeProvider{hang: true})
+
+	resp, body := do(t, ts, "POST", "/missions", `{"mission":"hello"}`)
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("start: %d %v", resp.StatusCode, body)
+	}
+	id := body["missionId"].(string)
+
+	// max_parallel = 1 while the first run hangs
+	if resp, _ := do(t, ts, "POST", "/missions", `{"mission":"hello"}`); resp.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 at capacity, got %d", resp.StatusCode)
+	}
+
+	// Follow the live run; the stream ends once the run has stopped
+	streamed := make(chan []string, 1)
+	req, _ := http.NewRequest("GET", ts.URL+"/missions/"+id+"/events?token=secret", nil)
+	eresp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		defer eresp.Body.Close()
+		var events []string
+		scanner := bufio.NewScanner(eresp.Body)
+		for scanner.Scan() {
+			if name, ok := strings.CutPrefix(scanner.Text(), "event: "); ok {
+				events = append(events, name)
+			}
+		}
+		streamed <- events
+	}()
+
+	if resp, body := do(t, ts, "DELETE", "/missions/"+id, ""); resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("stop: %d %v", resp.StatusCode, body)
+	}
+	waitForStatus(t, ts, id, "stopped")
+	select {
+	case events := <-streamed:
+		if len(events) == 0 || events[0] != "mission_started" {
+			t.Fatalf("unexpected live events: %v", events)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("live event stream did not end after the run stopped")
+	}
+	if resp, _ := do(t, ts, "DELETE", "/missions/"+id, ""); resp.StatusCode != http.StatusConflict {
+		t.Fatalf("expected 409 stopping a finished run, got %d", resp.StatusCode)
+	}
+
+	if resp, _ := do(t, ts, "POST", "/missions", `{"mission":"nope"}`); resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown mission, got %d", resp.StatusCode)
+	}
+	if resp, _ := do(t, ts, "GET", "/missions/unknown", ""); resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown run, got %d", resp.StatusCode)
+	}
+
+	req, _ = http.NewRequest("GET", ts.URL+"/missions/"+id, nil)
+	unauth, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unauth.Body.Close()
+	if unauth.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without a token, got %d", unauth.StatusCode)
+	}
+}
+
+func TestCancelMission(t *testing.T) {
+	_, ts := newTestServer(t, &fakeProvider{hang: true})
+
+	resp, body := do(t, ts, "POST", "/missions", `{"mission":"hello"}`)
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("start: %d %v", resp.StatusCode, body)
+	}
+	id := body["missionId"].(string)
+
+	if resp, body := do(t, ts, "POST", "/missions/"+id+"/cancel", `{"reason":"no longer needed"}`); resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("cancel: %d %v", resp.StatusCode, body)
+	}
+	waitForStatus(t, ts, id, "cancelled")
+	if resp, _ := do(t, ts, "POST", "/missions/"+id+"/cancel", ""); resp.StatusCode != http.StatusConflict {
+		t.Fatalf("expected 409 cancelling a finished run, got %d", resp.StatusCode)
+	}
+	if resp, _ := do(t, ts, "POST", "/missions/unknown/cancel", ""); resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown run, got %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,257 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"squadron/config"
+	"squadron/store"
+)
+
+// User and API key management, available in multi-user mode:
+//
+//	GET    /me                  the requesting user
+//	GET    /users               list users (admin)
+//	POST   /users               create a user (admin)
+//	PATCH  /users/{id}          change a user's role (admin)
+//	DELETE /users/{id}          delete a user and their keys (admin)
+//	GET    /users/{id}/keys     list a user's API keys (self or admin)
+//	POST   /users/{id}/keys     create an API key (self or admin)
+//	DELETE /keys/{id}           revoke an API key (its user or admin)
+func (s *Server) registerUserRoutes() {
+	s.mux.HandleFunc("GET /me", require(config.RoleViewer, s.handleMe))
+	s.mux.HandleFunc("GET /users", require(config.RoleAdmin, s.handleListUsers))
+	s.mux.HandleFunc("POST /users", require(config.RoleAdmin, s.handleCreateUser))
+	s.mux.HandleFunc("PATCH /users/{id}", require(config.RoleAdmin, s.handleUpdateUser))
+	s.mux.HandleFunc("DELETE /users/{id}", require(config.RoleAdmin, s.handleDeleteUser))
+	s.mux.HandleFunc("GET /users/{id}/keys", require(config.RoleViewer, s.handleListKeys))
+	s.mux.HandleFunc("POST /users/{id}/keys", require(config.RoleViewer, s.handleCreateKey))
+	s.mux.HandleFunc("DELETE /keys/{id}", require(config.RoleViewer, s.handleRevokeKey))
+}
+
+func (s *Server) handleMe(w http.ResponseWriter, r *http.Request) {
+	p := principalFrom(r)
+	if p.UserID == "" {
+		writeJSON(w, http.StatusOK, userResponse{Name: p.Name, Role: p.Role})
+		return
+	}
+	user, err := s.opts.Users.GetUser(p.UserID)
+	if err != nil || user == nil {
+		writeError(w, http.StatusInternalServerError, fmt.Sprintf("load user: %v", err))
+		return
+	}
+	writeJSON(w, http.StatusOK, toUserResponse(user))
+}
+
+func (s *Server) handleListUsers(w http.ResponseWriter, r *http.Request) {
+	users, err := s.opts.Users.ListUsers()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	out := make([]userResponse, 0, len(users))
+	for i := range users {
+		out = append(out, toUserResponse(&users[i]))
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"users": out})
+}
+
+// CreateUserRequest is the body of POST /users.
+type CreateUserRequest struct {
+	Name  string `json:"name"`
+	Email string `json:"email,omitempty"`
+	Role  string `json:"role"`
+}
+
+func (s *Server) handleCreateUser(w http.ResponseWriter, r *http.Request) {
+	var req CreateUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+		return
+	}
+	if req.Name == "" {
+		writeError(w, http.StatusBadRequest, "name is required")
+		return
+	}
+	if !config.ValidRole(req.Role) {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("unknown role %q", req.Role))
+		return
+	}
+	existing, err := s.opts.Users.GetUserByName(req.Name)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if existing != nil {
+		writeError(w, http.StatusConflict, fmt.Sprintf("user %q already exists", req.Name))
+		return
+	}
+	id, err := s.opts.Users.CreateUser(store.UserRecord{Name: req.Name, Email: req.Email, Role: req.Role})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusCreated, userResponse{ID: id, Name: req.Name, Email: req.Email, Role: req.Role})
+}
+
+// UpdateUserRequest is the body of PATCH /users/{id}.
+type UpdateUserRequest struct {
+	Role string `json:"role"`
+}
+
+func (s *Server) handleUpdateUser(w http.ResponseWriter, r *http.Request) {
+	var req UpdateUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+		return
+	}
+	if !config.ValidRole(req.Role) {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("unknown role %q", req.Role))
+		return
+	}
+	user := s.userOrError(w, r.PathValue("id"))
+	if user == nil {
+		return
+	}
+	if err := s.opts.Users.SetUserRole(user.ID, req.Role); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	user.Role = req.Role
+	writeJSON(w, http.StatusOK, toUserResponse(user))
+}
+
+func (s *Server) handleDeleteUser(w http.ResponseWriter, r *http.Request) {
+	user := s.userOrError(w, r.PathValue("id"))
+	if user == nil {
+		return
+	}
+	if user.ID == principalFrom(r).UserID {
+		writeError(w, http.StatusBadRequest, "you can't delete yourself")
+		return
+	}
+	if err := s.opts.Users.DeleteUser(user.ID); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// APIKey is how API keys appear in responses. Key is only set when the
+// key is created; the server keeps just a hash of it.
+type APIKey struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name,omitempty"`
+	Key        string     `json:"key,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty"`
+}
+
+func toAPIKey(k *store.APIKeyRecord) APIKey {
+	return APIKey{ID: k.ID, Name: k.Name, CreatedAt: k.CreatedAt, ExpiresAt: k.ExpiresAt, LastUsedAt: k.LastUsedAt, RevokedAt: k.RevokedAt}
+}
+
+func (s *Server) handleListKeys(w http.ResponseWriter, r *http.Request) {
+	user := s.selfOrAdmin(w, r)
+	if user == nil {
+		return
+	}
+	keys, err := s.opts.Users.ListAPIKeys(user.ID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	out := make([]APIKey, 0, len(keys))
+	for i := range keys {
+		out = append(out, toAPIKey(&keys[i]))
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"keys": out})
+}
+
+// CreateKeyRequest is the optional body of POST /users/{id}/keys.
+type CreateKeyRequest struct {
+	Name string `json:"name,omitempty"`
+	// ExpiresInHours, when set, makes the key stop working after that long.
+	ExpiresInHours int `json:"expiresInHours,omitempty"`
+}
+
+func (s *Server) handleCreateKey(w http.ResponseWriter, r *http.Request) {
+	var req CreateKeyRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+			return
+		}
+	}
+	if req.ExpiresInHours < 0 {
+		writeError(w, http.StatusBadRequest, "expiresInHours must be positive")
+		return
+	}
+	user := s.selfOrAdmin(w, r)
+	if user == nil {
+		return
+	}
+	var expiresAt *time.Time
+	if req.ExpiresInHours > 0 {
+		t := time.Now().UTC().Add(time.Duration(req.ExpiresInHours) * time.Hour)
+		expiresAt = &t
+	}
+	rec, key, err := s.opts.Users.CreateAPIKey(user.ID, req.Name, store.KeyKindAPI, expiresAt)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	out := toAPIKey(rec)
+	out.Key = key
+	writeJSON(w, http.StatusCreated, out)
+}
+
+func (s *Server) handleRevokeKey(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	key, err := s.opts.Users.GetAPIKey(id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	p := principalFrom(r)
+	// Other users' keys are reported missing rather than forbidden
+	if key == nil || (key.UserID != p.UserID && p.Role != config.RoleAdmin) {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("key %s not found", id))
+		return
+	}
+	if err := s.opts.Users.RevokeAPIKey(id); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// userOrError returns the user with the given ID, or writes a 404 or 500
+// and returns nil.
+func (s *Server) userOrError(w http.ResponseWriter, id string) *store.UserRecord {
+	user, err := s.opts.Users.GetUser(id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return nil
+	}
+	if user == nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("user %s not found", id))
+	}
+	return user
+}
+
+// selfOrAdmin returns the user named by the {id} path value when it is the
+// requester or the requester is an admin; otherwise it writes an error and
+// returns nil.
+func (s *Server) selfOrAdmin(w http.ResponseWriter, r *http.Request) *store.UserRecord {
+	id := r.PathValue("id")
+	if p := principalFrom(r); id != p.UserID && p.Role != config.RoleAdmin {
+		writeError(w, http.StatusForbidden, "you can only manage your own keys")
+		return nil
+	}
+	return s.userOrError(w, id)
+}
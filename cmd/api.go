@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"squadron/api"
+	"squadron/config"
+	"squadron/store"
+)
+
+var (
+	apiConfigPath string
+	apiPort       int
+	apiToken      string
+)
+
+var apiCmd = &cobra.Command{
+	Use:   "api",
+	Short: "Serve a REST API for launching and monitoring missions",
+	Long: `Start an HTTP server that lets other systems launch and monitor missions:
+
+  POST   /missions               start a mission ({"mission": "...", "inputs": {...}})
+  GET    /missions/{id}          run status
+  GET    /missions/{id}/tasks    task statuses and outputs
+  GET    /missions/{id}/events   mission events as Server-Sent Events
+  DELETE /missions/{id}          stop a run started by this server
+
+Requests must carry the token as "Authorization: Bearer <token>" (or ?token=) when
+--token or SQUADRON_API_TOKEN is set. On SIGINT/SIGTERM, runs in progress are stopped.
+
+Once the store has users (see 'squadron users') or the config has an api oidc block,
+the API runs in multi-user mode: requests authenticate with a user's API key or OIDC
+login session, are authorized by the user's role (viewer, operator, admin), and
+operators may only stop the runs they started. The --token still works, as an admin.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := applyHome(apiConfigPath); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := EnsureInitialized(false); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		cfg, err := config.LoadAndValidate(apiConfigPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+		stores, err := store.NewBundle(cfg.Storage)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error opening store: %v\n", err)
+			os.Exit(1)
+		}
+		defer stores.Close()
+
+		token := apiToken
+		if token == "" {
+			token = os.Getenv("SQUADRON_API_TOKEN")
+		}
+
+		// Multi-user mode once there is a user, or users can log in
+		var users store.UserStore
+		var oidc *config.OIDCConfig
+		if cfg.API != nil {
+			oidc = cfg.API.OIDC
+		}
+		existing, err := stores.Users.ListUsers()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error listing users: %v\n", err)
+			os.Exit(1)
+		}
+		if len(existing) > 0 || oidc != nil {
+			users = stores.Users
+		}
+		if token == "" && users == nil {
+			fmt.Fprintln(os.Stderr, "Warning: no --token, SQUADRON_API_TOKEN or users set; the API is unauthenticated")
+		}
+
+		srv := api.NewServer(api.Options{
+			Config:     cfg,
+			ConfigPath: apiConfigPath,
+			Stores:     stores,
+			Token:      token,
+			Users:      users,
+			OIDC:       oidc,
+		})
+		httpSrv := &http.Server{
+			Addr:              fmt.Sprintf(":%d", apiPort),
+			Handler:           srv,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+
+		sigs := make(chan os.Signal, 1)
+		signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			<-sigs
+			fmt.Println("\nShutting down...")
+			// SSE streams stay open until their run finishes, so stop runs
+			// before waiting on connections
+			srv.Shutdown()
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			httpSrv.Shutdown(ctx)
+		}()
+
+		fmt.Printf("Squadron API listening on :%d\n", apiPort)
+		if users != nil {
+			fmt.Printf("Multi-user mode: %d users", len(existing))
+			if oidc != nil {
+				fmt.Printf(", OIDC login at /auth/login")
+			}
+			fmt.Println()
+		}
+		if err := httpSrv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		<-done
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(apiCmd)
+	apiCmd.Flags().StringVarP(&apiConfigPath, "config", "c", ".", "Path to config file or directory")
+	apiCmd.Flags().IntVarP(&apiPort, "port", "p", 8090, "Port to listen on")
+	apiCmd.Flags().StringVar(&apiToken, "token", "", "Bearer token required on every request (default $SQUADRON_API_TOKEN)")
+}
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"squadron/mission"
+)
+
+// terminalApprover prompts on the terminal for tasks with require_approval.
+// Prompts are serialized so parallel tasks don't interleave their drafts.
+type terminalApprover struct {
+	in  io.Reader
+	out io.Writer
+
+	mu        sync.Mutex
+	startOnce sync.Once
+	lines     chan string
+}
+
+func newTerminalApprover(in io.Reader, out io.Writer) *terminalApprover {
+	return &terminalApprover{in: in, out: out}
+}
+
+// readLine waits for the next input line. Stdin reads can't be interrupted,
+// so a single goroutine feeds lines and callers give up on ctx.
+func (a *terminalApprover) readLine(ctx context.Context) (string, error) {
+	a.startOnce.Do(func() {
+		a.lines = make(chan string)
+		go func() {
+			scanner := bufio.NewScanner(a.in)
+			for scanner.Scan() {
+				a.lines <- scanner.Text()
+			}
+			close(a.lines)
+		}()
+	})
+	select {
+	case line, ok := <-a.lines:
+		if !ok {
+			return "", io.EOF
+		}
+		return line, nil
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+func (a *terminalApprover) RequestApproval(ctx context.Context, req mission.ApprovalRequest) (mission.ApprovalDecision, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	fmt.Fprintf(a.out, "\n=== Approval required: task '%s' ===\n", req.TaskName)
+	if req.Summary != "" {
+		fmt.Fprintf(a.out, "\nSummary:\n%s\n", req.Summary)
+	}
+	if len(req.Output) > 0 {
+		outputJSON, _ := json.MarshalIndent(req.Output, "", "  ")
+		fmt.Fprintf(a.out, "\nOutput:\n%s\n", outputJSON)
+	}
+
+	for {
+		fmt.Fprint(a.out, "\n[a]pprove, [e]dit, or [r]eject? ")
+		choice, err := a.readLine(ctx)
+		if err != nil {
+			return mission.ApprovalDecision{}, err
+		}
+		switch strings.ToLower(strings.TrimSpace(choice)) {
+		case "a", "approve":
+			return mission.ApprovalDecision{Approved: true, Summary: req.Summary, Output: req.Output}, nil
+		case "r", "reject":
+			fmt.Fprint(a.out, "Reason (optional): ")
+			reason, err := a.readLine(ctx)
+			if err != nil {
+				return mission.ApprovalDecision{}, err
+			}
+			return mission.ApprovalDecision{Reason: strings.TrimSpace(reason)}, nil
+		case "e", "edit":
+			return a.readEdit(ctx, req)
+		}
+	}
+}
+
+// readEdit reads a replacement up to the first empty line. A JSON object
+// replaces the output; anything else replaces the summary.
+func (a *terminalApprover) readEdit(ctx context.Context, req mission.ApprovalRequest) (mission.ApprovalDecision, error) {
+	fmt.Fprintln(a.out, "Enter the new summary, or the new output as a JSON object. Finish with an empty line:")
+	var lines []string
+	for {
+		line, err := a.readLine(ctx)
+		if err != nil && err != io.EOF {
+			return mission.ApprovalDecision{}, err
+		}
+		if err == io.EOF || line == "" {
+			break
+		}
+		lines = append(lines, line)
+	}
+	edited := strings.TrimSpace(strings.Join(lines, "\n"))
+	if edited == "" {
+		return mission.ApprovalDecision{Approved: true, Summary: req.Summary, Output: req.Output}, nil
+	}
+
+	var output map[string]any
+	if strings.HasPrefix(edited, "{") && json.Unmarshal([]byte(edited), &output) == nil {
+		return mission.ApprovalDecision{Approved: true, Summary: req.Summary, Output: output}, nil
+	}
+	return mission.ApprovalDecision{Approved: true, Summary: edited, Output: req.Output}, nil
+}
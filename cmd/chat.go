@@ -12,6 +12,7 @@ import (
 	"squadron/config"
 	"squadron/streamers/cli"
 	"squadron/mission"
+	"squadron/store"
 
 	"github.com/spf13/cobra"
 )
@@ -21,12 +22,19 @@ var debugMode bool
 var missionMode bool
 var missionTask string
 var chatAutoInit bool
+var chatAgents []string
+var chatCommander string
+var chatPromote string
+var chatPromoteName string
 
 var chatCmd = &cobra.Command{
 	Use:   "chat [agent_name]",
-	Short: "Chat with a given agent",
-	Long:  `Start an interactive chat session with the specified agent.`,
-	Args:  cobra.ExactArgs(1),
+	Short: "Chat with a given agent, or with a commander over several agents",
+	Long: `Start an interactive chat session with the specified agent.
+
+With --agents, chat with a commander instead: it calls the listed agents to handle each
+request. The chat is stored, and --promote <chat_id> later prints it as a mission.`,
+	Args: cobra.RangeArgs(0, 1),
 	Run: func(cmd *cobra.Command, args []string) {
 		if err := applyHome(configPath); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
@@ -37,6 +45,22 @@ var chatCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
+		switch {
+		case chatPromote != "":
+			promoteChat()
+			return
+		case len(chatAgents) > 0:
+			if len(args) > 0 {
+				fmt.Fprintln(os.Stderr, "Error: pass an agent name or --agents, not both")
+				os.Exit(1)
+			}
+			commanderChat()
+			return
+		case len(args) == 0:
+			fmt.Fprintln(os.Stderr, "Error: pass an agent name, --agents, or --promote")
+			os.Exit(1)
+		}
+
 		agentName := args[0]
 		ctx := context.Background()
 
@@ -65,8 +89,9 @@ var chatCmd = &cobra.Command{
 
 		if debugLogger.IsEnabled() {
 			fmt.Printf("Debug mode enabled. Writing to: %s\n", debugLogger.GetDebugDir())
-			opts.DebugFile = debugLogger.GetMessageFile("agent", agentName)
-			opts.TurnLogFile = debugLogger.GetTurnLogFile("agent", agentName)
+			scope := agent.DebugScope{Task: "chat"}
+			opts.DebugFile = debugLogger.GetMessageFile(scope, "agent", agentName)
+			opts.TurnLogFile = debugLogger.GetTurnLogFile(scope, "agent", agentName)
 			opts.EventLogger = debugLogger
 		}
 
@@ -117,6 +142,92 @@ var chatCmd = &cobra.Command{
 	},
 }
 
+// commanderChat runs an interactive chat with a commander over chatAgents.
+func commanderChat() {
+	ctx := context.Background()
+	cfg, err := config.LoadAndValidate(configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+
+	var debugDir string
+	if debugMode {
+		debugDir = filepath.Join("debug", fmt.Sprintf("chat_commander_%s", time.Now().Format("20060102_150405")))
+	}
+	debugLogger, err := mission.NewDebugLogger(debugDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating debug logger: %v\n", err)
+		os.Exit(1)
+	}
+	defer debugLogger.Close()
+	opts := mission.ChatOptions{
+		Config:     cfg,
+		ConfigPath: configPath,
+		Agents:     chatAgents,
+		Commander:  chatCommander,
+	}
+	if debugLogger.IsEnabled() {
+		fmt.Printf("Debug mode enabled. Writing to: %s\n", debugLogger.GetDebugDir())
+		opts.DebugLogger = debugLogger
+	}
+
+	streamer := cli.NewCommanderChatHandler()
+	chat, err := mission.NewChat(ctx, opts, streamer)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	defer chat.Close()
+	streamer.Welcome(chatAgents, chat.Model(), chat.ID())
+
+	for {
+		input, err := streamer.AwaitClientAnswer()
+		if err != nil {
+			if err == io.EOF {
+				streamer.Goodbye()
+				break
+			}
+			streamer.Error(err)
+			break
+		}
+		if input == "" {
+			continue
+		}
+		if input == "exit" || input == "quit" {
+			streamer.Goodbye()
+			break
+		}
+
+		streamer.Thinking()
+		if _, err := chat.Send(ctx, input, streamer); err != nil {
+			streamer.Error(err)
+		}
+	}
+	fmt.Printf("Promote this chat to a mission with: squadron chat --promote %s\n", chat.ID())
+}
+
+// promoteChat prints the stored chat chatPromote as a mission definition.
+func promoteChat() {
+	cfg, err := config.LoadAndValidate(configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+	stores, err := store.NewBundle(cfg.Storage)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error opening store: %v\n", err)
+		os.Exit(1)
+	}
+	defer stores.Close()
+	hcl, err := mission.PromoteChat(cfg, stores, chatPromote, chatPromoteName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	os.Stdout.Write(hcl)
+}
+
 func init() {
 	rootCmd.AddCommand(chatCmd)
 	chatCmd.Flags().StringVarP(&configPath, "config", "c", ".", "Path to config file or directory")
@@ -124,4 +235,8 @@ func init() {
 	chatCmd.Flags().BoolVarP(&missionMode, "mission", "w", false, "Run in mission mode (non-interactive)")
 	chatCmd.Flags().StringVarP(&missionTask, "task", "t", "", "Task to run in mission mode (requires --mission)")
 	chatCmd.Flags().BoolVar(&chatAutoInit, "init", false, "Auto-initialize Squadron if not already initialized")
+	chatCmd.Flags().StringSliceVar(&chatAgents, "agents", nil, "Chat with a commander that can call these agents (comma-separated)")
+	chatCmd.Flags().StringVar(&chatCommander, "commander", "", "Model for the commander (with --agents; default: the first agent's model)")
+	chatCmd.Flags().StringVar(&chatPromote, "promote", "", "Print the stored commander chat with this ID as a mission")
+	chatCmd.Flags().StringVar(&chatPromoteName, "name", "promoted_chat", "Name of the mission printed by --promote")
 }
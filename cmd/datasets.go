@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"squadron/config"
+	"squadron/store"
+
+	"github.com/spf13/cobra"
+)
+
+var datasetsConfigPath string
+var datasetsExportFormat string
+var datasetsExportOutput string
+
+var datasetsCmd = &cobra.Command{
+	Use:   "datasets",
+	Short: "Work with the datasets of past mission runs",
+}
+
+var datasetsExportCmd = &cobra.Command{
+	Use:   "export [mission_id] [dataset]",
+	Short: "Export a mission run's dataset as JSON, CSV, or JSONL",
+	Long: `Export the items a mission run stored in one of its datasets, including
+items added at runtime by result_to_dataset or set_dataset. Writes to stdout
+unless --output is given.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := config.ValidateDatasetExportFormat(datasetsExportFormat); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		cfg, stores := loadConfigAndStores(datasetsConfigPath)
+		defer stores.Close()
+
+		schema, items, err := loadDatasetItems(cfg, stores, args[0], args[1])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if datasetsExportOutput == "" {
+			err = config.WriteDatasetItems(os.Stdout, datasetsExportFormat, schema, items)
+		} else {
+			export := &config.DatasetExport{Path: datasetsExportOutput, Format: datasetsExportFormat}
+			err = export.WriteFile(schema, items)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error exporting dataset: %v\n", err)
+			os.Exit(1)
+		}
+		if datasetsExportOutput != "" {
+			fmt.Fprintf(os.Stderr, "Exported %d items to %s\n", len(items), datasetsExportOutput)
+		}
+	},
+}
+
+// loadDatasetItems reads every item of a mission run's dataset. The schema
+// comes from the current config when the mission is still defined there and
+// only orders CSV columns.
+func loadDatasetItems(cfg *config.Config, stores *store.Bundle, missionID, name string) (*config.InputsSchema, []any, error) {
+	record, err := stores.Missions.GetMission(missionID)
+	if err != nil {
+		return nil, nil, err
+	}
+	dsID, err := stores.Datasets.GetDatasetByName(missionID, name)
+	if err != nil {
+		return nil, nil, fmt.Errorf("dataset '%s' not found in mission '%s'", name, missionID)
+	}
+	count, err := stores.Datasets.GetItemCount(dsID)
+	if err != nil {
+		return nil, nil, err
+	}
+	raw, err := stores.Datasets.GetItemsRaw(dsID, 0, count)
+	if err != nil {
+		return nil, nil, err
+	}
+	items := make([]any, len(raw))
+	for i, r := range raw {
+		if err := json.Unmarshal([]byte(r), &items[i]); err != nil {
+			return nil, nil, fmt.Errorf("item %d: %w", i, err)
+		}
+	}
+
+	var schema *config.InputsSchema
+	for _, m := range cfg.Missions {
+		if m.Name != record.MissionName {
+			continue
+		}
+		for _, ds := range m.Datasets {
+			if ds.Name == name {
+				schema = ds.Schema
+			}
+		}
+	}
+	return schema, items, nil
+}
+
+func init() {
+	rootCmd.AddCommand(datasetsCmd)
+	datasetsCmd.AddCommand(datasetsExportCmd)
+	datasetsCmd.PersistentFlags().StringVarP(&datasetsConfigPath, "config", "c", ".", "Path to config file or directory")
+	datasetsExportCmd.Flags().StringVarP(&datasetsExportFormat, "format", "f", config.DatasetExportJSON, "Output format: json, csv, or jsonl")
+	datasetsExportCmd.Flags().StringVarP(&datasetsExportOutput, "output", "o", "", "Write to this file instead of stdout")
+}
@@ -391,6 +391,7 @@ func runEngage(cmd *cobra.Command, args []string) {
 	}()
 
 	sched := scheduler.New(client.RunScheduledMission)
+	sched.SetLocker(stores.Schedules)
 	client.SetConcurrencyTracker(sched)
 	if cfgErr == nil {
 		sched.UpdateConfig(cfg)
@@ -984,7 +985,7 @@ func openBrowser(url string) {
 }
 
 // runScratchpadCleanupLoop periodically sweeps expired per-run scratchpad
-// directories. The sweep walks the entire scratchpads tree, so it doesn't
+// and workspace directories. The sweep walks both trees, so it doesn't
 // need to know which missions are configured. It runs once immediately,
 // then hourly, and exits when shutdown is closed.
 func runScratchpadCleanupLoop(shutdown <-chan struct{}) {
@@ -0,0 +1,325 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"squadron/config"
+	"squadron/store"
+
+	"github.com/spf13/cobra"
+)
+
+var graphConfigPath string
+var graphFormat string
+
+var graphCmd = &cobra.Command{
+	Use:   "graph [mission_name] [mission_id]",
+	Short: "Export a mission's task graph as DOT or Mermaid",
+	Long: `Print the task dependency graph of a mission as Graphviz DOT or a Mermaid flowchart. Each task
+shows the agents available to it and, for iterated tasks, the dataset it iterates over. Router and
+send_to edges are dashed. Given the ID of a run of the mission, tasks also show their status and
+duration in that run, and the routes the run took are drawn bold.`,
+	Args: cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		if graphFormat != "dot" && graphFormat != "mermaid" {
+			fmt.Fprintf(os.Stderr, "Error: unknown format '%s' (expected dot or mermaid)\n", graphFormat)
+			os.Exit(1)
+		}
+		if err := applyHome(graphConfigPath); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		cfg, err := config.LoadAndValidate(graphConfigPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+		var m *config.Mission
+		for i := range cfg.Missions {
+			if cfg.Missions[i].Name == args[0] {
+				m = &cfg.Missions[i]
+			}
+		}
+		if m == nil {
+			fmt.Fprintf(os.Stderr, "Error: mission '%s' not found\n", args[0])
+			os.Exit(1)
+		}
+
+		var run *graphRun
+		if len(args) == 2 {
+			if err := EnsureInitialized(false); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			run, err = loadGraphRun(cfg, m.Name, args[1])
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		g := buildMissionGraph(m, cfg.Agents, run)
+		if graphFormat == "mermaid" {
+			writeMermaidGraph(os.Stdout, g)
+		} else {
+			writeDOTGraph(os.Stdout, g)
+		}
+	},
+}
+
+// graphRun is what the store recorded about one run of the mission.
+type graphRun struct {
+	Tasks  []store.MissionTask
+	Routes []store.RouteDecision
+	Now    time.Time // for the duration of tasks still running
+}
+
+func loadGraphRun(cfg *config.Config, missionName, missionID string) (*graphRun, error) {
+	stores, err := store.NewBundle(cfg.Storage)
+	if err != nil {
+		return nil, fmt.Errorf("opening store: %w", err)
+	}
+	defer stores.Close()
+
+	rec, err := stores.Missions.GetMission(missionID)
+	if err != nil {
+		return nil, fmt.Errorf("mission %s not found: %w", missionID, err)
+	}
+	if rec.MissionName != missionName {
+		return nil, fmt.Errorf("mission %s is a run of '%s', not '%s'", missionID, rec.MissionName, missionName)
+	}
+	tasks, err := stores.Missions.GetTasksByMission(missionID)
+	if err != nil {
+		return nil, fmt.Errorf("loading tasks: %w", err)
+	}
+	routes, err := stores.Missions.GetRouteDecisions(missionID)
+	if err != nil {
+		return nil, fmt.Errorf("loading route decisions: %w", err)
+	}
+	return &graphRun{Tasks: tasks, Routes: routes, Now: time.Now()}, nil
+}
+
+// graphNode is a task, or a mission a router can hand off to.
+type graphNode struct {
+	Name      string
+	IsMission bool
+	Agents    []string
+	Dataset   string // iterated tasks only
+	Parallel  bool
+	Status    string // only with a run; "" if the task never started
+	Duration  time.Duration
+}
+
+// graphEdge is a dependency (From must finish before To) or a route.
+type graphEdge struct {
+	From, To string
+	Routed   bool   // router or send_to rather than depends_on
+	Label    string // router condition
+	Taken    bool   // the run took this route (router or send_to)
+}
+
+type missionGraph struct {
+	Name  string
+	Nodes []graphNode
+	Edges []graphEdge
+}
+
+// buildMissionGraph lays out the mission's tasks in config order, followed
+// by any missions its routers hand off to. agents are the global agents,
+// used to resolve tasks that pick agents with requires. run may be nil.
+func buildMissionGraph(m *config.Mission, agents []config.Agent, run *graphRun) missionGraph {
+	g := missionGraph{Name: m.Name}
+
+	records := map[string]store.MissionTask{}
+	taken := map[[2]string]bool{}
+	if run != nil {
+		// A resumed run can record a task again; the latest record wins.
+		for _, t := range run.Tasks {
+			records[t.TaskName] = t
+		}
+		for _, r := range run.Routes {
+			taken[[2]string{r.RouterTask, r.TargetTask}] = true
+		}
+	}
+
+	var missionTargets []string
+	for _, t := range m.Tasks {
+		node := graphNode{Name: t.Name}
+		node.Agents, _ = m.TaskAgents(&t, agents)
+		if t.Iterator != nil {
+			node.Dataset = t.Iterator.Dataset
+			node.Parallel = t.Iterator.Parallel
+		}
+		if rec, ok := records[t.Name]; ok {
+			node.Status = rec.Status
+			if rec.StartedAt != nil {
+				end := run.Now
+				if rec.FinishedAt != nil {
+					end = *rec.FinishedAt
+				}
+				node.Duration = end.Sub(*rec.StartedAt)
+			}
+		}
+		g.Nodes = append(g.Nodes, node)
+
+		for _, dep := range t.DependsOn {
+			g.Edges = append(g.Edges, graphEdge{From: dep, To: t.Name})
+		}
+		for _, target := range t.SendTo {
+			g.Edges = append(g.Edges, graphEdge{From: t.Name, To: target, Routed: true, Taken: taken[[2]string{t.Name, target}]})
+		}
+		if t.Router != nil {
+			for _, route := range t.Router.Routes {
+				g.Edges = append(g.Edges, graphEdge{From: t.Name, To: route.Target, Routed: true, Label: route.Condition, Taken: taken[[2]string{t.Name, route.Target}]})
+				if route.IsMission && !slices.Contains(missionTargets, route.Target) {
+					missionTargets = append(missionTargets, route.Target)
+				}
+			}
+		}
+	}
+	for _, name := range missionTargets {
+		g.Nodes = append(g.Nodes, graphNode{Name: name, IsMission: true})
+	}
+	return g
+}
+
+// labelLines are the lines of a node's label.
+func (n graphNode) labelLines() []string {
+	if n.IsMission {
+		return []string{"mission " + n.Name}
+	}
+	lines := []string{n.Name}
+	if len(n.Agents) > 0 {
+		lines = append(lines, "agents: "+strings.Join(n.Agents, ", "))
+	}
+	if n.Dataset != "" {
+		mode := "sequential"
+		if n.Parallel {
+			mode = "parallel"
+		}
+		lines = append(lines, fmt.Sprintf("iterates %s (%s)", n.Dataset, mode))
+	}
+	if n.Status != "" {
+		status := n.Status
+		if n.Duration > 0 && n.Status == "running" {
+			status += " for " + n.Duration.Round(time.Second).String()
+		} else if n.Duration > 0 {
+			status += " in " + n.Duration.Round(time.Second).String()
+		}
+		lines = append(lines, status)
+	}
+	return lines
+}
+
+// graphStatusColors are the fill colors of tasks by run status.
+var graphStatusColors = map[string]string{
+	"completed": "#c8e6c9",
+	"failed":    "#ffcdd2",
+	"running":   "#fff9c4",
+	"stopped":   "#ffe0b2",
+	"skipped":   "#eeeeee",
+	"cancelled": "#eeeeee",
+}
+
+func writeDOTGraph(w io.Writer, g missionGraph) {
+	escape := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace
+	quote := func(s string) string { return `"` + escape(s) + `"` }
+
+	fmt.Fprintf(w, "digraph %s {\n", quote(g.Name))
+	fmt.Fprintln(w, "  rankdir=LR;")
+	fmt.Fprintln(w, `  node [shape=box, style="rounded", fontname="Helvetica"];`)
+	for _, n := range g.Nodes {
+		lines := n.labelLines()
+		for i := range lines {
+			lines[i] = escape(lines[i])
+		}
+		attrs := []string{`label="` + strings.Join(lines, `\n`) + `"`}
+		if n.IsMission {
+			attrs = append(attrs, "shape=doubleoctagon")
+		} else if color, ok := graphStatusColors[n.Status]; ok {
+			attrs = append(attrs, `style="rounded,filled"`, `fillcolor="`+color+`"`)
+		}
+		fmt.Fprintf(w, "  %s [%s];\n", quote(n.Name), strings.Join(attrs, ", "))
+	}
+	for _, e := range g.Edges {
+		var attrs []string
+		if e.Routed {
+			attrs = append(attrs, "style=dashed")
+		}
+		if e.Label != "" {
+			attrs = append(attrs, "label="+quote(e.Label))
+		}
+		if e.Taken {
+			attrs = append(attrs, "penwidth=2.5")
+		}
+		if len(attrs) > 0 {
+			fmt.Fprintf(w, "  %s -> %s [%s];\n", quote(e.From), quote(e.To), strings.Join(attrs, ", "))
+		} else {
+			fmt.Fprintf(w, "  %s -> %s;\n", quote(e.From), quote(e.To))
+		}
+	}
+	fmt.Fprintln(w, "}")
+}
+
+func writeMermaidGraph(w io.Writer, g missionGraph) {
+	// Task names like fetch["us"] aren't valid Mermaid IDs, so nodes get
+	// positional IDs and the name goes in the label.
+	ids := make(map[string]string, len(g.Nodes))
+	for i, n := range g.Nodes {
+		ids[n.Name] = fmt.Sprintf("n%d", i)
+	}
+	text := func(s string) string {
+		return strings.ReplaceAll(s, `"`, "#quot;")
+	}
+
+	fmt.Fprintln(w, "flowchart LR")
+	statuses := map[string][]string{}
+	for _, n := range g.Nodes {
+		lines := n.labelLines()
+		for i := range lines {
+			lines[i] = text(lines[i])
+		}
+		label := strings.Join(lines, "<br/>")
+		if n.IsMission {
+			fmt.Fprintf(w, "  %s{{\"%s\"}}\n", ids[n.Name], label)
+		} else {
+			fmt.Fprintf(w, "  %s(\"%s\")\n", ids[n.Name], label)
+		}
+		if _, ok := graphStatusColors[n.Status]; ok {
+			statuses[n.Status] = append(statuses[n.Status], ids[n.Name])
+		}
+	}
+	for _, e := range g.Edges {
+		arrow := "-->"
+		switch {
+		case e.Taken:
+			arrow = "==>"
+		case e.Routed:
+			arrow = "-.->"
+		}
+		if e.Label != "" {
+			fmt.Fprintf(w, "  %s %s|\"%s\"| %s\n", ids[e.From], arrow, text(e.Label), ids[e.To])
+		} else {
+			fmt.Fprintf(w, "  %s %s %s\n", ids[e.From], arrow, ids[e.To])
+		}
+	}
+	for _, status := range []string{"completed", "failed", "running", "stopped", "skipped", "cancelled"} {
+		if len(statuses[status]) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "  classDef %s fill:%s\n", status, graphStatusColors[status])
+		fmt.Fprintf(w, "  class %s %s\n", strings.Join(statuses[status], ","), status)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(graphCmd)
+	graphCmd.Flags().StringVarP(&graphConfigPath, "config", "c", ".", "Path to config file or directory")
+	graphCmd.Flags().StringVarP(&graphFormat, "format", "f", "dot", "Output format: dot or mermaid")
+}
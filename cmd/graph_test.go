@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"squadron/config"
+	"squadron/store"
+)
+
+func graphTestMission() *config.Mission {
+	return &config.Mission{
+		Name:   "leads",
+		Agents: []string{"researcher"},
+		Tasks: []config.Task{
+			{Name: "fetch", Iterator: &config.TaskIterator{Dataset: "cities", Parallel: true}},
+			{Name: "classify", DependsOn: []string{"fetch"}, Agents: []string{"analyst", "writer"}, Router: &config.TaskRouter{Routes: []config.TaskRoute{
+				{Target: "hot", Condition: `Lead is "hot"`},
+				{Target: "archive", Condition: "Nothing to follow up", IsMission: true},
+			}}},
+			{Name: "hot", SendTo: []string{`notify["slack"]`}},
+			{Name: `notify["slack"]`},
+		},
+	}
+}
+
+func TestBuildMissionGraph(t *testing.T) {
+	start := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	end := start.Add(90 * time.Second)
+	run := &graphRun{
+		Tasks: []store.MissionTask{
+			{TaskName: "fetch", Status: "failed", StartedAt: &start, FinishedAt: &start},
+			{TaskName: "fetch", Status: "completed", StartedAt: &start, FinishedAt: &end},
+			{TaskName: "classify", Status: "running", StartedAt: &end},
+		},
+		Routes: []store.RouteDecision{{RouterTask: "classify", TargetTask: "hot"}},
+		Now:    end.Add(time.Minute),
+	}
+
+	g := buildMissionGraph(graphTestMission(), nil, run)
+
+	if len(g.Nodes) != 5 || !g.Nodes[4].IsMission || g.Nodes[4].Name != "archive" {
+		t.Fatalf("expected the four tasks then the archive mission, got %+v", g.Nodes)
+	}
+	if n := g.Nodes[0]; n.Status != "completed" || n.Duration != 90*time.Second || strings.Join(n.Agents, ",") != "researcher" {
+		t.Fatalf("expected fetch's latest record and the mission's agents, got %+v", n)
+	}
+	if n := g.Nodes[1]; n.Status != "running" || n.Duration != time.Minute || strings.Join(n.Agents, ",") != "analyst,writer" {
+		t.Fatalf("expected classify running for a minute with its own agents, got %+v", n)
+	}
+	if n := g.Nodes[2]; n.Status != "" || n.Duration != 0 {
+		t.Fatalf("expected no status for a task that never ran, got %+v", n)
+	}
+
+	want := []graphEdge{
+		{From: "fetch", To: "classify"},
+		{From: "classify", To: "hot", Routed: true, Label: `Lead is "hot"`, Taken: true},
+		{From: "classify", To: "archive", Routed: true, Label: "Nothing to follow up"},
+		{From: "hot", To: `notify["slack"]`, Routed: true},
+	}
+	if len(g.Edges) != len(want) {
+		t.Fatalf("expected %d edges, got %+v", len(want), g.Edges)
+	}
+	for i := range want {
+		if g.Edges[i] != want[i] {
+			t.Errorf("edge %d = %+v, want %+v", i, g.Edges[i], want[i])
+		}
+	}
+}
+
+func TestWriteDOTGraph(t *testing.T) {
+	var buf bytes.Buffer
+	writeDOTGraph(&buf, buildMissionGraph(graphTestMission(), nil, &graphRun{
+		Tasks:  []store.MissionTask{{TaskName: "fetch", Status: "completed"}},
+		Routes: []store.RouteDecision{{RouterTask: "classify", TargetTask: "hot"}},
+	}))
+	out := buf.String()
+
+	for _, want := range []string{
+		`digraph "leads" {`,
+		`"fetch" [label="fetch\nagents: researcher\niterates cities (parallel)\ncompleted", style="rounded,filled", fillcolor="#c8e6c9"];`,
+		`"notify[\"slack\"]" [label="notify[\"slack\"]\nagents: researcher"];`,
+		`"archive" [label="mission archive", shape=doubleoctagon];`,
+		`"fetch" -> "classify";`,
+		`"classify" -> "hot" [style=dashed, label="Lead is \"hot\"", penwidth=2.5];`,
+		`"hot" -> "notify[\"slack\"]" [style=dashed];`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("DOT output missing %s:\n%s", want, out)
+		}
+	}
+}
+
+func TestWriteMermaidGraph(t *testing.T) {
+	var buf bytes.Buffer
+	writeMermaidGraph(&buf, buildMissionGraph(graphTestMission(), nil, &graphRun{
+		Tasks:  []store.MissionTask{{TaskName: "fetch", Status: "completed"}},
+		Routes: []store.RouteDecision{{RouterTask: "classify", TargetTask: "hot"}},
+	}))
+	out := buf.String()
+
+	for _, want := range []string{
+		"flowchart LR\n",
+		`n0("fetch<br/>agents: researcher<br/>iterates cities (parallel)<br/>completed")`,
+		`n3("notify[#quot;slack#quot;]<br/>agents: researcher")`,
+		`n4{{"mission archive"}}`,
+		"n0 --> n1\n",
+		`n1 ==>|"Lead is #quot;hot#quot;"| n2`,
+		`n1 -.->|"Nothing to follow up"| n4`,
+		"n2 -.-> n3\n",
+		"classDef completed fill:#c8e6c9\n  class n0 completed\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Mermaid output missing %s:\n%s", want, out)
+		}
+	}
+}
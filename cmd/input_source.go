@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"squadron/config"
+)
+
+// readInputSources replaces --input values that point elsewhere with what
+// they point to: key=@path reads a file, key=- reads stdin, and key=@@text
+// passes "@text" literally. A list input read this way may be a JSON array,
+// JSON Lines, or CSV with a header row; the latter two are converted to a
+// JSON array. m may be nil when the mission isn't known.
+func readInputSources(m *config.Mission, inputs map[string]string, stdin io.Reader) error {
+	types := make(map[string]*config.MissionInput)
+	if m != nil {
+		for i := range m.Inputs {
+			types[m.Inputs[i].Name] = &m.Inputs[i]
+		}
+	}
+
+	stdinUsedBy := ""
+	for key, value := range inputs {
+		var (
+			data   []byte
+			source string
+			err    error
+		)
+		switch {
+		case strings.HasPrefix(value, "@@"):
+			inputs[key] = value[1:]
+			continue
+		case strings.HasPrefix(value, "@"):
+			source = value[1:]
+			if source == "" {
+				return fmt.Errorf("input '%s': expected a file path after '@'", key)
+			}
+			data, err = os.ReadFile(source)
+		case value == "-":
+			if stdinUsedBy != "" {
+				return fmt.Errorf("inputs '%s' and '%s' both read stdin; only one input can", stdinUsedBy, key)
+			}
+			stdinUsedBy, source = key, "stdin"
+			data, err = io.ReadAll(stdin)
+		default:
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("input '%s': %w", key, err)
+		}
+
+		if input := types[key]; input != nil && input.Type == config.InputTypeList {
+			list, err := listInputJSON(data, input.Items, filepath.Ext(source))
+			if err != nil {
+				return fmt.Errorf("input '%s' (%s): %w", key, source, err)
+			}
+			inputs[key] = list
+			continue
+		}
+		inputs[key] = strings.TrimRight(string(data), "\r\n")
+	}
+	return nil
+}
+
+// listInputJSON converts the content of a list input's file to a JSON
+// array. JSON arrays pass through; .jsonl files, or content whose lines are
+// all JSON objects, are JSON Lines; anything else is CSV with a header row.
+// CSV rows become objects keyed by column, except that a single-column CSV
+// for a list of scalars becomes a list of the column's values.
+func listInputJSON(data []byte, items *config.MissionInput, ext string) (string, error) {
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return "[]", nil
+	}
+	if trimmed[0] == '[' {
+		return string(trimmed), nil
+	}
+
+	var list []any
+	if strings.EqualFold(ext, ".jsonl") || isJSONLines(trimmed) {
+		for i, line := range bytes.Split(trimmed, []byte("\n")) {
+			line = bytes.TrimSpace(line)
+			if len(line) == 0 {
+				continue
+			}
+			var item any
+			if err := json.Unmarshal(line, &item); err != nil {
+				return "", fmt.Errorf("line %d: invalid JSON: %w", i+1, err)
+			}
+			list = append(list, item)
+		}
+	} else {
+		records, err := csv.NewReader(bytes.NewReader(trimmed)).ReadAll()
+		if err != nil {
+			return "", fmt.Errorf("reading csv: %w", err)
+		}
+		header := records[0]
+		for i := range header {
+			header[i] = strings.TrimSpace(header[i])
+		}
+		scalars := len(header) == 1 && items != nil && isScalarInputType(items.Type)
+		for _, record := range records[1:] {
+			if scalars {
+				list = append(list, record[0])
+				continue
+			}
+			row := make(map[string]any, len(header))
+			for i, col := range header {
+				if i < len(record) {
+					row[col] = record[i]
+				}
+			}
+			list = append(list, row)
+		}
+	}
+
+	if list == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal(list)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// isJSONLines reports whether every non-blank line is a JSON object
+func isJSONLines(data []byte) bool {
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) > 0 && (line[0] != '{' || !json.Valid(line)) {
+			return false
+		}
+	}
+	return true
+}
+
+func isScalarInputType(t string) bool {
+	switch t {
+	case config.InputTypeString, config.InputTypeNumber, config.InputTypeInteger, config.InputTypeBool:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"squadron/config"
+)
+
+func TestReadInputSources(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	m := &config.Mission{Inputs: []config.MissionInput{
+		{Name: "leads", Type: config.InputTypeList, Items: &config.MissionInput{Type: config.InputTypeObject}},
+		{Name: "events", Type: config.InputTypeList},
+		{Name: "domains", Type: config.InputTypeList, Items: &config.MissionInput{Type: config.InputTypeString}},
+		{Name: "ids", Type: config.InputTypeList},
+		{Name: "query", Type: config.InputTypeString},
+		{Name: "brief", Type: config.InputTypeString},
+		{Name: "handle", Type: config.InputTypeString},
+	}}
+	inputs := map[string]string{
+		"leads":   "@" + write("leads.csv", "name,score\nAcme,3\n\"Globex, Inc\",5\n"),
+		"events":  "@" + write("events.jsonl", "{\"id\":1}\n\n{\"id\":2}\n"),
+		"domains": "@" + write("domains.csv", "domain\nacme.com\nglobex.com\n"),
+		"ids":     "@" + write("ids.json", "  [1, 2]\n"),
+		"query":   "-",
+		"brief":   "@" + write("brief.md", "# Brief\nKeep it short.\n"),
+		"handle":  "@@squadron",
+	}
+
+	if err := readInputSources(m, inputs, strings.NewReader("who is hiring?\n")); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"leads":   `[{"name":"Acme","score":"3"},{"name":"Globex, Inc","score":"5"}]`,
+		"events":  `[{"id":1},{"id":2}]`,
+		"domains": `["acme.com","globex.com"]`,
+		"ids":     `[1, 2]`,
+		"query":   "who is hiring?",
+		"brief":   "# Brief\nKeep it short.",
+		"handle":  "@squadron",
+	}
+	for key, w := range want {
+		if inputs[key] != w {
+			t.Errorf("%s = %q, want %q", key, inputs[key], w)
+		}
+	}
+}
+
+func TestReadInputSources_Errors(t *testing.T) {
+	cases := map[string]map[string]string{
+		"both read stdin":           {"a": "-", "b": "-"},
+		"expected a file path":      {"a": "@"},
+		"no such file or directory": {"a": "@" + filepath.Join(t.TempDir(), "missing.json")},
+	}
+	for want, inputs := range cases {
+		err := readInputSources(nil, inputs, strings.NewReader(""))
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("readInputSources(%v) = %v, want an error containing %q", inputs, err, want)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/spf13/cobra"
+
+	"squadron/config"
+	"squadron/mcphost"
+	"squadron/mission"
+	"squadron/streamers"
+)
+
+var mcpServeCmd = &cobra.Command{
+	Use:   "mcp-serve",
+	Short: "Serve missions as MCP tools over stdio",
+	Long: `Start an MCP server on stdin/stdout that exposes each configured mission as a tool.
+Mission inputs become the tool's input schema. A tool call runs the mission to completion
+and returns each task's status, summary and output; task progress is sent as MCP progress
+notifications. Point Claude Desktop or any other MCP client at "squadron mcp-serve -c <config>".
+
+Mission events are logged to stderr as NDJSON.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := applyHome(configPath); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		// No auto-init: it prints to stdout, which carries the MCP transport
+		if err := EnsureInitialized(false); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		cfg, err := config.LoadAndValidate(configPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+
+		srv := mcphost.NewMissionServer(mcphost.MissionDeps{
+			Config:   cfg,
+			Version:  Version,
+			EventLog: os.Stderr,
+			RunMission: func(ctx context.Context, name string, inputs map[string]string, handler streamers.MissionHandler) (*mcphost.MissionRun, error) {
+				return runMissionForMCP(ctx, cfg, name, inputs, handler)
+			},
+		})
+		if err := server.ServeStdio(srv); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// runMissionForMCP runs a mission to completion and reads back its results
+// before the runner's stores are closed.
+func runMissionForMCP(ctx context.Context, cfg *config.Config, name string, inputs map[string]string, handler streamers.MissionHandler) (*mcphost.MissionRun, error) {
+	runner, err := mission.NewRunner(cfg, configPath, name, inputs)
+	if err != nil {
+		return nil, err
+	}
+	defer runner.CloseStores()
+
+	streamer := streamers.NewStoringMissionHandler(handler, runner.EventStore(), runner.CostStore())
+	runErr := runner.Run(ctx, streamer)
+	if runner.MissionID() == "" {
+		return nil, runErr
+	}
+	run, err := mcphost.CollectMissionRun(runner.MissionStore(), runner.MissionID(), runErr)
+	if err != nil {
+		return nil, err
+	}
+	return run, runErr
+}
+
+func init() {
+	rootCmd.AddCommand(mcpServeCmd)
+	mcpServeCmd.Flags().StringVarP(&configPath, "config", "c", ".", "Path to config file or directory")
+}
@@ -2,29 +2,38 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"syscall"
 	"time"
 
 	"squadron/config"
 	"squadron/mission"
 	"squadron/streamers"
 	"squadron/streamers/cli"
+	"squadron/streamers/ndjson"
 
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 )
 
 var inputFlags []string
 var missionDebugMode bool
 var resumeMissionID string
+var priorMissionIDs []string
 var missionAutoInit bool
+var missionEventsPath string
+var missionDryRun bool
 
 var missionCmd = &cobra.Command{
 	Use:   "mission [mission_name]",
 	Short: "Run a mission",
-	Long:  `Execute a mission by name. The mission will run all tasks respecting their dependencies, executing independent tasks in parallel. Provide inputs with --input key=value flags.`,
+	Long:  `Execute a mission by name. The mission will run all tasks respecting their dependencies, executing independent tasks in parallel. Provide inputs with --input key=value flags or SQUADRON_INPUT_<name> environment variables; list, map, and object inputs take JSON. --input key=@file reads a value from a file and --input key=- from stdin; list inputs read this way may also be CSV or JSON Lines.`,
 	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		if err := applyHome(configPath); err != nil {
@@ -52,6 +61,29 @@ var missionCmd = &cobra.Command{
 			fmt.Fprintf(os.Stderr, "Error parsing inputs: %v\n", err)
 			os.Exit(1)
 		}
+		var m *config.Mission
+		for i := range cfg.Missions {
+			if cfg.Missions[i].Name == missionName {
+				m = &cfg.Missions[i]
+			}
+		}
+		if err := readInputSources(m, inputs, os.Stdin); err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading inputs: %v\n", err)
+			os.Exit(1)
+		}
+		if m != nil {
+			addInputsFromEnv(m, inputs, os.LookupEnv)
+		}
+
+		if missionDryRun {
+			plan, err := mission.BuildPlan(cfg, missionName, inputs)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			printMissionPlan(os.Stdout, plan)
+			return
+		}
 
 		// Create debug logger if debug mode is enabled
 		var debugDir string
@@ -66,7 +98,8 @@ var missionCmd = &cobra.Command{
 		defer debugLogger.Close()
 
 		if debugLogger.IsEnabled() {
-			fmt.Printf("Debug mode enabled. Writing to: %s\n", debugLogger.GetDebugDir())
+			// Keep stdout clean when it carries the NDJSON event stream
+			fmt.Fprintf(statusOutput(), "Debug mode enabled. Writing to: %s\n", debugLogger.GetDebugDir())
 		}
 
 		// Build runner options
@@ -75,6 +108,13 @@ var missionCmd = &cobra.Command{
 		if resumeMissionID != "" {
 			runnerOpts = append(runnerOpts, mission.WithResume(resumeMissionID))
 		}
+		if len(priorMissionIDs) > 0 {
+			runnerOpts = append(runnerOpts, mission.WithPriorMissions(priorMissionIDs...))
+		}
+		if term.IsTerminal(int(os.Stdin.Fd())) {
+			// Tasks with require_approval prompt here
+			runnerOpts = append(runnerOpts, mission.WithApprover(newTerminalApprover(os.Stdin, statusOutput())))
+		}
 
 		// Create mission runner
 		runner, err := mission.NewRunner(cfg, configPath, missionName, inputs, runnerOpts...)
@@ -83,20 +123,169 @@ var missionCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
-		// Create handler with event persistence
-		cliHandler := cli.NewMissionHandler()
-		streamer := streamers.NewStoringMissionHandler(cliHandler, runner.EventStore(), runner.CostStore())
+		// Pick the output handler: the TTY by default, NDJSON events alongside
+		// it with --events <file>, or NDJSON events alone with --events -
+		var output streamers.MissionHandler = cli.NewMissionHandler()
+		if missionEventsPath != "" {
+			eventsOut, closeEvents, err := openEventsOutput(missionEventsPath)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			defer closeEvents()
+			if missionEventsPath == "-" {
+				output = ndjson.NewMissionHandler(eventsOut)
+			} else {
+				output = streamers.NewMultiHandler(output, ndjson.NewMissionHandler(eventsOut))
+			}
+		}
+
+		// Wrap with event persistence
+		streamer := streamers.NewStoringMissionHandler(output, runner.EventStore(), runner.CostStore())
 
 		// Run the mission
-		err = runner.Run(ctx, streamer)
-		runner.CloseStores()
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "\nMission failed: %v\n", err)
-			os.Exit(1)
-		}
+		runUntilDone(ctx, runner, streamer, func(id string) string {
+			return fmt.Sprintf("squadron mission %s --resume %s", missionName, id)
+		})
 	},
 }
 
+// runUntilDone runs the mission and reports how it ended, exiting with 130
+// when it was cancelled and 1 when it failed. SIGTERM pauses the mission
+// instead of killing it, so it can be continued later. Ctrl+C cancels it,
+// giving running commanders a moment to summarize their work; a second
+// Ctrl+C exits. resumeCommand returns the command that continues the run.
+func runUntilDone(ctx context.Context, runner *mission.Runner, streamer streamers.MissionHandler, resumeCommand func(missionID string) string) {
+	sigs := make(chan os.Signal, 2)
+	signal.Notify(sigs, syscall.SIGTERM, os.Interrupt)
+	go func() {
+		if sig := <-sigs; sig == syscall.SIGTERM {
+			fmt.Fprintln(os.Stderr, "\nSIGTERM received — pausing mission...")
+			runner.Pause()
+			return
+		}
+		fmt.Fprintln(os.Stderr, "\nInterrupted — cancelling mission (Ctrl+C again to exit now)...")
+		runner.Cancel("interrupted")
+		<-sigs
+		os.Exit(130)
+	}()
+
+	err := runner.Run(ctx, streamer)
+	runner.CloseStores()
+	if errors.Is(err, mission.ErrMissionPaused) {
+		fmt.Fprintf(os.Stderr, "\nMission paused. Resume with: %s\n", resumeCommand(runner.MissionID()))
+		return
+	}
+	if errors.Is(err, mission.ErrMissionCancelled) {
+		fmt.Fprintf(os.Stderr, "\n%v. Resume with: %s\n", err, resumeCommand(runner.MissionID()))
+		os.Exit(130)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "\nMission failed: %v\n", err)
+		var breach *mission.BudgetBreach
+		if errors.As(err, &breach) {
+			printBudgetBreakdown(breach.Breakdown)
+		}
+		os.Exit(1)
+	}
+}
+
+// printMissionPlan writes the --dry-run execution plan.
+func printMissionPlan(out io.Writer, p *mission.Plan) {
+	maxTasks := p.MaxParallel
+	if p.MaxParallelTasks > 0 {
+		maxTasks = p.MaxParallelTasks
+	}
+	fmt.Fprintf(out, "Mission: %s (dry run, max %d tasks in parallel)\n", p.MissionName, maxTasks)
+
+	if len(p.Datasets) > 0 {
+		fmt.Fprintln(out, "\nDatasets:")
+		for _, ds := range p.Datasets {
+			if ds.Origin == "runtime" {
+				fmt.Fprintf(out, "  %s: filled at runtime\n", ds.Name)
+				continue
+			}
+			fmt.Fprintf(out, "  %s: %d items from %s\n", ds.Name, ds.Items, ds.Origin)
+		}
+	}
+
+	for i, group := range p.Groups {
+		fmt.Fprintf(out, "\nStep %d:\n", i+1)
+		for _, t := range group {
+			var notes []string
+			if t.Conditional {
+				notes = append(notes, "conditional")
+			}
+			if t.Dataset != "" {
+				count := fmt.Sprintf("%d", t.Iterations)
+				if t.RuntimeDataset {
+					count = "runtime-sized"
+				}
+				mode := "sequential"
+				if t.Parallel {
+					mode = fmt.Sprintf("parallel, %d at a time", t.ConcurrencyLimit)
+				}
+				notes = append(notes, fmt.Sprintf("iterates %s over %s, %s", count, t.Dataset, mode))
+			}
+			if len(t.DependsOn) > 0 {
+				notes = append(notes, "after "+strings.Join(t.DependsOn, ", "))
+			}
+			if t.When != "" {
+				notes = append(notes, "when "+t.When)
+			}
+			if len(t.Routes) > 0 {
+				notes = append(notes, "routes to "+strings.Join(t.Routes, ", "))
+			}
+			fmt.Fprintf(out, "  %s", t.Name)
+			if len(notes) > 0 {
+				fmt.Fprintf(out, " (%s)", strings.Join(notes, "; "))
+			}
+			fmt.Fprintln(out)
+			fmt.Fprintf(out, "    agents: %s\n", strings.Join(t.Agents, ", "))
+			if t.Objective != "" {
+				fmt.Fprintf(out, "    objective: %s\n", strings.ReplaceAll(strings.TrimSpace(t.Objective), "\n", "\n      "))
+			}
+			fmt.Fprintf(out, "    estimated LLM calls: %d\n", t.EstimatedLLMCalls)
+		}
+	}
+
+	fmt.Fprintf(out, "\nEstimated LLM calls: at least %d if every task runs\n", p.EstimatedLLMCalls)
+}
+
+// printBudgetBreakdown writes the per-task/entity spend captured when a
+// budget breach ended the mission.
+func printBudgetBreakdown(breakdown []mission.BudgetSpend) {
+	if len(breakdown) == 0 {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "\nSpend at breach:\n")
+	for _, sp := range breakdown {
+		fmt.Fprintf(os.Stderr, "  %-24s %-20s %8d tokens  %4d calls  $%.4f\n", sp.TaskName, sp.Entity, sp.Tokens, sp.LLMCalls, sp.Dollars)
+	}
+}
+
+// statusOutput returns where human-readable status lines go: stderr when
+// stdout carries the NDJSON event stream, stdout otherwise.
+func statusOutput() io.Writer {
+	if missionEventsPath == "-" {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+// openEventsOutput opens the destination for --events: "-" is stdout,
+// anything else is a file that is created or truncated.
+func openEventsOutput(path string) (io.Writer, func(), error) {
+	if path == "-" {
+		return os.Stdout, func() {}, nil
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("opening events file: %w", err)
+	}
+	return f, func() { f.Close() }, nil
+}
+
 // parseInputFlags parses --input key=value flags into a map
 func parseInputFlags(flags []string) (map[string]string, error) {
 	result := make(map[string]string)
@@ -115,11 +304,32 @@ func parseInputFlags(flags []string) (map[string]string, error) {
 	return result, nil
 }
 
+// inputEnvPrefix prefixes environment variables that supply mission inputs,
+// e.g. SQUADRON_INPUT_targets='["a","b"]'.
+const inputEnvPrefix = "SQUADRON_INPUT_"
+
+// addInputsFromEnv fills inputs not given with --input from
+// SQUADRON_INPUT_<name>. Values are parsed like flag values, so lists,
+// maps, and objects are JSON.
+func addInputsFromEnv(m *config.Mission, inputs map[string]string, lookup func(string) (string, bool)) {
+	for _, input := range m.Inputs {
+		if _, ok := inputs[input.Name]; ok || input.Protected {
+			continue
+		}
+		if v, ok := lookup(inputEnvPrefix + input.Name); ok {
+			inputs[input.Name] = v
+		}
+	}
+}
+
 func init() {
 	rootCmd.AddCommand(missionCmd)
 	missionCmd.Flags().StringVarP(&configPath, "config", "c", ".", "Path to config file or directory")
-	missionCmd.Flags().StringArrayVarP(&inputFlags, "input", "i", nil, "Mission input in key=value format (can be repeated)")
+	missionCmd.Flags().StringArrayVarP(&inputFlags, "input", "i", nil, "Mission input as key=value, key=@file or key=- for stdin (can be repeated)")
 	missionCmd.Flags().BoolVarP(&missionDebugMode, "debug", "d", false, "Enable debug mode to capture LLM messages and events")
-	missionCmd.Flags().StringVar(&resumeMissionID, "resume", "", "Resume a previously failed mission by its ID")
+	missionCmd.Flags().StringVar(&resumeMissionID, "resume", "", "Resume a previously failed, stopped or paused mission by its ID")
+	missionCmd.Flags().StringArrayVar(&priorMissionIDs, "ref", nil, "ID of a completed mission whose commanders can be queried with ask_prior_commander (can be repeated)")
+	missionCmd.Flags().StringVar(&missionEventsPath, "events", "", "Write mission events as NDJSON to a file (alongside terminal output), or '-' to stdout in place of it")
+	missionCmd.Flags().BoolVar(&missionDryRun, "dry-run", false, "Print the execution plan and validate inputs and datasets without running the mission")
 	missionCmd.Flags().BoolVar(&missionAutoInit, "init", false, "Auto-initialize Squadron if not already initialized")
 }
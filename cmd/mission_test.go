@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"squadron/config"
+	"squadron/mission"
+)
+
+func TestPrintMissionPlan(t *testing.T) {
+	plan := &mission.Plan{
+		MissionName: "triage",
+		MaxParallel: 3,
+		Datasets: []mission.PlannedDataset{
+			{Name: "tickets", Origin: "input ids", Items: 2},
+			{Name: "found", Origin: "runtime"},
+		},
+		Groups: [][]mission.PlannedTask{
+			{{Name: "classify", Agents: []string{"triager"}, Objective: "Classify\nthe tickets", Routes: []string{"escalate"}, EstimatedLLMCalls: 3}},
+			{{Name: "escalate", Agents: []string{"triager"}, Conditional: true, Dataset: "tickets", Iterations: 2, Parallel: true, ConcurrencyLimit: 5, EstimatedLLMCalls: 6}},
+		},
+		EstimatedLLMCalls: 9,
+	}
+
+	var buf bytes.Buffer
+	printMissionPlan(&buf, plan)
+	out := buf.String()
+
+	for _, want := range []string{
+		"Mission: triage (dry run, max 3 tasks in parallel)",
+		"tickets: 2 items from input ids",
+		"found: filled at runtime",
+		"Step 1:\n  classify (routes to escalate)",
+		"objective: Classify\n      the tickets",
+		"Step 2:\n  escalate (conditional; iterates 2 over tickets, parallel, 5 at a time)",
+		"Estimated LLM calls: at least 9 if every task runs",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestAddInputsFromEnv(t *testing.T) {
+	m := &config.Mission{Inputs: []config.MissionInput{
+		{Name: "targets", Type: "list"},
+		{Name: "region", Type: "string"},
+		{Name: "token", Type: "string", Protected: true},
+	}}
+	env := map[string]string{
+		"SQUADRON_INPUT_targets": `["a","b"]`,
+		"SQUADRON_INPUT_region":  "eu",
+		"SQUADRON_INPUT_token":   "secret",
+	}
+	lookup := func(k string) (string, bool) { v, ok := env[k]; return v, ok }
+
+	inputs := map[string]string{"region": "us"}
+	addInputsFromEnv(m, inputs, lookup)
+
+	if inputs["targets"] != `["a","b"]` {
+		t.Errorf("targets = %q, want the env value", inputs["targets"])
+	}
+	if inputs["region"] != "us" {
+		t.Errorf("region = %q, want the --input value to win", inputs["region"])
+	}
+	if _, ok := inputs["token"]; ok {
+		t.Error("protected inputs must not be read from the environment")
+	}
+}
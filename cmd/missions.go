@@ -0,0 +1,478 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"squadron/config"
+	"squadron/store"
+
+	"github.com/spf13/cobra"
+)
+
+var missionsConfigPath string
+var missionsJSON bool
+var missionsListLimit int
+var missionsListName string
+
+var missionsCmd = &cobra.Command{
+	Use:   "missions",
+	Short: "Inspect past mission runs",
+	Long:  `List, show, and compare mission runs recorded in the configured store.`,
+}
+
+var missionsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List recent mission runs",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		stores := openMissionStores()
+		defer stores.Close()
+
+		// ListMissions has no name filter, so fetch extra rows when filtering
+		fetch := missionsListLimit
+		if missionsListName != "" {
+			fetch = max(missionsListLimit*10, 500)
+		}
+		records, total, err := stores.Missions.ListMissions(fetch, 0)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error listing missions: %v\n", err)
+			os.Exit(1)
+		}
+		records = filterMissionRecords(records, missionsListName, missionsListLimit)
+
+		if missionsJSON {
+			printJSON(records)
+			return
+		}
+		printMissionList(os.Stdout, records, total)
+	},
+}
+
+var missionsShowCmd = &cobra.Command{
+	Use:   "show [mission_id]",
+	Short: "Show the tasks, iterations, and token spend of a mission run",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		stores := openMissionStores()
+		defer stores.Close()
+
+		run, err := loadRunSummary(stores, args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if missionsJSON {
+			printJSON(run)
+			return
+		}
+		printRunSummary(os.Stdout, run)
+	},
+}
+
+var missionsDiffCmd = &cobra.Command{
+	Use:   "diff [mission_id] [mission_id]",
+	Short: "Compare two mission runs task by task",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		stores := openMissionStores()
+		defer stores.Close()
+
+		a, err := loadRunSummary(stores, args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		b, err := loadRunSummary(stores, args[1])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		diff := diffRuns(a, b)
+		if missionsJSON {
+			printJSON(diff)
+			return
+		}
+		printRunDiff(os.Stdout, diff)
+	},
+}
+
+// openMissionStores loads the config and opens its store bundle, exiting on
+// failure.
+func openMissionStores() *store.Bundle {
+	_, stores := loadConfigAndStores(missionsConfigPath)
+	return stores
+}
+
+// loadConfigAndStores loads the config at configPath and opens its store
+// bundle, exiting on failure.
+func loadConfigAndStores(configPath string) (*config.Config, *store.Bundle) {
+	if err := applyHome(configPath); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if err := EnsureInitialized(false); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+	stores, err := store.NewBundle(cfg.Storage)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error opening store: %v\n", err)
+		os.Exit(1)
+	}
+	return cfg, stores
+}
+
+func printJSON(v any) {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
+
+// filterMissionRecords keeps records of the named mission (all when name is
+// empty), up to limit.
+func filterMissionRecords(records []store.MissionRecord, name string, limit int) []store.MissionRecord {
+	out := []store.MissionRecord{}
+	for _, r := range records {
+		if name != "" && r.MissionName != name {
+			continue
+		}
+		if len(out) == limit {
+			break
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+func printMissionList(out io.Writer, records []store.MissionRecord, total int) {
+	if len(records) == 0 {
+		fmt.Fprintln(out, "No mission runs recorded.")
+		return
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tMISSION\tSTATUS\tSTARTED\tDURATION")
+	for _, r := range records {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", r.ID, r.MissionName, r.Status,
+			r.StartedAt.Local().Format("2006-01-02 15:04:05"), formatRunDuration(r.StartedAt, r.FinishedAt))
+	}
+	w.Flush()
+	if total > len(records) {
+		fmt.Fprintf(out, "\nShowing %d of %d runs.\n", len(records), total)
+	}
+}
+
+func formatRunDuration(start time.Time, finish *time.Time) string {
+	if finish == nil {
+		return "-"
+	}
+	return finish.Sub(start).Round(time.Second).String()
+}
+
+// runSummary is one mission run as shown by `missions show`.
+type runSummary struct {
+	MissionID   string           `json:"missionId"`
+	MissionName string           `json:"missionName"`
+	Status      string           `json:"status"`
+	Inputs      map[string]any   `json:"inputs,omitempty"`
+	StartedAt   time.Time        `json:"startedAt"`
+	FinishedAt  *time.Time       `json:"finishedAt,omitempty"`
+	Total       costUsage        `json:"total"`
+	Tasks       []taskRunSummary `json:"tasks"`
+}
+
+// taskRunSummary is one task of a run. Iterated tasks report how many
+// iterations submitted output and which ones failed.
+type taskRunSummary struct {
+	Name             string     `json:"name"`
+	Status           string     `json:"status"`
+	StartedAt        *time.Time `json:"startedAt,omitempty"`
+	FinishedAt       *time.Time `json:"finishedAt,omitempty"`
+	Error            string     `json:"error,omitempty"`
+	ErrorCode        string     `json:"errorCode,omitempty"`
+	Confidence       *float64   `json:"confidence,omitempty"`
+	Output           string     `json:"output,omitempty"`
+	Iterated         bool       `json:"iterated,omitempty"`
+	Iterations       int        `json:"iterations,omitempty"`
+	FailedIterations []int      `json:"failedIterations,omitempty"`
+	costUsage
+}
+
+func loadRunSummary(stores *store.Bundle, missionID string) (runSummary, error) {
+	rec, err := stores.Missions.GetMission(missionID)
+	if err != nil {
+		return runSummary{}, fmt.Errorf("mission %s not found: %w", missionID, err)
+	}
+	tasks, err := stores.Missions.GetTasksByMission(missionID)
+	if err != nil {
+		return runSummary{}, fmt.Errorf("loading tasks: %w", err)
+	}
+	outputs := make(map[string][]store.TaskOutputRow, len(tasks))
+	sessions := make(map[string][]store.SessionInfo, len(tasks))
+	for _, t := range tasks {
+		if outputs[t.ID], err = stores.Missions.GetTaskOutputs(t.ID); err != nil {
+			return runSummary{}, fmt.Errorf("loading outputs of task %s: %w", t.TaskName, err)
+		}
+		if sessions[t.ID], err = stores.Sessions.GetSessionsByTask(t.ID); err != nil {
+			return runSummary{}, fmt.Errorf("loading sessions of task %s: %w", t.TaskName, err)
+		}
+	}
+	costs, err := stores.Costs.GetCostsByMission(missionID)
+	if err != nil {
+		return runSummary{}, fmt.Errorf("loading costs: %w", err)
+	}
+	return buildRunSummary(rec, tasks, outputs, sessions, costs), nil
+}
+
+// buildRunSummary assembles a run from its store rows. outputs and sessions
+// are keyed by task ID.
+func buildRunSummary(rec *store.MissionRecord, tasks []store.MissionTask, outputs map[string][]store.TaskOutputRow, sessions map[string][]store.SessionInfo, costs []store.TurnCostRecord) runSummary {
+	run := runSummary{
+		MissionID:   rec.ID,
+		MissionName: rec.MissionName,
+		Status:      rec.Status,
+		StartedAt:   rec.StartedAt,
+		FinishedAt:  rec.FinishedAt,
+		Tasks:       []taskRunSummary{},
+	}
+	json.Unmarshal([]byte(rec.InputValuesJSON), &run.Inputs)
+
+	taskIdx := make(map[string]int, len(tasks))
+	for _, t := range tasks {
+		ts := taskRunSummary{
+			Name:       t.TaskName,
+			Status:     t.Status,
+			StartedAt:  t.StartedAt,
+			FinishedAt: t.FinishedAt,
+		}
+		if t.Error != nil {
+			ts.Error = *t.Error
+		}
+		if t.ErrorCode != nil {
+			ts.ErrorCode = *t.ErrorCode
+		}
+		ts.Confidence = t.Confidence
+		if t.OutputJSON != nil {
+			ts.Output = *t.OutputJSON
+		}
+
+		seen := map[int]bool{}
+		for _, o := range outputs[t.ID] {
+			if o.DatasetIndex != nil && !seen[*o.DatasetIndex] {
+				seen[*o.DatasetIndex] = true
+				ts.Iterated = true
+				ts.Iterations++
+			}
+		}
+		failed := map[int]bool{}
+		for _, s := range sessions[t.ID] {
+			if s.IterationIndex == nil {
+				continue
+			}
+			ts.Iterated = true
+			if s.Role == "commander" && (s.Status == "failed" || s.Status == "timed_out") && !seen[*s.IterationIndex] {
+				failed[*s.IterationIndex] = true
+			}
+		}
+		for i := range failed {
+			ts.FailedIterations = append(ts.FailedIterations, i)
+		}
+		sort.Ints(ts.FailedIterations)
+
+		taskIdx[t.ID] = len(run.Tasks)
+		run.Tasks = append(run.Tasks, ts)
+	}
+
+	for _, c := range costs {
+		run.Total.add(c)
+		if i, ok := taskIdx[c.TaskID]; ok {
+			run.Tasks[i].add(c)
+		}
+	}
+	return run
+}
+
+func printRunSummary(out io.Writer, r runSummary) {
+	fmt.Fprintf(out, "Mission: %s (%s)\n", r.MissionName, r.MissionID)
+	fmt.Fprintf(out, "Status:  %s\n", r.Status)
+	if r.FinishedAt != nil {
+		fmt.Fprintf(out, "Ran:     %s (%s)\n", r.StartedAt.Local().Format("2006-01-02 15:04:05"), r.FinishedAt.Sub(r.StartedAt).Round(time.Second))
+	} else {
+		fmt.Fprintf(out, "Started: %s\n", r.StartedAt.Local().Format("2006-01-02 15:04:05"))
+	}
+	if len(r.Inputs) > 0 {
+		inputs, _ := json.Marshal(r.Inputs)
+		fmt.Fprintf(out, "Inputs:  %s\n", inputs)
+	}
+	fmt.Fprintf(out, "Total:   $%.4f over %d turns (%d in / %d out tokens)\n",
+		r.Total.Cost, r.Total.Turns, r.Total.InputTokens, r.Total.OutputTokens)
+
+	if len(r.Tasks) == 0 {
+		fmt.Fprintln(out, "\nNo tasks ran.")
+		return
+	}
+
+	fmt.Fprintln(out, "\nTasks:")
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TASK\tSTATUS\tDURATION\tITERATIONS\tTURNS\tINPUT\tOUTPUT\tCOST")
+	for _, t := range r.Tasks {
+		duration := "-"
+		if t.StartedAt != nil {
+			duration = formatRunDuration(*t.StartedAt, t.FinishedAt)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%d\t%d\t$%.4f\n",
+			t.Name, t.Status, duration, formatIterations(t), t.Turns, t.InputTokens, t.OutputTokens, t.Cost)
+	}
+	w.Flush()
+
+	header := false
+	for _, t := range r.Tasks {
+		if t.Confidence == nil {
+			continue
+		}
+		if !header {
+			fmt.Fprintln(out, "\nConfidence:")
+			header = true
+		}
+		fmt.Fprintf(out, "  %s: %.2f\n", t.Name, *t.Confidence)
+	}
+
+	header = false
+	for _, t := range r.Tasks {
+		if t.Error == "" {
+			continue
+		}
+		if !header {
+			fmt.Fprintln(out, "\nErrors:")
+			header = true
+		}
+		if t.ErrorCode != "" {
+			fmt.Fprintf(out, "  %s [%s]: %s\n", t.Name, t.ErrorCode, t.Error)
+			continue
+		}
+		fmt.Fprintf(out, "  %s: %s\n", t.Name, t.Error)
+	}
+}
+
+func formatIterations(t taskRunSummary) string {
+	if !t.Iterated {
+		return "-"
+	}
+	if len(t.FailedIterations) == 0 {
+		return fmt.Sprintf("%d ok", t.Iterations)
+	}
+	return fmt.Sprintf("%d ok, failed %v", t.Iterations, t.FailedIterations)
+}
+
+// runDiff compares two runs. Tasks are listed in the order they ran in A,
+// followed by tasks only B ran.
+type runDiff struct {
+	A             runSummary    `json:"a"`
+	B             runSummary    `json:"b"`
+	InputsChanged []string      `json:"inputsChanged,omitempty"`
+	Tasks         []taskRunDiff `json:"tasks"`
+}
+
+// taskRunDiff pairs a task across two runs; A or B is nil when only one run
+// ran it.
+type taskRunDiff struct {
+	Name          string          `json:"name"`
+	A             *taskRunSummary `json:"a,omitempty"`
+	B             *taskRunSummary `json:"b,omitempty"`
+	OutputChanged bool            `json:"outputChanged"`
+}
+
+func diffRuns(a, b runSummary) runDiff {
+	d := runDiff{A: a, B: b, Tasks: []taskRunDiff{}}
+
+	keys := map[string]bool{}
+	for k := range a.Inputs {
+		keys[k] = true
+	}
+	for k := range b.Inputs {
+		keys[k] = true
+	}
+	for k := range keys {
+		av, _ := json.Marshal(a.Inputs[k])
+		bv, _ := json.Marshal(b.Inputs[k])
+		if string(av) != string(bv) {
+			d.InputsChanged = append(d.InputsChanged, k)
+		}
+	}
+	sort.Strings(d.InputsChanged)
+
+	idx := map[string]int{}
+	for i := range a.Tasks {
+		idx[a.Tasks[i].Name] = len(d.Tasks)
+		d.Tasks = append(d.Tasks, taskRunDiff{Name: a.Tasks[i].Name, A: &a.Tasks[i]})
+	}
+	for i := range b.Tasks {
+		if j, ok := idx[b.Tasks[i].Name]; ok {
+			d.Tasks[j].B = &b.Tasks[i]
+			continue
+		}
+		d.Tasks = append(d.Tasks, taskRunDiff{Name: b.Tasks[i].Name, B: &b.Tasks[i]})
+	}
+	for i := range d.Tasks {
+		t := &d.Tasks[i]
+		t.OutputChanged = t.A != nil && t.B != nil && t.A.Output != t.B.Output
+	}
+	return d
+}
+
+func printRunDiff(out io.Writer, d runDiff) {
+	fmt.Fprintf(out, "A: %s %s (%s)\n", d.A.MissionName, d.A.MissionID, d.A.Status)
+	fmt.Fprintf(out, "B: %s %s (%s)\n", d.B.MissionName, d.B.MissionID, d.B.Status)
+	if d.A.MissionName != d.B.MissionName {
+		fmt.Fprintln(out, "Note: the runs are of different missions.")
+	}
+	if len(d.InputsChanged) > 0 {
+		fmt.Fprintf(out, "Inputs changed: %v\n", d.InputsChanged)
+	}
+	fmt.Fprintf(out, "Total: $%.4f -> $%.4f, %d -> %d turns, %d -> %d tokens\n",
+		d.A.Total.Cost, d.B.Total.Cost, d.A.Total.Turns, d.B.Total.Turns,
+		d.A.Total.InputTokens+d.A.Total.OutputTokens, d.B.Total.InputTokens+d.B.Total.OutputTokens)
+
+	fmt.Fprintln(out)
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TASK\tSTATUS A\tSTATUS B\tITERATIONS A\tITERATIONS B\tCOST A\tCOST B\tOUTPUT")
+	for _, t := range d.Tasks {
+		statusA, statusB, iterA, iterB, costA, costB := "-", "-", "-", "-", "-", "-"
+		if t.A != nil {
+			statusA, iterA, costA = t.A.Status, formatIterations(*t.A), fmt.Sprintf("$%.4f", t.A.Cost)
+		}
+		if t.B != nil {
+			statusB, iterB, costB = t.B.Status, formatIterations(*t.B), fmt.Sprintf("$%.4f", t.B.Cost)
+		}
+		output := "same"
+		switch {
+		case t.A == nil:
+			output = "only in B"
+		case t.B == nil:
+			output = "only in A"
+		case t.OutputChanged:
+			output = "changed"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", t.Name, statusA, statusB, iterA, iterB, costA, costB, output)
+	}
+	w.Flush()
+}
+
+func init() {
+	rootCmd.AddCommand(missionsCmd)
+	missionsCmd.AddCommand(missionsListCmd, missionsShowCmd, missionsDiffCmd)
+	missionsCmd.PersistentFlags().StringVarP(&missionsConfigPath, "config", "c", ".", "Path to config file or directory")
+	missionsCmd.PersistentFlags().BoolVar(&missionsJSON, "json", false, "Print as JSON")
+	missionsListCmd.Flags().IntVarP(&missionsListLimit, "limit", "n", 20, "Number of runs to show")
+	missionsListCmd.Flags().StringVar(&missionsListName, "mission", "", "Only show runs of this mission")
+}
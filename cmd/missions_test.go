@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"squadron/store"
+)
+
+func intPtr(i int) *int { return &i }
+
+func strPtr(s string) *string { return &s }
+
+func sampleRun(id, fetchStatus, summary string) runSummary {
+	rec := &store.MissionRecord{ID: id, MissionName: "research", Status: "completed", InputValuesJSON: `{"topic":"` + id + `"}`, StartedAt: time.Now()}
+	tasks := []store.MissionTask{
+		{ID: id + "-plan", TaskName: "plan", Status: "completed", OutputJSON: strPtr(summary)},
+		{ID: id + "-fetch", TaskName: "fetch", Status: fetchStatus},
+	}
+	outputs := map[string][]store.TaskOutputRow{
+		id + "-fetch": {{DatasetIndex: intPtr(0)}, {DatasetIndex: intPtr(2)}},
+	}
+	sessions := map[string][]store.SessionInfo{
+		id + "-fetch": {
+			{Role: "commander", Status: "completed", IterationIndex: intPtr(0)},
+			{Role: "commander", Status: "failed", IterationIndex: intPtr(1)},
+			{Role: "commander", Status: "completed", IterationIndex: intPtr(2)},
+		},
+	}
+	costs := []store.TurnCostRecord{
+		{TaskID: id + "-plan", InputTokens: 100, OutputTokens: 10, TotalCost: 0.01},
+		{TaskID: id + "-fetch", IterationIndex: intPtr(0), InputTokens: 50, OutputTokens: 5, TotalCost: 0.005},
+		{TaskID: id + "-fetch", IterationIndex: intPtr(1), InputTokens: 50, OutputTokens: 5, TotalCost: 0.005},
+	}
+	return buildRunSummary(rec, tasks, outputs, sessions, costs)
+}
+
+func TestBuildRunSummary(t *testing.T) {
+	r := sampleRun("m1", "failed", `{"n":1}`)
+
+	if r.Total.Turns != 3 || r.Total.InputTokens != 200 {
+		t.Fatalf("unexpected total: %+v", r.Total)
+	}
+	if r.Inputs["topic"] != "m1" {
+		t.Fatalf("inputs not decoded: %+v", r.Inputs)
+	}
+	if len(r.Tasks) != 2 {
+		t.Fatalf("expected 2 tasks, got %d", len(r.Tasks))
+	}
+	if plan := r.Tasks[0]; plan.Iterated || plan.Turns != 1 {
+		t.Fatalf("unexpected plan row: %+v", plan)
+	}
+	fetch := r.Tasks[1]
+	if !fetch.Iterated || fetch.Iterations != 2 || len(fetch.FailedIterations) != 1 || fetch.FailedIterations[0] != 1 {
+		t.Fatalf("unexpected iteration outcomes: %+v", fetch)
+	}
+	if fetch.Turns != 2 || fetch.InputTokens != 100 {
+		t.Fatalf("unexpected fetch spend: %+v", fetch.costUsage)
+	}
+
+	var buf bytes.Buffer
+	printRunSummary(&buf, r)
+	for _, want := range []string{"research (m1)", "Tasks:", "2 ok, failed [1]"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Fatalf("summary missing %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestPrintRunSummaryErrorCode(t *testing.T) {
+	rec := &store.MissionRecord{ID: "m1", MissionName: "research", Status: "failed", StartedAt: time.Now()}
+	tasks := []store.MissionTask{
+		{ID: "t1", TaskName: "fetch", Status: "failed", Error: strPtr("429 Too Many Requests"), ErrorCode: strPtr("rate_limited")},
+	}
+	r := buildRunSummary(rec, tasks, nil, nil, nil)
+	if r.Tasks[0].ErrorCode != "rate_limited" {
+		t.Fatalf("error code not carried: %+v", r.Tasks[0])
+	}
+
+	var buf bytes.Buffer
+	printRunSummary(&buf, r)
+	if want := "fetch [rate_limited]: 429 Too Many Requests"; !strings.Contains(buf.String(), want) {
+		t.Fatalf("summary missing %q:\n%s", want, buf.String())
+	}
+}
+
+func TestPrintRunSummaryConfidence(t *testing.T) {
+	rec := &store.MissionRecord{ID: "m1", MissionName: "research", Status: "completed", StartedAt: time.Now()}
+	confidence := 0.75
+	tasks := []store.MissionTask{
+		{ID: "t1", TaskName: "plan", Status: "completed"},
+		{ID: "t2", TaskName: "report", Status: "completed", Confidence: &confidence},
+	}
+
+	var buf bytes.Buffer
+	printRunSummary(&buf, buildRunSummary(rec, tasks, nil, nil, nil))
+	if want := "Confidence:\n  report: 0.75\n"; !strings.Contains(buf.String(), want) {
+		t.Fatalf("summary missing %q:\n%s", want, buf.String())
+	}
+	if strings.Contains(buf.String(), "plan: ") {
+		t.Fatalf("unverified task listed under confidence:\n%s", buf.String())
+	}
+}
+
+func TestDiffRuns(t *testing.T) {
+	a := sampleRun("m1", "failed", `{"n":1}`)
+	b := sampleRun("m2", "completed", `{"n":2}`)
+	b.Tasks = append(b.Tasks, taskRunSummary{Name: "report", Status: "completed"})
+
+	d := diffRuns(a, b)
+	if len(d.InputsChanged) != 1 || d.InputsChanged[0] != "topic" {
+		t.Fatalf("unexpected input changes: %v", d.InputsChanged)
+	}
+	if len(d.Tasks) != 3 {
+		t.Fatalf("expected 3 task rows, got %d", len(d.Tasks))
+	}
+	if !d.Tasks[0].OutputChanged || d.Tasks[1].OutputChanged {
+		t.Fatalf("unexpected output changes: %+v", d.Tasks)
+	}
+	if d.Tasks[2].A != nil || d.Tasks[2].B == nil {
+		t.Fatalf("expected report only in B: %+v", d.Tasks[2])
+	}
+
+	var buf bytes.Buffer
+	printRunDiff(&buf, d)
+	for _, want := range []string{"Inputs changed: [topic]", "only in B", "changed", "failed"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Fatalf("diff missing %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestFilterMissionRecords(t *testing.T) {
+	records := []store.MissionRecord{{MissionName: "a"}, {MissionName: "b"}, {MissionName: "a"}, {MissionName: "a"}}
+	if got := filterMissionRecords(records, "a", 2); len(got) != 2 || got[1].MissionName != "a" {
+		t.Fatalf("unexpected filter result: %+v", got)
+	}
+	if got := filterMissionRecords(records, "", 10); len(got) != 4 {
+		t.Fatalf("expected all records, got %d", len(got))
+	}
+}
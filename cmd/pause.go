@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"squadron/config"
+	"squadron/mission"
+	"squadron/store"
+
+	"github.com/spf13/cobra"
+)
+
+var pauseConfigPath string
+
+var pauseCmd = &cobra.Command{
+	Use:   "pause [mission_id]",
+	Short: "Pause a running mission",
+	Long: `Ask the process running a mission to pause it. The mission stops dispatching tasks and iterations,
+interrupts in-flight commanders at their current call, and is marked "paused". Continue it later with
+squadron mission <name> --resume <mission_id>.
+
+Sending SIGTERM to a "squadron mission" process has the same effect.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := applyHome(pauseConfigPath); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := EnsureInitialized(false); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		cfg, err := config.Load(pauseConfigPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+		stores, err := store.NewBundle(cfg.Storage)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error opening store: %v\n", err)
+			os.Exit(1)
+		}
+		defer stores.Close()
+
+		missionID := args[0]
+		if err := mission.RequestPause(stores.Missions, missionID); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Pause requested for mission %s\n", missionID)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(pauseCmd)
+	pauseCmd.Flags().StringVarP(&pauseConfigPath, "config", "c", ".", "Path to config file or directory")
+}
@@ -0,0 +1,397 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"squadron/aitools"
+	"squadron/config"
+	"squadron/mission"
+	"squadron/store"
+
+	"github.com/spf13/cobra"
+)
+
+var reportConfigPath string
+var reportJSON bool
+var reportArtifactsDir string
+
+var reportCmd = &cobra.Command{
+	Use:   "report [mission_id]",
+	Short: "Show token usage, cost, and tool usage for a mission run",
+	Long:  `Show the token usage and cost recorded for a mission run, broken down by task, iteration, and agent, along with the mix of models used. Tool calls are totalled per commander or agent and tool, with error rates and latency percentiles, and tools an agent was given but never called are listed. Browser state captured when iterations failed is listed too, and saved to a directory with --artifacts.`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := applyHome(reportConfigPath); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := EnsureInitialized(false); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		cfg, err := config.Load(reportConfigPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+		stores, err := store.NewBundle(cfg.Storage)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error opening store: %v\n", err)
+			os.Exit(1)
+		}
+		defer stores.Close()
+
+		missionID := args[0]
+		rec, err := stores.Missions.GetMission(missionID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: mission %s not found: %v\n", missionID, err)
+			os.Exit(1)
+		}
+		costs, err := stores.Costs.GetCostsByMission(missionID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading costs: %v\n", err)
+			os.Exit(1)
+		}
+
+		tools, err := stores.Sessions.GetToolStatsByMission(missionID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading tool calls: %v\n", err)
+			os.Exit(1)
+		}
+
+		var artifacts []store.ArtifactRecord
+		if stores.Artifacts != nil {
+			artifacts, err = stores.Artifacts.ListArtifacts(missionID)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error loading artifacts: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		report := buildCostReport(rec, costs)
+		report.Tools = tools
+		report.UnusedTools = unusedTools(missionAgents(cfg, rec.MissionName), report, tools)
+		report.FailureArtifacts = failureArtifacts(artifacts)
+		if reportArtifactsDir != "" {
+			if err := saveFailureArtifacts(stores.Artifacts, report.FailureArtifacts, reportArtifactsDir); err != nil {
+				fmt.Fprintf(os.Stderr, "Error saving artifacts: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if reportJSON {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			enc.Encode(report)
+			return
+		}
+		printCostReport(os.Stdout, report)
+		printToolUsage(os.Stdout, report)
+		printFailureArtifacts(os.Stdout, report.FailureArtifacts, reportArtifactsDir)
+	},
+}
+
+// costUsage is the token and dollar total for one slice of a mission run.
+type costUsage struct {
+	Turns            int     `json:"turns"`
+	InputTokens      int     `json:"inputTokens"`
+	OutputTokens     int     `json:"outputTokens"`
+	CacheReadTokens  int     `json:"cacheReadTokens"`
+	CacheWriteTokens int     `json:"cacheWriteTokens"`
+	Cost             float64 `json:"cost"`
+}
+
+func (u *costUsage) add(r store.TurnCostRecord) {
+	u.Turns++
+	u.InputTokens += r.InputTokens
+	u.OutputTokens += r.OutputTokens
+	u.CacheReadTokens += r.CacheReadTokens
+	u.CacheWriteTokens += r.CacheWriteTokens
+	u.Cost += r.TotalCost
+}
+
+// cacheHitRate is the share of prompt tokens read from the provider's
+// prompt cache, as a percentage.
+func (u costUsage) cacheHitRate() float64 {
+	prompt := u.InputTokens + u.CacheReadTokens + u.CacheWriteTokens
+	if prompt == 0 {
+		return 0
+	}
+	return float64(u.CacheReadTokens) / float64(prompt) * 100
+}
+
+// entityCost is the usage of one commander or agent within a task (or one
+// iteration of it).
+type entityCost struct {
+	Task   string `json:"task"`
+	Entity string `json:"entity"`
+	costUsage
+}
+
+type modelCost struct {
+	Model string `json:"model"`
+	costUsage
+}
+
+type costReport struct {
+	MissionID   string       `json:"missionId"`
+	MissionName string       `json:"missionName"`
+	Status      string       `json:"status"`
+	StartedAt   time.Time    `json:"startedAt"`
+	FinishedAt  *time.Time   `json:"finishedAt,omitempty"`
+	Total       costUsage    `json:"total"`
+	Entities    []entityCost `json:"entities"`
+	Models      []modelCost  `json:"models"`
+	// Tool calls per commander or agent and tool
+	Tools []store.ToolStats `json:"tools"`
+	// Tools each agent that ran was given but never called
+	UnusedTools []unusedTool `json:"unusedTools,omitempty"`
+	// Browser state captured when iterations failed
+	FailureArtifacts []failureArtifact `json:"failureArtifacts,omitempty"`
+}
+
+// unusedTool is a tool an agent's config grants that it never called.
+type unusedTool struct {
+	Agent string `json:"agent"`
+	Tool  string `json:"tool"` // the reference from the agent's tools list
+}
+
+// failureArtifact is one file captured from a browser plugin when an
+// iteration failed.
+type failureArtifact struct {
+	ID        string `json:"id"`
+	Task      string `json:"task"`
+	Name      string `json:"name"`
+	MediaType string `json:"mediaType"`
+	Size      int64  `json:"size"`
+}
+
+// buildCostReport totals turn costs per task/iteration and entity, in the
+// order they first ran, and per model, most expensive first.
+func buildCostReport(rec *store.MissionRecord, costs []store.TurnCostRecord) costReport {
+	report := costReport{
+		MissionID:   rec.ID,
+		MissionName: rec.MissionName,
+		Status:      rec.Status,
+		StartedAt:   rec.StartedAt,
+		FinishedAt:  rec.FinishedAt,
+		Entities:    []entityCost{},
+		Models:      []modelCost{},
+		Tools:       []store.ToolStats{},
+	}
+
+	entityIdx := map[[2]string]int{}
+	modelIdx := map[string]int{}
+	for _, c := range costs {
+		report.Total.add(c)
+
+		key := [2]string{c.TaskName, c.Entity}
+		i, ok := entityIdx[key]
+		if !ok {
+			i = len(report.Entities)
+			entityIdx[key] = i
+			report.Entities = append(report.Entities, entityCost{Task: c.TaskName, Entity: c.Entity})
+		}
+		report.Entities[i].add(c)
+
+		j, ok := modelIdx[c.Model]
+		if !ok {
+			j = len(report.Models)
+			modelIdx[c.Model] = j
+			report.Models = append(report.Models, modelCost{Model: c.Model})
+		}
+		report.Models[j].add(c)
+	}
+
+	sort.SliceStable(report.Models, func(a, b int) bool { return report.Models[a].Cost > report.Models[b].Cost })
+	return report
+}
+
+func printCostReport(out io.Writer, r costReport) {
+	fmt.Fprintf(out, "Mission: %s (%s)\n", r.MissionName, r.MissionID)
+	fmt.Fprintf(out, "Status:  %s\n", r.Status)
+	if r.FinishedAt != nil {
+		fmt.Fprintf(out, "Ran:     %s (%s)\n", r.StartedAt.Local().Format("2006-01-02 15:04:05"), r.FinishedAt.Sub(r.StartedAt).Round(time.Second))
+	} else {
+		fmt.Fprintf(out, "Started: %s\n", r.StartedAt.Local().Format("2006-01-02 15:04:05"))
+	}
+	fmt.Fprintf(out, "Total:   $%.4f over %d turns (%d in / %d out tokens, %d cache read / %d cache write)\n",
+		r.Total.Cost, r.Total.Turns, r.Total.InputTokens, r.Total.OutputTokens, r.Total.CacheReadTokens, r.Total.CacheWriteTokens)
+
+	if r.Total.Turns == 0 {
+		fmt.Fprintln(out, "\nNo LLM usage recorded for this mission.")
+		return
+	}
+
+	fmt.Fprintln(out, "\nBy task:")
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "TASK\tENTITY\tTURNS\tINPUT\tOUTPUT\tCACHE READ\tCACHE WRITE\tCACHE HIT\tCOST\t")
+	for _, e := range r.Entities {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%d\t%d\t%.1f%%\t$%.4f\t\n",
+			e.Task, e.Entity, e.Turns, e.InputTokens, e.OutputTokens, e.CacheReadTokens, e.CacheWriteTokens, e.cacheHitRate(), e.Cost)
+	}
+	w.Flush()
+
+	fmt.Fprintln(out, "\nModel mix:")
+	w = tabwriter.NewWriter(out, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "MODEL\tTURNS\tINPUT\tOUTPUT\tCOST\tSHARE\t")
+	for _, m := range r.Models {
+		share := 0.0
+		if r.Total.Cost > 0 {
+			share = m.Cost / r.Total.Cost * 100
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t$%.4f\t%.1f%%\t\n", m.Model, m.Turns, m.InputTokens, m.OutputTokens, m.Cost, share)
+	}
+	w.Flush()
+}
+
+// missionAgents returns the global agents and those scoped to missionName.
+func missionAgents(cfg *config.Config, missionName string) []config.Agent {
+	agents := append([]config.Agent(nil), cfg.Agents...)
+	for _, m := range cfg.Missions {
+		if m.Name == missionName {
+			agents = append(agents, m.LocalAgents...)
+		}
+	}
+	return agents
+}
+
+// unusedTools lists the tools each agent that ran in the mission was given
+// but never called, in config order. Whole-namespace references (".all")
+// are skipped, since what they expand to depends on the plugin or server,
+// as are the dataset tools injected into every mission agent.
+func unusedTools(agents []config.Agent, r costReport, tools []store.ToolStats) []unusedTool {
+	ran := map[string]bool{}
+	for _, e := range r.Entities {
+		ran[e.Entity] = true
+	}
+	called := map[[2]string]bool{}
+	for _, t := range tools {
+		ran[t.Entity] = true
+		called[[2]string{t.Entity, t.Tool}] = true
+	}
+
+	var out []unusedTool
+	for _, a := range agents {
+		if !ran[a.Name] {
+			continue
+		}
+		for _, ref := range a.Tools {
+			if strings.HasSuffix(ref, ".all") || strings.HasPrefix(ref, "builtins.dataset.") {
+				continue
+			}
+			// The model calls tools by their API-safe names
+			if called[[2]string{a.Name, ref}] || called[[2]string{a.Name, aitools.SanitizeToolName(ref)}] {
+				continue
+			}
+			out = append(out, unusedTool{Agent: a.Name, Tool: ref})
+		}
+	}
+	return out
+}
+
+func printToolUsage(out io.Writer, r costReport) {
+	if len(r.Tools) > 0 {
+		fmt.Fprintln(out, "\nTool usage:")
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', tabwriter.AlignRight)
+		fmt.Fprintln(w, "ENTITY\tTOOL\tCALLS\tERRORS\tERROR RATE\tP50\tP90\tP99\t")
+		for _, t := range r.Tools {
+			fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%.1f%%\t%s\t%s\t%s\t\n",
+				t.Entity, t.Tool, t.Calls, t.Errors, t.ErrorRate(), formatLatency(t.P50Ms), formatLatency(t.P90Ms), formatLatency(t.P99Ms))
+		}
+		w.Flush()
+	}
+	if len(r.UnusedTools) > 0 {
+		fmt.Fprintln(out, "\nNever called:")
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "AGENT\tTOOL")
+		for _, u := range r.UnusedTools {
+			fmt.Fprintf(w, "%s\t%s\n", u.Agent, u.Tool)
+		}
+		w.Flush()
+	}
+}
+
+// formatLatency renders a latency in milliseconds: 850ms, 1.2s.
+func formatLatency(ms int64) string {
+	if ms < 1000 {
+		return fmt.Sprintf("%dms", ms)
+	}
+	return fmt.Sprintf("%.1fs", float64(ms)/1000)
+}
+
+// failureArtifacts picks the failure captures out of a mission's artifacts
+func failureArtifacts(recs []store.ArtifactRecord) []failureArtifact {
+	var out []failureArtifact
+	for _, rec := range recs {
+		if rec.Author != mission.FailureCaptureAuthor {
+			continue
+		}
+		out = append(out, failureArtifact{ID: rec.ID, Task: rec.TaskName, Name: rec.Name, MediaType: rec.MediaType, Size: rec.Size})
+	}
+	return out
+}
+
+func printFailureArtifacts(out io.Writer, arts []failureArtifact, savedTo string) {
+	if len(arts) == 0 {
+		return
+	}
+	fmt.Fprintln(out, "\nFailure artifacts:")
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TASK\tNAME\tSIZE\tID")
+	for _, a := range arts {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", a.Task, a.Name, a.Size, a.ID)
+	}
+	w.Flush()
+	if savedTo != "" {
+		fmt.Fprintf(out, "Saved to %s\n", savedTo)
+	} else {
+		fmt.Fprintln(out, "Save them with --artifacts <dir>.")
+	}
+}
+
+// saveFailureArtifacts writes each artifact to <dir>/<task>/<name>
+func saveFailureArtifacts(artifacts store.ArtifactStore, arts []failureArtifact, dir string) error {
+	for _, a := range arts {
+		path := filepath.Join(dir, filepath.Base(a.Task), filepath.Base(a.Name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+		if err := saveArtifact(artifacts, a.ID, path); err != nil {
+			return fmt.Errorf("%s/%s: %w", a.Task, a.Name, err)
+		}
+	}
+	return nil
+}
+
+func saveArtifact(artifacts store.ArtifactStore, id, path string) error {
+	rc, err := artifacts.OpenArtifact(id)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, rc); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+	reportCmd.Flags().StringVarP(&reportConfigPath, "config", "c", ".", "Path to config file or directory")
+	reportCmd.Flags().BoolVar(&reportJSON, "json", false, "Print the report as JSON")
+	reportCmd.Flags().StringVar(&reportArtifactsDir, "artifacts", "", "Save the browser state captured when iterations failed to this directory")
+}
@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"squadron/config"
+	"squadron/mission"
+	"squadron/store"
+)
+
+func TestBuildCostReport(t *testing.T) {
+	rec := &store.MissionRecord{ID: "m1", MissionName: "research", Status: "completed", StartedAt: time.Now()}
+	costs := []store.TurnCostRecord{
+		{TaskName: "plan", Entity: "commander", Model: "claude-sonnet-4", InputTokens: 100, OutputTokens: 10, TotalCost: 0.01},
+		{TaskName: "plan", Entity: "researcher", Model: "gpt-4o-mini", InputTokens: 50, OutputTokens: 5, TotalCost: 0.001},
+		{TaskName: "plan", Entity: "commander", Model: "claude-sonnet-4", InputTokens: 200, OutputTokens: 20, CacheReadTokens: 80, TotalCost: 0.02},
+		{TaskName: "fetch[1]", Entity: "commander", Model: "claude-sonnet-4", InputTokens: 10, OutputTokens: 1, TotalCost: 0.002},
+	}
+
+	r := buildCostReport(rec, costs)
+
+	if r.Total.Turns != 4 || r.Total.InputTokens != 360 || r.Total.CacheReadTokens != 80 {
+		t.Fatalf("unexpected total: %+v", r.Total)
+	}
+	if len(r.Entities) != 3 {
+		t.Fatalf("expected 3 task/entity rows, got %d", len(r.Entities))
+	}
+	if e := r.Entities[0]; e.Task != "plan" || e.Entity != "commander" || e.Turns != 2 || e.OutputTokens != 30 {
+		t.Fatalf("unexpected first row: %+v", e)
+	}
+	if e := r.Entities[2]; e.Task != "fetch[1]" {
+		t.Fatalf("expected iteration row last, got %+v", e)
+	}
+	if len(r.Models) != 2 || r.Models[0].Model != "claude-sonnet-4" || r.Models[0].Turns != 3 {
+		t.Fatalf("unexpected model mix: %+v", r.Models)
+	}
+	if got := r.Entities[0].cacheHitRate(); got < 21 || got > 21.1 {
+		t.Fatalf("expected a 21.1%% cache hit rate (80 of 380 prompt tokens) for plan's commander, got %.2f", got)
+	}
+	if got := r.Entities[1].cacheHitRate(); got != 0 {
+		t.Fatalf("expected no cache hits for the researcher, got %.1f", got)
+	}
+}
+
+func TestPrintCostReport(t *testing.T) {
+	rec := &store.MissionRecord{ID: "m1", MissionName: "research", Status: "completed", StartedAt: time.Now()}
+
+	var buf bytes.Buffer
+	printCostReport(&buf, buildCostReport(rec, nil))
+	if !strings.Contains(buf.String(), "No LLM usage recorded") {
+		t.Fatalf("expected empty-report notice, got:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	printCostReport(&buf, buildCostReport(rec, []store.TurnCostRecord{
+		{TaskName: "plan", Entity: "commander", Model: "claude-sonnet-4", InputTokens: 100, TotalCost: 0.5},
+	}))
+	for _, want := range []string{"research (m1)", "By task:", "Model mix:", "claude-sonnet-4", "100.0%"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Fatalf("report missing %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestUnusedTools(t *testing.T) {
+	agents := []config.Agent{
+		{Name: "researcher", Tools: []string{"plugins.playwright.browser_navigate", "plugins.playwright.browser_click", "builtins.http.get", "builtins.dataset.set"}},
+		{Name: "writer", Tools: []string{"plugins.shell.all", "builtins.http.post"}},
+		{Name: "idle", Tools: []string{"builtins.http.get"}},
+	}
+	rec := &store.MissionRecord{ID: "m1", MissionName: "research", StartedAt: time.Now()}
+	r := buildCostReport(rec, []store.TurnCostRecord{
+		{TaskName: "plan", Entity: "commander"},
+		{TaskName: "plan", Entity: "writer"},
+	})
+	tools := []store.ToolStats{
+		{Entity: "researcher", Tool: "plugins_playwright_browser_navigate", Calls: 3},
+		{Entity: "commander", Tool: "builtins_http_get", Calls: 1},
+	}
+
+	got := unusedTools(agents, r, tools)
+	want := []unusedTool{
+		{Agent: "researcher", Tool: "plugins.playwright.browser_click"},
+		{Agent: "researcher", Tool: "builtins.http.get"},
+		{Agent: "writer", Tool: "builtins.http.post"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("unusedTools = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("unusedTools = %+v, want %+v", got, want)
+		}
+	}
+}
+
+func TestPrintToolUsage(t *testing.T) {
+	r := costReport{
+		Tools: []store.ToolStats{
+			{Entity: "researcher", Tool: "plugins_playwright_browser_navigate", Calls: 4, Errors: 1, P50Ms: 850, P90Ms: 1200, P99Ms: 4100},
+		},
+		UnusedTools: []unusedTool{{Agent: "researcher", Tool: "plugins.playwright.browser_click"}},
+	}
+
+	var buf bytes.Buffer
+	printToolUsage(&buf, r)
+	for _, want := range []string{"Tool usage:", "plugins_playwright_browser_navigate", "25.0%", "850ms", "1.2s", "4.1s", "Never called:", "plugins.playwright.browser_click"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Fatalf("output missing %q:\n%s", want, buf.String())
+		}
+	}
+
+	buf.Reset()
+	printToolUsage(&buf, costReport{})
+	if buf.Len() != 0 {
+		t.Fatalf("expected no output without tool calls, got:\n%s", buf.String())
+	}
+}
+
+func TestFailureArtifacts(t *testing.T) {
+	stores, err := store.NewSQLiteBundle(filepath.Join(t.TempDir(), "store.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stores.Close()
+
+	put := func(author, task, name, content string) {
+		_, err := stores.Artifacts.PutArtifact(store.ArtifactRecord{MissionID: "m1", TaskName: task, Author: author, Name: name, MediaType: "text/plain"}, strings.NewReader(content))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	put("writer", "report", "report.md", "# Report")
+	put(mission.FailureCaptureAuthor, "checkout[3]", "url.txt", "https://shop.example.com/checkout")
+
+	recs, err := stores.Artifacts.ListArtifacts("m1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	arts := failureArtifacts(recs)
+	if len(arts) != 1 || arts[0].Task != "checkout[3]" || arts[0].Name != "url.txt" {
+		t.Fatalf("failureArtifacts = %+v, want only the capture", arts)
+	}
+
+	dir := t.TempDir()
+	if err := saveFailureArtifacts(stores.Artifacts, arts, dir); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(filepath.Join(dir, "checkout[3]", "url.txt"))
+	if err != nil || string(got) != "https://shop.example.com/checkout" {
+		t.Fatalf("saved url.txt = %q, %v", got, err)
+	}
+
+	var buf bytes.Buffer
+	printFailureArtifacts(&buf, arts, dir)
+	for _, want := range []string{"Failure artifacts:", "checkout[3]", "url.txt", "Saved to " + dir} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, buf.String())
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"squadron/config"
+	"squadron/mission"
+	"squadron/store"
+	"squadron/streamers"
+	"squadron/streamers/cli"
+
+	"github.com/spf13/cobra"
+)
+
+var rerunConfigPath string
+var rerunTask string
+var rerunItems string
+
+var rerunCmd = &cobra.Command{
+	Use:   "rerun [mission_id]",
+	Short: "Re-run failed or selected iterations of a task",
+	Long: `Re-run iterations of an iterated task in an earlier mission run. With --items, the listed items
+(by their id, name, or key field, or item_N) run again and their new outputs supersede the old ones.
+Without --items, only items that failed or never finished run. The mission may already be completed;
+tasks downstream of the re-run task are not run again.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := applyHome(rerunConfigPath); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := EnsureInitialized(false); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		cfg, err := config.LoadAndValidate(rerunConfigPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+
+		// The mission name comes from the stored run
+		missionID := args[0]
+		stores, err := store.NewBundle(cfg.Storage)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error opening store: %v\n", err)
+			os.Exit(1)
+		}
+		rec, err := stores.Missions.GetMission(missionID)
+		stores.Close()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: mission %s not found: %v\n", missionID, err)
+			os.Exit(1)
+		}
+
+		runnerOpts := []mission.RunnerOption{
+			mission.WithResume(missionID),
+			mission.WithRerun(rerunTask, parseItemIDs(rerunItems)),
+		}
+		runner, err := mission.NewRunner(cfg, rerunConfigPath, rec.MissionName, map[string]string{}, runnerOpts...)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		streamer := streamers.NewStoringMissionHandler(cli.NewMissionHandler(), runner.EventStore(), runner.CostStore())
+		err = runner.Run(context.Background(), streamer)
+		runner.CloseStores()
+		if errors.Is(err, mission.ErrMissionPaused) {
+			fmt.Fprintf(os.Stderr, "\nMission paused. Resume with: squadron mission %s --resume %s\n", rec.MissionName, missionID)
+			return
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "\nRerun failed: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// parseItemIDs splits a comma-separated --items value, dropping blanks.
+func parseItemIDs(s string) []string {
+	var ids []string
+	for _, id := range strings.Split(s, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func init() {
+	rootCmd.AddCommand(rerunCmd)
+	rerunCmd.Flags().StringVarP(&rerunConfigPath, "config", "c", ".", "Path to config file or directory")
+	rerunCmd.Flags().StringVar(&rerunTask, "task", "", "Name of the iterated task to re-run")
+	rerunCmd.Flags().StringVar(&rerunItems, "items", "", "Comma-separated IDs of the items to re-run (default: failed and unfinished items)")
+	rerunCmd.MarkFlagRequired("task")
+}
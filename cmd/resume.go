@@ -0,0 +1,235 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"squadron/config"
+	"squadron/mission"
+	"squadron/store"
+	"squadron/streamers"
+	"squadron/streamers/cli"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var resumeConfigPath string
+var resumeLatest bool
+
+// resumeScanLimit caps how many recent runs are searched for resumable ones.
+const resumeScanLimit = 200
+
+var resumeCmd = &cobra.Command{
+	Use:   "resume [mission_id]",
+	Short: "Resume a failed, paused or interrupted mission",
+	Long: `Resume a mission run that did not complete. With a mission ID, that run is resumed. Without one,
+the resumable runs in the store are listed to pick from; --latest resumes the most recent one
+without asking, for scripts.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 1 && resumeLatest {
+			fmt.Fprintln(os.Stderr, "Error: pass a mission ID or --latest, not both")
+			os.Exit(1)
+		}
+		if err := applyHome(resumeConfigPath); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := EnsureInitialized(false); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		cfg, err := config.LoadAndValidate(resumeConfigPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+		stores, err := store.NewBundle(cfg.Storage)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error opening store: %v\n", err)
+			os.Exit(1)
+		}
+		rec, err := chooseResumeRun(stores, args)
+		stores.Close()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if rec == nil {
+			return
+		}
+		runResumedMission(cfg, rec)
+	},
+}
+
+// chooseResumeRun returns the run to resume: the one named in args, the
+// latest resumable one with --latest, or one picked from a list. It returns
+// nil when there is nothing to resume or the user backs out.
+func chooseResumeRun(stores *store.Bundle, args []string) (*store.MissionRecord, error) {
+	if len(args) == 1 {
+		rec, err := stores.Missions.GetMission(args[0])
+		if err != nil {
+			return nil, fmt.Errorf("mission %s not found: %w", args[0], err)
+		}
+		if !isResumable(rec.Status) {
+			return nil, fmt.Errorf("mission %s is %s and can't be resumed", rec.ID, rec.Status)
+		}
+		return rec, nil
+	}
+
+	runs, err := listResumableRuns(stores)
+	if err != nil {
+		return nil, err
+	}
+	if len(runs) == 0 {
+		fmt.Println("No resumable mission runs.")
+		return nil, nil
+	}
+	if resumeLatest {
+		return &runs[0].Record, nil
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		printResumableRuns(os.Stderr, runs, time.Now())
+		return nil, fmt.Errorf("pass a mission ID or --latest when not running in a terminal")
+	}
+	run, err := pickResumableRun(bufio.NewReader(os.Stdin), os.Stdout, runs, time.Now())
+	if err != nil || run == nil {
+		return nil, err
+	}
+	return &run.Record, nil
+}
+
+// isResumable reports whether a run with this status can be resumed. A run
+// still marked running had its process die mid-run.
+func isResumable(status string) bool {
+	switch mission.MissionState(status) {
+	case mission.MissionFailed, mission.MissionStopped, mission.MissionPaused,
+		mission.MissionCancelled, mission.MissionBudgetExceeded, mission.MissionRunning:
+		return true
+	}
+	return false
+}
+
+// resumableStatus is how a resumable run's status is shown.
+func resumableStatus(status string) string {
+	if status == string(mission.MissionRunning) {
+		return "interrupted"
+	}
+	return status
+}
+
+// resumableRun is a run that can be resumed, with how far it got.
+type resumableRun struct {
+	Record    store.MissionRecord
+	Completed int
+	Tasks     int
+}
+
+// listResumableRuns returns the resumable runs among the most recent ones in
+// the store, newest first.
+func listResumableRuns(stores *store.Bundle) ([]resumableRun, error) {
+	records, _, err := stores.Missions.ListMissions(resumeScanLimit, 0)
+	if err != nil {
+		return nil, fmt.Errorf("listing missions: %w", err)
+	}
+	var runs []resumableRun
+	for _, rec := range records {
+		if !isResumable(rec.Status) {
+			continue
+		}
+		tasks, err := stores.Missions.GetTasksByMission(rec.ID)
+		if err != nil {
+			return nil, fmt.Errorf("loading tasks of mission %s: %w", rec.ID, err)
+		}
+		run := resumableRun{Record: rec, Tasks: len(tasks)}
+		for _, t := range tasks {
+			if t.Status == "completed" || t.Status == "skipped" {
+				run.Completed++
+			}
+		}
+		runs = append(runs, run)
+	}
+	return runs, nil
+}
+
+func printResumableRuns(out io.Writer, runs []resumableRun, now time.Time) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "#\tID\tMISSION\tSTATUS\tSTARTED\tTASKS DONE")
+	for i, r := range runs {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%d/%d\n", i+1, r.Record.ID, r.Record.MissionName,
+			resumableStatus(r.Record.Status), formatAge(now.Sub(r.Record.StartedAt)), r.Completed, r.Tasks)
+	}
+	w.Flush()
+}
+
+// pickResumableRun lists runs and asks which one to resume. An empty answer
+// or "q" picks nothing.
+func pickResumableRun(in *bufio.Reader, out io.Writer, runs []resumableRun, now time.Time) (*resumableRun, error) {
+	printResumableRuns(out, runs, now)
+	fmt.Fprintln(out)
+	for {
+		fmt.Fprintf(out, "Resume which run? [1-%d, q to quit]: ", len(runs))
+		line, err := in.ReadString('\n')
+		line = strings.TrimSpace(line)
+		if line == "" || line == "q" {
+			return nil, nil
+		}
+		if n, convErr := strconv.Atoi(line); convErr == nil && n >= 1 && n <= len(runs) {
+			return &runs[n-1], nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading choice: %w", err)
+		}
+		fmt.Fprintf(out, "  Please enter a number between 1 and %d.\n", len(runs))
+	}
+}
+
+// formatAge renders how long ago something happened, e.g. "3h ago".
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	}
+}
+
+// runResumedMission resumes rec the way `squadron mission` runs a mission.
+func runResumedMission(cfg *config.Config, rec *store.MissionRecord) {
+	fmt.Printf("Resuming %s (%s)\n", rec.MissionName, rec.ID)
+
+	runnerOpts := []mission.RunnerOption{mission.WithResume(rec.ID)}
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		runnerOpts = append(runnerOpts, mission.WithApprover(newTerminalApprover(os.Stdin, os.Stdout)))
+	}
+	runner, err := mission.NewRunner(cfg, resumeConfigPath, rec.MissionName, map[string]string{}, runnerOpts...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	streamer := streamers.NewStoringMissionHandler(cli.NewMissionHandler(), runner.EventStore(), runner.CostStore())
+
+	runUntilDone(context.Background(), runner, streamer, func(id string) string {
+		return "squadron resume " + id
+	})
+}
+
+func init() {
+	rootCmd.AddCommand(resumeCmd)
+	resumeCmd.Flags().StringVarP(&resumeConfigPath, "config", "c", ".", "Path to config file or directory")
+	resumeCmd.Flags().BoolVar(&resumeLatest, "latest", false, "Resume the most recent resumable run without asking")
+}
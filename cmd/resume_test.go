@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"squadron/store"
+)
+
+func sampleResumableRuns(now time.Time) []resumableRun {
+	return []resumableRun{
+		{Record: store.MissionRecord{ID: "m2", MissionName: "research", Status: "running", StartedAt: now.Add(-90 * time.Minute)}, Completed: 2, Tasks: 3},
+		{Record: store.MissionRecord{ID: "m1", MissionName: "digest", Status: "paused", StartedAt: now.Add(-50 * time.Hour)}, Completed: 0, Tasks: 1},
+	}
+}
+
+func TestPrintResumableRuns(t *testing.T) {
+	now := time.Now()
+	var out bytes.Buffer
+	printResumableRuns(&out, sampleResumableRuns(now), now)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected a header and 2 rows, got:\n%s", out.String())
+	}
+	for _, want := range []string{"m2", "research", "interrupted", "1h ago", "2/3"} {
+		if !strings.Contains(lines[1], want) {
+			t.Fatalf("row %q is missing %q", lines[1], want)
+		}
+	}
+	if !strings.Contains(lines[2], "paused") || !strings.Contains(lines[2], "2d ago") {
+		t.Fatalf("unexpected row %q", lines[2])
+	}
+}
+
+func TestPickResumableRun(t *testing.T) {
+	now := time.Now()
+	runs := sampleResumableRuns(now)
+
+	var out bytes.Buffer
+	run, err := pickResumableRun(bufio.NewReader(strings.NewReader("7\n2\n")), &out, runs, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run == nil || run.Record.ID != "m1" {
+		t.Fatalf("expected m1 after a retry, got %+v", run)
+	}
+	if !strings.Contains(out.String(), "Please enter a number between 1 and 2") {
+		t.Fatalf("expected a retry prompt, got:\n%s", out.String())
+	}
+
+	for _, answer := range []string{"q\n", "\n", ""} {
+		run, err := pickResumableRun(bufio.NewReader(strings.NewReader(answer)), &bytes.Buffer{}, runs, now)
+		if err != nil || run != nil {
+			t.Fatalf("answer %q should pick nothing, got %+v, %v", answer, run, err)
+		}
+	}
+}
+
+func TestIsResumable(t *testing.T) {
+	for _, status := range []string{"failed", "stopped", "paused", "cancelled", "budget_exceeded", "running"} {
+		if !isResumable(status) {
+			t.Errorf("%s should be resumable", status)
+		}
+	}
+	if isResumable("completed") {
+		t.Error("completed runs can't be resumed")
+	}
+}
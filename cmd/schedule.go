@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"squadron/config"
+	"squadron/mission"
+	"squadron/scheduler"
+	"squadron/store"
+	"squadron/streamers"
+	"squadron/streamers/cli"
+
+	"github.com/spf13/cobra"
+)
+
+var scheduleConfigPath string
+
+var scheduleCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "Run missions on their schedules",
+	Long: `Run every mission that declares a schedule block on its cadence, in the foreground, until interrupted.
+
+Each run is stored as its own mission record. Before a run starts it is claimed in the store, so several
+'squadron schedule' processes sharing a Postgres storage backend start each scheduled run only once.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadScheduleConfig()
+
+		stores, err := store.NewBundle(cfg.Storage)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: init stores: %v\n", err)
+			os.Exit(1)
+		}
+		defer stores.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		r := &scheduledRuns{ctx: ctx, cfg: cfg}
+		r.sched = scheduler.New(r.fire)
+		r.sched.SetLocker(stores.Schedules)
+		r.sched.UpdateConfig(cfg)
+
+		fmt.Println("Scheduler started. Upcoming runs:")
+		printUpcoming(cfg)
+
+		sigs := make(chan os.Signal, 1)
+		signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+		<-sigs
+
+		fmt.Println("\nStopping scheduler; cancelling running missions...")
+		r.sched.Stop()
+		cancel()
+		r.wg.Wait()
+	},
+}
+
+var scheduleListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Show the next run of every schedule",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		printUpcoming(loadScheduleConfig())
+	},
+}
+
+// loadScheduleConfig loads the config for the schedule commands and exits
+// if no mission declares a schedule.
+func loadScheduleConfig() *config.Config {
+	if err := applyHome(scheduleConfigPath); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if err := EnsureInitialized(false); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	cfg, err := config.LoadAndValidate(scheduleConfigPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+	for _, m := range cfg.Missions {
+		if len(m.Schedules) > 0 {
+			return cfg
+		}
+	}
+	fmt.Fprintln(os.Stderr, "Error: no mission declares a schedule block")
+	os.Exit(1)
+	return nil
+}
+
+func printUpcoming(cfg *config.Config) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "MISSION\tSCHEDULE\tCRON\tNEXT RUN")
+	for _, run := range scheduler.Upcoming(cfg, time.Now()) {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", run.MissionName, run.Source, run.Cron, run.At.Format("2006-01-02 15:04 MST"))
+	}
+	w.Flush()
+}
+
+// scheduledRuns starts missions when the scheduler fires and tracks them
+// so shutdown can wait for cancelled runs to record their status.
+type scheduledRuns struct {
+	ctx   context.Context
+	cfg   *config.Config
+	sched *scheduler.Scheduler
+	wg    sync.WaitGroup
+}
+
+func (r *scheduledRuns) fire(missionName, source string, inputs map[string]string) {
+	if !r.sched.NotifyMissionStarted(missionName) {
+		log.Printf("schedule: mission %q at capacity, skipping %s", missionName, source)
+		return
+	}
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		defer r.sched.NotifyMissionDone(missionName)
+
+		log.Printf("schedule: starting mission %q (%s)", missionName, source)
+		debugLogger, _ := mission.NewDebugLogger("")
+		runner, err := mission.NewRunner(r.cfg, scheduleConfigPath, missionName, inputs, mission.WithDebugLogger(debugLogger))
+		if err != nil {
+			log.Printf("schedule: mission %q (%s): %v", missionName, source, err)
+			return
+		}
+
+		streamer := streamers.NewStoringMissionHandler(cli.NewMissionHandler(), runner.EventStore(), runner.CostStore())
+		err = runner.Run(r.ctx, streamer)
+		runner.CloseStores()
+		if err != nil {
+			log.Printf("schedule: mission %q (%s) failed: %v", missionName, source, err)
+			return
+		}
+		log.Printf("schedule: mission %q (%s) completed", missionName, source)
+	}()
+}
+
+func init() {
+	rootCmd.AddCommand(scheduleCmd)
+	scheduleCmd.AddCommand(scheduleListCmd)
+	scheduleCmd.PersistentFlags().StringVarP(&scheduleConfigPath, "config", "c", ".", "Path to config file or directory")
+}
@@ -0,0 +1,399 @@
+package cmd
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"squadron/store"
+
+	"github.com/spf13/cobra"
+)
+
+var sessionsConfigPath string
+var sessionsExportTask string
+var sessionsExportFormat string
+var sessionsExportOutput string
+
+const (
+	transcriptFormatMarkdown = "md"
+	transcriptFormatHTML     = "html"
+)
+
+var sessionsCmd = &cobra.Command{
+	Use:   "sessions",
+	Short: "Work with the commander and agent sessions of past mission runs",
+}
+
+var sessionsExportCmd = &cobra.Command{
+	Use:   "export [mission_id]",
+	Short: "Export a mission run's sessions as a Markdown or HTML transcript",
+	Long: `Render the stored commander and agent sessions of a mission run, with
+their reasoning, tool calls, observations, and answers, as a transcript for
+review and sharing. Writes to stdout unless --output is given.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if sessionsExportFormat != transcriptFormatMarkdown && sessionsExportFormat != transcriptFormatHTML {
+			fmt.Fprintf(os.Stderr, "Error: unknown format %q: must be %q or %q\n", sessionsExportFormat, transcriptFormatMarkdown, transcriptFormatHTML)
+			os.Exit(1)
+		}
+		_, stores := loadConfigAndStores(sessionsConfigPath)
+		defer stores.Close()
+
+		t, err := loadTranscript(stores, args[0], sessionsExportTask)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		out := io.Writer(os.Stdout)
+		if sessionsExportOutput != "" {
+			f, err := os.Create(sessionsExportOutput)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			defer f.Close()
+			out = f
+		}
+		if sessionsExportFormat == transcriptFormatHTML {
+			err = writeTranscriptHTML(out, t)
+		} else {
+			err = writeTranscriptMarkdown(out, t)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error exporting sessions: %v\n", err)
+			os.Exit(1)
+		}
+		if sessionsExportOutput != "" {
+			fmt.Fprintf(os.Stderr, "Exported %d sessions to %s\n", t.sessionCount(), sessionsExportOutput)
+		}
+	},
+}
+
+// transcript is a mission run's sessions, grouped by task in run order.
+type transcript struct {
+	MissionID   string
+	MissionName string
+	Status      string
+	StartedAt   time.Time
+	Tasks       []transcriptTask
+}
+
+type transcriptTask struct {
+	Name     string
+	Status   string
+	Sessions []transcriptSession
+}
+
+type transcriptSession struct {
+	Role      string // "commander" or "agent"
+	AgentName string
+	Model     string
+	Status    string
+	Iteration *int
+	StartedAt time.Time
+	Entries   []transcriptEntry
+}
+
+// Title names the session, e.g. "Agent researcher (iteration 2)".
+func (s transcriptSession) Title() string {
+	title := "Session"
+	if s.Role != "" {
+		title = strings.ToUpper(s.Role[:1]) + s.Role[1:]
+	}
+	if s.AgentName != "" {
+		title += " " + s.AgentName
+	}
+	if s.Iteration != nil {
+		title += fmt.Sprintf(" (iteration %d)", *s.Iteration)
+	}
+	return title
+}
+
+// Entry kinds, in the words of the transcript.
+const (
+	entrySystem      = "System prompt"
+	entryUser        = "Input"
+	entryReasoning   = "Reasoning"
+	entryAssistant   = "Response"
+	entryAnswer      = "Answer"
+	entryToolCall    = "Tool call"
+	entryObservation = "Observation"
+)
+
+// transcriptEntry is one rendered piece of a message. Code entries (tool
+// inputs and observations) are shown preformatted.
+type transcriptEntry struct {
+	Kind    string
+	Tool    string
+	Text    string
+	Code    bool
+	IsError bool
+}
+
+// Heading labels the entry, e.g. "Tool call: http_get".
+func (e transcriptEntry) Heading() string {
+	h := e.Kind
+	if e.Tool != "" {
+		h += ": " + e.Tool
+	}
+	if e.IsError {
+		h += " (error)"
+	}
+	return h
+}
+
+func (t transcript) sessionCount() int {
+	n := 0
+	for _, task := range t.Tasks {
+		n += len(task.Sessions)
+	}
+	return n
+}
+
+// loadTranscript reads the sessions of a mission run, or of one of its
+// tasks when taskName is set.
+func loadTranscript(stores *store.Bundle, missionID, taskName string) (transcript, error) {
+	rec, err := stores.Missions.GetMission(missionID)
+	if err != nil {
+		return transcript{}, fmt.Errorf("mission %s not found: %w", missionID, err)
+	}
+	tasks, err := stores.Missions.GetTasksByMission(missionID)
+	if err != nil {
+		return transcript{}, fmt.Errorf("loading tasks: %w", err)
+	}
+	if taskName != "" {
+		var matched []store.MissionTask
+		for _, t := range tasks {
+			if t.TaskName == taskName {
+				matched = append(matched, t)
+			}
+		}
+		if len(matched) == 0 {
+			return transcript{}, fmt.Errorf("task '%s' not found in mission %s", taskName, missionID)
+		}
+		tasks = matched
+	}
+
+	sessions := make(map[string][]store.SessionInfo, len(tasks))
+	messages := make(map[string][]store.StructuredMessage)
+	for _, t := range tasks {
+		if sessions[t.ID], err = stores.Sessions.GetSessionsByTask(t.ID); err != nil {
+			return transcript{}, fmt.Errorf("loading sessions of task %s: %w", t.TaskName, err)
+		}
+		for _, s := range sessions[t.ID] {
+			if messages[s.ID], err = stores.Sessions.GetStructuredMessages(s.ID); err != nil {
+				return transcript{}, fmt.Errorf("loading messages of session %s: %w", s.ID, err)
+			}
+		}
+	}
+	return buildTranscript(rec, tasks, sessions, messages), nil
+}
+
+// buildTranscript assembles a transcript from store rows. sessions is keyed
+// by task ID and messages by session ID.
+func buildTranscript(rec *store.MissionRecord, tasks []store.MissionTask, sessions map[string][]store.SessionInfo, messages map[string][]store.StructuredMessage) transcript {
+	t := transcript{
+		MissionID:   rec.ID,
+		MissionName: rec.MissionName,
+		Status:      rec.Status,
+		StartedAt:   rec.StartedAt,
+	}
+	for _, task := range tasks {
+		tt := transcriptTask{Name: task.TaskName, Status: task.Status}
+		for _, s := range sessions[task.ID] {
+			tt.Sessions = append(tt.Sessions, transcriptSession{
+				Role:      s.Role,
+				AgentName: s.AgentName,
+				Model:     s.Model,
+				Status:    s.Status,
+				Iteration: s.IterationIndex,
+				StartedAt: s.StartedAt,
+				Entries:   transcriptEntries(messages[s.ID]),
+			})
+		}
+		t.Tasks = append(t.Tasks, tt)
+	}
+	return t
+}
+
+// transcriptEntries turns a session's messages into entries. Tool results
+// are labeled with the name of the call they answer.
+func transcriptEntries(msgs []store.StructuredMessage) []transcriptEntry {
+	var entries []transcriptEntry
+	toolNames := map[string]string{}
+	for _, m := range msgs {
+		if m.Role == "system" {
+			entries = append(entries, transcriptEntry{Kind: entrySystem, Text: m.Content})
+			continue
+		}
+		if len(m.Parts) == 0 {
+			entries = append(entries, textEntries(m.Role, m.Content)...)
+			continue
+		}
+		for _, p := range m.Parts {
+			switch p.Type {
+			case "text":
+				entries = append(entries, textEntries(m.Role, p.Text)...)
+			case "thinking":
+				if strings.TrimSpace(p.Text) != "" {
+					entries = append(entries, transcriptEntry{Kind: entryReasoning, Text: p.Text})
+				}
+			case "tool_use":
+				toolNames[p.ToolUseID] = p.ToolName
+				entries = append(entries, transcriptEntry{Kind: entryToolCall, Tool: p.ToolName, Text: p.ToolInputJSON, Code: true})
+			case "tool_result":
+				entries = append(entries, transcriptEntry{
+					Kind:    entryObservation,
+					Tool:    toolNames[p.ToolUseID],
+					Text:    p.Text,
+					Code:    true,
+					IsError: p.IsError != nil && *p.IsError,
+				})
+			case "image":
+				entries = append(entries, transcriptEntry{Kind: entryUser, Text: fmt.Sprintf("[image: %s]", p.ImageMediaType)})
+			}
+		}
+	}
+	return entries
+}
+
+var answerPattern = regexp.MustCompile(`(?s)<ANSWER>(.*?)</ANSWER>`)
+
+// textEntries splits a text block into entries: an assistant's final
+// answer is pulled out of its <ANSWER> tags and shown after the rest.
+func textEntries(role, text string) []transcriptEntry {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil
+	}
+	if role != "assistant" {
+		return []transcriptEntry{{Kind: entryUser, Text: text}}
+	}
+	var entries []transcriptEntry
+	var answers []string
+	for _, m := range answerPattern.FindAllStringSubmatch(text, -1) {
+		answers = append(answers, strings.TrimSpace(m[1]))
+	}
+	if rest := strings.TrimSpace(answerPattern.ReplaceAllString(text, "")); rest != "" {
+		entries = append(entries, transcriptEntry{Kind: entryAssistant, Text: rest})
+	}
+	for _, a := range answers {
+		entries = append(entries, transcriptEntry{Kind: entryAnswer, Text: a})
+	}
+	return entries
+}
+
+// writeTranscriptMarkdown renders t as Markdown. System prompts are folded
+// into <details> blocks.
+func writeTranscriptMarkdown(w io.Writer, t transcript) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Mission %s\n\n", t.MissionName)
+	fmt.Fprintf(&b, "- **Run:** `%s`\n- **Status:** %s\n- **Started:** %s\n", t.MissionID, t.Status, t.StartedAt.Local().Format("2006-01-02 15:04:05"))
+	for _, task := range t.Tasks {
+		fmt.Fprintf(&b, "\n## Task %s (%s)\n", task.Name, task.Status)
+		if len(task.Sessions) == 0 {
+			b.WriteString("\nNo sessions recorded.\n")
+		}
+		for _, s := range task.Sessions {
+			fmt.Fprintf(&b, "\n### %s\n\n", s.Title())
+			fmt.Fprintf(&b, "_Model %s, %s, started %s_\n", s.Model, s.Status, s.StartedAt.Local().Format("15:04:05"))
+			for _, e := range s.Entries {
+				if e.Kind == entrySystem {
+					fmt.Fprintf(&b, "\n<details>\n<summary>%s</summary>\n\n%s\n</details>\n", e.Heading(), markdownFence(e.Text, ""))
+					continue
+				}
+				fmt.Fprintf(&b, "\n**%s**\n\n", e.Heading())
+				if e.Code {
+					b.WriteString(markdownFence(e.Text, codeLanguage(e.Text)))
+				} else {
+					b.WriteString(e.Text + "\n")
+				}
+			}
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// markdownFence wraps text in a code fence longer than any backtick run in
+// it, so tool output containing fences can't break out.
+func markdownFence(text, lang string) string {
+	longest, run := 0, 0
+	for _, r := range text {
+		if r == '`' {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+	fence := strings.Repeat("`", max(3, longest+1))
+	return fence + lang + "\n" + strings.TrimRight(text, "\n") + "\n" + fence + "\n"
+}
+
+// codeLanguage returns "json" for text that looks like JSON.
+func codeLanguage(text string) string {
+	text = strings.TrimSpace(text)
+	if strings.HasPrefix(text, "{") || strings.HasPrefix(text, "[") {
+		return "json"
+	}
+	return ""
+}
+
+var transcriptHTMLTemplate = template.Must(template.New("transcript").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Mission {{.MissionName}} ({{.MissionID}})</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", sans-serif; max-width: 960px; margin: 2rem auto; padding: 0 1rem; color: #1f2328; }
+h2 { border-bottom: 1px solid #d0d7de; padding-bottom: .3rem; margin-top: 2.5rem; }
+.meta { color: #656d76; font-size: .9rem; }
+.entry { margin: 1rem 0; padding: .5rem .75rem; border-left: 3px solid #d0d7de; }
+.entry h4 { margin: 0 0 .4rem; font-size: .85rem; text-transform: uppercase; letter-spacing: .03em; color: #656d76; }
+.entry.answer { border-color: #1a7f37; }
+.entry.reasoning { border-color: #8250df; }
+.entry.error { border-color: #cf222e; }
+pre { background: #f6f8fa; padding: .75rem; overflow-x: auto; white-space: pre-wrap; word-break: break-word; }
+.text { white-space: pre-wrap; }
+</style>
+</head>
+<body>
+<h1>Mission {{.MissionName}}</h1>
+<p class="meta">Run <code>{{.MissionID}}</code> &middot; {{.Status}} &middot; started {{.StartedAt.Local.Format "2006-01-02 15:04:05"}}</p>
+{{range .Tasks}}
+<h2>Task {{.Name}} <span class="meta">({{.Status}})</span></h2>
+{{if not .Sessions}}<p>No sessions recorded.</p>{{end}}
+{{range .Sessions}}
+<h3>{{.Title}}</h3>
+<p class="meta">Model {{.Model}}, {{.Status}}, started {{.StartedAt.Local.Format "15:04:05"}}</p>
+{{range .Entries}}
+{{if eq .Kind "System prompt"}}<details><summary>{{.Heading}}</summary><pre>{{.Text}}</pre></details>
+{{else}}<div class="entry{{if eq .Kind "Answer"}} answer{{end}}{{if eq .Kind "Reasoning"}} reasoning{{end}}{{if .IsError}} error{{end}}">
+<h4>{{.Heading}}</h4>
+{{if .Code}}<pre>{{.Text}}</pre>{{else}}<div class="text">{{.Text}}</div>{{end}}
+</div>
+{{end}}{{end}}{{end}}{{end}}
+</body>
+</html>
+`))
+
+// writeTranscriptHTML renders t as a standalone HTML page.
+func writeTranscriptHTML(w io.Writer, t transcript) error {
+	return transcriptHTMLTemplate.Execute(w, t)
+}
+
+func init() {
+	rootCmd.AddCommand(sessionsCmd)
+	sessionsCmd.AddCommand(sessionsExportCmd)
+	sessionsCmd.PersistentFlags().StringVarP(&sessionsConfigPath, "config", "c", ".", "Path to config file or directory")
+	sessionsExportCmd.Flags().StringVarP(&sessionsExportTask, "task", "t", "", "Only export the sessions of this task")
+	sessionsExportCmd.Flags().StringVarP(&sessionsExportFormat, "format", "f", transcriptFormatMarkdown, "Output format: md or html")
+	sessionsExportCmd.Flags().StringVarP(&sessionsExportOutput, "output", "o", "", "Write to this file instead of stdout")
+}
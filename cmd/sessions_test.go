@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"squadron/store"
+)
+
+func sampleTranscript() transcript {
+	isErr := true
+	rec := &store.MissionRecord{ID: "m1", MissionName: "research", Status: "completed", StartedAt: time.Now()}
+	tasks := []store.MissionTask{{ID: "t1", TaskName: "plan", Status: "completed"}}
+	sessions := map[string][]store.SessionInfo{
+		"t1": {
+			{ID: "s1", Role: "commander", Model: "claude-sonnet-4", Status: "completed"},
+			{ID: "s2", Role: "agent", AgentName: "researcher", Model: "gpt-4o", Status: "completed", IterationIndex: intPtr(1)},
+		},
+	}
+	messages := map[string][]store.StructuredMessage{
+		"s1": {
+			{Role: "system", Content: "You are a commander."},
+			{Role: "user", Parts: []store.MessagePart{{Type: "text", Text: "Plan the <script>alert(1)</script> research"}}},
+			{Role: "assistant", Parts: []store.MessagePart{
+				{Type: "thinking", Text: "I should ask the researcher."},
+				{Type: "text", Text: "Calling the researcher."},
+				{Type: "tool_use", ToolUseID: "call_1", ToolName: "call_agent", ToolInputJSON: `{"name":"researcher"}`},
+			}},
+			{Role: "user", Parts: []store.MessagePart{{Type: "tool_result", ToolUseID: "call_1", Text: "no results", IsError: &isErr}}},
+			{Role: "assistant", Parts: []store.MessagePart{{Type: "text", Text: "Done.\n<ANSWER>Three sources found.</ANSWER>"}}},
+		},
+		"s2": {
+			{Role: "assistant", Content: "legacy flat text"},
+		},
+	}
+	return buildTranscript(rec, tasks, sessions, messages)
+}
+
+func TestBuildTranscript(t *testing.T) {
+	tr := sampleTranscript()
+
+	if len(tr.Tasks) != 1 || len(tr.Tasks[0].Sessions) != 2 {
+		t.Fatalf("unexpected shape: %+v", tr.Tasks)
+	}
+	commander := tr.Tasks[0].Sessions[0]
+	var kinds []string
+	for _, e := range commander.Entries {
+		kinds = append(kinds, e.Heading())
+	}
+	want := []string{"System prompt", "Input", "Reasoning", "Response", "Tool call: call_agent", "Observation: call_agent (error)", "Response", "Answer"}
+	if strings.Join(kinds, "|") != strings.Join(want, "|") {
+		t.Fatalf("entries = %v, want %v", kinds, want)
+	}
+	if a := commander.Entries[7]; a.Text != "Three sources found." {
+		t.Fatalf("answer not extracted: %q", a.Text)
+	}
+
+	agent := tr.Tasks[0].Sessions[1]
+	if agent.Title() != "Agent researcher (iteration 1)" {
+		t.Fatalf("unexpected title %q", agent.Title())
+	}
+	if len(agent.Entries) != 1 || agent.Entries[0].Text != "legacy flat text" {
+		t.Fatalf("legacy content should fall back to Content, got %+v", agent.Entries)
+	}
+}
+
+func TestWriteTranscriptMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeTranscriptMarkdown(&buf, sampleTranscript()); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"# Mission research",
+		"## Task plan (completed)",
+		"### Commander",
+		"<summary>System prompt</summary>",
+		"**Tool call: call_agent**\n\n```json\n{\"name\":\"researcher\"}\n```",
+		"**Answer**\n\nThree sources found.",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Fatalf("markdown missing %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestWriteTranscriptHTMLEscapesContent(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeTranscriptHTML(&buf, sampleTranscript()); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if strings.Contains(out, "<script>") {
+		t.Fatal("message content should be escaped")
+	}
+	for _, want := range []string{"&lt;script&gt;", `class="entry answer"`, "Observation: call_agent (error)", "Agent researcher (iteration 1)"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("html missing %q", want)
+		}
+	}
+}
+
+func TestMarkdownFenceOutlastsBackticks(t *testing.T) {
+	got := markdownFence("before\n```\ninside\n```", "")
+	if !strings.HasPrefix(got, "````\n") || !strings.HasSuffix(got, "\n````\n") {
+		t.Fatalf("fence should be longer than the content's, got:\n%s", got)
+	}
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"squadron/config"
+	"squadron/store"
+
+	"github.com/spf13/cobra"
+)
+
+var storeConfigPath string
+var storeGCDryRun bool
+
+var storeCmd = &cobra.Command{
+	Use:   "store",
+	Short: "Maintain the mission store",
+}
+
+var storeGCCmd = &cobra.Command{
+	Use:   "gc",
+	Short: "Delete or compact old missions per the storage retention policy",
+	Long: `Apply the retention block of the storage config: delete finished missions
+past max_age_days or beyond max_missions, and redact the message bodies and
+tool results of those past compact_after_days. Running and paused missions
+are never touched. Use --dry-run to list what would change.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, stores := loadConfigAndStores(storeConfigPath)
+		defer stores.Close()
+
+		if cfg.Storage.Retention == nil {
+			fmt.Fprintln(os.Stderr, "Error: no retention policy: add a retention block to the storage config")
+			os.Exit(1)
+		}
+		res, err := stores.GC(retentionPolicy(cfg.Storage.Retention, storeGCDryRun), time.Now())
+		if res != nil {
+			printGCResult(res, storeGCDryRun)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func retentionPolicy(r *config.RetentionConfig, dryRun bool) store.RetentionPolicy {
+	return store.RetentionPolicy{
+		MaxAge:       r.MaxAge(),
+		MaxMissions:  r.MaxMissions,
+		CompactAfter: r.CompactAfter(),
+		DryRun:       dryRun,
+	}
+}
+
+func printGCResult(res *store.GCResult, dryRun bool) {
+	deleted, compacted := "Deleted", "Compacted"
+	if dryRun {
+		deleted, compacted = "Would delete", "Would compact"
+	}
+	for _, id := range res.Deleted {
+		fmt.Printf("%s mission %s\n", deleted, id)
+	}
+	for _, id := range res.Compacted {
+		fmt.Printf("%s mission %s\n", compacted, id)
+	}
+	fmt.Printf("%s %d missions, %s %d", deleted, len(res.Deleted), strings.ToLower(compacted), len(res.Compacted))
+	if !dryRun {
+		fmt.Printf(", removed %d artifact blobs", res.Blobs)
+	}
+	fmt.Println()
+}
+
+func init() {
+	rootCmd.AddCommand(storeCmd)
+	storeCmd.AddCommand(storeGCCmd)
+	storeCmd.PersistentFlags().StringVarP(&storeConfigPath, "config", "c", ".", "Path to config file or directory")
+	storeGCCmd.Flags().BoolVar(&storeGCDryRun, "dry-run", false, "List the missions that would be deleted or compacted without changing the store")
+}
@@ -0,0 +1,255 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"squadron/config"
+	"squadron/store"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	usersConfigPath string
+	usersRole       string
+	usersEmail      string
+	usersKeyName    string
+	usersKeyExpires time.Duration
+)
+
+var usersCmd = &cobra.Command{
+	Use:   "users",
+	Short: "Manage the users and API keys of squadron api",
+	Long: `Manage the users of 'squadron api'. Once the store has a user (or an api
+oidc block is configured), the API runs in multi-user mode: every request
+authenticates with a user's API key or login session and is authorized by
+the user's role:
+
+  viewer     read missions, tasks and events
+  operator   also start missions, and stop or cancel the ones they started
+  admin      everything, including managing users`,
+}
+
+var usersAddCmd = &cobra.Command{
+	Use:   "add NAME",
+	Short: "Add a user",
+	Long: `Add a user. To let someone log in with OIDC under a given role, add them
+by their email address; their first login links the identity to this user.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if !config.ValidRole(usersRole) {
+			exitf("unknown role %q (expected viewer, operator, or admin)", usersRole)
+		}
+		users := openUserStore()
+		defer users.close()
+		if existing, err := users.GetUserByName(args[0]); err != nil || existing != nil {
+			exitf("user %q already exists", args[0])
+		}
+		if _, err := users.CreateUser(store.UserRecord{Name: args[0], Email: usersEmail, Role: usersRole}); err != nil {
+			exitf("%v", err)
+		}
+		fmt.Printf("Added %s as %s. Create an API key with: squadron users key %s\n", args[0], usersRole, args[0])
+	},
+}
+
+var usersListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List users",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		users := openUserStore()
+		defer users.close()
+		list, err := users.ListUsers()
+		if err != nil {
+			exitf("%v", err)
+		}
+		if len(list) == 0 {
+			fmt.Println("No users. squadron api runs in single-user mode.")
+			return
+		}
+		printUsers(os.Stdout, list)
+	},
+}
+
+var usersRoleCmd = &cobra.Command{
+	Use:   "role NAME ROLE",
+	Short: "Change a user's role",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		if !config.ValidRole(args[1]) {
+			exitf("unknown role %q (expected viewer, operator, or admin)", args[1])
+		}
+		users := openUserStore()
+		defer users.close()
+		user := users.mustGet(args[0])
+		if err := users.SetUserRole(user.ID, args[1]); err != nil {
+			exitf("%v", err)
+		}
+		fmt.Printf("%s is now %s\n", user.Name, args[1])
+	},
+}
+
+var usersRemoveCmd = &cobra.Command{
+	Use:   "remove NAME",
+	Short: "Remove a user and revoke their API keys",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		users := openUserStore()
+		defer users.close()
+		user := users.mustGet(args[0])
+		if err := users.DeleteUser(user.ID); err != nil {
+			exitf("%v", err)
+		}
+		fmt.Printf("Removed %s\n", user.Name)
+	},
+}
+
+var usersKeyCmd = &cobra.Command{
+	Use:   "key NAME",
+	Short: "Create an API key for a user",
+	Long: `Create an API key for a user. The key is printed once; the store keeps only
+a hash of it. Send it as "Authorization: Bearer <key>" (or ?token=).`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		users := openUserStore()
+		defer users.close()
+		user := users.mustGet(args[0])
+		var expiresAt *time.Time
+		if usersKeyExpires > 0 {
+			t := time.Now().UTC().Add(usersKeyExpires)
+			expiresAt = &t
+		}
+		rec, key, err := users.CreateAPIKey(user.ID, usersKeyName, store.KeyKindAPI, expiresAt)
+		if err != nil {
+			exitf("%v", err)
+		}
+		fmt.Printf("Created key %s for %s:\n\n  %s\n\nStore it now; it can't be shown again.\n", rec.ID, user.Name, key)
+	},
+}
+
+var usersKeysCmd = &cobra.Command{
+	Use:   "keys NAME",
+	Short: "List a user's API keys",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		users := openUserStore()
+		defer users.close()
+		keys, err := users.ListAPIKeys(users.mustGet(args[0]).ID)
+		if err != nil {
+			exitf("%v", err)
+		}
+		printAPIKeys(os.Stdout, keys)
+	},
+}
+
+var usersRevokeCmd = &cobra.Command{
+	Use:   "revoke KEY_ID",
+	Short: "Revoke an API key",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		users := openUserStore()
+		defer users.close()
+		key, err := users.GetAPIKey(args[0])
+		if err != nil {
+			exitf("%v", err)
+		}
+		if key == nil {
+			exitf("key %s not found", args[0])
+		}
+		if err := users.RevokeAPIKey(key.ID); err != nil {
+			exitf("%v", err)
+		}
+		fmt.Printf("Revoked key %s\n", key.ID)
+	},
+}
+
+// userStore is the user store of an open bundle.
+type userStore struct {
+	store.UserStore
+	bundle *store.Bundle
+}
+
+func openUserStore() *userStore {
+	_, stores := loadConfigAndStores(usersConfigPath)
+	return &userStore{UserStore: stores.Users, bundle: stores}
+}
+
+func (u *userStore) close() { u.bundle.Close() }
+
+// mustGet returns the named user, exiting when there is none.
+func (u *userStore) mustGet(name string) *store.UserRecord {
+	user, err := u.GetUserByName(name)
+	if err != nil {
+		exitf("%v", err)
+	}
+	if user == nil {
+		exitf("user %q not found", name)
+	}
+	return user
+}
+
+func exitf(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, "Error: "+format+"\n", args...)
+	os.Exit(1)
+}
+
+func printUsers(out io.Writer, users []store.UserRecord) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tROLE\tEMAIL\tOIDC\tCREATED")
+	for _, u := range users {
+		linked := "-"
+		if u.Subject != "" {
+			linked = "linked"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", u.Name, u.Role, orDash(u.Email), linked, u.CreatedAt.Local().Format("2006-01-02"))
+	}
+	w.Flush()
+}
+
+func printAPIKeys(out io.Writer, keys []store.APIKeyRecord) {
+	if len(keys) == 0 {
+		fmt.Fprintln(out, "No API keys.")
+		return
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tCREATED\tLAST USED\tSTATUS")
+	now := time.Now()
+	for _, k := range keys {
+		lastUsed := "never"
+		if k.LastUsedAt != nil {
+			lastUsed = k.LastUsedAt.Local().Format("2006-01-02 15:04")
+		}
+		status := "active"
+		switch {
+		case k.RevokedAt != nil:
+			status = "revoked"
+		case k.ExpiresAt != nil && !now.Before(*k.ExpiresAt):
+			status = "expired"
+		case k.ExpiresAt != nil:
+			status = "expires " + k.ExpiresAt.Local().Format("2006-01-02")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", k.ID, orDash(k.Name), k.CreatedAt.Local().Format("2006-01-02"), lastUsed, status)
+	}
+	w.Flush()
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func init() {
+	rootCmd.AddCommand(usersCmd)
+	usersCmd.AddCommand(usersAddCmd, usersListCmd, usersRoleCmd, usersRemoveCmd, usersKeyCmd, usersKeysCmd, usersRevokeCmd)
+	usersCmd.PersistentFlags().StringVarP(&usersConfigPath, "config", "c", ".", "Path to config file or directory")
+	usersAddCmd.Flags().StringVar(&usersRole, "role", config.RoleViewer, "Role: viewer, operator, or admin")
+	usersAddCmd.Flags().StringVar(&usersEmail, "email", "", "Email address")
+	usersKeyCmd.Flags().StringVar(&usersKeyName, "name", "", "Label for the key, e.g. where it is used")
+	usersKeyCmd.Flags().DurationVar(&usersKeyExpires, "expires", 0, "Expire the key after this long, e.g. 720h (default: never)")
+}
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"squadron/store"
+)
+
+func TestPrintAPIKeys(t *testing.T) {
+	now := time.Now()
+	past, future := now.Add(-time.Hour), now.Add(48*time.Hour)
+	keys := []store.APIKeyRecord{
+		{ID: "k1", Name: "ci", CreatedAt: now, LastUsedAt: &now},
+		{ID: "k2", CreatedAt: now, RevokedAt: &past},
+		{ID: "k3", CreatedAt: now, ExpiresAt: &past},
+		{ID: "k4", CreatedAt: now, ExpiresAt: &future},
+	}
+	var buf bytes.Buffer
+	printAPIKeys(&buf, keys)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("expected header and 4 rows, got:\n%s", buf.String())
+	}
+	for i, want := range []string{"active", "revoked", "expired", "expires " + future.Local().Format("2006-01-02")} {
+		if !strings.HasSuffix(lines[i+1], want) {
+			t.Errorf("row %d: expected status %q, got %q", i+1, want, lines[i+1])
+		}
+	}
+	if !strings.Contains(lines[2], "never") {
+		t.Errorf("expected an unused key to show 'never', got %q", lines[2])
+	}
+
+	buf.Reset()
+	printAPIKeys(&buf, nil)
+	if buf.String() != "No API keys.\n" {
+		t.Fatalf("unexpected output for no keys: %q", buf.String())
+	}
+}
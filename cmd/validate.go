@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"squadron/config"
+
+	"github.com/spf13/cobra"
+)
+
+var validateMission string
+var validateJSON bool
+
+var validateCmd = &cobra.Command{
+	Use:   "validate [path]",
+	Short: "Statically analyze missions without running them",
+	Long: `Validate loads the HCL configuration and runs static analysis over every
+mission: dependency cycles, undefined agents/models/datasets, objectives that
+reference unknown or protected inputs, unset variables, item fields missing
+from the dataset schema or from the output an iterator maps over, and when
+conditions that read undeclared output fields. Nothing is executed. Mission
+analysis findings are all reported at once; structural config errors (cycles,
+undefined agents/models) stop at the first one. Exits non-zero if any error
+is found.
+
+Use verify to check that the config loads, see what it defines, and install
+its gateway; verify stops at the first error and skips the mission analysis.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		configPath := "."
+		if len(args) > 0 {
+			configPath = args[0]
+		}
+		if err := applyHome(configPath); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		cfg, err := config.Load(configPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		var diags []config.Diagnostic
+		if err := cfg.Validate(); err != nil {
+			diags = append(diags, config.Diagnostic{Severity: config.SeverityError, Message: err.Error()})
+		}
+		for _, d := range cfg.Analyze(config.ResolveVariableValue) {
+			if validateMission != "" && d.Mission != validateMission {
+				continue
+			}
+			diags = append(diags, d)
+		}
+
+		if validateMission != "" {
+			found := false
+			for _, m := range cfg.Missions {
+				if m.Name == validateMission {
+					found = true
+					break
+				}
+			}
+			if !found {
+				diags = append(diags, config.Diagnostic{Severity: config.SeverityError, Message: fmt.Sprintf("mission '%s' not found", validateMission)})
+			}
+		}
+
+		if validateJSON {
+			if diags == nil {
+				diags = []config.Diagnostic{}
+			}
+			data, _ := json.MarshalIndent(diags, "", "  ")
+			fmt.Println(string(data))
+		} else if len(diags) == 0 {
+			fmt.Println("No problems found.")
+		} else {
+			for _, d := range diags {
+				fmt.Println(d.String())
+			}
+		}
+
+		if config.HasErrors(diags) {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+	validateCmd.Flags().StringVarP(&validateMission, "mission", "m", "", "Only report findings for this mission")
+	validateCmd.Flags().BoolVar(&validateJSON, "json", false, "Print findings as JSON")
+}
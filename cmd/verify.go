@@ -13,7 +13,11 @@ import (
 var verifyCmd = &cobra.Command{
 	Use:   "verify [path]",
 	Short: "Verify that the configuration is valid",
-	Long:  `Verify parses and validates the HCL configuration files. Path can be a file or directory.`,
+	Long: `Verify parses and validates the HCL configuration files, installs the configured
+gateway, and lists what the config defines. Path can be a file or directory.
+
+Verify stops at the first error. To find mission problems that would only
+surface mid-run, and get every finding at once, use validate.`,
 	Args:  cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		configPath := "."
@@ -142,6 +146,9 @@ var verifyCmd = &cobra.Command{
 				if len(t.Agents) > 0 {
 					taskAgents = fmt.Sprintf("%v", t.Agents)
 				}
+				if len(t.Requires) > 0 {
+					taskAgents = fmt.Sprintf("requires %v", t.Requires)
+				}
 				fmt.Printf("      • %s (agents: %s)%s\n", t.Name, taskAgents, deps)
 			}
 		}
@@ -3,6 +3,9 @@ package config
 import (
 	"fmt"
 	"strings"
+	"text/template"
+
+	"squadron/aitools"
 )
 
 // AgentMode defines the operational mode of the agent
@@ -19,15 +22,17 @@ const (
 // ReservedBuiltinNamespaces are names reserved for built-in tools (cannot be
 // used as plugin or mcp server names). "mcp" itself is reserved so that a
 // `plugin "mcp" { ... }` can't shadow the consumer-side namespace.
-var ReservedBuiltinNamespaces = []string{"http", "dataset", "utils", "human", "mcp"}
+var ReservedBuiltinNamespaces = []string{"http", "dataset", "utils", "human", "shell", "sql", "mcp"}
 
 // BuiltinTools maps built-in namespaces to their tools.
 // These are accessed as builtins.http.get, builtins.http.get, etc.
 var BuiltinTools = map[string][]string{
-	"http":    {"get", "post", "put", "patch", "delete"},
+	"http":    {"get", "post", "put", "patch", "delete", "paginate"},
 	"dataset": {"set", "sample", "count"},
 	"utils":   {"sleep", "current_time"},
 	"human":   {"ask"},
+	"shell":   {"run_command", "read_file", "write_file"},
+	"sql":     {"query", "schema", "explain"},
 }
 
 // InternalTools is the list of available internal tools (legacy format for backwards compatibility)
@@ -82,10 +87,26 @@ func IsInternalTool(name string) bool {
 	return false
 }
 
-// Compaction configures context compaction for an agent
+// Compaction configures context compaction for an agent, a commander, or
+// (as a model block's `compaction "<model>"` block) every session on a model.
 type Compaction struct {
-	TokenLimit    int `hcl:"token_limit"`    // Trigger compaction when input tokens exceed this
-	TurnRetention int `hcl:"turn_retention"` // Keep this many recent turns uncompacted
+	TokenLimit    int    `hcl:"token_limit"`            // Trigger compaction when input tokens exceed this
+	TurnRetention int    `hcl:"turn_retention"`         // Keep this many recent turns uncompacted
+	SummaryModel  string `hcl:"summary_model,optional"` // Model key that writes the summary (default: built-in digest)
+}
+
+// Validate checks the token limit and that the summary model exists.
+func (c *Compaction) Validate(models []Model) error {
+	if c.TokenLimit <= 0 {
+		return fmt.Errorf("token_limit must be > 0")
+	}
+	if c.TurnRetention < 0 {
+		return fmt.Errorf("turn_retention must be >= 0")
+	}
+	if c.SummaryModel != "" && !isValidModelRef(c.SummaryModel, models) {
+		return fmt.Errorf("summary_model '%s' not found in models", c.SummaryModel)
+	}
+	return nil
 }
 
 // Pruning configures context pruning for an agent
@@ -104,6 +125,10 @@ type Agent struct {
 	Tools       []string `hcl:"tools,optional"`
 	Skills      []string `hcl:"-"`
 
+	// Capabilities are free-form tags ("web", "sql", "code") that tasks
+	// match with requires instead of naming agents.
+	Capabilities []string `hcl:"-" json:"capabilities,omitempty"`
+
 	// Agent-scoped skills (parsed manually)
 	LocalSkills []Skill `hcl:"-" json:"localSkills,omitempty"`
 
@@ -116,11 +141,35 @@ type Agent struct {
 	// Tool response size limits (optional block)
 	ToolResponse *ToolResponseConfig `hcl:"tool_response,block"`
 
+	// SummarizeBetweenTasks replaces the agent's conversation with a summary
+	// when the commander gives it a new task, instead of keeping every raw
+	// tool result from the earlier ones.
+	SummarizeBetweenTasks bool `hcl:"-" json:"summarizeBetweenTasks,omitempty"`
+
 	// Reasoning controls native provider reasoning (extended thinking on
 	// Anthropic, reasoning_effort on OpenAI, thinking_config on Gemini).
 	// Valid values: "", "low", "medium", "high". Silently no-op on models
 	// that don't support native reasoning.
 	Reasoning string `hcl:"reasoning,optional"`
+
+	// Env and WorkingDir are sent with the agent's plugin tool calls (see
+	// aitools.ToolEnv). A task's own env and working_dir take precedence.
+	Env        map[string]string `hcl:"-" json:"-"` // may hold credentials
+	WorkingDir string            `hcl:"-" json:"workingDir,omitempty"`
+
+	// PromptTemplate replaces the built-in agent prompt. It is a Go
+	// text/template; {{.Default}} is the built-in prompt.
+	PromptTemplate string `hcl:"-" json:"promptTemplate,omitempty"`
+
+	// Output is the structured result the agent must submit before it
+	// answers a mission task, so call_agent returns JSON instead of text.
+	Output *OutputSchema `hcl:"-" json:"output,omitempty"`
+}
+
+// GetToolEnv returns the env and working directory for the agent's plugin
+// tool calls, or nil when it sets neither.
+func (a *Agent) GetToolEnv() *aitools.ToolEnv {
+	return toolEnv(a.Env, a.WorkingDir)
 }
 
 // ToolResponseConfig configures how large tool call responses are handled.
@@ -128,6 +177,19 @@ type ToolResponseConfig struct {
 	// MaxTokens is the approximate max token count for a tool response before it gets truncated/sampled.
 	// Default: 16000. Hard max: 64000. Converted to bytes internally (~4 bytes per token).
 	MaxTokens int `hcl:"max_tokens,optional"`
+	// MaxItems is the most items a JSON array response may have before it gets sampled. Default: 19.
+	MaxItems int `hcl:"max_items,optional"`
+	// Tools override the limits for single tools
+	Tools []ToolResponseOverride `hcl:"tool,block"`
+}
+
+// ToolResponseOverride sets the response limits of one tool. Name is a tool
+// reference (plugins.playwright.browser_get_html) or a bare tool name
+// (browser_get_html), which matches the tool of that name from any source.
+type ToolResponseOverride struct {
+	Name      string `hcl:"name,label"`
+	MaxTokens int    `hcl:"max_tokens,optional"`
+	MaxItems  int    `hcl:"max_items,optional"`
 }
 
 const (
@@ -172,6 +234,23 @@ func (a *Agent) Validate() error {
 		return fmt.Errorf("agent %q: %w", a.Name, err)
 	}
 	a.Reasoning = normalized
+	if err := validateCapabilityTags("capabilities", a.Capabilities); err != nil {
+		return fmt.Errorf("agent %q: %w", a.Name, err)
+	}
+	if err := validatePromptTemplate(a.PromptTemplate); err != nil {
+		return fmt.Errorf("agent %q: %w", a.Name, err)
+	}
+	return nil
+}
+
+// validatePromptTemplate checks that a prompt_template parses.
+func validatePromptTemplate(text string) error {
+	if text == "" {
+		return nil
+	}
+	if _, err := template.New("prompt_template").Parse(text); err != nil {
+		return fmt.Errorf("invalid prompt_template: %w", err)
+	}
 	return nil
 }
 
@@ -15,7 +15,7 @@ var _ = Describe("Agent", func() {
 			Expect(config.IsBuiltinTool("builtins.utils.current_time")).To(BeTrue())
 			Expect(config.BuiltinTools["utils"]).To(ContainElement("current_time"))
 
-			tool := config.GetBuiltinTool("builtins.utils.current_time", nil, nil)
+			tool := config.GetBuiltinTool("builtins.utils.current_time", config.BuiltinSettings{}, nil, nil)
 			Expect(tool).NotTo(BeNil())
 			Expect(tool).To(BeAssignableToTypeOf(&aitools.CurrentTimeTool{}))
 			Expect(tool.ToolName()).To(Equal("current_time"))
@@ -36,7 +36,7 @@ agent "clock" {
 			Expect(cfg.Agents).To(HaveLen(1))
 			Expect(cfg.Agents[0].Tools).To(ConsistOf("builtins.utils.current_time"))
 
-			tools := config.BuildToolsMap(cfg.Agents[0].Tools, nil, nil, nil, nil, nil)
+			tools := config.BuildToolsMap(cfg.Agents[0].Tools, nil, nil, nil, config.BuiltinSettings{}, nil, nil)
 			Expect(tools).To(HaveKey("builtins.utils.current_time"))
 			Expect(tools["builtins.utils.current_time"]).To(BeAssignableToTypeOf(&aitools.CurrentTimeTool{}))
 		})
@@ -116,6 +116,59 @@ agent "compacted" {
 			Expect(cfg.Agents[0].Compaction.TurnRetention).To(Equal(3))
 		})
 
+		It("parses summarize_between_tasks", func() {
+			hcl := minimalVarsHCL() + minimalModelHCL() + `
+agent "summarizer" {
+  model                   = models.anthropic.claude_sonnet_4
+  personality             = "Concise"
+  summarize_between_tasks = true
+}
+`
+			_, f := writeFixture("config.hcl", hcl)
+			cfg, err := config.LoadFile(f)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.Agents[0].SummarizeBetweenTasks).To(BeTrue())
+		})
+
+		It("parses an agent output schema in shorthand and block form", func() {
+			hcl := minimalVarsHCL() + minimalModelHCL() + `
+agent "scout" {
+  model       = models.anthropic.claude_sonnet_4
+  personality = "Precise"
+  output = {
+    vendors = list(string, "Vendor names", true)
+    count   = integer("How many were found")
+  }
+}
+
+agent "grader" {
+  model       = models.anthropic.claude_sonnet_4
+  personality = "Strict"
+  output {
+    field "score" {
+      type     = "number"
+      required = true
+    }
+  }
+}
+`
+			_, f := writeFixture("config.hcl", hcl)
+			cfg, err := config.LoadFile(f)
+			Expect(err).NotTo(HaveOccurred())
+
+			scout := cfg.Agents[0].Output
+			Expect(scout).NotTo(BeNil())
+			Expect(scout.Fields).To(HaveLen(2))
+			Expect(scout.Fields[0].Name).To(Equal("count"))
+			Expect(scout.Fields[0].Type).To(Equal("integer"))
+			Expect(scout.Fields[1].Name).To(Equal("vendors"))
+			Expect(scout.Fields[1].Required).To(BeTrue())
+
+			grader := cfg.Agents[1].Output
+			Expect(grader).NotTo(BeNil())
+			Expect(grader.Fields).To(Equal([]config.OutputField{{Name: "score", Type: "number", Required: true}}))
+		})
+
 		It("parses an agent with no tools", func() {
 			hcl := minimalVarsHCL() + minimalModelHCL() + `
 agent "toolless" {
@@ -195,6 +248,67 @@ agent "huge_limit" {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(cfg.Agents[0].GetToolResponseMaxBytes()).To(Equal(64000 * 4))
 		})
+
+		It("resolves per-tool limits over the config-wide tool_response block", func() {
+			hcl := minimalVarsHCL() + minimalModelHCL() + `
+tool_response {
+  max_tokens = 20000
+  tool "browser_get_html" {
+    max_tokens = 5000
+  }
+  tool "builtins.sql.query" {
+    max_items = 50
+  }
+}
+
+agent "analyst" {
+  model       = models.anthropic.claude_sonnet_4
+  personality = "Thorough"
+  tools       = [builtins.http.get]
+  tool_response {
+    max_items = 10
+    tool "builtins.sql.query" {
+      max_items = 100
+    }
+  }
+}
+`
+			_, f := writeFixture("config.hcl", hcl)
+			cfg, err := config.LoadFile(f)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.ToolResponse.Tools).To(HaveLen(2))
+
+			limits := cfg.ToolResultLimits(cfg.Agents[0].ToolResponse, []string{"plugins.playwright.browser_get_html", "builtins.sql.query"})
+			Expect(limits.ByteThreshold).To(Equal(20000 * 4))
+			Expect(limits.ItemThreshold).To(Equal(11))
+
+			html := limits.ForTool("plugins_playwright_browser_get_html")
+			Expect(html.ByteThreshold).To(Equal(5000 * 4))
+			Expect(html.ItemThreshold).To(Equal(11))
+			Expect(limits.ForTool("plugins.playwright.browser_get_html").ByteThreshold).To(Equal(5000 * 4))
+
+			query := limits.ForTool("builtins_sql_query")
+			Expect(query.ItemThreshold).To(Equal(101))
+			Expect(query.ByteThreshold).To(Equal(20000 * 4))
+
+			Expect(limits.ForTool("http_get").ByteThreshold).To(Equal(20000 * 4))
+		})
+
+		It("rejects a tool override without limits", func() {
+			hcl := minimalVarsHCL() + minimalModelHCL() + `
+agent "analyst" {
+  model       = models.anthropic.claude_sonnet_4
+  personality = "Thorough"
+  tools       = [builtins.http.get]
+  tool_response {
+    tool "http_get" {}
+  }
+}
+`
+			_, f := writeFixture("config.hcl", hcl)
+			_, err := config.LoadFile(f)
+			Expect(err).To(MatchError(ContainSubstring("tool 'http_get': set max_tokens or max_items")))
+		})
 	})
 
 	Describe("Validate (tool references via Config.Validate)", func() {
@@ -0,0 +1,268 @@
+package config
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/tryfunc"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+)
+
+// TaskAggregate describes the `aggregate { ... }` block of an iterated task.
+// Once every iteration succeeds, the runner folds the iteration outputs into
+// a single object and stores it as the task's structured output, so
+// downstream tasks don't have to query each iteration.
+//
+// A reducer expression is evaluated over `iterations`, the list of iteration
+// outputs:
+//
+//	aggregate {
+//	  reduce = {
+//	    total  = sum(iterations[*].score)
+//	    cities = collect(iterations[*].city)
+//	  }
+//	}
+//
+// A prompt instead asks an LLM to aggregate the outputs into a JSON object:
+//
+//	aggregate {
+//	  prompt = "Rank the cities by overall score and explain the top pick"
+//	  model  = models.anthropic.claude_sonnet_4 # defaults to the commander model
+//	}
+type TaskAggregate struct {
+	ReduceExpr hcl.Expression `json:"-"`
+	RawReduce  string         `json:"reduce,omitempty"` // raw reducer source, for display
+	Prompt     string         `json:"prompt,omitempty"`
+	Model      string         `json:"model,omitempty"`
+}
+
+// IsLLM reports whether the aggregate is computed by an LLM prompt rather
+// than a reducer expression.
+func (a *TaskAggregate) IsLLM() bool {
+	return a.ReduceExpr == nil
+}
+
+// parseAggregateBlock parses an iterated task's aggregate block. The reducer
+// expression reads iteration outputs, so it is only checked here and
+// evaluated by the runner once every iteration has finished.
+func parseAggregateBlock(block *hcl.Block, ctx *hcl.EvalContext) (*TaskAggregate, error) {
+	content, diags := block.Body.Content(&hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{
+			{Name: "reduce"},
+			{Name: "prompt"},
+			{Name: "model"},
+		},
+	})
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("aggregate: %w", diags)
+	}
+
+	agg := &TaskAggregate{}
+	if attr, ok := content.Attributes["reduce"]; ok {
+		for _, trav := range attr.Expr.Variables() {
+			switch trav.RootName() {
+			case "iterations", "vars", "inputs":
+			default:
+				return nil, fmt.Errorf("aggregate: unknown reference '%s' in reduce (expected iterations, vars, or inputs)", trav.RootName())
+			}
+		}
+		agg.ReduceExpr = attr.Expr
+		agg.RawReduce = extractExpressionSource(attr.Expr)
+	}
+	if attr, ok := content.Attributes["prompt"]; ok {
+		val, diags := attr.Expr.Value(ctx)
+		if diags.HasErrors() {
+			return nil, fmt.Errorf("aggregate prompt: %w", diags)
+		}
+		if val.Type() != cty.String {
+			return nil, fmt.Errorf("aggregate: prompt must be a string")
+		}
+		agg.Prompt = val.AsString()
+	}
+	if attr, ok := content.Attributes["model"]; ok {
+		val, diags := attr.Expr.Value(ctx)
+		if diags.HasErrors() {
+			return nil, fmt.Errorf("aggregate model: %w", diags)
+		}
+		if val.Type() != cty.String {
+			return nil, fmt.Errorf("aggregate: model must be a model reference")
+		}
+		agg.Model = val.AsString()
+	}
+	return agg, nil
+}
+
+// Validate checks that exactly one of reduce and prompt is set, and that a
+// model is only given with a prompt.
+func (a *TaskAggregate) Validate(models []Model) error {
+	if a.ReduceExpr == nil && a.Prompt == "" {
+		return fmt.Errorf("one of reduce or prompt is required")
+	}
+	if a.ReduceExpr != nil && a.Prompt != "" {
+		return fmt.Errorf("reduce and prompt are mutually exclusive")
+	}
+	if a.Model != "" {
+		if a.ReduceExpr != nil {
+			return fmt.Errorf("model is only used with prompt")
+		}
+		if !isValidModelRef(a.Model, models) {
+			return fmt.Errorf("model '%s' not found in models", a.Model)
+		}
+	}
+	return nil
+}
+
+// EvaluateReduce evaluates the reducer expression with iterations bound to
+// the list of iteration outputs. The result must be an object; it is
+// returned as a Go map ready to store as the task output.
+func (a *TaskAggregate) EvaluateReduce(iterations cty.Value, vars, inputs map[string]cty.Value) (map[string]any, error) {
+	if a.ReduceExpr == nil {
+		return nil, fmt.Errorf("aggregate has no reduce expression")
+	}
+	ctx := &hcl.EvalContext{
+		Variables: map[string]cty.Value{
+			"iterations": iterations,
+			"vars":       cty.ObjectVal(vars),
+			"inputs":     cty.ObjectVal(inputs),
+		},
+		Functions: reduceFunctions,
+	}
+	val, diags := a.ReduceExpr.Value(ctx)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("evaluating aggregate: %s", diags.Error())
+	}
+	if val.IsNull() || !val.IsWhollyKnown() || !(val.Type().IsObjectType() || val.Type().IsMapType()) {
+		return nil, fmt.Errorf("evaluating aggregate: reduce must be an object, got %s", val.Type().FriendlyName())
+	}
+	out, _ := CtyValueToGo(val).(map[string]any)
+	return out, nil
+}
+
+// reduceFunctions are the functions a reducer expression can call. Each
+// takes a list (usually a splat like iterations[*].field) and ignores null
+// elements, so iterations that left a field unset don't break the fold.
+var reduceFunctions = map[string]function.Function{
+	"sum":     numberFold(func(nums []*big.Float) cty.Value { return cty.NumberVal(sumFloats(nums)) }),
+	"mean":    numberFold(meanFloats),
+	"min":     numberFold(func(nums []*big.Float) cty.Value { return pickFloat(nums, -1) }),
+	"max":     numberFold(func(nums []*big.Float) cty.Value { return pickFloat(nums, 1) }),
+	"count":   countFunc,
+	"collect": collectFunc,
+	"try":     tryfunc.TryFunc,
+	"can":     tryfunc.CanFunc,
+}
+
+// nonNullElements returns the non-null elements of a list, tuple, or set.
+func nonNullElements(list cty.Value) ([]cty.Value, error) {
+	ty := list.Type()
+	if !ty.IsListType() && !ty.IsTupleType() && !ty.IsSetType() {
+		return nil, fmt.Errorf("expected a list, got %s", ty.FriendlyName())
+	}
+	if list.IsNull() {
+		return nil, nil
+	}
+	var out []cty.Value
+	for it := list.ElementIterator(); it.Next(); {
+		_, v := it.Element()
+		if !v.IsNull() {
+			out = append(out, v)
+		}
+	}
+	return out, nil
+}
+
+// numberFold builds a list function over the numbers in its argument.
+func numberFold(fold func([]*big.Float) cty.Value) function.Function {
+	return function.New(&function.Spec{
+		Params: []function.Parameter{{Name: "list", Type: cty.DynamicPseudoType}},
+		Type:   function.StaticReturnType(cty.Number),
+		Impl: func(args []cty.Value, _ cty.Type) (cty.Value, error) {
+			elems, err := nonNullElements(args[0])
+			if err != nil {
+				return cty.NilVal, err
+			}
+			nums := make([]*big.Float, len(elems))
+			for i, v := range elems {
+				if v.Type() != cty.Number {
+					return cty.NilVal, fmt.Errorf("element %d is %s, not a number", i, v.Type().FriendlyName())
+				}
+				nums[i] = v.AsBigFloat()
+			}
+			return fold(nums), nil
+		},
+	})
+}
+
+func sumFloats(nums []*big.Float) *big.Float {
+	total := new(big.Float)
+	for _, n := range nums {
+		total.Add(total, n)
+	}
+	return total
+}
+
+// meanFloats is null for an empty list.
+func meanFloats(nums []*big.Float) cty.Value {
+	if len(nums) == 0 {
+		return cty.NullVal(cty.Number)
+	}
+	return cty.NumberVal(new(big.Float).Quo(sumFloats(nums), big.NewFloat(float64(len(nums)))))
+}
+
+// pickFloat returns the smallest (sign -1) or largest (sign 1) number, or
+// null for an empty list.
+func pickFloat(nums []*big.Float, sign int) cty.Value {
+	if len(nums) == 0 {
+		return cty.NullVal(cty.Number)
+	}
+	best := nums[0]
+	for _, n := range nums[1:] {
+		if n.Cmp(best) == sign {
+			best = n
+		}
+	}
+	return cty.NumberVal(best)
+}
+
+var countFunc = function.New(&function.Spec{
+	Params: []function.Parameter{{Name: "list", Type: cty.DynamicPseudoType}},
+	Type:   function.StaticReturnType(cty.Number),
+	Impl: func(args []cty.Value, _ cty.Type) (cty.Value, error) {
+		elems, err := nonNullElements(args[0])
+		if err != nil {
+			return cty.NilVal, err
+		}
+		return cty.NumberIntVal(int64(len(elems))), nil
+	},
+})
+
+// collectFunc returns the non-null elements of a list as a tuple.
+var collectFunc = function.New(&function.Spec{
+	Params: []function.Parameter{{Name: "list", Type: cty.DynamicPseudoType}},
+	Type: func(args []cty.Value) (cty.Type, error) {
+		if !args[0].IsKnown() {
+			return cty.DynamicPseudoType, nil
+		}
+		elems, err := nonNullElements(args[0])
+		if err != nil {
+			return cty.NilType, err
+		}
+		types := make([]cty.Type, len(elems))
+		for i, v := range elems {
+			types[i] = v.Type()
+		}
+		return cty.Tuple(types), nil
+	},
+	Impl: func(args []cty.Value, _ cty.Type) (cty.Value, error) {
+		elems, err := nonNullElements(args[0])
+		if err != nil {
+			return cty.NilVal, err
+		}
+		if len(elems) == 0 {
+			return cty.EmptyTupleVal, nil
+		}
+		return cty.TupleVal(elems), nil
+	},
+})
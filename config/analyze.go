@@ -0,0 +1,293 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+// Diagnostic severities reported by Analyze.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Diagnostic is a single finding from static analysis of a loaded config.
+type Diagnostic struct {
+	Severity string `json:"severity"`
+	Mission  string `json:"mission,omitempty"`
+	Task     string `json:"task,omitempty"`
+	Message  string `json:"message"`
+}
+
+func (d Diagnostic) String() string {
+	scope := ""
+	switch {
+	case d.Mission != "" && d.Task != "":
+		scope = fmt.Sprintf("mission '%s' task '%s': ", d.Mission, d.Task)
+	case d.Mission != "":
+		scope = fmt.Sprintf("mission '%s': ", d.Mission)
+	}
+	return fmt.Sprintf("%s: %s%s", d.Severity, scope, d.Message)
+}
+
+// HasErrors reports whether any diagnostic in the list is an error.
+func HasErrors(diags []Diagnostic) bool {
+	for _, d := range diags {
+		if d.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// Analyze runs static checks that go beyond Validate: problems that parse
+// cleanly but would only surface once a mission is running. It never
+// executes anything and collects every finding instead of stopping at the
+// first one.
+//
+// resolveVar looks up the current value of a variable (vault or default);
+// pass nil to skip the unset-variable checks.
+func (c *Config) Analyze(resolveVar func(*Variable) (string, error)) []Diagnostic {
+	var diags []Diagnostic
+
+	varsByName := make(map[string]*Variable, len(c.Variables))
+	for i := range c.Variables {
+		varsByName[c.Variables[i].Name] = &c.Variables[i]
+	}
+
+	for mi := range c.Missions {
+		m := &c.Missions[mi]
+		diags = append(diags, m.analyze(varsByName, resolveVar)...)
+	}
+
+	return diags
+}
+
+func (m *Mission) analyze(varsByName map[string]*Variable, resolveVar func(*Variable) (string, error)) []Diagnostic {
+	var diags []Diagnostic
+	add := func(severity, task, format string, args ...any) {
+		diags = append(diags, Diagnostic{
+			Severity: severity,
+			Mission:  m.Name,
+			Task:     task,
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	inputsByName := make(map[string]*MissionInput, len(m.Inputs))
+	for i := range m.Inputs {
+		inputsByName[m.Inputs[i].Name] = &m.Inputs[i]
+	}
+	datasetsByName := make(map[string]*Dataset, len(m.Datasets))
+	for i := range m.Datasets {
+		datasetsByName[m.Datasets[i].Name] = &m.Datasets[i]
+	}
+
+	// Static dataset items are checked against the declared schema up front
+	// so a typo in the HCL doesn't wait until the iterator reaches the item.
+	for _, ds := range m.Datasets {
+		for i, item := range ds.Items {
+			if err := ds.ValidateItem(item); err != nil {
+				add(SeverityError, "", "dataset '%s' item %d: %v", ds.Name, i, err)
+			}
+		}
+	}
+
+	// File sources are checked for existence; HTTP sources are never fetched
+	// during static analysis.
+	for _, ds := range m.Datasets {
+		if ds.Source == nil || ds.Source.Path == "" {
+			continue
+		}
+		if _, err := os.Stat(ds.Source.Path); err != nil {
+			add(SeverityError, "", "dataset '%s' source file %s is not readable: %v", ds.Name, ds.Source.Path, err)
+		}
+	}
+
+	usedDatasets := make(map[string]bool)
+	for _, t := range m.Tasks {
+		if t.Iterator != nil {
+			usedDatasets[t.Iterator.Dataset] = true
+		}
+		if t.ObjectiveExpr == nil {
+			continue
+		}
+
+		var ds *Dataset
+		if t.Iterator != nil {
+			ds = datasetsByName[t.Iterator.Dataset]
+		}
+
+		for _, traversal := range t.ObjectiveExpr.Variables() {
+			switch traversal.RootName() {
+			case "vars":
+				name, ok := traversalAttr(traversal, 1)
+				if !ok || resolveVar == nil {
+					continue
+				}
+				v, exists := varsByName[name]
+				if !exists {
+					continue
+				}
+				val, err := resolveVar(v)
+				if err != nil {
+					add(SeverityWarning, t.Name, "objective references vars.%s which could not be resolved: %v", name, err)
+				} else if val == "" {
+					add(SeverityWarning, t.Name, "objective references vars.%s which has no value set", name)
+				}
+
+			case "inputs":
+				name, ok := traversalAttr(traversal, 1)
+				if !ok {
+					continue
+				}
+				input, exists := inputsByName[name]
+				if !exists {
+					add(SeverityError, t.Name, "objective references unknown input '%s'", name)
+				} else if input.Protected {
+					add(SeverityError, t.Name, "objective references protected input '%s' — protected inputs cannot be interpolated", name)
+				}
+
+			case "item":
+				if t.Iterator == nil {
+					add(SeverityError, t.Name, "objective references 'item' but the task has no iterator")
+					continue
+				}
+				field, ok := traversalAttr(traversal, 1)
+				if !ok || ds == nil {
+					continue
+				}
+				if ds.Schema != nil && !schemaHasField(ds.Schema, field) {
+					add(SeverityError, t.Name, "objective references item.%s but dataset '%s' schema has no field '%s'", field, ds.Name, field)
+				}
+				// An iterator over a dependency's output is checked against
+				// the item type of that output field
+				items := m.outputItems(ds)
+				if items == nil {
+					continue
+				}
+				source := "tasks." + OutputDatasetName(ds.FromTask, ds.FromPath)
+				if items.Type != "object" {
+					add(SeverityError, t.Name, "objective references item.%s but the items of %s are %s values, not objects", field, source, items.Type)
+				} else if len(items.Properties) > 0 && !outputHasField(&OutputSchema{Fields: items.Properties}, field) {
+					add(SeverityError, t.Name, "objective references item.%s but the items of %s have no field '%s'", field, source, field)
+				}
+			}
+		}
+
+		if t.WhenExpr != nil {
+			for _, traversal := range t.WhenExpr.Variables() {
+				switch traversal.RootName() {
+				case "inputs":
+					name, ok := traversalAttr(traversal, 1)
+					if !ok {
+						continue
+					}
+					if _, exists := inputsByName[name]; !exists {
+						add(SeverityError, t.Name, "when references unknown input '%s'", name)
+					}
+				case "query":
+					// query.<task>.output.<field> on a non-iterated task with an output schema
+					dep, _ := traversalAttr(traversal, 1)
+					attr, _ := traversalAttr(traversal, 2)
+					field, ok := traversalAttr(traversal, 3)
+					upstream := m.GetTaskByName(dep)
+					if !ok || attr != "output" || upstream == nil || upstream.Iterator != nil || upstream.Output == nil {
+						continue
+					}
+					if !outputHasField(upstream.Output, field) {
+						add(SeverityError, t.Name, "when references query.%s.output.%s but task '%s' has no output field '%s'", dep, field, dep, field)
+					}
+				}
+			}
+		}
+
+		// A dataset with no static items, bind_to, source, or upstream output only
+		// gets items if an upstream commander calls set_dataset — worth flagging,
+		// not failing.
+		if ds != nil && len(ds.Items) == 0 && ds.BindTo == "" && ds.BindToExpr == nil && ds.Source == nil && ds.FromTask == "" {
+			add(SeverityWarning, t.Name, "iterator dataset '%s' has no items and no bind_to; it must be populated at runtime via set_dataset", ds.Name)
+		}
+	}
+
+	names := make([]string, 0, len(m.Datasets))
+	for _, ds := range m.Datasets {
+		if !usedDatasets[ds.Name] {
+			names = append(names, ds.Name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		add(SeverityWarning, "", "dataset '%s' is not used by any task iterator", name)
+	}
+
+	return diags
+}
+
+// traversalAttr returns the attribute name at position idx of a traversal
+// (e.g. idx 1 of `inputs.url` is "url").
+func traversalAttr(traversal hcl.Traversal, idx int) (string, bool) {
+	if len(traversal) <= idx {
+		return "", false
+	}
+	attr, ok := traversal[idx].(hcl.TraverseAttr)
+	if !ok {
+		return "", false
+	}
+	return attr.Name, true
+}
+
+// outputItems returns the declared item type of the output array an
+// output-backed dataset reads, or nil when the dataset isn't one, the array
+// is untyped, or the path doesn't resolve (Validate reports that).
+func (m *Mission) outputItems(ds *Dataset) *OutputField {
+	if ds.FromTask == "" {
+		return nil
+	}
+	src := m.GetTaskByName(ds.FromTask)
+	if src == nil || src.Output == nil {
+		return nil
+	}
+	fields := src.Output.Fields
+	for i, name := range ds.FromPath {
+		var field *OutputField
+		for j := range fields {
+			if fields[j].Name == name {
+				field = &fields[j]
+				break
+			}
+		}
+		if field == nil {
+			return nil
+		}
+		if i == len(ds.FromPath)-1 {
+			if field.Type != "array" {
+				return nil
+			}
+			return field.Items
+		}
+		fields = field.Properties
+	}
+	return nil
+}
+
+func outputHasField(schema *OutputSchema, name string) bool {
+	for _, f := range schema.Fields {
+		if f.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func schemaHasField(schema *InputsSchema, name string) bool {
+	for _, f := range schema.Fields {
+		if f.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,149 @@
+package config_test
+
+import (
+	"squadron/config"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Analyze", func() {
+	load := func(missionHCL string) *config.Config {
+		_, f := writeFixture("config.hcl", fullBaseHCL()+missionHCL)
+		cfg, err := config.LoadFile(f)
+		Expect(err).NotTo(HaveOccurred())
+		return cfg
+	}
+
+	messages := func(diags []config.Diagnostic) []string {
+		var out []string
+		for _, d := range diags {
+			out = append(out, d.Message)
+		}
+		return out
+	}
+
+	It("reports nothing for a clean mission", func() {
+		cfg := load(`
+mission "clean" {
+  commander { model = models.anthropic.claude_sonnet_4 }
+  agents = [agents.test_agent]
+  task "work" { objective = "Do work" }
+}
+`)
+		Expect(cfg.Analyze(nil)).To(BeEmpty())
+	})
+
+	It("flags item references in a task without an iterator", func() {
+		cfg := load(`
+mission "m" {
+  commander { model = models.anthropic.claude_sonnet_4 }
+  agents = [agents.test_agent]
+  task "work" { objective = "Process ${item.name}" }
+}
+`)
+		diags := cfg.Analyze(nil)
+		Expect(config.HasErrors(diags)).To(BeTrue())
+		Expect(diags[0].Task).To(Equal("work"))
+		Expect(diags[0].Message).To(ContainSubstring("no iterator"))
+	})
+
+	It("flags item fields missing from the dataset schema and bad static items", func() {
+		cfg := load(`
+mission "m" {
+  commander { model = models.anthropic.claude_sonnet_4 }
+  agents = [agents.test_agent]
+  dataset "cities" {
+    schema = { name = string("City name", true) }
+    items  = [{ name = "Paris" }, { title = "Rome" }]
+  }
+  task "work" {
+    objective = "Process ${item.city}"
+    iterator {
+      dataset  = datasets.cities
+      parallel = true
+    }
+  }
+}
+`)
+		msgs := messages(cfg.Analyze(nil))
+		Expect(msgs).To(ContainElement(ContainSubstring("dataset 'cities' item 1: required field 'name' is missing")))
+		Expect(msgs).To(ContainElement(ContainSubstring("item.city but dataset 'cities' schema has no field 'city'")))
+	})
+
+	It("flags item fields missing from the output an iterator maps over", func() {
+		cfg := load(`
+mission "m" {
+  commander { model = models.anthropic.claude_sonnet_4 }
+  agents = [agents.test_agent]
+  task "extract" {
+    objective = "Extract leads"
+    output = {
+      leads = list(object({
+        name    = string("Lead name", true)
+        company = string("Company")
+      }), "Leads", true)
+      tags = list(string, "Tags")
+    }
+  }
+  task "enrich" {
+    objective  = "Enrich ${item.name} at ${item.email}"
+    depends_on = [tasks.extract]
+    iterator {
+      dataset  = tasks.extract.output.leads
+      parallel = true
+    }
+  }
+  task "label" {
+    objective  = "Label ${item.text}"
+    depends_on = [tasks.extract]
+    iterator {
+      dataset  = tasks.extract.output.tags
+      parallel = true
+    }
+  }
+}
+`)
+		diags := cfg.Analyze(nil)
+		Expect(messages(diags)).To(ConsistOf(
+			"objective references item.email but the items of tasks.extract.output.leads have no field 'email'",
+			"objective references item.text but the items of tasks.extract.output.tags are string values, not objects",
+		))
+		Expect(diags[0].Task).To(Equal("enrich"))
+	})
+
+	It("flags protected inputs interpolated into an objective", func() {
+		cfg := load(`
+mission "m" {
+  commander { model = models.anthropic.claude_sonnet_4 }
+  agents = [agents.test_agent]
+  input "token" {
+    type      = "string"
+    protected = true
+    value     = vars.test_api_key
+  }
+  task "work" { objective = "Use ${inputs.token}" }
+}
+`)
+		Expect(messages(cfg.Analyze(nil))).To(ContainElement(ContainSubstring("protected input 'token'")))
+	})
+
+	It("warns about unset variables and unused datasets", func() {
+		cfg := load(`
+mission "m" {
+  commander { model = models.anthropic.claude_sonnet_4 }
+  agents = [agents.test_agent]
+  dataset "spare" {
+    items = [{ name = "a" }]
+  }
+  task "work" { objective = "Call ${vars.test_api_key}" }
+}
+`)
+		diags := cfg.Analyze(func(*config.Variable) (string, error) { return "", nil })
+		Expect(config.HasErrors(diags)).To(BeFalse())
+		Expect(messages(diags)).To(ConsistOf(
+			ContainSubstring("vars.test_api_key which has no value set"),
+			ContainSubstring("dataset 'spare' is not used"),
+		))
+	})
+})
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// API roles, from least to most privileged. Viewers read missions,
+// operators also start them and stop their own, admins do anything,
+// including managing users.
+const (
+	RoleViewer   = "viewer"
+	RoleOperator = "operator"
+	RoleAdmin    = "admin"
+)
+
+// ValidRole reports whether role is one of the API roles.
+func ValidRole(role string) bool {
+	return role == RoleViewer || role == RoleOperator || role == RoleAdmin
+}
+
+// APIConfig configures `squadron api`. nil when the api block is absent.
+type APIConfig struct {
+	OIDC *OIDCConfig `hcl:"oidc,block"`
+}
+
+// OIDCConfig enables browser login against an OpenID Connect provider.
+type OIDCConfig struct {
+	Issuer         string   `hcl:"issuer"`                   // Provider URL; discovery is read from <issuer>/.well-known/openid-configuration
+	ClientID       string   `hcl:"client_id"`                // OAuth client ID registered with the provider
+	ClientSecret   string   `hcl:"client_secret,optional"`   // Client secret, for confidential clients
+	RedirectURL    string   `hcl:"redirect_url"`             // Public URL of /auth/callback
+	Scopes         []string `hcl:"scopes,optional"`          // Requested scopes (default: openid, email, profile)
+	AllowedDomains []string `hcl:"allowed_domains,optional"` // Email domains allowed to sign up on first login
+	DefaultRole    string   `hcl:"default_role,optional"`    // Role given to users created on first login (default: "viewer")
+	SessionHours   int      `hcl:"session_hours,optional"`   // Login session lifetime (default: 12)
+}
+
+// Defaults fills in default values for unset fields
+func (o *OIDCConfig) Defaults() {
+	if len(o.Scopes) == 0 {
+		o.Scopes = []string{"openid", "email", "profile"}
+	}
+	if o.DefaultRole == "" {
+		o.DefaultRole = RoleViewer
+	}
+	if o.SessionHours == 0 {
+		o.SessionHours = 12
+	}
+	o.Issuer = strings.TrimSuffix(o.Issuer, "/")
+}
+
+// Validate checks the provider settings.
+func (o *OIDCConfig) Validate() error {
+	if o.Issuer == "" {
+		return fmt.Errorf("issuer is required")
+	}
+	if o.ClientID == "" {
+		return fmt.Errorf("client_id is required")
+	}
+	if o.RedirectURL == "" {
+		return fmt.Errorf("redirect_url is required")
+	}
+	if !ValidRole(o.DefaultRole) {
+		return fmt.Errorf("unknown default_role '%s' (expected '%s', '%s', or '%s')", o.DefaultRole, RoleViewer, RoleOperator, RoleAdmin)
+	}
+	if o.SessionHours < 0 {
+		return fmt.Errorf("session_hours must be positive")
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package config_test
+
+import (
+	"squadron/config"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("API Config", func() {
+
+	It("parses an oidc block and fills in defaults", func() {
+		_, f := writeFixture("api.hcl", `
+api {
+  oidc {
+    issuer          = "https://idp.example.com/"
+    client_id       = "squadron"
+    client_secret   = "s3cret"
+    redirect_url    = "https://squadron.example.com/auth/callback"
+    allowed_domains = ["example.com"]
+  }
+}
+`)
+		cfg, err := config.LoadFile(f)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.API).NotTo(BeNil())
+		oidc := cfg.API.OIDC
+		Expect(oidc.Issuer).To(Equal("https://idp.example.com"))
+		Expect(oidc.Scopes).To(Equal([]string{"openid", "email", "profile"}))
+		Expect(oidc.DefaultRole).To(Equal(config.RoleViewer))
+		Expect(oidc.SessionHours).To(Equal(12))
+		Expect(oidc.AllowedDomains).To(Equal([]string{"example.com"}))
+	})
+
+	It("rejects an unknown default_role", func() {
+		_, f := writeFixture("api.hcl", `
+api {
+  oidc {
+    issuer       = "https://idp.example.com"
+    client_id    = "squadron"
+    redirect_url = "https://squadron.example.com/auth/callback"
+    default_role = "owner"
+  }
+}
+`)
+		_, err := config.LoadFile(f)
+		Expect(err).To(MatchError(ContainSubstring("default_role")))
+	})
+
+	It("rejects a second api block", func() {
+		_, f := writeFixture("api.hcl", `
+api {}
+api {}
+`)
+		_, err := config.LoadFile(f)
+		Expect(err).To(MatchError(ContainSubstring("declared more than once")))
+	})
+})
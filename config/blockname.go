@@ -67,6 +67,18 @@ func (c *Config) validateBlockNames() error {
 			return err
 		}
 	}
+	if c.Secrets != nil {
+		for _, s := range c.Secrets.Secrets {
+			if err := validateBlockName("secret", s.Name); err != nil {
+				return err
+			}
+		}
+	}
+	for _, d := range c.Databases {
+		if err := validateBlockName("database", d.Name); err != nil {
+			return err
+		}
+	}
 	for _, m := range c.Memories {
 		if err := validateBlockName("memory", m.Name); err != nil {
 			return err
@@ -82,11 +94,18 @@ func (c *Config) validateBlockNames() error {
 			return err
 		}
 		for _, t := range m.Tasks {
-			if err := validateBlockName("task", t.Name); err != nil {
+			name := t.Name
+			if t.ForEach != nil {
+				name = t.ForEach.Group // instance keys are quoted, so any string goes
+			}
+			if err := validateBlockName("task", name); err != nil {
 				return fmt.Errorf("mission '%s': %w", m.Name, err)
 			}
 		}
 		for _, d := range m.Datasets {
+			if d.FromTask != "" {
+				continue // named after the output field, not a block label
+			}
 			if err := validateBlockName("dataset", d.Name); err != nil {
 				return fmt.Errorf("mission '%s': %w", m.Name, err)
 			}
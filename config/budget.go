@@ -3,8 +3,8 @@ package config
 import "fmt"
 
 // Budget defines spending limits for a mission or task.
-// At least one of Tokens or Dollars must be set. Whichever limit is reached
-// first causes the owning task (and therefore the mission) to fail.
+// At least one of Tokens, Dollars, or LLMCalls must be set. Whichever limit
+// is reached first causes the owning task (and therefore the mission) to fail.
 type Budget struct {
 	// Tokens is the cumulative token budget (input + output + cache).
 	// Nil means no token limit. Applies to every LLM call charged to the scope.
@@ -13,6 +13,11 @@ type Budget struct {
 	// Models without configured pricing contribute $0, so a pure dollar budget
 	// cannot constrain local/unpriced models — pair it with a token budget.
 	Dollars *float64 `json:"dollars,omitempty"`
+	// LLMCalls caps the number of LLM requests (commander and agent turns)
+	// charged to the scope. Nil means no call limit. Unlike tokens and
+	// dollars, the limit is checked before a call is issued, so a budget of
+	// N allows exactly N calls.
+	LLMCalls *int64 `json:"llmCalls,omitempty"`
 }
 
 // Validate checks that at least one field is set.
@@ -20,8 +25,8 @@ func (b *Budget) Validate() error {
 	if b == nil {
 		return nil
 	}
-	if b.Tokens == nil && b.Dollars == nil {
-		return fmt.Errorf("budget: at least one of 'tokens' or 'dollars' must be set")
+	if b.Tokens == nil && b.Dollars == nil && b.LLMCalls == nil {
+		return fmt.Errorf("budget: at least one of 'tokens', 'dollars', or 'llm_calls' must be set")
 	}
 	if b.Tokens != nil && *b.Tokens <= 0 {
 		return fmt.Errorf("budget: tokens must be > 0")
@@ -29,5 +34,8 @@ func (b *Budget) Validate() error {
 	if b.Dollars != nil && *b.Dollars <= 0 {
 		return fmt.Errorf("budget: dollars must be > 0")
 	}
+	if b.LLMCalls != nil && *b.LLMCalls <= 0 {
+		return fmt.Errorf("budget: llm_calls must be > 0")
+	}
 	return nil
 }
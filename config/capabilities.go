@@ -0,0 +1,83 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+// HasCapabilities reports whether the agent declares every one of tags.
+func (a *Agent) HasCapabilities(tags []string) bool {
+	for _, tag := range tags {
+		if !slices.Contains(a.Capabilities, tag) {
+			return false
+		}
+	}
+	return true
+}
+
+// validateCapabilityTags checks a capabilities or requires list: tags must be
+// non-empty and unique.
+func validateCapabilityTags(attr string, tags []string) error {
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		if strings.TrimSpace(tag) == "" {
+			return fmt.Errorf("%s: tags must not be empty", attr)
+		}
+		if seen[tag] {
+			return fmt.Errorf("%s: duplicate tag '%s'", attr, tag)
+		}
+		seen[tag] = true
+	}
+	return nil
+}
+
+// parseCapabilityTags reads a capabilities or requires attribute value.
+func parseCapabilityTags(val cty.Value) ([]string, error) {
+	if !val.Type().IsListType() && !val.Type().IsTupleType() && !val.Type().IsSetType() {
+		return nil, fmt.Errorf("must be a list of strings")
+	}
+	var tags []string
+	for it := val.ElementIterator(); it.Next(); {
+		_, v := it.Element()
+		if v.IsNull() || v.Type() != cty.String {
+			return nil, fmt.Errorf("must be a list of strings")
+		}
+		tags = append(tags, v.AsString())
+	}
+	return tags, nil
+}
+
+// TaskAgents returns the agents available to a task and, for agents picked
+// by the task's requires, why each was chosen (keyed by agent name).
+//
+// A task that names agents gets exactly those; a task with requires gets
+// every agent — global ones, in config order, then the mission's own — that
+// declares all the required capabilities; any other task gets the mission's
+// agents. agents are the globally defined agents.
+func (m *Mission) TaskAgents(t *Task, agents []Agent) ([]string, map[string]string) {
+	if len(t.Agents) > 0 {
+		return t.Agents, nil
+	}
+	if len(t.Requires) == 0 {
+		return m.Agents, nil
+	}
+
+	var names []string
+	reasons := make(map[string]string)
+	pick := func(a *Agent) {
+		if a.HasCapabilities(t.Requires) {
+			names = append(names, a.Name)
+			reasons[a.Name] = fmt.Sprintf("chosen because it declares the required capabilities %s", strings.Join(t.Requires, ", "))
+		}
+	}
+	for i := range agents {
+		pick(&agents[i])
+	}
+	for i := range m.LocalAgents {
+		pick(&m.LocalAgents[i])
+	}
+	return names, reasons
+}
@@ -0,0 +1,154 @@
+package config_test
+
+import (
+	"squadron/config"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Agent capabilities", func() {
+	capableAgentsHCL := func() string {
+		return minimalVarsHCL() + minimalModelHCL() + `
+agent "scraper" {
+  model        = models.anthropic.claude_sonnet_4
+  personality  = "Browses"
+  capabilities = ["web"]
+}
+
+agent "analyst" {
+  model        = models.anthropic.claude_sonnet_4
+  personality  = "Queries"
+  capabilities = ["sql", "code"]
+}
+
+agent "generalist" {
+  model        = models.anthropic.claude_sonnet_4
+  personality  = "Does both"
+  capabilities = ["web", "sql"]
+}
+`
+	}
+
+	It("picks every agent, global and mission-scoped, that declares all required tags", func() {
+		hcl := capableAgentsHCL() + `
+mission "research" {
+  commander {
+    model = models.anthropic.claude_sonnet_4
+  }
+  agents = [agents.analyst]
+  agent "crawler" {
+    model        = models.anthropic.claude_sonnet_4
+    personality  = "Crawls"
+    capabilities = ["web"]
+  }
+  task "fetch" {
+    objective = "Fetch pages"
+    requires  = ["web"]
+  }
+  task "report" {
+    objective = "Report"
+  }
+}
+`
+		_, f := writeFixture("config.hcl", hcl)
+		cfg, err := config.LoadFile(f)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Agents[1].Capabilities).To(Equal([]string{"sql", "code"}))
+
+		m := &cfg.Missions[0]
+		fetch := m.GetTaskByName("fetch")
+		Expect(fetch.Requires).To(Equal([]string{"web"}))
+		names, reasons := m.TaskAgents(fetch, cfg.Agents)
+		Expect(names).To(Equal([]string{"scraper", "generalist", "crawler"}))
+		Expect(reasons["crawler"]).To(ContainSubstring("declares the required capabilities web"))
+
+		names, reasons = m.TaskAgents(m.GetTaskByName("report"), cfg.Agents)
+		Expect(names).To(Equal([]string{"analyst"}))
+		Expect(reasons).To(BeEmpty())
+	})
+
+	It("requires every tag to match", func() {
+		hcl := capableAgentsHCL() + `
+mission "research" {
+  commander {
+    model = models.anthropic.claude_sonnet_4
+  }
+  agents = [agents.analyst]
+  task "join" {
+    objective = "Join web and sql data"
+    requires  = ["web", "sql"]
+  }
+}
+`
+		_, f := writeFixture("config.hcl", hcl)
+		cfg, err := config.LoadFile(f)
+		Expect(err).NotTo(HaveOccurred())
+		names, _ := cfg.Missions[0].TaskAgents(&cfg.Missions[0].Tasks[0], cfg.Agents)
+		Expect(names).To(Equal([]string{"generalist"}))
+	})
+
+	It("rejects a requires that no agent satisfies", func() {
+		hcl := capableAgentsHCL() + `
+mission "research" {
+  commander {
+    model = models.anthropic.claude_sonnet_4
+  }
+  agents = [agents.analyst]
+  task "design" {
+    objective = "Design"
+    requires  = ["design"]
+  }
+}
+`
+		_, f := writeFixture("config.hcl", hcl)
+		cfg, err := config.LoadFile(f)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Validate()).To(MatchError(ContainSubstring("requires [design]: no agent declares all of these capabilities")))
+	})
+
+	It("rejects a task with both agents and requires", func() {
+		hcl := capableAgentsHCL() + `
+mission "research" {
+  commander {
+    model = models.anthropic.claude_sonnet_4
+  }
+  agents = [agents.analyst]
+  task "fetch" {
+    objective = "Fetch"
+    agents    = [agents.scraper]
+    requires  = ["web"]
+  }
+}
+`
+		_, f := writeFixture("config.hcl", hcl)
+		cfg, err := config.LoadFile(f)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Validate()).To(MatchError(ContainSubstring("task cannot have both agents and requires")))
+	})
+
+	It("rejects duplicate and non-string capability tags", func() {
+		hcl := minimalVarsHCL() + minimalModelHCL() + `
+agent "scraper" {
+  model        = models.anthropic.claude_sonnet_4
+  personality  = "Browses"
+  capabilities = ["web", "web"]
+}
+`
+		_, f := writeFixture("config.hcl", hcl)
+		cfg, err := config.LoadFile(f)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Validate()).To(MatchError(ContainSubstring("capabilities: duplicate tag 'web'")))
+
+		hcl = minimalVarsHCL() + minimalModelHCL() + `
+agent "scraper" {
+  model        = models.anthropic.claude_sonnet_4
+  personality  = "Browses"
+  capabilities = "web"
+}
+`
+		_, f = writeFixture("config.hcl", hcl)
+		_, err = config.LoadFile(f)
+		Expect(err).To(MatchError(ContainSubstring("capabilities: must be a list of strings")))
+	})
+})
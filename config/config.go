@@ -1,7 +1,10 @@
 package config
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math/big"
 	"os"
 	"path/filepath"
 	"strings"
@@ -11,6 +14,7 @@ import (
 	"github.com/hashicorp/hcl/v2/gohcl"
 	"github.com/hashicorp/hcl/v2/hclparse"
 	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
 
 	schemafunc "squadron/config/functions"
 	vaultpkg "squadron/config/vault"
@@ -41,6 +45,32 @@ type Config struct {
 	// Storage configuration (optional, defaults to memory backend)
 	Storage *StorageConfig `hcl:"-"`
 
+	// Secrets configures secrets resolved from external backends at mission
+	// start (optional, nil when absent)
+	Secrets *SecretsConfig `hcl:"-"`
+
+	// Observability configures trace export (optional, nil when absent)
+	Observability *ObservabilityConfig `hcl:"-"`
+
+	// Shell enables the builtins.shell tools and holds their allowlists
+	// (optional, nil when absent)
+	Shell *ShellConfig `hcl:"-"`
+
+	// API configures `squadron api`, e.g. OIDC login (optional, nil when
+	// absent)
+	API *APIConfig `hcl:"-"`
+
+	// ToolResponse is the config-wide tool_response block: limits on tool
+	// results that agents' and commanders' own blocks override (optional)
+	ToolResponse *ToolResponseConfig `hcl:"-"`
+
+	// HTTP configures the builtins.http tools, e.g. per-host auth headers
+	// (optional, nil when absent)
+	HTTP *HTTPConfig `hcl:"-"`
+
+	// Databases are the databases the builtins.sql tools can query
+	Databases []DatabaseConfig `hcl:"-"`
+
 	// CommandCenter configuration (optional, nil when absent = standalone mode)
 	CommandCenter *CommandCenterConfig `hcl:"-"`
 
@@ -386,10 +416,11 @@ func LoadPartial(path string) (*Config, error) {
 		if p.Version == "" {
 			continue
 		}
-		client, loadErr := plugin.LoadPlugin(p.Name, p.Version, p.Source)
+		client, loadErr := plugin.LoadPluginWithTimeout(p.Name, p.Version, p.Source, p.GetTimeout())
 		if loadErr != nil {
 			continue
 		}
+		client.SetToolTimeouts(p.GetToolTimeouts())
 		partial.LoadedPlugins[p.Name] = client
 	}
 
@@ -445,6 +476,11 @@ func (c *Config) Validate() error {
 		if err := m.Validate(); err != nil {
 			return fmt.Errorf("model '%s': %w", m.Name, err)
 		}
+		for key, comp := range m.Compaction {
+			if err := comp.Validate(c.Models); err != nil {
+				return fmt.Errorf("model '%s': compaction '%s': %w", m.Name, key, err)
+			}
+		}
 	}
 
 	for _, v := range c.Variables {
@@ -459,6 +495,22 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	if c.Secrets != nil {
+		if err := c.Secrets.Validate(); err != nil {
+			return fmt.Errorf("secrets: %w", err)
+		}
+		// Secrets and protected inputs share the ${secrets.<name>} namespace
+		for _, sec := range c.Secrets.Secrets {
+			for _, m := range c.Missions {
+				for _, in := range m.Inputs {
+					if in.Protected && in.Name == sec.Name {
+						return fmt.Errorf("secret '%s' conflicts with protected input '%s' in mission '%s'", sec.Name, in.Name, m.Name)
+					}
+				}
+			}
+		}
+	}
+
 	if c.MCPHost != nil {
 		if err := c.MCPHost.Validate(); err != nil {
 			return fmt.Errorf("mcp_host: %w", err)
@@ -488,6 +540,11 @@ func (c *Config) Validate() error {
 		if err := c.Agents[i].Validate(); err != nil {
 			return err
 		}
+		if comp := c.Agents[i].Compaction; comp != nil {
+			if err := comp.Validate(c.Models); err != nil {
+				return fmt.Errorf("agent '%s' compaction: %w", c.Agents[i].Name, err)
+			}
+		}
 	}
 
 	for _, m := range c.Memories {
@@ -596,6 +653,9 @@ func (c *Config) Validate() error {
 			if !validToolRefs[toolRef] {
 				return fmt.Errorf("agent '%s': unknown tool '%s'. Available tools: %v", a.Name, toolRef, getToolNames(validToolRefs))
 			}
+			if err := c.checkConfiguredToolRef(toolRef); err != nil {
+				return fmt.Errorf("agent '%s': %w", a.Name, err)
+			}
 		}
 	}
 
@@ -606,6 +666,9 @@ func (c *Config) Validate() error {
 				if !validToolRefs[toolRef] {
 					return fmt.Errorf("mission '%s' agent '%s': unknown tool '%s'. Available tools: %v", m.Name, a.Name, toolRef, getToolNames(validToolRefs))
 				}
+				if err := c.checkConfiguredToolRef(toolRef); err != nil {
+					return fmt.Errorf("mission '%s' agent '%s': %w", m.Name, a.Name, err)
+				}
 			}
 		}
 	}
@@ -783,6 +846,14 @@ type parsedBlocks struct {
 	MCPHost       []*hcl.Block
 	Skills        []*hcl.Block
 	Gateways      []*hcl.Block
+	Secrets       []*hcl.Block
+	Observability []*hcl.Block
+	Shell         []*hcl.Block
+	API           []*hcl.Block
+	HTTP          []*hcl.Block
+	ToolResponse  []*hcl.Block
+	Databases     []*hcl.Block
+	Templates     []*hcl.Block
 	// File is the source path the blocks were extracted from. Used to drop
 	// blocks (and parse errors) from .hcl files that live inside a packet
 	// folder — packet folders are treated as opaque reference data.
@@ -838,6 +909,14 @@ func loadFromFiles(configDir string, files []string) (*Config, error) {
 				{Type: "mcp", LabelNames: []string{"name"}},
 				{Type: "skill", LabelNames: []string{"name"}},
 				{Type: "gateway", LabelNames: []string{"name"}},
+				{Type: "secrets"},
+				{Type: "observability"},
+				{Type: "shell"},
+				{Type: "api"},
+				{Type: "http"},
+				{Type: "tool_response"},
+				{Type: "database", LabelNames: []string{"name"}},
+				{Type: "template", LabelNames: []string{"name"}},
 			},
 		})
 		if diags.HasErrors() {
@@ -879,6 +958,22 @@ func loadFromFiles(configDir string, files []string) (*Config, error) {
 				pb.Skills = append(pb.Skills, block)
 			case "gateway":
 				pb.Gateways = append(pb.Gateways, block)
+			case "secrets":
+				pb.Secrets = append(pb.Secrets, block)
+			case "observability":
+				pb.Observability = append(pb.Observability, block)
+			case "shell":
+				pb.Shell = append(pb.Shell, block)
+			case "api":
+				pb.API = append(pb.API, block)
+			case "http":
+				pb.HTTP = append(pb.HTTP, block)
+			case "tool_response":
+				pb.ToolResponse = append(pb.ToolResponse, block)
+			case "database":
+				pb.Databases = append(pb.Databases, block)
+			case "template":
+				pb.Templates = append(pb.Templates, block)
 			}
 		}
 		allParsedBlocks = append(allParsedBlocks, pb)
@@ -1051,12 +1146,18 @@ func loadFromFiles(configDir string, files []string) (*Config, error) {
 		storageConfig = *DefaultStorageConfig(configDir)
 	}
 	storageConfig.Defaults()
+	if err := storageConfig.Validate(); err != nil {
+		return nil, fmt.Errorf("storage: %w", err)
+	}
 
 	// Resolve relative SQLite path against config directory
 	if storageConfig.Backend == "sqlite" && !filepath.IsAbs(storageConfig.Path) && len(files) > 0 {
 		configDir := filepath.Dir(files[0])
 		storageConfig.Path = filepath.Join(configDir, storageConfig.Path)
 	}
+	if storageConfig.ArtifactsPath != "" && !filepath.IsAbs(storageConfig.ArtifactsPath) && len(files) > 0 {
+		storageConfig.ArtifactsPath = filepath.Join(filepath.Dir(files[0]), storageConfig.ArtifactsPath)
+	}
 
 	// Parse command_center block (optional singleton, with vars context)
 	var commandCenterConfig *CommandCenterConfig
@@ -1072,6 +1173,148 @@ func loadFromFiles(configDir string, files []string) (*Config, error) {
 		}
 	}
 
+	// Parse secrets block (optional singleton, with vars context so backend
+	// credentials can come from the vault)
+	var secretsConfig *SecretsConfig
+	for _, pb := range allParsedBlocks {
+		for _, block := range pb.Secrets {
+			if secretsConfig != nil {
+				return nil, fmt.Errorf("secrets block declared more than once")
+			}
+			var sc SecretsConfig
+			if diags := gohcl.DecodeBody(block.Body, varsCtx, &sc); diags.HasErrors() {
+				return nil, fmt.Errorf("secrets: %w", diags)
+			}
+			sc.resolvePaths(filepath.Dir(block.DefRange.Filename))
+			secretsConfig = &sc
+		}
+	}
+
+	// Parse observability block (optional singleton, with vars context so
+	// collector headers can carry vault values)
+	var observabilityConfig *ObservabilityConfig
+	for _, pb := range allParsedBlocks {
+		for _, block := range pb.Observability {
+			if observabilityConfig != nil {
+				return nil, fmt.Errorf("observability block declared more than once")
+			}
+			var oc ObservabilityConfig
+			if diags := gohcl.DecodeBody(block.Body, varsCtx, &oc); diags.HasErrors() {
+				return nil, fmt.Errorf("observability: %w", diags)
+			}
+			if t := oc.Tracing; t != nil {
+				t.Defaults()
+				if err := t.Validate(); err != nil {
+					return nil, fmt.Errorf("observability: tracing: %w", err)
+				}
+				if t.Path != "" && !filepath.IsAbs(t.Path) {
+					t.Path = filepath.Join(filepath.Dir(block.DefRange.Filename), t.Path)
+				}
+			}
+			observabilityConfig = &oc
+		}
+	}
+
+	// Parse shell block (optional singleton)
+	var shellConfig *ShellConfig
+	for _, pb := range allParsedBlocks {
+		for _, block := range pb.Shell {
+			if shellConfig != nil {
+				return nil, fmt.Errorf("shell block declared more than once")
+			}
+			var sc ShellConfig
+			if diags := gohcl.DecodeBody(block.Body, varsCtx, &sc); diags.HasErrors() {
+				return nil, fmt.Errorf("shell: %w", diags)
+			}
+			sc.resolvePaths(filepath.Dir(block.DefRange.Filename))
+			if err := sc.Validate(); err != nil {
+				return nil, fmt.Errorf("shell: %w", err)
+			}
+			shellConfig = &sc
+		}
+	}
+
+	// Parse api block (optional singleton)
+	var apiConfig *APIConfig
+	for _, pb := range allParsedBlocks {
+		for _, block := range pb.API {
+			if apiConfig != nil {
+				return nil, fmt.Errorf("api block declared more than once")
+			}
+			var ac APIConfig
+			if diags := gohcl.DecodeBody(block.Body, varsCtx, &ac); diags.HasErrors() {
+				return nil, fmt.Errorf("api: %w", diags)
+			}
+			if ac.OIDC != nil {
+				ac.OIDC.Defaults()
+				if err := ac.OIDC.Validate(); err != nil {
+					return nil, fmt.Errorf("api oidc: %w", err)
+				}
+			}
+			apiConfig = &ac
+		}
+	}
+
+	// Parse http block (optional singleton). Header templates see secrets.*
+	// as placeholders that the http tools fill in per request.
+	var httpConfig *HTTPConfig
+	httpCtx := secretPlaceholderContext(varsCtx, secretsConfig)
+	for _, pb := range allParsedBlocks {
+		for _, block := range pb.HTTP {
+			if httpConfig != nil {
+				return nil, fmt.Errorf("http block declared more than once")
+			}
+			var hc HTTPConfig
+			if diags := gohcl.DecodeBody(block.Body, httpCtx, &hc); diags.HasErrors() {
+				return nil, fmt.Errorf("http: %w", diags)
+			}
+			if err := hc.Validate(); err != nil {
+				return nil, fmt.Errorf("http: %w", err)
+			}
+			httpConfig = &hc
+		}
+	}
+
+	// Parse tool_response block (optional singleton)
+	var toolResponseConfig *ToolResponseConfig
+	for _, pb := range allParsedBlocks {
+		for _, block := range pb.ToolResponse {
+			if toolResponseConfig != nil {
+				return nil, fmt.Errorf("tool_response block declared more than once")
+			}
+			var tr ToolResponseConfig
+			if diags := gohcl.DecodeBody(block.Body, varsCtx, &tr); diags.HasErrors() {
+				return nil, fmt.Errorf("tool_response: %w", diags)
+			}
+			if err := tr.Validate(); err != nil {
+				return nil, fmt.Errorf("tool_response: %w", err)
+			}
+			toolResponseConfig = &tr
+		}
+	}
+
+	// Parse database blocks
+	var databases []DatabaseConfig
+	for _, pb := range allParsedBlocks {
+		for _, block := range pb.Databases {
+			var db DatabaseConfig
+			if diags := gohcl.DecodeBody(block.Body, varsCtx, &db); diags.HasErrors() {
+				return nil, fmt.Errorf("database '%s': %w", block.Labels[0], diags)
+			}
+			db.Name = block.Labels[0]
+			for _, existing := range databases {
+				if existing.Name == db.Name {
+					return nil, fmt.Errorf("database '%s' declared more than once", db.Name)
+				}
+			}
+			db.resolvePaths(filepath.Dir(block.DefRange.Filename))
+			if err := db.Validate(); err != nil {
+				return nil, fmt.Errorf("database '%s': %w", db.Name, err)
+			}
+			databases = append(databases, db)
+		}
+	}
+
 	// parseModelBlock parses a model block with optional pricing sub-blocks.
 	parseModelBlock := func(block *hcl.Block, ctx *hcl.EvalContext) (*Model, error) {
 		content, _, diags := block.Body.PartialContent(&hcl.BodySchema{
@@ -1081,9 +1324,15 @@ func loadFromFiles(configDir string, files []string) (*Config, error) {
 				{Name: "api_key"},
 				{Name: "base_url"},
 				{Name: "prompt_caching"},
+				{Name: "context_window"},
 			},
 			Blocks: []hcl.BlockHeaderSchema{
 				{Type: "pricing", LabelNames: []string{"model"}},
+				{Type: "retry"},
+				{Type: "rate_limit"},
+				{Type: "compaction", LabelNames: []string{"model"}},
+				{Type: "sampling", LabelNames: []string{"model"}},
+				{Type: "response"},
 			},
 		})
 		if diags.HasErrors() {
@@ -1136,8 +1385,88 @@ func loadFromFiles(configDir string, files []string) (*Config, error) {
 			m.PromptCaching = &b
 		}
 
-		// Parse pricing sub-blocks
+		if attr, ok := content.Attributes["context_window"]; ok {
+			val, d := attr.Expr.Value(ctx)
+			if d.HasErrors() {
+				return nil, d
+			}
+			if !val.Type().IsObjectType() && !val.Type().IsMapType() {
+				return nil, fmt.Errorf("context_window must be a map of model name to tokens")
+			}
+			m.ContextWindows = make(map[string]int)
+			for it := val.ElementIterator(); it.Next(); {
+				k, v := it.Element()
+				if v.Type() != cty.Number {
+					return nil, fmt.Errorf("context_window '%s' must be a number", k.AsString())
+				}
+				n, acc := v.AsBigFloat().Int64()
+				if acc != big.Exact {
+					return nil, fmt.Errorf("context_window '%s' must be a whole number", k.AsString())
+				}
+				m.ContextWindows[k.AsString()] = int(n)
+			}
+		}
+
+		// Parse pricing and compaction sub-blocks and the optional retry and
+		// rate_limit blocks
 		for _, pBlock := range content.Blocks {
+			if pBlock.Type == "compaction" {
+				modelName := pBlock.Labels[0]
+				var c Compaction
+				if cDiags := gohcl.DecodeBody(pBlock.Body, ctx, &c); cDiags.HasErrors() {
+					return nil, fmt.Errorf("compaction '%s': %w", modelName, cDiags)
+				}
+				if m.Compaction == nil {
+					m.Compaction = make(map[string]*Compaction)
+				}
+				m.Compaction[modelName] = &c
+				continue
+			}
+			if pBlock.Type == "sampling" {
+				modelName := pBlock.Labels[0]
+				var sp Sampling
+				if sDiags := gohcl.DecodeBody(pBlock.Body, ctx, &sp); sDiags.HasErrors() {
+					return nil, fmt.Errorf("sampling '%s': %w", modelName, sDiags)
+				}
+				if err := sp.Validate(); err != nil {
+					return nil, fmt.Errorf("sampling '%s': %w", modelName, err)
+				}
+				if m.Sampling == nil {
+					m.Sampling = make(map[string]*Sampling)
+				}
+				m.Sampling[modelName] = &sp
+				continue
+			}
+			if pBlock.Type == "retry" {
+				if m.Retry != nil {
+					return nil, fmt.Errorf("only one retry block allowed")
+				}
+				var r ModelRetry
+				if rDiags := gohcl.DecodeBody(pBlock.Body, ctx, &r); rDiags.HasErrors() {
+					return nil, fmt.Errorf("retry: %w", rDiags)
+				}
+				m.Retry = &r
+				continue
+			}
+			if pBlock.Type == "response" {
+				r, err := parseScriptedResponse(pBlock, ctx)
+				if err != nil {
+					return nil, fmt.Errorf("response %d: %w", len(m.Responses)+1, err)
+				}
+				m.Responses = append(m.Responses, r)
+				continue
+			}
+			if pBlock.Type == "rate_limit" {
+				if m.RateLimit != nil {
+					return nil, fmt.Errorf("only one rate_limit block allowed")
+				}
+				var rl ModelRateLimit
+				if rlDiags := gohcl.DecodeBody(pBlock.Body, ctx, &rl); rlDiags.HasErrors() {
+					return nil, fmt.Errorf("rate_limit: %w", rlDiags)
+				}
+				m.RateLimit = &rl
+				continue
+			}
 			if pBlock.Type != "pricing" {
 				continue
 			}
@@ -1226,13 +1555,14 @@ func loadFromFiles(configDir string, files []string) (*Config, error) {
 			allPlugins = append(allPlugins, *p)
 
 			// Load the plugin (passes source for auto-download if not found locally)
-			client, err := plugin.LoadPlugin(p.Name, p.Version, p.Source)
+			client, err := plugin.LoadPluginWithTimeout(p.Name, p.Version, p.Source, p.GetTimeout())
 			if err != nil {
 				return nil, fmt.Errorf("plugin '%s' (version %s) failed to load: %w", p.Name, p.Version, err)
 			}
 			// Add to loaded plugins first — even if Configure fails,
 			// the plugin binary is running and ListTools works for metadata
 			loadedPlugins[p.Name] = client
+			client.SetToolTimeouts(p.GetToolTimeouts())
 
 			if err := configurePlugin(client, p.Name, p.Settings); err != nil {
 				return nil, err
@@ -1438,14 +1768,64 @@ func loadFromFiles(configDir string, files []string) (*Config, error) {
 		missionsCtx.Variables["missions"] = cty.ObjectVal(missionNames)
 	}
 
-	// Second pass: parse missions with missions context available
+	templates, err := collectTemplates(allParsedBlocks)
+	if err != nil {
+		return nil, err
+	}
+
+	// Second pass: parse missions with missions context available.
+	// Missions built from a template are parsed from the template's body.
 	var allMissions []Mission
 	for _, pb := range allParsedBlocks {
 		for _, block := range pb.Missions {
-			mission, err := parseMissionBlock(block, missionsCtx)
+			block, ctx, params, err := instantiateTemplate(block, templates, missionsCtx)
+			if err != nil {
+				return nil, err
+			}
+			mission, err := parseMissionBlock(block, ctx)
 			if err != nil {
 				return nil, err
 			}
+			if params != cty.NilVal {
+				for i := range mission.Tasks {
+					mission.Tasks[i].Params = params
+				}
+			}
+			// File-backed dataset sources and exports, and file output
+			// sinks, use the same path rule as packets and plugins.
+			hclDir := configDir
+			if block.DefRange.Filename != "" {
+				hclDir = filepath.Dir(block.DefRange.Filename)
+			}
+			for i := range mission.Datasets {
+				if src := mission.Datasets[i].Source; src != nil && src.Path != "" {
+					abs, err := paths.ResolveConfigPath(configDir, hclDir, src.Path)
+					if err != nil {
+						return nil, fmt.Errorf("mission '%s': dataset '%s': source: %w", mission.Name, mission.Datasets[i].Name, err)
+					}
+					src.Path = abs
+				}
+				if export := mission.Datasets[i].Export; export != nil && export.Path != "" {
+					abs, err := paths.ResolveConfigPath(configDir, hclDir, export.Path)
+					if err != nil {
+						return nil, fmt.Errorf("mission '%s': dataset '%s': export: %w", mission.Name, mission.Datasets[i].Name, err)
+					}
+					export.Path = abs
+				}
+			}
+			for i := range mission.Tasks {
+				for j := range mission.Tasks[i].OutputSinks {
+					sink := &mission.Tasks[i].OutputSinks[j]
+					if sink.Type != OutputSinkFile || sink.Path == "" {
+						continue
+					}
+					abs, err := paths.ResolveConfigPath(configDir, hclDir, sink.Path)
+					if err != nil {
+						return nil, fmt.Errorf("mission '%s': task '%s': output_sink '%s': %w", mission.Name, mission.Tasks[i].Name, sink.Name, err)
+					}
+					sink.Path = abs
+				}
+			}
 			allMissions = append(allMissions, *mission)
 		}
 	}
@@ -1461,6 +1841,13 @@ func loadFromFiles(configDir string, files []string) (*Config, error) {
 		Skills:           allSkills,
 		Storage:          &storageConfig,
 		CommandCenter:    commandCenterConfig,
+		Secrets:          secretsConfig,
+		Observability:    observabilityConfig,
+		Shell:            shellConfig,
+		API:              apiConfig,
+		HTTP:             httpConfig,
+		ToolResponse:     toolResponseConfig,
+		Databases:        databases,
 		MCPHost:          mcpHostConfig,
 		Memories:         allMemories,
 		Packets:         allPackets,
@@ -1512,13 +1899,18 @@ func configurePlugin(client pluginConfigurer, name string, settings map[string]s
 }
 
 // configureWithRetry retries plugin Configure() a few times to handle the case
-// where the gRPC process isn't fully ready immediately after launch.
+// where the gRPC process isn't fully ready immediately after launch. A
+// plugin_timeout isn't retried: the plugin has been killed by then.
 func configureWithRetry(client pluginConfigurer, name string, settings map[string]string) error {
 	var err error
 	for attempt := 0; attempt < 3; attempt++ {
 		if err = client.Configure(settings); err == nil {
 			return nil
 		}
+		var timeoutErr *plugin.TimeoutError
+		if errors.As(err, &timeoutErr) {
+			return err
+		}
 		time.Sleep(500 * time.Millisecond)
 	}
 	return fmt.Errorf("plugin '%s' failed to configure: %w", name, err)
@@ -1694,6 +2086,9 @@ func buildModelsContext(ctx *hcl.EvalContext, models []Model) *hcl.EvalContext {
 		for key := range m.AvailableModels() {
 			providerModels[key] = cty.StringVal(key)
 		}
+		for key := range m.AvailableEmbeddingModels() {
+			providerModels[key] = cty.StringVal(key)
+		}
 		modelsMap[m.Name] = cty.ObjectVal(providerModels)
 	}
 
@@ -1872,6 +2267,81 @@ type missionTaskBlock struct {
 // parseMissionBlock parses a mission block with its nested task blocks
 // parseAgentBlock parses an agent HCL block into an Agent struct.
 // Used for both global agents and mission-scoped agents.
+// parseScriptedResponse parses a `response` block of a scripted model.
+// A tool call's input may be an object, which is encoded as JSON, or a
+// string holding the JSON already.
+func parseScriptedResponse(block *hcl.Block, ctx *hcl.EvalContext) (ScriptedResponse, error) {
+	var r ScriptedResponse
+	content, diags := block.Body.Content(&hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{
+			{Name: "match"},
+			{Name: "content"},
+			{Name: "error"},
+			{Name: "repeat"},
+		},
+		Blocks: []hcl.BlockHeaderSchema{
+			{Type: "tool_call", LabelNames: []string{"name"}},
+		},
+	})
+	if diags.HasErrors() {
+		return r, diags
+	}
+	for name, dst := range map[string]*string{"match": &r.Match, "content": &r.Content, "error": &r.Error} {
+		attr, ok := content.Attributes[name]
+		if !ok {
+			continue
+		}
+		val, d := attr.Expr.Value(ctx)
+		if d.HasErrors() {
+			return r, d
+		}
+		if val.IsNull() || val.Type() != cty.String {
+			return r, fmt.Errorf("%s must be a string", name)
+		}
+		*dst = val.AsString()
+	}
+	if attr, ok := content.Attributes["repeat"]; ok {
+		val, d := attr.Expr.Value(ctx)
+		if d.HasErrors() {
+			return r, d
+		}
+		if val.IsNull() || val.Type() != cty.Bool {
+			return r, fmt.Errorf("repeat must be a bool")
+		}
+		r.Repeat = val.True()
+	}
+
+	for _, tb := range content.Blocks {
+		tc := ScriptedToolCall{Name: tb.Labels[0], Input: "{}"}
+		tcContent, d := tb.Body.Content(&hcl.BodySchema{
+			Attributes: []hcl.AttributeSchema{{Name: "input"}},
+		})
+		if d.HasErrors() {
+			return r, d
+		}
+		if attr, ok := tcContent.Attributes["input"]; ok {
+			val, d := attr.Expr.Value(ctx)
+			if d.HasErrors() {
+				return r, d
+			}
+			if val.Type() == cty.String {
+				tc.Input = val.AsString()
+			} else {
+				b, err := ctyjson.SimpleJSONValue{Value: val}.MarshalJSON()
+				if err != nil {
+					return r, fmt.Errorf("tool_call '%s' input: %w", tc.Name, err)
+				}
+				tc.Input = string(b)
+			}
+			if !json.Valid([]byte(tc.Input)) {
+				return r, fmt.Errorf("tool_call '%s' input is not valid JSON", tc.Name)
+			}
+		}
+		r.ToolCalls = append(r.ToolCalls, tc)
+	}
+	return r, nil
+}
+
 func parseSkillBlock(block *hcl.Block, ctx *hcl.EvalContext) (*Skill, error) {
 	var s Skill
 	s.Name = block.Labels[0]
@@ -1892,13 +2362,20 @@ func parseAgentBlock(block *hcl.Block, ctx *hcl.EvalContext) (*Agent, error) {
 			{Name: "role"}, // deprecated; accepted and ignored for backward compat
 			{Name: "tools"},
 			{Name: "skills"},
+			{Name: "capabilities"},
 			{Name: "reasoning"},
+			{Name: "env"},
+			{Name: "working_dir"},
+			{Name: "prompt_template"},
+			{Name: "summarize_between_tasks"},
+			{Name: "output"}, // shorthand: output = { field = string("desc", true) }
 		},
 		Blocks: []hcl.BlockHeaderSchema{
 			{Type: "skill", LabelNames: []string{"name"}},
 			{Type: "pruning"},
 			{Type: "compaction"},
 			{Type: "tool_response"},
+			{Type: "output"}, // verbose: output { field "name" { ... } }
 		},
 	})
 	if diags.HasErrors() {
@@ -1978,6 +2455,17 @@ func parseAgentBlock(block *hcl.Block, ctx *hcl.EvalContext) (*Agent, error) {
 			a.Skills = append(a.Skills, v.AsString())
 		}
 	}
+	if attr, ok := content.Attributes["capabilities"]; ok {
+		val, d := attr.Expr.Value(agentCtx)
+		if d.HasErrors() {
+			return nil, fmt.Errorf("agent '%s' capabilities: %w", a.Name, d)
+		}
+		tags, err := parseCapabilityTags(val)
+		if err != nil {
+			return nil, fmt.Errorf("agent '%s' capabilities: %w", a.Name, err)
+		}
+		a.Capabilities = tags
+	}
 	if attr, ok := content.Attributes["reasoning"]; ok {
 		val, d := attr.Expr.Value(agentCtx)
 		if d.HasErrors() {
@@ -1985,6 +2473,34 @@ func parseAgentBlock(block *hcl.Block, ctx *hcl.EvalContext) (*Agent, error) {
 		}
 		a.Reasoning = val.AsString()
 	}
+	if attr, ok := content.Attributes["prompt_template"]; ok {
+		val, d := attr.Expr.Value(agentCtx)
+		if d.HasErrors() {
+			return nil, fmt.Errorf("agent '%s' prompt_template: %w", a.Name, d)
+		}
+		a.PromptTemplate = val.AsString()
+	}
+	if attr, ok := content.Attributes["summarize_between_tasks"]; ok {
+		val, d := attr.Expr.Value(agentCtx)
+		if d.HasErrors() {
+			return nil, fmt.Errorf("agent '%s' summarize_between_tasks: %w", a.Name, d)
+		}
+		if val.IsNull() || val.Type() != cty.Bool {
+			return nil, fmt.Errorf("agent '%s' summarize_between_tasks must be a bool", a.Name)
+		}
+		a.SummarizeBetweenTasks = val.True()
+	}
+	env, dir, err := parseToolEnv(content.Attributes, agentCtx)
+	if err != nil {
+		return nil, fmt.Errorf("agent '%s': %w", a.Name, err)
+	}
+	a.Env, a.WorkingDir = env, dir
+
+	output, err := parseOutputSchema(content, agentCtx)
+	if err != nil {
+		return nil, fmt.Errorf("agent '%s': %w", a.Name, err)
+	}
+	a.Output = output
 
 	// Decode sub-blocks
 	for _, b := range content.Blocks {
@@ -2009,6 +2525,9 @@ func parseAgentBlock(block *hcl.Block, ctx *hcl.EvalContext) (*Agent, error) {
 			if d.HasErrors() {
 				return nil, fmt.Errorf("agent '%s' tool_response: %w", a.Name, d)
 			}
+			if err := tr.Validate(); err != nil {
+				return nil, fmt.Errorf("agent '%s' tool_response: %w", a.Name, err)
+			}
 			a.ToolResponse = &tr
 		}
 	}
@@ -2028,6 +2547,8 @@ func parseMissionBlock(block *hcl.Block, ctx *hcl.EvalContext) (*Mission, error)
 			{Name: "packets"},    // read-only packet references: packets = [packets.foo]
 			{Name: "scratchpad"}, // bool: opt the mission into a per-run scratchpad slot
 			{Name: "max_parallel"},
+			{Name: "max_parallel_tasks"},
+			{Name: "max_parallel_llm_calls"},
 			{Name: "inputs"}, // shorthand: inputs = { field = string("desc", { default = "val" }) }
 			// Detected so we can produce a nicer error than "unsupported argument".
 			{Name: "folders"},
@@ -2040,9 +2561,14 @@ func parseMissionBlock(block *hcl.Block, ctx *hcl.EvalContext) (*Mission, error)
 			{Type: "dataset", LabelNames: []string{"name"}},
 			{Type: "secret", LabelNames: []string{"name"}},
 			{Type: "memory"}, // mission-scoped persistent memory (slot "memory")
+			{Type: "workspace"}, // per-task private directory (slot "workspace")
+			{Type: "knowledge"},
 			{Type: "schedule"},
 			{Type: "trigger"},
 			{Type: "budget"},
+			{Type: "notifications"},
+			{Type: "policies"},
+			{Type: "response_cache"},
 			// Detected so we can produce a nicer error than the parser's default.
 			{Type: "folder"},
 			{Type: "run_folder"},
@@ -2067,11 +2593,14 @@ func parseMissionBlock(block *hcl.Block, ctx *hcl.EvalContext) (*Mission, error)
 			Attributes: []hcl.AttributeSchema{
 				{Name: "model", Required: true},
 				{Name: "reasoning"},
+				{Name: "prompt_template"},
+				{Name: "max_parallel_agents"},
 			},
 			Blocks: []hcl.BlockHeaderSchema{
 				{Type: "compaction"},
 				{Type: "pruning"},
 				{Type: "tool_response"},
+				{Type: "checkpoint"},
 			},
 		})
 		if cmdDiags.HasErrors() {
@@ -2098,6 +2627,27 @@ func parseMissionBlock(block *hcl.Block, ctx *hcl.EvalContext) (*Mission, error)
 			missionCommander.Reasoning = reasoningVal.AsString()
 		}
 
+		// Optional prompt_template attribute
+		if templateAttr, ok := cmdContent.Attributes["prompt_template"]; ok {
+			templateVal, templateDiags := templateAttr.Expr.Value(ctx)
+			if templateDiags.HasErrors() {
+				return nil, fmt.Errorf("mission '%s' commander prompt_template: %w", missionName, templateDiags)
+			}
+			missionCommander.PromptTemplate = templateVal.AsString()
+		}
+
+		// Optional max_parallel_agents attribute
+		if maxAttr, ok := cmdContent.Attributes["max_parallel_agents"]; ok {
+			var maxParallel int
+			if diags := gohcl.DecodeExpression(maxAttr.Expr, ctx, &maxParallel); diags.HasErrors() {
+				return nil, fmt.Errorf("mission '%s' commander max_parallel_agents: %w", missionName, diags)
+			}
+			if maxParallel < 1 {
+				return nil, fmt.Errorf("mission '%s' commander max_parallel_agents must be at least 1", missionName)
+			}
+			missionCommander.MaxParallelAgents = maxParallel
+		}
+
 		// Parse optional compaction and pruning sub-blocks
 		for _, subBlock := range cmdContent.Blocks {
 			switch subBlock.Type {
@@ -2125,7 +2675,17 @@ func parseMissionBlock(block *hcl.Block, ctx *hcl.EvalContext) (*Mission, error)
 				if trDiags.HasErrors() {
 					return nil, fmt.Errorf("mission '%s' commander tool_response: %w", missionName, trDiags)
 				}
+				if err := tr.Validate(); err != nil {
+					return nil, fmt.Errorf("mission '%s' commander tool_response: %w", missionName, err)
+				}
 				missionCommander.ToolResponse = &tr
+			case "checkpoint":
+				var cp CommanderCheckpoint
+				cpDiags := gohcl.DecodeBody(subBlock.Body, ctx, &cp)
+				if cpDiags.HasErrors() {
+					return nil, fmt.Errorf("mission '%s' commander checkpoint: %w", missionName, cpDiags)
+				}
+				missionCommander.Checkpoint = &cp
 			}
 		}
 	}
@@ -2252,6 +2812,38 @@ func parseMissionBlock(block *hcl.Block, ctx *hcl.EvalContext) (*Mission, error)
 		missionMemory = &mm
 	}
 
+	// Parse the optional `knowledge { ... }` block (one per mission).
+	var missionKnowledge *MissionKnowledge
+	for _, kb := range missionContent.Blocks {
+		if kb.Type != "knowledge" {
+			continue
+		}
+		if missionKnowledge != nil {
+			return nil, fmt.Errorf("mission '%s': only one knowledge block allowed", missionName)
+		}
+		var k MissionKnowledge
+		if diags := gohcl.DecodeBody(kb.Body, ctx, &k); diags.HasErrors() {
+			return nil, fmt.Errorf("mission '%s' knowledge: %w", missionName, diags)
+		}
+		missionKnowledge = &k
+	}
+
+	// Parse optional workspace block (at most one)
+	var missionWorkspace *MissionWorkspace
+	for _, wb := range missionContent.Blocks {
+		if wb.Type != "workspace" {
+			continue
+		}
+		if missionWorkspace != nil {
+			return nil, fmt.Errorf("mission '%s': only one workspace block allowed", missionName)
+		}
+		var w MissionWorkspace
+		if diags := gohcl.DecodeBody(wb.Body, ctx, &w); diags.HasErrors() {
+			return nil, fmt.Errorf("mission '%s' workspace: %w", missionName, diags)
+		}
+		missionWorkspace = &w
+	}
+
 	// Parse optional `scratchpad = true` attribute. Default false — agents
 	// only get a scratchpad slot when the mission explicitly opts in.
 	var missionScratchpad bool
@@ -2309,6 +2901,56 @@ func parseMissionBlock(block *hcl.Block, ctx *hcl.EvalContext) (*Mission, error)
 		missionBudget = b
 	}
 
+	// Parse notifications block (optional, singleton)
+	var notifications *Notifications
+	for _, notifyBlock := range missionContent.Blocks {
+		if notifyBlock.Type != "notifications" {
+			continue
+		}
+		if notifications != nil {
+			return nil, fmt.Errorf("mission '%s': only one notifications block allowed", missionName)
+		}
+		var n Notifications
+		diags := gohcl.DecodeBody(notifyBlock.Body, ctx, &n)
+		if diags.HasErrors() {
+			return nil, fmt.Errorf("mission '%s' notifications: %w", missionName, diags)
+		}
+		notifications = &n
+	}
+
+	// Parse policies block (optional, singleton)
+	var policies *Policies
+	for _, policyBlock := range missionContent.Blocks {
+		if policyBlock.Type != "policies" {
+			continue
+		}
+		if policies != nil {
+			return nil, fmt.Errorf("mission '%s': only one policies block allowed", missionName)
+		}
+		var p Policies
+		diags := gohcl.DecodeBody(policyBlock.Body, ctx, &p)
+		if diags.HasErrors() {
+			return nil, fmt.Errorf("mission '%s' policies: %w", missionName, diags)
+		}
+		policies = &p
+	}
+
+	// Parse response_cache block (optional, singleton)
+	var responseCache *ResponseCache
+	for _, rcBlock := range missionContent.Blocks {
+		if rcBlock.Type != "response_cache" {
+			continue
+		}
+		if responseCache != nil {
+			return nil, fmt.Errorf("mission '%s': only one response_cache block allowed", missionName)
+		}
+		var rc ResponseCache
+		if diags := gohcl.DecodeBody(rcBlock.Body, ctx, &rc); diags.HasErrors() {
+			return nil, fmt.Errorf("mission '%s' response_cache: %w", missionName, diags)
+		}
+		responseCache = &rc
+	}
+
 	// Parse max_parallel attribute (optional, default 3)
 	maxParallel := 3
 	if attr, ok := missionContent.Attributes["max_parallel"]; ok {
@@ -2321,6 +2963,15 @@ func parseMissionBlock(block *hcl.Block, ctx *hcl.EvalContext) (*Mission, error)
 		maxParallel = int(mp)
 	}
 
+	maxParallelTasks, err := parseMissionInt(missionContent, ctx, missionName, "max_parallel_tasks")
+	if err != nil {
+		return nil, err
+	}
+	maxParallelLLMCalls, err := parseMissionInt(missionContent, ctx, missionName, "max_parallel_llm_calls")
+	if err != nil {
+		return nil, err
+	}
+
 	mission := &Mission{
 		Name:        missionName,
 		Directive:   directive,
@@ -2331,10 +2982,17 @@ func parseMissionBlock(block *hcl.Block, ctx *hcl.EvalContext) (*Mission, error)
 		Packets:   missionPackets,
 		Memory:     missionMemory,
 		Scratchpad: missionScratchpad,
+		Workspace:  missionWorkspace,
+		Knowledge:  missionKnowledge,
 		Schedules:   schedules,
 		Trigger:     trigger,
 		MaxParallel: maxParallel,
+		MaxParallelTasks:    maxParallelTasks,
+		MaxParallelLLMCalls: maxParallelLLMCalls,
 		Budget:      missionBudget,
+		Notifications: notifications,
+		Policies:      policies,
+		ResponseCache: responseCache,
 	}
 
 	// Parse inputs — accept either shorthand attribute or verbose labeled block form.
@@ -2386,12 +3044,30 @@ func parseMissionBlock(block *hcl.Block, ctx *hcl.EvalContext) (*Mission, error)
 		mission.Datasets = append(mission.Datasets, *dataset)
 	}
 
-	// Build tasks context for depends_on references
+	// Build tasks context for depends_on references. A for_each task is an
+	// object of its instance names, so tasks.<name>["key"] is one instance
+	// and tasks.<name> all of them.
 	taskNames := make(map[string]cty.Value)
+	forEachInstances := make(map[*hcl.Block][]ForEachInstance)
 	for _, taskBlock := range missionContent.Blocks {
-		if taskBlock.Type == "task" {
-			taskNames[taskBlock.Labels[0]] = cty.StringVal(taskBlock.Labels[0])
+		if taskBlock.Type != "task" {
+			continue
+		}
+		label := taskBlock.Labels[0]
+		instances, ok, err := parseForEach(taskBlock, inputsCtx)
+		if err != nil {
+			return nil, fmt.Errorf("mission '%s': %w", missionName, err)
+		}
+		if !ok {
+			taskNames[label] = cty.StringVal(label)
+			continue
 		}
+		group := make(map[string]cty.Value, len(instances))
+		for _, inst := range instances {
+			group[inst.Key] = cty.StringVal(InstanceName(label, inst.Key))
+		}
+		taskNames[label] = cty.ObjectVal(group)
+		forEachInstances[taskBlock] = instances
 	}
 
 	// Build datasets context for iterator references
@@ -2419,13 +3095,29 @@ func parseMissionBlock(block *hcl.Block, ctx *hcl.EvalContext) (*Mission, error)
 			continue
 		}
 
-		task, err := parseTaskBlock(taskBlock, taskCtx)
-		if err != nil {
-			return nil, fmt.Errorf("mission '%s': %w", missionName, err)
-		}
-		mission.Tasks = append(mission.Tasks, *task)
-	}
-
+		instances, ok := forEachInstances[taskBlock]
+		if !ok {
+			task, err := parseTaskBlock(taskBlock, taskBlock.Labels[0], taskCtx)
+			if err != nil {
+				return nil, fmt.Errorf("mission '%s': %w", missionName, err)
+			}
+			mission.Tasks = append(mission.Tasks, *task)
+			continue
+		}
+
+		// for_each: one task per element, each parsed with its own `each`
+		for i := range instances {
+			inst := instances[i]
+			task, err := parseTaskBlock(taskBlock, InstanceName(inst.Group, inst.Key), withEach(taskCtx, &inst))
+			if err != nil {
+				return nil, fmt.Errorf("mission '%s': %w", missionName, err)
+			}
+			task.ForEach = &inst
+			mission.Tasks = append(mission.Tasks, *task)
+		}
+	}
+	mission.addOutputDatasets()
+
 	return mission, nil
 }
 
@@ -2470,6 +3162,27 @@ func parseScheduleBlock(block *hcl.Block, ctx *hcl.EvalContext) (*Schedule, erro
 	return &sched, nil
 }
 
+// parseMissionInt reads an optional whole-number mission attribute,
+// returning 0 when it is absent.
+func parseMissionInt(content *hcl.BodyContent, ctx *hcl.EvalContext, missionName, name string) (int, error) {
+	attr, ok := content.Attributes[name]
+	if !ok {
+		return 0, nil
+	}
+	val, diags := attr.Expr.Value(ctx)
+	if diags.HasErrors() {
+		return 0, fmt.Errorf("mission '%s' %s: %w", missionName, name, diags)
+	}
+	if val.IsNull() || val.Type() != cty.Number {
+		return 0, fmt.Errorf("mission '%s' %s must be a number", missionName, name)
+	}
+	n, accuracy := val.AsBigFloat().Int64()
+	if accuracy != big.Exact {
+		return 0, fmt.Errorf("mission '%s' %s must be a whole number", missionName, name)
+	}
+	return int(n), nil
+}
+
 // parseMissionInputBlock parses an input block within a mission
 func parseMissionInputBlock(block *hcl.Block, ctx *hcl.EvalContext) (*MissionInput, error) {
 	inputName := block.Labels[0]
@@ -2482,20 +3195,38 @@ func parseMissionInputBlock(block *hcl.Block, ctx *hcl.EvalContext) (*MissionInp
 			{Name: "protected"},
 			{Name: "value"},
 		},
+		Blocks: []hcl.BlockHeaderSchema{
+			{Type: "validation"},
+		},
 	})
 	if diags.HasErrors() {
 		return nil, fmt.Errorf("input '%s': %w", inputName, diags)
 	}
 
-	// Get type
+	// Get type: a type name ("object") or a schema expression such as
+	// object({ host = string("Host", true) }) or list(string)
 	typeVal, diags := inputContent.Attributes["type"].Expr.Value(ctx)
 	if diags.HasErrors() {
 		return nil, fmt.Errorf("input '%s': %w", inputName, diags)
 	}
 
-	input := &MissionInput{
-		Name: inputName,
-		Type: typeVal.AsString(),
+	input := &MissionInput{Name: inputName}
+	switch {
+	case typeVal.Type() == cty.String:
+		input.Type = typeVal.AsString()
+	case typeVal.Type().IsObjectType() && typeVal.Type().HasAttribute("kind"):
+		typed, err := parseSchemaNodeAsMissionInput(inputName, typeVal)
+		if err != nil {
+			return nil, fmt.Errorf("input '%s': type: %w", inputName, err)
+		}
+		input.Type = typed.Type
+		input.Items = typed.Items
+		input.Properties = typed.Properties
+		if input.Description == "" {
+			input.Description = typed.Description
+		}
+	default:
+		return nil, fmt.Errorf("input '%s': type must be a type name or a schema expression such as object({...})", inputName)
 	}
 
 	// Get optional description
@@ -2534,6 +3265,14 @@ func parseMissionInputBlock(block *hcl.Block, ctx *hcl.EvalContext) (*MissionInp
 		input.Value = &valueVal
 	}
 
+	for _, validationBlock := range inputContent.Blocks {
+		validation, err := parseInputValidationBlock(inputName, validationBlock, ctx)
+		if err != nil {
+			return nil, fmt.Errorf("input '%s': %w", inputName, err)
+		}
+		input.Validations = append(input.Validations, *validation)
+	}
+
 	return input, nil
 }
 
@@ -2550,6 +3289,9 @@ func parseDatasetBlock(block *hcl.Block, ctx *hcl.EvalContext) (*Dataset, error)
 		},
 		Blocks: []hcl.BlockHeaderSchema{
 			{Type: "schema"}, // verbose: schema { field "name" { ... } }
+			{Type: "source"},
+			{Type: "export"},
+			{Type: "transform"},
 		},
 	})
 	if diags.HasErrors() {
@@ -2607,6 +3349,45 @@ func parseDatasetBlock(block *hcl.Block, ctx *hcl.EvalContext) (*Dataset, error)
 		}
 	}
 
+	for _, sourceBlock := range datasetContent.Blocks {
+		if sourceBlock.Type != "source" {
+			continue
+		}
+		if dataset.Source != nil {
+			return nil, fmt.Errorf("dataset '%s': only one source block is allowed", datasetName)
+		}
+		source, err := parseDatasetSourceBlock(sourceBlock, ctx)
+		if err != nil {
+			return nil, fmt.Errorf("dataset '%s': %w", datasetName, err)
+		}
+		dataset.Source = source
+	}
+
+	for _, exportBlock := range datasetContent.Blocks {
+		if exportBlock.Type != "export" {
+			continue
+		}
+		if dataset.Export != nil {
+			return nil, fmt.Errorf("dataset '%s': only one export block is allowed", datasetName)
+		}
+		export, err := parseDatasetExportBlock(exportBlock, ctx)
+		if err != nil {
+			return nil, fmt.Errorf("dataset '%s': %w", datasetName, err)
+		}
+		dataset.Export = export
+	}
+
+	for _, transformBlock := range datasetContent.Blocks {
+		if transformBlock.Type != "transform" {
+			continue
+		}
+		transform, err := parseDatasetTransformBlock(transformBlock, ctx)
+		if err != nil {
+			return nil, fmt.Errorf("dataset '%s': %w", datasetName, err)
+		}
+		dataset.Transforms = append(dataset.Transforms, *transform)
+	}
+
 	// Parse schema — accept either shorthand attribute or verbose block form.
 	if schemaAttr, ok := datasetContent.Attributes["schema"]; ok {
 		// Shorthand: schema = { id = number("Item ID", true) }
@@ -2635,6 +3416,103 @@ func parseDatasetBlock(block *hcl.Block, ctx *hcl.EvalContext) (*Dataset, error)
 	return dataset, nil
 }
 
+// parseDatasetSourceBlock parses a dataset's source block. Values may use
+// vars but not mission inputs, since items are loaded before inputs bind.
+func parseDatasetSourceBlock(block *hcl.Block, ctx *hcl.EvalContext) (*DatasetSource, error) {
+	content, diags := block.Body.Content(&hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{
+			{Name: "type", Required: true},
+			{Name: "path"},
+			{Name: "url"},
+			{Name: "headers"},
+			{Name: "items_path"},
+			{Name: "fields"},
+		},
+	})
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("source: %w", diags)
+	}
+
+	source := &DatasetSource{}
+	for name, attr := range content.Attributes {
+		val, diags := attr.Expr.Value(ctx)
+		if diags.HasErrors() {
+			return nil, fmt.Errorf("source: %w", diags)
+		}
+		if !val.IsWhollyKnown() {
+			return nil, fmt.Errorf("source: %s cannot reference mission inputs", name)
+		}
+		switch name {
+		case "headers", "fields":
+			if !val.Type().IsObjectType() && !val.Type().IsMapType() {
+				return nil, fmt.Errorf("source: %s must be a map of strings", name)
+			}
+			m := make(map[string]string)
+			for it := val.ElementIterator(); it.Next(); {
+				k, v := it.Element()
+				if v.Type() != cty.String {
+					return nil, fmt.Errorf("source: %s.%s must be a string", name, k.AsString())
+				}
+				m[k.AsString()] = v.AsString()
+			}
+			if name == "headers" {
+				source.Headers = m
+			} else {
+				source.Fields = m
+			}
+		default:
+			if val.Type() != cty.String {
+				return nil, fmt.Errorf("source: %s must be a string", name)
+			}
+			switch name {
+			case "type":
+				source.Type = val.AsString()
+			case "path":
+				source.Path = val.AsString()
+			case "url":
+				source.URL = val.AsString()
+			case "items_path":
+				source.ItemsPath = val.AsString()
+			}
+		}
+	}
+	return source, nil
+}
+
+// parseDatasetExportBlock parses a dataset's export block. Like source
+// blocks, values may use vars but not mission inputs.
+func parseDatasetExportBlock(block *hcl.Block, ctx *hcl.EvalContext) (*DatasetExport, error) {
+	content, diags := block.Body.Content(&hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{
+			{Name: "path", Required: true},
+			{Name: "format", Required: true},
+		},
+	})
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("export: %w", diags)
+	}
+
+	export := &DatasetExport{}
+	for name, attr := range content.Attributes {
+		val, diags := attr.Expr.Value(ctx)
+		if diags.HasErrors() {
+			return nil, fmt.Errorf("export: %w", diags)
+		}
+		if !val.IsWhollyKnown() {
+			return nil, fmt.Errorf("export: %s cannot reference mission inputs", name)
+		}
+		if val.Type() != cty.String {
+			return nil, fmt.Errorf("export: %s must be a string", name)
+		}
+		if name == "path" {
+			export.Path = val.AsString()
+		} else {
+			export.Format = val.AsString()
+		}
+	}
+	return export, nil
+}
+
 // parseSchemaBlock parses a schema block (reuses inputFieldBlock pattern)
 func parseSchemaBlock(block *hcl.Block) (*InputsSchema, error) {
 	var schemaContent struct {
@@ -2667,25 +3545,43 @@ func parseIteratorBlock(block *hcl.Block, ctx *hcl.EvalContext) (*TaskIterator,
 			{Name: "concurrency_limit"},
 			{Name: "start_delay"},
 			{Name: "smoketest"},
+			{Name: "timeout"},
+			{Name: "continue_on_failure"},
+			{Name: "max_failure_rate"},
+		},
+		Blocks: []hcl.BlockHeaderSchema{
+			{Type: "models"},
 		},
 	})
 	if diags.HasErrors() {
 		return nil, diags
 	}
 
-	// Get dataset reference
-	datasetVal, diags := iterContent.Attributes["dataset"].Expr.Value(ctx)
-	if diags.HasErrors() {
-		return nil, diags
-	}
-
 	iterator := &TaskIterator{
-		Dataset:          datasetVal.AsString(),
 		Parallel:         false, // Default to sequential
 		MaxRetries:       0,     // Default to no retries
 		ConcurrencyLimit: 5,     // Default to 5 concurrent iterations
 	}
 
+	// Get dataset reference: a declared dataset, or a dependency's output
+	// field (tasks.<task>.output.<field>)
+	datasetExpr := iterContent.Attributes["dataset"].Expr
+	fromTask, fromPath, isOutput, err := taskOutputRef(datasetExpr)
+	if err != nil {
+		return nil, fmt.Errorf("dataset: %w", err)
+	}
+	if isOutput {
+		iterator.Dataset = OutputDatasetName(fromTask, fromPath)
+		iterator.FromTask = fromTask
+		iterator.FromPath = fromPath
+	} else {
+		datasetVal, diags := datasetExpr.Value(ctx)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		iterator.Dataset = datasetVal.AsString()
+	}
+
 	// Get optional parallel flag
 	if parallelAttr, ok := iterContent.Attributes["parallel"]; ok {
 		parallelVal, diags := parallelAttr.Expr.Value(ctx)
@@ -2743,6 +3639,52 @@ func parseIteratorBlock(block *hcl.Block, ctx *hcl.EvalContext) (*TaskIterator,
 		iterator.Smoketest = smoketestVal.True()
 	}
 
+	// Get optional per-iteration timeout
+	if timeoutAttr, ok := iterContent.Attributes["timeout"]; ok {
+		timeout, err := parseTimeoutAttr(timeoutAttr, ctx)
+		if err != nil {
+			return nil, err
+		}
+		iterator.Timeout = timeout
+	}
+
+	// Get optional continue_on_failure (complete the task despite failed iterations)
+	if continueAttr, ok := iterContent.Attributes["continue_on_failure"]; ok {
+		continueVal, diags := continueAttr.Expr.Value(ctx)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		iterator.ContinueOnFailure = continueVal.True()
+	}
+
+	// Get optional max_failure_rate (fraction of items allowed to fail)
+	if rateAttr, ok := iterContent.Attributes["max_failure_rate"]; ok {
+		if !iterator.ContinueOnFailure {
+			return nil, fmt.Errorf("max_failure_rate is only valid when continue_on_failure=true")
+		}
+		rateVal, diags := rateAttr.Expr.Value(ctx)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		if rateVal.Type() != cty.Number {
+			return nil, fmt.Errorf("max_failure_rate must be a number between 0 and 1")
+		}
+		rate, _ := rateVal.AsBigFloat().Float64()
+		iterator.MaxFailureRate = &rate
+	}
+
+	// Get optional model overrides for the iterations
+	for _, b := range iterContent.Blocks {
+		if iterator.Models != nil {
+			return nil, fmt.Errorf("iterator: only one models block allowed")
+		}
+		models, err := parseModelOverrideBlock(b, ctx)
+		if err != nil {
+			return nil, fmt.Errorf("iterator %w", err)
+		}
+		iterator.Models = models
+	}
+
 	// Validate: parallel-specific options are only valid when parallel=true
 	if !iterator.Parallel {
 		if _, ok := iterContent.Attributes["concurrency_limit"]; ok {
@@ -2754,30 +3696,50 @@ func parseIteratorBlock(block *hcl.Block, ctx *hcl.EvalContext) (*TaskIterator,
 		if _, ok := iterContent.Attributes["smoketest"]; ok {
 			return nil, fmt.Errorf("smoketest is only valid when parallel=true")
 		}
+		if _, ok := iterContent.Attributes["timeout"]; ok {
+			return nil, fmt.Errorf("timeout is only valid when parallel=true (set timeout on the task to bound a sequential iterator)")
+		}
+		if _, ok := iterContent.Attributes["continue_on_failure"]; ok {
+			return nil, fmt.Errorf("continue_on_failure is only valid when parallel=true (a sequential iterator processes every item in one commander session)")
+		}
 	}
 
 	return iterator, nil
 }
 
 // parseTaskBlock parses a single task block within a mission
-func parseTaskBlock(block *hcl.Block, ctx *hcl.EvalContext) (*Task, error) {
-	taskName := block.Labels[0]
-
+func parseTaskBlock(block *hcl.Block, taskName string, ctx *hcl.EvalContext) (*Task, error) {
 	// Parse task attributes and blocks
 	taskContent, _, diags := block.Body.PartialContent(&hcl.BodySchema{
 		Attributes: []hcl.AttributeSchema{
 			{Name: "objective", Required: true},
 			{Name: "agents"},    // Optional - uses mission-level agents if not specified
+			{Name: "requires"},  // Optional - picks agents by capability instead
 			{Name: "packets"},   // Optional - task-scoped declared packet references
 			{Name: "depends_on"},
 			{Name: "send_to"},
 			{Name: "output"}, // shorthand: output = { field = string("desc", true) }
+			{Name: "timeout"},
+			{Name: "max_turns"},
+			{Name: "priority"},
+			{Name: "require_approval"},
+			{Name: "verify"},
+			{Name: "tools_allow"},
+			{Name: "tools_deny"},
+			{Name: "env"},
+			{Name: "working_dir"},
+			{Name: "when"},
+			{Name: "for_each"}, // expanded by the mission parser (see parseForEach)
 		},
 		Blocks: []hcl.BlockHeaderSchema{
 			{Type: "iterator"},
 			{Type: "output"}, // verbose: output { field "name" { ... } }
 			{Type: "router"},
 			{Type: "budget"},
+			{Type: "aggregate"},
+			{Type: "models"},
+			{Type: "sampling"},
+			{Type: "output_sink", LabelNames: []string{"name"}},
 		},
 	})
 	if diags.HasErrors() {
@@ -2808,6 +3770,20 @@ func parseTaskBlock(block *hcl.Block, ctx *hcl.EvalContext) (*Task, error) {
 		}
 	}
 
+	// Get requires (optional array of capability tags)
+	var requires []string
+	if attr, ok := taskContent.Attributes["requires"]; ok {
+		val, diags := attr.Expr.Value(ctx)
+		if diags.HasErrors() {
+			return nil, fmt.Errorf("task '%s': %w", taskName, diags)
+		}
+		tags, err := parseCapabilityTags(val)
+		if err != nil {
+			return nil, fmt.Errorf("task '%s': requires: %w", taskName, err)
+		}
+		requires = tags
+	}
+
 	// Get packets (optional array of packet names)
 	var taskPackets []string
 	if pktAttr, ok := taskContent.Attributes["packets"]; ok {
@@ -2828,10 +3804,11 @@ func parseTaskBlock(block *hcl.Block, ctx *hcl.EvalContext) (*Task, error) {
 		if diags.HasErrors() {
 			return nil, fmt.Errorf("task '%s': %w", taskName, diags)
 		}
-		for it := depVal.ElementIterator(); it.Next(); {
-			_, v := it.Element()
-			dependsOn = append(dependsOn, v.AsString())
+		names, err := taskRefNames(depVal)
+		if err != nil {
+			return nil, fmt.Errorf("task '%s': depends_on: %w", taskName, err)
 		}
+		dependsOn = names
 	}
 
 	// Get send_to (optional array of task references)
@@ -2841,10 +3818,11 @@ func parseTaskBlock(block *hcl.Block, ctx *hcl.EvalContext) (*Task, error) {
 		if diags.HasErrors() {
 			return nil, fmt.Errorf("task '%s': %w", taskName, diags)
 		}
-		for it := sendToVal.ElementIterator(); it.Next(); {
-			_, v := it.Element()
-			sendTo = append(sendTo, v.AsString())
+		names, err := taskRefNames(sendToVal)
+		if err != nil {
+			return nil, fmt.Errorf("task '%s': send_to: %w", taskName, err)
 		}
+		sendTo = names
 	}
 
 	// Parse iterator block if present
@@ -2860,31 +3838,9 @@ func parseTaskBlock(block *hcl.Block, ctx *hcl.EvalContext) (*Task, error) {
 		}
 	}
 
-	// Parse output — accept either shorthand attribute or verbose block form.
-	var output *OutputSchema
-	if outputAttr, ok := taskContent.Attributes["output"]; ok {
-		// Shorthand: output = { summary = string("Research summary", true) }
-		val, diags := outputAttr.Expr.Value(ctx)
-		if diags.HasErrors() {
-			return nil, fmt.Errorf("task '%s': output: %w", taskName, diags)
-		}
-		fields, err := parseOutputSchemaObject(val)
-		if err != nil {
-			return nil, fmt.Errorf("task '%s': output: %w", taskName, err)
-		}
-		output = &OutputSchema{Fields: fields}
-	} else {
-		// Verbose block form: output { field "summary" { type = "string" ... } }
-		for _, outputBlock := range taskContent.Blocks {
-			if outputBlock.Type == "output" {
-				out, err := parseOutputBlock(outputBlock)
-				if err != nil {
-					return nil, fmt.Errorf("task '%s': %w", taskName, err)
-				}
-				output = out
-				break
-			}
-		}
+	output, err := parseOutputSchema(taskContent, ctx)
+	if err != nil {
+		return nil, fmt.Errorf("task '%s': %w", taskName, err)
 	}
 
 	// Parse router block if present
@@ -2916,6 +3872,70 @@ func parseTaskBlock(block *hcl.Block, ctx *hcl.EvalContext) (*Task, error) {
 		taskBudget = b
 	}
 
+	// Parse aggregate block if present
+	var aggregate *TaskAggregate
+	for _, aggBlock := range taskContent.Blocks {
+		if aggBlock.Type != "aggregate" {
+			continue
+		}
+		if aggregate != nil {
+			return nil, fmt.Errorf("task '%s': only one aggregate block allowed", taskName)
+		}
+		a, err := parseAggregateBlock(aggBlock, ctx)
+		if err != nil {
+			return nil, fmt.Errorf("task '%s': %w", taskName, err)
+		}
+		aggregate = a
+	}
+
+	// Parse models block (commander / agent model overrides) if present
+	var modelOverride *ModelOverride
+	for _, modelsBlock := range taskContent.Blocks {
+		if modelsBlock.Type != "models" {
+			continue
+		}
+		if modelOverride != nil {
+			return nil, fmt.Errorf("task '%s': only one models block allowed", taskName)
+		}
+		m, err := parseModelOverrideBlock(modelsBlock, ctx)
+		if err != nil {
+			return nil, fmt.Errorf("task '%s': %w", taskName, err)
+		}
+		modelOverride = m
+	}
+
+	// Parse sampling block (sampling settings for the task's LLM calls) if present
+	var sampling *Sampling
+	for _, samplingBlock := range taskContent.Blocks {
+		if samplingBlock.Type != "sampling" {
+			continue
+		}
+		if sampling != nil {
+			return nil, fmt.Errorf("task '%s': only one sampling block allowed", taskName)
+		}
+		var sp Sampling
+		if d := gohcl.DecodeBody(samplingBlock.Body, ctx, &sp); d.HasErrors() {
+			return nil, fmt.Errorf("task '%s': sampling: %w", taskName, d)
+		}
+		if err := sp.Validate(); err != nil {
+			return nil, fmt.Errorf("task '%s': sampling: %w", taskName, err)
+		}
+		sampling = &sp
+	}
+
+	// Parse output_sink blocks
+	var outputSinks []OutputSink
+	for _, sinkBlock := range taskContent.Blocks {
+		if sinkBlock.Type != "output_sink" {
+			continue
+		}
+		sink, err := parseOutputSinkBlock(sinkBlock, ctx)
+		if err != nil {
+			return nil, fmt.Errorf("task '%s': %w", taskName, err)
+		}
+		outputSinks = append(outputSinks, *sink)
+	}
+
 	// Validate: sequential iterator tasks must not reference `item` in their objective.
 	// The commander receives item data via the dataset_next tool, not through the objective.
 	if iterator != nil && !iterator.Parallel {
@@ -2926,28 +3946,248 @@ func parseTaskBlock(block *hcl.Block, ctx *hcl.EvalContext) (*Task, error) {
 		}
 	}
 
+	// Parse optional timeout (Go duration string, e.g. "10m")
+	var timeout string
+	if attr, ok := taskContent.Attributes["timeout"]; ok {
+		t, err := parseTimeoutAttr(attr, ctx)
+		if err != nil {
+			return nil, fmt.Errorf("task '%s': %w", taskName, err)
+		}
+		timeout = t
+	}
+
+	// Parse optional max_turns
+	var maxTurns int
+	if attr, ok := taskContent.Attributes["max_turns"]; ok {
+		val, diags := attr.Expr.Value(ctx)
+		if diags.HasErrors() {
+			return nil, fmt.Errorf("task '%s': %w", taskName, diags)
+		}
+		if val.Type() != cty.Number {
+			return nil, fmt.Errorf("task '%s': max_turns must be a number", taskName)
+		}
+		n, _ := val.AsBigFloat().Int64()
+		if n < 1 {
+			return nil, fmt.Errorf("task '%s': max_turns must be at least 1", taskName)
+		}
+		maxTurns = int(n)
+	}
+
+	// Parse optional priority
+	var priority int
+	if attr, ok := taskContent.Attributes["priority"]; ok {
+		val, diags := attr.Expr.Value(ctx)
+		if diags.HasErrors() {
+			return nil, fmt.Errorf("task '%s': %w", taskName, diags)
+		}
+		if val.Type() != cty.Number {
+			return nil, fmt.Errorf("task '%s': priority must be a number", taskName)
+		}
+		n, acc := val.AsBigFloat().Int64()
+		if acc != big.Exact {
+			return nil, fmt.Errorf("task '%s': priority must be a whole number", taskName)
+		}
+		priority = int(n)
+	}
+
+	// Parse optional require_approval
+	var requireApproval bool
+	if attr, ok := taskContent.Attributes["require_approval"]; ok {
+		val, diags := attr.Expr.Value(ctx)
+		if diags.HasErrors() {
+			return nil, fmt.Errorf("task '%s': %w", taskName, diags)
+		}
+		if val.Type() != cty.Bool {
+			return nil, fmt.Errorf("task '%s': require_approval must be a bool", taskName)
+		}
+		requireApproval = val.True()
+	}
+	if requireApproval && iterator != nil {
+		return nil, fmt.Errorf("task '%s': require_approval is not supported on iterated tasks", taskName)
+	}
+
+	// Parse optional verify
+	var verify bool
+	if attr, ok := taskContent.Attributes["verify"]; ok {
+		val, diags := attr.Expr.Value(ctx)
+		if diags.HasErrors() {
+			return nil, fmt.Errorf("task '%s': %w", taskName, diags)
+		}
+		if val.Type() != cty.Bool {
+			return nil, fmt.Errorf("task '%s': verify must be a bool", taskName)
+		}
+		verify = val.True()
+	}
+	if verify && iterator != nil {
+		return nil, fmt.Errorf("task '%s': verify is not supported on iterated tasks", taskName)
+	}
+
+	// Parse optional tools_allow / tools_deny (tool references)
+	toolLists := map[string][]string{}
+	for _, name := range []string{"tools_allow", "tools_deny"} {
+		attr, ok := taskContent.Attributes[name]
+		if !ok {
+			continue
+		}
+		val, diags := attr.Expr.Value(ctx)
+		if diags.HasErrors() {
+			return nil, fmt.Errorf("task '%s': %w", taskName, diags)
+		}
+		if !val.Type().IsListType() && !val.Type().IsTupleType() && !val.Type().IsSetType() {
+			return nil, fmt.Errorf("task '%s': %s must be a list of tool references", taskName, name)
+		}
+		for it := val.ElementIterator(); it.Next(); {
+			_, v := it.Element()
+			if v.Type() != cty.String {
+				return nil, fmt.Errorf("task '%s': %s must be a list of tool references", taskName, name)
+			}
+			toolLists[name] = append(toolLists[name], v.AsString())
+		}
+	}
+
+	// Parse optional env / working_dir for plugin tool calls
+	env, workingDir, err := parseToolEnv(taskContent.Attributes, ctx)
+	if err != nil {
+		return nil, fmt.Errorf("task '%s': %w", taskName, err)
+	}
+
+	// Parse optional when guard (evaluated by the runner once dependencies finish)
+	var whenExpr hcl.Expression
+	var rawWhen string
+	if attr, ok := taskContent.Attributes["when"]; ok {
+		if err := parseWhenExpr(attr.Expr); err != nil {
+			return nil, fmt.Errorf("task '%s': %w", taskName, err)
+		}
+		whenExpr = attr.Expr
+		rawWhen = extractExpressionSource(attr.Expr)
+	}
+
 	return &Task{
 		Name:          taskName,
 		ObjectiveExpr: objectiveExpr,
 		RawObjective:  rawObjective,
 		Agents:        agents,
+		Requires:      requires,
 		Packets:      taskPackets,
 		DependsOn:     dependsOn,
 		SendTo:        sendTo,
 		Iterator:      iterator,
+		Aggregate:     aggregate,
 		Output:        output,
 		Router:        router,
 		Budget:        taskBudget,
+		Timeout:       timeout,
+		MaxTurns:      maxTurns,
+		Priority:      priority,
+		RequireApproval: requireApproval,
+		Verify:          verify,
+		ToolsAllow:      toolLists["tools_allow"],
+		ToolsDeny:       toolLists["tools_deny"],
+		Models:          modelOverride,
+		Sampling:        sampling,
+		WhenExpr:        whenExpr,
+		RawWhen:         rawWhen,
+		Env:             env,
+		WorkingDir:      workingDir,
+		OutputSinks:     outputSinks,
 	}, nil
 }
 
-// parseBudgetBlock parses a `budget { tokens = N, dollars = M }` block.
-// Both attributes are optional but at least one must be set (enforced by Validate).
+// parseOutputSinkBlock parses a task's output_sink block. Like dataset
+// exports, values may use vars but not mission inputs.
+func parseOutputSinkBlock(block *hcl.Block, ctx *hcl.EvalContext) (*OutputSink, error) {
+	name := block.Labels[0]
+	content, diags := block.Body.Content(&hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{
+			{Name: "type", Required: true},
+			{Name: "url"},
+			{Name: "headers"},
+			{Name: "secret"},
+			{Name: "path"},
+			{Name: "region"},
+			{Name: "topic"},
+			{Name: "max_retries"},
+		},
+	})
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("output_sink '%s': %w", name, diags)
+	}
+
+	sink := &OutputSink{Name: name}
+	fields := map[string]*string{
+		"type":   &sink.Type,
+		"url":    &sink.URL,
+		"secret": &sink.Secret,
+		"path":   &sink.Path,
+		"region": &sink.Region,
+		"topic":  &sink.Topic,
+	}
+	for attrName, attr := range content.Attributes {
+		val, diags := attr.Expr.Value(ctx)
+		if diags.HasErrors() {
+			return nil, fmt.Errorf("output_sink '%s': %w", name, diags)
+		}
+		if !val.IsWhollyKnown() {
+			return nil, fmt.Errorf("output_sink '%s': %s cannot reference mission inputs", name, attrName)
+		}
+		switch attrName {
+		case "headers":
+			if !val.Type().IsObjectType() && !val.Type().IsMapType() {
+				return nil, fmt.Errorf("output_sink '%s': headers must be a map of strings", name)
+			}
+			sink.Headers = make(map[string]string)
+			for it := val.ElementIterator(); it.Next(); {
+				k, v := it.Element()
+				if v.IsNull() || v.Type() != cty.String {
+					return nil, fmt.Errorf("output_sink '%s': header %s must be a string", name, k.AsString())
+				}
+				sink.Headers[k.AsString()] = v.AsString()
+			}
+		case "max_retries":
+			if val.Type() != cty.Number || !val.AsBigFloat().IsInt() {
+				return nil, fmt.Errorf("output_sink '%s': max_retries must be an integer", name)
+			}
+			n, _ := val.AsBigFloat().Int64()
+			retries := int(n)
+			sink.MaxRetries = &retries
+		default:
+			if val.Type() != cty.String {
+				return nil, fmt.Errorf("output_sink '%s': %s must be a string", name, attrName)
+			}
+			*fields[attrName] = val.AsString()
+		}
+	}
+	return sink, nil
+}
+
+// parseTimeoutAttr evaluates a timeout attribute and checks that it is a
+// positive Go duration string such as "90s" or "10m".
+func parseTimeoutAttr(attr *hcl.Attribute, ctx *hcl.EvalContext) (string, error) {
+	val, diags := attr.Expr.Value(ctx)
+	if diags.HasErrors() {
+		return "", fmt.Errorf("timeout: %w", diags)
+	}
+	if val.Type() != cty.String {
+		return "", fmt.Errorf("timeout must be a duration string like \"10m\"")
+	}
+	d, err := time.ParseDuration(val.AsString())
+	if err != nil {
+		return "", fmt.Errorf("timeout %q: %w", val.AsString(), err)
+	}
+	if d <= 0 {
+		return "", fmt.Errorf("timeout must be > 0")
+	}
+	return val.AsString(), nil
+}
+
+// parseBudgetBlock parses a `budget { tokens = N, dollars = M, llm_calls = K }` block.
+// Every attribute is optional but at least one must be set (enforced by Validate).
 func parseBudgetBlock(block *hcl.Block, ctx *hcl.EvalContext) (*Budget, error) {
 	content, _, diags := block.Body.PartialContent(&hcl.BodySchema{
 		Attributes: []hcl.AttributeSchema{
 			{Name: "tokens"},
 			{Name: "dollars"},
+			{Name: "llm_calls"},
 		},
 	})
 	if diags.HasErrors() {
@@ -2975,6 +4215,18 @@ func parseBudgetBlock(block *hcl.Block, ctx *hcl.EvalContext) (*Budget, error) {
 		f, _ := val.AsBigFloat().Float64()
 		b.Dollars = &f
 	}
+	if attr, ok := content.Attributes["llm_calls"]; ok {
+		val, diags := attr.Expr.Value(ctx)
+		if diags.HasErrors() {
+			return nil, fmt.Errorf("llm_calls: %w", diags)
+		}
+		bf := val.AsBigFloat()
+		if !bf.IsInt() {
+			return nil, fmt.Errorf("llm_calls must be an integer")
+		}
+		n, _ := bf.Int64()
+		b.LLMCalls = &n
+	}
 	if err := b.Validate(); err != nil {
 		return nil, err
 	}
@@ -2989,7 +4241,32 @@ type outputFieldBlock struct {
 	Required    bool   `hcl:"required,optional"`
 }
 
-// parseOutputBlock parses an output block within a task
+// parseOutputSchema parses the output schema of a task or agent body, given
+// either as the shorthand attribute or the verbose block. It returns nil when
+// the body has neither.
+func parseOutputSchema(content *hcl.BodyContent, ctx *hcl.EvalContext) (*OutputSchema, error) {
+	if outputAttr, ok := content.Attributes["output"]; ok {
+		// Shorthand: output = { summary = string("Research summary", true) }
+		val, diags := outputAttr.Expr.Value(ctx)
+		if diags.HasErrors() {
+			return nil, fmt.Errorf("output: %w", diags)
+		}
+		fields, err := parseOutputSchemaObject(val)
+		if err != nil {
+			return nil, fmt.Errorf("output: %w", err)
+		}
+		return &OutputSchema{Fields: fields}, nil
+	}
+	// Verbose block form: output { field "summary" { type = "string" ... } }
+	for _, outputBlock := range content.Blocks {
+		if outputBlock.Type == "output" {
+			return parseOutputBlock(outputBlock)
+		}
+	}
+	return nil, nil
+}
+
+// parseOutputBlock parses an output block within a task or agent
 func parseOutputBlock(block *hcl.Block) (*OutputSchema, error) {
 	var outputContent struct {
 		Fields []outputFieldBlock `hcl:"field,block"`
@@ -3074,6 +4351,9 @@ func parsePluginBlock(block *hcl.Block, ctx *hcl.EvalContext) (*Plugin, error) {
 		Attributes: []hcl.AttributeSchema{
 			{Name: "source"},
 			{Name: "version", Required: true},
+			{Name: "plugin_timeout"},
+			{Name: "tool_timeout"},
+			{Name: "tool_timeouts"},
 		},
 		Blocks: []hcl.BlockHeaderSchema{
 			{Type: "settings"},
@@ -3106,6 +4386,47 @@ func parsePluginBlock(block *hcl.Block, ctx *hcl.EvalContext) (*Plugin, error) {
 		Settings: make(map[string]string),
 	}
 
+	if attr, ok := pluginContent.Attributes["plugin_timeout"]; ok {
+		timeout, err := parseTimeoutAttr(attr, ctx)
+		if err != nil {
+			return nil, fmt.Errorf("plugin '%s' plugin_%w", pluginName, err)
+		}
+		p.Timeout = timeout
+	}
+
+	if attr, ok := pluginContent.Attributes["tool_timeout"]; ok {
+		timeout, err := parseTimeoutAttr(attr, ctx)
+		if err != nil {
+			return nil, fmt.Errorf("plugin '%s' tool_%w", pluginName, err)
+		}
+		p.ToolTimeout = timeout
+	}
+
+	if attr, ok := pluginContent.Attributes["tool_timeouts"]; ok {
+		val, diags := attr.Expr.Value(ctx)
+		if diags.HasErrors() {
+			return nil, fmt.Errorf("plugin '%s' tool_timeouts: %w", pluginName, diags)
+		}
+		if !val.Type().IsObjectType() && !val.Type().IsMapType() {
+			return nil, fmt.Errorf("plugin '%s' tool_timeouts must be a map of tool name to duration", pluginName)
+		}
+		p.ToolTimeouts = make(map[string]string)
+		for it := val.ElementIterator(); it.Next(); {
+			k, v := it.Element()
+			if v.Type() != cty.String {
+				return nil, fmt.Errorf("plugin '%s' tool_timeouts '%s' must be a duration string like \"2m\"", pluginName, k.AsString())
+			}
+			d, err := time.ParseDuration(v.AsString())
+			if err != nil {
+				return nil, fmt.Errorf("plugin '%s' tool_timeouts '%s' %q: %w", pluginName, k.AsString(), v.AsString(), err)
+			}
+			if d <= 0 {
+				return nil, fmt.Errorf("plugin '%s' tool_timeouts '%s' must be > 0", pluginName, k.AsString())
+			}
+			p.ToolTimeouts[k.AsString()] = v.AsString()
+		}
+	}
+
 	// Parse settings block if present
 	for _, settingsBlock := range pluginContent.Blocks {
 		if settingsBlock.Type == "settings" {
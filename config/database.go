@@ -0,0 +1,94 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"squadron/aitools"
+)
+
+// DatabaseConfig describes a top-level `database "name" { ... }` block. Each
+// one is a database the builtins.sql tools (query, schema, explain) can
+// reach. Databases are read-only unless read_only = false, and query
+// results are capped at max_rows. A relative SQLite path is anchored to
+// the HCL file's directory.
+//
+//	database "analytics" {
+//	  driver      = "postgres" # postgres, mysql or sqlite
+//	  dsn         = vars.analytics_dsn
+//	  description = "Product analytics warehouse"
+//	  max_rows    = 500
+//	}
+type DatabaseConfig struct {
+	Name        string `hcl:"name,label"`
+	Driver      string `hcl:"driver"`
+	DSN         string `hcl:"dsn"`
+	Description string `hcl:"description,optional"`
+	ReadOnly    *bool  `hcl:"read_only,optional"`
+	MaxRows     int    `hcl:"max_rows,optional"`
+}
+
+// resolvePaths anchors a relative SQLite file path to dir.
+func (d *DatabaseConfig) resolvePaths(dir string) {
+	if d.Driver != "sqlite" || d.DSN == "" || strings.HasPrefix(d.DSN, "file:") || strings.HasPrefix(d.DSN, ":memory:") {
+		return
+	}
+	if !filepath.IsAbs(d.DSN) {
+		d.DSN = filepath.Join(dir, d.DSN)
+	}
+}
+
+// Validate checks the driver, DSN and row cap.
+func (d *DatabaseConfig) Validate() error {
+	switch d.Driver {
+	case "postgres", "mysql", "sqlite":
+	default:
+		return fmt.Errorf("driver must be one of postgres, mysql, sqlite (got '%s')", d.Driver)
+	}
+	if d.DSN == "" {
+		return fmt.Errorf("dsn is required")
+	}
+	if d.MaxRows < 0 {
+		return fmt.Errorf("max_rows must be positive")
+	}
+	return nil
+}
+
+// IsReadOnly reports whether the database only accepts reads (the default).
+func (d *DatabaseConfig) IsReadOnly() bool {
+	return d.ReadOnly == nil || *d.ReadOnly
+}
+
+// sqlDatabases returns the runtime view of the database blocks for the sql
+// tools, or nil when there are none.
+func sqlDatabases(dbs []DatabaseConfig) aitools.SQLDatabases {
+	if len(dbs) == 0 {
+		return nil
+	}
+	out := make(aitools.SQLDatabases, len(dbs))
+	for i, d := range dbs {
+		out[i] = aitools.SQLDatabase{
+			Name:        d.Name,
+			Driver:      d.Driver,
+			DSN:         d.DSN,
+			Description: d.Description,
+			ReadOnly:    d.IsReadOnly(),
+			MaxRows:     d.MaxRows,
+		}
+	}
+	return out
+}
+
+// checkConfiguredToolRef rejects builtin tool references whose backing
+// top-level block is missing: builtins.shell needs a shell block (there is
+// no allowlist to enforce without one) and builtins.sql a database block.
+func (c *Config) checkConfiguredToolRef(ref string) error {
+	if c.Shell == nil && strings.HasPrefix(ref, "builtins.shell.") {
+		return fmt.Errorf("tool '%s' requires a top-level shell { allowed_commands = [...] } block", ref)
+	}
+	if len(c.Databases) == 0 && strings.HasPrefix(ref, "builtins.sql.") {
+		return fmt.Errorf("tool '%s' requires at least one top-level database block", ref)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package config_test
+
+import (
+	"path/filepath"
+
+	"squadron/aitools"
+	"squadron/config"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Database Config", func() {
+
+	It("parses database blocks, defaulting to read-only", func() {
+		dir, f := writeFixture("db.hcl", `
+database "shop" {
+  driver   = "sqlite"
+  dsn      = "./shop.db"
+  max_rows = 50
+}
+
+database "warehouse" {
+  driver      = "postgres"
+  dsn         = "postgres://localhost/warehouse"
+  description = "Analytics warehouse"
+  read_only   = false
+}
+`)
+		cfg, err := config.LoadFile(f)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Databases).To(HaveLen(2))
+		Expect(cfg.Databases[0].DSN).To(Equal(filepath.Join(dir, "shop.db")))
+		Expect(cfg.Databases[0].IsReadOnly()).To(BeTrue())
+		Expect(cfg.Databases[1].IsReadOnly()).To(BeFalse())
+	})
+
+	It("rejects an unknown driver", func() {
+		_, f := writeFixture("db.hcl", `
+database "shop" {
+  driver = "oracle"
+  dsn    = "x"
+}
+`)
+		_, err := config.LoadFile(f)
+		Expect(err).To(MatchError(ContainSubstring("driver must be one of")))
+	})
+
+	It("requires a database block for builtins.sql tools", func() {
+		hcl := minimalVarsHCL() + minimalModelHCL() + `
+agent "analyst" {
+  model       = models.anthropic.claude_sonnet_4
+  personality = "Curious"
+  role        = "Analyst"
+  tools       = [builtins.sql.query]
+}
+`
+		_, f := writeFixture("config.hcl", hcl)
+		cfg, err := config.LoadFile(f)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Validate()).To(MatchError(ContainSubstring("requires at least one top-level database block")))
+	})
+
+	It("builds sql tools carrying the configured databases", func() {
+		hcl := minimalVarsHCL() + minimalModelHCL() + `
+database "shop" {
+  driver = "sqlite"
+  dsn    = ":memory:"
+}
+
+agent "analyst" {
+  model       = models.anthropic.claude_sonnet_4
+  personality = "Curious"
+  role        = "Analyst"
+  tools       = [builtins.sql.all]
+}
+`
+		_, f := writeFixture("config.hcl", hcl)
+		cfg, err := config.LoadFile(f)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Validate()).To(Succeed())
+
+		tools := config.BuildToolsMap(cfg.Agents[0].Tools, nil, nil, nil, cfg.Builtins(nil), nil, nil)
+		Expect(tools).To(HaveKey("builtins.sql.schema"))
+		Expect(tools).To(HaveKey("builtins.sql.explain"))
+		q, ok := tools["builtins.sql.query"].(*aitools.SQLQueryTool)
+		Expect(ok).To(BeTrue())
+		Expect(q.Databases).To(HaveLen(1))
+		Expect(q.Databases[0].ReadOnly).To(BeTrue())
+		Expect(q.Databases[0].DSN).To(Equal(":memory:"))
+	})
+})
@@ -0,0 +1,161 @@
+package config
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+)
+
+// Dataset export formats
+const (
+	DatasetExportJSON  = "json"
+	DatasetExportCSV   = "csv"
+	DatasetExportJSONL = "jsonl"
+)
+
+// DatasetExport describes where a dataset's items are written when the
+// mission completes, so datasets populated at runtime (result_to_dataset,
+// set_dataset) outlive the run. The file is overwritten on every run.
+type DatasetExport struct {
+	Path   string `json:"path"`   // file path (absolute after config load)
+	Format string `json:"format"` // json, csv, or jsonl
+}
+
+// Validate checks that the export configuration is valid
+func (e *DatasetExport) Validate() error {
+	if e.Path == "" {
+		return fmt.Errorf("export requires path")
+	}
+	return ValidateDatasetExportFormat(e.Format)
+}
+
+// ValidateDatasetExportFormat checks that format is a supported export format.
+func ValidateDatasetExportFormat(format string) error {
+	switch format {
+	case DatasetExportJSON, DatasetExportCSV, DatasetExportJSONL:
+		return nil
+	case "":
+		return fmt.Errorf("export format is required")
+	default:
+		return fmt.Errorf("unknown export format '%s' (expected json, csv, or jsonl)", format)
+	}
+}
+
+// WriteFile writes items to the export path, creating parent directories.
+// The file is replaced atomically so a failed export never leaves a
+// truncated file behind.
+func (e *DatasetExport) WriteFile(schema *InputsSchema, items []any) error {
+	if err := os.MkdirAll(filepath.Dir(e.Path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(e.Path), "."+filepath.Base(e.Path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := WriteDatasetItems(tmp, e.Format, schema, items); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), e.Path)
+}
+
+// WriteDatasetItems writes dataset items (plain Go values, as decoded from
+// JSON) in the given format. CSV columns follow the schema's field order,
+// then any remaining fields alphabetically; nested values are JSON-encoded.
+func WriteDatasetItems(w io.Writer, format string, schema *InputsSchema, items []any) error {
+	switch format {
+	case DatasetExportJSON:
+		if items == nil {
+			items = []any{}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(items)
+	case DatasetExportJSONL:
+		enc := json.NewEncoder(w)
+		for _, item := range items {
+			if err := enc.Encode(item); err != nil {
+				return err
+			}
+		}
+		return nil
+	case DatasetExportCSV:
+		return writeCSVItems(w, schema, items)
+	default:
+		return ValidateDatasetExportFormat(format)
+	}
+}
+
+func writeCSVItems(w io.Writer, schema *InputsSchema, items []any) error {
+	rows := make([]map[string]any, 0, len(items))
+	for i, item := range items {
+		row, ok := item.(map[string]any)
+		if !ok {
+			return fmt.Errorf("item %d: csv export requires object items", i)
+		}
+		rows = append(rows, row)
+	}
+
+	var columns []string
+	if schema != nil {
+		for _, f := range schema.Fields {
+			columns = append(columns, f.Name)
+		}
+	}
+	var extra []string
+	for _, row := range rows {
+		for k := range row {
+			if !slices.Contains(columns, k) && !slices.Contains(extra, k) {
+				extra = append(extra, k)
+			}
+		}
+	}
+	sort.Strings(extra)
+	columns = append(columns, extra...)
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(columns); err != nil {
+		return err
+	}
+	record := make([]string, len(columns))
+	for _, row := range rows {
+		for i, col := range columns {
+			cell, err := csvCell(row[col])
+			if err != nil {
+				return fmt.Errorf("field '%s': %w", col, err)
+			}
+			record[i] = cell
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// csvCell renders a single value as a CSV cell: strings as-is, nil as
+// empty, everything else as JSON.
+func csvCell(v any) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
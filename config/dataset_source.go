@@ -0,0 +1,312 @@
+package config
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Dataset source types
+const (
+	DatasetSourceCSV   = "csv"
+	DatasetSourceJSONL = "jsonl"
+	DatasetSourceHTTP  = "http"
+)
+
+// datasetSourceTimeout bounds how long an HTTP dataset source may take.
+const datasetSourceTimeout = 30 * time.Second
+
+// DatasetSource describes where a dataset's items are loaded from when they
+// are not declared inline. Items are read once when the mission starts.
+type DatasetSource struct {
+	Type string `json:"type"`           // csv, jsonl, or http
+	Path string `json:"path,omitempty"` // csv/jsonl: file path (absolute after config load)
+	URL  string `json:"url,omitempty"`  // http: endpoint returning JSON
+	// Headers are sent with the HTTP request. Excluded from JSON because
+	// they commonly carry credentials.
+	Headers map[string]string `json:"-"`
+	// ItemsPath is a dotted path to the item array inside an HTTP JSON
+	// response (e.g. "data.results"). Empty means the response is the array.
+	ItemsPath string `json:"itemsPath,omitempty"`
+	// Fields maps item field names to source column/key names. When set, only
+	// the mapped fields are kept.
+	Fields map[string]string `json:"fields,omitempty"`
+}
+
+// Validate checks that the source configuration is valid
+func (s *DatasetSource) Validate() error {
+	switch s.Type {
+	case DatasetSourceCSV, DatasetSourceJSONL:
+		if s.Path == "" {
+			return fmt.Errorf("source type '%s' requires path", s.Type)
+		}
+		if s.URL != "" {
+			return fmt.Errorf("source type '%s' does not accept url", s.Type)
+		}
+	case DatasetSourceHTTP:
+		if s.URL == "" {
+			return fmt.Errorf("source type 'http' requires url")
+		}
+		if !strings.HasPrefix(s.URL, "http://") && !strings.HasPrefix(s.URL, "https://") {
+			return fmt.Errorf("source url must start with http:// or https://")
+		}
+		if s.Path != "" {
+			return fmt.Errorf("source type 'http' does not accept path")
+		}
+	case "":
+		return fmt.Errorf("source type is required")
+	default:
+		return fmt.Errorf("unknown source type '%s' (expected csv, jsonl, or http)", s.Type)
+	}
+	if s.ItemsPath != "" && s.Type != DatasetSourceHTTP {
+		return fmt.Errorf("items_path is only supported for http sources")
+	}
+	return nil
+}
+
+// LoadSource reads the dataset's items from its source, applies the field
+// mapping, converts values to the schema's declared types, and validates
+// every item against the schema. Errors name the offending row. Transforms
+// are not applied here.
+func (d *Dataset) LoadSource(ctx context.Context) ([]cty.Value, error) {
+	if d.Source == nil {
+		return nil, nil
+	}
+
+	var rows []map[string]any
+	var err error
+	switch d.Source.Type {
+	case DatasetSourceCSV:
+		rows, err = readCSVRows(d.Source.Path)
+	case DatasetSourceJSONL:
+		rows, err = readJSONLRows(d.Source.Path)
+	case DatasetSourceHTTP:
+		rows, err = fetchHTTPRows(ctx, d.Source)
+	default:
+		err = fmt.Errorf("unknown source type '%s'", d.Source.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("source: %w", err)
+	}
+
+	items := make([]cty.Value, 0, len(rows))
+	for i, row := range rows {
+		item, err := d.buildSourceItem(row)
+		if err != nil {
+			return nil, fmt.Errorf("source row %d: %w", i+1, err)
+		}
+		// With transforms, items are checked once the pipeline has run,
+		// since a rename or map may produce the schema's fields
+		if len(d.Transforms) == 0 {
+			if err := d.ValidateItem(item); err != nil {
+				return nil, fmt.Errorf("source row %d: %w", i+1, err)
+			}
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// buildSourceItem applies the field mapping and schema type conversion to a
+// single raw row.
+func (d *Dataset) buildSourceItem(row map[string]any) (cty.Value, error) {
+	mapped := row
+	if len(d.Source.Fields) > 0 {
+		mapped = make(map[string]any, len(d.Source.Fields))
+		for field, column := range d.Source.Fields {
+			if v, ok := row[column]; ok {
+				mapped[field] = v
+			}
+		}
+	}
+
+	if d.Schema != nil {
+		for _, f := range d.Schema.Fields {
+			v, ok := mapped[f.Name]
+			if !ok {
+				continue
+			}
+			converted, err := coerceSourceValue(v, f.Type)
+			if err != nil {
+				return cty.NilVal, fmt.Errorf("field '%s': %w", f.Name, err)
+			}
+			if converted == nil {
+				delete(mapped, f.Name)
+				continue
+			}
+			mapped[f.Name] = converted
+		}
+	}
+
+	return mapToCtyValue(mapped), nil
+}
+
+// coerceSourceValue converts string cells (CSV, or loosely-typed JSON) to the
+// declared schema type. An empty string for a non-string field yields nil so
+// the field is treated as missing.
+func coerceSourceValue(v any, fieldType string) (any, error) {
+	s, isString := v.(string)
+	if !isString {
+		return v, nil
+	}
+	switch fieldType {
+	case "number", "integer":
+		if s == "" {
+			return nil, nil
+		}
+		n, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		if err != nil {
+			return nil, fmt.Errorf("'%s' is not a number", s)
+		}
+		if fieldType == "integer" && n != float64(int64(n)) {
+			return nil, fmt.Errorf("'%s' is not an integer", s)
+		}
+		return n, nil
+	case "boolean", "bool":
+		if s == "" {
+			return nil, nil
+		}
+		b, err := strconv.ParseBool(strings.TrimSpace(s))
+		if err != nil {
+			return nil, fmt.Errorf("'%s' is not a boolean", s)
+		}
+		return b, nil
+	}
+	return s, nil
+}
+
+func readCSVRows(path string) ([]map[string]any, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	header, err := r.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading csv header: %w", err)
+	}
+	for i := range header {
+		header[i] = strings.TrimSpace(header[i])
+	}
+	if len(header) > 0 {
+		header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	}
+
+	var rows []map[string]any
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading csv: %w", err)
+		}
+		row := make(map[string]any, len(header))
+		for i, col := range header {
+			if i < len(record) {
+				row[col] = record[i]
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func readJSONLRows(path string) ([]map[string]any, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var rows []map[string]any
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var row map[string]any
+		if err := json.Unmarshal([]byte(text), &row); err != nil {
+			return nil, fmt.Errorf("line %d: each line must be a JSON object: %w", line, err)
+		}
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func fetchHTTPRows(ctx context.Context, src *DatasetSource) ([]map[string]any, error) {
+	ctx, cancel := context.WithTimeout(ctx, datasetSourceTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, src.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	for k, v := range src.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("GET %s: %s", src.URL, resp.Status)
+	}
+
+	var body any
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("decoding response from %s: %w", src.URL, err)
+	}
+
+	if src.ItemsPath != "" {
+		for _, key := range strings.Split(src.ItemsPath, ".") {
+			obj, ok := body.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("items_path '%s': '%s' is not inside an object", src.ItemsPath, key)
+			}
+			body, ok = obj[key]
+			if !ok {
+				return nil, fmt.Errorf("items_path '%s': key '%s' not found", src.ItemsPath, key)
+			}
+		}
+	}
+
+	list, ok := body.([]any)
+	if !ok {
+		return nil, fmt.Errorf("response from %s is not a JSON array (set items_path to select one)", src.URL)
+	}
+	rows := make([]map[string]any, 0, len(list))
+	for i, el := range list {
+		row, ok := el.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("element %d of the response is not a JSON object", i)
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
@@ -0,0 +1,202 @@
+package config_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	"squadron/config"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/zclconf/go-cty/cty"
+)
+
+var _ = Describe("Dataset sources", func() {
+	missionWith := func(dataset string) string {
+		return fullBaseHCL() + `
+mission "m" {
+  commander { model = models.anthropic.claude_sonnet_4 }
+  agents = [agents.test_agent]
+` + dataset + `
+  task "work" {
+    objective = "Process ${item.name}"
+    iterator {
+      dataset  = datasets.cities
+      parallel = true
+    }
+  }
+}
+`
+	}
+
+	loadDataset := func(dir, dataset string) (*config.Dataset, error) {
+		f := filepath.Join(dir, "config.hcl")
+		Expect(os.WriteFile(f, []byte(missionWith(dataset)), 0644)).To(Succeed())
+		cfg, err := config.LoadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		if err := cfg.Validate(); err != nil {
+			return nil, err
+		}
+		return &cfg.Missions[0].Datasets[0], nil
+	}
+
+	It("loads CSV rows with field mapping and schema types", func() {
+		dir := GinkgoT().TempDir()
+		Expect(os.WriteFile(filepath.Join(dir, "cities.csv"), []byte("City,Pop,Capital\nParis,2100000,true\nLyon,,false\n"), 0644)).To(Succeed())
+
+		ds, err := loadDataset(dir, `
+  dataset "cities" {
+    schema = {
+      name       = string("City", true)
+      population = number("Population")
+      capital    = bool("Capital")
+    }
+    source {
+      type   = "csv"
+      path   = "cities.csv"
+      fields = { name = "City", population = "Pop", capital = "Capital" }
+    }
+  }`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ds.Source.Path).To(Equal(filepath.Join(dir, "cities.csv")))
+
+		items, err := ds.LoadSource(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(items).To(HaveLen(2))
+		Expect(items[0].GetAttr("name")).To(Equal(cty.StringVal("Paris")))
+		Expect(items[0].GetAttr("population").Equals(cty.NumberIntVal(2100000)).True()).To(BeTrue())
+		Expect(items[0].GetAttr("capital")).To(Equal(cty.True))
+		Expect(items[1].Type().HasAttribute("population")).To(BeFalse())
+	})
+
+	It("reports the row that fails schema validation", func() {
+		dir := GinkgoT().TempDir()
+		Expect(os.WriteFile(filepath.Join(dir, "cities.jsonl"), []byte("{\"name\":\"Paris\"}\n\n{\"title\":\"Rome\"}\n"), 0644)).To(Succeed())
+
+		ds, err := loadDataset(dir, `
+  dataset "cities" {
+    schema = { name = string("City", true) }
+    source {
+      type = "jsonl"
+      path = "cities.jsonl"
+    }
+  }`)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = ds.LoadSource(context.Background())
+		Expect(err).To(MatchError(ContainSubstring("source row 2: required field 'name' is missing")))
+	})
+
+	It("fetches items from an HTTP endpoint using items_path and headers", func() {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer test-key-123" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(`{"data":{"results":[{"name":"Paris","pop":2100000},{"name":"Oslo","pop":700000}]}}`))
+		}))
+		defer srv.Close()
+
+		ds, err := loadDataset(GinkgoT().TempDir(), `
+  dataset "cities" {
+    schema = { name = string("City", true), pop = number("Population") }
+    source {
+      type       = "http"
+      url        = "`+srv.URL+`"
+      headers    = { Authorization = "Bearer ${vars.test_api_key}" }
+      items_path = "data.results"
+    }
+  }`)
+		Expect(err).NotTo(HaveOccurred())
+
+		items, err := ds.LoadSource(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(items).To(HaveLen(2))
+		Expect(items[1].GetAttr("name")).To(Equal(cty.StringVal("Oslo")))
+	})
+
+	It("rejects a source combined with inline items", func() {
+		_, err := loadDataset(GinkgoT().TempDir(), `
+  dataset "cities" {
+    items = [{ name = "Paris" }]
+    source {
+      type = "csv"
+      path = "cities.csv"
+    }
+  }`)
+		Expect(err).To(MatchError(ContainSubstring("source cannot be combined with items or bind_to")))
+	})
+
+	It("rejects unknown source types and paths outside the project", func() {
+		_, err := loadDataset(GinkgoT().TempDir(), `
+  dataset "cities" {
+    source {
+      type = "xml"
+      path = "cities.xml"
+    }
+  }`)
+		Expect(err).To(MatchError(ContainSubstring("unknown source type 'xml'")))
+
+		_, err = loadDataset(GinkgoT().TempDir(), `
+  dataset "cities" {
+    source {
+      type = "csv"
+      path = "/etc/passwd"
+    }
+  }`)
+		Expect(err).To(MatchError(ContainSubstring("absolute paths are not allowed")))
+	})
+
+	Describe("exports", func() {
+		It("resolves the export path and writes every format", func() {
+			dir := GinkgoT().TempDir()
+			ds, err := loadDataset(dir, `
+  dataset "cities" {
+    schema = { name = string("City", true), pop = number("Population") }
+    export {
+      path   = "out/cities.csv"
+      format = "csv"
+    }
+  }`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ds.Export.Path).To(Equal(filepath.Join(dir, "out", "cities.csv")))
+
+			items := []any{
+				map[string]any{"pop": 2100000.0, "name": "Paris", "tags": []any{"capital"}},
+				map[string]any{"name": "Lyon, FR"},
+			}
+			Expect(ds.Export.WriteFile(ds.Schema, items)).To(Succeed())
+			data, err := os.ReadFile(ds.Export.Path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(Equal("name,pop,tags\nParis,2100000,\"[\"\"capital\"\"]\"\n\"Lyon, FR\",,\n"))
+
+			var buf bytes.Buffer
+			Expect(config.WriteDatasetItems(&buf, "jsonl", nil, items)).To(Succeed())
+			Expect(buf.String()).To(Equal("{\"name\":\"Paris\",\"pop\":2100000,\"tags\":[\"capital\"]}\n{\"name\":\"Lyon, FR\"}\n"))
+
+			buf.Reset()
+			Expect(config.WriteDatasetItems(&buf, "json", nil, nil)).To(Succeed())
+			Expect(buf.String()).To(Equal("[]\n"))
+		})
+
+		It("rejects unknown formats and non-object items in csv", func() {
+			_, err := loadDataset(GinkgoT().TempDir(), `
+  dataset "cities" {
+    export {
+      path   = "cities.xml"
+      format = "xml"
+    }
+  }`)
+			Expect(err).To(MatchError(ContainSubstring("unknown export format 'xml'")))
+
+			Expect(config.WriteDatasetItems(&bytes.Buffer{}, "csv", nil, []any{"Paris"})).
+				To(MatchError(ContainSubstring("item 0: csv export requires object items")))
+		})
+	})
+})
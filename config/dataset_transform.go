@@ -0,0 +1,337 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/tryfunc"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+	"github.com/zclconf/go-cty/cty/function"
+	"github.com/zclconf/go-cty/cty/function/stdlib"
+)
+
+// Dataset transform kinds
+const (
+	TransformFilter = "filter"
+	TransformDedupe = "dedupe"
+	TransformRename = "rename"
+	TransformMap    = "map"
+	TransformLimit  = "limit"
+	TransformSample = "sample"
+)
+
+// DatasetTransform is one step of a dataset's transform pipeline. Each
+// `transform` block holds a single step, and steps run in the order they are
+// declared whenever items are loaded into or appended to the dataset:
+//
+//	transform { filter = item.score >= vars.min_score }
+//	transform { dedupe = "email" }
+//	transform { rename = { company_name = "company" } }
+//	transform { map = { domain = lower(item.domain) } }
+//	transform { limit = 100 }
+type DatasetTransform struct {
+	Kind   string            `json:"kind"`
+	Fields []string          `json:"fields,omitempty"` // dedupe: fields that make up the key
+	Rename map[string]string `json:"rename,omitempty"` // rename: old field name -> new field name
+	Count  int               `json:"count,omitempty"`  // limit, sample
+	// Expr is the filter condition or the map object, evaluated per item
+	// with `item` and `vars` in scope.
+	Expr    hcl.Expression `json:"-"`
+	RawExpr string         `json:"expr,omitempty"` // raw expression source, for display
+	vars    cty.Value
+}
+
+// parseDatasetTransformBlock parses one transform block. filter and map are
+// evaluated per item at run time; the other steps are resolved here and may
+// use vars but not mission inputs.
+func parseDatasetTransformBlock(block *hcl.Block, ctx *hcl.EvalContext) (*DatasetTransform, error) {
+	content, diags := block.Body.Content(&hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{
+			{Name: TransformFilter},
+			{Name: TransformDedupe},
+			{Name: TransformRename},
+			{Name: TransformMap},
+			{Name: TransformLimit},
+			{Name: TransformSample},
+		},
+	})
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("transform: %w", diags)
+	}
+	if len(content.Attributes) != 1 {
+		return nil, fmt.Errorf("transform: each transform block takes exactly one of filter, dedupe, rename, map, limit, or sample")
+	}
+
+	var kind string
+	var attr *hcl.Attribute
+	for name, a := range content.Attributes {
+		kind, attr = name, a
+	}
+	t := &DatasetTransform{Kind: kind, vars: cty.EmptyObjectVal}
+	if v, ok := ctx.Variables["vars"]; ok {
+		t.vars = v
+	}
+
+	switch kind {
+	case TransformFilter, TransformMap:
+		for _, trav := range attr.Expr.Variables() {
+			switch trav.RootName() {
+			case "item", "vars":
+			default:
+				return nil, fmt.Errorf("transform: unknown reference '%s' in %s (expected item or vars)", trav.RootName(), kind)
+			}
+		}
+		t.Expr = attr.Expr
+		t.RawExpr = extractExpressionSource(attr.Expr)
+		return t, nil
+	}
+
+	val, diags := attr.Expr.Value(ctx)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("transform %s: %w", kind, diags)
+	}
+	if !val.IsWhollyKnown() {
+		return nil, fmt.Errorf("transform: %s cannot reference mission inputs", kind)
+	}
+	if val.IsNull() {
+		return nil, fmt.Errorf("transform: %s cannot be null", kind)
+	}
+
+	switch kind {
+	case TransformDedupe:
+		if val.Type() == cty.String {
+			t.Fields = []string{val.AsString()}
+			break
+		}
+		if !val.CanIterateElements() || val.Type().IsMapType() || val.Type().IsObjectType() {
+			return nil, fmt.Errorf("transform: dedupe must be a field name or a list of field names")
+		}
+		for it := val.ElementIterator(); it.Next(); {
+			_, v := it.Element()
+			if v.IsNull() || v.Type() != cty.String {
+				return nil, fmt.Errorf("transform: dedupe must be a field name or a list of field names")
+			}
+			t.Fields = append(t.Fields, v.AsString())
+		}
+		if len(t.Fields) == 0 {
+			return nil, fmt.Errorf("transform: dedupe needs at least one field")
+		}
+	case TransformRename:
+		if !val.Type().IsObjectType() && !val.Type().IsMapType() {
+			return nil, fmt.Errorf("transform: rename must be a map of old field name to new field name")
+		}
+		t.Rename = make(map[string]string)
+		for it := val.ElementIterator(); it.Next(); {
+			k, v := it.Element()
+			if v.IsNull() || v.Type() != cty.String {
+				return nil, fmt.Errorf("transform: rename.%s must be a string", k.AsString())
+			}
+			t.Rename[k.AsString()] = v.AsString()
+		}
+	case TransformLimit, TransformSample:
+		num, err := convert.Convert(val, cty.Number)
+		if err != nil {
+			return nil, fmt.Errorf("transform: %s must be a number", kind)
+		}
+		n, acc := num.AsBigFloat().Int64()
+		if acc != 0 || n < 1 {
+			return nil, fmt.Errorf("transform: %s must be a positive whole number", kind)
+		}
+		t.Count = int(n)
+	}
+	return t, nil
+}
+
+// ApplyTransforms runs the dataset's transform pipeline over items. existing
+// holds the items already in the dataset when items are appended: dedupe
+// then also drops items matching one of them, and limit caps the dataset's
+// total size rather than the batch's.
+func (d *Dataset) ApplyTransforms(items, existing []cty.Value) ([]cty.Value, error) {
+	for i := range d.Transforms {
+		t := &d.Transforms[i]
+		var err error
+		items, err = t.apply(items, existing)
+		if err != nil {
+			return nil, fmt.Errorf("transform %d (%s): %w", i+1, t.Kind, err)
+		}
+	}
+	return items, nil
+}
+
+func (t *DatasetTransform) apply(items, existing []cty.Value) ([]cty.Value, error) {
+	switch t.Kind {
+	case TransformFilter:
+		var out []cty.Value
+		for i, item := range items {
+			val, err := t.eval(item)
+			if err != nil {
+				return nil, fmt.Errorf("item %d: %w", i, err)
+			}
+			if val.IsNull() || val.Type() != cty.Bool {
+				return nil, fmt.Errorf("item %d: filter must be true or false, got %s", i, val.Type().FriendlyName())
+			}
+			if val.True() {
+				out = append(out, item)
+			}
+		}
+		return out, nil
+
+	case TransformDedupe:
+		seen := make(map[string]bool)
+		for _, item := range existing {
+			if key, ok := dedupeKey(item, t.Fields); ok {
+				seen[key] = true
+			}
+		}
+		var out []cty.Value
+		for _, item := range items {
+			// Items without the key fields can't be compared, so they're kept
+			key, ok := dedupeKey(item, t.Fields)
+			if ok && seen[key] {
+				continue
+			}
+			if ok {
+				seen[key] = true
+			}
+			out = append(out, item)
+		}
+		return out, nil
+
+	case TransformRename:
+		out := make([]cty.Value, len(items))
+		for i, item := range items {
+			fields, err := itemFields(item)
+			if err != nil {
+				return nil, fmt.Errorf("item %d: %w", i, err)
+			}
+			renamed := make(map[string]cty.Value, len(fields))
+			for name, v := range fields {
+				if _, ok := t.Rename[name]; !ok {
+					renamed[name] = v
+				}
+			}
+			for from, to := range t.Rename {
+				if v, ok := fields[from]; ok {
+					renamed[to] = v
+				}
+			}
+			out[i] = cty.ObjectVal(renamed)
+		}
+		return out, nil
+
+	case TransformMap:
+		out := make([]cty.Value, len(items))
+		for i, item := range items {
+			fields, err := itemFields(item)
+			if err != nil {
+				return nil, fmt.Errorf("item %d: %w", i, err)
+			}
+			val, err := t.eval(item)
+			if err != nil {
+				return nil, fmt.Errorf("item %d: %w", i, err)
+			}
+			if val.IsNull() || !(val.Type().IsObjectType() || val.Type().IsMapType()) {
+				return nil, fmt.Errorf("item %d: map must be an object of fields to set, got %s", i, val.Type().FriendlyName())
+			}
+			for name, v := range val.AsValueMap() {
+				fields[name] = v
+			}
+			out[i] = cty.ObjectVal(fields)
+		}
+		return out, nil
+
+	case TransformLimit:
+		n := t.Count - len(existing)
+		if n < 0 {
+			n = 0
+		}
+		if len(items) > n {
+			items = items[:n]
+		}
+		return items, nil
+
+	case TransformSample:
+		if len(items) <= t.Count {
+			return items, nil
+		}
+		// Pick Count indexes at random, then keep them in their original order
+		picked := rand.Perm(len(items))[:t.Count]
+		sort.Ints(picked)
+		out := make([]cty.Value, len(picked))
+		for i, idx := range picked {
+			out[i] = items[idx]
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("unknown transform")
+}
+
+// eval evaluates a filter or map expression with item in scope.
+func (t *DatasetTransform) eval(item cty.Value) (cty.Value, error) {
+	ctx := &hcl.EvalContext{
+		Variables: map[string]cty.Value{
+			"item": item,
+			"vars": t.vars,
+		},
+		Functions: transformFunctions,
+	}
+	val, diags := t.Expr.Value(ctx)
+	if diags.HasErrors() {
+		return cty.NilVal, fmt.Errorf("%s", diags.Error())
+	}
+	if !val.IsWhollyKnown() {
+		return cty.NilVal, fmt.Errorf("%s did not evaluate to a known value", t.Kind)
+	}
+	return val, nil
+}
+
+// transformFunctions are the functions filter and map expressions can call.
+var transformFunctions = map[string]function.Function{
+	"lower":     stdlib.LowerFunc,
+	"upper":     stdlib.UpperFunc,
+	"trimspace": stdlib.TrimSpaceFunc,
+	"length":    stdlib.LengthFunc,
+	"contains":  stdlib.ContainsFunc,
+	"coalesce":  stdlib.CoalesceFunc,
+	"regex":     stdlib.RegexFunc,
+	"try":       tryfunc.TryFunc,
+	"can":       tryfunc.CanFunc,
+}
+
+// itemFields returns an item's fields, for steps that reshape items.
+func itemFields(item cty.Value) (map[string]cty.Value, error) {
+	if item.IsNull() || !(item.Type().IsObjectType() || item.Type().IsMapType()) {
+		return nil, fmt.Errorf("item must be an object")
+	}
+	fields := item.AsValueMap()
+	if fields == nil {
+		fields = make(map[string]cty.Value)
+	}
+	return fields, nil
+}
+
+// dedupeKey builds an item's dedupe key from fields. It reports false when
+// the item isn't an object or lacks one of the fields.
+func dedupeKey(item cty.Value, fields []string) (string, bool) {
+	if item.IsNull() || !(item.Type().IsObjectType() || item.Type().IsMapType()) {
+		return "", false
+	}
+	values := item.AsValueMap()
+	key := make([]any, len(fields))
+	for i, f := range fields {
+		v, ok := values[f]
+		if !ok || v.IsNull() {
+			return "", false
+		}
+		key[i] = CtyValueToGo(v)
+	}
+	b, err := json.Marshal(key)
+	if err != nil {
+		return "", false
+	}
+	return string(b), true
+}
@@ -0,0 +1,198 @@
+package config_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"squadron/config"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/zclconf/go-cty/cty"
+)
+
+var _ = Describe("Dataset transforms", func() {
+	loadDatasetIn := func(dir, dataset string) (*config.Dataset, error) {
+		hcl := fullBaseHCL() + `
+variable "min_score" {
+  default = 50
+}
+
+mission "m" {
+  commander { model = models.anthropic.claude_sonnet_4 }
+  agents = [agents.test_agent]
+` + dataset + `
+  task "work" {
+    objective = "Process ${item.email}"
+    iterator {
+      dataset  = datasets.leads
+      parallel = true
+    }
+  }
+}
+`
+		f := filepath.Join(dir, "config.hcl")
+		Expect(os.WriteFile(f, []byte(hcl), 0644)).To(Succeed())
+		cfg, err := config.LoadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		if err := cfg.Validate(); err != nil {
+			return nil, err
+		}
+		return &cfg.Missions[0].Datasets[0], nil
+	}
+	loadDataset := func(dataset string) (*config.Dataset, error) {
+		return loadDatasetIn(GinkgoT().TempDir(), dataset)
+	}
+
+	lead := func(email string, score int64) cty.Value {
+		return cty.ObjectVal(map[string]cty.Value{
+			"email": cty.StringVal(email),
+			"score": cty.NumberIntVal(score),
+		})
+	}
+
+	emails := func(items []cty.Value) []string {
+		var out []string
+		for _, item := range items {
+			out = append(out, item.GetAttr("email").AsString())
+		}
+		return out
+	}
+
+	It("runs the steps in declaration order", func() {
+		ds, err := loadDataset(`
+  dataset "leads" {
+    transform { filter = item.score >= vars.min_score }
+    transform { map = { email = lower(item.email) } }
+    transform { dedupe = "email" }
+    transform { limit = 2 }
+  }`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ds.Transforms).To(HaveLen(4))
+		Expect(ds.Transforms[0].RawExpr).To(Equal("item.score >= vars.min_score"))
+
+		items, err := ds.ApplyTransforms([]cty.Value{
+			lead("a@x.com", 90),
+			lead("low@x.com", 10),
+			lead("A@X.com", 70),
+			lead("b@x.com", 60),
+			lead("c@x.com", 80),
+		}, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(emails(items)).To(Equal([]string{"a@x.com", "b@x.com"}))
+	})
+
+	It("renames fields and keeps the others", func() {
+		ds, err := loadDataset(`
+  dataset "leads" {
+    transform { rename = { mail = "email" } }
+  }`)
+		Expect(err).NotTo(HaveOccurred())
+
+		items, err := ds.ApplyTransforms([]cty.Value{cty.ObjectVal(map[string]cty.Value{
+			"mail":  cty.StringVal("a@x.com"),
+			"score": cty.NumberIntVal(1),
+		})}, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(items[0].Type().HasAttribute("mail")).To(BeFalse())
+		Expect(items[0].GetAttr("email")).To(Equal(cty.StringVal("a@x.com")))
+		Expect(items[0].Type().HasAttribute("score")).To(BeTrue())
+	})
+
+	It("samples N items in their original order", func() {
+		ds, err := loadDataset(`
+  dataset "leads" {
+    transform { sample = 3 }
+  }`)
+		Expect(err).NotTo(HaveOccurred())
+
+		var in []cty.Value
+		for i := int64(0); i < 10; i++ {
+			in = append(in, lead("x@x.com", i))
+		}
+		items, err := ds.ApplyTransforms(in, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(items).To(HaveLen(3))
+		prev := int64(-1)
+		for _, item := range items {
+			n, _ := item.GetAttr("score").AsBigFloat().Int64()
+			Expect(n).To(BeNumerically(">", prev))
+			prev = n
+		}
+	})
+
+	It("dedupes and limits against the items already in the dataset on append", func() {
+		ds, err := loadDataset(`
+  dataset "leads" {
+    transform { dedupe = ["email"] }
+    transform { limit = 3 }
+  }`)
+		Expect(err).NotTo(HaveOccurred())
+
+		existing := []cty.Value{lead("a@x.com", 1), lead("b@x.com", 2)}
+		items, err := ds.ApplyTransforms([]cty.Value{
+			lead("a@x.com", 3),
+			lead("c@x.com", 4),
+			lead("d@x.com", 5),
+		}, existing)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(emails(items)).To(Equal([]string{"c@x.com"}))
+	})
+
+	It("applies transforms before checking source rows against the schema", func() {
+		dir := GinkgoT().TempDir()
+		Expect(os.WriteFile(filepath.Join(dir, "leads.jsonl"), []byte("{\"mail\":\"a@x.com\"}\n{\"mail\":\"a@x.com\"}\n"), 0644)).To(Succeed())
+		ds, err := loadDatasetIn(dir, `
+  dataset "leads" {
+    schema = { email = string("Email", true) }
+    source {
+      type = "jsonl"
+      path = "leads.jsonl"
+    }
+    transform { rename = { mail = "email" } }
+    transform { dedupe = "email" }
+  }`)
+		Expect(err).NotTo(HaveOccurred())
+
+		items, err := ds.LoadSource(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+		items, err = ds.ApplyTransforms(items, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(items).To(HaveLen(1))
+		Expect(ds.ValidateItem(items[0])).To(Succeed())
+	})
+
+	It("reports the step and item of a failing filter", func() {
+		ds, err := loadDataset(`
+  dataset "leads" {
+    transform { limit = 10 }
+    transform { filter = item.score }
+  }`)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = ds.ApplyTransforms([]cty.Value{lead("a@x.com", 1)}, nil)
+		Expect(err).To(MatchError(ContainSubstring("transform 2 (filter): item 0: filter must be true or false")))
+	})
+
+	DescribeTable("rejects invalid transform blocks",
+		func(transform, errSubstring string) {
+			_, err := loadDataset(`
+  dataset "leads" {
+    ` + transform + `
+  }`)
+			Expect(err).To(MatchError(ContainSubstring(errSubstring)))
+		},
+		Entry("no step", `transform {}`, "exactly one of filter, dedupe, rename, map, limit, or sample"),
+		Entry("two steps", `transform {
+      limit  = 5
+      dedupe = "email"
+    }`, "exactly one of"),
+		Entry("zero limit", `transform { limit = 0 }`, "limit must be a positive whole number"),
+		Entry("fractional sample", `transform { sample = 1.5 }`, "sample must be a positive whole number"),
+		Entry("non-string dedupe", `transform { dedupe = 3 }`, "dedupe must be a field name or a list of field names"),
+		Entry("unknown reference in filter", `transform { filter = tasks.x.output.ok }`, "unknown reference 'tasks' in filter"),
+	)
+})
@@ -0,0 +1,157 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// ForEachInstance identifies a task generated by a task block's for_each:
+// the block label and the key and value of the element it was made for.
+type ForEachInstance struct {
+	Group string    `json:"group"`
+	Key   string    `json:"key"`
+	Value cty.Value `json:"-"`
+}
+
+// object returns the `each` value an instance's expressions read.
+func (e *ForEachInstance) object() cty.Value {
+	return cty.ObjectVal(map[string]cty.Value{
+		"key":   cty.StringVal(e.Key),
+		"value": e.Value,
+	})
+}
+
+// InstanceName returns the task name of a for_each instance, group["key"].
+func InstanceName(group, key string) string {
+	return group + "[" + strconv.Quote(key) + "]"
+}
+
+// splitInstanceName is the inverse of InstanceName.
+func splitInstanceName(name string) (group, key string, ok bool) {
+	i := strings.Index(name, `["`)
+	if i <= 0 || !strings.HasSuffix(name, `"]`) {
+		return "", "", false
+	}
+	key, err := strconv.Unquote(name[i+1 : len(name)-1])
+	if err != nil {
+		return "", "", false
+	}
+	return name[:i], key, true
+}
+
+// parseForEach evaluates a task block's for_each attribute. It returns
+// ok=false when the block has none. A list or set of strings makes one
+// instance per string (key and value are the string); a map or object
+// makes one per entry, in key order. The value must be known when the
+// config loads, so it can read vars but not inputs.
+func parseForEach(block *hcl.Block, ctx *hcl.EvalContext) (instances []ForEachInstance, ok bool, err error) {
+	taskName := block.Labels[0]
+	content, _, diags := block.Body.PartialContent(&hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{{Name: "for_each"}},
+	})
+	if diags.HasErrors() {
+		return nil, false, fmt.Errorf("task '%s': %w", taskName, diags)
+	}
+	attr, exists := content.Attributes["for_each"]
+	if !exists {
+		return nil, false, nil
+	}
+
+	val, diags := attr.Expr.Value(ctx)
+	if diags.HasErrors() {
+		return nil, false, fmt.Errorf("task '%s' for_each: %w", taskName, diags)
+	}
+	if !val.IsWhollyKnown() {
+		return nil, false, fmt.Errorf("task '%s': for_each must be known when the config loads — it can use vars, but not inputs", taskName)
+	}
+	if val.IsNull() {
+		return nil, false, fmt.Errorf("task '%s': for_each must not be null", taskName)
+	}
+
+	ty := val.Type()
+	seen := make(map[string]bool)
+	switch {
+	case ty.IsListType() || ty.IsTupleType() || ty.IsSetType():
+		for it := val.ElementIterator(); it.Next(); {
+			_, v := it.Element()
+			if v.IsNull() || v.Type() != cty.String {
+				return nil, false, fmt.Errorf("task '%s': for_each list elements must be strings (use a map to fan out over objects)", taskName)
+			}
+			key := v.AsString()
+			if seen[key] {
+				return nil, false, fmt.Errorf("task '%s': for_each has duplicate key %q", taskName, key)
+			}
+			seen[key] = true
+			instances = append(instances, ForEachInstance{Group: taskName, Key: key, Value: v})
+		}
+	case ty.IsMapType() || ty.IsObjectType():
+		for it := val.ElementIterator(); it.Next(); {
+			k, v := it.Element()
+			instances = append(instances, ForEachInstance{Group: taskName, Key: k.AsString(), Value: v})
+		}
+	default:
+		return nil, false, fmt.Errorf("task '%s': for_each must be a list of strings or a map, got %s", taskName, ty.FriendlyName())
+	}
+
+	for _, inst := range instances {
+		if inst.Key == "" {
+			return nil, false, fmt.Errorf("task '%s': for_each keys must not be empty", taskName)
+		}
+	}
+	return instances, true, nil
+}
+
+// withEach returns a copy of ctx that also defines `each` for inst.
+func withEach(ctx *hcl.EvalContext, inst *ForEachInstance) *hcl.EvalContext {
+	vars := make(map[string]cty.Value, len(ctx.Variables)+1)
+	for k, v := range ctx.Variables {
+		vars[k] = v
+	}
+	vars["each"] = inst.object()
+	return &hcl.EvalContext{Variables: vars, Functions: ctx.Functions}
+}
+
+// EvalVariables returns the variables the task's deferred expressions
+// (objective, when) are evaluated with: vars, inputs, each for
+// for_each instances, and params for missions built from a template.
+func (t *Task) EvalVariables(vars, inputs map[string]cty.Value) map[string]cty.Value {
+	out := map[string]cty.Value{
+		"vars":   cty.ObjectVal(vars),
+		"inputs": cty.ObjectVal(inputs),
+	}
+	if t.ForEach != nil {
+		out["each"] = t.ForEach.object()
+	}
+	if t.Params != cty.NilVal {
+		out["params"] = t.Params
+	}
+	return out
+}
+
+// taskRefNames flattens a depends_on / send_to value into task names.
+// Elements are task names, or whole for_each groups (tasks.<name> of a
+// for_each task), which stand for every instance.
+func taskRefNames(val cty.Value) ([]string, error) {
+	var names []string
+	for it := val.ElementIterator(); it.Next(); {
+		_, v := it.Element()
+		switch {
+		case v.IsNull() || !v.IsKnown():
+			return nil, fmt.Errorf("task references must be known task names")
+		case v.Type() == cty.String:
+			names = append(names, v.AsString())
+		case v.Type().IsObjectType() || v.Type().IsMapType():
+			for gi := v.ElementIterator(); gi.Next(); {
+				_, name := gi.Element()
+				names = append(names, name.AsString())
+			}
+		default:
+			return nil, fmt.Errorf("task references must be tasks.<name> values, got %s", v.Type().FriendlyName())
+		}
+	}
+	return names, nil
+}
@@ -0,0 +1,140 @@
+package config_test
+
+import (
+	"squadron/config"
+
+	"github.com/zclconf/go-cty/cty"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Task for_each", func() {
+	load := func(body string) (*config.Config, error) {
+		hcl := fullBaseHCL() + `
+variable "repo_root" {
+  default = "src"
+}
+
+mission "fanout" {
+  commander {
+    model = models.anthropic.claude_sonnet_4
+  }
+  agents = [agents.test_agent]
+  input "depth" {
+    type    = "string"
+    default = "shallow"
+  }
+` + body + `
+}
+`
+		_, f := writeFixture("config.hcl", hcl)
+		return config.LoadFile(f)
+	}
+
+	It("expands a list into one task per element", func() {
+		cfg, err := load(`
+  task "audit" {
+    for_each  = ["us", "eu"]
+    objective = "Audit ${each.key} at ${inputs.depth} depth"
+  }
+`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Validate()).To(Succeed())
+
+		tasks := cfg.Missions[0].Tasks
+		Expect(tasks).To(HaveLen(2))
+		Expect(tasks[0].Name).To(Equal(`audit["us"]`))
+		Expect(tasks[1].Name).To(Equal(`audit["eu"]`))
+		Expect(tasks[0].ForEach.Group).To(Equal("audit"))
+		Expect(tasks[0].ForEach.Key).To(Equal("us"))
+
+		inputs := map[string]cty.Value{"depth": cty.StringVal("deep")}
+		Expect(tasks[1].ResolvedObjective(nil, inputs)).To(Equal("Audit eu at deep depth"))
+	})
+
+	It("exposes map values as each.value", func() {
+		cfg, err := load(`
+  task "review" {
+    for_each = {
+      api = "${vars.repo_root}/services/api"
+      web = "${vars.repo_root}/apps/web"
+    }
+    objective = "Review the code in ${each.value}"
+  }
+`)
+		Expect(err).NotTo(HaveOccurred())
+		tasks := cfg.Missions[0].Tasks
+		Expect(tasks).To(HaveLen(2))
+		Expect(tasks[0].Name).To(Equal(`review["api"]`))
+		Expect(tasks[0].ResolvedObjective(nil, nil)).To(Equal("Review the code in src/services/api"))
+		Expect(tasks[1].ResolvedObjective(nil, nil)).To(Equal("Review the code in src/apps/web"))
+	})
+
+	It("lets downstream tasks depend on one instance or the whole expansion", func() {
+		cfg, err := load(`
+  task "prepare" { objective = "Prepare" }
+  task "audit" {
+    for_each   = ["us", "eu"]
+    objective  = "Audit ${each.key}"
+    depends_on = [tasks.prepare]
+  }
+  task "eu_followup" {
+    objective  = "Follow up on EU"
+    depends_on = [tasks.audit["eu"]]
+  }
+  task "summarize" {
+    objective  = "Summarize"
+    depends_on = [tasks.audit]
+    when       = query.audit["us"].status == "completed"
+  }
+`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Validate()).To(Succeed())
+
+		m := cfg.Missions[0]
+		Expect(m.GetTaskByName(`audit["us"]`).DependsOn).To(ConsistOf("prepare"))
+		Expect(m.GetTaskByName("eu_followup").DependsOn).To(ConsistOf(`audit["eu"]`))
+
+		summarize := m.GetTaskByName("summarize")
+		Expect(summarize.DependsOn).To(ConsistOf(`audit["us"]`, `audit["eu"]`))
+		Expect(summarize.WhenDependencies()).To(Equal([]string{`audit["us"]`}))
+		run, err := summarize.EvaluateWhen(nil, nil, map[string]cty.Value{
+			`audit["us"]`: cty.ObjectVal(map[string]cty.Value{
+				"status":  cty.StringVal("completed"),
+				"skipped": cty.False,
+				"output":  cty.NullVal(cty.DynamicPseudoType),
+			}),
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(run).To(BeTrue())
+	})
+
+	It("rejects a for_each that reads inputs", func() {
+		_, err := load(`
+  task "audit" {
+    for_each  = [inputs.depth]
+    objective = "Audit"
+  }
+`)
+		Expect(err).To(MatchError(ContainSubstring("for_each must be known when the config loads")))
+	})
+
+	It("rejects duplicate keys and non-string list elements", func() {
+		_, err := load(`
+  task "audit" {
+    for_each  = ["us", "us"]
+    objective = "Audit"
+  }
+`)
+		Expect(err).To(MatchError(ContainSubstring(`duplicate key "us"`)))
+
+		_, err = load(`
+  task "audit" {
+    for_each  = [1, 2]
+    objective = "Audit"
+  }
+`)
+		Expect(err).To(MatchError(ContainSubstring("use a map")))
+	})
+})
@@ -34,6 +34,11 @@ import (
 //	  lon = number("Longitude", true)
 //	}, "Geographic coordinates", true)
 //
+// Files — file(description, required?) — task outputs only; the value is an
+// artifact reference ("artifact:...") registered with artifact_put:
+//
+//	report  = file("Rendered PDF report", true)
+//
 // For free-form key-value data, use map() with any or any_primitive:
 //
 //	metadata = map(any, "Arbitrary data")
@@ -48,6 +53,7 @@ func SchemaFunctions() map[string]function.Function {
 		"number":  makePrimitiveFunc("number"),
 		"integer": makePrimitiveFunc("integer"),
 		"bool":    makePrimitiveFunc("bool"),
+		"file":    makePrimitiveFunc("file"),
 		"list":    makeListFunc(),
 		"map":     makeMapFunc(),
 		"object":  makeObjectFunc(),
@@ -77,7 +83,7 @@ func typeRef(kind string) cty.Value {
 	})
 }
 
-// makePrimitiveFunc creates a schema helper function for a primitive type (string/number/integer/bool).
+// makePrimitiveFunc creates a schema helper function for a primitive type (string/number/integer/bool/file).
 //
 // Signature: kind(description, [bool_required | options_object]?)
 //
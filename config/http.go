@@ -0,0 +1,81 @@
+package config
+
+import (
+	"fmt"
+
+	"squadron/aitools"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// HTTPConfig describes the top-level `http { ... }` block, which configures
+// the builtins.http tools. Each auth block attaches headers to requests
+// for its hosts, so agents call authenticated APIs without ever seeing the
+// credentials. Header values may reference secrets from the secrets block
+// as ${secrets.<name>}; they're filled in when the request is sent. At
+// most one per config.
+//
+//	http {
+//	  auth "github" {
+//	    hosts   = ["api.github.com"]
+//	    headers = { Authorization = "Bearer ${secrets.github_token}" }
+//	  }
+//	}
+type HTTPConfig struct {
+	Auth []HTTPAuth `hcl:"auth,block"`
+}
+
+// HTTPAuth is one `auth "name" { ... }` block inside http.
+type HTTPAuth struct {
+	Name    string            `hcl:"name,label"`
+	Hosts   []string          `hcl:"hosts"`
+	Headers map[string]string `hcl:"headers"`
+}
+
+// Validate checks that every auth block names hosts and headers.
+func (h *HTTPConfig) Validate() error {
+	seen := make(map[string]bool)
+	for _, a := range h.Auth {
+		if seen[a.Name] {
+			return fmt.Errorf("auth '%s' declared more than once", a.Name)
+		}
+		seen[a.Name] = true
+		if len(a.Hosts) == 0 {
+			return fmt.Errorf("auth '%s': hosts must list at least one host", a.Name)
+		}
+		if len(a.Headers) == 0 {
+			return fmt.Errorf("auth '%s': headers must set at least one header", a.Name)
+		}
+	}
+	return nil
+}
+
+// Authenticator returns the runtime auth rules with secrets for their
+// header templates, or nil when no http block is configured.
+func (h *HTTPConfig) Authenticator(secrets map[string]string) *aitools.HTTPAuthenticator {
+	if h == nil || len(h.Auth) == 0 {
+		return nil
+	}
+	a := &aitools.HTTPAuthenticator{Secrets: secrets}
+	for _, r := range h.Auth {
+		a.Rules = append(a.Rules, aitools.HTTPAuth{Name: r.Name, Hosts: r.Hosts, Headers: r.Headers})
+	}
+	return a
+}
+
+// secretPlaceholderContext extends ctx with a `secrets` object whose
+// attributes evaluate to their own ${secrets.<name>} placeholder, so
+// "Bearer ${secrets.token}" survives HCL evaluation and is resolved at
+// call time instead.
+func secretPlaceholderContext(ctx *hcl.EvalContext, secrets *SecretsConfig) *hcl.EvalContext {
+	placeholders := make(map[string]cty.Value)
+	if secrets != nil {
+		for _, s := range secrets.Secrets {
+			placeholders[s.Name] = cty.StringVal("${secrets." + s.Name + "}")
+		}
+	}
+	child := ctx.NewChild()
+	child.Variables = map[string]cty.Value{"secrets": cty.ObjectVal(placeholders)}
+	return child
+}
@@ -0,0 +1,60 @@
+package config_test
+
+import (
+	"squadron/config"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("HTTP Config", func() {
+
+	It("keeps secret references as placeholders in auth headers", func() {
+		_, f := writeFixture("http.hcl", `
+secrets {
+  secret "github_token" { env = "GITHUB_TOKEN" }
+}
+
+http {
+  auth "github" {
+    hosts   = ["api.github.com"]
+    headers = { Authorization = "Bearer ${secrets.github_token}" }
+  }
+}
+`)
+		cfg, err := config.LoadFile(f)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.HTTP.Auth).To(HaveLen(1))
+		Expect(cfg.HTTP.Auth[0].Headers).To(HaveKeyWithValue("Authorization", "Bearer ${secrets.github_token}"))
+
+		auth := cfg.HTTP.Authenticator(map[string]string{"github_token": "t"})
+		Expect(auth.Rules).To(HaveLen(1))
+		Expect(auth.Secrets).To(HaveKeyWithValue("github_token", "t"))
+	})
+
+	It("rejects references to undeclared secrets", func() {
+		_, f := writeFixture("http.hcl", `
+http {
+  auth "github" {
+    hosts   = ["api.github.com"]
+    headers = { Authorization = "Bearer ${secrets.nope}" }
+  }
+}
+`)
+		_, err := config.LoadFile(f)
+		Expect(err).To(MatchError(ContainSubstring("http")))
+	})
+
+	It("requires hosts on every auth block", func() {
+		_, f := writeFixture("http.hcl", `
+http {
+  auth "github" {
+    hosts   = []
+    headers = { Authorization = "x" }
+  }
+}
+`)
+		_, err := config.LoadFile(f)
+		Expect(err).To(MatchError(ContainSubstring("hosts")))
+	})
+})
@@ -0,0 +1,71 @@
+package config
+
+import "fmt"
+
+// Knowledge backends.
+const (
+	// KnowledgeBackendMemory keeps entries in process for the run; they are
+	// lost if the mission is resumed.
+	KnowledgeBackendMemory = "memory"
+	// KnowledgeBackendStore persists entries in the storage backend (sqlite
+	// or postgres) so a resumed mission can still search them.
+	KnowledgeBackendStore = "store"
+)
+
+// DefaultKnowledgeSearchLimit is how many entries memory_search returns when
+// neither the block nor the call sets a limit.
+const DefaultKnowledgeSearchLimit = 5
+
+// MissionKnowledge describes the `knowledge { ... }` block inside a mission —
+// a semantic memory the mission's commanders and agents write to with
+// memory_put and query with memory_search. At most one per mission.
+//
+//	knowledge {
+//	  embedding_model = models.openai.text_embedding_3_small
+//	  backend         = "store"
+//	}
+//
+// Without an embedding_model, entries are embedded locally with a hashed
+// bag-of-words vector: no API calls, but matches are lexical rather than
+// semantic.
+type MissionKnowledge struct {
+	EmbeddingModel string `hcl:"embedding_model,optional" json:"embeddingModel,omitempty"`
+	Backend        string `hcl:"backend,optional" json:"backend,omitempty"`
+	SearchLimit    int    `hcl:"search_limit,optional" json:"searchLimit,omitempty"`
+}
+
+// Validate checks the backend and that embedding_model names an embedding
+// model some model config provides.
+func (k *MissionKnowledge) Validate(models []Model) error {
+	switch k.Backend {
+	case "", KnowledgeBackendMemory, KnowledgeBackendStore:
+	default:
+		return fmt.Errorf("backend must be %q or %q, got %q", KnowledgeBackendMemory, KnowledgeBackendStore, k.Backend)
+	}
+	if k.SearchLimit < 0 {
+		return fmt.Errorf("search_limit must be >= 0")
+	}
+	if k.EmbeddingModel != "" {
+		if _, _, err := ResolveEmbeddingModel(k.EmbeddingModel, models); err != nil {
+			return fmt.Errorf("embedding_model: %w", err)
+		}
+	}
+	return nil
+}
+
+// GetBackend returns the configured backend, defaulting to memory.
+func (k *MissionKnowledge) GetBackend() string {
+	if k.Backend == "" {
+		return KnowledgeBackendMemory
+	}
+	return k.Backend
+}
+
+// GetSearchLimit returns the configured search limit, falling back to the
+// default.
+func (k *MissionKnowledge) GetSearchLimit() int {
+	if k.SearchLimit <= 0 {
+		return DefaultKnowledgeSearchLimit
+	}
+	return k.SearchLimit
+}
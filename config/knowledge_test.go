@@ -0,0 +1,83 @@
+package config_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"squadron/config"
+)
+
+var _ = Describe("Mission knowledge block", func() {
+	load := func(extraModels, block string) (*config.Config, error) {
+		hcl := fullBaseHCL() + extraModels + `
+mission "m" {
+  commander { model = models.anthropic.claude_sonnet_4 }
+  agents    = [agents.test_agent]
+` + block + `
+  task "t" { objective = "go" }
+}
+`
+		_, f := writeFixture("config.hcl", hcl)
+		return config.LoadAndValidate(f)
+	}
+
+	openaiModel := `
+model "openai" {
+  provider = "openai"
+  api_key  = vars.test_api_key
+}
+`
+
+	It("defaults to the memory backend and lexical embeddings", func() {
+		cfg, err := load("", `knowledge {}`)
+		Expect(err).NotTo(HaveOccurred())
+		k := cfg.Missions[0].Knowledge
+		Expect(k).NotTo(BeNil())
+		Expect(k.EmbeddingModel).To(BeEmpty())
+		Expect(k.GetBackend()).To(Equal(config.KnowledgeBackendMemory))
+		Expect(k.GetSearchLimit()).To(Equal(config.DefaultKnowledgeSearchLimit))
+	})
+
+	It("resolves embedding model references", func() {
+		cfg, err := load(openaiModel, `
+  knowledge {
+    embedding_model = models.openai.text_embedding_3_small
+    backend         = "store"
+    search_limit    = 8
+  }`)
+		Expect(err).NotTo(HaveOccurred())
+		k := cfg.Missions[0].Knowledge
+		Expect(k.EmbeddingModel).To(Equal("text_embedding_3_small"))
+		Expect(k.GetBackend()).To(Equal(config.KnowledgeBackendStore))
+		Expect(k.GetSearchLimit()).To(Equal(8))
+
+		m, apiName, err := config.ResolveEmbeddingModel(k.EmbeddingModel, cfg.Models)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(m.Name).To(Equal("openai"))
+		Expect(apiName).To(Equal("text-embedding-3-small"))
+	})
+
+	It("rejects chat models as embedding models and embedding models as commanders", func() {
+		_, err := load("", `knowledge { embedding_model = models.anthropic.claude_sonnet_4 }`)
+		Expect(err).To(MatchError(ContainSubstring("no model config provides embedding model 'claude_sonnet_4'")))
+
+		hcl := fullBaseHCL() + openaiModel + `
+mission "m" {
+  commander { model = models.openai.text_embedding_3_small }
+  agents    = [agents.test_agent]
+  task "t" { objective = "go" }
+}
+`
+		_, f := writeFixture("config.hcl", hcl)
+		_, err = config.LoadAndValidate(f)
+		Expect(err).To(MatchError(ContainSubstring("commander 'text_embedding_3_small' not found in models")))
+	})
+
+	It("rejects an unknown backend and duplicate blocks", func() {
+		_, err := load("", `knowledge { backend = "pgvector" }`)
+		Expect(err).To(MatchError(ContainSubstring(`backend must be "memory" or "store"`)))
+
+		_, err = load("", "knowledge {}\n  knowledge {}")
+		Expect(err).To(MatchError(ContainSubstring("only one knowledge block allowed")))
+	})
+})
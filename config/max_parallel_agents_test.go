@@ -0,0 +1,46 @@
+package config_test
+
+import (
+	"squadron/config"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("commander max_parallel_agents", func() {
+	missionHCL := func(commander string) string {
+		return fullBaseHCL() + `
+mission "test_mission" {
+  commander {
+    model = models.anthropic.claude_sonnet_4
+` + commander + `
+  }
+  agents = [agents.test_agent]
+  task "only_task" {
+    objective = "Do something"
+  }
+}
+`
+	}
+
+	It("parses on the commander block", func() {
+		_, f := writeFixture("config.hcl", missionHCL(`    max_parallel_agents = 8`))
+		cfg, err := config.LoadFile(f)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Missions[0].Commander.MaxParallelAgents).To(Equal(8))
+		Expect(cfg.Missions[0].Commander.GetMaxParallelAgents()).To(Equal(8))
+	})
+
+	It("defaults when unset", func() {
+		_, f := writeFixture("config.hcl", missionHCL(""))
+		cfg, err := config.LoadFile(f)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Missions[0].Commander.GetMaxParallelAgents()).To(Equal(config.DefaultMaxParallelAgents))
+	})
+
+	It("rejects a limit below 1", func() {
+		_, f := writeFixture("config.hcl", missionHCL(`    max_parallel_agents = 0`))
+		_, err := config.LoadFile(f)
+		Expect(err).To(MatchError(ContainSubstring("max_parallel_agents must be at least 1")))
+	})
+})
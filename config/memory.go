@@ -11,6 +11,7 @@ import (
 const (
 	MemorySlotName     = "memory"
 	ScratchpadSlotName = "scratchpad"
+	WorkspaceSlotName  = "workspace"
 )
 
 // ScratchpadCleanupDays is the auto-delete window applied to every
@@ -35,7 +36,7 @@ type Memory struct {
 
 // Validate enforces naming rules + the required description.
 func (m *Memory) Validate() error {
-	if m.Name == MemorySlotName || m.Name == ScratchpadSlotName {
+	if isReservedSlotName(m.Name) {
 		return fmt.Errorf("name %q is reserved for mission-scoped slots", m.Name)
 	}
 	if err := validateSlotName(m.Name); err != nil {
@@ -47,6 +48,11 @@ func (m *Memory) Validate() error {
 	return nil
 }
 
+// isReservedSlotName reports whether name is one of the mission-scoped slots.
+func isReservedSlotName(name string) bool {
+	return name == MemorySlotName || name == ScratchpadSlotName || name == WorkspaceSlotName
+}
+
 // validateSlotName rejects HCL labels that would break filesystem layout:
 // path separators, parent-dir traversal, or leading dot. Used by both shared
 // memory labels and mission names (since both become directory names under
@@ -85,3 +91,37 @@ func (mm *MissionMemory) Validate() error {
 	}
 	return nil
 }
+
+// Workspace on_complete modes.
+const (
+	WorkspaceOnCompleteDelete  = "delete"
+	WorkspaceOnCompleteArchive = "archive"
+	WorkspaceOnCompleteKeep    = "keep"
+)
+
+// MissionWorkspace describes the `workspace { ... }` block inside a mission.
+// Every task — and every iteration of an iterated task — gets its own
+// private directory, exposed to its commander and agents as the
+// "workspace" slot. OnComplete decides what happens to it once the task
+// (or iteration) succeeds; failed tasks keep theirs so a resume picks up
+// where it left off.
+type MissionWorkspace struct {
+	OnComplete string `hcl:"on_complete,optional" json:"onComplete,omitempty"`
+}
+
+// GetOnComplete returns the on_complete mode, defaulting to delete.
+func (w *MissionWorkspace) GetOnComplete() string {
+	if w.OnComplete == "" {
+		return WorkspaceOnCompleteDelete
+	}
+	return w.OnComplete
+}
+
+// Validate checks the on_complete mode.
+func (w *MissionWorkspace) Validate() error {
+	switch w.GetOnComplete() {
+	case WorkspaceOnCompleteDelete, WorkspaceOnCompleteArchive, WorkspaceOnCompleteKeep:
+		return nil
+	}
+	return fmt.Errorf("workspace on_complete must be \"delete\", \"archive\", or \"keep\", got %q", w.OnComplete)
+}
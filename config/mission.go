@@ -7,11 +7,14 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"text/template"
 	"time"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/robfig/cron/v3"
 	"github.com/zclconf/go-cty/cty"
+
+	"squadron/aitools"
 )
 
 // Input type constants
@@ -27,14 +30,16 @@ const (
 
 // MissionInput represents an input parameter for a mission
 type MissionInput struct {
-	Name        string          `json:"name"`
-	Type        string          `json:"type"`
-	Description string          `json:"description,omitempty"`
-	Default     *cty.Value      `json:"-"`
-	Protected   bool            `json:"protected,omitempty"`
-	Value       *cty.Value      `json:"-"`
-	Items       *MissionInput   `json:"items,omitempty"`       // Element type for list/map
-	Properties  []MissionInput  `json:"properties,omitempty"`  // Nested fields for object
+	Name        string            `json:"name"`
+	Type        string            `json:"type"`
+	Description string            `json:"description,omitempty"`
+	Default     *cty.Value        `json:"-"`
+	Protected   bool              `json:"protected,omitempty"`
+	Value       *cty.Value        `json:"-"`
+	Items       *MissionInput     `json:"items,omitempty"`       // Element type for list/map
+	Properties  []MissionInput    `json:"properties,omitempty"`  // Nested fields for object
+	Required    bool              `json:"required,omitempty"`    // Object properties only: must be set
+	Validations []InputValidation `json:"validations,omitempty"` // validation blocks, checked on resolve
 }
 
 // Dataset represents a collection of items for task iteration
@@ -43,8 +48,17 @@ type Dataset struct {
 	Description string         `json:"description,omitempty"`
 	BindTo      string         `json:"bindTo,omitempty"`
 	Schema      *InputsSchema  `json:"schema,omitempty"`
+	Source      *DatasetSource `json:"source,omitempty"`
+	Export      *DatasetExport `json:"export,omitempty"`
 	Items       []cty.Value    `json:"-"`
 	BindToExpr  hcl.Expression `json:"-"`
+	// FromTask and FromPath are set on the dataset of an iterator that maps
+	// over a dependency's output (tasks.<task>.output.<field>). The runner
+	// fills it from that output before the iterating task starts.
+	FromTask string   `json:"fromTask,omitempty"`
+	FromPath []string `json:"fromPath,omitempty"`
+	// Transforms run in order over items as they are loaded or appended
+	Transforms []DatasetTransform `json:"transforms,omitempty"`
 }
 
 // TaskIterator configures iteration over a dataset
@@ -55,6 +69,38 @@ type TaskIterator struct {
 	ConcurrencyLimit int    `json:"concurrencyLimit,omitempty"` // Default: 5. Max concurrent iterations when parallel=true.
 	StartDelay       int    `json:"startDelay,omitempty"`       // Default: 0. Milliseconds delay between starts in first concurrent batch.
 	Smoketest        bool   `json:"smoketest,omitempty"`        // Default: false. If true, run first iteration completely before starting others.
+	Timeout          string `json:"timeout,omitempty"`          // Optional Go duration bounding each iteration attempt (parallel only).
+	// ContinueOnFailure lets the task complete on the outputs of the items
+	// that succeeded, as long as no more than MaxFailureRate of the dataset
+	// failed (parallel only). Failed items stay marked failed in the store.
+	ContinueOnFailure bool     `json:"continueOnFailure,omitempty"`
+	MaxFailureRate    *float64 `json:"maxFailureRate,omitempty"` // Default: 1 (any number of failures, but not all)
+	// Models overrides the commander and agent models for the iterations,
+	// over the task's own models block. See ModelOverride.
+	Models *ModelOverride `json:"models,omitempty"`
+	// FromTask and FromPath are set when the iterator maps over a
+	// dependency's output field instead of a declared dataset; Dataset is
+	// then the OutputDatasetName.
+	FromTask string   `json:"fromTask,omitempty"`
+	FromPath []string `json:"fromPath,omitempty"`
+}
+
+// GetTimeout returns the per-iteration timeout, or 0 when none is set.
+func (i *TaskIterator) GetTimeout() time.Duration {
+	if i == nil || i.Timeout == "" {
+		return 0
+	}
+	d, _ := time.ParseDuration(i.Timeout)
+	return d
+}
+
+// GetMaxFailureRate returns the fraction of items that may fail before a
+// continue_on_failure task fails anyway.
+func (i *TaskIterator) GetMaxFailureRate() float64 {
+	if i == nil || i.MaxFailureRate == nil {
+		return 1
+	}
+	return *i.MaxFailureRate
 }
 
 // OutputSchema defines the structured output for a task
@@ -67,7 +113,7 @@ type OutputSchema struct {
 // For object types, Properties holds the nested field definitions.
 type OutputField struct {
 	Name        string        `json:"name"`
-	Type        string        `json:"type"`                  // string, number, integer, boolean, array, object
+	Type        string        `json:"type"`                  // string, number, integer, boolean, array, object, file
 	Description string        `json:"description,omitempty"`
 	Required    bool          `json:"required,omitempty"`
 	Items       *OutputField  `json:"items,omitempty"`
@@ -89,7 +135,7 @@ type Schedule struct {
 	Weekdays []string          `hcl:"weekdays,optional" json:"weekdays,omitempty"` // Day filter: "mon", "tue", etc.
 	Cron     string            `hcl:"cron,optional" json:"cron,omitempty"`         // 5-field cron expression
 	Timezone string            `hcl:"timezone,optional" json:"timezone,omitempty"` // IANA timezone, defaults to system local
-	Inputs   map[string]string `json:"inputs,omitempty"`                           // Input values to pass when firing (parsed manually from HCL)
+	Inputs   map[string]string `json:"inputs,omitempty"`                           // Input values to pass when firing (parsed manually from HCL); may use Go templates such as "{{ .Date }}"
 }
 
 // validWeekdays maps lowercase weekday abbreviations to true.
@@ -163,6 +209,15 @@ func (s *Schedule) Validate() error {
 		}
 	}
 
+	for k, v := range s.Inputs {
+		if !strings.Contains(v, "{{") {
+			continue
+		}
+		if _, err := template.New(k).Parse(v); err != nil {
+			return fmt.Errorf("invalid template in input %q: %w", k, err)
+		}
+	}
+
 	return nil
 }
 
@@ -275,16 +330,50 @@ type CommanderPruning struct {
 	PruneTo int `hcl:"prune_to"`
 }
 
+// CommanderCheckpoint configures mid-task checkpoints for a commander
+type CommanderCheckpoint struct {
+	// Interval: save a checkpoint every this many turns
+	Interval int `hcl:"interval"`
+}
+
 // MissionCommander holds configuration for the mission's commander LLM
 type MissionCommander struct {
-	Model        string              `json:"model"`
-	Compaction   *Compaction         `json:"compaction,omitempty"`
-	Pruning      *CommanderPruning   `json:"pruning,omitempty"`
-	ToolResponse *ToolResponseConfig `json:"toolResponse,omitempty"`
+	Model        string               `json:"model"`
+	Compaction   *Compaction          `json:"compaction,omitempty"`
+	Pruning      *CommanderPruning    `json:"pruning,omitempty"`
+	ToolResponse *ToolResponseConfig  `json:"toolResponse,omitempty"`
+	Checkpoint   *CommanderCheckpoint `json:"checkpoint,omitempty"`
 	// Reasoning controls native provider reasoning for the commander.
 	// Valid values: "", "low", "medium", "high". Silently no-op on models
 	// that don't support native reasoning.
 	Reasoning string `json:"reasoning,omitempty"`
+	// PromptTemplate replaces the built-in commander prompt. It is a Go
+	// text/template; {{.Default}} is the built-in prompt.
+	PromptTemplate string `json:"promptTemplate,omitempty"`
+	// MaxParallelAgents caps how many agents one call_agents_parallel call
+	// runs at once (0 = DefaultMaxParallelAgents).
+	MaxParallelAgents int `json:"maxParallelAgents,omitempty"`
+}
+
+// DefaultMaxParallelAgents is the call_agents_parallel fan-out limit when
+// the commander block doesn't set max_parallel_agents.
+const DefaultMaxParallelAgents = 4
+
+// GetMaxParallelAgents returns the configured call_agents_parallel fan-out
+// limit, falling back to the default.
+func (c *MissionCommander) GetMaxParallelAgents() int {
+	if c == nil || c.MaxParallelAgents <= 0 {
+		return DefaultMaxParallelAgents
+	}
+	return c.MaxParallelAgents
+}
+
+// GetToolResponse returns the commander's tool_response block, or nil.
+func (c *MissionCommander) GetToolResponse() *ToolResponseConfig {
+	if c == nil {
+		return nil
+	}
+	return c.ToolResponse
 }
 
 // GetToolResponseMaxBytes returns the configured max size in bytes for tool responses, falling back to default.
@@ -313,10 +402,19 @@ type Mission struct {
 	Packets   []string       // Packet names referenced by this mission (read-only reference data bundles)
 	Memory     *MissionMemory // Optional persistent mission memory (slot "memory")
 	Scratchpad bool           // If true, mission gets an ephemeral per-run scratchpad (slot "scratchpad")
+	Workspace  *MissionWorkspace `json:"workspace,omitempty"` // Optional private directory per task/iteration (slot "workspace")
+	Knowledge  *MissionKnowledge `json:"knowledge,omitempty"` // Optional semantic memory (memory_put / memory_search)
 	Schedules   []Schedule        `json:"schedules,omitempty"`
 	Trigger     *Trigger          `json:"trigger,omitempty"`
 	MaxParallel int               `json:"maxParallel,omitempty"` // default 3
+	// MaxParallelTasks caps how many tasks of one run execute at once (0 = no limit)
+	MaxParallelTasks int `json:"maxParallelTasks,omitempty"`
+	// MaxParallelLLMCalls caps concurrent LLM calls across all commanders and agents of one run (0 = no limit)
+	MaxParallelLLMCalls int `json:"maxParallelLLMCalls,omitempty"`
 	Budget      *Budget           `json:"budget,omitempty"`
+	Notifications *Notifications  `json:"notifications,omitempty"`
+	Policies      *Policies       `json:"policies,omitempty"` // tool-call rules for the mission's agents
+	ResponseCache *ResponseCache  `json:"responseCache,omitempty"` // reuse LLM responses to identical requests
 }
 
 // GetLocalAgent returns a mission-scoped agent by name, or nil if not found.
@@ -335,13 +433,84 @@ type Task struct {
 	ObjectiveExpr hcl.Expression `json:"-"`
 	RawObjective  string         `json:"rawObjective,omitempty"` // Raw objective text from HCL source (with ${...} placeholders intact)
 	Agents        []string       `hcl:"agents,optional" json:"agents,omitempty"`
+	// Requires picks agents by capability instead of by name: the task gets
+	// every agent declaring all of these tags. See Mission.TaskAgents.
+	Requires      []string       `json:"requires,omitempty"`
 	Packets      []string       `json:"packets,omitempty"` // task-scoped declared packet references (parsed manually)
 	DependsOn     []string       `hcl:"depends_on,optional" json:"dependsOn,omitempty"`
 	Iterator      *TaskIterator  `json:"iterator,omitempty"`
+	Aggregate     *TaskAggregate `json:"aggregate,omitempty"` // Folds iteration outputs into the task output; iterated tasks only
 	Output        *OutputSchema  `json:"output,omitempty"`
 	Router        *TaskRouter    `json:"router,omitempty"`
 	SendTo        []string       `json:"sendTo,omitempty"`
 	Budget        *Budget        `json:"budget,omitempty"`
+	Timeout       string         `json:"timeout,omitempty"` // Optional Go duration bounding the whole task
+	// MaxTurns caps the LLM turns of the task's commander (of each
+	// iteration's commander on iterated tasks). 0 means no limit.
+	MaxTurns int `json:"maxTurns,omitempty"`
+	// Priority orders tasks that are ready at the same time: higher
+	// priorities launch first, which matters when max_parallel_tasks holds
+	// some back. Default 0; may be negative.
+	Priority int `json:"priority,omitempty"`
+	// RequireApproval holds the commander's final summary and output until a
+	// human approves (or edits) them. Not supported on iterated tasks.
+	RequireApproval bool `json:"requireApproval,omitempty"`
+	// Verify has the commander check its work against the objective before
+	// the task completes, fixing what it finds and reporting a confidence
+	// score that is stored with the task. Not supported on iterated tasks.
+	Verify bool `json:"verify,omitempty"`
+	// ToolsAllow and ToolsDeny filter the configured tools agents get while
+	// working on this task. See ToolFilter.
+	ToolsAllow []string `json:"toolsAllow,omitempty"`
+	ToolsDeny  []string `json:"toolsDeny,omitempty"`
+	// Models overrides the commander and agent models for this task. See
+	// ModelOverride.
+	Models *ModelOverride `json:"models,omitempty"`
+	// Sampling tunes the LLM calls of the task's commander and agents,
+	// over the sampling settings of their models. See Sampling.
+	Sampling *Sampling `json:"sampling,omitempty"`
+	// WhenExpr guards the task: when it evaluates to false the runner skips
+	// the task and records it as "skipped". See EvaluateWhen.
+	WhenExpr hcl.Expression `json:"-"`
+	RawWhen  string         `json:"when,omitempty"` // raw guard source, for display
+	// ForEach is set on tasks generated by a task block's for_each; its
+	// objective and when guard can read each.key and each.value.
+	ForEach *ForEachInstance `json:"forEach,omitempty"`
+	// Params holds the template params of a mission built with
+	// from_template, so the objective and when guard can read params.*.
+	Params cty.Value `json:"-"`
+	// Env and WorkingDir are sent with the plugin tool calls agents make
+	// while working on this task, over the agent's own (see
+	// aitools.ToolEnv).
+	Env        map[string]string `json:"-"` // may hold credentials
+	WorkingDir string            `json:"workingDir,omitempty"`
+	// OutputSinks receive the task's output as soon as it is submitted.
+	// They require an output schema.
+	OutputSinks []OutputSink `json:"outputSinks,omitempty"`
+}
+
+// GetToolEnv returns the env and working directory for plugin tool calls
+// made on this task, or nil when it sets neither.
+func (t *Task) GetToolEnv() *aitools.ToolEnv {
+	return toolEnv(t.Env, t.WorkingDir)
+}
+
+// GetToolFilter returns the task's agent tool filter, or nil when the task
+// sets neither tools_allow nor tools_deny.
+func (t *Task) GetToolFilter() *ToolFilter {
+	if len(t.ToolsAllow) == 0 && len(t.ToolsDeny) == 0 {
+		return nil
+	}
+	return &ToolFilter{Allow: t.ToolsAllow, Deny: t.ToolsDeny}
+}
+
+// GetTimeout returns the task timeout, or 0 when none is set.
+func (t *Task) GetTimeout() time.Duration {
+	if t.Timeout == "" {
+		return 0
+	}
+	d, _ := time.ParseDuration(t.Timeout)
+	return d
 }
 
 // TaskRouter defines conditional routing after task completion
@@ -373,8 +542,8 @@ func (w *Mission) Validate(models []Model, agents []Agent, memories []Memory, pa
 
 	// Validate compaction settings if present
 	if w.Commander.Compaction != nil {
-		if w.Commander.Compaction.TokenLimit <= 0 {
-			return fmt.Errorf("commander compaction token_limit must be > 0")
+		if err := w.Commander.Compaction.Validate(models); err != nil {
+			return fmt.Errorf("commander compaction %w", err)
 		}
 	}
 
@@ -391,18 +560,30 @@ func (w *Mission) Validate(models []Model, agents []Agent, memories []Memory, pa
 		}
 	}
 
+	if w.Commander.Checkpoint != nil && w.Commander.Checkpoint.Interval <= 0 {
+		return fmt.Errorf("commander checkpoint interval must be > 0")
+	}
+
 	// Validate commander reasoning level
 	if normalized, err := NormalizeReasoning(w.Commander.Reasoning); err != nil {
 		return fmt.Errorf("commander: %w", err)
 	} else {
 		w.Commander.Reasoning = normalized
 	}
+	if err := validatePromptTemplate(w.Commander.PromptTemplate); err != nil {
+		return fmt.Errorf("commander: %w", err)
+	}
 
 	// Validate mission-scoped (local) agents
 	for i := range w.LocalAgents {
 		if err := w.LocalAgents[i].Validate(); err != nil {
 			return err
 		}
+		if c := w.LocalAgents[i].Compaction; c != nil {
+			if err := c.Validate(models); err != nil {
+				return fmt.Errorf("agent '%s' compaction: %w", w.LocalAgents[i].Name, err)
+			}
+		}
 	}
 
 	if len(w.Tasks) == 0 {
@@ -476,6 +657,10 @@ func (w *Mission) Validate(models []Model, agents []Agent, memories []Memory, pa
 		}
 	}
 
+	if err := w.Policies.Validate(agentNames); err != nil {
+		return err
+	}
+
 	// Validate shared memory references
 	memoryNames := make(map[string]bool)
 	for _, m := range memories {
@@ -512,11 +697,46 @@ func (w *Mission) Validate(models []Model, agents []Agent, memories []Memory, pa
 		}
 	}
 
+	if w.Workspace != nil {
+		if err := w.Workspace.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if w.Knowledge != nil {
+		if err := w.Knowledge.Validate(models); err != nil {
+			return fmt.Errorf("knowledge: %w", err)
+		}
+	}
+
+	if w.ResponseCache != nil {
+		if err := w.ResponseCache.Validate(); err != nil {
+			return fmt.Errorf("response_cache: %w", err)
+		}
+	}
+
 	// Validate each task
 	for _, t := range w.Tasks {
 		if err := t.Validate(taskNames, agentNames, datasetNames, w.Agents, allMissionNames); err != nil {
 			return fmt.Errorf("task '%s': %w", t.Name, err)
 		}
+		if t.Aggregate != nil {
+			if err := t.Aggregate.Validate(models); err != nil {
+				return fmt.Errorf("task '%s': aggregate: %w", t.Name, err)
+			}
+		}
+		taskAgents, _ := w.TaskAgents(&t, agents)
+		if len(t.Requires) > 0 && len(taskAgents) == 0 {
+			return fmt.Errorf("task '%s': requires [%s]: no agent declares all of these capabilities", t.Name, strings.Join(t.Requires, ", "))
+		}
+		if err := t.Models.Validate(models, taskAgents); err != nil {
+			return fmt.Errorf("task '%s': %w", t.Name, err)
+		}
+		if t.Iterator != nil {
+			if err := t.Iterator.Models.Validate(models, taskAgents); err != nil {
+				return fmt.Errorf("task '%s': iterator %w", t.Name, err)
+			}
+		}
 	}
 
 	// Validate router constraints at mission level
@@ -563,6 +783,14 @@ func (w *Mission) Validate(models []Model, agents []Agent, memories []Memory, pa
 		return err
 	}
 
+	if err := w.validateWhenRefs(); err != nil {
+		return err
+	}
+
+	if err := w.validateOutputIterators(); err != nil {
+		return err
+	}
+
 	// Validate schedules
 	for i, sched := range w.Schedules {
 		if err := sched.Validate(); err != nil {
@@ -581,12 +809,23 @@ func (w *Mission) Validate(models []Model, agents []Agent, memories []Memory, pa
 	if w.MaxParallel < 0 {
 		return fmt.Errorf("max_parallel must be >= 1")
 	}
+	if w.MaxParallelTasks < 0 {
+		return fmt.Errorf("max_parallel_tasks must be >= 1")
+	}
+	if w.MaxParallelLLMCalls < 0 {
+		return fmt.Errorf("max_parallel_llm_calls must be >= 1")
+	}
 
 	// Validate budget
 	if err := w.Budget.Validate(); err != nil {
 		return err
 	}
 
+	// Validate notifications
+	if err := w.Notifications.Validate(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -616,6 +855,19 @@ func (i *MissionInput) Validate() error {
 		}
 	}
 
+	// Defaults must match the declared type and pass the validation blocks
+	if i.Default != nil && !i.Default.IsNull() {
+		val, err := i.Conform(*i.Default)
+		if err != nil {
+			return fmt.Errorf("input %q: default: %w", i.Name, err)
+		}
+		for _, v := range i.Validations {
+			if err := v.check(i.Name, val); err != nil {
+				return fmt.Errorf("input %q: default: %w", i.Name, err)
+			}
+		}
+	}
+
 	// Protected inputs have additional requirements
 	if i.Protected {
 		// Protected inputs must have a value (from vars.* or literal)
@@ -637,6 +889,25 @@ func (d *Dataset) Validate() error {
 		return fmt.Errorf("dataset name is required")
 	}
 	// Datasets can have bind_to, default, or neither (populated dynamically via set_dataset tool)
+	if d.FromTask != "" && (d.Source != nil || d.BindTo != "" || len(d.Items) > 0) {
+		return fmt.Errorf("a dataset filled from task output cannot have a source, items or bind_to")
+	}
+	if d.FromTask != "" && len(d.Transforms) > 0 {
+		return fmt.Errorf("a dataset filled from task output cannot have transforms")
+	}
+	if d.Source != nil {
+		if d.BindTo != "" || len(d.Items) > 0 {
+			return fmt.Errorf("source cannot be combined with items or bind_to")
+		}
+		if err := d.Source.Validate(); err != nil {
+			return err
+		}
+	}
+	if d.Export != nil {
+		if err := d.Export.Validate(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -690,6 +961,9 @@ func (ti *TaskIterator) Validate() error {
 	if ti.Dataset == "" {
 		return fmt.Errorf("iterator dataset is required")
 	}
+	if ti.MaxFailureRate != nil && (*ti.MaxFailureRate < 0 || *ti.MaxFailureRate > 1) {
+		return fmt.Errorf("iterator max_failure_rate must be between 0 and 1, got %g", *ti.MaxFailureRate)
+	}
 	return nil
 }
 
@@ -735,20 +1009,31 @@ func (w *Mission) ResolveInputValues(provided map[string]string) (map[string]cty
 			continue
 		}
 
-		strVal, ok := provided[input.Name]
-		if !ok {
-			// Use default if available
-			if input.Default != nil {
-				result[input.Name] = *input.Default
-				continue
+		var ctyVal cty.Value
+		if strVal, ok := provided[input.Name]; ok {
+			// Convert string to appropriate cty type
+			v, err := parseInputValue(strVal, input.Type)
+			if err != nil {
+				return nil, fmt.Errorf("input '%s': %w", input.Name, err)
 			}
+			ctyVal = v
+		} else if input.Default != nil {
+			ctyVal = *input.Default
+		} else {
 			return nil, fmt.Errorf("required input '%s' not provided", input.Name)
 		}
 
-		// Convert string to appropriate cty type
-		ctyVal, err := parseInputValue(strVal, input.Type)
-		if err != nil {
-			return nil, fmt.Errorf("input '%s': %w", input.Name, err)
+		if !ctyVal.IsNull() {
+			v, err := input.Conform(ctyVal)
+			if err != nil {
+				return nil, fmt.Errorf("input '%s': %w", input.Name, err)
+			}
+			ctyVal = v
+			for _, validation := range input.Validations {
+				if err := validation.check(input.Name, ctyVal); err != nil {
+					return nil, fmt.Errorf("input '%s': %w", input.Name, err)
+				}
+			}
 		}
 		result[input.Name] = ctyVal
 	}
@@ -842,8 +1127,15 @@ func (t *Task) Validate(taskNames map[string]bool, agentNames map[string]bool, d
 		return fmt.Errorf("objective is required")
 	}
 
+	if len(t.Agents) > 0 && len(t.Requires) > 0 {
+		return fmt.Errorf("task cannot have both agents and requires")
+	}
+	if err := validateCapabilityTags("requires", t.Requires); err != nil {
+		return err
+	}
+
 	// Task must have agents either at task level or mission level
-	if len(t.Agents) == 0 && len(missionAgents) == 0 {
+	if len(t.Agents) == 0 && len(t.Requires) == 0 && len(missionAgents) == 0 {
 		return fmt.Errorf("no agents specified (neither at task nor mission level)")
 	}
 
@@ -873,6 +1165,9 @@ func (t *Task) Validate(taskNames map[string]bool, agentNames map[string]bool, d
 			return fmt.Errorf("iterator references unknown dataset '%s'", t.Iterator.Dataset)
 		}
 	}
+	if t.Aggregate != nil && t.Iterator == nil {
+		return fmt.Errorf("aggregate is only supported on iterated tasks")
+	}
 
 	// send_to and router are mutually exclusive
 	if len(t.SendTo) > 0 && t.Router != nil {
@@ -901,6 +1196,22 @@ func (t *Task) Validate(taskNames map[string]bool, agentNames map[string]bool, d
 		return err
 	}
 
+	// Validate output sinks if present
+	if len(t.OutputSinks) > 0 && t.Output == nil {
+		return fmt.Errorf("output_sink requires an output schema")
+	}
+	seenSinks := make(map[string]bool)
+	for i := range t.OutputSinks {
+		sink := &t.OutputSinks[i]
+		if seenSinks[sink.Name] {
+			return fmt.Errorf("duplicate output_sink '%s'", sink.Name)
+		}
+		seenSinks[sink.Name] = true
+		if err := sink.Validate(); err != nil {
+			return fmt.Errorf("output_sink '%s': %w", sink.Name, err)
+		}
+	}
+
 	// Validate router if present
 	if t.Router != nil {
 		if len(t.Router.Routes) == 0 {
@@ -943,10 +1254,7 @@ func (t *Task) Validate(taskNames map[string]bool, agentNames map[string]bool, d
 // ResolvedObjective evaluates the objective expression with the given vars and inputs
 func (t *Task) ResolvedObjective(vars map[string]cty.Value, inputs map[string]cty.Value) (string, error) {
 	ctx := &hcl.EvalContext{
-		Variables: map[string]cty.Value{
-			"vars":   cty.ObjectVal(vars),
-			"inputs": cty.ObjectVal(inputs),
-		},
+		Variables: t.EvalVariables(vars, inputs),
 	}
 	val, diags := t.ObjectiveExpr.Value(ctx)
 	if diags.HasErrors() {
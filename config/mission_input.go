@@ -0,0 +1,215 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/tryfunc"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+	"github.com/zclconf/go-cty/cty/function"
+	"github.com/zclconf/go-cty/cty/function/stdlib"
+)
+
+// InputValidation is a validation block on a mission input. Condition can
+// only read the input itself, as inputs.<name>.
+type InputValidation struct {
+	Condition    hcl.Expression `json:"-"`
+	RawCondition string         `json:"condition"`
+	ErrorMessage string         `json:"errorMessage"`
+}
+
+// validationFunctions are the functions a validation condition can call.
+var validationFunctions = map[string]function.Function{
+	"length":   stdlib.LengthFunc,
+	"contains": stdlib.ContainsFunc,
+	"keys":     stdlib.KeysFunc,
+	"regex":    stdlib.RegexFunc,
+	"try":      tryfunc.TryFunc,
+	"can":      tryfunc.CanFunc,
+}
+
+// parseInputValidationBlock parses a validation block within an input block.
+func parseInputValidationBlock(inputName string, block *hcl.Block, ctx *hcl.EvalContext) (*InputValidation, error) {
+	content, diags := block.Body.Content(&hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{
+			{Name: "condition", Required: true},
+			{Name: "error_message", Required: true},
+		},
+	})
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("validation: %w", diags)
+	}
+
+	cond := content.Attributes["condition"].Expr
+	for _, trav := range cond.Variables() {
+		name, ok := traversalAttr(trav, 1)
+		if trav.RootName() != "inputs" || !ok || name != inputName {
+			return nil, fmt.Errorf("validation: condition can only reference inputs.%s", inputName)
+		}
+	}
+
+	msgVal, diags := content.Attributes["error_message"].Expr.Value(ctx)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("validation: %w", diags)
+	}
+	if msgVal.IsNull() || msgVal.Type() != cty.String || strings.TrimSpace(msgVal.AsString()) == "" {
+		return nil, fmt.Errorf("validation: error_message must be a non-empty string")
+	}
+
+	return &InputValidation{
+		Condition:    cond,
+		RawCondition: extractExpressionSource(cond),
+		ErrorMessage: msgVal.AsString(),
+	}, nil
+}
+
+// check evaluates the condition against val and returns the validation's
+// error message when it doesn't hold.
+func (v *InputValidation) check(inputName string, val cty.Value) error {
+	ctx := &hcl.EvalContext{
+		Variables: map[string]cty.Value{
+			"inputs": cty.ObjectVal(map[string]cty.Value{inputName: val}),
+		},
+		Functions: validationFunctions,
+	}
+	result, diags := v.Condition.Value(ctx)
+	if diags.HasErrors() {
+		return fmt.Errorf("evaluating validation condition: %s", diags.Error())
+	}
+	if result.IsNull() || !result.IsKnown() || result.Type() != cty.Bool {
+		return fmt.Errorf("validation condition must be a bool, got %s", result.Type().FriendlyName())
+	}
+	if result.False() {
+		return fmt.Errorf("%s", v.ErrorMessage)
+	}
+	return nil
+}
+
+// Conform checks val against the input's type, including the element type
+// of lists and maps and the properties of objects, and returns it in
+// canonical form: primitives are converted (e.g. "5" to 5 for a number) and
+// object properties left unset take their default, or null.
+func (i *MissionInput) Conform(val cty.Value) (cty.Value, error) {
+	return conformValue(i, val, "")
+}
+
+func conformValue(schema *MissionInput, val cty.Value, path string) (cty.Value, error) {
+	where := func() string {
+		if path == "" {
+			return ""
+		}
+		return path + ": "
+	}
+	if val.IsNull() {
+		return cty.NilVal, fmt.Errorf("%svalue is required", where())
+	}
+	ty := val.Type()
+
+	switch schema.Type {
+	case "any":
+		return val, nil
+	case "any_primitive":
+		if !ty.IsPrimitiveType() {
+			return cty.NilVal, fmt.Errorf("%sexpected a string, number, or bool, got %s", where(), ty.FriendlyName())
+		}
+		return val, nil
+	case InputTypeString, InputTypeNumber, InputTypeInteger, InputTypeBool:
+		out, err := convert.Convert(val, inputTypeToCtyType(schema.Type))
+		if err != nil {
+			return cty.NilVal, fmt.Errorf("%sexpected %s, got %s", where(), schema.Type, ty.FriendlyName())
+		}
+		if schema.Type == InputTypeInteger && !out.AsBigFloat().IsInt() {
+			return cty.NilVal, fmt.Errorf("%sexpected a whole number", where())
+		}
+		return out, nil
+	case InputTypeList:
+		if !ty.IsListType() && !ty.IsTupleType() && !ty.IsSetType() {
+			return cty.NilVal, fmt.Errorf("%sexpected a list, got %s", where(), ty.FriendlyName())
+		}
+		if schema.Items == nil || val.LengthInt() == 0 {
+			return val, nil
+		}
+		var elems []cty.Value
+		for it := val.ElementIterator(); it.Next(); {
+			k, v := it.Element()
+			elem, err := conformValue(schema.Items, v, fmt.Sprintf("%s[%s]", path, k.AsBigFloat().Text('f', 0)))
+			if err != nil {
+				return cty.NilVal, err
+			}
+			elems = append(elems, elem)
+		}
+		return cty.TupleVal(elems), nil
+	case InputTypeMap:
+		if !ty.IsMapType() && !ty.IsObjectType() {
+			return cty.NilVal, fmt.Errorf("%sexpected a map, got %s", where(), ty.FriendlyName())
+		}
+		if schema.Items == nil || val.LengthInt() == 0 {
+			return val, nil
+		}
+		attrs := make(map[string]cty.Value)
+		for it := val.ElementIterator(); it.Next(); {
+			k, v := it.Element()
+			elem, err := conformValue(schema.Items, v, joinInputPath(path, k.AsString()))
+			if err != nil {
+				return cty.NilVal, err
+			}
+			attrs[k.AsString()] = elem
+		}
+		return cty.ObjectVal(attrs), nil
+	case InputTypeObject:
+		if !ty.IsMapType() && !ty.IsObjectType() {
+			return cty.NilVal, fmt.Errorf("%sexpected an object, got %s", where(), ty.FriendlyName())
+		}
+		if len(schema.Properties) == 0 {
+			return val, nil
+		}
+		given := make(map[string]cty.Value)
+		for it := val.ElementIterator(); it.Next(); {
+			k, v := it.Element()
+			given[k.AsString()] = v
+		}
+		attrs := make(map[string]cty.Value, len(schema.Properties))
+		for idx := range schema.Properties {
+			prop := &schema.Properties[idx]
+			v, ok := given[prop.Name]
+			delete(given, prop.Name)
+			if !ok || v.IsNull() {
+				switch {
+				case prop.Default != nil:
+					v = *prop.Default
+				case prop.Required:
+					return cty.NilVal, fmt.Errorf("%smissing required attribute %q", where(), prop.Name)
+				default:
+					attrs[prop.Name] = cty.NullVal(cty.DynamicPseudoType)
+					continue
+				}
+			}
+			conformed, err := conformValue(prop, v, joinInputPath(path, prop.Name))
+			if err != nil {
+				return cty.NilVal, err
+			}
+			attrs[prop.Name] = conformed
+		}
+		if len(given) > 0 {
+			extra := make([]string, 0, len(given))
+			for k := range given {
+				extra = append(extra, k)
+			}
+			sort.Strings(extra)
+			return cty.NilVal, fmt.Errorf("%sunexpected attribute %q", where(), extra[0])
+		}
+		return cty.ObjectVal(attrs), nil
+	default:
+		return val, nil
+	}
+}
+
+func joinInputPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
@@ -0,0 +1,156 @@
+package config_test
+
+import (
+	"squadron/config"
+
+	"github.com/zclconf/go-cty/cty"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Typed mission inputs", func() {
+	load := func(inputs string) (*config.Mission, error) {
+		hcl := fullBaseHCL() + `
+mission "deploy" {
+  commander {
+    model = models.anthropic.claude_sonnet_4
+  }
+  agents = [agents.test_agent]
+` + inputs + `
+  task "t" { objective = "Deploy" }
+}
+`
+		_, f := writeFixture("config.hcl", hcl)
+		cfg, err := config.LoadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		if err := cfg.Validate(); err != nil {
+			return nil, err
+		}
+		return &cfg.Missions[0], nil
+	}
+
+	const targetInput = `
+  input "target" {
+    type = object({
+      host  = string("Host name", true)
+      port  = integer("Port", { default = 443 })
+      tags  = list(string, "Tags")
+      notes = string("Free-form notes")
+    })
+    description = "Where to deploy"
+
+    validation {
+      condition     = inputs.target.port > 0 && inputs.target.port < 65536
+      error_message = "port must be between 1 and 65535"
+    }
+  }
+`
+
+	It("parses a schema expression as the input type", func() {
+		m, err := load(targetInput)
+		Expect(err).NotTo(HaveOccurred())
+
+		input := m.Inputs[0]
+		Expect(input.Type).To(Equal("object"))
+		Expect(input.Description).To(Equal("Where to deploy"))
+		Expect(input.Properties).To(HaveLen(4))
+		Expect(input.Validations).To(HaveLen(1))
+		Expect(input.Validations[0].RawCondition).To(Equal("inputs.target.port > 0 && inputs.target.port < 65536"))
+	})
+
+	It("parses JSON values, converting primitives and filling defaults", func() {
+		m, err := load(targetInput)
+		Expect(err).NotTo(HaveOccurred())
+
+		vals, err := m.ResolveInputValues(map[string]string{
+			"target": `{"host": "api.example.com", "tags": ["blue", 7]}`,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		target := vals["target"]
+		Expect(target.GetAttr("host")).To(Equal(cty.StringVal("api.example.com")))
+		Expect(target.GetAttr("port").AsBigFloat().String()).To(Equal("443"))
+		Expect(target.GetAttr("tags").Index(cty.NumberIntVal(1))).To(Equal(cty.StringVal("7")))
+		Expect(target.GetAttr("notes").IsNull()).To(BeTrue())
+	})
+
+	It("rejects values that don't match the type", func() {
+		m, err := load(targetInput)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = m.ResolveInputValues(map[string]string{"target": `{"port": 80}`})
+		Expect(err).To(MatchError(ContainSubstring(`missing required attribute "host"`)))
+
+		_, err = m.ResolveInputValues(map[string]string{"target": `{"host": "h", "region": "eu"}`})
+		Expect(err).To(MatchError(ContainSubstring(`unexpected attribute "region"`)))
+
+		_, err = m.ResolveInputValues(map[string]string{"target": `{"host": "h", "port": "https"}`})
+		Expect(err).To(MatchError(ContainSubstring("port: expected integer")))
+	})
+
+	It("reports the error message of a failing validation block", func() {
+		m, err := load(targetInput)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = m.ResolveInputValues(map[string]string{"target": `{"host": "h", "port": 70000}`})
+		Expect(err).To(MatchError("input 'target': port must be between 1 and 65535"))
+	})
+
+	It("checks defaults against the type and validation blocks", func() {
+		_, err := load(`
+  input "regions" {
+    type    = list(string)
+    default = ["us-east-1", "mars-1"]
+
+    validation {
+      condition     = !contains(inputs.regions, "mars-1")
+      error_message = "mars-1 is not a region"
+    }
+  }
+`)
+		Expect(err).To(MatchError(ContainSubstring("default: mars-1 is not a region")))
+
+		_, err = load(`
+  input "limits" {
+    type    = map(number)
+    default = { cpu = "lots" }
+  }
+`)
+		Expect(err).To(MatchError(ContainSubstring("default: cpu: expected number")))
+	})
+
+	It("rejects validation conditions that read anything but the input", func() {
+		_, err := load(`
+  input "env" {
+    type = "string"
+    validation {
+      condition     = inputs.env == vars.default_env
+      error_message = "wrong env"
+    }
+  }
+`)
+		Expect(err).To(MatchError(ContainSubstring("condition can only reference inputs.env")))
+	})
+
+	It("keeps string type names working", func() {
+		m, err := load(`
+  input "count" {
+    type    = "integer"
+    default = 3
+    validation {
+      condition     = inputs.count <= 10
+      error_message = "count must be at most 10"
+    }
+  }
+`)
+		Expect(err).NotTo(HaveOccurred())
+		vals, err := m.ResolveInputValues(nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(vals["count"].AsBigFloat().String()).To(Equal("3"))
+
+		_, err = m.ResolveInputValues(map[string]string{"count": "11"})
+		Expect(err).To(MatchError(ContainSubstring("count must be at most 10")))
+	})
+})
@@ -1,6 +1,9 @@
 package config_test
 
 import (
+	"strings"
+	"time"
+
 	"squadron/config"
 
 	"github.com/zclconf/go-cty/cty"
@@ -213,6 +216,529 @@ mission "parallel" {
 			Expect(iter.MaxRetries).To(Equal(3))
 		})
 
+		It("parses task and iterator timeouts", func() {
+			hcl := fullBaseHCL() + `
+mission "timed" {
+  commander {
+    model = models.anthropic.claude_sonnet_4
+  }
+  agents    = [agents.test_agent]
+  dataset "items" { description = "Items" }
+  task "process" {
+    objective = "Process items"
+    timeout   = "1h"
+    iterator {
+      dataset  = datasets.items
+      parallel = true
+      timeout  = "90s"
+    }
+  }
+}
+`
+			_, f := writeFixture("config.hcl", hcl)
+			cfg, err := config.LoadFile(f)
+			Expect(err).NotTo(HaveOccurred())
+			task := cfg.Missions[0].Tasks[0]
+			Expect(task.GetTimeout()).To(Equal(time.Hour))
+			Expect(task.Iterator.GetTimeout()).To(Equal(90 * time.Second))
+		})
+
+		It("parses max_turns and rejects values below 1", func() {
+			hcl := fullBaseHCL() + `
+mission "bounded" {
+  commander {
+    model = models.anthropic.claude_sonnet_4
+  }
+  agents    = [agents.test_agent]
+  task "research" {
+    objective = "Research"
+    max_turns = 25
+  }
+}
+`
+			_, f := writeFixture("config.hcl", hcl)
+			cfg, err := config.LoadFile(f)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.Missions[0].Tasks[0].MaxTurns).To(Equal(25))
+
+			_, f = writeFixture("config.hcl", strings.Replace(hcl, "max_turns = 25", "max_turns = 0", 1))
+			_, err = config.LoadFile(f)
+			Expect(err).To(MatchError(ContainSubstring("max_turns must be at least 1")))
+		})
+
+		It("parses priority and rejects fractions", func() {
+			hcl := fullBaseHCL() + `
+mission "prioritized" {
+  commander {
+    model = models.anthropic.claude_sonnet_4
+  }
+  agents    = [agents.test_agent]
+  task "crawl" {
+    objective = "Crawl"
+    priority  = -2
+  }
+}
+`
+			_, f := writeFixture("config.hcl", hcl)
+			cfg, err := config.LoadFile(f)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.Missions[0].Tasks[0].Priority).To(Equal(-2))
+
+			_, f = writeFixture("config.hcl", strings.Replace(hcl, "priority  = -2", "priority  = 1.5", 1))
+			_, err = config.LoadFile(f)
+			Expect(err).To(MatchError(ContainSubstring("priority must be a whole number")))
+		})
+
+		It("parses require_approval and rejects it on iterated tasks", func() {
+			hcl := fullBaseHCL() + `
+mission "gated" {
+  commander {
+    model = models.anthropic.claude_sonnet_4
+  }
+  agents    = [agents.test_agent]
+  task "deploy" {
+    objective        = "Deploy"
+    require_approval = true
+  }
+}
+`
+			_, f := writeFixture("config.hcl", hcl)
+			cfg, err := config.LoadFile(f)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.Missions[0].Tasks[0].RequireApproval).To(BeTrue())
+
+			hcl = fullBaseHCL() + `
+mission "gated_iter" {
+  commander {
+    model = models.anthropic.claude_sonnet_4
+  }
+  agents    = [agents.test_agent]
+  dataset "items" { description = "Items" }
+  task "deploy" {
+    objective        = "Deploy"
+    require_approval = true
+    iterator { dataset = datasets.items }
+  }
+}
+`
+			_, f = writeFixture("config.hcl", hcl)
+			_, err = config.LoadFile(f)
+			Expect(err).To(MatchError(ContainSubstring("require_approval is not supported on iterated tasks")))
+		})
+
+		It("parses verify and rejects it on iterated tasks", func() {
+			hcl := fullBaseHCL() + `
+mission "checked" {
+  commander {
+    model = models.anthropic.claude_sonnet_4
+  }
+  agents    = [agents.test_agent]
+  dataset "items" { description = "Items" }
+  task "research" {
+    objective = "Research"
+    verify    = true
+  }
+  task "each" {
+    objective = "Process"
+    verify    = true
+    iterator { dataset = datasets.items }
+  }
+}
+`
+			_, f := writeFixture("config.hcl", hcl)
+			_, err := config.LoadFile(f)
+			Expect(err).To(MatchError(ContainSubstring("verify is not supported on iterated tasks")))
+
+			_, f = writeFixture("config.hcl", strings.Replace(hcl, "    verify    = true\n    iterator", "    iterator", 1))
+			cfg, err := config.LoadFile(f)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.Missions[0].Tasks[0].Verify).To(BeTrue())
+			Expect(cfg.Missions[0].Tasks[1].Verify).To(BeFalse())
+		})
+
+		It("parses tools_allow and tools_deny into a task tool filter", func() {
+			hcl := fullBaseHCL() + `
+mission "readonly" {
+  commander {
+    model = models.anthropic.claude_sonnet_4
+  }
+  agents    = [agents.test_agent]
+  task "audit" {
+    objective   = "Audit"
+    tools_allow = [builtins.http.all, builtins.utils.current_time]
+    tools_deny  = [builtins.http.post, builtins.http.delete]
+  }
+  task "plain" {
+    objective = "Plain"
+  }
+}
+`
+			_, f := writeFixture("config.hcl", hcl)
+			cfg, err := config.LoadFile(f)
+			Expect(err).NotTo(HaveOccurred())
+			audit := cfg.Missions[0].Tasks[0]
+			Expect(audit.ToolsAllow).To(Equal([]string{"builtins.http.all", "builtins.utils.current_time"}))
+			Expect(audit.ToolsDeny).To(Equal([]string{"builtins.http.post", "builtins.http.delete"}))
+			Expect(audit.GetToolFilter()).NotTo(BeNil())
+			Expect(cfg.Missions[0].Tasks[1].GetToolFilter()).To(BeNil())
+		})
+
+		It("rejects a tools_allow that is not a list", func() {
+			hcl := fullBaseHCL() + `
+mission "readonly" {
+  commander {
+    model = models.anthropic.claude_sonnet_4
+  }
+  agents    = [agents.test_agent]
+  task "audit" {
+    objective   = "Audit"
+    tools_allow = builtins.http.get
+  }
+}
+`
+			_, f := writeFixture("config.hcl", hcl)
+			_, err := config.LoadFile(f)
+			Expect(err).To(MatchError(ContainSubstring("tools_allow must be a list of tool references")))
+		})
+
+		It("parses a when guard and evaluates it against dependency results", func() {
+			hcl := fullBaseHCL() + `
+mission "guarded" {
+  commander {
+    model = models.anthropic.claude_sonnet_4
+  }
+  agents    = [agents.test_agent]
+  input "mode" {
+    type    = "string"
+    default = "full"
+  }
+  task "scan" {
+    objective = "Scan"
+    output = {
+      count = number("Findings", true)
+    }
+  }
+  task "fix" {
+    objective  = "Fix"
+    depends_on = [tasks.scan]
+    when       = query.scan.output.count > 0 && inputs.mode == "full"
+  }
+}
+`
+			_, f := writeFixture("config.hcl", hcl)
+			cfg, err := config.LoadFile(f)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.Validate()).To(Succeed())
+
+			fix := cfg.Missions[0].Tasks[1]
+			Expect(fix.WhenExpr).NotTo(BeNil())
+			Expect(fix.RawWhen).To(Equal(`query.scan.output.count > 0 && inputs.mode == "full"`))
+			Expect(fix.WhenDependencies()).To(Equal([]string{"scan"}))
+
+			inputs := map[string]cty.Value{"mode": cty.StringVal("full")}
+			scan := func(count int64) map[string]cty.Value {
+				return map[string]cty.Value{"scan": cty.ObjectVal(map[string]cty.Value{
+					"status":  cty.StringVal("completed"),
+					"skipped": cty.False,
+					"output":  cty.ObjectVal(map[string]cty.Value{"count": cty.NumberIntVal(count)}),
+				})}
+			}
+			run, err := fix.EvaluateWhen(nil, inputs, scan(3))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(run).To(BeTrue())
+			run, err = fix.EvaluateWhen(nil, inputs, scan(0))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(run).To(BeFalse())
+
+			Expect(cfg.Missions[0].Tasks[0].EvaluateWhen(nil, nil, nil)).To(BeTrue())
+		})
+
+		It("rejects when guards with unknown references or non-upstream tasks", func() {
+			mission := func(when string) string {
+				return fullBaseHCL() + `
+mission "guarded" {
+  commander {
+    model = models.anthropic.claude_sonnet_4
+  }
+  agents    = [agents.test_agent]
+  task "scan" {
+    objective = "Scan"
+  }
+  task "other" {
+    objective = "Other"
+  }
+  task "fix" {
+    objective  = "Fix"
+    depends_on = [tasks.scan]
+    when       = ` + when + `
+  }
+}
+`
+			}
+
+			_, f := writeFixture("config.hcl", mission("item.ok"))
+			_, err := config.LoadFile(f)
+			Expect(err).To(MatchError(ContainSubstring("when: unknown reference 'item'")))
+
+			_, f = writeFixture("config.hcl", mission("query.scan.summary"))
+			_, err = config.LoadFile(f)
+			Expect(err).To(MatchError(ContainSubstring("query.scan supports only output, status, and skipped")))
+
+			_, f = writeFixture("config.hcl", mission("!query.other.skipped"))
+			cfg, err := config.LoadFile(f)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.Validate()).To(MatchError(ContainSubstring("when reads query.other, but 'other' is not an upstream dependency")))
+		})
+
+		It("fails evaluation when the guard is not a bool", func() {
+			hcl := fullBaseHCL() + `
+mission "guarded" {
+  commander {
+    model = models.anthropic.claude_sonnet_4
+  }
+  agents    = [agents.test_agent]
+  task "fix" {
+    objective = "Fix"
+    when      = "yes"
+  }
+}
+`
+			_, f := writeFixture("config.hcl", hcl)
+			cfg, err := config.LoadFile(f)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = cfg.Missions[0].Tasks[0].EvaluateWhen(nil, nil, nil)
+			Expect(err).To(MatchError(ContainSubstring("expression must be a bool")))
+		})
+
+		It("parses an aggregate reducer and folds iteration outputs", func() {
+			hcl := fullBaseHCL() + `
+mission "scores" {
+  commander {
+    model = models.anthropic.claude_sonnet_4
+  }
+  agents    = [agents.test_agent]
+  dataset "items" { description = "Items" }
+  task "score" {
+    objective = "Score each item"
+    iterator { dataset = datasets.items }
+    output = {
+      name  = string("Item name", true)
+      score = number("Score", false)
+    }
+    aggregate {
+      reduce = {
+        total = sum(iterations[*].score)
+        avg   = mean(iterations[*].score)
+        best  = max(iterations[*].score)
+        worst = min(iterations[*].score)
+        n     = count(iterations[*].score)
+        names = collect(iterations[*].name)
+      }
+    }
+  }
+}
+`
+			_, f := writeFixture("config.hcl", hcl)
+			cfg, err := config.LoadFile(f)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.Validate()).To(Succeed())
+
+			agg := cfg.Missions[0].Tasks[0].Aggregate
+			Expect(agg).NotTo(BeNil())
+			Expect(agg.IsLLM()).To(BeFalse())
+			Expect(agg.RawReduce).To(ContainSubstring("sum(iterations[*].score)"))
+
+			item := func(name string, score cty.Value) cty.Value {
+				return cty.ObjectVal(map[string]cty.Value{"name": cty.StringVal(name), "score": score})
+			}
+			iterations := cty.TupleVal([]cty.Value{
+				item("a", cty.NumberIntVal(2)),
+				item("b", cty.NullVal(cty.Number)),
+				item("c", cty.NumberIntVal(4)),
+			})
+			out, err := agg.EvaluateReduce(iterations, nil, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(out).To(Equal(map[string]any{
+				"total": float64(6),
+				"avg":   float64(3),
+				"best":  float64(4),
+				"worst": float64(2),
+				"n":     float64(2),
+				"names": []any{"a", "b", "c"},
+			}))
+		})
+
+		It("parses an LLM aggregate and validates it", func() {
+			mission := func(task, aggregate string) string {
+				return fullBaseHCL() + `
+mission "summaries" {
+  commander {
+    model = models.anthropic.claude_sonnet_4
+  }
+  agents    = [agents.test_agent]
+  dataset "items" { description = "Items" }
+  task "summarize" {
+    objective = "Summarize each item"
+    ` + task + `
+    aggregate {
+      ` + aggregate + `
+    }
+  }
+}
+`
+			}
+			iterator := "iterator { dataset = datasets.items }"
+
+			_, f := writeFixture("config.hcl", mission(iterator, `
+      prompt = "Combine the summaries"
+      model  = models.anthropic.claude_sonnet_4`))
+			cfg, err := config.LoadFile(f)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.Validate()).To(Succeed())
+			agg := cfg.Missions[0].Tasks[0].Aggregate
+			Expect(agg.IsLLM()).To(BeTrue())
+			Expect(agg.Prompt).To(Equal("Combine the summaries"))
+			Expect(agg.Model).To(Equal("claude_sonnet_4"))
+
+			_, f = writeFixture("config.hcl", mission("", `prompt = "Combine"`))
+			cfg, err = config.LoadFile(f)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.Validate()).To(MatchError(ContainSubstring("aggregate is only supported on iterated tasks")))
+
+			_, f = writeFixture("config.hcl", mission(iterator, `
+      prompt = "Combine"
+      reduce = { n = count(iterations) }`))
+			cfg, err = config.LoadFile(f)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.Validate()).To(MatchError(ContainSubstring("reduce and prompt are mutually exclusive")))
+
+			_, f = writeFixture("config.hcl", mission(iterator, ``))
+			cfg, err = config.LoadFile(f)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.Validate()).To(MatchError(ContainSubstring("one of reduce or prompt is required")))
+
+			_, f = writeFixture("config.hcl", mission(iterator, `reduce = { first = item.name }`))
+			_, err = config.LoadFile(f)
+			Expect(err).To(MatchError(ContainSubstring("unknown reference 'item' in reduce")))
+		})
+
+		It("parses task and iterator model overrides and validates them", func() {
+			mission := func(models string) string {
+				return fullBaseHCL() + `
+mission "bulk" {
+  commander {
+    model = models.anthropic.claude_sonnet_4
+  }
+  agents    = [agents.test_agent]
+  dataset "items" { description = "Items" }
+  task "classify" {
+    objective = "Classify each item"
+    models {
+      commander = models.anthropic.claude_opus_4
+      agents    = { test_agent = models.anthropic.claude_sonnet_4_5 }
+    }
+    iterator {
+      dataset = datasets.items
+      ` + models + `
+    }
+  }
+  task "synthesize" {
+    objective  = "Synthesize"
+    depends_on = [tasks.classify]
+  }
+}
+`
+			}
+
+			_, f := writeFixture("config.hcl", mission(`models { agents = { test_agent = models.anthropic.claude_haiku_4_5 } }`))
+			cfg, err := config.LoadFile(f)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.Validate()).To(Succeed())
+			classify := cfg.Missions[0].Tasks[0]
+			Expect(classify.Models.Commander).To(Equal("claude_opus_4"))
+			Expect(classify.CommanderModel("claude_sonnet_4")).To(Equal("claude_opus_4"))
+			Expect(classify.AgentModels()).To(Equal(map[string]string{"test_agent": "claude_haiku_4_5"}))
+			synthesize := cfg.Missions[0].Tasks[1]
+			Expect(synthesize.CommanderModel("claude_sonnet_4")).To(Equal("claude_sonnet_4"))
+			Expect(synthesize.AgentModels()).To(BeNil())
+
+			_, f = writeFixture("config.hcl", mission(`models { agents = { other_agent = models.anthropic.claude_haiku_4_5 } }`))
+			cfg, err = config.LoadFile(f)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.Validate()).To(MatchError(ContainSubstring("iterator models: agent 'other_agent' is not one of the task's agents")))
+
+			_, f = writeFixture("config.hcl", mission(`models { commander = "no_such_model" }`))
+			cfg, err = config.LoadFile(f)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.Validate()).To(MatchError(ContainSubstring("commander model 'no_such_model' not found in models")))
+
+			_, f = writeFixture("config.hcl", mission(`models { agents = models.anthropic.claude_haiku_4_5 }`))
+			_, err = config.LoadFile(f)
+			Expect(err).To(MatchError(ContainSubstring("agents must be a map of agent name to model reference")))
+		})
+
+		It("parses a task sampling block and validates it", func() {
+			mission := func(sampling string) string {
+				return fullBaseHCL() + `
+mission "extract" {
+  commander {
+    model = models.anthropic.claude_sonnet_4
+  }
+  agents    = [agents.test_agent]
+  task "extract" {
+    objective = "Extract the fields"
+    ` + sampling + `
+  }
+}
+`
+			}
+
+			_, f := writeFixture("config.hcl", mission(`sampling {
+      temperature       = 0
+      max_output_tokens = 2048
+    }`))
+			cfg, err := config.LoadFile(f)
+			Expect(err).NotTo(HaveOccurred())
+			s := cfg.Missions[0].Tasks[0].Sampling
+			Expect(s).NotTo(BeNil())
+			Expect(*s.Temperature).To(Equal(0.0))
+			Expect(s.TopP).To(BeNil())
+			Expect(s.MaxOutputTokens).To(Equal(2048))
+
+			_, f = writeFixture("config.hcl", mission(`sampling { max_output_tokens = -1 }`))
+			_, err = config.LoadFile(f)
+			Expect(err).To(MatchError(ContainSubstring("task 'extract': sampling: max_output_tokens must be positive")))
+
+			_, f = writeFixture("config.hcl", mission(`sampling { temperature = 0 }
+    sampling { temperature = 1 }`))
+			_, err = config.LoadFile(f)
+			Expect(err).To(MatchError(ContainSubstring("only one sampling block allowed")))
+		})
+
+		It("rejects a reducer that does not produce an object", func() {
+			hcl := fullBaseHCL() + `
+mission "scores" {
+  commander {
+    model = models.anthropic.claude_sonnet_4
+  }
+  agents    = [agents.test_agent]
+  dataset "items" { description = "Items" }
+  task "score" {
+    objective = "Score each item"
+    iterator { dataset = datasets.items }
+    aggregate {
+      reduce = sum(iterations[*].score)
+    }
+  }
+}
+`
+			_, f := writeFixture("config.hcl", hcl)
+			cfg, err := config.LoadFile(f)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = cfg.Missions[0].Tasks[0].Aggregate.EvaluateReduce(cty.EmptyTupleVal, nil, nil)
+			Expect(err).To(MatchError(ContainSubstring("reduce must be an object")))
+		})
+
 		It("parses dataset with bind_to input reference", func() {
 			hcl := fullBaseHCL() + `
 mission "bound" {
@@ -270,6 +796,48 @@ mission "multi_agent" {
 		})
 	})
 
+	Describe("Commander checkpoint", func() {
+		It("parses the checkpoint block on commander", func() {
+			hcl := fullBaseHCL() + `
+mission "checkpointed" {
+  commander {
+    model = models.anthropic.claude_sonnet_4
+    checkpoint {
+      interval = 5
+    }
+  }
+  agents = [agents.test_agent]
+  task "t" { objective = "Do work" }
+}
+`
+			_, f := writeFixture("config.hcl", hcl)
+			cfg, err := config.LoadFile(f)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.Validate()).To(Succeed())
+			Expect(cfg.Missions[0].Commander.Checkpoint).NotTo(BeNil())
+			Expect(cfg.Missions[0].Commander.Checkpoint.Interval).To(Equal(5))
+		})
+
+		It("rejects a non-positive interval", func() {
+			hcl := fullBaseHCL() + `
+mission "checkpointed" {
+  commander {
+    model = models.anthropic.claude_sonnet_4
+    checkpoint {
+      interval = 0
+    }
+  }
+  agents = [agents.test_agent]
+  task "t" { objective = "Do work" }
+}
+`
+			_, f := writeFixture("config.hcl", hcl)
+			cfg, err := config.LoadFile(f)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.Validate()).To(MatchError(ContainSubstring("commander checkpoint interval must be > 0")))
+		})
+	})
+
 	Describe("Commander tool_response", func() {
 		It("parses tool_response block on commander", func() {
 			hcl := fullBaseHCL() + `
@@ -567,6 +1135,48 @@ mission "bad_iter3" {
 				Expect(err.Error()).To(ContainSubstring("smoketest is only valid when parallel=true"))
 			})
 
+			It("rejects iterator timeout when parallel=false", func() {
+				hcl := fullBaseHCL() + `
+mission "bad_iter4" {
+  commander {
+    model = models.anthropic.claude_sonnet_4
+  }
+  agents    = [agents.test_agent]
+  dataset "items" { description = "Items" }
+  task "work" {
+    objective = "Do work"
+    iterator {
+      dataset = datasets.items
+      timeout = "5m"
+    }
+  }
+}
+`
+				_, f := writeFixture("config.hcl", hcl)
+				_, err := config.LoadFile(f)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("timeout is only valid when parallel=true"))
+			})
+
+			It("rejects an invalid task timeout", func() {
+				hcl := fullBaseHCL() + `
+mission "bad_timeout" {
+  commander {
+    model = models.anthropic.claude_sonnet_4
+  }
+  agents    = [agents.test_agent]
+  task "work" {
+    objective = "Do work"
+    timeout   = "soon"
+  }
+}
+`
+				_, f := writeFixture("config.hcl", hcl)
+				_, err := config.LoadFile(f)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("timeout"))
+			})
+
 			It("accepts parallel-specific options when parallel=true", func() {
 				hcl := fullBaseHCL() + `
 mission "good_iter" {
@@ -592,6 +1202,73 @@ mission "good_iter" {
 				Expect(err).NotTo(HaveOccurred())
 				Expect(cfg.Missions[0].Tasks[0].Iterator.Parallel).To(BeTrue())
 			})
+
+			iteratorMission := func(iterator string) (*config.Config, error) {
+				hcl := fullBaseHCL() + `
+mission "partial" {
+  commander {
+    model = models.anthropic.claude_sonnet_4
+  }
+  agents    = [agents.test_agent]
+  dataset "items" { description = "Items" }
+  task "work" {
+    objective = "Do work"
+    iterator {
+` + iterator + `
+    }
+  }
+}
+`
+				_, f := writeFixture("config.hcl", hcl)
+				return config.LoadFile(f)
+			}
+
+			It("parses continue_on_failure and max_failure_rate", func() {
+				cfg, err := iteratorMission(`
+      dataset             = datasets.items
+      parallel            = true
+      continue_on_failure = true
+      max_failure_rate    = 0.2`)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cfg.Validate()).To(Succeed())
+				iter := cfg.Missions[0].Tasks[0].Iterator
+				Expect(iter.ContinueOnFailure).To(BeTrue())
+				Expect(iter.GetMaxFailureRate()).To(Equal(0.2))
+			})
+
+			It("defaults max_failure_rate to 1", func() {
+				cfg, err := iteratorMission(`
+      dataset             = datasets.items
+      parallel            = true
+      continue_on_failure = true`)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cfg.Missions[0].Tasks[0].Iterator.GetMaxFailureRate()).To(Equal(1.0))
+			})
+
+			It("rejects continue_on_failure when parallel=false", func() {
+				_, err := iteratorMission(`
+      dataset             = datasets.items
+      continue_on_failure = true`)
+				Expect(err).To(MatchError(ContainSubstring("continue_on_failure is only valid when parallel=true")))
+			})
+
+			It("rejects max_failure_rate without continue_on_failure", func() {
+				_, err := iteratorMission(`
+      dataset          = datasets.items
+      parallel         = true
+      max_failure_rate = 0.5`)
+				Expect(err).To(MatchError(ContainSubstring("max_failure_rate is only valid when continue_on_failure=true")))
+			})
+
+			It("rejects a max_failure_rate outside 0 to 1", func() {
+				cfg, err := iteratorMission(`
+      dataset             = datasets.items
+      parallel            = true
+      continue_on_failure = true
+      max_failure_rate    = 20`)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cfg.Validate()).To(MatchError(ContainSubstring("max_failure_rate must be between 0 and 1, got 20")))
+			})
 		})
 
 		Context("DAG cycle detection", func() {
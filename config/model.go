@@ -1,6 +1,10 @@
 package config
 
-import "fmt"
+import (
+	"fmt"
+	"regexp"
+	"time"
+)
 
 type Provider string
 
@@ -9,8 +13,32 @@ const (
 	ProviderGemini    Provider = "gemini"
 	ProviderAnthropic Provider = "anthropic"
 	ProviderOllama    Provider = "ollama"
+
+	// ProviderOpenAICompatible targets any self-hosted server that speaks the
+	// OpenAI API (vLLM, LM Studio, llama.cpp, LiteLLM). Like Ollama, models
+	// are registered via `aliases`; unlike Ollama, an api_key may be set for
+	// servers that check one.
+	ProviderOpenAICompatible Provider = "openai_compatible"
+
+	// ProviderScripted replays the model block's `response` blocks instead
+	// of calling a model, for deterministic end-to-end tests. It needs no
+	// api_key.
+	ProviderScripted Provider = "scripted"
 )
 
+// IsSelfHosted reports whether the provider is a user-run OpenAI-compatible
+// server: base_url and aliases are required, api_key is optional, and
+// there is no built-in model list or pricing.
+func (p Provider) IsSelfHosted() bool {
+	return p == ProviderOllama || p == ProviderOpenAICompatible
+}
+
+// RequiresAPIKey reports whether model configs for the provider must set
+// api_key.
+func (p Provider) RequiresAPIKey() bool {
+	return !p.IsSelfHosted() && p != ProviderScripted
+}
+
 // ModelInfo describes a single registered model: the wire-name sent to the
 // provider and the capability flags Squadron needs to know about.
 //
@@ -28,6 +56,11 @@ type ModelInfo struct {
 	// whose resolved model has this false logs a warning at startup and
 	// the session runs without reasoning.
 	Reasoning bool
+
+	// ContextWindow is the most input tokens the provider accepts in one
+	// request. Sessions refuse to send larger requests instead of waiting
+	// for the provider's 400. Zero means unknown: no check is made.
+	ContextWindow int
 }
 
 // SupportedModels is the registry of every model Squadron ships built-in
@@ -35,6 +68,10 @@ type ModelInfo struct {
 //
 // HCL refs (`models.openai.gpt_5`) resolve through this table. Ollama keeps
 // an empty map because users register their own models via `aliases`.
+//
+// ContextWindow is the provider's default window. It is left unset where
+// that isn't settled (larger windows behind a beta flag or account tier
+// are set per config with `context_window`).
 var SupportedModels = map[Provider]map[string]ModelInfo{
 	ProviderOpenAI: {
 		// Reasoning models — gpt-5 family, o-series 3 and 4. o1 is
@@ -46,44 +83,44 @@ var SupportedModels = map[Provider]map[string]ModelInfo{
 		"gpt_5_4_nano":  {APIName: "gpt-5.4-nano", Reasoning: true},
 		"gpt_5_4_pro":   {APIName: "gpt-5.4-pro", Reasoning: true},
 		"gpt_5_3_codex": {APIName: "gpt-5.3-codex", Reasoning: true},
-		"gpt_5_2":       {APIName: "gpt-5.2", Reasoning: true},
-		"gpt_5":         {APIName: "gpt-5", Reasoning: true},
-		"gpt_5_mini":    {APIName: "gpt-5-mini", Reasoning: true},
-		"gpt_5_nano":    {APIName: "gpt-5-nano", Reasoning: true},
+		"gpt_5_2":       {APIName: "gpt-5.2", Reasoning: true, ContextWindow: 400000},
+		"gpt_5":         {APIName: "gpt-5", Reasoning: true, ContextWindow: 400000},
+		"gpt_5_mini":    {APIName: "gpt-5-mini", Reasoning: true, ContextWindow: 400000},
+		"gpt_5_nano":    {APIName: "gpt-5-nano", Reasoning: true, ContextWindow: 400000},
 		// o3, o4-mini, o3-mini are deprecated (API shutdown 2026-10-23) but
 		// still functional today; keep them registered until shutdown.
-		"o3":      {APIName: "o3", Reasoning: true},
-		"o4_mini": {APIName: "o4-mini", Reasoning: true},
-		"o3_mini": {APIName: "o3-mini", Reasoning: true},
+		"o3":      {APIName: "o3", Reasoning: true, ContextWindow: 200000},
+		"o4_mini": {APIName: "o4-mini", Reasoning: true, ContextWindow: 200000},
+		"o3_mini": {APIName: "o3-mini", Reasoning: true, ContextWindow: 200000},
 
 		// Non-reasoning chat models.
-		"gpt_4_1":      {APIName: "gpt-4.1"},
-		"gpt_4_1_mini": {APIName: "gpt-4.1-mini"},
-		"gpt_4_1_nano": {APIName: "gpt-4.1-nano"},
-		"gpt_4o":       {APIName: "gpt-4o"},
-		"gpt_4o_mini":  {APIName: "gpt-4o-mini"},
+		"gpt_4_1":      {APIName: "gpt-4.1", ContextWindow: 1047576},
+		"gpt_4_1_mini": {APIName: "gpt-4.1-mini", ContextWindow: 1047576},
+		"gpt_4_1_nano": {APIName: "gpt-4.1-nano", ContextWindow: 1047576},
+		"gpt_4o":       {APIName: "gpt-4o", ContextWindow: 128000},
+		"gpt_4o_mini":  {APIName: "gpt-4o-mini", ContextWindow: 128000},
 		// gpt-4-turbo and o1 are deprecated (shutdown 2026-10-23). o1-mini
 		// is fully retired (shutdown 2025-10-27) and removed from the
 		// registry — requests to it return 404 now.
-		"gpt_4_turbo": {APIName: "gpt-4-turbo"},
-		"o1":          {APIName: "o1"},
+		"gpt_4_turbo": {APIName: "gpt-4-turbo", ContextWindow: 128000},
+		"o1":          {APIName: "o1", ContextWindow: 200000},
 	},
 	ProviderGemini: {
 		// Gemini 2.5+ and 3.x support thinking.
-		"gemini_3_1_pro_preview":        {APIName: "gemini-3.1-pro-preview", Reasoning: true},
-		"gemini_3_1_flash_lite_preview": {APIName: "gemini-3.1-flash-lite-preview", Reasoning: true},
-		"gemini_3_flash_preview":        {APIName: "gemini-3-flash-preview", Reasoning: true},
-		"gemini_2_5_pro":                {APIName: "gemini-2.5-pro", Reasoning: true},
-		"gemini_2_5_flash":              {APIName: "gemini-2.5-flash", Reasoning: true},
-		"gemini_2_5_flash_lite":         {APIName: "gemini-2.5-flash-lite", Reasoning: true},
+		"gemini_3_1_pro_preview":        {APIName: "gemini-3.1-pro-preview", Reasoning: true, ContextWindow: 1048576},
+		"gemini_3_1_flash_lite_preview": {APIName: "gemini-3.1-flash-lite-preview", Reasoning: true, ContextWindow: 1048576},
+		"gemini_3_flash_preview":        {APIName: "gemini-3-flash-preview", Reasoning: true, ContextWindow: 1048576},
+		"gemini_2_5_pro":                {APIName: "gemini-2.5-pro", Reasoning: true, ContextWindow: 1048576},
+		"gemini_2_5_flash":              {APIName: "gemini-2.5-flash", Reasoning: true, ContextWindow: 1048576},
+		"gemini_2_5_flash_lite":         {APIName: "gemini-2.5-flash-lite", Reasoning: true, ContextWindow: 1048576},
 
 		// Earlier Gemini families don't support thinking. Gemini 2.0 flash
 		// variants are deprecated with a 2026-06-01 shutdown — keep them
 		// registered until then. The entire 1.5 family was retired in
 		// September 2025 (API returns 404) and is no longer registered.
-		"gemini_2_0_flash":      {APIName: "gemini-2.0-flash"},
-		"gemini_2_0_flash_lite": {APIName: "gemini-2.0-flash-lite"},
-		"gemini_2_0_flash_exp":  {APIName: "gemini-2.0-flash-exp"},
+		"gemini_2_0_flash":      {APIName: "gemini-2.0-flash", ContextWindow: 1048576},
+		"gemini_2_0_flash_lite": {APIName: "gemini-2.0-flash-lite", ContextWindow: 1048576},
+		"gemini_2_0_flash_exp":  {APIName: "gemini-2.0-flash-exp", ContextWindow: 1048576},
 	},
 	ProviderAnthropic: {
 		// Claude 4.x family supports extended thinking. claude-opus-4 and
@@ -92,12 +129,12 @@ var SupportedModels = map[Provider]map[string]ModelInfo{
 		// then so existing missions don't break.
 		"claude_opus_4_7":   {APIName: "claude-opus-4-7", Reasoning: true},
 		"claude_opus_4_6":   {APIName: "claude-opus-4-6", Reasoning: true},
-		"claude_opus_4_5":   {APIName: "claude-opus-4-5-20251101", Reasoning: true},
+		"claude_opus_4_5":   {APIName: "claude-opus-4-5-20251101", Reasoning: true, ContextWindow: 200000},
 		"claude_sonnet_4_6": {APIName: "claude-sonnet-4-6", Reasoning: true},
-		"claude_sonnet_4_5": {APIName: "claude-sonnet-4-5-20250929", Reasoning: true},
-		"claude_sonnet_4":   {APIName: "claude-sonnet-4-20250514", Reasoning: true},
-		"claude_opus_4":     {APIName: "claude-opus-4-20250514", Reasoning: true},
-		"claude_haiku_4_5":  {APIName: "claude-haiku-4-5-20251001", Reasoning: true},
+		"claude_sonnet_4_5": {APIName: "claude-sonnet-4-5-20250929", Reasoning: true, ContextWindow: 200000},
+		"claude_sonnet_4":   {APIName: "claude-sonnet-4-20250514", Reasoning: true, ContextWindow: 200000},
+		"claude_opus_4":     {APIName: "claude-opus-4-20250514", Reasoning: true, ContextWindow: 200000},
+		"claude_haiku_4_5":  {APIName: "claude-haiku-4-5-20251001", Reasoning: true, ContextWindow: 200000},
 		// Claude 3.5 Sonnet was retired 2025-10-28; Claude 3.5 Haiku was
 		// retired 2026-02-19. Both removed from the registry.
 	},
@@ -105,6 +142,28 @@ var SupportedModels = map[Provider]map[string]ModelInfo{
 	// Capability flags can't be inferred and aren't currently surfaced —
 	// `reasoning = "..."` on an Ollama agent is a no-op + warning.
 	ProviderOllama: {},
+	// Same as Ollama: models come from `aliases`.
+	ProviderOpenAICompatible: {},
+	// Every model of a scripted config replays the same script; `scripted`
+	// is there so a config needs no aliases.
+	ProviderScripted: {
+		"scripted": {APIName: "scripted"},
+	},
+}
+
+// EmbeddingModels is the registry of embedding models usable as a mission
+// knowledge block's `embedding_model`. Provider → HCL-friendly key → API
+// name. They resolve through the same `models.<config>.<key>` references as
+// chat models but are kept out of SupportedModels so they can't be picked
+// as an agent or commander model. Self-hosted providers use `aliases`.
+var EmbeddingModels = map[Provider]map[string]string{
+	ProviderOpenAI: {
+		"text_embedding_3_small": "text-embedding-3-small",
+		"text_embedding_3_large": "text-embedding-3-large",
+	},
+	ProviderGemini: {
+		"gemini_embedding_001": "gemini-embedding-001",
+	},
 }
 
 // BuildPricingOverrides builds a map of API model name → pricing from all
@@ -128,13 +187,46 @@ func BuildPricingOverrides(models []Model) map[string]*ModelPricingConfig {
 }
 
 type Model struct {
-	Name          string                         `hcl:"name,label"`
-	Provider      Provider                       `hcl:"provider"`
-	Aliases       map[string]string              `hcl:"-"` // HCL key → API model name (parsed manually)
-	APIKey        string                         `hcl:"api_key,optional"`
-	BaseURL       string                         `hcl:"base_url,optional"`
-	PromptCaching *bool                          `hcl:"prompt_caching,optional"`
-	Pricing       map[string]*ModelPricingConfig `json:"-"` // model name → pricing override
+	Name           string                         `hcl:"name,label"`
+	Provider       Provider                       `hcl:"provider"`
+	Aliases        map[string]string              `hcl:"-"` // HCL key → API model name (parsed manually)
+	APIKey         string                         `hcl:"api_key,optional"`
+	BaseURL        string                         `hcl:"base_url,optional"`
+	PromptCaching  *bool                          `hcl:"prompt_caching,optional"`
+	Pricing        map[string]*ModelPricingConfig `json:"-"`                       // model name → pricing override
+	Retry          *ModelRetry                    `json:"retry,omitempty"`         // provider error retry policy (parsed manually)
+	RateLimit      *ModelRateLimit                `json:"rateLimit,omitempty"`     // per-model request/token rate cap (parsed manually)
+	Compaction     map[string]*Compaction         `json:"compaction,omitempty"`    // model name → default compaction (parsed manually)
+	Sampling       map[string]*Sampling           `json:"sampling,omitempty"`      // model name → sampling settings (parsed manually)
+	Responses      []ScriptedResponse             `json:"-"`                       // canned replies for provider "scripted" (parsed manually)
+	ContextWindows map[string]int                 `json:"contextWindow,omitempty"` // model name → context window override (parsed manually)
+}
+
+// ScriptedResponse is a `response` block on a model with provider
+// "scripted". Each call gets the first unused response whose match regex
+// matches the prompt; see llm.ScriptedProvider for what the prompt looks
+// like.
+//
+//	response {
+//	  match   = "Commander"
+//	  content = "Handing this to the researcher."
+//	  tool_call "call_agent" {
+//	    input = { name = "researcher", task = "Find the release date" }
+//	  }
+//	}
+type ScriptedResponse struct {
+	Match     string
+	Content   string
+	Error     string
+	Repeat    bool
+	ToolCalls []ScriptedToolCall
+}
+
+// ScriptedToolCall is a `tool_call "<name>"` block in a response. Input is
+// the call's JSON arguments.
+type ScriptedToolCall struct {
+	Name  string
+	Input string
 }
 
 // AvailableModels returns all HCL keys available for this provider mapped to
@@ -153,6 +245,33 @@ func (m *Model) AvailableModels() map[string]string {
 	return result
 }
 
+// AvailableEmbeddingModels returns the embedding model keys this model
+// config provides: the registered ones for its provider, plus every alias on
+// self-hosted providers (whose capabilities can't be inferred).
+func (m *Model) AvailableEmbeddingModels() map[string]string {
+	result := make(map[string]string)
+	for key, apiName := range EmbeddingModels[m.Provider] {
+		result[key] = apiName
+	}
+	if m.Provider.IsSelfHosted() {
+		for key, apiName := range m.Aliases {
+			result[key] = apiName
+		}
+	}
+	return result
+}
+
+// ResolveEmbeddingModel finds the model config providing an embedding model
+// key, returning it along with the API name.
+func ResolveEmbeddingModel(key string, models []Model) (*Model, string, error) {
+	for i := range models {
+		if apiName, ok := models[i].AvailableEmbeddingModels()[key]; ok {
+			return &models[i], apiName, nil
+		}
+	}
+	return nil, "", fmt.Errorf("no model config provides embedding model '%s'", key)
+}
+
 // ModelInfoByAPIName looks up the registered ModelInfo for a given API name
 // on this model's provider. Returns ok=false if the API name isn't registered
 // (e.g. a user-aliased Ollama model). Linear scan; the registry is small and
@@ -168,6 +287,25 @@ func (m *Model) ModelInfoByAPIName(apiName string) (ModelInfo, bool) {
 	return ModelInfo{}, false
 }
 
+// ContextWindow returns the context window for an API model name: the
+// config's `context_window` entry for it, else the registry's. Zero means
+// the window is unknown.
+func (m *Model) ContextWindow(apiName string) int {
+	if m == nil {
+		return 0
+	}
+	available := m.AvailableModels()
+	for key, n := range m.ContextWindows {
+		if available[key] == apiName {
+			return n
+		}
+	}
+	if info, ok := m.ModelInfoByAPIName(apiName); ok {
+		return info.ContextWindow
+	}
+	return 0
+}
+
 // ModelPricingConfig holds per-million-token cost overrides for a model.
 type ModelPricingConfig struct {
 	Input      float64 `hcl:"input"`
@@ -176,6 +314,90 @@ type ModelPricingConfig struct {
 	CacheWrite float64 `hcl:"cache_write,optional"`
 }
 
+// ModelRetry is the optional `retry { ... }` block inside a model block. It
+// controls how rate limits, 5xx errors and timeouts from the provider are
+// retried before the call fails. Unset fields keep the defaults: 7 attempts,
+// backoff starting at 2s and doubling up to 64s.
+//
+//	retry {
+//	  max_attempts    = 4
+//	  initial_backoff = "1s"
+//	  max_backoff     = "30s"
+//	}
+type ModelRetry struct {
+	MaxAttempts    int    `hcl:"max_attempts,optional" json:"maxAttempts,omitempty"`
+	InitialBackoff string `hcl:"initial_backoff,optional" json:"initialBackoff,omitempty"`
+	MaxBackoff     string `hcl:"max_backoff,optional" json:"maxBackoff,omitempty"`
+}
+
+// Validate checks the attempt count and backoff durations.
+func (r *ModelRetry) Validate() error {
+	if r.MaxAttempts < 0 {
+		return fmt.Errorf("max_attempts must be >= 1")
+	}
+	for _, d := range []struct{ name, val string }{
+		{"initial_backoff", r.InitialBackoff},
+		{"max_backoff", r.MaxBackoff},
+	} {
+		if d.val == "" {
+			continue
+		}
+		parsed, err := time.ParseDuration(d.val)
+		if err != nil {
+			return fmt.Errorf("%s: invalid duration %q", d.name, d.val)
+		}
+		if parsed <= 0 {
+			return fmt.Errorf("%s must be positive", d.name)
+		}
+	}
+	if r.InitialBackoff != "" && r.MaxBackoff != "" && r.GetInitialBackoff() > r.GetMaxBackoff() {
+		return fmt.Errorf("initial_backoff must not exceed max_backoff")
+	}
+	return nil
+}
+
+// GetInitialBackoff returns initial_backoff, or 0 when unset.
+func (r *ModelRetry) GetInitialBackoff() time.Duration {
+	d, _ := time.ParseDuration(r.InitialBackoff)
+	return d
+}
+
+// GetMaxBackoff returns max_backoff, or 0 when unset.
+func (r *ModelRetry) GetMaxBackoff() time.Duration {
+	d, _ := time.ParseDuration(r.MaxBackoff)
+	return d
+}
+
+// ModelRateLimit is the optional `rate_limit { ... }` block inside a model
+// block. Every commander and agent in the process calling a model of the
+// config shares one budget per model, so parallel iterations wait their
+// turn instead of tripping 429s. Either attribute may be left unset.
+//
+//	rate_limit {
+//	  requests_per_minute = 50
+//	  tokens_per_minute   = 40000
+//	}
+type ModelRateLimit struct {
+	RequestsPerMinute int `hcl:"requests_per_minute,optional" json:"requestsPerMinute,omitempty"`
+	TokensPerMinute   int `hcl:"tokens_per_minute,optional" json:"tokensPerMinute,omitempty"`
+}
+
+// Validate checks that at least one positive limit is set.
+func (r *ModelRateLimit) Validate() error {
+	if r.RequestsPerMinute < 0 || r.TokensPerMinute < 0 {
+		return fmt.Errorf("requests_per_minute and tokens_per_minute must be positive")
+	}
+	if r.RequestsPerMinute == 0 && r.TokensPerMinute == 0 {
+		return fmt.Errorf("set requests_per_minute, tokens_per_minute, or both")
+	}
+	return nil
+}
+
+// CompactionFor returns the compaction defaults for a model key, or nil.
+func (m *Model) CompactionFor(key string) *Compaction {
+	return m.Compaction[key]
+}
+
 // IsPromptCachingEnabled returns whether prompt caching is enabled (defaults to true).
 func (m *Model) IsPromptCachingEnabled() bool {
 	if m.PromptCaching == nil {
@@ -189,7 +411,54 @@ func (m *Model) Validate() error {
 		return fmt.Errorf("unsupported provider '%s'", m.Provider)
 	}
 
-	if m.Provider == ProviderOllama {
+	if m.Retry != nil {
+		if err := m.Retry.Validate(); err != nil {
+			return fmt.Errorf("retry: %w", err)
+		}
+	}
+
+	if m.RateLimit != nil {
+		if err := m.RateLimit.Validate(); err != nil {
+			return fmt.Errorf("rate_limit: %w", err)
+		}
+	}
+
+	available := m.AvailableModels()
+	for key := range m.Compaction {
+		if _, ok := available[key]; !ok {
+			return fmt.Errorf("compaction '%s': not a model of this config", key)
+		}
+	}
+	for key := range m.Sampling {
+		if _, ok := available[key]; !ok {
+			return fmt.Errorf("sampling '%s': not a model of this config", key)
+		}
+	}
+	for key, n := range m.ContextWindows {
+		if _, ok := available[key]; !ok {
+			return fmt.Errorf("context_window '%s': not a model of this config", key)
+		}
+		if n <= 0 {
+			return fmt.Errorf("context_window '%s': must be a positive number of tokens", key)
+		}
+	}
+
+	if m.Provider == ProviderScripted {
+		if len(m.Responses) == 0 {
+			return fmt.Errorf("provider '%s' needs at least one response block", m.Provider)
+		}
+		for i, r := range m.Responses {
+			if _, err := regexp.Compile(r.Match); err != nil {
+				return fmt.Errorf("response %d: invalid match: %w", i+1, err)
+			}
+		}
+		return nil
+	}
+	if len(m.Responses) > 0 {
+		return fmt.Errorf("response blocks are only allowed with provider '%s'", ProviderScripted)
+	}
+
+	if m.Provider.IsSelfHosted() {
 		if m.BaseURL == "" {
 			return fmt.Errorf("base_url is required for provider '%s'", m.Provider)
 		}
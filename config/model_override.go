@@ -0,0 +1,138 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// ModelOverride describes the `models { ... }` block of a task or iterator.
+// It swaps the model the commander or individual agents run on for that
+// task only, e.g. a cheap model for bulk iterations and an expensive one
+// for the synthesis task:
+//
+//	task "summarize" {
+//	  models {
+//	    commander = models.anthropic.claude_opus_4
+//	    agents    = { researcher = models.anthropic.claude_haiku_4_5 }
+//	  }
+//	}
+//
+// On an iterator the block applies to the iterations and takes precedence
+// over the task's block, key by key.
+type ModelOverride struct {
+	Commander string            `json:"commander,omitempty"`
+	Agents    map[string]string `json:"agents,omitempty"` // agent name → model key
+}
+
+// parseModelOverrideBlock parses a task or iterator models block.
+func parseModelOverrideBlock(block *hcl.Block, ctx *hcl.EvalContext) (*ModelOverride, error) {
+	content, diags := block.Body.Content(&hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{
+			{Name: "commander"},
+			{Name: "agents"},
+		},
+	})
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("models: %w", diags)
+	}
+
+	o := &ModelOverride{}
+	if attr, ok := content.Attributes["commander"]; ok {
+		val, diags := attr.Expr.Value(ctx)
+		if diags.HasErrors() {
+			return nil, fmt.Errorf("models commander: %w", diags)
+		}
+		if val.Type() != cty.String {
+			return nil, fmt.Errorf("models: commander must be a model reference")
+		}
+		o.Commander = val.AsString()
+	}
+	if attr, ok := content.Attributes["agents"]; ok {
+		val, diags := attr.Expr.Value(ctx)
+		if diags.HasErrors() {
+			return nil, fmt.Errorf("models agents: %w", diags)
+		}
+		if !val.Type().IsObjectType() && !val.Type().IsMapType() {
+			return nil, fmt.Errorf("models: agents must be a map of agent name to model reference")
+		}
+		o.Agents = make(map[string]string)
+		for it := val.ElementIterator(); it.Next(); {
+			k, v := it.Element()
+			if v.Type() != cty.String {
+				return nil, fmt.Errorf("models: agents.%s must be a model reference", k.AsString())
+			}
+			o.Agents[k.AsString()] = v.AsString()
+		}
+	}
+	return o, nil
+}
+
+// Validate checks that every model exists and every overridden agent is one
+// of the task's agents.
+func (o *ModelOverride) Validate(models []Model, taskAgents []string) error {
+	if o == nil {
+		return nil
+	}
+	if o.Commander != "" && !isValidModelRef(o.Commander, models) {
+		return fmt.Errorf("models: commander model '%s' not found in models", o.Commander)
+	}
+	for agentName, model := range o.Agents {
+		if !slices.Contains(taskAgents, agentName) {
+			return fmt.Errorf("models: agent '%s' is not one of the task's agents", agentName)
+		}
+		if !isValidModelRef(model, models) {
+			return fmt.Errorf("models: model '%s' for agent '%s' not found in models", model, agentName)
+		}
+	}
+	return nil
+}
+
+// merge returns o with the keys set in over taking precedence.
+func (o *ModelOverride) merge(over *ModelOverride) *ModelOverride {
+	if over == nil {
+		return o
+	}
+	if o == nil {
+		return over
+	}
+	merged := &ModelOverride{Commander: o.Commander, Agents: make(map[string]string)}
+	if over.Commander != "" {
+		merged.Commander = over.Commander
+	}
+	for name, model := range o.Agents {
+		merged.Agents[name] = model
+	}
+	for name, model := range over.Agents {
+		merged.Agents[name] = model
+	}
+	return merged
+}
+
+// CommanderModel returns the model key the task's commander runs on: the
+// override from the task or iterator models block, or missionModel.
+func (t *Task) CommanderModel(missionModel string) string {
+	if o := t.modelOverride(); o != nil && o.Commander != "" {
+		return o.Commander
+	}
+	return missionModel
+}
+
+// AgentModels returns the task's per-agent model overrides by agent name,
+// or nil when there are none.
+func (t *Task) AgentModels() map[string]string {
+	if o := t.modelOverride(); o != nil && len(o.Agents) > 0 {
+		return o.Agents
+	}
+	return nil
+}
+
+func (t *Task) modelOverride() *ModelOverride {
+	var iter *ModelOverride
+	if t.Iterator != nil {
+		iter = t.Iterator.Models
+	}
+	return t.Models.merge(iter)
+}
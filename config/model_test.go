@@ -1,6 +1,8 @@
 package config_test
 
 import (
+	"time"
+
 	"squadron/config"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -118,6 +120,370 @@ storage {
 		})
 	})
 
+	Describe("openai_compatible parsing", func() {
+		It("parses an openai_compatible model block with api_key, base_url and aliases", func() {
+			hcl := `
+variable "unused" { default = "x" }
+model "vllm" {
+  provider = "openai_compatible"
+  base_url = "http://gpu-box:8000/v1"
+  api_key  = "token"
+  aliases = {
+    qwen = "Qwen/Qwen3-32B"
+  }
+}
+storage {
+  backend = "sqlite"
+}
+`
+			_, f := writeFixture("config.hcl", hcl)
+			cfg, err := config.LoadFile(f)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.Models[0].Provider).To(Equal(config.ProviderOpenAICompatible))
+			Expect(cfg.Models[0].BaseURL).To(Equal("http://gpu-box:8000/v1"))
+			Expect(cfg.Models[0].APIKey).To(Equal("token"))
+			Expect(cfg.Models[0].AvailableModels()).To(Equal(map[string]string{"qwen": "Qwen/Qwen3-32B"}))
+			Expect(cfg.Models[0].Validate()).To(Succeed())
+		})
+	})
+
+	Describe("scripted parsing", func() {
+		load := func(body string) (*config.Config, error) {
+			hcl := `
+variable "unused" { default = "x" }
+model "fake" {
+  provider = "scripted"
+` + body + `
+}
+storage {
+  backend = "sqlite"
+}
+`
+			_, f := writeFixture("config.hcl", hcl)
+			return config.LoadFile(f)
+		}
+
+		It("parses response blocks with tool calls", func() {
+			cfg, err := load(`
+  response {
+    match   = "Commander"
+    content = "Delegating."
+    tool_call "call_agent" {
+      input = { name = "worker", task = "Do it" }
+    }
+  }
+  response {
+    tool_call "task_complete" {
+      input = "{\"succeed\": true}"
+    }
+    repeat = true
+  }
+  response {
+    error = "503 Service Unavailable"
+  }
+`)
+			Expect(err).NotTo(HaveOccurred())
+			m := cfg.Models[0]
+			Expect(m.Validate()).To(Succeed())
+			Expect(m.AvailableModels()).To(HaveKeyWithValue("scripted", "scripted"))
+			Expect(m.Responses).To(HaveLen(3))
+			Expect(m.Responses[0].Match).To(Equal("Commander"))
+			Expect(m.Responses[0].Content).To(Equal("Delegating."))
+			Expect(m.Responses[0].ToolCalls).To(HaveLen(1))
+			Expect(m.Responses[0].ToolCalls[0].Name).To(Equal("call_agent"))
+			Expect(m.Responses[0].ToolCalls[0].Input).To(MatchJSON(`{"name": "worker", "task": "Do it"}`))
+			Expect(m.Responses[1].ToolCalls[0].Input).To(MatchJSON(`{"succeed": true}`))
+			Expect(m.Responses[1].Repeat).To(BeTrue())
+			Expect(m.Responses[2].Error).To(Equal("503 Service Unavailable"))
+		})
+
+		It("rejects tool call input that isn't JSON", func() {
+			_, err := load(`
+  response {
+    tool_call "task_complete" {
+      input = "not json"
+    }
+  }
+`)
+			Expect(err).To(MatchError(ContainSubstring("input is not valid JSON")))
+		})
+
+		It("rejects a scripted model without responses or with a bad match", func() {
+			m := config.Model{Name: "fake", Provider: config.ProviderScripted}
+			Expect(m.Validate()).To(MatchError(ContainSubstring("needs at least one response block")))
+			m.Responses = []config.ScriptedResponse{{Match: "("}}
+			Expect(m.Validate()).To(MatchError(ContainSubstring("response 1: invalid match")))
+		})
+
+		It("rejects response blocks on other providers", func() {
+			m := config.Model{
+				Name:      "anthropic",
+				Provider:  config.ProviderAnthropic,
+				APIKey:    "k",
+				Responses: []config.ScriptedResponse{{Content: "hi"}},
+			}
+			Expect(m.Validate()).To(MatchError(ContainSubstring("only allowed with provider 'scripted'")))
+		})
+	})
+
+	Describe("retry block", func() {
+		load := func(retry string) (*config.Model, error) {
+			hcl := minimalVarsHCL() + `
+model "anthropic" {
+  provider = "anthropic"
+  api_key  = vars.test_api_key
+` + retry + `
+}
+`
+			_, f := writeFixture("config.hcl", hcl)
+			cfg, err := config.LoadFile(f)
+			if err != nil {
+				return nil, err
+			}
+			return &cfg.Models[0], cfg.Models[0].Validate()
+		}
+
+		It("is nil when omitted", func() {
+			m, err := load("")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(m.Retry).To(BeNil())
+		})
+
+		It("parses attempts and backoff durations", func() {
+			m, err := load(`
+  retry {
+    max_attempts    = 4
+    initial_backoff = "500ms"
+    max_backoff     = "10s"
+  }`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(m.Retry.MaxAttempts).To(Equal(4))
+			Expect(m.Retry.GetInitialBackoff()).To(Equal(500 * time.Millisecond))
+			Expect(m.Retry.GetMaxBackoff()).To(Equal(10 * time.Second))
+		})
+
+		It("rejects invalid durations", func() {
+			_, err := load(`
+  retry {
+    initial_backoff = "soon"
+  }`)
+			Expect(err).To(MatchError(ContainSubstring(`retry: initial_backoff: invalid duration "soon"`)))
+		})
+
+		It("rejects an initial backoff above the max", func() {
+			_, err := load(`
+  retry {
+    initial_backoff = "1m"
+    max_backoff     = "10s"
+  }`)
+			Expect(err).To(MatchError(ContainSubstring("initial_backoff must not exceed max_backoff")))
+		})
+
+		It("rejects a second retry block", func() {
+			_, err := load(`
+  retry { max_attempts = 2 }
+  retry { max_attempts = 3 }`)
+			Expect(err).To(MatchError(ContainSubstring("only one retry block allowed")))
+		})
+	})
+
+	Describe("sampling block", func() {
+		load := func(sampling string) (*config.Model, error) {
+			hcl := minimalVarsHCL() + `
+model "anthropic" {
+  provider = "anthropic"
+  api_key  = vars.test_api_key
+` + sampling + `
+}
+`
+			_, f := writeFixture("config.hcl", hcl)
+			cfg, err := config.LoadFile(f)
+			if err != nil {
+				return nil, err
+			}
+			return &cfg.Models[0], cfg.Models[0].Validate()
+		}
+
+		It("parses sampling settings per model", func() {
+			m, err := load(`
+  sampling "claude_sonnet_4" {
+    temperature       = 0
+    top_p             = 0.9
+    max_output_tokens = 4096
+    seed              = 42
+  }`)
+			Expect(err).NotTo(HaveOccurred())
+			s := m.SamplingFor("claude_sonnet_4")
+			Expect(s).NotTo(BeNil())
+			Expect(*s.Temperature).To(Equal(0.0))
+			Expect(*s.TopP).To(Equal(0.9))
+			Expect(s.MaxOutputTokens).To(Equal(4096))
+			Expect(*s.Seed).To(Equal(int64(42)))
+			Expect(m.SamplingFor("claude_haiku_4_5")).To(BeNil())
+		})
+
+		It("rejects out-of-range settings", func() {
+			_, err := load(`
+  sampling "claude_sonnet_4" {
+    temperature = 3
+  }`)
+			Expect(err).To(MatchError(ContainSubstring("sampling 'claude_sonnet_4': temperature must be between 0 and 2")))
+
+			_, err = load(`
+  sampling "claude_sonnet_4" {
+    top_p = 0
+  }`)
+			Expect(err).To(MatchError(ContainSubstring("top_p must be greater than 0")))
+		})
+
+		It("rejects a model key that isn't a model of the config", func() {
+			_, err := load(`
+  sampling "gpt_4o" {
+    temperature = 0.5
+  }`)
+			Expect(err).To(MatchError(ContainSubstring("sampling 'gpt_4o': not a model of this config")))
+		})
+	})
+
+	Describe("rate_limit block", func() {
+		load := func(rateLimit string) (*config.Model, error) {
+			hcl := minimalVarsHCL() + `
+model "anthropic" {
+  provider = "anthropic"
+  api_key  = vars.test_api_key
+` + rateLimit + `
+}
+`
+			_, f := writeFixture("config.hcl", hcl)
+			cfg, err := config.LoadFile(f)
+			if err != nil {
+				return nil, err
+			}
+			return &cfg.Models[0], cfg.Models[0].Validate()
+		}
+
+		It("parses request and token limits", func() {
+			m, err := load(`
+  rate_limit {
+    requests_per_minute = 50
+    tokens_per_minute   = 40000
+  }`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(m.RateLimit.RequestsPerMinute).To(Equal(50))
+			Expect(m.RateLimit.TokensPerMinute).To(Equal(40000))
+		})
+
+		It("rejects an empty block", func() {
+			_, err := load(`
+  rate_limit {}`)
+			Expect(err).To(MatchError(ContainSubstring("rate_limit: set requests_per_minute, tokens_per_minute, or both")))
+		})
+
+		It("rejects negative limits", func() {
+			_, err := load(`
+  rate_limit { requests_per_minute = -1 }`)
+			Expect(err).To(MatchError(ContainSubstring("must be positive")))
+		})
+	})
+
+	Describe("compaction blocks", func() {
+		load := func(compaction string) (*config.Model, error) {
+			hcl := minimalVarsHCL() + `
+model "anthropic" {
+  provider = "anthropic"
+  api_key  = vars.test_api_key
+` + compaction + `
+}
+`
+			_, f := writeFixture("config.hcl", hcl)
+			cfg, err := config.LoadFile(f)
+			if err != nil {
+				return nil, err
+			}
+			return &cfg.Models[0], cfg.Models[0].Validate()
+		}
+
+		It("parses per-model defaults", func() {
+			m, err := load(`
+  compaction "claude_sonnet_4" {
+    token_limit    = 120000
+    turn_retention = 6
+    summary_model  = "claude_haiku_4_5"
+  }`)
+			Expect(err).NotTo(HaveOccurred())
+			c := m.CompactionFor("claude_sonnet_4")
+			Expect(c).NotTo(BeNil())
+			Expect(c.TokenLimit).To(Equal(120000))
+			Expect(c.TurnRetention).To(Equal(6))
+			Expect(c.SummaryModel).To(Equal("claude_haiku_4_5"))
+			Expect(m.CompactionFor("claude_haiku_4_5")).To(BeNil())
+		})
+
+		It("rejects a model this config does not serve", func() {
+			_, err := load(`
+  compaction "gpt_4o" {
+    token_limit    = 1000
+    turn_retention = 2
+  }`)
+			Expect(err).To(MatchError(ContainSubstring("compaction 'gpt_4o': not a model of this config")))
+		})
+
+		It("rejects an unknown summary model", func() {
+			_, f := writeFixture("config.hcl", minimalVarsHCL()+`
+model "anthropic" {
+  provider = "anthropic"
+  api_key  = vars.test_api_key
+  compaction "claude_sonnet_4" {
+    token_limit    = 1000
+    turn_retention = 2
+    summary_model  = "nope"
+  }
+}
+`)
+			cfg, err := config.LoadFile(f)
+			Expect(err).NotTo(HaveOccurred())
+			err = cfg.Validate()
+			Expect(err).To(MatchError(ContainSubstring("summary_model 'nope' not found in models")))
+		})
+	})
+
+	Describe("context_window", func() {
+		load := func(attr string) (*config.Model, error) {
+			hcl := minimalVarsHCL() + `
+model "anthropic" {
+  provider = "anthropic"
+  api_key  = vars.test_api_key
+` + attr + `
+}
+`
+			_, f := writeFixture("config.hcl", hcl)
+			cfg, err := config.LoadFile(f)
+			if err != nil {
+				return nil, err
+			}
+			return &cfg.Models[0], cfg.Models[0].Validate()
+		}
+
+		It("uses the registry's window unless overridden", func() {
+			m, err := load(`  context_window = { claude_opus_4_6 = 1000000 }`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(m.ContextWindow("claude-opus-4-6")).To(Equal(1000000))
+			Expect(m.ContextWindow("claude-haiku-4-5-20251001")).To(Equal(200000))
+			Expect(m.ContextWindow("claude-unknown")).To(Equal(0))
+		})
+
+		It("rejects models this config does not serve and non-positive sizes", func() {
+			_, err := load(`  context_window = { gpt_4o = 128000 }`)
+			Expect(err).To(MatchError(ContainSubstring("context_window 'gpt_4o': not a model of this config")))
+
+			_, err = load(`  context_window = { claude_opus_4_6 = 0 }`)
+			Expect(err).To(MatchError(ContainSubstring("must be a positive number of tokens")))
+
+			_, err = load(`  context_window = { claude_opus_4_6 = 1.5 }`)
+			Expect(err).To(MatchError(ContainSubstring("must be a whole number")))
+		})
+	})
+
 	Describe("Validate", func() {
 		It("rejects unsupported provider", func() {
 			hcl := minimalVarsHCL() + `
@@ -175,6 +541,40 @@ model "bad" {
 			Expect(err.Error()).To(ContainSubstring("aliases are required"))
 		})
 
+		It("accepts openai_compatible provider with or without api_key", func() {
+			m := config.Model{
+				Name:     "vllm",
+				Provider: config.ProviderOpenAICompatible,
+				Aliases:  map[string]string{"qwen": "Qwen/Qwen3-32B"},
+				BaseURL:  "http://localhost:8000/v1",
+			}
+			Expect(m.Validate()).To(Succeed())
+			m.APIKey = "token"
+			Expect(m.Validate()).To(Succeed())
+		})
+
+		It("rejects openai_compatible provider without base_url", func() {
+			m := config.Model{
+				Name:     "vllm",
+				Provider: config.ProviderOpenAICompatible,
+				Aliases:  map[string]string{"qwen": "Qwen/Qwen3-32B"},
+			}
+			err := m.Validate()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("base_url is required for provider 'openai_compatible'"))
+		})
+
+		It("rejects openai_compatible provider without aliases", func() {
+			m := config.Model{
+				Name:     "vllm",
+				Provider: config.ProviderOpenAICompatible,
+				BaseURL:  "http://localhost:8000/v1",
+			}
+			err := m.Validate()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("aliases are required"))
+		})
+
 		It("rejects cloud provider without api_key", func() {
 			m := config.Model{
 				Name:          "openai",
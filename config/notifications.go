@@ -0,0 +1,137 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Notification event names a webhook can subscribe to.
+const (
+	NotifyMissionStarted    = "mission_started"
+	NotifyMissionCompleted  = "mission_completed"
+	NotifyMissionFailed     = "mission_failed"
+	NotifyMissionCancelled  = "mission_cancelled"
+	NotifyTaskCompleted     = "task_completed"
+	NotifyTaskFailed        = "task_failed"
+	NotifyIterationRetrying = "iteration_retrying"
+	NotifyApprovalRequested = "task_approval_requested"
+)
+
+// NotificationEvents lists every event a webhook can subscribe to.
+var NotificationEvents = []string{
+	NotifyMissionStarted,
+	NotifyMissionCompleted,
+	NotifyMissionFailed,
+	NotifyMissionCancelled,
+	NotifyTaskCompleted,
+	NotifyTaskFailed,
+	NotifyIterationRetrying,
+	NotifyApprovalRequested,
+}
+
+// DefaultWebhookMaxRetries is used when a webhook does not set max_retries.
+const DefaultWebhookMaxRetries = 3
+
+// Notifications configures where mission lifecycle events are delivered.
+type Notifications struct {
+	Webhooks  []Webhook  `hcl:"webhook,block" json:"webhooks,omitempty"`
+	EventLogs []EventLog `hcl:"event_log,block" json:"eventLogs,omitempty"`
+}
+
+// EventLog writes every mission event to a file as NDJSON, alongside the
+// terminal or command center output — the same lines as `mission --events`.
+type EventLog struct {
+	Name string `hcl:"name,label" json:"name"`
+	// Path is relative to the working directory. The file is truncated at
+	// the start of each run unless Append is set.
+	Path   string `hcl:"path" json:"path"`
+	Append bool   `hcl:"append,optional" json:"append,omitempty"`
+}
+
+// Webhook posts a JSON payload to URL for each subscribed lifecycle event.
+type Webhook struct {
+	Name string `hcl:"name,label" json:"name"`
+	URL  string `hcl:"url" json:"url"`
+	// Secret, when set, signs each request body with HMAC-SHA256. The hex
+	// digest is sent as "X-Squadron-Signature: sha256=<digest>".
+	Secret string `hcl:"secret,optional" json:"-"`
+	// Events restricts delivery to the listed events. Empty means all events.
+	Events []string `hcl:"events,optional" json:"events,omitempty"`
+	// Headers are added to every request. Excluded from JSON because they
+	// commonly carry credentials.
+	Headers map[string]string `hcl:"headers,optional" json:"-"`
+	// MaxRetries bounds redelivery attempts after a network error, 429, or
+	// 5xx response. Nil means DefaultWebhookMaxRetries.
+	MaxRetries *int `hcl:"max_retries,optional" json:"maxRetries,omitempty"`
+}
+
+// Subscribed reports whether the webhook should receive the given event.
+func (w *Webhook) Subscribed(event string) bool {
+	if len(w.Events) == 0 {
+		return true
+	}
+	for _, e := range w.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// GetMaxRetries returns the configured retry count, falling back to the default.
+func (w *Webhook) GetMaxRetries() int {
+	if w.MaxRetries == nil {
+		return DefaultWebhookMaxRetries
+	}
+	return *w.MaxRetries
+}
+
+// Validate checks that every webhook is well formed.
+func (n *Notifications) Validate() error {
+	if n == nil {
+		return nil
+	}
+	seen := make(map[string]bool)
+	for _, w := range n.Webhooks {
+		if seen[w.Name] {
+			return fmt.Errorf("notifications: duplicate webhook '%s'", w.Name)
+		}
+		seen[w.Name] = true
+		if !strings.HasPrefix(w.URL, "http://") && !strings.HasPrefix(w.URL, "https://") {
+			return fmt.Errorf("notifications: webhook '%s': url must start with http:// or https://", w.Name)
+		}
+		if w.MaxRetries != nil && *w.MaxRetries < 0 {
+			return fmt.Errorf("notifications: webhook '%s': max_retries must be >= 0", w.Name)
+		}
+		for _, e := range w.Events {
+			if !isNotificationEvent(e) {
+				return fmt.Errorf("notifications: webhook '%s': unknown event '%s' (expected one of %s)", w.Name, e, strings.Join(NotificationEvents, ", "))
+			}
+		}
+	}
+	seen = make(map[string]bool)
+	paths := make(map[string]string)
+	for _, l := range n.EventLogs {
+		if seen[l.Name] {
+			return fmt.Errorf("notifications: duplicate event_log '%s'", l.Name)
+		}
+		seen[l.Name] = true
+		if strings.TrimSpace(l.Path) == "" {
+			return fmt.Errorf("notifications: event_log '%s': path must not be empty", l.Name)
+		}
+		if other, ok := paths[l.Path]; ok {
+			return fmt.Errorf("notifications: event_log '%s': path '%s' is already used by event_log '%s'", l.Name, l.Path, other)
+		}
+		paths[l.Path] = l.Name
+	}
+	return nil
+}
+
+func isNotificationEvent(event string) bool {
+	for _, e := range NotificationEvents {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+package config_test
+
+import (
+	"squadron/config"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Mission notifications", func() {
+	missionWith := func(notifications string) string {
+		return fullBaseHCL() + `
+mission "m" {
+  commander { model = models.anthropic.claude_sonnet_4 }
+  agents = [agents.test_agent]
+` + notifications + `
+  task "work" { objective = "Do work" }
+}
+`
+	}
+
+	It("parses webhooks with events, headers, and a secret", func() {
+		_, f := writeFixture("notify.hcl", missionWith(`
+  notifications {
+    webhook "ops" {
+      url         = "https://hooks.example.com/squadron"
+      secret      = vars.test_api_key
+      events      = ["mission_failed", "task_failed"]
+      headers     = { "X-Team" = "ops" }
+      max_retries = 5
+    }
+    webhook "audit" {
+      url = "http://localhost:9000/events"
+    }
+  }`))
+		cfg, err := config.LoadFile(f)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Validate()).To(Succeed())
+
+		n := cfg.Missions[0].Notifications
+		Expect(n).NotTo(BeNil())
+		Expect(n.Webhooks).To(HaveLen(2))
+
+		ops := n.Webhooks[0]
+		Expect(ops.Name).To(Equal("ops"))
+		Expect(ops.Secret).To(Equal("test-key-123"))
+		Expect(ops.Headers).To(HaveKeyWithValue("X-Team", "ops"))
+		Expect(ops.GetMaxRetries()).To(Equal(5))
+		Expect(ops.Subscribed("task_failed")).To(BeTrue())
+		Expect(ops.Subscribed("task_completed")).To(BeFalse())
+
+		audit := n.Webhooks[1]
+		Expect(audit.GetMaxRetries()).To(Equal(config.DefaultWebhookMaxRetries))
+		Expect(audit.Subscribed("iteration_retrying")).To(BeTrue())
+	})
+
+	It("rejects unknown events", func() {
+		_, f := writeFixture("notify-bad-event.hcl", missionWith(`
+  notifications {
+    webhook "ops" {
+      url    = "https://hooks.example.com"
+      events = ["task_started"]
+    }
+  }`))
+		cfg, err := config.LoadFile(f)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Validate()).To(MatchError(ContainSubstring("unknown event 'task_started'")))
+	})
+
+	It("rejects non-HTTP urls and duplicate names", func() {
+		_, f := writeFixture("notify-bad-url.hcl", missionWith(`
+  notifications {
+    webhook "ops" { url = "ftp://example.com" }
+  }`))
+		cfg, err := config.LoadFile(f)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Validate()).To(MatchError(ContainSubstring("url must start with http:// or https://")))
+
+		_, f = writeFixture("notify-dup.hcl", missionWith(`
+  notifications {
+    webhook "ops" { url = "https://a.example.com" }
+    webhook "ops" { url = "https://b.example.com" }
+  }`))
+		cfg, err = config.LoadFile(f)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Validate()).To(MatchError(ContainSubstring("duplicate webhook 'ops'")))
+	})
+
+	It("parses event logs and rejects a path used twice", func() {
+		_, f := writeFixture("notify-event-log.hcl", missionWith(`
+  notifications {
+    webhook "ops" { url = "https://hooks.example.com" }
+    event_log "audit" {
+      path   = "logs/audit.ndjson"
+      append = true
+    }
+  }`))
+		cfg, err := config.LoadFile(f)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Validate()).To(Succeed())
+		Expect(cfg.Missions[0].Notifications.EventLogs).To(Equal([]config.EventLog{
+			{Name: "audit", Path: "logs/audit.ndjson", Append: true},
+		}))
+
+		_, f = writeFixture("notify-event-log-dup.hcl", missionWith(`
+  notifications {
+    event_log "a" { path = "events.ndjson" }
+    event_log "b" { path = "events.ndjson" }
+  }`))
+		cfg, err = config.LoadFile(f)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Validate()).To(MatchError(ContainSubstring("path 'events.ndjson' is already used by event_log 'a'")))
+	})
+})
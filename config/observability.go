@@ -0,0 +1,68 @@
+package config
+
+import (
+	"fmt"
+
+	"squadron/tracing"
+)
+
+// ObservabilityConfig configures telemetry export. nil when the
+// observability block is absent.
+type ObservabilityConfig struct {
+	Tracing *TracingConfig `hcl:"tracing,block"`
+}
+
+// TracingConfig selects the OpenTelemetry exporter for mission traces.
+type TracingConfig struct {
+	Exporter    string            `hcl:"exporter"`              // "otlp_grpc", "otlp_http", or "file"
+	Endpoint    string            `hcl:"endpoint,optional"`     // Collector host:port; defaults to the OTEL_EXPORTER_OTLP_* env vars
+	Insecure    bool              `hcl:"insecure,optional"`     // Plain-text connection to the collector
+	Headers     map[string]string `hcl:"headers,optional"`      // Extra headers sent to the collector
+	Path        string            `hcl:"path,optional"`         // File exporter output (default: ".squadron/traces.jsonl")
+	ServiceName string            `hcl:"service_name,optional"` // service.name resource attribute (default: "squadron")
+	SampleRatio *float64          `hcl:"sample_ratio,optional"` // Fraction of missions traced (default: 1)
+}
+
+// Defaults fills in default values for unset fields
+func (t *TracingConfig) Defaults() {
+	if t.ServiceName == "" {
+		t.ServiceName = "squadron"
+	}
+	if t.Exporter == tracing.ExporterFile && t.Path == "" {
+		t.Path = ".squadron/traces.jsonl"
+	}
+}
+
+// Validate checks the exporter choice and sample ratio.
+func (t *TracingConfig) Validate() error {
+	switch t.Exporter {
+	case tracing.ExporterOTLPGRPC, tracing.ExporterOTLPHTTP, tracing.ExporterFile:
+	default:
+		return fmt.Errorf("unknown exporter '%s' (expected '%s', '%s', or '%s')", t.Exporter, tracing.ExporterOTLPGRPC, tracing.ExporterOTLPHTTP, tracing.ExporterFile)
+	}
+	if t.SampleRatio != nil && (*t.SampleRatio <= 0 || *t.SampleRatio > 1) {
+		return fmt.Errorf("sample_ratio must be greater than 0 and at most 1")
+	}
+	return nil
+}
+
+// TracingOptions returns the tracing exporter settings, or the zero value
+// (tracing disabled) when no tracing block is configured.
+func (o *ObservabilityConfig) TracingOptions() tracing.Options {
+	if o == nil || o.Tracing == nil {
+		return tracing.Options{}
+	}
+	t := o.Tracing
+	opts := tracing.Options{
+		Exporter:    t.Exporter,
+		Endpoint:    t.Endpoint,
+		Insecure:    t.Insecure,
+		Headers:     t.Headers,
+		Path:        t.Path,
+		ServiceName: t.ServiceName,
+	}
+	if t.SampleRatio != nil {
+		opts.SampleRatio = *t.SampleRatio
+	}
+	return opts
+}
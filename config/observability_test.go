@@ -0,0 +1,86 @@
+package config_test
+
+import (
+	"path/filepath"
+
+	"squadron/config"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Observability Config", func() {
+
+	It("is nil when no observability block is present", func() {
+		_, f := writeFixture("no-observability.hcl", `
+variable "x" {
+  default = "y"
+}
+`)
+		cfg, err := config.LoadFile(f)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Observability).To(BeNil())
+		Expect(cfg.Observability.TracingOptions().Exporter).To(BeEmpty())
+	})
+
+	It("parses an OTLP tracing exporter", func() {
+		_, f := writeFixture("observability-otlp.hcl", `
+observability {
+  tracing {
+    exporter     = "otlp_http"
+    endpoint     = "collector:4318"
+    insecure     = true
+    headers      = { "x-api-key" = "abc" }
+    sample_ratio = 0.25
+  }
+}
+`)
+		cfg, err := config.LoadFile(f)
+		Expect(err).NotTo(HaveOccurred())
+		opts := cfg.Observability.TracingOptions()
+		Expect(opts.Exporter).To(Equal("otlp_http"))
+		Expect(opts.Endpoint).To(Equal("collector:4318"))
+		Expect(opts.Insecure).To(BeTrue())
+		Expect(opts.Headers).To(HaveKeyWithValue("x-api-key", "abc"))
+		Expect(opts.ServiceName).To(Equal("squadron"))
+		Expect(opts.SampleRatio).To(Equal(0.25))
+	})
+
+	It("defaults the file exporter path next to the config", func() {
+		dir, f := writeFixture("observability-file.hcl", `
+observability {
+  tracing {
+    exporter = "file"
+  }
+}
+`)
+		cfg, err := config.LoadFile(f)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Observability.Tracing.Path).To(Equal(filepath.Join(dir, ".squadron", "traces.jsonl")))
+	})
+
+	It("rejects an unknown exporter", func() {
+		_, f := writeFixture("observability-unknown.hcl", `
+observability {
+  tracing {
+    exporter = "zipkin"
+  }
+}
+`)
+		_, err := config.LoadFile(f)
+		Expect(err).To(MatchError(ContainSubstring("unknown exporter 'zipkin'")))
+	})
+
+	It("rejects an out-of-range sample_ratio", func() {
+		_, f := writeFixture("observability-ratio.hcl", `
+observability {
+  tracing {
+    exporter     = "otlp_grpc"
+    sample_ratio = 2
+  }
+}
+`)
+		_, err := config.LoadFile(f)
+		Expect(err).To(MatchError(ContainSubstring("sample_ratio")))
+	})
+})
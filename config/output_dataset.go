@@ -0,0 +1,122 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// taskOutputRef reports whether an iterator's dataset is written as
+// tasks.<task>.output.<field>[.<field>...] and returns the task and the
+// field path. A for_each instance is read as tasks.<task>["key"].output.
+func taskOutputRef(expr hcl.Expression) (string, []string, bool, error) {
+	trav, diags := hcl.AbsTraversalForExpr(expr)
+	if diags.HasErrors() || trav.RootName() != "tasks" || len(trav) < 3 {
+		return "", nil, false, nil
+	}
+	task, ok := trav[1].(hcl.TraverseAttr)
+	if !ok {
+		return "", nil, false, nil
+	}
+	name := task.Name
+	rest := trav[2:]
+	if idx, ok := rest[0].(hcl.TraverseIndex); ok {
+		if idx.Key.IsNull() || !idx.Key.IsKnown() || idx.Key.Type() != cty.String {
+			return "", nil, false, fmt.Errorf("for_each tasks are read as tasks.%s[\"key\"].output.<field>", task.Name)
+		}
+		name = InstanceName(task.Name, idx.Key.AsString())
+		rest = rest[1:]
+	}
+	if len(rest) == 0 {
+		return "", nil, false, nil
+	}
+	if attr, ok := rest[0].(hcl.TraverseAttr); !ok || attr.Name != "output" {
+		return "", nil, false, nil
+	}
+	var path []string
+	for _, step := range rest[1:] {
+		attr, ok := step.(hcl.TraverseAttr)
+		if !ok {
+			return "", nil, false, fmt.Errorf("tasks.%s.output: only field names are supported, like tasks.%s.output.items", name, name)
+		}
+		path = append(path, attr.Name)
+	}
+	if len(path) == 0 {
+		return "", nil, false, fmt.Errorf("tasks.%s.output: name the array field to iterate, like tasks.%s.output.items", name, name)
+	}
+	return name, path, true, nil
+}
+
+// OutputDatasetName names the dataset an iterator over a dependency's output
+// field reads, e.g. "extract_leads.output.items".
+func OutputDatasetName(task string, path []string) string {
+	return task + ".output." + strings.Join(path, ".")
+}
+
+// addOutputDatasets adds a dataset for every iterator that maps over a
+// dependency's output field. The runner fills it from that output just
+// before the iterating task starts.
+func (w *Mission) addOutputDatasets() {
+	seen := make(map[string]bool)
+	for _, ds := range w.Datasets {
+		seen[ds.Name] = true
+	}
+	for _, t := range w.Tasks {
+		it := t.Iterator
+		if it == nil || it.FromTask == "" || seen[it.Dataset] {
+			continue
+		}
+		seen[it.Dataset] = true
+		w.Datasets = append(w.Datasets, Dataset{
+			Name:        it.Dataset,
+			Description: fmt.Sprintf("Items of task '%s' output field %s", it.FromTask, strings.Join(it.FromPath, ".")),
+			FromTask:    it.FromTask,
+			FromPath:    it.FromPath,
+		})
+	}
+}
+
+// validateOutputIterators checks that a task iterating over a dependency's
+// output depends on that task, and that the field is an array in its output
+// schema.
+func (w *Mission) validateOutputIterators() error {
+	for _, t := range w.Tasks {
+		it := t.Iterator
+		if it == nil || it.FromTask == "" {
+			continue
+		}
+		if !w.upstreamTasks(t.Name)[it.FromTask] {
+			return fmt.Errorf("task '%s': iterator reads tasks.%s.output, but '%s' is not an upstream dependency (add it to depends_on)", t.Name, it.FromTask, it.FromTask)
+		}
+		src := w.GetTaskByName(it.FromTask)
+		if src.Output == nil {
+			return fmt.Errorf("task '%s': iterator reads tasks.%s.output, but '%s' has no output schema", t.Name, it.FromTask, it.FromTask)
+		}
+		fields := src.Output.Fields
+		for i, name := range it.FromPath {
+			var field *OutputField
+			for j := range fields {
+				if fields[j].Name == name {
+					field = &fields[j]
+					break
+				}
+			}
+			if field == nil {
+				return fmt.Errorf("task '%s': iterator reads %s, but task '%s' has no output field '%s'", t.Name, it.Dataset, it.FromTask, strings.Join(it.FromPath[:i+1], "."))
+			}
+			if i == len(it.FromPath)-1 {
+				if field.Type != "array" {
+					return fmt.Errorf("task '%s': iterator reads %s, which is a %s, not an array", t.Name, it.Dataset, field.Type)
+				}
+				break
+			}
+			if field.Type != "object" {
+				return fmt.Errorf("task '%s': iterator reads %s, but '%s' is a %s, not an object", t.Name, it.Dataset, strings.Join(it.FromPath[:i+1], "."), field.Type)
+			}
+			fields = field.Properties
+		}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package config_test
+
+import (
+	"squadron/config"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Iterating over a dependency's output", func() {
+	outputMissionHCL := func(itemsType, dependsOn, dataset string) string {
+		return fullBaseHCL() + `
+mission "leads" {
+  commander {
+    model = models.anthropic.claude_sonnet_4
+  }
+  agents = [agents.test_agent]
+  task "extract_leads" {
+    objective = "Extract leads"
+    output {
+      field "items" {
+        type = "` + itemsType + `"
+      }
+      field "source" {
+        type = "string"
+      }
+    }
+  }
+  task "enrich" {
+    objective = "Enrich ${item.name}"
+    ` + dependsOn + `
+    iterator {
+      dataset  = ` + dataset + `
+      parallel = true
+    }
+  }
+}
+`
+	}
+
+	It("adds a dataset filled from the dependency's output field", func() {
+		_, f := writeFixture("config.hcl", outputMissionHCL("array", "depends_on = [tasks.extract_leads]", "tasks.extract_leads.output.items"))
+		cfg, err := config.LoadFile(f)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Validate()).To(Succeed())
+
+		m := cfg.Missions[0]
+		it := m.GetTaskByName("enrich").Iterator
+		Expect(it.Dataset).To(Equal("extract_leads.output.items"))
+		Expect(it.FromTask).To(Equal("extract_leads"))
+		Expect(it.FromPath).To(Equal([]string{"items"}))
+
+		Expect(m.Datasets).To(HaveLen(1))
+		Expect(m.Datasets[0].Name).To(Equal("extract_leads.output.items"))
+		Expect(m.Datasets[0].FromTask).To(Equal("extract_leads"))
+	})
+
+	DescribeTable("rejects iterators that can't read an array from the dependency",
+		func(itemsType, dependsOn, dataset, msg string) {
+			_, f := writeFixture("config.hcl", outputMissionHCL(itemsType, dependsOn, dataset))
+			cfg, err := config.LoadFile(f)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.Validate()).To(MatchError(ContainSubstring(msg)))
+		},
+		Entry("without depends_on", "array", "", "tasks.extract_leads.output.items", "is not an upstream dependency"),
+		Entry("a missing field", "array", "depends_on = [tasks.extract_leads]", "tasks.extract_leads.output.leads", "has no output field 'leads'"),
+		Entry("a non-array field", "array", "depends_on = [tasks.extract_leads]", "tasks.extract_leads.output.source", "which is a string, not an array"),
+		Entry("a field typed as something else", "object", "depends_on = [tasks.extract_leads]", "tasks.extract_leads.output.items", "which is a object, not an array"),
+	)
+
+	It("requires a field name after output", func() {
+		hcl := outputMissionHCL("array", "depends_on = [tasks.extract_leads]", "tasks.extract_leads.output")
+		_, f := writeFixture("config.hcl", hcl)
+		_, err := config.LoadFile(f)
+		Expect(err).To(MatchError(ContainSubstring("name the array field to iterate")))
+	})
+})
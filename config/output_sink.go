@@ -0,0 +1,128 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Output sink types
+const (
+	OutputSinkHTTP   = "http"
+	OutputSinkFile   = "file"
+	OutputSinkSQS    = "sqs"
+	OutputSinkPubSub = "pubsub"
+)
+
+// DefaultOutputSinkMaxRetries is used when a network sink does not set
+// max_retries.
+const DefaultOutputSinkMaxRetries = 3
+
+// OutputSink delivers a task's structured output to an external system as
+// soon as the commander submits it — once per task, or once per item on
+// iterated tasks — so downstream systems don't have to poll the store.
+type OutputSink struct {
+	Name string `json:"name"`
+	Type string `json:"type"` // http, file, sqs, or pubsub
+	// URL is the endpoint (http) or queue URL (sqs).
+	URL string `json:"url,omitempty"`
+	// Headers are added to every http request. Excluded from JSON because
+	// they commonly carry credentials.
+	Headers map[string]string `json:"-"`
+	// Secret, when set, signs each http request body the same way webhooks
+	// do ("X-Squadron-Signature: sha256=<digest>").
+	Secret string `json:"-"`
+	// Path is the file outputs are appended to, one JSON object per line
+	// (absolute after config load).
+	Path string `json:"path,omitempty"`
+	// Region overrides the region parsed from an sqs queue URL.
+	Region string `json:"region,omitempty"`
+	// Topic is the pubsub topic, as projects/<project>/topics/<topic>.
+	Topic string `json:"topic,omitempty"`
+	// MaxRetries bounds redelivery attempts of network sinks after a
+	// network error, 429, or 5xx response. Nil means
+	// DefaultOutputSinkMaxRetries.
+	MaxRetries *int `json:"maxRetries,omitempty"`
+}
+
+// GetMaxRetries returns the configured retry count, falling back to the default.
+func (s *OutputSink) GetMaxRetries() int {
+	if s.MaxRetries == nil {
+		return DefaultOutputSinkMaxRetries
+	}
+	return *s.MaxRetries
+}
+
+// Validate checks that the sink has the settings its type needs, and none
+// that belong to another type.
+func (s *OutputSink) Validate() error {
+	set := map[string]bool{
+		"url":     s.URL != "",
+		"headers": len(s.Headers) > 0,
+		"secret":  s.Secret != "",
+		"path":    s.Path != "",
+		"region":  s.Region != "",
+		"topic":   s.Topic != "",
+	}
+	var allowed []string
+	switch s.Type {
+	case OutputSinkHTTP:
+		allowed = []string{"url", "headers", "secret"}
+		if !strings.HasPrefix(s.URL, "http://") && !strings.HasPrefix(s.URL, "https://") {
+			return fmt.Errorf("url must start with http:// or https://")
+		}
+	case OutputSinkFile:
+		allowed = []string{"path"}
+		if strings.TrimSpace(s.Path) == "" {
+			return fmt.Errorf("file sink requires path")
+		}
+	case OutputSinkSQS:
+		allowed = []string{"url", "region"}
+		if !strings.HasPrefix(s.URL, "https://") {
+			return fmt.Errorf("url must be an https:// queue URL")
+		}
+		if s.Region == "" && SQSRegionFromURL(s.URL) == "" {
+			return fmt.Errorf("region is required when it can't be read from the queue URL")
+		}
+	case OutputSinkPubSub:
+		allowed = []string{"topic"}
+		parts := strings.Split(s.Topic, "/")
+		if len(parts) != 4 || parts[0] != "projects" || parts[1] == "" || parts[2] != "topics" || parts[3] == "" {
+			return fmt.Errorf("topic must look like projects/<project>/topics/<topic>")
+		}
+	case "":
+		return fmt.Errorf("type is required")
+	default:
+		return fmt.Errorf("unknown type '%s' (expected http, file, sqs, or pubsub)", s.Type)
+	}
+
+	for _, name := range []string{"url", "headers", "secret", "path", "region", "topic"} {
+		if set[name] && !slices.Contains(allowed, name) {
+			return fmt.Errorf("%s is not supported on %s sinks", name, s.Type)
+		}
+	}
+	if s.MaxRetries != nil {
+		if s.Type == OutputSinkFile {
+			return fmt.Errorf("max_retries is not supported on file sinks")
+		}
+		if *s.MaxRetries < 0 {
+			return fmt.Errorf("max_retries must be >= 0")
+		}
+	}
+	return nil
+}
+
+// SQSRegionFromURL reads the region out of a queue URL such as
+// https://sqs.us-east-1.amazonaws.com/123456789012/results. It returns ""
+// for URLs of any other shape.
+func SQSRegionFromURL(queueURL string) string {
+	host := strings.TrimPrefix(queueURL, "https://")
+	if i := strings.IndexByte(host, '/'); i >= 0 {
+		host = host[:i]
+	}
+	parts := strings.Split(host, ".")
+	if len(parts) < 4 || parts[0] != "sqs" || parts[len(parts)-2] != "amazonaws" {
+		return ""
+	}
+	return parts[1]
+}
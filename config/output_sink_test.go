@@ -0,0 +1,141 @@
+package config_test
+
+import (
+	"path/filepath"
+
+	"squadron/config"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Output sinks", func() {
+	load := func(task string) (*config.Config, string, error) {
+		hcl := fullBaseHCL() + `
+variable "results_token" {
+  default = "tok"
+}
+
+mission "report" {
+  commander {
+    model = models.anthropic.claude_sonnet_4
+  }
+  agents = [agents.test_agent]
+` + task + `
+}
+`
+		dir, f := writeFixture("config.hcl", hcl)
+		cfg, err := config.LoadFile(f)
+		return cfg, dir, err
+	}
+
+	It("parses output_sink blocks and resolves file paths against the config dir", func() {
+		cfg, dir, err := load(`
+  task "extract" {
+    objective = "Extract the title"
+    output = {
+      title = string("The title", true)
+    }
+
+    output_sink "api" {
+      type        = "http"
+      url         = "https://example.com/results"
+      headers     = { Authorization = "Bearer ${vars.results_token}" }
+      secret      = "s3cret"
+      max_retries = 5
+    }
+
+    output_sink "log" {
+      type = "file"
+      path = "out/results.jsonl"
+    }
+
+    output_sink "queue" {
+      type = "sqs"
+      url  = "https://sqs.eu-west-1.amazonaws.com/123456789012/results"
+    }
+
+    output_sink "topic" {
+      type  = "pubsub"
+      topic = "projects/acme/topics/results"
+    }
+  }`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Validate()).To(Succeed())
+
+		sinks := cfg.Missions[0].Tasks[0].OutputSinks
+		Expect(sinks).To(HaveLen(4))
+		Expect(sinks[0].Name).To(Equal("api"))
+		Expect(sinks[0].Type).To(Equal(config.OutputSinkHTTP))
+		Expect(sinks[0].Headers).To(Equal(map[string]string{"Authorization": "Bearer tok"}))
+		Expect(sinks[0].Secret).To(Equal("s3cret"))
+		Expect(sinks[0].GetMaxRetries()).To(Equal(5))
+		Expect(sinks[1].Path).To(Equal(filepath.Join(dir, "out", "results.jsonl")))
+		Expect(sinks[1].GetMaxRetries()).To(Equal(config.DefaultOutputSinkMaxRetries))
+		Expect(config.SQSRegionFromURL(sinks[2].URL)).To(Equal("eu-west-1"))
+		Expect(sinks[3].Topic).To(Equal("projects/acme/topics/results"))
+	})
+
+	DescribeTable("rejects invalid sinks",
+		func(sink, want string) {
+			cfg, _, err := load(`
+  task "extract" {
+    objective = "Extract the title"
+    output = {
+      title = string("The title", true)
+    }
+` + sink + `
+  }`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.Validate()).To(MatchError(ContainSubstring(want)))
+		},
+		Entry("unknown type", `output_sink "x" { type = "kafka" }`, "output_sink 'x': unknown type 'kafka'"),
+		Entry("http without a url", `output_sink "x" { type = "http" }`, "url must start with http:// or https://"),
+		Entry("file without a path", `output_sink "x" { type = "file" }`, "file sink requires path"),
+		Entry("setting of another type", `
+    output_sink "x" {
+      type = "file"
+      path = "out.jsonl"
+      url  = "https://example.com"
+    }`, "url is not supported on file sinks"),
+		Entry("retries on a file sink", `
+    output_sink "x" {
+      type        = "file"
+      path        = "out.jsonl"
+      max_retries = 1
+    }`, "max_retries is not supported on file sinks"),
+		Entry("sqs queue without a region", `
+    output_sink "x" {
+      type = "sqs"
+      url  = "https://queue.example.com/results"
+    }`, "region is required"),
+		Entry("malformed pubsub topic", `
+    output_sink "x" {
+      type  = "pubsub"
+      topic = "results"
+    }`, "topic must look like projects/<project>/topics/<topic>"),
+		Entry("duplicate names", `
+    output_sink "x" {
+      type = "file"
+      path = "a.jsonl"
+    }
+    output_sink "x" {
+      type = "file"
+      path = "b.jsonl"
+    }`, "duplicate output_sink 'x'"),
+	)
+
+	It("requires an output schema", func() {
+		cfg, _, err := load(`
+  task "extract" {
+    objective = "Extract the title"
+
+    output_sink "log" {
+      type = "file"
+      path = "out.jsonl"
+    }
+  }`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Validate()).To(MatchError(ContainSubstring("output_sink requires an output schema")))
+	})
+})
@@ -40,7 +40,7 @@ func (p *Packet) Validate() error {
 	// Reuse the same naming rules as shared memory slots so packet names
 	// can't accidentally collide with reserved slot names or break the
 	// filesystem layout.
-	if p.Name == MemorySlotName || p.Name == ScratchpadSlotName {
+	if isReservedSlotName(p.Name) {
 		return fmt.Errorf("name %q is reserved for mission-scoped slots", p.Name)
 	}
 	if err := validateSlotName(p.Name); err != nil {
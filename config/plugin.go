@@ -5,6 +5,9 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"time"
+
+	"squadron/plugin"
 )
 
 // Plugin represents a plugin configuration
@@ -13,6 +16,13 @@ type Plugin struct {
 	Source   string            `hcl:"source,optional"`
 	Version  string            `hcl:"version"`
 	Settings map[string]string `hcl:"-"` // Parsed manually from settings block
+	// Timeout is a Go duration bounding how long the plugin may take to
+	// start and to finish configuring. Default: plugin.DefaultTimeout.
+	Timeout string `hcl:"-"`
+	// ToolTimeout is a Go duration bounding each call to the plugin's
+	// tools; ToolTimeouts overrides it per tool name. Unset means no limit.
+	ToolTimeout  string            `hcl:"-"`
+	ToolTimeouts map[string]string `hcl:"-"`
 }
 
 // semverRegex matches semantic versioning strings like v1.0.0, v0.1.0-beta, etc.
@@ -73,6 +83,30 @@ func (p *Plugin) IsLocal() bool {
 	return p.Version == "local"
 }
 
+// GetTimeout returns how long the plugin may take to start and to finish
+// configuring.
+func (p *Plugin) GetTimeout() time.Duration {
+	if p.Timeout == "" {
+		return plugin.DefaultTimeout
+	}
+	d, _ := time.ParseDuration(p.Timeout)
+	return d
+}
+
+// GetToolTimeouts returns the call timeout for the plugin's tools and the
+// per-tool overrides. Zero means no limit.
+func (p *Plugin) GetToolTimeouts() (time.Duration, map[string]time.Duration) {
+	var def time.Duration
+	if p.ToolTimeout != "" {
+		def, _ = time.ParseDuration(p.ToolTimeout)
+	}
+	byTool := make(map[string]time.Duration, len(p.ToolTimeouts))
+	for name, v := range p.ToolTimeouts {
+		byTool[name], _ = time.ParseDuration(v)
+	}
+	return def, byTool
+}
+
 // GetVersion returns the version string, normalizing it if needed
 func (p *Plugin) GetVersion() string {
 	return p.Version
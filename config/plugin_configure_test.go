@@ -1,8 +1,12 @@
 package config
 
 import (
+	"errors"
 	"strings"
 	"testing"
+	"time"
+
+	"squadron/plugin"
 )
 
 type fakeConfigurer struct {
@@ -55,3 +59,26 @@ func TestConfigurePlugin_RejectsEmptyValues(t *testing.T) {
 	}
 }
 
+
+type timingOutConfigurer struct {
+	calls int
+}
+
+func (f *timingOutConfigurer) Configure(settings map[string]string) error {
+	f.calls++
+	return &plugin.TimeoutError{Plugin: "slow", Stage: "finish configuring", Timeout: time.Second}
+}
+
+// A plugin that hit its plugin_timeout has been killed, so retrying would
+// only fail against a dead process and bury the actionable error.
+func TestConfigurePlugin_DoesNotRetryTimeouts(t *testing.T) {
+	fc := &timingOutConfigurer{}
+	err := configurePlugin(fc, "slow", nil)
+	var timeoutErr *plugin.TimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("expected a *plugin.TimeoutError, got %v", err)
+	}
+	if fc.calls != 1 {
+		t.Errorf("Configure call count = %d, want 1", fc.calls)
+	}
+}
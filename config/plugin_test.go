@@ -2,8 +2,10 @@ package config_test
 
 import (
 	"path/filepath"
+	"time"
 
 	"squadron/config"
+	"squadron/plugin"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -42,6 +44,71 @@ plugin "configured" {
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("configured"))
 		})
+
+		It("rejects a plugin_timeout that isn't a positive duration", func() {
+			hcl := minimalVarsHCL() + `
+plugin "slow" {
+  source         = "github.com/example/slow"
+  version        = "v1.0.0"
+  plugin_timeout = "soon"
+}
+`
+			_, f := writeFixture("config.hcl", hcl)
+			_, err := config.LoadFile(f)
+			Expect(err).To(MatchError(ContainSubstring(`plugin 'slow' plugin_timeout "soon"`)))
+		})
+
+		It("rejects tool timeouts that aren't positive durations", func() {
+			hcl := minimalVarsHCL() + `
+plugin "slow" {
+  source       = "github.com/example/slow"
+  version      = "v1.0.0"
+  tool_timeout = "0s"
+}
+`
+			_, f := writeFixture("config.hcl", hcl)
+			_, err := config.LoadFile(f)
+			Expect(err).To(MatchError(ContainSubstring("plugin 'slow' tool_timeout must be > 0")))
+
+			hcl = minimalVarsHCL() + `
+plugin "slow" {
+  source        = "github.com/example/slow"
+  version       = "v1.0.0"
+  tool_timeouts = { browser_navigate = "later" }
+}
+`
+			_, f = writeFixture("config.hcl", hcl)
+			_, err = config.LoadFile(f)
+			Expect(err).To(MatchError(ContainSubstring(`plugin 'slow' tool_timeouts 'browser_navigate' "later"`)))
+		})
+	})
+
+	Describe("GetTimeout", func() {
+		It("defaults to the plugin package's timeout", func() {
+			p := config.Plugin{Name: "myplugin"}
+			Expect(p.GetTimeout()).To(Equal(plugin.DefaultTimeout))
+		})
+
+		It("parses plugin_timeout", func() {
+			p := config.Plugin{Name: "myplugin", Timeout: "15m"}
+			Expect(p.GetTimeout()).To(Equal(15 * time.Minute))
+		})
+	})
+
+	Describe("GetToolTimeouts", func() {
+		It("has no limit by default", func() {
+			p := config.Plugin{Name: "myplugin"}
+			def, byTool := p.GetToolTimeouts()
+			Expect(def).To(BeZero())
+			Expect(byTool).To(BeEmpty())
+		})
+
+		It("parses tool_timeout and per-tool overrides", func() {
+			p := config.Plugin{Name: "myplugin", ToolTimeout: "2m", ToolTimeouts: map[string]string{"browser_navigate": "30s"}}
+			def, byTool := p.GetToolTimeouts()
+			Expect(def).To(Equal(2 * time.Minute))
+			Expect(byTool).To(Equal(map[string]time.Duration{"browser_navigate": 30 * time.Second}))
+		})
 	})
 
 	Describe("Validate", func() {
@@ -0,0 +1,127 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// Policy rule actions
+const (
+	PolicyAllow           = "allow"
+	PolicyDeny            = "deny"
+	PolicyRequireApproval = "require_approval"
+)
+
+// Policies are tool-call rules for a mission's agents. Rules are checked in
+// order before every call to a configured tool; the first matching rule
+// decides, and calls no rule matches are allowed.
+type Policies struct {
+	Rules []PolicyRule `hcl:"rule,block" json:"rules,omitempty"`
+}
+
+// PolicyRule matches tool calls by tool, agent, and (optionally) target host.
+type PolicyRule struct {
+	Name   string `hcl:"name,label" json:"name"`
+	Action string `hcl:"action" json:"action"` // allow, deny, or require_approval
+	// Tools uses the same references as an agent's tools list;
+	// "<ns>.<name>.all" matches every tool under that namespace.
+	Tools []string `hcl:"tools" json:"tools"`
+	// Agents limits the rule to these agents. Empty means every agent.
+	Agents []string `hcl:"agents,optional" json:"agents,omitempty"`
+	// AllowedHosts limits the rule to calls whose "url" input points
+	// elsewhere. "*.example.com" matches subdomains. Calls without a url
+	// never match a rule that sets allowed_hosts.
+	AllowedHosts []string `hcl:"allowed_hosts,optional" json:"allowedHosts,omitempty"`
+	// Reason is shown to the agent when a call is denied and to the
+	// reviewer when approval is required.
+	Reason string `hcl:"reason,optional" json:"reason,omitempty"`
+}
+
+// Match returns the first rule matching a call of tool (a tool reference)
+// by agent with the given JSON input, or nil when no rule applies.
+func (p *Policies) Match(agent, tool, input string) *PolicyRule {
+	if p == nil {
+		return nil
+	}
+	for i := range p.Rules {
+		if p.Rules[i].matches(agent, tool, input) {
+			return &p.Rules[i]
+		}
+	}
+	return nil
+}
+
+func (r *PolicyRule) matches(agent, tool, input string) bool {
+	if !matchToolRef(r.Tools, tool) {
+		return false
+	}
+	if len(r.Agents) > 0 && !slices.Contains(r.Agents, agent) {
+		return false
+	}
+	if len(r.AllowedHosts) > 0 {
+		host := inputHost(input)
+		if host == "" || matchHost(r.AllowedHosts, host) {
+			return false
+		}
+	}
+	return true
+}
+
+// inputHost returns the host of the call's top-level "url" input, if any.
+func inputHost(input string) string {
+	var params struct {
+		URL string `json:"url"`
+	}
+	if json.Unmarshal([]byte(input), &params) != nil || params.URL == "" {
+		return ""
+	}
+	u, err := url.Parse(params.URL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Hostname())
+}
+
+func matchHost(patterns []string, host string) bool {
+	for _, p := range patterns {
+		p = strings.ToLower(p)
+		if p == host {
+			return true
+		}
+		if suffix, ok := strings.CutPrefix(p, "*."); ok && strings.HasSuffix(host, "."+suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks rule names, actions, and that referenced agents exist.
+func (p *Policies) Validate(agentNames map[string]bool) error {
+	if p == nil {
+		return nil
+	}
+	seen := make(map[string]bool)
+	for _, r := range p.Rules {
+		if seen[r.Name] {
+			return fmt.Errorf("policies: duplicate rule '%s'", r.Name)
+		}
+		seen[r.Name] = true
+		switch r.Action {
+		case PolicyAllow, PolicyDeny, PolicyRequireApproval:
+		default:
+			return fmt.Errorf("policies: rule '%s': unknown action '%s' (expected allow, deny, or require_approval)", r.Name, r.Action)
+		}
+		if len(r.Tools) == 0 {
+			return fmt.Errorf("policies: rule '%s': tools must not be empty", r.Name)
+		}
+		for _, a := range r.Agents {
+			if !agentNames[a] {
+				return fmt.Errorf("policies: rule '%s': agent '%s' not found", r.Name, a)
+			}
+		}
+	}
+	return nil
+}
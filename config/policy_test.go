@@ -0,0 +1,115 @@
+package config_test
+
+import (
+	"squadron/config"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Mission policies", func() {
+	missionWith := func(policies string) string {
+		return fullBaseHCL() + `
+mission "m" {
+  commander { model = models.anthropic.claude_sonnet_4 }
+  agents = [agents.test_agent]
+` + policies + `
+  task "work" { objective = "Do work" }
+}
+`
+	}
+
+	It("parses rules with tool and agent references", func() {
+		_, f := writeFixture("policies.hcl", missionWith(`
+  policies {
+    rule "no_eval" {
+      action = "deny"
+      tools  = ["plugins.playwright.browser_evaluate"]
+      agents = [agents.test_agent]
+      reason = "arbitrary JS is not allowed"
+    }
+    rule "http_allowlist" {
+      action        = "require_approval"
+      tools         = [builtins.http.all]
+      allowed_hosts = ["api.example.com", "*.internal.example.com"]
+    }
+  }`))
+		cfg, err := config.LoadFile(f)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Validate()).To(Succeed())
+
+		p := cfg.Missions[0].Policies
+		Expect(p).NotTo(BeNil())
+		Expect(p.Rules).To(HaveLen(2))
+		Expect(p.Rules[0].Tools).To(Equal([]string{"plugins.playwright.browser_evaluate"}))
+		Expect(p.Rules[0].Agents).To(Equal([]string{"test_agent"}))
+		Expect(p.Rules[1].Tools).To(Equal([]string{"builtins.http.all"}))
+	})
+
+	It("rejects unknown actions, agents, and duplicate rules", func() {
+		_, f := writeFixture("policies-bad-action.hcl", missionWith(`
+  policies {
+    rule "r" {
+      action = "block"
+      tools  = [builtins.http.get]
+    }
+  }`))
+		cfg, err := config.LoadFile(f)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Validate()).To(MatchError(ContainSubstring("unknown action 'block'")))
+
+		_, f = writeFixture("policies-bad-agent.hcl", missionWith(`
+  policies {
+    rule "r" {
+      action = "deny"
+      tools  = [builtins.http.get]
+      agents = ["ghost"]
+    }
+  }`))
+		cfg, err = config.LoadFile(f)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Validate()).To(MatchError(ContainSubstring("agent 'ghost' not found")))
+
+		_, f = writeFixture("policies-dup.hcl", missionWith(`
+  policies {
+    rule "r" {
+      action = "deny"
+      tools  = [builtins.http.get]
+    }
+    rule "r" {
+      action = "allow"
+      tools  = [builtins.http.get]
+    }
+  }`))
+		cfg, err = config.LoadFile(f)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Validate()).To(MatchError(ContainSubstring("duplicate rule 'r'")))
+	})
+
+	Describe("Match", func() {
+		policies := &config.Policies{Rules: []config.PolicyRule{
+			{Name: "trusted", Action: config.PolicyAllow, Tools: []string{"builtins.http.get"}, Agents: []string{"admin"}},
+			{Name: "no_eval", Action: config.PolicyDeny, Tools: []string{"plugins.playwright.browser_evaluate"}, Agents: []string{"scraper"}},
+			{Name: "http_allowlist", Action: config.PolicyRequireApproval, Tools: []string{"builtins.http.all"}, AllowedHosts: []string{"api.example.com", "*.internal.example.com"}},
+		}}
+
+		It("matches tools, namespaces, and agents in order", func() {
+			Expect(policies.Match("scraper", "plugins.playwright.browser_evaluate", `{}`).Name).To(Equal("no_eval"))
+			Expect(policies.Match("writer", "plugins.playwright.browser_evaluate", `{}`)).To(BeNil())
+			Expect(policies.Match("admin", "builtins.http.get", `{"url":"https://evil.example.org"}`).Name).To(Equal("trusted"))
+			Expect(policies.Match("scraper", "builtins.http.post", `{"url":"https://evil.example.org/x"}`).Name).To(Equal("http_allowlist"))
+		})
+
+		It("skips rules whose allowed hosts cover the call", func() {
+			Expect(policies.Match("scraper", "builtins.http.get", `{"url":"https://API.example.com/v1"}`)).To(BeNil())
+			Expect(policies.Match("scraper", "builtins.http.get", `{"url":"http://db.internal.example.com"}`)).To(BeNil())
+			Expect(policies.Match("scraper", "builtins.http.get", `{"url":"https://internal.example.com.evil.io"}`)).NotTo(BeNil())
+			Expect(policies.Match("scraper", "builtins.http.get", `{"query":"no url"}`)).To(BeNil())
+		})
+
+		It("is nil-safe", func() {
+			var p *config.Policies
+			Expect(p.Match("a", "builtins.http.get", `{}`)).To(BeNil())
+		})
+	})
+})
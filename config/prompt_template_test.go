@@ -0,0 +1,64 @@
+package config_test
+
+import (
+	"squadron/config"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Prompt templates", func() {
+	load := func(agentTemplate, commanderTemplate string) (*config.Config, error) {
+		hcl := fullBaseHCL() + `
+agent "writer" {
+  model           = models.anthropic.claude_sonnet_4
+  personality     = "Terse"
+  prompt_template = ` + agentTemplate + `
+}
+
+mission "report" {
+  commander {
+    model           = models.anthropic.claude_sonnet_4
+    prompt_template = ` + commanderTemplate + `
+  }
+  agents = [agents.writer]
+  task "write" {
+    objective = "Write the report"
+  }
+}
+`
+		_, f := writeFixture("config.hcl", hcl)
+		return config.LoadFile(f)
+	}
+
+	It("parses prompt_template on agents and the mission commander", func() {
+		cfg, err := load(`<<-EOT
+    {{.Default}}
+    Always answer in French.
+  EOT`, `"{{.Default}}\nTeam: {{.AgentList}}"`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Validate()).To(Succeed())
+
+		var writer *config.Agent
+		for i := range cfg.Agents {
+			if cfg.Agents[i].Name == "writer" {
+				writer = &cfg.Agents[i]
+			}
+		}
+		Expect(writer).NotTo(BeNil())
+		Expect(writer.PromptTemplate).To(Equal("{{.Default}}\nAlways answer in French.\n"))
+		Expect(cfg.Missions[0].Commander.PromptTemplate).To(Equal("{{.Default}}\nTeam: {{.AgentList}}"))
+	})
+
+	It("rejects an agent template that doesn't parse", func() {
+		cfg, err := load(`"{{.Default"`, `"{{.Default}}"`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Validate()).To(MatchError(ContainSubstring("invalid prompt_template")))
+	})
+
+	It("rejects a commander template that doesn't parse", func() {
+		cfg, err := load(`"{{.Default}}"`, `"{{if .IsIteration}}unclosed"`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Validate()).To(MatchError(ContainSubstring("commander: invalid prompt_template")))
+	})
+})
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultResponseCacheTTL is how long a cached LLM response is reused when
+// the response_cache block doesn't set ttl.
+const DefaultResponseCacheTTL = 24 * time.Hour
+
+// ResponseCache describes the `response_cache { ... }` block inside a
+// mission. LLM responses of the mission's commanders and agents are stored
+// keyed by a hash of the request (model, system prompts, history, and
+// tools), and an identical request within ttl reuses the stored response
+// instead of calling the provider. At most one per mission.
+//
+//	response_cache {
+//	  ttl = "6h"
+//	}
+type ResponseCache struct {
+	TTL string `hcl:"ttl,optional" json:"ttl,omitempty"`
+}
+
+// Validate checks that ttl is a positive duration.
+func (c *ResponseCache) Validate() error {
+	if c.TTL == "" {
+		return nil
+	}
+	d, err := time.ParseDuration(c.TTL)
+	if err != nil {
+		return fmt.Errorf("invalid ttl '%s': %w", c.TTL, err)
+	}
+	if d <= 0 {
+		return fmt.Errorf("ttl must be positive")
+	}
+	return nil
+}
+
+// GetTTL returns how long cached responses stay valid.
+func (c *ResponseCache) GetTTL() time.Duration {
+	d, err := time.ParseDuration(c.TTL)
+	if err != nil || d <= 0 {
+		return DefaultResponseCacheTTL
+	}
+	return d
+}
@@ -0,0 +1,50 @@
+package config_test
+
+import (
+	"time"
+
+	"squadron/config"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Mission response_cache", func() {
+	missionWith := func(block string) string {
+		return fullBaseHCL() + `
+mission "m" {
+  commander { model = models.anthropic.claude_sonnet_4 }
+  agents = [agents.test_agent]
+` + block + `
+  task "work" { objective = "Do work" }
+}
+`
+	}
+
+	It("parses the block and defaults the ttl", func() {
+		_, f := writeFixture("response-cache.hcl", missionWith(`
+  response_cache {
+    ttl = "6h"
+  }`))
+		cfg, err := config.LoadFile(f)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Validate()).To(Succeed())
+		Expect(cfg.Missions[0].ResponseCache.GetTTL()).To(Equal(6 * time.Hour))
+
+		_, f = writeFixture("response-cache-default.hcl", missionWith(`
+  response_cache {}`))
+		cfg, err = config.LoadFile(f)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Missions[0].ResponseCache.GetTTL()).To(Equal(config.DefaultResponseCacheTTL))
+	})
+
+	It("rejects an invalid ttl", func() {
+		_, f := writeFixture("response-cache-bad.hcl", missionWith(`
+  response_cache {
+    ttl = "soon"
+  }`))
+		cfg, err := config.LoadFile(f)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Validate()).To(MatchError(ContainSubstring("response_cache: invalid ttl 'soon'")))
+	})
+})
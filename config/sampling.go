@@ -0,0 +1,53 @@
+package config
+
+import "fmt"
+
+// Sampling tunes how a model generates its output, e.g. a low temperature
+// for extraction and a higher one for writing. It is set on a model config
+// for one of its models, and on a task for its commander and agents:
+//
+//	model "anthropic" {
+//	  provider = "anthropic"
+//	  sampling "claude_sonnet_4" {
+//	    temperature = 0.7
+//	  }
+//	}
+//
+//	task "extract" {
+//	  sampling {
+//	    temperature       = 0
+//	    max_output_tokens = 4096
+//	  }
+//	}
+//
+// Each setting comes from the task when it sets it, else from the model,
+// else the provider's default. Seed is only sent to providers that take
+// one (Gemini).
+type Sampling struct {
+	Temperature     *float64 `hcl:"temperature,optional" json:"temperature,omitempty"`
+	TopP            *float64 `hcl:"top_p,optional" json:"topP,omitempty"`
+	MaxOutputTokens int      `hcl:"max_output_tokens,optional" json:"maxOutputTokens,omitempty"`
+	Seed            *int64   `hcl:"seed,optional" json:"seed,omitempty"`
+}
+
+// Validate checks the settings are in range.
+func (s *Sampling) Validate() error {
+	if s.Temperature != nil && (*s.Temperature < 0 || *s.Temperature > 2) {
+		return fmt.Errorf("temperature must be between 0 and 2")
+	}
+	if s.TopP != nil && (*s.TopP <= 0 || *s.TopP > 1) {
+		return fmt.Errorf("top_p must be greater than 0 and at most 1")
+	}
+	if s.MaxOutputTokens < 0 {
+		return fmt.Errorf("max_output_tokens must be positive")
+	}
+	return nil
+}
+
+// SamplingFor returns the sampling settings for a model key, or nil.
+func (m *Model) SamplingFor(key string) *Sampling {
+	if m == nil {
+		return nil
+	}
+	return m.Sampling[key]
+}
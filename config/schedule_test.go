@@ -315,6 +315,28 @@ mission "bad" {
 			Expect(err.Error()).To(ContainSubstring("invalid timezone"))
 		})
 
+		It("rejects a malformed input template", func() {
+			hcl := fullBaseHCL() + `
+mission "bad" {
+  commander { model = models.anthropic.claude_sonnet_4 }
+  agents = [agents.test_agent]
+
+  schedule {
+    every  = "1h"
+    inputs = {
+      day = "{{ .Date "
+    }
+  }
+
+  task "work" { objective = "Do work" }
+}
+`
+			_, f := writeFixture("bad-template.hcl", hcl)
+			_, err := config.LoadAndValidate(f)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(`invalid template in input "day"`))
+		})
+
 		It("rejects at/weekdays with cron", func() {
 			hcl := fullBaseHCL() + `
 mission "bad" {
@@ -448,4 +470,70 @@ mission "m" {
 			Expect(cfg.Missions[0].MaxParallel).To(Equal(1))
 		})
 	})
+
+	Describe("max_parallel_tasks and max_parallel_llm_calls", func() {
+		It("default to 0 (unlimited)", func() {
+			hcl := fullBaseHCL() + `
+mission "m" {
+  commander { model = models.anthropic.claude_sonnet_4 }
+  agents = [agents.test_agent]
+  task "work" { objective = "Do work" }
+}
+`
+			_, f := writeFixture("default-task-limits.hcl", hcl)
+			cfg, err := config.LoadFile(f)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.Missions[0].MaxParallelTasks).To(Equal(0))
+			Expect(cfg.Missions[0].MaxParallelLLMCalls).To(Equal(0))
+		})
+
+		It("parses both limits", func() {
+			hcl := fullBaseHCL() + `
+mission "m" {
+  commander { model = models.anthropic.claude_sonnet_4 }
+  agents                 = [agents.test_agent]
+  max_parallel_tasks     = 2
+  max_parallel_llm_calls = 4
+
+  task "work" { objective = "Do work" }
+}
+`
+			_, f := writeFixture("task-limits.hcl", hcl)
+			cfg, err := config.LoadFile(f)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.Missions[0].MaxParallelTasks).To(Equal(2))
+			Expect(cfg.Missions[0].MaxParallelLLMCalls).To(Equal(4))
+		})
+
+		It("rejects a limit below 1", func() {
+			hcl := fullBaseHCL() + `
+mission "m" {
+  commander { model = models.anthropic.claude_sonnet_4 }
+  agents             = [agents.test_agent]
+  max_parallel_tasks = -1
+
+  task "work" { objective = "Do work" }
+}
+`
+			_, f := writeFixture("bad-task-limit.hcl", hcl)
+			cfg, err := config.LoadFile(f)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.Validate()).To(MatchError(ContainSubstring("max_parallel_tasks must be >= 1")))
+		})
+
+		It("rejects a fractional limit", func() {
+			hcl := fullBaseHCL() + `
+mission "m" {
+  commander { model = models.anthropic.claude_sonnet_4 }
+  agents                 = [agents.test_agent]
+  max_parallel_llm_calls = 1.5
+
+  task "work" { objective = "Do work" }
+}
+`
+			_, f := writeFixture("fractional-llm-limit.hcl", hcl)
+			_, err := config.LoadFile(f)
+			Expect(err).To(MatchError(ContainSubstring("max_parallel_llm_calls")))
+		})
+	})
 })
@@ -72,6 +72,9 @@ func parseSchemaNode(name string, val cty.Value) (*InputField, error) {
 	}
 
 	kind := val.GetAttr("kind").AsString()
+	if kind == "file" {
+		return nil, fmt.Errorf("field %q: file() is only allowed in task outputs", name)
+	}
 	desc := schemaNodeString(val, "description")
 	required := schemaNodeBool(val, "required")
 
@@ -191,6 +194,7 @@ func parseSchemaNodeAsMissionInput(name string, val cty.Value) (*MissionInput, e
 		Name:        name,
 		Type:        kind,
 		Description: desc,
+		Required:    val.Type().HasAttribute("required") && val.GetAttr("required") == cty.True,
 	}
 
 	// Nested types: items for list/map, properties for object
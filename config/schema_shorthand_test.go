@@ -238,6 +238,44 @@ mission "m" {
 			Expect(cat.Items).NotTo(BeNil())
 			Expect(cat.Items.Type).To(Equal("string"))
 		})
+
+		It("parses file output field", func() {
+			hcl := fullBaseHCL() + `
+mission "m" {
+  commander { model = models.anthropic.claude_sonnet_4 }
+  agents = [agents.test_agent]
+  task "t" {
+    objective = "Do"
+    output = {
+      report = file("Rendered PDF report", true)
+    }
+  }
+}
+`
+			_, f := writeFixture("config.hcl", hcl)
+			cfg, err := config.LoadFile(f)
+			Expect(err).NotTo(HaveOccurred())
+
+			report := outputFieldByName(cfg.Missions[0].Tasks[0].Output.Fields, "report")
+			Expect(report.Type).To(Equal("file"))
+			Expect(report.Description).To(Equal("Rendered PDF report"))
+			Expect(report.Required).To(BeTrue())
+		})
+
+		It("rejects file fields in tool inputs", func() {
+			hcl := minimalVarsHCL() + minimalModelHCL() + `
+tool "upload" {
+  implements = builtins.http.get
+  inputs = {
+    doc = file("Document", true)
+  }
+  url = "https://api.example.com/upload"
+}
+`
+			_, f := writeFixture("config.hcl", hcl)
+			_, err := config.LoadFile(f)
+			Expect(err).To(MatchError(ContainSubstring("file() is only allowed in task outputs")))
+		})
 	})
 
 	// ── dataset schema ────────────────────────────────────────────────────────
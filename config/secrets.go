@@ -0,0 +1,137 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Secret sources, one per attribute of a `secret` block.
+const (
+	SecretSourceEnv   = "env"
+	SecretSourceFile  = "file"
+	SecretSourceVault = "vault"
+	SecretSourceAWS   = "aws"
+)
+
+// SecretsConfig is the top-level `secrets` block. Each secret is resolved
+// when a mission starts and offered to its commanders and agents as
+// ${secrets.<name>}, the same way protected inputs are. At most one per
+// config.
+//
+//	secrets {
+//	  vault {
+//	    address = "https://vault.example.com"
+//	    token   = vars.vault_token
+//	  }
+//	  aws {
+//	    region = "us-east-1"
+//	  }
+//
+//	  secret "github_token" {
+//	    description = "GitHub API token"
+//	    env         = "GITHUB_TOKEN"
+//	  }
+//	  secret "db_password" { file = "/run/secrets/db_password" }
+//	  secret "stripe_key"  { vault = "secret/data/stripe#api_key" }
+//	  secret "openai_key"  { aws = "prod/openai#api_key" }
+//	}
+type SecretsConfig struct {
+	Vault   *SecretsVault `hcl:"vault,block" json:"vault,omitempty"`
+	AWS     *SecretsAWS   `hcl:"aws,block" json:"aws,omitempty"`
+	Secrets []Secret      `hcl:"secret,block" json:"secrets"`
+}
+
+// SecretsVault configures the HashiCorp Vault backend. Unset fields fall
+// back to VAULT_ADDR, VAULT_TOKEN and VAULT_NAMESPACE.
+type SecretsVault struct {
+	Address   string `hcl:"address,optional" json:"address,omitempty"`
+	Token     string `hcl:"token,optional" json:"-"`
+	Namespace string `hcl:"namespace,optional" json:"namespace,omitempty"`
+}
+
+// SecretsAWS configures the AWS Secrets Manager backend. Region falls back
+// to AWS_REGION / AWS_DEFAULT_REGION; credentials come from
+// AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN. Endpoint
+// overrides the regional endpoint (for example a VPC endpoint).
+type SecretsAWS struct {
+	Region   string `hcl:"region,optional" json:"region,omitempty"`
+	Endpoint string `hcl:"endpoint,optional" json:"endpoint,omitempty"`
+}
+
+// Secret is a `secret "name"` block. Exactly one source attribute is set:
+//   - env: an environment variable name
+//   - file: a file path; relative paths resolve against the declaring file
+//   - vault: a Vault KV path, optionally with #field
+//   - aws: a Secrets Manager secret ID, optionally with #key for JSON secrets
+type Secret struct {
+	Name        string `hcl:"name,label" json:"name"`
+	Description string `hcl:"description,optional" json:"description,omitempty"`
+	Env         string `hcl:"env,optional" json:"env,omitempty"`
+	File        string `hcl:"file,optional" json:"file,omitempty"`
+	Vault       string `hcl:"vault,optional" json:"vault,omitempty"`
+	AWS         string `hcl:"aws,optional" json:"aws,omitempty"`
+}
+
+// Source returns which backend the secret comes from and its reference
+// there.
+func (s *Secret) Source() (string, string) {
+	switch {
+	case s.Env != "":
+		return SecretSourceEnv, s.Env
+	case s.File != "":
+		return SecretSourceFile, s.File
+	case s.Vault != "":
+		return SecretSourceVault, s.Vault
+	case s.AWS != "":
+		return SecretSourceAWS, s.AWS
+	}
+	return "", ""
+}
+
+// Validate checks that exactly one source is set.
+func (s *Secret) Validate() error {
+	set := 0
+	for _, v := range []string{s.Env, s.File, s.Vault, s.AWS} {
+		if v != "" {
+			set++
+		}
+	}
+	if set != 1 {
+		return fmt.Errorf("exactly one of env, file, vault or aws is required")
+	}
+	return nil
+}
+
+// Validate checks every secret and that names are unique.
+func (c *SecretsConfig) Validate() error {
+	seen := make(map[string]bool, len(c.Secrets))
+	for i := range c.Secrets {
+		s := &c.Secrets[i]
+		if seen[s.Name] {
+			return fmt.Errorf("duplicate secret '%s'", s.Name)
+		}
+		seen[s.Name] = true
+		if err := s.Validate(); err != nil {
+			return fmt.Errorf("secret '%s': %w", s.Name, err)
+		}
+	}
+	return nil
+}
+
+// resolvePaths makes relative file sources absolute against the directory
+// of the HCL file that declared the block.
+func (c *SecretsConfig) resolvePaths(hclDir string) {
+	for i := range c.Secrets {
+		if f := c.Secrets[i].File; f != "" && !filepath.IsAbs(f) {
+			c.Secrets[i].File = filepath.Join(hclDir, f)
+		}
+	}
+}
+
+// SplitSecretRef splits a vault or aws reference into its path and the
+// optional field after '#'.
+func SplitSecretRef(ref string) (string, string) {
+	path, field, _ := strings.Cut(ref, "#")
+	return path, field
+}
@@ -0,0 +1,107 @@
+package config_test
+
+import (
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"squadron/config"
+)
+
+var _ = Describe("Secrets block", func() {
+	load := func(block string) (*config.Config, string, error) {
+		hcl := fullBaseHCL() + block + `
+mission "m" {
+  commander { model = models.anthropic.claude_sonnet_4 }
+  agents    = [agents.test_agent]
+  input "token" {
+    type      = "string"
+    protected = true
+    value     = vars.test_api_key
+  }
+  task "t" { objective = "go" }
+}
+`
+		dir, f := writeFixture("config.hcl", hcl)
+		cfg, err := config.LoadAndValidate(f)
+		return cfg, dir, err
+	}
+
+	It("parses backends and secrets, resolving vars and relative file paths", func() {
+		cfg, dir, err := load(`
+secrets {
+  vault {
+    address = "https://vault.example.com"
+    token   = vars.test_api_key
+  }
+  aws { region = "eu-west-1" }
+
+  secret "github_token" {
+    description = "GitHub API token"
+    env         = "GITHUB_TOKEN"
+  }
+  secret "db_password" { file = "secrets/db" }
+  secret "stripe_key" { vault = "secret/data/stripe#api_key" }
+  secret "openai_key" { aws = "prod/openai" }
+}
+`)
+		Expect(err).NotTo(HaveOccurred())
+		s := cfg.Secrets
+		Expect(s).NotTo(BeNil())
+		Expect(s.Vault.Address).To(Equal("https://vault.example.com"))
+		Expect(s.Vault.Token).To(Equal("test-key-123"))
+		Expect(s.AWS.Region).To(Equal("eu-west-1"))
+		Expect(s.Secrets).To(HaveLen(4))
+
+		source, ref := s.Secrets[0].Source()
+		Expect(source).To(Equal(config.SecretSourceEnv))
+		Expect(ref).To(Equal("GITHUB_TOKEN"))
+		Expect(s.Secrets[0].Description).To(Equal("GitHub API token"))
+
+		source, ref = s.Secrets[1].Source()
+		Expect(source).To(Equal(config.SecretSourceFile))
+		resolvedDir, _ := filepath.EvalSymlinks(dir)
+		Expect([]string{filepath.Join(dir, "secrets/db"), filepath.Join(resolvedDir, "secrets/db")}).To(ContainElement(ref))
+
+		path, field := config.SplitSecretRef(s.Secrets[2].Vault)
+		Expect(path).To(Equal("secret/data/stripe"))
+		Expect(field).To(Equal("api_key"))
+	})
+
+	It("requires exactly one source per secret", func() {
+		_, _, err := load(`
+secrets {
+  secret "x" {
+    env  = "X"
+    file = "x.txt"
+  }
+}
+`)
+		Expect(err).To(MatchError(ContainSubstring("secret 'x': exactly one of env, file, vault or aws is required")))
+
+		_, _, err = load(`
+secrets {
+  secret "x" { description = "nothing" }
+}
+`)
+		Expect(err).To(MatchError(ContainSubstring("exactly one of env, file, vault or aws is required")))
+	})
+
+	It("rejects duplicate names and collisions with protected inputs", func() {
+		_, _, err := load(`
+secrets {
+  secret "x" { env = "X" }
+  secret "x" { env = "Y" }
+}
+`)
+		Expect(err).To(MatchError(ContainSubstring("duplicate secret 'x'")))
+
+		_, _, err = load(`
+secrets {
+  secret "token" { env = "TOKEN" }
+}
+`)
+		Expect(err).To(MatchError(ContainSubstring("secret 'token' conflicts with protected input 'token' in mission 'm'")))
+	})
+})
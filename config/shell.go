@@ -0,0 +1,98 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"squadron/aitools"
+)
+
+// ShellConfig describes the top-level `shell { ... }` block. It turns on the
+// builtins.shell tools (run_command, read_file, write_file) and fences them
+// in: only allowed_commands may run, and every path they touch must be
+// inside allowed_paths. Relative entries in allowed_paths and working_dir
+// are anchored to the HCL file's directory. At most one per config.
+//
+//	shell {
+//	  allowed_commands = ["git", "ls", "python3"]
+//	  allowed_paths    = ["./work", "/tmp/squadron"]
+//	  working_dir      = "./work"
+//	  timeout          = "2m"
+//	}
+type ShellConfig struct {
+	AllowedCommands []string `hcl:"allowed_commands"`
+	AllowedPaths    []string `hcl:"allowed_paths,optional"`
+	WorkingDir      string   `hcl:"working_dir,optional"`
+	Timeout         string   `hcl:"timeout,optional"`
+}
+
+// resolvePaths anchors relative allowed_paths and working_dir to dir and
+// defaults working_dir to the first allowed path.
+func (s *ShellConfig) resolvePaths(dir string) {
+	for i, p := range s.AllowedPaths {
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(dir, p)
+		}
+		s.AllowedPaths[i] = filepath.Clean(p)
+	}
+	if s.WorkingDir != "" && !filepath.IsAbs(s.WorkingDir) {
+		s.WorkingDir = filepath.Join(dir, s.WorkingDir)
+	}
+	if s.WorkingDir == "" && len(s.AllowedPaths) > 0 {
+		s.WorkingDir = s.AllowedPaths[0]
+	}
+	if s.WorkingDir != "" {
+		s.WorkingDir = filepath.Clean(s.WorkingDir)
+	}
+}
+
+// Validate checks the allowlists and timeout. Call after resolvePaths.
+func (s *ShellConfig) Validate() error {
+	if len(s.AllowedCommands) == 0 {
+		return fmt.Errorf("allowed_commands must list at least one command")
+	}
+	for _, c := range s.AllowedCommands {
+		if c == "" {
+			return fmt.Errorf("allowed_commands must not contain empty entries")
+		}
+	}
+	if s.Timeout != "" {
+		d, err := time.ParseDuration(s.Timeout)
+		if err != nil {
+			return fmt.Errorf("invalid timeout '%s': %w", s.Timeout, err)
+		}
+		if d <= 0 {
+			return fmt.Errorf("timeout must be positive")
+		}
+	}
+	if s.WorkingDir != "" {
+		inside := false
+		for _, p := range s.AllowedPaths {
+			if s.WorkingDir == p || strings.HasPrefix(s.WorkingDir, p+string(filepath.Separator)) {
+				inside = true
+				break
+			}
+		}
+		if !inside {
+			return fmt.Errorf("working_dir %q must be inside allowed_paths", s.WorkingDir)
+		}
+	}
+	return nil
+}
+
+// Policy returns the runtime allowlist for the shell tools, or nil when no
+// shell block is configured (the tools then refuse every call).
+func (s *ShellConfig) Policy() *aitools.ShellPolicy {
+	if s == nil {
+		return nil
+	}
+	d, _ := time.ParseDuration(s.Timeout)
+	return &aitools.ShellPolicy{
+		AllowedCommands: s.AllowedCommands,
+		AllowedPaths:    s.AllowedPaths,
+		WorkingDir:      s.WorkingDir,
+		Timeout:         d,
+	}
+}
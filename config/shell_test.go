@@ -0,0 +1,83 @@
+package config_test
+
+import (
+	"path/filepath"
+
+	"squadron/aitools"
+	"squadron/config"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Shell Config", func() {
+
+	It("anchors relative paths to the HCL file and defaults working_dir", func() {
+		dir, f := writeFixture("shell.hcl", `
+shell {
+  allowed_commands = ["git", "ls"]
+  allowed_paths    = ["./work", "/tmp/squadron"]
+  timeout          = "2m"
+}
+`)
+		cfg, err := config.LoadFile(f)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Shell).NotTo(BeNil())
+		Expect(cfg.Shell.AllowedPaths).To(Equal([]string{filepath.Join(dir, "work"), "/tmp/squadron"}))
+		Expect(cfg.Shell.WorkingDir).To(Equal(filepath.Join(dir, "work")))
+		Expect(cfg.Shell.Policy().Timeout.Minutes()).To(Equal(2.0))
+	})
+
+	It("rejects a working_dir outside allowed_paths", func() {
+		_, f := writeFixture("shell.hcl", `
+shell {
+  allowed_commands = ["ls"]
+  allowed_paths    = ["./work"]
+  working_dir      = "/etc"
+}
+`)
+		_, err := config.LoadFile(f)
+		Expect(err).To(MatchError(ContainSubstring("working_dir")))
+	})
+
+	It("requires a shell block for builtins.shell tools", func() {
+		hcl := minimalVarsHCL() + minimalModelHCL() + `
+agent "ops" {
+  model       = models.anthropic.claude_sonnet_4
+  personality = "Careful"
+  role        = "Operator"
+  tools       = [builtins.shell.run_command]
+}
+`
+		_, f := writeFixture("config.hcl", hcl)
+		cfg, err := config.LoadFile(f)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Validate()).To(MatchError(ContainSubstring("requires a top-level shell")))
+	})
+
+	It("builds shell tools carrying the block's allowlist", func() {
+		hcl := minimalVarsHCL() + minimalModelHCL() + `
+shell {
+  allowed_commands = ["echo"]
+  allowed_paths    = ["./work"]
+}
+
+agent "ops" {
+  model       = models.anthropic.claude_sonnet_4
+  personality = "Careful"
+  role        = "Operator"
+  tools       = [builtins.shell.all]
+}
+`
+		_, f := writeFixture("config.hcl", hcl)
+		cfg, err := config.LoadFile(f)
+		Expect(err).NotTo(HaveOccurred())
+
+		tools := config.BuildToolsMap(cfg.Agents[0].Tools, nil, nil, nil, cfg.Builtins(nil), nil, nil)
+		Expect(tools).To(HaveKey("builtins.shell.read_file"))
+		Expect(tools).To(HaveKey("builtins.shell.write_file"))
+		run, ok := tools["builtins.shell.run_command"].(*aitools.ShellRunCommandTool)
+		Expect(ok).To(BeTrue())
+		Expect(run.Policy.AllowedCommands).To(ConsistOf("echo"))
+	})
+})
@@ -1,25 +1,177 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 )
 
 // StorageConfig defines the storage backend for mission state
 type StorageConfig struct {
 	Backend    string `hcl:"backend,optional"`     // "sqlite" or "postgres"
+	Driver     string `hcl:"driver,optional"`      // Alias for Backend
 	Path       string `hcl:"path,optional"`        // SQLite file path (default: ".squadron/store.db")
 	ConnString string `hcl:"conn_string,optional"` // Postgres connection string
+	// ArtifactsPath is the directory holding artifact content (default: an
+	// "artifacts" directory next to the SQLite file, or .squadron/artifacts)
+	ArtifactsPath string `hcl:"artifacts_path,optional"`
+	// ObjectStore keeps large payloads in a remote bucket (optional)
+	ObjectStore *ObjectStoreConfig `hcl:"object_store,block"`
+	// Retention limits how much mission history `squadron store gc` keeps
+	// (optional)
+	Retention *RetentionConfig `hcl:"retention,block"`
+}
+
+// RetentionConfig is the retention policy `squadron store gc` applies to
+// finished missions. Missions that are running or paused are never touched.
+// Zero leaves a limit unset.
+type RetentionConfig struct {
+	// MaxAgeDays deletes missions that finished more than this many days ago
+	MaxAgeDays int `hcl:"max_age_days,optional"`
+	// MaxMissions keeps only this many of the most recent finished missions
+	MaxMissions int `hcl:"max_missions,optional"`
+	// CompactAfterDays redacts the message bodies and tool results of
+	// missions that finished more than this many days ago, keeping their
+	// tasks, outputs, events and costs
+	CompactAfterDays int `hcl:"compact_after_days,optional"`
+}
+
+// Validate checks the limits are positive and at least one is set.
+func (r *RetentionConfig) Validate() error {
+	if r.MaxAgeDays < 0 || r.MaxMissions < 0 || r.CompactAfterDays < 0 {
+		return fmt.Errorf("retention: max_age_days, max_missions and compact_after_days must be positive")
+	}
+	if r.MaxAgeDays == 0 && r.MaxMissions == 0 && r.CompactAfterDays == 0 {
+		return fmt.Errorf("retention: set max_age_days, max_missions or compact_after_days")
+	}
+	if r.MaxAgeDays > 0 && r.CompactAfterDays >= r.MaxAgeDays {
+		return fmt.Errorf("retention: compact_after_days must be less than max_age_days")
+	}
+	return nil
+}
+
+// MaxAge returns max_age_days as a duration, or 0 when unset.
+func (r *RetentionConfig) MaxAge() time.Duration {
+	return time.Duration(r.MaxAgeDays) * 24 * time.Hour
+}
+
+// CompactAfter returns compact_after_days as a duration, or 0 when unset.
+func (r *RetentionConfig) CompactAfter() time.Duration {
+	return time.Duration(r.CompactAfterDays) * 24 * time.Hour
+}
+
+// DefaultOffloadThreshold is the size in bytes above which tool results and
+// images go to the object store when object_store sets no offload_threshold.
+const DefaultOffloadThreshold = 64 * 1024
+
+// ObjectStoreConfig points storage at an S3-compatible bucket. With it,
+// artifact content and debug logs go to the bucket, and tool results and
+// images larger than OffloadThreshold are replaced in the relational store
+// by a reference to their object.
+type ObjectStoreConfig struct {
+	Provider        string `hcl:"provider"`                   // "s3" or "gcs"
+	Bucket          string `hcl:"bucket"`                     // Bucket name
+	Prefix          string `hcl:"prefix,optional"`            // Key prefix, e.g. "prod/"
+	Region          string `hcl:"region,optional"`            // S3 region (default: AWS_REGION, then us-east-1)
+	Endpoint        string `hcl:"endpoint,optional"`          // Custom endpoint for S3-compatible servers (MinIO, R2)
+	AccessKeyID     string `hcl:"access_key_id,optional"`     // Defaults to AWS_ACCESS_KEY_ID for s3
+	SecretAccessKey string `hcl:"secret_access_key,optional"` // Defaults to AWS_SECRET_ACCESS_KEY for s3
+	// OffloadThreshold is the payload size in bytes above which tool results
+	// and images are offloaded (default: 64 KiB)
+	OffloadThreshold int `hcl:"offload_threshold,optional"`
+}
+
+// Defaults fills in default values for unset fields
+func (o *ObjectStoreConfig) Defaults() {
+	if o.OffloadThreshold == 0 {
+		o.OffloadThreshold = DefaultOffloadThreshold
+	}
+	switch o.Provider {
+	case "s3":
+		if o.Region == "" {
+			o.Region = os.Getenv("AWS_REGION")
+		}
+		if o.Region == "" {
+			o.Region = "us-east-1"
+		}
+		if o.AccessKeyID == "" && o.SecretAccessKey == "" {
+			o.AccessKeyID = os.Getenv("AWS_ACCESS_KEY_ID")
+			o.SecretAccessKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+		}
+	case "gcs":
+		// GCS speaks the S3 protocol with HMAC keys at its XML API endpoint
+		if o.Region == "" {
+			o.Region = "auto"
+		}
+		if o.Endpoint == "" {
+			o.Endpoint = "https://storage.googleapis.com"
+		}
+	}
+}
+
+// Validate checks the provider and credentials. Call after Defaults.
+func (o *ObjectStoreConfig) Validate() error {
+	switch o.Provider {
+	case "s3", "gcs":
+	default:
+		return fmt.Errorf("unknown object_store provider: %s (expected 's3' or 'gcs')", o.Provider)
+	}
+	if o.Bucket == "" {
+		return fmt.Errorf("object_store requires bucket to be set")
+	}
+	if o.AccessKeyID == "" || o.SecretAccessKey == "" {
+		if o.Provider == "gcs" {
+			return fmt.Errorf("object_store provider 'gcs' requires access_key_id and secret_access_key (an HMAC key)")
+		}
+		return fmt.Errorf("object_store requires access_key_id and secret_access_key (or AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY)")
+	}
+	if o.OffloadThreshold < 0 {
+		return fmt.Errorf("object_store offload_threshold must not be negative")
+	}
+	return nil
 }
 
 // Defaults fills in default values for unset fields
 func (s *StorageConfig) Defaults() {
+	if s.Backend == "" {
+		s.Backend = s.Driver
+	}
 	if s.Backend == "" {
 		s.Backend = "sqlite"
 	}
 	if s.Path == "" {
 		s.Path = ".squadron/store.db"
 	}
+	if s.ObjectStore != nil {
+		s.ObjectStore.Defaults()
+	}
+}
+
+// Validate checks the backend choice and its required settings. Call after
+// Defaults.
+func (s *StorageConfig) Validate() error {
+	if s.Driver != "" && s.Driver != s.Backend {
+		return fmt.Errorf("driver and backend disagree ('%s' vs '%s'); set only one", s.Driver, s.Backend)
+	}
+	switch s.Backend {
+	case "sqlite":
+	case "postgres":
+		if s.ConnString == "" {
+			return fmt.Errorf("postgres backend requires conn_string to be set")
+		}
+	default:
+		return fmt.Errorf("unknown storage backend: %s (expected 'sqlite' or 'postgres')", s.Backend)
+	}
+	if s.Retention != nil {
+		if err := s.Retention.Validate(); err != nil {
+			return err
+		}
+	}
+	if s.ObjectStore != nil {
+		return s.ObjectStore.Validate()
+	}
+	return nil
 }
 
 // DefaultStorageConfig returns a storage config using sensible defaults,
@@ -0,0 +1,188 @@
+package config_test
+
+import (
+	"time"
+
+	"squadron/config"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Storage Config", func() {
+
+	It("defaults to sqlite when no storage block is present", func() {
+		hcl := `
+variable "test_api_key" {
+  default = "test-key-123"
+}
+`
+		_, f := writeFixture("no-storage.hcl", hcl)
+		cfg, err := config.LoadFile(f)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Storage.Backend).To(Equal("sqlite"))
+	})
+
+	It("accepts driver as an alias for backend", func() {
+		hcl := `
+storage {
+  driver      = "postgres"
+  conn_string = "postgres://squadron@db:5432/squadron"
+}
+`
+		_, f := writeFixture("storage-driver.hcl", hcl)
+		cfg, err := config.LoadFile(f)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Storage.Backend).To(Equal("postgres"))
+		Expect(cfg.Storage.ConnString).To(Equal("postgres://squadron@db:5432/squadron"))
+	})
+
+	It("rejects postgres without a conn_string", func() {
+		hcl := `
+storage {
+  backend = "postgres"
+}
+`
+		_, f := writeFixture("storage-no-conn.hcl", hcl)
+		_, err := config.LoadFile(f)
+		Expect(err).To(MatchError(ContainSubstring("requires conn_string")))
+	})
+
+	It("rejects an unknown backend", func() {
+		hcl := `
+storage {
+  driver = "mysql"
+}
+`
+		_, f := writeFixture("storage-unknown.hcl", hcl)
+		_, err := config.LoadFile(f)
+		Expect(err).To(MatchError(ContainSubstring("unknown storage backend: mysql")))
+	})
+
+	It("rejects driver and backend that disagree", func() {
+		hcl := `
+storage {
+  backend = "sqlite"
+  driver  = "postgres"
+}
+`
+		_, f := writeFixture("storage-conflict.hcl", hcl)
+		_, err := config.LoadFile(f)
+		Expect(err).To(MatchError(ContainSubstring("driver and backend disagree")))
+	})
+
+	It("parses an object_store block with gcs defaults", func() {
+		hcl := `
+storage {
+  object_store {
+    provider          = "gcs"
+    bucket            = "squadron-payloads"
+    prefix            = "prod/"
+    access_key_id     = "GOOG1EXAMPLE"
+    secret_access_key = "secret"
+  }
+}
+`
+		_, f := writeFixture("storage-gcs.hcl", hcl)
+		cfg, err := config.LoadFile(f)
+		Expect(err).NotTo(HaveOccurred())
+		obj := cfg.Storage.ObjectStore
+		Expect(obj).NotTo(BeNil())
+		Expect(obj.Bucket).To(Equal("squadron-payloads"))
+		Expect(obj.Prefix).To(Equal("prod/"))
+		Expect(obj.Endpoint).To(Equal("https://storage.googleapis.com"))
+		Expect(obj.Region).To(Equal("auto"))
+		Expect(obj.OffloadThreshold).To(Equal(config.DefaultOffloadThreshold))
+	})
+
+	It("takes s3 credentials from the environment", func() {
+		GinkgoT().Setenv("AWS_ACCESS_KEY_ID", "AKIDENV")
+		GinkgoT().Setenv("AWS_SECRET_ACCESS_KEY", "secretenv")
+		GinkgoT().Setenv("AWS_REGION", "eu-west-1")
+		hcl := `
+storage {
+  object_store {
+    provider          = "s3"
+    bucket            = "squadron-payloads"
+    offload_threshold = 1024
+  }
+}
+`
+		_, f := writeFixture("storage-s3.hcl", hcl)
+		cfg, err := config.LoadFile(f)
+		Expect(err).NotTo(HaveOccurred())
+		obj := cfg.Storage.ObjectStore
+		Expect(obj.AccessKeyID).To(Equal("AKIDENV"))
+		Expect(obj.SecretAccessKey).To(Equal("secretenv"))
+		Expect(obj.Region).To(Equal("eu-west-1"))
+		Expect(obj.OffloadThreshold).To(Equal(1024))
+	})
+
+	It("rejects an object_store without credentials or with an unknown provider", func() {
+		GinkgoT().Setenv("AWS_ACCESS_KEY_ID", "")
+		GinkgoT().Setenv("AWS_SECRET_ACCESS_KEY", "")
+		hcl := `
+storage {
+  object_store {
+    provider = "s3"
+    bucket   = "b"
+  }
+}
+`
+		_, f := writeFixture("storage-s3-nocreds.hcl", hcl)
+		_, err := config.LoadFile(f)
+		Expect(err).To(MatchError(ContainSubstring("requires access_key_id and secret_access_key")))
+
+		hcl = `
+storage {
+  object_store {
+    provider = "azure"
+    bucket   = "b"
+  }
+}
+`
+		_, f = writeFixture("storage-azure.hcl", hcl)
+		_, err = config.LoadFile(f)
+		Expect(err).To(MatchError(ContainSubstring("unknown object_store provider: azure")))
+	})
+
+	It("parses a retention block and validates it", func() {
+		hcl := `
+storage {
+  retention {
+    max_age_days       = 90
+    max_missions       = 500
+    compact_after_days = 14
+  }
+}
+`
+		_, f := writeFixture("storage-retention.hcl", hcl)
+		cfg, err := config.LoadFile(f)
+		Expect(err).NotTo(HaveOccurred())
+		r := cfg.Storage.Retention
+		Expect(r.MaxMissions).To(Equal(500))
+		Expect(r.MaxAge()).To(Equal(90 * 24 * time.Hour))
+		Expect(r.CompactAfter()).To(Equal(14 * 24 * time.Hour))
+
+		hcl = `
+storage {
+  retention {}
+}
+`
+		_, f = writeFixture("storage-retention-empty.hcl", hcl)
+		_, err = config.LoadFile(f)
+		Expect(err).To(MatchError(ContainSubstring("set max_age_days, max_missions or compact_after_days")))
+
+		hcl = `
+storage {
+  retention {
+    max_age_days       = 7
+    compact_after_days = 30
+  }
+}
+`
+		_, f = writeFixture("storage-retention-order.hcl", hcl)
+		_, err = config.LoadFile(f)
+		Expect(err).To(MatchError(ContainSubstring("compact_after_days must be less than max_age_days")))
+	})
+})
@@ -0,0 +1,206 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// A template block declares a mission shape once — its commander, agents,
+// tasks, datasets — with param blocks for the parts that vary:
+//
+//	template "crawler" {
+//	  param "start_url" {}
+//	  param "depth" { default = 2 }
+//
+//	  agents = [agents.browser]
+//	  task "crawl" {
+//	    objective = "Crawl ${params.start_url} to depth ${params.depth}"
+//	  }
+//	}
+//
+// A mission instantiates it with from_template and with. The expansion
+// happens at load time: the mission is parsed from the template's body with
+// params set, so the result is an ordinary Mission.
+//
+//	mission "docs_crawl" {
+//	  from_template = "crawler"
+//	  with = { start_url = "https://example.com/docs" }
+//	}
+
+// templateParam is one param block of a template.
+type templateParam struct {
+	Name     string
+	Default  cty.Value
+	Required bool
+}
+
+// collectTemplates indexes the template blocks of every file by name.
+func collectTemplates(allParsedBlocks []parsedBlocks) (map[string]*hcl.Block, error) {
+	templates := make(map[string]*hcl.Block)
+	for _, pb := range allParsedBlocks {
+		for _, block := range pb.Templates {
+			name := block.Labels[0]
+			if _, dup := templates[name]; dup {
+				return nil, fmt.Errorf("template '%s' declared more than once", name)
+			}
+			templates[name] = block
+		}
+	}
+	return templates, nil
+}
+
+// instantiateTemplate expands a mission block that sets from_template. It
+// returns the block to parse the mission from — the template body under the
+// mission's name — and the context to parse it with, which adds params. A
+// mission without from_template is returned as is, with a nil params value.
+func instantiateTemplate(block *hcl.Block, templates map[string]*hcl.Block, ctx *hcl.EvalContext) (*hcl.Block, *hcl.EvalContext, cty.Value, error) {
+	missionName := block.Labels[0]
+
+	probe, _, _ := block.Body.PartialContent(&hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{{Name: "from_template"}},
+	})
+	if _, ok := probe.Attributes["from_template"]; !ok {
+		return block, ctx, cty.NilVal, nil
+	}
+
+	content, diags := block.Body.Content(&hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{
+			{Name: "from_template", Required: true},
+			{Name: "with"},
+		},
+	})
+	if diags.HasErrors() {
+		return nil, nil, cty.NilVal, fmt.Errorf("mission '%s': a mission with from_template may only set from_template and with: %w", missionName, diags)
+	}
+
+	nameVal, diags := content.Attributes["from_template"].Expr.Value(ctx)
+	if diags.HasErrors() {
+		return nil, nil, cty.NilVal, fmt.Errorf("mission '%s': from_template: %w", missionName, diags)
+	}
+	if nameVal.IsNull() || nameVal.Type() != cty.String {
+		return nil, nil, cty.NilVal, fmt.Errorf("mission '%s': from_template must be a template name", missionName)
+	}
+	templateName := nameVal.AsString()
+	tmpl, ok := templates[templateName]
+	if !ok {
+		return nil, nil, cty.NilVal, fmt.Errorf("mission '%s': template '%s' not found", missionName, templateName)
+	}
+
+	with := map[string]cty.Value{}
+	if attr, ok := content.Attributes["with"]; ok {
+		val, diags := attr.Expr.Value(ctx)
+		if diags.HasErrors() {
+			return nil, nil, cty.NilVal, fmt.Errorf("mission '%s': with: %w", missionName, diags)
+		}
+		if !val.IsNull() {
+			if !val.Type().IsObjectType() && !val.Type().IsMapType() {
+				return nil, nil, cty.NilVal, fmt.Errorf("mission '%s': with must be an object of param values", missionName)
+			}
+			with = val.AsValueMap()
+		}
+	}
+
+	declared, err := parseTemplateParams(tmpl, ctx)
+	if err != nil {
+		return nil, nil, cty.NilVal, err
+	}
+	params, err := bindTemplateParams(templateName, declared, with)
+	if err != nil {
+		return nil, nil, cty.NilVal, fmt.Errorf("mission '%s': %w", missionName, err)
+	}
+
+	expanded := &hcl.Block{
+		Type:        block.Type,
+		Labels:      block.Labels,
+		Body:        tmpl.Body,
+		DefRange:    tmpl.DefRange,
+		TypeRange:   block.TypeRange,
+		LabelRanges: block.LabelRanges,
+	}
+	vars := make(map[string]cty.Value, len(ctx.Variables)+1)
+	for k, v := range ctx.Variables {
+		vars[k] = v
+	}
+	vars["params"] = params
+	return expanded, &hcl.EvalContext{Variables: vars, Functions: ctx.Functions}, params, nil
+}
+
+// parseTemplateParams reads a template's param blocks. Defaults are
+// evaluated with the missions context, so they can name agents or vars.
+func parseTemplateParams(tmpl *hcl.Block, ctx *hcl.EvalContext) ([]templateParam, error) {
+	templateName := tmpl.Labels[0]
+	content, _, diags := tmpl.Body.PartialContent(&hcl.BodySchema{
+		Blocks: []hcl.BlockHeaderSchema{{Type: "param", LabelNames: []string{"name"}}},
+	})
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("template '%s': %w", templateName, diags)
+	}
+
+	var params []templateParam
+	seen := make(map[string]bool)
+	for _, block := range content.Blocks {
+		name := block.Labels[0]
+		if seen[name] {
+			return nil, fmt.Errorf("template '%s': param '%s' declared more than once", templateName, name)
+		}
+		seen[name] = true
+
+		paramContent, diags := block.Body.Content(&hcl.BodySchema{
+			Attributes: []hcl.AttributeSchema{
+				{Name: "description"},
+				{Name: "default"},
+			},
+		})
+		if diags.HasErrors() {
+			return nil, fmt.Errorf("template '%s': param '%s': %w", templateName, name, diags)
+		}
+		p := templateParam{Name: name, Required: true}
+		if attr, ok := paramContent.Attributes["default"]; ok {
+			val, diags := attr.Expr.Value(ctx)
+			if diags.HasErrors() {
+				return nil, fmt.Errorf("template '%s': param '%s': default: %w", templateName, name, diags)
+			}
+			p.Default = val
+			p.Required = false
+		}
+		params = append(params, p)
+	}
+	return params, nil
+}
+
+// bindTemplateParams merges a mission's with values over the template's
+// param defaults into the params object. Unknown and missing params are
+// errors.
+func bindTemplateParams(templateName string, declared []templateParam, with map[string]cty.Value) (cty.Value, error) {
+	known := make(map[string]bool, len(declared))
+	for _, p := range declared {
+		known[p.Name] = true
+	}
+	var unknown []string
+	for name := range with {
+		if !known[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return cty.NilVal, fmt.Errorf("template '%s' has no param '%s'", templateName, unknown[0])
+	}
+
+	values := make(map[string]cty.Value, len(declared))
+	for _, p := range declared {
+		val, ok := with[p.Name]
+		switch {
+		case ok:
+			values[p.Name] = val
+		case p.Required:
+			return cty.NilVal, fmt.Errorf("template '%s': param '%s' is required", templateName, p.Name)
+		default:
+			values[p.Name] = p.Default
+		}
+	}
+	return cty.ObjectVal(values), nil
+}
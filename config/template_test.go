@@ -0,0 +1,136 @@
+package config_test
+
+import (
+	"squadron/config"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Mission templates", func() {
+	const crawler = `
+template "crawler" {
+  param "start_url" {
+    description = "Where the crawl starts"
+  }
+  param "depth" {
+    default = 2
+  }
+  param "crawlers" {
+    default = [agents.test_agent]
+  }
+
+  commander {
+    model = models.anthropic.claude_sonnet_4
+  }
+  agents = params.crawlers
+  task "crawl" {
+    objective = "Crawl ${params.start_url} to depth ${params.depth}"
+  }
+  task "summarize" {
+    depends_on = [tasks.crawl]
+    objective  = "Summarize what was found"
+  }
+}
+`
+	load := func(missions string) (*config.Config, error) {
+		_, f := writeFixture("config.hcl", fullBaseHCL()+crawler+missions)
+		return config.LoadFile(f)
+	}
+
+	It("expands each instantiation into a full mission", func() {
+		cfg, err := load(`
+mission "docs" {
+  from_template = "crawler"
+  with = {
+    start_url = "https://example.com/docs"
+  }
+}
+
+mission "blog" {
+  from_template = "crawler"
+  with = {
+    start_url = "https://example.com/blog"
+    depth     = 5
+  }
+}
+`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Validate()).To(Succeed())
+		Expect(cfg.Missions).To(HaveLen(2))
+
+		docs, blog := cfg.Missions[0], cfg.Missions[1]
+		Expect(docs.Name).To(Equal("docs"))
+		Expect(docs.Agents).To(Equal([]string{"test_agent"}))
+		Expect(docs.Tasks).To(HaveLen(2))
+		Expect(docs.Tasks[1].DependsOn).To(Equal([]string{"crawl"}))
+		Expect(docs.Tasks[0].ResolvedObjective(nil, nil)).To(Equal("Crawl https://example.com/docs to depth 2"))
+
+		Expect(blog.Name).To(Equal("blog"))
+		Expect(blog.Tasks[0].ResolvedObjective(nil, nil)).To(Equal("Crawl https://example.com/blog to depth 5"))
+	})
+
+	It("lets with reference agents", func() {
+		cfg, err := load(`
+mission "docs" {
+  from_template = "crawler"
+  with = {
+    start_url = "https://example.com"
+    crawlers  = [agents.test_agent]
+  }
+}
+`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Missions[0].Agents).To(Equal([]string{"test_agent"}))
+	})
+
+	It("rejects a missing required param", func() {
+		_, err := load(`
+mission "docs" {
+  from_template = "crawler"
+}
+`)
+		Expect(err).To(MatchError(ContainSubstring("template 'crawler': param 'start_url' is required")))
+	})
+
+	It("rejects an unknown param", func() {
+		_, err := load(`
+mission "docs" {
+  from_template = "crawler"
+  with = {
+    start_url = "https://example.com"
+    speed     = "fast"
+  }
+}
+`)
+		Expect(err).To(MatchError(ContainSubstring("template 'crawler' has no param 'speed'")))
+	})
+
+	It("rejects an unknown template", func() {
+		_, err := load(`
+mission "docs" {
+  from_template = "scraper"
+}
+`)
+		Expect(err).To(MatchError(ContainSubstring("template 'scraper' not found")))
+	})
+
+	It("rejects other mission settings alongside from_template", func() {
+		_, err := load(`
+mission "docs" {
+  from_template = "crawler"
+  with = {
+    start_url = "https://example.com"
+  }
+  directive = "Be thorough"
+}
+`)
+		Expect(err).To(MatchError(ContainSubstring("may only set from_template and with")))
+	})
+
+	It("does not create missions from unused templates", func() {
+		cfg, err := load("")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Missions).To(BeEmpty())
+	})
+})
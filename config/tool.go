@@ -174,6 +174,8 @@ func (t *CustomTool) GetImplementedTool() aitools.Tool {
 		return &aitools.HTTPPatchTool{}
 	case "builtins.http.delete":
 		return &aitools.HTTPDeleteTool{}
+	case "builtins.http.paginate":
+		return &aitools.HTTPPaginateTool{}
 	default:
 		return nil
 	}
@@ -233,7 +235,7 @@ func stringToCtyType(s string) cty.Type {
 		return cty.String
 	case "number", "integer":
 		return cty.Number
-	case "boolean":
+	case "boolean", "bool":
 		return cty.Bool
 	case "array":
 		return cty.List(cty.DynamicPseudoType)
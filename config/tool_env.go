@@ -0,0 +1,74 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+
+	"squadron/aitools"
+)
+
+// envNameRegex matches valid environment variable names.
+var envNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// parseToolEnv reads the optional env and working_dir attributes of an agent
+// or task block. Both must be known when the config loads, so they can use
+// vars (and each in a for_each task) but not inputs.
+func parseToolEnv(attrs hcl.Attributes, ctx *hcl.EvalContext) (map[string]string, string, error) {
+	var env map[string]string
+	if attr, ok := attrs["env"]; ok {
+		val, diags := attr.Expr.Value(ctx)
+		if diags.HasErrors() {
+			return nil, "", fmt.Errorf("env: %w", diags)
+		}
+		if !val.IsWhollyKnown() {
+			return nil, "", fmt.Errorf("env must be known when the config loads — it can use vars, but not inputs")
+		}
+		if !val.Type().IsObjectType() && !val.Type().IsMapType() {
+			return nil, "", fmt.Errorf("env must be a map of strings, like { GIT_AUTHOR_NAME = \"squadron\" }")
+		}
+		env = make(map[string]string)
+		for it := val.ElementIterator(); it.Next(); {
+			k, v := it.Element()
+			name := k.AsString()
+			if !envNameRegex.MatchString(name) {
+				return nil, "", fmt.Errorf("env: %q is not a valid environment variable name", name)
+			}
+			if v.IsNull() || v.Type() != cty.String {
+				return nil, "", fmt.Errorf("env: %s must be a string", name)
+			}
+			env[name] = v.AsString()
+		}
+	}
+
+	var dir string
+	if attr, ok := attrs["working_dir"]; ok {
+		val, diags := attr.Expr.Value(ctx)
+		if diags.HasErrors() {
+			return nil, "", fmt.Errorf("working_dir: %w", diags)
+		}
+		if !val.IsWhollyKnown() {
+			return nil, "", fmt.Errorf("working_dir must be known when the config loads — it can use vars, but not inputs")
+		}
+		if val.Type() != cty.String {
+			return nil, "", fmt.Errorf("working_dir must be a string")
+		}
+		dir = val.AsString()
+		if !filepath.IsAbs(dir) {
+			return nil, "", fmt.Errorf("working_dir %q must be an absolute path", dir)
+		}
+	}
+	return env, dir, nil
+}
+
+// toolEnv builds the ToolEnv for an env map and working directory, or nil
+// when both are empty.
+func toolEnv(env map[string]string, dir string) *aitools.ToolEnv {
+	if len(env) == 0 && dir == "" {
+		return nil
+	}
+	return &aitools.ToolEnv{Env: env, Dir: dir}
+}
@@ -0,0 +1,112 @@
+package config_test
+
+import (
+	"squadron/config"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Plugin env and working_dir", func() {
+	load := func(body string) (*config.Config, error) {
+		hcl := fullBaseHCL() + `
+variable "repo_root" {
+  default = "/srv/repos"
+}
+
+agent "committer" {
+  model       = models.anthropic.claude_sonnet_4
+  personality = "Careful"
+  env = {
+    GIT_AUTHOR_NAME = "squadron"
+    REGION          = "us"
+  }
+  working_dir = vars.repo_root
+}
+
+mission "repos" {
+  commander {
+    model = models.anthropic.claude_sonnet_4
+  }
+  agents = [agents.test_agent, agents.committer]
+  input "branch" {
+    type    = "string"
+    default = "main"
+  }
+` + body + `
+}
+`
+		_, f := writeFixture("config.hcl", hcl)
+		return config.LoadFile(f)
+	}
+
+	It("parses env and working_dir on agents and tasks", func() {
+		cfg, err := load(`
+  task "review" {
+    for_each = {
+      api = "${vars.repo_root}/api"
+      web = "${vars.repo_root}/web"
+    }
+    objective   = "Review ${each.key}"
+    env         = { REGION = "eu" }
+    working_dir = each.value
+  }
+  task "plain" {
+    objective = "Plain"
+  }
+`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Validate()).To(Succeed())
+
+		var committer config.Agent
+		for _, a := range cfg.Agents {
+			if a.Name == "committer" {
+				committer = a
+			}
+		}
+		env := committer.GetToolEnv()
+		Expect(env).NotTo(BeNil())
+		Expect(env.Env).To(Equal(map[string]string{"GIT_AUTHOR_NAME": "squadron", "REGION": "us"}))
+		Expect(env.Dir).To(Equal("/srv/repos"))
+
+		tasks := cfg.Missions[0].Tasks
+		Expect(tasks[0].GetToolEnv().Dir).To(Equal("/srv/repos/api"))
+		Expect(tasks[1].GetToolEnv().Dir).To(Equal("/srv/repos/web"))
+		Expect(tasks[1].GetToolEnv().Env).To(Equal(map[string]string{"REGION": "eu"}))
+		Expect(tasks[2].GetToolEnv()).To(BeNil())
+
+		merged := env.Merge(tasks[1].GetToolEnv())
+		Expect(merged.Env).To(Equal(map[string]string{"GIT_AUTHOR_NAME": "squadron", "REGION": "eu"}))
+		Expect(merged.Dir).To(Equal("/srv/repos/web"))
+	})
+
+	It("rejects a relative working_dir", func() {
+		_, err := load(`
+  task "review" {
+    objective   = "Review"
+    working_dir = "repos/api"
+  }
+`)
+		Expect(err).To(MatchError(ContainSubstring(`working_dir "repos/api" must be an absolute path`)))
+	})
+
+	It("rejects invalid variable names", func() {
+		_, err := load(`
+  task "review" {
+    objective = "Review"
+    env       = { "MY-VAR" = "x" }
+  }
+`)
+		Expect(err).To(MatchError(ContainSubstring(`"MY-VAR" is not a valid environment variable name`)))
+	})
+
+	It("rejects env that reads inputs", func() {
+		_, err := load(`
+  task "review" {
+    objective = "Review"
+    env       = { BRANCH = inputs.branch }
+  }
+`)
+		Expect(err).To(MatchError(ContainSubstring("env must be known when the config loads")))
+	})
+})
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"strings"
+
+	"squadron/aitools"
+)
+
+// ToolFilter narrows the configured tools an agent gets while working on one
+// task (tools_allow / tools_deny on the task block). Entries use the same
+// references as an agent's tools list; "<ns>.<name>.all" matches every tool
+// under that namespace.
+type ToolFilter struct {
+	Allow []string `json:"allow,omitempty"`
+	Deny  []string `json:"deny,omitempty"`
+}
+
+// missionDatasetTools are injected by BuildToolsMap in mission context rather
+// than configured on the agent, so filters leave them alone.
+var missionDatasetTools = map[string]bool{
+	"set_dataset":    true,
+	"dataset_sample": true,
+	"dataset_count":  true,
+}
+
+// Apply removes every configured tool that Allow (when set) doesn't match or
+// that Deny matches. A nil filter keeps everything.
+func (f *ToolFilter) Apply(tools map[string]aitools.Tool) {
+	if f == nil {
+		return
+	}
+	for ref := range tools {
+		if missionDatasetTools[ref] {
+			continue
+		}
+		if (len(f.Allow) > 0 && !matchToolRef(f.Allow, ref)) || matchToolRef(f.Deny, ref) {
+			delete(tools, ref)
+		}
+	}
+}
+
+func matchToolRef(patterns []string, ref string) bool {
+	for _, p := range patterns {
+		if p == ref {
+			return true
+		}
+		if ns, ok := strings.CutSuffix(p, ".all"); ok && strings.HasPrefix(ref, ns+".") {
+			return true
+		}
+	}
+	return false
+}
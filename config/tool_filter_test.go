@@ -0,0 +1,62 @@
+package config_test
+
+import (
+	"squadron/aitools"
+	"squadron/config"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ToolFilter", func() {
+	var tools map[string]aitools.Tool
+
+	BeforeEach(func() {
+		tools = map[string]aitools.Tool{
+			"builtins.http.get":    &aitools.HTTPGetTool{},
+			"builtins.http.post":   &aitools.HTTPPostTool{},
+			"plugins.shell.exec":   nil,
+			"plugins.shell.read":   nil,
+			"mcp.github.get_issue": nil,
+			"tools.weather":        nil,
+			"set_dataset":          nil,
+		}
+	})
+
+	keys := func() []string {
+		var out []string
+		for k := range tools {
+			out = append(out, k)
+		}
+		return out
+	}
+
+	It("keeps everything when nil", func() {
+		var f *config.ToolFilter
+		f.Apply(tools)
+		Expect(tools).To(HaveLen(7))
+	})
+
+	It("keeps only allowed tools, expanding .all", func() {
+		(&config.ToolFilter{Allow: []string{"builtins.http.get", "plugins.shell.all"}}).Apply(tools)
+		Expect(keys()).To(ConsistOf("builtins.http.get", "plugins.shell.exec", "plugins.shell.read", "set_dataset"))
+	})
+
+	It("removes denied tools", func() {
+		(&config.ToolFilter{Deny: []string{"plugins.shell.exec", "builtins.http.all"}}).Apply(tools)
+		Expect(keys()).To(ConsistOf("plugins.shell.read", "mcp.github.get_issue", "tools.weather", "set_dataset"))
+	})
+
+	It("applies deny after allow", func() {
+		(&config.ToolFilter{
+			Allow: []string{"plugins.shell.all", "tools.weather"},
+			Deny:  []string{"plugins.shell.exec"},
+		}).Apply(tools)
+		Expect(keys()).To(ConsistOf("plugins.shell.read", "tools.weather", "set_dataset"))
+	})
+
+	It("does not treat a namespace prefix as a match without .all", func() {
+		(&config.ToolFilter{Allow: []string{"plugins.shell"}}).Apply(tools)
+		Expect(keys()).To(ConsistOf("set_dataset"))
+	})
+})
@@ -0,0 +1,101 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"squadron/aitools"
+)
+
+// Validate checks the limits are positive and each tool is overridden once.
+func (t *ToolResponseConfig) Validate() error {
+	if t.MaxTokens < 0 {
+		return fmt.Errorf("max_tokens must be positive")
+	}
+	if t.MaxItems < 0 {
+		return fmt.Errorf("max_items must be positive")
+	}
+	seen := make(map[string]bool, len(t.Tools))
+	for _, o := range t.Tools {
+		if seen[o.Name] {
+			return fmt.Errorf("tool '%s' declared more than once", o.Name)
+		}
+		seen[o.Name] = true
+		if o.MaxTokens < 0 || o.MaxItems < 0 {
+			return fmt.Errorf("tool '%s': max_tokens and max_items must be positive", o.Name)
+		}
+		if o.MaxTokens == 0 && o.MaxItems == 0 {
+			return fmt.Errorf("tool '%s': set max_tokens or max_items", o.Name)
+		}
+	}
+	return nil
+}
+
+// toolResponseBytes converts max_tokens to bytes, capped at the hard max
+func toolResponseBytes(tokens int) int {
+	if tokens > HardMaxToolResponseTokens {
+		tokens = HardMaxToolResponseTokens
+	}
+	return tokens * bytesPerToken
+}
+
+// ToolResultLimits builds the large-result settings of an agent or commander
+// whose own tool_response block is own (nil when absent). Each limit comes
+// from the most specific place that sets it: a tool override in own, one in
+// the config-wide tool_response block, own's general limit, the config-wide
+// one, and finally the default. toolNames are the references of the tools
+// the caller has, so overrides by bare name can be matched to them; names
+// are also matched as written.
+func (c *Config) ToolResultLimits(own *ToolResponseConfig, toolNames []string) aitools.LargeResultConfig {
+	var blocks []*ToolResponseConfig // least specific first
+	if c != nil && c.ToolResponse != nil {
+		blocks = append(blocks, c.ToolResponse)
+	}
+	if own != nil {
+		blocks = append(blocks, own)
+	}
+
+	maxTokens, maxItems := 0, 0
+	for _, b := range blocks {
+		if b.MaxTokens > 0 {
+			maxTokens = b.MaxTokens
+		}
+		if b.MaxItems > 0 {
+			maxItems = b.MaxItems
+		}
+	}
+	limits := aitools.DefaultLargeResultConfig()
+	if maxTokens > 0 {
+		limits = aitools.LargeResultConfigWithMaxSize(toolResponseBytes(maxTokens))
+	}
+	if maxItems > 0 {
+		limits.ItemThreshold = maxItems + 1
+	}
+
+	for _, b := range blocks {
+		for _, o := range b.Tools {
+			names := []string{o.Name}
+			for _, ref := range toolNames {
+				if ref != o.Name && ref[strings.LastIndex(ref, ".")+1:] == o.Name {
+					names = append(names, ref)
+				}
+			}
+			for _, name := range names {
+				for _, key := range []string{name, aitools.SanitizeToolName(name)} {
+					if limits.Tools == nil {
+						limits.Tools = make(map[string]aitools.ToolLimits)
+					}
+					l := limits.Tools[key]
+					if o.MaxTokens > 0 {
+						l.ByteThreshold = toolResponseBytes(o.MaxTokens)
+					}
+					if o.MaxItems > 0 {
+						l.ItemThreshold = o.MaxItems + 1
+					}
+					limits.Tools[key] = l
+				}
+			}
+		}
+	}
+	return limits
+}
@@ -10,7 +10,7 @@ import (
 
 // BuildToolsMap creates a map of tool name -> Tool implementation from the agent's tools list
 // Tools can be:
-//   - Builtin tools: builtins.http.get, builtins.human.ask
+//   - Builtin tools: builtins.http.get, builtins.human.ask, builtins.shell.run_command, builtins.sql.query
 //   - Plugin tools: plugins.pinger.echo (external plugins)
 //   - MCP tools: mcp.filesystem.read_file (consumer-side MCP servers)
 //   - Custom tools: tools.weather, tools.shout (defined in HCL)
@@ -21,7 +21,10 @@ import (
 // humanBridge is optional and powers the `builtins.human.ask` tool. Pass
 // nil when no commander is attached; the tool is still registered and returns
 // a stable "[no human available]" observation to the agent instead of blocking.
-func BuildToolsMap(agentTools []string, customTools []CustomTool, loadedPlugins map[string]*plugin.PluginClient, loadedMCPClients map[string]*squadronmcp.Client, datasetStore aitools.DatasetStore, humanBridge aitools.HumanInputBridge) map[string]aitools.Tool {
+//
+// builtins carries the shell allowlists, http auth rules and databases for
+// the builtins.shell, builtins.http and builtins.sql tools.
+func BuildToolsMap(agentTools []string, customTools []CustomTool, loadedPlugins map[string]*plugin.PluginClient, loadedMCPClients map[string]*squadronmcp.Client, builtins BuiltinSettings, datasetStore aitools.DatasetStore, humanBridge aitools.HumanInputBridge) map[string]aitools.Tool {
 	tools := make(map[string]aitools.Tool)
 
 	// Build a lookup map for custom tool definitions
@@ -45,7 +48,7 @@ func BuildToolsMap(agentTools []string, customTools []CustomTool, loadedPlugins
 				if builtinToolList, ok := BuiltinTools[namespaceName]; ok {
 					for _, toolName := range builtinToolList {
 						ref := "builtins." + namespaceName + "." + toolName
-						tool := GetBuiltinTool(ref, datasetStore, humanBridge)
+						tool := GetBuiltinTool(ref, builtins, datasetStore, humanBridge)
 						if tool != nil {
 							tools[ref] = tool
 						}
@@ -101,7 +104,7 @@ func BuildToolsMap(agentTools []string, customTools []CustomTool, loadedPlugins
 
 		// Check if it's a builtin tool reference (builtins.{namespace}.{tool})
 		if IsBuiltinTool(toolRef) {
-			tool := GetBuiltinTool(toolRef, datasetStore, humanBridge)
+			tool := GetBuiltinTool(toolRef, builtins, datasetStore, humanBridge)
 			if tool != nil {
 				tools[toolRef] = tool
 			}
@@ -157,22 +160,44 @@ func BuildToolsMap(agentTools []string, customTools []CustomTool, loadedPlugins
 	return tools
 }
 
+// BuiltinSettings carries what built-in tools need from the config and the
+// run. The zero value leaves the shell and sql tools disabled and adds no
+// http auth.
+type BuiltinSettings struct {
+	Shell     *ShellConfig
+	HTTP      *HTTPConfig
+	Databases []DatabaseConfig
+	Secrets   map[string]string // Secret values for http auth header templates
+}
+
+// Builtins returns the config's built-in tool settings, with secrets for
+// the http auth header templates.
+func (c *Config) Builtins(secrets map[string]string) BuiltinSettings {
+	return BuiltinSettings{Shell: c.Shell, HTTP: c.HTTP, Databases: c.Databases, Secrets: secrets}
+}
+
 // GetBuiltinTool returns the aitools.Tool for a built-in tool reference.
 // datasetStore is optional and required for dataset tools.
 // humanBridge is optional; when nil, the ask tool returns a stable
 // "[no human available]" observation rather than blocking.
-func GetBuiltinTool(ref string, datasetStore aitools.DatasetStore, humanBridge aitools.HumanInputBridge) aitools.Tool {
+// Without builtins.Shell the shell tools refuse every call, and without
+// builtins.Databases so do the sql tools.
+func GetBuiltinTool(ref string, builtins BuiltinSettings, datasetStore aitools.DatasetStore, humanBridge aitools.HumanInputBridge) aitools.Tool {
+	auth := builtins.HTTP.Authenticator(builtins.Secrets)
+	shell := builtins.Shell
 	switch ref {
 	case "builtins.http.get":
-		return &aitools.HTTPGetTool{}
+		return &aitools.HTTPGetTool{Auth: auth}
 	case "builtins.http.post":
-		return &aitools.HTTPPostTool{}
+		return &aitools.HTTPPostTool{Auth: auth}
 	case "builtins.http.put":
-		return &aitools.HTTPPutTool{}
+		return &aitools.HTTPPutTool{Auth: auth}
 	case "builtins.http.patch":
-		return &aitools.HTTPPatchTool{}
+		return &aitools.HTTPPatchTool{Auth: auth}
 	case "builtins.http.delete":
-		return &aitools.HTTPDeleteTool{}
+		return &aitools.HTTPDeleteTool{Auth: auth}
+	case "builtins.http.paginate":
+		return &aitools.HTTPPaginateTool{Auth: auth}
 	case "builtins.dataset.set":
 		return &aitools.SetDatasetTool{Store: datasetStore}
 	case "builtins.dataset.sample":
@@ -185,6 +210,18 @@ func GetBuiltinTool(ref string, datasetStore aitools.DatasetStore, humanBridge a
 		return &aitools.CurrentTimeTool{}
 	case "builtins.human.ask":
 		return &aitools.HumanInputTool{Bridge: humanBridge}
+	case "builtins.shell.run_command":
+		return &aitools.ShellRunCommandTool{Policy: shell.Policy()}
+	case "builtins.shell.read_file":
+		return &aitools.ShellReadFileTool{Policy: shell.Policy()}
+	case "builtins.shell.write_file":
+		return &aitools.ShellWriteFileTool{Policy: shell.Policy()}
+	case "builtins.sql.query":
+		return &aitools.SQLQueryTool{Databases: sqlDatabases(builtins.Databases)}
+	case "builtins.sql.schema":
+		return &aitools.SQLSchemaTool{Databases: sqlDatabases(builtins.Databases)}
+	case "builtins.sql.explain":
+		return &aitools.SQLExplainTool{Databases: sqlDatabases(builtins.Databases)}
 	default:
 		return nil
 	}
@@ -0,0 +1,164 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/tryfunc"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+)
+
+// Attributes a `when` guard can read from query.<task>.
+var whenQueryAttrs = map[string]bool{"output": true, "status": true, "skipped": true}
+
+// parseWhenExpr checks that a task's `when` guard only reads vars, inputs,
+// each (for_each instances), and query.<task>.{output,status,skipped}. Dependency outputs aren't known
+// until the task is about to run, so the expression is evaluated then.
+func parseWhenExpr(expr hcl.Expression) error {
+	for _, trav := range expr.Variables() {
+		switch trav.RootName() {
+		case "vars", "inputs", "each":
+		case "query":
+			if _, _, err := whenQueryRef(trav); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("when: unknown reference '%s' (expected vars, inputs, or query.<task>)", trav.RootName())
+		}
+	}
+	return nil
+}
+
+// whenQueryRef returns the task name and attribute of a query.<task>.<attr>
+// traversal. A for_each instance is read as query.<task>["key"].<attr>.
+func whenQueryRef(trav hcl.Traversal) (string, string, error) {
+	if len(trav) < 3 {
+		return "", "", fmt.Errorf("when: query references must look like query.<task>.output, query.<task>.status, or query.<task>.skipped")
+	}
+	task, ok := trav[1].(hcl.TraverseAttr)
+	if !ok {
+		return "", "", fmt.Errorf("when: query references must name a task, like query.<task>.output")
+	}
+	name := task.Name
+	rest := trav[2:]
+	if idx, ok := rest[0].(hcl.TraverseIndex); ok {
+		if idx.Key.IsNull() || !idx.Key.IsKnown() || idx.Key.Type() != cty.String || len(rest) < 2 {
+			return "", "", fmt.Errorf("when: for_each tasks are read as query.%s[\"key\"].output", task.Name)
+		}
+		name = InstanceName(task.Name, idx.Key.AsString())
+		rest = rest[1:]
+	}
+	attr, ok := rest[0].(hcl.TraverseAttr)
+	if !ok || !whenQueryAttrs[attr.Name] {
+		return "", "", fmt.Errorf("when: query.%s supports only output, status, and skipped", name)
+	}
+	return name, attr.Name, nil
+}
+
+// WhenDependencies returns the tasks a `when` guard reads through query.
+func (t *Task) WhenDependencies() []string {
+	if t.WhenExpr == nil {
+		return nil
+	}
+	var names []string
+	seen := make(map[string]bool)
+	for _, trav := range t.WhenExpr.Variables() {
+		if trav.RootName() != "query" {
+			continue
+		}
+		name, _, err := whenQueryRef(trav)
+		if err != nil || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	return names
+}
+
+// EvaluateWhen reports whether the task should run. Tasks without a guard
+// always run. query maps each dependency the guard reads to an object with
+// output, status, and skipped attributes.
+func (t *Task) EvaluateWhen(vars, inputs, query map[string]cty.Value) (bool, error) {
+	if t.WhenExpr == nil {
+		return true, nil
+	}
+	variables := t.EvalVariables(vars, inputs)
+	variables["query"] = cty.ObjectVal(nestInstances(query))
+	ctx := &hcl.EvalContext{
+		Variables: variables,
+		Functions: map[string]function.Function{
+			"try": tryfunc.TryFunc,
+			"can": tryfunc.CanFunc,
+		},
+	}
+	val, diags := t.WhenExpr.Value(ctx)
+	if diags.HasErrors() {
+		return false, fmt.Errorf("evaluating when: %s", diags.Error())
+	}
+	if val.IsNull() || !val.IsKnown() || val.Type() != cty.Bool {
+		return false, fmt.Errorf("evaluating when: expression must be a bool, got %s", val.Type().FriendlyName())
+	}
+	return val.True(), nil
+}
+
+// nestInstances groups for_each instance entries (group["key"]) under their
+// group, so query.group["key"] resolves.
+func nestInstances(query map[string]cty.Value) map[string]cty.Value {
+	out := make(map[string]cty.Value, len(query))
+	groups := make(map[string]map[string]cty.Value)
+	for name, v := range query {
+		group, key, ok := splitInstanceName(name)
+		if !ok {
+			out[name] = v
+			continue
+		}
+		if groups[group] == nil {
+			groups[group] = make(map[string]cty.Value)
+		}
+		groups[group][key] = v
+	}
+	for group, entries := range groups {
+		out[group] = cty.ObjectVal(entries)
+	}
+	return out
+}
+
+// validateWhenRefs checks that every task a `when` guard reads through query
+// is an upstream dependency, so its result exists when the guard runs.
+func (w *Mission) validateWhenRefs() error {
+	for _, t := range w.Tasks {
+		deps := t.WhenDependencies()
+		if len(deps) == 0 {
+			continue
+		}
+		upstream := w.upstreamTasks(t.Name)
+		for _, dep := range deps {
+			if !upstream[dep] {
+				return fmt.Errorf("task '%s': when reads query.%s, but '%s' is not an upstream dependency (add it to depends_on)", t.Name, dep, dep)
+			}
+		}
+	}
+	return nil
+}
+
+// upstreamTasks returns every task reachable through depends_on.
+func (w *Mission) upstreamTasks(name string) map[string]bool {
+	out := make(map[string]bool)
+	var walk func(string)
+	walk = func(n string) {
+		t := w.GetTaskByName(n)
+		if t == nil {
+			return
+		}
+		for _, dep := range t.DependsOn {
+			if !out[dep] {
+				out[dep] = true
+				walk(dep)
+			}
+		}
+	}
+	walk(name)
+	return out
+}
@@ -3,8 +3,22 @@ export default {
   engage: 'engage',
   disengage: 'disengage',
   verify: 'verify',
+  validate: 'validate',
   chat: 'chat',
   mission: 'mission',
+  pause: 'pause',
+  resume: 'resume',
+  rerun: 'rerun',
+  'mcp-serve': 'mcp-serve',
+  api: 'api',
+  users: 'users',
+  schedule: 'schedule',
+  report: 'report',
+  graph: 'graph',
+  missions: 'missions',
+  datasets: 'datasets',
+  sessions: 'sessions',
+  store: 'store',
   vars: 'vars',
   upgrade: 'upgrade',
 }
@@ -1,4 +1,5 @@
 export default {
   'no-code-multi-agent-workflow': 'No-Code Multi-Agent Workflow',
   'distributing-plugins': 'Distributing Plugins',
+  'embedding-in-go': 'Embedding in Go',
 }
@@ -7,7 +7,12 @@ export default {
   iteration: 'Iteration',
   folders: 'Memory & Scratchpad',
   packets: 'Packets',
+  knowledge: 'Knowledge Base',
+  artifacts: 'Artifacts',
   'internal-tools': 'Internal Tools',
   budgets: 'Budgets',
+  notifications: 'Notifications',
+  policies: 'Tool Policies',
   schedules: 'Schedules & Triggers',
+  templates: 'Templates',
 }
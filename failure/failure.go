@@ -0,0 +1,132 @@
+// Package failure classifies why a task, iteration, or agent run failed.
+//
+// Errors keep their messages as they are; the class travels alongside as a
+// wrapped sentinel, so it survives fmt.Errorf("...: %w", err) on the way from
+// the provider through agents and commanders up to the runner. The runner
+// stores the class's Code on the task record, and iteration retries use it to
+// skip failures that would only happen again.
+package failure
+
+import "errors"
+
+// Code identifies a class of failure. Codes are stored with task records, so
+// their values must not change.
+type Code string
+
+const (
+	CodeRateLimited      Code = "rate_limited"
+	CodeContextTooLong   Code = "context_too_long"
+	CodeProviderError    Code = "provider_error"
+	CodeBudgetExceeded   Code = "budget_exceeded"
+	CodeTimeout          Code = "timeout"
+	CodeToolFailure      Code = "tool_failure"
+	CodeApprovalRejected Code = "approval_rejected"
+	CodeMarkedFailed     Code = "marked_failed"
+	CodeMaxTurns         Code = "max_turns"
+	CodeLoopDetected     Code = "loop_detected"
+	CodeUnknown          Code = "unknown"
+)
+
+// Class is a sentinel error for one Code. Match it with errors.Is.
+type Class struct {
+	code Code
+}
+
+func (c *Class) Error() string     { return string(c.code) }
+func (c *Class) FailureCode() Code { return c.code }
+
+var (
+	// ErrRateLimited: the provider kept rejecting requests with 429 after retries.
+	ErrRateLimited = &Class{CodeRateLimited}
+	// ErrContextTooLong: the conversation no longer fits the model's context window.
+	ErrContextTooLong = &Class{CodeContextTooLong}
+	// ErrProviderError: any other error returned by the LLM provider, or a
+	// commander giving up on the model's responses.
+	ErrProviderError = &Class{CodeProviderError}
+	// ErrBudgetExceeded: the mission's token or cost budget ran out.
+	ErrBudgetExceeded = &Class{CodeBudgetExceeded}
+	// ErrTimeout: the task or iteration ran past its timeout.
+	ErrTimeout = &Class{CodeTimeout}
+	// ErrToolFailure: an agent called by the commander failed outside the
+	// provider.
+	ErrToolFailure = &Class{CodeToolFailure}
+	// ErrApprovalRejected: a reviewer rejected the task's output.
+	ErrApprovalRejected = &Class{CodeApprovalRejected}
+	// ErrMarkedFailed: the commander called task_complete with succeed=false.
+	ErrMarkedFailed = &Class{CodeMarkedFailed}
+	// ErrMaxTurns: the commander used up the task's max_turns without
+	// completing it.
+	ErrMaxTurns = &Class{CodeMaxTurns}
+	// ErrLoopDetected: the commander kept repeating the same tool call with
+	// the same result.
+	ErrLoopDetected = &Class{CodeLoopDetected}
+)
+
+var classes = []*Class{
+	ErrRateLimited, ErrContextTooLong, ErrProviderError, ErrBudgetExceeded,
+	ErrTimeout, ErrToolFailure, ErrApprovalRejected, ErrMarkedFailed,
+	ErrMaxTurns, ErrLoopDetected,
+}
+
+// ClassFor returns the sentinel for code, or nil if there is none (as for
+// CodeUnknown).
+func ClassFor(code Code) *Class {
+	for _, c := range classes {
+		if c.code == code {
+			return c
+		}
+	}
+	return nil
+}
+
+// Coder is implemented by errors that know their own failure class, such as
+// the runner's timeout and budget errors.
+type Coder interface {
+	FailureCode() Code
+}
+
+// Wrap returns err tagged with class. The message is err's. A nil err stays
+// nil, an err that already has a class keeps it, and a nil class leaves err
+// as it is.
+func Wrap(class *Class, err error) error {
+	if err == nil || class == nil {
+		return err
+	}
+	var c Coder
+	if errors.As(err, &c) {
+		return err
+	}
+	return &classified{err: err, class: class}
+}
+
+type classified struct {
+	err   error
+	class *Class
+}
+
+func (e *classified) Error() string   { return e.err.Error() }
+func (e *classified) Unwrap() []error { return []error{e.err, e.class} }
+
+// CodeOf returns the failure class of err: the first Coder found in its
+// chain, CodeUnknown if there is none, and "" for a nil err.
+func CodeOf(err error) Code {
+	if err == nil {
+		return ""
+	}
+	var c Coder
+	if errors.As(err, &c) {
+		return c.FailureCode()
+	}
+	return CodeUnknown
+}
+
+// Retryable reports whether a failure with this code might succeed if run
+// again. Running out of budget or context, or being rejected by a reviewer,
+// would only happen again.
+func Retryable(code Code) bool {
+	switch code {
+	case CodeBudgetExceeded, CodeContextTooLong, CodeApprovalRejected:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,82 @@
+package failure
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+type selfCoded struct{}
+
+func (selfCoded) Error() string     { return "over budget" }
+func (selfCoded) FailureCode() Code { return CodeBudgetExceeded }
+
+func TestWrapKeepsMessageAndClass(t *testing.T) {
+	base := errors.New("POST /v1/messages: 429 Too Many Requests")
+	err := fmt.Errorf("agent failed: %w", Wrap(ErrRateLimited, base))
+
+	if err.Error() != "agent failed: POST /v1/messages: 429 Too Many Requests" {
+		t.Errorf("unexpected message %q", err.Error())
+	}
+	if !errors.Is(err, ErrRateLimited) {
+		t.Error("expected errors.Is(err, ErrRateLimited)")
+	}
+	if !errors.Is(err, base) {
+		t.Error("expected the original error to stay in the chain")
+	}
+	if got := CodeOf(err); got != CodeRateLimited {
+		t.Errorf("CodeOf = %q, want %q", got, CodeRateLimited)
+	}
+}
+
+func TestWrapKeepsExistingClass(t *testing.T) {
+	err := Wrap(ErrToolFailure, fmt.Errorf("call_agent: %w", Wrap(ErrContextTooLong, errors.New("prompt is too long"))))
+	if got := CodeOf(err); got != CodeContextTooLong {
+		t.Errorf("CodeOf = %q, want %q", got, CodeContextTooLong)
+	}
+	if errors.Is(err, ErrToolFailure) {
+		t.Error("expected the inner class to win")
+	}
+
+	if got := CodeOf(Wrap(ErrToolFailure, selfCoded{})); got != CodeBudgetExceeded {
+		t.Errorf("CodeOf = %q, want %q", got, CodeBudgetExceeded)
+	}
+}
+
+func TestCodeOf(t *testing.T) {
+	if got := CodeOf(nil); got != "" {
+		t.Errorf("CodeOf(nil) = %q, want empty", got)
+	}
+	if got := CodeOf(errors.New("boom")); got != CodeUnknown {
+		t.Errorf("CodeOf(plain) = %q, want %q", got, CodeUnknown)
+	}
+	if Wrap(ErrTimeout, nil) != nil {
+		t.Error("expected Wrap(nil) to be nil")
+	}
+}
+
+func TestClassFor(t *testing.T) {
+	if ClassFor(CodeTimeout) != ErrTimeout {
+		t.Error("expected ClassFor(CodeTimeout) to be ErrTimeout")
+	}
+	if ClassFor(CodeUnknown) != nil {
+		t.Error("expected no class for CodeUnknown")
+	}
+	plain := errors.New("boom")
+	if Wrap(ClassFor(CodeUnknown), plain) != plain {
+		t.Error("expected a nil class to leave the error unchanged")
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	for _, code := range []Code{CodeRateLimited, CodeProviderError, CodeTimeout, CodeToolFailure, CodeMarkedFailed, CodeMaxTurns, CodeLoopDetected, CodeUnknown} {
+		if !Retryable(code) {
+			t.Errorf("expected %q to be retryable", code)
+		}
+	}
+	for _, code := range []Code{CodeBudgetExceeded, CodeContextTooLong, CodeApprovalRejected} {
+		if Retryable(code) {
+			t.Errorf("expected %q not to be retryable", code)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package awssig
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Cases from the AWS Signature Version 4 test suite.
+func TestSign(t *testing.T) {
+	creds := Credentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"}
+	now := time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)
+	for name, tc := range map[string]struct {
+		url       string
+		signature string
+	}{
+		"get-vanilla":                 {"https://example.amazonaws.com/", "5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31"},
+		"get-vanilla-query-order-key": {"https://example.amazonaws.com/?Param2=value2&Param1=value1", "b97d918cfa904a5beff61c982a1b6f458b799221646efd99d3219ec94cdf2500"},
+	} {
+		req, _ := http.NewRequest(http.MethodGet, tc.url, nil)
+		Sign(req, EmptyPayloadHash, creds, "us-east-1", "service", now)
+		want := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=" + tc.signature
+		if got := req.Header.Get("Authorization"); got != want {
+			t.Errorf("%s: Authorization = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestSignAddsSessionToken(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodPost, "https://sqs.us-east-1.amazonaws.com/123/queue", nil)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	Sign(req, EmptyPayloadHash, Credentials{AccessKeyID: "AKID", SecretAccessKey: "secret", SessionToken: "session"}, "us-east-1", "sqs", time.Unix(0, 0))
+	if got := req.Header.Get("X-Amz-Security-Token"); got != "session" {
+		t.Errorf("X-Amz-Security-Token = %q", got)
+	}
+	want := "SignedHeaders=content-type;host;x-amz-date;x-amz-security-token,"
+	if got := req.Header.Get("Authorization"); !strings.Contains(got, want) {
+		t.Errorf("Authorization = %q, want it to contain %q", got, want)
+	}
+}
+
+func TestCanonicalQuery(t *testing.T) {
+	q := url.Values{"b": {"two words", "a+b"}, "a": {"x/y~z"}}
+	if got, want := canonicalQuery(q), "a=x%2Fy~z&b=a%2Bb&b=two%20words"; got != want {
+		t.Errorf("canonicalQuery = %q, want %q", got, want)
+	}
+}
+
+func TestCredentialsFromEnv(t *testing.T) {
+	env := map[string]string{"AWS_ACCESS_KEY_ID": "AKID"}
+	if _, err := CredentialsFromEnv(func(k string) string { return env[k] }); err == nil {
+		t.Error("expected an error without a secret key")
+	}
+	env["AWS_SECRET_ACCESS_KEY"] = "secret"
+	creds, err := CredentialsFromEnv(func(k string) string { return env[k] })
+	if err != nil || creds.AccessKeyID != "AKID" || creds.SecretAccessKey != "secret" {
+		t.Errorf("creds = %+v, err = %v", creds, err)
+	}
+}
@@ -23,8 +23,27 @@ func NewAnthropicProvider(apiKey, baseURL string) *AnthropicProvider {
 	return &AnthropicProvider{client: &client}
 }
 
+// anthropicHeaders sends req.Headers with the request.
+func anthropicHeaders(req *ChatRequest) []option.RequestOption {
+	var opts []option.RequestOption
+	for k, v := range req.Headers {
+		opts = append(opts, option.WithHeader(k, v))
+	}
+	return opts
+}
+
+// anthropicSampling sets temperature and top_p. Anthropic has no seed.
+func anthropicSampling(params *anthropic.MessageNewParams, sampling Sampling) {
+	if sampling.Temperature != nil {
+		params.Temperature = anthropic.Float(*sampling.Temperature)
+	}
+	if sampling.TopP != nil {
+		params.TopP = anthropic.Float(*sampling.TopP)
+	}
+}
+
 func (p *AnthropicProvider) Chat(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
-	msgs, systemPrompts := p.convertMessages(req.Messages, req.PromptCaching, req.ConversationCaching)
+	msgs, systemPrompts := p.convertMessages(req.Messages, req.PromptCaching, req.ConversationCaching, req.CachePrefix)
 
 	maxTokens := int64(req.MaxTokens)
 	if maxTokens == 0 {
@@ -33,8 +52,7 @@ func (p *AnthropicProvider) Chat(ctx context.Context, req *ChatRequest) (*ChatRe
 
 	// Extended thinking: budget_tokens must be < max_tokens, so clamp upward
 	// when needed. Anthropic also requires temperature=1 and rejects
-	// top_p/top_k when thinking is on; we don't set those today, but any
-	// future code that does must skip them when params.Thinking is set.
+	// top_p/top_k when thinking is on, so anthropicSampling skips them then.
 	var thinkingBudget int64
 	if req.Reasoning != "" {
 		thinkingBudget = anthropicBudgetTokens(req.Reasoning)
@@ -51,6 +69,8 @@ func (p *AnthropicProvider) Chat(ctx context.Context, req *ChatRequest) (*ChatRe
 
 	if thinkingBudget > 0 {
 		params.Thinking = anthropic.ThinkingConfigParamOfEnabled(thinkingBudget)
+	} else {
+		anthropicSampling(&params, req.Sampling)
 	}
 
 	if len(systemPrompts) > 0 {
@@ -65,7 +85,7 @@ func (p *AnthropicProvider) Chat(ctx context.Context, req *ChatRequest) (*ChatRe
 		params.Tools = p.convertTools(req.Tools)
 	}
 
-	resp, err := p.client.Messages.New(ctx, params)
+	resp, err := p.client.Messages.New(ctx, params, anthropicHeaders(req)...)
 	if err != nil {
 		return nil, err
 	}
@@ -125,7 +145,7 @@ func (p *AnthropicProvider) Chat(ctx context.Context, req *ChatRequest) (*ChatRe
 }
 
 func (p *AnthropicProvider) ChatStream(ctx context.Context, req *ChatRequest) (<-chan StreamChunk, error) {
-	msgs, systemPrompts := p.convertMessages(req.Messages, req.PromptCaching, req.ConversationCaching)
+	msgs, systemPrompts := p.convertMessages(req.Messages, req.PromptCaching, req.ConversationCaching, req.CachePrefix)
 
 	maxTokens := int64(req.MaxTokens)
 	if maxTokens == 0 {
@@ -148,6 +168,8 @@ func (p *AnthropicProvider) ChatStream(ctx context.Context, req *ChatRequest) (<
 
 	if thinkingBudget > 0 {
 		params.Thinking = anthropic.ThinkingConfigParamOfEnabled(thinkingBudget)
+	} else {
+		anthropicSampling(&params, req.Sampling)
 	}
 
 	if len(systemPrompts) > 0 {
@@ -162,7 +184,7 @@ func (p *AnthropicProvider) ChatStream(ctx context.Context, req *ChatRequest) (<
 		params.Tools = p.convertTools(req.Tools)
 	}
 
-	stream := p.client.Messages.NewStreaming(ctx, params)
+	stream := p.client.Messages.NewStreaming(ctx, params, anthropicHeaders(req)...)
 
 	chunks := make(chan StreamChunk)
 
@@ -348,7 +370,7 @@ func (p *AnthropicProvider) convertTools(tools []ToolDefinition) []anthropic.Too
 	return result
 }
 
-func (p *AnthropicProvider) convertMessages(messages []Message, promptCaching bool, conversationCaching bool) ([]anthropic.MessageParam, []anthropic.TextBlockParam) {
+func (p *AnthropicProvider) convertMessages(messages []Message, promptCaching bool, conversationCaching bool, cachePrefix int) ([]anthropic.MessageParam, []anthropic.TextBlockParam) {
 	var msgs []anthropic.MessageParam
 	var systemPrompts []anthropic.TextBlockParam
 
@@ -373,6 +395,11 @@ func (p *AnthropicProvider) convertMessages(messages []Message, promptCaching bo
 		if len(systemPrompts) > 0 {
 			systemPrompts[len(systemPrompts)-1].CacheControl = anthropic.CacheControlEphemeralParam{Type: "ephemeral"}
 		}
+		// And at the end of the prefix shared with other sessions, so they
+		// hit the cache even where their later system prompts differ.
+		if cachePrefix > 0 && cachePrefix < len(systemPrompts) {
+			systemPrompts[cachePrefix-1].CacheControl = anthropic.CacheControlEphemeralParam{Type: "ephemeral"}
+		}
 	}
 
 	if conversationCaching {
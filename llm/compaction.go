@@ -0,0 +1,325 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// CompactionPolicy configures automatic context compaction for a session.
+// After each response whose input tokens exceed TokenLimit, the session
+// replaces everything but the last TurnRetention turns with a summary.
+// System prompts are stored separately and are never compacted.
+type CompactionPolicy struct {
+	TokenLimit    int // Compact once a response reports more input tokens than this
+	TurnRetention int // Keep this many recent turns verbatim
+	// SummaryProvider and SummaryModel, when set, write the summary of the
+	// compacted turns — usually a cheaper model than the session's. Without
+	// them, or when the summary call fails, the summary is built from the
+	// tool calls and answers in the history.
+	SummaryProvider Provider
+	SummaryModel    string
+	// Context, when set, returns caller state (dataset progress, subtasks)
+	// that is added to every summary.
+	Context func() string
+}
+
+// CompactionEvent describes one automatic compaction.
+type CompactionEvent struct {
+	InputTokens       int
+	TokenLimit        int
+	MessagesCompacted int
+	TurnRetention     int
+	SummaryModel      string // Model that wrote the summary; empty for the built-in digest
+}
+
+// compactionSummaryPrompt instructs the summary model.
+const compactionSummaryPrompt = `You compress the earlier part of an AI agent's working session so it can continue within its context window.
+Write a concise summary of the transcript you are given. Keep every fact, identifier, number, file name, URL and decision the agent will still need, what has been completed, and what was still in progress. Drop chit-chat, repeated attempts and raw tool output that has already been acted on.
+Reply with the summary only.`
+
+// maxSummaryTranscriptBytes caps how much of a single message is sent to the
+// summary model or handed off to another agent.
+const maxSummaryTranscriptBytes = 4000
+
+// SetCompaction enables automatic compaction (nil disables it).
+func (s *Session) SetCompaction(p *CompactionPolicy) {
+	s.compaction = p
+}
+
+// SetCompactionObserver registers a callback invoked after each automatic
+// compaction, so the caller can emit a compaction event.
+func (s *Session) SetCompactionObserver(fn func(CompactionEvent)) {
+	s.onCompaction = fn
+}
+
+// compactIfNeeded runs the session's compaction policy after a response.
+func (s *Session) compactIfNeeded(ctx context.Context, inputTokens int) {
+	p := s.compaction
+	if p == nil || p.TokenLimit <= 0 || inputTokens <= p.TokenLimit {
+		return
+	}
+	s.compact(ctx, inputTokens, p.TokenLimit)
+}
+
+// compact applies the compaction policy because inputTokens went over limit.
+// Returns false if there was nothing old enough to compact.
+func (s *Session) compact(ctx context.Context, inputTokens, limit int) bool {
+	p := s.compaction
+	end := s.compactionBoundary(p.TurnRetention)
+	if end <= 0 {
+		return false
+	}
+
+	var extra string
+	if p.Context != nil {
+		extra = p.Context()
+	}
+	summary, summaryModel := s.buildCompactionSummary(s.messages[:end]), ""
+	if p.SummaryProvider != nil && p.SummaryModel != "" {
+		written, err := s.summarize(ctx, p.SummaryProvider, p.SummaryModel, s.messages[:end])
+		if err != nil {
+			log.Printf("[LLM] Compaction summary with %s failed, using built-in summary: %v", p.SummaryModel, err)
+		} else {
+			summary, summaryModel = s.buildModelSummary(s.messages[:end], written), p.SummaryModel
+		}
+	}
+	s.replaceWithSummary(end, withExtraContext(summary, extra))
+
+	s.logMessage("Compaction", fmt.Sprintf("Compacted %d messages into summary (%d input tokens > %d). Retained last %d turns.", end, inputTokens, limit, p.TurnRetention))
+	if s.onCompaction != nil {
+		s.onCompaction(CompactionEvent{
+			InputTokens:       inputTokens,
+			TokenLimit:        limit,
+			MessagesCompacted: end,
+			TurnRetention:     p.TurnRetention,
+			SummaryModel:      summaryModel,
+		})
+	}
+	return true
+}
+
+// compactionBoundary returns how many leading messages to compact so the
+// last turnRetention turns stay intact. The boundary never falls between a
+// tool call and its result: a retained history may not start with tool
+// results whose tool_use was compacted away.
+func (s *Session) compactionBoundary(turnRetention int) int {
+	// A turn is a user message + assistant response pair (2 messages)
+	end := len(s.messages) - turnRetention*2
+	if end <= 0 {
+		return 0
+	}
+	for end < len(s.messages) && isToolResultMessage(s.messages[end]) {
+		end++
+	}
+	if end >= len(s.messages) {
+		return 0
+	}
+	return end
+}
+
+func isToolResultMessage(m Message) bool {
+	if m.Role != RoleUser {
+		return false
+	}
+	for _, part := range m.Parts {
+		if part.Type == ContentTypeToolResult {
+			return true
+		}
+	}
+	return false
+}
+
+// replaceWithSummary replaces the first end messages with a summary message.
+func (s *Session) replaceWithSummary(end int, summary string) {
+	newMessages := make([]Message, 0, 1+len(s.messages)-end)
+	newMessages = append(newMessages, Message{
+		Role:    RoleUser,
+		Content: summary,
+	})
+	newMessages = append(newMessages, s.messages[end:]...)
+	s.messages = newMessages
+}
+
+// withExtraContext injects caller context after the summary's opening tag
+// and description.
+func withExtraContext(summary, extraContext string) string {
+	if extraContext == "" {
+		return summary
+	}
+	insertPoint := strings.Index(summary, "\n\n")
+	if insertPoint == -1 {
+		return summary
+	}
+	return summary[:insertPoint+2] + extraContext + "\n" + summary[insertPoint+2:]
+}
+
+// summarize asks the summary model to condense messages. The transcript is
+// sent as plain text so any provider can summarize any session.
+func (s *Session) summarize(ctx context.Context, provider Provider, model string, messages []Message) (string, error) {
+	req := &ChatRequest{
+		Model: model,
+		Messages: []Message{
+			{Role: RoleSystem, Content: compactionSummaryPrompt},
+			{Role: RoleUser, Content: s.redactor.Redact(RenderTranscript(messages))},
+		},
+	}
+
+	release, err := s.callLimiter.Acquire(ctx)
+	if err != nil {
+		return "", err
+	}
+	resp, err := provider.Chat(ctx, req)
+	release()
+	if err != nil {
+		return "", err
+	}
+	text := strings.TrimSpace(resp.Content)
+	if text == "" {
+		return "", fmt.Errorf("empty summary")
+	}
+	return text, nil
+}
+
+// RenderTranscript renders messages as a plain-text transcript, e.g. for a
+// summary model or an agent handoff. Long entries are truncated.
+func RenderTranscript(messages []Message) string {
+	var sb strings.Builder
+	for _, msg := range messages {
+		if !msg.HasParts() {
+			writeTranscriptEntry(&sb, string(msg.Role), msg.Content)
+			continue
+		}
+		for _, part := range msg.Parts {
+			switch {
+			case part.Type == ContentTypeText:
+				writeTranscriptEntry(&sb, string(msg.Role), part.Text)
+			case part.Type == ContentTypeToolUse && part.ToolUse != nil:
+				writeTranscriptEntry(&sb, "tool call "+part.ToolUse.Name, string(part.ToolUse.Input))
+			case part.Type == ContentTypeToolResult && part.ToolResult != nil:
+				writeTranscriptEntry(&sb, "tool result", part.ToolResult.Content)
+			}
+		}
+	}
+	return sb.String()
+}
+
+func writeTranscriptEntry(sb *strings.Builder, label, text string) {
+	text = truncateSummaryText(text)
+	if text == "" {
+		return
+	}
+	fmt.Fprintf(sb, "[%s]\n%s\n\n", label, text)
+}
+
+// buildModelSummary wraps a model-written summary in the same envelope as the
+// built-in one, keeping the current task verbatim.
+func (s *Session) buildModelSummary(messages []Message, written string) string {
+	var summary strings.Builder
+	summary.WriteString("<COMPACTED_CONTEXT>\n")
+	summary.WriteString("The following is a summary of earlier conversation history that has been compacted to reduce context size:\n\n")
+	if task := currentTask(messages); task != "" {
+		summary.WriteString("**Current Task:**\n")
+		summary.WriteString(task)
+		summary.WriteString("\n\n")
+	}
+	summary.WriteString("**Summary:**\n")
+	summary.WriteString(written)
+	summary.WriteString("\n</COMPACTED_CONTEXT>")
+	return summary.String()
+}
+
+// currentTask returns the most recent task message so the agent knows its
+// current assignment: the first user message, unless a later <NEW_TASK>
+// message replaced it. Tool results are skipped.
+func currentTask(messages []Message) string {
+	var lastTask string
+	for i, msg := range messages {
+		if msg.Role != RoleUser || isToolResultMessage(msg) {
+			continue
+		}
+		content := msg.GetTextContent()
+		if i == 0 || strings.Contains(content, "<NEW_TASK>") {
+			lastTask = content
+		}
+	}
+	return lastTask
+}
+
+// The summary that replaces a session's history when it moves on to a new
+// task is wrapped in these tags.
+const (
+	previousTasksOpen  = "<PREVIOUS_TASKS>"
+	previousTasksClose = "</PREVIOUS_TASKS>"
+	previousTasksIntro = "Your earlier work in this session was summarized before your new task. Raw tool output was dropped; run the tools again if you need the details."
+)
+
+// SummarizeForNewTask replaces the whole conversation with a summary of the
+// work so far, so a session reassigned to a new task keeps what it learned
+// without carrying every raw tool result. The compaction policy's summary
+// model writes the summary when one is set; otherwise it lists the earlier
+// tasks and their answers. Returns the number of messages replaced and the
+// model that wrote the summary (empty for the built-in one).
+func (s *Session) SummarizeForNewTask(ctx context.Context) (int, string) {
+	n := len(s.messages)
+	if n == 0 {
+		return 0, ""
+	}
+
+	var body, summaryModel string
+	if p := s.compaction; p != nil && p.SummaryProvider != nil && p.SummaryModel != "" {
+		written, err := s.summarize(ctx, p.SummaryProvider, p.SummaryModel, s.messages)
+		if err != nil {
+			log.Printf("[LLM] Task summary with %s failed, using built-in summary: %v", p.SummaryModel, err)
+		} else {
+			body, summaryModel = written, p.SummaryModel
+		}
+	}
+	if body == "" {
+		body = taskDigest(s.messages)
+	}
+	s.messages = []Message{{
+		Role:    RoleUser,
+		Content: previousTasksOpen + "\n" + previousTasksIntro + "\n\n" + body + "\n" + previousTasksClose,
+	}}
+
+	s.logMessage("Task Summary", fmt.Sprintf("Summarized %d messages before a new task.", n))
+	return n, summaryModel
+}
+
+// taskDigest lists the tasks in messages with the answers given to them,
+// after the digest of any earlier summary it replaces.
+func taskDigest(messages []Message) string {
+	var sb strings.Builder
+	for i, msg := range messages {
+		content := msg.GetTextContent()
+		switch {
+		case i == 0 && strings.HasPrefix(content, previousTasksOpen):
+			inner := strings.TrimSuffix(strings.TrimPrefix(content, previousTasksOpen), previousTasksClose)
+			if _, earlier, ok := strings.Cut(inner, "\n\n"); ok {
+				sb.WriteString(strings.TrimSpace(earlier) + "\n")
+			}
+		case msg.Role == RoleUser && !isToolResultMessage(msg) && (i == 0 || strings.Contains(content, "<NEW_TASK>")):
+			task := strings.NewReplacer("<NEW_TASK>", "", "</NEW_TASK>", "").Replace(content)
+			fmt.Fprintf(&sb, "- Task: %s\n", truncateSummaryText(task))
+		case msg.Role == RoleAssistant:
+			if answer, ok := extractAnswer(content); ok {
+				fmt.Fprintf(&sb, "  Answer: %s\n", truncateSummaryText(answer))
+			}
+		}
+	}
+	if sb.Len() == 0 {
+		return "(No tasks or answers were recorded.)"
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// truncateSummaryText trims text and caps it at maxSummaryTranscriptBytes.
+func truncateSummaryText(text string) string {
+	text = strings.TrimSpace(text)
+	if len(text) > maxSummaryTranscriptBytes {
+		text = text[:maxSummaryTranscriptBytes] + "...[truncated]"
+	}
+	return text
+}
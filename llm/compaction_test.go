@@ -0,0 +1,211 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// compactionSession returns a session holding three turns whose provider
+// streams "next answer" and reports inputTokens.
+func compactionSession(inputTokens int) *Session {
+	provider := &mockProvider{
+		streamChunks: []StreamChunk{
+			{Content: "next answer"},
+			{Done: true, Usage: &Usage{InputTokens: inputTokens}},
+		},
+	}
+	s := NewSession(provider, "big-model", "sys")
+	s.messages = []Message{
+		{Role: RoleUser, Content: "first task"},
+		{Role: RoleAssistant, Content: "did the first task"},
+		{Role: RoleUser, Content: "second question"},
+		{Role: RoleAssistant, Content: "second answer"},
+	}
+	return s
+}
+
+func TestAutoCompaction_BelowLimitDoesNothing(t *testing.T) {
+	s := compactionSession(100)
+	s.SetCompaction(&CompactionPolicy{TokenLimit: 1000, TurnRetention: 1})
+	called := false
+	s.SetCompactionObserver(func(CompactionEvent) { called = true })
+
+	if _, err := s.SendStream(context.Background(), "third question", nil); err != nil {
+		t.Fatal(err)
+	}
+	if called {
+		t.Fatal("observer should not be called below the token limit")
+	}
+	if len(s.messages) != 6 {
+		t.Fatalf("expected 6 messages, got %d", len(s.messages))
+	}
+}
+
+func TestAutoCompaction_SummarizesWithSummaryModel(t *testing.T) {
+	s := compactionSession(5000)
+	summarizer := &mockProvider{chatResponse: &ChatResponse{Content: "the agent finished the first task"}}
+	s.SetCompaction(&CompactionPolicy{
+		TokenLimit:      1000,
+		TurnRetention:   1,
+		SummaryProvider: summarizer,
+		SummaryModel:    "small-model",
+		Context:         func() string { return "PROGRESS: 2/3" },
+	})
+	var events []CompactionEvent
+	s.SetCompactionObserver(func(ev CompactionEvent) { events = append(events, ev) })
+
+	if _, err := s.SendStream(context.Background(), "third question", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(events) != 1 {
+		t.Fatalf("expected 1 compaction event, got %d", len(events))
+	}
+	ev := events[0]
+	if ev.InputTokens != 5000 || ev.TokenLimit != 1000 || ev.MessagesCompacted != 4 || ev.SummaryModel != "small-model" {
+		t.Fatalf("unexpected event: %+v", ev)
+	}
+
+	if summarizer.lastRequest == nil || summarizer.lastRequest.Model != "small-model" {
+		t.Fatal("summary should be requested from the summary model")
+	}
+	if !strings.Contains(summarizer.lastRequest.Messages[1].Content, "second answer") {
+		t.Fatal("summary request should include the compacted transcript")
+	}
+
+	// Summary + last turn; system prompts are untouched
+	if len(s.messages) != 3 {
+		t.Fatalf("expected 3 messages, got %d", len(s.messages))
+	}
+	summary := s.messages[0].Content
+	for _, want := range []string{"<COMPACTED_CONTEXT>", "first task", "PROGRESS: 2/3", "the agent finished the first task"} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("summary missing %q:\n%s", want, summary)
+		}
+	}
+	if s.messages[1].Content != "third question" || s.messages[2].Content != "next answer" {
+		t.Fatalf("last turn should be retained, got %+v", s.messages[1:])
+	}
+	if got := s.GetSystemPrompts(); len(got) != 1 || got[0] != "sys" {
+		t.Fatalf("system prompts should be preserved, got %v", got)
+	}
+}
+
+func TestAutoCompaction_FallsBackWhenSummaryFails(t *testing.T) {
+	s := compactionSession(5000)
+	s.SetCompaction(&CompactionPolicy{
+		TokenLimit:      1000,
+		TurnRetention:   1,
+		SummaryProvider: &mockProvider{chatErr: errors.New("boom")},
+		SummaryModel:    "small-model",
+	})
+	var event CompactionEvent
+	s.SetCompactionObserver(func(ev CompactionEvent) { event = ev })
+
+	if _, err := s.SendStream(context.Background(), "third question", nil); err != nil {
+		t.Fatal(err)
+	}
+	if event.MessagesCompacted != 4 {
+		t.Fatalf("expected 4 messages compacted, got %d", event.MessagesCompacted)
+	}
+	if event.SummaryModel != "" {
+		t.Fatalf("fallback summary should not report a summary model, got %q", event.SummaryModel)
+	}
+	if !strings.Contains(s.messages[0].Content, "<COMPACTED_CONTEXT>") {
+		t.Fatal("expected built-in summary")
+	}
+}
+
+func TestCompactionBoundary_KeepsToolResultsWithToolCalls(t *testing.T) {
+	s := NewSession(&mockProvider{}, "m")
+	s.messages = []Message{
+		{Role: RoleUser, Content: "task"},
+		{Role: RoleAssistant, Parts: []ContentBlock{{Type: ContentTypeToolUse, ToolUse: &ToolUseBlock{ID: "tc_1", Name: "search", Input: []byte("{}")}}}},
+		{Role: RoleUser, Parts: []ContentBlock{{Type: ContentTypeToolResult, ToolResult: &ToolResultBlock{ToolUseID: "tc_1", Content: "r1"}}}},
+		{Role: RoleAssistant, Parts: []ContentBlock{{Type: ContentTypeToolUse, ToolUse: &ToolUseBlock{ID: "tc_2", Name: "search", Input: []byte("{}")}}}},
+		{Role: RoleUser, Parts: []ContentBlock{{Type: ContentTypeToolResult, ToolResult: &ToolResultBlock{ToolUseID: "tc_2", Content: "r2"}}}},
+		{Role: RoleAssistant, Content: "done"},
+	}
+
+	// Retaining 1 turn would start the history at the tc_2 result; the
+	// boundary moves past it so no orphaned tool result remains.
+	if end := s.compactionBoundary(1); end != 5 {
+		t.Fatalf("expected boundary 5, got %d", end)
+	}
+	if end := s.compactionBoundary(3); end != 0 {
+		t.Fatalf("expected nothing to compact, got %d", end)
+	}
+}
+
+// reassignedSession returns a session that finished one task with a tool
+// call whose raw output should not survive a task summary.
+func reassignedSession() *Session {
+	s := NewSession(&mockProvider{}, "m", "sys")
+	s.messages = []Message{
+		{Role: RoleUser, Content: "<NEW_TASK>\nCount the rows\n</NEW_TASK>"},
+		{Role: RoleAssistant, Parts: []ContentBlock{{Type: ContentTypeToolUse, ToolUse: &ToolUseBlock{ID: "tc_1", Name: "query", Input: []byte("{}")}}}},
+		{Role: RoleUser, Parts: []ContentBlock{{Type: ContentTypeToolResult, ToolResult: &ToolResultBlock{ToolUseID: "tc_1", Content: "RAW ROW DUMP"}}}},
+		{Role: RoleAssistant, Content: "<ANSWER>There are 42 rows.</ANSWER>"},
+	}
+	return s
+}
+
+func TestSummarizeForNewTask_BuiltInDigest(t *testing.T) {
+	s := reassignedSession()
+	n, model := s.SummarizeForNewTask(context.Background())
+	if n != 4 || model != "" {
+		t.Fatalf("got n=%d model=%q", n, model)
+	}
+	if len(s.messages) != 1 {
+		t.Fatalf("expected one summary message, got %d", len(s.messages))
+	}
+	summary := s.messages[0].Content
+	for _, want := range []string{"<PREVIOUS_TASKS>", "- Task: Count the rows", "Answer: There are 42 rows."} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("summary missing %q:\n%s", want, summary)
+		}
+	}
+	if strings.Contains(summary, "RAW ROW DUMP") {
+		t.Error("summary should drop raw tool output")
+	}
+
+	// A second reassignment keeps the earlier tasks.
+	s.messages = append(s.messages,
+		Message{Role: RoleUser, Content: "<NEW_TASK>\nSum the totals\n</NEW_TASK>"},
+		Message{Role: RoleAssistant, Content: "<ANSWER>The total is 7.</ANSWER>"},
+	)
+	s.SummarizeForNewTask(context.Background())
+	summary = s.messages[0].Content
+	if strings.Count(summary, "<PREVIOUS_TASKS>") != 1 {
+		t.Errorf("expected summaries not to nest:\n%s", summary)
+	}
+	if !strings.Contains(summary, "Count the rows") || !strings.Contains(summary, "The total is 7.") {
+		t.Errorf("expected both tasks in the summary:\n%s", summary)
+	}
+}
+
+func TestSummarizeForNewTask_UsesSummaryModel(t *testing.T) {
+	s := reassignedSession()
+	summarizer := &mockProvider{chatResponse: &ChatResponse{Content: "The table has 42 rows."}}
+	s.SetCompaction(&CompactionPolicy{TokenLimit: 1000, SummaryProvider: summarizer, SummaryModel: "small-model"})
+
+	n, model := s.SummarizeForNewTask(context.Background())
+	if n != 4 || model != "small-model" {
+		t.Fatalf("got n=%d model=%q", n, model)
+	}
+	if !strings.Contains(s.messages[0].Content, "The table has 42 rows.") {
+		t.Errorf("expected the model-written summary, got:\n%s", s.messages[0].Content)
+	}
+	if !strings.Contains(summarizer.lastRequest.Messages[1].Content, "RAW ROW DUMP") {
+		t.Error("expected the summary model to see the full transcript")
+	}
+}
+
+func TestSummarizeForNewTask_EmptySession(t *testing.T) {
+	s := NewSession(&mockProvider{}, "m")
+	if n, _ := s.SummarizeForNewTask(context.Background()); n != 0 || len(s.messages) != 0 {
+		t.Fatalf("expected nothing to summarize, got n=%d", n)
+	}
+}
@@ -0,0 +1,127 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+
+	"squadron/failure"
+)
+
+// SessionUsage is the running token total of a session's requests.
+// Responses replayed from the response cache add nothing.
+type SessionUsage struct {
+	Requests         int
+	InputTokens      int
+	OutputTokens     int
+	CacheReadTokens  int
+	CacheWriteTokens int
+	LastPromptTokens int // Prompt size of the latest request, cached tokens included
+}
+
+// Total returns all input, output and cache tokens used so far.
+func (u SessionUsage) Total() int {
+	return u.InputTokens + u.OutputTokens + u.CacheReadTokens + u.CacheWriteTokens
+}
+
+// ContextWindowError is returned instead of sending a request that would not
+// fit the model's context window, even after compaction.
+type ContextWindowError struct {
+	Model     string
+	Window    int // Context window of the model, in tokens
+	Estimated int // Estimated prompt tokens of the refused request
+}
+
+func (e *ContextWindowError) Error() string {
+	return fmt.Sprintf("request to %s needs about %d tokens, more than its %d-token context window", e.Model, e.Estimated, e.Window)
+}
+
+// FailureCode classifies the error as a context overflow.
+func (e *ContextWindowError) FailureCode() failure.Code { return failure.CodeContextTooLong }
+
+// SetContextWindow sets the model's context window in tokens. Requests
+// estimated to exceed it are compacted first when a compaction policy is
+// set, and refused with a *ContextWindowError otherwise. Zero disables the
+// check.
+func (s *Session) SetContextWindow(tokens int) {
+	s.contextWindow = tokens
+}
+
+// ContextWindow returns the window set with SetContextWindow (0 = unknown).
+func (s *Session) ContextWindow() int {
+	return s.contextWindow
+}
+
+// Usage returns the session's running token totals.
+func (s *Session) Usage() SessionUsage {
+	return s.usage
+}
+
+// EstimatedContextTokens estimates the prompt tokens of sending the current
+// history as it is: system prompts, messages and tool definitions.
+func (s *Session) EstimatedContextTokens() int {
+	return s.estimatePromptTokens(&ChatRequest{Messages: s.buildCurrentMessages(), Tools: s.tools})
+}
+
+// RemainingContext estimates how many tokens the history can still grow by
+// before it fills the context window. ok is false when the window is unknown.
+func (s *Session) RemainingContext() (remaining int, ok bool) {
+	if s.contextWindow <= 0 {
+		return 0, false
+	}
+	return s.contextWindow - s.EstimatedContextTokens(), true
+}
+
+// estimatePromptTokens estimates the prompt tokens of req. The rough
+// EstimateRequestTokens count is scaled by how far off it was for the last
+// request the provider reported usage for.
+func (s *Session) estimatePromptTokens(req *ChatRequest) int {
+	n := EstimateRequestTokens(req)
+	if s.promptTokens > 0 && s.promptEstimate > 0 {
+		n = int(int64(n) * int64(s.promptTokens) / int64(s.promptEstimate))
+	}
+	return n
+}
+
+// checkContextWindow runs before each request. A request estimated to
+// exceed the context window is compacted when a compaction policy is set;
+// rebuild then returns the request's messages from the compacted history.
+// If it still doesn't fit, a *ContextWindowError is returned.
+func (s *Session) checkContextWindow(ctx context.Context, req *ChatRequest, rebuild func() []Message) error {
+	if s.contextWindow <= 0 {
+		return nil
+	}
+	estimated := s.estimatePromptTokens(req)
+	if estimated <= s.contextWindow {
+		return nil
+	}
+	if s.compaction != nil && s.compact(ctx, estimated, s.contextWindow) {
+		req.Messages = rebuild()
+		estimated = s.estimatePromptTokens(req)
+		if estimated <= s.contextWindow {
+			return nil
+		}
+	}
+	return &ContextWindowError{Model: s.model, Window: s.contextWindow, Estimated: estimated}
+}
+
+// recordUsage adds a response's usage to the session totals and calibrates
+// the prompt estimate against the request that produced it.
+func (s *Session) recordUsage(req *ChatRequest, u Usage, cached bool) {
+	if cached {
+		return
+	}
+	s.usage.Requests++
+	s.usage.InputTokens += u.InputTokens
+	s.usage.OutputTokens += u.OutputTokens
+	s.usage.CacheReadTokens += u.CacheReadTokens
+	s.usage.CacheWriteTokens += u.CacheWriteTokens
+
+	prompt := u.InputTokens + u.CacheReadTokens + u.CacheWriteTokens
+	if prompt == 0 {
+		return
+	}
+	s.usage.LastPromptTokens = prompt
+	if rough := EstimateRequestTokens(req); rough > 0 {
+		s.promptTokens, s.promptEstimate = prompt, rough
+	}
+}
@@ -0,0 +1,120 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"squadron/failure"
+)
+
+func TestSessionUsage_AccumulatesAcrossRequests(t *testing.T) {
+	provider := &mockProvider{
+		streamChunks: []StreamChunk{
+			{Content: "ok"},
+			{Done: true, Usage: &Usage{InputTokens: 100, OutputTokens: 10, CacheReadTokens: 50}},
+		},
+	}
+	s := NewSession(provider, "test-model", "sys")
+
+	for i := 0; i < 2; i++ {
+		if _, err := s.SendStream(context.Background(), "hello", nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	u := s.Usage()
+	if u.Requests != 2 || u.InputTokens != 200 || u.OutputTokens != 20 || u.CacheReadTokens != 100 {
+		t.Fatalf("unexpected totals: %+v", u)
+	}
+	if u.LastPromptTokens != 150 {
+		t.Fatalf("expected last prompt of 150 tokens, got %d", u.LastPromptTokens)
+	}
+	if u.Total() != 320 {
+		t.Fatalf("expected 320 tokens in total, got %d", u.Total())
+	}
+}
+
+func TestContextWindow_RefusesOversizedRequest(t *testing.T) {
+	provider := &mockProvider{streamChunks: []StreamChunk{{Content: "ok"}, {Done: true}}}
+	s := NewSession(provider, "small-model", "sys")
+	s.SetContextWindow(100)
+
+	_, err := s.SendStream(context.Background(), strings.Repeat("x", 1000), nil)
+
+	var cwErr *ContextWindowError
+	if !errors.As(err, &cwErr) {
+		t.Fatalf("expected a ContextWindowError, got %v", err)
+	}
+	if cwErr.Model != "small-model" || cwErr.Window != 100 || cwErr.Estimated <= 100 {
+		t.Fatalf("unexpected error fields: %+v", cwErr)
+	}
+	if failure.CodeOf(err) != failure.CodeContextTooLong {
+		t.Fatalf("expected context_too_long, got %q", failure.CodeOf(err))
+	}
+	if provider.lastRequest != nil {
+		t.Fatal("the request should not reach the provider")
+	}
+	if len(s.messages) != 0 {
+		t.Fatalf("a refused request should not be added to history, got %d messages", len(s.messages))
+	}
+}
+
+func TestContextWindow_CompactsBeforeRefusing(t *testing.T) {
+	provider := &mockProvider{streamChunks: []StreamChunk{{Content: "ok"}, {Done: true}}}
+	s := NewSession(provider, "small-model", "sys")
+	s.messages = []Message{
+		{Role: RoleUser, Content: "first task"},
+		{Role: RoleAssistant, Content: strings.Repeat("a", 4000)},
+		{Role: RoleUser, Content: "second question"},
+		{Role: RoleAssistant, Content: "second answer"},
+	}
+	s.SetContextWindow(500)
+	s.SetCompaction(&CompactionPolicy{TokenLimit: 100000, TurnRetention: 1})
+	var events []CompactionEvent
+	s.SetCompactionObserver(func(ev CompactionEvent) { events = append(events, ev) })
+
+	if _, err := s.SendStream(context.Background(), "third question", nil); err != nil {
+		t.Fatalf("expected the compacted request to fit, got %v", err)
+	}
+
+	if len(events) != 1 || events[0].TokenLimit != 500 || events[0].InputTokens <= 500 {
+		t.Fatalf("expected one compaction against the context window, got %+v", events)
+	}
+	for _, m := range provider.lastRequest.Messages {
+		if strings.Contains(m.Content, strings.Repeat("a", 4000)) {
+			t.Fatal("the sent request should not contain the compacted turn")
+		}
+	}
+}
+
+func TestRemainingContext(t *testing.T) {
+	provider := &mockProvider{
+		streamChunks: []StreamChunk{
+			{Content: "ok"},
+			{Done: true, Usage: &Usage{InputTokens: 400}},
+		},
+	}
+	s := NewSession(provider, "test-model", "sys")
+
+	if _, ok := s.RemainingContext(); ok {
+		t.Fatal("remaining context should be unknown without a context window")
+	}
+
+	s.SetContextWindow(10000)
+	if _, err := s.SendStream(context.Background(), strings.Repeat("x", 400), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// The provider counted 400 tokens where the rough estimate saw about
+	// 100, so later estimates are scaled up to match.
+	estimated := s.EstimatedContextTokens()
+	if estimated < 400 {
+		t.Fatalf("expected the estimate to be calibrated to the reported usage, got %d", estimated)
+	}
+	remaining, ok := s.RemainingContext()
+	if !ok || remaining != 10000-estimated {
+		t.Fatalf("expected %d tokens remaining, got %d (ok=%v)", 10000-estimated, remaining, ok)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"net/http"
 
 	"github.com/google/uuid"
 	"google.golang.org/genai"
@@ -155,8 +156,11 @@ func usageFromGemini(u *genai.GenerateContentResponseUsageMetadata) Usage {
 	if u == nil {
 		return Usage{}
 	}
+	// Gemini caches shared prompt prefixes implicitly. PromptTokenCount
+	// includes the cached tokens; count them once, as cache reads, the way
+	// the other providers report them.
 	return Usage{
-		InputTokens:     int(u.PromptTokenCount),
+		InputTokens:     int(u.PromptTokenCount - u.CachedContentTokenCount),
 		OutputTokens:    int(u.CandidatesTokenCount),
 		CacheReadTokens: int(u.CachedContentTokenCount),
 	}
@@ -167,13 +171,28 @@ func (p *GeminiProvider) buildConfig(req *ChatRequest, sysInstr *genai.Content)
 	if sysInstr != nil {
 		cfg.SystemInstruction = sysInstr
 	}
+	if len(req.Headers) > 0 {
+		headers := http.Header{}
+		for k, v := range req.Headers {
+			headers.Set(k, v)
+		}
+		cfg.HTTPOptions = &genai.HTTPOptions{Headers: headers}
+	}
 	if req.MaxTokens > 0 {
 		cfg.MaxOutputTokens = int32(req.MaxTokens)
 	}
-	if req.Temperature > 0 {
-		t := float32(req.Temperature)
+	if req.Temperature != nil {
+		t := float32(*req.Temperature)
 		cfg.Temperature = &t
 	}
+	if req.TopP != nil {
+		topP := float32(*req.TopP)
+		cfg.TopP = &topP
+	}
+	if req.Seed != nil {
+		seed := int32(*req.Seed)
+		cfg.Seed = &seed
+	}
 	if len(req.StopSequences) > 0 {
 		cfg.StopSequences = req.StopSequences
 	}
@@ -0,0 +1,33 @@
+package llm
+
+import "context"
+
+// CallLimiter caps how many LLM calls run at once across every session that
+// shares it, so a wide mission doesn't exceed a provider's rate limits.
+// A nil *CallLimiter imposes no limit.
+type CallLimiter struct {
+	slots chan struct{}
+}
+
+// NewCallLimiter returns a limiter allowing n concurrent calls, or nil when
+// n <= 0.
+func NewCallLimiter(n int) *CallLimiter {
+	if n <= 0 {
+		return nil
+	}
+	return &CallLimiter{slots: make(chan struct{}, n)}
+}
+
+// Acquire blocks until a call slot is free or ctx is done. The returned
+// func releases the slot and must be called exactly once.
+func (l *CallLimiter) Acquire(ctx context.Context) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+	select {
+	case l.slots <- struct{}{}:
+		return func() { <-l.slots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
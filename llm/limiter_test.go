@@ -0,0 +1,42 @@
+package llm
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCallLimiterCapsConcurrentCalls(t *testing.T) {
+	l := NewCallLimiter(1)
+	release, err := l.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := l.Acquire(ctx); err == nil {
+		t.Fatal("second Acquire succeeded while the only slot was held")
+	}
+
+	release()
+	release2, err := l.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("Acquire after release: %v", err)
+	}
+	release2()
+}
+
+func TestNilCallLimiterIsUnlimited(t *testing.T) {
+	l := NewCallLimiter(0)
+	if l != nil {
+		t.Fatal("NewCallLimiter(0) should return nil")
+	}
+	for i := 0; i < 3; i++ {
+		release, err := l.Acquire(context.Background())
+		if err != nil {
+			t.Fatalf("Acquire: %v", err)
+		}
+		defer release()
+	}
+}
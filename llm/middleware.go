@@ -0,0 +1,243 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Middleware hooks into every call a provider makes, without changing the
+// provider: Request sees (and may replace) each request before it is sent,
+// Response each response before the session sees it. Either hook may be
+// nil. A hook that returns an error fails the call with that error, so a
+// content filter can block a request or a response.
+//
+// Requests carry Headers, which every built-in provider sends as extra HTTP
+// headers, so a Request hook can add custom headers.
+//
+// A Response hook needs the whole response, so streamed responses are
+// buffered while any middleware has one: the chunks are delivered after the
+// hook has run, rebuilt from the response it returned.
+type Middleware struct {
+	// Name identifies the middleware in errors.
+	Name string
+	// Providers limits the middleware to these providers ("anthropic",
+	// "openai", "gemini", ...). Empty applies it to every provider.
+	Providers []string
+
+	Request  func(ctx context.Context, req *ChatRequest) (*ChatRequest, error)
+	Response func(ctx context.Context, req *ChatRequest, resp *ChatResponse) (*ChatResponse, error)
+}
+
+// appliesTo reports whether the middleware runs for provider.
+func (m Middleware) appliesTo(provider string) bool {
+	if len(m.Providers) == 0 {
+		return true
+	}
+	for _, p := range m.Providers {
+		if p == provider {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	middlewareMu sync.RWMutex
+	middlewares  []Middleware
+)
+
+// Use registers middleware for every provider created afterwards. Request
+// hooks run in registration order; Response hooks run in reverse, so the
+// first middleware registered sees the request first and the response last.
+func Use(mw ...Middleware) {
+	middlewareMu.Lock()
+	defer middlewareMu.Unlock()
+	middlewares = append(middlewares, mw...)
+}
+
+// ResetMiddleware removes all registered middleware.
+func ResetMiddleware() {
+	middlewareMu.Lock()
+	defer middlewareMu.Unlock()
+	middlewares = nil
+}
+
+// MiddlewareFor returns the registered middleware that applies to provider.
+func MiddlewareFor(provider string) []Middleware {
+	middlewareMu.RLock()
+	defer middlewareMu.RUnlock()
+	var out []Middleware
+	for _, m := range middlewares {
+		if m.appliesTo(provider) {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// WithMiddleware wraps p so every Chat and ChatStream call runs through mw.
+// It returns p unchanged when mw is empty.
+func WithMiddleware(p Provider, mw []Middleware) Provider {
+	if len(mw) == 0 {
+		return p
+	}
+	return &middlewareProvider{Provider: p, mw: mw}
+}
+
+type middlewareProvider struct {
+	Provider
+	mw []Middleware
+}
+
+func (p *middlewareProvider) Chat(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
+	req, err := p.request(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.Provider.Chat(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return p.response(ctx, req, resp)
+}
+
+func (p *middlewareProvider) ChatStream(ctx context.Context, req *ChatRequest) (<-chan StreamChunk, error) {
+	req, err := p.request(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	in, err := p.Provider.ChatStream(ctx, req)
+	if err != nil || !p.hasResponseHooks() {
+		return in, err
+	}
+
+	out := make(chan StreamChunk)
+	go func() {
+		defer close(out)
+		sr, err := readStream(ctx, in, nil)
+		if err != nil {
+			// Drain so the provider can finish, then report the error.
+			for range in {
+			}
+			sendChunk(ctx, out, StreamChunk{Error: err})
+			return
+		}
+		resp, err := p.response(ctx, req, streamResponse(sr))
+		if err != nil {
+			sendChunk(ctx, out, StreamChunk{Error: err})
+			return
+		}
+		for _, chunk := range responseChunks(resp) {
+			if !sendChunk(ctx, out, chunk) {
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+// Close forwards to the wrapped provider so providers holding resources
+// (Gemini) are still released.
+func (p *middlewareProvider) Close() {
+	if closer, ok := p.Provider.(interface{ Close() }); ok {
+		closer.Close()
+	}
+}
+
+func (p *middlewareProvider) request(ctx context.Context, req *ChatRequest) (*ChatRequest, error) {
+	for _, m := range p.mw {
+		if m.Request == nil {
+			continue
+		}
+		next, err := m.Request(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("middleware %q: %w", m.Name, err)
+		}
+		if next != nil {
+			req = next
+		}
+	}
+	return req, nil
+}
+
+func (p *middlewareProvider) response(ctx context.Context, req *ChatRequest, resp *ChatResponse) (*ChatResponse, error) {
+	for i := len(p.mw) - 1; i >= 0; i-- {
+		m := p.mw[i]
+		if m.Response == nil {
+			continue
+		}
+		next, err := m.Response(ctx, req, resp)
+		if err != nil {
+			return nil, fmt.Errorf("middleware %q: %w", m.Name, err)
+		}
+		if next != nil {
+			resp = next
+		}
+	}
+	return resp, nil
+}
+
+func (p *middlewareProvider) hasResponseHooks() bool {
+	for _, m := range p.mw {
+		if m.Response != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// streamResponse turns a fully read stream into the response a Chat call
+// would have returned.
+func streamResponse(sr streamResult) *ChatResponse {
+	resp := &ChatResponse{
+		Content:       sr.TextContent,
+		ContentBlocks: sr.ContentBlocks,
+		FinishReason:  sr.StopReason,
+	}
+	if sr.LastChunk.Usage != nil {
+		resp.Usage = *sr.LastChunk.Usage
+	}
+	return resp
+}
+
+// responseChunks streams resp the way a provider would have: reasoning,
+// text, each tool call, then the final chunk.
+func responseChunks(resp *ChatResponse) []StreamChunk {
+	var chunks []StreamChunk
+	for _, block := range resp.ContentBlocks {
+		if block.Type == ContentTypeThinking && block.Thinking != nil && block.Thinking.Text != "" {
+			chunks = append(chunks,
+				StreamChunk{ReasoningStart: true},
+				StreamChunk{ReasoningDelta: block.Thinking.Text},
+				StreamChunk{ReasoningDone: true},
+			)
+		}
+	}
+	if resp.Content != "" {
+		chunks = append(chunks, StreamChunk{Content: resp.Content})
+	}
+	for _, block := range resp.ContentBlocks {
+		if block.Type != ContentTypeToolUse || block.ToolUse == nil {
+			continue
+		}
+		id := block.ToolUse.ID
+		chunks = append(chunks,
+			StreamChunk{ToolCallStart: &ToolCallStartChunk{ID: id, Name: block.ToolUse.Name}},
+			StreamChunk{ToolCallDelta: string(block.ToolUse.Input)},
+			StreamChunk{ToolCallDone: &id},
+		)
+	}
+	usage := resp.Usage
+	return append(chunks, StreamChunk{Done: true, Usage: &usage, StopReason: resp.FinishReason, ContentBlocks: resp.ContentBlocks})
+}
+
+// sendChunk delivers chunk unless ctx is done first.
+func sendChunk(ctx context.Context, out chan<- StreamChunk, chunk StreamChunk) bool {
+	select {
+	case out <- chunk:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,101 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestMiddleware_RequestAndResponseOrder(t *testing.T) {
+	provider := newMockProvider("call me at 555-0100")
+	var order []string
+	mw := func(name string) Middleware {
+		return Middleware{
+			Name: name,
+			Request: func(ctx context.Context, req *ChatRequest) (*ChatRequest, error) {
+				order = append(order, "req "+name)
+				out := *req
+				out.Headers = map[string]string{"X-Audit": name}
+				return &out, nil
+			},
+			Response: func(ctx context.Context, req *ChatRequest, resp *ChatResponse) (*ChatResponse, error) {
+				order = append(order, "resp "+name)
+				return nil, nil // keep the response unchanged
+			},
+		}
+	}
+	p := WithMiddleware(provider, []Middleware{mw("outer"), mw("inner")})
+
+	if _, err := p.Chat(context.Background(), &ChatRequest{Model: "m"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := strings.Join(order, ","); got != "req outer,req inner,resp inner,resp outer" {
+		t.Fatalf("unexpected hook order %s", got)
+	}
+	if provider.lastRequest.Headers["X-Audit"] != "inner" {
+		t.Fatalf("the provider should see the last rewritten request, got %+v", provider.lastRequest.Headers)
+	}
+}
+
+func TestMiddleware_RequestErrorBlocksCall(t *testing.T) {
+	provider := newMockProvider("ok")
+	blocked := errors.New("contains a credit card number")
+	p := WithMiddleware(provider, []Middleware{{
+		Name: "pii",
+		Request: func(ctx context.Context, req *ChatRequest) (*ChatRequest, error) {
+			return nil, blocked
+		},
+	}})
+
+	_, err := p.ChatStream(context.Background(), &ChatRequest{Model: "m"})
+
+	if !errors.Is(err, blocked) || !strings.Contains(err.Error(), `middleware "pii"`) {
+		t.Fatalf("expected the hook's error, got %v", err)
+	}
+	if provider.lastRequest != nil {
+		t.Fatal("a blocked request should not reach the provider")
+	}
+}
+
+func TestMiddleware_ScrubsStreamedResponse(t *testing.T) {
+	provider := newMockProvider("call me at 555-0100")
+	scrub := Middleware{
+		Name: "scrub",
+		Response: func(ctx context.Context, req *ChatRequest, resp *ChatResponse) (*ChatResponse, error) {
+			resp.Content = strings.ReplaceAll(resp.Content, "555-0100", "[PHONE]")
+			return resp, nil
+		},
+	}
+	s := NewSession(WithMiddleware(provider, []Middleware{scrub}), "m", "sys")
+
+	var streamed strings.Builder
+	resp, err := s.SendStream(context.Background(), "hi", func(c StreamChunk) { streamed.WriteString(c.Content) })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.Content != "call me at [PHONE]" || streamed.String() != "call me at [PHONE]" {
+		t.Fatalf("expected the scrubbed text, got response %q and stream %q", resp.Content, streamed.String())
+	}
+	if resp.Usage.InputTokens != 10 {
+		t.Fatalf("usage should survive the rebuilt stream, got %+v", resp.Usage)
+	}
+}
+
+func TestMiddlewareFor_FiltersByProvider(t *testing.T) {
+	defer ResetMiddleware()
+	Use(Middleware{Name: "all"}, Middleware{Name: "anthropic-only", Providers: []string{"anthropic"}})
+
+	var names []string
+	for _, m := range MiddlewareFor("openai") {
+		names = append(names, m.Name)
+	}
+	if strings.Join(names, ",") != "all" {
+		t.Fatalf("unexpected middleware for openai: %v", names)
+	}
+	if got := len(MiddlewareFor("anthropic")); got != 2 {
+		t.Fatalf("expected 2 middleware for anthropic, got %d", got)
+	}
+}
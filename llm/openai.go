@@ -2,6 +2,8 @@ package llm
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -34,6 +36,9 @@ func truncate(s string, n int) string {
 // sufficient.
 type OpenAIProvider struct {
 	client *openai.Client
+	// cacheKeys sends prompt_cache_key, which only OpenAI's own API
+	// understands.
+	cacheKeys bool
 }
 
 func NewOpenAIProvider(apiKey, baseURL string) *OpenAIProvider {
@@ -42,28 +47,41 @@ func NewOpenAIProvider(apiKey, baseURL string) *OpenAIProvider {
 		opts = append(opts, option.WithBaseURL(baseURL))
 	}
 	client := openai.NewClient(opts...)
-	return &OpenAIProvider{client: &client}
+	return &OpenAIProvider{client: &client, cacheKeys: true}
 }
 
 // NewOpenAICompatibleProvider creates a provider that targets an OpenAI-compatible
 // API at the given base URL (e.g. Ollama at http://localhost:11434/v1).
 // Requires the server to implement /v1/responses (Ollama 0.13.3+, recent vLLM,
-// LiteLLM with default routing).
-func NewOpenAICompatibleProvider(baseURL string) *OpenAIProvider {
+// LiteLLM with default routing). apiKey may be empty for servers that don't
+// check one.
+func NewOpenAICompatibleProvider(baseURL, apiKey string) *OpenAIProvider {
+	if apiKey == "" {
+		apiKey = "ollama" // dummy key; local servers ignore it
+	}
 	client := openai.NewClient(
 		option.WithBaseURL(baseURL),
-		option.WithAPIKey("ollama"), // dummy key; local servers ignore it
+		option.WithAPIKey(apiKey),
 	)
 	return &OpenAIProvider{client: &client}
 }
 
+// openaiHeaders sends req.Headers with the request.
+func openaiHeaders(req *ChatRequest) []option.RequestOption {
+	var opts []option.RequestOption
+	for k, v := range req.Headers {
+		opts = append(opts, option.WithHeader(k, v))
+	}
+	return opts
+}
+
 func (p *OpenAIProvider) Chat(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
 	params, err := p.buildResponseParams(req)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := p.client.Responses.New(ctx, params)
+	resp, err := p.client.Responses.New(ctx, params, openaiHeaders(req)...)
 	if err != nil {
 		return nil, err
 	}
@@ -100,7 +118,7 @@ func (p *OpenAIProvider) ChatStream(ctx context.Context, req *ChatRequest) (<-ch
 		}
 	}
 
-	stream := p.client.Responses.NewStreaming(ctx, params)
+	stream := p.client.Responses.NewStreaming(ctx, params, openaiHeaders(req)...)
 
 	// gpt-5 and o-series reason by default whether or not we asked for it.
 	// When the agent didn't opt in via reasoning="...", suppress all
@@ -350,8 +368,16 @@ func (p *OpenAIProvider) buildResponseParams(req *ChatRequest) (responses.Respon
 		params.MaxOutputTokens = param.NewOpt(int64(req.MaxTokens))
 	}
 
-	if req.Temperature > 0 {
-		params.Temperature = param.NewOpt(req.Temperature)
+	// The Responses API takes no seed
+	if req.Temperature != nil {
+		params.Temperature = param.NewOpt(*req.Temperature)
+	}
+	if req.TopP != nil {
+		params.TopP = param.NewOpt(*req.TopP)
+	}
+
+	if key := promptCacheKey(req); key != "" && p.cacheKeys {
+		params.PromptCacheKey = param.NewOpt(key)
 	}
 
 	if effort := openAIReasoningEffort(req.Reasoning); effort != "" {
@@ -380,6 +406,32 @@ func (p *OpenAIProvider) buildResponseParams(req *ChatRequest) (responses.Respon
 	return params, nil
 }
 
+// promptCacheKey identifies the prefix a request shares with other sessions
+// (see ChatRequest.CachePrefix). OpenAI caches prompt prefixes on its own;
+// requests with the same key are routed to the same cache, which keeps
+// parallel iterations of a task from missing it. Empty when there is no
+// shared prefix.
+func promptCacheKey(req *ChatRequest) string {
+	if !req.PromptCaching || req.CachePrefix <= 0 {
+		return ""
+	}
+	h := sha256.New()
+	h.Write([]byte(req.Model))
+	n := 0
+	for _, m := range req.Messages {
+		if m.Role != RoleSystem {
+			continue
+		}
+		if n == req.CachePrefix {
+			break
+		}
+		h.Write([]byte{0})
+		h.Write([]byte(m.Content))
+		n++
+	}
+	return "squadron-" + hex.EncodeToString(h.Sum(nil))[:32]
+}
+
 // convertMessages walks the conversation history and produces (a) a single
 // instructions string from system messages and (b) a list of input items
 // for the Responses API.
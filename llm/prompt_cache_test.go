@@ -0,0 +1,114 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"google.golang.org/genai"
+)
+
+func TestMarkCachePrefix_PassedToRequests(t *testing.T) {
+	p := newMockProvider("ok")
+	s := NewSession(p, "m", "base instructions")
+	s.AddSystemPrompt("dependency context")
+	s.MarkCachePrefix()
+	s.AddSystemPrompt("previous iteration output")
+
+	if _, err := s.Send(context.Background(), "hello"); err != nil {
+		t.Fatal(err)
+	}
+	if p.lastRequest.CachePrefix != 2 {
+		t.Errorf("CachePrefix = %d, want 2", p.lastRequest.CachePrefix)
+	}
+
+	if _, err := s.Clone().Send(context.Background(), "hello"); err != nil {
+		t.Fatal(err)
+	}
+	if p.lastRequest.CachePrefix != 2 {
+		t.Errorf("clone CachePrefix = %d, want 2", p.lastRequest.CachePrefix)
+	}
+}
+
+func TestAnthropicConvertMessages_CachePrefixBreakpoint(t *testing.T) {
+	p := &AnthropicProvider{}
+	messages := []Message{
+		{Role: RoleSystem, Content: "base instructions"},
+		{Role: RoleSystem, Content: "dependency context"},
+		{Role: RoleSystem, Content: "previous iteration output"},
+		{Role: RoleUser, Content: "go"},
+	}
+
+	cached := func(prefix int, promptCaching bool) []bool {
+		_, system := p.convertMessages(messages, promptCaching, false, prefix)
+		var out []bool
+		for _, sp := range system {
+			b, _ := json.Marshal(sp)
+			out = append(out, strings.Contains(string(b), "cache_control"))
+		}
+		return out
+	}
+
+	if got := cached(2, true); got[0] || !got[1] || !got[2] {
+		t.Errorf("expected breakpoints on the prefix end and the last system prompt, got %v", got)
+	}
+	if got := cached(0, true); got[0] || got[1] || !got[2] {
+		t.Errorf("expected only the last system prompt cached without a prefix, got %v", got)
+	}
+	if got := cached(2, false); got[0] || got[1] || got[2] {
+		t.Errorf("expected no breakpoints with prompt caching off, got %v", got)
+	}
+}
+
+func TestPromptCacheKey(t *testing.T) {
+	req := func(model string, prefix int, system ...string) *ChatRequest {
+		r := &ChatRequest{Model: model, PromptCaching: true, CachePrefix: prefix}
+		for _, s := range system {
+			r.Messages = append(r.Messages, Message{Role: RoleSystem, Content: s})
+		}
+		r.Messages = append(r.Messages, Message{Role: RoleUser, Content: "item"})
+		return r
+	}
+
+	a := promptCacheKey(req("gpt-5", 1, "task", "iteration 1"))
+	b := promptCacheKey(req("gpt-5", 1, "task", "iteration 2"))
+	if a == "" || a != b {
+		t.Errorf("expected requests sharing a prefix to share a key, got %q and %q", a, b)
+	}
+	if c := promptCacheKey(req("gpt-5", 1, "other task", "iteration 1")); c == a {
+		t.Error("expected a different prefix to get a different key")
+	}
+	if c := promptCacheKey(req("gpt-5-mini", 1, "task", "iteration 1")); c == a {
+		t.Error("expected a different model to get a different key")
+	}
+	if c := promptCacheKey(req("gpt-5", 0, "task")); c != "" {
+		t.Errorf("expected no key without a marked prefix, got %q", c)
+	}
+
+	p := &OpenAIProvider{cacheKeys: true}
+	params, err := p.buildResponseParams(req("gpt-5", 1, "task", "iteration 1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params.PromptCacheKey.Value != a {
+		t.Errorf("prompt_cache_key = %q, want %q", params.PromptCacheKey.Value, a)
+	}
+
+	compatible := &OpenAIProvider{}
+	params, _ = compatible.buildResponseParams(req("llama3", 1, "task"))
+	if params.PromptCacheKey.Valid() {
+		t.Error("expected no prompt_cache_key for OpenAI-compatible servers")
+	}
+}
+
+func TestUsageFromGemini_CountsCachedTokensOnce(t *testing.T) {
+	u := usageFromGemini(&genai.GenerateContentResponseUsageMetadata{
+		PromptTokenCount:        1000,
+		CachedContentTokenCount: 800,
+		CandidatesTokenCount:    50,
+	})
+	if u.InputTokens != 200 || u.CacheReadTokens != 800 || u.OutputTokens != 50 {
+		t.Errorf("unexpected usage: %+v", u)
+	}
+}
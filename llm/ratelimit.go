@@ -0,0 +1,255 @@
+package llm
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimit caps the request and token rate to one model. Zero fields are
+// unlimited.
+type RateLimit struct {
+	RequestsPerMinute int
+	TokensPerMinute   int
+}
+
+// IsZero reports whether the limit imposes nothing.
+func (l RateLimit) IsZero() bool {
+	return l.RequestsPerMinute <= 0 && l.TokensPerMinute <= 0
+}
+
+// RateLimiter is a token bucket on requests and tokens per minute. Callers
+// reserve a request and an estimate of its tokens up front with Wait and
+// settle the difference with Charge once the response reports its usage.
+// Reservations may run the buckets negative, so concurrent callers queue
+// up behind each other instead of waking together. A nil *RateLimiter
+// imposes no limit.
+type RateLimiter struct {
+	mu       sync.Mutex
+	requests bucket
+	tokens   bucket
+}
+
+// bucket refills at rate units per second up to capacity. A zero rate is
+// unlimited.
+type bucket struct {
+	capacity float64
+	level    float64
+	rate     float64
+	last     time.Time
+}
+
+func newBucket(perMinute int, now time.Time) bucket {
+	if perMinute <= 0 {
+		return bucket{}
+	}
+	return bucket{
+		capacity: float64(perMinute),
+		level:    float64(perMinute),
+		rate:     float64(perMinute) / 60,
+		last:     now,
+	}
+}
+
+// take refills the bucket to now, removes n and returns how long the
+// caller must wait for the level to get back to zero.
+func (b *bucket) take(n float64, now time.Time) time.Duration {
+	if b.rate == 0 {
+		return 0
+	}
+	b.level = min(b.capacity, b.level+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	// A single request bigger than the whole bucket would otherwise wait
+	// forever; let it through once the bucket is full.
+	b.level -= min(n, b.capacity)
+	if b.level >= 0 {
+		return 0
+	}
+	return time.Duration(-b.level / b.rate * float64(time.Second))
+}
+
+// NewRateLimiter returns a limiter for limit, or nil when limit is zero.
+func NewRateLimiter(limit RateLimit) *RateLimiter {
+	if limit.IsZero() {
+		return nil
+	}
+	now := time.Now()
+	return &RateLimiter{
+		requests: newBucket(limit.RequestsPerMinute, now),
+		tokens:   newBucket(limit.TokensPerMinute, now),
+	}
+}
+
+// Wait reserves one request and tokens, then blocks until the reservation
+// is within the limit or ctx is done. A cancelled reservation is returned
+// to the buckets.
+func (l *RateLimiter) Wait(ctx context.Context, tokens int) error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	now := time.Now()
+	wait := max(l.requests.take(1, now), l.tokens.take(float64(tokens), now))
+	l.mu.Unlock()
+	if wait == 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		l.requests.level += min(1, l.requests.capacity)
+		l.tokens.level += min(float64(tokens), l.tokens.capacity)
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// Charge settles a reservation against actual usage: a positive n takes
+// more tokens, a negative one gives back an overestimate.
+func (l *RateLimiter) Charge(n int) {
+	if l == nil || n == 0 || l.tokens.rate == 0 {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tokens.level = min(l.tokens.capacity, l.tokens.level-float64(n))
+}
+
+// RateLimiters hands out one RateLimiter per model, all with the same
+// limit, so every session calling a model shares its budget.
+type RateLimiters struct {
+	limit   RateLimit
+	mu      sync.Mutex
+	byModel map[string]*RateLimiter
+}
+
+// NewRateLimiters returns a per-model limiter set, or nil when limit is
+// zero.
+func NewRateLimiters(limit RateLimit) *RateLimiters {
+	if limit.IsZero() {
+		return nil
+	}
+	return &RateLimiters{limit: limit, byModel: make(map[string]*RateLimiter)}
+}
+
+// For returns the limiter for model.
+func (r *RateLimiters) For(model string) *RateLimiter {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	l, ok := r.byModel[model]
+	if !ok {
+		l = NewRateLimiter(r.limit)
+		r.byModel[model] = l
+	}
+	return l
+}
+
+// WithRateLimits wraps p so every Chat and ChatStream call waits on the
+// limiter for its request's model. It returns p unchanged when limiters is
+// nil.
+func WithRateLimits(p Provider, limiters *RateLimiters) Provider {
+	if limiters == nil {
+		return p
+	}
+	return &rateLimitedProvider{Provider: p, limiters: limiters}
+}
+
+type rateLimitedProvider struct {
+	Provider
+	limiters *RateLimiters
+}
+
+func (p *rateLimitedProvider) Chat(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
+	l := p.limiters.For(req.Model)
+	estimate := EstimateRequestTokens(req)
+	if err := l.Wait(ctx, estimate); err != nil {
+		return nil, err
+	}
+	resp, err := p.Provider.Chat(ctx, req)
+	if err == nil {
+		l.Charge(resp.Usage.Total() - estimate)
+	}
+	return resp, err
+}
+
+func (p *rateLimitedProvider) ChatStream(ctx context.Context, req *ChatRequest) (<-chan StreamChunk, error) {
+	l := p.limiters.For(req.Model)
+	estimate := EstimateRequestTokens(req)
+	if err := l.Wait(ctx, estimate); err != nil {
+		return nil, err
+	}
+	in, err := p.Provider.ChatStream(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan StreamChunk)
+	go func() {
+		defer close(out)
+		for chunk := range in {
+			if chunk.Done && chunk.Usage != nil {
+				l.Charge(chunk.Usage.Total() - estimate)
+			}
+			select {
+			case out <- chunk:
+			case <-ctx.Done():
+				// The reader has gone; drain so the provider can finish.
+				for range in {
+				}
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+// Close forwards to the wrapped provider so providers holding resources
+// (Gemini) are still released.
+func (p *rateLimitedProvider) Close() {
+	if closer, ok := p.Provider.(interface{ Close() }); ok {
+		closer.Close()
+	}
+}
+
+// imageTokenEstimate is the rough token cost charged per image up front.
+const imageTokenEstimate = 1500
+
+// EstimateRequestTokens roughly estimates a request's input tokens at four
+// characters per token. It only needs to be close enough for rate
+// limiting; the real count is settled from the response's usage.
+func EstimateRequestTokens(req *ChatRequest) int {
+	chars := 0
+	images := 0
+	for _, m := range req.Messages {
+		if !m.HasParts() {
+			chars += len(m.Content)
+			continue
+		}
+		for _, p := range m.Parts {
+			switch {
+			case p.Type == ContentTypeText:
+				chars += len(p.Text)
+			case p.Type == ContentTypeImage:
+				images++
+			case p.ToolUse != nil:
+				chars += len(p.ToolUse.Name) + len(p.ToolUse.Input)
+			case p.ToolResult != nil:
+				chars += len(p.ToolResult.Content)
+			case p.Thinking != nil:
+				chars += len(p.Thinking.Text)
+			}
+		}
+	}
+	for _, t := range req.Tools {
+		chars += len(t.Name) + len(t.Description) + len(t.InputSchema)
+	}
+	return chars/4 + images*imageTokenEstimate
+}
@@ -0,0 +1,115 @@
+package llm
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterQueuesRequestsPastTheLimit(t *testing.T) {
+	// 600 requests/minute refills one request every 100ms.
+	l := NewRateLimiter(RateLimit{RequestsPerMinute: 600})
+	l.requests.level = 1
+
+	if err := l.Wait(context.Background(), 0); err != nil {
+		t.Fatalf("first Wait: %v", err)
+	}
+	start := time.Now()
+	if err := l.Wait(context.Background(), 0); err != nil {
+		t.Fatalf("second Wait: %v", err)
+	}
+	if waited := time.Since(start); waited < 80*time.Millisecond {
+		t.Fatalf("second Wait returned after %s, want ~100ms", waited)
+	}
+}
+
+func TestRateLimiterCancelledWaitReturnsReservation(t *testing.T) {
+	l := NewRateLimiter(RateLimit{TokensPerMinute: 60})
+	l.tokens.level = 0
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := l.Wait(ctx, 30); err == nil {
+		t.Fatal("Wait succeeded with an empty token bucket")
+	}
+	if l.tokens.level < -1 {
+		t.Fatalf("cancelled reservation not returned: level %v", l.tokens.level)
+	}
+}
+
+func TestRateLimiterChargeSettlesUsage(t *testing.T) {
+	l := NewRateLimiter(RateLimit{TokensPerMinute: 1000})
+	if err := l.Wait(context.Background(), 100); err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+	l.Charge(400)
+	if got := l.tokens.level; got > 500.5 || got < 499 {
+		t.Fatalf("level after charge = %v, want ~500", got)
+	}
+	l.Charge(-2000)
+	if got := l.tokens.level; got != 1000 {
+		t.Fatalf("refund should cap at capacity, got %v", got)
+	}
+}
+
+func TestNilRateLimiterIsUnlimited(t *testing.T) {
+	if l := NewRateLimiter(RateLimit{}); l != nil {
+		t.Fatal("NewRateLimiter with no limits should return nil")
+	}
+	var l *RateLimiter
+	if err := l.Wait(context.Background(), 1_000_000); err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+	l.Charge(10)
+	if p := WithRateLimits(nil, nil); p != nil {
+		t.Fatal("WithRateLimits with nil limiters should return the provider unchanged")
+	}
+}
+
+func TestRateLimitersShareOneLimiterPerModel(t *testing.T) {
+	r := NewRateLimiters(RateLimit{RequestsPerMinute: 10})
+	if r.For("a") != r.For("a") {
+		t.Fatal("same model should share a limiter")
+	}
+	if r.For("a") == r.For("b") {
+		t.Fatal("different models should get separate limiters")
+	}
+}
+
+type usageProvider struct{ usage Usage }
+
+func (p *usageProvider) Chat(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
+	return &ChatResponse{Usage: p.usage}, nil
+}
+
+func (p *usageProvider) ChatStream(ctx context.Context, req *ChatRequest) (<-chan StreamChunk, error) {
+	ch := make(chan StreamChunk, 2)
+	ch <- StreamChunk{Content: "hi"}
+	ch <- StreamChunk{Done: true, Usage: &p.usage}
+	close(ch)
+	return ch, nil
+}
+
+func TestRateLimitedProviderChargesReportedUsage(t *testing.T) {
+	limiters := NewRateLimiters(RateLimit{TokensPerMinute: 10000})
+	p := WithRateLimits(&usageProvider{usage: Usage{InputTokens: 3000, OutputTokens: 1000}}, limiters)
+	req := &ChatRequest{Model: "m"}
+
+	if _, err := p.Chat(context.Background(), req); err != nil {
+		t.Fatalf("Chat: %v", err)
+	}
+	stream, err := p.ChatStream(context.Background(), req)
+	if err != nil {
+		t.Fatalf("ChatStream: %v", err)
+	}
+	n := 0
+	for range stream {
+		n++
+	}
+	if n != 2 {
+		t.Fatalf("got %d chunks, want 2", n)
+	}
+	if got := limiters.For("m").tokens.level; got > 2000.5 || got < 1999 {
+		t.Fatalf("token level = %v, want ~2000 after two 4000-token calls", got)
+	}
+}
@@ -0,0 +1,77 @@
+package llm
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// minRedactLen is the shortest secret value the redactor replaces. Shorter
+// values would match all over ordinary text and mangle the transcript.
+const minRedactLen = 4
+
+// tokenPatterns match well-known credential formats that may show up in
+// tool results even when they were never declared as secrets.
+var tokenPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`),
+	regexp.MustCompile(`\bsk-ant-[A-Za-z0-9_-]{20,}`),
+	regexp.MustCompile(`\bsk-(?:proj-)?[A-Za-z0-9_-]{32,}`),
+	regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{36,}`),
+	regexp.MustCompile(`\bgithub_pat_[A-Za-z0-9_]{40,}`),
+	regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}`),
+	regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`),
+	regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}`),
+	regexp.MustCompile(`(?i)\bBearer\s+[A-Za-z0-9._~+/-]{20,}=*`),
+}
+
+// redactedToken replaces a credential matched by a token pattern.
+const redactedToken = "[REDACTED]"
+
+// Redactor scrubs secret values from text before it is persisted or written
+// to debug files. Known values are replaced with their ${secrets.name}
+// placeholder — the same text the model wrote before injection — so a
+// redacted transcript can still be replayed on resume. Anything matching a
+// common token format is replaced with [REDACTED].
+//
+// A nil *Redactor is valid and returns text unchanged.
+type Redactor struct {
+	values []redactedValue
+}
+
+type redactedValue struct {
+	value       string
+	placeholder string
+}
+
+// NewRedactor creates a redactor for the given secret name → value map.
+func NewRedactor(secrets map[string]string) *Redactor {
+	r := &Redactor{}
+	for name, value := range secrets {
+		if len(value) < minRedactLen {
+			continue
+		}
+		r.values = append(r.values, redactedValue{value: value, placeholder: "${secrets." + name + "}"})
+	}
+	// Longest first so a secret containing another is replaced whole
+	sort.Slice(r.values, func(i, j int) bool {
+		if len(r.values[i].value) != len(r.values[j].value) {
+			return len(r.values[i].value) > len(r.values[j].value)
+		}
+		return r.values[i].placeholder < r.values[j].placeholder
+	})
+	return r
+}
+
+// Redact returns text with secret values and recognizable tokens removed.
+func (r *Redactor) Redact(text string) string {
+	if r == nil || text == "" {
+		return text
+	}
+	for _, v := range r.values {
+		text = strings.ReplaceAll(text, v.value, v.placeholder)
+	}
+	for _, p := range tokenPatterns {
+		text = p.ReplaceAllString(text, redactedToken)
+	}
+	return text
+}
@@ -0,0 +1,104 @@
+package llm
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+)
+
+// ResponseCache stores LLM responses by request key, so identical requests
+// (same model, system prompts, history, tools, and settings) are answered
+// without calling the provider. Implementations must be safe for
+// concurrent use.
+type ResponseCache interface {
+	Get(key string) (*CachedResponse, bool)
+	Put(key string, resp *CachedResponse)
+}
+
+// CachedResponse is the part of a response worth replaying. Usage is not
+// kept: a cache hit costs no tokens.
+type CachedResponse struct {
+	Model         string         `json:"model"`
+	Content       string         `json:"content"`
+	ContentBlocks []ContentBlock `json:"contentBlocks,omitempty"`
+	FinishReason  string         `json:"finishReason"`
+}
+
+// RequestCacheKey hashes the parts of req that determine the response.
+// Prompt caching flags only change billing, so they are left out.
+func RequestCacheKey(req *ChatRequest) string {
+	b, _ := json.Marshal(struct {
+		Model         string
+		Messages      []Message
+		MaxTokens     int
+		Temperature   *float64 `json:",omitempty"`
+		TopP          *float64 `json:",omitempty"`
+		Seed          *int64   `json:",omitempty"`
+		StopSequences []string
+		Tools         []ToolDefinition
+		Reasoning     string
+	}{req.Model, req.Messages, req.MaxTokens, req.Temperature, req.TopP, req.Seed, req.StopSequences, req.Tools, req.Reasoning})
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// SetResponseCache enables response caching for this session (nil disables).
+func (s *Session) SetResponseCache(c ResponseCache) {
+	s.responseCache = c
+}
+
+// cachedResult looks req up in the response cache.
+func (s *Session) cachedResult(req *ChatRequest) (string, streamResult, bool) {
+	if s.responseCache == nil {
+		return "", streamResult{}, false
+	}
+	key := RequestCacheKey(req)
+	cached, ok := s.responseCache.Get(key)
+	if !ok {
+		return key, streamResult{}, false
+	}
+	return key, streamResult{
+		TextContent:   cached.Content,
+		ContentBlocks: cached.ContentBlocks,
+		StopReason:    cached.FinishReason,
+		Cached:        true,
+	}, true
+}
+
+// storeResult caches a successful response under key. Truncated responses
+// are not cached, since the caller usually retries them.
+func (s *Session) storeResult(key string, sr streamResult) {
+	if s.responseCache == nil || key == "" || sr.StopReason == "max_tokens" {
+		return
+	}
+	if sr.TextContent == "" && len(sr.ContentBlocks) == 0 {
+		return
+	}
+	s.responseCache.Put(key, &CachedResponse{
+		Model:         s.model,
+		Content:       sr.TextContent,
+		ContentBlocks: sr.ContentBlocks,
+		FinishReason:  sr.StopReason,
+	})
+}
+
+// replayChunks streams a cached response to onChunk the way a provider
+// would have: text, then each tool call, then the final chunk.
+func replayChunks(sr streamResult, onChunk func(StreamChunk)) {
+	if onChunk == nil {
+		return
+	}
+	if sr.TextContent != "" {
+		onChunk(StreamChunk{Content: sr.TextContent})
+	}
+	for _, block := range sr.ContentBlocks {
+		if block.Type != ContentTypeToolUse || block.ToolUse == nil {
+			continue
+		}
+		id := block.ToolUse.ID
+		onChunk(StreamChunk{ToolCallStart: &ToolCallStartChunk{ID: id, Name: block.ToolUse.Name}})
+		onChunk(StreamChunk{ToolCallDelta: string(block.ToolUse.Input)})
+		onChunk(StreamChunk{ToolCallDone: &id})
+	}
+	onChunk(StreamChunk{Done: true, Usage: &Usage{}, StopReason: sr.StopReason, ContentBlocks: sr.ContentBlocks})
+}
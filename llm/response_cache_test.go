@@ -0,0 +1,154 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+)
+
+// mapResponseCache is an in-memory ResponseCache.
+type mapResponseCache struct {
+	mu      sync.Mutex
+	entries map[string]*CachedResponse
+}
+
+func (c *mapResponseCache) Get(key string) (*CachedResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	r, ok := c.entries[key]
+	return r, ok
+}
+
+func (c *mapResponseCache) Put(key string, resp *CachedResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = resp
+}
+
+// streamCountingProvider counts ChatStream calls.
+type streamCountingProvider struct {
+	*mockProvider
+	streams int
+}
+
+func (p *streamCountingProvider) ChatStream(ctx context.Context, req *ChatRequest) (<-chan StreamChunk, error) {
+	p.streams++
+	return p.mockProvider.ChatStream(ctx, req)
+}
+
+func TestResponseCache_ReplaysIdenticalStreamRequests(t *testing.T) {
+	toolID := "tc_1"
+	input := json.RawMessage(`{"q":"acme"}`)
+	provider := &streamCountingProvider{mockProvider: &mockProvider{streamChunks: []StreamChunk{
+		{Content: "Searching."},
+		{ToolCallStart: &ToolCallStartChunk{ID: toolID, Name: "search"}},
+		{ToolCallDelta: string(input)},
+		{ToolCallDone: &toolID},
+		{Done: true, StopReason: "tool_use", Usage: &Usage{InputTokens: 100, OutputTokens: 20}},
+	}}}
+	cache := &mapResponseCache{entries: map[string]*CachedResponse{}}
+
+	newSession := func() *Session {
+		s := NewSession(provider, "test-model", "You are a researcher.")
+		s.SetResponseCache(cache)
+		return s
+	}
+
+	first, err := newSession().SendStream(context.Background(), "find acme", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Cached || first.Usage.InputTokens != 100 {
+		t.Fatalf("first call should hit the provider, got %+v", first)
+	}
+
+	var replayed []StreamChunk
+	second, err := newSession().SendStream(context.Background(), "find acme", func(c StreamChunk) {
+		replayed = append(replayed, c)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if provider.streams != 1 {
+		t.Fatalf("identical request should be served from cache, provider called %d times", provider.streams)
+	}
+	if !second.Cached || second.Usage != (Usage{}) {
+		t.Fatalf("cached response should be marked and cost nothing, got %+v", second)
+	}
+	if second.Content != "Searching." || second.FinishReason != "tool_use" {
+		t.Fatalf("unexpected cached response: %+v", second)
+	}
+	if len(second.ContentBlocks) != 1 || second.ContentBlocks[0].ToolUse.Name != "search" || string(second.ContentBlocks[0].ToolUse.Input) != string(input) {
+		t.Fatalf("tool call not replayed in content blocks: %+v", second.ContentBlocks)
+	}
+	if len(replayed) != 5 || replayed[0].Content != "Searching." || replayed[1].ToolCallStart == nil || !replayed[4].Done {
+		t.Fatalf("stream should be replayed to the callback, got %+v", replayed)
+	}
+
+	// A different question is a different request
+	if _, err := newSession().SendStream(context.Background(), "find globex", nil); err != nil {
+		t.Fatal(err)
+	}
+	if provider.streams != 2 {
+		t.Fatalf("different request should miss the cache, provider called %d times", provider.streams)
+	}
+}
+
+func TestResponseCache_Send(t *testing.T) {
+	provider := newMockProvider("42")
+	provider.chatResponse.FinishReason = "end_turn"
+	cache := &mapResponseCache{entries: map[string]*CachedResponse{}}
+
+	for i := 0; i < 2; i++ {
+		s := NewSession(provider, "test-model")
+		s.SetResponseCache(cache)
+		resp, err := s.Send(context.Background(), "meaning of life?")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.Content != "42" || resp.Cached != (i == 1) {
+			t.Fatalf("call %d: unexpected response %+v", i, resp)
+		}
+		if got := s.GetHistory(); len(got) != 2 || got[1].Content != "42" {
+			t.Fatalf("call %d: history should hold the exchange, got %+v", i, got)
+		}
+	}
+	if provider.chatCallCount != 1 {
+		t.Fatalf("expected 1 provider call, got %d", provider.chatCallCount)
+	}
+}
+
+func TestResponseCache_SkipsTruncatedResponses(t *testing.T) {
+	provider := &streamCountingProvider{mockProvider: &mockProvider{streamChunks: []StreamChunk{
+		{Content: "partial"},
+		{Done: true, StopReason: "max_tokens"},
+	}}}
+	cache := &mapResponseCache{entries: map[string]*CachedResponse{}}
+
+	for i := 0; i < 2; i++ {
+		s := NewSession(provider, "test-model")
+		s.SetResponseCache(cache)
+		if _, err := s.SendStream(context.Background(), "write a novel", nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if provider.streams != 2 {
+		t.Fatalf("truncated responses must not be cached, provider called %d times", provider.streams)
+	}
+}
+
+func TestRequestCacheKey_IgnoresPromptCaching(t *testing.T) {
+	req := &ChatRequest{Model: "m", Messages: []Message{{Role: RoleUser, Content: "hi"}}}
+	cached := *req
+	cached.PromptCaching = true
+	cached.ConversationCaching = true
+	if RequestCacheKey(req) != RequestCacheKey(&cached) {
+		t.Fatal("prompt caching flags should not change the key")
+	}
+	other := *req
+	other.Model = "m2"
+	if RequestCacheKey(req) == RequestCacheKey(&other) {
+		t.Fatal("model should change the key")
+	}
+}
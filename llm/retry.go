@@ -0,0 +1,135 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"time"
+
+	"squadron/failure"
+)
+
+// RetryPolicy controls how a Session retries transient provider errors:
+// rate limits (429), server errors (5xx), Anthropic overloaded (529), and
+// request timeouts. The backoff doubles after each failed attempt, starting
+// at InitialBackoff and capped at MaxBackoff.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of calls, including the first.
+	// 1 disables retries.
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// Default retry policy: up to 6 retries, waiting 2, 4, 8, 16, 32 and 64
+// seconds.
+const (
+	DefaultRetryMaxAttempts    = 7
+	DefaultRetryInitialBackoff = 2 * time.Second
+	DefaultRetryMaxBackoff     = 64 * time.Second
+)
+
+// DefaultRetryPolicy returns the policy sessions use unless SetRetryPolicy
+// is called.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    DefaultRetryMaxAttempts,
+		InitialBackoff: DefaultRetryInitialBackoff,
+		MaxBackoff:     DefaultRetryMaxBackoff,
+	}
+}
+
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = DefaultRetryMaxAttempts
+	}
+	if p.InitialBackoff <= 0 {
+		p.InitialBackoff = DefaultRetryInitialBackoff
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = max(DefaultRetryMaxBackoff, p.InitialBackoff)
+	}
+	return p
+}
+
+// Backoff returns the wait after the given failed attempt (0-based).
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	d := p.InitialBackoff
+	for i := 0; i < attempt && d < p.MaxBackoff; i++ {
+		d *= 2
+	}
+	return min(d, p.MaxBackoff)
+}
+
+// RetryEvent describes a retry a Session is about to make. Attempt is the
+// 1-based attempt that failed with Err; the next one starts after Backoff.
+type RetryEvent struct {
+	Attempt     int
+	MaxAttempts int
+	Backoff     time.Duration
+	Err         error
+}
+
+// retryableStatusCodes are HTTP status codes that indicate a transient error
+// worth retrying: rate limits (429), server errors (5xx), and Anthropic
+// overloaded (529).
+var retryableStatusCodes = []string{"429", "500", "502", "503", "504", "529"}
+
+// isRetryableError checks if an LLM provider error is transient and may
+// succeed on retry. Works across providers by checking the error message
+// for HTTP status codes (both OpenAI and Anthropic SDKs include them), and
+// treats network and request timeouts as transient. Callers must check the
+// request context first: a timeout caused by the caller's own deadline is
+// not retryable.
+func isRetryableError(err error) bool {
+	msg := err.Error()
+	for _, code := range retryableStatusCodes {
+		if strings.Contains(msg, code) {
+			return true
+		}
+	}
+	return isTimeoutError(err)
+}
+
+func isTimeoutError(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "timeout") || strings.Contains(msg, "timed out")
+}
+
+// contextTooLongMessages are fragments of the errors providers return when a
+// request exceeds the model's context window.
+var contextTooLongMessages = []string{
+	"prompt is too long",
+	"context_length_exceeded",
+	"maximum context length",
+	"context window",
+	"too many tokens",
+}
+
+// classifyProviderError tags an error a provider call finally failed with
+// (after any retries) with its failure class. Like isRetryableError it goes
+// by the message, which is all the provider SDKs share. Errors from the
+// caller's own context are returned unchanged.
+func classifyProviderError(ctx context.Context, err error) error {
+	if err == nil || ctx.Err() != nil {
+		return err
+	}
+	msg := strings.ToLower(err.Error())
+	if strings.Contains(msg, "429") {
+		return failure.Wrap(failure.ErrRateLimited, err)
+	}
+	for _, m := range contextTooLongMessages {
+		if strings.Contains(msg, m) {
+			return failure.Wrap(failure.ErrContextTooLong, err)
+		}
+	}
+	return failure.Wrap(failure.ErrProviderError, err)
+}
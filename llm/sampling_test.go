@@ -0,0 +1,62 @@
+package llm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+func testSampling() Sampling {
+	temperature, topP, seed := 0.0, 0.9, int64(7)
+	return Sampling{MaxTokens: 2048, Temperature: &temperature, TopP: &topP, Seed: &seed}
+}
+
+func TestSetSampling_PropagatesToRequestsAndClones(t *testing.T) {
+	p := &captureProvider{}
+	s := NewSession(p, "test-model")
+	s.SetSampling(testSampling())
+	if _, err := s.SendStream(context.Background(), "hi", nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Clone().ContinueStream(context.Background(), nil); err != nil {
+		t.Fatal(err)
+	}
+	for i, req := range p.requests {
+		if req.MaxTokens != 2048 || req.Temperature == nil || *req.Temperature != 0 || *req.TopP != 0.9 || *req.Seed != 7 {
+			t.Errorf("request %d sampling = %+v", i, req.Sampling)
+		}
+	}
+}
+
+func TestOpenAISampling(t *testing.T) {
+	p := &OpenAIProvider{}
+	params, err := p.buildResponseParams(&ChatRequest{Model: "gpt-5", Sampling: testSampling()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A temperature of 0 is sent, not dropped as unset
+	if !params.Temperature.Valid() || params.Temperature.Value != 0 || params.TopP.Value != 0.9 || params.MaxOutputTokens.Value != 2048 {
+		t.Errorf("unexpected params: temperature=%v top_p=%v max_output_tokens=%v", params.Temperature, params.TopP, params.MaxOutputTokens)
+	}
+
+	params, _ = p.buildResponseParams(&ChatRequest{Model: "gpt-5"})
+	if params.Temperature.Valid() || params.TopP.Valid() {
+		t.Error("expected no sampling params when unset")
+	}
+}
+
+func TestGeminiSampling(t *testing.T) {
+	cfg := (&GeminiProvider{}).buildConfig(&ChatRequest{Sampling: testSampling()}, nil)
+	if cfg.Temperature == nil || *cfg.Temperature != 0 || *cfg.TopP != 0.9 || *cfg.Seed != 7 || cfg.MaxOutputTokens != 2048 {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+}
+
+func TestAnthropicSampling(t *testing.T) {
+	var params anthropic.MessageNewParams
+	anthropicSampling(&params, testSampling())
+	if !params.Temperature.Valid() || params.Temperature.Value != 0 || params.TopP.Value != 0.9 {
+		t.Errorf("unexpected params: temperature=%v top_p=%v", params.Temperature, params.TopP)
+	}
+}
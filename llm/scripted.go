@@ -0,0 +1,177 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// ScriptedResponse is one canned reply of a ScriptedProvider.
+type ScriptedResponse struct {
+	// Match is tested against the request's prompt (see ScriptedProvider).
+	// Nil matches every request.
+	Match *regexp.Regexp
+	// Content is the reply's text.
+	Content string
+	// ToolCalls are made after the text, in order.
+	ToolCalls []ScriptedToolCall
+	// Error, when set, fails the call with this message instead of
+	// replying. Messages with a retryable status code ("503") are retried.
+	Error string
+	// Repeat keeps the response available after it has been used.
+	Repeat bool
+}
+
+// ScriptedToolCall is a tool call made by a ScriptedResponse.
+type ScriptedToolCall struct {
+	Name  string
+	Input json.RawMessage
+}
+
+// ScriptedProvider replays canned responses instead of calling a model, so
+// whole missions can run deterministically without API keys. Each call
+// gets the first unused response, in script order, whose Match matches the
+// prompt: the request's messages rendered one per line as "role: text",
+// with tool calls as "tool_use <name> <input>" and tool results as
+// "tool_result: <content>". A call no response matches fails.
+//
+// Token usage is estimated at four characters per token.
+type ScriptedProvider struct {
+	mu        sync.Mutex
+	responses []ScriptedResponse
+	used      []bool
+	calls     int
+}
+
+// NewScriptedProvider creates a provider that replays responses.
+func NewScriptedProvider(responses []ScriptedResponse) *ScriptedProvider {
+	return &ScriptedProvider{responses: responses, used: make([]bool, len(responses))}
+}
+
+// scriptedPrompt renders a request the way ScriptedResponse.Match sees it.
+func scriptedPrompt(req *ChatRequest) string {
+	var sb strings.Builder
+	for _, m := range req.Messages {
+		if len(m.Parts) == 0 {
+			fmt.Fprintf(&sb, "%s: %s\n", m.Role, m.Content)
+			continue
+		}
+		for _, p := range m.Parts {
+			switch {
+			case p.Type == ContentTypeText:
+				fmt.Fprintf(&sb, "%s: %s\n", m.Role, p.Text)
+			case p.Type == ContentTypeToolUse && p.ToolUse != nil:
+				fmt.Fprintf(&sb, "tool_use %s %s\n", p.ToolUse.Name, p.ToolUse.Input)
+			case p.Type == ContentTypeToolResult && p.ToolResult != nil:
+				fmt.Fprintf(&sb, "tool_result: %s\n", p.ToolResult.Content)
+			}
+		}
+	}
+	return sb.String()
+}
+
+// next picks the response for a request and builds its content blocks.
+func (p *ScriptedProvider) next(req *ChatRequest) (*ChatResponse, error) {
+	prompt := scriptedPrompt(req)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.calls++
+	for i, r := range p.responses {
+		if p.used[i] || (r.Match != nil && !r.Match.MatchString(prompt)) {
+			continue
+		}
+		if !r.Repeat {
+			p.used[i] = true
+		}
+		if r.Error != "" {
+			return nil, errors.New(r.Error)
+		}
+
+		resp := &ChatResponse{
+			ID:           fmt.Sprintf("scripted_%d", p.calls),
+			Content:      r.Content,
+			FinishReason: "end_turn",
+		}
+		if r.Content != "" {
+			resp.ContentBlocks = append(resp.ContentBlocks, ContentBlock{Type: ContentTypeText, Text: r.Content})
+		}
+		output := len(r.Content)
+		for j, tc := range r.ToolCalls {
+			input := tc.Input
+			if len(input) == 0 {
+				input = json.RawMessage(`{}`)
+			}
+			resp.ContentBlocks = append(resp.ContentBlocks, ContentBlock{
+				Type: ContentTypeToolUse,
+				ToolUse: &ToolUseBlock{
+					ID:    fmt.Sprintf("scripted_%d_%d", p.calls, j),
+					Name:  tc.Name,
+					Input: input,
+				},
+			})
+			output += len(tc.Name) + len(input)
+			resp.FinishReason = "tool_use"
+		}
+		resp.Usage = Usage{InputTokens: len(prompt) / 4, OutputTokens: output / 4}
+		return resp, nil
+	}
+	return nil, fmt.Errorf("scripted provider: no response matches call %d", p.calls)
+}
+
+// Calls returns how many calls the provider has received.
+func (p *ScriptedProvider) Calls() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.calls
+}
+
+// Remaining returns how many responses are still unused, not counting ones
+// that repeat.
+func (p *ScriptedProvider) Remaining() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	n := 0
+	for i, r := range p.responses {
+		if !p.used[i] && !r.Repeat {
+			n++
+		}
+	}
+	return n
+}
+
+func (p *ScriptedProvider) Chat(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return p.next(req)
+}
+
+func (p *ScriptedProvider) ChatStream(ctx context.Context, req *ChatRequest) (<-chan StreamChunk, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	resp, err := p.next(req)
+	if err != nil {
+		return nil, err
+	}
+	ch := make(chan StreamChunk, 2)
+	go func() {
+		defer close(ch)
+		if resp.Content != "" {
+			ch <- StreamChunk{Content: resp.Content}
+		}
+		usage := resp.Usage
+		ch <- StreamChunk{
+			Done:          true,
+			Usage:         &usage,
+			StopReason:    resp.FinishReason,
+			ContentBlocks: resp.ContentBlocks,
+		}
+	}()
+	return ch, nil
+}
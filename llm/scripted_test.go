@@ -0,0 +1,94 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestScriptedProvider_MatchesInScriptOrder(t *testing.T) {
+	p := NewScriptedProvider([]ScriptedResponse{
+		{Match: regexp.MustCompile(`user: second`), Content: "two"},
+		{Content: "any"},
+		{Match: regexp.MustCompile(`system: agent`), Content: "agent", Repeat: true},
+	})
+	req := func(system, user string) *ChatRequest {
+		return &ChatRequest{Messages: []Message{
+			{Role: RoleSystem, Content: system},
+			{Role: RoleUser, Content: user},
+		}}
+	}
+
+	for i, tc := range []struct{ system, user, want string }{
+		{"commander", "second", "two"},
+		{"agent", "first", "any"},
+		{"agent", "first", "agent"},
+		{"agent", "again", "agent"},
+	} {
+		resp, err := p.Chat(context.Background(), req(tc.system, tc.user))
+		if err != nil {
+			t.Fatalf("call %d: %v", i+1, err)
+		}
+		if resp.Content != tc.want {
+			t.Errorf("call %d: got %q, want %q", i+1, resp.Content, tc.want)
+		}
+	}
+
+	_, err := p.Chat(context.Background(), req("commander", "third"))
+	if err == nil || !strings.Contains(err.Error(), "no response matches call 5") {
+		t.Errorf("expected a no-match error, got %v", err)
+	}
+	if p.Calls() != 5 || p.Remaining() != 0 {
+		t.Errorf("Calls = %d, Remaining = %d", p.Calls(), p.Remaining())
+	}
+}
+
+func TestScriptedProvider_ToolCallsThroughSession(t *testing.T) {
+	p := NewScriptedProvider([]ScriptedResponse{
+		{Content: "Looking.", ToolCalls: []ScriptedToolCall{{Name: "search", Input: json.RawMessage(`{"q":"go"}`)}}},
+		{Match: regexp.MustCompile(`tool_result: 3 hits`), Content: "Found 3."},
+	})
+	s := NewSession(p, "scripted", "system")
+
+	resp, err := s.Send(context.Background(), "find go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var uses []ToolUseBlock
+	for _, b := range resp.ContentBlocks {
+		if b.Type == ContentTypeToolUse {
+			uses = append(uses, *b.ToolUse)
+		}
+	}
+	if len(uses) != 1 || uses[0].Name != "search" || string(uses[0].Input) != `{"q":"go"}` {
+		t.Fatalf("unexpected tool uses: %+v", uses)
+	}
+	if resp.Usage.InputTokens == 0 || resp.Usage.OutputTokens == 0 {
+		t.Errorf("expected estimated usage, got %+v", resp.Usage)
+	}
+
+	s.AddToolResults([]ToolResultBlock{{ToolUseID: uses[0].ID, Content: "3 hits"}})
+	resp, err = s.ContinueStream(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Content != "Found 3." {
+		t.Errorf("got %q", resp.Content)
+	}
+}
+
+func TestScriptedProvider_Error(t *testing.T) {
+	p := NewScriptedProvider([]ScriptedResponse{{Error: "503 Service Unavailable"}, {Content: "ok"}})
+	s := NewSession(p, "scripted", "system")
+	s.SetRetryPolicy(RetryPolicy{MaxAttempts: 2, InitialBackoff: 1, MaxBackoff: 1})
+
+	resp, err := s.SendStream(context.Background(), "hi", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Content != "ok" || p.Calls() != 2 {
+		t.Errorf("expected the scripted 503 to be retried, got %q after %d calls", resp.Content, p.Calls())
+	}
+}
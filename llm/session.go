@@ -10,6 +10,8 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type Session struct {
@@ -18,11 +20,24 @@ type Session struct {
 	systemPrompts []string
 	messages      []Message
 	debugFile     *os.File
+	redactor      *Redactor // scrubs secrets from debug file writes (nil = none)
 	stopSequences []string
 	tools         []ToolDefinition // Tool definitions for native tool calling
 	promptCaching        bool
 	conversationCaching  bool   // Whether to cache conversation history (disabled when pruning is active)
+	cachePrefix          int    // Leading system prompts shared with other sessions (0 = none)
 	reasoning            string // Native reasoning level: "", "low", "medium", "high"
+	sampling             Sampling
+	retryPolicy          RetryPolicy
+	onRetry              func(RetryEvent)
+	callLimiter          *CallLimiter // shared cap on concurrent LLM calls (nil = none)
+	compaction           *CompactionPolicy      // automatic compaction (nil = none)
+	onCompaction         func(CompactionEvent) // called after each automatic compaction
+	responseCache        ResponseCache          // replays responses to identical requests (nil = none)
+	contextWindow        int                    // model's context window in tokens (0 = unknown, no check)
+	usage                SessionUsage           // running token totals
+	promptTokens         int                    // reported prompt tokens of the last request...
+	promptEstimate       int                    // ...and its rough estimate, to calibrate later estimates
 }
 
 func NewSession(provider Provider, model string, systemPrompts ...string) *Session {
@@ -31,6 +46,7 @@ func NewSession(provider Provider, model string, systemPrompts ...string) *Sessi
 		model:         model,
 		systemPrompts: systemPrompts,
 		messages:      []Message{},
+		retryPolicy:   DefaultRetryPolicy(),
 	}
 }
 
@@ -43,6 +59,14 @@ func (s *Session) SetPromptCaching(enabled bool, conversationCaching bool) {
 	s.conversationCaching = conversationCaching
 }
 
+// MarkCachePrefix marks the system prompts added so far as a prefix other
+// sessions share, like the instructions and dependency context common to
+// all iterations of a task, so providers cache it separately from prompts
+// added later. Only used when prompt caching is enabled.
+func (s *Session) MarkCachePrefix() {
+	s.cachePrefix = len(s.systemPrompts)
+}
+
 // SetReasoning sets the native reasoning level for this session. Valid values:
 // "", "low", "medium", "high". Providers that don't support reasoning ignore it.
 func (s *Session) SetReasoning(level string) {
@@ -54,75 +78,51 @@ func (s *Session) GetReasoning() string {
 	return s.reasoning
 }
 
-// retryableStatusCodes are HTTP status codes that indicate a transient error
-// worth retrying: rate limits (429), server errors (5xx), and Anthropic
-// overloaded (529).
-var retryableStatusCodes = []string{"429", "500", "502", "503", "504", "529"}
-
-// isRetryableError checks if an LLM provider error is transient and may
-// succeed on retry. Works across providers by checking the error message
-// for HTTP status codes (both OpenAI and Anthropic SDKs include them).
-func isRetryableError(err error) bool {
-	msg := err.Error()
-	for _, code := range retryableStatusCodes {
-		if strings.Contains(msg, code) {
-			return true
-		}
-	}
-	return false
+// SetSampling sets the sampling settings sent with every request.
+func (s *Session) SetSampling(sampling Sampling) {
+	s.sampling = sampling
 }
 
-// retryBackoffs defines the exponential backoff schedule for retries.
-var retryBackoffs = []time.Duration{2, 4, 8, 16, 32, 64}
-
-// chatStreamWithRetry wraps provider.ChatStream with exponential backoff for
-// transient errors (429, 5xx). Retries up to 6 times with 2, 4, 8, 16, 32, 64 second delays.
-func (s *Session) chatStreamWithRetry(ctx context.Context, req *ChatRequest) (<-chan StreamChunk, error) {
-	for attempt := 0; ; attempt++ {
-		stream, err := s.provider.ChatStream(ctx, req)
-		if err == nil {
-			return stream, nil
-		}
-
-		if !isRetryableError(err) || attempt >= len(retryBackoffs) {
-			return nil, err
-		}
-
-		backoff := retryBackoffs[attempt] * time.Second
-		log.Printf("[LLM] Retryable error (attempt %d/%d: %v), retrying in %s...", attempt+1, len(retryBackoffs), err, backoff)
+// SetRetryPolicy sets how transient provider errors are retried. Zero fields
+// keep their defaults.
+func (s *Session) SetRetryPolicy(p RetryPolicy) {
+	s.retryPolicy = p.withDefaults()
+}
 
-		// TODO(mission-issue): emit a warning-severity mission_issue with
-		// category=provider_error, retrying=true, details={attempt, backoff}.
-		// Session currently has no handle on the mission streamer; add an
-		// optional callback to Session so the runner/commander can wire it.
+// SetRetryObserver registers a callback invoked before each retry, so the
+// caller can surface provider trouble (debug log, mission events).
+func (s *Session) SetRetryObserver(fn func(RetryEvent)) {
+	s.onRetry = fn
+}
 
-		select {
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		case <-time.After(backoff):
-		}
-	}
+// SetCallLimiter makes every LLM call of this session take a slot from l
+// first. Slots are held for one attempt, not across retry backoff.
+func (s *Session) SetCallLimiter(l *CallLimiter) {
+	s.callLimiter = l
 }
 
 // streamWithRetry handles the full stream lifecycle with retries.
-// Connection and mid-stream errors are retried with backoff.
-// On retry, the onChunk callback is suppressed to avoid sending
-// duplicate/garbled chunks to the UI — only the final successful
-// stream delivers chunks.
+// Connection and mid-stream errors are retried with backoff according to the
+// session's RetryPolicy. On retry, the onChunk callback is suppressed to
+// avoid sending duplicate/garbled chunks to the UI — only the final
+// successful stream delivers chunks.
 func (s *Session) streamWithRetry(ctx context.Context, req *ChatRequest, onChunk func(StreamChunk)) (streamResult, error) {
+	cacheKey, cached, ok := s.cachedResult(req)
+	if ok {
+		replayChunks(cached, onChunk)
+		return cached, nil
+	}
 	for attempt := 0; ; attempt++ {
+		release, err := s.callLimiter.Acquire(ctx)
+		if err != nil {
+			return streamResult{}, err
+		}
 		stream, err := s.provider.ChatStream(ctx, req)
 		if err != nil {
-			if !isRetryableError(err) || attempt >= len(retryBackoffs) {
+			release()
+			if err := s.awaitRetry(ctx, attempt, "connection", err); err != nil {
 				return streamResult{}, err
 			}
-			backoff := retryBackoffs[attempt] * time.Second
-			log.Printf("[LLM] Retryable connection error (attempt %d/%d: %v), retrying in %s...", attempt+1, len(retryBackoffs), err, backoff)
-			select {
-			case <-ctx.Done():
-				return streamResult{}, ctx.Err()
-			case <-time.After(backoff):
-			}
 			continue
 		}
 
@@ -134,21 +134,47 @@ func (s *Session) streamWithRetry(ctx context.Context, req *ChatRequest, onChunk
 		}
 
 		sr, streamErr := readStream(ctx, stream, cb)
+		release()
 		if streamErr == nil {
+			s.storeResult(cacheKey, sr)
 			return sr, nil
 		}
-
-		if !isRetryableError(streamErr) || attempt >= len(retryBackoffs) {
-			return streamResult{}, streamErr
+		if err := s.awaitRetry(ctx, attempt, "stream", streamErr); err != nil {
+			return streamResult{}, err
 		}
+	}
+}
 
-		backoff := retryBackoffs[attempt] * time.Second
-		log.Printf("[LLM] Retryable stream error (attempt %d/%d: %v), retrying in %s...", attempt+1, len(retryBackoffs), streamErr, backoff)
-		select {
-		case <-ctx.Done():
-			return streamResult{}, ctx.Err()
-		case <-time.After(backoff):
-		}
+// awaitRetry decides whether the failed attempt (0-based) is retried. If
+// so it reports the retry and waits out the backoff, returning nil;
+// otherwise it returns the error the call should fail with.
+func (s *Session) awaitRetry(ctx context.Context, attempt int, phase string, err error) error {
+	policy := s.retryPolicy
+	if ctx.Err() != nil || !isRetryableError(err) || attempt+1 >= policy.MaxAttempts {
+		return classifyProviderError(ctx, err)
+	}
+
+	backoff := policy.Backoff(attempt)
+	log.Printf("[LLM] Retryable %s error (attempt %d/%d: %v), retrying in %s...", phase, attempt+1, policy.MaxAttempts, err, backoff)
+	trace.SpanFromContext(ctx).AddEvent("retry", trace.WithAttributes(
+		attribute.Int("attempt", attempt+1),
+		attribute.String("phase", phase),
+		attribute.String("error", err.Error()),
+	))
+	if s.onRetry != nil {
+		s.onRetry(RetryEvent{
+			Attempt:     attempt + 1,
+			MaxAttempts: policy.MaxAttempts,
+			Backoff:     backoff,
+			Err:         err,
+		})
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(backoff):
+		return nil
 	}
 }
 
@@ -168,6 +194,12 @@ func (s *Session) EnableDebug(filename string) error {
 	return nil
 }
 
+// SetRedactor sets the redactor applied to everything written to the debug
+// file. Set it before EnableDebug so the system prompts are covered too.
+func (s *Session) SetRedactor(r *Redactor) {
+	s.redactor = r
+}
+
 // Close closes any open resources
 func (s *Session) Close() {
 	if s.debugFile != nil {
@@ -181,7 +213,7 @@ func (s *Session) logMessage(label string, content string) {
 	}
 	timestamp := time.Now().Format(time.RFC3339)
 	s.debugFile.WriteString(fmt.Sprintf("[%s] === %s ===\n", timestamp, label))
-	s.debugFile.WriteString(content)
+	s.debugFile.WriteString(s.redactor.Redact(content))
 	s.debugFile.WriteString("\n\n")
 }
 
@@ -357,7 +389,17 @@ func (s *Session) Clone() *Session {
 		tools:               toolsCopy,
 		promptCaching:       s.promptCaching,
 		conversationCaching: s.conversationCaching,
+		cachePrefix:         s.cachePrefix,
 		reasoning:           s.reasoning,
+		sampling:            s.sampling,
+		retryPolicy:         s.retryPolicy,
+		onRetry:             s.onRetry,
+		callLimiter:         s.callLimiter,
+		responseCache:       s.responseCache,
+		contextWindow:       s.contextWindow,
+		promptTokens:        s.promptTokens,
+		promptEstimate:      s.promptEstimate,
+		redactor:            s.redactor,
 		debugFile:           nil, // Don't share debug file - clones are for isolated queries
 	}
 }
@@ -384,7 +426,10 @@ func (s *Session) buildMessagesWithMessage(userMsg Message) []Message {
 	return msgs
 }
 
-func (s *Session) Send(ctx context.Context, userMessage string) (*ChatResponse, error) {
+func (s *Session) Send(ctx context.Context, userMessage string) (resp *ChatResponse, err error) {
+	ctx, span := s.startChatSpan(ctx)
+	defer func() { endChatSpan(span, resp, err) }()
+
 	s.logMessage("User Message", userMessage)
 
 	req := &ChatRequest{
@@ -394,14 +439,37 @@ func (s *Session) Send(ctx context.Context, userMessage string) (*ChatResponse,
 		Tools:               s.tools,
 		PromptCaching:       s.promptCaching,
 		ConversationCaching: s.conversationCaching,
+		CachePrefix:         s.cachePrefix,
 		Reasoning:           s.reasoning,
+		Sampling:            s.sampling,
 	}
 
-	resp, err := s.provider.Chat(ctx, req)
-	if err != nil {
+	if err := s.checkContextWindow(ctx, req, func() []Message { return s.buildMessages(userMessage) }); err != nil {
 		return nil, err
 	}
 
+	cacheKey, cached, ok := s.cachedResult(req)
+	if ok {
+		resp = &ChatResponse{
+			ID:            uuid.New().String(),
+			Content:       cached.TextContent,
+			ContentBlocks: cached.ContentBlocks,
+			FinishReason:  cached.StopReason,
+			Cached:        true,
+		}
+	} else {
+		release, err := s.callLimiter.Acquire(ctx)
+		if err != nil {
+			return nil, err
+		}
+		resp, err = s.provider.Chat(ctx, req)
+		release()
+		if err != nil {
+			return nil, classifyProviderError(ctx, err)
+		}
+		s.storeResult(cacheKey, streamResult{TextContent: resp.Content, ContentBlocks: resp.ContentBlocks, StopReason: resp.FinishReason})
+	}
+
 	resp.Content = s.stripStopSequences(resp.Content)
 
 	s.logMessage("LLM Response", resp.Content)
@@ -409,6 +477,8 @@ func (s *Session) Send(ctx context.Context, userMessage string) (*ChatResponse,
 	// Append user message and assistant response to history
 	s.messages = append(s.messages, Message{Role: RoleUser, Content: userMessage})
 	s.messages = append(s.messages, s.buildAssistantMessage(resp.Content, resp.ContentBlocks))
+	s.recordUsage(req, resp.Usage, resp.Cached)
+	s.compactIfNeeded(ctx, resp.Usage.InputTokens)
 
 	return resp, nil
 }
@@ -461,7 +531,10 @@ func (s *Session) buildAssistantMessage(textContent string, contentBlocks []Cont
 	}
 }
 
-func (s *Session) SendStream(ctx context.Context, userMessage string, onChunk func(StreamChunk)) (*ChatResponse, error) {
+func (s *Session) SendStream(ctx context.Context, userMessage string, onChunk func(StreamChunk)) (resp *ChatResponse, err error) {
+	ctx, span := s.startChatSpan(ctx)
+	defer func() { endChatSpan(span, resp, err) }()
+
 	s.logMessage("User Message", userMessage)
 
 	req := &ChatRequest{
@@ -471,7 +544,13 @@ func (s *Session) SendStream(ctx context.Context, userMessage string, onChunk fu
 		Tools:               s.tools,
 		PromptCaching:       s.promptCaching,
 		ConversationCaching: s.conversationCaching,
+		CachePrefix:         s.cachePrefix,
 		Reasoning:           s.reasoning,
+		Sampling:            s.sampling,
+	}
+
+	if err := s.checkContextWindow(ctx, req, func() []Message { return s.buildMessages(userMessage) }); err != nil {
+		return nil, err
 	}
 
 	sr, err := s.streamWithRetry(ctx, req, onChunk)
@@ -484,11 +563,12 @@ func (s *Session) SendStream(ctx context.Context, userMessage string, onChunk fu
 	s.logMessage("LLM Response", content)
 
 	// Build the final response
-	resp := &ChatResponse{
+	resp = &ChatResponse{
 		ID:            uuid.New().String(),
 		Content:       content,
 		ContentBlocks: sr.ContentBlocks,
 		FinishReason:  sr.StopReason,
+		Cached:        sr.Cached,
 	}
 
 	// Capture usage from the final chunk if provider included it
@@ -499,6 +579,8 @@ func (s *Session) SendStream(ctx context.Context, userMessage string, onChunk fu
 	// Append user message and assistant response to history
 	s.messages = append(s.messages, Message{Role: RoleUser, Content: userMessage})
 	s.messages = append(s.messages, s.buildAssistantMessage(content, sr.ContentBlocks))
+	s.recordUsage(req, resp.Usage, resp.Cached)
+	s.compactIfNeeded(ctx, resp.Usage.InputTokens)
 
 	return resp, nil
 }
@@ -507,7 +589,10 @@ func (s *Session) SendStream(ctx context.Context, userMessage string, onChunk fu
 // Unlike SendStream, it sends the existing history as-is and only appends the assistant
 // response. Used when resuming an interrupted session where a pending user message is
 // already in the history.
-func (s *Session) ContinueStream(ctx context.Context, onChunk func(StreamChunk)) (*ChatResponse, error) {
+func (s *Session) ContinueStream(ctx context.Context, onChunk func(StreamChunk)) (resp *ChatResponse, err error) {
+	ctx, span := s.startChatSpan(ctx)
+	defer func() { endChatSpan(span, resp, err) }()
+
 	s.logMessage("Continue", "(resuming from existing state)")
 
 	req := &ChatRequest{
@@ -517,7 +602,13 @@ func (s *Session) ContinueStream(ctx context.Context, onChunk func(StreamChunk))
 		Tools:               s.tools,
 		PromptCaching:       s.promptCaching,
 		ConversationCaching: s.conversationCaching,
+		CachePrefix:         s.cachePrefix,
 		Reasoning:           s.reasoning,
+		Sampling:            s.sampling,
+	}
+
+	if err := s.checkContextWindow(ctx, req, func() []Message { return s.buildCurrentMessages() }); err != nil {
+		return nil, err
 	}
 
 	sr, err := s.streamWithRetry(ctx, req, onChunk)
@@ -530,11 +621,12 @@ func (s *Session) ContinueStream(ctx context.Context, onChunk func(StreamChunk))
 	s.logMessage("LLM Response", content)
 
 	// Build the final response
-	resp := &ChatResponse{
+	resp = &ChatResponse{
 		ID:            uuid.New().String(),
 		Content:       content,
 		ContentBlocks: sr.ContentBlocks,
 		FinishReason:  sr.StopReason,
+		Cached:        sr.Cached,
 	}
 
 	// Capture usage from the final chunk if provider included it
@@ -544,6 +636,8 @@ func (s *Session) ContinueStream(ctx context.Context, onChunk func(StreamChunk))
 
 	// Append ONLY the assistant response (no user message — it's already in history)
 	s.messages = append(s.messages, s.buildAssistantMessage(content, sr.ContentBlocks))
+	s.recordUsage(req, resp.Usage, resp.Cached)
+	s.compactIfNeeded(ctx, resp.Usage.InputTokens)
 
 	return resp, nil
 }
@@ -615,77 +709,23 @@ func (s *Session) DropOldMessages(keep int) int {
 // older messages with a compressed summary. System prompts are never affected.
 // Returns the number of messages that were compacted.
 func (s *Session) Compact(turnRetention int) int {
-	// A turn is a user message + assistant response pair (2 messages)
-	messagesToRetain := turnRetention * 2
-
-	// If we don't have enough messages to compact, return early
-	if len(s.messages) <= messagesToRetain {
-		return 0
-	}
-
-	// Identify messages to compact (everything before the retained turns)
-	compactEnd := len(s.messages) - messagesToRetain
-	if compactEnd <= 0 {
-		return 0
-	}
-
-	// Build a summary of the compacted messages
-	summary := s.buildCompactionSummary(s.messages[:compactEnd])
-
-	// Create the new message history: summary + retained turns
-	newMessages := make([]Message, 0, 1+messagesToRetain)
-	newMessages = append(newMessages, Message{
-		Role:    RoleUser,
-		Content: summary,
-	})
-	newMessages = append(newMessages, s.messages[compactEnd:]...)
-
-	compactedCount := compactEnd
-	s.messages = newMessages
-
-	s.logMessage("Compaction", fmt.Sprintf("Compacted %d messages into summary. Retained last %d turns.", compactedCount, turnRetention))
-
-	return compactedCount
+	return s.CompactWithContext(turnRetention, "")
 }
 
 // CompactWithContext is like Compact but injects additional context into the compaction summary.
 // Used by commanders to preserve state like dataset progress and subtask status.
 func (s *Session) CompactWithContext(turnRetention int, extraContext string) int {
-	// A turn is a user message + assistant response pair (2 messages)
-	messagesToRetain := turnRetention * 2
-
-	if len(s.messages) <= messagesToRetain {
-		return 0
-	}
-
-	compactEnd := len(s.messages) - messagesToRetain
+	compactEnd := s.compactionBoundary(turnRetention)
 	if compactEnd <= 0 {
 		return 0
 	}
 
 	summary := s.buildCompactionSummary(s.messages[:compactEnd])
+	s.replaceWithSummary(compactEnd, withExtraContext(summary, extraContext))
 
-	// Inject extra context after the opening tag and description
-	if extraContext != "" {
-		insertPoint := strings.Index(summary, "\n\n")
-		if insertPoint != -1 {
-			summary = summary[:insertPoint+2] + extraContext + "\n" + summary[insertPoint+2:]
-		}
-	}
-
-	newMessages := make([]Message, 0, 1+messagesToRetain)
-	newMessages = append(newMessages, Message{
-		Role:    RoleUser,
-		Content: summary,
-	})
-	newMessages = append(newMessages, s.messages[compactEnd:]...)
-
-	compactedCount := compactEnd
-	s.messages = newMessages
+	s.logMessage("Compaction", fmt.Sprintf("Compacted %d messages into summary. Retained last %d turns.", compactEnd, turnRetention))
 
-	s.logMessage("Compaction", fmt.Sprintf("Compacted %d messages into summary with extra context. Retained last %d turns.", compactedCount, turnRetention))
-
-	return compactedCount
+	return compactEnd
 }
 
 // buildCompactionSummary creates a condensed summary of compacted messages
@@ -695,40 +735,7 @@ func (s *Session) buildCompactionSummary(messages []Message) string {
 	summary.WriteString("The following is a summary of earlier conversation history that has been compacted to reduce context size:\n\n")
 
 	// CRITICAL: Preserve the most recent task message so the agent knows its current assignment.
-	// - First user message is the original task (if no later tasks)
-	// - Messages with <NEW_TASK> are follow-up assignments - keep only the LAST one
-	var lastTask string
-	for i, msg := range messages {
-		if msg.Role != RoleUser {
-			continue
-		}
-		content := msg.GetTextContent()
-
-		// Skip tool results
-		if msg.HasParts() {
-			isToolResult := false
-			for _, part := range msg.Parts {
-				if part.Type == ContentTypeToolResult {
-					isToolResult = true
-					break
-				}
-			}
-			if isToolResult {
-				continue
-			}
-		}
-
-		// First user message is the original task
-		if i == 0 {
-			lastTask = content
-			continue
-		}
-
-		// Later <NEW_TASK> messages override as the current task
-		if strings.Contains(content, "<NEW_TASK>") {
-			lastTask = content
-		}
-	}
+	lastTask := currentTask(messages)
 
 	if lastTask != "" {
 		summary.WriteString("**Current Task:**\n")
@@ -755,14 +762,11 @@ func (s *Session) buildCompactionSummary(messages []Message) string {
 
 		// Extract answers from assistant messages
 		if msg.Role == RoleAssistant {
-			if answerStart := strings.Index(content, "<ANSWER>"); answerStart != -1 {
-				if answerEnd := strings.Index(content[answerStart:], "</ANSWER>"); answerEnd != -1 {
-					answer := content[answerStart+8 : answerStart+answerEnd]
-					if len(answer) > 200 {
-						answer = answer[:200] + "..."
-					}
-					keyFindings = append(keyFindings, strings.TrimSpace(answer))
+			if answer, ok := extractAnswer(content); ok {
+				if len(answer) > 200 {
+					answer = answer[:200] + "..."
 				}
+				keyFindings = append(keyFindings, answer)
 			}
 		}
 	}
@@ -790,6 +794,19 @@ func (s *Session) buildCompactionSummary(messages []Message) string {
 	return summary.String()
 }
 
+// extractAnswer returns the trimmed text between <ANSWER> and </ANSWER>.
+func extractAnswer(content string) (string, bool) {
+	answerStart := strings.Index(content, "<ANSWER>")
+	if answerStart == -1 {
+		return "", false
+	}
+	answerEnd := strings.Index(content[answerStart:], "</ANSWER>")
+	if answerEnd == -1 {
+		return "", false
+	}
+	return strings.TrimSpace(content[answerStart+8 : answerStart+answerEnd]), true
+}
+
 // uniqueStrings returns unique strings preserving order
 func uniqueStrings(strs []string) []string {
 	seen := make(map[string]bool)
@@ -805,7 +822,10 @@ func uniqueStrings(strs []string) []string {
 
 // SendMessageStream sends a multimodal message and streams the response
 // Use this for messages containing images or mixed content
-func (s *Session) SendMessageStream(ctx context.Context, userMsg Message, onChunk func(StreamChunk)) (*ChatResponse, error) {
+func (s *Session) SendMessageStream(ctx context.Context, userMsg Message, onChunk func(StreamChunk)) (resp *ChatResponse, err error) {
+	ctx, span := s.startChatSpan(ctx)
+	defer func() { endChatSpan(span, resp, err) }()
+
 	// Log text content for debugging (images are not logged)
 	s.logMessage("User Message", userMsg.GetTextContent())
 	if userMsg.HasParts() {
@@ -823,7 +843,13 @@ func (s *Session) SendMessageStream(ctx context.Context, userMsg Message, onChun
 		Tools:               s.tools,
 		PromptCaching:       s.promptCaching,
 		ConversationCaching: s.conversationCaching,
+		CachePrefix:         s.cachePrefix,
 		Reasoning:           s.reasoning,
+		Sampling:            s.sampling,
+	}
+
+	if err := s.checkContextWindow(ctx, req, func() []Message { return s.buildMessagesWithMessage(userMsg) }); err != nil {
+		return nil, err
 	}
 
 	sr, err := s.streamWithRetry(ctx, req, onChunk)
@@ -836,11 +862,12 @@ func (s *Session) SendMessageStream(ctx context.Context, userMsg Message, onChun
 	s.logMessage("LLM Response", content)
 
 	// Build the final response
-	resp := &ChatResponse{
+	resp = &ChatResponse{
 		ID:            uuid.New().String(),
 		Content:       content,
 		ContentBlocks: sr.ContentBlocks,
 		FinishReason:  sr.StopReason,
+		Cached:        sr.Cached,
 	}
 
 	// Capture usage from the final chunk if provider included it
@@ -851,6 +878,8 @@ func (s *Session) SendMessageStream(ctx context.Context, userMsg Message, onChun
 	// Append user message and assistant response to history
 	s.messages = append(s.messages, userMsg)
 	s.messages = append(s.messages, s.buildAssistantMessage(content, sr.ContentBlocks))
+	s.recordUsage(req, resp.Usage, resp.Cached)
+	s.compactIfNeeded(ctx, resp.Usage.InputTokens)
 
 	return resp, nil
 }
@@ -861,6 +890,7 @@ type streamResult struct {
 	ContentBlocks []ContentBlock
 	StopReason    string
 	LastChunk     StreamChunk
+	Cached        bool // replayed from the response cache
 }
 
 // readStream reads chunks from a stream channel, respecting context cancellation.
@@ -2,10 +2,13 @@ package llm
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
 	"time"
+
+	"squadron/failure"
 )
 
 // failingProvider returns errors for the first N calls, then succeeds.
@@ -145,6 +148,8 @@ func TestIsRetryableError(t *testing.T) {
 		{`POST "https://api.openai.com/v1/chat": 401 Unauthorized`, false},
 		{`POST "https://api.openai.com/v1/chat": 403 Forbidden`, false},
 		{`connection refused`, false},
+		{`Post "https://api.openai.com/v1/chat": net/http: request canceled (Client.Timeout exceeded while awaiting headers)`, true},
+		{`read tcp 10.0.0.1:443: i/o timeout`, true},
 	}
 
 	for _, tt := range tests {
@@ -156,6 +161,100 @@ func TestIsRetryableError(t *testing.T) {
 	}
 }
 
+func TestClassifyProviderError(t *testing.T) {
+	tests := []struct {
+		msg  string
+		code failure.Code
+	}{
+		{`POST "https://api.openai.com/v1/chat": 429 Too Many Requests`, failure.CodeRateLimited},
+		{`POST "https://api.anthropic.com/v1/messages": 400 Bad Request: prompt is too long: 210000 tokens > 200000 maximum`, failure.CodeContextTooLong},
+		{`POST "https://api.openai.com/v1/chat": 400 Bad Request: context_length_exceeded`, failure.CodeContextTooLong},
+		{`POST "https://api.openai.com/v1/chat": 401 Unauthorized`, failure.CodeProviderError},
+		{`connection refused`, failure.CodeProviderError},
+	}
+
+	for _, tt := range tests {
+		err := classifyProviderError(context.Background(), fmt.Errorf("%s", tt.msg))
+		if got := failure.CodeOf(err); got != tt.code {
+			t.Errorf("classifyProviderError(%q) code = %q, want %q", tt.msg, got, tt.code)
+		}
+		if err.Error() != tt.msg {
+			t.Errorf("classifyProviderError(%q) changed the message to %q", tt.msg, err.Error())
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := classifyProviderError(ctx, context.Canceled); err != context.Canceled {
+		t.Errorf("expected a cancelled call's error unchanged, got %v", err)
+	}
+}
+
+func TestRetry_ExhaustedIsRateLimited(t *testing.T) {
+	provider := &failingProvider{failCount: 100, statusCode: 429}
+	session := NewSession(provider, "test-model", "system prompt")
+	session.SetRetryPolicy(RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond})
+
+	_, err := session.SendStream(context.Background(), "hello", nil)
+	if !errors.Is(err, failure.ErrRateLimited) {
+		t.Fatalf("expected ErrRateLimited after exhausting retries, got: %v", err)
+	}
+}
+
+func TestRetry_CustomPolicy(t *testing.T) {
+	provider := &failingProvider{failCount: 100, statusCode: 429}
+	session := NewSession(provider, "test-model", "system prompt")
+	session.SetRetryPolicy(RetryPolicy{MaxAttempts: 3, InitialBackoff: 10 * time.Millisecond})
+
+	var events []RetryEvent
+	session.SetRetryObserver(func(ev RetryEvent) { events = append(events, ev) })
+
+	_, err := session.SendStream(context.Background(), "hello", nil)
+	if err == nil || !strings.Contains(err.Error(), "429") {
+		t.Fatalf("expected the 429 error after exhausting retries, got: %v", err)
+	}
+	if provider.callCount != 3 {
+		t.Fatalf("expected 3 calls (max_attempts), got %d", provider.callCount)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected 2 retry events, got %d", len(events))
+	}
+	if events[0].Attempt != 1 || events[0].MaxAttempts != 3 || events[0].Backoff != 10*time.Millisecond {
+		t.Errorf("unexpected first event: %+v", events[0])
+	}
+	if events[1].Attempt != 2 || events[1].Backoff != 20*time.Millisecond {
+		t.Errorf("unexpected second event: %+v", events[1])
+	}
+}
+
+func TestRetry_SingleAttemptDisablesRetries(t *testing.T) {
+	provider := &failingProvider{failCount: 1, statusCode: 503}
+	session := NewSession(provider, "test-model", "system prompt")
+	session.SetRetryPolicy(RetryPolicy{MaxAttempts: 1})
+
+	if _, err := session.SendStream(context.Background(), "hello", nil); err == nil {
+		t.Fatal("expected error with retries disabled")
+	}
+	if provider.callCount != 1 {
+		t.Fatalf("expected 1 call, got %d", provider.callCount)
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	p := RetryPolicy{InitialBackoff: time.Second, MaxBackoff: 5 * time.Second}
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for attempt, w := range want {
+		if got := p.Backoff(attempt); got != w {
+			t.Errorf("Backoff(%d) = %s, want %s", attempt, got, w)
+		}
+	}
+
+	d := DefaultRetryPolicy()
+	if got := d.Backoff(5); got != 64*time.Second {
+		t.Errorf("default Backoff(5) = %s, want 64s", got)
+	}
+}
+
 // ---------------------------------------------------------------------------
 // mockProvider — configurable provider for non-retry tests
 // ---------------------------------------------------------------------------
@@ -0,0 +1,35 @@
+package llm
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"squadron/tracing"
+)
+
+// startChatSpan starts the span covering one LLM call, retries included.
+func (s *Session) startChatSpan(ctx context.Context) (context.Context, trace.Span) {
+	return tracing.Start(ctx, "llm "+s.model,
+		attribute.String("gen_ai.request.model", s.model),
+		attribute.Int("squadron.llm.history_messages", len(s.messages)),
+		attribute.Int("squadron.llm.tools", len(s.tools)),
+	)
+}
+
+// endChatSpan records the response's usage and stop reason, or err, and ends
+// the span.
+func endChatSpan(span trace.Span, resp *ChatResponse, err error) {
+	if resp != nil {
+		span.SetAttributes(
+			attribute.Int("gen_ai.usage.input_tokens", resp.Usage.InputTokens),
+			attribute.Int("gen_ai.usage.output_tokens", resp.Usage.OutputTokens),
+			attribute.Int("squadron.llm.cache_read_tokens", resp.Usage.CacheReadTokens),
+			attribute.Int("squadron.llm.cache_write_tokens", resp.Usage.CacheWriteTokens),
+			attribute.String("gen_ai.response.finish_reason", resp.FinishReason),
+			attribute.Bool("squadron.llm.cached", resp.Cached),
+		)
+	}
+	tracing.End(span, err)
+}
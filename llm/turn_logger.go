@@ -12,6 +12,7 @@ const contentPreviewMaxLen = 200
 type TurnLogger struct {
 	file      *os.File
 	turnCount int
+	redactor  *Redactor
 }
 
 // NewTurnLogger creates a turn logger that writes to the given file path.
@@ -24,6 +25,11 @@ func NewTurnLogger(filename string) (*TurnLogger, error) {
 	return &TurnLogger{file: f}, nil
 }
 
+// SetRedactor sets the redactor applied to content previews.
+func (tl *TurnLogger) SetRedactor(r *Redactor) {
+	tl.redactor = r
+}
+
 // Close closes the underlying file.
 func (tl *TurnLogger) Close() {
 	if tl.file != nil {
@@ -80,8 +86,8 @@ func (tl *TurnLogger) LogTurn(action string, messages []Message) {
 		}
 
 		// Content info
-		text := msg.GetTextContent()
-		ms.ContentLength = len(text)
+		ms.ContentLength = len(msg.GetTextContent())
+		text := tl.redactor.Redact(msg.GetTextContent())
 		if len(text) > contentPreviewMaxLen {
 			ms.ContentPreview = text[:contentPreviewMaxLen] + "..."
 		} else if text != "" {
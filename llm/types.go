@@ -197,19 +197,36 @@ type StreamChunk struct {
 	ContentBlocks []ContentBlock // Accumulated structured content blocks
 }
 
+// Sampling controls how a model generates its output. Unset fields (nil
+// or 0) leave the provider's default.
+type Sampling struct {
+	MaxTokens   int // Max output tokens
+	Temperature *float64
+	TopP        *float64
+	// Seed asks for reproducible sampling. Only Gemini takes one; the other
+	// providers ignore it.
+	Seed *int64
+}
+
 type ChatRequest struct {
 	Model               string
 	Messages            []Message
-	MaxTokens           int
-	Temperature         float64
+	Sampling            // MaxTokens, Temperature, TopP and Seed
 	StopSequences       []string
 	PromptCaching       bool             // Cache system prompts
 	ConversationCaching bool             // Cache conversation history (last user message breakpoint)
+	// CachePrefix is the number of leading system messages that form a
+	// prefix shared with other sessions, such as a task's instructions and
+	// dependency context (0 = none). See Session.MarkCachePrefix.
+	CachePrefix int
 	Tools               []ToolDefinition // Tool definitions for native tool calling
 	// Reasoning, when non-empty, requests native provider reasoning at the
 	// given level. Valid values: "low", "medium", "high". Providers that don't
 	// support native reasoning silently ignore this field.
 	Reasoning string
+	// Headers are sent as extra HTTP headers with the request, typically
+	// set by a Middleware.
+	Headers map[string]string
 }
 
 type ChatResponse struct {
@@ -218,6 +235,7 @@ type ChatResponse struct {
 	ContentBlocks []ContentBlock // Full structured response (text + tool_use blocks)
 	FinishReason  string
 	Usage         Usage
+	Cached        bool // replayed from the response cache; Usage is zero
 }
 
 type Usage struct {
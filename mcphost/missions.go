@@ -0,0 +1,359 @@
+package mcphost
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"squadron/config"
+	"squadron/store"
+	"squadron/streamers"
+	"squadron/streamers/ndjson"
+)
+
+// MissionDeps holds the dependencies of the mission server started by
+// `squadron mcp-serve`.
+type MissionDeps struct {
+	// Config is the validated config whose missions become tools.
+	Config *config.Config
+	// Version is the squadron CLI version string.
+	Version string
+	// RunMission runs a mission to completion, sending its events to
+	// handler, and returns the run's results. A nil result means the mission
+	// could not be started (unknown mission, bad inputs).
+	RunMission func(ctx context.Context, name string, inputs map[string]string, handler streamers.MissionHandler) (*MissionRun, error)
+	// EventLog receives every mission event as NDJSON. Defaults to discarding
+	// them; it must not be the stdio transport's stdout.
+	EventLog io.Writer
+}
+
+// MissionRun is the result returned to the MCP client when a mission tool
+// call finishes.
+type MissionRun struct {
+	MissionID string              `json:"missionId"`
+	Mission   string              `json:"mission"`
+	Status    string              `json:"status"`
+	Error     string              `json:"error,omitempty"`
+	Tasks     []MissionTaskResult `json:"tasks"`
+}
+
+// MissionTaskResult is one task of a finished MissionRun.
+type MissionTaskResult struct {
+	Name    string  `json:"name"`
+	Status  string  `json:"status"`
+	Summary *string `json:"summary,omitempty"`
+	Output  any     `json:"output,omitempty"`
+	Error   *string `json:"error,omitempty"`
+}
+
+// CollectMissionRun reads a mission's record and task results from the
+// store. runErr, if set, is reported as the run's error.
+func CollectMissionRun(missions store.MissionStore, missionID string, runErr error) (*MissionRun, error) {
+	record, err := missions.GetMission(missionID)
+	if err != nil {
+		return nil, fmt.Errorf("get mission: %w", err)
+	}
+	tasks, err := missions.GetTasksByMission(missionID)
+	if err != nil {
+		return nil, fmt.Errorf("get tasks: %w", err)
+	}
+
+	run := &MissionRun{
+		MissionID: record.ID,
+		Mission:   record.MissionName,
+		Status:    record.Status,
+		Tasks:     make([]MissionTaskResult, 0, len(tasks)),
+	}
+	if runErr != nil {
+		run.Error = runErr.Error()
+	}
+	for _, t := range tasks {
+		tr := MissionTaskResult{
+			Name:    t.TaskName,
+			Status:  t.Status,
+			Summary: t.Summary,
+			Error:   t.Error,
+		}
+		if t.OutputJSON != nil && *t.OutputJSON != "" {
+			var output any
+			if json.Unmarshal([]byte(*t.OutputJSON), &output) == nil {
+				tr.Output = output
+			}
+		}
+		run.Tasks = append(run.Tasks, tr)
+	}
+	return run, nil
+}
+
+// NewMissionServer creates an MCP server exposing each mission in the
+// config as a tool named after it. A call runs the mission to completion
+// and returns its task results; task progress is streamed back as MCP
+// progress notifications when the client asks for them.
+func NewMissionServer(deps MissionDeps) *server.MCPServer {
+	srv := server.NewMCPServer(
+		"squadron",
+		deps.Version,
+		server.WithToolCapabilities(false),
+	)
+	for i := range deps.Config.Missions {
+		m := &deps.Config.Missions[i]
+		srv.AddTool(missionTool(m), missionToolHandler(deps, m))
+	}
+	return srv
+}
+
+// missionTool describes a mission as an MCP tool: the directive becomes
+// the description and the mission inputs the input schema.
+func missionTool(m *config.Mission) mcp.Tool {
+	description := m.Directive
+	if description == "" {
+		description = fmt.Sprintf("Run the squadron mission '%s'.", m.Name)
+	}
+	schema, _ := json.Marshal(missionInputSchema(m.Inputs))
+	return mcp.NewToolWithRawSchema(m.Name, description, schema)
+}
+
+// missionInputSchema builds the JSON schema for a mission's inputs.
+// Protected inputs are left out since their value comes from config, and
+// inputs without a default are required.
+func missionInputSchema(inputs []config.MissionInput) map[string]any {
+	properties := make(map[string]any)
+	required := []string{}
+	for _, in := range inputs {
+		if in.Protected {
+			continue
+		}
+		prop := inputPropertySchema(in)
+		if in.Default != nil {
+			prop["default"] = config.CtyValueToGo(*in.Default)
+		} else {
+			required = append(required, in.Name)
+		}
+		properties[in.Name] = prop
+	}
+	return map[string]any{
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}
+}
+
+// inputPropertySchema maps an input's type (and its items or properties)
+// to a JSON schema property.
+func inputPropertySchema(in config.MissionInput) map[string]any {
+	prop := make(map[string]any)
+	if in.Description != "" {
+		prop["description"] = in.Description
+	}
+	switch in.Type {
+	case config.InputTypeNumber:
+		prop["type"] = "number"
+	case config.InputTypeInteger:
+		prop["type"] = "integer"
+	case config.InputTypeBool:
+		prop["type"] = "boolean"
+	case config.InputTypeList:
+		prop["type"] = "array"
+		if in.Items != nil {
+			prop["items"] = inputPropertySchema(*in.Items)
+		}
+	case config.InputTypeObject, config.InputTypeMap:
+		prop["type"] = "object"
+		if in.Items != nil {
+			prop["additionalProperties"] = inputPropertySchema(*in.Items)
+		}
+		if len(in.Properties) > 0 {
+			nested := make(map[string]any)
+			for _, p := range in.Properties {
+				nested[p.Name] = inputPropertySchema(p)
+			}
+			prop["properties"] = nested
+		}
+	default:
+		prop["type"] = "string"
+	}
+	return prop
+}
+
+// missionInputValues converts tool arguments to the string form the runner
+// takes: strings as-is, everything else JSON-encoded.
+func missionInputValues(args map[string]any) (map[string]string, error) {
+	inputs := make(map[string]string, len(args))
+	for k, v := range args {
+		if s, ok := v.(string); ok {
+			inputs[k] = s
+			continue
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("input '%s': %w", k, err)
+		}
+		inputs[k] = string(b)
+	}
+	return inputs, nil
+}
+
+func missionToolHandler(deps MissionDeps, m *config.Mission) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		inputs, err := missionInputValues(req.GetArguments())
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		var token mcp.ProgressToken
+		if req.Params.Meta != nil {
+			token = req.Params.Meta.ProgressToken
+		}
+		eventLog := deps.EventLog
+		if eventLog == nil {
+			eventLog = io.Discard
+		}
+		handler := newProgressHandler(ctx, ndjson.NewMissionHandler(eventLog), server.ServerFromContext(ctx), token)
+
+		run, err := deps.RunMission(ctx, m.Name, inputs, handler)
+		if run == nil {
+			if err == nil {
+				err = fmt.Errorf("mission produced no result")
+			}
+			return mcp.NewToolResultError(fmt.Sprintf("failed to run mission: %v", err)), nil
+		}
+		result, jerr := toolResult(run)
+		if jerr != nil {
+			return nil, jerr
+		}
+		result.IsError = err != nil
+		return result, nil
+	}
+}
+
+// progressHandler wraps the event log handler and also reports mission and
+// task lifecycle events to the MCP client as progress notifications.
+// Progress counts finished tasks out of the mission's task count.
+type progressHandler struct {
+	streamers.MissionHandler
+	ctx   context.Context
+	srv   *server.MCPServer
+	token mcp.ProgressToken
+
+	mu       sync.Mutex
+	total    int
+	finished int
+}
+
+func newProgressHandler(ctx context.Context, inner streamers.MissionHandler, srv *server.MCPServer, token mcp.ProgressToken) *progressHandler {
+	return &progressHandler{
+		MissionHandler: inner,
+		ctx:            ctx,
+		srv:            srv,
+		token:          token,
+	}
+}
+
+// notify sends a progress notification, advancing progress if done is set.
+// Without a progress token from the client there is nowhere to send it.
+func (h *progressHandler) notify(done bool, message string) {
+	if h.token == nil || h.srv == nil {
+		return
+	}
+	h.mu.Lock()
+	if done {
+		h.finished++
+	}
+	params := map[string]any{
+		"progressToken": h.token,
+		"progress":      h.finished,
+		"message":       message,
+	}
+	if h.total > 0 {
+		params["total"] = h.total
+	}
+	h.mu.Unlock()
+	_ = h.srv.SendNotificationToClient(h.ctx, "notifications/progress", params)
+}
+
+func (h *progressHandler) MissionStarted(name string, missionID string, taskCount int) {
+	h.mu.Lock()
+	h.total = taskCount
+	h.mu.Unlock()
+	h.MissionHandler.MissionStarted(name, missionID, taskCount)
+	h.notify(false, fmt.Sprintf("Mission %s started (%s)", name, missionID))
+}
+
+func (h *progressHandler) MissionCompleted(name string) {
+	h.MissionHandler.MissionCompleted(name)
+	h.notify(false, fmt.Sprintf("Mission %s completed", name))
+}
+
+// MissionFailed implements streamers.MissionFailureHandler.
+func (h *progressHandler) MissionFailed(name string, err error) {
+	if fh, ok := h.MissionHandler.(streamers.MissionFailureHandler); ok {
+		fh.MissionFailed(name, err)
+	}
+	h.notify(false, fmt.Sprintf("Mission %s failed: %v", name, err))
+}
+
+func (h *progressHandler) TaskStarted(taskName string, objective string) {
+	h.MissionHandler.TaskStarted(taskName, objective)
+	h.notify(false, fmt.Sprintf("Task %s started", taskName))
+}
+
+func (h *progressHandler) TaskCompleted(taskName string) {
+	h.MissionHandler.TaskCompleted(taskName)
+	h.notify(true, fmt.Sprintf("Task %s completed", taskName))
+}
+
+func (h *progressHandler) TaskFailed(taskName string, err error) {
+	h.MissionHandler.TaskFailed(taskName, err)
+	h.notify(true, fmt.Sprintf("Task %s failed: %v", taskName, err))
+}
+
+// TaskCancelled implements streamers.CancelHandler.
+func (h *progressHandler) TaskCancelled(data streamers.TaskCancelledData) {
+	if ch, ok := h.MissionHandler.(streamers.CancelHandler); ok {
+		ch.TaskCancelled(data)
+	}
+	h.notify(true, fmt.Sprintf("Task %s cancelled", data.TaskName))
+}
+
+// MissionCancelled implements streamers.CancelHandler.
+func (h *progressHandler) MissionCancelled(data streamers.MissionCancelledData) {
+	if ch, ok := h.MissionHandler.(streamers.CancelHandler); ok {
+		ch.MissionCancelled(data)
+	}
+	h.notify(false, fmt.Sprintf("Mission %s cancelled: %s", data.MissionName, data.Reason))
+}
+
+// TaskSkipped implements streamers.TaskSkipHandler.
+func (h *progressHandler) TaskSkipped(data streamers.TaskSkippedData) {
+	if sh, ok := h.MissionHandler.(streamers.TaskSkipHandler); ok {
+		sh.TaskSkipped(data)
+	}
+	h.notify(true, fmt.Sprintf("Task %s skipped", data.TaskName))
+}
+
+// IterationProgress implements streamers.IterationProgressHandler.
+func (h *progressHandler) IterationProgress(data streamers.IterationProgressData) {
+	if ph, ok := h.MissionHandler.(streamers.IterationProgressHandler); ok {
+		ph.IterationProgress(data)
+	}
+	h.notify(false, fmt.Sprintf("Task %s: %d/%d iterations done", data.TaskName, data.Completed+data.Failed, data.Total))
+}
+
+// TaskApprovalRequested implements streamers.ApprovalHandler.
+func (h *progressHandler) TaskApprovalRequested(data streamers.TaskApprovalRequestedData) {
+	if ah, ok := h.MissionHandler.(streamers.ApprovalHandler); ok {
+		ah.TaskApprovalRequested(data)
+	}
+}
+
+// TaskApprovalResolved implements streamers.ApprovalHandler.
+func (h *progressHandler) TaskApprovalResolved(data streamers.TaskApprovalResolvedData) {
+	if ah, ok := h.MissionHandler.(streamers.ApprovalHandler); ok {
+		ah.TaskApprovalResolved(data)
+	}
+}
@@ -0,0 +1,123 @@
+package mcphost
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/zclconf/go-cty/cty"
+
+	"squadron/config"
+	"squadron/streamers"
+)
+
+func testMissionConfig() *config.Config {
+	limit := cty.NumberIntVal(10)
+	return &config.Config{Missions: []config.Mission{{
+		Name:      "research",
+		Directive: "Research a topic and write a brief",
+		Inputs: []config.MissionInput{
+			{Name: "topic", Type: config.InputTypeString, Description: "What to research"},
+			{Name: "limit", Type: config.InputTypeInteger, Default: &limit},
+			{Name: "sources", Type: config.InputTypeList, Items: &config.MissionInput{Type: config.InputTypeString}},
+			{Name: "api_key", Type: config.InputTypeString, Protected: true},
+		},
+	}}}
+}
+
+func TestMissionServerToolSchema(t *testing.T) {
+	srv := NewMissionServer(MissionDeps{Config: testMissionConfig()})
+
+	tool := srv.GetTool("research")
+	if tool == nil {
+		t.Fatalf("expected a tool named research, got %v", srv.ListTools())
+	}
+	if tool.Tool.Description != "Research a topic and write a brief" {
+		t.Errorf("description = %q", tool.Tool.Description)
+	}
+
+	var schema struct {
+		Properties map[string]map[string]any `json:"properties"`
+		Required   []string                  `json:"required"`
+	}
+	if err := json.Unmarshal(tool.Tool.RawInputSchema, &schema); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := schema.Properties["api_key"]; ok {
+		t.Error("protected input should not be in the schema")
+	}
+	if got := schema.Properties["topic"]["type"]; got != "string" {
+		t.Errorf("topic type = %v", got)
+	}
+	if got := schema.Properties["limit"]["type"]; got != "integer" {
+		t.Errorf("limit type = %v", got)
+	}
+	if got := schema.Properties["limit"]["default"]; got != float64(10) {
+		t.Errorf("limit default = %v", got)
+	}
+	if got := schema.Properties["sources"]["items"]; got.(map[string]any)["type"] != "string" {
+		t.Errorf("sources items = %v", got)
+	}
+	if len(schema.Required) != 2 || schema.Required[0] != "topic" || schema.Required[1] != "sources" {
+		t.Errorf("required = %v", schema.Required)
+	}
+}
+
+func TestMissionToolCall(t *testing.T) {
+	var gotInputs map[string]string
+	deps := MissionDeps{
+		Config: testMissionConfig(),
+		RunMission: func(ctx context.Context, name string, inputs map[string]string, handler streamers.MissionHandler) (*MissionRun, error) {
+			gotInputs = inputs
+			handler.MissionStarted(name, "m1", 1)
+			handler.TaskCompleted("gather")
+			return &MissionRun{MissionID: "m1", Mission: name, Status: "failed", Error: "boom"}, errors.New("boom")
+		},
+	}
+	srv := NewMissionServer(deps)
+
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "research"
+	req.Params.Arguments = map[string]any{"topic": "go", "sources": []any{"a", "b"}, "limit": 3}
+	result, err := srv.GetTool("research").Handler(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if gotInputs["topic"] != "go" || gotInputs["sources"] != `["a","b"]` || gotInputs["limit"] != "3" {
+		t.Errorf("inputs = %v", gotInputs)
+	}
+	if !result.IsError {
+		t.Error("a failed mission should be reported as a tool error")
+	}
+	var run MissionRun
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &run); err != nil {
+		t.Fatal(err)
+	}
+	if run.MissionID != "m1" || run.Status != "failed" || run.Error != "boom" {
+		t.Errorf("run = %+v", run)
+	}
+}
+
+func TestMissionToolCallNotStarted(t *testing.T) {
+	deps := MissionDeps{
+		Config: testMissionConfig(),
+		RunMission: func(ctx context.Context, name string, inputs map[string]string, handler streamers.MissionHandler) (*MissionRun, error) {
+			return nil, errors.New("required input 'topic' not provided")
+		},
+	}
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "research"
+	result, err := NewMissionServer(deps).GetTool("research").Handler(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.IsError {
+		t.Fatal("expected a tool error")
+	}
+	if text := result.Content[0].(mcp.TextContent).Text; text != "failed to run mission: required input 'topic' not provided" {
+		t.Errorf("text = %q", text)
+	}
+}
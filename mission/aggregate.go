@@ -0,0 +1,223 @@
+package mission
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mlund01/squadron-wire/protocol"
+	"github.com/zclconf/go-cty/cty"
+
+	"squadron/agent"
+	"squadron/config"
+	"squadron/llm"
+	"squadron/streamers"
+)
+
+// aggregateSystemPrompt instructs the model computing an LLM aggregate.
+const aggregateSystemPrompt = `You aggregate the results of a task that ran once per item of a dataset.
+You are given the task objective, aggregation instructions, and the structured output of every iteration.
+Respond with a single JSON object that follows the instructions, and nothing else.`
+
+// aggregateOutputJSON computes an iterated task's aggregate and returns it
+// JSON-encoded for the task record, or nil when the task has no aggregate
+// block.
+func (r *Runner) aggregateOutputJSON(ctx context.Context, task config.Task, taskID string, streamer streamers.MissionHandler) (*string, error) {
+	if task.Aggregate == nil {
+		return nil, nil
+	}
+	result, err := r.aggregateIterations(ctx, task, taskID, streamer)
+	if err != nil {
+		return nil, fmt.Errorf("aggregate: %w", err)
+	}
+	if result == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("aggregate: %w", err)
+	}
+	outputJSON := string(b)
+	return &outputJSON, nil
+}
+
+// aggregateIterations folds the iteration outputs of a finished iterated task
+// into the single object its aggregate block describes. It returns nil when
+// there is nothing to aggregate (an LLM aggregate over zero iterations).
+func (r *Runner) aggregateIterations(ctx context.Context, task config.Task, taskID string, streamer streamers.MissionHandler) (map[string]any, error) {
+	rows, err := r.stores.Missions.GetTaskOutputs(taskID)
+	if err != nil {
+		return nil, fmt.Errorf("loading iteration outputs: %w", err)
+	}
+	outputs := make([]map[string]any, 0, len(rows))
+	for _, row := range rows {
+		var output map[string]any
+		if row.OutputJSON != "" {
+			json.Unmarshal([]byte(row.OutputJSON), &output)
+		}
+		outputs = append(outputs, output)
+	}
+
+	if !task.Aggregate.IsLLM() {
+		return task.Aggregate.EvaluateReduce(iterationsToCty(outputs), r.varsValues, r.inputValues)
+	}
+	if len(outputs) == 0 {
+		return nil, nil
+	}
+	return r.aggregateWithLLM(ctx, task, outputs, streamer)
+}
+
+// iterationsToCty converts iteration outputs to the list a reducer reads.
+// Every element gets every field seen across iterations (null where unset),
+// so splats like iterations[*].score work when some iterations skipped a
+// field.
+func iterationsToCty(outputs []map[string]any) cty.Value {
+	fields := make(map[string]bool)
+	for _, o := range outputs {
+		for k := range o {
+			fields[k] = true
+		}
+	}
+	items := make([]any, len(outputs))
+	for i, o := range outputs {
+		item := make(map[string]any, len(fields))
+		for k := range fields {
+			item[k] = o[k]
+		}
+		items[i] = item
+	}
+	return config.GoToCtyValue(items)
+}
+
+// aggregateWithLLM asks the aggregate model (the task's commander model
+// unless the block names one) to combine the iteration outputs into a JSON object.
+func (r *Runner) aggregateWithLLM(ctx context.Context, task config.Task, outputs []map[string]any, streamer streamers.MissionHandler) (map[string]any, error) {
+	modelKey := task.Aggregate.Model
+	if modelKey == "" {
+		modelKey = task.CommanderModel(r.mission.Commander.Model)
+	}
+	modelCfg, apiName, err := resolveModelKey(r.cfg.Models, modelKey)
+	if err != nil {
+		return nil, err
+	}
+	provider := r.testProvider()
+	if provider == nil {
+		var closeProvider func()
+		provider, closeProvider, err = agent.NewProvider(ctx, modelCfg)
+		if err != nil {
+			return nil, err
+		}
+		defer closeProvider()
+	}
+
+	outputsJSON, _ := json.MarshalIndent(outputs, "", "  ")
+	// Objectives that reference item can't be resolved outside an iteration
+	objective, err := task.ResolvedObjective(r.varsValues, r.inputValues)
+	if err != nil {
+		objective = task.RawObjective
+	}
+	var user strings.Builder
+	fmt.Fprintf(&user, "## Task objective\n\n%s\n\n", objective)
+	fmt.Fprintf(&user, "## Aggregation instructions\n\n%s\n\n", task.Aggregate.Prompt)
+	fmt.Fprintf(&user, "## Iteration outputs (%d)\n\n```json\n%s\n```\n", len(outputs), outputsJSON)
+
+	// The aggregate call counts against the task's budget like a commander turn
+	budget := r.budgetTracker.For(task.Name)
+	if budget != nil {
+		if err := budget.CheckBudget(); err != nil {
+			return nil, err
+		}
+	}
+	release, err := r.callLimiter.Acquire(ctx)
+	if err != nil {
+		if budget != nil {
+			budget.ReleaseCall()
+		}
+		return nil, err
+	}
+	start := time.Now()
+	resp, err := provider.Chat(ctx, &llm.ChatRequest{
+		Model: apiName,
+		Messages: []llm.Message{
+			llm.NewTextMessage(llm.RoleSystem, aggregateSystemPrompt),
+			llm.NewTextMessage(llm.RoleUser, user.String()),
+		},
+		Sampling: agent.SamplingFor(modelCfg, modelKey, task.Sampling),
+	})
+	release()
+	if err != nil {
+		if budget != nil {
+			budget.ReleaseCall()
+		}
+		return nil, err
+	}
+
+	turn := protocol.SessionTurnData{
+		TaskName:         task.Name,
+		Entity:           "aggregate",
+		Model:            apiName,
+		InputTokens:      resp.Usage.InputTokens,
+		OutputTokens:     resp.Usage.OutputTokens,
+		CacheWriteTokens: resp.Usage.CacheWriteTokens,
+		CacheReadTokens:  resp.Usage.CacheReadTokens,
+		UserMessages:     1,
+		SystemMessages:   1,
+		TurnDurationMs:   time.Since(start).Milliseconds(),
+	}
+	if pricing := llm.GetPricing(apiName, r.pricingOverrides); pricing != nil {
+		cost := llm.ComputeTurnCost(pricing, resp.Usage.InputTokens, resp.Usage.OutputTokens, resp.Usage.CacheReadTokens, resp.Usage.CacheWriteTokens)
+		turn.Cost = cost.TotalCost
+		turn.InputCost = cost.InputCost
+		turn.OutputCost = cost.OutputCost
+		turn.CacheReadCost = cost.CacheReadCost
+		turn.CacheWriteCost = cost.CacheWriteCost
+	}
+	streamer.SessionTurn(turn)
+	if budget != nil {
+		if err := budget.RecordUsage(resp.Usage.Total(), turn.Cost); err != nil {
+			return nil, err
+		}
+	}
+
+	var result map[string]any
+	if err := json.Unmarshal([]byte(stripCodeFence(resp.Content)), &result); err != nil || result == nil {
+		return nil, fmt.Errorf("model did not return a JSON object: %s", truncateForError(resp.Content))
+	}
+	return result, nil
+}
+
+// resolveModelKey finds the model config providing a model key, returning it
+// along with the API name.
+func resolveModelKey(models []config.Model, key string) (*config.Model, string, error) {
+	for i := range models {
+		if apiName, ok := models[i].AvailableModels()[key]; ok {
+			return &models[i], apiName, nil
+		}
+	}
+	return nil, "", fmt.Errorf("no model config found for model '%s'", key)
+}
+
+// stripCodeFence removes a surrounding ``` or ```json fence, which models
+// often add despite being asked for bare JSON.
+func stripCodeFence(s string) string {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "```") {
+		return s
+	}
+	s = strings.TrimPrefix(s, "```")
+	if nl := strings.IndexByte(s, '\n'); nl >= 0 {
+		s = s[nl+1:]
+	}
+	return strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(s), "```"))
+}
+
+// truncateForError shortens model output quoted in an error message.
+func truncateForError(s string) string {
+	const max = 200
+	if len(s) > max {
+		return s[:max] + "..."
+	}
+	return s
+}
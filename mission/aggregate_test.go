@@ -0,0 +1,135 @@
+package mission
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/zclconf/go-cty/cty"
+
+	"squadron/config"
+	"squadron/llm"
+)
+
+var _ = Describe("Iterated task aggregate", func() {
+	scoredItems := func(aggregate *config.TaskAggregate) config.Mission {
+		task := testTask("score", "Score each item")
+		task.Iterator = &config.TaskIterator{Dataset: "items"}
+		task.Output = &config.OutputSchema{Fields: []config.OutputField{
+			{Name: "name", Type: "string", Required: true},
+			{Name: "score", Type: "number"},
+		}}
+		task.Aggregate = aggregate
+		mission := testMission("scores", []config.Task{task})
+		mission.Datasets = []config.Dataset{{Name: "items", Items: []cty.Value{
+			cty.ObjectVal(map[string]cty.Value{"name": cty.StringVal("a")}),
+			cty.ObjectVal(map[string]cty.Value{"name": cty.StringVal("b")}),
+		}}}
+		return mission
+	}
+	iterationResponses := func() []mockResponse {
+		return []mockResponse{
+			cmdDatasetNext(),
+			cmdSubmitOutput(map[string]any{"name": "a", "score": 3}),
+			cmdDatasetNext(),
+			cmdSubmitOutput(map[string]any{"name": "b", "score": 5}),
+			cmdDatasetNext(),
+			cmdTaskComplete(),
+		}
+	}
+
+	run := func(mission config.Mission, provider *mockProvider) (*Runner, error) {
+		cfg := buildTestConfig(mission, testAgent("worker"))
+		runner, err := NewRunner(cfg, "", mission.Name, nil, WithProviderFactory(func() llm.Provider { return provider }))
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(runner.CloseStores)
+		return runner, runner.Run(context.Background(), newMockMissionStreamer())
+	}
+
+	taskOutput := func(runner *Runner) *TaskOutput {
+		ks := &PersistentKnowledgeStore{MissionID: runner.missionID, Store: runner.stores.Missions}
+		to, ok := ks.GetTaskOutput("score")
+		Expect(ok).To(BeTrue())
+		return to
+	}
+
+	It("stores the reducer result as the task output", func() {
+		reduce, diags := hclsyntax.ParseExpression([]byte(`{
+  total = sum(iterations[*].score)
+  names = collect(iterations[*].name)
+}`), "test.hcl", hcl.InitialPos)
+		Expect(diags.HasErrors()).To(BeFalse())
+
+		provider := newMockProvider(iterationResponses()...)
+		runner, err := run(scoredItems(&config.TaskAggregate{ReduceExpr: reduce}), provider)
+		Expect(err).NotTo(HaveOccurred())
+
+		to := taskOutput(runner)
+		Expect(to.IsIterated).To(BeTrue())
+		Expect(to.Iterations).To(HaveLen(2))
+		Expect(to.Output).To(Equal(map[string]any{"total": float64(8), "names": []any{"a", "b"}}))
+
+		// Guards downstream read the aggregate, not the iteration list
+		Expect(taskOutputToCty(to).GetAttr("total").AsBigFloat().String()).To(Equal("8"))
+	})
+
+	It("asks the commander model to aggregate when the block has a prompt", func() {
+		isAggregate := func(req *llm.ChatRequest) bool {
+			return strings.Contains(req.Messages[0].Content, "You aggregate the results")
+		}
+		provider := newMockProvider(iterationResponses()...)
+		provider.addResponses(withMatch(mockResponse{Content: "```json\n{\"winner\": \"b\"}\n```"}, isAggregate))
+
+		runner, err := run(scoredItems(&config.TaskAggregate{Prompt: "Pick the item with the best score"}), provider)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(taskOutput(runner).Output).To(Equal(map[string]any{"winner": "b"}))
+
+		var aggregateCall *mockCall
+		for _, call := range provider.getCalls() {
+			if strings.Contains(call.Messages[0].Content, "You aggregate the results") {
+				aggregateCall = &call
+			}
+		}
+		Expect(aggregateCall).NotTo(BeNil())
+		Expect(aggregateCall.Model).To(Equal("claude-sonnet-4-20250514"))
+		prompt := aggregateCall.Messages[1].Content
+		Expect(prompt).To(ContainSubstring("Pick the item with the best score"))
+		outputs, _ := json.MarshalIndent([]map[string]any{{"name": "a", "score": 3}, {"name": "b", "score": 5}}, "", "  ")
+		Expect(prompt).To(ContainSubstring(string(outputs)))
+	})
+
+	It("charges the aggregate call to the task's budget", func() {
+		isAggregate := func(req *llm.ChatRequest) bool {
+			return strings.Contains(req.Messages[0].Content, "You aggregate the results")
+		}
+		provider := newMockProvider(iterationResponses()...)
+		provider.addResponses(withMatch(mockResponse{Content: `{"winner": "b"}`}, isAggregate))
+
+		mission := scoredItems(&config.TaskAggregate{Prompt: "Pick the best"})
+		limit := int64(100)
+		mission.Tasks[0].Budget = &config.Budget{LLMCalls: &limit}
+		runner, err := run(mission, provider)
+		Expect(err).NotTo(HaveOccurred())
+
+		var calls int64
+		for _, sp := range runner.budgetTracker.Breakdown() {
+			calls += sp.LLMCalls
+		}
+		Expect(calls).To(Equal(int64(len(provider.getCalls()))))
+	})
+
+	It("fails the task when the model does not return a JSON object", func() {
+		isAggregate := func(req *llm.ChatRequest) bool {
+			return strings.Contains(req.Messages[0].Content, "You aggregate the results")
+		}
+		provider := newMockProvider(iterationResponses()...)
+		provider.addResponses(withMatch(mockResponse{Content: "b wins"}, isAggregate))
+
+		_, err := run(scoredItems(&config.TaskAggregate{Prompt: "Pick the best"}), provider)
+		Expect(err).To(MatchError(ContainSubstring("aggregate: model did not return a JSON object: b wins")))
+	})
+})
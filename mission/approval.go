@@ -0,0 +1,155 @@
+package mission
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"squadron/aitools"
+	"squadron/failure"
+	"squadron/streamers"
+)
+
+// ApprovalRequest is the draft result of a require_approval task, held until
+// a reviewer decides on it.
+type ApprovalRequest struct {
+	MissionID string
+	TaskID    string
+	TaskName  string
+	Summary   string
+	Output    map[string]any
+}
+
+// ApprovalDecision is a reviewer's answer. An approved decision carries the
+// summary and output to commit, which may be edited versions of the draft.
+type ApprovalDecision struct {
+	Approved bool
+	Summary  string
+	Output   map[string]any
+	Reason   string // why the draft was rejected, when given
+}
+
+// Approver asks a human to review a draft task result. Implementations block
+// until a decision arrives or ctx is done.
+type Approver interface {
+	RequestApproval(ctx context.Context, req ApprovalRequest) (ApprovalDecision, error)
+}
+
+// ApprovalRejectedError is returned from a task whose draft was rejected.
+type ApprovalRejectedError struct {
+	TaskName string
+	Reason   string
+}
+
+func (e *ApprovalRejectedError) Error() string {
+	if e.Reason != "" {
+		return fmt.Sprintf("task '%s' output rejected: %s", e.TaskName, e.Reason)
+	}
+	return fmt.Sprintf("task '%s' output rejected", e.TaskName)
+}
+
+func (e *ApprovalRejectedError) FailureCode() failure.Code { return failure.CodeApprovalRejected }
+
+// Reviewer choices offered through the human-input bridge.
+const (
+	ApprovalChoiceApprove = "Approve"
+	ApprovalChoiceReject  = "Reject"
+)
+
+// BridgeApprover routes approvals through the human-input bridge, so they
+// show up in the command center inbox and connected gateways next to
+// builtins.human.ask questions.
+//
+// Replies are interpreted as: "Approve" keeps the draft; "Reject" (or
+// "Reject: <reason>") fails the task; any other free-text reply approves an
+// edit — a JSON object replaces the output, anything else replaces the
+// summary.
+type BridgeApprover struct {
+	Bridge aitools.HumanInputBridge
+}
+
+func (a *BridgeApprover) RequestApproval(ctx context.Context, req ApprovalRequest) (ApprovalDecision, error) {
+	resp, err := a.Bridge.AskHuman(ctx, aitools.HumanInputRequest{
+		ToolCallID:        uuid.NewString(),
+		MissionID:         req.MissionID,
+		TaskID:            req.TaskID,
+		Question:          fmt.Sprintf("Approve the result of task '%s'?", req.TaskName),
+		ShortSummary:      fmt.Sprintf("Approval needed: %s", req.TaskName),
+		AdditionalContext: formatApprovalDraft(req) + "\n\nReply with an edited summary, or an edited output as a JSON object, to approve your changes instead.",
+		Choices:           []string{ApprovalChoiceApprove, ApprovalChoiceReject},
+	})
+	if err != nil {
+		return ApprovalDecision{}, err
+	}
+	return ParseApprovalReply(req, resp), nil
+}
+
+// ParseApprovalReply turns a free-form reviewer reply into a decision using
+// the rules documented on BridgeApprover.
+func ParseApprovalReply(req ApprovalRequest, reply string) ApprovalDecision {
+	reply = strings.TrimSpace(reply)
+	switch {
+	case strings.EqualFold(reply, ApprovalChoiceApprove):
+		return ApprovalDecision{Approved: true, Summary: req.Summary, Output: req.Output}
+	case strings.EqualFold(reply, ApprovalChoiceReject):
+		return ApprovalDecision{}
+	case len(reply) > len(ApprovalChoiceReject) && strings.EqualFold(reply[:len(ApprovalChoiceReject)+1], ApprovalChoiceReject+":"):
+		return ApprovalDecision{Reason: strings.TrimSpace(reply[len(ApprovalChoiceReject)+1:])}
+	}
+
+	var edited map[string]any
+	if strings.HasPrefix(reply, "{") && json.Unmarshal([]byte(reply), &edited) == nil {
+		return ApprovalDecision{Approved: true, Summary: req.Summary, Output: edited}
+	}
+	return ApprovalDecision{Approved: true, Summary: reply, Output: req.Output}
+}
+
+// formatApprovalDraft renders the draft as markdown for reviewers.
+func formatApprovalDraft(req ApprovalRequest) string {
+	var b strings.Builder
+	b.WriteString("**Summary**\n\n")
+	if req.Summary != "" {
+		b.WriteString(req.Summary)
+	} else {
+		b.WriteString("_(none)_")
+	}
+	if len(req.Output) > 0 {
+		outputJSON, _ := json.MarshalIndent(req.Output, "", "  ")
+		b.WriteString("\n\n**Output**\n\n```json\n")
+		b.Write(outputJSON)
+		b.WriteString("\n```")
+	}
+	return b.String()
+}
+
+// awaitApproval surfaces the draft to the streamer, blocks on the approver,
+// and reports the decision.
+func (r *Runner) awaitApproval(ctx context.Context, req ApprovalRequest, streamer streamers.MissionHandler) (ApprovalDecision, error) {
+	ah, _ := streamer.(streamers.ApprovalHandler)
+	if ah != nil {
+		ah.TaskApprovalRequested(streamers.TaskApprovalRequestedData{
+			TaskName: req.TaskName,
+			Summary:  req.Summary,
+			Output:   req.Output,
+		})
+	}
+
+	decision, err := r.approver.RequestApproval(ctx, req)
+	if err != nil {
+		return ApprovalDecision{}, err
+	}
+
+	if ah != nil {
+		ah.TaskApprovalResolved(streamers.TaskApprovalResolvedData{
+			TaskName: req.TaskName,
+			Approved: decision.Approved,
+			Edited:   decision.Approved && (decision.Summary != req.Summary || !reflect.DeepEqual(decision.Output, req.Output)),
+			Reason:   decision.Reason,
+		})
+	}
+	return decision, nil
+}
@@ -0,0 +1,43 @@
+package mission
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ParseApprovalReply", func() {
+	draft := ApprovalRequest{
+		TaskName: "deploy",
+		Summary:  "Deployed to staging.",
+		Output:   map[string]any{"target": "staging"},
+	}
+
+	It("keeps the draft on Approve", func() {
+		d := ParseApprovalReply(draft, " approve ")
+		Expect(d.Approved).To(BeTrue())
+		Expect(d.Summary).To(Equal(draft.Summary))
+		Expect(d.Output).To(Equal(draft.Output))
+	})
+
+	It("rejects, with an optional reason", func() {
+		Expect(ParseApprovalReply(draft, "Reject").Approved).To(BeFalse())
+
+		d := ParseApprovalReply(draft, "reject: wrong environment")
+		Expect(d.Approved).To(BeFalse())
+		Expect(d.Reason).To(Equal("wrong environment"))
+	})
+
+	It("treats a JSON object as an edited output", func() {
+		d := ParseApprovalReply(draft, `{"target": "production"}`)
+		Expect(d.Approved).To(BeTrue())
+		Expect(d.Summary).To(Equal(draft.Summary))
+		Expect(d.Output).To(HaveKeyWithValue("target", "production"))
+	})
+
+	It("treats other text as an edited summary", func() {
+		d := ParseApprovalReply(draft, "Deployed to production instead.")
+		Expect(d.Approved).To(BeTrue())
+		Expect(d.Summary).To(Equal("Deployed to production instead."))
+		Expect(d.Output).To(Equal(draft.Output))
+	})
+})
@@ -0,0 +1,121 @@
+package mission
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"squadron/aitools"
+	"squadron/config"
+	"squadron/store"
+)
+
+// missionArtifacts is the mission's aitools.Artifacts, backed by the store
+// bundle's ArtifactStore. References are the record ID behind
+// aitools.ArtifactRefPrefix and only resolve within the mission that
+// registered them.
+type missionArtifacts struct {
+	store     store.ArtifactStore
+	missionID string
+}
+
+// buildArtifacts returns the artifact store for a mission run, or nil if no
+// task declares a `file` output field or the bundle has no artifact store.
+func buildArtifacts(m *config.Mission, stores *store.Bundle, missionID string) aitools.Artifacts {
+	if stores == nil || stores.Artifacts == nil || !hasFileOutputs(m) {
+		return nil
+	}
+	return &missionArtifacts{store: stores.Artifacts, missionID: missionID}
+}
+
+// hasFileOutputs reports whether any task declares a `file` output field.
+func hasFileOutputs(m *config.Mission) bool {
+	for _, task := range m.Tasks {
+		if task.Output == nil {
+			continue
+		}
+		for _, field := range task.Output.Fields {
+			if field.Type == "file" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (a *missionArtifacts) Put(ctx context.Context, art aitools.Artifact, r io.Reader) (aitools.Artifact, error) {
+	rec, err := a.store.PutArtifact(store.ArtifactRecord{
+		MissionID: a.missionID,
+		TaskName:  art.Task,
+		Author:    art.Author,
+		Name:      art.Name,
+		MediaType: art.MediaType,
+	}, r)
+	if err != nil {
+		return aitools.Artifact{}, err
+	}
+	return toArtifact(*rec), nil
+}
+
+func (a *missionArtifacts) Stat(ctx context.Context, ref string) (aitools.Artifact, error) {
+	rec, err := a.lookup(ref)
+	if err != nil {
+		return aitools.Artifact{}, err
+	}
+	return toArtifact(*rec), nil
+}
+
+func (a *missionArtifacts) Open(ctx context.Context, ref string) (io.ReadCloser, aitools.Artifact, error) {
+	rec, err := a.lookup(ref)
+	if err != nil {
+		return nil, aitools.Artifact{}, err
+	}
+	rc, err := a.store.OpenArtifact(rec.ID)
+	if err != nil {
+		return nil, aitools.Artifact{}, err
+	}
+	return rc, toArtifact(*rec), nil
+}
+
+func (a *missionArtifacts) List(ctx context.Context, task string) ([]aitools.Artifact, error) {
+	recs, err := a.store.ListArtifacts(a.missionID)
+	if err != nil {
+		return nil, err
+	}
+	var out []aitools.Artifact
+	for _, rec := range recs {
+		if task != "" && rec.TaskName != task {
+			continue
+		}
+		out = append(out, toArtifact(rec))
+	}
+	return out, nil
+}
+
+// lookup resolves a reference to a record of this mission.
+func (a *missionArtifacts) lookup(ref string) (*store.ArtifactRecord, error) {
+	id, ok := strings.CutPrefix(ref, aitools.ArtifactRefPrefix)
+	if !ok || id == "" {
+		return nil, fmt.Errorf("invalid artifact reference %q", ref)
+	}
+	rec, err := a.store.GetArtifact(id)
+	if err != nil {
+		return nil, err
+	}
+	if rec == nil || rec.MissionID != a.missionID {
+		return nil, fmt.Errorf("artifact %q not found", ref)
+	}
+	return rec, nil
+}
+
+func toArtifact(rec store.ArtifactRecord) aitools.Artifact {
+	return aitools.Artifact{
+		Ref:       aitools.ArtifactRefPrefix + rec.ID,
+		Name:      rec.Name,
+		MediaType: rec.MediaType,
+		Size:      rec.Size,
+		Task:      rec.TaskName,
+		Author:    rec.Author,
+	}
+}
@@ -0,0 +1,74 @@
+package mission
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"squadron/aitools"
+	"squadron/config"
+	"squadron/store"
+)
+
+var _ = Describe("Mission artifacts", func() {
+	ctx := context.Background()
+
+	var (
+		bundle *store.Bundle
+		m      config.Mission
+	)
+
+	BeforeEach(func() {
+		var err error
+		bundle, err = store.NewSQLiteBundle(filepath.Join(GinkgoT().TempDir(), "store.db"))
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(bundle.Close)
+
+		m = testMission("m", []config.Task{{
+			Name:   "render",
+			Output: &config.OutputSchema{Fields: []config.OutputField{{Name: "report", Type: "file", Required: true}}},
+		}})
+	})
+
+	It("is only built when a task has a file output", func() {
+		Expect(buildArtifacts(&m, bundle, "m1")).NotTo(BeNil())
+		Expect(buildArtifacts(&m, nil, "m1")).To(BeNil())
+
+		m.Tasks[0].Output.Fields[0].Type = "string"
+		Expect(buildArtifacts(&m, bundle, "m1")).To(BeNil())
+	})
+
+	It("resolves references only within the mission that registered them", func() {
+		first, err := bundle.Missions.CreateMission("m", "{}", "{}")
+		Expect(err).NotTo(HaveOccurred())
+		second, err := bundle.Missions.CreateMission("m", "{}", "{}")
+		Expect(err).NotTo(HaveOccurred())
+
+		artifacts := buildArtifacts(&m, bundle, first)
+		a, err := aitools.ScopeArtifacts(artifacts, "render", "commander").Put(ctx, aitools.Artifact{Name: "report.md", MediaType: "text/markdown"}, strings.NewReader("# Report"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(a.Ref).To(HavePrefix(aitools.ArtifactRefPrefix))
+		Expect(a.Task).To(Equal("render"))
+		Expect(a.Size).To(Equal(int64(8)))
+
+		rc, got, err := artifacts.Open(ctx, a.Ref)
+		Expect(err).NotTo(HaveOccurred())
+		content, _ := io.ReadAll(rc)
+		rc.Close()
+		Expect(string(content)).To(Equal("# Report"))
+		Expect(got).To(Equal(a))
+
+		list, err := artifacts.List(ctx, "other")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(list).To(BeEmpty())
+
+		_, err = buildArtifacts(&m, bundle, second).Stat(ctx, a.Ref)
+		Expect(err).To(MatchError(ContainSubstring("not found")))
+		_, err = artifacts.Stat(ctx, "report.md")
+		Expect(err).To(MatchError(ContainSubstring("invalid artifact reference")))
+	})
+})
@@ -0,0 +1,160 @@
+package mission
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"squadron/aitools"
+	"squadron/store"
+)
+
+// FailureCaptureAuthor is the author of artifacts collected from a browser
+// plugin when an iteration fails.
+const FailureCaptureAuthor = "failure_capture"
+
+// browserScreenshotTool is the tool a plugin must offer to count as a
+// browser; the other capture tools are used when present.
+const browserScreenshotTool = "browser_take_screenshot"
+
+// failureCaptureTimeout bounds the whole capture, so a hung browser doesn't
+// hold up the iteration's result.
+const failureCaptureTimeout = 30 * time.Second
+
+// browserCaller is the part of a plugin client failure capture uses.
+type browserCaller interface {
+	Call(ctx context.Context, toolName string, payload string) (string, error)
+}
+
+// browserCapture is one piece of browser state saved on failure.
+type browserCapture struct {
+	tool      string
+	payload   string
+	name      string
+	mediaType string
+	image     bool // the result holds a base64 image; name gets its extension
+}
+
+var browserCaptures = []browserCapture{
+	{tool: browserScreenshotTool, payload: `{}`, name: "screenshot", image: true},
+	{tool: "browser_console_messages", payload: `{}`, name: "console.log", mediaType: "text/plain"},
+	{tool: "browser_evaluate", payload: `{"function": "() => window.location.href"}`, name: "url.txt", mediaType: "text/plain"},
+	{tool: "browser_snapshot", payload: `{}`, name: "aria_snapshot.yml", mediaType: "application/yaml"},
+}
+
+// defaultBrowserPlugin returns the loaded plugin with this name if it offers
+// browser tools, or nil.
+func (r *Runner) defaultBrowserPlugin(name string) browserCaller {
+	client, ok := r.cfg.LoadedPlugins[name]
+	if !ok || client == nil {
+		return nil
+	}
+	if _, err := client.GetToolInfo(browserScreenshotTool); err != nil {
+		return nil
+	}
+	return client
+}
+
+// iterationBrowsers returns the browser plugins the given agents have tools
+// from, keyed by plugin name.
+func (r *Runner) iterationBrowsers(agentNames []string) map[string]browserCaller {
+	lookup := r.browserPlugin
+	if lookup == nil {
+		lookup = r.defaultBrowserPlugin
+	}
+	browsers := make(map[string]browserCaller)
+	for _, name := range agentNames {
+		a := r.mission.GetLocalAgent(name)
+		if a == nil {
+			for i := range r.cfg.Agents {
+				if r.cfg.Agents[i].Name == name {
+					a = &r.cfg.Agents[i]
+					break
+				}
+			}
+		}
+		if a == nil {
+			continue
+		}
+		for _, ref := range a.Tools {
+			parts := strings.Split(ref, ".")
+			if len(parts) != 3 || parts[0] != "plugins" {
+				continue
+			}
+			if _, seen := browsers[parts[1]]; seen {
+				continue
+			}
+			if b := lookup(parts[1]); b != nil {
+				browsers[parts[1]] = b
+			}
+		}
+	}
+	return browsers
+}
+
+// captureBrowserFailure saves the state of the browsers a failed iteration's
+// agents used — final screenshot, console log, URL and aria snapshot — as
+// artifacts of the iteration, so the failure can be debugged after the run.
+// Each piece is best effort: a plugin without the tool, or a call that
+// fails, is skipped. Parallel iterations share a plugin's browser, so the
+// state is whatever the browser shows when the iteration fails.
+func (r *Runner) captureBrowserFailure(ctx context.Context, taskName string, index int, agentNames []string) {
+	if ctx.Err() != nil || r.stores == nil || r.stores.Artifacts == nil {
+		return
+	}
+	browsers := r.iterationBrowsers(agentNames)
+	if len(browsers) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, failureCaptureTimeout)
+	defer cancel()
+	iterTaskName := fmt.Sprintf("%s[%d]", taskName, index)
+	for pluginName, browser := range browsers {
+		for _, c := range browserCaptures {
+			data, mediaType, err := c.collect(ctx, browser)
+			if err != nil || len(data) == 0 {
+				continue
+			}
+			name := c.name
+			if c.image {
+				name += "." + strings.TrimPrefix(mediaType, "image/")
+			}
+			if len(browsers) > 1 {
+				name = pluginName + "_" + name
+			}
+			if _, err := r.stores.Artifacts.PutArtifact(store.ArtifactRecord{
+				MissionID: r.missionID,
+				TaskName:  iterTaskName,
+				Author:    FailureCaptureAuthor,
+				Name:      name,
+				MediaType: mediaType,
+			}, bytes.NewReader(data)); err != nil {
+				log.Printf("[Mission] Failed to store %s of %s: %v", name, iterTaskName, err)
+			}
+		}
+	}
+}
+
+// collect calls the capture's tool and returns the content to store and its
+// media type.
+func (c browserCapture) collect(ctx context.Context, browser browserCaller) ([]byte, string, error) {
+	result, err := browser.Call(ctx, c.tool, c.payload)
+	if err != nil {
+		return nil, "", err
+	}
+	if !c.image {
+		return []byte(strings.TrimSpace(result)), c.mediaType, nil
+	}
+	extracted := aitools.ExtractImages(result)
+	if len(extracted.Images) == 0 {
+		return nil, "", fmt.Errorf("%s returned no image", c.tool)
+	}
+	img := extracted.Images[0]
+	data, err := base64.StdEncoding.DecodeString(img.Data)
+	return data, img.MediaType, err
+}
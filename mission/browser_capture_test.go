@@ -0,0 +1,110 @@
+package mission
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/zclconf/go-cty/cty"
+
+	"squadron/config"
+	"squadron/llm"
+)
+
+// fakeBrowser answers the browser capture tools of a Playwright-like plugin.
+type fakeBrowser struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+var fakeScreenshot = []byte("\x89PNG\r\n\x1a\nfake screenshot")
+
+func (b *fakeBrowser) Call(ctx context.Context, toolName, payload string) (string, error) {
+	b.mu.Lock()
+	b.calls = append(b.calls, toolName)
+	b.mu.Unlock()
+	switch toolName {
+	case "browser_take_screenshot":
+		return "data:image/png;base64," + base64.StdEncoding.EncodeToString(fakeScreenshot), nil
+	case "browser_console_messages":
+		return "[error] Uncaught TypeError: cart is undefined\n", nil
+	case "browser_evaluate":
+		return "https://shop.example.com/checkout", nil
+	case "browser_snapshot":
+		return "", fmt.Errorf("no page open")
+	}
+	return "", fmt.Errorf("unknown tool %s", toolName)
+}
+
+var _ = Describe("Browser failure capture", func() {
+	run := func(agentTools ...string) (*Runner, *fakeBrowser) {
+		task := testTask("checkout", "Check out")
+		task.ObjectiveExpr = templateExpr("Check out ${item.name}")
+		task.Iterator = &config.TaskIterator{Dataset: "items", Parallel: true, ContinueOnFailure: true}
+		mission := testMission("shop", []config.Task{task})
+		mission.Datasets = []config.Dataset{{Name: "items", Items: []cty.Value{
+			cty.ObjectVal(map[string]cty.Value{"name": cty.StringVal("alpha")}),
+			cty.ObjectVal(map[string]cty.Value{"name": cty.StringVal("beta")}),
+		}}}
+		worker := testAgent("worker")
+		worker.Tools = agentTools
+
+		provider := newMockProvider(
+			withMatch(cmdTaskComplete(), matchLastUserContains("Check out alpha")),
+			withMatch(cmdTaskCompleteFail("checkout button never appeared"), matchLastUserContains("Check out beta")),
+		)
+		runner, err := NewRunner(buildTestConfig(mission, worker), "", "shop", nil, WithProviderFactory(func() llm.Provider { return provider }))
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(runner.CloseStores)
+
+		browser := &fakeBrowser{}
+		runner.browserPlugin = func(name string) browserCaller {
+			if name == "playwright" {
+				return browser
+			}
+			return nil
+		}
+		Expect(runner.Run(context.Background(), newMockMissionStreamer())).To(Succeed())
+		return runner, browser
+	}
+
+	It("stores the browser state of a failed iteration as artifacts", func() {
+		runner, _ := run("plugins.playwright.browser_navigate")
+
+		recs, err := runner.stores.Artifacts.ListArtifacts(runner.MissionID())
+		Expect(err).NotTo(HaveOccurred())
+		names := map[string]string{}
+		for _, rec := range recs {
+			Expect(rec.TaskName).To(Equal("checkout[1]"))
+			Expect(rec.Author).To(Equal(FailureCaptureAuthor))
+			names[rec.Name] = rec.MediaType
+
+			if rec.Name == "screenshot.png" {
+				rc, err := runner.stores.Artifacts.OpenArtifact(rec.ID)
+				Expect(err).NotTo(HaveOccurred())
+				data, _ := io.ReadAll(rc)
+				rc.Close()
+				Expect(data).To(Equal(fakeScreenshot))
+			}
+		}
+		// The snapshot call failed, so it's skipped
+		Expect(names).To(Equal(map[string]string{
+			"screenshot.png": "image/png",
+			"console.log":    "text/plain",
+			"url.txt":        "text/plain",
+		}))
+	})
+
+	It("captures nothing when the agents use no browser plugin", func() {
+		runner, browser := run("plugins.pinger.echo")
+
+		recs, err := runner.stores.Artifacts.ListArtifacts(runner.MissionID())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recs).To(BeEmpty())
+		Expect(browser.calls).To(BeEmpty())
+	})
+})
@@ -3,10 +3,14 @@ package mission
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 
+	"squadron/agent"
 	"squadron/config"
+	"squadron/failure"
 )
 
 // BudgetScope identifies whether a breach is against a task's or the mission's budget.
@@ -21,10 +25,25 @@ const (
 type BudgetKind string
 
 const (
-	BudgetKindTokens  BudgetKind = "tokens"
-	BudgetKindDollars BudgetKind = "dollars"
+	BudgetKindTokens   BudgetKind = "tokens"
+	BudgetKindDollars  BudgetKind = "dollars"
+	BudgetKindLLMCalls BudgetKind = "llm_calls"
 )
 
+// commanderEntity is the entity name spend is attributed to for a task's
+// commander. Agents are attributed under their own names.
+const commanderEntity = "commander"
+
+// BudgetSpend is the cumulative usage attributed to one entity (the
+// commander or a named agent) within one task.
+type BudgetSpend struct {
+	TaskName string  `json:"task"`
+	Entity   string  `json:"entity"`
+	Tokens   int64   `json:"tokens"`
+	Dollars  float64 `json:"dollars"`
+	LLMCalls int64   `json:"llmCalls"`
+}
+
 // BudgetBreach describes which budget limit was exceeded.
 type BudgetBreach struct {
 	Scope    BudgetScope
@@ -32,21 +51,29 @@ type BudgetBreach struct {
 	Kind     BudgetKind
 	Limit    float64 // configured limit
 	Used     float64 // cumulative usage at the moment of breach
+	// Breakdown is a snapshot of spend per task/entity taken when the breach
+	// latched, sorted by task then entity.
+	Breakdown []BudgetSpend
 }
 
 func (b *BudgetBreach) Error() string {
-	if b.Scope == BudgetScopeMission {
-		if b.Kind == BudgetKindTokens {
-			return fmt.Sprintf("mission budget exceeded: %.0f tokens used, limit %.0f", b.Used, b.Limit)
-		}
-		return fmt.Sprintf("mission budget exceeded: $%.4f used, limit $%.4f", b.Used, b.Limit)
+	var usage string
+	switch b.Kind {
+	case BudgetKindTokens:
+		usage = fmt.Sprintf("%.0f tokens used, limit %.0f", b.Used, b.Limit)
+	case BudgetKindLLMCalls:
+		usage = fmt.Sprintf("%.0f LLM calls made, limit %.0f", b.Used, b.Limit)
+	default:
+		usage = fmt.Sprintf("$%.4f used, limit $%.4f", b.Used, b.Limit)
 	}
-	if b.Kind == BudgetKindTokens {
-		return fmt.Sprintf("task '%s' budget exceeded: %.0f tokens used, limit %.0f", b.TaskName, b.Used, b.Limit)
+	if b.Scope == BudgetScopeMission {
+		return "mission budget exceeded: " + usage
 	}
-	return fmt.Sprintf("task '%s' budget exceeded: $%.4f used, limit $%.4f", b.TaskName, b.Used, b.Limit)
+	return fmt.Sprintf("task '%s' budget exceeded: %s", b.TaskName, usage)
 }
 
+func (b *BudgetBreach) FailureCode() failure.Code { return failure.CodeBudgetExceeded }
+
 // BudgetTracker tracks cumulative token/cost usage against mission- and task-scoped
 // budgets. Safe for concurrent use. Once any budget is breached the tracker latches
 // into the breached state and every subsequent Check/Record returns the same breach.
@@ -57,8 +84,16 @@ type BudgetTracker struct {
 
 	missionTokens int64
 	missionCost   float64
+	missionCalls  int64
 	taskTokens    map[string]int64
 	taskCost      map[string]float64
+	taskCalls     map[string]int64
+	spend         map[spendKey]*BudgetSpend
+
+	// Calls reserved by Check and not yet recorded or released. They count
+	// toward llm_calls limits so concurrent callers can't all pass Check.
+	missionReserved int64
+	taskReserved    map[string]int64
 
 	breach   *BudgetBreach
 	cancel   context.CancelFunc
@@ -86,9 +121,17 @@ func NewBudgetTracker(mission *config.Mission) *BudgetTracker {
 		taskBudgets:   taskBudgets,
 		taskTokens:    make(map[string]int64),
 		taskCost:      make(map[string]float64),
+		taskCalls:     make(map[string]int64),
+		spend:         make(map[spendKey]*BudgetSpend),
+		taskReserved:  make(map[string]int64),
 	}
 }
 
+type spendKey struct {
+	task   string
+	entity string
+}
+
 // SetCancel registers a cancel function that the tracker will invoke on first breach
 // so in-flight LLM and tool calls across the mission unwind promptly.
 func (bt *BudgetTracker) SetCancel(cancel context.CancelFunc) {
@@ -116,30 +159,72 @@ func (bt *BudgetTracker) SetOnBreach(fn func(*BudgetBreach)) {
 // baseTaskName strips the `[N]` iteration suffix so every iteration of an iterated
 // task shares the same per-task budget.
 func baseTaskName(taskName string) string {
-	if idx := strings.LastIndex(taskName, "["); idx != -1 {
-		return taskName[:idx]
+	idx := strings.LastIndex(taskName, "[")
+	if idx == -1 || !strings.HasSuffix(taskName, "]") {
+		return taskName
 	}
-	return taskName
+	// for_each instances (task["key"]) are tasks in their own right
+	if _, err := strconv.Atoi(taskName[idx+1 : len(taskName)-1]); err != nil {
+		return taskName
+	}
+	return taskName[:idx]
 }
 
-// Check returns the latched breach error without modifying usage counters.
-// Commanders and agents call this immediately before issuing an LLM request.
+// Check returns the latched breach, or reserves one LLM call for the task.
+// Commanders and agents call this immediately before issuing an LLM request,
+// so it is also where call-count limits trip: a task that has already made
+// (or reserved) its allotted number of calls is refused the next one. The
+// reservation is turned into a call by Record, or handed back by Release
+// when the request fails without a response.
 func (bt *BudgetTracker) Check(taskName string) error {
 	if bt == nil {
 		return nil
 	}
 	bt.mu.Lock()
-	defer bt.mu.Unlock()
 	if bt.breach != nil {
-		return bt.breach
+		b := bt.breach
+		bt.mu.Unlock()
+		return b
+	}
+	base := baseTaskName(taskName)
+	breach := bt.detectCallBreachLocked(base)
+	if breach == nil {
+		bt.missionReserved++
+		bt.taskReserved[base]++
+		bt.mu.Unlock()
+		return nil
+	}
+	return bt.latchAndUnlock(breach)
+}
+
+// Release hands back a call reserved by Check that was never made.
+func (bt *BudgetTracker) Release(taskName string) {
+	if bt == nil {
+		return
+	}
+	bt.mu.Lock()
+	bt.unreserveLocked(baseTaskName(taskName))
+	bt.mu.Unlock()
+}
+
+func (bt *BudgetTracker) unreserveLocked(base string) {
+	if bt.taskReserved[base] > 0 {
+		bt.taskReserved[base]--
+		bt.missionReserved--
 	}
-	return nil
 }
 
 // Record adds the given usage to the task and mission counters and returns a breach
 // error if any limit has been reached. The first breach wins — subsequent calls
 // return that same breach. Pass the raw task name (iteration suffixes are stripped).
+// Each Record counts as one LLM call.
 func (bt *BudgetTracker) Record(taskName string, tokens int, cost float64) error {
+	return bt.RecordFor(taskName, commanderEntity, tokens, cost)
+}
+
+// RecordFor is Record with the usage attributed to a specific entity (the
+// commander or an agent name) for the spend breakdown.
+func (bt *BudgetTracker) RecordFor(taskName, entity string, tokens int, cost float64) error {
 	if bt == nil {
 		return nil
 	}
@@ -151,16 +236,36 @@ func (bt *BudgetTracker) Record(taskName string, tokens int, cost float64) error
 	}
 
 	base := baseTaskName(taskName)
+	bt.unreserveLocked(base)
 	bt.missionTokens += int64(tokens)
 	bt.missionCost += cost
+	bt.missionCalls++
 	bt.taskTokens[base] += int64(tokens)
 	bt.taskCost[base] += cost
+	bt.taskCalls[base]++
+
+	key := spendKey{task: base, entity: entity}
+	sp, ok := bt.spend[key]
+	if !ok {
+		sp = &BudgetSpend{TaskName: base, Entity: entity}
+		bt.spend[key] = sp
+	}
+	sp.Tokens += int64(tokens)
+	sp.Dollars += cost
+	sp.LLMCalls++
 
 	breach := bt.detectBreachLocked(base)
 	if breach == nil {
 		bt.mu.Unlock()
 		return nil
 	}
+	return bt.latchAndUnlock(breach)
+}
+
+// latchAndUnlock records breach as the tracker's terminal state, releases
+// bt.mu, and fires the breach side effects. Must be called with bt.mu held.
+func (bt *BudgetTracker) latchAndUnlock(breach *BudgetBreach) error {
+	breach.Breakdown = bt.breakdownLocked()
 	bt.breach = breach
 	cb, cancel := bt.onBreach, bt.cancel
 	bt.mu.Unlock()
@@ -208,6 +313,51 @@ func (bt *BudgetTracker) detectBreachLocked(base string) *BudgetBreach {
 	return nil
 }
 
+// detectCallBreachLocked reports whether issuing one more LLM call for the
+// task would exceed a task or mission llm_calls limit. Reserved calls count
+// as made.
+func (bt *BudgetTracker) detectCallBreachLocked(base string) *BudgetBreach {
+	taskCalls := bt.taskCalls[base] + bt.taskReserved[base]
+	if tb, ok := bt.taskBudgets[base]; ok && tb.LLMCalls != nil && taskCalls >= *tb.LLMCalls {
+		return &BudgetBreach{
+			Scope: BudgetScopeTask, TaskName: base, Kind: BudgetKindLLMCalls,
+			Limit: float64(*tb.LLMCalls), Used: float64(taskCalls),
+		}
+	}
+	missionCalls := bt.missionCalls + bt.missionReserved
+	if mb := bt.missionBudget; mb != nil && mb.LLMCalls != nil && missionCalls >= *mb.LLMCalls {
+		return &BudgetBreach{
+			Scope: BudgetScopeMission, Kind: BudgetKindLLMCalls,
+			Limit: float64(*mb.LLMCalls), Used: float64(missionCalls),
+		}
+	}
+	return nil
+}
+
+func (bt *BudgetTracker) breakdownLocked() []BudgetSpend {
+	out := make([]BudgetSpend, 0, len(bt.spend))
+	for _, sp := range bt.spend {
+		out = append(out, *sp)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].TaskName != out[j].TaskName {
+			return out[i].TaskName < out[j].TaskName
+		}
+		return out[i].Entity < out[j].Entity
+	})
+	return out
+}
+
+// Breakdown returns the current spend per task/entity, sorted by task then entity.
+func (bt *BudgetTracker) Breakdown() []BudgetSpend {
+	if bt == nil {
+		return nil
+	}
+	bt.mu.Lock()
+	defer bt.mu.Unlock()
+	return bt.breakdownLocked()
+}
+
 // Breach returns the latched breach, or nil if no budget has been exceeded.
 func (bt *BudgetTracker) Breach() *BudgetBreach {
 	if bt == nil {
@@ -223,20 +373,25 @@ func (bt *BudgetTracker) Breach() *BudgetBreach {
 type BudgetChecker interface {
 	CheckBudget() error
 	RecordUsage(tokens int, cost float64) error
+	ReleaseCall()
+	ForAgent(agentName string) agent.BudgetChecker
 }
 
-// For returns a per-task BudgetChecker bound to this tracker. Returns nil when
-// the tracker itself is nil so callers can unconditionally pass it through.
+// For returns a per-task BudgetChecker bound to this tracker. Usage recorded
+// through it is attributed to the task's commander; ForAgent derives a
+// checker for an agent spawned by that commander. Returns nil when the
+// tracker itself is nil so callers can unconditionally pass it through.
 func (bt *BudgetTracker) For(taskName string) BudgetChecker {
 	if bt == nil {
 		return nil
 	}
-	return &taskBudgetChecker{tracker: bt, taskName: taskName}
+	return &taskBudgetChecker{tracker: bt, taskName: taskName, entity: commanderEntity}
 }
 
 type taskBudgetChecker struct {
 	tracker  *BudgetTracker
 	taskName string
+	entity   string
 }
 
 func (c *taskBudgetChecker) CheckBudget() error {
@@ -244,5 +399,13 @@ func (c *taskBudgetChecker) CheckBudget() error {
 }
 
 func (c *taskBudgetChecker) RecordUsage(tokens int, cost float64) error {
-	return c.tracker.Record(c.taskName, tokens, cost)
+	return c.tracker.RecordFor(c.taskName, c.entity, tokens, cost)
+}
+
+func (c *taskBudgetChecker) ReleaseCall() {
+	c.tracker.Release(c.taskName)
+}
+
+func (c *taskBudgetChecker) ForAgent(agentName string) agent.BudgetChecker {
+	return &taskBudgetChecker{tracker: c.tracker, taskName: c.taskName, entity: agentName}
 }
@@ -2,6 +2,9 @@ package mission
 
 import (
 	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
 	"testing"
 
 	"squadron/config"
@@ -139,3 +142,110 @@ func TestBudgetTracker_NoLimitMeansNoBreach(t *testing.T) {
 		}
 	}
 }
+
+func TestBudgetTracker_LLMCallsCheckedBeforeCall(t *testing.T) {
+	m := &config.Mission{
+		Tasks: []config.Task{
+			{Name: "a", Budget: &config.Budget{LLMCalls: intp(2)}},
+		},
+	}
+	bt := NewBudgetTracker(m)
+	for i := 0; i < 2; i++ {
+		if err := bt.Check("a"); err != nil {
+			t.Fatalf("call %d should be allowed: %v", i+1, err)
+		}
+		if err := bt.Record("a", 10, 0); err != nil {
+			t.Fatalf("unexpected breach recording call %d: %v", i+1, err)
+		}
+	}
+	err := bt.Check("a")
+	b, ok := err.(*BudgetBreach)
+	if !ok {
+		t.Fatalf("expected *BudgetBreach before the third call, got %v", err)
+	}
+	if b.Kind != BudgetKindLLMCalls || b.Used != 2 || b.Limit != 2 {
+		t.Fatalf("unexpected breach shape: %+v", b)
+	}
+}
+
+func TestBudgetTracker_LLMCallsReservedUnderConcurrency(t *testing.T) {
+	m := &config.Mission{
+		Tasks: []config.Task{
+			{Name: "a", Budget: &config.Budget{LLMCalls: intp(5)}},
+		},
+	}
+	bt := NewBudgetTracker(m)
+	var allowed atomic.Int64
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// Parallel iterations share the base task's limit
+			if bt.Check(fmt.Sprintf("a[%d]", i)) == nil {
+				allowed.Add(1)
+			}
+		}(i)
+	}
+	wg.Wait()
+	if got := allowed.Load(); got != 5 {
+		t.Fatalf("expected exactly 5 calls to pass Check, got %d", got)
+	}
+}
+
+func TestBudgetTracker_ReleaseReturnsReservedCall(t *testing.T) {
+	m := &config.Mission{
+		Tasks: []config.Task{
+			{Name: "a", Budget: &config.Budget{LLMCalls: intp(1)}},
+		},
+	}
+	bt := NewBudgetTracker(m)
+	c := bt.For("a")
+	if err := c.CheckBudget(); err != nil {
+		t.Fatalf("first call should be allowed: %v", err)
+	}
+	// The request failed without a response, so the slot goes back
+	c.ReleaseCall()
+	if err := c.CheckBudget(); err != nil {
+		t.Fatalf("released call should be available again: %v", err)
+	}
+	if err := c.RecordUsage(10, 0); err != nil {
+		t.Fatalf("unexpected breach recording the call: %v", err)
+	}
+	if err := c.CheckBudget(); err == nil {
+		t.Fatal("expected a breach once the single call was made")
+	}
+}
+
+func TestBudgetTracker_BreakdownPerEntity(t *testing.T) {
+	m := &config.Mission{
+		Budget: &config.Budget{Tokens: intp(100)},
+		Tasks:  []config.Task{{Name: "a"}, {Name: "b"}},
+	}
+	bt := NewBudgetTracker(m)
+	cmd := bt.For("a[0]")
+	if err := cmd.RecordUsage(10, 0.01); err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.ForAgent("scout").RecordUsage(30, 0.02); err != nil {
+		t.Fatal(err)
+	}
+	err := bt.For("b").RecordUsage(60, 0)
+	b, ok := err.(*BudgetBreach)
+	if !ok {
+		t.Fatalf("expected mission breach, got %v", err)
+	}
+	want := []BudgetSpend{
+		{TaskName: "a", Entity: "commander", Tokens: 10, Dollars: 0.01, LLMCalls: 1},
+		{TaskName: "a", Entity: "scout", Tokens: 30, Dollars: 0.02, LLMCalls: 1},
+		{TaskName: "b", Entity: "commander", Tokens: 60, LLMCalls: 1},
+	}
+	if len(b.Breakdown) != len(want) {
+		t.Fatalf("expected %d breakdown rows, got %+v", len(want), b.Breakdown)
+	}
+	for i := range want {
+		if b.Breakdown[i] != want[i] {
+			t.Fatalf("row %d: expected %+v, got %+v", i, want[i], b.Breakdown[i])
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package mission
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"squadron/agent"
+	"squadron/streamers"
+)
+
+// ErrMissionCancelled is returned (wrapped with the reason) by Run when the
+// mission was cancelled with Cancel. The mission is recorded as
+// "cancelled"; WithResume can still continue it.
+var ErrMissionCancelled = errors.New("mission cancelled")
+
+// cancelGracePeriod is how long in-flight commanders get, from the moment
+// Cancel is called, to write their partial summaries.
+var cancelGracePeriod = 30 * time.Second
+
+// cancellation records a Cancel call.
+type cancellation struct {
+	reason   string
+	deadline time.Time // for partial summaries
+
+	// Partial results of interrupted commanders, by task name
+	partials map[string][]partialResult
+}
+
+// partialResult is what one interrupted commander (a task, or one iteration
+// of it) left behind.
+type partialResult struct {
+	Index   *int           `json:"index,omitempty"`
+	Summary string         `json:"summary,omitempty"`
+	Output  map[string]any `json:"output,omitempty"`
+}
+
+// Cancel ends the mission for good, unlike Pause. No new tasks or
+// iterations are dispatched and in-flight commanders are interrupted at
+// their current call, then each is asked for a short summary of what it
+// got done, within cancelGracePeriod. Interrupted tasks are recorded as
+// "cancelled" with those summaries and any output already submitted, the
+// mission as "cancelled", and streamers implementing CancelHandler are
+// told. Run then returns an error wrapping ErrMissionCancelled. Safe to
+// call from any goroutine; only the first reason is kept.
+func (r *Runner) Cancel(reason string) {
+	if reason == "" {
+		reason = "cancelled by request"
+	}
+	r.pauseMu.Lock()
+	if r.cancelled == nil {
+		r.cancelled = &cancellation{
+			reason:   reason,
+			deadline: time.Now().Add(cancelGracePeriod),
+			partials: make(map[string][]partialResult),
+		}
+	}
+	cancel := r.cancelRun
+	r.pauseMu.Unlock()
+
+	r.Drain()
+	if cancel != nil {
+		cancel()
+	}
+}
+
+// IsCancelled returns true if Cancel has been called.
+func (r *Runner) IsCancelled() bool {
+	r.pauseMu.Lock()
+	defer r.pauseMu.Unlock()
+	return r.cancelled != nil
+}
+
+// cancelReason returns the reason given to Cancel, or "".
+func (r *Runner) cancelReason() string {
+	r.pauseMu.Lock()
+	defer r.pauseMu.Unlock()
+	if r.cancelled == nil {
+		return ""
+	}
+	return r.cancelled.reason
+}
+
+// recordPartialResult asks a commander interrupted by Cancel for its
+// partial summary and keeps it, with any output it already submitted, for
+// the task's cancelled record. index is nil for a non-iterated task. It
+// does nothing unless the mission is being cancelled.
+func (r *Runner) recordPartialResult(taskName string, index *int, sup *agent.Commander) {
+	r.pauseMu.Lock()
+	c := r.cancelled
+	r.pauseMu.Unlock()
+	if c == nil || sup == nil {
+		return
+	}
+
+	ctx, cancel := context.WithDeadline(context.Background(), c.deadline)
+	defer cancel()
+	summary, err := sup.PartialSummary(ctx, c.reason)
+	if err != nil && r.debugLogger != nil {
+		r.debugLogger.LogEvent(EventPartialSummaryFailed, map[string]any{
+			"task":  taskName,
+			"error": err.Error(),
+		})
+	}
+
+	p := partialResult{Index: index, Summary: summary}
+	// A sequential commander's submitted outputs are already stored per
+	// item; only a plain task's output would otherwise be lost
+	if index == nil && !sup.HasSequentialDataset() {
+		if results := sup.GetSubmitResults(); len(results) > 0 {
+			p.Output = results[0].Output
+		}
+	}
+	if p.Summary == "" && p.Output == nil {
+		return
+	}
+
+	r.pauseMu.Lock()
+	c.partials[taskName] = append(c.partials[taskName], p)
+	r.pauseMu.Unlock()
+}
+
+// finishCancelledTask records a task interrupted by Cancel as cancelled,
+// with its partial results as output and their summaries as its summary.
+func (r *Runner) finishCancelledTask(taskName, taskID string, streamer streamers.MissionHandler) {
+	r.pauseMu.Lock()
+	reason := r.cancelled.reason
+	partials := r.cancelled.partials[taskName]
+	r.pauseMu.Unlock()
+
+	var summaries []string
+	for _, p := range partials {
+		if p.Summary == "" {
+			continue
+		}
+		if p.Index != nil {
+			summaries = append(summaries, fmt.Sprintf("[%d] %s", *p.Index, p.Summary))
+		} else {
+			summaries = append(summaries, p.Summary)
+		}
+	}
+	summary := strings.Join(summaries, "\n")
+
+	if taskID != "" {
+		outputJSON, _ := json.Marshal(map[string]any{
+			"cancelled": true,
+			"reason":    reason,
+			"partial":   partials,
+		})
+		out := string(outputJSON)
+		r.stores.Missions.UpdateTaskStatus(taskID, string(TaskCancelled), &out, &reason)
+		if summary != "" {
+			r.stores.Missions.UpdateTaskSummary(taskID, summary)
+		}
+	}
+
+	if ch, ok := streamer.(streamers.CancelHandler); ok {
+		ch.TaskCancelled(streamers.TaskCancelledData{TaskName: taskName, Reason: reason, Summary: summary})
+	}
+	if r.debugLogger != nil {
+		r.debugLogger.LogEvent(EventTaskCancelled, map[string]any{
+			"task":    taskName,
+			"reason":  reason,
+			"summary": summary,
+		})
+	}
+}
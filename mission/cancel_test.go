@@ -0,0 +1,86 @@
+package mission
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"squadron/config"
+	"squadron/llm"
+)
+
+var _ = Describe("Mission cancel", func() {
+	var cfg *config.Config
+
+	BeforeEach(func() {
+		first := testTask("first", "Do the first thing")
+		second := testTask("second", "Do the second thing")
+		second.DependsOn = []string{"first"}
+		cfg = buildTestConfig(testMission("cancellable", []config.Task{first, second}), testAgent("worker"))
+		cfg.Storage.Path = filepath.Join(GinkgoT().TempDir(), "store.db")
+	})
+
+	It("records a partial summary and marks the task and mission cancelled", func() {
+		// The commander hands the task to an agent, which hangs
+		provider := newMockProvider(
+			cmdCallAgent("worker", "Read the files"),
+			hangingCall(),
+			withMatch(textResponse("Looked at half the files."), matchLastUserContains("<CANCELLED>")),
+		)
+		runner, err := NewRunner(cfg, "", "cancellable", nil, WithProviderFactory(func() llm.Provider { return provider }))
+		Expect(err).NotTo(HaveOccurred())
+		defer runner.CloseStores()
+		streamer := newMockMissionStreamer()
+		done := make(chan error, 1)
+		go func() { done <- runner.Run(context.Background(), streamer) }()
+		Eventually(provider.callCount).Should(Equal(2))
+
+		runner.Cancel("operator gave up")
+		var runErr error
+		Eventually(done).Should(Receive(&runErr))
+		Expect(errors.Is(runErr, ErrMissionCancelled)).To(BeTrue())
+		Expect(runErr).To(MatchError(ContainSubstring("operator gave up")))
+
+		record, err := runner.stores.Missions.GetMission(runner.MissionID())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(record.Status).To(Equal("cancelled"))
+
+		tasks, err := runner.stores.Missions.GetTasksByMission(runner.MissionID())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tasks).To(HaveLen(1)) // second was never dispatched
+		Expect(tasks[0].Status).To(Equal("cancelled"))
+		Expect(tasks[0].Summary).NotTo(BeNil())
+		Expect(*tasks[0].Summary).To(Equal("Looked at half the files."))
+
+		var out struct {
+			Cancelled bool            `json:"cancelled"`
+			Reason    string          `json:"reason"`
+			Partial   []partialResult `json:"partial"`
+		}
+		Expect(tasks[0].OutputJSON).NotTo(BeNil())
+		Expect(json.Unmarshal([]byte(*tasks[0].OutputJSON), &out)).To(Succeed())
+		Expect(out.Cancelled).To(BeTrue())
+		Expect(out.Reason).To(Equal("operator gave up"))
+		Expect(out.Partial).To(HaveLen(1))
+
+		Expect(streamer.hasEvent("task_cancelled")).To(BeTrue())
+		Expect(streamer.hasEvent("mission_cancelled")).To(BeTrue())
+		Expect(streamer.hasEvent("mission_failed")).To(BeFalse())
+	})
+
+	It("cancels a mission before it starts", func() {
+		provider := newMockProvider()
+		runner, err := NewRunner(cfg, "", "cancellable", nil, WithProviderFactory(func() llm.Provider { return provider }))
+		Expect(err).NotTo(HaveOccurred())
+		defer runner.CloseStores()
+		runner.Cancel("")
+		err = runner.Run(context.Background(), newMockMissionStreamer())
+		Expect(errors.Is(err, ErrMissionCancelled)).To(BeTrue())
+		Expect(err).To(MatchError(ContainSubstring("cancelled by request")))
+		Expect(runner.IsCancelled()).To(BeTrue())
+	})
+})
@@ -0,0 +1,163 @@
+package mission
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+
+	"squadron/aitools"
+	"squadron/llm"
+)
+
+// ErrChaosKilled is returned by Run when chaos mode killed the run. The
+// store is left the way the interrupted run wrote it, so the mission can be
+// continued with WithResume like one whose process died.
+var ErrChaosKilled = errors.New("mission killed by chaos injection")
+
+// Chaos configures failure injection for a mission run. It is meant for
+// tests: together with a fake provider from WithProviderFactory it drives
+// the retry, session healing and resume paths without a real outage.
+//
+// Rates are probabilities between 0 and 1, drawn at every provider call
+// (only for providers from WithProviderFactory) and every tool call, in
+// that order. Draws come from a generator seeded with Seed, so a mission
+// whose tasks run one at a time fails the same way on every run.
+type Chaos struct {
+	Seed int64
+
+	// ProviderErrorRate is the chance a provider call fails with a
+	// retryable 503 error without reaching the provider.
+	ProviderErrorRate float64
+
+	// ToolFailureRate is the chance a tool call returns an error result
+	// without running.
+	ToolFailureRate float64
+
+	// KillRate is the chance the run is killed at a provider or tool call,
+	// as if the process died there.
+	KillRate float64
+
+	// KillAt kills the run at the given provider or tool call, counting
+	// both from 1. 0 disables it.
+	KillAt int
+}
+
+// WithChaos enables failure injection for the run. See Chaos.
+func WithChaos(c Chaos) RunnerOption {
+	return func(r *Runner) {
+		r.chaos = &chaosInjector{cfg: c, rng: rand.New(rand.NewSource(c.Seed))}
+	}
+}
+
+// chaosInjector makes the draws for a Chaos config and counts what it
+// injected.
+type chaosInjector struct {
+	cfg Chaos
+
+	mu     sync.Mutex
+	rng    *rand.Rand
+	calls  int                // provider and tool calls seen
+	kill   context.CancelFunc // cancels the run
+	killed bool
+
+	providerErrors int
+	toolFailures   int
+}
+
+// start attaches the tool fault to the run's context and records cancel as
+// the way to kill it.
+func (c *chaosInjector) start(ctx context.Context, cancel context.CancelFunc) context.Context {
+	c.mu.Lock()
+	c.kill = cancel
+	c.mu.Unlock()
+	return aitools.WithToolFault(ctx, c.toolFault)
+}
+
+func (c *chaosInjector) wasKilled() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.killed
+}
+
+// draw counts a call and decides its fate: whether the run dies here, and
+// otherwise whether the call fails with the given rate.
+func (c *chaosInjector) draw(rate float64) (kill, fail bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.killed {
+		return true, false
+	}
+	c.calls++
+	if c.calls == c.cfg.KillAt || c.chance(c.cfg.KillRate) {
+		c.killed = true
+		if c.kill != nil {
+			c.kill()
+		}
+		return true, false
+	}
+	return false, c.chance(rate)
+}
+
+// chance draws only for non-zero rates, so enabling one kind of failure
+// doesn't change where the others land.
+func (c *chaosInjector) chance(rate float64) bool {
+	return rate > 0 && c.rng.Float64() < rate
+}
+
+func (c *chaosInjector) toolFault(ctx context.Context, toolName string) string {
+	kill, fail := c.draw(c.cfg.ToolFailureRate)
+	switch {
+	case kill:
+		return "Error: " + context.Canceled.Error()
+	case fail:
+		c.mu.Lock()
+		c.toolFailures++
+		c.mu.Unlock()
+		return fmt.Sprintf("Error: chaos: injected failure of tool '%s'", toolName)
+	}
+	return ""
+}
+
+func (c *chaosInjector) providerFault() error {
+	kill, fail := c.draw(c.cfg.ProviderErrorRate)
+	switch {
+	case kill:
+		return context.Canceled
+	case fail:
+		c.mu.Lock()
+		c.providerErrors++
+		c.mu.Unlock()
+		return errors.New("chaos: injected provider error: 503 Service Unavailable")
+	}
+	return nil
+}
+
+// wrapProvider returns p with provider faults injected, or p itself when
+// chaos is off.
+func (c *chaosInjector) wrapProvider(p llm.Provider) llm.Provider {
+	if c == nil || p == nil {
+		return p
+	}
+	return &chaosProvider{Provider: p, chaos: c}
+}
+
+type chaosProvider struct {
+	llm.Provider
+	chaos *chaosInjector
+}
+
+func (p *chaosProvider) Chat(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+	if err := p.chaos.providerFault(); err != nil {
+		return nil, err
+	}
+	return p.Provider.Chat(ctx, req)
+}
+
+func (p *chaosProvider) ChatStream(ctx context.Context, req *llm.ChatRequest) (<-chan llm.StreamChunk, error) {
+	if err := p.chaos.providerFault(); err != nil {
+		return nil, err
+	}
+	return p.Provider.ChatStream(ctx, req)
+}
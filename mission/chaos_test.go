@@ -0,0 +1,106 @@
+package mission
+
+import (
+	"context"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"squadron/config"
+	"squadron/failure"
+	"squadron/llm"
+)
+
+var _ = Describe("Chaos mode", func() {
+	var cfg *config.Config
+
+	BeforeEach(func() {
+		cfg = buildTestConfig(testMission("chaotic", []config.Task{testTask("work", "Do the work")}), testAgent("worker"))
+		cfg.Storage.Path = filepath.Join(GinkgoT().TempDir(), "store.db")
+		cfg.Models[0].Retry = &config.ModelRetry{MaxAttempts: 10, InitialBackoff: "1ms", MaxBackoff: "1ms"}
+	})
+
+	It("retries injected provider errors without reaching the provider", func() {
+		provider := newMockProvider(
+			cmdCallAgent("worker", "Do it"),
+			textResponse("<ANSWER>done</ANSWER>"),
+			cmdTaskComplete(),
+		)
+		runner, err := NewRunner(cfg, "", "chaotic", nil,
+			WithProviderFactory(func() llm.Provider { return provider }),
+			WithChaos(Chaos{Seed: 7, ProviderErrorRate: 0.5}))
+		Expect(err).NotTo(HaveOccurred())
+		defer runner.CloseStores()
+
+		Expect(runner.Run(context.Background(), newMockMissionStreamer())).To(Succeed())
+		Expect(runner.chaos.providerErrors).To(BeNumerically(">", 0))
+		Expect(provider.callCount()).To(Equal(3))
+	})
+
+	It("fails the task with provider_error once retries run out", func() {
+		cfg.Models[0].Retry.MaxAttempts = 2
+		provider := newMockProvider(cmdTaskComplete())
+		runner, err := NewRunner(cfg, "", "chaotic", nil,
+			WithProviderFactory(func() llm.Provider { return provider }),
+			WithChaos(Chaos{ProviderErrorRate: 1}))
+		Expect(err).NotTo(HaveOccurred())
+		defer runner.CloseStores()
+
+		Expect(runner.Run(context.Background(), newMockMissionStreamer())).NotTo(Succeed())
+		Expect(provider.callCount()).To(Equal(0))
+		tasks, err := runner.stores.Missions.GetTasksByMission(runner.MissionID())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tasks[0].ErrorCode).NotTo(BeNil())
+		Expect(*tasks[0].ErrorCode).To(Equal(string(failure.CodeProviderError)))
+	})
+
+	It("returns injected tool failures to the model as error results", func() {
+		cfg.Missions[0].Tasks[0].MaxTurns = 2
+		provider := newMockProvider(
+			cmdCallAgent("worker", "Do it"),
+			cmdCallAgent("worker", "Try again"),
+		)
+		runner, err := NewRunner(cfg, "", "chaotic", nil,
+			WithProviderFactory(func() llm.Provider { return provider }),
+			WithChaos(Chaos{ToolFailureRate: 1}))
+		Expect(err).NotTo(HaveOccurred())
+		defer runner.CloseStores()
+
+		Expect(runner.Run(context.Background(), newMockMissionStreamer())).NotTo(Succeed())
+		Expect(toolResults(provider)).To(ContainElement(ContainSubstring("chaos: injected failure of tool 'call_agent'")))
+		Expect(runner.chaos.toolFailures).To(BeNumerically(">=", 1))
+	})
+
+	It("kills the run at a chosen call and resumes it to completion", func() {
+		// Call 1 is the commander's turn, call 2 its call_agent tool call,
+		// call 3 the agent's first turn.
+		provider := newMockProvider(cmdCallAgent("worker", "Do it"))
+		runner, err := NewRunner(cfg, "", "chaotic", nil,
+			WithProviderFactory(func() llm.Provider { return provider }),
+			WithChaos(Chaos{KillAt: 3}))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(runner.Run(context.Background(), newMockMissionStreamer())).To(MatchError(ErrChaosKilled))
+		Expect(provider.callCount()).To(Equal(1))
+		missionID := runner.MissionID()
+		runner.CloseStores()
+
+		// The agent picks up its interrupted turn, then the commander's
+		// pending call_agent gets its answer.
+		provider = newMockProvider(
+			textResponse("<ANSWER>done</ANSWER>"),
+			cmdTaskComplete(),
+		)
+		resumed, err := NewRunner(cfg, "", "chaotic", nil, WithResume(missionID),
+			WithProviderFactory(func() llm.Provider { return provider }))
+		Expect(err).NotTo(HaveOccurred())
+		defer resumed.CloseStores()
+		Expect(resumed.Run(context.Background(), newMockMissionStreamer())).To(Succeed())
+		Expect(provider.callCount()).To(Equal(2))
+		Expect(systemPrompt(provider.getCalls()[0])).To(HavePrefix("# Agent System Prompt"))
+
+		tasks, err := resumed.stores.Missions.GetTasksByMission(missionID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tasks[0].Status).To(Equal("completed"))
+	})
+})
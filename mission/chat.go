@@ -0,0 +1,287 @@
+package mission
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+
+	"squadron/agent"
+	"squadron/config"
+	"squadron/llm"
+	"squadron/store"
+	"squadron/streamers"
+)
+
+// ChatMissionName is the mission name a chat is stored under. Each chat is a
+// mission record with a single task, so its commander and agent sessions are
+// kept like any other run.
+const ChatMissionName = "chat"
+
+// chatTaskName names the single task of a chat's mission record.
+const chatTaskName = "chat"
+
+// ChatStreamer receives the events of an interactive commander chat.
+type ChatStreamer interface {
+	agent.CommanderStreamer
+	AgentStarted(agentName, instruction string)
+	AgentHandler(agentName string) streamers.ChatHandler
+	AgentCompleted(agentName string)
+}
+
+// ChatOptions configures an interactive commander chat.
+type ChatOptions struct {
+	Config     *config.Config
+	ConfigPath string
+	// Agents are the agents the commander may call.
+	Agents []string
+	// Commander is the commander's model key. Defaults to the first agent's model.
+	Commander   string
+	DebugLogger *DebugLogger
+	// Provider replaces the LLM provider (tests only).
+	Provider llm.Provider
+}
+
+// Chat is a conversation between a human and a commander outside any
+// mission. It is persisted as a mission record named ChatMissionName, so a
+// chat can later be turned into a mission with PromoteChat.
+type Chat struct {
+	commander *agent.Commander
+	stores    *store.Bundle
+	missionID string
+	taskID    string
+}
+
+// NewChat starts a chat. Close it when the conversation ends.
+func NewChat(ctx context.Context, opts ChatOptions, streamer ChatStreamer) (*Chat, error) {
+	cfg := opts.Config
+	if len(opts.Agents) == 0 {
+		return nil, fmt.Errorf("chat needs at least one agent")
+	}
+	for _, name := range opts.Agents {
+		if findAgent(cfg, name) == nil {
+			return nil, fmt.Errorf("agent '%s' not found", name)
+		}
+	}
+	commanderModel := opts.Commander
+	if commanderModel == "" {
+		commanderModel = findAgent(cfg, opts.Agents[0]).Model
+	}
+
+	stores, err := store.NewBundle(cfg.Storage)
+	if err != nil {
+		return nil, fmt.Errorf("chat: init stores: %w", err)
+	}
+	c := &Chat{stores: stores}
+
+	configJSON, _ := json.Marshal(map[string]any{
+		"name":      ChatMissionName,
+		"commander": commanderModel,
+		"agents":    opts.Agents,
+	})
+	if c.missionID, err = stores.Missions.CreateMission(ChatMissionName, "{}", string(configJSON)); err != nil {
+		stores.Close()
+		return nil, fmt.Errorf("create chat record: %w", err)
+	}
+	if c.taskID, err = stores.Missions.CreateTask(c.missionID, chatTaskName, "{}"); err != nil {
+		stores.Close()
+		return nil, fmt.Errorf("create chat record: %w", err)
+	}
+
+	var debugFile string
+	if opts.DebugLogger != nil {
+		debugFile = opts.DebugLogger.GetMessageFile(agent.DebugScope{Task: chatTaskName}, "commander", "")
+	}
+	c.commander, err = agent.NewCommander(ctx, agent.CommanderOptions{
+		Config:      cfg,
+		ConfigPath:  opts.ConfigPath,
+		MissionName: ChatMissionName,
+		TaskName:    chatTaskName,
+		Commander:   commanderModel,
+		AgentNames:  opts.Agents,
+		DebugFile:   debugFile,
+		Provider:    opts.Provider,
+		Interactive: true,
+	})
+	if err != nil {
+		c.finish("failed")
+		return nil, err
+	}
+
+	var debugLogger agent.DebugLogger
+	if opts.DebugLogger != nil {
+		debugLogger = opts.DebugLogger
+	}
+	c.commander.SetToolCallbacks(&agent.CommanderToolCallbacks{
+		OnAgentStart: func(_, agentName, instruction string) {
+			streamer.AgentStarted(agentName, instruction)
+		},
+		GetAgentHandler: func(_, agentName string) streamers.ChatHandler {
+			return streamer.AgentHandler(agentName)
+		},
+		OnAgentComplete: func(_, agentName string) {
+			streamer.AgentCompleted(agentName)
+		},
+		DebugLogger:   debugLogger,
+		SessionLogger: stores.Sessions,
+		TaskID:        c.taskID,
+		MissionID:     c.missionID,
+	}, nil)
+	return c, nil
+}
+
+// ID returns the chat's mission record ID.
+func (c *Chat) ID() string {
+	return c.missionID
+}
+
+// Model returns the commander's model name.
+func (c *Chat) Model() string {
+	return c.commander.ModelName
+}
+
+// Send passes one message to the commander and returns its reply.
+func (c *Chat) Send(ctx context.Context, message string, streamer agent.CommanderStreamer) (string, error) {
+	c.stores.Missions.StoreTaskInput(c.taskID, nil, message)
+	reply, err := c.commander.Chat(ctx, message, streamer)
+	if err == nil {
+		c.stores.Missions.UpdateTaskSummary(c.taskID, reply)
+	}
+	return reply, err
+}
+
+// Close ends the chat and marks its record completed.
+func (c *Chat) Close() {
+	c.commander.Close()
+	c.finish("completed")
+}
+
+func (c *Chat) finish(status string) {
+	c.stores.Missions.UpdateTaskStatus(c.taskID, status, nil, nil)
+	c.stores.Missions.UpdateMissionStatus(c.missionID, status)
+	c.stores.Close()
+}
+
+// PromoteChat turns a stored chat into a mission definition. Each message
+// the human sent becomes a task, in order, each depending on the one
+// before it, so later requests see the summaries of earlier ones. The
+// mission uses the chat's commander model and agents.
+func PromoteChat(cfg *config.Config, stores *store.Bundle, chatID, missionName string) ([]byte, error) {
+	rec, err := stores.Missions.GetMission(chatID)
+	if err != nil {
+		return nil, fmt.Errorf("chat %s not found: %w", chatID, err)
+	}
+	if rec.MissionName != ChatMissionName {
+		return nil, fmt.Errorf("%s is a run of mission '%s', not a chat", chatID, rec.MissionName)
+	}
+	var snap struct {
+		Commander string   `json:"commander"`
+		Agents    []string `json:"agents"`
+	}
+	if err := json.Unmarshal([]byte(rec.ConfigJSON), &snap); err != nil {
+		return nil, fmt.Errorf("chat %s: reading config: %w", chatID, err)
+	}
+	provider := ""
+	for i := range cfg.Models {
+		if _, ok := cfg.Models[i].AvailableModels()[snap.Commander]; ok {
+			provider = cfg.Models[i].Name
+			break
+		}
+	}
+	if provider == "" {
+		return nil, fmt.Errorf("chat %s: commander model '%s' not found in models", chatID, snap.Commander)
+	}
+
+	requests, err := chatRequests(stores, chatID)
+	if err != nil {
+		return nil, err
+	}
+	if len(requests) == 0 {
+		return nil, fmt.Errorf("chat %s has no messages", chatID)
+	}
+
+	f := hclwrite.NewEmptyFile()
+	m := f.Body().AppendNewBlock("mission", []string{missionName}).Body()
+	m.AppendNewBlock("commander", nil).Body().SetAttributeTraversal("model", hcl.Traversal{
+		hcl.TraverseRoot{Name: "models"},
+		hcl.TraverseAttr{Name: provider},
+		hcl.TraverseAttr{Name: snap.Commander},
+	})
+	var agents []hclwrite.Tokens
+	for _, name := range snap.Agents {
+		agents = append(agents, hclwrite.TokensForTraversal(hcl.Traversal{
+			hcl.TraverseRoot{Name: "agents"},
+			hcl.TraverseAttr{Name: name},
+		}))
+	}
+	m.SetAttributeRaw("agents", hclwrite.TokensForTuple(agents))
+	for i, request := range requests {
+		m.AppendNewline()
+		t := m.AppendNewBlock("task", []string{fmt.Sprintf("step_%d", i+1)}).Body()
+		t.SetAttributeValue("objective", cty.StringVal(request))
+		if i > 0 {
+			t.SetAttributeRaw("depends_on", hclwrite.TokensForTuple([]hclwrite.Tokens{
+				hclwrite.TokensForTraversal(hcl.Traversal{
+					hcl.TraverseRoot{Name: "tasks"},
+					hcl.TraverseAttr{Name: fmt.Sprintf("step_%d", i)},
+				}),
+			}))
+		}
+	}
+	return f.Bytes(), nil
+}
+
+// chatRequests returns the messages the human sent in a chat, in order.
+func chatRequests(stores *store.Bundle, chatID string) ([]string, error) {
+	task, err := stores.Missions.GetTaskByName(chatID, chatTaskName)
+	if err != nil {
+		return nil, fmt.Errorf("chat %s: %w", chatID, err)
+	}
+	sessions, err := stores.Sessions.GetSessionsByTask(task.ID)
+	if err != nil {
+		return nil, fmt.Errorf("chat %s: %w", chatID, err)
+	}
+	var requests []string
+	for _, sess := range sessions {
+		if sess.Role != "commander" {
+			continue
+		}
+		msgs, err := stores.Sessions.GetStructuredMessages(sess.ID)
+		if err != nil {
+			return nil, fmt.Errorf("chat %s: %w", chatID, err)
+		}
+		for _, msg := range msgs {
+			if msg.Role != "user" || !isTextOnly(msg.Parts) {
+				continue
+			}
+			if text := strings.TrimSpace(msg.Content); text != "" {
+				requests = append(requests, text)
+			}
+		}
+	}
+	return requests, nil
+}
+
+// isTextOnly reports whether a message is plain text — a human message
+// rather than tool results.
+func isTextOnly(parts []store.MessagePart) bool {
+	for _, p := range parts {
+		if p.Type != "text" {
+			return false
+		}
+	}
+	return true
+}
+
+func findAgent(cfg *config.Config, name string) *config.Agent {
+	for i := range cfg.Agents {
+		if cfg.Agents[i].Name == name {
+			return &cfg.Agents[i]
+		}
+	}
+	return nil
+}
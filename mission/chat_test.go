@@ -0,0 +1,169 @@
+package mission
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"sync"
+
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/mlund01/squadron-wire/protocol"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"squadron/config"
+	"squadron/llm"
+	"squadron/store"
+	"squadron/streamers"
+)
+
+// mockChatStreamer records the replies and agent runs of a chat.
+type mockChatStreamer struct {
+	mu      sync.Mutex
+	answers []string
+	agents  []string
+}
+
+func (s *mockChatStreamer) ReasoningStarted()                                   {}
+func (s *mockChatStreamer) ReasoningCompleted(content string)                   {}
+func (s *mockChatStreamer) CallingTool(toolCallId, name, input string)          {}
+func (s *mockChatStreamer) ToolComplete(toolCallId, name string, result string) {}
+func (s *mockChatStreamer) Compaction(inputTokens, tokenLimit, messagesCompacted, turnRetention int) {
+}
+func (s *mockChatStreamer) SessionTurn(data protocol.SessionTurnData) {}
+func (s *mockChatStreamer) AgentHandler(agentName string) streamers.ChatHandler {
+	return &mockChatHandler{}
+}
+func (s *mockChatStreamer) AgentCompleted(agentName string) {}
+
+func (s *mockChatStreamer) Answer(content string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.answers = append(s.answers, content)
+}
+
+func (s *mockChatStreamer) AgentStarted(agentName, instruction string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.agents = append(s.agents, agentName)
+}
+
+func chatReply(summary string) mockResponse {
+	input, _ := json.Marshal(map[string]string{"summary": summary})
+	return mockToolCall("task_complete", input)
+}
+
+var _ = Describe("Commander chat", func() {
+	var cfg *config.Config
+
+	BeforeEach(func() {
+		cfg = buildTestConfig(testMission("unused", []config.Task{testTask("t", "x")}), testAgent("worker"), testAgent("writer"))
+		cfg.Storage.Path = filepath.Join(GinkgoT().TempDir(), "store.db")
+	})
+
+	openStores := func() *store.Bundle {
+		stores, err := store.NewBundle(cfg.Storage)
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(stores.Close)
+		return stores
+	}
+
+	It("answers each message with a task_complete summary and keeps the conversation", func() {
+		provider := newMockProvider(
+			cmdCallAgent("worker", "List the files"),
+			agentAnswer("a.go, b.go"),
+			chatReply("There are two files: a.go and b.go."),
+			chatReply("b.go is the larger one."),
+		)
+		streamer := &mockChatStreamer{}
+		chat, err := NewChat(context.Background(), ChatOptions{
+			Config:   cfg,
+			Agents:   []string{"worker", "writer"},
+			Provider: provider,
+		}, streamer)
+		Expect(err).NotTo(HaveOccurred())
+
+		reply, err := chat.Send(context.Background(), "What files are there?", streamer)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reply).To(Equal("There are two files: a.go and b.go."))
+		Expect(streamer.agents).To(Equal([]string{"worker"}))
+
+		reply, err = chat.Send(context.Background(), "Which is larger?", streamer)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reply).To(Equal("b.go is the larger one."))
+		Expect(streamer.answers).To(HaveLen(2))
+
+		// The second request carries the first exchange, under the chat prompt
+		calls := provider.getCalls()
+		last := calls[len(calls)-1].Messages
+		Expect(last[0].Content).To(ContainSubstring("CHAT MODE"))
+		Expect(last[0].Content).NotTo(ContainSubstring("set_subtasks"))
+		var userTexts []string
+		for _, m := range last {
+			if m.Role == llm.RoleUser && m.Content != "" {
+				userTexts = append(userTexts, m.Content)
+			}
+		}
+		Expect(userTexts).To(ContainElements("What files are there?", "Which is larger?"))
+
+		chatID := chat.ID()
+		chat.Close()
+
+		stores := openStores()
+		rec, err := stores.Missions.GetMission(chatID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rec.MissionName).To(Equal(ChatMissionName))
+		Expect(rec.Status).To(Equal("completed"))
+	})
+
+	It("promotes a stored chat to a mission with one task per message", func() {
+		provider := newMockProvider(
+			chatReply("Drafted."),
+			chatReply("Reviewed."),
+		)
+		streamer := &mockChatStreamer{}
+		chat, err := NewChat(context.Background(), ChatOptions{
+			Config:   cfg,
+			Agents:   []string{"worker", "writer"},
+			Provider: provider,
+		}, streamer)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = chat.Send(context.Background(), "Draft the ${release} notes", streamer)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = chat.Send(context.Background(), "Review them", streamer)
+		Expect(err).NotTo(HaveOccurred())
+		chatID := chat.ID()
+		chat.Close()
+
+		src, err := PromoteChat(cfg, openStores(), chatID, "release_notes")
+		Expect(err).NotTo(HaveOccurred())
+		out := string(src)
+		Expect(out).To(ContainSubstring(`mission "release_notes"`))
+		Expect(out).To(ContainSubstring("model = models.test.claude_sonnet_4"))
+		Expect(out).To(ContainSubstring("agents = [agents.worker, agents.writer]"))
+		Expect(out).To(ContainSubstring(`task "step_1"`))
+		Expect(out).To(ContainSubstring(`objective = "Draft the $${release} notes"`))
+		Expect(out).To(ContainSubstring(`task "step_2"`))
+		Expect(out).To(ContainSubstring("depends_on = [tasks.step_1]"))
+
+		_, diags := hclparse.NewParser().ParseHCL(src, "promoted.hcl")
+		Expect(diags.HasErrors()).To(BeFalse(), diags.Error())
+	})
+
+	It("refuses to promote a mission run that isn't a chat", func() {
+		stores := openStores()
+		id, err := stores.Missions.CreateMission("nightly", "{}", "{}")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = PromoteChat(cfg, stores, id, "x")
+		Expect(err).To(MatchError(ContainSubstring("not a chat")))
+	})
+
+	It("rejects unknown agents", func() {
+		_, err := NewChat(context.Background(), ChatOptions{
+			Config:   cfg,
+			Agents:   []string{"ghost"},
+			Provider: newMockProvider(),
+		}, &mockChatStreamer{})
+		Expect(err).To(MatchError(ContainSubstring("agent 'ghost' not found")))
+	})
+})
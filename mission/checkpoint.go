@@ -0,0 +1,43 @@
+package mission
+
+import (
+	"log"
+	"time"
+
+	"squadron/agent"
+	"squadron/llm"
+)
+
+// restoreCheckpoint swaps a resumed commander onto its session's latest
+// checkpoint, if there is one. logMsgs is the stored message log already
+// loaded into sup. Turns logged after the checkpoint are redone, so a
+// dangling tool call at the end of the log gets a placeholder result;
+// that keeps the log well-formed for anything that replays it later
+// (resaturation, a resume after the task is marked complete).
+func (r *Runner) restoreCheckpoint(sup *agent.Commander, sessionID string, logMsgs []llm.Message) {
+	if r.stores.Checkpoints == nil {
+		return
+	}
+	cp, err := r.stores.Checkpoints.GetCheckpoint(sessionID)
+	if err != nil {
+		log.Printf("Commander %s: failed to load checkpoint: %v", sup.TaskName, err)
+		return
+	}
+	if cp == nil {
+		return
+	}
+	if err := sup.RestoreCheckpoint(cp); err != nil {
+		log.Printf("Commander %s: ignoring checkpoint: %v", sup.TaskName, err)
+		return
+	}
+
+	healed := agent.HealSessionMessages(logMsgs)
+	if len(healed) > len(logMsgs) {
+		msg := healed[len(healed)-1]
+		now := time.Now()
+		r.stores.Sessions.AppendStructuredMessage(sessionID, string(msg.Role), agent.AuditContentForMessage(msg), agent.PartsFromMessage(msg), now, now)
+	}
+	if r.debugLogger != nil {
+		r.debugLogger.LogEvent("commander_checkpoint_restored", map[string]any{"task": sup.TaskName, "turn": cp.Turn})
+	}
+}
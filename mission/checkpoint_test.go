@@ -0,0 +1,80 @@
+package mission
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"squadron/config"
+	"squadron/llm"
+)
+
+var _ = Describe("Commander checkpoints", func() {
+	var cfg *config.Config
+
+	BeforeEach(func() {
+		m := testMission("checkpointed", []config.Task{testTask("work", "Do the work")})
+		m.Commander.Checkpoint = &config.CommanderCheckpoint{Interval: 2}
+		cfg = buildTestConfig(m, testAgent("worker"))
+		cfg.Storage.Path = filepath.Join(GinkgoT().TempDir(), "store.db")
+	})
+
+	toolUseIDs := func(msgs []llm.Message) []string {
+		var ids []string
+		for _, m := range msgs {
+			for _, p := range m.Parts {
+				if p.Type == llm.ContentTypeToolUse && p.ToolUse != nil {
+					ids = append(ids, p.ToolUse.ID)
+				}
+			}
+		}
+		return ids
+	}
+
+	It("resumes from the last checkpoint and drops it when the task completes", func() {
+		step1 := mockToolCall("step", json.RawMessage(`{}`))
+		step2 := mockToolCall("step", json.RawMessage(`{}`))
+		step3 := mockToolCall("step", json.RawMessage(`{}`))
+		provider := newMockProvider(step1, step2, step3, hangingCall())
+		runner, err := NewRunner(cfg, "", "checkpointed", nil, WithProviderFactory(func() llm.Provider { return provider }))
+		Expect(err).NotTo(HaveOccurred())
+		done := make(chan error, 1)
+		go func() { done <- runner.Run(context.Background(), newMockMissionStreamer()) }()
+		Eventually(provider.callCount).Should(Equal(4))
+		runner.Pause()
+		Eventually(done).Should(Receive(MatchError(ErrMissionPaused)))
+
+		tasks, err := runner.stores.Missions.GetTasksByMission(runner.MissionID())
+		Expect(err).NotTo(HaveOccurred())
+		sessions, err := runner.stores.Sessions.GetSessionsByTask(tasks[0].ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sessions).To(HaveLen(1))
+		sessionID := sessions[0].ID
+		cp, err := runner.stores.Checkpoints.GetCheckpoint(sessionID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cp).NotTo(BeNil())
+		Expect(cp.Turn).To(Equal(2))
+		missionID := runner.MissionID()
+		runner.CloseStores()
+
+		provider = newMockProvider(cmdTaskComplete())
+		resumed, err := NewRunner(cfg, "", "checkpointed", nil, WithResume(missionID), WithProviderFactory(func() llm.Provider { return provider }))
+		Expect(err).NotTo(HaveOccurred())
+		defer resumed.CloseStores()
+		Expect(resumed.Run(context.Background(), newMockMissionStreamer())).To(Succeed())
+
+		// The resumed commander starts from turn 2; the third step is redone.
+		calls := provider.getCalls()
+		Expect(calls).NotTo(BeEmpty())
+		ids := toolUseIDs(calls[0].Messages)
+		Expect(ids).To(ContainElements(step1.ContentBlocks[0].ToolUse.ID, step2.ContentBlocks[0].ToolUse.ID))
+		Expect(ids).NotTo(ContainElement(step3.ContentBlocks[0].ToolUse.ID))
+
+		cp, err = resumed.stores.Checkpoints.GetCheckpoint(sessionID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cp).To(BeNil())
+	})
+})
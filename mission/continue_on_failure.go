@@ -0,0 +1,58 @@
+package mission
+
+import (
+	"fmt"
+
+	"squadron/config"
+	"squadron/failure"
+	"squadron/streamers"
+)
+
+// tolerateFailedIterations decides whether a continue_on_failure task can
+// complete on the items that succeeded. It returns nil when it can, after
+// reporting the failed items as a mission issue, and otherwise the error
+// to fail the task with. total is the dataset size: items that never ran,
+// such as those after a failed smoketest, count as failed.
+func (r *Runner) tolerateFailedIterations(task config.Task, iterations []IterationResult, total int, streamer streamers.MissionHandler) error {
+	var failedIndices []int
+	var firstError error
+	succeeded := 0
+	for _, iter := range iterations {
+		if iter.Success {
+			succeeded++
+			continue
+		}
+		// A budget breach will fail every item after it; finishing on
+		// partial results would hide that the mission ran out of budget.
+		if failure.CodeOf(iter.Error) == failure.CodeBudgetExceeded {
+			return iter.Error
+		}
+		failedIndices = append(failedIndices, iter.Index)
+		if firstError == nil {
+			firstError = iter.Error
+		}
+	}
+	if succeeded == 0 {
+		return fmt.Errorf("no iterations succeeded: %w", firstError)
+	}
+
+	failed := total - succeeded
+	rate := float64(failed) / float64(total)
+	maxRate := task.Iterator.GetMaxFailureRate()
+	if rate > maxRate {
+		return fmt.Errorf("%d of %d iterations failed (%.0f%%), over max_failure_rate %g: %w", failed, total, rate*100, maxRate, firstError)
+	}
+
+	streamer.MissionIssue(streamers.MissionIssueData{
+		Severity: streamers.IssueWarning,
+		Category: streamers.IssueCategoryFailedIterations,
+		Message:  fmt.Sprintf("task '%s' continued on %d of %d items; %d failed: %v", task.Name, succeeded, total, failed, firstError),
+		TaskName: task.Name,
+		Details: map[string]any{
+			"failed":           failedIndices,
+			"total":            total,
+			"max_failure_rate": maxRate,
+		},
+	})
+	return nil
+}
@@ -0,0 +1,91 @@
+package mission
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/zclconf/go-cty/cty"
+
+	"squadron/config"
+	"squadron/llm"
+	"squadron/store"
+)
+
+var _ = Describe("continue_on_failure", func() {
+	names := []string{"alpha", "beta", "gamma", "delta"}
+
+	// run processes one item per name in parallel, failing the named items.
+	run := func(maxFailureRate *float64, failing ...string) (*Runner, *mockMissionStreamer, error) {
+		task := testTask("process", "Process item")
+		task.ObjectiveExpr = templateExpr("Process ${item.name}")
+		task.Iterator = &config.TaskIterator{Dataset: "items", Parallel: true, ContinueOnFailure: true, MaxFailureRate: maxFailureRate}
+		mission := testMission("partial", []config.Task{task})
+		var items []cty.Value
+		for _, name := range names {
+			items = append(items, cty.ObjectVal(map[string]cty.Value{"name": cty.StringVal(name)}))
+		}
+		mission.Datasets = []config.Dataset{{Name: "items", Items: items}}
+
+		provider := newMockProvider()
+		for _, name := range failing {
+			provider.addResponses(withMatch(cmdTaskCompleteFail(name+" is unreachable"), matchLastUserContains("Process "+name)))
+		}
+		for range len(names) - len(failing) {
+			provider.addResponses(cmdTaskComplete())
+		}
+
+		runner, err := NewRunner(buildTestConfig(mission, testAgent("worker")), "", "partial", nil, WithProviderFactory(func() llm.Provider { return provider }))
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(runner.CloseStores)
+		streamer := newMockMissionStreamer()
+		return runner, streamer, runner.Run(context.Background(), streamer)
+	}
+
+	rate := func(r float64) *float64 { return &r }
+
+	It("completes the task on the items that succeeded and marks the rest failed", func() {
+		runner, streamer, err := run(rate(0.5), "gamma")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(streamer.hasEvent("mission_completed")).To(BeTrue())
+
+		tasks, err := runner.stores.Missions.GetTasksByMission(runner.MissionID())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tasks).To(HaveLen(1))
+		Expect(tasks[0].Status).To(Equal("completed"))
+		Expect(tasks[0].Summary).To(HaveValue(ContainSubstring("completed 3/4 iterations successfully")))
+		Expect(tasks[0].Summary).To(HaveValue(ContainSubstring("Iterations [2] failed and have no output")))
+
+		statuses, err := runner.stores.Datasets.GetItemStatuses(tasks[0].ID)
+		Expect(err).NotTo(HaveOccurred())
+		for _, s := range statuses {
+			if s.ItemID == "gamma" {
+				Expect(s.Status).To(Equal(store.ItemFailed))
+				Expect(s.Error).To(HaveValue(ContainSubstring("gamma is unreachable")))
+			} else {
+				Expect(s.Status).To(Equal(store.ItemCompleted))
+			}
+		}
+
+		var issue map[string]string
+		for _, e := range streamer.getEvents() {
+			if e.Type == "mission_issue" {
+				issue = e.Data
+			}
+		}
+		Expect(issue).To(HaveKeyWithValue("category", "failed_iterations"))
+		Expect(issue).To(HaveKeyWithValue("severity", "warning"))
+		Expect(issue["message"]).To(ContainSubstring("task 'process' continued on 3 of 4 items; 1 failed"))
+	})
+
+	It("fails the task when more items fail than max_failure_rate allows", func() {
+		_, streamer, err := run(rate(0.25), "gamma", "delta")
+		Expect(err).To(MatchError(ContainSubstring("2 of 4 iterations failed (50%), over max_failure_rate 0.25")))
+		Expect(streamer.hasEvent("mission_issue")).To(BeFalse())
+	})
+
+	It("fails the task when every item fails, whatever the rate", func() {
+		_, _, err := run(nil, names...)
+		Expect(err).To(MatchError(ContainSubstring("no iterations succeeded")))
+	})
+})
@@ -0,0 +1,56 @@
+package mission
+
+import (
+	"fmt"
+
+	"squadron/config"
+	"squadron/streamers"
+)
+
+// exportDatasets writes every dataset with an export block to disk once the
+// mission has completed. The mission has already succeeded at this point,
+// so a failed export is reported as an error-level issue rather than
+// failing the run.
+func (r *Runner) exportDatasets(streamer streamers.MissionHandler) {
+	for i := range r.mission.Datasets {
+		ds := &r.mission.Datasets[i]
+		if ds.Export == nil {
+			continue
+		}
+		if err := r.exportDataset(ds); err != nil {
+			streamer.MissionIssue(streamers.MissionIssueData{
+				Severity: streamers.IssueError,
+				Category: streamers.IssueCategoryDatasetExport,
+				Message:  fmt.Sprintf("exporting dataset '%s' to %s: %v", ds.Name, ds.Export.Path, err),
+				Details: map[string]any{
+					"dataset": ds.Name,
+					"path":    ds.Export.Path,
+					"format":  ds.Export.Format,
+				},
+			})
+		}
+	}
+}
+
+func (r *Runner) exportDataset(ds *config.Dataset) error {
+	r.mu.RLock()
+	dsID, ok := r.datasetIDs[ds.Name]
+	r.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("dataset not initialized")
+	}
+
+	count, err := r.stores.Datasets.GetItemCount(dsID)
+	if err != nil {
+		return err
+	}
+	values, err := r.stores.Datasets.GetItems(dsID, 0, count)
+	if err != nil {
+		return err
+	}
+	items := make([]any, len(values))
+	for i, v := range values {
+		items[i] = config.CtyValueToGo(v)
+	}
+	return ds.Export.WriteFile(ds.Schema, items)
+}
@@ -0,0 +1,39 @@
+package mission
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/zclconf/go-cty/cty"
+
+	"squadron/config"
+	"squadron/llm"
+)
+
+var _ = Describe("Dataset export", func() {
+	It("writes exported datasets when the mission completes", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "out", "cities.jsonl")
+		m := testMission("export", []config.Task{testTask("work", "Work")})
+		m.Datasets = []config.Dataset{{
+			Name:   "cities",
+			Items:  []cty.Value{cty.ObjectVal(map[string]cty.Value{"name": cty.StringVal("Paris")})},
+			Export: &config.DatasetExport{Path: path, Format: config.DatasetExportJSONL},
+		}}
+		cfg := buildTestConfig(m, testAgent("worker"))
+		provider := newMockProvider(cmdTaskComplete())
+
+		runner, err := NewRunner(cfg, "", "export", nil, WithProviderFactory(func() llm.Provider { return provider }))
+		Expect(err).NotTo(HaveOccurred())
+		defer runner.CloseStores()
+		streamer := newMockMissionStreamer()
+		Expect(runner.Run(context.Background(), streamer)).To(Succeed())
+
+		data, err := os.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(Equal("{\"name\":\"Paris\"}\n"))
+		Expect(streamer.hasEvent("mission_issue")).To(BeFalse())
+	})
+})
@@ -0,0 +1,65 @@
+package mission
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/zclconf/go-cty/cty"
+
+	"squadron/config"
+	"squadron/llm"
+)
+
+var _ = Describe("Dataset transforms", func() {
+	lead := func(email string) cty.Value {
+		return cty.ObjectVal(map[string]cty.Value{"email": cty.StringVal(email)})
+	}
+
+	It("applies transforms to inline items and to items appended at run time", func() {
+		mission := testMission("leads", []config.Task{testTask("collect", "Collect more leads")})
+		mission.Datasets = []config.Dataset{{
+			Name:  "leads",
+			Items: []cty.Value{lead("a@x.com"), lead("a@x.com"), lead("b@x.com")},
+			Transforms: []config.DatasetTransform{
+				{Kind: config.TransformDedupe, Fields: []string{"email"}},
+				{Kind: config.TransformLimit, Count: 4},
+			},
+		}}
+		cfg := buildTestConfig(mission, testAgent("worker"))
+
+		appendInput, _ := json.Marshal(map[string]any{
+			"name":   "leads",
+			"append": true,
+			"items":  []map[string]any{{"email": "b@x.com"}, {"email": "c@x.com"}},
+		})
+		provider := newMockProvider(
+			mockToolCall("set_dataset", appendInput),
+			cmdTaskComplete(),
+		)
+		runner, err := NewRunner(cfg, "", mission.Name, nil, WithProviderFactory(func() llm.Provider { return provider }))
+		Expect(err).NotTo(HaveOccurred())
+		defer runner.CloseStores()
+
+		Expect(runner.Run(context.Background(), newMockMissionStreamer())).To(Succeed())
+
+		emails := func() []string {
+			items, err := runner.stores.Datasets.GetItems(runner.datasetIDs["leads"], 0, 10)
+			Expect(err).NotTo(HaveOccurred())
+			var out []string
+			for _, item := range items {
+				out = append(out, item.GetAttr("email").AsString())
+			}
+			return out
+		}
+		Expect(emails()).To(Equal([]string{"a@x.com", "b@x.com", "c@x.com"}))
+
+		// The limit caps the whole dataset, not each batch
+		Expect(runner.AppendDataset("leads", []cty.Value{lead("d@x.com"), lead("e@x.com")})).To(Succeed())
+		Expect(emails()).To(Equal([]string{"a@x.com", "b@x.com", "c@x.com", "d@x.com"}))
+
+		Expect(runner.SetDataset("leads", []cty.Value{lead("z@x.com"), lead("z@x.com")})).To(Succeed())
+		Expect(emails()).To(Equal([]string{"z@x.com"}))
+	})
+})
@@ -1,24 +1,75 @@
 package mission
 
 import (
+	"bufio"
 	"encoding/json"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
+
+	"squadron/agent"
+	"squadron/store"
 )
 
-// DebugLogger captures mission execution events and LLM messages for debugging
+// DebugLogger captures mission execution events and LLM messages for
+// debugging. Everything goes under one directory per mission run:
+//
+//	events.log                                  events, one JSON object per line
+//	manifest.json                               index of the files below
+//	tasks/<task>/commander.md                   a task's conversations
+//	tasks/<task>/agent_<name>.md
+//	tasks/<task>/iterations/<n>/commander.md    a parallel iteration's
+//
+// Each conversation has a .turns.jsonl file of per-turn snapshots next to
+// its .md file. Parallel iterations write to their own directories, so
+// their logs never interleave.
 type DebugLogger struct {
-	dir        string
-	eventsFile *os.File
-	mu         sync.Mutex
-	enabled    bool
+	dir     string
+	enabled bool
+
+	// LogEvent hands lines to a single writer goroutine, so concurrent
+	// iterations never wait on each other to log
+	events       chan debugLine
+	done         chan struct{}
+	writerDone   chan struct{}
+	closeOnce    sync.Once
+	manifestMu   sync.Mutex
+	manifest     map[string]*DebugManifestEntry
+	manifestPath string
+}
+
+// debugLine is one events.log line, or, when flushed is set, a request to
+// flush everything before it to disk.
+type debugLine struct {
+	data    []byte
+	flushed chan struct{}
+}
+
+// debugEventBuffer is how many events can be queued before LogEvent waits
+// for the writer.
+const debugEventBuffer = 1024
+
+// DebugManifest is the index written to manifest.json: where each task,
+// iteration, and agent's logs are, relative to the debug directory.
+type DebugManifest struct {
+	Events  string               `json:"events"`
+	Entries []DebugManifestEntry `json:"entries"`
+}
 
-	// Track message files by entity (commander/agent)
-	messageFiles map[string]*os.File
+// DebugManifestEntry locates one conversation's logs.
+type DebugManifestEntry struct {
+	Task      string `json:"task"`
+	Iteration *int   `json:"iteration,omitempty"`
+	Entity    string `json:"entity"` // commander or agent
+	Name      string `json:"name,omitempty"`
+	Messages  string `json:"messages,omitempty"`
+	Turns     string `json:"turns,omitempty"`
 }
 
 // NewDebugLogger creates a new debug logger that writes to the specified directory
@@ -39,30 +90,112 @@ func NewDebugLogger(dir string) (*DebugLogger, error) {
 		return nil, fmt.Errorf("creating events file: %w", err)
 	}
 
-	return &DebugLogger{
+	d := &DebugLogger{
 		dir:          dir,
-		eventsFile:   eventsFile,
 		enabled:      true,
-		messageFiles: make(map[string]*os.File),
-	}, nil
+		events:       make(chan debugLine, debugEventBuffer),
+		done:         make(chan struct{}),
+		writerDone:   make(chan struct{}),
+		manifest:     make(map[string]*DebugManifestEntry),
+		manifestPath: filepath.Join(dir, "manifest.json"),
+	}
+	go d.writeEvents(eventsFile)
+	if err := d.writeManifest(); err != nil {
+		d.Close()
+		return nil, fmt.Errorf("creating manifest: %w", err)
+	}
+	return d, nil
+}
+
+// writeEvents writes queued events to f until the logger is closed, then
+// writes whatever is still queued and closes f.
+func (d *DebugLogger) writeEvents(f *os.File) {
+	defer close(d.writerDone)
+	defer f.Close()
+	w := bufio.NewWriter(f)
+	write := func(line debugLine) {
+		if line.flushed != nil {
+			w.Flush()
+			close(line.flushed)
+			return
+		}
+		w.Write(line.data)
+	}
+	for {
+		select {
+		case line := <-d.events:
+			write(line)
+			// Flush once the queue is empty, so the file stays current
+			// without a write per event under load
+			if len(d.events) == 0 {
+				w.Flush()
+			}
+		case <-d.done:
+			for {
+				select {
+				case line := <-d.events:
+					write(line)
+				default:
+					w.Flush()
+					return
+				}
+			}
+		}
+	}
 }
 
-// Close closes all open files
+// flushEvents waits until every event logged so far is on disk.
+func (d *DebugLogger) flushEvents() {
+	flushed := make(chan struct{})
+	select {
+	case d.events <- debugLine{flushed: flushed}:
+	case <-d.done:
+		return
+	}
+	select {
+	case <-flushed:
+	case <-d.writerDone:
+	}
+}
+
+// Close writes out pending events and the manifest.
 func (d *DebugLogger) Close() {
 	if !d.enabled {
 		return
 	}
+	d.closeOnce.Do(func() {
+		close(d.done)
+		<-d.writerDone
+		d.writeManifest()
+	})
+}
 
-	d.mu.Lock()
-	defer d.mu.Unlock()
-
-	if d.eventsFile != nil {
-		d.eventsFile.Close()
+// Upload copies every file in the debug directory to objects, under
+// prefix followed by the file's path relative to the directory.
+func (d *DebugLogger) Upload(objects store.ObjectStore, prefix string) error {
+	if !d.enabled {
+		return nil
 	}
-
-	for _, f := range d.messageFiles {
-		f.Close()
+	d.flushEvents()
+	if err := d.writeManifest(); err != nil {
+		return err
 	}
+
+	return filepath.WalkDir(d.dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(d.dir, path)
+		if err != nil {
+			return err
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		return objects.PutObject(prefix+filepath.ToSlash(rel), f)
+	})
 }
 
 // IsEnabled returns true if debug logging is enabled
@@ -81,12 +214,8 @@ func (d *DebugLogger) LogEvent(eventType string, data map[string]any) {
 		return
 	}
 
-	d.mu.Lock()
-	defer d.mu.Unlock()
-
-	timestamp := time.Now().Format(time.RFC3339Nano)
 	entry := map[string]any{
-		"timestamp": timestamp,
+		"timestamp": time.Now().Format(time.RFC3339Nano),
 		"event":     eventType,
 	}
 	for k, v := range data {
@@ -98,119 +227,104 @@ func (d *DebugLogger) LogEvent(eventType string, data map[string]any) {
 		return
 	}
 
-	d.eventsFile.WriteString(string(jsonBytes) + "\n")
+	select {
+	case d.events <- debugLine{data: append(jsonBytes, '\n')}:
+	case <-d.done:
+	}
 }
 
-// GetMessageFile returns a file path for logging LLM messages for a specific entity
-func (d *DebugLogger) GetMessageFile(entityType, entityName string) string {
+// GetMessageFile returns a file path for logging the LLM messages of a
+// commander or agent in scope, creating its directory.
+func (d *DebugLogger) GetMessageFile(scope agent.DebugScope, entityType, entityName string) string {
 	if !d.enabled {
 		return ""
 	}
-
-	// Sanitize name for filename
-	safeName := strings.ReplaceAll(entityName, "/", "_")
-	safeName = strings.ReplaceAll(safeName, "[", "_")
-	safeName = strings.ReplaceAll(safeName, "]", "")
-
-	filename := fmt.Sprintf("%s_%s.md", entityType, safeName)
-	return filepath.Join(d.dir, filename)
+	path := d.entityPath(scope, entityType, entityName, ".md")
+	d.register(scope, entityType, entityName, func(e *DebugManifestEntry) { e.Messages = path })
+	return d.abs(path)
 }
 
-// GetTurnLogFile returns a .jsonl file path for per-turn session snapshots.
-func (d *DebugLogger) GetTurnLogFile(entityType, entityName string) string {
+// GetTurnLogFile returns a .jsonl file path for per-turn session snapshots
+// of a commander or agent in scope, creating its directory.
+func (d *DebugLogger) GetTurnLogFile(scope agent.DebugScope, entityType, entityName string) string {
 	if !d.enabled {
 		return ""
 	}
-
-	safeName := strings.ReplaceAll(entityName, "/", "_")
-	safeName = strings.ReplaceAll(safeName, "[", "_")
-	safeName = strings.ReplaceAll(safeName, "]", "")
-
-	filename := fmt.Sprintf("turns_%s_%s.jsonl", entityType, safeName)
-	return filepath.Join(d.dir, filename)
+	path := d.entityPath(scope, entityType, entityName, ".turns.jsonl")
+	d.register(scope, entityType, entityName, func(e *DebugManifestEntry) { e.Turns = path })
+	return d.abs(path)
 }
 
-// WriteSystemPrompt writes a system prompt to an entity's message file
-func (d *DebugLogger) WriteSystemPrompt(entityType, entityName string, prompts []string) {
-	if !d.enabled {
-		return
+// entityPath returns the slash-separated path of an entity's log file,
+// relative to the debug directory.
+func (d *DebugLogger) entityPath(scope agent.DebugScope, entityType, entityName, ext string) string {
+	parts := []string{"tasks", safeDebugName(scope.Task)}
+	if scope.Iteration != nil {
+		parts = append(parts, "iterations", strconv.Itoa(*scope.Iteration))
 	}
-
-	filePath := d.GetMessageFile(entityType, entityName)
-	f, err := d.getOrCreateFile(filePath)
-	if err != nil {
-		return
-	}
-
-	timestamp := time.Now().Format(time.RFC3339)
-	f.WriteString(fmt.Sprintf("# %s: %s\n\n", entityType, entityName))
-	f.WriteString(fmt.Sprintf("*Started: %s*\n\n", timestamp))
-	f.WriteString("---\n\n")
-
-	for i, prompt := range prompts {
-		f.WriteString(fmt.Sprintf("## System Prompt %d\n\n", i+1))
-		f.WriteString("```\n")
-		f.WriteString(prompt)
-		f.WriteString("\n```\n\n")
+	file := entityType
+	if entityName != "" {
+		file += "_" + safeDebugName(entityName)
 	}
+	return strings.Join(append(parts, file+ext), "/")
+}
 
-	f.WriteString("---\n\n")
-	f.WriteString("# Conversation\n\n")
+// abs returns the path of rel on disk, creating its directory.
+func (d *DebugLogger) abs(rel string) string {
+	path := filepath.Join(d.dir, filepath.FromSlash(rel))
+	os.MkdirAll(filepath.Dir(path), 0755)
+	return path
 }
 
-// WriteMessage writes a message exchange to an entity's message file
-func (d *DebugLogger) WriteMessage(entityType, entityName string, role string, content string) {
-	if !d.enabled {
-		return
+// register records an entity's file in the manifest.
+func (d *DebugLogger) register(scope agent.DebugScope, entityType, entityName string, set func(*DebugManifestEntry)) {
+	key := d.entityPath(scope, entityType, entityName, "")
+	d.manifestMu.Lock()
+	defer d.manifestMu.Unlock()
+	e, ok := d.manifest[key]
+	if !ok {
+		e = &DebugManifestEntry{Task: scope.Task, Entity: entityType, Name: entityName}
+		if scope.Iteration != nil {
+			i := *scope.Iteration
+			e.Iteration = &i
+		}
+		d.manifest[key] = e
 	}
+	set(e)
+}
 
-	filePath := d.GetMessageFile(entityType, entityName)
-	f, err := d.getOrCreateFile(filePath)
-	if err != nil {
-		return
+// writeManifest writes manifest.json, with entries in path order.
+func (d *DebugLogger) writeManifest() error {
+	d.manifestMu.Lock()
+	keys := make([]string, 0, len(d.manifest))
+	for k := range d.manifest {
+		keys = append(keys, k)
 	}
-
-	timestamp := time.Now().Format("15:04:05")
-
-	switch role {
-	case "user":
-		f.WriteString(fmt.Sprintf("## [%s] User\n\n", timestamp))
-	case "assistant":
-		f.WriteString(fmt.Sprintf("## [%s] Assistant\n\n", timestamp))
-	case "tool_call":
-		f.WriteString(fmt.Sprintf("### [%s] Tool Call\n\n", timestamp))
-	case "tool_result":
-		f.WriteString(fmt.Sprintf("### [%s] Tool Result\n\n", timestamp))
-	default:
-		f.WriteString(fmt.Sprintf("## [%s] %s\n\n", timestamp, role))
+	sort.Strings(keys)
+	m := DebugManifest{Events: "events.log", Entries: make([]DebugManifestEntry, 0, len(keys))}
+	for _, k := range keys {
+		m.Entries = append(m.Entries, *d.manifest[k])
 	}
+	d.manifestMu.Unlock()
 
-	// Wrap in code block if it looks like structured content
-	if strings.HasPrefix(content, "{") || strings.HasPrefix(content, "<") {
-		f.WriteString("```\n")
-		f.WriteString(content)
-		f.WriteString("\n```\n\n")
-	} else {
-		f.WriteString(content)
-		f.WriteString("\n\n")
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
 	}
+	return os.WriteFile(d.manifestPath, data, 0644)
 }
 
-func (d *DebugLogger) getOrCreateFile(path string) (*os.File, error) {
-	d.mu.Lock()
-	defer d.mu.Unlock()
-
-	if f, ok := d.messageFiles[path]; ok {
-		return f, nil
-	}
-
-	f, err := os.Create(path)
-	if err != nil {
-		return nil, err
+// safeDebugName makes a task or agent name safe to use as a path element.
+func safeDebugName(name string) string {
+	safe := strings.ReplaceAll(name, "/", "_")
+	safe = strings.ReplaceAll(safe, "\\", "_")
+	safe = strings.ReplaceAll(safe, "[", "_")
+	safe = strings.ReplaceAll(safe, "]", "")
+	safe = strings.ReplaceAll(safe, "\"", "")
+	if safe == "" || safe == "." || safe == ".." {
+		safe = "_"
 	}
-
-	d.messageFiles[path] = f
-	return f, nil
+	return safe
 }
 
 // Event type constants
@@ -220,6 +334,10 @@ const (
 	EventTaskStarted         = "task_started"
 	EventTaskCompleted       = "task_completed"
 	EventTaskFailed          = "task_failed"
+	EventTaskSkipped         = "task_skipped"
+	EventTaskCancelled       = "task_cancelled"
+	EventMissionCancelled    = "mission_cancelled"
+	EventPartialSummaryFailed = "partial_summary_failed"
 	EventIterationStarted    = "iteration_started"
 	EventIterationCompleted  = "iteration_completed"
 	EventIterationFailed     = "iteration_failed"
@@ -0,0 +1,82 @@
+package mission
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"squadron/agent"
+)
+
+var _ = Describe("DebugLogger", func() {
+	var dir string
+	var logger *DebugLogger
+
+	BeforeEach(func() {
+		dir = filepath.Join(GinkgoT().TempDir(), "debug")
+		var err error
+		logger, err = NewDebugLogger(dir)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("writes every concurrently logged event as its own line", func() {
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				for j := 0; j < 50; j++ {
+					logger.LogEvent(EventIterationStarted, map[string]any{"task": "score", "iteration": i, "n": j})
+				}
+			}(i)
+		}
+		wg.Wait()
+		logger.Close()
+
+		f, err := os.Open(filepath.Join(dir, "events.log"))
+		Expect(err).NotTo(HaveOccurred())
+		defer f.Close()
+		lines := 0
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			var entry map[string]any
+			Expect(json.Unmarshal(scanner.Bytes(), &entry)).To(Succeed())
+			Expect(entry["event"]).To(Equal(EventIterationStarted))
+			lines++
+		}
+		Expect(lines).To(Equal(1000))
+
+		// Logging after Close is dropped rather than blocking
+		logger.LogEvent(EventTaskStarted, nil)
+	})
+
+	It("gives each task and parallel iteration its own directory and indexes them", func() {
+		three := 3
+		task := agent.DebugScope{Task: "score"}
+		iteration := agent.DebugScope{Task: "score", Iteration: &three}
+
+		Expect(logger.GetMessageFile(task, "commander", "")).To(Equal(filepath.Join(dir, "tasks", "score", "commander.md")))
+		Expect(logger.GetMessageFile(iteration, "commander", "")).To(Equal(filepath.Join(dir, "tasks", "score", "iterations", "3", "commander.md")))
+		turns := logger.GetTurnLogFile(iteration, "agent", "researcher")
+		Expect(turns).To(Equal(filepath.Join(dir, "tasks", "score", "iterations", "3", "agent_researcher.turns.jsonl")))
+		Expect(filepath.Join(dir, "tasks", "score", "iterations", "3")).To(BeADirectory())
+		logger.Close()
+
+		data, err := os.ReadFile(filepath.Join(dir, "manifest.json"))
+		Expect(err).NotTo(HaveOccurred())
+		var manifest DebugManifest
+		Expect(json.Unmarshal(data, &manifest)).To(Succeed())
+		Expect(manifest.Events).To(Equal("events.log"))
+		Expect(manifest.Entries).To(HaveLen(3))
+		Expect(manifest.Entries[0]).To(Equal(DebugManifestEntry{Task: "score", Entity: "commander", Messages: "tasks/score/commander.md"}))
+		Expect(manifest.Entries[1].Name).To(Equal("researcher"))
+		Expect(*manifest.Entries[1].Iteration).To(Equal(3))
+		Expect(manifest.Entries[1].Turns).To(Equal("tasks/score/iterations/3/agent_researcher.turns.jsonl"))
+		Expect(manifest.Entries[2].Messages).To(Equal("tasks/score/iterations/3/commander.md"))
+	})
+})
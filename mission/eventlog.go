@@ -0,0 +1,44 @@
+package mission
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"squadron/config"
+	"squadron/streamers"
+	"squadron/streamers/ndjson"
+)
+
+// openEventLogs opens the mission's event_log files and returns an NDJSON
+// handler for each, plus a func that closes the files.
+func openEventLogs(logs []config.EventLog) ([]streamers.MissionHandler, func(), error) {
+	var files []*os.File
+	closeAll := func() {
+		for _, f := range files {
+			f.Close()
+		}
+	}
+
+	var handlers []streamers.MissionHandler
+	for _, l := range logs {
+		flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+		if l.Append {
+			flags = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+		}
+		if dir := filepath.Dir(l.Path); dir != "." {
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				closeAll()
+				return nil, nil, fmt.Errorf("event_log '%s': %w", l.Name, err)
+			}
+		}
+		f, err := os.OpenFile(l.Path, flags, 0644)
+		if err != nil {
+			closeAll()
+			return nil, nil, fmt.Errorf("event_log '%s': %w", l.Name, err)
+		}
+		files = append(files, f)
+		handlers = append(handlers, ndjson.NewMissionHandler(f))
+	}
+	return handlers, closeAll, nil
+}
@@ -0,0 +1,58 @@
+package mission
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"squadron/config"
+	"squadron/llm"
+)
+
+// panickingStreamer breaks on the first task event.
+type panickingStreamer struct {
+	*mockMissionStreamer
+}
+
+func (s *panickingStreamer) TaskStarted(taskName, objective string) {
+	panic("broken streamer")
+}
+
+var _ = Describe("Event logs", func() {
+	It("writes every event to each event_log and survives a panicking streamer", func() {
+		dir := GinkgoT().TempDir()
+		logPath := filepath.Join(dir, "logs", "events.ndjson")
+		mission := testMission("logged", []config.Task{testTask("work", "Do the work")})
+		mission.Notifications = &config.Notifications{EventLogs: []config.EventLog{{Name: "audit", Path: logPath}}}
+		cfg := buildTestConfig(mission, testAgent("worker"))
+		cfg.Storage.Path = filepath.Join(dir, "store.db")
+
+		provider := newMockProvider(cmdTaskComplete())
+		runner, err := NewRunner(cfg, "", "logged", nil, WithProviderFactory(func() llm.Provider { return provider }))
+		Expect(err).NotTo(HaveOccurred())
+		defer runner.CloseStores()
+		streamer := &panickingStreamer{newMockMissionStreamer()}
+		Expect(runner.Run(context.Background(), streamer)).To(Succeed())
+
+		// The panicking streamer was dropped after mission_started
+		Expect(streamer.hasEvent("mission_started")).To(BeTrue())
+		Expect(streamer.hasEvent("mission_completed")).To(BeFalse())
+
+		data, err := os.ReadFile(logPath)
+		Expect(err).NotTo(HaveOccurred())
+		var types []string
+		for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+			var ev struct {
+				Type string `json:"type"`
+			}
+			Expect(json.Unmarshal([]byte(line), &ev)).To(Succeed())
+			types = append(types, ev.Type)
+		}
+		Expect(types).To(ContainElements("mission_started", "task_started", "task_completed", "mission_completed"))
+	})
+})
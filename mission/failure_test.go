@@ -0,0 +1,81 @@
+package mission
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/zclconf/go-cty/cty"
+
+	"squadron/config"
+	"squadron/failure"
+	"squadron/llm"
+)
+
+var _ = Describe("Failure codes", func() {
+	var cfg *config.Config
+
+	BeforeEach(func() {
+		cfg = buildTestConfig(testMission("failing", []config.Task{testTask("work", "Do the work")}), testAgent("worker"))
+		cfg.Models[0].Retry = &config.ModelRetry{MaxAttempts: 1}
+	})
+
+	// run runs the mission and returns its error and the failure code stored
+	// on its only task.
+	run := func(provider *mockProvider) (error, string) {
+		runner, err := NewRunner(cfg, "", cfg.Missions[0].Name, nil, WithProviderFactory(func() llm.Provider { return provider }))
+		Expect(err).NotTo(HaveOccurred())
+		defer runner.CloseStores()
+
+		runErr := runner.Run(context.Background(), newMockMissionStreamer())
+		tasks, err := runner.stores.Missions.GetTasksByMission(runner.MissionID())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tasks).To(HaveLen(1))
+		Expect(tasks[0].Status).To(Equal("failed"))
+		Expect(tasks[0].ErrorCode).NotTo(BeNil())
+		return runErr, *tasks[0].ErrorCode
+	}
+
+	It("stores marked_failed for a task the commander failed", func() {
+		err, code := run(newMockProvider(cmdTaskCompleteFail("source is offline")))
+		Expect(code).To(Equal(string(failure.CodeMarkedFailed)))
+		Expect(errors.Is(err, failure.ErrMarkedFailed)).To(BeTrue())
+		Expect(err).To(MatchError(ContainSubstring("source is offline")))
+	})
+
+	It("stores the provider's failure class when the commander's own call fails", func() {
+		err, code := run(newMockProvider(
+			mockResponse{Err: errors.New(`POST "https://api.anthropic.com/v1/messages": 429 Too Many Requests`)},
+		))
+		Expect(code).To(Equal(string(failure.CodeRateLimited)))
+		Expect(errors.Is(err, failure.ErrRateLimited)).To(BeTrue())
+	})
+
+	It("carries a failed agent's class through the commander", func() {
+		_, code := run(newMockProvider(
+			cmdCallAgent("worker", "Fetch the data"),
+			mockResponse{Err: errors.New(`POST "https://api.anthropic.com/v1/messages": 400 Bad Request: prompt is too long`)},
+			cmdTaskCompleteFail("the worker could not fetch the data"),
+		))
+		Expect(code).To(Equal(string(failure.CodeContextTooLong)))
+	})
+
+	It("does not retry an iteration whose failure would happen again", func() {
+		task := testTask("process", "Process item")
+		task.Iterator = &config.TaskIterator{Dataset: "items", Parallel: true, MaxRetries: 2}
+		mission := testMission("failing_iter", []config.Task{task})
+		mission.Datasets = []config.Dataset{{
+			Name:  "items",
+			Items: []cty.Value{cty.ObjectVal(map[string]cty.Value{"name": cty.StringVal("alpha")})},
+		}}
+		cfg = buildTestConfig(mission, testAgent("worker"))
+		cfg.Models[0].Retry = &config.ModelRetry{MaxAttempts: 1}
+
+		tooLong := mockResponse{Err: errors.New(`POST "https://api.openai.com/v1/chat": 400 Bad Request: context_length_exceeded`)}
+		provider := newMockProvider(tooLong, tooLong, tooLong)
+		_, code := run(provider)
+		Expect(code).To(Equal(string(failure.CodeContextTooLong)))
+		Expect(provider.callCount()).To(Equal(1))
+	})
+})
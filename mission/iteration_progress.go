@@ -0,0 +1,102 @@
+package mission
+
+import (
+	"sync"
+	"time"
+
+	"squadron/streamers"
+)
+
+// iterationProgress tallies the iterations of a parallel iterated task and
+// sends the running counts to streamers implementing
+// IterationProgressHandler. A nil *iterationProgress does nothing, so the
+// parallel runners can call it unconditionally.
+type iterationProgress struct {
+	handler     streamers.IterationProgressHandler
+	taskName    string
+	total       int
+	concurrency int
+
+	mu        sync.Mutex
+	completed int
+	failed    int
+	inFlight  int
+	finished  int           // iterations finished in this run
+	elapsed   time.Duration // summed over finished
+}
+
+// newIterationProgress returns a tracker for a task of total iterations,
+// done of which already completed in an earlier run, or nil when streamer
+// does not take progress events.
+func newIterationProgress(streamer streamers.MissionHandler, task string, total, done, concurrency int) *iterationProgress {
+	h, ok := streamer.(streamers.IterationProgressHandler)
+	if !ok {
+		return nil
+	}
+	p := &iterationProgress{
+		handler:     h,
+		taskName:    task,
+		total:       total,
+		concurrency: concurrency,
+		completed:   done,
+	}
+	p.emitLocked()
+	return p
+}
+
+// start counts an iteration as in flight and returns the func to call with
+// its final result, after any retries.
+func (p *iterationProgress) start() func(success bool) {
+	if p == nil {
+		return func(bool) {}
+	}
+	began := time.Now()
+	p.mu.Lock()
+	p.inFlight++
+	p.emitLocked()
+	p.mu.Unlock()
+
+	return func(success bool) {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		p.inFlight--
+		if success {
+			p.completed++
+		} else {
+			p.failed++
+		}
+		p.finished++
+		p.elapsed += time.Since(began)
+		p.emitLocked()
+	}
+}
+
+// emitLocked sends the counts. It runs under p.mu so handlers see them in
+// order.
+func (p *iterationProgress) emitLocked() {
+	data := streamers.IterationProgressData{
+		TaskName:  p.taskName,
+		Total:     p.total,
+		Completed: p.completed,
+		Failed:    p.failed,
+		InFlight:  p.inFlight,
+	}
+	if p.finished > 0 {
+		avg := p.elapsed / time.Duration(p.finished)
+		data.AvgDurationMs = avg.Milliseconds()
+		data.EtaMs = iterationETA(avg, p.total-p.completed-p.failed, p.concurrency).Milliseconds()
+	}
+	p.handler.IterationProgress(data)
+}
+
+// iterationETA estimates how long remaining iterations take when each takes
+// avg and up to concurrency run at once.
+func iterationETA(avg time.Duration, remaining, concurrency int) time.Duration {
+	if remaining <= 0 {
+		return 0
+	}
+	if concurrency < 1 || concurrency > remaining {
+		concurrency = remaining
+	}
+	return avg * time.Duration(remaining) / time.Duration(concurrency)
+}
@@ -0,0 +1,75 @@
+package mission
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/zclconf/go-cty/cty"
+
+	"squadron/config"
+	"squadron/llm"
+)
+
+var _ = Describe("iteration progress", func() {
+	// run processes four items in parallel, two at a time, failing gamma.
+	run := func() *mockMissionStreamer {
+		task := testTask("process", "Process item")
+		task.ObjectiveExpr = templateExpr("Process ${item.name}")
+		task.Iterator = &config.TaskIterator{Dataset: "items", Parallel: true, ConcurrencyLimit: 2, ContinueOnFailure: true}
+		mission := testMission("progress", []config.Task{task})
+		var items []cty.Value
+		for _, name := range []string{"alpha", "beta", "gamma", "delta"} {
+			items = append(items, cty.ObjectVal(map[string]cty.Value{"name": cty.StringVal(name)}))
+		}
+		mission.Datasets = []config.Dataset{{Name: "items", Items: items}}
+
+		provider := newMockProvider(withMatch(cmdTaskCompleteFail("gamma is unreachable"), matchLastUserContains("Process gamma")))
+		provider.addResponses(cmdTaskComplete(), cmdTaskComplete(), cmdTaskComplete())
+
+		runner, err := NewRunner(buildTestConfig(mission, testAgent("worker")), "", "progress", nil, WithProviderFactory(func() llm.Provider { return provider }))
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(runner.CloseStores)
+		streamer := newMockMissionStreamer()
+		Expect(runner.Run(context.Background(), streamer)).To(Succeed())
+		return streamer
+	}
+
+	It("counts iterations as they start and finish", func() {
+		streamer := run()
+
+		var progress []map[string]string
+		for _, e := range streamer.getEvents() {
+			if e.Type == "iteration_progress" {
+				progress = append(progress, e.Data)
+			}
+		}
+		// One event up front, then one per start and one per finish
+		Expect(progress).To(HaveLen(9))
+		Expect(progress[0]).To(HaveKeyWithValue("completed", "0"))
+		Expect(progress[0]).To(HaveKeyWithValue("in_flight", "0"))
+		for _, p := range progress {
+			Expect(p).To(HaveKeyWithValue("task", "process"))
+			Expect(p).To(HaveKeyWithValue("total", "4"))
+			Expect(p["in_flight"]).To(BeElementOf("0", "1", "2"))
+		}
+		last := progress[len(progress)-1]
+		Expect(last).To(HaveKeyWithValue("completed", "3"))
+		Expect(last).To(HaveKeyWithValue("failed", "1"))
+		Expect(last).To(HaveKeyWithValue("in_flight", "0"))
+		Expect(last).To(HaveKeyWithValue("eta_ms", "0"))
+	})
+
+	It("does nothing for a streamer without IterationProgress", func() {
+		Expect(newIterationProgress(nil, "process", 4, 0, 2)).To(BeNil())
+		var p *iterationProgress
+		Expect(func() { p.start()(true) }).NotTo(Panic())
+	})
+
+	It("estimates the time left from the average and the concurrency limit", func() {
+		Expect(iterationETA(time.Minute, 6, 3)).To(Equal(2 * time.Minute))
+		Expect(iterationETA(time.Minute, 2, 5)).To(Equal(time.Minute))
+		Expect(iterationETA(time.Minute, 0, 5)).To(BeZero())
+	})
+})
@@ -84,6 +84,9 @@ type KnowledgeStore interface {
 
 	// Aggregate performs an aggregate operation on iterations
 	Aggregate(taskName string, query AggregateQuery) AggregateResult
+
+	// Search ranks completed task summaries and outputs against keywords
+	Search(query SearchQuery) SearchResult
 }
 
 // PersistentKnowledgeStore reads task outputs from the MissionStore.
@@ -91,6 +94,10 @@ type KnowledgeStore interface {
 type PersistentKnowledgeStore struct {
 	MissionID string
 	Store     store.MissionStore
+	// IterationSummaries returns the per-iteration summaries of a task
+	// (index -> summary), for Search. Optional: they are only held in
+	// memory by the runner.
+	IterationSummaries func(taskName string) map[int]string
 }
 
 // GetTaskOutput loads a task's output from the store by task name
@@ -112,6 +119,10 @@ func (s *PersistentKnowledgeStore) GetTaskOutput(taskName string) (*TaskOutput,
 	}
 
 	if len(outputs) == 0 {
+		// An aggregate over an empty dataset still records a task output
+		if task.OutputJSON != nil && *task.OutputJSON != "" {
+			json.Unmarshal([]byte(*task.OutputJSON), &to.Output)
+		}
 		return to, true
 	}
 
@@ -137,6 +148,10 @@ func (s *PersistentKnowledgeStore) GetTaskOutput(taskName string) (*TaskOutput,
 			}
 			to.Iterations = append(to.Iterations, iter)
 		}
+		// An aggregate block stores its result as the task's own output
+		if task.OutputJSON != nil && *task.OutputJSON != "" {
+			json.Unmarshal([]byte(*task.OutputJSON), &to.Output)
+		}
 	} else {
 		// Non-iterated: single output
 		var outputMap map[string]any
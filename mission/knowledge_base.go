@@ -0,0 +1,241 @@
+package mission
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"squadron/aitools"
+	"squadron/config"
+	"squadron/llm"
+	"squadron/store"
+)
+
+// lexicalEmbeddingModel is recorded on entries embedded without an
+// embedding_model.
+const lexicalEmbeddingModel = "lexical"
+
+// lexicalDims is the width of the hashed bag-of-words vectors.
+const lexicalDims = 512
+
+// embedFunc embeds texts, one vector per input.
+type embedFunc func(ctx context.Context, texts []string) ([][]float32, error)
+
+// knowledgeBase is the mission's aitools.KnowledgeBase. Entries are kept in
+// memory for search; with the store backend every entry is also written to
+// the storage backend and reloaded on resume. Search is a linear cosine
+// scan, which is plenty for the few thousand entries a mission writes.
+type knowledgeBase struct {
+	missionID    string
+	model        string
+	embed        embedFunc
+	persist      store.KnowledgeEntryStore // nil for the memory backend
+	defaultLimit int
+
+	mu      sync.RWMutex
+	entries []knowledgeEntry
+	nextID  int
+}
+
+type knowledgeEntry struct {
+	id string
+	aitools.KnowledgeEntry
+	vector []float32
+}
+
+// buildKnowledgeBase creates the knowledge base for a mission run, or nil if
+// the mission has no knowledge block. With the store backend it reloads the
+// entries a previous attempt of the same mission ID saved.
+func buildKnowledgeBase(ctx context.Context, m *config.Mission, models []config.Model, stores *store.Bundle, missionID string) (aitools.KnowledgeBase, error) {
+	if m.Knowledge == nil {
+		return nil, nil
+	}
+	kb := &knowledgeBase{
+		missionID:    missionID,
+		model:        lexicalEmbeddingModel,
+		embed:        lexicalEmbed,
+		defaultLimit: m.Knowledge.GetSearchLimit(),
+	}
+
+	if key := m.Knowledge.EmbeddingModel; key != "" {
+		modelCfg, apiName, err := config.ResolveEmbeddingModel(key, models)
+		if err != nil {
+			return nil, err
+		}
+		embedder, err := newEmbedder(ctx, modelCfg)
+		if err != nil {
+			return nil, fmt.Errorf("embedding_model '%s': %w", key, err)
+		}
+		kb.model = apiName
+		kb.embed = func(ctx context.Context, texts []string) ([][]float32, error) {
+			return embedder.Embed(ctx, apiName, texts)
+		}
+	}
+
+	if m.Knowledge.GetBackend() == config.KnowledgeBackendStore {
+		kb.persist = stores.Knowledge
+		records, err := stores.Knowledge.GetKnowledgeEntries(missionID)
+		if err != nil {
+			return nil, fmt.Errorf("load knowledge entries: %w", err)
+		}
+		for _, r := range records {
+			// Entries embedded by a different model aren't comparable.
+			if r.Model != kb.model {
+				continue
+			}
+			kb.entries = append(kb.entries, knowledgeEntry{
+				id: r.ID,
+				KnowledgeEntry: aitools.KnowledgeEntry{
+					Content: r.Content,
+					Tags:    r.Tags,
+					Task:    r.TaskName,
+					Author:  r.Author,
+				},
+				vector: r.Embedding,
+			})
+		}
+	}
+	return kb, nil
+}
+
+// newEmbedder creates an embedding client for a model config.
+func newEmbedder(ctx context.Context, m *config.Model) (llm.Embedder, error) {
+	if m.Provider.RequiresAPIKey() && m.APIKey == "" {
+		return nil, fmt.Errorf("API key not set for model '%s'", m.Name)
+	}
+	switch m.Provider {
+	case config.ProviderOpenAI:
+		return llm.NewOpenAIProvider(m.APIKey, m.BaseURL), nil
+	case config.ProviderGemini:
+		return llm.NewGeminiProvider(ctx, m.APIKey, m.BaseURL)
+	case config.ProviderOllama, config.ProviderOpenAICompatible:
+		return llm.NewOpenAICompatibleProvider(m.BaseURL, m.APIKey), nil
+	default:
+		return nil, fmt.Errorf("provider '%s' has no embeddings API", m.Provider)
+	}
+}
+
+func (kb *knowledgeBase) DefaultLimit() int { return kb.defaultLimit }
+
+func (kb *knowledgeBase) Put(ctx context.Context, entry aitools.KnowledgeEntry) (string, error) {
+	vectors, err := kb.embed(ctx, []string{entry.Content})
+	if err != nil {
+		return "", fmt.Errorf("embedding entry: %w", err)
+	}
+	vector := vectors[0]
+
+	kb.mu.Lock()
+	defer kb.mu.Unlock()
+	var id string
+	if kb.persist != nil {
+		id, err = kb.persist.AddKnowledgeEntry(store.KnowledgeEntryRecord{
+			MissionID: kb.missionID,
+			TaskName:  entry.Task,
+			Author:    entry.Author,
+			Content:   entry.Content,
+			Tags:      entry.Tags,
+			Model:     kb.model,
+			Embedding: vector,
+		})
+		if err != nil {
+			return "", fmt.Errorf("saving entry: %w", err)
+		}
+	} else {
+		kb.nextID++
+		id = fmt.Sprintf("k%d", kb.nextID)
+	}
+	kb.entries = append(kb.entries, knowledgeEntry{id: id, KnowledgeEntry: entry, vector: vector})
+	return id, nil
+}
+
+func (kb *knowledgeBase) Search(ctx context.Context, query string, limit int, task string) ([]aitools.KnowledgeHit, error) {
+	vectors, err := kb.embed(ctx, []string{query})
+	if err != nil {
+		return nil, fmt.Errorf("embedding query: %w", err)
+	}
+	q := vectors[0]
+
+	kb.mu.RLock()
+	var hits []aitools.KnowledgeHit
+	for _, e := range kb.entries {
+		if task != "" && e.Task != task {
+			continue
+		}
+		score := cosineSimilarity(q, e.vector)
+		if score <= 0 {
+			continue
+		}
+		hits = append(hits, aitools.KnowledgeHit{ID: e.id, KnowledgeEntry: e.KnowledgeEntry, Score: score})
+	}
+	kb.mu.RUnlock()
+
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].Score > hits[j].Score })
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits, nil
+}
+
+// cosineSimilarity returns 0 for vectors of different lengths or zero norm.
+func cosineSimilarity(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
+
+// lexicalStopwords are left out of lexical embeddings so entries don't match
+// on filler words alone.
+var lexicalStopwords = map[string]bool{
+	"an": true, "and": true, "are": true, "as": true, "at": true, "be": true, "by": true,
+	"did": true, "do": true, "does": true, "for": true, "from": true, "has": true, "have": true,
+	"how": true, "in": true, "is": true, "it": true, "its": true, "of": true, "on": true,
+	"or": true, "that": true, "the": true, "this": true, "to": true, "was": true, "were": true,
+	"what": true, "when": true, "where": true, "which": true, "who": true, "why": true, "with": true,
+}
+
+// lexicalEmbed is the built-in embedding used without an embedding_model:
+// lowercased words hashed into a fixed-width vector, weighted by log term
+// frequency. It matches shared vocabulary, not paraphrases.
+func lexicalEmbed(_ context.Context, texts []string) ([][]float32, error) {
+	out := make([][]float32, len(texts))
+	for i, text := range texts {
+		counts := make(map[string]int)
+		for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		}) {
+			if len(word) > 1 && !lexicalStopwords[word] {
+				counts[word]++
+			}
+		}
+		vec := make([]float32, lexicalDims)
+		for word, n := range counts {
+			h := fnv.New32a()
+			h.Write([]byte(word))
+			sum := h.Sum32()
+			weight := float32(1 + math.Log(float64(n)))
+			// The top bit picks the sign so collisions cancel out on average
+			// instead of always inflating similarity.
+			if sum&(1<<31) != 0 {
+				weight = -weight
+			}
+			vec[sum%lexicalDims] += weight
+		}
+		out[i] = vec
+	}
+	return out, nil
+}
@@ -0,0 +1,99 @@
+package mission
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"squadron/aitools"
+	"squadron/config"
+	"squadron/llm"
+	"squadron/store"
+)
+
+var _ = Describe("Knowledge base", func() {
+	ctx := context.Background()
+
+	It("ranks entries by similarity and filters by task", func() {
+		m := testMission("m", nil)
+		m.Knowledge = &config.MissionKnowledge{SearchLimit: 2}
+		kb, err := buildKnowledgeBase(ctx, &m, nil, nil, "mission-1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(kb.DefaultLimit()).To(Equal(2))
+
+		research := aitools.ScopeKnowledgeBase(kb, "research", "scout")
+		_, err = research.Put(ctx, aitools.KnowledgeEntry{Content: "Acme raised subscription prices by 10 percent in March"})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = research.Put(ctx, aitools.KnowledgeEntry{Content: "The Berlin office moved to a new building"})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = aitools.ScopeKnowledgeBase(kb, "pricing", "commander").Put(ctx, aitools.KnowledgeEntry{Content: "Acme prices are now above the market average", Tags: []string{"pricing"}})
+		Expect(err).NotTo(HaveOccurred())
+
+		hits, err := kb.Search(ctx, "What happened to Acme prices?", 5, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(hits).To(HaveLen(2))
+		for _, h := range hits {
+			Expect(h.Content).To(ContainSubstring("Acme"))
+		}
+
+		hits, err = kb.Search(ctx, "Acme prices", 5, "research")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(hits).To(HaveLen(1))
+		Expect(hits[0].Task).To(Equal("research"))
+		Expect(hits[0].Author).To(Equal("scout"))
+	})
+
+	It("reloads persisted entries with the store backend", func() {
+		bundle, err := store.NewSQLiteBundle(filepath.Join(GinkgoT().TempDir(), "store.db"))
+		Expect(err).NotTo(HaveOccurred())
+		defer bundle.Close()
+		missionID, err := bundle.Missions.CreateMission("m", "{}", "{}")
+		Expect(err).NotTo(HaveOccurred())
+
+		m := testMission("m", nil)
+		m.Knowledge = &config.MissionKnowledge{Backend: config.KnowledgeBackendStore}
+		kb, err := buildKnowledgeBase(ctx, &m, nil, bundle, missionID)
+		Expect(err).NotTo(HaveOccurred())
+		id, err := aitools.ScopeKnowledgeBase(kb, "research", "scout").Put(ctx, aitools.KnowledgeEntry{Content: "The deploy key rotates every 30 days"})
+		Expect(err).NotTo(HaveOccurred())
+
+		resumed, err := buildKnowledgeBase(ctx, &m, nil, bundle, missionID)
+		Expect(err).NotTo(HaveOccurred())
+		hits, err := resumed.Search(ctx, "how often does the deploy key rotate", 0, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(hits).To(HaveLen(1))
+		Expect(hits[0].ID).To(Equal(id))
+		Expect(hits[0].Task).To(Equal("research"))
+	})
+
+	It("lets a later task's commander find what an earlier task saved", func() {
+		save := testTask("save", "Save a finding")
+		recall := testTask("recall", "Recall the finding")
+		recall.DependsOn = []string{"save"}
+		m := testMission("kb", []config.Task{save, recall})
+		m.Knowledge = &config.MissionKnowledge{}
+		cfg := buildTestConfig(m, testAgent("worker"))
+
+		provider := newMockProvider(
+			mockToolCall("memory_put", json.RawMessage(`{"content":"The staging database password rotates on Fridays"}`)),
+			cmdTaskComplete(),
+			mockToolCall("memory_search", json.RawMessage(`{"query":"when does the staging password rotate"}`)),
+			cmdTaskComplete(),
+		)
+		runner, err := NewRunner(cfg, "", "kb", nil, WithProviderFactory(func() llm.Provider { return provider }))
+		Expect(err).NotTo(HaveOccurred())
+		defer runner.CloseStores()
+
+		Expect(runner.Run(ctx, newMockMissionStreamer())).To(Succeed())
+
+		calls := provider.getCalls()
+		last, err := json.Marshal(calls[len(calls)-1].Messages)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(strings.Contains(string(last), "rotates on Fridays")).To(BeTrue())
+		Expect(strings.Contains(string(last), `\"task\": \"save\"`)).To(BeTrue())
+	})
+})
@@ -0,0 +1,297 @@
+package mission
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Kinds of text a search hit can come from
+const (
+	SearchKindSummary          = "summary"
+	SearchKindIterationSummary = "iteration_summary"
+	SearchKindOutput           = "output"
+	SearchKindIterationOutput  = "iteration_output"
+)
+
+// DefaultSearchLimit is how many hits a search returns when no limit is set.
+const DefaultSearchLimit = 10
+
+// SearchQuery is a keyword search over the mission's completed task results
+type SearchQuery struct {
+	Text  string   `json:"text"`
+	Tasks []string `json:"tasks,omitempty"` // Restrict to these tasks (all completed tasks when empty)
+	Limit int      `json:"limit,omitempty"`
+}
+
+// SearchHit is one matching summary or output, best match first
+type SearchHit struct {
+	TaskName string  `json:"task_name"`
+	Kind     string  `json:"kind"`
+	Index    *int    `json:"index,omitempty"` // Iteration index, for iteration hits
+	ItemID   string  `json:"item_id,omitempty"`
+	Score    float64 `json:"score"`
+	Snippet  string  `json:"snippet"`
+}
+
+// SearchResult is the ranked result of a search
+type SearchResult struct {
+	TotalMatches int         `json:"total_matches"`
+	Hits         []SearchHit `json:"hits"`
+}
+
+// searchDoc is one searchable piece of text: a summary or an output
+type searchDoc struct {
+	hit    SearchHit
+	text   string
+	tokens []string
+}
+
+// Search ranks the summaries, iteration summaries and structured outputs of
+// the mission's completed tasks against the query's keywords (BM25), and
+// returns the best hits with a snippet around the match.
+func (s *PersistentKnowledgeStore) Search(query SearchQuery) SearchResult {
+	terms := uniqueTerms(tokenize(query.Text))
+	if len(terms) == 0 {
+		return SearchResult{}
+	}
+	limit := query.Limit
+	if limit <= 0 {
+		limit = DefaultSearchLimit
+	}
+	return rankSearchDocs(s.searchDocs(query.Tasks), terms, strings.ToLower(strings.TrimSpace(query.Text)), limit)
+}
+
+// searchDocs collects the searchable text of completed tasks
+func (s *PersistentKnowledgeStore) searchDocs(only []string) []searchDoc {
+	tasks, err := s.Store.GetTasksByMission(s.MissionID)
+	if err != nil {
+		return nil
+	}
+	wanted := make(map[string]bool, len(only))
+	for _, name := range only {
+		wanted[name] = true
+	}
+
+	var docs []searchDoc
+	add := func(hit SearchHit, text string) {
+		if text = strings.TrimSpace(text); text != "" {
+			docs = append(docs, searchDoc{hit: hit, text: text, tokens: tokenize(text)})
+		}
+	}
+	for _, task := range tasks {
+		if task.Status != "completed" || (len(wanted) > 0 && !wanted[task.TaskName]) {
+			continue
+		}
+		if task.Summary != nil {
+			add(SearchHit{TaskName: task.TaskName, Kind: SearchKindSummary}, *task.Summary)
+		}
+		if s.IterationSummaries != nil {
+			summaries := s.IterationSummaries(task.TaskName)
+			indices := make([]int, 0, len(summaries))
+			for idx := range summaries {
+				indices = append(indices, idx)
+			}
+			sort.Ints(indices)
+			for _, idx := range indices {
+				idx := idx
+				add(SearchHit{TaskName: task.TaskName, Kind: SearchKindIterationSummary, Index: &idx}, summaries[idx])
+			}
+		}
+
+		output, ok := s.GetTaskOutput(task.TaskName)
+		if !ok {
+			continue
+		}
+		if len(output.Output) > 0 {
+			add(SearchHit{TaskName: task.TaskName, Kind: SearchKindOutput}, flattenOutput(output.Output))
+		}
+		for _, iter := range output.Iterations {
+			idx := iter.Index
+			add(SearchHit{TaskName: task.TaskName, Kind: SearchKindIterationOutput, Index: &idx, ItemID: iter.ItemID}, flattenOutput(iter.Output))
+		}
+	}
+	return docs
+}
+
+// BM25 parameters
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// rankSearchDocs scores docs against the query terms with BM25. Docs that
+// contain the whole query as a phrase rank above those that only share
+// words with it.
+func rankSearchDocs(docs []searchDoc, terms []string, phrase string, limit int) SearchResult {
+	if len(docs) == 0 {
+		return SearchResult{}
+	}
+
+	docFreq := make(map[string]int, len(terms))
+	totalLen := 0
+	for _, d := range docs {
+		totalLen += len(d.tokens)
+		seen := make(map[string]bool, len(terms))
+		for _, tok := range d.tokens {
+			seen[tok] = true
+		}
+		for _, term := range terms {
+			if seen[term] {
+				docFreq[term]++
+			}
+		}
+	}
+	avgLen := float64(totalLen) / float64(len(docs))
+	n := float64(len(docs))
+
+	var hits []SearchHit
+	for _, d := range docs {
+		freq := make(map[string]int, len(terms))
+		for _, tok := range d.tokens {
+			freq[tok]++
+		}
+		score := 0.0
+		for _, term := range terms {
+			tf := float64(freq[term])
+			if tf == 0 {
+				continue
+			}
+			df := float64(docFreq[term])
+			idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+			norm := tf + bm25K1*(1-bm25B+bm25B*float64(len(d.tokens))/avgLen)
+			score += idf * tf * (bm25K1 + 1) / norm
+		}
+		if score == 0 {
+			continue
+		}
+		lower := strings.ToLower(d.text)
+		if len(terms) > 1 && strings.Contains(lower, phrase) {
+			score *= 1.5
+		}
+		hit := d.hit
+		hit.Score = math.Round(score*1000) / 1000
+		hit.Snippet = snippet(d.text, terms, phrase)
+		hits = append(hits, hit)
+	}
+
+	// Stable, so equal scores keep task order
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].Score > hits[j].Score })
+	result := SearchResult{TotalMatches: len(hits)}
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+	result.Hits = hits
+	return result
+}
+
+// snippetRadius is how many characters of context a snippet keeps on each
+// side of the match.
+const snippetRadius = 100
+
+// snippet cuts the text around the first occurrence of the phrase, or else
+// of the first query term found in it.
+func snippet(text string, terms []string, phrase string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	lower := strings.ToLower(text)
+
+	pos := strings.Index(lower, phrase)
+	if pos < 0 || phrase == "" {
+		pos = -1
+		for _, term := range terms {
+			if p := indexWord(lower, term); p >= 0 && (pos < 0 || p < pos) {
+				pos = p
+			}
+		}
+	}
+	if pos < 0 {
+		pos = 0
+	}
+
+	start, end := pos-snippetRadius, pos+snippetRadius
+	prefix, suffix := "...", "..."
+	if start <= 0 {
+		start, prefix = 0, ""
+	}
+	if end >= len(text) {
+		end, suffix = len(text), ""
+	}
+	// Don't cut a multi-byte character in half
+	for start > 0 && !isRuneStart(text[start]) {
+		start--
+	}
+	for end < len(text) && !isRuneStart(text[end]) {
+		end++
+	}
+	return prefix + text[start:end] + suffix
+}
+
+// indexWord finds term in lower as a whole word, returning -1 if absent
+func indexWord(lower, term string) int {
+	for from := 0; from < len(lower); {
+		p := strings.Index(lower[from:], term)
+		if p < 0 {
+			return -1
+		}
+		p += from
+		end := p + len(term)
+		if (p == 0 || !isWordByte(lower[p-1])) && (end == len(lower) || !isWordByte(lower[end])) {
+			return p
+		}
+		from = p + 1
+	}
+	return -1
+}
+
+func isWordByte(b byte) bool {
+	return b >= 0x80 || b == '_' || (b >= '0' && b <= '9') || (b >= 'a' && b <= 'z')
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
+
+// tokenize lowercases text and splits it into words
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	})
+}
+
+// uniqueTerms drops repeated query words, keeping their order
+func uniqueTerms(tokens []string) []string {
+	seen := make(map[string]bool, len(tokens))
+	var out []string
+	for _, tok := range tokens {
+		if !seen[tok] {
+			seen[tok] = true
+			out = append(out, tok)
+		}
+	}
+	return out
+}
+
+// flattenOutput renders a structured output as "field: value" lines, in
+// field order, so both field names and values are searchable.
+func flattenOutput(output map[string]any) string {
+	keys := make([]string, 0, len(output))
+	for k := range output {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var sb strings.Builder
+	for _, k := range keys {
+		switch v := output[k].(type) {
+		case string:
+			fmt.Fprintf(&sb, "%s: %s\n", k, v)
+		default:
+			b, _ := json.Marshal(v)
+			fmt.Fprintf(&sb, "%s: %s\n", k, b)
+		}
+	}
+	return sb.String()
+}
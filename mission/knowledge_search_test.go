@@ -0,0 +1,139 @@
+package mission
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"squadron/store"
+)
+
+// setupSearchStore creates a store with a summarized task, an iterated task,
+// and a task that has not completed.
+func setupSearchStore() *mockMissionStore {
+	ms := newMockStore()
+
+	ms.addTask("m1", "t1", "research", "completed")
+	ms.tasks[ms.key("m1", "research")].Summary = strPtr("Researched three companies. Acme closed a Series B funding round led by Sequoia.")
+	ms.addOutput("t1", store.TaskOutputRow{
+		ID:         "out-1",
+		OutputJSON: outputJSON(map[string]any{"companies": []any{"Acme", "Globex", "Initech"}}),
+		CreatedAt:  time.Now(),
+	})
+
+	ms.addTask("m1", "t2", "score", "completed")
+	ms.tasks[ms.key("m1", "score")].Summary = strPtr("Scored every lead.")
+	ds := "leads"
+	for i, note := range []string{"Initech is hiring engineers", "Globex was acquired by Hooli"} {
+		idx := i
+		itemID := []string{"initech", "globex"}[i]
+		ms.addOutput("t2", store.TaskOutputRow{
+			ID:           itemID,
+			DatasetName:  &ds,
+			DatasetIndex: &idx,
+			ItemID:       &itemID,
+			OutputJSON:   outputJSON(map[string]any{"note": note, "score": float64(i + 1)}),
+			CreatedAt:    time.Now(),
+		})
+	}
+
+	ms.addTask("m1", "t3", "pending", "running")
+	ms.tasks[ms.key("m1", "pending")].Summary = strPtr("Acme funding is still being checked")
+	return ms
+}
+
+func TestSearch_RanksSummariesAndOutputs(t *testing.T) {
+	ks := &PersistentKnowledgeStore{MissionID: "m1", Store: setupSearchStore()}
+
+	result := ks.Search(SearchQuery{Text: "Acme funding"})
+	if result.TotalMatches != 2 {
+		t.Fatalf("TotalMatches = %d, want 2 (summary and output of research): %+v", result.TotalMatches, result.Hits)
+	}
+	top := result.Hits[0]
+	if top.TaskName != "research" || top.Kind != SearchKindSummary {
+		t.Errorf("top hit = %s/%s, want research/summary", top.TaskName, top.Kind)
+	}
+	if !strings.Contains(top.Snippet, "Acme closed a Series B funding round") {
+		t.Errorf("snippet %q should contain the match", top.Snippet)
+	}
+	if result.Hits[1].Kind != SearchKindOutput || result.Hits[1].Score >= top.Score {
+		t.Errorf("second hit = %+v, want the lower-scored research output", result.Hits[1])
+	}
+}
+
+func TestSearch_IterationOutputs(t *testing.T) {
+	ks := &PersistentKnowledgeStore{MissionID: "m1", Store: setupSearchStore()}
+
+	result := ks.Search(SearchQuery{Text: "acquired"})
+	if len(result.Hits) != 1 {
+		t.Fatalf("got %d hits, want 1: %+v", len(result.Hits), result.Hits)
+	}
+	hit := result.Hits[0]
+	if hit.TaskName != "score" || hit.Kind != SearchKindIterationOutput || hit.Index == nil || *hit.Index != 1 || hit.ItemID != "globex" {
+		t.Errorf("hit = %+v, want score iteration 1 (globex)", hit)
+	}
+	if !strings.Contains(hit.Snippet, "note: Globex was acquired by Hooli") {
+		t.Errorf("snippet %q should show the output field", hit.Snippet)
+	}
+}
+
+func TestSearch_IterationSummaries(t *testing.T) {
+	ks := &PersistentKnowledgeStore{
+		MissionID: "m1",
+		Store:     setupSearchStore(),
+		IterationSummaries: func(taskName string) map[int]string {
+			if taskName != "score" {
+				return nil
+			}
+			return map[int]string{0: "Initech looks promising: strong hiring signal"}
+		},
+	}
+
+	result := ks.Search(SearchQuery{Text: "hiring"})
+	kinds := map[string]bool{}
+	for _, h := range result.Hits {
+		kinds[h.Kind] = true
+	}
+	if !kinds[SearchKindIterationSummary] || !kinds[SearchKindIterationOutput] {
+		t.Errorf("hits = %+v, want an iteration summary and an iteration output", result.Hits)
+	}
+}
+
+func TestSearch_TasksAndLimit(t *testing.T) {
+	ks := &PersistentKnowledgeStore{MissionID: "m1", Store: setupSearchStore()}
+
+	if result := ks.Search(SearchQuery{Text: "Globex", Tasks: []string{"score"}}); len(result.Hits) != 1 || result.Hits[0].TaskName != "score" {
+		t.Errorf("restricted search = %+v, want only the score hit", result.Hits)
+	}
+
+	result := ks.Search(SearchQuery{Text: "Globex", Limit: 1})
+	if result.TotalMatches != 2 || len(result.Hits) != 1 {
+		t.Errorf("TotalMatches = %d with %d hits, want 2 with 1", result.TotalMatches, len(result.Hits))
+	}
+}
+
+func TestSearch_SkipsIncompleteTasksAndEmptyQueries(t *testing.T) {
+	ks := &PersistentKnowledgeStore{MissionID: "m1", Store: setupSearchStore()}
+
+	for _, h := range ks.Search(SearchQuery{Text: "checked"}).Hits {
+		t.Errorf("unexpected hit from a running task: %+v", h)
+	}
+	if result := ks.Search(SearchQuery{Text: "  ...  "}); result.TotalMatches != 0 {
+		t.Errorf("empty query matched %d results", result.TotalMatches)
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	text := strings.Repeat("lorem ipsum ", 30) + "the Acme deal closed" + strings.Repeat(" dolor sit", 30)
+	got := snippet(text, []string{"acme"}, "acme")
+	if !strings.HasPrefix(got, "...") || !strings.HasSuffix(got, "...") || !strings.Contains(got, "the Acme deal closed") {
+		t.Errorf("snippet = %q", got)
+	}
+
+	if got := snippet("Acmeville and then acme", []string{"acme"}, "acme"); got != "Acmeville and then acme" {
+		t.Errorf("short text should be returned whole, got %q", got)
+	}
+	if p := indexWord("acmeville and then acme", "acme"); p != 19 {
+		t.Errorf("indexWord = %d, want the whole-word match at 19", p)
+	}
+}
@@ -3,6 +3,7 @@ package mission
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 	"testing"
 	"time"
 
@@ -10,7 +11,7 @@ import (
 )
 
 // mockMissionStore implements store.MissionStore for testing.
-// Only GetTaskByName and GetTaskOutputs are used by PersistentKnowledgeStore.
+// Only GetTaskByName, GetTaskOutputs and GetTasksByMission are used by PersistentKnowledgeStore.
 type mockMissionStore struct {
 	tasks   map[string]*store.MissionTask           // key: missionID + "|" + taskName
 	outputs map[string][]store.TaskOutputRow         // key: taskID
@@ -59,6 +60,9 @@ func (m *mockMissionStore) CreateMission(name, inputsJSON, configJSON string) (s
 	return "", nil
 }
 func (m *mockMissionStore) UpdateMissionStatus(id, status string) error { return nil }
+func (m *mockMissionStore) SupersedeTaskOutputs(taskID string, datasetIndices []int) error {
+	return nil
+}
 func (m *mockMissionStore) UpdateMissionStatusCAS(id, expectedOld, newStatus string) (bool, error) { return true, nil }
 func (m *mockMissionStore) CreateTask(missionID, taskName, configJSON string) (string, error) {
 	return "", nil
@@ -70,9 +74,22 @@ func (m *mockMissionStore) UpdateTaskStatusCAS(id, expectedOld, newStatus string
 	return true, nil
 }
 func (m *mockMissionStore) UpdateTaskSummary(id, summary string) error { return nil }
+func (m *mockMissionStore) SetTaskErrorCode(id, code string) error {
+	return nil
+}
+func (m *mockMissionStore) SetTaskConfidence(id string, confidence float64) error {
+	return nil
+}
 func (m *mockMissionStore) GetTask(id string) (*store.MissionTask, error) { return nil, nil }
 func (m *mockMissionStore) GetTasksByMission(missionID string) ([]store.MissionTask, error) {
-	return nil, nil
+	var tasks []store.MissionTask
+	for _, t := range m.tasks {
+		if t.MissionID == missionID {
+			tasks = append(tasks, *t)
+		}
+	}
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].ID < tasks[j].ID })
+	return tasks, nil
 }
 func (m *mockMissionStore) GetMission(id string) (*store.MissionRecord, error) { return nil, nil }
 func (m *mockMissionStore) ListMissions(limit, offset int) ([]store.MissionRecord, int, error) {
@@ -0,0 +1,59 @@
+package mission
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"squadron/config"
+	"squadron/llm"
+)
+
+var _ = Describe("Mission concurrency limits", func() {
+	// twoIndependent builds a mission whose two tasks could run side by side.
+	twoIndependent := func(configure func(*config.Mission)) *config.Config {
+		mission := testMission("wide", []config.Task{
+			testTask("left", "Do the left thing"),
+			testTask("right", "Do the right thing"),
+		})
+		configure(&mission)
+		return buildTestConfig(mission, testAgent("worker"))
+	}
+
+	// expectOneCallAtATime starts the mission with a first LLM call that
+	// never returns and checks no second call starts alongside it.
+	expectOneCallAtATime := func(cfg *config.Config) {
+		provider := newMockProvider(hangingCall())
+		runner, err := NewRunner(cfg, "", "wide", nil, WithProviderFactory(func() llm.Provider { return provider }))
+		Expect(err).NotTo(HaveOccurred())
+		defer runner.CloseStores()
+		done := make(chan error, 1)
+		go func() { done <- runner.Run(context.Background(), newMockMissionStreamer()) }()
+
+		Eventually(provider.callCount).Should(Equal(1))
+		Consistently(provider.callCount, 200*time.Millisecond).Should(Equal(1))
+
+		runner.Pause()
+		Eventually(done).Should(Receive(MatchError(ErrMissionPaused)))
+	}
+
+	It("holds back ready tasks beyond max_parallel_tasks", func() {
+		expectOneCallAtATime(twoIndependent(func(m *config.Mission) { m.MaxParallelTasks = 1 }))
+	})
+
+	It("runs every task once the earlier ones finish", func() {
+		cfg := twoIndependent(func(m *config.Mission) { m.MaxParallelTasks = 1 })
+		provider := newMockProvider(cmdTaskComplete(), cmdTaskComplete())
+		runner, err := NewRunner(cfg, "", "wide", nil, WithProviderFactory(func() llm.Provider { return provider }))
+		Expect(err).NotTo(HaveOccurred())
+		defer runner.CloseStores()
+		Expect(runner.Run(context.Background(), newMockMissionStreamer())).To(Succeed())
+		Expect(provider.callCount()).To(Equal(2))
+	})
+
+	It("queues LLM calls beyond max_parallel_llm_calls", func() {
+		expectOneCallAtATime(twoIndependent(func(m *config.Mission) { m.MaxParallelLLMCalls = 1 }))
+	})
+})
@@ -116,7 +116,7 @@ func buildMemoryStore(mission *config.Mission, memories []config.Memory, packets
 	}
 
 	for _, name := range mission.Memories {
-		if name == config.MemorySlotName || name == config.ScratchpadSlotName {
+		if name == config.MemorySlotName || name == config.ScratchpadSlotName || name == config.WorkspaceSlotName {
 			return nil, fmt.Errorf("shared memory %q uses a reserved slot name", name)
 		}
 		mem, ok := memByName[name]
@@ -291,19 +291,31 @@ func (s *missionMemoryStore) MemoryInfos() []aitools.MemoryInfo {
 	return infos
 }
 
-// SweepExpiredScratchpads deletes any per-run scratchpad directory whose
-// sidecar (.squadron-run.json) records a created_at older than its
-// cleanup_days. Directories without a sidecar, or with cleanup_days == 0,
-// are left alone.
+// SweepExpiredScratchpads deletes any per-run scratchpad or workspace
+// directory whose sidecar (.squadron-run.json) records a created_at older
+// than its cleanup_days. Directories without a sidecar, or with
+// cleanup_days == 0, are left alone.
 //
-// Walks `<squadron_home>/scratchpads/*/*` and considers every per-run
-// directory — no per-mission filtering, so callers don't need to know which
-// missions exist.
+// Walks `<squadron_home>/scratchpads/*/*` and `<squadron_home>/workspaces/*/*`
+// and considers every per-run directory — no per-mission filtering, so
+// callers don't need to know which missions exist.
 func SweepExpiredScratchpads() (removed []string, err error) {
-	root, err := ScratchpadsRoot()
-	if err != nil {
-		return nil, err
+	for _, rootFn := range []func() (string, error){ScratchpadsRoot, WorkspacesRoot} {
+		root, err := rootFn()
+		if err != nil {
+			return removed, err
+		}
+		swept, err := sweepExpiredRunDirs(root)
+		removed = append(removed, swept...)
+		if err != nil {
+			return removed, err
+		}
 	}
+	return removed, nil
+}
+
+// sweepExpiredRunDirs removes the expired `<root>/*/*` run directories.
+func sweepExpiredRunDirs(root string) (removed []string, err error) {
 	entries, err := os.ReadDir(root)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -0,0 +1,47 @@
+package mission
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"squadron/config"
+	"squadron/llm"
+)
+
+var _ = Describe("Task model overrides", func() {
+	run := func(task config.Task, provider *mockProvider) []mockCall {
+		cfg := buildTestConfig(testMission("override", []config.Task{task}), testAgent("worker"))
+		runner, err := NewRunner(cfg, "", "override", nil, WithProviderFactory(func() llm.Provider { return provider }))
+		Expect(err).NotTo(HaveOccurred())
+		defer runner.CloseStores()
+		Expect(runner.Run(context.Background(), newMockMissionStreamer())).To(Succeed())
+		return provider.getCalls()
+	}
+
+	It("runs the commander and agents on the task's models", func() {
+		task := testTask("synthesize", "Synthesize")
+		task.Models = &config.ModelOverride{
+			Commander: "claude_opus_4",
+			Agents:    map[string]string{"worker": "claude_haiku_4_5"},
+		}
+		provider := newMockProvider(cmdCallAgent("worker", "Do it"), agentAnswer("done"), cmdTaskComplete())
+
+		calls := run(task, provider)
+		Expect(calls).To(HaveLen(3))
+		Expect(calls[0].Model).To(Equal("claude-opus-4-20250514"))
+		Expect(calls[1].Model).To(Equal("claude-haiku-4-5-20251001"))
+		Expect(calls[2].Model).To(Equal("claude-opus-4-20250514"))
+	})
+
+	It("uses the mission models without an override", func() {
+		provider := newMockProvider(cmdCallAgent("worker", "Do it"), agentAnswer("done"), cmdTaskComplete())
+
+		calls := run(testTask("plain", "Plain"), provider)
+		Expect(calls).To(HaveLen(3))
+		for _, call := range calls {
+			Expect(call.Model).To(Equal("claude-sonnet-4-20250514"))
+		}
+	})
+})
@@ -0,0 +1,94 @@
+package mission
+
+import (
+	"fmt"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"squadron/config"
+)
+
+// fillOutputDataset fills the dataset of an iterator that maps over a
+// dependency's output field (tasks.<task>.output.<field>) from that task's
+// stored output. It leaves a dataset alone once iteration over it has begun,
+// so a resumed run iterates the same items.
+func (r *Runner) fillOutputDataset(task config.Task) error {
+	it := task.Iterator
+	if it == nil || it.FromTask == "" {
+		return nil
+	}
+	dsID, ok := r.datasetIDs[it.Dataset]
+	if !ok {
+		return fmt.Errorf("dataset '%s' not found", it.Dataset)
+	}
+	if locked, err := r.stores.Datasets.IsDatasetLocked(dsID); err == nil && locked {
+		return nil
+	}
+
+	var to *TaskOutput
+	if r.knowledgeStore != nil {
+		to, _ = r.knowledgeStore.GetTaskOutput(it.FromTask)
+	}
+	if to == nil {
+		return fmt.Errorf("iterator over %s: task '%s' has no output", it.Dataset, it.FromTask)
+	}
+	var output any = to.Output
+	if to.IsIterated && to.Output == nil {
+		iterations := make([]any, len(to.Iterations))
+		for i, iter := range to.Iterations {
+			iterations[i] = iter.Output
+		}
+		output = iterations
+	}
+	values, err := outputItems(output, it.FromPath)
+	if err != nil {
+		return fmt.Errorf("iterator over %s: %w", it.Dataset, err)
+	}
+
+	items := make([]cty.Value, len(values))
+	for i, v := range values {
+		items[i] = config.GoToCtyValue(v)
+	}
+	if err := r.stores.Datasets.SetItems(dsID, items); err != nil {
+		return fmt.Errorf("persist dataset '%s': %w", it.Dataset, err)
+	}
+	return nil
+}
+
+// outputItems follows path through a task output and returns the array it
+// ends at. The outputs of an iterated task (a list, one per iteration) are
+// each followed and their arrays concatenated.
+func outputItems(v any, path []string) ([]any, error) {
+	switch val := v.(type) {
+	case []any:
+		if len(path) == 0 {
+			return val, nil
+		}
+		var out []any
+		for _, elem := range val {
+			items, err := outputItems(elem, path)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, items...)
+		}
+		return out, nil
+	case map[string]any:
+		if len(path) == 0 {
+			return nil, fmt.Errorf("expected an array, got an object")
+		}
+		next, ok := val[path[0]]
+		if !ok || next == nil {
+			// A missing optional field means nothing to iterate
+			return nil, nil
+		}
+		return outputItems(next, path[1:])
+	case nil:
+		return nil, nil
+	default:
+		if len(path) == 0 {
+			return nil, fmt.Errorf("expected an array, got %T", v)
+		}
+		return nil, fmt.Errorf("field '%s' is read from a %T, not an object", path[0], v)
+	}
+}
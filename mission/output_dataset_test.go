@@ -0,0 +1,113 @@
+package mission
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"squadron/config"
+	"squadron/llm"
+)
+
+var _ = Describe("Iterating over a dependency's output", func() {
+	// extract_leads → enrich (one iteration per lead)
+	leadsMission := func() config.Mission {
+		extract := testTask("extract_leads", "Extract leads")
+		extract.Output = &config.OutputSchema{Fields: []config.OutputField{
+			{Name: "items", Type: "array", Items: &config.OutputField{Type: "object"}},
+		}}
+		enrich := testTask("enrich", "Enrich each lead")
+		enrich.DependsOn = []string{"extract_leads"}
+		enrich.Iterator = &config.TaskIterator{
+			Dataset:  config.OutputDatasetName("extract_leads", []string{"items"}),
+			FromTask: "extract_leads",
+			FromPath: []string{"items"},
+		}
+		enrich.Output = &config.OutputSchema{Fields: []config.OutputField{
+			{Name: "company", Type: "string"},
+		}}
+		mission := testMission("leads", []config.Task{extract, enrich})
+		mission.Datasets = []config.Dataset{{
+			Name:     enrich.Iterator.Dataset,
+			FromTask: "extract_leads",
+			FromPath: []string{"items"},
+		}}
+		return mission
+	}
+
+	It("fills the dataset from the dependency's output and iterates it", func() {
+		provider := newMockProvider(
+			cmdSubmitOutput(map[string]any{"items": []any{
+				map[string]any{"company": "Acme"},
+				map[string]any{"company": "Globex"},
+			}}),
+			cmdTaskComplete(),
+			cmdDatasetNext(),
+			cmdSubmitOutput(map[string]any{"company": "Acme"}),
+			cmdDatasetNext(),
+			cmdSubmitOutput(map[string]any{"company": "Globex"}),
+			cmdDatasetNext(),
+			cmdTaskComplete(),
+		)
+		mission := leadsMission()
+		cfg := buildTestConfig(mission, testAgent("worker"))
+		runner, err := NewRunner(cfg, "", mission.Name, nil, WithProviderFactory(func() llm.Provider { return provider }))
+		Expect(err).NotTo(HaveOccurred())
+		defer runner.CloseStores()
+
+		Expect(runner.Run(context.Background(), newMockMissionStreamer())).To(Succeed())
+
+		dsID := runner.datasetIDs["extract_leads.output.items"]
+		items, err := runner.stores.Datasets.GetItems(dsID, 0, 10)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(items).To(HaveLen(2))
+		Expect(items[1].GetAttr("company").AsString()).To(Equal("Globex"))
+
+		ks := &PersistentKnowledgeStore{MissionID: runner.missionID, Store: runner.stores.Missions}
+		to, ok := ks.GetTaskOutput("enrich")
+		Expect(ok).To(BeTrue())
+		Expect(to.Iterations).To(HaveLen(2))
+	})
+
+	It("rejects writes to a dataset filled from a task's output", func() {
+		mission := leadsMission()
+		cfg := buildTestConfig(mission, testAgent("worker"))
+		runner, err := NewRunner(cfg, "", mission.Name, nil, WithProviderFactory(func() llm.Provider { return newMockProvider() }))
+		Expect(err).NotTo(HaveOccurred())
+		defer runner.CloseStores()
+
+		err = runner.SetDataset("extract_leads.output.items", nil)
+		Expect(err).To(MatchError(ContainSubstring("is filled from the output of task 'extract_leads'")))
+	})
+
+	Describe("outputItems", func() {
+		It("follows nested fields to the array", func() {
+			items, err := outputItems(map[string]any{"result": map[string]any{"leads": []any{"a", "b"}}}, []string{"result", "leads"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(items).To(Equal([]any{"a", "b"}))
+		})
+
+		It("concatenates the arrays of an iterated dependency", func() {
+			iterations := []any{
+				map[string]any{"items": []any{"a"}},
+				map[string]any{"items": []any{"b", "c"}},
+				map[string]any{},
+			}
+			items, err := outputItems(iterations, []string{"items"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(items).To(Equal([]any{"a", "b", "c"}))
+		})
+
+		It("treats a missing field as no items", func() {
+			items, err := outputItems(map[string]any{}, []string{"items"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(items).To(BeEmpty())
+		})
+
+		It("rejects a field that isn't an array", func() {
+			_, err := outputItems(map[string]any{"items": "a"}, []string{"items"})
+			Expect(err).To(MatchError(ContainSubstring("expected an array")))
+		})
+	})
+})
@@ -0,0 +1,254 @@
+package mission
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"squadron/config"
+	"squadron/streamers"
+	"squadron/streamers/webhook"
+)
+
+// outputSinkEvent is the event name sent with every output sink delivery.
+const outputSinkEvent = "task_output"
+
+// sinkRequestTimeout bounds a single delivery attempt of a network sink.
+const sinkRequestTimeout = 10 * time.Second
+
+// sinkRetryBaseDelay is the wait before the first redelivery; it doubles on
+// every further attempt.
+var sinkRetryBaseDelay = 500 * time.Millisecond
+
+// OutputSinkPayload is the JSON document delivered to a task's output sinks
+// each time the task's output is submitted.
+type OutputSinkPayload struct {
+	Event       string         `json:"event"`
+	MissionID   string         `json:"missionId"`
+	MissionName string         `json:"missionName"`
+	TaskID      string         `json:"taskId"`
+	TaskName    string         `json:"taskName"`
+	Dataset     string         `json:"dataset,omitempty"` // iterated tasks only
+	Index       *int           `json:"index,omitempty"`   // iterated tasks only
+	ItemID      string         `json:"itemId,omitempty"`  // iterated tasks only
+	Output      map[string]any `json:"output"`
+	Timestamp   time.Time      `json:"timestamp"`
+}
+
+type sinkDelivery struct {
+	sinks   []config.OutputSink
+	payload OutputSinkPayload
+}
+
+// outputSinks delivers task outputs to their sinks on a background worker,
+// in submission order, so a slow endpoint never stalls the mission. The
+// output is already in the store, so a failed delivery is reported as a
+// mission issue rather than failing the task.
+type outputSinks struct {
+	streamer streamers.MissionHandler
+	client   *http.Client
+	queue    chan sinkDelivery
+	done     chan struct{}
+
+	mu     sync.Mutex
+	closed bool
+}
+
+func newOutputSinks(streamer streamers.MissionHandler) *outputSinks {
+	s := &outputSinks{
+		streamer: streamer,
+		client:   &http.Client{Timeout: sinkRequestTimeout},
+		queue:    make(chan sinkDelivery, 64),
+		done:     make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// Close stops accepting outputs and waits for queued deliveries (including
+// their retries) to finish.
+func (s *outputSinks) Close() {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	close(s.queue)
+	s.mu.Unlock()
+	<-s.done
+}
+
+func (s *outputSinks) enqueue(d sinkDelivery) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	s.queue <- d
+}
+
+func (s *outputSinks) run() {
+	defer close(s.done)
+	for d := range s.queue {
+		body, err := json.Marshal(d.payload)
+		if err != nil {
+			s.report(d, nil, err)
+			continue
+		}
+		for i := range d.sinks {
+			if err := s.deliver(&d.sinks[i], d.payload.TaskName, body); err != nil {
+				s.report(d, &d.sinks[i], err)
+			}
+		}
+	}
+}
+
+func (s *outputSinks) report(d sinkDelivery, sink *config.OutputSink, err error) {
+	details := map[string]any{"task": d.payload.TaskName}
+	message := fmt.Sprintf("delivering output of task '%s': %v", d.payload.TaskName, err)
+	if sink != nil {
+		details["sink"] = sink.Name
+		details["type"] = sink.Type
+		message = fmt.Sprintf("output_sink '%s': delivering output of task '%s': %v", sink.Name, d.payload.TaskName, err)
+	}
+	if d.payload.Index != nil {
+		details["index"] = *d.payload.Index
+	}
+	s.streamer.MissionIssue(streamers.MissionIssueData{
+		Severity: streamers.IssueError,
+		Category: streamers.IssueCategoryOutputSink,
+		Message:  message,
+		TaskName: d.payload.TaskName,
+		Details:  details,
+	})
+}
+
+// deliver writes body to one sink. Network sinks retry network errors,
+// 429s, and 5xx responses with exponential backoff.
+func (s *outputSinks) deliver(sink *config.OutputSink, taskName string, body []byte) error {
+	if sink.Type == config.OutputSinkFile {
+		return appendOutputLine(sink.Path, body)
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= sink.GetMaxRetries(); attempt++ {
+		if attempt > 0 {
+			time.Sleep(sinkRetryBaseDelay << (attempt - 1))
+		}
+		var retry bool
+		var err error
+		switch sink.Type {
+		case config.OutputSinkHTTP:
+			retry, err = s.postHTTP(sink, body)
+		case config.OutputSinkSQS:
+			retry, err = s.sendSQS(sink, body)
+		case config.OutputSinkPubSub:
+			retry, err = s.publishPubSub(sink, taskName, body)
+		default:
+			return fmt.Errorf("unknown sink type '%s'", sink.Type)
+		}
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if !retry {
+			break
+		}
+	}
+	return lastErr
+}
+
+// appendOutputLine appends body to path as one JSON line, creating the file
+// and its parent directories.
+func appendOutputLine(path string, body []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(body, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// postHTTP makes a single http delivery attempt. The bool reports whether a
+// failure is worth retrying.
+func (s *outputSinks) postHTTP(sink *config.OutputSink, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, sink.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range sink.Headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set(webhook.HeaderEvent, outputSinkEvent)
+	if sink.Secret != "" {
+		req.Header.Set(webhook.HeaderSignature, webhook.Sign(sink.Secret, body))
+	}
+	return s.do(req)
+}
+
+// do sends req and classifies the response the way every network sink
+// does: 2xx succeeds, and network errors, 429s, and 5xx are retried.
+func (s *outputSinks) do(req *http.Request) (bool, error) {
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, fmt.Errorf("%s %s: %s", req.Method, req.URL.Redacted(), resp.Status)
+}
+
+// emitTaskOutput hands a submitted output to the task's sinks. dataset,
+// index, and itemID are set for iterated tasks.
+func (r *Runner) emitTaskOutput(task config.Task, taskID string, dataset *string, index *int, itemID *string, output map[string]any) {
+	if r.sinks == nil || len(task.OutputSinks) == 0 {
+		return
+	}
+	payload := OutputSinkPayload{
+		Event:       outputSinkEvent,
+		MissionID:   r.missionID,
+		MissionName: r.mission.Name,
+		TaskID:      taskID,
+		TaskName:    task.Name,
+		Output:      output,
+		Timestamp:   time.Now().UTC(),
+	}
+	if dataset != nil {
+		payload.Dataset = *dataset
+	}
+	if index != nil {
+		i := *index
+		payload.Index = &i
+	}
+	if itemID != nil {
+		payload.ItemID = *itemID
+	}
+	r.sinks.enqueue(sinkDelivery{sinks: task.OutputSinks, payload: payload})
+}
+
+// hasOutputSinks reports whether any task of the mission has an output sink.
+func (r *Runner) hasOutputSinks() bool {
+	for _, task := range r.mission.Tasks {
+		if len(task.OutputSinks) > 0 {
+			return true
+		}
+	}
+	return false
+}
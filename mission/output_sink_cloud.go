@@ -0,0 +1,114 @@
+package mission
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/auth"
+	"cloud.google.com/go/auth/credentials"
+
+	"squadron/config"
+	"squadron/internal/awssig"
+)
+
+// =============================================================================
+// Amazon SQS
+// =============================================================================
+
+// sendSQS makes a single SendMessage attempt against the sink's queue,
+// using the SQS query API signed with Signature Version 4.
+func (s *outputSinks) sendSQS(sink *config.OutputSink, body []byte) (bool, error) {
+	creds, err := awssig.CredentialsFromEnv(os.Getenv)
+	if err != nil {
+		return false, err
+	}
+	region := sink.Region
+	if region == "" {
+		region = config.SQSRegionFromURL(sink.URL)
+	}
+
+	form := url.Values{
+		"Action":      {"SendMessage"},
+		"MessageBody": {string(body)},
+		"Version":     {"2012-11-05"},
+	}.Encode()
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, sink.URL, strings.NewReader(form))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	awssig.Sign(req, awssig.PayloadHash([]byte(form)), creds, region, "sqs", time.Now())
+	return s.do(req)
+}
+
+// =============================================================================
+// Google Cloud Pub/Sub
+// =============================================================================
+
+// pubsubEndpoint is the Pub/Sub REST API base URL.
+var pubsubEndpoint = "https://pubsub.googleapis.com/v1/"
+
+// pubsubToken returns an OAuth access token for Pub/Sub from Application
+// Default Credentials. The credentials are detected once per process and
+// cache their token until it expires.
+var pubsubToken = func(ctx context.Context) (string, error) {
+	pubsubCredsOnce.Do(func() {
+		pubsubCreds, pubsubCredsErr = credentials.DetectDefault(&credentials.DetectOptions{
+			Scopes: []string{"https://www.googleapis.com/auth/pubsub"},
+		})
+	})
+	if pubsubCredsErr != nil {
+		return "", pubsubCredsErr
+	}
+	tok, err := pubsubCreds.Token(ctx)
+	if err != nil {
+		return "", err
+	}
+	return tok.Value, nil
+}
+
+var (
+	pubsubCredsOnce sync.Once
+	pubsubCreds     *auth.Credentials
+	pubsubCredsErr  error
+)
+
+// publishPubSub makes a single publish attempt to the sink's topic. The
+// payload is the message data; the event and task name are attributes so
+// subscriptions can filter on them.
+func (s *outputSinks) publishPubSub(sink *config.OutputSink, taskName string, body []byte) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), sinkRequestTimeout)
+	defer cancel()
+	token, err := pubsubToken(ctx)
+	if err != nil {
+		return false, fmt.Errorf("pubsub credentials: %w", err)
+	}
+
+	msg, err := json.Marshal(map[string]any{
+		"messages": []map[string]any{{
+			"data": body, // []byte is base64-encoded, as the API expects
+			"attributes": map[string]string{
+				"event": outputSinkEvent,
+				"task":  taskName,
+			},
+		}},
+	})
+	if err != nil {
+		return false, err
+	}
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, pubsubEndpoint+sink.Topic+":publish", bytes.NewReader(msg))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	return s.do(req)
+}
@@ -0,0 +1,238 @@
+package mission
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/zclconf/go-cty/cty"
+
+	"squadron/config"
+	"squadron/llm"
+	"squadron/streamers/webhook"
+)
+
+// sinkServer records the requests an httptest server receives.
+type sinkServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests []*http.Request
+	bodies   []string
+}
+
+func newSinkServer(status int) *sinkServer {
+	s := &sinkServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		s.mu.Lock()
+		s.requests = append(s.requests, r)
+		s.bodies = append(s.bodies, string(body))
+		s.mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	DeferCleanup(s.Close)
+	return s
+}
+
+func (s *sinkServer) received() ([]*http.Request, []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*http.Request(nil), s.requests...), append([]string(nil), s.bodies...)
+}
+
+var _ = Describe("Output sinks", func() {
+	outputSchema := &config.OutputSchema{Fields: []config.OutputField{
+		{Name: "title", Type: "string", Description: "The title", Required: true},
+	}}
+
+	run := func(mission config.Mission, provider *mockProvider) (*mockMissionStreamer, error) {
+		cfg := buildTestConfig(mission, testAgent("worker"))
+		runner, err := NewRunner(cfg, "", "sinks", nil, WithProviderFactory(func() llm.Provider { return provider }))
+		Expect(err).NotTo(HaveOccurred())
+		defer runner.CloseStores()
+		streamer := newMockMissionStreamer()
+		return streamer, runner.Run(context.Background(), streamer)
+	}
+
+	singleOutput := func() *mockProvider {
+		return newMockProvider(
+			cmdCallAgent("worker", "Extract the title"),
+			agentAnswer("The title is Report."),
+			cmdSubmitOutput(map[string]interface{}{"title": "Report"}),
+			cmdTaskComplete(),
+		)
+	}
+
+	It("delivers the submitted output to http and file sinks", func() {
+		server := newSinkServer(http.StatusOK)
+		path := filepath.Join(GinkgoT().TempDir(), "out", "results.jsonl")
+
+		task := testTask("extract", "Extract the title")
+		task.Output = outputSchema
+		task.OutputSinks = []config.OutputSink{
+			{Name: "api", Type: config.OutputSinkHTTP, URL: server.URL, Secret: "s3cret", Headers: map[string]string{"X-Team": "data"}},
+			{Name: "log", Type: config.OutputSinkFile, Path: path},
+		}
+
+		streamer, err := run(testMission("sinks", []config.Task{task}), singleOutput())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(streamer.hasEvent("mission_issue")).To(BeFalse())
+
+		requests, bodies := server.received()
+		Expect(bodies).To(HaveLen(1))
+		Expect(requests[0].Header.Get(webhook.HeaderEvent)).To(Equal("task_output"))
+		Expect(requests[0].Header.Get("X-Team")).To(Equal("data"))
+		Expect(requests[0].Header.Get(webhook.HeaderSignature)).To(Equal(webhook.Sign("s3cret", []byte(bodies[0]))))
+
+		var payload OutputSinkPayload
+		Expect(json.Unmarshal([]byte(bodies[0]), &payload)).To(Succeed())
+		Expect(payload.Event).To(Equal("task_output"))
+		Expect(payload.MissionName).To(Equal("sinks"))
+		Expect(payload.MissionID).NotTo(BeEmpty())
+		Expect(payload.TaskName).To(Equal("extract"))
+		Expect(payload.TaskID).NotTo(BeEmpty())
+		Expect(payload.Index).To(BeNil())
+		Expect(payload.Output).To(Equal(map[string]any{"title": "Report"}))
+
+		data, err := os.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(Equal(bodies[0] + "\n"))
+	})
+
+	It("delivers one payload per item of an iterated task", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "items.jsonl")
+
+		task := testTask("process", "Process items")
+		task.Iterator = &config.TaskIterator{Dataset: "items"}
+		task.Output = outputSchema
+		task.OutputSinks = []config.OutputSink{{Name: "log", Type: config.OutputSinkFile, Path: path}}
+		mission := testMission("sinks", []config.Task{task})
+		mission.Datasets = []config.Dataset{{
+			Name: "items",
+			Items: []cty.Value{
+				cty.ObjectVal(map[string]cty.Value{"name": cty.StringVal("first")}),
+				cty.ObjectVal(map[string]cty.Value{"name": cty.StringVal("second")}),
+			},
+		}}
+
+		provider := newMockProvider(
+			cmdDatasetNext(),
+			cmdSubmitOutput(map[string]interface{}{"title": "first"}),
+			cmdDatasetNext(),
+			cmdSubmitOutput(map[string]interface{}{"title": "second"}),
+			cmdDatasetNext(),
+			cmdTaskComplete(),
+		)
+		_, err := run(mission, provider)
+		Expect(err).NotTo(HaveOccurred())
+
+		data, err := os.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+		Expect(lines).To(HaveLen(2))
+		for i, title := range []string{"first", "second"} {
+			var payload OutputSinkPayload
+			Expect(json.Unmarshal([]byte(lines[i]), &payload)).To(Succeed())
+			Expect(payload.Dataset).To(Equal("items"))
+			Expect(payload.Index).To(HaveValue(Equal(i)))
+			Expect(payload.Output["title"]).To(Equal(title))
+		}
+	})
+
+	It("reports a failed delivery as a mission issue without failing the task", func() {
+		server := newSinkServer(http.StatusBadRequest)
+
+		task := testTask("extract", "Extract the title")
+		task.Output = outputSchema
+		task.OutputSinks = []config.OutputSink{{Name: "api", Type: config.OutputSinkHTTP, URL: server.URL}}
+
+		streamer, err := run(testMission("sinks", []config.Task{task}), singleOutput())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(streamer.hasEvent("mission_completed")).To(BeTrue())
+
+		_, bodies := server.received()
+		Expect(bodies).To(HaveLen(1), "4xx responses are not retried")
+		var issue map[string]string
+		for _, e := range streamer.getEvents() {
+			if e.Type == "mission_issue" {
+				issue = e.Data
+			}
+		}
+		Expect(issue).To(HaveKeyWithValue("category", "output_sink"))
+		Expect(issue).To(HaveKeyWithValue("task", "extract"))
+		Expect(issue["message"]).To(ContainSubstring("output_sink 'api'"))
+	})
+
+	It("sends signed SendMessage requests to SQS", func() {
+		server := newSinkServer(http.StatusOK)
+		GinkgoT().Setenv("AWS_ACCESS_KEY_ID", "AKIDTEST")
+		GinkgoT().Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+		GinkgoT().Setenv("AWS_SESSION_TOKEN", "session")
+
+		task := testTask("extract", "Extract the title")
+		task.Output = outputSchema
+		task.OutputSinks = []config.OutputSink{{Name: "queue", Type: config.OutputSinkSQS, URL: server.URL + "/123456789012/results", Region: "us-east-1"}}
+
+		_, err := run(testMission("sinks", []config.Task{task}), singleOutput())
+		Expect(err).NotTo(HaveOccurred())
+
+		requests, bodies := server.received()
+		Expect(bodies).To(HaveLen(1))
+		Expect(requests[0].URL.Path).To(Equal("/123456789012/results"))
+		Expect(requests[0].Header.Get("Authorization")).To(HavePrefix("AWS4-HMAC-SHA256 Credential=AKIDTEST/"))
+		Expect(requests[0].Header.Get("Authorization")).To(ContainSubstring("/us-east-1/sqs/aws4_request, SignedHeaders=content-type;host;x-amz-date;x-amz-security-token, Signature="))
+		Expect(requests[0].Header.Get("X-Amz-Security-Token")).To(Equal("session"))
+
+		form, err := url.ParseQuery(bodies[0])
+		Expect(err).NotTo(HaveOccurred())
+		Expect(form.Get("Action")).To(Equal("SendMessage"))
+		var payload OutputSinkPayload
+		Expect(json.Unmarshal([]byte(form.Get("MessageBody")), &payload)).To(Succeed())
+		Expect(payload.Output).To(Equal(map[string]any{"title": "Report"}))
+	})
+
+	It("publishes to a Pub/Sub topic", func() {
+		server := newSinkServer(http.StatusOK)
+		endpoint, token := pubsubEndpoint, pubsubToken
+		DeferCleanup(func() { pubsubEndpoint, pubsubToken = endpoint, token })
+		pubsubEndpoint = server.URL + "/v1/"
+		pubsubToken = func(context.Context) (string, error) { return "tok", nil }
+
+		task := testTask("extract", "Extract the title")
+		task.Output = outputSchema
+		task.OutputSinks = []config.OutputSink{{Name: "topic", Type: config.OutputSinkPubSub, Topic: "projects/acme/topics/results"}}
+
+		_, err := run(testMission("sinks", []config.Task{task}), singleOutput())
+		Expect(err).NotTo(HaveOccurred())
+
+		requests, bodies := server.received()
+		Expect(bodies).To(HaveLen(1))
+		Expect(requests[0].URL.Path).To(Equal("/v1/projects/acme/topics/results:publish"))
+		Expect(requests[0].Header.Get("Authorization")).To(Equal("Bearer tok"))
+
+		var msg struct {
+			Messages []struct {
+				Data       string            `json:"data"`
+				Attributes map[string]string `json:"attributes"`
+			} `json:"messages"`
+		}
+		Expect(json.Unmarshal([]byte(bodies[0]), &msg)).To(Succeed())
+		Expect(msg.Messages).To(HaveLen(1))
+		Expect(msg.Messages[0].Attributes).To(Equal(map[string]string{"event": "task_output", "task": "extract"}))
+		data, err := base64.StdEncoding.DecodeString(msg.Messages[0].Data)
+		Expect(err).NotTo(HaveOccurred())
+		var payload OutputSinkPayload
+		Expect(json.Unmarshal(data, &payload)).To(Succeed())
+		Expect(payload.TaskName).To(Equal("extract"))
+	})
+})
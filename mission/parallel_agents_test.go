@@ -0,0 +1,75 @@
+package mission
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"squadron/config"
+	"squadron/llm"
+)
+
+var _ = Describe("call_agents_parallel", func() {
+	It("runs the same agent on several sub-tasks and returns every result", func() {
+		mission := testMission("research", []config.Task{testTask("research", "Research the companies")})
+		mission.Commander.MaxParallelAgents = 2
+		cfg := buildTestConfig(mission, testAgent("worker"))
+
+		input, _ := json.Marshal(map[string]any{"calls": []map[string]string{
+			{"name": "worker", "task": "Research Acme"},
+			{"name": "worker", "task": "Research Globex"},
+			{"name": "worker", "task": "Research Initech"},
+		}})
+		provider := newMockProvider(
+			mockToolCall("call_agents_parallel", input),
+			withMatch(agentAnswer("Acme raised $10M"), matchLastUserContains("Research Acme")),
+			withMatch(agentAnswer("Globex was acquired"), matchLastUserContains("Research Globex")),
+			withMatch(agentAnswer("Initech is hiring"), matchLastUserContains("Research Initech")),
+			cmdTaskComplete(),
+		)
+		runner, err := NewRunner(cfg, "", mission.Name, nil, WithProviderFactory(func() llm.Provider { return provider }))
+		Expect(err).NotTo(HaveOccurred())
+		defer runner.CloseStores()
+
+		streamer := newMockMissionStreamer()
+		Expect(runner.Run(context.Background(), streamer)).To(Succeed())
+
+		var started []string
+		for _, e := range streamer.getEvents() {
+			if e.Type == "agent_started" {
+				started = append(started, e.Data["agent"])
+			}
+		}
+		Expect(started).To(ConsistOf("worker", "worker#2", "worker#3"))
+
+		// The commander's next turn carries all three results, in call order
+		var result string
+		for _, call := range provider.getCalls() {
+			for _, m := range call.Messages {
+				for _, p := range m.Parts {
+					if p.ToolResult != nil && strings.Contains(p.ToolResult.Content, `"results"`) {
+						result = p.ToolResult.Content
+					}
+				}
+			}
+		}
+		var parsed struct {
+			Results []struct {
+				Agent  string `json:"agent"`
+				Status string `json:"status"`
+				Result string `json:"result"`
+			} `json:"results"`
+		}
+		Expect(json.Unmarshal([]byte(result), &parsed)).To(Succeed())
+		Expect(parsed.Results).To(HaveLen(3))
+		Expect(parsed.Results[0].Agent).To(Equal("worker"))
+		Expect(parsed.Results[0].Result).To(Equal("Acme raised $10M"))
+		Expect(parsed.Results[1].Agent).To(Equal("worker#2"))
+		Expect(parsed.Results[1].Result).To(Equal("Globex was acquired"))
+		Expect(parsed.Results[2].Agent).To(Equal("worker#3"))
+		Expect(parsed.Results[2].Status).To(Equal("completed"))
+	})
+})
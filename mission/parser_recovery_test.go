@@ -0,0 +1,78 @@
+package mission
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"squadron/config"
+	"squadron/llm"
+)
+
+var _ = Describe("Parser recovery", func() {
+	It("re-prompts an agent that replies without a tool call or answer", func() {
+		mission := testMission("recover", []config.Task{testTask("research", "Research the market")})
+		cfg := buildTestConfig(mission, testAgent("worker"))
+
+		provider := newMockProvider(
+			cmdCallAgent("worker", "Size the market"),
+			textResponse("The market is probably around $2B."),
+			withMatch(agentAnswer("The market is $2B"), matchLastUserContains("no <ANSWER> tags")),
+			cmdTaskComplete(),
+		)
+		runner, err := NewRunner(cfg, "", mission.Name, nil, WithProviderFactory(func() llm.Provider { return provider }))
+		Expect(err).NotTo(HaveOccurred())
+		defer runner.CloseStores()
+
+		streamer := newMockMissionStreamer()
+		Expect(runner.Run(context.Background(), streamer)).To(Succeed())
+
+		var issues []map[string]string
+		for _, e := range streamer.getEvents() {
+			if e.Type == "mission_issue" && e.Data["category"] == "parser_recovery" {
+				issues = append(issues, e.Data)
+			}
+		}
+		Expect(issues).To(HaveLen(1))
+		Expect(issues[0]["severity"]).To(Equal("warning"))
+		Expect(issues[0]["message"]).To(ContainSubstring("attempt 1/3"))
+
+		// The commander got the recovered answer, not an empty result
+		var sawAnswer bool
+		for _, call := range provider.getCalls() {
+			for _, m := range call.Messages {
+				for _, p := range m.Parts {
+					if p.ToolResult != nil && p.ToolResult.Content == "The market is $2B" {
+						sawAnswer = true
+					}
+				}
+			}
+		}
+		Expect(sawAnswer).To(BeTrue())
+	})
+
+	It("reports a commander turn with no tool call", func() {
+		mission := testMission("recover", []config.Task{testTask("research", "Research the market")})
+		cfg := buildTestConfig(mission, testAgent("worker"))
+
+		provider := newMockProvider(
+			textResponse("Let me think about this."),
+			cmdTaskComplete(),
+		)
+		runner, err := NewRunner(cfg, "", mission.Name, nil, WithProviderFactory(func() llm.Provider { return provider }))
+		Expect(err).NotTo(HaveOccurred())
+		defer runner.CloseStores()
+
+		streamer := newMockMissionStreamer()
+		Expect(runner.Run(context.Background(), streamer)).To(Succeed())
+
+		var categories []string
+		for _, e := range streamer.getEvents() {
+			if e.Type == "mission_issue" {
+				categories = append(categories, e.Data["category"])
+			}
+		}
+		Expect(categories).To(Equal([]string{"parser_recovery"}))
+	})
+})
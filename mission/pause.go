@@ -0,0 +1,100 @@
+package mission
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"squadron/store"
+)
+
+// ErrMissionPaused is returned by Run when the mission was paused. The
+// mission is recorded as "paused" and can be continued with WithResume.
+var ErrMissionPaused = errors.New("mission paused")
+
+// defaultPauseRequestPollInterval is how often a running mission checks the
+// store for a pause requested from another process.
+const defaultPauseRequestPollInterval = time.Second
+
+// WithPauseRequestPollInterval sets how often the runner checks the store
+// for a pause requested from another process (default: one second).
+func WithPauseRequestPollInterval(d time.Duration) RunnerOption {
+	return func(r *Runner) {
+		r.pauseRequestPollInterval = d
+	}
+}
+
+// Pause stops the mission so it can be resumed later. No new tasks or
+// iterations are dispatched, and in-flight commanders and agents are
+// interrupted at their current LLM or tool call. Their sessions are
+// persisted turn by turn, so nothing is lost: interrupted tasks are recorded
+// as stopped and continue from their stored sessions on resume. Run then
+// returns ErrMissionPaused. Safe to call from any goroutine, more than once.
+func (r *Runner) Pause() {
+	r.pauseMu.Lock()
+	r.paused = true
+	cancel := r.cancelRun
+	r.pauseMu.Unlock()
+
+	r.Drain()
+	if cancel != nil {
+		cancel()
+	}
+}
+
+// IsPaused returns true if Pause has been called.
+func (r *Runner) IsPaused() bool {
+	r.pauseMu.Lock()
+	defer r.pauseMu.Unlock()
+	return r.paused
+}
+
+// setRunCancel records the cancel func of the current Run's context. A
+// Pause or Cancel that arrived before Run started cancels it right away.
+func (r *Runner) setRunCancel(cancel context.CancelFunc) {
+	r.pauseMu.Lock()
+	r.cancelRun = cancel
+	stopped := r.paused || r.cancelled != nil
+	r.pauseMu.Unlock()
+	if stopped {
+		cancel()
+	}
+}
+
+// RequestPause asks the process running a mission to pause it by moving
+// the mission from "running" to "pausing" in the store. The runner notices
+// within its pause request poll interval. It fails if the mission isn't running.
+func RequestPause(missions store.MissionStore, missionID string) error {
+	record, err := missions.GetMission(missionID)
+	if err != nil {
+		return fmt.Errorf("mission '%s' not found: %w", missionID, err)
+	}
+	ok, err := missions.UpdateMissionStatusCAS(missionID, string(MissionRunning), string(MissionPausing))
+	if err != nil {
+		return fmt.Errorf("requesting pause: %w", err)
+	}
+	if !ok {
+		return fmt.Errorf("mission '%s' is %s, not running", missionID, record.Status)
+	}
+	return nil
+}
+
+// watchPauseRequests pauses the runner when the mission's stored status
+// becomes "pausing". It returns when ctx is done.
+func (r *Runner) watchPauseRequests(ctx context.Context, missionID string) {
+	ticker := time.NewTicker(r.pauseRequestPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			record, err := r.stores.Missions.GetMission(missionID)
+			if err == nil && record.Status == string(MissionPausing) {
+				r.Pause()
+				return
+			}
+		}
+	}
+}
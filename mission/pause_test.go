@@ -0,0 +1,81 @@
+package mission
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"squadron/config"
+	"squadron/llm"
+)
+
+var _ = Describe("Mission pause", func() {
+	var cfg *config.Config
+
+	BeforeEach(func() {
+		first := testTask("first", "Do the first thing")
+		second := testTask("second", "Do the second thing")
+		second.DependsOn = []string{"first"}
+		cfg = buildTestConfig(testMission("pausable", []config.Task{first, second}), testAgent("worker"))
+		cfg.Storage.Path = filepath.Join(GinkgoT().TempDir(), "store.db")
+	})
+
+	// startHanging runs the mission in the background with a commander whose
+	// first LLM call never returns, and waits for that call to start.
+	startHanging := func() (*Runner, <-chan error) {
+		provider := newMockProvider(hangingCall())
+		runner, err := NewRunner(cfg, "", "pausable", nil,
+			WithProviderFactory(func() llm.Provider { return provider }),
+			WithPauseRequestPollInterval(10*time.Millisecond))
+		Expect(err).NotTo(HaveOccurred())
+		done := make(chan error, 1)
+		go func() { done <- runner.Run(context.Background(), newMockMissionStreamer()) }()
+		Eventually(provider.callCount).Should(Equal(1))
+		return runner, done
+	}
+
+	missionStatus := func(runner *Runner) string {
+		record, err := runner.stores.Missions.GetMission(runner.MissionID())
+		Expect(err).NotTo(HaveOccurred())
+		return record.Status
+	}
+
+	It("marks the mission paused and resumes it with WithResume", func() {
+		runner, done := startHanging()
+		runner.Pause()
+		Eventually(done).Should(Receive(MatchError(ErrMissionPaused)))
+		Expect(missionStatus(runner)).To(Equal("paused"))
+
+		tasks, err := runner.stores.Missions.GetTasksByMission(runner.MissionID())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tasks).To(HaveLen(1)) // second was never dispatched
+		Expect(tasks[0].Status).To(Equal("stopped"))
+		sessions, err := runner.stores.Sessions.GetSessionsByTask(tasks[0].ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sessions).To(HaveLen(1))
+		missionID := runner.MissionID()
+		runner.CloseStores()
+
+		provider := newMockProvider(cmdTaskComplete(), cmdTaskComplete())
+		resumed, err := NewRunner(cfg, "", "pausable", nil, WithResume(missionID), WithProviderFactory(func() llm.Provider { return provider }))
+		Expect(err).NotTo(HaveOccurred())
+		defer resumed.CloseStores()
+		Expect(resumed.Run(context.Background(), newMockMissionStreamer())).To(Succeed())
+		Expect(missionStatus(resumed)).To(Equal("completed"))
+	})
+
+	It("pauses when another process requests it through the store", func() {
+		runner, done := startHanging()
+		defer runner.CloseStores()
+		Expect(RequestPause(runner.stores.Missions, runner.MissionID())).To(Succeed())
+		Eventually(done).Should(Receive(MatchError(ErrMissionPaused)))
+		Expect(runner.IsPaused()).To(BeTrue())
+		Expect(missionStatus(runner)).To(Equal("paused"))
+
+		err := RequestPause(runner.stores.Missions, runner.MissionID())
+		Expect(err).To(MatchError(ContainSubstring("is paused, not running")))
+	})
+})
@@ -0,0 +1,224 @@
+package mission
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"squadron/config"
+)
+
+// Plan is the execution plan of a mission, built from config alone — no
+// mission record is created and no provider is called.
+type Plan struct {
+	MissionName string
+	// Groups holds tasks that can run in parallel, in execution order. A task
+	// is in the group after the last of its dependencies (or, for router
+	// targets, after the router that can activate it).
+	Groups   [][]PlannedTask
+	Datasets []PlannedDataset
+	// EstimatedLLMCalls is the minimum number of LLM calls the mission makes
+	// if every task runs. Tasks iterating over a dataset filled at runtime
+	// are counted once.
+	EstimatedLLMCalls int
+	MaxParallel       int
+	// MaxParallelTasks is the mission's max_parallel_tasks; 0 means no cap.
+	MaxParallelTasks int
+}
+
+// PlannedTask is one task in a Plan.
+type PlannedTask struct {
+	Name      string
+	DependsOn []string
+	Agents    []string
+	// Objective is the resolved objective. For iterated tasks it is resolved
+	// against the first item; empty if the dataset is filled at runtime.
+	Objective string
+	// Conditional is true for tasks that only run when a router or send_to
+	// activates them, directly or through a dependency.
+	Conditional bool
+	When        string   // raw `when` guard; the task is skipped if it's false
+	Routes      []string // router targets this task can activate
+
+	Dataset          string // iterated dataset, if any
+	Iterations       int    // number of items; 0 for non-iterated tasks
+	RuntimeDataset   bool   // the dataset is filled by set_dataset while the mission runs
+	Parallel         bool
+	ConcurrencyLimit int
+
+	EstimatedLLMCalls int
+}
+
+// PlannedDataset reports how a dataset resolved for the plan.
+type PlannedDataset struct {
+	Name   string
+	Origin string // "inline", "input <name>", "source <type>", or "runtime"
+	Items  int
+}
+
+// Per-execution LLM call estimate: the commander dispatches work and then
+// completes the task, and each agent answers at least once.
+const (
+	planCommanderCalls = 2
+	planAgentCalls     = 1
+)
+
+// BuildPlan resolves inputs, datasets and objectives for a mission and
+// lays its tasks out in parallel groups. It fails on the same input and
+// dataset errors NewRunner would.
+func BuildPlan(cfg *config.Config, missionName string, inputs map[string]string) (*Plan, error) {
+	var m *config.Mission
+	for i := range cfg.Missions {
+		if cfg.Missions[i].Name == missionName {
+			m = &cfg.Missions[i]
+			break
+		}
+	}
+	if m == nil {
+		return nil, fmt.Errorf("mission '%s' not found", missionName)
+	}
+
+	inputValues, err := m.ResolveInputValues(inputs)
+	if err != nil {
+		return nil, fmt.Errorf("mission '%s': %w", missionName, err)
+	}
+	datasets, err := resolveDatasets(m, inputValues)
+	if err != nil {
+		return nil, fmt.Errorf("mission '%s': %w", missionName, err)
+	}
+
+	plan := &Plan{MissionName: m.Name, MaxParallel: m.MaxParallel, MaxParallelTasks: m.MaxParallelTasks}
+	if plan.MaxParallel == 0 {
+		plan.MaxParallel = 3
+	}
+
+	runtimeDatasets := make(map[string]bool)
+	for _, ds := range m.Datasets {
+		pd := PlannedDataset{Name: ds.Name, Items: len(datasets[ds.Name])}
+		switch {
+		case ds.BindTo != "":
+			pd.Origin = "input " + ds.BindTo
+		case len(ds.Items) > 0:
+			pd.Origin = "inline"
+		case ds.Source != nil:
+			pd.Origin = "source " + ds.Source.Type
+		case ds.FromTask != "":
+			pd.Origin = "output of task " + ds.FromTask
+			runtimeDatasets[ds.Name] = true
+		default:
+			pd.Origin = "runtime"
+			runtimeDatasets[ds.Name] = true
+		}
+		plan.Datasets = append(plan.Datasets, pd)
+	}
+
+	levels, conditional := planLevels(m)
+	var tasks []PlannedTask
+	for _, t := range m.TopologicalSort() {
+		pt := PlannedTask{
+			Name:        t.Name,
+			DependsOn:   t.DependsOn,
+			Conditional: conditional[t.Name],
+			When:        t.RawWhen,
+		}
+		pt.Agents, _ = m.TaskAgents(&t, cfg.Agents)
+		if t.Router != nil {
+			for _, route := range t.Router.Routes {
+				pt.Routes = append(pt.Routes, route.Target)
+			}
+		}
+		pt.Routes = append(pt.Routes, t.SendTo...)
+
+		perRun := planCommanderCalls + planAgentCalls*len(pt.Agents)
+		if t.Iterator != nil {
+			pt.Dataset = t.Iterator.Dataset
+			pt.Parallel = t.Iterator.Parallel
+			if pt.Parallel {
+				pt.ConcurrencyLimit = t.Iterator.ConcurrencyLimit
+				if pt.ConcurrencyLimit == 0 {
+					pt.ConcurrencyLimit = 5
+				}
+			}
+			items := datasets[t.Iterator.Dataset]
+			pt.Iterations = len(items)
+			pt.RuntimeDataset = runtimeDatasets[t.Iterator.Dataset]
+			if len(items) > 0 {
+				obj, err := resolveObjectiveWithItem(t, cfg.ResolvedVars, inputValues, items[0])
+				if err != nil {
+					return nil, fmt.Errorf("task '%s': %w", t.Name, err)
+				}
+				pt.Objective = obj
+			}
+			pt.EstimatedLLMCalls = perRun * max(pt.Iterations, 1)
+		} else {
+			obj, err := t.ResolvedObjective(cfg.ResolvedVars, inputValues)
+			if err != nil {
+				return nil, fmt.Errorf("task '%s': %w", t.Name, err)
+			}
+			pt.Objective = obj
+			pt.EstimatedLLMCalls = perRun
+		}
+		plan.EstimatedLLMCalls += pt.EstimatedLLMCalls
+		tasks = append(tasks, pt)
+	}
+
+	for _, pt := range tasks {
+		level := levels[pt.Name]
+		for len(plan.Groups) <= level {
+			plan.Groups = append(plan.Groups, nil)
+		}
+		plan.Groups[level] = append(plan.Groups[level], pt)
+	}
+	for _, group := range plan.Groups {
+		sort.SliceStable(group, func(i, j int) bool { return !group[i].Conditional && group[j].Conditional })
+	}
+	return plan, nil
+}
+
+// planLevels assigns each task the group index after its last dependency.
+// Router-only tasks go after the router that activates them; they and
+// anything depending on them are conditional.
+func planLevels(m *config.Mission) (map[string]int, map[string]bool) {
+	routerParents := m.GetRouterTargets()
+	levels := make(map[string]int)
+	conditional := make(map[string]bool)
+	visiting := make(map[string]bool)
+
+	var visit func(name string) int
+	visit = func(name string) int {
+		if l, ok := levels[name]; ok {
+			return l
+		}
+		t := m.GetTaskByName(name)
+		if t == nil || visiting[name] {
+			return 0
+		}
+		visiting[name] = true
+		level := 0
+		for _, dep := range t.DependsOn {
+			level = max(level, visit(dep)+1)
+			conditional[name] = conditional[name] || conditional[dep]
+		}
+		if m.IsRouterOnlyTask(name) {
+			conditional[name] = true
+			for _, parent := range routerParents[name] {
+				level = max(level, visit(parent)+1)
+			}
+		}
+		visiting[name] = false
+		levels[name] = level
+		return level
+	}
+	for _, t := range m.Tasks {
+		visit(t.Name)
+	}
+	return levels, conditional
+}
+
+// resolveObjectiveWithItem evaluates an iterated task's objective the same
+// way the runner does for each iteration.
+func resolveObjectiveWithItem(t config.Task, vars, inputs map[string]cty.Value, item cty.Value) (string, error) {
+	r := &Runner{varsValues: vars, inputValues: inputs}
+	return r.resolveIterationObjective(t, item)
+}
@@ -0,0 +1,124 @@
+package mission
+
+import (
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/zclconf/go-cty/cty"
+
+	"squadron/config"
+)
+
+func templateExpr(src string) hcl.Expression {
+	expr, diags := hclsyntax.ParseTemplate([]byte(src), "test.hcl", hcl.InitialPos)
+	Expect(diags.HasErrors()).To(BeFalse())
+	return expr
+}
+
+func groupNames(p *Plan) [][]string {
+	var names [][]string
+	for _, g := range p.Groups {
+		var row []string
+		for _, t := range g {
+			row = append(row, t.Name)
+		}
+		names = append(names, row)
+	}
+	return names
+}
+
+var _ = Describe("BuildPlan", func() {
+	It("groups tasks by dependency depth and estimates LLM calls", func() {
+		fetch := testTask("fetch", "Fetch")
+		scan := testTask("scan", "Scan")
+		report := testTask("report", "Report")
+		report.DependsOn = []string{"fetch", "scan"}
+		report.Agents = []string{"worker", "writer"}
+		cfg := buildTestConfig(testMission("m", []config.Task{fetch, scan, report}), testAgent("worker"), testAgent("writer"))
+
+		plan, err := BuildPlan(cfg, "m", nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(groupNames(plan)).To(Equal([][]string{{"fetch", "scan"}, {"report"}}))
+		Expect(plan.Groups[1][0].EstimatedLLMCalls).To(Equal(4))
+		Expect(plan.EstimatedLLMCalls).To(Equal(3 + 3 + 4))
+		Expect(plan.MaxParallel).To(Equal(3))
+	})
+
+	It("resolves iterated objectives against the first item and counts iterations", func() {
+		task := testTask("process", "")
+		task.ObjectiveExpr = templateExpr("Process ${item.name}")
+		task.Iterator = &config.TaskIterator{Dataset: "items", Parallel: true}
+		m := testMission("m", []config.Task{task})
+		m.Datasets = []config.Dataset{{Name: "items", Items: []cty.Value{
+			cty.ObjectVal(map[string]cty.Value{"name": cty.StringVal("alpha")}),
+			cty.ObjectVal(map[string]cty.Value{"name": cty.StringVal("beta")}),
+		}}}
+		cfg := buildTestConfig(m, testAgent("worker"))
+
+		plan, err := BuildPlan(cfg, "m", nil)
+		Expect(err).NotTo(HaveOccurred())
+		pt := plan.Groups[0][0]
+		Expect(pt.Objective).To(Equal("Process alpha"))
+		Expect(pt.Iterations).To(Equal(2))
+		Expect(pt.ConcurrencyLimit).To(Equal(5))
+		Expect(pt.EstimatedLLMCalls).To(Equal(6))
+		Expect(plan.Datasets).To(Equal([]PlannedDataset{{Name: "items", Origin: "inline", Items: 2}}))
+	})
+
+	It("marks datasets filled at runtime", func() {
+		seed := testTask("seed", "Seed")
+		task := testTask("process", "Process")
+		task.DependsOn = []string{"seed"}
+		task.Iterator = &config.TaskIterator{Dataset: "found"}
+		m := testMission("m", []config.Task{seed, task})
+		m.Datasets = []config.Dataset{{Name: "found"}}
+		cfg := buildTestConfig(m, testAgent("worker"))
+
+		plan, err := BuildPlan(cfg, "m", nil)
+		Expect(err).NotTo(HaveOccurred())
+		pt := plan.Groups[1][0]
+		Expect(pt.RuntimeDataset).To(BeTrue())
+		Expect(pt.Objective).To(BeEmpty())
+		Expect(pt.EstimatedLLMCalls).To(Equal(3))
+		Expect(plan.Datasets[0].Origin).To(Equal("runtime"))
+	})
+
+	It("places router targets after their router as conditional", func() {
+		classify := testTask("classify", "Classify")
+		classify.Router = &config.TaskRouter{Routes: []config.TaskRoute{
+			{Target: "handle_a", Condition: "A"},
+			{Target: "handle_b", Condition: "B"},
+		}}
+		cfg := buildTestConfig(testMission("m", []config.Task{
+			classify,
+			testTask("handle_a", "Handle A"),
+			testTask("handle_b", "Handle B"),
+		}), testAgent("worker"))
+
+		plan, err := BuildPlan(cfg, "m", nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(groupNames(plan)).To(Equal([][]string{{"classify"}, {"handle_a", "handle_b"}}))
+		Expect(plan.Groups[0][0].Conditional).To(BeFalse())
+		Expect(plan.Groups[0][0].Routes).To(Equal([]string{"handle_a", "handle_b"}))
+		Expect(plan.Groups[1][0].Conditional).To(BeTrue())
+	})
+
+	It("fails on missing required inputs and invalid dataset bindings", func() {
+		m := testMission("m", []config.Task{testTask("t", "Do it")})
+		m.Inputs = []config.MissionInput{{Name: "target", Type: "string"}}
+		_, err := BuildPlan(buildTestConfig(m, testAgent("worker")), "m", nil)
+		Expect(err).To(MatchError(ContainSubstring("target")))
+
+		m = testMission("m", []config.Task{testTask("t", "Do it")})
+		m.Inputs = []config.MissionInput{{Name: "urls", Type: "string"}}
+		m.Datasets = []config.Dataset{{Name: "pages", BindTo: "urls"}}
+		_, err = BuildPlan(buildTestConfig(m, testAgent("worker")), "m", map[string]string{"urls": "not-a-list"})
+		Expect(err).To(MatchError(ContainSubstring("is not a list")))
+	})
+
+	It("fails for an unknown mission", func() {
+		_, err := BuildPlan(buildTestConfig(testMission("m", nil)), "nope", nil)
+		Expect(err).To(MatchError("mission 'nope' not found"))
+	})
+})
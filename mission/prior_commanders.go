@@ -0,0 +1,245 @@
+package mission
+
+import (
+	"context"
+	"fmt"
+
+	"squadron/agent"
+	"squadron/config"
+	"squadron/store"
+)
+
+// WithPriorMissions lets commanders in this run query the commanders of
+// completed earlier missions (by mission ID) through ask_prior_commander.
+// The referenced missions must exist in the store, be completed, and still be
+// defined in the loaded config.
+func WithPriorMissions(missionIDs ...string) RunnerOption {
+	return func(r *Runner) {
+		r.priorMissionIDs = append(r.priorMissionIDs, missionIDs...)
+	}
+}
+
+// loadPriorMissions validates the referenced prior missions and records the
+// completed tasks each one exposes. Commanders are not revived here — that
+// happens lazily on the first question.
+func (r *Runner) loadPriorMissions() error {
+	for _, id := range r.priorMissionIDs {
+		record, err := r.stores.Missions.GetMission(id)
+		if err != nil {
+			return fmt.Errorf("prior mission '%s': %w", id, err)
+		}
+		if record.Status != "completed" {
+			return fmt.Errorf("prior mission '%s' is %s, only completed missions can be referenced", id, record.Status)
+		}
+		if record.CompactedAt != nil {
+			return fmt.Errorf("prior mission '%s' was compacted by store gc; its commanders can't be asked", id)
+		}
+		if r.priorMissionConfig(record.MissionName) == nil {
+			return fmt.Errorf("prior mission '%s': mission '%s' is not defined in the current config", id, record.MissionName)
+		}
+
+		tasks, err := r.stores.Missions.GetTasksByMission(id)
+		if err != nil {
+			return fmt.Errorf("prior mission '%s': loading tasks: %w", id, err)
+		}
+		info := agent.PriorMissionInfo{ID: id, Name: record.MissionName}
+		for _, t := range tasks {
+			if t.Status == "completed" {
+				info.Tasks = append(info.Tasks, t.TaskName)
+			}
+		}
+		r.priorMissions = append(r.priorMissions, info)
+	}
+	return nil
+}
+
+func (r *Runner) priorMissionConfig(name string) *config.Mission {
+	for i := range r.cfg.Missions {
+		if r.cfg.Missions[i].Name == name {
+			return &r.cfg.Missions[i]
+		}
+	}
+	return nil
+}
+
+// priorCommanderCallback returns the AskPriorCommander callback for a task's
+// commander, or nil when this run references no prior missions.
+func (r *Runner) priorCommanderCallback(ctx context.Context, requestingTask string) func(string, string, int, string) (string, error) {
+	if len(r.priorMissions) == 0 {
+		return nil
+	}
+	return func(missionID, taskName string, iterationIndex int, question string) (string, error) {
+		return r.AskPriorCommander(ctx, missionID, taskName, iterationIndex, requestingTask, question)
+	}
+}
+
+// AskPriorCommander asks a question to the commander of a completed task in a
+// prior mission referenced via WithPriorMissions. The commander is revived
+// from its stored session on first use and cached; each question is answered
+// by an isolated clone so questions never mutate the revived state. The
+// clone's turns are charged to requestingTask's budget, regardless of which
+// task first revived the commander. For iterated tasks, pass the iteration index (0+).
+// For regular tasks, pass -1.
+func (r *Runner) AskPriorCommander(ctx context.Context, missionID, taskName string, iterationIndex int, requestingTask, question string) (string, error) {
+	sup, err := r.priorCommander(ctx, missionID, taskName, iterationIndex)
+	if err != nil {
+		return "", err
+	}
+	clone := sup.CloneForQuery()
+	defer clone.Close()
+	if budget := r.budgetTracker.For(requestingTask); budget != nil {
+		clone.SetBudget(budget)
+	}
+	return clone.AnswerQueryIsolated(ctx, question)
+}
+
+// priorCommander returns the revived commander for a prior mission's task,
+// rebuilding it from the store if this is the first question asked of it.
+func (r *Runner) priorCommander(ctx context.Context, missionID, taskName string, iterationIndex int) (*agent.Commander, error) {
+	var info *agent.PriorMissionInfo
+	for i := range r.priorMissions {
+		if r.priorMissions[i].ID == missionID {
+			info = &r.priorMissions[i]
+			break
+		}
+	}
+	if info == nil {
+		return nil, fmt.Errorf("mission '%s' is not a referenced prior mission", missionID)
+	}
+
+	cacheKey := missionID + "/" + taskName
+	var iterPtr *int
+	if iterationIndex >= 0 {
+		cacheKey = fmt.Sprintf("%s[%d]", cacheKey, iterationIndex)
+		iterPtr = &iterationIndex
+	}
+
+	r.priorMu.Lock()
+	defer r.priorMu.Unlock()
+	if sup, ok := r.priorCommanders[cacheKey]; ok {
+		return sup, nil
+	}
+
+	prior := r.priorMissionConfig(info.Name)
+	task := prior.GetTaskByName(taskName)
+	if task == nil {
+		return nil, fmt.Errorf("task '%s' not found in mission '%s'", taskName, info.Name)
+	}
+	taskRecord, err := r.stores.Missions.GetTaskByName(missionID, taskName)
+	if err != nil {
+		return nil, fmt.Errorf("loading task record for '%s': %w", taskName, err)
+	}
+	if taskRecord.Status != "completed" {
+		return nil, fmt.Errorf("task '%s' in mission '%s' did not complete", taskName, missionID)
+	}
+	if task.Iterator != nil && iterPtr == nil {
+		return nil, fmt.Errorf("task '%s' is an iterated task - you must provide an 'index' parameter to query a specific iteration", taskName)
+	}
+
+	sessions, err := r.stores.Sessions.GetSessionsByTask(taskRecord.ID)
+	if err != nil {
+		return nil, fmt.Errorf("loading sessions for task '%s': %w", taskName, err)
+	}
+
+	agents, agentReasons := prior.TaskAgents(task, r.cfg.Agents)
+	var reasoning, promptTemplate string
+	var toolResponse *config.ToolResponseConfig
+	var model string
+	if prior.Commander != nil {
+		model = task.CommanderModel(prior.Commander.Model)
+		reasoning = prior.Commander.Reasoning
+		promptTemplate = prior.Commander.PromptTemplate
+		toolResponse = prior.Commander.ToolResponse
+	}
+
+	sup, err := agent.NewCommander(ctx, agent.CommanderOptions{
+		Config:             r.cfg,
+		ConfigPath:         r.configPath,
+		MissionName:        prior.Name,
+		TaskName:           taskName,
+		Commander:          model,
+		AgentNames:         agents,
+		AgentReasons:       agentReasons,
+		TaskOutputSchema:   r.getTaskOutputSchema(*task),
+		IsIteration:        task.Iterator != nil,
+		Reasoning:          reasoning,
+		PromptTemplate:     promptTemplate,
+		ToolResponse:       toolResponse,
+		PricingOverrides:   r.pricingOverrides,
+		MissionLocalAgents: prior.LocalAgents,
+		Provider:           r.testProvider(),
+		CallLimiter:        r.callLimiter,
+		ToolFilter:         task.GetToolFilter(),
+		AgentModels:        task.AgentModels(),
+		Sampling:           task.Sampling,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("reviving commander for '%s' in mission '%s': %w", taskName, missionID, err)
+	}
+
+	loaded := false
+	for _, s := range sessions {
+		if s.Role != "commander" || !intPtrEqual(s.IterationIndex, iterPtr) {
+			continue
+		}
+		msgs, err := agent.LoadSessionMessages(r.stores.Sessions, s.ID)
+		if err == nil && len(msgs) > 0 {
+			sup.LoadSessionMessages(msgs)
+			loaded = true
+		}
+		break
+	}
+	if !loaded {
+		sup.Close()
+		return nil, fmt.Errorf("no stored commander session for task '%s' in mission '%s'", taskName, missionID)
+	}
+
+	// Revived commanders only answer questions; they get no dataset or
+	// session callbacks so nothing is written back to the prior mission.
+	sup.SetToolCallbacks(&agent.CommanderToolCallbacks{}, nil)
+	r.restorePriorAgents(ctx, sup, sessions, iterPtr, task.GetToolFilter(), task.AgentModels(), task.Sampling)
+
+	if r.priorCommanders == nil {
+		r.priorCommanders = make(map[string]*agent.Commander)
+	}
+	r.priorCommanders[cacheKey] = sup
+	return sup, nil
+}
+
+// restorePriorAgents rebuilds the completed agents of a revived commander so
+// its clones can answer follow-ups with ask_agent.
+func (r *Runner) restorePriorAgents(ctx context.Context, sup *agent.Commander, sessions []store.SessionInfo, iterationIndex *int, toolFilter *config.ToolFilter, agentModels map[string]string, sampling *config.Sampling) {
+	for _, s := range sessions {
+		if s.Role != "agent" || s.AgentName == "" || !intPtrEqual(s.IterationIndex, iterationIndex) {
+			continue
+		}
+		msgs, err := agent.LoadSessionMessages(r.stores.Sessions, s.ID)
+		if err != nil || len(msgs) == 0 {
+			continue
+		}
+		restored, err := agent.RestoreAgent(ctx, agent.Options{
+			ConfigPath:  r.configPath,
+			Config:      r.cfg,
+			AgentName:   agent.AgentConfigName(s.AgentName),
+			Model:       agentModels[agent.AgentConfigName(s.AgentName)],
+			Sampling:    sampling,
+			Provider:    r.testProvider(),
+			ToolFilter:  toolFilter,
+			CallLimiter: r.callLimiter,
+		}, msgs)
+		if err != nil {
+			continue // Non-fatal: the commander can still answer from its own context
+		}
+		sup.AddRestoredAgent(s.AgentName, restored)
+	}
+}
+
+// closePriorCommanders releases every commander revived for prior-mission queries.
+func (r *Runner) closePriorCommanders() {
+	r.priorMu.Lock()
+	defer r.priorMu.Unlock()
+	for key, sup := range r.priorCommanders {
+		sup.Close()
+		delete(r.priorCommanders, key)
+	}
+}
@@ -0,0 +1,53 @@
+package mission
+
+import (
+	"sort"
+
+	"squadron/config"
+)
+
+// taskPriorities returns each task's effective priority: its own, raised to
+// the highest priority of any task that depends on it, directly or not.
+// Without that, a high-priority task would wait on prerequisites that lose
+// out to unrelated low-priority work.
+func taskPriorities(m *config.Mission) map[string]int {
+	dependents := make(map[string][]string)
+	for _, t := range m.Tasks {
+		for _, dep := range t.DependsOn {
+			dependents[dep] = append(dependents[dep], t.Name)
+		}
+	}
+
+	priorities := make(map[string]int, len(m.Tasks))
+	var resolve func(name string, visiting map[string]bool) int
+	resolve = func(name string, visiting map[string]bool) int {
+		if p, ok := priorities[name]; ok {
+			return p
+		}
+		p := 0
+		if t := m.GetTaskByName(name); t != nil {
+			p = t.Priority
+		}
+		visiting[name] = true // validation rejects cycles; this just can't loop
+		for _, d := range dependents[name] {
+			if !visiting[d] {
+				p = max(p, resolve(d, visiting))
+			}
+		}
+		delete(visiting, name)
+		priorities[name] = p
+		return p
+	}
+	for _, t := range m.Tasks {
+		resolve(t.Name, map[string]bool{})
+	}
+	return priorities
+}
+
+// sortByPriority orders ready tasks highest effective priority first,
+// keeping dependency order among equal priorities.
+func sortByPriority(tasks []config.Task, priorities map[string]int) {
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return priorities[tasks[i].Name] > priorities[tasks[j].Name]
+	})
+}
@@ -0,0 +1,74 @@
+package mission
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"squadron/config"
+	"squadron/llm"
+)
+
+var _ = Describe("Task priority", func() {
+	// startOrder runs the mission one task at a time and returns the order
+	// the tasks started in.
+	startOrder := func(tasks ...config.Task) []string {
+		mission := testMission("prioritized", tasks)
+		mission.MaxParallelTasks = 1
+		provider := newMockProvider()
+		for range tasks {
+			provider.addResponses(cmdTaskComplete())
+		}
+		runner, err := NewRunner(buildTestConfig(mission, testAgent("worker")), "", "prioritized", nil, WithProviderFactory(func() llm.Provider { return provider }))
+		Expect(err).NotTo(HaveOccurred())
+		defer runner.CloseStores()
+		streamer := newMockMissionStreamer()
+		Expect(runner.Run(context.Background(), streamer)).To(Succeed())
+
+		var order []string
+		for _, e := range streamer.getEvents() {
+			if e.Type == "task_started" {
+				order = append(order, e.Data["task"])
+			}
+		}
+		return order
+	}
+
+	withPriority := func(t config.Task, priority int) config.Task {
+		t.Priority = priority
+		return t
+	}
+
+	It("launches higher-priority ready tasks first", func() {
+		Expect(startOrder(
+			testTask("leaf", "Quick check"),
+			withPriority(testTask("crawl", "Long crawl"), 10),
+			withPriority(testTask("cleanup", "Tidy up"), -1),
+		)).To(Equal([]string{"crawl", "leaf", "cleanup"}))
+	})
+
+	It("runs the prerequisites of a high-priority task ahead of other work", func() {
+		crawl := withPriority(testTask("crawl", "Long crawl"), 10)
+		crawl.DependsOn = []string{"prepare"}
+		Expect(startOrder(
+			testTask("leaf", "Quick check"),
+			testTask("prepare", "Prepare the crawl"),
+			crawl,
+		)).To(Equal([]string{"prepare", "crawl", "leaf"}))
+	})
+
+	It("keeps dependency order among equal priorities", func() {
+		priorities := taskPriorities(&config.Mission{Tasks: []config.Task{
+			{Name: "a"},
+			{Name: "b", DependsOn: []string{"a"}, Priority: 3},
+			{Name: "c", DependsOn: []string{"b"}},
+			{Name: "d", Priority: 3},
+		}})
+		Expect(priorities).To(Equal(map[string]int{"a": 3, "b": 3, "c": 0, "d": 3}))
+
+		ready := []config.Task{{Name: "c"}, {Name: "a"}, {Name: "d"}}
+		sortByPriority(ready, priorities)
+		Expect([]string{ready[0].Name, ready[1].Name, ready[2].Name}).To(Equal([]string{"a", "d", "c"}))
+	})
+})
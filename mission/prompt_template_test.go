@@ -0,0 +1,67 @@
+package mission
+
+import (
+	"context"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"squadron/config"
+	"squadron/llm"
+)
+
+// systemPrompt joins the system messages of a recorded call.
+func systemPrompt(call mockCall) string {
+	var parts []string
+	for _, m := range call.Messages {
+		if m.Role == llm.RoleSystem {
+			parts = append(parts, m.Content)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+var _ = Describe("Prompt templates", func() {
+	var cfg *config.Config
+
+	BeforeEach(func() {
+		cfg = buildTestConfig(testMission("templated", []config.Task{testTask("work", "Do the work")}), testAgent("worker"))
+	})
+
+	It("renders the commander and agent prompt templates in place of the built-in prompts", func() {
+		cfg.Missions[0].Commander.PromptTemplate = "{{.Default}}\nCOMMANDER TEMPLATE for {{.Mission}}/{{.Task}} with {{range .Agents}}{{.Name}}{{end}}"
+		cfg.Agents[0].PromptTemplate = "AGENT TEMPLATE for {{.Name}} in {{.Mode}} mode"
+
+		provider := newMockProvider(
+			cmdCallAgent("worker", "Do it"),
+			textResponse("<ANSWER>done</ANSWER>"),
+			cmdTaskComplete(),
+		)
+		runner, err := NewRunner(cfg, "", "templated", nil, WithProviderFactory(func() llm.Provider { return provider }))
+		Expect(err).NotTo(HaveOccurred())
+		defer runner.CloseStores()
+		Expect(runner.Run(context.Background(), newMockMissionStreamer())).To(Succeed())
+
+		calls := provider.getCalls()
+		Expect(calls).To(HaveLen(3))
+		commander := systemPrompt(calls[0])
+		Expect(commander).To(ContainSubstring("COMMANDER TEMPLATE for templated/work with worker"))
+		Expect(commander).To(ContainSubstring("MISSION MODE"))
+
+		agent := systemPrompt(calls[1])
+		Expect(agent).To(ContainSubstring("AGENT TEMPLATE for worker in mission mode"))
+		Expect(agent).NotTo(ContainSubstring("MISSION MODE"))
+	})
+
+	It("fails the task when the commander template can't be executed", func() {
+		cfg.Missions[0].Commander.PromptTemplate = "{{.Objective}}"
+
+		provider := newMockProvider(cmdTaskComplete())
+		runner, err := NewRunner(cfg, "", "templated", nil, WithProviderFactory(func() llm.Provider { return provider }))
+		Expect(err).NotTo(HaveOccurred())
+		defer runner.CloseStores()
+		Expect(runner.Run(context.Background(), newMockMissionStreamer())).To(MatchError(ContainSubstring("prompt_template")))
+		Expect(provider.callCount()).To(Equal(0))
+	})
+})
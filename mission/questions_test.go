@@ -0,0 +1,113 @@
+package mission
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"squadron/config"
+	"squadron/llm"
+)
+
+// toolResults returns the contents of every tool result the provider was sent.
+func toolResults(provider *mockProvider) []string {
+	var results []string
+	for _, call := range provider.getCalls() {
+		for _, m := range call.Messages {
+			for _, p := range m.Parts {
+				if p.Type == llm.ContentTypeToolResult && p.ToolResult != nil {
+					results = append(results, p.ToolResult.Content)
+				}
+			}
+		}
+	}
+	return results
+}
+
+func cmdCommanderTool(name string, params map[string]any) mockResponse {
+	input, _ := json.Marshal(params)
+	return mockToolCall(name, input)
+}
+
+var _ = Describe("Commander question persistence", func() {
+	var cfg *config.Config
+
+	BeforeEach(func() {
+		first := testTask("first", "Pick a region")
+		second := testTask("second", "Deploy to the region")
+		second.DependsOn = []string{"first"}
+		cfg = buildTestConfig(testMission("questions", []config.Task{first, second}), testAgent("worker"))
+		cfg.Storage.Path = filepath.Join(GinkgoT().TempDir(), "store.db")
+	})
+
+	It("stores ask_commander questions and answers and restores them on resume", func() {
+		provider := newMockProvider(
+			cmdTaskComplete(),
+			cmdCommanderTool("ask_commander", map[string]any{"task_name": "first", "question": "Which region?"}),
+			withMatch(textResponse("<ANSWER>eu-west-1</ANSWER>"), matchLastUserContains("Another commander is asking")),
+			hangingCall(),
+		)
+		runner, err := NewRunner(cfg, "", "questions", nil, WithProviderFactory(func() llm.Provider { return provider }))
+		Expect(err).NotTo(HaveOccurred())
+		done := make(chan error, 1)
+		go func() { done <- runner.Run(context.Background(), newMockMissionStreamer()) }()
+		Eventually(provider.callCount).Should(Equal(4))
+		runner.Pause()
+		Eventually(done).Should(Receive(MatchError(ErrMissionPaused)))
+
+		missionID := runner.MissionID()
+		questions, err := runner.stores.Questions.GetQuestions(missionID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(questions).To(HaveLen(1))
+		Expect(questions[0].TaskName).To(Equal("first"))
+		Expect(questions[0].IterationIndex).To(BeNil())
+		Expect(questions[0].Question).To(Equal("Which region?"))
+		Expect(questions[0].Answer).NotTo(BeNil())
+		Expect(*questions[0].Answer).To(Equal("eu-west-1"))
+		runner.CloseStores()
+
+		provider = newMockProvider(
+			cmdCommanderTool("list_commander_questions", map[string]any{"task_name": "first"}),
+			cmdCommanderTool("get_commander_answer", map[string]any{"task_name": "first", "index": 0}),
+			cmdTaskComplete(),
+		)
+		resumed, err := NewRunner(cfg, "", "questions", nil, WithResume(missionID), WithProviderFactory(func() llm.Provider { return provider }))
+		Expect(err).NotTo(HaveOccurred())
+		defer resumed.CloseStores()
+		Expect(resumed.Run(context.Background(), newMockMissionStreamer())).To(Succeed())
+
+		results := toolResults(provider)
+		Expect(results).To(ContainElement(ContainSubstring(`0: "Which region?"`)))
+		Expect(results).To(ContainElement("eu-west-1"))
+	})
+
+	It("answers questions left pending by the previous run with an error", func() {
+		provider := newMockProvider(
+			cmdTaskComplete(),
+			cmdCommanderTool("ask_commander", map[string]any{"task_name": "first", "question": "Which region?"}),
+			withMatch(hangingCall(), matchLastUserContains("Another commander is asking")),
+		)
+		runner, err := NewRunner(cfg, "", "questions", nil, WithProviderFactory(func() llm.Provider { return provider }))
+		Expect(err).NotTo(HaveOccurred())
+		done := make(chan error, 1)
+		go func() { done <- runner.Run(context.Background(), newMockMissionStreamer()) }()
+		Eventually(provider.callCount).Should(Equal(3))
+		runner.Pause()
+		Eventually(done).Should(Receive(MatchError(ErrMissionPaused)))
+		missionID := runner.MissionID()
+		runner.CloseStores()
+
+		provider = newMockProvider(
+			cmdCommanderTool("get_commander_answer", map[string]any{"task_name": "first", "index": 0}),
+			cmdTaskComplete(),
+		)
+		resumed, err := NewRunner(cfg, "", "questions", nil, WithResume(missionID), WithProviderFactory(func() llm.Provider { return provider }))
+		Expect(err).NotTo(HaveOccurred())
+		defer resumed.CloseStores()
+		Expect(resumed.Run(context.Background(), newMockMissionStreamer())).To(Succeed())
+		Expect(toolResults(provider)).To(ContainElement(ContainSubstring("ERROR:")))
+	})
+})
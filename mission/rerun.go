@@ -0,0 +1,262 @@
+package mission
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"squadron/agent"
+	"squadron/config"
+	"squadron/failure"
+	"squadron/store"
+	"squadron/streamers"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+// rerunRequest selects iterations of one iterated task to run again on a
+// resumed mission.
+type rerunRequest struct {
+	Task    string
+	ItemIDs []string // empty: every item without a completed output
+}
+
+// WithRerun re-executes iterations of an iterated task in the mission
+// given to WithResume. The selected items' outputs are superseded and
+// produced again; with no itemIDs, only items that failed or never
+// finished run. Items without a current output always run, so the task
+// completes with an output for every item. The mission may already be
+// completed.
+func WithRerun(taskName string, itemIDs []string) RunnerOption {
+	return func(r *Runner) {
+		r.rerun = &rerunRequest{Task: taskName, ItemIDs: itemIDs}
+	}
+}
+
+// prepareRerun reopens the rerun task of a resumed mission: it checks the
+// task and item selection, supersedes the selected items' outputs and
+// resets their status to pending. It must run after the dataset IDs are
+// loaded and before task states are registered.
+func (r *Runner) prepareRerun(missionID string, tasks []store.MissionTask) error {
+	var task *config.Task
+	for i := range r.mission.Tasks {
+		if r.mission.Tasks[i].Name == r.rerun.Task {
+			task = &r.mission.Tasks[i]
+			break
+		}
+	}
+	if task == nil {
+		return fmt.Errorf("rerun: task '%s' not found in mission '%s'", r.rerun.Task, r.mission.Name)
+	}
+	if task.Iterator == nil {
+		return fmt.Errorf("rerun: task '%s' does not iterate over a dataset", task.Name)
+	}
+
+	var taskID string
+	for _, t := range tasks {
+		if t.TaskName == task.Name {
+			taskID = t.ID
+			break
+		}
+	}
+	if taskID == "" {
+		return fmt.Errorf("rerun: task '%s' has not run in mission '%s'", task.Name, missionID)
+	}
+
+	dsID, ok := r.datasetIDs[task.Iterator.Dataset]
+	if !ok {
+		return fmt.Errorf("rerun: dataset '%s' not found", task.Iterator.Dataset)
+	}
+	itemCount, _ := r.stores.Datasets.GetItemCount(dsID)
+	items, err := r.stores.Datasets.GetItems(dsID, 0, itemCount)
+	if err != nil {
+		return fmt.Errorf("rerun: loading dataset '%s': %w", task.Iterator.Dataset, err)
+	}
+
+	outputs, err := r.stores.Missions.GetTaskOutputs(taskID)
+	if err != nil {
+		return fmt.Errorf("rerun: loading outputs of '%s': %w", task.Name, err)
+	}
+	hasOutput := make(map[int]bool)
+	for _, o := range outputs {
+		if o.DatasetIndex != nil {
+			hasOutput[*o.DatasetIndex] = true
+		}
+	}
+
+	selected, err := selectRerunItems(items, r.rerun.ItemIDs)
+	if err != nil {
+		return fmt.Errorf("rerun: task '%s': %w", task.Name, err)
+	}
+	var rerun []int
+	for i := range items {
+		if selected[i] || !hasOutput[i] {
+			rerun = append(rerun, i)
+		}
+	}
+	if len(rerun) == 0 {
+		return fmt.Errorf("rerun: every item of task '%s' already completed; select items with --items", task.Name)
+	}
+
+	if err := r.stores.Missions.SupersedeTaskOutputs(taskID, rerun); err != nil {
+		return fmt.Errorf("rerun: superseding outputs: %w", err)
+	}
+	for _, i := range rerun {
+		r.stores.Datasets.SetItemStatus(taskID, i, getItemID(items[i], i), store.ItemPending, nil)
+	}
+	r.stores.Missions.UpdateTaskStatus(taskID, "stopped", nil, nil)
+	return r.stores.Missions.UpdateMissionStatus(missionID, string(MissionStopped))
+}
+
+// selectRerunItems maps item IDs to dataset indices. An unknown ID is an
+// error so a typo doesn't silently rerun nothing.
+func selectRerunItems(items []cty.Value, itemIDs []string) (map[int]bool, error) {
+	selected := make(map[int]bool)
+	if len(itemIDs) == 0 {
+		return selected, nil
+	}
+	byID := make(map[string][]int, len(items))
+	for i, item := range items {
+		id := getItemID(item, i)
+		byID[id] = append(byID[id], i)
+	}
+	var unknown []string
+	for _, id := range itemIDs {
+		indices, ok := byID[id]
+		if !ok {
+			unknown = append(unknown, id)
+			continue
+		}
+		for _, i := range indices {
+			selected[i] = true
+		}
+	}
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("unknown item IDs: %s", strings.Join(unknown, ", "))
+	}
+	return selected, nil
+}
+
+// setItemStatus records one dataset item's status in an iterated task.
+func (r *Runner) setItemStatus(taskID string, index int, itemID, status string, itemErr error) {
+	var errMsg *string
+	if itemErr != nil {
+		s := itemErr.Error()
+		errMsg = &s
+	}
+	r.stores.Datasets.SetItemStatus(taskID, index, itemID, status, errMsg)
+}
+
+// recordIterationResult settles an iteration's item status from its result.
+// An iteration cut short by cancellation goes back to pending, since a
+// resume runs it again.
+func (r *Runner) recordIterationResult(ctx context.Context, taskID string, result IterationResult) {
+	switch {
+	case result.Success:
+		r.setItemStatus(taskID, result.Index, result.ItemID, store.ItemCompleted, nil)
+	case ctx.Err() != nil && timeoutCause(ctx) == nil:
+		r.setItemStatus(taskID, result.Index, result.ItemID, store.ItemPending, nil)
+	default:
+		r.setItemStatus(taskID, result.Index, result.ItemID, store.ItemFailed, result.Error)
+	}
+}
+
+// settleRunningItems closes out items still marked running after an
+// iterated task stopped short, e.g. the item a sequential commander was on
+// when it failed.
+func (r *Runner) settleRunningItems(ctx context.Context, taskID string, taskErr error) {
+	statuses, err := r.stores.Datasets.GetItemStatuses(taskID)
+	if err != nil {
+		return
+	}
+	for _, s := range statuses {
+		if s.Status != store.ItemRunning {
+			continue
+		}
+		r.recordIterationResult(ctx, taskID, IterationResult{Index: s.Index, ItemID: s.ItemID, Error: taskErr})
+	}
+}
+
+// remainingIterations returns the indices of items without a current
+// output, in dataset order.
+func (r *Runner) remainingIterations(taskID string, itemCount int) (remaining []int, completed map[int]bool) {
+	outputs, _ := r.stores.Missions.GetTaskOutputs(taskID)
+	completed = make(map[int]bool)
+	for _, o := range outputs {
+		if o.DatasetIndex != nil {
+			completed[*o.DatasetIndex] = true
+		}
+	}
+	for i := 0; i < itemCount; i++ {
+		if !completed[i] {
+			remaining = append(remaining, i)
+		}
+	}
+	return remaining, completed
+}
+
+// runIterationsInOrder runs the given iterations of a sequential task one
+// at a time, each with its own commander, stopping at the first failure.
+// Used when a sequential task resumes with gaps (after a rerun), which a
+// single dataset_next commander can't skip over. Each iteration gets the
+// stored output of the item before it, if there is one.
+func (r *Runner) runIterationsInOrder(ctx context.Context, task config.Task, items []cty.Value, indices []int, taskID string, depSummaries []agent.DependencySummary, streamer streamers.MissionHandler) []IterationResult {
+	maxRetries := 0
+	if task.Iterator != nil {
+		maxRetries = task.Iterator.MaxRetries
+	}
+
+	var results []IterationResult
+	for _, index := range indices {
+		prevOutput := r.storedIterationOutput(taskID, index-1)
+
+		var result IterationResult
+		for attempt := 0; attempt <= maxRetries; attempt++ {
+			if err := ctx.Err(); err != nil {
+				return append(results, IterationResult{
+					Index:   index,
+					ItemID:  getItemID(items[index], index),
+					Success: false,
+					Error:   err,
+				})
+			}
+			result = r.runSingleIteration(ctx, task, index, items[index], prevOutput, taskID, "", depSummaries, streamer)
+			if result.Success || !failure.Retryable(result.ErrorCode()) {
+				break
+			}
+			reportIfTimeout(streamer, result.Error, attempt < maxRetries)
+			if attempt < maxRetries {
+				streamer.IterationRetrying(task.Name, index, attempt+1, maxRetries, result.Error)
+			}
+		}
+		results = append(results, result)
+		if !result.Success {
+			break
+		}
+	}
+	return results
+}
+
+// storedIterationOutput returns the current output of iteration index, or
+// nil when there is none.
+func (r *Runner) storedIterationOutput(taskID string, index int) map[string]any {
+	if index < 0 {
+		return nil
+	}
+	outputs, err := r.stores.Missions.GetTaskOutputs(taskID)
+	if err != nil {
+		return nil
+	}
+	for _, o := range outputs {
+		if o.DatasetIndex == nil || *o.DatasetIndex != index {
+			continue
+		}
+		var out map[string]any
+		if err := json.Unmarshal([]byte(o.OutputJSON), &out); err != nil {
+			return nil
+		}
+		return out
+	}
+	return nil
+}
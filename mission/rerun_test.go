@@ -0,0 +1,125 @@
+package mission
+
+import (
+	"context"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/zclconf/go-cty/cty"
+
+	"squadron/config"
+	"squadron/llm"
+	"squadron/store"
+)
+
+var _ = Describe("Rerun", func() {
+	var cfg *config.Config
+
+	buildConfig := func(parallel bool) {
+		task := testTask("process", "Process item")
+		task.Iterator = &config.TaskIterator{Dataset: "items", Parallel: parallel, ConcurrencyLimit: 1}
+		task.Output = &config.OutputSchema{Fields: []config.OutputField{{Name: "result", Type: "string", Required: true}}}
+		mission := testMission("rerunnable", []config.Task{task})
+		mission.Datasets = []config.Dataset{{Name: "items", Items: []cty.Value{
+			cty.ObjectVal(map[string]cty.Value{"id": cty.StringVal("alpha")}),
+			cty.ObjectVal(map[string]cty.Value{"id": cty.StringVal("beta")}),
+		}}}
+		cfg = buildTestConfig(mission, testAgent("worker"))
+		cfg.Storage.Path = filepath.Join(GinkgoT().TempDir(), "store.db")
+	}
+
+	submit := func(result string) []mockResponse {
+		return []mockResponse{cmdSubmitOutput(map[string]interface{}{"result": result}), cmdTaskComplete()}
+	}
+
+	run := func(responses []mockResponse, opts ...RunnerOption) (*Runner, error) {
+		provider := newMockProvider(responses...)
+		opts = append(opts, WithProviderFactory(func() llm.Provider { return provider }))
+		runner, err := NewRunner(cfg, "", "rerunnable", nil, opts...)
+		Expect(err).NotTo(HaveOccurred())
+		return runner, runner.Run(context.Background(), newMockMissionStreamer())
+	}
+
+	// results returns the current output of each item, by item ID.
+	results := func(runner *Runner) map[string]string {
+		task, err := runner.stores.Missions.GetTaskByName(runner.MissionID(), "process")
+		Expect(err).NotTo(HaveOccurred())
+		outputs, err := runner.stores.Missions.GetTaskOutputs(task.ID)
+		Expect(err).NotTo(HaveOccurred())
+		got := map[string]string{}
+		for _, o := range outputs {
+			got[*o.ItemID] = o.OutputJSON
+		}
+		return got
+	}
+
+	statuses := func(runner *Runner) map[string]string {
+		task, err := runner.stores.Missions.GetTaskByName(runner.MissionID(), "process")
+		Expect(err).NotTo(HaveOccurred())
+		rows, err := runner.stores.Datasets.GetItemStatuses(task.ID)
+		Expect(err).NotTo(HaveOccurred())
+		got := map[string]string{}
+		for _, s := range rows {
+			got[s.ItemID] = s.Status
+		}
+		return got
+	}
+
+	// firstRun completes the mission once and returns its ID.
+	firstRun := func(parallel bool) string {
+		// Parallel iterations may take the responses in either order
+		responses := append(submit("run 1"), submit("run 1")...)
+		if !parallel {
+			// One commander pulls every item with dataset_next
+			responses = []mockResponse{
+				cmdDatasetNext(), cmdSubmitOutput(map[string]interface{}{"result": "run 1"}),
+				cmdDatasetNext(), cmdSubmitOutput(map[string]interface{}{"result": "run 1"}),
+				cmdDatasetNext(), cmdTaskComplete(),
+			}
+		}
+		runner, err := run(responses)
+		Expect(err).NotTo(HaveOccurred())
+		defer runner.CloseStores()
+		Expect(statuses(runner)).To(Equal(map[string]string{"alpha": store.ItemCompleted, "beta": store.ItemCompleted}))
+		return runner.MissionID()
+	}
+
+	for _, parallel := range []bool{true, false} {
+		parallel := parallel
+		mode := map[bool]string{true: "parallel", false: "sequential"}[parallel]
+
+		It("reruns the selected items of a completed "+mode+" task and supersedes their outputs", func() {
+			buildConfig(parallel)
+			missionID := firstRun(parallel)
+
+			// Rerunning the first item leaves a gap before a completed one,
+			// which a sequential task runs on its own
+			runner, err := run(submit("run 2"), WithResume(missionID), WithRerun("process", []string{"alpha"}))
+			Expect(err).NotTo(HaveOccurred())
+			defer runner.CloseStores()
+
+			Expect(results(runner)).To(Equal(map[string]string{
+				"alpha": `{"result":"run 2"}`,
+				"beta":  `{"result":"run 1"}`,
+			}))
+			Expect(statuses(runner)).To(Equal(map[string]string{"alpha": store.ItemCompleted, "beta": store.ItemCompleted}))
+			record, err := runner.stores.Missions.GetMission(missionID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(record.Status).To(Equal("completed"))
+		})
+	}
+
+	It("rejects unknown items and a selection with nothing to run", func() {
+		buildConfig(true)
+		missionID := firstRun(true)
+
+		runner, err := run(nil, WithResume(missionID), WithRerun("process", []string{"gamma"}))
+		runner.CloseStores()
+		Expect(err).To(MatchError(ContainSubstring("unknown item IDs: gamma")))
+
+		runner, err = run(nil, WithResume(missionID), WithRerun("process", nil))
+		runner.CloseStores()
+		Expect(err).To(MatchError(ContainSubstring("already completed")))
+	})
+})
@@ -0,0 +1,58 @@
+package mission
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+
+	"squadron/config"
+	"squadron/llm"
+	"squadron/store"
+)
+
+// storeResponseCache keeps LLM responses in the storage backend so a
+// mission's response_cache is shared across runs and processes.
+type storeResponseCache struct {
+	store store.ResponseCacheStore
+	ttl   time.Duration
+	now   func() time.Time
+}
+
+// buildResponseCache returns the mission's response cache, or nil when the
+// mission has no response_cache block. Expired entries are pruned up front.
+func buildResponseCache(mission *config.Mission, stores *store.Bundle) llm.ResponseCache {
+	if mission.ResponseCache == nil || stores == nil || stores.Responses == nil {
+		return nil
+	}
+	c := &storeResponseCache{store: stores.Responses, ttl: mission.ResponseCache.GetTTL(), now: time.Now}
+	if _, err := c.store.PruneCachedResponses(c.now().Add(-c.ttl)); err != nil {
+		log.Printf("[ResponseCache] prune failed: %v", err)
+	}
+	return c
+}
+
+func (c *storeResponseCache) Get(key string) (*llm.CachedResponse, bool) {
+	rec, err := c.store.GetCachedResponse(key)
+	if err != nil {
+		log.Printf("[ResponseCache] lookup failed: %v", err)
+		return nil, false
+	}
+	if rec == nil || c.now().Sub(rec.CreatedAt) > c.ttl {
+		return nil, false
+	}
+	var resp llm.CachedResponse
+	if err := json.Unmarshal([]byte(rec.ResponseJSON), &resp); err != nil {
+		return nil, false
+	}
+	return &resp, true
+}
+
+func (c *storeResponseCache) Put(key string, resp *llm.CachedResponse) {
+	b, err := json.Marshal(resp)
+	if err != nil {
+		return
+	}
+	if err := c.store.PutCachedResponse(store.CachedResponseRecord{Key: key, Model: resp.Model, ResponseJSON: string(b)}); err != nil {
+		log.Printf("[ResponseCache] store failed: %v", err)
+	}
+}
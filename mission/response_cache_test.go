@@ -0,0 +1,47 @@
+package mission
+
+import (
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"squadron/config"
+	"squadron/llm"
+	"squadron/store"
+)
+
+var _ = Describe("Response cache", func() {
+	var bundle *store.Bundle
+
+	BeforeEach(func() {
+		var err error
+		bundle, err = store.NewSQLiteBundle(filepath.Join(GinkgoT().TempDir(), "store.db"))
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(bundle.Close)
+	})
+
+	It("is nil without a response_cache block", func() {
+		Expect(buildResponseCache(&config.Mission{}, bundle)).To(BeNil())
+	})
+
+	It("round-trips responses through the store and honors ttl", func() {
+		cache := buildResponseCache(&config.Mission{ResponseCache: &config.ResponseCache{TTL: "1h"}}, bundle)
+		Expect(cache).NotTo(BeNil())
+
+		cache.Put("k", &llm.CachedResponse{Model: "m", Content: "hello", FinishReason: "end_turn"})
+		got, ok := cache.Get("k")
+		Expect(ok).To(BeTrue())
+		Expect(got.Content).To(Equal("hello"))
+		Expect(got.FinishReason).To(Equal("end_turn"))
+
+		_, ok = cache.Get("other")
+		Expect(ok).To(BeFalse())
+
+		// Entries older than the ttl are ignored
+		cache.(*storeResponseCache).now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+		_, ok = cache.Get("k")
+		Expect(ok).To(BeFalse())
+	})
+})
@@ -0,0 +1,58 @@
+package mission
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"squadron/config"
+	"squadron/llm"
+)
+
+var _ = Describe("Provider retries", func() {
+	It("retries a rate-limited call per the model retry block and reports it as a warning", func() {
+		cfg := buildTestConfig(testMission("retry", []config.Task{testTask("work", "Do the work")}), testAgent("worker"))
+		cfg.Models[0].Retry = &config.ModelRetry{MaxAttempts: 3, InitialBackoff: "1ms"}
+
+		provider := newMockProvider(
+			mockResponse{Err: errors.New(`POST "https://api.anthropic.com/v1/messages": 429 Too Many Requests`)},
+			cmdTaskComplete(),
+		)
+		runner, err := NewRunner(cfg, "", "retry", nil, WithProviderFactory(func() llm.Provider { return provider }))
+		Expect(err).NotTo(HaveOccurred())
+		defer runner.CloseStores()
+
+		streamer := newMockMissionStreamer()
+		Expect(runner.Run(context.Background(), streamer)).To(Succeed())
+		Expect(provider.callCount()).To(Equal(2))
+
+		var issues []streamEvent
+		for _, e := range streamer.getEvents() {
+			if e.Type == "mission_issue" {
+				issues = append(issues, e)
+			}
+		}
+		Expect(issues).To(HaveLen(1))
+		Expect(issues[0].Data["severity"]).To(Equal("warning"))
+		Expect(issues[0].Data["category"]).To(Equal("provider_error"))
+		Expect(issues[0].Data["task"]).To(Equal("work"))
+		Expect(issues[0].Data["message"]).To(ContainSubstring("attempt 1/3"))
+	})
+
+	It("fails the task once max_attempts is used up", func() {
+		cfg := buildTestConfig(testMission("retry", []config.Task{testTask("work", "Do the work")}), testAgent("worker"))
+		cfg.Models[0].Retry = &config.ModelRetry{MaxAttempts: 2, InitialBackoff: "1ms"}
+
+		unavailable := mockResponse{Err: errors.New(`POST "https://api.anthropic.com/v1/messages": 503 Service Unavailable`)}
+		provider := newMockProvider(unavailable, unavailable, unavailable)
+		runner, err := NewRunner(cfg, "", "retry", nil, WithProviderFactory(func() llm.Provider { return provider }))
+		Expect(err).NotTo(HaveOccurred())
+		defer runner.CloseStores()
+
+		err = runner.Run(context.Background(), newMockMissionStreamer())
+		Expect(err).To(MatchError(ContainSubstring("503")))
+		Expect(provider.callCount()).To(Equal(2))
+	})
+})
@@ -3,20 +3,27 @@ package mission
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
 	"sync"
 	"time"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/mlund01/squadron-wire/protocol"
 	"github.com/zclconf/go-cty/cty"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	"squadron/agent"
 	"squadron/aitools"
 	"squadron/config"
+	"squadron/failure"
 	"squadron/llm"
 	"squadron/store"
 	"squadron/streamers"
+	"squadron/streamers/webhook"
+	"squadron/tracing"
 )
 
 // Runner executes a mission by orchestrating commanders for each task
@@ -62,10 +69,17 @@ type Runner struct {
 	// Resume support
 	resumeMissionID string            // Non-empty when resuming a prior mission
 	rawInputs       map[string]string // Raw input strings for persistence/resume
+	rerun           *rerunRequest     // Iterations to run again on resume (WithRerun)
 
 	// Memory access for mission
 	memoryStore aitools.MemoryStore
 
+	// Semantic memory for memory_put / memory_search (nil without a knowledge block)
+	knowledgeBase aitools.KnowledgeBase
+
+	// Files for artifact_put / artifact_get (nil unless a task has a `file` output)
+	artifacts aitools.Artifacts
+
 	// Conditional routing state
 	routerPending []routerActivation // queue of tasks activated by routers
 	routerParents map[string]string  // taskName → routerTaskName that activated it
@@ -77,11 +91,26 @@ type Runner struct {
 	// Provider factory for testing — when set, commanders and agents use this instead of creating real providers
 	providerFactory func() llm.Provider
 
+	// Failure injection for testing (WithChaos); nil outside chaos runs
+	chaos *chaosInjector
+
+	// Looks up a browser plugin by name for failure capture; nil uses the
+	// config's loaded plugins (tests substitute fakes)
+	browserPlugin func(name string) browserCaller
+
+	// Delivers submitted task outputs to output_sink blocks; nil when no
+	// task has one
+	sinks *outputSinks
+
 	// HumanBridge powers builtins.human.ask on agents spawned by
 	// this mission. Nil when no commander is attached (e.g. CLI runs);
 	// the tool then surfaces "[no human available]" instead of blocking.
 	humanBridge aitools.HumanInputBridge
 
+	// Approver reviews the draft result of require_approval tasks. Falls
+	// back to a BridgeApprover over humanBridge when unset.
+	approver Approver
+
 	// Task state manager — single authority for task lifecycle
 	stateMgr *TaskStateManager
 
@@ -89,9 +118,37 @@ type Runner struct {
 	drainCh   chan struct{}
 	drainOnce sync.Once
 
+	// Pause state — set by Pause; cancelRun cancels the mission-scoped context
+	// of the current Run so in-flight commanders stop at their current call.
+	pauseMu   sync.Mutex
+	paused    bool
+	cancelRun context.CancelFunc
+
+	// How often Run checks the store for a pause requested by another
+	// process
+	pauseRequestPollInterval time.Duration
+
+	// Set by Cancel (guarded by pauseMu); collects partial summaries of
+	// interrupted commanders
+	cancelled *cancellation
+
+	// Prior missions referenced via WithPriorMissions. Their commanders are
+	// revived lazily into priorCommanders (keyed "missionID/task" or
+	// "missionID/task[i]") on the first ask_prior_commander question.
+	priorMissionIDs []string
+	priorMissions   []agent.PriorMissionInfo
+	priorMu         sync.Mutex
+	priorCommanders map[string]*agent.Commander
+
 	// Budget tracker — nil when neither the mission nor any task declares a budget.
 	// First breach cancels the mission-scoped context and fails the mission.
 	budgetTracker *BudgetTracker
+
+	// Shared cap on concurrent LLM calls (max_parallel_llm_calls) — nil when unset
+	callLimiter *llm.CallLimiter
+
+	// Store-backed LLM response cache (response_cache block) — nil when unset
+	responseCache llm.ResponseCache
 }
 
 // routerActivation represents a task activated by a router
@@ -108,6 +165,7 @@ type askCommanderStore struct {
 
 // questionEntry represents a question asked to a dependency commander
 type questionEntry struct {
+	ID       string // Store record ID ("" if the question wasn't persisted)
 	Question string
 	Answer   string
 	Ready    chan struct{} // Closed when answer is ready
@@ -149,10 +207,19 @@ func WithHumanBridge(bridge aitools.HumanInputBridge) RunnerOption {
 	}
 }
 
+// WithApprover sets who reviews tasks with require_approval. Without it,
+// approvals go through the human-input bridge when one is set; a mission
+// with such tasks and neither fails to start.
+func WithApprover(approver Approver) RunnerOption {
+	return func(r *Runner) {
+		r.approver = approver
+	}
+}
+
 // testProvider returns a provider from the factory if set, or nil (letting the commander/agent create its own).
 func (r *Runner) testProvider() llm.Provider {
 	if r.providerFactory != nil {
-		return r.providerFactory()
+		return r.chaos.wrapProvider(r.providerFactory())
 	}
 	return nil
 }
@@ -177,6 +244,11 @@ type TaskResult struct {
 	MissionInputs  map[string]string // inputs for the mission route (nil for task routes)
 }
 
+// ErrorCode returns the failure class of the task's error ("" if it succeeded).
+func (t *TaskResult) ErrorCode() failure.Code {
+	return failure.CodeOf(t.Error)
+}
+
 // IterationResult holds the outcome of a single iteration
 type IterationResult struct {
 	Index   int
@@ -186,6 +258,11 @@ type IterationResult struct {
 	Error   error
 }
 
+// ErrorCode returns the failure class of the iteration's error ("" if it succeeded).
+func (it *IterationResult) ErrorCode() failure.Code {
+	return failure.CodeOf(it.Error)
+}
+
 // IteratedTaskResult holds the outcome of an iterated task
 type IteratedTaskResult struct {
 	TaskName   string
@@ -229,6 +306,8 @@ func NewRunner(cfg *config.Config, configPath string, missionName string, inputs
 		},
 		routerParents: make(map[string]string),
 		drainCh:       make(chan struct{}),
+
+		pauseRequestPollInterval: defaultPauseRequestPollInterval,
 	}
 
 	// Apply options (must happen before input/dataset resolution so resumeMissionID is set)
@@ -238,6 +317,12 @@ func NewRunner(cfg *config.Config, configPath string, missionName string, inputs
 
 	// Build budget tracker (nil if no budgets declared — zero overhead in that case)
 	r.budgetTracker = NewBudgetTracker(mission)
+	r.callLimiter = llm.NewCallLimiter(mission.MaxParallelLLMCalls)
+	r.responseCache = buildResponseCache(mission, r.stores)
+
+	if err := r.loadPriorMissions(); err != nil {
+		return nil, fmt.Errorf("mission '%s': %w", missionName, err)
+	}
 
 	// Build pricing overrides from model config
 	configOverrides := config.BuildPricingOverrides(cfg.Models)
@@ -322,6 +407,18 @@ func resolveDatasets(mission *config.Mission, inputValues map[string]cty.Value)
 		} else if len(ds.Items) > 0 {
 			// Use inline items
 			items = ds.Items
+		} else if ds.Source != nil {
+			// Load from CSV/JSONL/HTTP — LoadSource validates against the schema
+			loaded, err := ds.LoadSource(context.Background())
+			if err != nil {
+				return nil, fmt.Errorf("dataset '%s': %w", ds.Name, err)
+			}
+			items = loaded
+		}
+
+		items, err := ds.ApplyTransforms(items, nil)
+		if err != nil {
+			return nil, fmt.Errorf("dataset '%s': %w", ds.Name, err)
 		}
 
 		// Validate items against schema if present
@@ -347,6 +444,18 @@ func (r *Runner) CostStore() store.CostStore {
 	return r.stores.Costs
 }
 
+// MissionStore returns the runner's mission store, e.g. to read task results
+// after Run returns. Use it before CloseStores.
+func (r *Runner) MissionStore() store.MissionStore {
+	return r.stores.Missions
+}
+
+// MissionID returns the ID of the mission record, set once Run has created
+// (or resumed) it.
+func (r *Runner) MissionID() string {
+	return r.missionID
+}
+
 // CloseStores closes the underlying data stores. Call after Run returns and all events are flushed.
 func (r *Runner) CloseStores() {
 	r.stores.Close()
@@ -385,12 +494,67 @@ func (r *Runner) NextMissionInputs() map[string]string {
 
 // Run executes the mission.
 // The caller is responsible for closing r.stores after Run returns and all events are flushed.
-func (r *Runner) Run(ctx context.Context, streamer streamers.MissionHandler) error {
+func (r *Runner) Run(ctx context.Context, streamer streamers.MissionHandler) (runErr error) {
+	if r.approver == nil && r.humanBridge != nil {
+		r.approver = &BridgeApprover{Bridge: r.humanBridge}
+	}
+	if r.approver == nil {
+		for _, task := range r.mission.Tasks {
+			if task.RequireApproval {
+				return fmt.Errorf("task '%s' requires approval but no approver is available (run interactively or connect to a command center)", task.Name)
+			}
+		}
+	}
+
+	// Export spans if the config asks for it. Flush is deferred before the
+	// mission span ends, so it runs after and sends the whole trace.
+	if err := tracing.Configure(ctx, r.cfg.Observability.TracingOptions()); err != nil {
+		return err
+	}
+	defer tracing.Flush(ctx)
+
+	// Fan events out to the mission's event_log files too. Every handler is
+	// isolated, so one that panics can't take the mission down.
+	handlers := []streamers.MissionHandler{streamer}
+	if r.mission.Notifications != nil && len(r.mission.Notifications.EventLogs) > 0 {
+		logs, closeLogs, err := openEventLogs(r.mission.Notifications.EventLogs)
+		if err != nil {
+			return fmt.Errorf("mission '%s': %w", r.mission.Name, err)
+		}
+		defer closeLogs()
+		handlers = append(handlers, logs...)
+	}
+	streamer = streamers.NewMultiHandler(handlers...)
+
+	// Deliver lifecycle events to the mission's webhooks, if any. Close is
+	// deferred before the mission_failed event below, so it runs after it.
+	if r.mission.Notifications != nil && len(r.mission.Notifications.Webhooks) > 0 {
+		notifier := webhook.NewMissionHandler(streamer, r.mission.Notifications.Webhooks)
+		defer notifier.Close()
+		streamer = notifier
+	}
+
+	// Deliver task outputs to their output sinks as they are submitted.
+	// Close is deferred after the webhook's, so queued outputs flush first.
+	if r.hasOutputSinks() {
+		r.sinks = newOutputSinks(streamer)
+		defer r.sinks.Close()
+	}
 
 	// Derive a mission-scoped context so the budget tracker can cancel every in-flight
 	// commander and agent the moment a task or mission budget is breached.
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
+	defer r.closePriorCommanders()
+	r.setRunCancel(cancel)
+	if r.chaos != nil {
+		ctx = r.chaos.start(ctx, cancel)
+		defer func() {
+			if runErr != nil && r.chaos.wasKilled() {
+				runErr = ErrChaosKilled
+			}
+		}()
+	}
 	if r.budgetTracker != nil {
 		r.budgetTracker.SetCancel(cancel)
 		r.budgetTracker.SetOnBreach(func(b *BudgetBreach) {
@@ -403,10 +567,11 @@ func (r *Runner) Run(ctx context.Context, streamer streamers.MissionHandler) err
 				Message:  b.Error(),
 				TaskName: b.TaskName,
 				Details: map[string]any{
-					"scope": b.Scope,
-					"kind":  b.Kind,
-					"used":  b.Used,
-					"limit": b.Limit,
+					"scope":     b.Scope,
+					"kind":      b.Kind,
+					"used":      b.Used,
+					"limit":     b.Limit,
+					"breakdown": b.Breakdown,
 				},
 			})
 		})
@@ -435,9 +600,12 @@ func (r *Runner) Run(ctx context.Context, streamer streamers.MissionHandler) err
 		if record.MissionName != r.mission.Name {
 			return fmt.Errorf("resume: mission name mismatch: store has '%s', config has '%s'", record.MissionName, r.mission.Name)
 		}
-		if record.Status == "completed" {
+		if record.Status == "completed" && r.rerun == nil {
 			return fmt.Errorf("resume: mission '%s' is already completed", missionID)
 		}
+		if record.CompactedAt != nil {
+			return fmt.Errorf("resume: mission '%s' was compacted by store gc and its sessions are gone", missionID)
+		}
 
 		// Load raw inputs from store and re-resolve
 		var rawInputs map[string]string
@@ -464,9 +632,12 @@ func (r *Runner) Run(ctx context.Context, streamer streamers.MissionHandler) err
 				Description: input.Description,
 			})
 		}
+		if err := r.resolveConfigSecrets(ctx); err != nil {
+			return fmt.Errorf("resume: resolving secrets: %w", err)
+		}
 
 		// Initialize store-backed knowledge store
-		r.knowledgeStore = &PersistentKnowledgeStore{MissionID: missionID, Store: r.stores.Missions}
+		r.knowledgeStore = &PersistentKnowledgeStore{MissionID: missionID, Store: r.stores.Missions, IterationSummaries: r.iterationSummaries}
 
 		// Load dataset IDs from store
 		for _, ds := range r.mission.Datasets {
@@ -482,16 +653,32 @@ func (r *Runner) Run(ctx context.Context, streamer streamers.MissionHandler) err
 		if err != nil {
 			return fmt.Errorf("resume: loading tasks: %w", err)
 		}
+		if r.rerun != nil {
+			if err := r.prepareRerun(missionID, tasks); err != nil {
+				return err
+			}
+			// prepareRerun reopened the task and the mission; reload so
+			// both register as stopped
+			if tasks, err = r.stores.Missions.GetTasksByMission(missionID); err != nil {
+				return fmt.Errorf("resume: loading tasks: %w", err)
+			}
+			record.Status = string(MissionStopped)
+		}
 		for _, t := range tasks {
 			existingTaskIDs[t.TaskName] = t.ID
 			// Register with actual DB status so CAS transitions match
 			switch t.Status {
 			case "completed":
 				stateMgr.RegisterTask(t.TaskName, t.ID, TaskCompleted)
+			case "skipped":
+				stateMgr.RegisterTask(t.TaskName, t.ID, TaskSkipped)
+				r.taskSummaries[t.TaskName] = skippedTaskSummary
 			case "stopped":
 				stateMgr.RegisterTask(t.TaskName, t.ID, TaskStopped)
 			case "failed":
 				stateMgr.RegisterTask(t.TaskName, t.ID, TaskFailed)
+			case "cancelled":
+				stateMgr.RegisterTask(t.TaskName, t.ID, TaskCancelled)
 			case "running":
 				// Was running when process died — treat as stopped
 				stateMgr.RegisterTask(t.TaskName, t.ID, TaskStopped)
@@ -521,7 +708,8 @@ func (r *Runner) Run(ctx context.Context, streamer streamers.MissionHandler) err
 		sortedTasks := r.mission.TopologicalSort()
 		var completedNames []string
 		for _, t := range sortedTasks {
-			if stateMgr.IsCompleted(t.Name) {
+			// Skipped tasks never ran, so there is no commander to restore
+			if state, _ := stateMgr.GetTaskState(t.Name); state == TaskCompleted {
 				completedNames = append(completedNames, t.Name)
 			}
 		}
@@ -529,10 +717,28 @@ func (r *Runner) Run(ctx context.Context, streamer streamers.MissionHandler) err
 			return fmt.Errorf("resume: resaturating commanders: %w", err)
 		}
 
+		// Restore the questions asked of dependency commanders so iterations
+		// keep sharing answers from the previous run
+		if err := r.loadCommanderQuestions(missionID); err != nil {
+			return fmt.Errorf("resume: loading commander questions: %w", err)
+		}
+
 		stateMgr.missionState = MissionStopped // resume from stopped
+		switch record.Status {
+		case string(MissionPaused):
+			stateMgr.missionState = MissionPaused
+		case string(MissionCancelled):
+			stateMgr.missionState = MissionCancelled
+		case string(MissionBudgetExceeded):
+			stateMgr.missionState = MissionBudgetExceeded
+		}
 		_ = stateMgr.TransitionMission(MissionRunning)
 	} else {
 		// === FRESH PATH ===
+		if err := r.resolveConfigSecrets(ctx); err != nil {
+			return fmt.Errorf("mission '%s': resolving secrets: %w", r.mission.Name, err)
+		}
+
 		rawInputsJSON, _ := json.Marshal(r.rawInputs)
 		configJSON, _ := json.Marshal(r.missionSnapshot())
 		var err error
@@ -546,7 +752,7 @@ func (r *Runner) Run(ctx context.Context, streamer streamers.MissionHandler) err
 		r.stateMgr = stateMgr
 
 		// Initialize store-backed knowledge store
-		r.knowledgeStore = &PersistentKnowledgeStore{MissionID: missionID, Store: r.stores.Missions}
+		r.knowledgeStore = &PersistentKnowledgeStore{MissionID: missionID, Store: r.stores.Missions, IterationSummaries: r.iterationSummaries}
 
 		// Persist datasets to store
 		for _, ds := range r.mission.Datasets {
@@ -569,9 +775,10 @@ func (r *Runner) Run(ctx context.Context, streamer streamers.MissionHandler) err
 	}
 
 	// Memory store depends on missionID (for the scratchpad path), so build
-	// it here rather than in NewRunner. Sweep expired scratchpads async —
-	// the result doesn't affect this run's correctness, only disk usage.
-	if r.mission.Scratchpad {
+	// it here rather than in NewRunner. Sweep expired scratchpads and
+	// workspaces async — the result doesn't affect this run's correctness,
+	// only disk usage.
+	if r.mission.Scratchpad || r.mission.Workspace != nil {
 		go func() { _, _ = SweepExpiredScratchpads() }()
 	}
 	memoryStore, err := buildMemoryStore(r.mission, r.cfg.Memories, r.cfg.Packets, missionID)
@@ -580,7 +787,37 @@ func (r *Runner) Run(ctx context.Context, streamer streamers.MissionHandler) err
 	}
 	r.memoryStore = memoryStore
 
+	knowledgeBase, err := buildKnowledgeBase(ctx, r.mission, r.cfg.Models, r.stores, missionID)
+	if err != nil {
+		return fmt.Errorf("mission '%s': build knowledge base: %w", r.mission.Name, err)
+	}
+	r.knowledgeBase = knowledgeBase
+	r.artifacts = buildArtifacts(r.mission, r.stores, missionID)
+
+	ctx, span := tracing.Start(ctx, "mission "+r.mission.Name,
+		attribute.String("squadron.mission", r.mission.Name),
+		attribute.String("squadron.mission_id", missionID),
+		attribute.Bool("squadron.mission.resumed", r.resumeMissionID != ""),
+	)
+	defer func() { tracing.End(span, runErr) }()
+
+	// Copy debug logs to the object store once the run ends, however it ends
+	if r.debugLogger != nil && r.debugLogger.IsEnabled() && r.stores != nil && r.stores.Objects != nil {
+		defer func() {
+			if err := r.debugLogger.Upload(r.stores.Objects, "debug/"+missionID+"/"); err != nil {
+				log.Printf("[Debug] upload debug logs: %v", err)
+			}
+		}()
+	}
+
 	streamer.MissionStarted(r.mission.Name, missionID, len(r.mission.Tasks))
+	if fh, ok := streamer.(streamers.MissionFailureHandler); ok {
+		defer func() {
+			if runErr != nil && !errors.Is(runErr, ErrMissionPaused) && !errors.Is(runErr, ErrMissionCancelled) {
+				fh.MissionFailed(r.mission.Name, runErr)
+			}
+		}()
+	}
 
 	// Log mission start event
 	if r.debugLogger != nil {
@@ -600,6 +837,7 @@ func (r *Runner) Run(ctx context.Context, streamer streamers.MissionHandler) err
 			sortedTasks = append(sortedTasks, t)
 		}
 	}
+	priorities := taskPriorities(r.mission)
 
 	// Create a wait group for all tasks
 	var wg sync.WaitGroup
@@ -634,22 +872,77 @@ func (r *Runner) Run(ctx context.Context, streamer streamers.MissionHandler) err
 		return nil
 	}
 
+	// pauseMission waits for in-flight tasks to wind down and records the
+	// mission as paused so a later run can pick it up with WithResume.
+	pauseMission := func() error {
+		stateMgr.StopAll()
+		wg.Wait()
+		r.stores.Missions.UpdateMissionStatus(missionID, string(MissionPaused))
+		stateMgr.missionState = MissionPaused
+		return ErrMissionPaused
+	}
+
+	// cancelMission waits for in-flight tasks to record their partial
+	// results and marks the mission cancelled.
+	cancelMission := func() error {
+		stateMgr.StopAll()
+		wg.Wait()
+		reason := r.cancelReason()
+		r.stores.Missions.UpdateMissionStatus(missionID, string(MissionCancelled))
+		stateMgr.missionState = MissionCancelled
+		if ch, ok := streamer.(streamers.CancelHandler); ok {
+			ch.MissionCancelled(streamers.MissionCancelledData{MissionName: r.mission.Name, Reason: reason})
+		}
+		if r.debugLogger != nil {
+			r.debugLogger.LogEvent(EventMissionCancelled, map[string]any{
+				"mission": r.mission.Name,
+				"reason":  reason,
+			})
+		}
+		return fmt.Errorf("%w: %s", ErrMissionCancelled, reason)
+	}
+
+	// Pick up pause requests made from another process (squadron pause).
+	// Run waits for the watcher, so it never outlives the run.
+	watchCtx, stopWatch := context.WithCancel(ctx)
+	watchDone := make(chan struct{})
+	go func() {
+		defer close(watchDone)
+		r.watchPauseRequests(watchCtx, missionID)
+	}()
+	defer func() {
+		stopWatch()
+		<-watchDone
+	}()
+
 	// Process tasks, launching parallel tasks when their dependencies are met
 	for !isLoopDone() {
 		// Check for drain signal — wait for in-flight tasks then stop gracefully
 		select {
 		case <-r.drainCh:
+			if r.IsCancelled() {
+				return cancelMission()
+			}
+			if r.IsPaused() {
+				return pauseMission()
+			}
 			stateMgr.StopAll()
 			wg.Wait()
 			r.stores.Missions.UpdateMissionStatus(missionID, "stopped")
 			stateMgr.missionState = MissionStopped
 			return fmt.Errorf("mission stopped")
 		case <-ctx.Done():
+			if r.IsCancelled() {
+				return cancelMission()
+			}
+			if r.IsPaused() {
+				return pauseMission()
+			}
 			stateMgr.StopAll()
 			wg.Wait()
 			if err := budgetFail(); err != nil {
-				r.stores.Missions.UpdateMissionStatus(missionID, "failed")
-				stateMgr.missionState = MissionFailed
+				r.stores.Missions.UpdateMissionStatus(missionID, string(MissionBudgetExceeded))
+				stateMgr.missionState = MissionBudgetExceeded
 				return err
 			}
 			r.stores.Missions.UpdateMissionStatus(missionID, "stopped")
@@ -683,6 +976,7 @@ func (r *Runner) Run(ctx context.Context, streamer streamers.MissionHandler) err
 		var pendingCopy []routerActivation
 		pendingCopy = append(pendingCopy, r.routerPending...)
 		r.routerPending = nil
+		routed := make(map[string]routerActivation)
 		for _, activation := range pendingCopy {
 			if stateMgr.IsCompleted(activation.TaskName) || stateMgr.IsInFlight(activation.TaskName) {
 				continue
@@ -690,6 +984,24 @@ func (r *Runner) Run(ctx context.Context, streamer streamers.MissionHandler) err
 			task := r.mission.GetTaskByName(activation.TaskName)
 			if task != nil {
 				readyTasks = append(readyTasks, *task)
+				routed[task.Name] = activation
+			}
+		}
+
+		// Highest priority first, so it gets the slots max_parallel_tasks leaves
+		sortByPriority(readyTasks, priorities)
+
+		// Hold back tasks beyond max_parallel_tasks; they launch as slots free up.
+		// Router activations that don't fit go back on the queue.
+		if limit := r.mission.MaxParallelTasks; limit > 0 {
+			free := max(limit-stateMgr.InFlightCount(), 0)
+			if len(readyTasks) > free {
+				for _, task := range readyTasks[free:] {
+					if activation, ok := routed[task.Name]; ok {
+						r.routerPending = append(r.routerPending, activation)
+					}
+				}
+				readyTasks = readyTasks[:free]
 			}
 		}
 
@@ -698,24 +1010,43 @@ func (r *Runner) Run(ctx context.Context, streamer streamers.MissionHandler) err
 			select {
 			case err := <-errChan:
 				if err != nil {
+					if r.IsCancelled() {
+						return cancelMission()
+					}
+					if r.IsPaused() {
+						return pauseMission()
+					}
 					if budgetErr := budgetFail(); budgetErr != nil {
-						err = budgetErr
+						r.stores.Missions.UpdateMissionStatus(missionID, string(MissionBudgetExceeded))
+						return budgetErr
 					}
 					r.stores.Missions.UpdateMissionStatus(missionID, "failed")
 					return err
 				}
 			case <-r.drainCh:
+				if r.IsCancelled() {
+					return cancelMission()
+				}
+				if r.IsPaused() {
+					return pauseMission()
+				}
 				stateMgr.StopAll()
 				wg.Wait()
 				r.stores.Missions.UpdateMissionStatus(missionID, "stopped")
 				stateMgr.missionState = MissionStopped
 				return fmt.Errorf("mission stopped")
 			case <-ctx.Done():
+				if r.IsCancelled() {
+					return cancelMission()
+				}
+				if r.IsPaused() {
+					return pauseMission()
+				}
 				stateMgr.StopAll()
 				wg.Wait()
 				if err := budgetFail(); err != nil {
-					r.stores.Missions.UpdateMissionStatus(missionID, "failed")
-					stateMgr.missionState = MissionFailed
+					r.stores.Missions.UpdateMissionStatus(missionID, string(MissionBudgetExceeded))
+					stateMgr.missionState = MissionBudgetExceeded
 					return err
 				}
 				r.stores.Missions.UpdateMissionStatus(missionID, "stopped")
@@ -745,14 +1076,24 @@ func (r *Runner) Run(ctx context.Context, streamer streamers.MissionHandler) err
 				// Run the task (regular or iterated)
 				// Each task queries its ancestors internally using the pull model
 				var result *TaskResult
-				var err error
 
 				existingTaskID := existingTaskIDs[task.Name]
-				if task.Iterator != nil {
-					result, err = r.runIteratedTask(ctx, task, missionID, existingTaskID, streamer)
-				} else {
-					result, err = r.runTask(ctx, task, missionID, existingTaskID, streamer)
+				taskCtx, span := tracing.Start(ctx, "task "+task.Name,
+					attribute.String("squadron.task", task.Name),
+					attribute.Bool("squadron.task.iterated", task.Iterator != nil),
+				)
+				// Plugin tool calls on this task carry its env / working_dir
+				taskCtx = aitools.WithToolEnv(taskCtx, task.GetToolEnv())
+				skipped, err := r.skipIfGuardFalse(task, missionID, existingTaskID, streamer)
+				if err == nil && !skipped {
+					if task.Iterator != nil {
+						result, err = r.runIteratedTask(taskCtx, task, missionID, existingTaskID, streamer)
+					} else {
+						result, err = r.runTask(taskCtx, task, missionID, existingTaskID, streamer)
+					}
 				}
+				span.SetAttributes(attribute.Bool("squadron.task.skipped", skipped))
+				tracing.End(span, err)
 
 				if err != nil {
 					// A budget breach cancels the mission context — tasks that observe
@@ -762,7 +1103,11 @@ func (r *Runner) Run(ctx context.Context, streamer streamers.MissionHandler) err
 					// non-stop reason today; if more "fail-fast" sources appear they
 					// should follow the same pattern.
 					budgetBreach := r.budgetTracker.Breach() != nil
-					if ctx.Err() != nil && !budgetBreach {
+					if ctx.Err() != nil && r.IsCancelled() {
+						stateMgr.ForceState(task.Name, TaskCancelled)
+						r.finishCancelledTask(task.Name, stateMgr.GetTaskID(task.Name), streamer)
+						errChan <- ctx.Err()
+					} else if ctx.Err() != nil && !budgetBreach {
 						// Mission was stopped — mark task as stopped
 						stateMgr.ForceState(task.Name, TaskStopped)
 						if tid := stateMgr.GetTaskID(task.Name); tid != "" {
@@ -773,16 +1118,26 @@ func (r *Runner) Run(ctx context.Context, streamer streamers.MissionHandler) err
 						stateMgr.ForceState(task.Name, TaskFailed)
 						if tid := stateMgr.GetTaskID(task.Name); tid != "" {
 							errMsg := err.Error()
+							code := failure.CodeOf(err)
 							if budgetBreach {
 								errMsg = r.budgetTracker.Breach().Error()
+								code = failure.CodeBudgetExceeded
 							}
 							r.stores.Missions.UpdateTaskStatus(tid, "failed", nil, &errMsg)
+							r.stores.Missions.SetTaskErrorCode(tid, string(code))
 						}
 						errChan <- fmt.Errorf("task '%s' failed: %w", task.Name, err)
 					}
 					return
 				}
 
+				if skipped {
+					// A skipped task activates no routes (DB already updated by skipIfGuardFalse)
+					stateMgr.ForceState(task.Name, TaskSkipped)
+					errChan <- nil
+					return
+				}
+
 				// Handle route activation
 				if task.Router != nil && (result.ChosenRoute == "" || result.ChosenRoute == "none") {
 					// Router chose "none" — emit event so UI can show terminal state
@@ -888,7 +1243,8 @@ func (r *Runner) Run(ctx context.Context, streamer streamers.MissionHandler) err
 	for err := range errChan {
 		if err != nil {
 			if budgetErr := budgetFail(); budgetErr != nil {
-				err = budgetErr
+				_ = stateMgr.TransitionMission(MissionBudgetExceeded)
+				return budgetErr
 			}
 			_ = stateMgr.TransitionMission(MissionFailed)
 			return err
@@ -898,6 +1254,8 @@ func (r *Runner) Run(ctx context.Context, streamer streamers.MissionHandler) err
 	// Cleanup iteration commanders now that all tasks are complete
 	r.cleanupIterationCommanders()
 
+	r.exportDatasets(streamer)
+
 	r.stores.Missions.UpdateMissionStatus(missionID, "completed")
 	streamer.MissionCompleted(r.mission.Name)
 
@@ -979,39 +1337,48 @@ func (r *Runner) resaturateCommanders(ctx context.Context, completedTaskNames []
 		depOutputSchemas := r.collectDepOutputSchemas(taskName)
 		taskOutputSchema := r.getTaskOutputSchema(*task)
 
-		agents := task.Agents
-		if len(agents) == 0 {
-			agents = r.mission.Agents
-		}
+		agents, agentReasons := r.mission.TaskAgents(task, r.cfg.Agents)
 
 		// Determine if this was an iterated task
 		isIterated := task.Iterator != nil
 
 		// Create commander with same config (gets correct system prompts, tools, provider)
 		sup, err := agent.NewCommander(ctx, agent.CommanderOptions{
-			Config:              r.cfg,
-			ConfigPath:          r.configPath,
-			MissionName:         r.mission.Name,
-			TaskName:            taskName,
-			Commander:           r.mission.Commander.Model,
-			AgentNames:          agents,
-			DepSummaries:        depSummaries,
-			DepOutputSchemas:    depOutputSchemas,
-			TaskOutputSchema:    taskOutputSchema,
-			SecretInfos:         r.secretInfos,
-			SecretValues:        r.secretValues,
-			IsIteration:         isIterated,
-			MemoryStore:         r.memoryStore,
-			Compaction:          r.commanderCompaction(),
-			PruneOn:             r.commanderPruneOn(),
-			PruneTo:             r.commanderPruneTo(),
-			Reasoning:           r.mission.Commander.Reasoning,
-			ToolResponseMaxSize: r.mission.Commander.GetToolResponseMaxBytes(),
-			PricingOverrides:    r.pricingOverrides,
-			MissionLocalAgents:  r.mission.LocalAgents,
-			Provider:            r.testProvider(),
-			Budget:              r.budgetTracker.For(taskName),
-			HumanBridge:         r.humanBridge,
+			Config:             r.cfg,
+			ConfigPath:         r.configPath,
+			MissionName:        r.mission.Name,
+			TaskName:           taskName,
+			Commander:          task.CommanderModel(r.mission.Commander.Model),
+			AgentNames:         agents,
+			AgentReasons:       agentReasons,
+			DepSummaries:       depSummaries,
+			DepOutputSchemas:   depOutputSchemas,
+			TaskOutputSchema:   taskOutputSchema,
+			SecretInfos:        r.secretInfos,
+			SecretValues:       r.secretValues,
+			IsIteration:        isIterated,
+			MemoryStore:        r.memoryStore,
+			Knowledge:          r.knowledgeBase,
+			Artifacts:          r.artifacts,
+			Compaction:         r.commanderCompaction(),
+			PruneOn:            r.commanderPruneOn(),
+			PruneTo:            r.commanderPruneTo(),
+			Reasoning:          r.mission.Commander.Reasoning,
+			PromptTemplate:     r.mission.Commander.PromptTemplate,
+			MaxParallelAgents:  r.mission.Commander.GetMaxParallelAgents(),
+			MaxTurns:           task.MaxTurns,
+			ToolResponse:       r.mission.Commander.GetToolResponse(),
+			PricingOverrides:   r.pricingOverrides,
+			MissionLocalAgents: r.mission.LocalAgents,
+			Provider:           r.testProvider(),
+			Budget:             r.budgetTracker.For(taskName),
+			CallLimiter:        r.callLimiter,
+			ResponseCache:      r.responseCache,
+			HumanBridge:        r.humanBridge,
+			ToolFilter:         task.GetToolFilter(),
+			Policies:           r.mission.Policies,
+			AgentModels:        task.AgentModels(),
+			Sampling:           task.Sampling,
 		})
 		if err != nil {
 			return fmt.Errorf("creating commander for resaturation of '%s': %w", taskName, err)
@@ -1036,6 +1403,8 @@ func (r *Runner) resaturateCommanders(ctx context.Context, completedTaskNames []
 			AskCommanderWithCache: func(targetTask string, iterationIndex int, question string) (string, error) {
 				return r.askCommanderWithCache(ctx, targetTask, iterationIndex, taskName, question)
 			},
+			AskPriorCommander: r.priorCommanderCallback(ctx, taskName),
+			PriorMissions:     r.priorMissions,
 			SessionLogger:     r.stores.Sessions,
 			TaskID:            taskRecord.ID,
 			MissionID:         r.missionID,
@@ -1048,15 +1417,23 @@ func (r *Runner) resaturateCommanders(ctx context.Context, completedTaskNames []
 			if err != nil {
 				continue // Non-fatal: skip agent if messages can't be loaded
 			}
+			configName := agent.AgentConfigName(agentName)
 			restoredAgent, err := agent.RestoreAgent(ctx, agent.Options{
 				ConfigPath:   r.configPath,
 				Config:       r.cfg,
-				AgentName:    agentName,
+				AgentName:    configName,
 				SecretInfos:  r.secretInfos,
 				SecretValues: r.secretValues,
 				DatasetStore: r,
 				MemoryStore:  r.memoryStore,
+				Knowledge:    aitools.ScopeKnowledgeBase(r.knowledgeBase, task.Name, configName),
+				Artifacts:    aitools.ScopeArtifacts(r.artifacts, task.Name, configName),
 				HumanBridge:  r.humanBridge,
+				ToolFilter:   task.GetToolFilter(),
+				Policies:     r.mission.Policies,
+				Model:        task.AgentModels()[configName],
+				CallLimiter:  r.callLimiter,
+				ResponseCache: r.responseCache,
 			}, agentLLMMsgs)
 			if err != nil {
 				continue // Non-fatal: skip agent if it can't be restored
@@ -1083,6 +1460,44 @@ func (r *Runner) resaturateCommanders(ctx context.Context, completedTaskNames []
 	return nil
 }
 
+// findInterruptedIterationSessions returns, per iteration index, the most
+// recently started commander session for taskID that never finished — the
+// iterations that were in flight when the prior run stopped. Sessions that
+// ran to the end are marked completed, so they are never resumed.
+func (r *Runner) findInterruptedIterationSessions(taskID string) map[int]string {
+	interrupted := make(map[int]string)
+	sessions, err := r.stores.Sessions.GetSessionsByTask(taskID)
+	if err != nil {
+		return interrupted
+	}
+	latest := make(map[int]time.Time)
+	for _, s := range sessions {
+		if s.Role != "commander" || s.IterationIndex == nil || s.Status != "running" {
+			continue
+		}
+		idx := *s.IterationIndex
+		if prev, ok := latest[idx]; ok && !s.StartedAt.After(prev) {
+			continue
+		}
+		latest[idx] = s.StartedAt
+		interrupted[idx] = s.ID
+	}
+	return interrupted
+}
+
+// loadCommanderSession loads a stored commander session's messages into the
+// commander's LLM session, then its checkpoint if one was saved. Returns
+// false if the session has no messages.
+func (r *Runner) loadCommanderSession(sup *agent.Commander, sessionID string) bool {
+	llmMsgs, err := agent.LoadSessionMessages(r.stores.Sessions, sessionID)
+	if err != nil || len(llmMsgs) == 0 {
+		return false
+	}
+	sup.LoadSessionMessages(llmMsgs)
+	r.restoreCheckpoint(sup, sessionID, llmMsgs)
+	return true
+}
+
 // findAndLoadExistingSession checks the store for a prior commander session matching
 // the given taskID and iterationIndex. If found, loads the stored messages into the
 // commander's LLM session and returns the session ID for reuse.
@@ -1094,11 +1509,9 @@ func (r *Runner) findAndLoadExistingSession(sup *agent.Commander, taskID string,
 	}
 	for _, s := range sessions {
 		if s.Role == "commander" && intPtrEqual(s.IterationIndex, iterationIndex) {
-			llmMsgs, err := agent.LoadSessionMessages(r.stores.Sessions, s.ID)
-			if err != nil || len(llmMsgs) == 0 {
+			if !r.loadCommanderSession(sup, s.ID) {
 				return ""
 			}
-			sup.LoadSessionMessages(llmMsgs)
 			return s.ID
 		}
 	}
@@ -1110,7 +1523,7 @@ func (r *Runner) findAndLoadExistingSession(sup *agent.Commander, taskID string,
 // running/interrupted agents go into agentSessions (for call_agent to reuse).
 // iterationIndex filters to a specific iteration (nil matches sessions with no iteration).
 // Must be called AFTER SetToolCallbacks (needs sessionLogger to be wired up).
-func (r *Runner) restoreAgentSessions(ctx context.Context, sup *agent.Commander, taskID string, iterationIndex *int) {
+func (r *Runner) restoreAgentSessions(ctx context.Context, sup *agent.Commander, taskID string, iterationIndex *int, toolFilter *config.ToolFilter, agentModels map[string]string, sampling *config.Sampling) {
 	sessions, err := r.stores.Sessions.GetSessionsByTask(taskID)
 	if err != nil {
 		return
@@ -1131,16 +1544,27 @@ func (r *Runner) restoreAgentSessions(ctx context.Context, sup *agent.Commander,
 		// tool_result so the next provider request stays well-formed.
 		llmMsgs = agent.HealSessionMessages(llmMsgs)
 		mode := config.ModeMission
+		// Instances from call_agents_parallel ("researcher#2") run their agent's config
+		agentName := agent.AgentConfigName(s.AgentName)
 		restoredAgent, err := agent.RestoreAgent(ctx, agent.Options{
 			ConfigPath:   r.configPath,
 			Config:       r.cfg,
-			AgentName:    s.AgentName,
+			AgentName:    agentName,
 			Mode:         &mode,
 			SecretInfos:  r.secretInfos,
 			SecretValues: r.secretValues,
 			DatasetStore: r,
-			MemoryStore:  r.memoryStore,
+			MemoryStore:  sup.MemoryStore(),
+			Knowledge:    aitools.ScopeKnowledgeBase(r.knowledgeBase, sup.TaskName, agentName),
+			Artifacts:    aitools.ScopeArtifacts(r.artifacts, sup.TaskName, agentName),
 			HumanBridge:  r.humanBridge,
+			ToolFilter:   toolFilter,
+			Policies:     r.mission.Policies,
+			Model:        agentModels[agentName],
+			Sampling:     sampling,
+			CallLimiter:  r.callLimiter,
+			ResponseCache: r.responseCache,
+			Provider:     r.testProvider(),
 		}, llmMsgs)
 		if err != nil {
 			continue
@@ -1229,11 +1653,8 @@ func (r *Runner) runTask(ctx context.Context, task config.Task, missionID string
 		})
 	}
 
-	// Get agents for this task (task-level or mission-level)
-	agents := task.Agents
-	if len(agents) == 0 {
-		agents = r.mission.Agents
-	}
+	// Get agents for this task (named, matched by capability, or mission-level)
+	agents, agentReasons := r.mission.TaskAgents(&task, r.cfg.Agents)
 
 	// Collect dependency output schemas for the commander
 	depOutputSchemas := r.collectDepOutputSchemas(task.Name)
@@ -1244,36 +1665,64 @@ func (r *Runner) runTask(ctx context.Context, task config.Task, missionID string
 	// Get debug file for commander if debug mode is enabled
 	var debugFile string
 	if r.debugLogger != nil {
-		debugFile = r.debugLogger.GetMessageFile("commander", task.Name)
+		debugFile = r.debugLogger.GetMessageFile(agent.DebugScope{Task: task.Name}, "commander", "")
+	}
+
+	// Open the task's private workspace, if the mission has one
+	ws, err := r.openWorkspace(task.Name, nil)
+	if err != nil {
+		errStr := err.Error()
+		updateTaskDone(false, nil, &errStr)
+		streamer.TaskFailed(task.Name, err)
+		return &TaskResult{
+			TaskName: task.Name,
+			Success:  false,
+			Error:    err,
+		}, err
 	}
 
 	// Create commander for this task (non-iterated)
 	sup, err := agent.NewCommander(ctx, agent.CommanderOptions{
-		Config:              r.cfg,
-		ConfigPath:          r.configPath,
-		MissionName:         r.mission.Name,
-		TaskName:            task.Name,
-		Commander:           r.mission.Commander.Model,
-		AgentNames:          agents,
-		DepSummaries:        depSummaries,
-		DepOutputSchemas:    depOutputSchemas,
-		TaskOutputSchema:    taskOutputSchema,
-		SecretInfos:         r.secretInfos,
-		SecretValues:        r.secretValues,
-		IsIteration:         false,
-		DebugFile:           debugFile,
-		MemoryStore:         r.memoryStore,
-		Compaction:          r.commanderCompaction(),
-		PruneOn:             r.commanderPruneOn(),
-		PruneTo:             r.commanderPruneTo(),
-		Reasoning:           r.mission.Commander.Reasoning,
-		Routes:              r.routeOptionsForTask(task),
-		ToolResponseMaxSize: r.mission.Commander.GetToolResponseMaxBytes(),
-		PricingOverrides:    r.pricingOverrides,
-		MissionLocalAgents:  r.mission.LocalAgents,
-		Provider:            r.testProvider(),
-		Budget:              r.budgetTracker.For(task.Name),
-		HumanBridge:         r.humanBridge,
+		Config:             r.cfg,
+		ConfigPath:         r.configPath,
+		MissionName:        r.mission.Name,
+		TaskName:           task.Name,
+		Commander:          task.CommanderModel(r.mission.Commander.Model),
+		AgentNames:         agents,
+		AgentReasons:       agentReasons,
+		DepSummaries:       depSummaries,
+		DepOutputSchemas:   depOutputSchemas,
+		TaskOutputSchema:   taskOutputSchema,
+		SecretInfos:        r.secretInfos,
+		SecretValues:       r.secretValues,
+		IsIteration:        false,
+		DebugFile:          debugFile,
+		MemoryStore:        r.storeFor(ws),
+		Knowledge:          r.knowledgeBase,
+		Artifacts:          r.artifacts,
+		Compaction:         r.commanderCompaction(),
+		PruneOn:            r.commanderPruneOn(),
+		PruneTo:            r.commanderPruneTo(),
+		CheckpointInterval: r.commanderCheckpointInterval(),
+		Checkpoints:        r.stores.Checkpoints,
+		Reasoning:          r.mission.Commander.Reasoning,
+		PromptTemplate:     r.mission.Commander.PromptTemplate,
+		MaxParallelAgents:  r.mission.Commander.GetMaxParallelAgents(),
+		MaxTurns:           task.MaxTurns,
+		Routes:             r.routeOptionsForTask(task),
+		Verify:             task.Verify,
+		ToolResponse:       r.mission.Commander.GetToolResponse(),
+		PricingOverrides:   r.pricingOverrides,
+		MissionLocalAgents: r.mission.LocalAgents,
+		Provider:           r.testProvider(),
+		Budget:             r.budgetTracker.For(task.Name),
+		CallLimiter:        r.callLimiter,
+		ResponseCache:      r.responseCache,
+		HumanBridge:        r.humanBridge,
+		ToolFilter:         task.GetToolFilter(),
+		Policies:           r.mission.Policies,
+		AgentModels:        task.AgentModels(),
+		Sampling:           task.Sampling,
 	})
 	if err != nil {
 		errStr := err.Error()
@@ -1320,6 +1769,8 @@ func (r *Runner) runTask(ctx context.Context, task config.Task, missionID string
 		},
 		OnAgentCompaction:  agentCompactionCallback(streamer),
 		OnAgentSessionTurn: agentSessionTurnCallback(streamer),
+		OnProviderRetry:    providerRetryCallback(streamer),
+		OnParserRecovery:   parserRecoveryCallback(streamer),
 		DatasetStore:       r,
 		KnowledgeStore:     &knowledgeStoreAdapter{store: r.knowledgeStore},
 		DebugLogger:        r.debugLoggerInterface(),
@@ -1336,9 +1787,16 @@ func (r *Runner) runTask(ctx context.Context, task config.Task, missionID string
 		AskCommanderWithCache: func(targetTask string, iterationIndex int, question string) (string, error) {
 			return r.askCommanderWithCache(ctx, targetTask, iterationIndex, task.Name, question)
 		},
+		AskPriorCommander: r.priorCommanderCallback(ctx, task.Name),
+		PriorMissions:     r.priorMissions,
 		OnSubmitOutput: func(index int, output map[string]any) {
+			if task.RequireApproval {
+				// Held until approved — see awaitApproval below
+				return
+			}
 			outputJSON, _ := json.Marshal(output)
 			r.stores.Missions.StoreTaskOutput(taskID, nil, nil, nil, string(outputJSON))
+			r.emitTaskOutput(task, taskID, nil, nil, nil, output)
 		},
 		SessionLogger:     r.stores.Sessions,
 		TaskID:            taskID,
@@ -1364,7 +1822,7 @@ func (r *Runner) runTask(ctx context.Context, task config.Task, missionID string
 	}, depSummaries)
 
 	// Restore any agent sessions from the store (so call_agent reuses them)
-	r.restoreAgentSessions(ctx, sup, taskID, nil)
+	r.restoreAgentSessions(ctx, sup, taskID, nil, task.GetToolFilter(), task.AgentModels(), task.Sampling)
 
 	// Create task-specific streamer adapter
 	taskStreamer := &commanderStreamerAdapter{
@@ -1372,9 +1830,29 @@ func (r *Runner) runTask(ctx context.Context, task config.Task, missionID string
 		streamer: streamer,
 	}
 
-	// Execute (or resume if stored messages were loaded)
-	err = sup.ExecuteOrResume(ctx, objective, taskStreamer)
+	// Execute (or resume if stored messages were loaded), bounded by the task timeout
+	execCtx, cancelTimeout := withTimeout(ctx, &TimeoutError{TaskName: task.Name, Timeout: task.GetTimeout()})
+	err = sup.ExecuteOrResume(execCtx, objective, taskStreamer)
+	var te *TimeoutError
+	if err != nil {
+		te = timeoutCause(execCtx)
+	}
+	cancelTimeout()
+	if te != nil {
+		sup.Close()
+		if cmdSessionID != "" {
+			r.stores.Sessions.TimeOutSession(cmdSessionID)
+		}
+		errStr := te.Error()
+		updateTaskDone(false, nil, &errStr)
+		reportTimeout(streamer, te, false)
+		streamer.TaskFailed(task.Name, te)
+		return &TaskResult{TaskName: task.Name, Success: false, Error: te}, te
+	}
 	if err != nil {
+		if ctx.Err() != nil {
+			r.recordPartialResult(task.Name, nil, sup)
+		}
 		sup.Close()
 		if ctx.Err() != nil {
 			// Mission was stopped — don't emit task_failed, just propagate
@@ -1396,35 +1874,73 @@ func (r *Runner) runTask(ctx context.Context, task config.Task, missionID string
 		if reason := sup.TaskFailureReason(); reason != "" {
 			errStr = reason
 		}
+		failErr := failure.Wrap(sup.TaskFailureClass(), fmt.Errorf("%s", errStr))
 		updateTaskDone(false, nil, &errStr)
 		sup.Close()
-		streamer.TaskFailed(task.Name, fmt.Errorf("%s", errStr))
+		streamer.TaskFailed(task.Name, failErr)
 		return &TaskResult{
 			TaskName: task.Name,
 			Success:  false,
-			Error:    fmt.Errorf("%s", errStr),
-		}, fmt.Errorf("%s", errStr)
+			Error:    failErr,
+		}, failErr
+	}
+
+	// Get output from submit_output tool
+	summary := sup.TaskSummary()
+	var output map[string]any
+	if results := sup.GetSubmitResults(); len(results) > 0 {
+		output = results[0].Output
+	}
+
+	// Hold the result for human review before anything downstream sees it
+	if task.RequireApproval {
+		decision, err := r.awaitApproval(ctx, ApprovalRequest{
+			MissionID: r.missionID,
+			TaskID:    taskID,
+			TaskName:  task.Name,
+			Summary:   summary,
+			Output:    output,
+		}, streamer)
+		if err == nil && !decision.Approved {
+			err = &ApprovalRejectedError{TaskName: task.Name, Reason: decision.Reason}
+		}
+		if err != nil {
+			sup.Close()
+			if ctx.Err() != nil {
+				return &TaskResult{TaskName: task.Name, Success: false, Error: ctx.Err()}, ctx.Err()
+			}
+			errStr := err.Error()
+			updateTaskDone(false, nil, &errStr)
+			streamer.TaskFailed(task.Name, err)
+			return &TaskResult{TaskName: task.Name, Success: false, Error: err}, err
+		}
+		summary, output = decision.Summary, decision.Output
+		if output != nil {
+			outputJSON, _ := json.Marshal(output)
+			r.stores.Missions.StoreTaskOutput(taskID, nil, nil, nil, string(outputJSON))
+			r.emitTaskOutput(task, taskID, nil, nil, nil, output)
+		}
 	}
 
 	// Store commander and summary for dependent tasks
 	r.mu.Lock()
 	r.taskCommanders[task.Name] = sup
-	if summary := sup.TaskSummary(); summary != "" {
+	if summary != "" {
 		r.taskSummaries[task.Name] = summary
 		r.stores.Missions.UpdateTaskSummary(taskID, summary)
 	}
 	r.mu.Unlock()
 
-	// Get output from submit_output tool
-	var output map[string]any
-	if results := sup.GetSubmitResults(); len(results) > 0 {
-		output = results[0].Output
+	// Keep the confidence the commander gave its verified result with the task
+	if confidence, ok := sup.Confidence(); ok {
+		r.stores.Missions.SetTaskConfidence(taskID, confidence)
 	}
 
 	// Update task status to completed (output already persisted via OnSubmitOutput)
 	outputJSON, _ := json.Marshal(output)
 	outputStr := string(outputJSON)
 	updateTaskDone(true, &outputStr, nil)
+	r.finishWorkspace(ws)
 
 	streamer.TaskCompleted(task.Name)
 	return &TaskResult{
@@ -1486,35 +2002,9 @@ func (r *Runner) getDependencyChain(taskName string) []string {
 	return result
 }
 
-// convertOutputField converts a config.OutputField to agent.OutputFieldSchema recursively
-func convertOutputField(field config.OutputField) agent.OutputFieldSchema {
-	s := agent.OutputFieldSchema{
-		Name:        field.Name,
-		Type:        field.Type,
-		Description: field.Description,
-		Required:    field.Required,
-	}
-	if field.Items != nil {
-		items := convertOutputField(*field.Items)
-		s.Items = &items
-	}
-	for _, prop := range field.Properties {
-		s.Properties = append(s.Properties, convertOutputField(prop))
-	}
-	return s
-}
-
 // getTaskOutputSchema converts a task's output schema to agent.OutputFieldSchema slice
 func (r *Runner) getTaskOutputSchema(task config.Task) []agent.OutputFieldSchema {
-	if task.Output == nil {
-		return nil
-	}
-
-	var result []agent.OutputFieldSchema
-	for _, field := range task.Output.Fields {
-		result = append(result, convertOutputField(field))
-	}
-	return result
+	return agent.OutputFieldsFromConfig(task.Output)
 }
 
 // collectDepOutputSchemas gathers output schema info from dependency tasks
@@ -1537,6 +2027,7 @@ func (r *Runner) collectDepOutputSchemas(taskName string) []agent.DependencyOutp
 			TaskName:   depTaskName,
 			IsIterated: output.IsIterated,
 			ItemCount:  output.TotalIterations,
+			Aggregated: output.IsIterated && len(output.Output) > 0,
 		}
 
 		// Include output schema if defined
@@ -1609,6 +2100,46 @@ func agentSessionTurnCallback(streamer streamers.MissionHandler) func(string, st
 	}
 }
 
+// providerRetryCallback returns a callback that reports a retried LLM call
+// as a warning-level mission issue.
+func providerRetryCallback(streamer streamers.MissionHandler) func(string, string, llm.RetryEvent) {
+	return func(taskName, entity string, ev llm.RetryEvent) {
+		streamer.MissionIssue(streamers.MissionIssueData{
+			Severity: streamers.IssueWarning,
+			Category: streamers.IssueCategoryProviderError,
+			Message:  fmt.Sprintf("LLM call failed (attempt %d/%d), retrying in %s: %v", ev.Attempt, ev.MaxAttempts, ev.Backoff, ev.Err),
+			TaskName: taskName,
+			Entity:   entity,
+			Retrying: true,
+			Details: map[string]any{
+				"attempt":     ev.Attempt,
+				"maxAttempts": ev.MaxAttempts,
+				"backoffMs":   ev.Backoff.Milliseconds(),
+			},
+		})
+	}
+}
+
+// parserRecoveryCallback returns a callback that reports a format correction
+// sent after a malformed LLM response as a warning-level mission issue.
+func parserRecoveryCallback(streamer streamers.MissionHandler) func(string, string, agent.ParserRecoveryEvent) {
+	return func(taskName, entity string, ev agent.ParserRecoveryEvent) {
+		streamer.MissionIssue(streamers.MissionIssueData{
+			Severity: streamers.IssueWarning,
+			Category: streamers.IssueCategoryParserRecovery,
+			Message:  fmt.Sprintf("LLM response had no tool call or answer (attempt %d/%d), re-prompting with a format correction", ev.Attempt, ev.MaxAttempts),
+			TaskName: taskName,
+			Entity:   entity,
+			Retrying: true,
+			Details: map[string]any{
+				"attempt":     ev.Attempt,
+				"maxAttempts": ev.MaxAttempts,
+				"response":    ev.Response,
+			},
+		})
+	}
+}
+
 // routeOptionsForTask converts a task's router config into RouteOption slice for the commander.
 // For mission route targets, it populates IsMission and the target mission's input info.
 func (r *Runner) routeOptionsForTask(task config.Task) []aitools.RouteOption {
@@ -1649,6 +2180,7 @@ func (r *Runner) commanderCompaction() *agent.CompactionConfig {
 	return &agent.CompactionConfig{
 		TokenLimit:    r.mission.Commander.Compaction.TokenLimit,
 		TurnRetention: r.mission.Commander.Compaction.TurnRetention,
+		SummaryModel:  r.mission.Commander.Compaction.SummaryModel,
 	}
 }
 
@@ -1668,6 +2200,15 @@ func (r *Runner) commanderPruneTo() int {
 	return r.mission.Commander.Pruning.PruneTo
 }
 
+// commanderCheckpointInterval returns the checkpoint interval from the mission's
+// commander config, or 0 if checkpointing is off.
+func (r *Runner) commanderCheckpointInterval() int {
+	if r.mission.Commander == nil || r.mission.Commander.Checkpoint == nil {
+		return 0
+	}
+	return r.mission.Commander.Checkpoint.Interval
+}
+
 // missionSnapshot returns a JSON-friendly representation of the mission config.
 func (r *Runner) missionSnapshot() map[string]any {
 	snap := map[string]any{
@@ -1728,6 +2269,12 @@ func (r *Runner) missionSnapshot() map[string]any {
 	if r.mission.Scratchpad {
 		snap["scratchpad"] = true
 	}
+	if r.mission.Workspace != nil {
+		snap["workspace"] = r.mission.Workspace
+	}
+	if r.mission.Knowledge != nil {
+		snap["knowledge"] = r.mission.Knowledge
+	}
 
 	var tasks []map[string]any
 	for _, task := range r.mission.Tasks {
@@ -1751,6 +2298,9 @@ func taskSnapshot(task config.Task, resolvedObjective string) map[string]any {
 	if len(task.Agents) > 0 {
 		snap["agents"] = task.Agents
 	}
+	if len(task.Requires) > 0 {
+		snap["requires"] = task.Requires
+	}
 	if len(task.DependsOn) > 0 {
 		snap["dependsOn"] = task.DependsOn
 	}
@@ -1769,11 +2319,20 @@ func taskSnapshot(task config.Task, resolvedObjective string) map[string]any {
 	if len(task.Packets) > 0 {
 		snap["packets"] = task.Packets
 	}
+	if task.RawWhen != "" {
+		snap["when"] = task.RawWhen
+	}
 	return snap
 }
 
 // runIteratedTask executes a task that iterates over a dataset
 func (r *Runner) runIteratedTask(ctx context.Context, task config.Task, missionID string, existingTaskID string, streamer streamers.MissionHandler) (*TaskResult, error) {
+	// Fill the dataset from the dependency's output when the iterator maps over one
+	if err := r.fillOutputDataset(task); err != nil {
+		streamer.TaskFailed(task.Name, err)
+		return nil, err
+	}
+
 	// Load dataset items from store
 	datasetName := task.Iterator.Dataset
 	dsID, ok := r.datasetIDs[datasetName]
@@ -1820,9 +2379,16 @@ func (r *Runner) runIteratedTask(ctx context.Context, task config.Task, missionI
 	if len(items) == 0 {
 		// No items to iterate - return success
 		streamer.TaskStarted(task.Name, fmt.Sprintf("(0 iterations over %s)", datasetName))
+		outputJSON, err := r.aggregateOutputJSON(ctx, task, taskID, streamer)
+		if err != nil {
+			errStr := err.Error()
+			updateTaskDone(false, nil, &errStr)
+			streamer.TaskFailed(task.Name, err)
+			return &TaskResult{TaskName: task.Name, Success: false, Error: err}, err
+		}
 		streamer.TaskCompleted(task.Name)
 
-		updateTaskDone(true, nil, nil)
+		updateTaskDone(true, outputJSON, nil)
 		return &TaskResult{
 			TaskName: task.Name,
 			Success:  true,
@@ -1850,12 +2416,17 @@ func (r *Runner) runIteratedTask(ctx context.Context, task config.Task, missionI
 			iterObj, _ := r.resolveIterationObjective(task, item)
 			idx := i
 			r.stores.Missions.StoreTaskInput(taskID, &idx, iterObj)
+			r.setItemStatus(taskID, i, getItemID(item, i), store.ItemPending, nil)
 		}
 	}
 
 	// Notify mission handler about iteration start
 	streamer.TaskIterationStarted(task.Name, len(items), task.Iterator.Parallel)
 
+	// Bound the whole iterated task (every iteration and retry) by the task timeout
+	ctx, cancelTimeout := withTimeout(ctx, &TimeoutError{TaskName: task.Name, Timeout: task.GetTimeout()})
+	defer cancelTimeout()
+
 	var iterations []IterationResult
 
 	if task.Iterator.Parallel {
@@ -1879,6 +2450,7 @@ func (r *Runner) runIteratedTask(ctx context.Context, task config.Task, missionI
 				}
 			}
 
+			progress := r.newIterationProgress(task, streamer, len(items), len(items)-len(remainingItems))
 			if len(remainingItems) == 0 {
 				// All iterations already completed
 				iterations = make([]IterationResult, len(items))
@@ -1887,7 +2459,7 @@ func (r *Runner) runIteratedTask(ctx context.Context, task config.Task, missionI
 				}
 			} else {
 				// Run only remaining iterations
-				partialResults := r.runParallelIterationsWithIndices(ctx, task, remainingItems, remainingIndices, taskID, depSummaries, streamer)
+				partialResults := r.runParallelIterationsWithIndices(ctx, task, remainingItems, remainingIndices, taskID, depSummaries, streamer, progress)
 				// Merge with completed
 				iterations = make([]IterationResult, len(items))
 				for i := range items {
@@ -1901,12 +2473,27 @@ func (r *Runner) runIteratedTask(ctx context.Context, task config.Task, missionI
 			}
 		} else {
 			// Fresh: parallel execution with fail-fast
-			iterations = r.runParallelIterations(ctx, task, items, taskID, depSummaries, streamer)
+			progress := r.newIterationProgress(task, streamer, len(items), 0)
+			iterations = r.runParallelIterations(ctx, task, items, taskID, depSummaries, streamer, progress)
 		}
 	} else {
 		// Sequential execution
 		if existingTaskID != "" {
-			iterations = r.runSequentialIterationsResume(ctx, task, items, taskID, depSummaries, streamer)
+			remaining, completed := r.remainingIterations(taskID, len(items))
+			if len(remaining) > 0 && len(completed) > remaining[0] {
+				// Gaps before completed items (a rerun): the dataset_next
+				// commander only continues from a prefix, so run each
+				// remaining item on its own
+				iterations = make([]IterationResult, 0, len(items))
+				for i := range items {
+					if completed[i] {
+						iterations = append(iterations, IterationResult{Index: i, Success: true})
+					}
+				}
+				iterations = append(iterations, r.runIterationsInOrder(ctx, task, items, remaining, taskID, depSummaries, streamer)...)
+			} else {
+				iterations = r.runSequentialIterationsResume(ctx, task, items, taskID, depSummaries, streamer)
+			}
 		} else {
 			iterations = r.runSequentialIterations(ctx, task, items, taskID, depSummaries, streamer)
 		}
@@ -1927,15 +2514,39 @@ func (r *Runner) runIteratedTask(ctx context.Context, task config.Task, missionI
 		}
 	}
 
+	if !allSuccess || ctx.Err() != nil {
+		taskErr := firstError
+		if taskErr == nil {
+			taskErr = ctx.Err()
+		}
+		r.settleRunningItems(ctx, taskID, taskErr)
+	}
+
 	// Treat a canceled context as an interrupted task, not a completed one,
 	// even if every iteration that DID run reported success. Without this,
 	// a kill mid-iteration on a sequential iterator that already submitted
 	// some outputs would mark the whole task completed — and the runner's
 	// resume path would skip the still-pending iterations entirely.
+	if te := timeoutCause(ctx); te != nil {
+		errStr := te.Error()
+		updateTaskDone(false, nil, &errStr)
+		reportTimeout(streamer, te, false)
+		streamer.TaskFailed(task.Name, te)
+		return &TaskResult{TaskName: task.Name, Success: false, Error: te}, te
+	}
 	if ctx.Err() != nil {
 		return &TaskResult{TaskName: task.Name, Success: false, Error: ctx.Err()}, ctx.Err()
 	}
 
+	// continue_on_failure: complete on the items that succeeded if few enough failed
+	if !allSuccess && task.Iterator.ContinueOnFailure {
+		if err := r.tolerateFailedIterations(task, iterations, len(items), streamer); err != nil {
+			firstError = err
+		} else {
+			allSuccess = true
+		}
+	}
+
 	if !allSuccess {
 		errStr := firstError.Error()
 		updateTaskDone(false, nil, &errStr)
@@ -1947,9 +2558,18 @@ func (r *Runner) runIteratedTask(ctx context.Context, task config.Task, missionI
 		}, firstError
 	}
 
+	// Fold iteration outputs into the task output if the task has an aggregate block
+	outputJSON, err := r.aggregateOutputJSON(ctx, task, taskID, streamer)
+	if err != nil {
+		errStr := err.Error()
+		updateTaskDone(false, nil, &errStr)
+		streamer.TaskFailed(task.Name, err)
+		return &TaskResult{TaskName: task.Name, Success: false, Error: err}, err
+	}
+
 	// Update task status to completed
 	// Individual iteration outputs already persisted via OnSubmitOutput callbacks
-	updateTaskDone(true, nil, nil)
+	updateTaskDone(true, outputJSON, nil)
 
 	// Store summary for iterated tasks
 	// Parallel iterations: aggregated factual summary (individual commanders don't share state)
@@ -1957,6 +2577,15 @@ func (r *Runner) runIteratedTask(ctx context.Context, task config.Task, missionI
 	if task.Iterator.Parallel {
 		iterSummary := fmt.Sprintf("Iterated task '%s' completed %d/%d iterations successfully. Objective: %s. Use query_task_output to access individual iteration results.",
 			task.Name, successCount, len(iterations), task.RawObjective)
+		if successCount < len(iterations) {
+			var failed []int
+			for _, iter := range iterations {
+				if !iter.Success {
+					failed = append(failed, iter.Index)
+				}
+			}
+			iterSummary += fmt.Sprintf(" Iterations %v failed and have no output.", failed)
+		}
 		r.mu.Lock()
 		r.taskSummaries[task.Name] = iterSummary
 		r.stores.Missions.UpdateTaskSummary(taskID, iterSummary)
@@ -2010,10 +2639,7 @@ func (r *Runner) runIteratedTask(ctx context.Context, task config.Task, missionI
 // runSequentialIterations runs all iterations in a single commander session with agent reuse
 func (r *Runner) runSequentialIterations(ctx context.Context, task config.Task, items []cty.Value, taskID string, depSummaries []agent.DependencySummary, streamer streamers.MissionHandler) []IterationResult {
 	// Get agents for this task
-	agents := task.Agents
-	if len(agents) == 0 {
-		agents = r.mission.Agents
-	}
+	agents, agentReasons := r.mission.TaskAgents(&task, r.cfg.Agents)
 
 	// Collect dependency output schemas for the commander
 	depOutputSchemas := r.collectDepOutputSchemas(task.Name)
@@ -2024,7 +2650,7 @@ func (r *Runner) runSequentialIterations(ctx context.Context, task config.Task,
 	// Get debug file for commander if debug mode is enabled
 	var debugFile string
 	if r.debugLogger != nil {
-		debugFile = r.debugLogger.GetMessageFile("commander", task.Name)
+		debugFile = r.debugLogger.GetMessageFile(agent.DebugScope{Task: task.Name}, "commander", "")
 	}
 
 	// Build objective for sequential dataset processing
@@ -2045,35 +2671,59 @@ Task objective: %s
 Use dataset_next to get each item. Process it completely, then call submit_output with the output.
 Continue until dataset_next returns "exhausted".`, len(items), taskObjective)
 
+	// One commander handles every item, so the items share one workspace
+	ws, err := r.openWorkspace(task.Name, nil)
+	if err != nil {
+		return []IterationResult{{
+			Index:   0,
+			Success: false,
+			Error:   err,
+		}}
+	}
+
 	// Create single commander with all items
 	sup, err := agent.NewCommander(ctx, agent.CommanderOptions{
-		Config:              r.cfg,
-		ConfigPath:          r.configPath,
-		MissionName:         r.mission.Name,
-		TaskName:            task.Name,
-		Commander:           r.mission.Commander.Model,
-		AgentNames:          agents,
-		DepSummaries:        depSummaries,
-		DepOutputSchemas:    depOutputSchemas,
-		TaskOutputSchema:    taskOutputSchema,
-		SecretInfos:         r.secretInfos,
-		SecretValues:        r.secretValues,
-		IsIteration:         true,
-		IsParallel:          false,
-		DebugFile:           debugFile,
-		SequentialDataset:   items,
-		MemoryStore:         r.memoryStore,
-		Compaction:          r.commanderCompaction(),
-		PruneOn:             r.commanderPruneOn(),
-		PruneTo:             r.commanderPruneTo(),
-		Reasoning:           r.mission.Commander.Reasoning,
-		Routes:              r.routeOptionsForTask(task),
-		ToolResponseMaxSize: r.mission.Commander.GetToolResponseMaxBytes(),
-		PricingOverrides:    r.pricingOverrides,
-		MissionLocalAgents:  r.mission.LocalAgents,
-		Provider:            r.testProvider(),
-		Budget:              r.budgetTracker.For(task.Name),
-		HumanBridge:         r.humanBridge,
+		Config:             r.cfg,
+		ConfigPath:         r.configPath,
+		MissionName:        r.mission.Name,
+		TaskName:           task.Name,
+		Commander:          task.CommanderModel(r.mission.Commander.Model),
+		AgentNames:         agents,
+		AgentReasons:       agentReasons,
+		DepSummaries:       depSummaries,
+		DepOutputSchemas:   depOutputSchemas,
+		TaskOutputSchema:   taskOutputSchema,
+		SecretInfos:        r.secretInfos,
+		SecretValues:       r.secretValues,
+		IsIteration:        true,
+		IsParallel:         false,
+		DebugFile:          debugFile,
+		SequentialDataset:  items,
+		MemoryStore:        r.storeFor(ws),
+		Knowledge:          r.knowledgeBase,
+		Artifacts:          r.artifacts,
+		Compaction:         r.commanderCompaction(),
+		PruneOn:            r.commanderPruneOn(),
+		PruneTo:            r.commanderPruneTo(),
+		CheckpointInterval: r.commanderCheckpointInterval(),
+		Checkpoints:        r.stores.Checkpoints,
+		Reasoning:          r.mission.Commander.Reasoning,
+		PromptTemplate:     r.mission.Commander.PromptTemplate,
+		MaxParallelAgents:  r.mission.Commander.GetMaxParallelAgents(),
+		MaxTurns:           task.MaxTurns,
+		Routes:             r.routeOptionsForTask(task),
+		ToolResponse:       r.mission.Commander.GetToolResponse(),
+		PricingOverrides:   r.pricingOverrides,
+		MissionLocalAgents: r.mission.LocalAgents,
+		Provider:           r.testProvider(),
+		Budget:             r.budgetTracker.For(task.Name),
+		CallLimiter:        r.callLimiter,
+		ResponseCache:      r.responseCache,
+		HumanBridge:        r.humanBridge,
+		ToolFilter:         task.GetToolFilter(),
+		Policies:           r.mission.Policies,
+		AgentModels:        task.AgentModels(),
+		Sampling:           task.Sampling,
 	})
 	if err != nil {
 		return []IterationResult{{
@@ -2083,8 +2733,15 @@ Continue until dataset_next returns "exhausted".`, len(items), taskObjective)
 		}}
 	}
 
-	// Wire up OnNext to emit iteration_started events
+	// Wire up OnNext to emit iteration_started events. Sequential
+	// iterations share one commander, so they show up as events on the
+	// task span rather than spans of their own.
+	taskSpan := trace.SpanFromContext(ctx)
 	sup.SetDatasetOnNext(func(index int) {
+		if index < len(items) {
+			r.setItemStatus(taskID, index, getItemID(items[index], index), store.ItemRunning, nil)
+		}
+		taskSpan.AddEvent("iteration_started", trace.WithAttributes(attribute.Int("squadron.iteration", index)))
 		streamer.IterationStarted(task.Name, index, taskObjective)
 	})
 
@@ -2117,6 +2774,8 @@ Continue until dataset_next returns "exhausted".`, len(items), taskObjective)
 		},
 		OnAgentCompaction:  agentCompactionCallback(streamer),
 		OnAgentSessionTurn: agentSessionTurnCallback(streamer),
+		OnProviderRetry:    providerRetryCallback(streamer),
+		OnParserRecovery:   parserRecoveryCallback(streamer),
 		DatasetStore:       r,
 		KnowledgeStore:     &knowledgeStoreAdapter{store: r.knowledgeStore},
 		DebugLogger:        r.debugLoggerInterface(),
@@ -2132,6 +2791,8 @@ Continue until dataset_next returns "exhausted".`, len(items), taskObjective)
 		AskCommanderWithCache: func(targetTask string, iterationIndex int, question string) (string, error) {
 			return r.askCommanderWithCache(ctx, targetTask, iterationIndex, task.Name, question)
 		},
+		AskPriorCommander: r.priorCommanderCallback(ctx, task.Name),
+		PriorMissions:     r.priorMissions,
 		OnSubmitOutput: func(index int, output map[string]any) {
 			datasetName := task.Iterator.Dataset
 			itemID := ""
@@ -2140,6 +2801,8 @@ Continue until dataset_next returns "exhausted".`, len(items), taskObjective)
 			}
 			outputJSON, _ := json.Marshal(output)
 			r.stores.Missions.StoreTaskOutput(taskID, &datasetName, &index, &itemID, string(outputJSON))
+			r.emitTaskOutput(task, taskID, &datasetName, &index, &itemID, output)
+			r.setItemStatus(taskID, index, itemID, store.ItemCompleted, nil)
 			streamer.IterationCompleted(task.Name, index)
 		},
 		SessionLogger: r.stores.Sessions,
@@ -2180,6 +2843,9 @@ Continue until dataset_next returns "exhausted".`, len(items), taskObjective)
 
 	// Execute the task - commander handles all items internally
 	err = sup.ExecuteTask(ctx, objective, seqStreamer)
+	if err != nil && ctx.Err() != nil {
+		r.recordPartialResult(task.Name, nil, sup)
+	}
 
 	// Check if task was explicitly marked as failed
 	if err == nil && !sup.IsTaskSucceeded() {
@@ -2187,11 +2853,12 @@ Continue until dataset_next returns "exhausted".`, len(items), taskObjective)
 		if reason := sup.TaskFailureReason(); reason != "" {
 			failMsg = reason
 		}
+		failErr := failure.Wrap(sup.TaskFailureClass(), fmt.Errorf("%s", failMsg))
 		sup.Close()
 		return []IterationResult{{
 			Index:   0,
 			Success: false,
-			Error:   fmt.Errorf("%s", failMsg),
+			Error:   failErr,
 		}}
 	}
 
@@ -2222,6 +2889,7 @@ Continue until dataset_next returns "exhausted".`, len(items), taskObjective)
 				Success: true,
 			}
 		}
+		r.finishWorkspace(ws)
 		return iterations
 	}
 
@@ -2249,11 +2917,24 @@ Continue until dataset_next returns "exhausted".`, len(items), taskObjective)
 	r.iterationCommanders[task.Name][0] = sup
 	r.mu.Unlock()
 
+	if err == nil {
+		r.finishWorkspace(ws)
+	}
 	return iterations
 }
 
+// newIterationProgress returns the progress tracker for a parallel iterated
+// task, or nil when the streamer does not take progress events.
+func (r *Runner) newIterationProgress(task config.Task, streamer streamers.MissionHandler, total, done int) *iterationProgress {
+	concurrencyLimit := 5
+	if task.Iterator != nil && task.Iterator.ConcurrencyLimit > 0 {
+		concurrencyLimit = task.Iterator.ConcurrencyLimit
+	}
+	return newIterationProgress(streamer, task.Name, total, done, concurrencyLimit)
+}
+
 // runParallelIterations runs iterations in parallel with concurrency limit and optional staggered starts
-func (r *Runner) runParallelIterations(ctx context.Context, task config.Task, items []cty.Value, taskID string, depSummaries []agent.DependencySummary, streamer streamers.MissionHandler) []IterationResult {
+func (r *Runner) runParallelIterations(ctx context.Context, task config.Task, items []cty.Value, taskID string, depSummaries []agent.DependencySummary, streamer streamers.MissionHandler, progress *iterationProgress) []IterationResult {
 	iterations := make([]IterationResult, len(items))
 	maxRetries := 0
 	if task.Iterator != nil {
@@ -2282,9 +2963,11 @@ func (r *Runner) runParallelIterations(ctx context.Context, task config.Task, it
 	if smoketest && len(items) > 0 {
 		// Run first iteration synchronously
 		var firstResult IterationResult
+		finish := progress.start()
 		for attempt := 0; attempt <= maxRetries; attempt++ {
 			select {
 			case <-ctx.Done():
+				finish(false)
 				return []IterationResult{{
 					Index:   0,
 					ItemID:  getItemID(items[0], 0),
@@ -2294,10 +2977,11 @@ func (r *Runner) runParallelIterations(ctx context.Context, task config.Task, it
 			default:
 			}
 
-			firstResult = r.runSingleIteration(ctx, task, 0, items[0], nil, taskID, depSummaries, streamer)
-			if firstResult.Success {
+			firstResult = r.runSingleIteration(ctx, task, 0, items[0], nil, taskID, "", depSummaries, streamer)
+			if firstResult.Success || !failure.Retryable(firstResult.ErrorCode()) {
 				break
 			}
+			reportIfTimeout(streamer, firstResult.Error, attempt < maxRetries)
 
 			if attempt < maxRetries {
 				streamer.IterationRetrying(task.Name, 0, attempt+1, maxRetries, firstResult.Error)
@@ -2305,6 +2989,7 @@ func (r *Runner) runParallelIterations(ctx context.Context, task config.Task, it
 		}
 
 		iterations[0] = firstResult
+		finish(firstResult.Success)
 
 		// If smoketest failed, don't start other iterations
 		if !firstResult.Success {
@@ -2318,7 +3003,7 @@ func (r *Runner) runParallelIterations(ctx context.Context, task config.Task, it
 		}
 
 		// Run remaining iterations in parallel
-		remainingIterations := r.runParallelIterationsCore(ctx, task, items, 1, maxRetries, concurrencyLimit, startDelay, taskID, depSummaries, streamer)
+		remainingIterations := r.runParallelIterationsCore(ctx, task, items, 1, maxRetries, concurrencyLimit, startDelay, taskID, depSummaries, streamer, progress)
 		for i, result := range remainingIterations {
 			iterations[i+1] = result
 		}
@@ -2326,11 +3011,11 @@ func (r *Runner) runParallelIterations(ctx context.Context, task config.Task, it
 	}
 
 	// No smoketest - run all iterations in parallel
-	return r.runParallelIterationsCore(ctx, task, items, 0, maxRetries, concurrencyLimit, startDelay, taskID, depSummaries, streamer)
+	return r.runParallelIterationsCore(ctx, task, items, 0, maxRetries, concurrencyLimit, startDelay, taskID, depSummaries, streamer, progress)
 }
 
 // runParallelIterationsCore is the core parallel execution logic
-func (r *Runner) runParallelIterationsCore(ctx context.Context, task config.Task, items []cty.Value, indexOffset int, maxRetries int, concurrencyLimit int, startDelay int, taskID string, depSummaries []agent.DependencySummary, streamer streamers.MissionHandler) []IterationResult {
+func (r *Runner) runParallelIterationsCore(ctx context.Context, task config.Task, items []cty.Value, indexOffset int, maxRetries int, concurrencyLimit int, startDelay int, taskID string, depSummaries []agent.DependencySummary, streamer streamers.MissionHandler, progress *iterationProgress) []IterationResult {
 	iterations := make([]IterationResult, len(items))
 
 	// Semaphore to limit concurrent iterations
@@ -2356,6 +3041,7 @@ func (r *Runner) runParallelIterationsCore(ctx context.Context, task config.Task
 
 			// Run with retries
 			var result IterationResult
+			finish := progress.start()
 			for attempt := 0; attempt <= maxRetries; attempt++ {
 				select {
 				case <-ctx.Done():
@@ -2365,15 +3051,17 @@ func (r *Runner) runParallelIterationsCore(ctx context.Context, task config.Task
 						Success: false,
 						Error:   ctx.Err(),
 					}
+					finish(false)
 					return
 				default:
 				}
 
 				// Pass nil for prevOutput in parallel iterations (no meaningful ordering)
-				result = r.runSingleIteration(ctx, task, actualIndex, item, nil, taskID, depSummaries, streamer)
-				if result.Success {
+				result = r.runSingleIteration(ctx, task, actualIndex, item, nil, taskID, "", depSummaries, streamer)
+				if result.Success || !failure.Retryable(result.ErrorCode()) {
 					break
 				}
+				reportIfTimeout(streamer, result.Error, attempt < maxRetries)
 
 				// If we have retries remaining, log and retry
 				if attempt < maxRetries {
@@ -2382,6 +3070,7 @@ func (r *Runner) runParallelIterationsCore(ctx context.Context, task config.Task
 			}
 
 			iterations[i] = result
+			finish(result.Success)
 		}()
 	}
 
@@ -2391,7 +3080,11 @@ func (r *Runner) runParallelIterationsCore(ctx context.Context, task config.Task
 
 // runParallelIterationsWithIndices runs specific iterations (by index) in parallel.
 // Used on resume to only run iterations that didn't complete in the prior run.
-func (r *Runner) runParallelIterationsWithIndices(ctx context.Context, task config.Task, items []cty.Value, indices []int, taskID string, depSummaries []agent.DependencySummary, streamer streamers.MissionHandler) []IterationResult {
+// Iterations that were in flight when the prior run stopped continue from
+// their stored commander session; the rest start fresh.
+func (r *Runner) runParallelIterationsWithIndices(ctx context.Context, task config.Task, items []cty.Value, indices []int, taskID string, depSummaries []agent.DependencySummary, streamer streamers.MissionHandler, progress *iterationProgress) []IterationResult {
+	interrupted := r.findInterruptedIterationSessions(taskID)
+
 	maxRetries := 0
 	if task.Iterator != nil {
 		maxRetries = task.Iterator.MaxRetries
@@ -2416,6 +3109,7 @@ func (r *Runner) runParallelIterationsWithIndices(ctx context.Context, task conf
 			defer func() { <-sem }()
 
 			var result IterationResult
+			finish := progress.start()
 			for attempt := 0; attempt <= maxRetries; attempt++ {
 				select {
 				case <-ctx.Done():
@@ -2425,19 +3119,27 @@ func (r *Runner) runParallelIterationsWithIndices(ctx context.Context, task conf
 						Success: false,
 						Error:   ctx.Err(),
 					}
+					finish(false)
 					return
 				default:
 				}
 
-				result = r.runSingleIteration(ctx, task, actualIndex, item, nil, taskID, depSummaries, streamer)
-				if result.Success {
+				// Only the first attempt resumes; a retry starts the iteration over.
+				resumeSessionID := ""
+				if attempt == 0 {
+					resumeSessionID = interrupted[actualIndex]
+				}
+				result = r.runSingleIteration(ctx, task, actualIndex, item, nil, taskID, resumeSessionID, depSummaries, streamer)
+				if result.Success || !failure.Retryable(result.ErrorCode()) {
 					break
 				}
+				reportIfTimeout(streamer, result.Error, attempt < maxRetries)
 				if attempt < maxRetries {
 					streamer.IterationRetrying(task.Name, actualIndex, attempt+1, maxRetries, result.Error)
 				}
 			}
 			results[i] = result
+			finish(result.Success)
 		}()
 	}
 
@@ -2471,16 +3173,13 @@ func (r *Runner) runSequentialIterationsResume(ctx context.Context, task config.
 	remainingItems := items[completedCount:]
 
 	// Get agents for this task
-	agents := task.Agents
-	if len(agents) == 0 {
-		agents = r.mission.Agents
-	}
+	agents, agentReasons := r.mission.TaskAgents(&task, r.cfg.Agents)
 	depOutputSchemas := r.collectDepOutputSchemas(task.Name)
 	taskOutputSchema := r.getTaskOutputSchema(task)
 
 	var debugFile string
 	if r.debugLogger != nil {
-		debugFile = r.debugLogger.GetMessageFile("commander", task.Name)
+		debugFile = r.debugLogger.GetMessageFile(agent.DebugScope{Task: task.Name}, "commander", "")
 	}
 
 	taskObjective, err := task.ResolvedObjective(r.varsValues, r.inputValues)
@@ -2499,34 +3198,58 @@ Task objective: %s
 Use dataset_next to get each item. Process it completely, then call submit_output with the output.
 Continue until dataset_next returns "exhausted".`, len(remainingItems), taskObjective)
 
+	ws, err := r.openWorkspace(task.Name, nil)
+	if err != nil {
+		return append(iterations, IterationResult{
+			Index:   completedCount,
+			Success: false,
+			Error:   err,
+		})
+	}
+
 	// Create commander for remaining items
 	sup, err := agent.NewCommander(ctx, agent.CommanderOptions{
-		Config:              r.cfg,
-		ConfigPath:          r.configPath,
-		MissionName:         r.mission.Name,
-		TaskName:            task.Name,
-		Commander:           r.mission.Commander.Model,
-		AgentNames:          agents,
-		DepSummaries:        depSummaries,
-		DepOutputSchemas:    depOutputSchemas,
-		TaskOutputSchema:    taskOutputSchema,
-		SecretInfos:         r.secretInfos,
-		SecretValues:        r.secretValues,
-		IsIteration:         true,
-		IsParallel:          false,
-		DebugFile:           debugFile,
-		SequentialDataset:   remainingItems,
-		MemoryStore:         r.memoryStore,
-		Compaction:          r.commanderCompaction(),
-		PruneOn:             r.commanderPruneOn(),
-		PruneTo:             r.commanderPruneTo(),
-		Reasoning:           r.mission.Commander.Reasoning,
-		ToolResponseMaxSize: r.mission.Commander.GetToolResponseMaxBytes(),
-		PricingOverrides:    r.pricingOverrides,
-		MissionLocalAgents:  r.mission.LocalAgents,
-		Provider:            r.testProvider(),
-		Budget:              r.budgetTracker.For(task.Name),
-		HumanBridge:         r.humanBridge,
+		Config:             r.cfg,
+		ConfigPath:         r.configPath,
+		MissionName:        r.mission.Name,
+		TaskName:           task.Name,
+		Commander:          task.CommanderModel(r.mission.Commander.Model),
+		AgentNames:         agents,
+		AgentReasons:       agentReasons,
+		DepSummaries:       depSummaries,
+		DepOutputSchemas:   depOutputSchemas,
+		TaskOutputSchema:   taskOutputSchema,
+		SecretInfos:        r.secretInfos,
+		SecretValues:       r.secretValues,
+		IsIteration:        true,
+		IsParallel:         false,
+		DebugFile:          debugFile,
+		SequentialDataset:  remainingItems,
+		MemoryStore:        r.storeFor(ws),
+		Knowledge:          r.knowledgeBase,
+		Artifacts:          r.artifacts,
+		Compaction:         r.commanderCompaction(),
+		PruneOn:            r.commanderPruneOn(),
+		PruneTo:            r.commanderPruneTo(),
+		CheckpointInterval: r.commanderCheckpointInterval(),
+		Checkpoints:        r.stores.Checkpoints,
+		DatasetOffset:      completedCount,
+		Reasoning:          r.mission.Commander.Reasoning,
+		PromptTemplate:     r.mission.Commander.PromptTemplate,
+		MaxParallelAgents:  r.mission.Commander.GetMaxParallelAgents(),
+		MaxTurns:           task.MaxTurns,
+		ToolResponse:       r.mission.Commander.GetToolResponse(),
+		PricingOverrides:   r.pricingOverrides,
+		MissionLocalAgents: r.mission.LocalAgents,
+		Provider:           r.testProvider(),
+		Budget:             r.budgetTracker.For(task.Name),
+		CallLimiter:        r.callLimiter,
+		ResponseCache:      r.responseCache,
+		HumanBridge:        r.humanBridge,
+		ToolFilter:         task.GetToolFilter(),
+		Policies:           r.mission.Policies,
+		AgentModels:        task.AgentModels(),
+		Sampling:           task.Sampling,
 	})
 	if err != nil {
 		return append(iterations, IterationResult{
@@ -2543,12 +3266,17 @@ Continue until dataset_next returns "exhausted".`, len(remainingItems), taskObje
 	// against the events store so each iteration emits iteration_started
 	// at most once over the mission's lifetime.
 	alreadyStarted := r.collectStartedIterations(task.Name)
+	taskSpan := trace.SpanFromContext(ctx)
 	sup.SetDatasetOnNext(func(index int) {
 		actualIndex := index + completedCount
+		if actualIndex < len(items) {
+			r.setItemStatus(taskID, actualIndex, getItemID(items[actualIndex], actualIndex), store.ItemRunning, nil)
+		}
 		if alreadyStarted[actualIndex] {
 			return
 		}
 		alreadyStarted[actualIndex] = true
+		taskSpan.AddEvent("iteration_started", trace.WithAttributes(attribute.Int("squadron.iteration", actualIndex)))
 		streamer.IterationStarted(task.Name, actualIndex, taskObjective)
 	})
 
@@ -2575,6 +3303,8 @@ Continue until dataset_next returns "exhausted".`, len(remainingItems), taskObje
 		},
 		OnAgentCompaction:  agentCompactionCallback(streamer),
 		OnAgentSessionTurn: agentSessionTurnCallback(streamer),
+		OnProviderRetry:    providerRetryCallback(streamer),
+		OnParserRecovery:   parserRecoveryCallback(streamer),
 		DatasetStore:       r,
 		KnowledgeStore:     &knowledgeStoreAdapter{store: r.knowledgeStore},
 		DebugLogger:        r.debugLoggerInterface(),
@@ -2590,6 +3320,8 @@ Continue until dataset_next returns "exhausted".`, len(remainingItems), taskObje
 		AskCommanderWithCache: func(targetTask string, iterationIndex int, question string) (string, error) {
 			return r.askCommanderWithCache(ctx, targetTask, iterationIndex, task.Name, question)
 		},
+		AskPriorCommander: r.priorCommanderCallback(ctx, task.Name),
+		PriorMissions:     r.priorMissions,
 		OnSubmitOutput: func(index int, output map[string]any) {
 			// Adjust index to account for already-completed items
 			actualIndex := index + completedCount
@@ -2600,6 +3332,8 @@ Continue until dataset_next returns "exhausted".`, len(remainingItems), taskObje
 			}
 			outputJSON, _ := json.Marshal(output)
 			r.stores.Missions.StoreTaskOutput(taskID, &datasetName, &actualIndex, &itemID, string(outputJSON))
+			r.emitTaskOutput(task, taskID, &datasetName, &actualIndex, &itemID, output)
+			r.setItemStatus(taskID, actualIndex, itemID, store.ItemCompleted, nil)
 			streamer.IterationCompleted(task.Name, actualIndex)
 		},
 		SessionLogger:     r.stores.Sessions,
@@ -2628,7 +3362,7 @@ Continue until dataset_next returns "exhausted".`, len(remainingItems), taskObje
 	}, depSummaries)
 
 	// Restore any agent sessions from the store
-	r.restoreAgentSessions(ctx, sup, taskID, nil)
+	r.restoreAgentSessions(ctx, sup, taskID, nil, task.GetToolFilter(), task.AgentModels(), task.Sampling)
 
 	seqStreamer := &iterationStreamerAdapter{
 		taskName: task.Name,
@@ -2643,6 +3377,9 @@ Continue until dataset_next returns "exhausted".`, len(remainingItems), taskObje
 
 	// Execute (or resume if stored messages were loaded)
 	err = sup.ExecuteOrResume(ctx, objective, seqStreamer)
+	if err != nil && ctx.Err() != nil {
+		r.recordPartialResult(task.Name, nil, sup)
+	}
 
 	// Check if task was explicitly marked as failed
 	if err == nil && !sup.IsTaskSucceeded() {
@@ -2650,11 +3387,12 @@ Continue until dataset_next returns "exhausted".`, len(remainingItems), taskObje
 		if reason := sup.TaskFailureReason(); reason != "" {
 			failMsg = reason
 		}
+		failErr := failure.Wrap(sup.TaskFailureClass(), fmt.Errorf("%s", failMsg))
 		sup.Close()
 		iterations = append(iterations, IterationResult{
 			Index:   completedCount,
 			Success: false,
-			Error:   fmt.Errorf("%s", failMsg),
+			Error:   failErr,
 		})
 		return iterations
 	}
@@ -2691,14 +3429,30 @@ Continue until dataset_next returns "exhausted".`, len(remainingItems), taskObje
 	r.iterationCommanders[task.Name][0] = sup
 	r.mu.Unlock()
 
+	if err == nil {
+		r.finishWorkspace(ws)
+	}
 	return iterations
 }
 
 // runSingleIteration executes a single iteration of an iterated task.
 // It checks the store for existing session state and resumes automatically if found.
-func (r *Runner) runSingleIteration(ctx context.Context, task config.Task, index int, item cty.Value, prevOutput map[string]any, taskID string, depSummaries []agent.DependencySummary, streamer streamers.MissionHandler) IterationResult {
+// resumeSessionID, when non-empty, is a stored commander session for this
+// iteration that was interrupted in a prior run; it is loaded and continued
+// instead of starting the iteration over. Retries always pass "".
+func (r *Runner) runSingleIteration(ctx context.Context, task config.Task, index int, item cty.Value, prevOutput map[string]any, taskID string, resumeSessionID string, depSummaries []agent.DependencySummary, streamer streamers.MissionHandler) (result IterationResult) {
 	itemID := getItemID(item, index)
 
+	ctx, span := tracing.Start(ctx, fmt.Sprintf("iteration %d", index),
+		attribute.String("squadron.task", task.Name),
+		attribute.Int("squadron.iteration", index),
+		attribute.String("squadron.iteration.item_id", itemID),
+	)
+	defer func() { tracing.End(span, result.Error) }()
+
+	r.setItemStatus(taskID, index, itemID, store.ItemRunning, nil)
+	defer func() { r.recordIterationResult(ctx, taskID, result) }()
+
 	// Resolve the objective with item context
 	objective, err := r.resolveIterationObjective(task, item)
 	if err != nil {
@@ -2724,10 +3478,7 @@ func (r *Runner) runSingleIteration(ctx context.Context, task config.Task, index
 	}
 
 	// Get agents for this task
-	agents := task.Agents
-	if len(agents) == 0 {
-		agents = r.mission.Agents
-	}
+	agents, agentReasons := r.mission.TaskAgents(&task, r.cfg.Agents)
 
 	// Collect dependency output schemas for the commander
 	depOutputSchemas := r.collectDepOutputSchemas(task.Name)
@@ -2739,7 +3490,19 @@ func (r *Runner) runSingleIteration(ctx context.Context, task config.Task, index
 	iterTaskName := fmt.Sprintf("%s[%d]", task.Name, index)
 	var debugFile string
 	if r.debugLogger != nil {
-		debugFile = r.debugLogger.GetMessageFile("commander", iterTaskName)
+		debugFile = r.debugLogger.GetMessageFile(agent.DebugScope{Task: task.Name, Iteration: &index}, "commander", "")
+	}
+
+	// Each iteration gets its own workspace
+	ws, err := r.openWorkspace(task.Name, &index)
+	if err != nil {
+		streamer.IterationFailed(task.Name, index, err)
+		return IterationResult{
+			Index:   index,
+			ItemID:  itemID,
+			Success: false,
+			Error:   err,
+		}
 	}
 
 	// Create commander for this iteration
@@ -2748,8 +3511,9 @@ func (r *Runner) runSingleIteration(ctx context.Context, task config.Task, index
 		ConfigPath:          r.configPath,
 		MissionName:         r.mission.Name,
 		TaskName:            iterTaskName,
-		Commander:           r.mission.Commander.Model,
+		Commander:           task.CommanderModel(r.mission.Commander.Model),
 		AgentNames:          agents,
+		AgentReasons:        agentReasons,
 		DepSummaries:        depSummaries,
 		DepOutputSchemas:    depOutputSchemas,
 		TaskOutputSchema:    taskOutputSchema,
@@ -2759,17 +3523,30 @@ func (r *Runner) runSingleIteration(ctx context.Context, task config.Task, index
 		IsIteration:         true,
 		IsParallel:          task.Iterator.Parallel,
 		DebugFile:           debugFile,
-		MemoryStore:         r.memoryStore,
+		MemoryStore:         r.storeFor(ws),
+		Knowledge:           r.knowledgeBase,
+		Artifacts:           r.artifacts,
 		Compaction:          r.commanderCompaction(),
 		PruneOn:             r.commanderPruneOn(),
 		PruneTo:             r.commanderPruneTo(),
+		CheckpointInterval:  r.commanderCheckpointInterval(),
+		Checkpoints:         r.stores.Checkpoints,
 		Reasoning:           r.mission.Commander.Reasoning,
-		ToolResponseMaxSize: r.mission.Commander.GetToolResponseMaxBytes(),
+		PromptTemplate:      r.mission.Commander.PromptTemplate,
+		MaxParallelAgents:   r.mission.Commander.GetMaxParallelAgents(),
+		MaxTurns:            task.MaxTurns,
+		ToolResponse:        r.mission.Commander.GetToolResponse(),
 		PricingOverrides:    r.pricingOverrides,
 		MissionLocalAgents:  r.mission.LocalAgents,
 		Provider:            r.testProvider(),
 		Budget:              r.budgetTracker.For(task.Name),
+		CallLimiter:         r.callLimiter,
+		ResponseCache:       r.responseCache,
 		HumanBridge:         r.humanBridge,
+		ToolFilter:          task.GetToolFilter(),
+		Policies:            r.mission.Policies,
+		AgentModels:         task.AgentModels(),
+		Sampling:            task.Sampling,
 	})
 	if err != nil {
 		streamer.IterationFailed(task.Name, index, err)
@@ -2783,9 +3560,12 @@ func (r *Runner) runSingleIteration(ctx context.Context, task config.Task, index
 	// Note: Don't close sup here - store it for ask_commander queries from dependent tasks
 	// Cleanup happens in cleanupIterationCommanders() after all dependent tasks complete
 
-	// Check for existing session state (finds stored session from prior run if any)
+	// Load the interrupted session from a prior run, if we were given one
 	iterIdx := index
-	existingSessionID := r.findAndLoadExistingSession(sup, taskID, &iterIdx)
+	var existingSessionID string
+	if resumeSessionID != "" && r.loadCommanderSession(sup, resumeSessionID) {
+		existingSessionID = resumeSessionID
+	}
 
 	// Track commander session ID for subtask callbacks
 	var iterCmdSessionID string
@@ -2818,6 +3598,8 @@ func (r *Runner) runSingleIteration(ctx context.Context, task config.Task, index
 		},
 		OnAgentCompaction:  agentCompactionCallback(streamer),
 		OnAgentSessionTurn: agentSessionTurnCallback(streamer),
+		OnProviderRetry:    providerRetryCallback(streamer),
+		OnParserRecovery:   parserRecoveryCallback(streamer),
 		DatasetStore:       r,
 		KnowledgeStore:     &knowledgeStoreAdapter{store: r.knowledgeStore},
 		DebugLogger:        r.debugLoggerInterface(),
@@ -2833,11 +3615,14 @@ func (r *Runner) runSingleIteration(ctx context.Context, task config.Task, index
 		AskCommanderWithCache: func(targetTask string, iterationIndex int, question string) (string, error) {
 			return r.askCommanderWithCache(ctx, targetTask, iterationIndex, task.Name, question)
 		},
+		AskPriorCommander: r.priorCommanderCallback(ctx, task.Name),
+		PriorMissions:     r.priorMissions,
 		OnSubmitOutput: func(idx int, output map[string]any) {
 			datasetName := task.Iterator.Dataset
 			outputJSON, _ := json.Marshal(output)
 			actualIdx := index
 			r.stores.Missions.StoreTaskOutput(taskID, &datasetName, &actualIdx, &itemID, string(outputJSON))
+			r.emitTaskOutput(task, taskID, &datasetName, &actualIdx, &itemID, output)
 		},
 		SessionLogger:     r.stores.Sessions,
 		TaskID:            taskID,
@@ -2863,8 +3648,10 @@ func (r *Runner) runSingleIteration(ctx context.Context, task config.Task, index
 		},
 	}, depSummaries)
 
-	// Restore any agent sessions from the store
-	r.restoreAgentSessions(ctx, sup, taskID, &iterIdx)
+	// Restore the interrupted iteration's agent sessions from the store
+	if existingSessionID != "" {
+		r.restoreAgentSessions(ctx, sup, taskID, &iterIdx, task.GetToolFilter(), task.AgentModels(), task.Sampling)
+	}
 
 	// Create iteration-specific streamer adapter
 	iterStreamer := &iterationStreamerAdapter{
@@ -2873,9 +3660,26 @@ func (r *Runner) runSingleIteration(ctx context.Context, task config.Task, index
 		streamer: streamer,
 	}
 
-	// Execute (or resume if stored messages were loaded)
-	err = sup.ExecuteOrResume(ctx, objective, iterStreamer)
+	// Execute (or resume if stored messages were loaded), bounded by the
+	// iteration timeout. Each retry attempt gets a fresh timeout.
+	var iterTimeout time.Duration
+	if task.Iterator != nil {
+		iterTimeout = task.Iterator.GetTimeout()
+	}
+	execCtx, cancelTimeout := withTimeout(ctx, &TimeoutError{TaskName: task.Name, Index: &iterIdx, Timeout: iterTimeout})
+	err = sup.ExecuteOrResume(execCtx, objective, iterStreamer)
+	if te := timeoutCause(execCtx); err != nil && te != nil {
+		err = te
+		if iterCmdSessionID != "" {
+			r.stores.Sessions.TimeOutSession(iterCmdSessionID)
+		}
+	}
+	cancelTimeout()
 	if err != nil {
+		if ctx.Err() != nil {
+			r.recordPartialResult(task.Name, &iterIdx, sup)
+		}
+		r.captureBrowserFailure(ctx, task.Name, index, agents)
 		sup.Close() // Close on failure
 		streamer.IterationFailed(task.Name, index, err)
 		return IterationResult{
@@ -2892,8 +3696,9 @@ func (r *Runner) runSingleIteration(ctx context.Context, task config.Task, index
 		if reason := sup.TaskFailureReason(); reason != "" {
 			failMsg = reason
 		}
+		failErr := failure.Wrap(sup.TaskFailureClass(), fmt.Errorf("%s", failMsg))
+		r.captureBrowserFailure(ctx, task.Name, index, agents)
 		sup.Close()
-		failErr := fmt.Errorf("%s", failMsg)
 		streamer.IterationFailed(task.Name, index, failErr)
 		return IterationResult{
 			Index:   index,
@@ -2917,6 +3722,7 @@ func (r *Runner) runSingleIteration(ctx context.Context, task config.Task, index
 	r.iterationCommanders[task.Name][index] = sup
 	r.mu.Unlock()
 
+	r.finishWorkspace(ws)
 	streamer.IterationCompleted(task.Name, index)
 	return IterationResult{
 		Index:   index,
@@ -2928,13 +3734,9 @@ func (r *Runner) runSingleIteration(ctx context.Context, task config.Task, index
 
 // resolveIterationObjective evaluates the objective with vars, inputs, and item context
 func (r *Runner) resolveIterationObjective(task config.Task, item cty.Value) (string, error) {
-	ctx := &hcl.EvalContext{
-		Variables: map[string]cty.Value{
-			"vars":   cty.ObjectVal(r.varsValues),
-			"inputs": cty.ObjectVal(r.inputValues),
-			"item":   item,
-		},
-	}
+	variables := task.EvalVariables(r.varsValues, r.inputValues)
+	variables["item"] = item
+	ctx := &hcl.EvalContext{Variables: variables}
 	val, diags := task.ObjectiveExpr.Value(ctx)
 	if diags.HasErrors() {
 		return "", fmt.Errorf("evaluating objective: %s", diags.Error())
@@ -3010,10 +3812,10 @@ func (s *iterationStreamerAdapter) SessionTurn(data protocol.SessionTurnData) {
 // Commander Query Support - allows commanders to query previous commanders
 // =============================================================================
 
-// queryAncestorsForContext queries each non-iterated ancestor commander with the task's objective
-// to get targeted context instead of generic summaries.
-// For iterated ancestors, we skip the query (they use ask_commander with specific indices instead).
-// Returns error if any ancestor query fails - this is a critical failure.
+// queryAncestorsForContext collects the push summary each ancestor stored
+// when it completed. No commander is queried here; a task that needs more
+// than the summary uses ask_commander. The error return is kept for
+// callers and is currently always nil.
 func (r *Runner) queryAncestorsForContext(ctx context.Context, taskName string, objective string) ([]agent.DependencySummary, error) {
 	depChain := r.getDependencyChain(taskName)
 	var depSummaries []agent.DependencySummary
@@ -3158,7 +3960,10 @@ func (r *Runner) askCommanderWithCache(ctx context.Context, targetTask string, i
 		Answer:   "",
 		Ready:    make(chan struct{}),
 	}
+	seq := len(r.askCommanderStore.questions[cacheKey])
 	r.askCommanderStore.questions[cacheKey] = append(r.askCommanderStore.questions[cacheKey], entry)
+	// Persist under the lock so stored seq values follow the in-memory order
+	r.recordQuestion(entry, targetTask, iterationIndex, seq)
 	r.askCommanderStore.mu.Unlock()
 
 	// Query the commander (outside lock)
@@ -3179,10 +3984,7 @@ func (r *Runner) askCommanderWithCache(ctx context.Context, targetTask string, i
 
 	if !ok {
 		// Mark as failed and close the channel
-		r.askCommanderStore.mu.Lock()
-		entry.Answer = "ERROR: commander not found"
-		close(entry.Ready)
-		r.askCommanderStore.mu.Unlock()
+		r.answerQuestion(entry, "ERROR: commander not found")
 		if iterationIndex >= 0 {
 			return "", fmt.Errorf("commander for task '%s' iteration %d not found", targetTask, iterationIndex)
 		}
@@ -3197,20 +3999,87 @@ func (r *Runner) askCommanderWithCache(ctx context.Context, targetTask string, i
 	answer, err := clone.AnswerQueryIsolated(ctx, question)
 	if err != nil {
 		// Mark as failed and close the channel
-		r.askCommanderStore.mu.Lock()
-		entry.Answer = fmt.Sprintf("ERROR: %v", err)
-		close(entry.Ready)
-		r.askCommanderStore.mu.Unlock()
+		r.answerQuestion(entry, fmt.Sprintf("ERROR: %v", err))
 		return "", err
 	}
 
 	// Store the answer and signal ready
+	r.answerQuestion(entry, answer)
+
+	return answer, nil
+}
+
+// interruptedQuestionAnswer answers questions a previous run registered but
+// never got an answer to.
+const interruptedQuestionAnswer = "ERROR: the mission stopped before this question was answered - ask it again"
+
+// recordQuestion persists a newly registered question. The caller holds
+// askCommanderStore.mu. A failed write only costs the question on resume,
+// so it doesn't fail the ask.
+func (r *Runner) recordQuestion(entry *questionEntry, targetTask string, iterationIndex, seq int) {
+	if r.stores == nil || r.stores.Questions == nil {
+		return
+	}
+	rec := store.CommanderQuestionRecord{
+		MissionID: r.missionID,
+		TaskName:  targetTask,
+		Seq:       seq,
+		Question:  entry.Question,
+	}
+	if iterationIndex >= 0 {
+		rec.IterationIndex = &iterationIndex
+	}
+	if id, err := r.stores.Questions.AddQuestion(rec); err == nil {
+		entry.ID = id
+	}
+}
+
+// answerQuestion sets a question's answer, releases anyone waiting on it,
+// and persists the answer.
+func (r *Runner) answerQuestion(entry *questionEntry, answer string) {
 	r.askCommanderStore.mu.Lock()
 	entry.Answer = answer
 	close(entry.Ready)
 	r.askCommanderStore.mu.Unlock()
 
-	return answer, nil
+	if entry.ID != "" {
+		r.stores.Questions.AnswerQuestion(entry.ID, answer)
+	}
+}
+
+// loadCommanderQuestions restores the shared question store from a previous
+// run of the mission, keeping each question at the index it had, so
+// list_commander_questions and get_commander_answer see the same list.
+func (r *Runner) loadCommanderQuestions(missionID string) error {
+	if r.stores.Questions == nil {
+		return nil
+	}
+	records, err := r.stores.Questions.GetQuestions(missionID)
+	if err != nil {
+		return err
+	}
+
+	r.askCommanderStore.mu.Lock()
+	defer r.askCommanderStore.mu.Unlock()
+	for _, rec := range records {
+		cacheKey := rec.TaskName
+		if rec.IterationIndex != nil {
+			cacheKey = fmt.Sprintf("%s[%d]", rec.TaskName, *rec.IterationIndex)
+		}
+		answer := interruptedQuestionAnswer
+		if rec.Answer != nil {
+			answer = *rec.Answer
+		}
+		entry := &questionEntry{
+			ID:       rec.ID,
+			Question: rec.Question,
+			Answer:   answer,
+			Ready:    make(chan struct{}),
+		}
+		close(entry.Ready)
+		r.askCommanderStore.questions[cacheKey] = append(r.askCommanderStore.questions[cacheKey], entry)
+	}
+	return nil
 }
 
 // =============================================================================
@@ -3240,6 +4109,14 @@ func (r *Runner) SetDataset(name string, items []cty.Value) error {
 	if ds == nil {
 		return fmt.Errorf("dataset '%s' not found", name)
 	}
+	if ds.FromTask != "" {
+		return fmt.Errorf("dataset '%s' is filled from the output of task '%s' and cannot be modified", name, ds.FromTask)
+	}
+
+	items, err := ds.ApplyTransforms(items, nil)
+	if err != nil {
+		return err
+	}
 
 	// Validate items against schema if present
 	for i, item := range items {
@@ -3281,6 +4158,28 @@ func (r *Runner) AppendDataset(name string, items []cty.Value) error {
 	if ds == nil {
 		return fmt.Errorf("dataset '%s' not found", name)
 	}
+	if ds.FromTask != "" {
+		return fmt.Errorf("dataset '%s' is filled from the output of task '%s' and cannot be modified", name, ds.FromTask)
+	}
+	if !ok {
+		return fmt.Errorf("dataset '%s' not initialized", name)
+	}
+
+	if len(ds.Transforms) > 0 {
+		// Dedupe and limit steps account for the items already stored
+		count, err := r.stores.Datasets.GetItemCount(dsID)
+		if err != nil {
+			return fmt.Errorf("read dataset '%s': %w", name, err)
+		}
+		existing, err := r.stores.Datasets.GetItems(dsID, 0, count)
+		if err != nil {
+			return fmt.Errorf("read dataset '%s': %w", name, err)
+		}
+		items, err = ds.ApplyTransforms(items, existing)
+		if err != nil {
+			return err
+		}
+	}
 
 	for i, item := range items {
 		if err := ds.ValidateItem(item); err != nil {
@@ -3288,8 +4187,8 @@ func (r *Runner) AppendDataset(name string, items []cty.Value) error {
 		}
 	}
 
-	if !ok {
-		return fmt.Errorf("dataset '%s' not initialized", name)
+	if len(items) == 0 {
+		return nil
 	}
 	if err := r.stores.Datasets.AddItems(dsID, items); err != nil {
 		return fmt.Errorf("append to dataset '%s': %w", name, err)
@@ -3365,6 +4264,28 @@ func (r *Runner) GetKnowledgeStore() KnowledgeStore {
 	return r.knowledgeStore
 }
 
+// iterationSummaries returns the summaries of a parallel iterated task's
+// iteration commanders, by iteration index. Sequential iterations share one
+// commander, whose summary is already the task summary.
+func (r *Runner) iterationSummaries(taskName string) map[int]string {
+	task := r.mission.GetTaskByName(taskName)
+	if task == nil || task.Iterator == nil || !task.Iterator.Parallel {
+		return nil
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	summaries := make(map[int]string)
+	for idx, sup := range r.iterationCommanders[taskName] {
+		if sup == nil {
+			continue
+		}
+		if summary := sup.TaskSummary(); summary != "" {
+			summaries[idx] = summary
+		}
+	}
+	return summaries
+}
+
 // GetTaskOutputSchema returns the output schema for a task by name
 func (r *Runner) GetTaskOutputSchema(taskName string) *config.OutputSchema {
 	task := r.mission.GetTaskByName(taskName)
@@ -3545,6 +4466,28 @@ func (a *knowledgeStoreAdapter) Aggregate(taskName string, query agent.Aggregate
 	return agentResult
 }
 
+// Search implements agent.KnowledgeStore
+func (a *knowledgeStoreAdapter) Search(query agent.TaskSearchQuery) agent.TaskSearchResult {
+	result := a.store.Search(SearchQuery{
+		Text:  query.Text,
+		Tasks: query.Tasks,
+		Limit: query.Limit,
+	})
+
+	hits := make([]agent.TaskSearchHit, len(result.Hits))
+	for i, h := range result.Hits {
+		hits[i] = agent.TaskSearchHit{
+			TaskName: h.TaskName,
+			Kind:     h.Kind,
+			Index:    h.Index,
+			ItemID:   h.ItemID,
+			Score:    h.Score,
+			Snippet:  h.Snippet,
+		}
+	}
+	return agent.TaskSearchResult{TotalMatches: result.TotalMatches, Hits: hits}
+}
+
 // collectStartedIterations returns the set of iteration indices that have
 // already had an iteration_started event emitted for the given task in the
 // current mission. Used by the resume path to suppress duplicate emissions
@@ -2,16 +2,25 @@ package mission
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/zclconf/go-cty/cty"
 
+	"squadron/agent"
 	"squadron/config"
 	"squadron/llm"
+	"squadron/streamers/webhook"
 )
 
 var _ = Describe("Runner Integration", func() {
@@ -292,6 +301,303 @@ var _ = Describe("Runner Integration", func() {
 		})
 	})
 
+	Describe("mission webhooks", func() {
+		It("posts signed lifecycle events, including mission_failed", func() {
+			var mu sync.Mutex
+			var received []webhook.Payload
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				if r.Header.Get(webhook.HeaderSignature) != webhook.Sign("s3cret", body) {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				var p webhook.Payload
+				Expect(json.Unmarshal(body, &p)).To(Succeed())
+				Expect(r.Header.Get(webhook.HeaderEvent)).To(Equal(p.Event))
+				mu.Lock()
+				received = append(received, p)
+				mu.Unlock()
+			}))
+			defer srv.Close()
+
+			mission := testMission("test_hooks", []config.Task{
+				testTask("broken", "This will fail"),
+			})
+			mission.Notifications = &config.Notifications{Webhooks: []config.Webhook{{
+				Name:   "ops",
+				URL:    srv.URL,
+				Secret: "s3cret",
+				Events: []string{config.NotifyMissionStarted, config.NotifyTaskFailed, config.NotifyMissionFailed},
+			}}}
+			cfg := buildTestConfig(mission, testAgent("worker"))
+
+			provider := newMockProvider(
+				cmdCallAgent("worker", "Try something"),
+				agentAnswer("I tried but it didn't work."),
+				cmdTaskCompleteFail("Could not complete the work"),
+			)
+
+			streamer, err := runMission(cfg, "test_hooks", provider, nil)
+			Expect(err).To(HaveOccurred())
+			Expect(streamer.hasEvent("task_failed")).To(BeTrue())
+
+			// Run flushes pending deliveries before returning.
+			mu.Lock()
+			defer mu.Unlock()
+			var events []string
+			for _, p := range received {
+				Expect(p.MissionName).To(Equal("test_hooks"))
+				Expect(p.MissionID).NotTo(BeEmpty())
+				events = append(events, p.Event)
+			}
+			Expect(events).To(Equal([]string{"mission_started", "task_failed", "mission_failed"}))
+			Expect(received[1].TaskName).To(Equal("broken"))
+			Expect(received[2].Error).NotTo(BeEmpty())
+		})
+	})
+
+	Describe("parallel iteration resume", func() {
+		It("continues interrupted iteration sessions and starts the rest fresh", func() {
+			task := testTask("process", "Process item")
+			task.Iterator = &config.TaskIterator{Dataset: "items", Parallel: true}
+			mission := testMission("test_iter_resume", []config.Task{task})
+			items := []cty.Value{
+				cty.ObjectVal(map[string]cty.Value{"name": cty.StringVal("alpha")}),
+				cty.ObjectVal(map[string]cty.Value{"name": cty.StringVal("beta")}),
+			}
+			mission.Datasets = []config.Dataset{{Name: "items", Items: items}}
+			cfg := buildTestConfig(mission, testAgent("worker"))
+
+			provider := newMockProvider(cmdTaskComplete(), cmdTaskComplete())
+			runner, err := NewRunner(cfg, "", "test_iter_resume", nil, WithProviderFactory(func() llm.Provider { return provider }))
+			Expect(err).NotTo(HaveOccurred())
+			defer runner.CloseStores()
+
+			missionID, err := runner.stores.Missions.CreateMission("test_iter_resume", "{}", "{}")
+			Expect(err).NotTo(HaveOccurred())
+			runner.missionID = missionID
+			taskID, err := runner.stores.Missions.CreateTask(missionID, "process", "{}")
+			Expect(err).NotTo(HaveOccurred())
+
+			// Prior run: iteration 0 ran to the end but produced no output, and
+			// iteration 1 was interrupted mid-conversation.
+			idx0, idx1 := 0, 1
+			finished, err := runner.stores.Sessions.CreateSession(taskID, "commander", "", "claude_sonnet_4", &idx0)
+			Expect(err).NotTo(HaveOccurred())
+			runner.stores.Sessions.CompleteSession(finished, nil)
+			interrupted, err := runner.stores.Sessions.CreateSession(taskID, "commander", "", "claude_sonnet_4", &idx1)
+			Expect(err).NotTo(HaveOccurred())
+			prior := llm.Message{Role: llm.RoleUser, Content: "Process beta (interrupted before the reply)"}
+			now := time.Now()
+			Expect(runner.stores.Sessions.AppendStructuredMessage(interrupted, string(prior.Role), prior.Content, agent.PartsFromMessage(prior), now, now)).To(Succeed())
+
+			Expect(runner.findInterruptedIterationSessions(taskID)).To(Equal(map[int]string{1: interrupted}))
+
+			results := runner.runParallelIterationsWithIndices(context.Background(), task, items, []int{0, 1}, taskID, nil, newMockMissionStreamer(), nil)
+			Expect(results).To(HaveLen(2))
+			Expect(results[0].Success).To(BeTrue())
+			Expect(results[1].Success).To(BeTrue())
+
+			// Exactly one call continued the stored conversation.
+			resumedCalls := 0
+			for _, call := range provider.getCalls() {
+				for _, m := range call.Messages {
+					if strings.Contains(m.GetTextContent(), "interrupted before the reply") {
+						resumedCalls++
+						break
+					}
+				}
+			}
+			Expect(resumedCalls).To(Equal(1))
+
+			// Iteration 1 reused its session; iteration 0 got a new one.
+			sessions, err := runner.stores.Sessions.GetSessionsByTask(taskID)
+			Expect(err).NotTo(HaveOccurred())
+			perIndex := map[int]int{}
+			for _, s := range sessions {
+				if s.Role == "commander" && s.IterationIndex != nil {
+					perIndex[*s.IterationIndex]++
+				}
+			}
+			Expect(perIndex).To(Equal(map[int]int{0: 2, 1: 1}))
+		})
+	})
+
+	Describe("approval gates", func() {
+		approvalTask := func() config.Task {
+			task := testTask("deploy", "Deploy the release")
+			task.RequireApproval = true
+			task.Output = &config.OutputSchema{
+				Fields: []config.OutputField{
+					{Name: "target", Type: "string", Description: "Where it went", Required: true},
+				},
+			}
+			return task
+		}
+
+		runWithApprover := func(cfg *config.Config, name string, provider *mockProvider, approver Approver) (*Runner, *mockMissionStreamer, error) {
+			streamer := newMockMissionStreamer()
+			opts := []RunnerOption{WithProviderFactory(func() llm.Provider { return provider })}
+			if approver != nil {
+				opts = append(opts, WithApprover(approver))
+			}
+			runner, err := NewRunner(cfg, "", name, nil, opts...)
+			Expect(err).NotTo(HaveOccurred())
+			DeferCleanup(runner.CloseStores)
+			return runner, streamer, runner.Run(context.Background(), streamer)
+		}
+
+		draftResponses := func() *mockProvider {
+			return newMockProvider(
+				cmdSubmitOutput(map[string]interface{}{"target": "staging"}),
+				cmdTaskComplete(),
+			)
+		}
+
+		It("commits the edited output and summary once approved", func() {
+			cfg := buildTestConfig(testMission("test_approval_edit", []config.Task{approvalTask()}), testAgent("worker"))
+			approver := &fakeApprover{decide: func(req ApprovalRequest) ApprovalDecision {
+				return ApprovalDecision{Approved: true, Summary: "Deployed to production.", Output: map[string]any{"target": "production"}}
+			}}
+
+			runner, streamer, err := runWithApprover(cfg, "test_approval_edit", draftResponses(), approver)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(approver.requests).To(HaveLen(1))
+			Expect(approver.requests[0].TaskName).To(Equal("deploy"))
+			Expect(approver.requests[0].Output).To(HaveKeyWithValue("target", "staging"))
+			Expect(approver.requests[0].Summary).To(Equal("Task completed successfully."))
+
+			Expect(streamer.hasEvent("task_approval_requested")).To(BeTrue())
+			var resolved map[string]string
+			for _, e := range streamer.getEvents() {
+				if e.Type == "task_approval_resolved" {
+					resolved = e.Data
+				}
+			}
+			Expect(resolved).To(HaveKeyWithValue("approved", "true"))
+			Expect(resolved).To(HaveKeyWithValue("edited", "true"))
+			Expect(streamer.hasEvent("task_completed")).To(BeTrue())
+
+			task, err := runner.stores.Missions.GetTaskByName(runner.missionID, "deploy")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*task.Summary).To(Equal("Deployed to production."))
+			Expect(*task.OutputJSON).To(ContainSubstring("production"))
+			outputs, err := runner.stores.Missions.GetTaskOutputs(task.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(outputs).To(HaveLen(1))
+			Expect(outputs[0].OutputJSON).To(ContainSubstring("production"))
+		})
+
+		It("fails the task when the draft is rejected", func() {
+			cfg := buildTestConfig(testMission("test_approval_reject", []config.Task{approvalTask()}), testAgent("worker"))
+			approver := &fakeApprover{decide: func(ApprovalRequest) ApprovalDecision {
+				return ApprovalDecision{Reason: "wrong environment"}
+			}}
+
+			runner, streamer, err := runWithApprover(cfg, "test_approval_reject", draftResponses(), approver)
+			var rejected *ApprovalRejectedError
+			Expect(errors.As(err, &rejected)).To(BeTrue())
+			Expect(rejected.Reason).To(Equal("wrong environment"))
+			Expect(streamer.hasEvent("task_failed")).To(BeTrue())
+
+			task, err := runner.stores.Missions.GetTaskByName(runner.missionID, "deploy")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(task.Status).To(Equal("failed"))
+			outputs, err := runner.stores.Missions.GetTaskOutputs(task.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(outputs).To(BeEmpty())
+		})
+
+		It("refuses to start without an approver", func() {
+			cfg := buildTestConfig(testMission("test_approval_none", []config.Task{approvalTask()}), testAgent("worker"))
+			_, streamer, err := runWithApprover(cfg, "test_approval_none", draftResponses(), nil)
+			Expect(err).To(MatchError(ContainSubstring("requires approval but no approver is available")))
+			Expect(streamer.hasEvent("mission_started")).To(BeFalse())
+		})
+	})
+
+	Describe("verified tasks", func() {
+		It("asks the commander to check its work and stores its confidence", func() {
+			task := testTask("research", "Research the market")
+			task.Verify = true
+			provider := newMockProvider(
+				cmdTaskComplete(),
+				mockToolCall("task_complete", json.RawMessage(`{"summary": "Checked against the sources.", "confidence": 0.9}`)),
+			)
+			cfg := buildTestConfig(testMission("test_verify", []config.Task{task}), testAgent("worker"))
+			runner, err := NewRunner(cfg, "", "test_verify", nil, WithProviderFactory(func() llm.Provider { return provider }))
+			Expect(err).NotTo(HaveOccurred())
+			DeferCleanup(runner.CloseStores)
+			Expect(runner.Run(context.Background(), newMockMissionStreamer())).To(Succeed())
+
+			Expect(provider.callCount()).To(Equal(2))
+			stored, err := runner.stores.Missions.GetTaskByName(runner.missionID, "research")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stored.Status).To(Equal("completed"))
+			Expect(*stored.Summary).To(Equal("Checked against the sources."))
+			Expect(stored.Confidence).NotTo(BeNil())
+			Expect(*stored.Confidence).To(Equal(0.9))
+		})
+	})
+
+	Describe("timeouts", func() {
+		It("fails a task that exceeds its timeout and marks its session timed out", func() {
+			task := testTask("slow", "Take forever")
+			task.Timeout = "50ms"
+			cfg := buildTestConfig(testMission("test_task_timeout", []config.Task{task}), testAgent("worker"))
+
+			provider := newMockProvider(hangingCall())
+			streamer := newMockMissionStreamer()
+			runner, err := NewRunner(cfg, "", "test_task_timeout", nil, WithProviderFactory(func() llm.Provider { return provider }))
+			Expect(err).NotTo(HaveOccurred())
+			defer runner.CloseStores()
+
+			err = runner.Run(context.Background(), streamer)
+			var te *TimeoutError
+			Expect(errors.As(err, &te)).To(BeTrue())
+			Expect(te.TaskName).To(Equal("slow"))
+			Expect(te.Index).To(BeNil())
+			Expect(streamer.hasEvent("task_failed")).To(BeTrue())
+
+			var timeoutIssues int
+			for _, e := range streamer.getEvents() {
+				if e.Type == "mission_issue" && e.Data["category"] == "timeout" {
+					timeoutIssues++
+				}
+			}
+			Expect(timeoutIssues).To(Equal(1))
+
+			tasks, err := runner.stores.Missions.GetTasksByMission(runner.missionID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tasks).To(HaveLen(1))
+			Expect(tasks[0].Status).To(Equal("failed"))
+			sessions, err := runner.stores.Sessions.GetSessionsByTask(tasks[0].ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sessions).To(HaveLen(1))
+			Expect(sessions[0].Status).To(Equal("timed_out"))
+		})
+
+		It("counts an iteration timeout against max_retries", func() {
+			task := testTask("process", "Process item")
+			task.Iterator = &config.TaskIterator{Dataset: "items", Parallel: true, MaxRetries: 1, Timeout: "50ms"}
+			mission := testMission("test_iter_timeout", []config.Task{task})
+			mission.Datasets = []config.Dataset{{
+				Name:  "items",
+				Items: []cty.Value{cty.ObjectVal(map[string]cty.Value{"name": cty.StringVal("alpha")})},
+			}}
+			cfg := buildTestConfig(mission, testAgent("worker"))
+
+			// First attempt hangs past the timeout; the retry completes.
+			provider := newMockProvider(hangingCall(), cmdTaskComplete())
+			streamer, err := runMission(cfg, "test_iter_timeout", provider, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(streamer.eventCount("iteration_retrying")).To(Equal(1))
+			Expect(streamer.hasEvent("iteration_failed")).To(BeTrue())
+			Expect(streamer.hasEvent("task_completed")).To(BeTrue())
+		})
+	})
+
 	// -----------------------------------------------------------------------
 	// g) Parallel iterated task
 	// -----------------------------------------------------------------------
@@ -324,9 +630,11 @@ var _ = Describe("Runner Integration", func() {
 
 			// Parallel iterations interleave unpredictably.
 			// Use matchers to route responses to the correct session type.
+			// The commander's prompt lists the agent's personality too, so
+			// match the "Personality:" line only the agent's own prompt has.
 			isAgentSession := func(req *llm.ChatRequest) bool {
 				for _, m := range req.Messages {
-					if m.Role == llm.RoleSystem && strings.Contains(m.Content, "Test agent") {
+					if m.Role == llm.RoleSystem && strings.Contains(m.Content, "Personality: Test agent") {
 						return true
 					}
 				}
@@ -571,7 +879,7 @@ var _ = Describe("Runner Integration", func() {
 
 			isAgentSession := func(req *llm.ChatRequest) bool {
 				for _, m := range req.Messages {
-					if m.Role == llm.RoleSystem && strings.Contains(m.Content, "Test agent") {
+					if m.Role == llm.RoleSystem && strings.Contains(m.Content, "Personality: Test agent") {
 						return true
 					}
 				}
@@ -708,6 +1016,75 @@ var _ = Describe("Runner Integration", func() {
 			Expect(firstEvent(streamer, "mission_issue").Data["severity"]).To(Equal("fatal"))
 		})
 
+		It("refuses the next LLM call once the llm_calls budget is spent and persists budget_exceeded", func() {
+			// Two calls are allowed (commander call_agent + agent answer); the
+			// commander's third turn is refused before it reaches the provider.
+			task := testTask("work", "Do something")
+			task.Budget = &config.Budget{LLMCalls: tokens(2)}
+			mission := testMission("test_call_budget", []config.Task{task})
+			cfg := buildTestConfig(mission, testAgent("worker"))
+
+			provider := newMockProvider(
+				cmdCallAgent("worker", "Do the work"),
+				agentAnswer("Work is done."),
+				cmdTaskComplete(),
+			)
+
+			runner, err := NewRunner(cfg, "", "test_call_budget", nil, WithProviderFactory(func() llm.Provider { return provider }))
+			Expect(err).NotTo(HaveOccurred())
+			defer runner.CloseStores()
+
+			err = runner.Run(context.Background(), newMockMissionStreamer())
+			Expect(err).To(HaveOccurred())
+			var breach *BudgetBreach
+			Expect(errors.As(err, &breach)).To(BeTrue())
+			Expect(breach.Kind).To(Equal(BudgetKindLLMCalls))
+			Expect(breach.Used).To(Equal(float64(2)))
+
+			calls := make(map[string]int64)
+			for _, sp := range breach.Breakdown {
+				Expect(sp.TaskName).To(Equal("work"))
+				calls[sp.Entity] = sp.LLMCalls
+			}
+			Expect(calls).To(Equal(map[string]int64{"commander": 1, "worker": 1}))
+
+			record, err := runner.stores.Missions.GetMission(runner.missionID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(record.Status).To(Equal("budget_exceeded"))
+			Expect(record.FinishedAt).NotTo(BeNil())
+		})
+
+		It("resumes a budget_exceeded mission as running once the budget is raised", func() {
+			task := testTask("work", "Do something")
+			task.Budget = &config.Budget{LLMCalls: tokens(1)}
+			cfg := buildTestConfig(testMission("test_budget_resume", []config.Task{task}), testAgent("worker"))
+			cfg.Storage.Path = filepath.Join(GinkgoT().TempDir(), "store.db")
+
+			provider := newMockProvider(cmdCallAgent("worker", "Do the work"), agentAnswer("Work is done."))
+			runner, err := NewRunner(cfg, "", "test_budget_resume", nil, WithProviderFactory(func() llm.Provider { return provider }))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(runner.Run(context.Background(), newMockMissionStreamer())).NotTo(Succeed())
+			missionID := runner.missionID
+			runner.CloseStores()
+
+			cfg.Missions[0].Tasks[0].Budget = &config.Budget{LLMCalls: tokens(10)}
+			provider = newMockProvider(hangingCall())
+			resumed, err := NewRunner(cfg, "", "test_budget_resume", nil, WithResume(missionID), WithProviderFactory(func() llm.Provider { return provider }))
+			Expect(err).NotTo(HaveOccurred())
+			defer resumed.CloseStores()
+			done := make(chan error, 1)
+			go func() { done <- resumed.Run(context.Background(), newMockMissionStreamer()) }()
+			Eventually(provider.callCount).Should(Equal(1))
+
+			Expect(resumed.stateMgr.GetMissionState()).To(Equal(MissionRunning))
+			record, err := resumed.stores.Missions.GetMission(missionID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(record.Status).To(Equal("running"))
+
+			resumed.Cancel("test done")
+			Eventually(done).Should(Receive())
+		})
+
 		It("runs to completion when usage stays under budget", func() {
 			// Regression guard: budgets must not fire on the happy path.
 			task := testTask("work", "Do something")
@@ -728,6 +1105,103 @@ var _ = Describe("Runner Integration", func() {
 			Expect(streamer.hasEvent("mission_issue")).To(BeFalse())
 		})
 	})
+
+	// -----------------------------------------------------------------------
+	// Prior mission queries (ask_prior_commander)
+	// -----------------------------------------------------------------------
+	Describe("prior mission queries", func() {
+		It("revives a completed mission's commander from the store to answer questions", func() {
+			first := testMission("first", []config.Task{testTask("research", "Research the harbor schedule")})
+			second := testMission("second", []config.Task{testTask("report", "Write the report")})
+			cfg := buildTestConfig(first, testAgent("worker"))
+			cfg.Missions = append(cfg.Missions, second)
+			cfg.Storage.Path = filepath.Join(GinkgoT().TempDir(), "store.db")
+
+			firstProvider := newMockProvider(cmdTaskComplete())
+			firstRunner, err := NewRunner(cfg, "", "first", nil, WithProviderFactory(func() llm.Provider { return firstProvider }))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(firstRunner.Run(context.Background(), newMockMissionStreamer())).To(Succeed())
+			firstRunner.CloseStores()
+			priorID := firstRunner.missionID
+
+			provider := newMockProvider(agentAnswer("Ferries leave hourly."))
+			runner, err := NewRunner(cfg, "", "second", nil,
+				WithPriorMissions(priorID),
+				WithProviderFactory(func() llm.Provider { return provider }))
+			Expect(err).NotTo(HaveOccurred())
+			defer runner.CloseStores()
+			defer runner.closePriorCommanders()
+
+			Expect(runner.priorMissions).To(ConsistOf(agent.PriorMissionInfo{ID: priorID, Name: "first", Tasks: []string{"research"}}))
+
+			answer, err := runner.AskPriorCommander(context.Background(), priorID, "research", -1, "report", "How often do ferries leave?")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(answer).To(Equal("Ferries leave hourly."))
+
+			// The revived commander carries the prior mission's conversation.
+			calls := provider.getCalls()
+			Expect(calls).To(HaveLen(1))
+			var transcript strings.Builder
+			for _, m := range calls[0].Messages {
+				transcript.WriteString(m.GetTextContent())
+			}
+			Expect(transcript.String()).To(ContainSubstring("Research the harbor schedule"))
+			Expect(transcript.String()).To(ContainSubstring("How often do ferries leave?"))
+
+			_, err = runner.AskPriorCommander(context.Background(), "not-a-mission", "research", -1, "report", "?")
+			Expect(err).To(MatchError(ContainSubstring("not a referenced prior mission")))
+		})
+
+		It("charges each question to the asking task's budget", func() {
+			first := testMission("first", []config.Task{testTask("research", "Research the harbor schedule")})
+			limit := int64(10)
+			report := testTask("report", "Write the report")
+			report.Budget = &config.Budget{LLMCalls: &limit}
+			review := testTask("review", "Review the report")
+			review.Budget = &config.Budget{LLMCalls: &limit}
+			second := testMission("second", []config.Task{report, review})
+			cfg := buildTestConfig(first, testAgent("worker"))
+			cfg.Missions = append(cfg.Missions, second)
+			cfg.Storage.Path = filepath.Join(GinkgoT().TempDir(), "store.db")
+
+			firstProvider := newMockProvider(cmdTaskComplete())
+			firstRunner, err := NewRunner(cfg, "", "first", nil, WithProviderFactory(func() llm.Provider { return firstProvider }))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(firstRunner.Run(context.Background(), newMockMissionStreamer())).To(Succeed())
+			firstRunner.CloseStores()
+			priorID := firstRunner.missionID
+
+			provider := newMockProvider(agentAnswer("Ferries leave hourly."), agentAnswer("Hourly, from dawn."))
+			runner, err := NewRunner(cfg, "", "second", nil,
+				WithPriorMissions(priorID),
+				WithProviderFactory(func() llm.Provider { return provider }))
+			Expect(err).NotTo(HaveOccurred())
+			defer runner.CloseStores()
+			defer runner.closePriorCommanders()
+
+			// Both questions hit the same cached commander; each is charged
+			// to the task that asked it, not the one that revived it.
+			_, err = runner.AskPriorCommander(context.Background(), priorID, "research", -1, "report", "How often do ferries leave?")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = runner.AskPriorCommander(context.Background(), priorID, "research", -1, "review", "When do they start?")
+			Expect(err).NotTo(HaveOccurred())
+
+			spent := map[string]BudgetSpend{}
+			for _, s := range runner.budgetTracker.Breakdown() {
+				spent[s.TaskName] = s
+			}
+			Expect(spent["report"].LLMCalls).To(Equal(int64(1)))
+			Expect(spent["report"].Tokens).To(Equal(int64(150)))
+			Expect(spent["review"].LLMCalls).To(Equal(int64(1)))
+			Expect(spent["review"].Tokens).To(Equal(int64(150)))
+		})
+
+		It("rejects references to missions that are not in the store", func() {
+			cfg := buildTestConfig(testMission("second", []config.Task{testTask("report", "Write the report")}), testAgent("worker"))
+			_, err := NewRunner(cfg, "", "second", nil, WithPriorMissions("missing"))
+			Expect(err).To(MatchError(ContainSubstring("prior mission 'missing'")))
+		})
+	})
 })
 
 // firstEvent returns the first recorded event of the given type, or nil.
@@ -0,0 +1,76 @@
+package mission
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"squadron/config"
+)
+
+var _ = Describe("Scripted provider", func() {
+	It("runs a mission end to end from a config with no API keys", func() {
+		dir := GinkgoT().TempDir()
+		path := filepath.Join(dir, "config.hcl")
+		Expect(os.WriteFile(path, []byte(`
+model "fake" {
+  provider = "scripted"
+
+  response {
+    match = "Commander Agent System Prompt"
+    tool_call "call_agent" {
+      input = { name = "worker", task = "Count the items" }
+    }
+  }
+  response {
+    match   = "user: <NEW_TASK>\\s+Count the items"
+    content = "<ANSWER>There are 3 items.</ANSWER>"
+  }
+  response {
+    match = "tool_result: There are 3 items."
+    tool_call "task_complete" {
+      input = { succeed = true }
+    }
+  }
+}
+
+agent "worker" {
+  model       = models.fake.scripted
+  personality = "Careful"
+  role        = "Counts things"
+}
+
+mission "count" {
+  commander {
+    model = models.fake.scripted
+  }
+  agents = [agents.worker]
+  task "count" {
+    objective = "Count the items"
+  }
+}
+
+storage {
+  backend = "sqlite"
+  path    = "`+filepath.Join(dir, "store.db")+`"
+}
+`), 0o644)).To(Succeed())
+
+		cfg, err := config.LoadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Validate()).To(Succeed())
+
+		runner, err := NewRunner(cfg, path, "count", nil)
+		Expect(err).NotTo(HaveOccurred())
+		defer runner.CloseStores()
+		Expect(runner.Run(context.Background(), newMockMissionStreamer())).To(Succeed())
+
+		tasks, err := runner.stores.Missions.GetTasksByMission(runner.MissionID())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tasks).To(HaveLen(1))
+		Expect(tasks[0].Status).To(Equal("completed"))
+	})
+})
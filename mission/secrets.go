@@ -0,0 +1,33 @@
+package mission
+
+import (
+	"context"
+
+	"squadron/agent"
+	"squadron/secrets"
+)
+
+// resolveConfigSecrets fetches the secrets declared in the config's secrets
+// block and adds them to the secrets commanders and agents can reference as
+// ${secrets.<name>}, next to protected inputs. Values are fetched on every
+// run (and resume) and never stored.
+func (r *Runner) resolveConfigSecrets(ctx context.Context) error {
+	if r.cfg.Secrets == nil || len(r.cfg.Secrets.Secrets) == 0 {
+		return nil
+	}
+	values, err := secrets.NewResolver(r.cfg.Secrets).ResolveAll(ctx)
+	if err != nil {
+		return err
+	}
+	if r.secretValues == nil {
+		r.secretValues = make(map[string]string, len(values))
+	}
+	for _, s := range r.cfg.Secrets.Secrets {
+		r.secretValues[s.Name] = values[s.Name]
+		r.secretInfos = append(r.secretInfos, agent.SecretInfo{
+			Name:        s.Name,
+			Description: s.Description,
+		})
+	}
+	return nil
+}
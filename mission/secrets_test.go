@@ -0,0 +1,49 @@
+package mission
+
+import (
+	"context"
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"squadron/agent"
+	"squadron/config"
+	"squadron/llm"
+)
+
+var _ = Describe("Config secrets", func() {
+	It("offers secrets from the secrets block to the mission", func() {
+		os.Setenv("SQUADRON_TEST_SECRET", "s3cr3t")
+		DeferCleanup(os.Unsetenv, "SQUADRON_TEST_SECRET")
+
+		mission := testMission("with_secrets", []config.Task{testTask("only", "Do the thing")})
+		cfg := buildTestConfig(mission, testAgent("worker"))
+		cfg.Secrets = &config.SecretsConfig{Secrets: []config.Secret{
+			{Name: "api_token", Description: "Token for the API", Env: "SQUADRON_TEST_SECRET"},
+		}}
+
+		provider := newMockProvider(cmdTaskComplete())
+		runner, err := NewRunner(cfg, "", mission.Name, nil, WithProviderFactory(func() llm.Provider { return provider }))
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(runner.CloseStores)
+		Expect(runner.Run(context.Background(), newMockMissionStreamer())).To(Succeed())
+
+		Expect(runner.secretValues).To(HaveKeyWithValue("api_token", "s3cr3t"))
+		Expect(runner.secretInfos).To(ContainElement(agent.SecretInfo{Name: "api_token", Description: "Token for the API"}))
+	})
+
+	It("fails the run when a secret can't be resolved", func() {
+		mission := testMission("missing_secret", []config.Task{testTask("only", "Do the thing")})
+		cfg := buildTestConfig(mission, testAgent("worker"))
+		cfg.Secrets = &config.SecretsConfig{Secrets: []config.Secret{
+			{Name: "api_token", Env: "SQUADRON_TEST_SECRET_UNSET"},
+		}}
+
+		runner, err := NewRunner(cfg, "", mission.Name, nil, WithProviderFactory(func() llm.Provider { return newMockProvider() }))
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(runner.CloseStores)
+		err = runner.Run(context.Background(), newMockMissionStreamer())
+		Expect(err).To(MatchError(ContainSubstring("resolving secrets: secret 'api_token': environment variable SQUADRON_TEST_SECRET_UNSET is not set")))
+	})
+})
@@ -0,0 +1,59 @@
+package mission
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"squadron/config"
+	"squadron/llm"
+)
+
+var _ = Describe("Agent task summaries", func() {
+	// run has the commander give the worker two tasks and returns the
+	// messages of the worker's second turn.
+	run := func(summarize bool) []llm.Message {
+		worker := testAgent("worker")
+		worker.SummarizeBetweenTasks = summarize
+		cfg := buildTestConfig(testMission("reassigned", []config.Task{testTask("work", "Do the work")}), worker)
+
+		provider := newMockProvider(
+			cmdCallAgent("worker", "Count the rows"),
+			textResponse("<ANSWER>There are 42 rows.</ANSWER>"),
+			cmdCallAgent("worker", "Sum the totals"),
+			textResponse("<ANSWER>The total is 7.</ANSWER>"),
+			cmdTaskComplete(),
+		)
+		runner, err := NewRunner(cfg, "", "reassigned", nil, WithProviderFactory(func() llm.Provider { return provider }))
+		Expect(err).NotTo(HaveOccurred())
+		defer runner.CloseStores()
+		Expect(runner.Run(context.Background(), newMockMissionStreamer())).To(Succeed())
+
+		calls := provider.getCalls()
+		Expect(calls).To(HaveLen(5))
+		Expect(systemPrompt(calls[3])).To(HavePrefix("# Agent System Prompt"))
+		var conversation []llm.Message
+		for _, m := range calls[3].Messages {
+			if m.Role != llm.RoleSystem {
+				conversation = append(conversation, m)
+			}
+		}
+		return conversation
+	}
+
+	It("keeps the agent's full history by default", func() {
+		msgs := run(false)
+		Expect(msgs).To(HaveLen(3))
+		Expect(msgs[0].Content).To(ContainSubstring("<NEW_TASK>\nCount the rows"))
+	})
+
+	It("replaces the earlier task with a summary when summarize_between_tasks is set", func() {
+		msgs := run(true)
+		Expect(msgs).To(HaveLen(2))
+		Expect(msgs[0].Content).To(HavePrefix("<PREVIOUS_TASKS>"))
+		Expect(msgs[0].Content).To(ContainSubstring("- Task: Count the rows"))
+		Expect(msgs[0].Content).To(ContainSubstring("Answer: There are 42 rows."))
+		Expect(msgs[1].Content).To(ContainSubstring("<NEW_TASK>\nSum the totals"))
+	})
+})
@@ -16,6 +16,12 @@ const (
 	TaskFailed    TaskState = "failed"
 	TaskStopping  TaskState = "stopping"
 	TaskStopped   TaskState = "stopped"
+	// TaskSkipped is terminal: the task's `when` guard was false. It
+	// satisfies dependencies like TaskCompleted.
+	TaskSkipped TaskState = "skipped"
+	// TaskCancelled is a task interrupted by Runner.Cancel. Its partial
+	// summary is kept; like a stopped task, it can be resumed.
+	TaskCancelled TaskState = "cancelled"
 )
 
 // MissionState represents the lifecycle state of a mission.
@@ -28,25 +34,44 @@ const (
 	MissionFailed    MissionState = "failed"
 	MissionStopping  MissionState = "stopping"
 	MissionStopped   MissionState = "stopped"
+	// MissionBudgetExceeded is a failure caused by a budget breach. Unlike
+	// MissionFailed it is not terminal: WithResume continues it once the
+	// budget is raised.
+	MissionBudgetExceeded MissionState = "budget_exceeded"
+	// MissionPausing is written to the store by `squadron pause` to ask the
+	// process running the mission to pause it.
+	MissionPausing MissionState = "pausing"
+	// MissionPaused is a mission that was paused on request (Runner.Pause or
+	// SIGTERM). It is not terminal: WithResume continues it.
+	MissionPaused MissionState = "paused"
+	// MissionCancelled is a mission ended by Runner.Cancel. In-flight tasks
+	// are recorded as cancelled with partial summaries. WithResume can
+	// still continue it.
+	MissionCancelled MissionState = "cancelled"
 )
 
 // validTaskTransitions defines allowed state transitions.
 var validTaskTransitions = map[TaskState][]TaskState{
-	TaskPending:  {TaskReady},
-	TaskReady:    {TaskRunning},
-	TaskRunning:  {TaskCompleted, TaskFailed, TaskStopping},
-	TaskStopping: {TaskStopped},
-	TaskStopped:  {TaskReady},   // resume
-	TaskFailed:   {TaskReady},   // retry
-	// TaskCompleted is terminal
+	TaskPending:   {TaskReady},
+	TaskReady:     {TaskRunning},
+	TaskRunning:   {TaskCompleted, TaskFailed, TaskStopping},
+	TaskStopping:  {TaskStopped},
+	TaskStopped:   {TaskReady}, // resume
+	TaskFailed:    {TaskReady}, // retry
+	TaskCancelled: {TaskReady}, // resume
+	// TaskCompleted and TaskSkipped are terminal
 }
 
 var validMissionTransitions = map[MissionState][]MissionState{
-	MissionPending:  {MissionRunning},
-	MissionRunning:  {MissionCompleted, MissionFailed, MissionStopping},
-	MissionStopping: {MissionStopped},
-	MissionStopped:  {MissionRunning}, // resume
-	// MissionCompleted, MissionFailed are terminal
+	MissionPending:   {MissionRunning},
+	MissionRunning:   {MissionCompleted, MissionFailed, MissionBudgetExceeded, MissionStopping, MissionPausing, MissionPaused, MissionCancelled},
+	MissionStopping:  {MissionStopped},
+	MissionStopped:   {MissionRunning}, // resume
+	MissionPausing:   {MissionPaused},
+	MissionPaused:    {MissionRunning}, // resume
+	MissionCancelled: {MissionRunning}, // resume
+	MissionBudgetExceeded: {MissionRunning}, // resume with a raised budget
+	// MissionCompleted and MissionFailed are terminal
 }
 
 // TaskStateStore persists task/mission state transitions.
@@ -67,8 +92,8 @@ type StateTransitionCallback func(taskName string, from, to TaskState)
 // All state reads and mutations go through this manager.
 type TaskStateManager struct {
 	mu           sync.RWMutex
-	tasks        map[string]TaskState  // taskName → current state
-	taskIDs      map[string]string     // taskName → DB task ID
+	tasks        map[string]TaskState // taskName → current state
+	taskIDs      map[string]string    // taskName → DB task ID
 	missionID    string
 	missionState MissionState
 	store        TaskStateStore
@@ -129,19 +154,21 @@ func (m *TaskStateManager) GetTaskState(taskName string) (TaskState, bool) {
 	return s, ok
 }
 
-// IsCompleted returns true if the task has reached TaskCompleted.
+// IsCompleted returns true if the task has reached TaskCompleted or was
+// skipped by its `when` guard.
 func (m *TaskStateManager) IsCompleted(taskName string) bool {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
-	return m.tasks[taskName] == TaskCompleted
+	s := m.tasks[taskName]
+	return s == TaskCompleted || s == TaskSkipped
 }
 
-// IsTerminal returns true if the task is in a terminal state (completed or failed).
+// IsTerminal returns true if the task is in a terminal state (completed, skipped, or failed).
 func (m *TaskStateManager) IsTerminal(taskName string) bool {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 	s := m.tasks[taskName]
-	return s == TaskCompleted || s == TaskFailed
+	return s == TaskCompleted || s == TaskSkipped || s == TaskFailed
 }
 
 // IsInFlight returns true if the task is currently running or stopping.
@@ -152,12 +179,13 @@ func (m *TaskStateManager) IsInFlight(taskName string) bool {
 	return s == TaskRunning || s == TaskStopping
 }
 
-// AllCompleted returns true if every registered task is in TaskCompleted state.
+// AllCompleted returns true if every registered task is in TaskCompleted
+// (or TaskSkipped) state.
 func (m *TaskStateManager) AllCompleted() bool {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 	for _, s := range m.tasks {
-		if s != TaskCompleted {
+		if s != TaskCompleted && s != TaskSkipped {
 			return false
 		}
 	}
@@ -176,6 +204,19 @@ func (m *TaskStateManager) AnyInFlight() bool {
 	return false
 }
 
+// InFlightCount returns how many tasks are running or stopping.
+func (m *TaskStateManager) InFlightCount() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	n := 0
+	for _, s := range m.tasks {
+		if s == TaskRunning || s == TaskStopping {
+			n++
+		}
+	}
+	return n
+}
+
 // TransitionTask moves a task to a new state.
 // Validates the transition, persists to the store (write-ahead), then updates in-memory state.
 func (m *TaskStateManager) TransitionTask(taskName string, to TaskState, outputJSON, errMsg *string) error {
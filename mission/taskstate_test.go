@@ -97,6 +97,12 @@ var _ = Describe("TaskStateManager", func() {
 			Expect(mgr.TransitionMission(MissionRunning)).To(Succeed())
 		})
 
+		It("allows running → budget_exceeded → running (resume)", func() {
+			Expect(mgr.TransitionMission(MissionRunning)).To(Succeed())
+			Expect(mgr.TransitionMission(MissionBudgetExceeded)).To(Succeed())
+			Expect(mgr.TransitionMission(MissionRunning)).To(Succeed())
+		})
+
 		It("rejects invalid transitions", func() {
 			err := mgr.TransitionMission(MissionCompleted)
 			Expect(err).To(HaveOccurred())
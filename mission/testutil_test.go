@@ -33,6 +33,11 @@ type mockResponse struct {
 	// Match optionally filters which request this response should be used for.
 	// When nil the response matches any request.
 	Match func(*llm.ChatRequest) bool
+	// Hang makes the call block until the request context is canceled, then
+	// fail with the context error — simulates a stuck provider.
+	Hang bool
+	// Err makes ChatStream fail with this error — simulates a provider error.
+	Err error
 }
 
 // mockProvider implements llm.Provider with scripted, queue-based responses.
@@ -80,8 +85,12 @@ func (p *mockProvider) pickResponse(req *llm.ChatRequest) mockResponse {
 	return mockToolCall("task_complete", json.RawMessage(`{}`))
 }
 
-func (p *mockProvider) Chat(_ context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+func (p *mockProvider) Chat(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
 	r := p.pickResponse(req)
+	if r.Hang {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
 	return &llm.ChatResponse{
 		ID:            "mock-resp",
 		Content:       r.Content,
@@ -91,8 +100,15 @@ func (p *mockProvider) Chat(_ context.Context, req *llm.ChatRequest) (*llm.ChatR
 	}, nil
 }
 
-func (p *mockProvider) ChatStream(_ context.Context, req *llm.ChatRequest) (<-chan llm.StreamChunk, error) {
+func (p *mockProvider) ChatStream(ctx context.Context, req *llm.ChatRequest) (<-chan llm.StreamChunk, error) {
 	r := p.pickResponse(req)
+	if r.Hang {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	if r.Err != nil {
+		return nil, r.Err
+	}
 	ch := make(chan llm.StreamChunk, 2)
 	go func() {
 		defer close(ch)
@@ -256,6 +272,35 @@ func (s *mockMissionStreamer) MissionIssue(data streamers.MissionIssueData) {
 		"message":  data.Message,
 	})
 }
+func (s *mockMissionStreamer) TaskApprovalRequested(data streamers.TaskApprovalRequestedData) {
+	s.record("task_approval_requested", map[string]string{"task": data.TaskName, "summary": data.Summary})
+}
+func (s *mockMissionStreamer) TaskApprovalResolved(data streamers.TaskApprovalResolvedData) {
+	s.record("task_approval_resolved", map[string]string{
+		"task":     data.TaskName,
+		"approved": fmt.Sprintf("%t", data.Approved),
+		"edited":   fmt.Sprintf("%t", data.Edited),
+	})
+}
+func (s *mockMissionStreamer) TaskSkipped(data streamers.TaskSkippedData) {
+	s.record("task_skipped", map[string]string{"task": data.TaskName, "when": data.When})
+}
+func (s *mockMissionStreamer) IterationProgress(data streamers.IterationProgressData) {
+	s.record("iteration_progress", map[string]string{
+		"task":      data.TaskName,
+		"total":     fmt.Sprintf("%d", data.Total),
+		"completed": fmt.Sprintf("%d", data.Completed),
+		"failed":    fmt.Sprintf("%d", data.Failed),
+		"in_flight": fmt.Sprintf("%d", data.InFlight),
+		"eta_ms":    fmt.Sprintf("%d", data.EtaMs),
+	})
+}
+func (s *mockMissionStreamer) TaskCancelled(data streamers.TaskCancelledData) {
+	s.record("task_cancelled", map[string]string{"task": data.TaskName, "reason": data.Reason, "summary": data.Summary})
+}
+func (s *mockMissionStreamer) MissionCancelled(data streamers.MissionCancelledData) {
+	s.record("mission_cancelled", map[string]string{"mission": data.MissionName, "reason": data.Reason})
+}
 func (s *mockMissionStreamer) AgentStarted(taskName, agentName, instruction string) {
 	s.record("agent_started", map[string]string{"task": taskName, "agent": agentName})
 }
@@ -271,6 +316,25 @@ func (s *mockMissionStreamer) RouteChosen(routerTask, targetTask, condition stri
 	})
 }
 
+// ---------------------------------------------------------------------------
+// Fake Approver
+// ---------------------------------------------------------------------------
+
+// fakeApprover answers every approval request with a fixed decision built
+// from the draft.
+type fakeApprover struct {
+	mu       sync.Mutex
+	requests []ApprovalRequest
+	decide   func(ApprovalRequest) ApprovalDecision
+}
+
+func (a *fakeApprover) RequestApproval(_ context.Context, req ApprovalRequest) (ApprovalDecision, error) {
+	a.mu.Lock()
+	a.requests = append(a.requests, req)
+	a.mu.Unlock()
+	return a.decide(req), nil
+}
+
 // ---------------------------------------------------------------------------
 // Mock Chat Handler (for agent events)
 // ---------------------------------------------------------------------------
@@ -382,6 +446,11 @@ func cmdTaskComplete() mockResponse {
 	return mockToolCall("task_complete", json.RawMessage(`{"summary":"Task completed successfully."}`))
 }
 
+// hangingCall blocks until the request context is canceled.
+func hangingCall() mockResponse {
+	return mockResponse{Hang: true}
+}
+
 func cmdTaskCompleteFail(reason string) mockResponse {
 	input, _ := json.Marshal(map[string]interface{}{"succeed": false, "reason": reason})
 	return mockToolCall("task_complete", input)
@@ -0,0 +1,84 @@
+package mission
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"squadron/failure"
+	"squadron/streamers"
+)
+
+// TimeoutError is returned when a task or a single iteration attempt runs
+// longer than its configured timeout. It is also the cancellation cause of
+// the timed-out context, which is how the runner tells a timeout apart from
+// the mission being stopped.
+type TimeoutError struct {
+	TaskName string
+	Index    *int // iteration index; nil when the whole task timed out
+	Timeout  time.Duration
+}
+
+func (e *TimeoutError) Error() string {
+	if e.Index != nil {
+		return fmt.Sprintf("iteration %d of task '%s' timed out after %s", *e.Index, e.TaskName, e.Timeout)
+	}
+	return fmt.Sprintf("task '%s' timed out after %s", e.TaskName, e.Timeout)
+}
+
+func (e *TimeoutError) FailureCode() failure.Code { return failure.CodeTimeout }
+
+// withTimeout derives a context that is canceled with te as its cause once
+// te.Timeout elapses. A zero timeout returns ctx unchanged.
+func withTimeout(ctx context.Context, te *TimeoutError) (context.Context, context.CancelFunc) {
+	if te.Timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeoutCause(ctx, te.Timeout, te)
+}
+
+// timeoutCause returns the *TimeoutError that ended ctx, or nil when ctx is
+// still live or was canceled for another reason (stop, budget breach).
+func timeoutCause(ctx context.Context) *TimeoutError {
+	if ctx.Err() == nil {
+		return nil
+	}
+	var te *TimeoutError
+	if errors.As(context.Cause(ctx), &te) {
+		return te
+	}
+	return nil
+}
+
+// reportTimeout emits the timeout as a mission issue so handlers can show it
+// distinctly from other failures. retrying is set when an iteration attempt
+// timed out and another attempt will follow.
+func reportTimeout(streamer streamers.MissionHandler, te *TimeoutError, retrying bool) {
+	severity := streamers.IssueFatal
+	if te.Index != nil {
+		severity = streamers.IssueError
+	}
+	details := map[string]any{"timeout": te.Timeout.String()}
+	if te.Index != nil {
+		details["index"] = *te.Index
+	}
+	streamer.MissionIssue(streamers.MissionIssueData{
+		Severity: severity,
+		Category: streamers.IssueCategoryTimeout,
+		Message:  te.Error(),
+		TaskName: te.TaskName,
+		Entity:   "commander",
+		Retrying: retrying,
+		Details:  details,
+	})
+}
+
+// reportIfTimeout reports err as a timeout issue when it is an iteration's
+// own *TimeoutError. A whole-task timeout is reported once by the task.
+func reportIfTimeout(streamer streamers.MissionHandler, err error, retrying bool) {
+	var te *TimeoutError
+	if errors.As(err, &te) && te.Index != nil {
+		reportTimeout(streamer, te, retrying)
+	}
+}
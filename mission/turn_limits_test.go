@@ -0,0 +1,90 @@
+package mission
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"squadron/config"
+	"squadron/failure"
+	"squadron/llm"
+)
+
+var _ = Describe("Commander turn limits", func() {
+	var cfg *config.Config
+
+	BeforeEach(func() {
+		cfg = buildTestConfig(testMission("looping", []config.Task{testTask("work", "Do the work")}), testAgent("worker"))
+	})
+
+	// run runs the mission and returns its error and its only task.
+	run := func(provider *mockProvider) (error, string) {
+		runner, err := NewRunner(cfg, "", "looping", nil, WithProviderFactory(func() llm.Provider { return provider }))
+		Expect(err).NotTo(HaveOccurred())
+		defer runner.CloseStores()
+
+		runErr := runner.Run(context.Background(), newMockMissionStreamer())
+		tasks, err := runner.stores.Missions.GetTasksByMission(runner.MissionID())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tasks).To(HaveLen(1))
+		code := ""
+		if tasks[0].ErrorCode != nil {
+			code = *tasks[0].ErrorCode
+		}
+		return runErr, code
+	}
+
+	lookup := func(query string) mockResponse {
+		return cmdCommanderTool("lookup", map[string]any{"query": query})
+	}
+
+	It("warns the commander on its last turn and fails the task at max_turns", func() {
+		cfg.Missions[0].Tasks[0].MaxTurns = 3
+		provider := newMockProvider(lookup("a"), lookup("b"), lookup("c"), cmdTaskComplete())
+
+		err, code := run(provider)
+		Expect(err).To(MatchError(ContainSubstring("max_turns (3)")))
+		Expect(errors.Is(err, failure.ErrMaxTurns)).To(BeTrue())
+		Expect(code).To(Equal(string(failure.CodeMaxTurns)))
+		Expect(provider.callCount()).To(Equal(3))
+
+		results := toolResults(provider)
+		Expect(results[len(results)-1]).To(ContainSubstring("you have one left"))
+	})
+
+	It("lets a task finish within max_turns", func() {
+		cfg.Missions[0].Tasks[0].MaxTurns = 3
+		provider := newMockProvider(lookup("a"), lookup("b"), cmdTaskComplete())
+
+		err, code := run(provider)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(code).To(BeEmpty())
+	})
+
+	It("intervenes when the commander repeats the same call, then stops it", func() {
+		provider := newMockProvider(lookup("a"), lookup("a"), lookup("a"), lookup("a"), lookup("a"), cmdTaskComplete())
+
+		err, code := run(provider)
+		Expect(err).To(MatchError(ContainSubstring("repeated the same lookup call 5 turns in a row")))
+		Expect(code).To(Equal(string(failure.CodeLoopDetected)))
+		Expect(provider.callCount()).To(Equal(5))
+
+		interventions := 0
+		for _, r := range toolResults(provider) {
+			if strings.Contains(r, "exactly this call") {
+				interventions++
+			}
+		}
+		Expect(interventions).To(BeNumerically(">", 0))
+	})
+
+	It("recovers when the commander changes course after an intervention", func() {
+		provider := newMockProvider(lookup("a"), lookup("a"), lookup("a"), lookup("b"), lookup("a"), lookup("a"), cmdTaskComplete())
+
+		err, _ := run(provider)
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
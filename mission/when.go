@@ -0,0 +1,107 @@
+package mission
+
+import (
+	"encoding/json"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"squadron/config"
+	"squadron/streamers"
+)
+
+// skippedTaskSummary stands in for a skipped task's summary, so downstream
+// commanders know not to look for its output.
+const skippedTaskSummary = "(Skipped — this task's when guard was false, so it did not run and produced no output.)"
+
+// skipIfGuardFalse evaluates the task's `when` guard. When it is false the
+// task is recorded as skipped and true is returned; the caller must not run
+// it. An evaluation error fails the task.
+func (r *Runner) skipIfGuardFalse(task config.Task, missionID, existingTaskID string, streamer streamers.MissionHandler) (bool, error) {
+	if task.WhenExpr == nil {
+		return false, nil
+	}
+
+	run, err := task.EvaluateWhen(r.varsValues, r.inputValues, r.whenQueryValues(task))
+	if err != nil {
+		streamer.TaskFailed(task.Name, err)
+		return false, err
+	}
+	if run {
+		return false, nil
+	}
+
+	taskID := existingTaskID
+	if taskID == "" {
+		objective, _ := task.ResolvedObjective(r.varsValues, r.inputValues)
+		taskConfigJSON, _ := json.Marshal(taskSnapshot(task, objective))
+		taskID, _ = r.stores.Missions.CreateTask(missionID, task.Name, string(taskConfigJSON))
+	}
+	if reg, ok := streamer.(streamers.IDRegistrar); ok {
+		reg.SetTaskID(task.Name, taskID)
+	}
+	if r.stateMgr != nil {
+		r.stateMgr.SetTaskID(task.Name, taskID)
+	}
+	r.stores.Missions.UpdateTaskStatus(taskID, string(TaskSkipped), nil, nil)
+
+	// Downstream commanders see this in place of a dependency summary
+	r.mu.Lock()
+	r.taskSummaries[task.Name] = skippedTaskSummary
+	r.mu.Unlock()
+
+	if sh, ok := streamer.(streamers.TaskSkipHandler); ok {
+		sh.TaskSkipped(streamers.TaskSkippedData{TaskName: task.Name, When: task.RawWhen})
+	}
+	if r.debugLogger != nil {
+		r.debugLogger.LogEvent(EventTaskSkipped, map[string]any{
+			"task": task.Name,
+			"when": task.RawWhen,
+		})
+	}
+	return true, nil
+}
+
+// whenQueryValues builds the query.<task> objects a guard reads: status
+// ("completed" or "skipped"), skipped, and output. Iterated tasks expose
+// output as a list of per-iteration outputs, or as their aggregate when the
+// task has an aggregate block; skipped tasks and tasks without structured
+// output have a null output.
+func (r *Runner) whenQueryValues(task config.Task) map[string]cty.Value {
+	query := make(map[string]cty.Value)
+	for _, name := range task.WhenDependencies() {
+		status := TaskCompleted
+		if r.stateMgr != nil {
+			if s, ok := r.stateMgr.GetTaskState(name); ok && s == TaskSkipped {
+				status = TaskSkipped
+			}
+		}
+
+		output := cty.NullVal(cty.DynamicPseudoType)
+		if status == TaskCompleted && r.knowledgeStore != nil {
+			if to, ok := r.knowledgeStore.GetTaskOutput(name); ok {
+				output = taskOutputToCty(to)
+			}
+		}
+
+		query[name] = cty.ObjectVal(map[string]cty.Value{
+			"status":  cty.StringVal(string(status)),
+			"skipped": cty.BoolVal(status == TaskSkipped),
+			"output":  output,
+		})
+	}
+	return query
+}
+
+func taskOutputToCty(to *TaskOutput) cty.Value {
+	if to.IsIterated && to.Output == nil {
+		items := make([]any, len(to.Iterations))
+		for i, iter := range to.Iterations {
+			items[i] = iter.Output
+		}
+		return config.GoToCtyValue(items)
+	}
+	if to.Output == nil {
+		return cty.NullVal(cty.DynamicPseudoType)
+	}
+	return config.GoToCtyValue(to.Output)
+}
@@ -0,0 +1,95 @@
+package mission
+
+import (
+	"context"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"squadron/config"
+	"squadron/llm"
+)
+
+var _ = Describe("Task when guards", func() {
+	whenExpr := func(src string) hcl.Expression {
+		expr, diags := hclsyntax.ParseExpression([]byte(src), "test.hcl", hcl.InitialPos)
+		Expect(diags.HasErrors()).To(BeFalse())
+		return expr
+	}
+
+	// scan → fix (guarded on scan's count) → report
+	guardedMission := func() *config.Config {
+		scan := testTask("scan", "Scan for findings")
+		scan.Output = &config.OutputSchema{Fields: []config.OutputField{
+			{Name: "count", Type: "integer", Description: "Findings", Required: true},
+		}}
+		fix := testTask("fix", "Fix the findings")
+		fix.DependsOn = []string{"scan"}
+		fix.WhenExpr = whenExpr("query.scan.output.count > 0")
+		fix.RawWhen = "query.scan.output.count > 0"
+		report := testTask("report", "Report")
+		report.DependsOn = []string{"fix"}
+		return buildTestConfig(testMission("guarded", []config.Task{scan, fix, report}), testAgent("worker"))
+	}
+
+	run := func(count int) (*mockMissionStreamer, map[string]string, error) {
+		provider := newMockProvider(
+			cmdSubmitOutput(map[string]interface{}{"count": count}),
+			cmdTaskComplete(),
+			cmdTaskComplete(),
+			cmdTaskComplete(),
+		)
+		runner, err := NewRunner(guardedMission(), "", "guarded", nil, WithProviderFactory(func() llm.Provider { return provider }))
+		Expect(err).NotTo(HaveOccurred())
+		defer runner.CloseStores()
+
+		streamer := newMockMissionStreamer()
+		err = runner.Run(context.Background(), streamer)
+
+		statuses := map[string]string{}
+		tasks, _ := runner.stores.Missions.GetTasksByMission(runner.missionID)
+		for _, t := range tasks {
+			statuses[t.TaskName] = t.Status
+		}
+		return streamer, statuses, err
+	}
+
+	It("skips a task whose guard is false and still runs its dependents", func() {
+		streamer, statuses, err := run(0)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(streamer.hasEvent("mission_completed")).To(BeTrue())
+		Expect(statuses).To(Equal(map[string]string{"scan": "completed", "fix": "skipped", "report": "completed"}))
+
+		for _, e := range streamer.getEvents() {
+			if e.Type == "task_started" {
+				Expect(e.Data["task"]).NotTo(Equal("fix"))
+			}
+		}
+		Expect(streamer.eventCount("task_skipped")).To(Equal(1))
+	})
+
+	It("runs a task whose guard is true", func() {
+		streamer, statuses, err := run(2)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(statuses).To(Equal(map[string]string{"scan": "completed", "fix": "completed", "report": "completed"}))
+		Expect(streamer.hasEvent("task_skipped")).To(BeFalse())
+	})
+
+	It("fails the mission when the guard can't be evaluated", func() {
+		cfg := buildTestConfig(testMission("broken", []config.Task{func() config.Task {
+			t := testTask("t", "Do it")
+			t.WhenExpr = whenExpr(`"yes"`)
+			return t
+		}()}), testAgent("worker"))
+		provider := newMockProvider()
+		runner, err := NewRunner(cfg, "", "broken", nil, WithProviderFactory(func() llm.Provider { return provider }))
+		Expect(err).NotTo(HaveOccurred())
+		defer runner.CloseStores()
+
+		err = runner.Run(context.Background(), newMockMissionStreamer())
+		Expect(err).To(MatchError(ContainSubstring("when: expression must be a bool")))
+		Expect(provider.callCount()).To(Equal(0))
+	})
+})
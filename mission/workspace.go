@@ -0,0 +1,220 @@
+package mission
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"squadron/aitools"
+	"squadron/config"
+	"squadron/internal/paths"
+)
+
+// On-disk layout under SquadronHome:
+//
+//	<squadron_home>/workspaces/<mission_name>/<run_id>/<task>/           — task workspace
+//	<squadron_home>/workspaces/<mission_name>/<run_id>/<task>/<index>/   — iteration workspace
+//	<squadron_home>/workspaces/<mission_name>/archive/<run_id>/*.tar.gz  — archived workspaces
+//
+// Run directories carry the same .squadron-run.json sidecar as scratchpads,
+// so SweepExpiredScratchpads removes workspaces left behind by failed runs.
+// The archive directory has no sidecar and is never swept.
+const (
+	workspaceSubdir  = "workspaces"
+	workspaceArchive = "archive"
+)
+
+// WorkspacesRoot returns `<squadron_home>/workspaces`, the parent of every
+// per-run workspace directory.
+func WorkspacesRoot() (string, error) {
+	home, err := paths.SquadronHome()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, workspaceSubdir), nil
+}
+
+// taskWorkspace is one task's (or iteration's) private directory.
+type taskWorkspace struct {
+	path        string // absolute directory backing the "workspace" slot
+	archivePath string // where on_complete = "archive" writes the tarball
+	onComplete  string
+}
+
+// openWorkspace creates the workspace for a task, or for one of its
+// iterations when iteration is non-nil. Returns nil when the mission has no
+// workspace block. Paths are deterministic, so a resumed task reopens the
+// directory it left behind.
+func (r *Runner) openWorkspace(taskName string, iteration *int) (*taskWorkspace, error) {
+	if r.mission.Workspace == nil {
+		return nil, nil
+	}
+	root, err := WorkspacesRoot()
+	if err != nil {
+		return nil, fmt.Errorf("workspace: resolve path: %w", err)
+	}
+	runDir := filepath.Join(root, r.mission.Name, r.missionID)
+	if err := os.MkdirAll(runDir, 0755); err != nil {
+		return nil, fmt.Errorf("workspace: create directory: %w", err)
+	}
+	if err := writeRunMetadata(runDir, r.mission.Name, r.missionID, config.ScratchpadCleanupDays); err != nil {
+		return nil, fmt.Errorf("workspace: write metadata: %w", err)
+	}
+
+	dirName := workspaceDirName(taskName)
+	path := filepath.Join(runDir, dirName)
+	archiveName := dirName
+	if iteration != nil {
+		path = filepath.Join(path, strconv.Itoa(*iteration))
+		archiveName = fmt.Sprintf("%s-%d", dirName, *iteration)
+	}
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return nil, fmt.Errorf("workspace: create directory: %w", err)
+	}
+	return &taskWorkspace{
+		path:        path,
+		archivePath: filepath.Join(root, r.mission.Name, workspaceArchive, r.missionID, archiveName+".tar.gz"),
+		onComplete:  r.mission.Workspace.GetOnComplete(),
+	}, nil
+}
+
+// workspaceDirName turns a task name into a directory name. for_each
+// instances (task["key"]) become task_key.
+func workspaceDirName(taskName string) string {
+	return strings.Map(func(c rune) rune {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '_', c == '-', c == '.':
+			return c
+		case c == '[':
+			return '_'
+		}
+		return -1
+	}, taskName)
+}
+
+// storeFor returns the memory store a task's commander and agents see: the
+// mission's slots plus ws as "workspace".
+func (r *Runner) storeFor(ws *taskWorkspace) aitools.MemoryStore {
+	if ws == nil {
+		return r.memoryStore
+	}
+	return &workspaceMemoryStore{base: r.memoryStore, path: ws.path}
+}
+
+// finishWorkspace applies on_complete once the task or iteration has
+// succeeded. Failures are logged, not returned — the task's result stands.
+func (r *Runner) finishWorkspace(ws *taskWorkspace) {
+	if ws == nil {
+		return
+	}
+	switch ws.onComplete {
+	case config.WorkspaceOnCompleteKeep:
+		return
+	case config.WorkspaceOnCompleteArchive:
+		if err := archiveDir(ws.path, ws.archivePath); err != nil {
+			log.Printf("Workspace %s: archive failed, keeping it: %v", ws.path, err)
+			return
+		}
+	}
+	if err := os.RemoveAll(ws.path); err != nil {
+		log.Printf("Workspace %s: cleanup failed: %v", ws.path, err)
+	}
+}
+
+// archiveDir writes dir's contents to a gzipped tarball at dest.
+func archiveDir(dir, dest string) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+	f, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+
+	walkErr := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		if !info.Mode().IsRegular() && !info.IsDir() {
+			return nil // skip symlinks and devices
+		}
+		hdr, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(rel)
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		src, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		_, err = io.Copy(tw, src)
+		return err
+	})
+
+	for _, closeErr := range []error{tw.Close(), gz.Close(), f.Close()} {
+		if walkErr == nil {
+			walkErr = closeErr
+		}
+	}
+	if walkErr != nil {
+		os.Remove(dest)
+	}
+	return walkErr
+}
+
+// workspaceMemoryStore layers a task's "workspace" slot over the mission's
+// memory store (which may be nil when the mission declares no other slots).
+type workspaceMemoryStore struct {
+	base aitools.MemoryStore
+	path string
+}
+
+func (s *workspaceMemoryStore) ResolvePath(slotName string, relPath string) (string, error) {
+	if slotName != config.WorkspaceSlotName {
+		if s.base == nil {
+			return "", fmt.Errorf("slot %q not found. Available: [%s]", slotName, config.WorkspaceSlotName)
+		}
+		return s.base.ResolvePath(slotName, relPath)
+	}
+
+	cleaned := filepath.Clean(relPath)
+	if cleaned == "." {
+		return s.path, nil
+	}
+	fullPath := filepath.Join(s.path, cleaned)
+	if !strings.HasPrefix(fullPath, s.path+string(filepath.Separator)) {
+		return "", fmt.Errorf("path escapes slot root")
+	}
+	return fullPath, nil
+}
+
+func (s *workspaceMemoryStore) MemoryInfos() []aitools.MemoryInfo {
+	var infos []aitools.MemoryInfo
+	if s.base != nil {
+		infos = append(infos, s.base.MemoryInfos()...)
+	}
+	infos = append(infos, aitools.MemoryInfo{Name: config.WorkspaceSlotName})
+	// Same stable ordering as missionMemoryStore, for prompt caching
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos
+}
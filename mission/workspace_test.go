@@ -0,0 +1,117 @@
+package mission
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"squadron/config"
+	"squadron/internal/paths"
+	"squadron/llm"
+)
+
+var _ = Describe("Task workspaces", func() {
+	var home string
+
+	BeforeEach(func() {
+		home = GinkgoT().TempDir()
+		paths.ResetHome()
+		Expect(paths.SetHome(home)).To(Succeed())
+		DeferCleanup(paths.ResetHome)
+	})
+
+	writeNotes := func() mockResponse {
+		return mockToolCall("file_create", json.RawMessage(`{"slot": "workspace", "path": "notes/draft.md", "content": "draft"}`))
+	}
+
+	run := func(onComplete string, responses ...mockResponse) (*Runner, error) {
+		m := testMission("builder", []config.Task{testTask("build", "Build it")})
+		m.Workspace = &config.MissionWorkspace{OnComplete: onComplete}
+		cfg := buildTestConfig(m, testAgent("worker"))
+		provider := newMockProvider(responses...)
+		runner, err := NewRunner(cfg, "", "builder", nil, WithProviderFactory(func() llm.Provider { return provider }))
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(runner.CloseStores)
+		return runner, runner.Run(context.Background(), newMockMissionStreamer())
+	}
+
+	runDir := func(r *Runner) string {
+		return filepath.Join(home, "workspaces", "builder", r.MissionID())
+	}
+
+	It("deletes the workspace once the task succeeds", func() {
+		runner, err := run("", writeNotes(), cmdTaskComplete())
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(filepath.Join(runDir(runner), "build")).NotTo(BeADirectory())
+		Expect(filepath.Join(runDir(runner), runMetadataFile)).To(BeAnExistingFile())
+	})
+
+	It("keeps the workspace with on_complete = keep", func() {
+		runner, err := run(config.WorkspaceOnCompleteKeep, writeNotes(), cmdTaskComplete())
+		Expect(err).NotTo(HaveOccurred())
+
+		b, err := os.ReadFile(filepath.Join(runDir(runner), "build", "notes", "draft.md"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(b)).To(Equal("draft"))
+	})
+
+	It("archives the workspace with on_complete = archive", func() {
+		runner, err := run(config.WorkspaceOnCompleteArchive, writeNotes(), cmdTaskComplete())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(filepath.Join(runDir(runner), "build")).NotTo(BeADirectory())
+
+		f, err := os.Open(filepath.Join(home, "workspaces", "builder", "archive", runner.MissionID(), "build.tar.gz"))
+		Expect(err).NotTo(HaveOccurred())
+		defer f.Close()
+		gz, err := gzip.NewReader(f)
+		Expect(err).NotTo(HaveOccurred())
+		contents := map[string]string{}
+		tr := tar.NewReader(gz)
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			Expect(err).NotTo(HaveOccurred())
+			b, err := io.ReadAll(tr)
+			Expect(err).NotTo(HaveOccurred())
+			contents[hdr.Name] = string(b)
+		}
+		Expect(contents).To(HaveKeyWithValue("notes/draft.md", "draft"))
+	})
+
+	It("leaves the workspace in place when the task fails", func() {
+		runner, err := run("", writeNotes(), cmdTaskCompleteFail("could not build"))
+		Expect(err).To(HaveOccurred())
+
+		Expect(filepath.Join(runDir(runner), "build", "notes", "draft.md")).To(BeAnExistingFile())
+	})
+})
+
+var _ = Describe("workspaceMemoryStore", func() {
+	It("resolves the workspace slot and delegates the rest", func() {
+		dir := GinkgoT().TempDir()
+		s := &workspaceMemoryStore{path: dir}
+
+		Expect(s.ResolvePath(config.WorkspaceSlotName, "a/b.txt")).To(Equal(filepath.Join(dir, "a", "b.txt")))
+		_, err := s.ResolvePath(config.WorkspaceSlotName, "../escape")
+		Expect(err).To(MatchError(ContainSubstring("escapes")))
+		_, err = s.ResolvePath(config.ScratchpadSlotName, ".")
+		Expect(err).To(MatchError(ContainSubstring("not found")))
+
+		Expect(s.MemoryInfos()).To(HaveLen(1))
+		Expect(s.MemoryInfos()[0].Name).To(Equal(config.WorkspaceSlotName))
+	})
+
+	It("names for_each instance directories after the key", func() {
+		Expect(workspaceDirName(`audit["us-east"]`)).To(Equal("audit_us-east"))
+	})
+})
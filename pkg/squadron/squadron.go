@@ -0,0 +1,318 @@
+// Package squadron embeds Squadron in other Go programs. It loads a config,
+// runs its missions, and hands back their events and results as Go values,
+// so a service can run missions without shelling out to the CLI.
+//
+//	cfg, err := squadron.Load("./config")
+//	if err != nil {
+//		return err
+//	}
+//	defer cfg.Close()
+//
+//	res, err := cfg.Run(ctx, "research", map[string]any{"topic": "batteries"}, func(e squadron.Event) {
+//		log.Printf("%s %v", e.Type, e.Data)
+//	})
+//
+// The exported API of this package follows semantic versioning: within a
+// major version nothing is removed and behavior only changes in
+// backward-compatible ways. The other packages of the module are
+// implementation details and may change in any release.
+package squadron
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"squadron/config"
+	squadronmcp "squadron/mcp"
+	"squadron/mission"
+	"squadron/plugin"
+	"squadron/store"
+	"squadron/streamers"
+	"squadron/streamers/ndjson"
+)
+
+// ErrNotInitialized is returned by Load before `squadron init` has set up
+// the vault on this machine.
+var ErrNotInitialized = errors.New("squadron not initialized: run 'squadron init'")
+
+// Errors returned by Run when a mission stops before finishing; test for
+// them with errors.Is. Both runs can be continued with WithResume.
+var (
+	ErrPaused    = mission.ErrMissionPaused
+	ErrCancelled = mission.ErrMissionCancelled
+)
+
+// Config is a loaded and validated squadron config.
+type Config struct {
+	cfg  *config.Config
+	path string
+}
+
+// Load loads and validates the config at path, a directory of .hcl files or
+// a single file, the same way `squadron mission -c path` does.
+func Load(path string) (*Config, error) {
+	if !config.IsVaultInitialized() {
+		return nil, ErrNotInitialized
+	}
+	cfg, err := config.LoadAndValidate(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Config{cfg: cfg, path: path}, nil
+}
+
+// Close stops the plugins and MCP servers started for the config. Call it
+// once the program is done running missions.
+func (c *Config) Close() {
+	squadronmcp.CloseAll()
+	plugin.CloseAll()
+}
+
+// Mission describes a mission of the config.
+type Mission struct {
+	Name      string
+	Directive string
+	Inputs    []Input
+	Tasks     []string
+}
+
+// Input describes a mission input. Required inputs have no default.
+// Protected inputs take their value from the config and are left out.
+type Input struct {
+	Name        string
+	Type        string
+	Description string
+	Required    bool
+}
+
+// Missions returns the missions of the config in declaration order.
+func (c *Config) Missions() []Mission {
+	missions := make([]Mission, 0, len(c.cfg.Missions))
+	for _, m := range c.cfg.Missions {
+		info := Mission{Name: m.Name, Directive: m.Directive}
+		for _, in := range m.Inputs {
+			if in.Protected {
+				continue
+			}
+			info.Inputs = append(info.Inputs, Input{
+				Name:        in.Name,
+				Type:        in.Type,
+				Description: in.Description,
+				Required:    in.Default == nil,
+			})
+		}
+		for _, t := range m.Tasks {
+			info.Tasks = append(info.Tasks, t.Name)
+		}
+		missions = append(missions, info)
+	}
+	return missions
+}
+
+// Event is a mission event, the same one `squadron mission --events` writes
+// as a JSON line. Data holds the event's payload type from
+// github.com/mlund01/squadron-wire/protocol, e.g. protocol.TaskCompletedData
+// for "task_completed".
+type Event struct {
+	Type      string
+	MissionID string
+	Timestamp time.Time
+	Data      any
+}
+
+// Handler receives the events of a run. Calls are serialized, including
+// those from tasks running in parallel, and block the run until they return.
+type Handler func(Event)
+
+// Approval types for tasks with require_approval; see WithApprover.
+type (
+	Approver         = mission.Approver
+	ApprovalRequest  = mission.ApprovalRequest
+	ApprovalDecision = mission.ApprovalDecision
+)
+
+// Option configures a run.
+type Option func(*runOptions)
+
+type runOptions struct {
+	runner []mission.RunnerOption
+}
+
+// WithApprover sets who reviews the results of tasks with require_approval.
+// Missions with such tasks don't start without one.
+func WithApprover(a Approver) Option {
+	return func(o *runOptions) { o.runner = append(o.runner, mission.WithApprover(a)) }
+}
+
+// WithResume continues the paused, cancelled or failed run with this
+// mission ID instead of starting a new one.
+func WithResume(missionID string) Option {
+	return func(o *runOptions) { o.runner = append(o.runner, mission.WithResume(missionID)) }
+}
+
+// Runner runs one mission of a config.
+type Runner struct {
+	runner *mission.Runner
+}
+
+// NewRunner prepares a run of the named mission. Input values that aren't
+// strings are passed as JSON, as with `squadron mission --input`.
+func (c *Config) NewRunner(missionName string, inputs map[string]any, opts ...Option) (*Runner, error) {
+	values, err := inputValues(inputs)
+	if err != nil {
+		return nil, err
+	}
+	var o runOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	r, err := mission.NewRunner(c.cfg, c.path, missionName, values, o.runner...)
+	if err != nil {
+		return nil, err
+	}
+	return &Runner{runner: r}, nil
+}
+
+// Run is a shorthand for NewRunner followed by Runner.Run.
+func (c *Config) Run(ctx context.Context, missionName string, inputs map[string]any, handler Handler, opts ...Option) (*Result, error) {
+	r, err := c.NewRunner(missionName, inputs, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return r.Run(ctx, handler)
+}
+
+// Run runs the mission to the end, passing its events to handler (which may
+// be nil), and returns its result. A Runner runs once.
+//
+// The result is returned whenever the run started, also alongside the error
+// of a mission that failed, was paused (ErrPaused) or was cancelled
+// (ErrCancelled).
+func (r *Runner) Run(ctx context.Context, handler Handler) (*Result, error) {
+	defer r.runner.CloseStores()
+	events := ndjson.NewMissionHandlerFunc(func(line ndjson.Line) {
+		if handler != nil {
+			handler(Event{Type: string(line.Type), MissionID: line.MissionID, Timestamp: line.Timestamp, Data: line.Data})
+		}
+	})
+	streamer := streamers.NewStoringMissionHandler(events, r.runner.EventStore(), r.runner.CostStore())
+
+	runErr := r.runner.Run(ctx, streamer)
+	if r.runner.MissionID() == "" {
+		return nil, runErr
+	}
+	res, err := collectResult(r.runner.MissionStore(), r.runner.MissionID())
+	if err != nil {
+		return nil, errors.Join(runErr, err)
+	}
+	return res, runErr
+}
+
+// MissionID returns the ID of the run, once Run has started it.
+func (r *Runner) MissionID() string {
+	return r.runner.MissionID()
+}
+
+// Pause stops the run so it can be resumed later. In-flight calls are
+// interrupted and their sessions kept; Run then returns ErrPaused. Safe to
+// call from any goroutine.
+func (r *Runner) Pause() {
+	r.runner.Pause()
+}
+
+// Cancel ends the run, giving running commanders a moment to summarize
+// their work; Run then returns an error wrapping ErrCancelled. Safe to call
+// from any goroutine.
+func (r *Runner) Cancel(reason string) {
+	r.runner.Cancel(reason)
+}
+
+// Result is the outcome of a run.
+type Result struct {
+	MissionID string
+	Mission   string
+	Status    string // completed, failed, paused or cancelled
+	Tasks     []TaskResult
+}
+
+// TaskResult is the outcome of one task of a run.
+type TaskResult struct {
+	Name    string
+	Status  string
+	Summary string
+	// Output is the task's structured output decoded from JSON, or nil
+	// when the task has no output schema.
+	Output any
+	Error  string
+	// ErrorCode classifies the failure of a failed task, e.g. "timeout".
+	ErrorCode string
+	// Confidence is the score the commander gave a task with verify = true.
+	Confidence *float64
+}
+
+// Task returns the result of the named task, or nil if it didn't run.
+func (r *Result) Task(name string) *TaskResult {
+	for i := range r.Tasks {
+		if r.Tasks[i].Name == name {
+			return &r.Tasks[i]
+		}
+	}
+	return nil
+}
+
+func collectResult(missions store.MissionStore, missionID string) (*Result, error) {
+	record, err := missions.GetMission(missionID)
+	if err != nil {
+		return nil, fmt.Errorf("get mission: %w", err)
+	}
+	tasks, err := missions.GetTasksByMission(missionID)
+	if err != nil {
+		return nil, fmt.Errorf("get tasks: %w", err)
+	}
+
+	res := &Result{
+		MissionID: record.ID,
+		Mission:   record.MissionName,
+		Status:    record.Status,
+		Tasks:     make([]TaskResult, 0, len(tasks)),
+	}
+	for _, t := range tasks {
+		tr := TaskResult{Name: t.TaskName, Status: t.Status, Confidence: t.Confidence}
+		if t.Summary != nil {
+			tr.Summary = *t.Summary
+		}
+		if t.Error != nil {
+			tr.Error = *t.Error
+		}
+		if t.ErrorCode != nil {
+			tr.ErrorCode = *t.ErrorCode
+		}
+		if t.OutputJSON != nil && *t.OutputJSON != "" {
+			var output any
+			if json.Unmarshal([]byte(*t.OutputJSON), &output) == nil {
+				tr.Output = output
+			}
+		}
+		res.Tasks = append(res.Tasks, tr)
+	}
+	return res, nil
+}
+
+func inputValues(in map[string]any) (map[string]string, error) {
+	inputs := make(map[string]string, len(in))
+	for k, v := range in {
+		if s, ok := v.(string); ok {
+			inputs[k] = s
+			continue
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("input '%s': %w", k, err)
+		}
+		inputs[k] = string(b)
+	}
+	return inputs, nil
+}
@@ -0,0 +1,122 @@
+package squadron
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+
+	"squadron/config"
+	"squadron/llm"
+	"squadron/mission"
+)
+
+// fakeProvider completes every task on the first commander turn.
+type fakeProvider struct{}
+
+func (p *fakeProvider) Chat(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+	return nil, nil
+}
+
+func (p *fakeProvider) ChatStream(ctx context.Context, req *llm.ChatRequest) (<-chan llm.StreamChunk, error) {
+	ch := make(chan llm.StreamChunk, 1)
+	ch <- llm.StreamChunk{
+		Done:  true,
+		Usage: &llm.Usage{InputTokens: 10, OutputTokens: 5},
+		ContentBlocks: []llm.ContentBlock{{
+			Type:    llm.ContentTypeToolUse,
+			ToolUse: &llm.ToolUseBlock{ID: "tc_1", Name: "task_complete", Input: json.RawMessage(`{"summary":"done"}`)},
+		}},
+	}
+	close(ch)
+	return ch, nil
+}
+
+func withFakeProvider() Option {
+	return func(o *runOptions) {
+		o.runner = append(o.runner, mission.WithProviderFactory(func() llm.Provider { return &fakeProvider{} }))
+	}
+}
+
+func testConfig(t *testing.T) *Config {
+	t.Helper()
+	promptCaching := false
+	topicDefault := cty.StringVal("batteries")
+	return &Config{cfg: &config.Config{
+		Models: []config.Model{{Name: "test", Provider: config.ProviderAnthropic, APIKey: "test-key", PromptCaching: &promptCaching}},
+		Agents: []config.Agent{{Name: "worker", Model: "claude_sonnet_4", Personality: "Test agent"}},
+		Missions: []config.Mission{{
+			Name:        "hello",
+			Directive:   "Greet the world",
+			MaxParallel: 1,
+			Commander:   &config.MissionCommander{Model: "claude_sonnet_4"},
+			Agents:      []string{"worker"},
+			Inputs: []config.MissionInput{
+				{Name: "name", Type: config.InputTypeString},
+				{Name: "topic", Type: config.InputTypeString, Default: &topicDefault},
+				{Name: "api_key", Type: config.InputTypeString, Protected: true},
+			},
+			Tasks: []config.Task{{
+				Name:          "greet",
+				ObjectiveExpr: hcl.StaticExpr(cty.StringVal("Say hello"), hcl.Range{}),
+				RawObjective:  "Say hello",
+			}},
+		}},
+		Storage: &config.StorageConfig{Backend: "sqlite", Path: filepath.Join(t.TempDir(), "store.db")},
+	}}
+}
+
+func TestMissions(t *testing.T) {
+	missions := testConfig(t).Missions()
+	if len(missions) != 1 {
+		t.Fatalf("expected 1 mission, got %d", len(missions))
+	}
+	m := missions[0]
+	if m.Name != "hello" || m.Directive != "Greet the world" || len(m.Tasks) != 1 || m.Tasks[0] != "greet" {
+		t.Fatalf("unexpected mission: %+v", m)
+	}
+	want := []Input{
+		{Name: "name", Type: config.InputTypeString, Required: true},
+		{Name: "topic", Type: config.InputTypeString},
+	}
+	if len(m.Inputs) != len(want) || m.Inputs[0] != want[0] || m.Inputs[1] != want[1] {
+		t.Fatalf("inputs = %+v, want %+v", m.Inputs, want)
+	}
+}
+
+func TestRun(t *testing.T) {
+	var events []string
+	res, err := testConfig(t).Run(context.Background(), "hello", map[string]any{"name": "world"}, func(e Event) {
+		if e.MissionID == "" && e.Type != "mission_started" {
+			t.Errorf("event %s has no mission ID", e.Type)
+		}
+		events = append(events, e.Type)
+	}, withFakeProvider())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.Mission != "hello" || res.Status != "completed" || res.MissionID == "" {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	greet := res.Task("greet")
+	if greet == nil || greet.Status != "completed" || greet.Summary != "done" {
+		t.Fatalf("unexpected task result: %+v", greet)
+	}
+	if len(events) == 0 || events[0] != "mission_started" || events[len(events)-1] != "mission_completed" {
+		t.Fatalf("unexpected events: %v", events)
+	}
+}
+
+func TestNewRunnerErrors(t *testing.T) {
+	cfg := testConfig(t)
+	if _, err := cfg.NewRunner("missing", nil); err == nil {
+		t.Fatal("expected an error for an unknown mission")
+	}
+	if _, err := cfg.NewRunner("hello", map[string]any{"name": func() {}}); err == nil {
+		t.Fatal("expected an error for an input that isn't JSON")
+	}
+}
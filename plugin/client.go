@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"sync"
+	"time"
 
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-plugin"
@@ -26,6 +27,30 @@ type PluginClient struct {
 	client   *plugin.Client
 	provider ToolProvider
 	name     string
+	tree     *processTree  // the plugin and the processes it spawned
+	timeout  time.Duration // bounds startup and Configure
+
+	toolTimeoutMu sync.RWMutex
+	toolTimeout   time.Duration            // bounds each tool call (0 = none)
+	toolTimeouts  map[string]time.Duration // per-tool overrides of toolTimeout
+}
+
+// DefaultTimeout bounds how long a plugin may take to start and to finish
+// configuring when its plugin block doesn't set plugin_timeout.
+const DefaultTimeout = 5 * time.Minute
+
+// TimeoutError is returned when a plugin doesn't start or finish configuring
+// within its timeout. The plugin has been killed by then.
+type TimeoutError struct {
+	Plugin  string
+	Stage   string // "start" or "finish configuring"
+	Timeout time.Duration
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("plugin '%s' did not %s within %s (plugin_timeout). Plugins that install dependencies on first use, "+
+		"such as browser downloads, can take longer than that on a fresh machine or CI runner: install them ahead of time "+
+		"or raise plugin_timeout in the plugin block", e.Plugin, e.Stage, e.Timeout)
 }
 
 // GetPluginsDir returns the base directory for plugins
@@ -65,6 +90,12 @@ func GetPluginDir(name, version string) (string, error) {
 // the existing instance is returned. This allows browser sessions and
 // other plugin state to persist across mission tasks.
 func LoadPlugin(name, version, source string) (*PluginClient, error) {
+	return LoadPluginWithTimeout(name, version, source, DefaultTimeout)
+}
+
+// LoadPluginWithTimeout is LoadPlugin with the time the plugin may take to
+// start, and later to finish Configure, bounded by timeout.
+func LoadPluginWithTimeout(name, version, source string, timeout time.Duration) (*PluginClient, error) {
 	key := name + ":" + version
 
 	// Check if plugin is already loaded and still alive
@@ -77,6 +108,7 @@ func LoadPlugin(name, version, source string) (*PluginClient, error) {
 		// Plugin process died — will re-launch below
 		globalRegistryLock.RUnlock()
 		globalRegistryLock.Lock()
+		existing.tree.kill() // anything it spawned that outlived it
 		delete(globalRegistry, key)
 		globalRegistryLock.Unlock()
 	} else {
@@ -112,6 +144,7 @@ func LoadPlugin(name, version, source string) (*PluginClient, error) {
 	if err != nil {
 		return nil, err
 	}
+	prepareCommand(cmd)
 
 	logger := hclog.New(&hclog.LoggerOptions{
 		Name:   "plugin",
@@ -125,11 +158,23 @@ func LoadPlugin(name, version, source string) (*PluginClient, error) {
 		Cmd:              cmd,
 		Logger:           logger,
 		AllowedProtocols: []plugin.Protocol{plugin.ProtocolGRPC},
+		GRPCDialOptions:  dialOptions,
+		StartTimeout:     timeout,
 	})
 
+	started := time.Now()
 	provider, err := DispenseToolProvider(client)
 	if err != nil {
 		client.Kill()
+		if cmd.Process != nil {
+			// Kill whatever the plugin spawned before giving up on it.
+			if tree, treeErr := newProcessTree(cmd.Process.Pid); treeErr == nil {
+				tree.kill()
+			}
+		}
+		if timeout > 0 && time.Since(started) >= timeout {
+			return nil, &TimeoutError{Plugin: name, Stage: "start", Timeout: timeout}
+		}
 		return nil, fmt.Errorf("plugin %q (version %s) failed to start (%s): %w", name, version, cmd.Path, err)
 	}
 
@@ -137,6 +182,12 @@ func LoadPlugin(name, version, source string) (*PluginClient, error) {
 		client:   client,
 		provider: provider,
 		name:     name,
+		timeout:  timeout,
+	}
+	if cmd.Process != nil {
+		// Without a process tree the plugin still works; only processes
+		// it spawns may outlive it, so that isn't worth failing the load.
+		pc.tree, _ = newProcessTree(cmd.Process.Pid)
 	}
 
 	// Store in global registry
@@ -145,9 +196,24 @@ func LoadPlugin(name, version, source string) (*PluginClient, error) {
 	return pc, nil
 }
 
-// Configure passes settings to the plugin
+// Configure passes settings to the plugin. A plugin that hasn't answered
+// within its timeout is killed and a *TimeoutError returned, so a plugin
+// stuck installing dependencies fails the load instead of hanging it.
 func (p *PluginClient) Configure(settings map[string]string) error {
-	return p.provider.Configure(settings)
+	if p.timeout <= 0 {
+		return p.provider.Configure(settings)
+	}
+	done := make(chan error, 1)
+	go func() { done <- p.provider.Configure(settings) }()
+	timer := time.NewTimer(p.timeout)
+	defer timer.Stop()
+	select {
+	case err := <-done:
+		return err
+	case <-timer.C:
+		p.kill()
+		return &TimeoutError{Plugin: p.name, Stage: "finish configuring", Timeout: p.timeout}
+	}
 }
 
 // Call invokes a tool on the plugin
@@ -155,6 +221,43 @@ func (p *PluginClient) Call(ctx context.Context, toolName string, payload string
 	return p.provider.Call(ctx, toolName, payload)
 }
 
+// CallStream invokes a tool on the plugin, passing progress updates to
+// progress when the plugin streams them
+func (p *PluginClient) CallStream(ctx context.Context, toolName string, payload string, progress func(content string)) (string, error) {
+	if sp, ok := p.provider.(StreamingToolProvider); ok {
+		return sp.CallStream(ctx, toolName, payload, progress)
+	}
+	return p.provider.Call(ctx, toolName, payload)
+}
+
+// SetToolTimeouts bounds how long each call to the plugin's tools may run:
+// byTool for the tools it names, def for the rest. Zero means no limit.
+// Tools returned by GetTool and GetAllTools afterwards use the new limits.
+func (p *PluginClient) SetToolTimeouts(def time.Duration, byTool map[string]time.Duration) {
+	p.toolTimeoutMu.Lock()
+	defer p.toolTimeoutMu.Unlock()
+	p.toolTimeout = def
+	p.toolTimeouts = byTool
+}
+
+// toolTimeoutFor returns the call timeout for a tool.
+func (p *PluginClient) toolTimeoutFor(toolName string) time.Duration {
+	p.toolTimeoutMu.RLock()
+	defer p.toolTimeoutMu.RUnlock()
+	if d, ok := p.toolTimeouts[toolName]; ok {
+		return d
+	}
+	return p.toolTimeout
+}
+
+// newTool wraps a tool of this plugin with its call timeout.
+func (p *PluginClient) newTool(info *ToolInfo) *PluginTool {
+	t := NewPluginTool(p.provider, info)
+	t.plugin = p.name
+	t.timeout = p.toolTimeoutFor(info.Name)
+	return t
+}
+
 // GetToolInfo returns metadata about a specific tool
 func (p *PluginClient) GetToolInfo(toolName string) (*ToolInfo, error) {
 	return p.provider.GetToolInfo(toolName)
@@ -171,7 +274,7 @@ func (p *PluginClient) GetTool(toolName string) (aitools.Tool, error) {
 	if err != nil {
 		return nil, err
 	}
-	return NewPluginTool(p.provider, info), nil
+	return p.newTool(info), nil
 }
 
 // GetAllTools returns a map of all tools provided by this plugin
@@ -182,7 +285,7 @@ func (p *PluginClient) GetAllTools() (map[string]aitools.Tool, error) {
 	}
 	tools := make(map[string]aitools.Tool, len(infos))
 	for _, info := range infos {
-		tools[info.Name] = NewPluginTool(p.provider, info)
+		tools[info.Name] = p.newTool(info)
 	}
 	return tools, nil
 }
@@ -191,9 +294,15 @@ func (p *PluginClient) GetAllTools() (map[string]aitools.Tool, error) {
 // Note: When using globally cached plugins, prefer CloseAll() at program exit
 // rather than closing individual plugins.
 func (p *PluginClient) Close() {
+	p.kill()
+}
+
+// kill stops the plugin process, then anything it spawned.
+func (p *PluginClient) kill() {
 	if p.client != nil {
 		p.client.Kill()
 	}
+	p.tree.kill()
 }
 
 // CloseAll shuts down all globally cached plugins.
@@ -203,9 +312,7 @@ func CloseAll() {
 	defer globalRegistryLock.Unlock()
 
 	for key, pc := range globalRegistry {
-		if pc.client != nil {
-			pc.client.Kill()
-		}
+		pc.kill()
 		delete(globalRegistry, key)
 	}
 }
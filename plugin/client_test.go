@@ -0,0 +1,59 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// stuckProvider never finishes Configure, like a plugin downloading
+// browsers on a machine without network access.
+type stuckProvider struct {
+	release chan struct{}
+}
+
+func (p *stuckProvider) Configure(settings map[string]string) error {
+	<-p.release
+	return nil
+}
+
+func (p *stuckProvider) Call(ctx context.Context, toolName string, payload string) (string, error) {
+	return "", nil
+}
+
+func (p *stuckProvider) GetToolInfo(toolName string) (*ToolInfo, error) { return nil, nil }
+
+func (p *stuckProvider) ListTools() ([]*ToolInfo, error) { return nil, nil }
+
+func TestConfigureTimesOut(t *testing.T) {
+	provider := &stuckProvider{release: make(chan struct{})}
+	defer close(provider.release)
+	pc := &PluginClient{provider: provider, name: "browser", timeout: 50 * time.Millisecond}
+
+	err := pc.Configure(map[string]string{"headless": "true"})
+
+	var timeoutErr *TimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("expected a *TimeoutError, got %v", err)
+	}
+	if timeoutErr.Plugin != "browser" || timeoutErr.Stage != "finish configuring" {
+		t.Errorf("unexpected timeout error %+v", timeoutErr)
+	}
+	for _, want := range []string{"plugin 'browser' did not finish configuring within 50ms", "raise plugin_timeout"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q should contain %q", err.Error(), want)
+		}
+	}
+}
+
+func TestConfigureWithinTimeout(t *testing.T) {
+	provider := &stuckProvider{release: make(chan struct{})}
+	close(provider.release)
+	pc := &PluginClient{provider: provider, name: "browser", timeout: time.Minute}
+
+	if err := pc.Configure(nil); err != nil {
+		t.Fatalf("Configure: %v", err)
+	}
+}
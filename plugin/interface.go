@@ -12,7 +12,9 @@ import (
 
 var (
 	Handshake = squadron.Handshake
-	PluginMap = squadron.PluginMap
+	PluginMap = map[string]goplugin.Plugin{
+		"tool": &toolGRPCPlugin{},
+	}
 )
 
 type ToolInfo struct {
@@ -41,6 +43,15 @@ func (w *sdkProviderWrapper) Call(ctx context.Context, toolName string, payload
 	return w.impl.Call(ctx, toolName, payload)
 }
 
+// CallStream streams progress when the underlying provider supports it and
+// otherwise behaves like Call.
+func (w *sdkProviderWrapper) CallStream(ctx context.Context, toolName string, payload string, progress func(content string)) (string, error) {
+	if s, ok := w.impl.(StreamingToolProvider); ok {
+		return s.CallStream(ctx, toolName, payload, progress)
+	}
+	return w.impl.Call(ctx, toolName, payload)
+}
+
 func (w *sdkProviderWrapper) GetToolInfo(toolName string) (*ToolInfo, error) {
 	sdkInfo, err := w.impl.GetToolInfo(toolName)
 	if err != nil {
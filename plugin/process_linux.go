@@ -0,0 +1,11 @@
+package plugin
+
+import "syscall"
+
+// setParentDeathSignal has the kernel kill the plugin when the thread that
+// started it exits. Go only retires a thread when a goroutine locked to it
+// exits, which LoadPlugin never does, so in practice this fires when
+// squadron itself dies.
+func setParentDeathSignal(attr *syscall.SysProcAttr) {
+	attr.Pdeathsig = syscall.SIGKILL
+}
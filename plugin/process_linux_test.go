@@ -0,0 +1,75 @@
+package plugin
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// Killing the process tree must also kill what the plugin spawned, such as
+// a browser, not just the plugin itself.
+func TestProcessTreeKillsChildren(t *testing.T) {
+	cmd := exec.Command("sh", "-c", "sleep 60 & echo $!; wait")
+	prepareCommand(cmd)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	line, err := bufio.NewReader(stdout).ReadString('\n')
+	if err != nil {
+		t.Fatalf("read child pid: %v", err)
+	}
+	child, err := strconv.Atoi(strings.TrimSpace(line))
+	if err != nil {
+		t.Fatalf("parse child pid %q: %v", line, err)
+	}
+	if pgid, err := syscall.Getpgid(child); err != nil || pgid != cmd.Process.Pid {
+		t.Fatalf("child pgid = %d (%v), want the plugin's pid %d", pgid, err, cmd.Process.Pid)
+	}
+
+	tree, err := newProcessTree(cmd.Process.Pid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tree.kill()
+
+	done := make(chan struct{})
+	go func() {
+		cmd.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("plugin process survived kill")
+	}
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		if !processRunning(child) {
+			return
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	t.Fatalf("child %d survived killing the process tree", child)
+}
+
+// processRunning reports whether pid exists and isn't a zombie. The killed
+// child is orphaned, and may stay a zombie until init gets round to it.
+func processRunning(pid int) bool {
+	stat, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return false
+	}
+	// The state follows the parenthesized command name.
+	fields := strings.Fields(string(stat[strings.LastIndexByte(string(stat), ')')+1:]))
+	return len(fields) > 0 && fields[0] != "Z"
+}
@@ -0,0 +1,9 @@
+//go:build !linux && !windows
+
+package plugin
+
+import "syscall"
+
+// setParentDeathSignal is a no-op outside Linux: there is no parent death
+// signal, so plugins rely on CloseAll running at exit.
+func setParentDeathSignal(attr *syscall.SysProcAttr) {}
@@ -0,0 +1,39 @@
+//go:build !windows
+
+package plugin
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// processTree is a plugin process and everything it spawns. The plugin
+// leads its own process group, so a browser or language server it started
+// is killed along with it instead of being orphaned.
+type processTree struct {
+	pgid int
+}
+
+// prepareCommand puts the plugin in its own process group, which also keeps
+// a Ctrl+C in squadron's terminal from reaching it before squadron has shut
+// it down, and has it killed if squadron dies without cleaning up (Linux
+// only; see setParentDeathSignal).
+func prepareCommand(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setpgid = true
+	setParentDeathSignal(cmd.SysProcAttr)
+}
+
+func newProcessTree(pid int) (*processTree, error) {
+	return &processTree{pgid: pid}, nil
+}
+
+// kill kills whatever is left of the process group.
+func (t *processTree) kill() {
+	if t == nil || t.pgid <= 0 {
+		return
+	}
+	_ = syscall.Kill(-t.pgid, syscall.SIGKILL)
+}
@@ -0,0 +1,68 @@
+package plugin
+
+import (
+	"fmt"
+	"os/exec"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// processTree is a plugin process and everything it spawns, held in a job
+// object. Closing the job kills every process in it, so plugins die with
+// squadron even when it exits without cleaning up.
+type processTree struct {
+	job windows.Handle
+}
+
+// prepareCommand starts the plugin in its own process group, so a Ctrl+C in
+// squadron's console doesn't reach it before squadron has shut it down, and
+// without a console window of its own.
+func prepareCommand(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.CreationFlags |= windows.CREATE_NEW_PROCESS_GROUP
+	cmd.SysProcAttr.HideWindow = true
+}
+
+// newProcessTree puts the running plugin in a new kill-on-close job. Child
+// processes it starts from then on join the job too.
+func newProcessTree(pid int) (*processTree, error) {
+	job, err := windows.CreateJobObject(nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create job object: %w", err)
+	}
+	info := windows.JOBOBJECT_EXTENDED_LIMIT_INFORMATION{
+		BasicLimitInformation: windows.JOBOBJECT_BASIC_LIMIT_INFORMATION{
+			LimitFlags: windows.JOB_OBJECT_LIMIT_KILL_ON_JOB_CLOSE,
+		},
+	}
+	if _, err := windows.SetInformationJobObject(job, windows.JobObjectExtendedLimitInformation,
+		uintptr(unsafe.Pointer(&info)), uint32(unsafe.Sizeof(info))); err != nil {
+		windows.CloseHandle(job)
+		return nil, fmt.Errorf("configure job object: %w", err)
+	}
+	proc, err := windows.OpenProcess(windows.PROCESS_SET_QUOTA|windows.PROCESS_TERMINATE, false, uint32(pid))
+	if err != nil {
+		windows.CloseHandle(job)
+		return nil, fmt.Errorf("open plugin process: %w", err)
+	}
+	defer windows.CloseHandle(proc)
+	if err := windows.AssignProcessToJobObject(job, proc); err != nil {
+		windows.CloseHandle(job)
+		return nil, fmt.Errorf("assign plugin to job object: %w", err)
+	}
+	return &processTree{job: job}, nil
+}
+
+// kill terminates every process left in the job and releases it.
+func (t *processTree) kill() {
+	if t == nil || t.job == 0 {
+		return
+	}
+	_ = windows.TerminateJobObject(t.job, 1)
+	_ = windows.CloseHandle(t.job)
+	t.job = 0
+}
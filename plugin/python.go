@@ -10,41 +10,47 @@ import (
 	"strings"
 )
 
-func installPython(pluginDir, source, scriptName string) error {
+// venvExecutable returns the path, relative to the venv, of an executable
+// pip installed into it: bin/name, or Scripts\name.exe on Windows.
+func venvExecutable(name string) string {
 	if runtime.GOOS == "windows" {
-		return fmt.Errorf("Python plugins on Windows are not yet supported")
+		return filepath.Join("venv", "Scripts", name+".exe")
 	}
+	return filepath.Join("venv", "bin", name)
+}
 
+func installPython(pluginDir, source, scriptName string) error {
 	pythonBin, err := findPython()
 	if err != nil {
 		return err
 	}
 
 	venvDir := filepath.Join(pluginDir, "venv")
-	venvBin := filepath.Join(venvDir, "bin")
 
 	fmt.Printf("  Creating venv (%s)...\n", pythonBin)
 	if err := runStreamed(pythonBin, "-m", "venv", venvDir); err != nil {
 		return fmt.Errorf("python venv creation failed: %w", err)
 	}
 
-	pip := filepath.Join(venvBin, "pip")
+	// pip runs through the venv's python: on Windows a running pip.exe
+	// can't replace itself during the upgrade.
+	python := filepath.Join(pluginDir, venvExecutable("python"))
 	fmt.Println("  Installing source...")
-	if err := runStreamed(pip, "install", "--upgrade", "pip"); err != nil {
+	if err := runStreamed(python, "-m", "pip", "install", "--upgrade", "pip"); err != nil {
 		return fmt.Errorf("pip upgrade failed: %w", err)
 	}
-	if err := runStreamed(pip, "install", source); err != nil {
+	if err := runStreamed(python, "-m", "pip", "install", source); err != nil {
 		return fmt.Errorf("pip install failed: %w", err)
 	}
 
-	scriptPath := filepath.Join(venvBin, scriptName)
+	scriptPath := filepath.Join(pluginDir, venvExecutable(scriptName))
 	if _, err := os.Stat(scriptPath); err != nil {
 		return fmt.Errorf("expected script %q not found at %s after install", scriptName, scriptPath)
 	}
 
 	runner := &Runner{
 		Kind:  "python",
-		Entry: filepath.Join("venv", "bin", scriptName),
+		Entry: venvExecutable(scriptName),
 	}
 	if err := writeRunner(pluginDir, runner); err != nil {
 		return fmt.Errorf("write runner.json: %w", err)
@@ -0,0 +1,203 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync/atomic"
+
+	goplugin "github.com/hashicorp/go-plugin"
+	"github.com/mlund01/squadron-sdk"
+	pb "github.com/mlund01/squadron-sdk/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+// StreamingToolProvider is implemented by providers whose tools can report
+// progress while they run. progress is called once per update, from the
+// calling goroutine, before CallStream returns the final result.
+type StreamingToolProvider interface {
+	CallStream(ctx context.Context, toolName string, payload string, progress func(content string)) (string, error)
+}
+
+// CallStreamMethod is the server-streaming RPC a plugin serves to stream
+// tool progress. It takes the same CallRequest as Call and replies with a
+// sequence of CallStreamChunk messages (see proto/stream.proto). Plugins
+// that don't serve it answer Unimplemented and the host falls back to Call.
+const CallStreamMethod = "/plugin.ToolPlugin/CallStream"
+
+var callStreamDesc = grpc.StreamDesc{
+	StreamName:    "CallStream",
+	ServerStreams: true,
+}
+
+// CallStreamChunk is one message on the CallStream response stream. Every
+// message before the last carries Progress; the last has Done set along with
+// Result or Error.
+type CallStreamChunk struct {
+	Progress string
+	Result   string
+	Error    string
+	Done     bool
+}
+
+// Field numbers from proto/stream.proto.
+const (
+	chunkFieldProgress protowire.Number = 1
+	chunkFieldResult   protowire.Number = 2
+	chunkFieldError    protowire.Number = 3
+	chunkFieldDone     protowire.Number = 4
+)
+
+func (c *CallStreamChunk) marshal() []byte {
+	var b []byte
+	if c.Progress != "" {
+		b = protowire.AppendTag(b, chunkFieldProgress, protowire.BytesType)
+		b = protowire.AppendString(b, c.Progress)
+	}
+	if c.Result != "" {
+		b = protowire.AppendTag(b, chunkFieldResult, protowire.BytesType)
+		b = protowire.AppendString(b, c.Result)
+	}
+	if c.Error != "" {
+		b = protowire.AppendTag(b, chunkFieldError, protowire.BytesType)
+		b = protowire.AppendString(b, c.Error)
+	}
+	if c.Done {
+		b = protowire.AppendTag(b, chunkFieldDone, protowire.VarintType)
+		b = protowire.AppendVarint(b, 1)
+	}
+	return b
+}
+
+func (c *CallStreamChunk) unmarshal(b []byte) error {
+	*c = CallStreamChunk{}
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+		switch {
+		case num == chunkFieldProgress && typ == protowire.BytesType:
+			c.Progress, n = protowire.ConsumeString(b)
+		case num == chunkFieldResult && typ == protowire.BytesType:
+			c.Result, n = protowire.ConsumeString(b)
+		case num == chunkFieldError && typ == protowire.BytesType:
+			c.Error, n = protowire.ConsumeString(b)
+		case num == chunkFieldDone && typ == protowire.VarintType:
+			var v uint64
+			v, n = protowire.ConsumeVarint(b)
+			c.Done = v != 0
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, b)
+		}
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+	}
+	return nil
+}
+
+// streamCodec encodes CallStreamChunk by hand and everything else as
+// regular protobuf. It keeps the name "proto" so the wire content type is
+// unchanged and plugins can use generated code for stream.proto.
+type streamCodec struct{}
+
+func (streamCodec) Name() string { return "proto" }
+
+func (streamCodec) Marshal(v any) ([]byte, error) {
+	switch m := v.(type) {
+	case *CallStreamChunk:
+		return m.marshal(), nil
+	case proto.Message:
+		return proto.Marshal(m)
+	}
+	return nil, fmt.Errorf("streamCodec: cannot marshal %T", v)
+}
+
+func (streamCodec) Unmarshal(data []byte, v any) error {
+	switch m := v.(type) {
+	case *CallStreamChunk:
+		return m.unmarshal(data)
+	case proto.Message:
+		return proto.Unmarshal(data, m)
+	}
+	return fmt.Errorf("streamCodec: cannot unmarshal into %T", v)
+}
+
+// =============================================================================
+// Host side
+// =============================================================================
+
+// toolGRPCPlugin is the SDK's tool plugin with a client that can also call
+// CallStream over the same connection.
+type toolGRPCPlugin struct {
+	squadron.ToolPluginGRPCPlugin
+}
+
+func (p *toolGRPCPlugin) GRPCClient(ctx context.Context, broker *goplugin.GRPCBroker, conn *grpc.ClientConn) (interface{}, error) {
+	raw, err := p.ToolPluginGRPCPlugin.GRPCClient(ctx, broker, conn)
+	if err != nil {
+		return nil, err
+	}
+	return &grpcToolClient{ToolProvider: raw.(squadron.ToolProvider), conn: conn}, nil
+}
+
+// grpcToolClient adds CallStream to the SDK's gRPC client.
+type grpcToolClient struct {
+	squadron.ToolProvider
+	conn *grpc.ClientConn
+
+	// unsupported is set once the plugin answers Unimplemented so later
+	// calls go straight to Call.
+	unsupported atomic.Bool
+}
+
+func (c *grpcToolClient) CallStream(ctx context.Context, toolName string, payload string, progress func(content string)) (string, error) {
+	if c.unsupported.Load() {
+		return c.Call(ctx, toolName, payload)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := c.conn.NewStream(ctx, &callStreamDesc, CallStreamMethod, grpc.ForceCodec(streamCodec{}))
+	if err != nil {
+		return "", err
+	}
+	if err := stream.SendMsg(&pb.CallRequest{ToolName: toolName, Payload: payload}); err != nil {
+		return "", err
+	}
+	if err := stream.CloseSend(); err != nil {
+		return "", err
+	}
+
+	for first := true; ; first = false {
+		var chunk CallStreamChunk
+		if err := stream.RecvMsg(&chunk); err != nil {
+			if first && status.Code(err) == codes.Unimplemented {
+				c.unsupported.Store(true)
+				return c.Call(ctx, toolName, payload)
+			}
+			if err == io.EOF {
+				return "", fmt.Errorf("plugin ended CallStream for '%s' without a result", toolName)
+			}
+			return "", err
+		}
+		if chunk.Done {
+			if chunk.Error != "" {
+				return "", errors.New(chunk.Error)
+			}
+			return chunk.Result, nil
+		}
+		if chunk.Progress != "" && progress != nil {
+			progress(chunk.Progress)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package plugin
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/mlund01/squadron-sdk"
+	pb "github.com/mlund01/squadron-sdk/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	"squadron/aitools"
+)
+
+// echoProvider is a unary-only plugin implementation.
+type echoProvider struct{}
+
+func (echoProvider) Configure(map[string]string) error { return nil }
+func (echoProvider) Call(_ context.Context, toolName, payload string) (string, error) {
+	return toolName + ":" + payload, nil
+}
+func (echoProvider) GetToolInfo(string) (*squadron.ToolInfo, error) { return nil, nil }
+func (echoProvider) ListTools() ([]*squadron.ToolInfo, error)       { return nil, nil }
+
+// streamingService serves only CallStream, sending a fixed set of chunks.
+func streamingService(chunks []CallStreamChunk) *grpc.ServiceDesc {
+	return &grpc.ServiceDesc{
+		ServiceName: "plugin.ToolPlugin",
+		HandlerType: (*any)(nil),
+		Streams: []grpc.StreamDesc{{
+			StreamName:    "CallStream",
+			ServerStreams: true,
+			Handler: func(_ any, stream grpc.ServerStream) error {
+				var req pb.CallRequest
+				if err := stream.RecvMsg(&req); err != nil {
+					return err
+				}
+				for i := range chunks {
+					if err := stream.SendMsg(&chunks[i]); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		}},
+	}
+}
+
+func dialTestPlugin(t *testing.T, register func(*grpc.Server)) *grpcToolClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer(grpc.ForceServerCodec(streamCodec{}))
+	register(srv)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	raw, err := (&toolGRPCPlugin{}).GRPCClient(context.Background(), nil, conn)
+	if err != nil {
+		t.Fatalf("GRPCClient: %v", err)
+	}
+	return raw.(*grpcToolClient)
+}
+
+func TestCallStreamForwardsProgress(t *testing.T) {
+	client := dialTestPlugin(t, func(s *grpc.Server) {
+		s.RegisterService(streamingService([]CallStreamChunk{
+			{Progress: "navigating"},
+			{Progress: "page loaded"},
+			{Done: true, Result: "done"},
+		}), nil)
+	})
+
+	var got []string
+	result, err := client.CallStream(context.Background(), "browse", "{}", func(c string) { got = append(got, c) })
+	if err != nil {
+		t.Fatalf("CallStream: %v", err)
+	}
+	if result != "done" {
+		t.Fatalf("result = %q, want %q", result, "done")
+	}
+	if strings.Join(got, "|") != "navigating|page loaded" {
+		t.Fatalf("progress = %v", got)
+	}
+}
+
+func TestCallStreamReturnsPluginError(t *testing.T) {
+	client := dialTestPlugin(t, func(s *grpc.Server) {
+		s.RegisterService(streamingService([]CallStreamChunk{
+			{Progress: "starting"},
+			{Done: true, Error: "browser crashed"},
+		}), nil)
+	})
+
+	_, err := client.CallStream(context.Background(), "browse", "{}", nil)
+	if err == nil || err.Error() != "browser crashed" {
+		t.Fatalf("err = %v, want browser crashed", err)
+	}
+}
+
+func TestCallStreamWithoutResultFails(t *testing.T) {
+	client := dialTestPlugin(t, func(s *grpc.Server) {
+		s.RegisterService(streamingService([]CallStreamChunk{{Progress: "starting"}}), nil)
+	})
+
+	if _, err := client.CallStream(context.Background(), "browse", "{}", nil); err == nil {
+		t.Fatal("expected an error when the stream ends without a done chunk")
+	}
+}
+
+func TestCallStreamFallsBackToCall(t *testing.T) {
+	client := dialTestPlugin(t, func(s *grpc.Server) {
+		pb.RegisterToolPluginServer(s, &squadron.GRPCServer{Impl: echoProvider{}})
+	})
+
+	for i := 0; i < 2; i++ {
+		result, err := client.CallStream(context.Background(), "echo", "hi", func(string) {
+			t.Fatal("unary plugins should not report progress")
+		})
+		if err != nil {
+			t.Fatalf("CallStream: %v", err)
+		}
+		if result != "echo:hi" {
+			t.Fatalf("result = %q, want %q", result, "echo:hi")
+		}
+	}
+	if !client.unsupported.Load() {
+		t.Fatal("expected the client to remember that CallStream is unsupported")
+	}
+}
+
+func TestPluginToolUsesProgressFromContext(t *testing.T) {
+	client := dialTestPlugin(t, func(s *grpc.Server) {
+		s.RegisterService(streamingService([]CallStreamChunk{
+			{Progress: "step 1"},
+			{Done: true, Result: "ok"},
+		}), nil)
+	})
+	tool := NewPluginTool(WrapSDKProvider(client), &ToolInfo{Name: "browse"})
+
+	var got []string
+	ctx := aitools.WithToolProgress(context.Background(), func(c string) { got = append(got, c) })
+	if result := tool.Call(ctx, "{}"); result != "ok" {
+		t.Fatalf("result = %q, want ok", result)
+	}
+	if len(got) != 1 || got[0] != "step 1" {
+		t.Fatalf("progress = %v", got)
+	}
+}
+
+func TestCallStreamChunkRoundTrip(t *testing.T) {
+	in := CallStreamChunk{Progress: "p", Result: "r", Error: "e", Done: true}
+	var out CallStreamChunk
+	if err := out.unmarshal(in.marshal()); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if out != in {
+		t.Fatalf("got %+v, want %+v", out, in)
+	}
+	if err := out.unmarshal([]byte{0x0a, 0x05}); err == nil {
+		t.Fatal("expected truncated input to fail")
+	}
+}
@@ -3,6 +3,9 @@ package plugin
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
 
 	"squadron/aitools"
 )
@@ -10,6 +13,8 @@ import (
 type PluginTool struct {
 	provider ToolProvider
 	info     *ToolInfo
+	plugin   string        // plugin block name, for timeout errors
+	timeout  time.Duration // bounds each call (0 = none)
 }
 
 func NewPluginTool(provider ToolProvider, info *ToolInfo) *PluginTool {
@@ -35,10 +40,56 @@ func (t *PluginTool) ToolOutputSchema() json.RawMessage {
 	return t.info.OutputSchema
 }
 
+// Call invokes the tool. When the caller installed a progress callback
+// (aitools.WithToolProgress) and the provider can stream, progress updates
+// are forwarded as they arrive.
+//
+// The call is cancelled with ctx or once the tool's timeout elapses. Either
+// way Call returns right away, even if the plugin ignores the cancellation.
 func (t *PluginTool) Call(ctx context.Context, params string) string {
-	result, err := t.provider.Call(ctx, t.info.Name, params)
-	if err != nil {
-		return "error: " + err.Error()
+	var callCtx context.Context
+	var cancel context.CancelFunc
+	if t.timeout > 0 {
+		callCtx, cancel = context.WithTimeout(ctx, t.timeout)
+	} else {
+		callCtx, cancel = context.WithCancel(ctx)
 	}
-	return result
+	defer cancel()
+
+	type callResult struct {
+		result string
+		err    error
+	}
+	done := make(chan callResult, 1)
+	go func() {
+		result, err := t.call(callCtx, params)
+		done <- callResult{result, err}
+	}()
+
+	var res callResult
+	select {
+	case res = <-done:
+	case <-callCtx.Done():
+		select {
+		case res = <-done:
+		default:
+			res.err = callCtx.Err()
+		}
+	}
+	if res.err != nil {
+		if ctx.Err() == nil && errors.Is(callCtx.Err(), context.DeadlineExceeded) {
+			return fmt.Sprintf("error: tool '%s' did not finish within %s (tool_timeout of plugin '%s')", t.info.Name, t.timeout, t.plugin)
+		}
+		return "error: " + res.err.Error()
+	}
+	return res.result
+}
+
+func (t *PluginTool) call(ctx context.Context, params string) (string, error) {
+	if sp, ok := t.provider.(StreamingToolProvider); ok {
+		if progress := aitools.ToolProgressFromContext(ctx); progress != nil {
+			return sp.CallStream(ctx, t.info.Name, params, progress)
+		}
+	}
+	return t.provider.Call(ctx, t.info.Name, params)
 }
@@ -0,0 +1,54 @@
+package plugin
+
+import (
+	"context"
+	"sort"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"squadron/aitools"
+)
+
+// gRPC metadata keys carrying a call's aitools.ToolEnv. A plugin reads
+// them from the incoming context of Call: the working directory as a single
+// value, and each environment variable as a "NAME=value" entry.
+const (
+	MetadataWorkingDir = "squadron-working-dir"
+	MetadataEnv        = "squadron-env"
+)
+
+// dialOptions are the gRPC options for every plugin connection.
+var dialOptions = append(append([]grpc.DialOption{}, traceDialOptions...),
+	grpc.WithChainUnaryInterceptor(toolEnvUnaryInterceptor),
+	grpc.WithChainStreamInterceptor(toolEnvStreamInterceptor),
+)
+
+func toolEnvUnaryInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	return invoker(withToolEnvMetadata(ctx), method, req, reply, cc, opts...)
+}
+
+func toolEnvStreamInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return streamer(withToolEnvMetadata(ctx), desc, cc, method, opts...)
+}
+
+// withToolEnvMetadata adds ctx's ToolEnv to its outgoing metadata.
+func withToolEnvMetadata(ctx context.Context) context.Context {
+	env := aitools.ToolEnvFromContext(ctx)
+	if env.IsEmpty() {
+		return ctx
+	}
+	var kv []string
+	if env.Dir != "" {
+		kv = append(kv, MetadataWorkingDir, env.Dir)
+	}
+	names := make([]string, 0, len(env.Env))
+	for name := range env.Env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		kv = append(kv, MetadataEnv, name+"="+env.Env[name])
+	}
+	return metadata.AppendToOutgoingContext(ctx, kv...)
+}
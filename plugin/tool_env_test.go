@@ -0,0 +1,36 @@
+package plugin
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/metadata"
+
+	"squadron/aitools"
+)
+
+func TestWithToolEnvMetadata(t *testing.T) {
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-existing", "1")
+
+	// No ToolEnv: nothing to send
+	md, _ := metadata.FromOutgoingContext(withToolEnvMetadata(ctx))
+	if len(md.Get(MetadataWorkingDir)) != 0 || len(md.Get(MetadataEnv)) != 0 {
+		t.Fatalf("no tool env metadata expected, got %v", md)
+	}
+
+	ctx = aitools.WithToolEnv(ctx, &aitools.ToolEnv{
+		Env: map[string]string{"GIT_AUTHOR": "bot", "API_TOKEN": "a=b"},
+		Dir: "/repos/api",
+	})
+	md, _ = metadata.FromOutgoingContext(withToolEnvMetadata(ctx))
+	if got := md.Get(MetadataWorkingDir); len(got) != 1 || got[0] != "/repos/api" {
+		t.Fatalf("expected working dir /repos/api, got %v", got)
+	}
+	got := md.Get(MetadataEnv)
+	if len(got) != 2 || got[0] != "API_TOKEN=a=b" || got[1] != "GIT_AUTHOR=bot" {
+		t.Fatalf("expected sorted NAME=value entries, got %v", got)
+	}
+	if got := md.Get("x-existing"); len(got) != 1 || got[0] != "1" {
+		t.Fatalf("existing metadata should be kept, got %v", md)
+	}
+}
@@ -0,0 +1,78 @@
+package plugin
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+// hangingProvider blocks every call until release is closed, ignoring ctx,
+// like a plugin stuck on a page that never loads.
+type hangingProvider struct {
+	release chan struct{}
+}
+
+func (p *hangingProvider) Configure(settings map[string]string) error { return nil }
+
+func (p *hangingProvider) Call(ctx context.Context, toolName string, payload string) (string, error) {
+	<-p.release
+	return "loaded", nil
+}
+
+func (p *hangingProvider) GetToolInfo(toolName string) (*ToolInfo, error) {
+	return &ToolInfo{Name: toolName}, nil
+}
+
+func (p *hangingProvider) ListTools() ([]*ToolInfo, error) { return nil, nil }
+
+func TestPluginToolTimesOut(t *testing.T) {
+	provider := &hangingProvider{release: make(chan struct{})}
+	defer close(provider.release)
+	pc := &PluginClient{provider: provider, name: "playwright"}
+	pc.SetToolTimeouts(time.Hour, map[string]time.Duration{"browser_navigate": 50 * time.Millisecond})
+
+	tool, err := pc.GetTool("browser_navigate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := tool.Call(context.Background(), `{"url":"https://example.com"}`)
+
+	want := "error: tool 'browser_navigate' did not finish within 50ms (tool_timeout of plugin 'playwright')"
+	if got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestPluginToolCancelledWithContext(t *testing.T) {
+	provider := &hangingProvider{release: make(chan struct{})}
+	defer close(provider.release)
+	pc := &PluginClient{provider: provider, name: "playwright"}
+
+	tool, err := pc.GetTool("browser_navigate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	got := tool.Call(ctx, "{}")
+
+	if !strings.HasPrefix(got, "error: ") || strings.Contains(got, "tool_timeout") {
+		t.Fatalf("expected a plain cancellation error, got %q", got)
+	}
+}
+
+func TestPluginToolWithinTimeout(t *testing.T) {
+	provider := &hangingProvider{release: make(chan struct{})}
+	close(provider.release)
+	pc := &PluginClient{provider: provider, name: "playwright"}
+	pc.SetToolTimeouts(time.Minute, nil)
+
+	tool, err := pc.GetTool("browser_navigate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := tool.Call(context.Background(), "{}"); got != "loaded" {
+		t.Fatalf("got %q, want %q", got, "loaded")
+	}
+}
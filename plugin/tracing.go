@@ -0,0 +1,59 @@
+package plugin
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"squadron/tracing"
+)
+
+// traceDialOptions propagate the caller's trace context to plugins as W3C
+// traceparent/tracestate gRPC metadata, so a plugin that instruments its
+// server can join the mission's trace.
+var traceDialOptions = []grpc.DialOption{
+	grpc.WithChainUnaryInterceptor(traceUnaryInterceptor),
+	grpc.WithChainStreamInterceptor(traceStreamInterceptor),
+}
+
+func traceUnaryInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	return invoker(withTraceMetadata(ctx), method, req, reply, cc, opts...)
+}
+
+func traceStreamInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return streamer(withTraceMetadata(ctx), desc, cc, method, opts...)
+}
+
+// withTraceMetadata adds ctx's trace context to its outgoing metadata.
+func withTraceMetadata(ctx context.Context) context.Context {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	md = md.Copy()
+	tracing.Inject(ctx, metadataCarrier(md))
+	if len(md) == 0 {
+		return ctx
+	}
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+// metadataCarrier adapts gRPC metadata to propagation.TextMapCarrier.
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	if v := metadata.MD(c).Get(key); len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
@@ -0,0 +1,34 @@
+package plugin
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestWithTraceMetadata(t *testing.T) {
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-existing", "1")
+
+	// No span: nothing to propagate
+	md, _ := metadata.FromOutgoingContext(withTraceMetadata(ctx))
+	if len(md.Get("traceparent")) != 0 {
+		t.Fatalf("no traceparent expected without a span, got %v", md)
+	}
+
+	tp := sdktrace.NewTracerProvider()
+	defer tp.Shutdown(context.Background())
+	ctx, span := tp.Tracer("test").Start(ctx, "tool call")
+	defer span.End()
+
+	md, _ = metadata.FromOutgoingContext(withTraceMetadata(ctx))
+	parent := md.Get("traceparent")
+	if len(parent) != 1 || !strings.Contains(parent[0], span.SpanContext().TraceID().String()) {
+		t.Fatalf("expected traceparent for trace %s, got %v", span.SpanContext().TraceID(), md)
+	}
+	if got := md.Get("x-existing"); len(got) != 1 || got[0] != "1" {
+		t.Fatalf("existing metadata should be kept, got %v", md)
+	}
+}
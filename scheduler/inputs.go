@@ -0,0 +1,47 @@
+package scheduler
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// InputData is what schedule input templates can reference, e.g.
+// inputs = { day = "{{ .Date }}" }.
+type InputData struct {
+	Mission string
+	Source  string
+	Time    time.Time // scheduled fire time, in the schedule's timezone
+	Date    string    // Time as YYYY-MM-DD
+}
+
+// RenderInputs expands Go templates in schedule input values. Values
+// without "{{" are passed through unchanged.
+func RenderInputs(inputs map[string]string, data InputData) (map[string]string, error) {
+	if len(inputs) == 0 {
+		return inputs, nil
+	}
+	if data.Date == "" && !data.Time.IsZero() {
+		data.Date = data.Time.Format("2006-01-02")
+	}
+
+	rendered := make(map[string]string, len(inputs))
+	for k, v := range inputs {
+		if !strings.Contains(v, "{{") {
+			rendered[k] = v
+			continue
+		}
+		tmpl, err := template.New(k).Option("missingkey=error").Parse(v)
+		if err != nil {
+			return nil, fmt.Errorf("input %q: %w", k, err)
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return nil, fmt.Errorf("input %q: %w", k, err)
+		}
+		rendered[k] = buf.String()
+	}
+	return rendered, nil
+}
@@ -3,6 +3,7 @@ package scheduler
 import (
 	"fmt"
 	"log"
+	"sort"
 	"sync"
 	"time"
 
@@ -13,9 +14,16 @@ import (
 // source describes the trigger (e.g., "schedule[0]", "webhook").
 type FireFunc func(missionName, source string, inputs map[string]string)
 
+// Locker claims scheduled runs so that several instances sharing a store
+// start each run only once. store.ScheduleStore implements it.
+type Locker interface {
+	ClaimScheduledRun(missionName, source string, fireAt time.Time) (bool, error)
+}
+
 // Scheduler manages timer-based mission firing and concurrency tracking.
 type Scheduler struct {
 	fireFn  FireFunc
+	locker  Locker
 	timers  map[string][]*time.Timer // missionName -> active timers
 	running map[string]int           // missionName -> count of running instances
 	limits  map[string]int           // missionName -> max_parallel
@@ -47,6 +55,14 @@ func New(fireFn FireFunc) *Scheduler {
 	}
 }
 
+// SetLocker makes every scheduled run claim its fire time through l before
+// firing, skipping runs another instance already claimed.
+func (s *Scheduler) SetLocker(l Locker) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.locker = l
+}
+
 // UpdateConfig replaces the current schedule configuration.
 // Stops all existing timers and starts new ones based on the config.
 func (s *Scheduler) UpdateConfig(cfg *config.Config) {
@@ -180,19 +196,80 @@ func (s *Scheduler) scheduleNextLocked(missionName string, entry schedEntry) {
 
 		// Re-schedule for next occurrence regardless of whether we fire
 		s.scheduleNextLocked(missionName, entry)
+		locker := s.locker
 		s.mu.Unlock()
 
 		if atCapacity {
 			return
 		}
-
-		source := "schedule"
-		if entry.index > 0 {
-			source = fmt.Sprintf("schedule[%d]", entry.index)
-		}
-		// Fire the mission (this is async — fireFn is expected to be non-blocking)
-		s.fireFn(missionName, source, entry.inputs)
+		s.fire(missionName, entry, next, locker)
 	})
 
 	s.timers[missionName] = append(s.timers[missionName], timer)
 }
+
+// fire renders the entry's inputs, claims the run when a locker is set, and
+// calls fireFn. Called without s.mu held.
+func (s *Scheduler) fire(missionName string, entry schedEntry, fireAt time.Time, locker Locker) {
+	source := scheduleSource(entry.index)
+
+	inputs, err := RenderInputs(entry.inputs, InputData{Mission: missionName, Source: source, Time: fireAt})
+	if err != nil {
+		log.Printf("scheduler: mission %q %s: %v (skipping)", missionName, source, err)
+		return
+	}
+
+	if locker != nil {
+		claimed, err := locker.ClaimScheduledRun(missionName, source, fireAt)
+		if err != nil {
+			log.Printf("scheduler: mission %q %s: claim run at %s: %v (skipping)", missionName, source, fireAt.Format(time.RFC3339), err)
+			return
+		}
+		if !claimed {
+			log.Printf("scheduler: mission %q %s at %s already claimed by another instance, skipping", missionName, source, fireAt.Format(time.RFC3339))
+			return
+		}
+	}
+
+	// Fire the mission (this is async — fireFn is expected to be non-blocking)
+	s.fireFn(missionName, source, inputs)
+}
+
+// scheduleSource names the index-th schedule of a mission for events and
+// dedupe claims.
+func scheduleSource(index int) string {
+	if index == 0 {
+		return "schedule"
+	}
+	return fmt.Sprintf("schedule[%d]", index)
+}
+
+// UpcomingRun is the next run of one schedule.
+type UpcomingRun struct {
+	MissionName string
+	Source      string
+	At          time.Time
+	Cron        string
+}
+
+// Upcoming returns the next run of every valid schedule in cfg after the
+// given time, soonest first.
+func Upcoming(cfg *config.Config, after time.Time) []UpcomingRun {
+	var runs []UpcomingRun
+	for _, m := range cfg.Missions {
+		for i := range m.Schedules {
+			nf, err := ParseSchedule(&m.Schedules[i])
+			if err != nil {
+				continue
+			}
+			runs = append(runs, UpcomingRun{
+				MissionName: m.Name,
+				Source:      scheduleSource(i),
+				At:          nf(after),
+				Cron:        m.Schedules[i].ToCron(),
+			})
+		}
+	}
+	sort.SliceStable(runs, func(i, j int) bool { return runs[i].At.Before(runs[j].At) })
+	return runs
+}
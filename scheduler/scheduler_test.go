@@ -291,3 +291,48 @@ var _ = Describe("Scheduler", func() {
 		s.Stop()
 	})
 })
+
+var _ = Describe("RenderInputs", func() {
+	fireAt := time.Date(2026, 3, 26, 9, 0, 0, 0, time.UTC)
+
+	It("expands templates against the fire time", func() {
+		got, err := scheduler.RenderInputs(map[string]string{
+			"day":    "{{ .Date }}",
+			"label":  "{{ .Mission }} via {{ .Source }} at {{ .Time.Format \"15:04\" }}",
+			"static": "daily",
+		}, scheduler.InputData{Mission: "report", Source: "schedule", Time: fireAt})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(got).To(Equal(map[string]string{
+			"day":    "2026-03-26",
+			"label":  "report via schedule at 09:00",
+			"static": "daily",
+		}))
+	})
+
+	It("reports templates that fail to execute", func() {
+		_, err := scheduler.RenderInputs(map[string]string{"day": "{{ .Nope }}"}, scheduler.InputData{Time: fireAt})
+		Expect(err).To(MatchError(ContainSubstring(`input "day"`)))
+	})
+})
+
+var _ = Describe("Upcoming", func() {
+	It("lists the next run of each schedule, soonest first", func() {
+		cfg := &config.Config{
+			Missions: []config.Mission{
+				{Name: "nightly", Schedules: []config.Schedule{{Cron: "0 23 * * *", Timezone: "UTC"}}},
+				{Name: "morning", Schedules: []config.Schedule{
+					{At: []string{"09:00"}, Timezone: "UTC"},
+					{At: []string{"06:30"}, Timezone: "UTC"},
+				}},
+			},
+		}
+		runs := scheduler.Upcoming(cfg, time.Date(2026, 3, 26, 8, 0, 0, 0, time.UTC))
+		Expect(runs).To(HaveLen(3))
+		Expect(runs[0].MissionName).To(Equal("morning"))
+		Expect(runs[0].Source).To(Equal("schedule"))
+		Expect(runs[0].At.Hour()).To(Equal(9))
+		Expect(runs[1].MissionName).To(Equal("nightly"))
+		Expect(runs[2].Source).To(Equal("schedule[1]"))
+		Expect(runs[2].At.Day()).To(Equal(27))
+	})
+})
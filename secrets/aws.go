@@ -0,0 +1,87 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"squadron/config"
+	"squadron/internal/awssig"
+)
+
+const awsService = "secretsmanager"
+
+// resolveAWS reads a secret with Secrets Manager's GetSecretValue. ref is a
+// secret ID or ARN, optionally with #key to pick a field of a JSON secret.
+// The request is signed with SigV4 using credentials from the environment.
+func (r *Resolver) resolveAWS(ctx context.Context, ref string) (string, error) {
+	var ac config.SecretsAWS
+	if r.cfg.AWS != nil {
+		ac = *r.cfg.AWS
+	}
+	region := firstNonEmpty(ac.Region, r.getenv("AWS_REGION"), r.getenv("AWS_DEFAULT_REGION"))
+	if region == "" {
+		return "", fmt.Errorf("aws region not set (secrets.aws.region or AWS_REGION)")
+	}
+	creds, err := awssig.CredentialsFromEnv(r.getenv)
+	if err != nil {
+		return "", fmt.Errorf("aws credentials not set: %w", err)
+	}
+	endpoint := ac.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://%s.%s.amazonaws.com/", awsService, region)
+	}
+
+	secretID, key := config.SplitSecretRef(ref)
+	body, _ := json.Marshal(map[string]string{"SecretId": secretID})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+	awssig.Sign(req, awssig.PayloadHash(body), creds, region, awsService, r.now())
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("aws: %w", err)
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("aws: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Type    string `json:"__type"`
+			Message string `json:"message"`
+		}
+		json.Unmarshal(respBody, &apiErr)
+		return "", fmt.Errorf("aws: %s returned %s %s %s", secretID, resp.Status, apiErr.Type, apiErr.Message)
+	}
+
+	var out struct {
+		SecretString *string `json:"SecretString"`
+	}
+	if err := json.Unmarshal(respBody, &out); err != nil {
+		return "", fmt.Errorf("aws: invalid response: %w", err)
+	}
+	if out.SecretString == nil {
+		return "", fmt.Errorf("aws: %s has no SecretString (binary secrets are not supported)", secretID)
+	}
+	if key == "" {
+		return *out.SecretString, nil
+	}
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(*out.SecretString), &fields); err != nil {
+		return "", fmt.Errorf("aws: %s is not a JSON secret, so #%s can't be read", secretID, key)
+	}
+	v, err := pickField(fields, key)
+	if err != nil {
+		return "", fmt.Errorf("aws: %s: %w", secretID, err)
+	}
+	return v, nil
+}
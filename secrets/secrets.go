@@ -0,0 +1,104 @@
+// Package secrets resolves the secrets declared in a config's `secrets`
+// block from their backends: environment variables, files, HashiCorp Vault
+// and AWS Secrets Manager.
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"squadron/config"
+)
+
+// requestTimeout bounds each call to a remote backend.
+const requestTimeout = 30 * time.Second
+
+// Resolver fetches secret values. The zero value is not usable; create one
+// with NewResolver.
+type Resolver struct {
+	cfg    *config.SecretsConfig
+	client *http.Client
+	getenv func(string) string
+	now    func() time.Time
+}
+
+// NewResolver creates a resolver for a secrets block.
+func NewResolver(cfg *config.SecretsConfig) *Resolver {
+	return &Resolver{
+		cfg:    cfg,
+		client: &http.Client{Timeout: requestTimeout},
+		getenv: os.Getenv,
+		now:    time.Now,
+	}
+}
+
+// ResolveAll returns every declared secret's value, keyed by secret name.
+// It fails on the first secret that can't be resolved.
+func (r *Resolver) ResolveAll(ctx context.Context) (map[string]string, error) {
+	values := make(map[string]string, len(r.cfg.Secrets))
+	for i := range r.cfg.Secrets {
+		s := &r.cfg.Secrets[i]
+		v, err := r.Resolve(ctx, s)
+		if err != nil {
+			return nil, fmt.Errorf("secret '%s': %w", s.Name, err)
+		}
+		values[s.Name] = v
+	}
+	return values, nil
+}
+
+// Resolve fetches a single secret from its backend.
+func (r *Resolver) Resolve(ctx context.Context, s *config.Secret) (string, error) {
+	source, ref := s.Source()
+	switch source {
+	case config.SecretSourceEnv:
+		v := r.getenv(ref)
+		if v == "" {
+			return "", fmt.Errorf("environment variable %s is not set", ref)
+		}
+		return v, nil
+	case config.SecretSourceFile:
+		data, err := os.ReadFile(ref)
+		if err != nil {
+			return "", err
+		}
+		// Secret files usually end in a newline that isn't part of the value
+		return strings.TrimRight(string(data), "\r\n"), nil
+	case config.SecretSourceVault:
+		return r.resolveVault(ctx, ref)
+	case config.SecretSourceAWS:
+		return r.resolveAWS(ctx, ref)
+	default:
+		return "", fmt.Errorf("no source set")
+	}
+}
+
+// pickField returns data[field], or the only value when field is empty and
+// data has exactly one entry. Values must be strings.
+func pickField(data map[string]any, field string) (string, error) {
+	if field == "" {
+		if len(data) != 1 {
+			keys := make([]string, 0, len(data))
+			for k := range data {
+				keys = append(keys, k)
+			}
+			return "", fmt.Errorf("secret has %d fields, pick one with #field (fields: %s)", len(data), strings.Join(keys, ", "))
+		}
+		for k := range data {
+			field = k
+		}
+	}
+	v, ok := data[field]
+	if !ok {
+		return "", fmt.Errorf("field %q not found", field)
+	}
+	s, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("field %q is not a string", field)
+	}
+	return s, nil
+}
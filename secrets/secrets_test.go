@@ -0,0 +1,143 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"squadron/config"
+)
+
+func testResolver(cfg *config.SecretsConfig, env map[string]string) *Resolver {
+	r := NewResolver(cfg)
+	r.getenv = func(k string) string { return env[k] }
+	r.now = func() time.Time { return time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC) }
+	return r
+}
+
+func TestResolveEnvAndFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "db_password")
+	if err := os.WriteFile(path, []byte("hunter2\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.SecretsConfig{Secrets: []config.Secret{
+		{Name: "token", Env: "TOKEN"},
+		{Name: "db", File: path},
+	}}
+
+	values, err := testResolver(cfg, map[string]string{"TOKEN": "abc"}).ResolveAll(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if values["token"] != "abc" || values["db"] != "hunter2" {
+		t.Errorf("values = %v", values)
+	}
+
+	_, err = testResolver(cfg, nil).ResolveAll(context.Background())
+	if err == nil || err.Error() != "secret 'token': environment variable TOKEN is not set" {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestResolveVault(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "s.root" || r.Header.Get("X-Vault-Namespace") != "team" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/stripe": // KV v2
+			io.WriteString(w, `{"data":{"data":{"api_key":"sk_live","webhook":"whsec"},"metadata":{"version":3}}}`)
+		case "/v1/kv/github": // KV v1
+			io.WriteString(w, `{"data":{"token":"ghp_x"}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	cfg := &config.SecretsConfig{
+		Vault: &config.SecretsVault{Address: srv.URL, Namespace: "team"},
+		Secrets: []config.Secret{
+			{Name: "stripe", Vault: "secret/data/stripe#api_key"},
+			{Name: "github", Vault: "kv/github"},
+		},
+	}
+	r := testResolver(cfg, map[string]string{"VAULT_TOKEN": "s.root"})
+	values, err := r.ResolveAll(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if values["stripe"] != "sk_live" || values["github"] != "ghp_x" {
+		t.Errorf("values = %v", values)
+	}
+
+	_, err = r.Resolve(context.Background(), &config.Secret{Name: "s", Vault: "secret/data/stripe"})
+	if err == nil || !strings.Contains(err.Error(), "secret has 2 fields, pick one with #field") {
+		t.Errorf("err = %v", err)
+	}
+	_, err = r.Resolve(context.Background(), &config.Secret{Name: "s", Vault: "secret/data/missing#x"})
+	if err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestResolveAWS(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKID/20260301/eu-west-1/secretsmanager/aws4_request, SignedHeaders=content-type;host;x-amz-date;x-amz-security-token;x-amz-target, Signature=") {
+			t.Errorf("Authorization = %q", auth)
+		}
+		if r.Header.Get("X-Amz-Date") != "20260301T120000Z" || r.Header.Get("X-Amz-Security-Token") != "session" {
+			t.Errorf("headers = %v", r.Header)
+		}
+		if r.Header.Get("X-Amz-Target") != "secretsmanager.GetSecretValue" {
+			t.Errorf("X-Amz-Target = %q", r.Header.Get("X-Amz-Target"))
+		}
+		var in struct{ SecretId string }
+		json.NewDecoder(r.Body).Decode(&in)
+		switch in.SecretId {
+		case "prod/openai":
+			io.WriteString(w, `{"Name":"prod/openai","SecretString":"{\"api_key\":\"sk-openai\"}"}`)
+		case "prod/plain":
+			io.WriteString(w, `{"Name":"prod/plain","SecretString":"plain-value"}`)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			io.WriteString(w, `{"__type":"ResourceNotFoundException","message":"Secrets Manager can't find the specified secret."}`)
+		}
+	}))
+	defer srv.Close()
+
+	cfg := &config.SecretsConfig{
+		AWS: &config.SecretsAWS{Endpoint: srv.URL},
+		Secrets: []config.Secret{
+			{Name: "openai", AWS: "prod/openai#api_key"},
+			{Name: "plain", AWS: "prod/plain"},
+		},
+	}
+	r := testResolver(cfg, map[string]string{
+		"AWS_REGION":            "eu-west-1",
+		"AWS_ACCESS_KEY_ID":     "AKID",
+		"AWS_SECRET_ACCESS_KEY": "secret",
+		"AWS_SESSION_TOKEN":     "session",
+	})
+	values, err := r.ResolveAll(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if values["openai"] != "sk-openai" || values["plain"] != "plain-value" {
+		t.Errorf("values = %v", values)
+	}
+
+	_, err = r.Resolve(context.Background(), &config.Secret{Name: "gone", AWS: "prod/gone"})
+	if err == nil || !strings.Contains(err.Error(), "ResourceNotFoundException") {
+		t.Errorf("err = %v", err)
+	}
+}
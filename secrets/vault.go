@@ -0,0 +1,82 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"squadron/config"
+)
+
+// resolveVault reads a secret from Vault's HTTP API. ref is the full API
+// path under /v1 plus an optional #field, e.g. "secret/data/stripe#api_key".
+// Both KV v1 and KV v2 (whose payload is nested under data.data) work.
+func (r *Resolver) resolveVault(ctx context.Context, ref string) (string, error) {
+	var vc config.SecretsVault
+	if r.cfg.Vault != nil {
+		vc = *r.cfg.Vault
+	}
+	address := firstNonEmpty(vc.Address, r.getenv("VAULT_ADDR"))
+	token := firstNonEmpty(vc.Token, r.getenv("VAULT_TOKEN"))
+	namespace := firstNonEmpty(vc.Namespace, r.getenv("VAULT_NAMESPACE"))
+	if address == "" {
+		return "", fmt.Errorf("vault address not set (secrets.vault.address or VAULT_ADDR)")
+	}
+	if token == "" {
+		return "", fmt.Errorf("vault token not set (secrets.vault.token or VAULT_TOKEN)")
+	}
+
+	path, field := config.SplitSecretRef(ref)
+	url := strings.TrimRight(address, "/") + "/v1/" + strings.TrimLeft(path, "/")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if namespace != "" {
+		req.Header.Set("X-Vault-Namespace", namespace)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("vault: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("vault: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("vault: %s returned %s", path, resp.Status)
+	}
+
+	var payload struct {
+		Data map[string]any `json:"data"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return "", fmt.Errorf("vault: invalid response: %w", err)
+	}
+	data := payload.Data
+	if nested, ok := data["data"].(map[string]any); ok {
+		if _, hasMeta := data["metadata"]; hasMeta {
+			data = nested
+		}
+	}
+	v, err := pickField(data, field)
+	if err != nil {
+		return "", fmt.Errorf("vault: %s: %w", path, err)
+	}
+	return v, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
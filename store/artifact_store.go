@@ -0,0 +1,103 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// SQLiteArtifactStore implements ArtifactStore with records in SQLite and
+// content in a BlobStore.
+type SQLiteArtifactStore struct {
+	db    *sql.DB
+	blobs BlobStore
+}
+
+func (s *SQLiteArtifactStore) PutArtifact(a ArtifactRecord, r io.Reader) (*ArtifactRecord, error) {
+	digest, size, err := putBlob(s.blobs, r)
+	if err != nil {
+		return nil, err
+	}
+	a.ID, a.Digest, a.Size = generateID(), digest, size
+	createdAt := tsNow()
+	_, err = s.db.Exec(
+		`INSERT INTO artifacts (id, mission_id, task_name, author, name, media_type, digest, size, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		a.ID, a.MissionID, a.TaskName, a.Author, a.Name, a.MediaType, a.Digest, a.Size, createdAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("record artifact: %w", err)
+	}
+	a.CreatedAt, _ = tsParse(createdAt)
+	return &a, nil
+}
+
+func (s *SQLiteArtifactStore) GetArtifact(id string) (*ArtifactRecord, error) {
+	row := s.db.QueryRow(
+		`SELECT id, mission_id, task_name, author, name, media_type, digest, size, created_at FROM artifacts WHERE id = ?`,
+		id,
+	)
+	return scanArtifact(row)
+}
+
+func (s *SQLiteArtifactStore) OpenArtifact(id string) (io.ReadCloser, error) {
+	return openArtifact(s, s.blobs, id)
+}
+
+func (s *SQLiteArtifactStore) ListArtifacts(missionID string) ([]ArtifactRecord, error) {
+	rows, err := s.db.Query(
+		`SELECT id, mission_id, task_name, author, name, media_type, digest, size, created_at FROM artifacts WHERE mission_id = ? ORDER BY created_at, id`,
+		missionID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return scanArtifacts(rows)
+}
+
+// artifactRowScanner is the common interface over *sql.Row and *sql.Rows.
+type artifactRowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanArtifactRow(row artifactRowScanner) (*ArtifactRecord, error) {
+	var a ArtifactRecord
+	var createdAtStr string
+	if err := row.Scan(&a.ID, &a.MissionID, &a.TaskName, &a.Author, &a.Name, &a.MediaType, &a.Digest, &a.Size, &createdAtStr); err != nil {
+		return nil, err
+	}
+	a.CreatedAt, _ = tsParse(createdAtStr)
+	return &a, nil
+}
+
+func scanArtifact(row *sql.Row) (*ArtifactRecord, error) {
+	a, err := scanArtifactRow(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return a, err
+}
+
+func scanArtifacts(rows *sql.Rows) ([]ArtifactRecord, error) {
+	defer rows.Close()
+	var out []ArtifactRecord
+	for rows.Next() {
+		a, err := scanArtifactRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *a)
+	}
+	return out, rows.Err()
+}
+
+func openArtifact(s ArtifactStore, blobs BlobStore, id string) (io.ReadCloser, error) {
+	a, err := s.GetArtifact(id)
+	if err != nil {
+		return nil, err
+	}
+	if a == nil {
+		return nil, fmt.Errorf("artifact %s not found", id)
+	}
+	return blobs.OpenBlob(a.Digest)
+}
@@ -0,0 +1,55 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+)
+
+// PgArtifactStore implements ArtifactStore with records in Postgres and
+// content in a BlobStore.
+type PgArtifactStore struct {
+	db    *sql.DB
+	blobs BlobStore
+}
+
+func (s *PgArtifactStore) PutArtifact(a ArtifactRecord, r io.Reader) (*ArtifactRecord, error) {
+	digest, size, err := putBlob(s.blobs, r)
+	if err != nil {
+		return nil, err
+	}
+	a.ID, a.Digest, a.Size = generateID(), digest, size
+	createdAt := tsNow()
+	_, err = s.db.Exec(
+		`INSERT INTO artifacts (id, mission_id, task_name, author, name, media_type, digest, size, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		a.ID, a.MissionID, a.TaskName, a.Author, a.Name, a.MediaType, a.Digest, a.Size, createdAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("record artifact: %w", err)
+	}
+	a.CreatedAt, _ = tsParse(createdAt)
+	return &a, nil
+}
+
+func (s *PgArtifactStore) GetArtifact(id string) (*ArtifactRecord, error) {
+	row := s.db.QueryRow(
+		`SELECT id, mission_id, task_name, author, name, media_type, digest, size, created_at FROM artifacts WHERE id = $1`,
+		id,
+	)
+	return scanArtifact(row)
+}
+
+func (s *PgArtifactStore) OpenArtifact(id string) (io.ReadCloser, error) {
+	return openArtifact(s, s.blobs, id)
+}
+
+func (s *PgArtifactStore) ListArtifacts(missionID string) ([]ArtifactRecord, error) {
+	rows, err := s.db.Query(
+		`SELECT id, mission_id, task_name, author, name, media_type, digest, size, created_at FROM artifacts WHERE mission_id = $1 ORDER BY created_at, id`,
+		missionID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return scanArtifacts(rows)
+}
@@ -0,0 +1,83 @@
+package store_test
+
+import (
+	"io"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"squadron/store"
+)
+
+var _ = Describe("SQLite ArtifactStore", func() {
+	var (
+		bundle  *store.Bundle
+		cleanup func()
+	)
+
+	BeforeEach(func() {
+		bundle, cleanup = newSQLiteBundle()
+	})
+
+	AfterEach(func() {
+		cleanup()
+	})
+
+	read := func(id string) string {
+		r, err := bundle.Artifacts.OpenArtifact(id)
+		Expect(err).NotTo(HaveOccurred())
+		defer r.Close()
+		b, err := io.ReadAll(r)
+		Expect(err).NotTo(HaveOccurred())
+		return string(b)
+	}
+
+	It("stores content by digest and records each registration", func() {
+		missionID, _ := seedMissionAndTask(bundle)
+
+		a, err := bundle.Artifacts.PutArtifact(store.ArtifactRecord{
+			MissionID: missionID, TaskName: "render", Author: "designer", Name: "chart.svg", MediaType: "image/svg+xml",
+		}, strings.NewReader("<svg/>"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(a.ID).NotTo(BeEmpty())
+		Expect(a.Size).To(Equal(int64(6)))
+		Expect(a.Digest).To(HaveLen(64))
+
+		// Same content, new record, same blob
+		b, err := bundle.Artifacts.PutArtifact(store.ArtifactRecord{
+			MissionID: missionID, TaskName: "render", Author: "commander", Name: "copy.svg", MediaType: "image/svg+xml",
+		}, strings.NewReader("<svg/>"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(b.ID).NotTo(Equal(a.ID))
+		Expect(b.Digest).To(Equal(a.Digest))
+
+		Expect(read(a.ID)).To(Equal("<svg/>"))
+		Expect(read(b.ID)).To(Equal("<svg/>"))
+
+		got, err := bundle.Artifacts.GetArtifact(a.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(got.Name).To(Equal("chart.svg"))
+		Expect(got.Author).To(Equal("designer"))
+
+		list, err := bundle.Artifacts.ListArtifacts(missionID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(list).To(HaveLen(2))
+	})
+
+	It("returns nil for an unknown artifact and fails to open it", func() {
+		got, err := bundle.Artifacts.GetArtifact("missing")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(got).To(BeNil())
+		_, err = bundle.Artifacts.OpenArtifact("missing")
+		Expect(err).To(MatchError(ContainSubstring("artifact missing not found")))
+	})
+
+	It("keeps content in a replacement blob store", func() {
+		missionID, _ := seedMissionAndTask(bundle)
+		bundle.SetBlobStore(store.NewDiskBlobStore(GinkgoT().TempDir()))
+		a, err := bundle.Artifacts.PutArtifact(store.ArtifactRecord{MissionID: missionID, Name: "notes.txt"}, strings.NewReader("hello"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(read(a.ID)).To(Equal("hello"))
+	})
+})
@@ -0,0 +1,117 @@
+package store
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// BlobStore holds artifact content addressed by its SHA-256 digest (hex).
+type BlobStore interface {
+	// PutBlob stores the content read from r under digest. Storing a digest
+	// that already exists is a no-op.
+	PutBlob(digest string, r io.Reader) error
+	// OpenBlob opens the content stored under digest.
+	OpenBlob(digest string) (io.ReadCloser, error)
+	// DeleteBlob removes the content stored under digest. Deleting a digest
+	// that doesn't exist is a no-op.
+	DeleteBlob(digest string) error
+}
+
+// DiskBlobStore keeps blobs in a directory on the local disk, under
+// <dir>/<first two hex chars>/<digest>.
+type DiskBlobStore struct {
+	dir string
+}
+
+// NewDiskBlobStore stores blobs under dir, which is created on first write.
+func NewDiskBlobStore(dir string) *DiskBlobStore {
+	return &DiskBlobStore{dir: dir}
+}
+
+func (s *DiskBlobStore) path(digest string) (string, error) {
+	if len(digest) != sha256.Size*2 || strings.Trim(digest, "0123456789abcdef") != "" {
+		return "", fmt.Errorf("invalid blob digest %q", digest)
+	}
+	return filepath.Join(s.dir, digest[:2], digest), nil
+}
+
+func (s *DiskBlobStore) PutBlob(digest string, r io.Reader) error {
+	path, err := s.path(digest)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("create blob directory: %w", err)
+	}
+	// Write to a temp file and rename, so a reader never sees a partial blob
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".blob-*")
+	if err != nil {
+		return fmt.Errorf("create blob: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write blob: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write blob: %w", err)
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (s *DiskBlobStore) OpenBlob(digest string) (io.ReadCloser, error) {
+	path, err := s.path(digest)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("blob %s not found", digest)
+	}
+	return f, err
+}
+
+func (s *DiskBlobStore) DeleteBlob(digest string) error {
+	path, err := s.path(digest)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("delete blob: %w", err)
+	}
+	return nil
+}
+
+// putBlob spools r to a temp file to learn its digest and size, then stores
+// it in blobs.
+func putBlob(blobs BlobStore, r io.Reader) (digest string, size int64, err error) {
+	tmp, err := os.CreateTemp("", "squadron-artifact-*")
+	if err != nil {
+		return "", 0, fmt.Errorf("spool artifact: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	h := sha256.New()
+	size, err = io.Copy(io.MultiWriter(tmp, h), r)
+	if err != nil {
+		return "", 0, fmt.Errorf("spool artifact: %w", err)
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return "", 0, fmt.Errorf("spool artifact: %w", err)
+	}
+	digest = hex.EncodeToString(h.Sum(nil))
+	if err := blobs.PutBlob(digest, tmp); err != nil {
+		return "", 0, err
+	}
+	return digest, size, nil
+}
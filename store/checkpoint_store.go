@@ -0,0 +1,51 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+)
+
+// SQLiteCheckpointStore implements CheckpointStore backed by SQLite.
+type SQLiteCheckpointStore struct {
+	db *sql.DB
+}
+
+func (s *SQLiteCheckpointStore) SaveCheckpoint(cp SessionCheckpoint) error {
+	_, err := s.db.Exec(
+		`INSERT INTO session_checkpoints (session_id, turn, messages_json, results_json, dataset_position, created_at) VALUES (?, ?, ?, ?, ?, ?)
+		 ON CONFLICT(session_id) DO UPDATE SET turn = excluded.turn, messages_json = excluded.messages_json, results_json = excluded.results_json, dataset_position = excluded.dataset_position, created_at = excluded.created_at`,
+		cp.SessionID, cp.Turn, cp.MessagesJSON, cp.ResultsJSON, cp.DatasetPosition, tsNow(),
+	)
+	return err
+}
+
+func (s *SQLiteCheckpointStore) GetCheckpoint(sessionID string) (*SessionCheckpoint, error) {
+	row := s.db.QueryRow(
+		`SELECT session_id, turn, messages_json, results_json, dataset_position, created_at FROM session_checkpoints WHERE session_id = ?`,
+		sessionID,
+	)
+	return scanCheckpoint(row)
+}
+
+func (s *SQLiteCheckpointStore) DeleteCheckpoint(sessionID string) error {
+	_, err := s.db.Exec(`DELETE FROM session_checkpoints WHERE session_id = ?`, sessionID)
+	return err
+}
+
+func scanCheckpoint(row *sql.Row) (*SessionCheckpoint, error) {
+	var cp SessionCheckpoint
+	var position sql.NullInt64
+	var createdAtStr string
+	if err := row.Scan(&cp.SessionID, &cp.Turn, &cp.MessagesJSON, &cp.ResultsJSON, &position, &createdAtStr); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if position.Valid {
+		p := int(position.Int64)
+		cp.DatasetPosition = &p
+	}
+	cp.CreatedAt, _ = tsParse(createdAtStr)
+	return &cp, nil
+}
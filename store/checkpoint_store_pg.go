@@ -0,0 +1,30 @@
+package store
+
+import "database/sql"
+
+// PgCheckpointStore implements CheckpointStore backed by Postgres.
+type PgCheckpointStore struct {
+	db *sql.DB
+}
+
+func (s *PgCheckpointStore) SaveCheckpoint(cp SessionCheckpoint) error {
+	_, err := s.db.Exec(
+		`INSERT INTO session_checkpoints (session_id, turn, messages_json, results_json, dataset_position, created_at) VALUES ($1, $2, $3, $4, $5, $6)
+		 ON CONFLICT (session_id) DO UPDATE SET turn = EXCLUDED.turn, messages_json = EXCLUDED.messages_json, results_json = EXCLUDED.results_json, dataset_position = EXCLUDED.dataset_position, created_at = EXCLUDED.created_at`,
+		cp.SessionID, cp.Turn, cp.MessagesJSON, cp.ResultsJSON, cp.DatasetPosition, tsNow(),
+	)
+	return err
+}
+
+func (s *PgCheckpointStore) GetCheckpoint(sessionID string) (*SessionCheckpoint, error) {
+	row := s.db.QueryRow(
+		`SELECT session_id, turn, messages_json, results_json, dataset_position, created_at FROM session_checkpoints WHERE session_id = $1`,
+		sessionID,
+	)
+	return scanCheckpoint(row)
+}
+
+func (s *PgCheckpointStore) DeleteCheckpoint(sessionID string) error {
+	_, err := s.db.Exec(`DELETE FROM session_checkpoints WHERE session_id = $1`, sessionID)
+	return err
+}
@@ -0,0 +1,60 @@
+package store_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"squadron/store"
+)
+
+var _ = Describe("SQLite CheckpointStore", func() {
+	var (
+		bundle  *store.Bundle
+		cleanup func()
+	)
+
+	BeforeEach(func() {
+		bundle, cleanup = newSQLiteBundle()
+	})
+
+	AfterEach(func() {
+		cleanup()
+	})
+
+	It("returns nil for a session without a checkpoint", func() {
+		cp, err := bundle.Checkpoints.GetCheckpoint("missing")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cp).To(BeNil())
+	})
+
+	It("keeps only the latest checkpoint per session and deletes it", func() {
+		_, taskID := seedMissionAndTask(bundle)
+		sessionID, err := bundle.Sessions.CreateSession(taskID, "commander", "", "gpt-4", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(bundle.Checkpoints.SaveCheckpoint(store.SessionCheckpoint{
+			SessionID: sessionID, Turn: 5, MessagesJSON: `[1]`, ResultsJSON: `{}`,
+		})).To(Succeed())
+		position := 3
+		Expect(bundle.Checkpoints.SaveCheckpoint(store.SessionCheckpoint{
+			SessionID: sessionID, Turn: 10, MessagesJSON: `[2]`, ResultsJSON: `{"seq":1}`, DatasetPosition: &position,
+		})).To(Succeed())
+
+		cp, err := bundle.Checkpoints.GetCheckpoint(sessionID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cp).NotTo(BeNil())
+		Expect(cp.Turn).To(Equal(10))
+		Expect(cp.MessagesJSON).To(Equal(`[2]`))
+		Expect(cp.ResultsJSON).To(Equal(`{"seq":1}`))
+		Expect(cp.DatasetPosition).NotTo(BeNil())
+		Expect(*cp.DatasetPosition).To(Equal(3))
+		Expect(cp.CreatedAt).To(BeTemporally("~", time.Now(), time.Minute))
+
+		Expect(bundle.Checkpoints.DeleteCheckpoint(sessionID)).To(Succeed())
+		cp, err = bundle.Checkpoints.GetCheckpoint(sessionID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cp).To(BeNil())
+	})
+})
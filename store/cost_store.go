@@ -12,12 +12,12 @@ type SQLiteCostStore struct {
 
 func (s *SQLiteCostStore) StoreTurnCost(cost TurnCostRecord) error {
 	_, err := s.db.Exec(
-		`INSERT INTO turn_costs (id, mission_id, task_id, session_id, mission_name, task_name, entity, model,
+		`INSERT INTO turn_costs (id, mission_id, task_id, session_id, mission_name, task_name, iteration_index, entity, model,
 		 input_tokens, output_tokens, cache_write_tokens, cache_read_tokens,
 		 input_cost, output_cost, cache_read_cost, cache_write_cost, total_cost,
-		 duration_ms, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		 duration_ms, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		generateID(), cost.MissionID, cost.TaskID, cost.SessionID,
-		cost.MissionName, cost.TaskName, cost.Entity, cost.Model,
+		cost.MissionName, cost.TaskName, cost.IterationIndex, cost.Entity, cost.Model,
 		cost.InputTokens, cost.OutputTokens, cost.CacheWriteTokens, cost.CacheReadTokens,
 		cost.InputCost, cost.OutputCost, cost.CacheReadCost, cost.CacheWriteCost, cost.TotalCost,
 		cost.DurationMs, tsNow(),
@@ -27,7 +27,7 @@ func (s *SQLiteCostStore) StoreTurnCost(cost TurnCostRecord) error {
 
 func (s *SQLiteCostStore) GetCostsByMission(missionID string) ([]TurnCostRecord, error) {
 	rows, err := s.db.Query(
-		`SELECT id, mission_id, task_id, session_id, mission_name, task_name, entity, model,
+		`SELECT id, mission_id, task_id, session_id, mission_name, task_name, iteration_index, entity, model,
 		 input_tokens, output_tokens, cache_write_tokens, cache_read_tokens,
 		 input_cost, output_cost, cache_read_cost, cache_write_cost, total_cost,
 		 duration_ms, created_at FROM turn_costs WHERE mission_id = ? ORDER BY created_at`,
@@ -149,14 +149,19 @@ func scanTurnCosts(rows *sql.Rows) ([]TurnCostRecord, error) {
 	for rows.Next() {
 		var r TurnCostRecord
 		var createdAtStr string
+		var iterationIndex sql.NullInt64
 		if err := rows.Scan(&r.ID, &r.MissionID, &r.TaskID, &r.SessionID,
-			&r.MissionName, &r.TaskName, &r.Entity, &r.Model,
+			&r.MissionName, &r.TaskName, &iterationIndex, &r.Entity, &r.Model,
 			&r.InputTokens, &r.OutputTokens, &r.CacheWriteTokens, &r.CacheReadTokens,
 			&r.InputCost, &r.OutputCost, &r.CacheReadCost, &r.CacheWriteCost, &r.TotalCost,
 			&r.DurationMs, &createdAtStr); err != nil {
 			return nil, err
 		}
 		r.CreatedAt, _ = tsParse(createdAtStr)
+		if iterationIndex.Valid {
+			idx := int(iterationIndex.Int64)
+			r.IterationIndex = &idx
+		}
 		results = append(results, r)
 	}
 	return results, nil
@@ -0,0 +1,58 @@
+package store_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"squadron/store"
+)
+
+var _ = Describe("SQLite CostStore", func() {
+	var (
+		bundle  *store.Bundle
+		cleanup func()
+	)
+
+	BeforeEach(func() {
+		bundle, cleanup = newSQLiteBundle()
+	})
+
+	AfterEach(func() {
+		cleanup()
+	})
+
+	It("round-trips turn costs with their iteration index", func() {
+		missionID, taskID := seedMissionAndTask(bundle)
+		idx := 2
+		sessionID, err := bundle.Sessions.CreateSession(taskID, "commander", "", "claude-sonnet-4", &idx)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(bundle.Costs.StoreTurnCost(store.TurnCostRecord{
+			MissionID: missionID, TaskID: taskID, SessionID: sessionID,
+			MissionName: "test-mission", TaskName: "test-task[2]", IterationIndex: &idx,
+			Entity: "commander", Model: "claude-sonnet-4",
+			InputTokens: 120, OutputTokens: 30, CacheReadTokens: 40, CacheWriteTokens: 10,
+			TotalCost: 0.0125,
+		})).To(Succeed())
+		Expect(bundle.Costs.StoreTurnCost(store.TurnCostRecord{
+			MissionID: missionID, TaskID: taskID, SessionID: sessionID,
+			MissionName: "test-mission", TaskName: "test-task",
+			Entity: "commander", Model: "claude-sonnet-4", InputTokens: 5,
+		})).To(Succeed())
+
+		costs, err := bundle.Costs.GetCostsByMission(missionID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(costs).To(HaveLen(2))
+		byTask := map[string]store.TurnCostRecord{}
+		for _, c := range costs {
+			byTask[c.TaskName] = c
+		}
+		iter := byTask["test-task[2]"]
+		Expect(iter.IterationIndex).To(HaveValue(Equal(2)))
+		Expect(iter.InputTokens).To(Equal(120))
+		Expect(iter.CacheReadTokens).To(Equal(40))
+		Expect(iter.CacheWriteTokens).To(Equal(10))
+		Expect(iter.TotalCost).To(BeNumerically("~", 0.0125))
+		Expect(byTask["test-task"].IterationIndex).To(BeNil())
+	})
+})
@@ -8,8 +8,15 @@ import (
 	"squadron/config"
 )
 
+// DefaultArtifactsPath is where a Postgres bundle keeps artifact content
+// when the storage block sets no artifacts_path. A SQLite bundle defaults to
+// an "artifacts" directory next to its database file.
+const DefaultArtifactsPath = ".squadron/artifacts"
+
 // NewBundle creates a store Bundle based on the storage configuration
 func NewBundle(cfg *config.StorageConfig) (*Bundle, error) {
+	var b *Bundle
+	var err error
 	switch cfg.Backend {
 	case "sqlite":
 		// Ensure directory exists
@@ -17,15 +24,55 @@ func NewBundle(cfg *config.StorageConfig) (*Bundle, error) {
 		if err := os.MkdirAll(dir, 0755); err != nil {
 			return nil, fmt.Errorf("create storage directory %s: %w", dir, err)
 		}
-		return NewSQLiteBundle(cfg.Path)
+		b, err = NewSQLiteBundle(cfg.Path)
 
 	case "postgres":
 		if cfg.ConnString == "" {
 			return nil, fmt.Errorf("postgres backend requires conn_string to be set")
 		}
-		return NewPostgresBundle(cfg.ConnString)
+		b, err = NewPostgresBundle(cfg.ConnString)
 
 	default:
 		return nil, fmt.Errorf("unknown storage backend: %s (expected 'sqlite' or 'postgres')", cfg.Backend)
 	}
+	if err != nil {
+		return nil, err
+	}
+	if cfg.ArtifactsPath != "" {
+		b.SetBlobStore(NewDiskBlobStore(cfg.ArtifactsPath))
+	}
+	if cfg.ObjectStore != nil {
+		objects, err := NewS3ObjectStore(S3Options{
+			Endpoint:        cfg.ObjectStore.Endpoint,
+			Region:          cfg.ObjectStore.Region,
+			Bucket:          cfg.ObjectStore.Bucket,
+			Prefix:          cfg.ObjectStore.Prefix,
+			AccessKeyID:     cfg.ObjectStore.AccessKeyID,
+			SecretAccessKey: cfg.ObjectStore.SecretAccessKey,
+		})
+		if err != nil {
+			b.Close()
+			return nil, err
+		}
+		b.SetObjectStore(objects, cfg.ObjectStore.OffloadThreshold)
+	}
+	return b, nil
+}
+
+// SetObjectStore moves the bundle's large payloads to objects: artifact
+// content, and tool results and images over threshold bytes.
+func (b *Bundle) SetObjectStore(objects ObjectStore, threshold int) {
+	b.Objects = objects
+	b.SetBlobStore(NewObjectBlobStore(objects))
+	b.Sessions = &offloadingSessionStore{SessionStore: b.Sessions, objects: objects, threshold: threshold}
+}
+
+// SetBlobStore replaces where the bundle's artifact content is kept.
+func (b *Bundle) SetBlobStore(blobs BlobStore) {
+	switch s := b.Artifacts.(type) {
+	case *SQLiteArtifactStore:
+		s.blobs = blobs
+	case *PgArtifactStore:
+		s.blobs = blobs
+	}
 }
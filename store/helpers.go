@@ -22,6 +22,13 @@ func tsFromPtr(t *time.Time) *string {
 	return &s
 }
 
+// missionFinished reports whether a mission status is terminal and should
+// stamp finished_at. "budget_exceeded" is a failure variant written by the
+// runner when a budget breach ends the mission.
+func missionFinished(status string) bool {
+	return status == "completed" || status == "failed" || status == "budget_exceeded"
+}
+
 // tsParse parses an ISO-8601 timestamp string back to time.Time.
 // Falls back to second-precision format if millisecond parse fails.
 func tsParse(s string) (time.Time, error) {
@@ -44,6 +51,28 @@ func tsParseNull(s sql.NullString) (*time.Time, error) {
 	return &t, nil
 }
 
+// scanItemStatuses reads dataset_item_status rows (task_id, item_index,
+// item_id, status, error, updated_at); shared by the SQLite and Postgres
+// stores.
+func scanItemStatuses(rows *sql.Rows) ([]DatasetItemStatus, error) {
+	var statuses []DatasetItemStatus
+	for rows.Next() {
+		var st DatasetItemStatus
+		var itemID, errMsg sql.NullString
+		var updatedAt string
+		if err := rows.Scan(&st.TaskID, &st.Index, &itemID, &st.Status, &errMsg, &updatedAt); err != nil {
+			return nil, err
+		}
+		st.ItemID = itemID.String
+		if errMsg.Valid {
+			st.Error = &errMsg.String
+		}
+		st.UpdatedAt, _ = tsParse(updatedAt)
+		statuses = append(statuses, st)
+	}
+	return statuses, rows.Err()
+}
+
 // =============================================================================
 // cty conversion helpers
 // =============================================================================
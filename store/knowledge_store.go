@@ -0,0 +1,81 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+)
+
+// SQLiteKnowledgeEntryStore implements KnowledgeEntryStore backed by SQLite.
+type SQLiteKnowledgeEntryStore struct {
+	db *sql.DB
+}
+
+func (s *SQLiteKnowledgeEntryStore) AddKnowledgeEntry(entry KnowledgeEntryRecord) (string, error) {
+	tagsJSON, embeddingJSON, err := marshalKnowledgeEntry(entry)
+	if err != nil {
+		return "", err
+	}
+	id := generateID()
+	_, err = s.db.Exec(
+		`INSERT INTO knowledge_entries (id, mission_id, task_name, author, content, tags_json, model, embedding_json, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		id, entry.MissionID, entry.TaskName, entry.Author, entry.Content, tagsJSON, entry.Model, embeddingJSON, tsNow(),
+	)
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (s *SQLiteKnowledgeEntryStore) GetKnowledgeEntries(missionID string) ([]KnowledgeEntryRecord, error) {
+	rows, err := s.db.Query(
+		`SELECT id, mission_id, task_name, author, content, tags_json, model, embedding_json, created_at
+		 FROM knowledge_entries WHERE mission_id = ? ORDER BY created_at`,
+		missionID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanKnowledgeEntries(rows)
+}
+
+func marshalKnowledgeEntry(entry KnowledgeEntryRecord) (sql.NullString, string, error) {
+	var tagsJSON sql.NullString
+	if len(entry.Tags) > 0 {
+		b, err := json.Marshal(entry.Tags)
+		if err != nil {
+			return tagsJSON, "", err
+		}
+		tagsJSON = sql.NullString{String: string(b), Valid: true}
+	}
+	b, err := json.Marshal(entry.Embedding)
+	if err != nil {
+		return tagsJSON, "", err
+	}
+	return tagsJSON, string(b), nil
+}
+
+func scanKnowledgeEntries(rows *sql.Rows) ([]KnowledgeEntryRecord, error) {
+	var results []KnowledgeEntryRecord
+	for rows.Next() {
+		var r KnowledgeEntryRecord
+		var tagsJSON sql.NullString
+		var embeddingJSON, createdAtStr string
+		if err := rows.Scan(&r.ID, &r.MissionID, &r.TaskName, &r.Author, &r.Content,
+			&tagsJSON, &r.Model, &embeddingJSON, &createdAtStr); err != nil {
+			return nil, err
+		}
+		if tagsJSON.Valid {
+			if err := json.Unmarshal([]byte(tagsJSON.String), &r.Tags); err != nil {
+				return nil, err
+			}
+		}
+		if err := json.Unmarshal([]byte(embeddingJSON), &r.Embedding); err != nil {
+			return nil, err
+		}
+		r.CreatedAt, _ = tsParse(createdAtStr)
+		results = append(results, r)
+	}
+	return results, rows.Err()
+}
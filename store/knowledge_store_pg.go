@@ -0,0 +1,38 @@
+package store
+
+import "database/sql"
+
+// PgKnowledgeEntryStore implements KnowledgeEntryStore backed by Postgres.
+type PgKnowledgeEntryStore struct {
+	db *sql.DB
+}
+
+func (s *PgKnowledgeEntryStore) AddKnowledgeEntry(entry KnowledgeEntryRecord) (string, error) {
+	tagsJSON, embeddingJSON, err := marshalKnowledgeEntry(entry)
+	if err != nil {
+		return "", err
+	}
+	id := generateID()
+	_, err = s.db.Exec(
+		`INSERT INTO knowledge_entries (id, mission_id, task_name, author, content, tags_json, model, embedding_json, created_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		id, entry.MissionID, entry.TaskName, entry.Author, entry.Content, tagsJSON, entry.Model, embeddingJSON, tsNow(),
+	)
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (s *PgKnowledgeEntryStore) GetKnowledgeEntries(missionID string) ([]KnowledgeEntryRecord, error) {
+	rows, err := s.db.Query(
+		`SELECT id, mission_id, task_name, author, content, tags_json, model, embedding_json, created_at
+		 FROM knowledge_entries WHERE mission_id = $1 ORDER BY created_at`,
+		missionID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanKnowledgeEntries(rows)
+}
@@ -0,0 +1,59 @@
+package store_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"squadron/store"
+)
+
+var _ = Describe("SQLite KnowledgeEntryStore", func() {
+	var (
+		bundle  *store.Bundle
+		cleanup func()
+	)
+
+	BeforeEach(func() {
+		bundle, cleanup = newSQLiteBundle()
+	})
+
+	AfterEach(func() {
+		cleanup()
+	})
+
+	It("round-trips entries with their tags and embeddings, scoped to the mission", func() {
+		missionID, _ := seedMissionAndTask(bundle)
+
+		id, err := bundle.Knowledge.AddKnowledgeEntry(store.KnowledgeEntryRecord{
+			MissionID: missionID, TaskName: "research", Author: "scout",
+			Content: "Acme raised prices 10% in March", Tags: []string{"pricing"},
+			Model: "text-embedding-3-small", Embedding: []float32{0.25, -0.5, 1},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(id).NotTo(BeEmpty())
+		_, err = bundle.Knowledge.AddKnowledgeEntry(store.KnowledgeEntryRecord{
+			MissionID: missionID, TaskName: "research", Author: "commander",
+			Content: "No tags here", Model: "lexical", Embedding: []float32{1},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		entries, err := bundle.Knowledge.GetKnowledgeEntries(missionID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(entries).To(HaveLen(2))
+		byAuthor := map[string]store.KnowledgeEntryRecord{}
+		for _, e := range entries {
+			byAuthor[e.Author] = e
+		}
+		scout := byAuthor["scout"]
+		Expect(scout.ID).To(Equal(id))
+		Expect(scout.TaskName).To(Equal("research"))
+		Expect(scout.Tags).To(Equal([]string{"pricing"}))
+		Expect(scout.Embedding).To(Equal([]float32{0.25, -0.5, 1}))
+		Expect(scout.CreatedAt.IsZero()).To(BeFalse())
+		Expect(byAuthor["commander"].Tags).To(BeNil())
+
+		other, err := bundle.Knowledge.GetKnowledgeEntries("other-mission")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(other).To(BeEmpty())
+	})
+})
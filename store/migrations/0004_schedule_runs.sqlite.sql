@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS schedule_runs (
+    mission_name TEXT NOT NULL,
+    source TEXT NOT NULL,
+    fire_at TEXT NOT NULL,
+    claimed_at TEXT NOT NULL,
+    PRIMARY KEY (mission_name, source, fire_at)
+);
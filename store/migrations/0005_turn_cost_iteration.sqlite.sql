@@ -0,0 +1 @@
+ALTER TABLE turn_costs ADD COLUMN iteration_index INTEGER;
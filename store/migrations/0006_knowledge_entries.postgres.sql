@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS knowledge_entries (
+    id TEXT PRIMARY KEY,
+    mission_id TEXT NOT NULL REFERENCES missions(id),
+    task_name TEXT NOT NULL,
+    author TEXT NOT NULL,
+    content TEXT NOT NULL,
+    tags_json TEXT,
+    model TEXT NOT NULL,
+    embedding_json TEXT NOT NULL,
+    created_at TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_knowledge_entries_mission ON knowledge_entries(mission_id);
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS session_checkpoints (
+    session_id TEXT PRIMARY KEY REFERENCES sessions(id),
+    turn INTEGER NOT NULL,
+    messages_json TEXT NOT NULL,
+    results_json TEXT NOT NULL,
+    dataset_position INTEGER,
+    created_at TEXT NOT NULL
+);
@@ -0,0 +1,10 @@
+ALTER TABLE task_outputs ADD COLUMN superseded_at TEXT;
+CREATE TABLE IF NOT EXISTS dataset_item_status (
+    task_id TEXT NOT NULL REFERENCES mission_tasks(id),
+    item_index INTEGER NOT NULL,
+    item_id TEXT,
+    status TEXT NOT NULL,
+    error TEXT,
+    updated_at TEXT NOT NULL,
+    PRIMARY KEY (task_id, item_index)
+);
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS commander_questions (
+    id TEXT PRIMARY KEY,
+    mission_id TEXT NOT NULL REFERENCES missions(id),
+    task_name TEXT NOT NULL,
+    iteration_index INTEGER,
+    seq INTEGER NOT NULL,
+    question TEXT NOT NULL,
+    answer TEXT,
+    created_at TEXT NOT NULL,
+    answered_at TEXT
+);
+CREATE INDEX IF NOT EXISTS idx_commander_questions_mission ON commander_questions(mission_id);
@@ -0,0 +1 @@
+ALTER TABLE mission_tasks ADD COLUMN error_code TEXT;
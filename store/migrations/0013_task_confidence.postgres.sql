@@ -0,0 +1 @@
+ALTER TABLE mission_tasks ADD COLUMN confidence REAL;
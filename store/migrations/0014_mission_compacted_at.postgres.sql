@@ -0,0 +1 @@
+ALTER TABLE missions ADD COLUMN compacted_at TEXT;
@@ -0,0 +1 @@
+ALTER TABLE tool_results ADD COLUMN is_error BOOLEAN NOT NULL DEFAULT FALSE;
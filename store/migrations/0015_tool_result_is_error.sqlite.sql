@@ -0,0 +1 @@
+ALTER TABLE tool_results ADD COLUMN is_error INTEGER NOT NULL DEFAULT 0;
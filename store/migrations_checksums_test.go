@@ -41,6 +41,32 @@ var migrationChecksums = map[string]string{
 	"0002_human_input_requests.postgres.sql": "65efa3f72f005b8b01424616115177da6bca365cabb1abc9824529755fe6e2ee",
 	"0003_session_message_parts.sqlite.sql":   "40371e8a46c410ca7c06324d998ab1db2177a1011f2e1d6a7ac9ab3ca04c973d",
 	"0003_session_message_parts.postgres.sql": "281190245e3a27f9cd4bf5feec9e973a5857a962d64e35caef8fef6440d6b8d9",
+	"0004_schedule_runs.sqlite.sql":           "4c7a6010d9584b7411a31520e9f79bc0b7a0fe68631453093b3e1f2dc8eea513",
+	"0004_schedule_runs.postgres.sql":         "0a3d412c4bad7d346e0f967bb8dd813a4aa0f1429010848d965d31abc95289ea",
+	"0005_turn_cost_iteration.sqlite.sql":     "b040c1d8051d99d365f468dc92beed23381908b0ca446f4ca31ae64ce25bb631",
+	"0005_turn_cost_iteration.postgres.sql":   "b040c1d8051d99d365f468dc92beed23381908b0ca446f4ca31ae64ce25bb631",
+	"0006_knowledge_entries.sqlite.sql":       "217bff9a6873fa401997f8e47393c094bf7e8222db3f0ed250e1c64a44eb7874",
+	"0006_knowledge_entries.postgres.sql":     "217bff9a6873fa401997f8e47393c094bf7e8222db3f0ed250e1c64a44eb7874",
+	"0007_llm_response_cache.sqlite.sql":      "c916d41d811410531810fa7ae38c9a5e55d6f0f6956da32cfef831f1ce807f9c",
+	"0007_llm_response_cache.postgres.sql":    "c916d41d811410531810fa7ae38c9a5e55d6f0f6956da32cfef831f1ce807f9c",
+	"0008_session_checkpoints.sqlite.sql":     "251f20480750f556ec465d37ffe03ad40720f0a3fc041d1bb2a58e9634f13d39",
+	"0008_session_checkpoints.postgres.sql":   "251f20480750f556ec465d37ffe03ad40720f0a3fc041d1bb2a58e9634f13d39",
+	"0009_dataset_item_status.sqlite.sql":     "797fce3fd8c94bc3de64ac745af8c30cfd9bf9f63cf11f7b080970d3700f7a7a",
+	"0009_dataset_item_status.postgres.sql":   "797fce3fd8c94bc3de64ac745af8c30cfd9bf9f63cf11f7b080970d3700f7a7a",
+	"0010_artifacts.sqlite.sql":               "215bda9dfbafdf6963f3698f4e5d07def2d768b6e005c2f8eed3cc79dc0447ab",
+	"0010_artifacts.postgres.sql":             "215bda9dfbafdf6963f3698f4e5d07def2d768b6e005c2f8eed3cc79dc0447ab",
+	"0011_commander_questions.sqlite.sql":     "894324b3a2510fb7fd5985401733d0deaeda78541c406d16c07f674a6e0e58e8",
+	"0011_commander_questions.postgres.sql":   "894324b3a2510fb7fd5985401733d0deaeda78541c406d16c07f674a6e0e58e8",
+	"0012_task_error_code.sqlite.sql":         "78814c015951240b766103fbd8ebf6414946db1b988481e40fcbefc0cdca2981",
+	"0012_task_error_code.postgres.sql":       "78814c015951240b766103fbd8ebf6414946db1b988481e40fcbefc0cdca2981",
+	"0013_task_confidence.sqlite.sql":         "eea1508af90cb46fe20706cf6fd45bd026674263ba9dd571308551c3022ce347",
+	"0013_task_confidence.postgres.sql":       "eea1508af90cb46fe20706cf6fd45bd026674263ba9dd571308551c3022ce347",
+	"0014_mission_compacted_at.sqlite.sql":    "1c8cc2623fc9030e6587af0b3195b07fc0ca9b1b6487679afb289770da9327d0",
+	"0014_mission_compacted_at.postgres.sql":  "1c8cc2623fc9030e6587af0b3195b07fc0ca9b1b6487679afb289770da9327d0",
+	"0015_tool_result_is_error.sqlite.sql":    "e7bd691474a85a0ab9724c37754b491ee184b3f4bdc254f15c979a99190f98ea",
+	"0015_tool_result_is_error.postgres.sql":  "55d1c545e2ad0d6c0c0b7715ab1a418cb4935d1d3799a4e9911d431228a404ee",
+	"0016_api_users.sqlite.sql":               "2e4bf14d78d552d1f74e0b949c0004c7dfcf993c8c1c8dd46e6013bf14bfd1e8",
+	"0016_api_users.postgres.sql":             "2e4bf14d78d552d1f74e0b949c0004c7dfcf993c8c1c8dd46e6013bf14bfd1e8",
 }
 
 var _ = Describe("Migration checksums", func() {
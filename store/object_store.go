@@ -0,0 +1,177 @@
+package store
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ObjectStore is a remote bucket for payloads too large to keep in the
+// relational store: artifact content, offloaded tool results and images,
+// and debug logs.
+type ObjectStore interface {
+	// PutObject stores the content of r under key, replacing any object
+	// already there.
+	PutObject(key string, r io.ReadSeeker) error
+	// GetObject opens the object stored under key.
+	GetObject(key string) (io.ReadCloser, error)
+	// DeleteObject removes the object stored under key. Deleting a key that
+	// doesn't exist is a no-op.
+	DeleteObject(key string) error
+}
+
+// ObjectBlobStore keeps artifact blobs in an ObjectStore under
+// blobs/<digest>.
+type ObjectBlobStore struct {
+	objects ObjectStore
+}
+
+// NewObjectBlobStore stores blobs in objects.
+func NewObjectBlobStore(objects ObjectStore) *ObjectBlobStore {
+	return &ObjectBlobStore{objects: objects}
+}
+
+func (s *ObjectBlobStore) PutBlob(digest string, r io.Reader) error {
+	rs, ok := r.(io.ReadSeeker)
+	if !ok {
+		b, err := io.ReadAll(r)
+		if err != nil {
+			return fmt.Errorf("read blob: %w", err)
+		}
+		rs = bytes.NewReader(b)
+	}
+	return s.objects.PutObject("blobs/"+digest, rs)
+}
+
+func (s *ObjectBlobStore) OpenBlob(digest string) (io.ReadCloser, error) {
+	return s.objects.GetObject("blobs/" + digest)
+}
+
+func (s *ObjectBlobStore) DeleteBlob(digest string) error {
+	return s.objects.DeleteObject("blobs/" + digest)
+}
+
+// offloadRefPrefix marks a column value that was moved to the object store;
+// the rest of the value is the object key.
+const offloadRefPrefix = "squadron-object:"
+
+// offloadingSessionStore keeps tool results and images larger than
+// threshold bytes in an ObjectStore. The relational store only holds an
+// offloadRefPrefix reference, which reads resolve back to the content.
+type offloadingSessionStore struct {
+	SessionStore
+	objects   ObjectStore
+	threshold int
+}
+
+// offload stores data in the object store if it is over the threshold and
+// returns the reference to keep in its place, or data unchanged.
+func (s *offloadingSessionStore) offload(data string) (string, error) {
+	if len(data) <= s.threshold {
+		return data, nil
+	}
+	key := "payloads/" + sha256Hex([]byte(data))
+	if err := s.objects.PutObject(key, strings.NewReader(data)); err != nil {
+		return "", fmt.Errorf("offload payload: %w", err)
+	}
+	return offloadRefPrefix + key, nil
+}
+
+// offloadResult offloads a tool result unless it is an error, which stays
+// inline so the inner store can flag it (see IsToolError).
+func (s *offloadingSessionStore) offloadResult(rawData string) (string, error) {
+	if IsToolError(rawData) {
+		return rawData, nil
+	}
+	return s.offload(rawData)
+}
+
+// resolve returns the content a stored value refers to. Values that aren't
+// references are returned unchanged.
+func (s *offloadingSessionStore) resolve(value string) (string, error) {
+	key, ok := strings.CutPrefix(value, offloadRefPrefix)
+	if !ok {
+		return value, nil
+	}
+	rc, err := s.objects.GetObject(key)
+	if err != nil {
+		return "", fmt.Errorf("load offloaded payload: %w", err)
+	}
+	defer rc.Close()
+	b, err := io.ReadAll(rc)
+	if err != nil {
+		return "", fmt.Errorf("load offloaded payload: %w", err)
+	}
+	return string(b), nil
+}
+
+func (s *offloadingSessionStore) StoreToolResult(taskID, sessionID, toolCallId, toolName, inputParams, rawData string, startedAt, finishedAt time.Time) error {
+	rawData, err := s.offloadResult(rawData)
+	if err != nil {
+		return err
+	}
+	return s.SessionStore.StoreToolResult(taskID, sessionID, toolCallId, toolName, inputParams, rawData, startedAt, finishedAt)
+}
+
+func (s *offloadingSessionStore) CompleteToolCall(id, rawData string) error {
+	rawData, err := s.offloadResult(rawData)
+	if err != nil {
+		return err
+	}
+	return s.SessionStore.CompleteToolCall(id, rawData)
+}
+
+func (s *offloadingSessionStore) GetToolResultsByTask(taskID string) ([]ToolResult, error) {
+	results, err := s.SessionStore.GetToolResultsByTask(taskID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range results {
+		if results[i].RawData, err = s.resolve(results[i].RawData); err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
+
+func (s *offloadingSessionStore) AppendStructuredMessage(sessionID, role, content string, parts []MessagePart, createdAt, completedAt time.Time) error {
+	var offloaded []MessagePart
+	for i, p := range parts {
+		if p.ImageData == "" || len(p.ImageData) <= s.threshold {
+			continue
+		}
+		if offloaded == nil {
+			offloaded = append([]MessagePart(nil), parts...)
+		}
+		ref, err := s.offload(p.ImageData)
+		if err != nil {
+			return err
+		}
+		offloaded[i].ImageData = ref
+	}
+	if offloaded != nil {
+		parts = offloaded
+	}
+	return s.SessionStore.AppendStructuredMessage(sessionID, role, content, parts, createdAt, completedAt)
+}
+
+func (s *offloadingSessionStore) GetStructuredMessages(sessionID string) ([]StructuredMessage, error) {
+	msgs, err := s.SessionStore.GetStructuredMessages(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range msgs {
+		for j := range msgs[i].Parts {
+			p := &msgs[i].Parts[j]
+			if !strings.HasPrefix(p.ImageData, offloadRefPrefix) {
+				continue
+			}
+			if p.ImageData, err = s.resolve(p.ImageData); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return msgs, nil
+}
@@ -0,0 +1,219 @@
+package store_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"squadron/store"
+)
+
+// memObjects is an in-memory ObjectStore.
+type memObjects struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func newMemObjects() *memObjects { return &memObjects{objects: map[string][]byte{}} }
+
+func (m *memObjects) PutObject(key string, r io.ReadSeeker) error {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.objects[key] = b
+	return nil
+}
+
+func (m *memObjects) DeleteObject(key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.objects, key)
+	return nil
+}
+
+func (m *memObjects) GetObject(key string) (io.ReadCloser, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	b, ok := m.objects[key]
+	if !ok {
+		return nil, io.ErrUnexpectedEOF
+	}
+	return io.NopCloser(bytes.NewReader(b)), nil
+}
+
+func (m *memObjects) keys(prefix string) []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var keys []string
+	for k := range m.objects {
+		if strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+var _ = Describe("S3ObjectStore", func() {
+	var (
+		server  *httptest.Server
+		objects map[string][]byte
+		auth    []string
+	)
+
+	BeforeEach(func() {
+		objects = map[string][]byte{}
+		auth = nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			auth = append(auth, r.Header.Get("Authorization"))
+			switch r.Method {
+			case http.MethodPut:
+				b, _ := io.ReadAll(r.Body)
+				objects[r.URL.EscapedPath()] = b
+			case http.MethodGet:
+				b, ok := objects[r.URL.EscapedPath()]
+				if !ok {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				w.Write(b)
+			}
+		}))
+		DeferCleanup(server.Close)
+	})
+
+	newStore := func() *store.S3ObjectStore {
+		s, err := store.NewS3ObjectStore(store.S3Options{
+			Endpoint:        server.URL,
+			Region:          "us-east-1",
+			Bucket:          "payloads",
+			Prefix:          "prod/",
+			AccessKeyID:     "AKID",
+			SecretAccessKey: "secret",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		return s
+	}
+
+	It("puts and gets objects under the bucket and prefix with signed requests", func() {
+		s := newStore()
+		Expect(s.PutObject("debug/m1/events log.txt", strings.NewReader("hello"))).To(Succeed())
+		Expect(objects).To(HaveKeyWithValue("/payloads/prod/debug/m1/events%20log.txt", []byte("hello")))
+
+		rc, err := s.GetObject("debug/m1/events log.txt")
+		Expect(err).NotTo(HaveOccurred())
+		b, _ := io.ReadAll(rc)
+		rc.Close()
+		Expect(string(b)).To(Equal("hello"))
+
+		day := time.Now().UTC().Format("20060102")
+		Expect(auth[0]).To(HavePrefix("AWS4-HMAC-SHA256 Credential=AKID/" + day + "/us-east-1/s3/aws4_request, SignedHeaders=content-type;host;x-amz-content-sha256;x-amz-date, Signature="))
+		Expect(auth[1]).To(ContainSubstring("SignedHeaders=host;x-amz-content-sha256;x-amz-date,"))
+	})
+
+	It("reports missing objects", func() {
+		_, err := newStore().GetObject("nope")
+		Expect(err).To(MatchError(ContainSubstring("not found")))
+	})
+})
+
+var _ = Describe("Object store offloading", func() {
+	var (
+		bundle  *store.Bundle
+		cleanup func()
+		objects *memObjects
+	)
+
+	BeforeEach(func() {
+		bundle, cleanup = newSQLiteBundle()
+		objects = newMemObjects()
+		bundle.SetObjectStore(objects, 16)
+	})
+
+	AfterEach(func() {
+		cleanup()
+	})
+
+	It("keeps large tool results in the object store and resolves them on read", func() {
+		_, taskID := seedMissionAndTask(bundle)
+		sessionID, err := bundle.Sessions.CreateSession(taskID, "agent", "scout", "m", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		large := "<html>" + strings.Repeat("x", 100) + "</html>"
+		now := time.Now()
+		Expect(bundle.Sessions.StoreToolResult(taskID, sessionID, "tc-1", "browser_html", "{}", `{"ok":true}`, now, now)).To(Succeed())
+		Expect(bundle.Sessions.StoreToolResult(taskID, sessionID, "tc-2", "browser_html", "{}", large, now, now)).To(Succeed())
+		id, err := bundle.Sessions.StartToolCall(taskID, sessionID, "tc-3", "browser_html", "{}")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(bundle.Sessions.CompleteToolCall(id, large)).To(Succeed())
+
+		Expect(objects.keys("payloads/")).To(HaveLen(1))
+
+		results, err := bundle.Sessions.GetToolResultsByTask(taskID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(results).To(HaveLen(3))
+		raw := map[string]string{}
+		for _, r := range results {
+			raw[r.ToolCallId] = r.RawData
+		}
+		Expect(raw).To(Equal(map[string]string{"tc-1": `{"ok":true}`, "tc-2": large, "tc-3": large}))
+	})
+
+	It("keeps large tool errors inline so they are counted as errors", func() {
+		missionID, taskID := seedMissionAndTask(bundle)
+		sessionID, err := bundle.Sessions.CreateSession(taskID, "agent", "scout", "m", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		now := time.Now()
+		Expect(bundle.Sessions.StoreToolResult(taskID, sessionID, "tc-1", "browser_html", "{}", "Error: "+strings.Repeat("x", 100), now, now)).To(Succeed())
+		Expect(objects.keys("payloads/")).To(BeEmpty())
+
+		stats, err := bundle.Sessions.GetToolStatsByMission(missionID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stats).To(HaveLen(1))
+		Expect(stats[0].Errors).To(Equal(1))
+	})
+
+	It("offloads large images in structured messages", func() {
+		_, taskID := seedMissionAndTask(bundle)
+		sessionID, err := bundle.Sessions.CreateSession(taskID, "agent", "scout", "m", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		screenshot := strings.Repeat("iVBORw0KGgo", 10)
+		parts := []store.MessagePart{
+			{Type: "text", Text: "here is the page"},
+			{Type: "image", ImageData: screenshot, ImageMediaType: "image/png"},
+		}
+		now := time.Now()
+		Expect(bundle.Sessions.AppendStructuredMessage(sessionID, "user", "screenshot", parts, now, now)).To(Succeed())
+		Expect(parts[1].ImageData).To(Equal(screenshot), "caller's parts are left untouched")
+		Expect(objects.keys("payloads/")).To(HaveLen(1))
+
+		msgs, err := bundle.Sessions.GetStructuredMessages(sessionID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(msgs).To(HaveLen(1))
+		Expect(msgs[0].Parts[1].ImageData).To(Equal(screenshot))
+	})
+
+	It("stores artifact content in the object store", func() {
+		missionID, _ := seedMissionAndTask(bundle)
+		rec, err := bundle.Artifacts.PutArtifact(store.ArtifactRecord{MissionID: missionID, Name: "a.txt"}, strings.NewReader("artifact body"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(objects.keys("blobs/")).To(Equal([]string{"blobs/" + rec.Digest}))
+
+		rc, err := bundle.Artifacts.OpenArtifact(rec.ID)
+		Expect(err).NotTo(HaveOccurred())
+		b, _ := io.ReadAll(rc)
+		rc.Close()
+		Expect(string(b)).To(Equal("artifact body"))
+	})
+})
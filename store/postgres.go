@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/zclconf/go-cty/cty"
@@ -39,6 +40,15 @@ func NewPostgresBundle(connStr string) (*Bundle, error) {
 		Events:      batchingEvents,
 		Costs:       &PgCostStore{db: db},
 		HumanInputs: &PgHumanInputStore{db: db},
+		Schedules:   &PgScheduleStore{db: db},
+		Knowledge:   &PgKnowledgeEntryStore{db: db},
+		Responses:   &PgResponseCacheStore{db: db},
+		Checkpoints: &PgCheckpointStore{db: db},
+		Artifacts:   &PgArtifactStore{db: db, blobs: NewDiskBlobStore(DefaultArtifactsPath)},
+		Questions:   &PgQuestionStore{db: db},
+		Users:       &PgUserStore{db: db},
+		db:          db,
+		dialect:     DialectPostgres,
 		closer: func() error {
 			batchingEvents.Close()
 			return db.Close()
@@ -68,7 +78,7 @@ func (s *PgMissionStore) CreateMission(name string, inputsJSON, configJSON strin
 
 func (s *PgMissionStore) UpdateMissionStatus(id, status string) error {
 	var finishedAt *string
-	if status == "completed" || status == "failed" {
+	if missionFinished(status) {
 		s := tsNow()
 		finishedAt = &s
 	}
@@ -109,6 +119,16 @@ func (s *PgMissionStore) UpdateTaskSummary(id, summary string) error {
 	return err
 }
 
+func (s *PgMissionStore) SetTaskErrorCode(id, code string) error {
+	_, err := s.db.Exec(`UPDATE mission_tasks SET error_code = $1 WHERE id = $2`, code, id)
+	return err
+}
+
+func (s *PgMissionStore) SetTaskConfidence(id string, confidence float64) error {
+	_, err := s.db.Exec(`UPDATE mission_tasks SET confidence = $1 WHERE id = $2`, confidence, id)
+	return err
+}
+
 func (s *PgMissionStore) UpdateTaskStatusCAS(id, expectedOldStatus, newStatus string, outputJSON, errMsg *string) (bool, error) {
 	var finishedAt *string
 	if newStatus == "completed" || newStatus == "failed" {
@@ -128,7 +148,7 @@ func (s *PgMissionStore) UpdateTaskStatusCAS(id, expectedOldStatus, newStatus st
 
 func (s *PgMissionStore) UpdateMissionStatusCAS(id, expectedOldStatus, newStatus string) (bool, error) {
 	var finishedAt *string
-	if newStatus == "completed" || newStatus == "failed" {
+	if missionFinished(newStatus) {
 		s := tsNow()
 		finishedAt = &s
 	}
@@ -145,7 +165,7 @@ func (s *PgMissionStore) UpdateMissionStatusCAS(id, expectedOldStatus, newStatus
 
 func (s *PgMissionStore) GetTasksByMission(missionID string) ([]MissionTask, error) {
 	rows, err := s.db.Query(
-		`SELECT id, mission_id, task_name, status, config_json, started_at, finished_at, output_json, summary, error FROM mission_tasks WHERE mission_id = $1`,
+		`SELECT id, mission_id, task_name, status, config_json, started_at, finished_at, output_json, summary, error, error_code, confidence FROM mission_tasks WHERE mission_id = $1`,
 		missionID,
 	)
 	if err != nil {
@@ -158,9 +178,10 @@ func (s *PgMissionStore) GetTasksByMission(missionID string) ([]MissionTask, err
 		var t MissionTask
 		var configJSON sql.NullString
 		var startedAtStr, finishedAtStr sql.NullString
-		var outputJSON, summary, errMsg sql.NullString
+		var outputJSON, summary, errMsg, errCode sql.NullString
+		var confidence sql.NullFloat64
 
-		if err := rows.Scan(&t.ID, &t.MissionID, &t.TaskName, &t.Status, &configJSON, &startedAtStr, &finishedAtStr, &outputJSON, &summary, &errMsg); err != nil {
+		if err := rows.Scan(&t.ID, &t.MissionID, &t.TaskName, &t.Status, &configJSON, &startedAtStr, &finishedAtStr, &outputJSON, &summary, &errMsg, &errCode, &confidence); err != nil {
 			return nil, err
 		}
 
@@ -175,6 +196,12 @@ func (s *PgMissionStore) GetTasksByMission(missionID string) ([]MissionTask, err
 		if errMsg.Valid {
 			t.Error = &errMsg.String
 		}
+		if errCode.Valid {
+			t.ErrorCode = &errCode.String
+		}
+		if confidence.Valid {
+			t.Confidence = &confidence.Float64
+		}
 
 		tasks = append(tasks, t)
 	}
@@ -194,12 +221,13 @@ func (s *PgMissionStore) GetTask(id string) (*MissionTask, error) {
 	var t MissionTask
 	var configJSON sql.NullString
 	var startedAtStr, finishedAtStr sql.NullString
-	var outputJSON, summary, errMsg sql.NullString
+	var outputJSON, summary, errMsg, errCode sql.NullString
+	var confidence sql.NullFloat64
 
 	err := s.db.QueryRow(
-		`SELECT id, mission_id, task_name, status, config_json, started_at, finished_at, output_json, summary, error FROM mission_tasks WHERE id = $1`,
+		`SELECT id, mission_id, task_name, status, config_json, started_at, finished_at, output_json, summary, error, error_code, confidence FROM mission_tasks WHERE id = $1`,
 		id,
-	).Scan(&t.ID, &t.MissionID, &t.TaskName, &t.Status, &configJSON, &startedAtStr, &finishedAtStr, &outputJSON, &summary, &errMsg)
+	).Scan(&t.ID, &t.MissionID, &t.TaskName, &t.Status, &configJSON, &startedAtStr, &finishedAtStr, &outputJSON, &summary, &errMsg, &errCode, &confidence)
 	if err != nil {
 		return nil, fmt.Errorf("task %q not found: %w", id, err)
 	}
@@ -218,6 +246,12 @@ func (s *PgMissionStore) GetTask(id string) (*MissionTask, error) {
 	if errMsg.Valid {
 		t.Error = &errMsg.String
 	}
+	if errCode.Valid {
+		t.ErrorCode = &errCode.String
+	}
+	if confidence.Valid {
+		t.Confidence = &confidence.Float64
+	}
 
 	return &t, nil
 }
@@ -226,12 +260,13 @@ func (s *PgMissionStore) GetTaskByName(missionID, taskName string) (*MissionTask
 	var t MissionTask
 	var configJSON sql.NullString
 	var startedAtStr, finishedAtStr sql.NullString
-	var outputJSON, summary, errMsg sql.NullString
+	var outputJSON, summary, errMsg, errCode sql.NullString
+	var confidence sql.NullFloat64
 
 	err := s.db.QueryRow(
-		`SELECT id, mission_id, task_name, status, config_json, started_at, finished_at, output_json, summary, error FROM mission_tasks WHERE mission_id = $1 AND task_name = $2`,
+		`SELECT id, mission_id, task_name, status, config_json, started_at, finished_at, output_json, summary, error, error_code, confidence FROM mission_tasks WHERE mission_id = $1 AND task_name = $2`,
 		missionID, taskName,
-	).Scan(&t.ID, &t.MissionID, &t.TaskName, &t.Status, &configJSON, &startedAtStr, &finishedAtStr, &outputJSON, &summary, &errMsg)
+	).Scan(&t.ID, &t.MissionID, &t.TaskName, &t.Status, &configJSON, &startedAtStr, &finishedAtStr, &outputJSON, &summary, &errMsg, &errCode, &confidence)
 	if err != nil {
 		return nil, fmt.Errorf("task '%s' not found: %w", taskName, err)
 	}
@@ -250,6 +285,12 @@ func (s *PgMissionStore) GetTaskByName(missionID, taskName string) (*MissionTask
 	if errMsg.Valid {
 		t.Error = &errMsg.String
 	}
+	if errCode.Valid {
+		t.ErrorCode = &errCode.String
+	}
+	if confidence.Valid {
+		t.Confidence = &confidence.Float64
+	}
 
 	return &t, nil
 }
@@ -258,12 +299,12 @@ func (s *PgMissionStore) GetMission(id string) (*MissionRecord, error) {
 	var m MissionRecord
 	var inputsJSON, configJSON sql.NullString
 	var startedAtStr string
-	var finishedAtStr sql.NullString
+	var finishedAtStr, compactedAtStr sql.NullString
 
 	err := s.db.QueryRow(
-		`SELECT id, mission_name, status, input_values_json, config_json, started_at, finished_at FROM missions WHERE id = $1`,
+		`SELECT id, mission_name, status, input_values_json, config_json, started_at, finished_at, compacted_at FROM missions WHERE id = $1`,
 		id,
-	).Scan(&m.ID, &m.MissionName, &m.Status, &inputsJSON, &configJSON, &startedAtStr, &finishedAtStr)
+	).Scan(&m.ID, &m.MissionName, &m.Status, &inputsJSON, &configJSON, &startedAtStr, &finishedAtStr, &compactedAtStr)
 	if err != nil {
 		return nil, fmt.Errorf("mission not found: %w", err)
 	}
@@ -276,13 +317,27 @@ func (s *PgMissionStore) GetMission(id string) (*MissionRecord, error) {
 		m.ConfigJSON = configJSON.String
 	}
 	m.FinishedAt, _ = tsParseNull(finishedAtStr)
+	m.CompactedAt, _ = tsParseNull(compactedAtStr)
 
 	return &m, nil
 }
 
+func (s *PgMissionStore) SupersedeTaskOutputs(taskID string, datasetIndices []int) error {
+	now := tsNow()
+	for _, idx := range datasetIndices {
+		if _, err := s.db.Exec(
+			`UPDATE task_outputs SET superseded_at = $1 WHERE task_id = $2 AND dataset_index = $3 AND superseded_at IS NULL`,
+			now, taskID, idx,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (s *PgMissionStore) GetTaskOutputs(taskID string) ([]TaskOutputRow, error) {
 	rows, err := s.db.Query(
-		`SELECT id, task_id, dataset_name, dataset_index, item_id, output_json, created_at FROM task_outputs WHERE task_id = $1 ORDER BY dataset_index ASC, created_at ASC`,
+		`SELECT id, task_id, dataset_name, dataset_index, item_id, output_json, created_at FROM task_outputs WHERE task_id = $1 AND superseded_at IS NULL ORDER BY dataset_index ASC, created_at ASC`,
 		taskID,
 	)
 	if err != nil {
@@ -580,6 +635,10 @@ func (s *PgSessionStore) ReopenSession(id string) {
 	s.db.Exec(`UPDATE sessions SET status = 'running', finished_at = NULL WHERE id = $1`, id)
 }
 
+func (s *PgSessionStore) TimeOutSession(id string) {
+	s.db.Exec(`UPDATE sessions SET status = 'timed_out', finished_at = $1 WHERE id = $2`, tsNow(), id)
+}
+
 func (s *PgSessionStore) AppendMessage(sessionID, role, content string, createdAt, completedAt time.Time) error {
 	_, err := s.db.Exec(
 		`INSERT INTO session_messages (session_id, role, content, created_at, completed_at) VALUES ($1, $2, $3, $4, $5)`,
@@ -779,8 +838,8 @@ func (s *PgSessionStore) GetSessionsByTask(taskID string) ([]SessionInfo, error)
 func (s *PgSessionStore) StoreToolResult(taskID, sessionID, toolCallId, toolName, inputParams, rawData string, startedAt, finishedAt time.Time) error {
 	id := generateID()
 	_, err := s.db.Exec(
-		`INSERT INTO tool_results (id, task_id, session_id, tool_call_id, tool_name, input_params, raw_data, status, started_at, finished_at) VALUES ($1, $2, $3, $4, $5, $6, $7, 'completed', $8, $9)`,
-		id, taskID, sessionID, toolCallId, toolName, inputParams, rawData, tsFrom(startedAt), tsFrom(finishedAt),
+		`INSERT INTO tool_results (id, task_id, session_id, tool_call_id, tool_name, input_params, raw_data, status, is_error, started_at, finished_at) VALUES ($1, $2, $3, $4, $5, $6, $7, 'completed', $8, $9, $10)`,
+		id, taskID, sessionID, toolCallId, toolName, inputParams, rawData, IsToolError(rawData), tsFrom(startedAt), tsFrom(finishedAt),
 	)
 	return err
 }
@@ -799,12 +858,20 @@ func (s *PgSessionStore) StartToolCall(taskID, sessionID, toolCallId, toolName,
 
 func (s *PgSessionStore) CompleteToolCall(id, rawData string) error {
 	_, err := s.db.Exec(
-		`UPDATE tool_results SET status = 'completed', raw_data = $1, finished_at = $2 WHERE id = $3`,
-		rawData, tsNow(), id,
+		`UPDATE tool_results SET status = 'completed', raw_data = $1, is_error = $2, finished_at = $3 WHERE id = $4`,
+		rawData, IsToolError(rawData), tsNow(), id,
 	)
 	return err
 }
 
+func (s *PgSessionStore) GetToolStatsByMission(missionID string) ([]ToolStats, error) {
+	rows, err := s.db.Query(strings.Replace(toolStatsQuery, "?", "$1", 1), missionID)
+	if err != nil {
+		return nil, err
+	}
+	return scanToolStats(rows)
+}
+
 func (s *PgSessionStore) GetToolResultsByTask(taskID string) ([]ToolResult, error) {
 	rows, err := s.db.Query(
 		`SELECT id, task_id, session_id, COALESCE(tool_call_id, ''), tool_name, input_params, raw_data, COALESCE(status, 'completed'), started_at, COALESCE(finished_at, started_at) FROM tool_results WHERE task_id = $1 ORDER BY started_at`,
@@ -1086,6 +1153,27 @@ func (s *PgDatasetStore) IsDatasetLocked(datasetID string) (bool, error) {
 	return locked == 1, nil
 }
 
+func (s *PgDatasetStore) SetItemStatus(taskID string, index int, itemID, status string, errMsg *string) error {
+	_, err := s.db.Exec(
+		`INSERT INTO dataset_item_status (task_id, item_index, item_id, status, error, updated_at) VALUES ($1, $2, $3, $4, $5, $6)
+		 ON CONFLICT (task_id, item_index) DO UPDATE SET item_id = EXCLUDED.item_id, status = EXCLUDED.status, error = EXCLUDED.error, updated_at = EXCLUDED.updated_at`,
+		taskID, index, itemID, status, errMsg, tsNow(),
+	)
+	return err
+}
+
+func (s *PgDatasetStore) GetItemStatuses(taskID string) ([]DatasetItemStatus, error) {
+	rows, err := s.db.Query(
+		`SELECT task_id, item_index, item_id, status, error, updated_at FROM dataset_item_status WHERE task_id = $1 ORDER BY item_index ASC`,
+		taskID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanItemStatuses(rows)
+}
+
 func (s *PgDatasetStore) GetItemsRaw(datasetID string, offset, limit int) ([]string, error) {
 	rows, err := s.db.Query(
 		`SELECT item_json FROM dataset_items WHERE dataset_id = $1 ORDER BY item_index LIMIT $2 OFFSET $3`,
@@ -0,0 +1,29 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// PgScheduleStore is the Postgres mirror of SQLiteScheduleStore.
+type PgScheduleStore struct {
+	db *sql.DB
+}
+
+func (s *PgScheduleStore) ClaimScheduledRun(missionName, source string, fireAt time.Time) (bool, error) {
+	result, err := s.db.Exec(
+		`INSERT INTO schedule_runs (mission_name, source, fire_at, claimed_at)
+		 VALUES ($1, $2, $3, $4)
+		 ON CONFLICT(mission_name, source, fire_at) DO NOTHING`,
+		missionName, source, fireAt.UTC(), time.Now().UTC(),
+	)
+	if err != nil {
+		return false, fmt.Errorf("claim scheduled run: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("claim scheduled run: %w", err)
+	}
+	return n == 1, nil
+}
@@ -0,0 +1,69 @@
+package store
+
+import "database/sql"
+
+// SQLiteQuestionStore implements QuestionStore backed by SQLite.
+type SQLiteQuestionStore struct {
+	db *sql.DB
+}
+
+func (s *SQLiteQuestionStore) AddQuestion(q CommanderQuestionRecord) (string, error) {
+	id := generateID()
+	_, err := s.db.Exec(
+		`INSERT INTO commander_questions (id, mission_id, task_name, iteration_index, seq, question, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		id, q.MissionID, q.TaskName, q.IterationIndex, q.Seq, q.Question, tsNow(),
+	)
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (s *SQLiteQuestionStore) AnswerQuestion(id, answer string) error {
+	_, err := s.db.Exec(
+		`UPDATE commander_questions SET answer = ?, answered_at = ? WHERE id = ?`,
+		answer, tsNow(), id,
+	)
+	return err
+}
+
+func (s *SQLiteQuestionStore) GetQuestions(missionID string) ([]CommanderQuestionRecord, error) {
+	rows, err := s.db.Query(
+		`SELECT id, mission_id, task_name, iteration_index, seq, question, answer, created_at, answered_at
+		 FROM commander_questions WHERE mission_id = ? ORDER BY task_name, iteration_index, seq`,
+		missionID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanCommanderQuestions(rows)
+}
+
+// scanCommanderQuestions reads commander_questions rows; shared by the
+// SQLite and Postgres stores.
+func scanCommanderQuestions(rows *sql.Rows) ([]CommanderQuestionRecord, error) {
+	var results []CommanderQuestionRecord
+	for rows.Next() {
+		var q CommanderQuestionRecord
+		var iterIdx sql.NullInt64
+		var answer, answeredAtStr sql.NullString
+		var createdAtStr string
+		if err := rows.Scan(&q.ID, &q.MissionID, &q.TaskName, &iterIdx, &q.Seq, &q.Question,
+			&answer, &createdAtStr, &answeredAtStr); err != nil {
+			return nil, err
+		}
+		if iterIdx.Valid {
+			idx := int(iterIdx.Int64)
+			q.IterationIndex = &idx
+		}
+		if answer.Valid {
+			q.Answer = &answer.String
+		}
+		q.CreatedAt, _ = tsParse(createdAtStr)
+		q.AnsweredAt, _ = tsParseNull(answeredAtStr)
+		results = append(results, q)
+	}
+	return results, rows.Err()
+}
@@ -0,0 +1,42 @@
+package store
+
+import "database/sql"
+
+// PgQuestionStore implements QuestionStore backed by Postgres.
+type PgQuestionStore struct {
+	db *sql.DB
+}
+
+func (s *PgQuestionStore) AddQuestion(q CommanderQuestionRecord) (string, error) {
+	id := generateID()
+	_, err := s.db.Exec(
+		`INSERT INTO commander_questions (id, mission_id, task_name, iteration_index, seq, question, created_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		id, q.MissionID, q.TaskName, q.IterationIndex, q.Seq, q.Question, tsNow(),
+	)
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (s *PgQuestionStore) AnswerQuestion(id, answer string) error {
+	_, err := s.db.Exec(
+		`UPDATE commander_questions SET answer = $1, answered_at = $2 WHERE id = $3`,
+		answer, tsNow(), id,
+	)
+	return err
+}
+
+func (s *PgQuestionStore) GetQuestions(missionID string) ([]CommanderQuestionRecord, error) {
+	rows, err := s.db.Query(
+		`SELECT id, mission_id, task_name, iteration_index, seq, question, answer, created_at, answered_at
+		 FROM commander_questions WHERE mission_id = $1 ORDER BY task_name, iteration_index, seq`,
+		missionID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanCommanderQuestions(rows)
+}
@@ -0,0 +1,73 @@
+package store_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"squadron/store"
+)
+
+var _ = Describe("SQLite QuestionStore", func() {
+	var (
+		bundle  *store.Bundle
+		cleanup func()
+	)
+
+	BeforeEach(func() {
+		bundle, cleanup = newSQLiteBundle()
+	})
+
+	AfterEach(func() {
+		cleanup()
+	})
+
+	It("records questions and their answers per task and iteration", func() {
+		missionID, _ := seedMissionAndTask(bundle)
+		two := 2
+
+		first, err := bundle.Questions.AddQuestion(store.CommanderQuestionRecord{
+			MissionID: missionID, TaskName: "research", Seq: 0, Question: "Which sources?",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = bundle.Questions.AddQuestion(store.CommanderQuestionRecord{
+			MissionID: missionID, TaskName: "research", Seq: 1, Question: "Any paywalls?",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = bundle.Questions.AddQuestion(store.CommanderQuestionRecord{
+			MissionID: missionID, TaskName: "scrape", IterationIndex: &two, Seq: 0, Question: "Which URL?",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(bundle.Questions.AnswerQuestion(first, "Reuters and AP")).To(Succeed())
+
+		questions, err := bundle.Questions.GetQuestions(missionID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(questions).To(HaveLen(3))
+
+		Expect(questions[0].ID).To(Equal(first))
+		Expect(questions[0].IterationIndex).To(BeNil())
+		Expect(*questions[0].Answer).To(Equal("Reuters and AP"))
+		Expect(questions[0].AnsweredAt).NotTo(BeNil())
+
+		Expect(questions[1].Question).To(Equal("Any paywalls?"))
+		Expect(questions[1].Seq).To(Equal(1))
+		Expect(questions[1].Answer).To(BeNil())
+		Expect(questions[1].AnsweredAt).To(BeNil())
+
+		Expect(questions[2].TaskName).To(Equal("scrape"))
+		Expect(*questions[2].IterationIndex).To(Equal(2))
+	})
+
+	It("scopes questions to their mission", func() {
+		missionID, _ := seedMissionAndTask(bundle)
+		_, err := bundle.Questions.AddQuestion(store.CommanderQuestionRecord{
+			MissionID: missionID, TaskName: "research", Question: "Which sources?",
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		otherID, err := bundle.Missions.CreateMission("other", "{}", "{}")
+		Expect(err).NotTo(HaveOccurred())
+		questions, err := bundle.Questions.GetQuestions(otherID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(questions).To(BeEmpty())
+	})
+})
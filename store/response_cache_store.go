@@ -0,0 +1,51 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// SQLiteResponseCacheStore implements ResponseCacheStore backed by SQLite.
+type SQLiteResponseCacheStore struct {
+	db *sql.DB
+}
+
+func (s *SQLiteResponseCacheStore) GetCachedResponse(key string) (*CachedResponseRecord, error) {
+	row := s.db.QueryRow(
+		`SELECT cache_key, model, response_json, created_at FROM llm_response_cache WHERE cache_key = ?`,
+		key,
+	)
+	return scanCachedResponse(row)
+}
+
+func (s *SQLiteResponseCacheStore) PutCachedResponse(rec CachedResponseRecord) error {
+	_, err := s.db.Exec(
+		`INSERT INTO llm_response_cache (cache_key, model, response_json, created_at) VALUES (?, ?, ?, ?)
+		 ON CONFLICT(cache_key) DO UPDATE SET model = excluded.model, response_json = excluded.response_json, created_at = excluded.created_at`,
+		rec.Key, rec.Model, rec.ResponseJSON, tsNow(),
+	)
+	return err
+}
+
+func (s *SQLiteResponseCacheStore) PruneCachedResponses(before time.Time) (int, error) {
+	res, err := s.db.Exec(`DELETE FROM llm_response_cache WHERE created_at < ?`, tsFrom(before))
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
+
+func scanCachedResponse(row *sql.Row) (*CachedResponseRecord, error) {
+	var r CachedResponseRecord
+	var createdAtStr string
+	if err := row.Scan(&r.Key, &r.Model, &r.ResponseJSON, &createdAtStr); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	r.CreatedAt, _ = tsParse(createdAtStr)
+	return &r, nil
+}
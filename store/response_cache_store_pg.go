@@ -0,0 +1,37 @@
+package store
+
+import (
+	"database/sql"
+	"time"
+)
+
+// PgResponseCacheStore implements ResponseCacheStore backed by Postgres.
+type PgResponseCacheStore struct {
+	db *sql.DB
+}
+
+func (s *PgResponseCacheStore) GetCachedResponse(key string) (*CachedResponseRecord, error) {
+	row := s.db.QueryRow(
+		`SELECT cache_key, model, response_json, created_at FROM llm_response_cache WHERE cache_key = $1`,
+		key,
+	)
+	return scanCachedResponse(row)
+}
+
+func (s *PgResponseCacheStore) PutCachedResponse(rec CachedResponseRecord) error {
+	_, err := s.db.Exec(
+		`INSERT INTO llm_response_cache (cache_key, model, response_json, created_at) VALUES ($1, $2, $3, $4)
+		 ON CONFLICT (cache_key) DO UPDATE SET model = EXCLUDED.model, response_json = EXCLUDED.response_json, created_at = EXCLUDED.created_at`,
+		rec.Key, rec.Model, rec.ResponseJSON, tsNow(),
+	)
+	return err
+}
+
+func (s *PgResponseCacheStore) PruneCachedResponses(before time.Time) (int, error) {
+	res, err := s.db.Exec(`DELETE FROM llm_response_cache WHERE created_at < $1`, tsFrom(before))
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
@@ -0,0 +1,58 @@
+package store_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"squadron/store"
+)
+
+var _ = Describe("SQLite ResponseCacheStore", func() {
+	var (
+		bundle  *store.Bundle
+		cleanup func()
+	)
+
+	BeforeEach(func() {
+		bundle, cleanup = newSQLiteBundle()
+	})
+
+	AfterEach(func() {
+		cleanup()
+	})
+
+	It("returns nil for a missing key", func() {
+		rec, err := bundle.Responses.GetCachedResponse("missing")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rec).To(BeNil())
+	})
+
+	It("stores, replaces, and prunes entries", func() {
+		Expect(bundle.Responses.PutCachedResponse(store.CachedResponseRecord{
+			Key: "k1", Model: "claude-sonnet-4", ResponseJSON: `{"content":"first"}`,
+		})).To(Succeed())
+		Expect(bundle.Responses.PutCachedResponse(store.CachedResponseRecord{
+			Key: "k1", Model: "claude-sonnet-4", ResponseJSON: `{"content":"second"}`,
+		})).To(Succeed())
+
+		rec, err := bundle.Responses.GetCachedResponse("k1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rec).NotTo(BeNil())
+		Expect(rec.Model).To(Equal("claude-sonnet-4"))
+		Expect(rec.ResponseJSON).To(Equal(`{"content":"second"}`))
+		Expect(rec.CreatedAt).To(BeTemporally("~", time.Now(), time.Minute))
+
+		n, err := bundle.Responses.PruneCachedResponses(time.Now().Add(-time.Hour))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(n).To(Equal(0))
+
+		n, err = bundle.Responses.PruneCachedResponses(time.Now().Add(time.Second))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(n).To(Equal(1))
+		rec, err = bundle.Responses.GetCachedResponse("k1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rec).To(BeNil())
+	})
+})
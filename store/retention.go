@@ -0,0 +1,270 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// RetentionPolicy selects the finished missions GC deletes or compacts. A
+// mission counts as finished once it is completed, failed, stopped,
+// cancelled or over budget; running and paused missions are never touched.
+// Zero leaves a limit unset.
+type RetentionPolicy struct {
+	// MaxAge deletes missions that finished longer ago than this
+	MaxAge time.Duration
+	// MaxMissions keeps only this many of the most recent finished missions
+	MaxMissions int
+	// CompactAfter redacts the message bodies, message parts, checkpoints
+	// and tool results of missions that finished longer ago than this. Their
+	// tasks, outputs, datasets, events and costs stay.
+	CompactAfter time.Duration
+	// DryRun reports what GC would do without changing the store
+	DryRun bool
+}
+
+// GCResult lists what GC removed.
+type GCResult struct {
+	Deleted   []string // IDs of deleted missions
+	Compacted []string // IDs of compacted missions
+	// Blobs is the number of artifact blobs deleted because no remaining
+	// artifact refers to them
+	Blobs int
+}
+
+// CompactedContent replaces the message bodies and tool results of a
+// compacted mission.
+const CompactedContent = "[compacted]"
+
+// gcStatuses are the mission statuses GC may delete or compact.
+var gcStatuses = []string{"completed", "failed", "budget_exceeded", "stopped", "cancelled"}
+
+// Subqueries selecting the rows that belong to one mission (the ? is its ID).
+const (
+	gcTasks    = `SELECT id FROM mission_tasks WHERE mission_id = ?`
+	gcSessions = `SELECT id FROM sessions WHERE task_id IN (` + gcTasks + `)`
+	gcMessages = `SELECT id FROM session_messages WHERE session_id IN (` + gcSessions + `)`
+)
+
+// gcDeletes remove a mission and everything recorded for it. Rows go before
+// the rows they reference, so foreign keys hold at every step.
+var gcDeletes = []string{
+	`DELETE FROM session_message_parts WHERE message_id IN (` + gcMessages + `)`,
+	`DELETE FROM session_messages WHERE session_id IN (` + gcSessions + `)`,
+	`DELETE FROM session_checkpoints WHERE session_id IN (` + gcSessions + `)`,
+	`DELETE FROM mission_task_subtasks WHERE task_id IN (` + gcTasks + `)`,
+	`DELETE FROM tool_results WHERE task_id IN (` + gcTasks + `)`,
+	`DELETE FROM task_outputs WHERE task_id IN (` + gcTasks + `)`,
+	`DELETE FROM task_inputs WHERE task_id IN (` + gcTasks + `)`,
+	`DELETE FROM dataset_item_status WHERE task_id IN (` + gcTasks + `)`,
+	`DELETE FROM turn_costs WHERE mission_id = ?`,
+	`DELETE FROM mission_events WHERE mission_id = ?`,
+	`DELETE FROM dataset_items WHERE dataset_id IN (SELECT id FROM datasets WHERE mission_id = ?)`,
+	`DELETE FROM datasets WHERE mission_id = ?`,
+	`DELETE FROM route_decisions WHERE mission_id = ?`,
+	`DELETE FROM knowledge_entries WHERE mission_id = ?`,
+	`DELETE FROM commander_questions WHERE mission_id = ?`,
+	`DELETE FROM human_input_requests WHERE mission_id = ?`,
+	`DELETE FROM artifacts WHERE mission_id = ?`,
+	`DELETE FROM sessions WHERE task_id IN (` + gcTasks + `)`,
+	`DELETE FROM mission_tasks WHERE mission_id = ?`,
+	`DELETE FROM missions WHERE id = ?`,
+}
+
+// gcCompacts redact the transcripts of a mission.
+var gcCompacts = []string{
+	`DELETE FROM session_message_parts WHERE message_id IN (` + gcMessages + `)`,
+	`DELETE FROM session_checkpoints WHERE session_id IN (` + gcSessions + `)`,
+	`UPDATE session_messages SET content = '` + CompactedContent + `' WHERE session_id IN (` + gcSessions + `)`,
+	`UPDATE tool_results SET raw_data = '` + CompactedContent + `' WHERE task_id IN (` + gcTasks + `)`,
+}
+
+// GC applies policy to the bundle's missions as of now. Each mission is
+// deleted or compacted in its own transaction, so a failure leaves the
+// missions handled so far done and the rest untouched. Objects offloaded to
+// an object store under payloads/ are shared across missions by content and
+// are left in place.
+func (b *Bundle) GC(policy RetentionPolicy, now time.Time) (*GCResult, error) {
+	if b.db == nil {
+		return nil, fmt.Errorf("store gc: bundle has no database")
+	}
+	candidates, err := b.gcCandidates()
+	if err != nil {
+		return nil, err
+	}
+
+	res := &GCResult{}
+	for i, c := range candidates {
+		age := now.Sub(c.finishedAt)
+		switch {
+		case policy.MaxMissions > 0 && i >= policy.MaxMissions,
+			policy.MaxAge > 0 && age > policy.MaxAge:
+			res.Deleted = append(res.Deleted, c.id)
+		case policy.CompactAfter > 0 && age > policy.CompactAfter && !c.compacted:
+			res.Compacted = append(res.Compacted, c.id)
+		}
+	}
+	if policy.DryRun {
+		return res, nil
+	}
+
+	digests := map[string]bool{}
+	for _, id := range res.Deleted {
+		d, err := b.gcArtifactDigests(id)
+		if err != nil {
+			return res, err
+		}
+		for _, digest := range d {
+			digests[digest] = true
+		}
+		err = b.gcTx(func(tx *sql.Tx) error { return b.gcExec(tx, id, gcDeletes) })
+		if err != nil {
+			return res, fmt.Errorf("delete mission %s: %w", id, err)
+		}
+	}
+	for _, id := range res.Compacted {
+		err := b.gcTx(func(tx *sql.Tx) error {
+			if err := b.gcExec(tx, id, gcCompacts); err != nil {
+				return err
+			}
+			_, err := tx.Exec(b.gcBind(`UPDATE missions SET compacted_at = ? WHERE id = ?`), tsFrom(now), id)
+			return err
+		})
+		if err != nil {
+			return res, fmt.Errorf("compact mission %s: %w", id, err)
+		}
+	}
+
+	blobs := b.blobStore()
+	for digest := range digests {
+		var refs int
+		if err := b.db.QueryRow(b.gcBind(`SELECT COUNT(*) FROM artifacts WHERE digest = ?`), digest).Scan(&refs); err != nil {
+			return res, fmt.Errorf("count artifact references: %w", err)
+		}
+		if refs > 0 || blobs == nil {
+			continue
+		}
+		if err := blobs.DeleteBlob(digest); err != nil {
+			return res, err
+		}
+		res.Blobs++
+	}
+	return res, nil
+}
+
+type gcCandidate struct {
+	id         string
+	finishedAt time.Time
+	compacted  bool
+}
+
+// gcCandidates returns the finished missions, most recently finished first.
+// Missions stopped or cancelled before finished_at was recorded count from
+// when they started.
+func (b *Bundle) gcCandidates() ([]gcCandidate, error) {
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(gcStatuses)), ", ")
+	args := make([]any, len(gcStatuses))
+	for i, s := range gcStatuses {
+		args[i] = s
+	}
+	rows, err := b.db.Query(b.gcBind(
+		`SELECT id, COALESCE(finished_at, started_at), compacted_at FROM missions WHERE status IN (`+placeholders+`)`), args...)
+	if err != nil {
+		return nil, fmt.Errorf("list missions: %w", err)
+	}
+	defer rows.Close()
+
+	var candidates []gcCandidate
+	for rows.Next() {
+		var c gcCandidate
+		var finishedAt, compactedAt sql.NullString
+		if err := rows.Scan(&c.id, &finishedAt, &compactedAt); err != nil {
+			return nil, fmt.Errorf("scan mission: %w", err)
+		}
+		c.finishedAt, _ = tsParse(finishedAt.String)
+		c.compacted = compactedAt.Valid
+		candidates = append(candidates, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if !a.finishedAt.Equal(b.finishedAt) {
+			return a.finishedAt.After(b.finishedAt)
+		}
+		return a.id > b.id
+	})
+	return candidates, nil
+}
+
+func (b *Bundle) gcArtifactDigests(missionID string) ([]string, error) {
+	rows, err := b.db.Query(b.gcBind(`SELECT DISTINCT digest FROM artifacts WHERE mission_id = ?`), missionID)
+	if err != nil {
+		return nil, fmt.Errorf("list artifacts: %w", err)
+	}
+	defer rows.Close()
+	var digests []string
+	for rows.Next() {
+		var d string
+		if err := rows.Scan(&d); err != nil {
+			return nil, fmt.Errorf("scan artifact: %w", err)
+		}
+		digests = append(digests, d)
+	}
+	return digests, rows.Err()
+}
+
+// gcTx runs fn in a transaction, committing when it succeeds.
+func (b *Bundle) gcTx(fn func(tx *sql.Tx) error) error {
+	tx, err := b.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// gcExec runs stmts, each taking the mission ID as its only parameter.
+func (b *Bundle) gcExec(tx *sql.Tx, missionID string, stmts []string) error {
+	for _, stmt := range stmts {
+		if _, err := tx.Exec(b.gcBind(stmt), missionID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// gcBind numbers the ? placeholders of query as $1, $2, ... for Postgres.
+func (b *Bundle) gcBind(query string) string {
+	if b.dialect != DialectPostgres {
+		return query
+	}
+	var sb strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			fmt.Fprintf(&sb, "$%d", n)
+			continue
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// blobStore returns where the bundle's artifact content is kept.
+func (b *Bundle) blobStore() BlobStore {
+	switch s := b.Artifacts.(type) {
+	case *SQLiteArtifactStore:
+		return s.blobs
+	case *PgArtifactStore:
+		return s.blobs
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package store_test
+
+import (
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"squadron/store"
+)
+
+var _ = Describe("Bundle GC", func() {
+	var (
+		bundle  *store.Bundle
+		cleanup func()
+	)
+
+	BeforeEach(func() {
+		bundle, cleanup = newSQLiteBundle()
+	})
+
+	AfterEach(func() {
+		cleanup()
+	})
+
+	// seedRun records a mission with a session transcript, a tool call, a
+	// cost, a checkpoint, a dataset and an artifact holding content, and
+	// leaves the mission in status.
+	seedRun := func(status, content string) (missionID, taskID, sessionID string) {
+		missionID, taskID = seedMissionAndTask(bundle)
+		sessionID, err := bundle.Sessions.CreateSession(taskID, "commander", "", "claude_sonnet_4", nil)
+		Expect(err).NotTo(HaveOccurred())
+		now := time.Now()
+		Expect(bundle.Sessions.AppendStructuredMessage(sessionID, "assistant", "the plan",
+			[]store.MessagePart{{Type: "text", Text: "the plan"}}, now, now)).To(Succeed())
+		Expect(bundle.Sessions.StoreToolResult(taskID, sessionID, "tc_1", "http_get", `{"url":"x"}`, "a long page", now, now)).To(Succeed())
+		Expect(bundle.Costs.StoreTurnCost(store.TurnCostRecord{
+			MissionID: missionID, TaskID: taskID, SessionID: sessionID,
+			MissionName: "test-mission", TaskName: "test-task", Entity: "commander", Model: "claude_sonnet_4",
+			TotalCost: 0.5, CreatedAt: now,
+		})).To(Succeed())
+		Expect(bundle.Checkpoints.SaveCheckpoint(store.SessionCheckpoint{SessionID: sessionID, MessagesJSON: "[]"})).To(Succeed())
+		_, err = bundle.Datasets.CreateDataset(missionID, "items", "Items")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = bundle.Artifacts.PutArtifact(store.ArtifactRecord{MissionID: missionID, Name: "notes.txt"}, strings.NewReader(content))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(bundle.Missions.UpdateMissionStatus(missionID, status)).To(Succeed())
+		return missionID, taskID, sessionID
+	}
+
+	later := time.Now().Add(48 * time.Hour)
+
+	It("deletes finished missions past max age and leaves running and paused ones", func() {
+		oldID, oldTask, _ := seedRun("completed", "only in the old run")
+		stoppedID, _, _ := seedRun("stopped", "shared")
+		runningID, _, _ := seedRun("running", "shared")
+		pausedID, _, _ := seedRun("paused", "paused content")
+
+		res, err := bundle.GC(store.RetentionPolicy{MaxAge: 24 * time.Hour}, later)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.Deleted).To(ConsistOf(oldID, stoppedID))
+		Expect(res.Compacted).To(BeEmpty())
+		// The shared blob is still used by the running mission's artifact
+		Expect(res.Blobs).To(Equal(1))
+
+		_, err = bundle.Missions.GetMission(oldID)
+		Expect(err).To(HaveOccurred())
+		tasks, err := bundle.Missions.GetTasksByMission(oldID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tasks).To(BeEmpty())
+		sessions, err := bundle.Sessions.GetSessionsByTask(oldTask)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sessions).To(BeEmpty())
+		costs, err := bundle.Costs.GetCostsByMission(oldID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(costs).To(BeEmpty())
+
+		for _, id := range []string{runningID, pausedID} {
+			_, err := bundle.Missions.GetMission(id)
+			Expect(err).NotTo(HaveOccurred())
+			artifacts, err := bundle.Artifacts.ListArtifacts(id)
+			Expect(err).NotTo(HaveOccurred())
+			r, err := bundle.Artifacts.OpenArtifact(artifacts[0].ID)
+			Expect(err).NotTo(HaveOccurred())
+			r.Close()
+		}
+	})
+
+	It("keeps only the most recent max_missions finished missions", func() {
+		var ids []string
+		for i := 0; i < 3; i++ {
+			id, _, _ := seedRun("completed", "content")
+			ids = append(ids, id)
+			time.Sleep(5 * time.Millisecond)
+		}
+
+		res, err := bundle.GC(store.RetentionPolicy{MaxMissions: 2}, time.Now())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.Deleted).To(Equal([]string{ids[0]}))
+		missions, total, err := bundle.Missions.ListMissions(10, 0)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(total).To(Equal(2))
+		Expect([]string{missions[0].ID, missions[1].ID}).To(ConsistOf(ids[1], ids[2]))
+	})
+
+	It("compacts transcripts and keeps the mission's tasks and costs", func() {
+		missionID, taskID, sessionID := seedRun("failed", "content")
+
+		res, err := bundle.GC(store.RetentionPolicy{CompactAfter: 24 * time.Hour}, later)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.Compacted).To(Equal([]string{missionID}))
+		Expect(res.Deleted).To(BeEmpty())
+
+		record, err := bundle.Missions.GetMission(missionID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(record.CompactedAt).NotTo(BeNil())
+		messages, err := bundle.Sessions.GetStructuredMessages(sessionID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(messages).To(HaveLen(1))
+		Expect(messages[0].Content).To(Equal(store.CompactedContent))
+		Expect(messages[0].Parts).To(BeEmpty())
+		results, err := bundle.Sessions.GetToolResultsByTask(taskID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(results[0].RawData).To(Equal(store.CompactedContent))
+		cp, err := bundle.Checkpoints.GetCheckpoint(sessionID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cp).To(BeNil())
+		costs, err := bundle.Costs.GetCostsByMission(missionID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(costs).To(HaveLen(1))
+
+		res, err = bundle.GC(store.RetentionPolicy{CompactAfter: 24 * time.Hour}, later)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.Compacted).To(BeEmpty())
+	})
+
+	It("changes nothing on a dry run", func() {
+		missionID, _, sessionID := seedRun("completed", "content")
+
+		res, err := bundle.GC(store.RetentionPolicy{MaxAge: 24 * time.Hour, DryRun: true}, later)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.Deleted).To(Equal([]string{missionID}))
+
+		_, err = bundle.Missions.GetMission(missionID)
+		Expect(err).NotTo(HaveOccurred())
+		messages, err := bundle.Sessions.GetMessages(sessionID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(messages[0].Content).To(Equal("the plan"))
+	})
+})
@@ -0,0 +1,153 @@
+package store
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"squadron/internal/awssig"
+)
+
+// S3Options configures an S3ObjectStore.
+type S3Options struct {
+	// Endpoint is the server URL, e.g. "https://storage.googleapis.com" or
+	// "http://localhost:9000". Empty means AWS S3 in Region.
+	Endpoint        string
+	Region          string
+	Bucket          string
+	Prefix          string
+	AccessKeyID     string
+	SecretAccessKey string
+}
+
+// S3ObjectStore is an ObjectStore speaking the S3 REST protocol with
+// Signature Version 4, so it works against AWS S3, GCS (XML API with HMAC
+// keys), and S3-compatible servers such as MinIO or R2.
+type S3ObjectStore struct {
+	opts    S3Options
+	base    *url.URL // bucket URL; object keys are appended to its path
+	client  *http.Client
+	nowFunc func() time.Time
+}
+
+// NewS3ObjectStore creates an object store for the bucket in opts. With a
+// custom Endpoint the bucket goes in the path; on AWS it goes in the host.
+func NewS3ObjectStore(opts S3Options) (*S3ObjectStore, error) {
+	var base *url.URL
+	var err error
+	if opts.Endpoint == "" {
+		base, err = url.Parse(fmt.Sprintf("https://%s.s3.%s.amazonaws.com/", opts.Bucket, opts.Region))
+	} else {
+		base, err = url.Parse(strings.TrimRight(opts.Endpoint, "/") + "/" + opts.Bucket + "/")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("object store endpoint: %w", err)
+	}
+	return &S3ObjectStore{
+		opts:    opts,
+		base:    base,
+		client:  &http.Client{Timeout: 5 * time.Minute},
+		nowFunc: time.Now,
+	}, nil
+}
+
+func (s *S3ObjectStore) objectURL(key string) *url.URL {
+	u := *s.base
+	u.Path += s.opts.Prefix + key
+	u.RawPath = s3EscapePath(u.Path)
+	return &u
+}
+
+// s3EscapePath percent-encodes everything in p but unreserved characters
+// and slashes, the way SigV4 canonical URIs require.
+func s3EscapePath(p string) string {
+	var b strings.Builder
+	for i := 0; i < len(p); i++ {
+		c := p[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || strings.IndexByte("-._~/", c) >= 0 {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func (s *S3ObjectStore) PutObject(key string, r io.ReadSeeker) error {
+	size, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return fmt.Errorf("put object %s: %w", key, err)
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("put object %s: %w", key, err)
+	}
+	req, err := http.NewRequest(http.MethodPut, s.objectURL(key).String(), io.NopCloser(r))
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", "application/octet-stream")
+	resp, err := s.do(req, awssig.UnsignedPayload)
+	if err != nil {
+		return fmt.Errorf("put object %s: %w", key, err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (s *S3ObjectStore) GetObject(key string) (io.ReadCloser, error) {
+	req, err := http.NewRequest(http.MethodGet, s.objectURL(key).String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.do(req, awssig.EmptyPayloadHash)
+	if err != nil {
+		return nil, fmt.Errorf("get object %s: %w", key, err)
+	}
+	return resp.Body, nil
+}
+
+func (s *S3ObjectStore) DeleteObject(key string) error {
+	req, err := http.NewRequest(http.MethodDelete, s.objectURL(key).String(), nil)
+	if err != nil {
+		return err
+	}
+	// S3 answers 204 whether or not the key existed
+	resp, err := s.do(req, awssig.EmptyPayloadHash)
+	if err != nil {
+		return fmt.Errorf("delete object %s: %w", key, err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// do signs and sends req, turning non-2xx responses into errors.
+func (s *S3ObjectStore) do(req *http.Request, payloadHash string) (*http.Response, error) {
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	creds := awssig.Credentials{AccessKeyID: s.opts.AccessKeyID, SecretAccessKey: s.opts.SecretAccessKey}
+	awssig.Sign(req, payloadHash, creds, s.opts.Region, "s3", s.nowFunc())
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		resp.Body.Close()
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, fmt.Errorf("not found")
+		}
+		return nil, fmt.Errorf("%s: %s", resp.Status, bytes.TrimSpace(body))
+	}
+	return resp, nil
+}
+
+func sha256Hex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
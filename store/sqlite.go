@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"path/filepath"
 	"time"
 
 	"github.com/zclconf/go-cty/cty"
@@ -35,6 +36,15 @@ func NewSQLiteBundle(dbPath string) (*Bundle, error) {
 		Events:      batchingEvents,
 		Costs:       &SQLiteCostStore{db: db},
 		HumanInputs: &SQLiteHumanInputStore{db: db},
+		Schedules:   &SQLiteScheduleStore{db: db},
+		Knowledge:   &SQLiteKnowledgeEntryStore{db: db},
+		Responses:   &SQLiteResponseCacheStore{db: db},
+		Checkpoints: &SQLiteCheckpointStore{db: db},
+		Artifacts:   &SQLiteArtifactStore{db: db, blobs: NewDiskBlobStore(filepath.Join(filepath.Dir(dbPath), "artifacts"))},
+		Questions:   &SQLiteQuestionStore{db: db},
+		Users:       &SQLiteUserStore{db: db},
+		db:          db,
+		dialect:     DialectSQLite,
 		closer: func() error {
 			batchingEvents.Close()
 			return db.Close()
@@ -64,7 +74,7 @@ func (s *SQLiteMissionStore) CreateMission(name string, inputsJSON, configJSON s
 
 func (s *SQLiteMissionStore) UpdateMissionStatus(id, status string) error {
 	var finishedAt *string
-	if status == "completed" || status == "failed" {
+	if missionFinished(status) {
 		s := tsNow()
 		finishedAt = &s
 	}
@@ -105,6 +115,16 @@ func (s *SQLiteMissionStore) UpdateTaskSummary(id, summary string) error {
 	return err
 }
 
+func (s *SQLiteMissionStore) SetTaskErrorCode(id, code string) error {
+	_, err := s.db.Exec(`UPDATE mission_tasks SET error_code = ? WHERE id = ?`, code, id)
+	return err
+}
+
+func (s *SQLiteMissionStore) SetTaskConfidence(id string, confidence float64) error {
+	_, err := s.db.Exec(`UPDATE mission_tasks SET confidence = ? WHERE id = ?`, confidence, id)
+	return err
+}
+
 func (s *SQLiteMissionStore) UpdateTaskStatusCAS(id, expectedOldStatus, newStatus string, outputJSON, errMsg *string) (bool, error) {
 	var finishedAt *string
 	if newStatus == "completed" || newStatus == "failed" {
@@ -124,7 +144,7 @@ func (s *SQLiteMissionStore) UpdateTaskStatusCAS(id, expectedOldStatus, newStatu
 
 func (s *SQLiteMissionStore) UpdateMissionStatusCAS(id, expectedOldStatus, newStatus string) (bool, error) {
 	var finishedAt *string
-	if newStatus == "completed" || newStatus == "failed" {
+	if missionFinished(newStatus) {
 		s := tsNow()
 		finishedAt = &s
 	}
@@ -141,7 +161,7 @@ func (s *SQLiteMissionStore) UpdateMissionStatusCAS(id, expectedOldStatus, newSt
 
 func (s *SQLiteMissionStore) GetTasksByMission(missionID string) ([]MissionTask, error) {
 	rows, err := s.db.Query(
-		`SELECT id, mission_id, task_name, status, config_json, started_at, finished_at, output_json, summary, error FROM mission_tasks WHERE mission_id = ?`,
+		`SELECT id, mission_id, task_name, status, config_json, started_at, finished_at, output_json, summary, error, error_code, confidence FROM mission_tasks WHERE mission_id = ?`,
 		missionID,
 	)
 	if err != nil {
@@ -154,9 +174,10 @@ func (s *SQLiteMissionStore) GetTasksByMission(missionID string) ([]MissionTask,
 		var t MissionTask
 		var configJSON sql.NullString
 		var startedAtStr, finishedAtStr sql.NullString
-		var outputJSON, summary, errMsg sql.NullString
+		var outputJSON, summary, errMsg, errCode sql.NullString
+		var confidence sql.NullFloat64
 
-		if err := rows.Scan(&t.ID, &t.MissionID, &t.TaskName, &t.Status, &configJSON, &startedAtStr, &finishedAtStr, &outputJSON, &summary, &errMsg); err != nil {
+		if err := rows.Scan(&t.ID, &t.MissionID, &t.TaskName, &t.Status, &configJSON, &startedAtStr, &finishedAtStr, &outputJSON, &summary, &errMsg, &errCode, &confidence); err != nil {
 			return nil, err
 		}
 
@@ -174,6 +195,12 @@ func (s *SQLiteMissionStore) GetTasksByMission(missionID string) ([]MissionTask,
 		if errMsg.Valid {
 			t.Error = &errMsg.String
 		}
+		if errCode.Valid {
+			t.ErrorCode = &errCode.String
+		}
+		if confidence.Valid {
+			t.Confidence = &confidence.Float64
+		}
 
 		tasks = append(tasks, t)
 	}
@@ -193,12 +220,13 @@ func (s *SQLiteMissionStore) GetTask(id string) (*MissionTask, error) {
 	var t MissionTask
 	var configJSON sql.NullString
 	var startedAtStr, finishedAtStr sql.NullString
-	var outputJSON, summary, errMsg sql.NullString
+	var outputJSON, summary, errMsg, errCode sql.NullString
+	var confidence sql.NullFloat64
 
 	err := s.db.QueryRow(
-		`SELECT id, mission_id, task_name, status, config_json, started_at, finished_at, output_json, summary, error FROM mission_tasks WHERE id = ?`,
+		`SELECT id, mission_id, task_name, status, config_json, started_at, finished_at, output_json, summary, error, error_code, confidence FROM mission_tasks WHERE id = ?`,
 		id,
-	).Scan(&t.ID, &t.MissionID, &t.TaskName, &t.Status, &configJSON, &startedAtStr, &finishedAtStr, &outputJSON, &summary, &errMsg)
+	).Scan(&t.ID, &t.MissionID, &t.TaskName, &t.Status, &configJSON, &startedAtStr, &finishedAtStr, &outputJSON, &summary, &errMsg, &errCode, &confidence)
 	if err != nil {
 		return nil, fmt.Errorf("task %q not found: %w", id, err)
 	}
@@ -217,6 +245,12 @@ func (s *SQLiteMissionStore) GetTask(id string) (*MissionTask, error) {
 	if errMsg.Valid {
 		t.Error = &errMsg.String
 	}
+	if errCode.Valid {
+		t.ErrorCode = &errCode.String
+	}
+	if confidence.Valid {
+		t.Confidence = &confidence.Float64
+	}
 
 	return &t, nil
 }
@@ -225,12 +259,13 @@ func (s *SQLiteMissionStore) GetTaskByName(missionID, taskName string) (*Mission
 	var t MissionTask
 	var configJSON sql.NullString
 	var startedAtStr, finishedAtStr sql.NullString
-	var outputJSON, summary, errMsg sql.NullString
+	var outputJSON, summary, errMsg, errCode sql.NullString
+	var confidence sql.NullFloat64
 
 	err := s.db.QueryRow(
-		`SELECT id, mission_id, task_name, status, config_json, started_at, finished_at, output_json, summary, error FROM mission_tasks WHERE mission_id = ? AND task_name = ?`,
+		`SELECT id, mission_id, task_name, status, config_json, started_at, finished_at, output_json, summary, error, error_code, confidence FROM mission_tasks WHERE mission_id = ? AND task_name = ?`,
 		missionID, taskName,
-	).Scan(&t.ID, &t.MissionID, &t.TaskName, &t.Status, &configJSON, &startedAtStr, &finishedAtStr, &outputJSON, &summary, &errMsg)
+	).Scan(&t.ID, &t.MissionID, &t.TaskName, &t.Status, &configJSON, &startedAtStr, &finishedAtStr, &outputJSON, &summary, &errMsg, &errCode, &confidence)
 	if err != nil {
 		return nil, fmt.Errorf("task '%s' not found: %w", taskName, err)
 	}
@@ -249,6 +284,12 @@ func (s *SQLiteMissionStore) GetTaskByName(missionID, taskName string) (*Mission
 	if errMsg.Valid {
 		t.Error = &errMsg.String
 	}
+	if errCode.Valid {
+		t.ErrorCode = &errCode.String
+	}
+	if confidence.Valid {
+		t.Confidence = &confidence.Float64
+	}
 
 	return &t, nil
 }
@@ -257,12 +298,12 @@ func (s *SQLiteMissionStore) GetMission(id string) (*MissionRecord, error) {
 	var m MissionRecord
 	var inputsJSON, configJSON sql.NullString
 	var startedAtStr string
-	var finishedAtStr sql.NullString
+	var finishedAtStr, compactedAtStr sql.NullString
 
 	err := s.db.QueryRow(
-		`SELECT id, mission_name, status, input_values_json, config_json, started_at, finished_at FROM missions WHERE id = ?`,
+		`SELECT id, mission_name, status, input_values_json, config_json, started_at, finished_at, compacted_at FROM missions WHERE id = ?`,
 		id,
-	).Scan(&m.ID, &m.MissionName, &m.Status, &inputsJSON, &configJSON, &startedAtStr, &finishedAtStr)
+	).Scan(&m.ID, &m.MissionName, &m.Status, &inputsJSON, &configJSON, &startedAtStr, &finishedAtStr, &compactedAtStr)
 	if err != nil {
 		return nil, fmt.Errorf("mission not found: %w", err)
 	}
@@ -275,13 +316,27 @@ func (s *SQLiteMissionStore) GetMission(id string) (*MissionRecord, error) {
 		m.ConfigJSON = configJSON.String
 	}
 	m.FinishedAt, _ = tsParseNull(finishedAtStr)
+	m.CompactedAt, _ = tsParseNull(compactedAtStr)
 
 	return &m, nil
 }
 
+func (s *SQLiteMissionStore) SupersedeTaskOutputs(taskID string, datasetIndices []int) error {
+	now := tsNow()
+	for _, idx := range datasetIndices {
+		if _, err := s.db.Exec(
+			`UPDATE task_outputs SET superseded_at = ? WHERE task_id = ? AND dataset_index = ? AND superseded_at IS NULL`,
+			now, taskID, idx,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (s *SQLiteMissionStore) GetTaskOutputs(taskID string) ([]TaskOutputRow, error) {
 	rows, err := s.db.Query(
-		`SELECT id, task_id, dataset_name, dataset_index, item_id, output_json, created_at FROM task_outputs WHERE task_id = ? ORDER BY dataset_index ASC, created_at ASC`,
+		`SELECT id, task_id, dataset_name, dataset_index, item_id, output_json, created_at FROM task_outputs WHERE task_id = ? AND superseded_at IS NULL ORDER BY dataset_index ASC, created_at ASC`,
 		taskID,
 	)
 	if err != nil {
@@ -355,6 +410,10 @@ func (s *SQLiteSessionStore) ReopenSession(id string) {
 	s.db.Exec(`UPDATE sessions SET status = 'running', finished_at = NULL WHERE id = ?`, id)
 }
 
+func (s *SQLiteSessionStore) TimeOutSession(id string) {
+	s.db.Exec(`UPDATE sessions SET status = 'timed_out', finished_at = ? WHERE id = ?`, tsNow(), id)
+}
+
 func (s *SQLiteSessionStore) AppendMessage(sessionID, role, content string, createdAt, completedAt time.Time) error {
 	_, err := s.db.Exec(
 		`INSERT INTO session_messages (session_id, role, content, created_at, completed_at) VALUES (?, ?, ?, ?, ?)`,
@@ -590,8 +649,8 @@ func (s *SQLiteSessionStore) GetSessionsByTask(taskID string) ([]SessionInfo, er
 func (s *SQLiteSessionStore) StoreToolResult(taskID, sessionID, toolCallId, toolName, inputParams, rawData string, startedAt, finishedAt time.Time) error {
 	id := generateID()
 	_, err := s.db.Exec(
-		`INSERT INTO tool_results (id, task_id, session_id, tool_call_id, tool_name, input_params, raw_data, status, started_at, finished_at) VALUES (?, ?, ?, ?, ?, ?, ?, 'completed', ?, ?)`,
-		id, taskID, sessionID, toolCallId, toolName, inputParams, rawData, tsFrom(startedAt), tsFrom(finishedAt),
+		`INSERT INTO tool_results (id, task_id, session_id, tool_call_id, tool_name, input_params, raw_data, status, is_error, started_at, finished_at) VALUES (?, ?, ?, ?, ?, ?, ?, 'completed', ?, ?, ?)`,
+		id, taskID, sessionID, toolCallId, toolName, inputParams, rawData, IsToolError(rawData), tsFrom(startedAt), tsFrom(finishedAt),
 	)
 	return err
 }
@@ -610,12 +669,20 @@ func (s *SQLiteSessionStore) StartToolCall(taskID, sessionID, toolCallId, toolNa
 
 func (s *SQLiteSessionStore) CompleteToolCall(id, rawData string) error {
 	_, err := s.db.Exec(
-		`UPDATE tool_results SET status = 'completed', raw_data = ?, finished_at = ? WHERE id = ?`,
-		rawData, tsNow(), id,
+		`UPDATE tool_results SET status = 'completed', raw_data = ?, is_error = ?, finished_at = ? WHERE id = ?`,
+		rawData, IsToolError(rawData), tsNow(), id,
 	)
 	return err
 }
 
+func (s *SQLiteSessionStore) GetToolStatsByMission(missionID string) ([]ToolStats, error) {
+	rows, err := s.db.Query(toolStatsQuery, missionID)
+	if err != nil {
+		return nil, err
+	}
+	return scanToolStats(rows)
+}
+
 func (s *SQLiteSessionStore) GetToolResultsByTask(taskID string) ([]ToolResult, error) {
 	rows, err := s.db.Query(
 		`SELECT id, task_id, session_id, COALESCE(tool_call_id, ''), tool_name, input_params, raw_data, COALESCE(status, 'completed'), started_at, COALESCE(finished_at, started_at) FROM tool_results WHERE task_id = ? ORDER BY started_at`,
@@ -893,6 +960,27 @@ func (s *SQLiteDatasetStore) IsDatasetLocked(datasetID string) (bool, error) {
 	return locked == 1, nil
 }
 
+func (s *SQLiteDatasetStore) SetItemStatus(taskID string, index int, itemID, status string, errMsg *string) error {
+	_, err := s.db.Exec(
+		`INSERT INTO dataset_item_status (task_id, item_index, item_id, status, error, updated_at) VALUES (?, ?, ?, ?, ?, ?)
+		 ON CONFLICT(task_id, item_index) DO UPDATE SET item_id = excluded.item_id, status = excluded.status, error = excluded.error, updated_at = excluded.updated_at`,
+		taskID, index, itemID, status, errMsg, tsNow(),
+	)
+	return err
+}
+
+func (s *SQLiteDatasetStore) GetItemStatuses(taskID string) ([]DatasetItemStatus, error) {
+	rows, err := s.db.Query(
+		`SELECT task_id, item_index, item_id, status, error, updated_at FROM dataset_item_status WHERE task_id = ? ORDER BY item_index ASC`,
+		taskID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanItemStatuses(rows)
+}
+
 func (s *SQLiteDatasetStore) GetItemsRaw(datasetID string, offset, limit int) ([]string, error) {
 	rows, err := s.db.Query(
 		`SELECT item_json FROM dataset_items WHERE dataset_id = ? ORDER BY item_index LIMIT ? OFFSET ?`,
@@ -0,0 +1,29 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// SQLiteScheduleStore backs ScheduleStore with SQLite.
+type SQLiteScheduleStore struct {
+	db *sql.DB
+}
+
+func (s *SQLiteScheduleStore) ClaimScheduledRun(missionName, source string, fireAt time.Time) (bool, error) {
+	result, err := s.db.Exec(
+		`INSERT INTO schedule_runs (mission_name, source, fire_at, claimed_at)
+		 VALUES (?, ?, ?, ?)
+		 ON CONFLICT(mission_name, source, fire_at) DO NOTHING`,
+		missionName, source, tsFrom(fireAt), tsFrom(time.Now()),
+	)
+	if err != nil {
+		return false, fmt.Errorf("claim scheduled run: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("claim scheduled run: %w", err)
+	}
+	return n == 1, nil
+}
@@ -0,0 +1,61 @@
+package store_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"squadron/store"
+)
+
+var _ = Describe("ScheduleStore (SQLite)", func() {
+	var (
+		bundle  *store.Bundle
+		cleanup func()
+		fireAt  time.Time
+	)
+
+	BeforeEach(func() {
+		bundle, cleanup = newSQLiteBundle()
+		fireAt = time.Date(2026, 3, 26, 9, 0, 0, 0, time.UTC)
+	})
+	AfterEach(func() { cleanup() })
+
+	It("grants a run to the first claimant only", func() {
+		claimed, err := bundle.Schedules.ClaimScheduledRun("report", "schedule", fireAt)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(claimed).To(BeTrue())
+
+		claimed, err = bundle.Schedules.ClaimScheduledRun("report", "schedule", fireAt)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(claimed).To(BeFalse())
+	})
+
+	It("treats the same instant in another timezone as the same run", func() {
+		chicago, err := time.LoadLocation("America/Chicago")
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = bundle.Schedules.ClaimScheduledRun("report", "schedule", fireAt)
+		Expect(err).NotTo(HaveOccurred())
+		claimed, err := bundle.Schedules.ClaimScheduledRun("report", "schedule", fireAt.In(chicago))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(claimed).To(BeFalse())
+	})
+
+	It("keeps runs of different schedules, missions, and times apart", func() {
+		for _, c := range []struct {
+			mission, source string
+			at              time.Time
+		}{
+			{"report", "schedule", fireAt},
+			{"report", "schedule[1]", fireAt},
+			{"digest", "schedule", fireAt},
+			{"report", "schedule", fireAt.Add(time.Hour)},
+		} {
+			claimed, err := bundle.Schedules.ClaimScheduledRun(c.mission, c.source, c.at)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(claimed).To(BeTrue())
+		}
+	})
+})
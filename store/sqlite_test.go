@@ -183,6 +183,40 @@ var _ = Describe("SQLite MissionStore", func() {
 			Expect(t.Error).NotTo(BeNil())
 			Expect(*t.Error).To(Equal("something broke"))
 			Expect(t.FinishedAt).NotTo(BeNil())
+			Expect(t.ErrorCode).To(BeNil())
+		})
+
+		It("stores an error code alongside the error", func() {
+			missionID, taskID := seedMissionAndTask(bundle)
+
+			errMsg := "429 Too Many Requests"
+			Expect(bundle.Missions.UpdateTaskStatus(taskID, "failed", nil, &errMsg)).To(Succeed())
+			Expect(bundle.Missions.SetTaskErrorCode(taskID, "rate_limited")).To(Succeed())
+
+			t, _ := bundle.Missions.GetTask(taskID)
+			Expect(t.ErrorCode).NotTo(BeNil())
+			Expect(*t.ErrorCode).To(Equal("rate_limited"))
+
+			tasks, err := bundle.Missions.GetTasksByMission(missionID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tasks).To(HaveLen(1))
+			Expect(*tasks[0].ErrorCode).To(Equal("rate_limited"))
+		})
+
+		It("stores the confidence of a verified task", func() {
+			missionID, taskID := seedMissionAndTask(bundle)
+
+			output := `{"ok":true}`
+			Expect(bundle.Missions.UpdateTaskStatus(taskID, "completed", &output, nil)).To(Succeed())
+			Expect(bundle.Missions.SetTaskConfidence(taskID, 0.85)).To(Succeed())
+
+			t, _ := bundle.Missions.GetTask(taskID)
+			Expect(t.Confidence).NotTo(BeNil())
+			Expect(*t.Confidence).To(Equal(0.85))
+
+			byName, err := bundle.Missions.GetTaskByName(missionID, t.TaskName)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*byName.Confidence).To(Equal(0.85))
 		})
 	})
 
@@ -317,6 +351,22 @@ var _ = Describe("SQLite MissionStore", func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(outputs).To(BeEmpty())
 		})
+
+		It("leaves out superseded outputs", func() {
+			_, taskID := seedMissionAndTask(bundle)
+
+			idx0, idx1 := 0, 1
+			Expect(bundle.Missions.StoreTaskOutput(taskID, nil, &idx0, nil, `{"run":1}`)).To(Succeed())
+			Expect(bundle.Missions.StoreTaskOutput(taskID, nil, &idx1, nil, `{"run":1}`)).To(Succeed())
+			Expect(bundle.Missions.SupersedeTaskOutputs(taskID, []int{0})).To(Succeed())
+			Expect(bundle.Missions.StoreTaskOutput(taskID, nil, &idx0, nil, `{"run":2}`)).To(Succeed())
+
+			outputs, err := bundle.Missions.GetTaskOutputs(taskID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(outputs).To(HaveLen(2))
+			Expect(outputs[0].OutputJSON).To(Equal(`{"run":2}`))
+			Expect(outputs[1].OutputJSON).To(Equal(`{"run":1}`))
+		})
 	})
 
 	// =========================================================================
@@ -1273,4 +1323,34 @@ var _ = Describe("SQLite DatasetStore", func() {
 			Expect(locked).To(BeTrue())
 		})
 	})
+
+	// =========================================================================
+	// 30. SetItemStatus / GetItemStatuses
+	// =========================================================================
+	Describe("SetItemStatus and GetItemStatuses", func() {
+		It("keeps the latest status per item in index order", func() {
+			taskID, err := bundle.Missions.CreateTask(missionID, "process", "{}")
+			Expect(err).NotTo(HaveOccurred())
+
+			boom := "boom"
+			Expect(bundle.Datasets.SetItemStatus(taskID, 1, "beta", store.ItemPending, nil)).To(Succeed())
+			Expect(bundle.Datasets.SetItemStatus(taskID, 0, "alpha", store.ItemRunning, nil)).To(Succeed())
+			Expect(bundle.Datasets.SetItemStatus(taskID, 0, "alpha", store.ItemFailed, &boom)).To(Succeed())
+
+			statuses, err := bundle.Datasets.GetItemStatuses(taskID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(statuses).To(HaveLen(2))
+			Expect(statuses[0].Index).To(Equal(0))
+			Expect(statuses[0].ItemID).To(Equal("alpha"))
+			Expect(statuses[0].Status).To(Equal(store.ItemFailed))
+			Expect(*statuses[0].Error).To(Equal("boom"))
+			Expect(statuses[1].Status).To(Equal(store.ItemPending))
+			Expect(statuses[1].Error).To(BeNil())
+
+			Expect(bundle.Datasets.SetItemStatus(taskID, 0, "alpha", store.ItemCompleted, nil)).To(Succeed())
+			statuses, _ = bundle.Datasets.GetItemStatuses(taskID)
+			Expect(statuses[0].Status).To(Equal(store.ItemCompleted))
+			Expect(statuses[0].Error).To(BeNil())
+		})
+	})
 })
@@ -1,6 +1,8 @@
 package store
 
 import (
+	"database/sql"
+	"io"
 	"time"
 
 	"github.com/zclconf/go-cty/cty"
@@ -17,6 +19,16 @@ type Bundle struct {
 	Events      EventStore
 	Costs       CostStore
 	HumanInputs HumanInputStore
+	Schedules   ScheduleStore
+	Knowledge   KnowledgeEntryStore
+	Responses   ResponseCacheStore
+	Checkpoints CheckpointStore
+	Artifacts   ArtifactStore
+	Questions   QuestionStore
+	Users       UserStore
+	Objects     ObjectStore // Remote bucket for large payloads (nil without an object_store block)
+	db          *sql.DB     // Shared by the stores above; used by GC
+	dialect     Dialect
 	closer      func() error
 }
 
@@ -37,6 +49,12 @@ type MissionStore interface {
 	CreateTask(missionID, taskName, configJSON string) (id string, err error)
 	UpdateTaskStatus(id, status string, outputJSON, errMsg *string) error
 	UpdateTaskSummary(id, summary string) error
+	// SetTaskErrorCode records the failure class of a failed task (see
+	// package failure).
+	SetTaskErrorCode(id, code string) error
+	// SetTaskConfidence records the confidence score the commander gave a
+	// task it verified (see config.Task.Verify).
+	SetTaskConfidence(id string, confidence float64) error
 	// UpdateTaskStatusCAS atomically transitions a task status, returning false if current status doesn't match expected.
 	UpdateTaskStatusCAS(id, expectedOldStatus, newStatus string, outputJSON, errMsg *string) (bool, error)
 	GetTask(id string) (*MissionTask, error)
@@ -45,7 +63,11 @@ type MissionStore interface {
 	GetMission(id string) (*MissionRecord, error)
 	ListMissions(limit, offset int) ([]MissionRecord, int, error)
 	StoreTaskOutput(taskID string, datasetName *string, datasetIndex *int, itemID *string, outputJSON string) error
+	// GetTaskOutputs returns the task's current outputs; superseded ones are left out.
 	GetTaskOutputs(taskID string) ([]TaskOutputRow, error)
+	// SupersedeTaskOutputs retires the outputs of the given iterations so a
+	// re-run's outputs replace them.
+	SupersedeTaskOutputs(taskID string, datasetIndices []int) error
 
 	// Task inputs (per-execution/iteration resolved inputs)
 	StoreTaskInput(taskID string, iterationIndex *int, objective string) error
@@ -74,6 +96,8 @@ type MissionTask struct {
 	OutputJSON *string    `json:"outputJson,omitempty"`
 	Summary    *string    `json:"summary,omitempty"`
 	Error      *string    `json:"error,omitempty"`
+	ErrorCode  *string    `json:"errorCode,omitempty"`
+	Confidence *float64   `json:"confidence,omitempty"`
 }
 
 // MissionRecord represents a mission row
@@ -85,6 +109,9 @@ type MissionRecord struct {
 	ConfigJSON      string     `json:"configJson"`
 	StartedAt       time.Time  `json:"startedAt"`
 	FinishedAt      *time.Time `json:"finishedAt,omitempty"`
+	// CompactedAt is when `squadron store gc` redacted the mission's
+	// sessions (see RetentionPolicy.CompactAfter)
+	CompactedAt *time.Time `json:"compactedAt,omitempty"`
 }
 
 // TaskOutputRow represents a single row from the task_outputs table
@@ -135,6 +162,9 @@ type SessionStore interface {
 	CreateSession(taskID, role, agentName, model string, iterationIndex *int) (id string, err error)
 	CompleteSession(id string, err error)
 	ReopenSession(id string)
+	// TimeOutSession marks a session "timed_out" — it was cut off by a task
+	// or iteration timeout rather than failing or being interrupted.
+	TimeOutSession(id string)
 	AppendMessage(sessionID, role, content string, createdAt, completedAt time.Time) error
 	// AppendStructuredMessage atomically inserts a session_messages row plus
 	// one session_message_parts row per part. The content string is the
@@ -156,6 +186,9 @@ type SessionStore interface {
 	// CompleteToolCall marks a tool call as completed with result data.
 	CompleteToolCall(id, rawData string) error
 	GetToolResultsByTask(taskID string) ([]ToolResult, error)
+	// GetToolStatsByMission totals a mission's tool calls per commander or
+	// agent and tool. Calls are flagged as errors when they are recorded.
+	GetToolStatsByMission(missionID string) ([]ToolStats, error)
 
 	// Chat-specific methods
 	CreateChatSession(agentName, model string) (string, error)
@@ -219,6 +252,28 @@ type DatasetStore interface {
 	GetItemsRaw(datasetID string, offset, limit int) ([]string, error)
 	LockDataset(datasetID string) error
 	IsDatasetLocked(datasetID string) (bool, error)
+
+	// Per-item status of an iterated task (see the Item* status constants)
+	SetItemStatus(taskID string, index int, itemID, status string, errMsg *string) error
+	GetItemStatuses(taskID string) ([]DatasetItemStatus, error)
+}
+
+// Statuses of one dataset item in an iterated task.
+const (
+	ItemPending   = "pending"
+	ItemRunning   = "running"
+	ItemCompleted = "completed"
+	ItemFailed    = "failed"
+)
+
+// DatasetItemStatus is the status of one dataset item in an iterated task
+type DatasetItemStatus struct {
+	TaskID    string    `json:"taskId"`
+	Index     int       `json:"index"`
+	ItemID    string    `json:"itemId,omitempty"`
+	Status    string    `json:"status"`
+	Error     *string   `json:"error,omitempty"`
+	UpdatedAt time.Time `json:"updatedAt"`
 }
 
 // EventStore persists mission execution events for history/audit
@@ -257,6 +312,205 @@ type CostStore interface {
 	GetTotalCosts(from, to time.Time) (*CostTotals, error)
 }
 
+// KnowledgeEntryStore persists entries of a mission's knowledge base (the
+// `knowledge` block with backend = "store") so a resumed mission can still
+// search what earlier tasks saved.
+type KnowledgeEntryStore interface {
+	AddKnowledgeEntry(entry KnowledgeEntryRecord) (string, error)
+	// GetKnowledgeEntries returns a mission's entries in insertion order.
+	GetKnowledgeEntries(missionID string) ([]KnowledgeEntryRecord, error)
+}
+
+// ResponseCacheStore persists LLM responses keyed by a hash of the request
+// (a mission's `response_cache` block), so identical requests are answered
+// across runs without calling the provider.
+type ResponseCacheStore interface {
+	// GetCachedResponse returns the entry for key, or nil if there is none.
+	GetCachedResponse(key string) (*CachedResponseRecord, error)
+	// PutCachedResponse stores rec, replacing any entry with the same key.
+	PutCachedResponse(rec CachedResponseRecord) error
+	// PruneCachedResponses deletes entries created before the given time.
+	PruneCachedResponses(before time.Time) (int, error)
+}
+
+// CachedResponseRecord is one cached LLM response. ResponseJSON holds the
+// serialized llm.CachedResponse.
+type CachedResponseRecord struct {
+	Key          string    `json:"key"`
+	Model        string    `json:"model"`
+	ResponseJSON string    `json:"responseJson"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+// CheckpointStore holds the latest mid-task checkpoint of each commander
+// session (a mission commander's `checkpoint` block). A resumed commander
+// starts from its checkpoint instead of healing the raw message log.
+type CheckpointStore interface {
+	// SaveCheckpoint stores cp, replacing the session's previous checkpoint.
+	SaveCheckpoint(cp SessionCheckpoint) error
+	// GetCheckpoint returns the session's checkpoint, or nil if there is none.
+	GetCheckpoint(sessionID string) (*SessionCheckpoint, error)
+	// DeleteCheckpoint removes the session's checkpoint, if any.
+	DeleteCheckpoint(sessionID string) error
+}
+
+// QuestionStore persists the questions commanders ask dependency task
+// commanders with ask_commander, and their answers. Parallel iterations
+// share them through list_commander_questions, so a resumed mission
+// reloads them instead of asking again.
+type QuestionStore interface {
+	// AddQuestion records an unanswered question and returns its ID.
+	AddQuestion(q CommanderQuestionRecord) (string, error)
+	// AnswerQuestion records the answer to a question.
+	AnswerQuestion(id, answer string) error
+	// GetQuestions returns a mission's questions ordered by task,
+	// iteration and Seq.
+	GetQuestions(missionID string) ([]CommanderQuestionRecord, error)
+}
+
+// CommanderQuestionRecord is one question asked of a task's commander.
+// IterationIndex is set when the question went to one iteration of an
+// iterated task. Seq is the question's position among those asked of the
+// same task (or iteration); it is the index list_commander_questions shows.
+// Answer is nil until the commander has answered.
+type CommanderQuestionRecord struct {
+	ID             string     `json:"id"`
+	MissionID      string     `json:"missionId"`
+	TaskName       string     `json:"taskName"`
+	IterationIndex *int       `json:"iterationIndex,omitempty"`
+	Seq            int        `json:"seq"`
+	Question       string     `json:"question"`
+	Answer         *string    `json:"answer,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	AnsweredAt     *time.Time `json:"answeredAt,omitempty"`
+}
+
+// UserStore holds the users of `squadron api`, their API keys and login
+// sessions, and which user started each mission through the API. Keys are
+// stored as SHA-256 hashes; the key itself is only returned when created.
+type UserStore interface {
+	// CreateUser records u and returns its ID. Names are unique.
+	CreateUser(u UserRecord) (string, error)
+	// GetUser, GetUserByName and GetUserBySubject return nil when no user
+	// matches.
+	GetUser(id string) (*UserRecord, error)
+	GetUserByName(name string) (*UserRecord, error)
+	GetUserBySubject(subject string) (*UserRecord, error)
+	ListUsers() ([]UserRecord, error)
+	SetUserRole(id, role string) error
+	// SetUserSubject links a user to an identity provider login.
+	SetUserSubject(id, subject string) error
+	// DeleteUser removes a user and its keys. Missions it owns keep their
+	// owner ID.
+	DeleteUser(id string) error
+
+	// CreateAPIKey issues a new key of kind KeyKindAPI or KeyKindSession
+	// for a user and returns its record and the key. expiresAt may be nil.
+	CreateAPIKey(userID, name, kind string, expiresAt *time.Time) (*APIKeyRecord, string, error)
+	// LookupAPIKey returns the user and record of a key that is neither
+	// revoked nor expired, and marks it used, or nils when there is none.
+	LookupAPIKey(key string) (*UserRecord, *APIKeyRecord, error)
+	// ListAPIKeys returns a user's keys of kind KeyKindAPI, newest first.
+	ListAPIKeys(userID string) ([]APIKeyRecord, error)
+	GetAPIKey(id string) (*APIKeyRecord, error)
+	RevokeAPIKey(id string) error
+
+	SetMissionOwner(missionID, userID string) error
+	// GetMissionOwner returns the ID of the user who started a mission, or
+	// "" for missions started outside the API.
+	GetMissionOwner(missionID string) (string, error)
+}
+
+// Kinds of API key
+const (
+	KeyKindAPI     = "key"     // created by a user or admin for scripts
+	KeyKindSession = "session" // issued by an identity provider login
+)
+
+// UserRecord is a user of `squadron api`. Subject is the sub claim of its
+// OIDC login; it is empty for users that only use API keys. Role is
+// "viewer", "operator" or "admin".
+type UserRecord struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email,omitempty"`
+	Subject   string    `json:"subject,omitempty"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// APIKeyRecord describes an API key or login session, without the key.
+type APIKeyRecord struct {
+	ID         string     `json:"id"`
+	UserID     string     `json:"userId"`
+	Name       string     `json:"name,omitempty"`
+	Kind       string     `json:"kind"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty"`
+}
+
+// ArtifactStore keeps the files tasks produce (a `file` output field holds a
+// reference to one). Content goes to a BlobStore addressed by its SHA-256
+// digest, so identical files are stored once; the relational store keeps a
+// record per registered artifact.
+type ArtifactStore interface {
+	// PutArtifact stores the content read from r and records it. ID, Digest,
+	// Size and CreatedAt are filled in from the content.
+	PutArtifact(a ArtifactRecord, r io.Reader) (*ArtifactRecord, error)
+	// GetArtifact returns the record, or nil if there is none.
+	GetArtifact(id string) (*ArtifactRecord, error)
+	// OpenArtifact opens the content of a recorded artifact.
+	OpenArtifact(id string) (io.ReadCloser, error)
+	// ListArtifacts returns a mission's artifacts, oldest first.
+	ListArtifacts(missionID string) ([]ArtifactRecord, error)
+}
+
+// ArtifactRecord describes one registered artifact. TaskName and Author
+// record which task, and which commander or agent, registered it.
+type ArtifactRecord struct {
+	ID        string    `json:"id"`
+	MissionID string    `json:"missionId"`
+	TaskName  string    `json:"taskName"`
+	Author    string    `json:"author"`
+	Name      string    `json:"name"`
+	MediaType string    `json:"mediaType"`
+	Digest    string    `json:"digest"`
+	Size      int64     `json:"size"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// SessionCheckpoint is a snapshot of a commander session taken between
+// turns. MessagesJSON holds the session's messages (system prompts
+// included) and ResultsJSON its stored large tool results.
+// DatasetPosition is the number of dataset items already handed out by
+// dataset_next, counted from the start of the task's dataset (nil when the
+// task has no sequential dataset).
+type SessionCheckpoint struct {
+	SessionID       string    `json:"sessionId"`
+	Turn            int       `json:"turn"`
+	MessagesJSON    string    `json:"messagesJson"`
+	ResultsJSON     string    `json:"resultsJson"`
+	DatasetPosition *int      `json:"datasetPosition,omitempty"`
+	CreatedAt       time.Time `json:"createdAt"`
+}
+
+// KnowledgeEntryRecord is one knowledge base entry with its embedding.
+// Model records which embedding produced Embedding, so entries embedded
+// differently can be told apart.
+type KnowledgeEntryRecord struct {
+	ID        string    `json:"id"`
+	MissionID string    `json:"missionId"`
+	TaskName  string    `json:"taskName"`
+	Author    string    `json:"author"`
+	Content   string    `json:"content"`
+	Tags      []string  `json:"tags,omitempty"`
+	Model     string    `json:"model"`
+	Embedding []float32 `json:"embedding"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
 // TurnCostRecord represents a single turn's cost data stored in the DB.
 type TurnCostRecord struct {
 	ID               string    `json:"id"`
@@ -265,6 +519,7 @@ type TurnCostRecord struct {
 	SessionID        string    `json:"sessionId"`
 	MissionName      string    `json:"missionName"`
 	TaskName         string    `json:"taskName"`
+	IterationIndex   *int      `json:"iterationIndex,omitempty"`
 	Entity           string    `json:"entity"`
 	Model            string    `json:"model"`
 	InputTokens      int       `json:"inputTokens"`
@@ -328,6 +583,14 @@ type HumanInputStore interface {
 	ListRequests(filter HumanInputFilter) ([]HumanInputRequestRecord, int, error)
 }
 
+// ScheduleStore records which scheduled runs have fired so that several
+// instances sharing one database start each run only once.
+type ScheduleStore interface {
+	// ClaimScheduledRun returns true if this caller is the first to claim
+	// the run of the mission's schedule (source) due at fireAt.
+	ClaimScheduledRun(missionName, source string, fireAt time.Time) (bool, error)
+}
+
 const (
 	HumanInputStateOpen     = "open"
 	HumanInputStateResolved = "resolved"
@@ -0,0 +1,118 @@
+package store
+
+import (
+	"database/sql"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ToolStats is how one commander or agent used one tool over a mission.
+type ToolStats struct {
+	Entity string `json:"entity"` // agent name, or "commander"
+	Tool   string `json:"tool"`
+	Calls  int    `json:"calls"`
+	// Errors counts calls whose result reported an error to the model
+	Errors int `json:"errors"`
+	// Interrupted counts calls that never finished, e.g. when the mission
+	// was stopped mid-call. They are left out of the latencies.
+	Interrupted int   `json:"interrupted"`
+	P50Ms       int64 `json:"p50Ms"`
+	P90Ms       int64 `json:"p90Ms"`
+	P99Ms       int64 `json:"p99Ms"`
+}
+
+// ErrorRate is the share of calls that reported an error, as a percentage.
+func (t ToolStats) ErrorRate() float64 {
+	if t.Calls == 0 {
+		return 0
+	}
+	return float64(t.Errors) / float64(t.Calls) * 100
+}
+
+// IsToolError reports whether a tool result is an error. Tools report
+// errors to the model as results starting with "Error".
+func IsToolError(rawData string) bool {
+	return strings.HasPrefix(rawData, "Error")
+}
+
+// toolStatsQuery selects every tool call of a mission with the entity that
+// made it (the ? is the mission ID).
+const toolStatsQuery = `SELECT COALESCE(NULLIF(s.agent_name, ''), s.role), tr.tool_name, COALESCE(tr.status, 'completed'), tr.is_error, tr.started_at, tr.finished_at
+	FROM tool_results tr
+	JOIN sessions s ON s.id = tr.session_id
+	JOIN mission_tasks t ON t.id = tr.task_id
+	WHERE t.mission_id = ?`
+
+// scanToolStats totals the rows of toolStatsQuery per entity and tool,
+// ordered by entity, then most called first.
+func scanToolStats(rows *sql.Rows) ([]ToolStats, error) {
+	defer rows.Close()
+	type key struct{ entity, tool string }
+	stats := map[key]*ToolStats{}
+	latencies := map[key][]time.Duration{}
+	for rows.Next() {
+		var k key
+		var status string
+		var isError bool
+		var startedAt, finishedAt sql.NullString
+		if err := rows.Scan(&k.entity, &k.tool, &status, &isError, &startedAt, &finishedAt); err != nil {
+			return nil, err
+		}
+		s, ok := stats[k]
+		if !ok {
+			s = &ToolStats{Entity: k.entity, Tool: k.tool}
+			stats[k] = s
+		}
+		s.Calls++
+		if isError {
+			s.Errors++
+		}
+		if status != "completed" || !finishedAt.Valid {
+			s.Interrupted++
+			continue
+		}
+		start, err1 := tsParse(startedAt.String)
+		end, err2 := tsParse(finishedAt.String)
+		if err1 == nil && err2 == nil {
+			latencies[k] = append(latencies[k], end.Sub(start))
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	out := make([]ToolStats, 0, len(stats))
+	for k, s := range stats {
+		d := latencies[k]
+		sort.Slice(d, func(i, j int) bool { return d[i] < d[j] })
+		s.P50Ms = percentile(d, 50).Milliseconds()
+		s.P90Ms = percentile(d, 90).Milliseconds()
+		s.P99Ms = percentile(d, 99).Milliseconds()
+		out = append(out, *s)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Entity != out[j].Entity {
+			return out[i].Entity < out[j].Entity
+		}
+		if out[i].Calls != out[j].Calls {
+			return out[i].Calls > out[j].Calls
+		}
+		return out[i].Tool < out[j].Tool
+	})
+	return out, nil
+}
+
+// percentile returns the nearest-rank p-th percentile of sorted, or 0 when
+// it is empty.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
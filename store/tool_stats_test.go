@@ -0,0 +1,80 @@
+package store_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"squadron/store"
+)
+
+var _ = Describe("Tool stats", func() {
+	var (
+		bundle  *store.Bundle
+		cleanup func()
+	)
+
+	BeforeEach(func() {
+		bundle, cleanup = newSQLiteBundle()
+	})
+
+	AfterEach(func() {
+		cleanup()
+	})
+
+	It("totals calls, errors and latency per entity and tool", func() {
+		missionID, taskID := seedMissionAndTask(bundle)
+		commander, err := bundle.Sessions.CreateSession(taskID, "commander", "", "claude_sonnet_4", nil)
+		Expect(err).NotTo(HaveOccurred())
+		agent, err := bundle.Sessions.CreateSession(taskID, "agent", "browser", "claude_sonnet_4", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		start := time.Now()
+		for i, ms := range []int{100, 200, 300, 400} {
+			result := "a page"
+			if i == 3 {
+				result = "Error: connection refused"
+			}
+			Expect(bundle.Sessions.StoreToolResult(taskID, agent, "", "plugins_playwright_browser_navigate", "{}", result,
+				start, start.Add(time.Duration(ms)*time.Millisecond))).To(Succeed())
+		}
+		id, err := bundle.Sessions.StartToolCall(taskID, agent, "tc_1", "plugins_playwright_browser_click", "{}")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(bundle.Sessions.CompleteToolCall(id, "Error: element not found")).To(Succeed())
+		_, err = bundle.Sessions.StartToolCall(taskID, agent, "tc_2", "plugins_playwright_browser_click", "{}")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(bundle.Sessions.StoreToolResult(taskID, commander, "", "call_agent", "{}", "done", start, start.Add(time.Second))).To(Succeed())
+
+		stats, err := bundle.Sessions.GetToolStatsByMission(missionID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stats).To(HaveLen(3))
+
+		navigate := stats[0]
+		Expect(navigate.Entity).To(Equal("browser"))
+		Expect(navigate.Tool).To(Equal("plugins_playwright_browser_navigate"))
+		Expect(navigate.Calls).To(Equal(4))
+		Expect(navigate.Errors).To(Equal(1))
+		Expect(navigate.ErrorRate()).To(Equal(25.0))
+		Expect(navigate.P50Ms).To(Equal(int64(200)))
+		Expect(navigate.P90Ms).To(Equal(int64(400)))
+		Expect(navigate.P99Ms).To(Equal(int64(400)))
+
+		click := stats[1]
+		Expect(click.Tool).To(Equal("plugins_playwright_browser_click"))
+		Expect(click.Calls).To(Equal(2))
+		Expect(click.Errors).To(Equal(1))
+		Expect(click.Interrupted).To(Equal(1))
+
+		Expect(stats[2].Entity).To(Equal("commander"))
+		Expect(stats[2].Tool).To(Equal("call_agent"))
+		Expect(stats[2].P50Ms).To(Equal(int64(1000)))
+	})
+
+	It("returns nothing for a mission without tool calls", func() {
+		missionID, _ := seedMissionAndTask(bundle)
+		stats, err := bundle.Sessions.GetToolStatsByMission(missionID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stats).To(BeEmpty())
+	})
+})
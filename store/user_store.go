@@ -0,0 +1,244 @@
+package store
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// apiKeyPrefix marks squadron API keys, so they are easy to spot in logs
+// and secret scanners.
+const apiKeyPrefix = "sqd_"
+
+// newAPIKey returns a random API key.
+func newAPIKey() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate api key: %w", err)
+	}
+	return apiKeyPrefix + hex.EncodeToString(b), nil
+}
+
+// hashAPIKey is what the store keeps in place of a key. Keys are random,
+// so a plain SHA-256 is enough.
+func hashAPIKey(key string) string {
+	return sha256Hex([]byte(key))
+}
+
+// SQLiteUserStore implements UserStore backed by SQLite.
+type SQLiteUserStore struct {
+	db *sql.DB
+}
+
+const (
+	userColumns   = `id, name, email, subject, role, created_at`
+	apiKeyColumns = `id, user_id, name, kind, created_at, expires_at, last_used_at, revoked_at`
+)
+
+func (s *SQLiteUserStore) CreateUser(u UserRecord) (string, error) {
+	id := generateID()
+	_, err := s.db.Exec(
+		`INSERT INTO api_users (id, name, email, subject, role, created_at) VALUES (?, ?, ?, ?, ?, ?)`,
+		id, u.Name, u.Email, nullIfEmpty(u.Subject), u.Role, tsNow(),
+	)
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (s *SQLiteUserStore) GetUser(id string) (*UserRecord, error) {
+	return scanUser(s.db.QueryRow(`SELECT `+userColumns+` FROM api_users WHERE id = ?`, id))
+}
+
+func (s *SQLiteUserStore) GetUserByName(name string) (*UserRecord, error) {
+	return scanUser(s.db.QueryRow(`SELECT `+userColumns+` FROM api_users WHERE name = ?`, name))
+}
+
+func (s *SQLiteUserStore) GetUserBySubject(subject string) (*UserRecord, error) {
+	return scanUser(s.db.QueryRow(`SELECT `+userColumns+` FROM api_users WHERE subject = ?`, subject))
+}
+
+func (s *SQLiteUserStore) ListUsers() ([]UserRecord, error) {
+	rows, err := s.db.Query(`SELECT ` + userColumns + ` FROM api_users ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	return scanUsers(rows)
+}
+
+func (s *SQLiteUserStore) SetUserRole(id, role string) error {
+	_, err := s.db.Exec(`UPDATE api_users SET role = ? WHERE id = ?`, role, id)
+	return err
+}
+
+func (s *SQLiteUserStore) SetUserSubject(id, subject string) error {
+	_, err := s.db.Exec(`UPDATE api_users SET subject = ? WHERE id = ?`, nullIfEmpty(subject), id)
+	return err
+}
+
+func (s *SQLiteUserStore) DeleteUser(id string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec(`DELETE FROM api_keys WHERE user_id = ?`, id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM api_users WHERE id = ?`, id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *SQLiteUserStore) CreateAPIKey(userID, name, kind string, expiresAt *time.Time) (*APIKeyRecord, string, error) {
+	key, err := newAPIKey()
+	if err != nil {
+		return nil, "", err
+	}
+	rec := &APIKeyRecord{ID: generateID(), UserID: userID, Name: name, Kind: kind, CreatedAt: time.Now().UTC(), ExpiresAt: expiresAt}
+	_, err = s.db.Exec(
+		`INSERT INTO api_keys (id, user_id, key_hash, name, kind, created_at, expires_at) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		rec.ID, userID, hashAPIKey(key), name, kind, tsFrom(rec.CreatedAt), tsFromPtr(expiresAt),
+	)
+	if err != nil {
+		return nil, "", err
+	}
+	return rec, key, nil
+}
+
+func (s *SQLiteUserStore) LookupAPIKey(key string) (*UserRecord, *APIKeyRecord, error) {
+	var keyID string
+	err := s.db.QueryRow(`SELECT id FROM api_keys WHERE key_hash = ? AND revoked_at IS NULL`, hashAPIKey(key)).Scan(&keyID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	rec, err := s.GetAPIKey(keyID)
+	if err != nil || rec == nil || rec.expired(time.Now()) {
+		return nil, nil, err
+	}
+	user, err := s.GetUser(rec.UserID)
+	if err != nil || user == nil {
+		return nil, nil, err
+	}
+	if _, err := s.db.Exec(`UPDATE api_keys SET last_used_at = ? WHERE id = ?`, tsNow(), keyID); err != nil {
+		return nil, nil, err
+	}
+	return user, rec, nil
+}
+
+func (s *SQLiteUserStore) ListAPIKeys(userID string) ([]APIKeyRecord, error) {
+	rows, err := s.db.Query(
+		`SELECT `+apiKeyColumns+` FROM api_keys WHERE user_id = ? AND kind = ? ORDER BY created_at DESC`,
+		userID, KeyKindAPI,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return scanAPIKeys(rows)
+}
+
+func (s *SQLiteUserStore) GetAPIKey(id string) (*APIKeyRecord, error) {
+	return scanAPIKey(s.db.QueryRow(`SELECT `+apiKeyColumns+` FROM api_keys WHERE id = ?`, id))
+}
+
+func (s *SQLiteUserStore) RevokeAPIKey(id string) error {
+	_, err := s.db.Exec(`UPDATE api_keys SET revoked_at = ? WHERE id = ? AND revoked_at IS NULL`, tsNow(), id)
+	return err
+}
+
+func (s *SQLiteUserStore) SetMissionOwner(missionID, userID string) error {
+	_, err := s.db.Exec(`UPDATE missions SET owner_id = ? WHERE id = ?`, userID, missionID)
+	return err
+}
+
+func (s *SQLiteUserStore) GetMissionOwner(missionID string) (string, error) {
+	var owner sql.NullString
+	err := s.db.QueryRow(`SELECT owner_id FROM missions WHERE id = ?`, missionID).Scan(&owner)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	return owner.String, err
+}
+
+// expired reports whether the key's expiry has passed at now.
+func (k *APIKeyRecord) expired(now time.Time) bool {
+	return k.ExpiresAt != nil && !now.Before(*k.ExpiresAt)
+}
+
+// The scanners below are shared by the SQLite and Postgres stores.
+
+func scanUserRow(row interface{ Scan(...any) error }) (*UserRecord, error) {
+	var u UserRecord
+	var subject sql.NullString
+	var createdAtStr string
+	if err := row.Scan(&u.ID, &u.Name, &u.Email, &subject, &u.Role, &createdAtStr); err != nil {
+		return nil, err
+	}
+	u.Subject = subject.String
+	u.CreatedAt, _ = tsParse(createdAtStr)
+	return &u, nil
+}
+
+func scanUser(row *sql.Row) (*UserRecord, error) {
+	u, err := scanUserRow(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return u, err
+}
+
+func scanUsers(rows *sql.Rows) ([]UserRecord, error) {
+	defer rows.Close()
+	var out []UserRecord
+	for rows.Next() {
+		u, err := scanUserRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *u)
+	}
+	return out, rows.Err()
+}
+
+func scanAPIKeyRow(row interface{ Scan(...any) error }) (*APIKeyRecord, error) {
+	var k APIKeyRecord
+	var createdAtStr string
+	var expiresAt, lastUsedAt, revokedAt sql.NullString
+	if err := row.Scan(&k.ID, &k.UserID, &k.Name, &k.Kind, &createdAtStr, &expiresAt, &lastUsedAt, &revokedAt); err != nil {
+		return nil, err
+	}
+	k.CreatedAt, _ = tsParse(createdAtStr)
+	k.ExpiresAt, _ = tsParseNull(expiresAt)
+	k.LastUsedAt, _ = tsParseNull(lastUsedAt)
+	k.RevokedAt, _ = tsParseNull(revokedAt)
+	return &k, nil
+}
+
+func scanAPIKey(row *sql.Row) (*APIKeyRecord, error) {
+	k, err := scanAPIKeyRow(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return k, err
+}
+
+func scanAPIKeys(rows *sql.Rows) ([]APIKeyRecord, error) {
+	defer rows.Close()
+	var out []APIKeyRecord
+	for rows.Next() {
+		k, err := scanAPIKeyRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *k)
+	}
+	return out, rows.Err()
+}
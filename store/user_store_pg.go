@@ -0,0 +1,142 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// PgUserStore implements UserStore backed by Postgres.
+type PgUserStore struct {
+	db *sql.DB
+}
+
+func (s *PgUserStore) CreateUser(u UserRecord) (string, error) {
+	id := generateID()
+	_, err := s.db.Exec(
+		`INSERT INTO api_users (id, name, email, subject, role, created_at) VALUES ($1, $2, $3, $4, $5, $6)`,
+		id, u.Name, u.Email, nullIfEmpty(u.Subject), u.Role, tsNow(),
+	)
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (s *PgUserStore) GetUser(id string) (*UserRecord, error) {
+	return scanUser(s.db.QueryRow(`SELECT `+userColumns+` FROM api_users WHERE id = $1`, id))
+}
+
+func (s *PgUserStore) GetUserByName(name string) (*UserRecord, error) {
+	return scanUser(s.db.QueryRow(`SELECT `+userColumns+` FROM api_users WHERE name = $1`, name))
+}
+
+func (s *PgUserStore) GetUserBySubject(subject string) (*UserRecord, error) {
+	return scanUser(s.db.QueryRow(`SELECT `+userColumns+` FROM api_users WHERE subject = $1`, subject))
+}
+
+func (s *PgUserStore) ListUsers() ([]UserRecord, error) {
+	rows, err := s.db.Query(`SELECT ` + userColumns + ` FROM api_users ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	return scanUsers(rows)
+}
+
+func (s *PgUserStore) SetUserRole(id, role string) error {
+	_, err := s.db.Exec(`UPDATE api_users SET role = $1 WHERE id = $2`, role, id)
+	return err
+}
+
+func (s *PgUserStore) SetUserSubject(id, subject string) error {
+	_, err := s.db.Exec(`UPDATE api_users SET subject = $1 WHERE id = $2`, nullIfEmpty(subject), id)
+	return err
+}
+
+func (s *PgUserStore) DeleteUser(id string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec(`DELETE FROM api_keys WHERE user_id = $1`, id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM api_users WHERE id = $1`, id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *PgUserStore) CreateAPIKey(userID, name, kind string, expiresAt *time.Time) (*APIKeyRecord, string, error) {
+	key, err := newAPIKey()
+	if err != nil {
+		return nil, "", err
+	}
+	rec := &APIKeyRecord{ID: generateID(), UserID: userID, Name: name, Kind: kind, CreatedAt: time.Now().UTC(), ExpiresAt: expiresAt}
+	_, err = s.db.Exec(
+		`INSERT INTO api_keys (id, user_id, key_hash, name, kind, created_at, expires_at) VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		rec.ID, userID, hashAPIKey(key), name, kind, tsFrom(rec.CreatedAt), tsFromPtr(expiresAt),
+	)
+	if err != nil {
+		return nil, "", err
+	}
+	return rec, key, nil
+}
+
+func (s *PgUserStore) LookupAPIKey(key string) (*UserRecord, *APIKeyRecord, error) {
+	var keyID string
+	err := s.db.QueryRow(`SELECT id FROM api_keys WHERE key_hash = $1 AND revoked_at IS NULL`, hashAPIKey(key)).Scan(&keyID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	rec, err := s.GetAPIKey(keyID)
+	if err != nil || rec == nil || rec.expired(time.Now()) {
+		return nil, nil, err
+	}
+	user, err := s.GetUser(rec.UserID)
+	if err != nil || user == nil {
+		return nil, nil, err
+	}
+	if _, err := s.db.Exec(`UPDATE api_keys SET last_used_at = $1 WHERE id = $2`, tsNow(), keyID); err != nil {
+		return nil, nil, err
+	}
+	return user, rec, nil
+}
+
+func (s *PgUserStore) ListAPIKeys(userID string) ([]APIKeyRecord, error) {
+	rows, err := s.db.Query(
+		`SELECT `+apiKeyColumns+` FROM api_keys WHERE user_id = $1 AND kind = $2 ORDER BY created_at DESC`,
+		userID, KeyKindAPI,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return scanAPIKeys(rows)
+}
+
+func (s *PgUserStore) GetAPIKey(id string) (*APIKeyRecord, error) {
+	return scanAPIKey(s.db.QueryRow(`SELECT `+apiKeyColumns+` FROM api_keys WHERE id = $1`, id))
+}
+
+func (s *PgUserStore) RevokeAPIKey(id string) error {
+	_, err := s.db.Exec(`UPDATE api_keys SET revoked_at = $1 WHERE id = $2 AND revoked_at IS NULL`, tsNow(), id)
+	return err
+}
+
+func (s *PgUserStore) SetMissionOwner(missionID, userID string) error {
+	_, err := s.db.Exec(`UPDATE missions SET owner_id = $1 WHERE id = $2`, userID, missionID)
+	return err
+}
+
+func (s *PgUserStore) GetMissionOwner(missionID string) (string, error) {
+	var owner sql.NullString
+	err := s.db.QueryRow(`SELECT owner_id FROM missions WHERE id = $1`, missionID).Scan(&owner)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	return owner.String, err
+}
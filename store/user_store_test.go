@@ -0,0 +1,139 @@
+package store_test
+
+import (
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"squadron/store"
+)
+
+var _ = Describe("SQLite UserStore", func() {
+	var (
+		bundle  *store.Bundle
+		cleanup func()
+	)
+
+	BeforeEach(func() {
+		bundle, cleanup = newSQLiteBundle()
+	})
+
+	AfterEach(func() {
+		cleanup()
+	})
+
+	It("creates, finds and updates users", func() {
+		id, err := bundle.Users.CreateUser(store.UserRecord{Name: "alice", Email: "alice@example.com", Role: "operator"})
+		Expect(err).NotTo(HaveOccurred())
+
+		byName, err := bundle.Users.GetUserByName("alice")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(byName.ID).To(Equal(id))
+		Expect(byName.Role).To(Equal("operator"))
+		Expect(byName.Subject).To(BeEmpty())
+
+		Expect(bundle.Users.SetUserRole(id, "admin")).To(Succeed())
+		Expect(bundle.Users.SetUserSubject(id, "idp|123")).To(Succeed())
+		bySubject, err := bundle.Users.GetUserBySubject("idp|123")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(bySubject.ID).To(Equal(id))
+		Expect(bySubject.Role).To(Equal("admin"))
+
+		_, err = bundle.Users.CreateUser(store.UserRecord{Name: "alice", Role: "viewer"})
+		Expect(err).To(HaveOccurred())
+
+		missing, err := bundle.Users.GetUserByName("bob")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(missing).To(BeNil())
+	})
+
+	It("lists users by name", func() {
+		_, _ = bundle.Users.CreateUser(store.UserRecord{Name: "zoe", Role: "viewer"})
+		_, _ = bundle.Users.CreateUser(store.UserRecord{Name: "adam", Role: "admin"})
+
+		users, err := bundle.Users.ListUsers()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(users).To(HaveLen(2))
+		Expect(users[0].Name).To(Equal("adam"))
+		Expect(users[1].Name).To(Equal("zoe"))
+	})
+
+	It("stores only a hash of API keys and resolves them to their user", func() {
+		id, _ := bundle.Users.CreateUser(store.UserRecord{Name: "alice", Role: "operator"})
+
+		rec, key, err := bundle.Users.CreateAPIKey(id, "ci", store.KeyKindAPI, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(strings.HasPrefix(key, "sqd_")).To(BeTrue())
+
+		user, got, err := bundle.Users.LookupAPIKey(key)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(user.Name).To(Equal("alice"))
+		Expect(got.ID).To(Equal(rec.ID))
+
+		keys, err := bundle.Users.ListAPIKeys(id)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(keys).To(HaveLen(1))
+		Expect(keys[0].Name).To(Equal("ci"))
+		Expect(keys[0].LastUsedAt).NotTo(BeNil())
+
+		user, _, err = bundle.Users.LookupAPIKey("sqd_wrong")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(user).To(BeNil())
+	})
+
+	It("rejects revoked and expired keys", func() {
+		id, _ := bundle.Users.CreateUser(store.UserRecord{Name: "alice", Role: "operator"})
+
+		rec, key, _ := bundle.Users.CreateAPIKey(id, "", store.KeyKindAPI, nil)
+		Expect(bundle.Users.RevokeAPIKey(rec.ID)).To(Succeed())
+		user, _, err := bundle.Users.LookupAPIKey(key)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(user).To(BeNil())
+
+		past := time.Now().Add(-time.Minute)
+		_, key, _ = bundle.Users.CreateAPIKey(id, "", store.KeyKindSession, &past)
+		user, _, err = bundle.Users.LookupAPIKey(key)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(user).To(BeNil())
+	})
+
+	It("leaves session keys out of the key list", func() {
+		id, _ := bundle.Users.CreateUser(store.UserRecord{Name: "alice", Role: "viewer"})
+		future := time.Now().Add(time.Hour)
+		_, _, err := bundle.Users.CreateAPIKey(id, "", store.KeyKindSession, &future)
+		Expect(err).NotTo(HaveOccurred())
+
+		keys, err := bundle.Users.ListAPIKeys(id)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(keys).To(BeEmpty())
+	})
+
+	It("deletes a user together with their keys", func() {
+		id, _ := bundle.Users.CreateUser(store.UserRecord{Name: "alice", Role: "viewer"})
+		_, key, _ := bundle.Users.CreateAPIKey(id, "", store.KeyKindAPI, nil)
+
+		Expect(bundle.Users.DeleteUser(id)).To(Succeed())
+		user, _, err := bundle.Users.LookupAPIKey(key)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(user).To(BeNil())
+		gone, err := bundle.Users.GetUser(id)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(gone).To(BeNil())
+	})
+
+	It("records mission owners", func() {
+		missionID, _ := seedMissionAndTask(bundle)
+		id, _ := bundle.Users.CreateUser(store.UserRecord{Name: "alice", Role: "operator"})
+
+		owner, err := bundle.Users.GetMissionOwner(missionID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(owner).To(BeEmpty())
+
+		Expect(bundle.Users.SetMissionOwner(missionID, id)).To(Succeed())
+		owner, err = bundle.Users.GetMissionOwner(missionID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(owner).To(Equal(id))
+	})
+})
@@ -0,0 +1,27 @@
+package streamers
+
+import "github.com/mlund01/squadron-wire/protocol"
+
+// Approval event types for tasks with require_approval. Defined locally like
+// EventMissionIssue until the shape settles in squadron-wire.
+const (
+	EventTaskApprovalRequested protocol.MissionEventType = "task_approval_requested"
+	EventTaskApprovalResolved  protocol.MissionEventType = "task_approval_resolved"
+)
+
+// TaskApprovalRequestedData carries the commander's draft result while the
+// task waits for review.
+type TaskApprovalRequestedData struct {
+	TaskName string         `json:"taskName"`
+	Summary  string         `json:"summary,omitempty"`
+	Output   map[string]any `json:"output,omitempty"`
+}
+
+// TaskApprovalResolvedData records the reviewer's decision. Edited is set
+// when the approved summary or output differs from the draft.
+type TaskApprovalResolvedData struct {
+	TaskName string `json:"taskName"`
+	Approved bool   `json:"approved"`
+	Edited   bool   `json:"edited,omitempty"`
+	Reason   string `json:"reason,omitempty"` // rejection reason, when given
+}
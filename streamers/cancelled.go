@@ -0,0 +1,23 @@
+package streamers
+
+import "github.com/mlund01/squadron-wire/protocol"
+
+// Cancellation events. Defined locally like EventTaskSkipped until the
+// shape settles in squadron-wire.
+const (
+	EventTaskCancelled    protocol.MissionEventType = "task_cancelled"
+	EventMissionCancelled protocol.MissionEventType = "mission_cancelled"
+)
+
+// TaskCancelledData describes a task interrupted by a mission cancellation.
+type TaskCancelledData struct {
+	TaskName string `json:"taskName"`
+	Reason   string `json:"reason"`
+	Summary  string `json:"summary,omitempty"` // the commanders' partial summaries
+}
+
+// MissionCancelledData describes a cancelled mission.
+type MissionCancelledData struct {
+	MissionName string `json:"missionName"`
+	Reason      string `json:"reason"`
+}
@@ -77,6 +77,13 @@ func (s *ChatHandler) ToolComplete(toolCallId string, toolName string, result st
 	fmt.Printf("%s✓%s %s%s%s called\n\n", ColorGray, ColorReset, ColorBold, toolName, ColorReset)
 }
 
+// ToolProgress implements streamers.ToolProgressHandler by showing the latest
+// update in the tool's spinner.
+func (s *ChatHandler) ToolProgress(toolCallId string, toolName string, content string) {
+	s.spinner.Stop()
+	s.spinner.Start("", fmt.Sprintf("Calling %s%s%s... %s%s%s", ColorBold, toolName, ColorReset, ColorGray, content, ColorReset))
+}
+
 func (s *ChatHandler) ReasoningStarted() {
 	// CLI display handled in PublishReasoningChunk on first chunk
 }
@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/mlund01/squadron-wire/protocol"
+	"squadron/streamers"
+)
+
+// CommanderChatHandler is the terminal side of an interactive chat with a
+// commander: it reads the human's messages and shows the commander's
+// reasoning, tool calls, agent activity, and replies.
+type CommanderChatHandler struct {
+	*ChatHandler
+	mu sync.Mutex
+}
+
+// NewCommanderChatHandler creates a new CLI commander chat handler
+func NewCommanderChatHandler() *CommanderChatHandler {
+	return &CommanderChatHandler{ChatHandler: NewChatHandler()}
+}
+
+func (s *CommanderChatHandler) Welcome(agents []string, modelName string, chatID string) {
+	fmt.Printf("%s%sStarting chat with a commander%s (model: %s, agents: %s)\n", ColorBold, ColorOrange, ColorReset, modelName, strings.Join(agents, ", "))
+	fmt.Printf("%sChat ID: %s. Type 'exit' or 'quit' to end the conversation.%s\n", ColorGray, chatID, ColorReset)
+	fmt.Println()
+}
+
+func (s *CommanderChatHandler) ReasoningStarted() {
+	s.spinner.Stop()
+	s.spinner.Start("", "Reasoning...")
+}
+
+func (s *CommanderChatHandler) ReasoningCompleted(content string) {
+	s.spinner.Stop()
+	if content == "" {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fmt.Printf("%s%sReasoning%s\n", ColorBold, ColorMagenta, ColorReset)
+	fmt.Printf("%s%s%s%s\n\n", ColorItalic, ColorMagenta, strings.TrimSpace(content), ColorReset)
+}
+
+func (s *CommanderChatHandler) Answer(content string) {
+	s.spinner.Stop()
+	rendered := content
+	if s.renderer != nil {
+		if out, err := s.renderer.Render(content); err == nil {
+			rendered = out
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fmt.Printf("%s•%s%s\n\n", ColorGray, ColorReset, strings.TrimSpace(rendered))
+}
+
+func (s *CommanderChatHandler) CallingTool(toolCallId, name, input string) {
+	s.spinner.Stop()
+	// Agents report their own progress; a spinner would interleave with it
+	if name == "call_agent" || name == "call_agents_parallel" || name == "task_complete" {
+		return
+	}
+	s.spinner.Start("", fmt.Sprintf("Calling %s%s%s...", ColorBold, name, ColorReset))
+}
+
+func (s *CommanderChatHandler) ToolComplete(toolCallId, name string, result string) {
+	s.spinner.Stop()
+	if name == "task_complete" {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fmt.Printf("%s✓%s %s%s%s called\n\n", ColorGray, ColorReset, ColorBold, name, ColorReset)
+}
+
+func (s *CommanderChatHandler) Compaction(inputTokens int, tokenLimit int, messagesCompacted int, turnRetention int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fmt.Printf("%sContext compacted: %d tokens > %d limit, %d messages compacted%s\n",
+		ColorYellow, inputTokens, tokenLimit, messagesCompacted, ColorReset)
+}
+
+func (s *CommanderChatHandler) SessionTurn(data protocol.SessionTurnData) {
+	// No-op for CLI — telemetry is primarily for the web UI
+}
+
+func (s *CommanderChatHandler) AgentStarted(agentName string, instruction string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fmt.Printf("%sRunning agent '%s': %s%s\n", ColorLightBrown, agentName, truncate(instruction, 100), ColorReset)
+}
+
+func (s *CommanderChatHandler) AgentHandler(agentName string) streamers.ChatHandler {
+	return &agentHandler{
+		taskName:  "chat",
+		agentName: agentName,
+		mu:        &s.mu,
+		printf:    func(format string, args ...any) { fmt.Printf(format, args...) },
+	}
+}
+
+func (s *CommanderChatHandler) AgentCompleted(agentName string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fmt.Printf("%sAgent '%s' finished%s\n\n", ColorLightBrown, agentName, ColorReset)
+}
@@ -2,54 +2,131 @@ package cli
 
 import (
 	"fmt"
+	"io"
+	"os"
 	"strings"
 	"sync"
 
 	"github.com/mlund01/squadron-wire/protocol"
+	"golang.org/x/term"
 	"squadron/streamers"
 )
 
-// MissionHandler implements streamers.MissionHandler for CLI output
+// MissionHandler implements streamers.MissionHandler for CLI output. On a
+// terminal it keeps a status line per running task at the bottom of the
+// output, with elapsed time, turns, and token and cost totals, so a runaway
+// task stands out. Parallel iterated tasks show a progress bar there instead
+// of each iteration's output.
 type MissionHandler struct {
-	mu sync.Mutex
+	mu  sync.Mutex
+	out io.Writer
+
+	live         bool // draw status lines (stdout is a terminal)
+	running      []*taskStatus
+	drawn        int  // status lines currently on screen
+	partialLine  bool // the last output didn't end its line
+	statusPaused bool // an approval prompt owns the terminal
+	stopTicker   chan struct{}
 }
 
 // NewMissionHandler creates a new CLI mission handler
 func NewMissionHandler() *MissionHandler {
-	return &MissionHandler{}
+	return &MissionHandler{
+		out:  os.Stdout,
+		live: term.IsTerminal(int(os.Stdout.Fd())),
+	}
 }
 
 func (s *MissionHandler) MissionStarted(name string, missionID string, taskCount int) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	fmt.Printf("\n%s%s=== Mission: %s ===%s\n", ColorBold, ColorCyan, name, ColorReset)
-	fmt.Printf("%sMission ID: %s%s\n", ColorGray, missionID, ColorReset)
-	fmt.Printf("%sTasks: %d%s\n\n", ColorGray, taskCount, ColorReset)
+	s.printf("\n%s%s=== Mission: %s ===%s\n", ColorBold, ColorCyan, name, ColorReset)
+	s.printf("%sMission ID: %s%s\n", ColorGray, missionID, ColorReset)
+	s.printf("%sTasks: %d%s\n\n", ColorGray, taskCount, ColorReset)
 }
 
 func (s *MissionHandler) MissionCompleted(name string) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	fmt.Printf("\n%s%s=== Mission '%s' completed ===%s\n", ColorBold, ColorGreen, name, ColorReset)
+	s.finishStatus()
+	s.printf("\n%s%s=== Mission '%s' completed ===%s\n", ColorBold, ColorGreen, name, ColorReset)
 }
 
 func (s *MissionHandler) TaskStarted(taskName string, objective string) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	fmt.Printf("\n%s%s--- Task: %s ---%s\n", ColorBold, ColorCyan, taskName, ColorReset)
-	fmt.Printf("%sObjective: %s%s\n\n", ColorGray, objective, ColorReset)
+	s.startTask(taskName, 0)
+	s.printf("\n%s%s--- Task: %s ---%s\n", ColorBold, ColorCyan, taskName, ColorReset)
+	s.printf("%sObjective: %s%s\n\n", ColorGray, objective, ColorReset)
 }
 
 func (s *MissionHandler) TaskCompleted(taskName string) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	fmt.Printf("\n%s%s[Task '%s' completed]%s\n", ColorBold, ColorGreen, taskName, ColorReset)
+	s.finishTask(taskName)
+	s.printf("\n%s%s[Task '%s' completed]%s\n", ColorBold, ColorGreen, taskName, ColorReset)
 }
 
 func (s *MissionHandler) TaskFailed(taskName string, err error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	fmt.Printf("\n%s%s[Task '%s' FAILED: %v]%s\n", ColorBold, ColorRed, taskName, err, ColorReset)
+	s.finishTask(taskName)
+	s.printf("\n%s%s[Task '%s' FAILED: %v]%s\n", ColorBold, ColorRed, taskName, err, ColorReset)
+}
+
+// TaskApprovalRequested implements streamers.ApprovalHandler. The draft
+// itself is shown by whichever approver prompts for the decision.
+func (s *MissionHandler) TaskApprovalRequested(data streamers.TaskApprovalRequestedData) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clearStatus()
+	s.statusPaused = true
+	s.printf("\n%s%s[Task '%s' awaiting approval]%s\n", ColorBold, ColorYellow, data.TaskName, ColorReset)
+}
+
+// TaskApprovalResolved implements streamers.ApprovalHandler.
+func (s *MissionHandler) TaskApprovalResolved(data streamers.TaskApprovalResolvedData) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.statusPaused = false
+	switch {
+	case !data.Approved:
+		s.printf("%s[Task '%s' rejected]%s\n", ColorRed, data.TaskName, ColorReset)
+	case data.Edited:
+		s.printf("%s[Task '%s' approved with edits]%s\n", ColorGreen, data.TaskName, ColorReset)
+	default:
+		s.printf("%s[Task '%s' approved]%s\n", ColorGreen, data.TaskName, ColorReset)
+	}
+}
+
+// TaskCancelled implements streamers.CancelHandler.
+func (s *MissionHandler) TaskCancelled(data streamers.TaskCancelledData) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.finishTask(data.TaskName)
+	s.printf("\n%s%s[Task '%s' cancelled]%s\n", ColorBold, ColorYellow, data.TaskName, ColorReset)
+	if data.Summary != "" {
+		s.printf("%s%s%s\n", ColorGray, data.Summary, ColorReset)
+	}
+}
+
+// MissionCancelled implements streamers.CancelHandler.
+func (s *MissionHandler) MissionCancelled(data streamers.MissionCancelledData) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.finishStatus()
+	s.printf("\n%s%s[Mission '%s' cancelled: %s]%s\n", ColorBold, ColorYellow, data.MissionName, data.Reason, ColorReset)
+}
+
+// TaskSkipped implements streamers.TaskSkipHandler.
+func (s *MissionHandler) TaskSkipped(data streamers.TaskSkippedData) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if data.When == "" {
+		s.printf("\n%s[Task '%s' skipped]%s\n", ColorGray, data.TaskName, ColorReset)
+		return
+	}
+	s.printf("\n%s[Task '%s' skipped: when = %s]%s\n", ColorGray, data.TaskName, data.When, ColorReset)
 }
 
 func (s *MissionHandler) CommanderReasoningStarted(taskName string) {
@@ -59,56 +136,83 @@ func (s *MissionHandler) CommanderReasoningStarted(taskName string) {
 func (s *MissionHandler) CommanderReasoningCompleted(taskName string, content string) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	fmt.Printf("[%s] Thinking: %s\n", taskName, truncate(content, 100))
+	if s.quiet(taskName) {
+		return
+	}
+	s.printf("[%s] Thinking: %s\n", taskName, truncate(content, 100))
 }
 
 func (s *MissionHandler) CommanderAnswer(taskName string, content string) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	fmt.Printf("[%s] Answer:\n%s\n", taskName, content)
+	if s.quiet(taskName) {
+		return
+	}
+	s.printf("[%s] Answer:\n%s\n", taskName, content)
 }
 
 func (s *MissionHandler) CommanderCallingTool(taskName string, toolCallId string, toolName string, input string) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	fmt.Printf("[%s] Calling: %s\n", taskName, toolName)
+	if s.quiet(taskName) {
+		return
+	}
+	s.printf("[%s] Calling: %s\n", taskName, toolName)
 }
 
 func (s *MissionHandler) CommanderToolComplete(taskName string, toolCallId string, toolName string, result string) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	fmt.Printf("[%s] %s complete\n", taskName, toolName)
+	if s.quiet(taskName) {
+		return
+	}
+	s.printf("[%s] %s complete\n", taskName, toolName)
 }
 
 func (s *MissionHandler) AgentStarted(taskName string, agentName string, instruction string) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	fmt.Printf("%s[%s] Running agent '%s'...%s\n", ColorLightBrown, taskName, agentName, ColorReset)
+	if s.quiet(taskName) {
+		return
+	}
+	s.printf("%s[%s] Running agent '%s'...%s\n", ColorLightBrown, taskName, agentName, ColorReset)
 }
 
 func (s *MissionHandler) AgentHandler(taskName string, agentName string) streamers.ChatHandler {
+	s.mu.Lock()
+	quiet := s.quiet(taskName)
+	s.mu.Unlock()
 	return &agentHandler{
 		taskName:  taskName,
 		agentName: agentName,
 		mu:        &s.mu,
+		printf:    s.printf,
+		quiet:     quiet,
 	}
 }
 
 func (s *MissionHandler) AgentCompleted(taskName string, agentName string) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	fmt.Printf("%s[%s] Agent '%s' finished%s\n", ColorLightBrown, taskName, agentName, ColorReset)
+	if s.quiet(taskName) {
+		return
+	}
+	s.printf("%s[%s] Agent '%s' finished%s\n", ColorLightBrown, taskName, agentName, ColorReset)
 }
 
 func (s *MissionHandler) Compaction(taskName string, entity string, inputTokens int, tokenLimit int, messagesCompacted int, turnRetention int) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	fmt.Printf("%s[%s] Context compacted (%s): %d tokens > %d limit, %d messages compacted%s\n",
+	s.printf("%s[%s] Context compacted (%s): %d tokens > %d limit, %d messages compacted%s\n",
 		ColorYellow, taskName, entity, inputTokens, tokenLimit, messagesCompacted, ColorReset)
 }
 
+// SessionTurn adds each LLM turn's usage to its task's status line.
 func (s *MissionHandler) SessionTurn(data protocol.SessionTurnData) {
-	// No-op for CLI — telemetry is primarily for the web UI
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.recordTurn(data)
+	s.redrawStatus()
 }
 
 func (s *MissionHandler) MissionIssue(data streamers.MissionIssueData) {
@@ -132,7 +236,7 @@ func (s *MissionHandler) MissionIssue(data streamers.MissionIssueData) {
 	if data.Retrying {
 		retrying = " (retrying)"
 	}
-	fmt.Printf("%s%s%s %s%s: %s%s%s\n",
+	s.printf("%s%s%s %s%s: %s%s%s\n",
 		ColorBold, color, label, data.Category, scope, data.Message, retrying, ColorReset)
 }
 
@@ -140,9 +244,9 @@ func (s *MissionHandler) RouteChosen(routerTask string, targetTask string, condi
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	if isMission {
-		fmt.Printf("%s[%s] Route chosen → mission:%s (condition: %s)%s\n", ColorCyan, routerTask, targetTask, condition, ColorReset)
+		s.printf("%s[%s] Route chosen → mission:%s (condition: %s)%s\n", ColorCyan, routerTask, targetTask, condition, ColorReset)
 	} else {
-		fmt.Printf("%s[%s] Route chosen → %s (condition: %s)%s\n", ColorCyan, routerTask, targetTask, condition, ColorReset)
+		s.printf("%s[%s] Route chosen → %s (condition: %s)%s\n", ColorCyan, routerTask, targetTask, condition, ColorReset)
 	}
 }
 
@@ -151,53 +255,82 @@ func (s *MissionHandler) RouteChosen(routerTask string, targetTask string, condi
 func (s *MissionHandler) TaskIterationStarted(taskName string, totalItems int, parallel bool) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	s.startTask(taskName, totalItems)
 	mode := "sequential"
 	if parallel {
 		mode = "parallel"
 	}
-	fmt.Printf("\n%s%s--- Task: %s (iterating %d items, %s) ---%s\n", ColorBold, ColorCyan, taskName, totalItems, mode, ColorReset)
+	s.printf("\n%s%s--- Task: %s (iterating %d items, %s) ---%s\n", ColorBold, ColorCyan, taskName, totalItems, mode, ColorReset)
 }
 
 func (s *MissionHandler) TaskIterationCompleted(taskName string, completedCount int) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	fmt.Printf("\n%s%s[Task '%s' iterations completed: %d]%s\n", ColorBold, ColorGreen, taskName, completedCount, ColorReset)
+	s.printf("\n%s%s[Task '%s' iterations completed: %d]%s\n", ColorBold, ColorGreen, taskName, completedCount, ColorReset)
 }
 
 func (s *MissionHandler) IterationStarted(taskName string, index int, objective string) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	fmt.Printf("\n  [%s][%d] Starting: %s\n", taskName, index, truncate(objective, 80))
+	if s.quiet(taskName) {
+		return
+	}
+	s.printf("\n  [%s][%d] Starting: %s\n", taskName, index, truncate(objective, 80))
 }
 
 func (s *MissionHandler) IterationCompleted(taskName string, index int) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	fmt.Printf("  [%s][%d] Completed\n", taskName, index)
+	if t := s.task(taskName); t != nil && !t.progress {
+		t.itemsDone++
+	}
+	if s.quiet(taskName) {
+		return
+	}
+	s.printf("  [%s][%d] Completed\n", taskName, index)
 }
 
 func (s *MissionHandler) IterationFailed(taskName string, index int, err error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	fmt.Printf("  [%s][%d] FAILED: %v\n", taskName, index, err)
+	if t := s.task(taskName); t != nil && !t.progress {
+		t.itemsDone++
+	}
+	s.printf("  [%s][%d] FAILED: %v\n", taskName, index, err)
 }
 
 func (s *MissionHandler) IterationRetrying(taskName string, index int, attempt int, maxRetries int, err error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	fmt.Printf("  [%s][%d] Retrying (%d/%d): %v\n", taskName, index, attempt, maxRetries, err)
+	s.printf("  [%s][%d] Retrying (%d/%d): %v\n", taskName, index, attempt, maxRetries, err)
 }
 
 func (s *MissionHandler) IterationReasoning(taskName string, index int, content string) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	fmt.Printf("  [%s][%d] Thinking: %s\n", taskName, index, truncate(content, 80))
+	if s.quiet(taskName) {
+		return
+	}
+	s.printf("  [%s][%d] Thinking: %s\n", taskName, index, truncate(content, 80))
 }
 
 func (s *MissionHandler) IterationAnswer(taskName string, index int, content string) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	fmt.Printf("  [%s][%d] Answer: %s\n", taskName, index, truncate(content, 100))
+	if s.quiet(taskName) {
+		return
+	}
+	s.printf("  [%s][%d] Answer: %s\n", taskName, index, truncate(content, 100))
+}
+
+// IterationProgress implements streamers.IterationProgressHandler. On a
+// terminal it drives the task's progress bar; elsewhere the per-iteration
+// lines are printed as usual and this adds nothing.
+func (s *MissionHandler) IterationProgress(data streamers.IterationProgressData) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.setProgress(data)
+	s.redrawStatus()
 }
 
 
@@ -216,6 +349,8 @@ type agentHandler struct {
 	taskName         string
 	agentName        string
 	mu               *sync.Mutex
+	printf           func(format string, args ...any) // called with mu held
+	quiet            bool                             // a progress bar stands in for its output
 	reasoningStarted bool
 	answerBuffer     strings.Builder
 }
@@ -236,25 +371,44 @@ func (s *agentHandler) Goodbye() {
 func (s *agentHandler) Error(err error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	fmt.Printf("%s    [%s/%s] Error: %v%s\n", ColorLightBrown, s.taskName, s.agentName, err, ColorReset)
+	s.printf("%s    [%s/%s] Error: %v%s\n", ColorLightBrown, s.taskName, s.agentName, err, ColorReset)
 }
 
 func (s *agentHandler) Thinking() {
+	if s.quiet {
+		return
+	}
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	fmt.Printf("%s    [%s/%s] Thinking...%s\n", ColorLightBrown, s.taskName, s.agentName, ColorReset)
+	s.printf("%s    [%s/%s] Thinking...%s\n", ColorLightBrown, s.taskName, s.agentName, ColorReset)
 }
 
 func (s *agentHandler) CallingTool(toolCallId, toolName, payload string) {
+	if s.quiet {
+		return
+	}
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	fmt.Printf("%s    [%s/%s] Calling %s...%s\n", ColorLightBrown, s.taskName, s.agentName, toolName, ColorReset)
+	s.printf("%s    [%s/%s] Calling %s...%s\n", ColorLightBrown, s.taskName, s.agentName, toolName, ColorReset)
 }
 
 func (s *agentHandler) ToolComplete(toolCallId string, toolName string, result string) {
+	if s.quiet {
+		return
+	}
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	fmt.Printf("%s    [%s/%s] %s complete%s\n", ColorLightBrown, s.taskName, s.agentName, toolName, ColorReset)
+	s.printf("%s    [%s/%s] %s complete%s\n", ColorLightBrown, s.taskName, s.agentName, toolName, ColorReset)
+}
+
+// ToolProgress implements streamers.ToolProgressHandler.
+func (s *agentHandler) ToolProgress(toolCallId string, toolName string, content string) {
+	if s.quiet {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.printf("%s    [%s/%s] %s: %s%s\n", ColorGray, s.taskName, s.agentName, toolName, content, ColorReset)
 }
 
 func (s *agentHandler) ReasoningStarted() {
@@ -262,26 +416,32 @@ func (s *agentHandler) ReasoningStarted() {
 }
 
 func (s *agentHandler) PublishReasoningChunk(chunk string) {
+	if s.quiet {
+		return
+	}
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	if !s.reasoningStarted {
-		fmt.Printf("%s    [%s/%s] Reasoning: ", ColorLightBrown, s.taskName, s.agentName)
+		s.printf("%s    [%s/%s] Reasoning: ", ColorLightBrown, s.taskName, s.agentName)
 		s.reasoningStarted = true
 	}
 	// Stream reasoning inline in light brown italic
-	fmt.Printf("%s%s", ColorItalic, chunk)
+	s.printf("%s%s", ColorItalic, chunk)
 }
 
 func (s *agentHandler) ReasoningCompleted() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	if s.reasoningStarted {
-		fmt.Printf("%s\n", ColorReset)
+		s.printf("%s\n", ColorReset)
 		s.reasoningStarted = false
 	}
 }
 
 func (s *agentHandler) PublishAnswerChunk(chunk string) {
+	if s.quiet {
+		return
+	}
 	// Buffer the answer
 	s.answerBuffer.WriteString(chunk)
 }
@@ -293,16 +453,19 @@ func (s *agentHandler) FinishAnswer() {
 	if answer != "" {
 		// Show a truncated version of the answer
 		truncated := truncate(answer, 200)
-		fmt.Printf("%s    [%s/%s] Answer: %s%s\n", ColorLightBrown, s.taskName, s.agentName, truncated, ColorReset)
+		s.printf("%s    [%s/%s] Answer: %s%s\n", ColorLightBrown, s.taskName, s.agentName, truncated, ColorReset)
 	}
 	s.answerBuffer.Reset()
 }
 
 func (s *agentHandler) AskCommander(content string) {
+	if s.quiet {
+		return
+	}
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	truncated := truncate(content, 200)
-	fmt.Printf("%s    [%s/%s] Ask Commander: %s%s\n", ColorLightBrown, s.taskName, s.agentName, truncated, ColorReset)
+	s.printf("%s    [%s/%s] Ask Commander: %s%s\n", ColorLightBrown, s.taskName, s.agentName, truncated, ColorReset)
 }
 
 func (s *agentHandler) CommanderResponse(content string) {}
@@ -0,0 +1,262 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mlund01/squadron-wire/protocol"
+	"golang.org/x/term"
+	"squadron/streamers"
+)
+
+// statusInterval is how often the status lines are redrawn to advance the
+// elapsed times.
+const statusInterval = time.Second
+
+// taskStatus is the running total of one task shown in its status line.
+// Turns and spend include its agents and, for iterated tasks, every
+// iteration.
+type taskStatus struct {
+	name         string
+	started      time.Time
+	turns        int
+	inputTokens  int
+	outputTokens int
+	cost         float64
+	items        int // dataset size, for iterated tasks
+	itemsDone    int
+
+	// Set by IterationProgress for parallel iterated tasks, which then get
+	// a progress bar in place of per-iteration output
+	progress bool
+	failed   int
+	inFlight int
+	etaAt    time.Time // zero until an iteration finishes
+}
+
+// printf writes event output. On a terminal it first erases the status
+// lines and redraws them below the new output, so they stay at the bottom.
+// Callers hold s.mu.
+func (s *MissionHandler) printf(format string, args ...any) {
+	s.clearStatus()
+	text := fmt.Sprintf(format, args...)
+	io.WriteString(s.out, text)
+	// A line still open (streamed reasoning) must not be broken by the
+	// status lines; they come back once it ends
+	s.partialLine = !strings.HasSuffix(text, "\n")
+	s.drawStatus()
+}
+
+// clearStatus erases the status lines drawn last.
+func (s *MissionHandler) clearStatus() {
+	if s.drawn == 0 {
+		return
+	}
+	fmt.Fprintf(s.out, "\033[%dA\033[J", s.drawn)
+	s.drawn = 0
+}
+
+// drawStatus draws one status line per running task, in start order.
+func (s *MissionHandler) drawStatus() {
+	if !s.live || s.partialLine || s.statusPaused || len(s.running) == 0 {
+		return
+	}
+	width := 0
+	if w, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil {
+		width = w
+	}
+	now := time.Now()
+	for _, t := range s.running {
+		line := formatTaskStatus(t, now)
+		// A wrapped line would throw off the count clearStatus erases
+		if r := []rune(line); width > 1 && len(r) >= width {
+			line = string(r[:width-1])
+		}
+		fmt.Fprintf(s.out, "%s%s%s\n", ColorGray, line, ColorReset)
+		s.drawn++
+	}
+}
+
+// redrawStatus refreshes the status lines in place.
+func (s *MissionHandler) redrawStatus() {
+	if s.partialLine {
+		return
+	}
+	s.clearStatus()
+	s.drawStatus()
+}
+
+// tickStatus redraws the status lines every statusInterval until stop is
+// closed.
+func (s *MissionHandler) tickStatus(stop <-chan struct{}) {
+	ticker := time.NewTicker(statusInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.mu.Lock()
+			s.redrawStatus()
+			s.mu.Unlock()
+		case <-stop:
+			return
+		}
+	}
+}
+
+// startTask adds a status line for taskName, starting the ticker on the
+// first one. Callers hold s.mu.
+func (s *MissionHandler) startTask(taskName string, items int) {
+	if t := s.task(taskName); t != nil {
+		t.items = items
+		return
+	}
+	s.running = append(s.running, &taskStatus{name: taskName, started: time.Now(), items: items})
+	if s.live && s.stopTicker == nil {
+		s.stopTicker = make(chan struct{})
+		go s.tickStatus(s.stopTicker)
+	}
+}
+
+// finishTask removes taskName's status line. Callers hold s.mu.
+func (s *MissionHandler) finishTask(taskName string) {
+	for i, t := range s.running {
+		if t.name == taskName {
+			s.running = append(s.running[:i], s.running[i+1:]...)
+			return
+		}
+	}
+}
+
+// finishStatus erases the status lines and stops the ticker once the
+// mission ends. Callers hold s.mu.
+func (s *MissionHandler) finishStatus() {
+	s.running = nil
+	s.clearStatus()
+	if s.stopTicker != nil {
+		close(s.stopTicker)
+		s.stopTicker = nil
+	}
+}
+
+func (s *MissionHandler) task(taskName string) *taskStatus {
+	for _, t := range s.running {
+		if t.name == taskName {
+			return t
+		}
+	}
+	return nil
+}
+
+// recordTurn adds an LLM turn's usage to its task's totals.
+func (s *MissionHandler) recordTurn(data protocol.SessionTurnData) {
+	t := s.task(baseTaskName(data.TaskName))
+	if t == nil {
+		return
+	}
+	t.turns++
+	t.inputTokens += data.InputTokens + data.CacheReadTokens + data.CacheWriteTokens
+	t.outputTokens += data.OutputTokens
+	t.cost += data.Cost
+}
+
+// setProgress records a parallel iterated task's progress.
+func (s *MissionHandler) setProgress(data streamers.IterationProgressData) {
+	t := s.task(data.TaskName)
+	if t == nil {
+		return
+	}
+	t.progress = true
+	t.items = data.Total
+	t.itemsDone = data.Completed + data.Failed
+	t.failed = data.Failed
+	t.inFlight = data.InFlight
+	t.etaAt = time.Time{}
+	if data.EtaMs > 0 {
+		t.etaAt = time.Now().Add(time.Duration(data.EtaMs) * time.Millisecond)
+	}
+}
+
+// quiet reports whether output for taskName, a parallel iterated task or
+// one of its iterations ("score[3]"), is left to the progress bar. Only on
+// a terminal; failures and retries are printed regardless.
+func (s *MissionHandler) quiet(taskName string) bool {
+	if !s.live {
+		return false
+	}
+	t := s.task(baseTaskName(taskName))
+	return t != nil && t.progress
+}
+
+// baseTaskName strips the iteration index from a parallel iteration's task
+// name ("score[3]" -> "score").
+func baseTaskName(name string) string {
+	if i := strings.LastIndex(name, "["); i > 0 && strings.HasSuffix(name, "]") {
+		return name[:i]
+	}
+	return name
+}
+
+// formatTaskStatus renders a task's status line, e.g.
+// "  ▸ score  1m05s  3/10 items  12 turns  34.5k in / 2.1k out  $0.0412".
+// Parallel iterated tasks get a progress bar in place of the item count.
+func formatTaskStatus(t *taskStatus, now time.Time) string {
+	parts := []string{"  ▸ " + t.name, now.Sub(t.started).Truncate(time.Second).String()}
+	switch {
+	case t.progress:
+		parts = append(parts, formatProgress(t, now))
+	case t.items > 0:
+		parts = append(parts, fmt.Sprintf("%d/%d items", t.itemsDone, t.items))
+	}
+	turns := "turns"
+	if t.turns == 1 {
+		turns = "turn"
+	}
+	parts = append(parts,
+		fmt.Sprintf("%d %s", t.turns, turns),
+		fmt.Sprintf("%s in / %s out", formatTokenCount(t.inputTokens), formatTokenCount(t.outputTokens)),
+		fmt.Sprintf("$%.4f", t.cost),
+	)
+	return strings.Join(parts, "  ")
+}
+
+// progressBarWidth is the number of cells in a progress bar.
+const progressBarWidth = 20
+
+// formatProgress renders a parallel iterated task's progress, e.g.
+// "[██████░░░░░░░░░░░░░░] 6/20 items, 1 failed, 3 running, ETA 1m20s".
+func formatProgress(t *taskStatus, now time.Time) string {
+	filled := 0
+	if t.items > 0 {
+		filled = t.itemsDone * progressBarWidth / t.items
+	}
+	text := fmt.Sprintf("[%s%s] %d/%d items", strings.Repeat("█", filled), strings.Repeat("░", progressBarWidth-filled), t.itemsDone, t.items)
+	if t.failed > 0 {
+		text += fmt.Sprintf(", %d failed", t.failed)
+	}
+	if t.inFlight > 0 {
+		text += fmt.Sprintf(", %d running", t.inFlight)
+	}
+	if !t.etaAt.IsZero() {
+		eta := t.etaAt.Sub(now).Truncate(time.Second)
+		if eta < 0 {
+			eta = 0
+		}
+		text += fmt.Sprintf(", ETA %s", eta)
+	}
+	return text
+}
+
+// formatTokenCount abbreviates a token count: 950, 34.5k, 1.2M.
+func formatTokenCount(n int) string {
+	switch {
+	case n >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(n)/1_000_000)
+	case n >= 1_000:
+		return fmt.Sprintf("%.1fk", float64(n)/1_000)
+	default:
+		return fmt.Sprintf("%d", n)
+	}
+}
@@ -105,3 +105,53 @@ type IDRegistrar interface {
 	SetTaskID(taskName, taskID string)
 	SetSessionID(taskName, agentName, sessionID string)
 }
+
+// MissionFailureHandler is an optional interface that MissionHandler
+// implementations can implement to be told when a started mission fails.
+// MissionCompleted is only called on success, so without it a handler
+// cannot tell a failed run from one that is still going.
+type MissionFailureHandler interface {
+	MissionFailed(name string, err error)
+}
+
+// ToolProgressHandler is an optional interface that ChatHandler
+// implementations can implement to receive progress updates from tools that
+// stream them (see plugin.StreamingToolProvider). Updates arrive between
+// CallingTool and ToolComplete for the same toolCallId.
+type ToolProgressHandler interface {
+	ToolProgress(toolCallId string, toolName string, content string)
+}
+
+// ApprovalHandler is an optional interface that MissionHandler
+// implementations can implement to be told when a task with
+// require_approval is waiting on a human, and how the review ended.
+type ApprovalHandler interface {
+	TaskApprovalRequested(data TaskApprovalRequestedData)
+	TaskApprovalResolved(data TaskApprovalResolvedData)
+}
+
+// CancelHandler is an optional interface that MissionHandler
+// implementations can implement to be told when a mission is cancelled.
+// TaskCancelled fires for each task that was in flight, after its
+// commanders wrote their partial summaries, then MissionCancelled once.
+// Neither TaskFailed nor MissionFailed is called for a cancellation.
+type CancelHandler interface {
+	TaskCancelled(data TaskCancelledData)
+	MissionCancelled(data MissionCancelledData)
+}
+
+// TaskSkipHandler is an optional interface that MissionHandler
+// implementations can implement to be told when a task is skipped because
+// its `when` guard was false. Skipped tasks get neither TaskStarted nor
+// TaskCompleted.
+type TaskSkipHandler interface {
+	TaskSkipped(data TaskSkippedData)
+}
+
+// IterationProgressHandler is an optional interface that MissionHandler
+// implementations can implement to get the running tally of a parallel
+// iterated task each time one of its iterations starts or finishes, e.g. to
+// draw a progress bar. The per-iteration events are still sent.
+type IterationProgressHandler interface {
+	IterationProgress(data IterationProgressData)
+}
@@ -39,6 +39,15 @@ const (
 	IssueCategoryBudgetExceeded = "budget_exceeded"
 	IssueCategoryProviderError  = "provider_error"
 	IssueCategoryToolError      = "tool_error"
+	IssueCategoryTimeout        = "timeout"
+	IssueCategoryDatasetExport  = "dataset_export"
+	IssueCategoryOutputSink     = "output_sink"
+	// IssueCategoryFailedIterations reports iterations a continue_on_failure
+	// task completed without.
+	IssueCategoryFailedIterations = "failed_iterations"
+	// IssueCategoryParserRecovery reports a commander or agent re-prompted
+	// after a response with no tool call and no answer.
+	IssueCategoryParserRecovery = "parser_recovery"
 )
 
 // MissionIssueData is the payload for a mission_issue event. Category and
@@ -0,0 +1,25 @@
+package streamers
+
+import "github.com/mlund01/squadron-wire/protocol"
+
+// EventIterationProgress carries the running tally of a parallel iterated
+// task. Defined locally like EventTaskSkipped until the shape settles in
+// squadron-wire.
+const EventIterationProgress protocol.MissionEventType = "iteration_progress"
+
+// IterationProgressData counts the iterations of a parallel iterated task.
+// Completed includes iterations finished by an earlier run of a resumed
+// mission. A retried iteration stays in flight until its last attempt.
+type IterationProgressData struct {
+	TaskName  string `json:"taskName"`
+	Total     int    `json:"total"`
+	Completed int    `json:"completed"`
+	Failed    int    `json:"failed"`
+	InFlight  int    `json:"inFlight"`
+	// AvgDurationMs is the mean duration of the iterations finished so far
+	// in this run, retries included (0 until one finishes)
+	AvgDurationMs int64 `json:"avgDurationMs,omitempty"`
+	// EtaMs estimates the time left from AvgDurationMs and the task's
+	// concurrency_limit (0 until an iteration finishes)
+	EtaMs int64 `json:"etaMs,omitempty"`
+}
@@ -0,0 +1,358 @@
+package streamers
+
+import (
+	"log"
+	"runtime/debug"
+	"sync"
+
+	"github.com/mlund01/squadron-wire/protocol"
+)
+
+// MultiHandler is a MissionHandler that fans every event out to several
+// handlers, in order — e.g. the terminal, an NDJSON file and webhooks at once.
+// Optional interfaces (IDRegistrar, CancelHandler, ...) are forwarded to the
+// handlers that implement them.
+//
+// Each handler is isolated: a handler that panics is logged and dropped for
+// the rest of the run, and the other handlers and the mission carry on.
+type MultiHandler struct {
+	handlers []MissionHandler
+
+	mu     sync.Mutex
+	broken map[int]bool
+}
+
+// NewMultiHandler fans events out to handlers. Nil handlers are ignored.
+func NewMultiHandler(handlers ...MissionHandler) *MultiHandler {
+	m := &MultiHandler{broken: make(map[int]bool)}
+	for _, h := range handlers {
+		if h != nil {
+			m.handlers = append(m.handlers, h)
+		}
+	}
+	return m
+}
+
+// Handlers returns the handlers events are fanned out to.
+func (m *MultiHandler) Handlers() []MissionHandler {
+	return m.handlers
+}
+
+// each calls fn for every handler that hasn't panicked yet.
+func (m *MultiHandler) each(event string, fn func(MissionHandler)) {
+	for i, h := range m.handlers {
+		if m.isBroken(i) {
+			continue
+		}
+		m.call(i, h, event, fn)
+	}
+}
+
+func (m *MultiHandler) call(i int, h MissionHandler, event string, fn func(MissionHandler)) {
+	defer func() {
+		if r := recover(); r != nil {
+			m.mu.Lock()
+			m.broken[i] = true
+			m.mu.Unlock()
+			log.Printf("streamers: %T panicked on %s, dropping it: %v\n%s", h, event, r, debug.Stack())
+		}
+	}()
+	fn(h)
+}
+
+func (m *MultiHandler) isBroken(i int) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.broken[i]
+}
+
+// =============================================================================
+// MissionHandler implementation
+// =============================================================================
+
+func (m *MultiHandler) MissionStarted(name string, missionID string, taskCount int) {
+	m.each("MissionStarted", func(h MissionHandler) { h.MissionStarted(name, missionID, taskCount) })
+}
+
+func (m *MultiHandler) MissionCompleted(name string) {
+	m.each("MissionCompleted", func(h MissionHandler) { h.MissionCompleted(name) })
+}
+
+func (m *MultiHandler) TaskStarted(taskName string, objective string) {
+	m.each("TaskStarted", func(h MissionHandler) { h.TaskStarted(taskName, objective) })
+}
+
+func (m *MultiHandler) TaskCompleted(taskName string) {
+	m.each("TaskCompleted", func(h MissionHandler) { h.TaskCompleted(taskName) })
+}
+
+func (m *MultiHandler) TaskFailed(taskName string, err error) {
+	m.each("TaskFailed", func(h MissionHandler) { h.TaskFailed(taskName, err) })
+}
+
+func (m *MultiHandler) TaskIterationStarted(taskName string, totalItems int, parallel bool) {
+	m.each("TaskIterationStarted", func(h MissionHandler) { h.TaskIterationStarted(taskName, totalItems, parallel) })
+}
+
+func (m *MultiHandler) TaskIterationCompleted(taskName string, completedCount int) {
+	m.each("TaskIterationCompleted", func(h MissionHandler) { h.TaskIterationCompleted(taskName, completedCount) })
+}
+
+func (m *MultiHandler) IterationStarted(taskName string, index int, objective string) {
+	m.each("IterationStarted", func(h MissionHandler) { h.IterationStarted(taskName, index, objective) })
+}
+
+func (m *MultiHandler) IterationCompleted(taskName string, index int) {
+	m.each("IterationCompleted", func(h MissionHandler) { h.IterationCompleted(taskName, index) })
+}
+
+func (m *MultiHandler) IterationFailed(taskName string, index int, err error) {
+	m.each("IterationFailed", func(h MissionHandler) { h.IterationFailed(taskName, index, err) })
+}
+
+func (m *MultiHandler) IterationRetrying(taskName string, index int, attempt int, maxRetries int, err error) {
+	m.each("IterationRetrying", func(h MissionHandler) { h.IterationRetrying(taskName, index, attempt, maxRetries, err) })
+}
+
+func (m *MultiHandler) IterationReasoning(taskName string, index int, content string) {
+	m.each("IterationReasoning", func(h MissionHandler) { h.IterationReasoning(taskName, index, content) })
+}
+
+func (m *MultiHandler) IterationAnswer(taskName string, index int, content string) {
+	m.each("IterationAnswer", func(h MissionHandler) { h.IterationAnswer(taskName, index, content) })
+}
+
+func (m *MultiHandler) CommanderReasoningStarted(taskName string) {
+	m.each("CommanderReasoningStarted", func(h MissionHandler) { h.CommanderReasoningStarted(taskName) })
+}
+
+func (m *MultiHandler) CommanderReasoningCompleted(taskName string, content string) {
+	m.each("CommanderReasoningCompleted", func(h MissionHandler) { h.CommanderReasoningCompleted(taskName, content) })
+}
+
+func (m *MultiHandler) CommanderAnswer(taskName string, content string) {
+	m.each("CommanderAnswer", func(h MissionHandler) { h.CommanderAnswer(taskName, content) })
+}
+
+func (m *MultiHandler) CommanderCallingTool(taskName string, toolCallId string, toolName string, input string) {
+	m.each("CommanderCallingTool", func(h MissionHandler) { h.CommanderCallingTool(taskName, toolCallId, toolName, input) })
+}
+
+func (m *MultiHandler) CommanderToolComplete(taskName string, toolCallId string, toolName string, result string) {
+	m.each("CommanderToolComplete", func(h MissionHandler) { h.CommanderToolComplete(taskName, toolCallId, toolName, result) })
+}
+
+func (m *MultiHandler) Compaction(taskName string, entity string, inputTokens int, tokenLimit int, messagesCompacted int, turnRetention int) {
+	m.each("Compaction", func(h MissionHandler) {
+		h.Compaction(taskName, entity, inputTokens, tokenLimit, messagesCompacted, turnRetention)
+	})
+}
+
+func (m *MultiHandler) SessionTurn(data protocol.SessionTurnData) {
+	m.each("SessionTurn", func(h MissionHandler) { h.SessionTurn(data) })
+}
+
+func (m *MultiHandler) AgentStarted(taskName string, agentName string, instruction string) {
+	m.each("AgentStarted", func(h MissionHandler) { h.AgentStarted(taskName, agentName, instruction) })
+}
+
+// AgentHandler fans the agent's chat events out to the chat handler of
+// every mission handler.
+func (m *MultiHandler) AgentHandler(taskName string, agentName string) ChatHandler {
+	c := &multiChatHandler{}
+	m.each("AgentHandler", func(h MissionHandler) {
+		if ch := h.AgentHandler(taskName, agentName); ch != nil {
+			c.handlers = append(c.handlers, ch)
+		}
+	})
+	return c
+}
+
+func (m *MultiHandler) AgentCompleted(taskName string, agentName string) {
+	m.each("AgentCompleted", func(h MissionHandler) { h.AgentCompleted(taskName, agentName) })
+}
+
+func (m *MultiHandler) RouteChosen(routerTask string, targetTask string, condition string, isMission bool) {
+	m.each("RouteChosen", func(h MissionHandler) { h.RouteChosen(routerTask, targetTask, condition, isMission) })
+}
+
+func (m *MultiHandler) MissionIssue(data MissionIssueData) {
+	m.each("MissionIssue", func(h MissionHandler) { h.MissionIssue(data) })
+}
+
+// =============================================================================
+// Optional interfaces
+// =============================================================================
+
+// SetTaskID implements IDRegistrar.
+func (m *MultiHandler) SetTaskID(taskName, taskID string) {
+	m.each("SetTaskID", func(h MissionHandler) {
+		if reg, ok := h.(IDRegistrar); ok {
+			reg.SetTaskID(taskName, taskID)
+		}
+	})
+}
+
+// SetSessionID implements IDRegistrar.
+func (m *MultiHandler) SetSessionID(taskName, agentName, sessionID string) {
+	m.each("SetSessionID", func(h MissionHandler) {
+		if reg, ok := h.(IDRegistrar); ok {
+			reg.SetSessionID(taskName, agentName, sessionID)
+		}
+	})
+}
+
+// MissionFailed implements MissionFailureHandler.
+func (m *MultiHandler) MissionFailed(name string, err error) {
+	m.each("MissionFailed", func(h MissionHandler) {
+		if fh, ok := h.(MissionFailureHandler); ok {
+			fh.MissionFailed(name, err)
+		}
+	})
+}
+
+// TaskApprovalRequested implements ApprovalHandler.
+func (m *MultiHandler) TaskApprovalRequested(data TaskApprovalRequestedData) {
+	m.each("TaskApprovalRequested", func(h MissionHandler) {
+		if ah, ok := h.(ApprovalHandler); ok {
+			ah.TaskApprovalRequested(data)
+		}
+	})
+}
+
+// TaskApprovalResolved implements ApprovalHandler.
+func (m *MultiHandler) TaskApprovalResolved(data TaskApprovalResolvedData) {
+	m.each("TaskApprovalResolved", func(h MissionHandler) {
+		if ah, ok := h.(ApprovalHandler); ok {
+			ah.TaskApprovalResolved(data)
+		}
+	})
+}
+
+// TaskCancelled implements CancelHandler.
+func (m *MultiHandler) TaskCancelled(data TaskCancelledData) {
+	m.each("TaskCancelled", func(h MissionHandler) {
+		if ch, ok := h.(CancelHandler); ok {
+			ch.TaskCancelled(data)
+		}
+	})
+}
+
+// MissionCancelled implements CancelHandler.
+func (m *MultiHandler) MissionCancelled(data MissionCancelledData) {
+	m.each("MissionCancelled", func(h MissionHandler) {
+		if ch, ok := h.(CancelHandler); ok {
+			ch.MissionCancelled(data)
+		}
+	})
+}
+
+// TaskSkipped implements TaskSkipHandler.
+func (m *MultiHandler) TaskSkipped(data TaskSkippedData) {
+	m.each("TaskSkipped", func(h MissionHandler) {
+		if sh, ok := h.(TaskSkipHandler); ok {
+			sh.TaskSkipped(data)
+		}
+	})
+}
+
+// IterationProgress implements IterationProgressHandler.
+func (m *MultiHandler) IterationProgress(data IterationProgressData) {
+	m.each("IterationProgress", func(h MissionHandler) {
+		if ph, ok := h.(IterationProgressHandler); ok {
+			ph.IterationProgress(data)
+		}
+	})
+}
+
+// =============================================================================
+// ChatHandler fan-out
+// =============================================================================
+
+// multiChatHandler fans an agent's chat events out to several ChatHandlers.
+// Agents in a mission never read client input, so AwaitClientAnswer is
+// answered by the first handler alone.
+type multiChatHandler struct {
+	handlers []ChatHandler
+}
+
+func (c *multiChatHandler) each(fn func(ChatHandler)) {
+	for _, h := range c.handlers {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					log.Printf("streamers: %T panicked: %v", h, r)
+				}
+			}()
+			fn(h)
+		}()
+	}
+}
+
+func (c *multiChatHandler) Welcome(agentName string, modelName string) {
+	c.each(func(h ChatHandler) { h.Welcome(agentName, modelName) })
+}
+
+func (c *multiChatHandler) AwaitClientAnswer() (string, error) {
+	if len(c.handlers) == 0 {
+		return "", nil
+	}
+	return c.handlers[0].AwaitClientAnswer()
+}
+
+func (c *multiChatHandler) Goodbye() {
+	c.each(func(h ChatHandler) { h.Goodbye() })
+}
+
+func (c *multiChatHandler) Error(err error) {
+	c.each(func(h ChatHandler) { h.Error(err) })
+}
+
+func (c *multiChatHandler) Thinking() {
+	c.each(func(h ChatHandler) { h.Thinking() })
+}
+
+func (c *multiChatHandler) CallingTool(toolCallId string, toolName string, payload string) {
+	c.each(func(h ChatHandler) { h.CallingTool(toolCallId, toolName, payload) })
+}
+
+func (c *multiChatHandler) ToolComplete(toolCallId string, toolName string, result string) {
+	c.each(func(h ChatHandler) { h.ToolComplete(toolCallId, toolName, result) })
+}
+
+// ToolProgress implements ToolProgressHandler.
+func (c *multiChatHandler) ToolProgress(toolCallId string, toolName string, content string) {
+	c.each(func(h ChatHandler) {
+		if ph, ok := h.(ToolProgressHandler); ok {
+			ph.ToolProgress(toolCallId, toolName, content)
+		}
+	})
+}
+
+func (c *multiChatHandler) ReasoningStarted() {
+	c.each(func(h ChatHandler) { h.ReasoningStarted() })
+}
+
+func (c *multiChatHandler) PublishReasoningChunk(chunk string) {
+	c.each(func(h ChatHandler) { h.PublishReasoningChunk(chunk) })
+}
+
+func (c *multiChatHandler) ReasoningCompleted() {
+	c.each(func(h ChatHandler) { h.ReasoningCompleted() })
+}
+
+func (c *multiChatHandler) PublishAnswerChunk(chunk string) {
+	c.each(func(h ChatHandler) { h.PublishAnswerChunk(chunk) })
+}
+
+func (c *multiChatHandler) FinishAnswer() {
+	c.each(func(h ChatHandler) { h.FinishAnswer() })
+}
+
+func (c *multiChatHandler) AskCommander(content string) {
+	c.each(func(h ChatHandler) { h.AskCommander(content) })
+}
+
+func (c *multiChatHandler) CommanderResponse(content string) {
+	c.each(func(h ChatHandler) { h.CommanderResponse(content) })
+}
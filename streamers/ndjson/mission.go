@@ -0,0 +1,379 @@
+// Package ndjson writes mission events as newline-delimited JSON, one event
+// per line, for machine consumers that tail a file or pipe.
+package ndjson
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mlund01/squadron-wire/protocol"
+
+	"squadron/streamers"
+)
+
+// Line is the JSON object written for every event. Data carries the same
+// payload types the event store and command center use for the event.
+type Line struct {
+	Type      protocol.MissionEventType `json:"type"`
+	MissionID string                    `json:"missionId,omitempty"`
+	Timestamp time.Time                 `json:"timestamp"`
+	Data      any                       `json:"data"`
+}
+
+// MissionHandler implements streamers.MissionHandler by writing each event as
+// a single JSON line. Writes are serialized, so lines from parallel tasks
+// never interleave.
+type MissionHandler struct {
+	mu        sync.Mutex
+	send      func(Line)
+	missionID string
+}
+
+// NewMissionHandler creates a handler that writes events to w.
+func NewMissionHandler(w io.Writer) *MissionHandler {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return &MissionHandler{send: func(line Line) {
+		if err := enc.Encode(line); err != nil {
+			log.Printf("ndjson: write %s event: %v", line.Type, err)
+		}
+	}}
+}
+
+// NewMissionHandlerFunc creates a handler that passes each event to fn
+// instead of writing it. Calls to fn are serialized like writes are.
+func NewMissionHandlerFunc(fn func(Line)) *MissionHandler {
+	return &MissionHandler{send: fn}
+}
+
+func (h *MissionHandler) emit(eventType protocol.MissionEventType, data any) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	line := Line{
+		Type:      eventType,
+		MissionID: h.missionID,
+		Timestamp: time.Now().UTC(),
+		Data:      data,
+	}
+	h.send(line)
+}
+
+// =============================================================================
+// MissionHandler implementation
+// =============================================================================
+
+func (h *MissionHandler) MissionStarted(name string, missionID string, taskCount int) {
+	h.mu.Lock()
+	h.missionID = missionID
+	h.mu.Unlock()
+	h.emit(protocol.EventMissionStarted, protocol.MissionStartedData{
+		MissionName: name,
+		MissionID:   missionID,
+		TaskCount:   taskCount,
+	})
+}
+
+func (h *MissionHandler) MissionCompleted(name string) {
+	h.emit(protocol.EventMissionCompleted, protocol.MissionCompletedData{MissionName: name})
+}
+
+// MissionFailed implements streamers.MissionFailureHandler.
+func (h *MissionHandler) MissionFailed(name string, err error) {
+	h.emit(protocol.EventMissionFailed, protocol.MissionFailedData{
+		MissionName: name,
+		Error:       err.Error(),
+	})
+}
+
+// TaskApprovalRequested implements streamers.ApprovalHandler.
+func (h *MissionHandler) TaskApprovalRequested(data streamers.TaskApprovalRequestedData) {
+	h.emit(streamers.EventTaskApprovalRequested, data)
+}
+
+// TaskApprovalResolved implements streamers.ApprovalHandler.
+func (h *MissionHandler) TaskApprovalResolved(data streamers.TaskApprovalResolvedData) {
+	h.emit(streamers.EventTaskApprovalResolved, data)
+}
+
+// TaskCancelled implements streamers.CancelHandler.
+func (h *MissionHandler) TaskCancelled(data streamers.TaskCancelledData) {
+	h.emit(streamers.EventTaskCancelled, data)
+}
+
+// MissionCancelled implements streamers.CancelHandler.
+func (h *MissionHandler) MissionCancelled(data streamers.MissionCancelledData) {
+	h.emit(streamers.EventMissionCancelled, data)
+}
+
+// TaskSkipped implements streamers.TaskSkipHandler.
+func (h *MissionHandler) TaskSkipped(data streamers.TaskSkippedData) {
+	h.emit(streamers.EventTaskSkipped, data)
+}
+
+// IterationProgress implements streamers.IterationProgressHandler.
+func (h *MissionHandler) IterationProgress(data streamers.IterationProgressData) {
+	h.emit(streamers.EventIterationProgress, data)
+}
+
+func (h *MissionHandler) TaskStarted(taskName string, objective string) {
+	h.emit(protocol.EventTaskStarted, protocol.TaskStartedData{TaskName: taskName, Objective: objective})
+}
+
+func (h *MissionHandler) TaskCompleted(taskName string) {
+	h.emit(protocol.EventTaskCompleted, protocol.TaskCompletedData{TaskName: taskName})
+}
+
+func (h *MissionHandler) TaskFailed(taskName string, err error) {
+	h.emit(protocol.EventTaskFailed, protocol.TaskFailedData{TaskName: taskName, Error: err.Error()})
+}
+
+func (h *MissionHandler) TaskIterationStarted(taskName string, totalItems int, parallel bool) {
+	h.emit(protocol.EventTaskIterationStarted, protocol.TaskIterationStartedData{
+		TaskName:   taskName,
+		TotalItems: totalItems,
+		Parallel:   parallel,
+	})
+}
+
+func (h *MissionHandler) TaskIterationCompleted(taskName string, completedCount int) {
+	h.emit(protocol.EventTaskIterationCompleted, protocol.TaskIterationCompletedData{
+		TaskName:       taskName,
+		CompletedCount: completedCount,
+	})
+}
+
+func (h *MissionHandler) IterationStarted(taskName string, index int, objective string) {
+	h.emit(protocol.EventIterationStarted, protocol.IterationStartedData{
+		TaskName:  taskName,
+		Index:     index,
+		Objective: objective,
+	})
+}
+
+func (h *MissionHandler) IterationCompleted(taskName string, index int) {
+	h.emit(protocol.EventIterationCompleted, protocol.IterationCompletedData{TaskName: taskName, Index: index})
+}
+
+func (h *MissionHandler) IterationFailed(taskName string, index int, err error) {
+	h.emit(protocol.EventIterationFailed, protocol.IterationFailedData{
+		TaskName: taskName,
+		Index:    index,
+		Error:    err.Error(),
+	})
+}
+
+func (h *MissionHandler) IterationRetrying(taskName string, index int, attempt int, maxRetries int, err error) {
+	h.emit(protocol.EventIterationRetrying, protocol.IterationRetryingData{
+		TaskName:   taskName,
+		Index:      index,
+		Attempt:    attempt,
+		MaxRetries: maxRetries,
+		Error:      err.Error(),
+	})
+}
+
+func (h *MissionHandler) IterationReasoning(taskName string, index int, content string) {
+	h.emit(protocol.EventIterationReasoning, protocol.IterationReasoningData{
+		TaskName: taskName,
+		Index:    index,
+		Content:  content,
+	})
+}
+
+func (h *MissionHandler) IterationAnswer(taskName string, index int, content string) {
+	h.emit(protocol.EventIterationAnswer, protocol.IterationAnswerData{
+		TaskName: taskName,
+		Index:    index,
+		Content:  content,
+	})
+}
+
+func (h *MissionHandler) CommanderReasoningStarted(taskName string) {
+	h.emit(protocol.EventCommanderReasoningStarted, protocol.CommanderReasoningStartedData{TaskName: taskName})
+}
+
+func (h *MissionHandler) CommanderReasoningCompleted(taskName string, content string) {
+	h.emit(protocol.EventCommanderReasoningCompleted, protocol.CommanderReasoningCompletedData{
+		TaskName: taskName,
+		Content:  content,
+	})
+}
+
+func (h *MissionHandler) CommanderAnswer(taskName string, content string) {
+	h.emit(protocol.EventCommanderAnswer, protocol.CommanderAnswerData{TaskName: taskName, Content: content})
+}
+
+func (h *MissionHandler) CommanderCallingTool(taskName string, toolCallId string, toolName string, input string) {
+	h.emit(protocol.EventCommanderCallingTool, protocol.CommanderCallingToolData{
+		TaskName:   taskName,
+		ToolCallId: toolCallId,
+		ToolName:   toolName,
+		Input:      input,
+	})
+}
+
+func (h *MissionHandler) CommanderToolComplete(taskName string, toolCallId string, toolName string, result string) {
+	h.emit(protocol.EventCommanderToolComplete, protocol.CommanderToolCompleteData{
+		TaskName:   taskName,
+		ToolCallId: toolCallId,
+		ToolName:   toolName,
+		Result:     result,
+	})
+}
+
+func (h *MissionHandler) Compaction(taskName string, entity string, inputTokens int, tokenLimit int, messagesCompacted int, turnRetention int) {
+	h.emit(protocol.EventCompaction, protocol.CompactionData{
+		TaskName:          taskName,
+		Entity:            entity,
+		InputTokens:       inputTokens,
+		TokenLimit:        tokenLimit,
+		MessagesCompacted: messagesCompacted,
+		TurnRetention:     turnRetention,
+	})
+}
+
+func (h *MissionHandler) SessionTurn(data protocol.SessionTurnData) {
+	h.emit(protocol.EventSessionTurn, data)
+}
+
+func (h *MissionHandler) AgentStarted(taskName string, agentName string, instruction string) {
+	h.emit(protocol.EventAgentStarted, protocol.AgentStartedData{
+		TaskName:    taskName,
+		AgentName:   agentName,
+		Instruction: instruction,
+	})
+}
+
+func (h *MissionHandler) AgentHandler(taskName string, agentName string) streamers.ChatHandler {
+	return &chatHandler{parent: h, taskName: taskName, agentName: agentName}
+}
+
+func (h *MissionHandler) AgentCompleted(taskName string, agentName string) {
+	h.emit(protocol.EventAgentCompleted, protocol.AgentCompletedData{TaskName: taskName, AgentName: agentName})
+}
+
+func (h *MissionHandler) RouteChosen(routerTask string, targetTask string, condition string, isMission bool) {
+	h.emit(protocol.EventRouteChosen, protocol.RouteChosenData{
+		RouterTask: routerTask,
+		TargetTask: targetTask,
+		Condition:  condition,
+		IsMission:  isMission,
+	})
+}
+
+func (h *MissionHandler) MissionIssue(data streamers.MissionIssueData) {
+	h.emit(streamers.EventMissionIssue, data)
+}
+
+// =============================================================================
+// chatHandler — agent-level events
+// =============================================================================
+
+// chatHandler buffers streamed reasoning and answer chunks and writes each as
+// one line when it completes, rather than one line per chunk.
+type chatHandler struct {
+	parent       *MissionHandler
+	taskName     string
+	agentName    string
+	reasoningBuf strings.Builder
+	answerBuf    strings.Builder
+}
+
+func (c *chatHandler) Welcome(agentName string, modelName string) {}
+
+func (c *chatHandler) AwaitClientAnswer() (string, error) { return "", nil }
+
+func (c *chatHandler) Goodbye() {}
+
+func (c *chatHandler) Error(err error) {}
+
+func (c *chatHandler) Thinking() {
+	c.reasoningBuf.Reset()
+}
+
+func (c *chatHandler) CallingTool(toolCallId string, toolName string, payload string) {
+	c.parent.emit(protocol.EventAgentCallingTool, protocol.AgentCallingToolData{
+		TaskName:   c.taskName,
+		AgentName:  c.agentName,
+		ToolCallId: toolCallId,
+		ToolName:   toolName,
+		Payload:    payload,
+	})
+}
+
+func (c *chatHandler) ToolComplete(toolCallId string, toolName string, result string) {
+	c.parent.emit(protocol.EventAgentToolComplete, protocol.AgentToolCompleteData{
+		TaskName:   c.taskName,
+		AgentName:  c.agentName,
+		ToolCallId: toolCallId,
+		ToolName:   toolName,
+		Result:     result,
+	})
+}
+
+// ToolProgress implements streamers.ToolProgressHandler.
+func (c *chatHandler) ToolProgress(toolCallId string, toolName string, content string) {
+	c.parent.emit(streamers.EventAgentToolProgress, streamers.AgentToolProgressData{
+		TaskName:   c.taskName,
+		AgentName:  c.agentName,
+		ToolCallId: toolCallId,
+		ToolName:   toolName,
+		Content:    content,
+	})
+}
+
+func (c *chatHandler) ReasoningStarted() {
+	c.parent.emit(protocol.EventAgentReasoningStarted, protocol.AgentReasoningStartedData{
+		TaskName:  c.taskName,
+		AgentName: c.agentName,
+	})
+}
+
+func (c *chatHandler) PublishReasoningChunk(chunk string) {
+	c.reasoningBuf.WriteString(chunk)
+}
+
+func (c *chatHandler) ReasoningCompleted() {
+	c.parent.emit(protocol.EventAgentReasoningCompleted, protocol.AgentReasoningCompletedData{
+		TaskName:  c.taskName,
+		AgentName: c.agentName,
+		Content:   c.reasoningBuf.String(),
+	})
+	c.reasoningBuf.Reset()
+}
+
+func (c *chatHandler) PublishAnswerChunk(chunk string) {
+	c.answerBuf.WriteString(chunk)
+}
+
+func (c *chatHandler) FinishAnswer() {
+	if c.answerBuf.Len() == 0 {
+		return
+	}
+	c.parent.emit(protocol.EventAgentAnswer, protocol.AgentAnswerData{
+		TaskName:  c.taskName,
+		AgentName: c.agentName,
+		Content:   c.answerBuf.String(),
+	})
+	c.answerBuf.Reset()
+}
+
+func (c *chatHandler) AskCommander(content string) {
+	c.parent.emit(protocol.EventAgentAskCommander, protocol.AgentAskCommanderData{
+		TaskName:  c.taskName,
+		AgentName: c.agentName,
+		Content:   content,
+	})
+}
+
+func (c *chatHandler) CommanderResponse(content string) {
+	c.parent.emit(protocol.EventAgentCommanderResponse, protocol.AgentCommanderResponseData{
+		TaskName:  c.taskName,
+		AgentName: c.agentName,
+		Content:   content,
+	})
+}
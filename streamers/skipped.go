@@ -0,0 +1,14 @@
+package streamers
+
+import "github.com/mlund01/squadron-wire/protocol"
+
+// EventTaskSkipped is emitted when a task's `when` guard evaluates to false.
+// Defined locally like EventMissionIssue until the shape settles in
+// squadron-wire.
+const EventTaskSkipped protocol.MissionEventType = "task_skipped"
+
+// TaskSkippedData names the skipped task and the guard that skipped it.
+type TaskSkippedData struct {
+	TaskName string `json:"taskName"`
+	When     string `json:"when,omitempty"` // raw guard source
+}
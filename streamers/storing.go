@@ -141,6 +141,66 @@ func (h *StoringMissionHandler) MissionCompleted(name string) {
 	h.inner.MissionCompleted(name)
 }
 
+// MissionFailed implements MissionFailureHandler, forwarding to the inner
+// handler when it implements it too.
+func (h *StoringMissionHandler) MissionFailed(name string, err error) {
+	h.storeEvent(protocol.EventMissionFailed, nil, nil, nil, protocol.MissionFailedData{
+		MissionName: name,
+		Error:       err.Error(),
+	})
+	if fh, ok := h.inner.(MissionFailureHandler); ok {
+		fh.MissionFailed(name, err)
+	}
+}
+
+// TaskApprovalRequested implements ApprovalHandler.
+func (h *StoringMissionHandler) TaskApprovalRequested(data TaskApprovalRequestedData) {
+	h.storeEvent(EventTaskApprovalRequested, &data.TaskName, nil, nil, data)
+	if ah, ok := h.inner.(ApprovalHandler); ok {
+		ah.TaskApprovalRequested(data)
+	}
+}
+
+// TaskApprovalResolved implements ApprovalHandler.
+func (h *StoringMissionHandler) TaskApprovalResolved(data TaskApprovalResolvedData) {
+	h.storeEvent(EventTaskApprovalResolved, &data.TaskName, nil, nil, data)
+	if ah, ok := h.inner.(ApprovalHandler); ok {
+		ah.TaskApprovalResolved(data)
+	}
+}
+
+// TaskCancelled implements CancelHandler.
+func (h *StoringMissionHandler) TaskCancelled(data TaskCancelledData) {
+	h.storeEvent(EventTaskCancelled, &data.TaskName, nil, nil, data)
+	if ch, ok := h.inner.(CancelHandler); ok {
+		ch.TaskCancelled(data)
+	}
+}
+
+// MissionCancelled implements CancelHandler.
+func (h *StoringMissionHandler) MissionCancelled(data MissionCancelledData) {
+	h.storeEvent(EventMissionCancelled, nil, nil, nil, data)
+	if ch, ok := h.inner.(CancelHandler); ok {
+		ch.MissionCancelled(data)
+	}
+}
+
+// TaskSkipped implements TaskSkipHandler.
+func (h *StoringMissionHandler) TaskSkipped(data TaskSkippedData) {
+	h.storeEvent(EventTaskSkipped, &data.TaskName, nil, nil, data)
+	if sh, ok := h.inner.(TaskSkipHandler); ok {
+		sh.TaskSkipped(data)
+	}
+}
+
+// IterationProgress implements IterationProgressHandler. Progress is not
+// stored: the iteration events it tallies already are.
+func (h *StoringMissionHandler) IterationProgress(data IterationProgressData) {
+	if ph, ok := h.inner.(IterationProgressHandler); ok {
+		ph.IterationProgress(data)
+	}
+}
+
 func (h *StoringMissionHandler) TaskStarted(taskName string, objective string) {
 	h.storeEvent(protocol.EventTaskStarted, &taskName, nil, nil, protocol.TaskStartedData{
 		TaskName:  taskName,
@@ -327,6 +387,7 @@ func (h *StoringMissionHandler) SessionTurn(data protocol.SessionTurnData) {
 			SessionID:        sessionID,
 			MissionName:      missionName,
 			TaskName:         data.TaskName,
+			IterationIndex:   extractIterationIndex(data.TaskName),
 			Entity:           data.Entity,
 			Model:            data.Model,
 			InputTokens:      data.InputTokens,
@@ -460,6 +521,20 @@ func (c *storingChatHandler) ToolComplete(toolCallId string, toolName string, re
 	c.inner.ToolComplete(toolCallId, toolName, result)
 }
 
+// ToolProgress implements ToolProgressHandler.
+func (c *storingChatHandler) ToolProgress(toolCallId string, toolName string, content string) {
+	c.parent.storeEvent(EventAgentToolProgress, &c.taskName, &c.sessionKey, extractIterationIndex(c.taskName), AgentToolProgressData{
+		TaskName:   c.taskName,
+		AgentName:  c.agentName,
+		ToolCallId: toolCallId,
+		ToolName:   toolName,
+		Content:    content,
+	})
+	if ph, ok := c.inner.(ToolProgressHandler); ok {
+		ph.ToolProgress(toolCallId, toolName, content)
+	}
+}
+
 func (c *storingChatHandler) ReasoningStarted() {
 	c.parent.storeEvent(protocol.EventAgentReasoningStarted, &c.taskName, &c.sessionKey, extractIterationIndex(c.taskName), protocol.AgentReasoningStartedData{
 		TaskName:  c.taskName,
@@ -0,0 +1,17 @@
+package streamers
+
+import "github.com/mlund01/squadron-wire/protocol"
+
+// EventAgentToolProgress is the event type for a progress update from a
+// running agent tool. Like EventMissionIssue it is defined locally until the
+// shape settles; the command center forwards unknown event types as-is.
+const EventAgentToolProgress protocol.MissionEventType = "agent_tool_progress"
+
+// AgentToolProgressData is the payload for an agent_tool_progress event.
+type AgentToolProgressData struct {
+	TaskName   string `json:"taskName"`
+	AgentName  string `json:"agentName"`
+	ToolCallId string `json:"toolCallId"`
+	ToolName   string `json:"toolName"`
+	Content    string `json:"content"`
+}
@@ -0,0 +1,308 @@
+// Package webhook delivers mission lifecycle events to HTTP endpoints
+// configured in a mission's notifications block.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"squadron/config"
+	"squadron/streamers"
+)
+
+// Request headers sent with every delivery.
+const (
+	HeaderEvent     = "X-Squadron-Event"
+	HeaderDelivery  = "X-Squadron-Delivery"
+	HeaderSignature = "X-Squadron-Signature"
+)
+
+// requestTimeout bounds a single delivery attempt.
+const requestTimeout = 10 * time.Second
+
+// queueSize is how many events can wait for delivery before new ones are
+// dropped.
+const queueSize = 64
+
+// retryBaseDelay is the wait before the first redelivery; it doubles on
+// every further attempt.
+var retryBaseDelay = 500 * time.Millisecond
+
+// Payload is the JSON body posted for each lifecycle event.
+type Payload struct {
+	Event       string    `json:"event"`
+	MissionID   string    `json:"missionId"`
+	MissionName string    `json:"missionName"`
+	TaskName    string    `json:"taskName,omitempty"`
+	Index       *int      `json:"index,omitempty"`      // iteration_retrying only
+	Attempt     int       `json:"attempt,omitempty"`    // iteration_retrying only
+	MaxRetries  int       `json:"maxRetries,omitempty"` // iteration_retrying only
+	Error       string    `json:"error,omitempty"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+// MissionHandler is a streamers.MissionHandler decorator that posts lifecycle
+// events to webhooks, then delegates every event to the inner handler.
+// Deliveries run in order on a background worker so a slow endpoint never
+// stalls the mission: when queueSize events are already pending, further
+// events are dropped and logged. Call Close to flush pending deliveries.
+type MissionHandler struct {
+	streamers.MissionHandler
+
+	hooks  []config.Webhook
+	client *http.Client
+	queue  chan Payload
+	done   chan struct{}
+
+	mu          sync.Mutex
+	missionID   string
+	missionName string
+	closed      bool
+	deliveries  uint64
+}
+
+// NewMissionHandler wraps inner with webhook delivery for the given hooks.
+func NewMissionHandler(inner streamers.MissionHandler, hooks []config.Webhook) *MissionHandler {
+	h := &MissionHandler{
+		MissionHandler: inner,
+		hooks:          hooks,
+		client:         &http.Client{Timeout: requestTimeout},
+		queue:          make(chan Payload, queueSize),
+		done:           make(chan struct{}),
+	}
+	go h.run()
+	return h
+}
+
+// Close stops accepting events and waits for queued deliveries (including
+// their retries) to finish.
+func (h *MissionHandler) Close() {
+	h.mu.Lock()
+	if h.closed {
+		h.mu.Unlock()
+		return
+	}
+	h.closed = true
+	close(h.queue)
+	h.mu.Unlock()
+	<-h.done
+}
+
+// SetTaskID forwards to the inner handler when it tracks IDs.
+func (h *MissionHandler) SetTaskID(taskName, taskID string) {
+	if reg, ok := h.MissionHandler.(streamers.IDRegistrar); ok {
+		reg.SetTaskID(taskName, taskID)
+	}
+}
+
+// SetSessionID forwards to the inner handler when it tracks IDs.
+func (h *MissionHandler) SetSessionID(taskName, agentName, sessionID string) {
+	if reg, ok := h.MissionHandler.(streamers.IDRegistrar); ok {
+		reg.SetSessionID(taskName, agentName, sessionID)
+	}
+}
+
+// =============================================================================
+// Lifecycle events
+// =============================================================================
+
+func (h *MissionHandler) MissionStarted(name string, missionID string, taskCount int) {
+	h.mu.Lock()
+	h.missionID = missionID
+	h.missionName = name
+	h.mu.Unlock()
+
+	h.enqueue(Payload{Event: config.NotifyMissionStarted})
+	h.MissionHandler.MissionStarted(name, missionID, taskCount)
+}
+
+func (h *MissionHandler) MissionCompleted(name string) {
+	h.enqueue(Payload{Event: config.NotifyMissionCompleted})
+	h.MissionHandler.MissionCompleted(name)
+}
+
+// MissionFailed implements streamers.MissionFailureHandler.
+func (h *MissionHandler) MissionFailed(name string, err error) {
+	h.enqueue(Payload{Event: config.NotifyMissionFailed, Error: err.Error()})
+	if fh, ok := h.MissionHandler.(streamers.MissionFailureHandler); ok {
+		fh.MissionFailed(name, err)
+	}
+}
+
+func (h *MissionHandler) TaskCompleted(taskName string) {
+	h.enqueue(Payload{Event: config.NotifyTaskCompleted, TaskName: taskName})
+	h.MissionHandler.TaskCompleted(taskName)
+}
+
+func (h *MissionHandler) TaskFailed(taskName string, err error) {
+	h.enqueue(Payload{Event: config.NotifyTaskFailed, TaskName: taskName, Error: err.Error()})
+	h.MissionHandler.TaskFailed(taskName, err)
+}
+
+func (h *MissionHandler) IterationRetrying(taskName string, index int, attempt int, maxRetries int, err error) {
+	h.enqueue(Payload{
+		Event:      config.NotifyIterationRetrying,
+		TaskName:   taskName,
+		Index:      &index,
+		Attempt:    attempt,
+		MaxRetries: maxRetries,
+		Error:      err.Error(),
+	})
+	h.MissionHandler.IterationRetrying(taskName, index, attempt, maxRetries, err)
+}
+
+// TaskApprovalRequested implements streamers.ApprovalHandler.
+func (h *MissionHandler) TaskApprovalRequested(data streamers.TaskApprovalRequestedData) {
+	h.enqueue(Payload{Event: config.NotifyApprovalRequested, TaskName: data.TaskName})
+	if ah, ok := h.MissionHandler.(streamers.ApprovalHandler); ok {
+		ah.TaskApprovalRequested(data)
+	}
+}
+
+// TaskApprovalResolved implements streamers.ApprovalHandler.
+func (h *MissionHandler) TaskApprovalResolved(data streamers.TaskApprovalResolvedData) {
+	if ah, ok := h.MissionHandler.(streamers.ApprovalHandler); ok {
+		ah.TaskApprovalResolved(data)
+	}
+}
+
+// TaskCancelled implements streamers.CancelHandler.
+func (h *MissionHandler) TaskCancelled(data streamers.TaskCancelledData) {
+	if ch, ok := h.MissionHandler.(streamers.CancelHandler); ok {
+		ch.TaskCancelled(data)
+	}
+}
+
+// MissionCancelled implements streamers.CancelHandler.
+func (h *MissionHandler) MissionCancelled(data streamers.MissionCancelledData) {
+	h.enqueue(Payload{Event: config.NotifyMissionCancelled, Error: data.Reason})
+	if ch, ok := h.MissionHandler.(streamers.CancelHandler); ok {
+		ch.MissionCancelled(data)
+	}
+}
+
+// TaskSkipped implements streamers.TaskSkipHandler.
+func (h *MissionHandler) TaskSkipped(data streamers.TaskSkippedData) {
+	if sh, ok := h.MissionHandler.(streamers.TaskSkipHandler); ok {
+		sh.TaskSkipped(data)
+	}
+}
+
+// IterationProgress implements streamers.IterationProgressHandler.
+func (h *MissionHandler) IterationProgress(data streamers.IterationProgressData) {
+	if ph, ok := h.MissionHandler.(streamers.IterationProgressHandler); ok {
+		ph.IterationProgress(data)
+	}
+}
+
+// =============================================================================
+// Delivery
+// =============================================================================
+
+func (h *MissionHandler) enqueue(p Payload) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return
+	}
+	p.MissionID = h.missionID
+	p.MissionName = h.missionName
+	p.Timestamp = time.Now().UTC()
+	// Never block under the lock: a full queue means the endpoints are
+	// behind, and waiting would stall the runner with them.
+	select {
+	case h.queue <- p:
+	default:
+		log.Printf("webhook: delivery queue full, dropping %s event", p.Event)
+	}
+}
+
+func (h *MissionHandler) run() {
+	defer close(h.done)
+	for p := range h.queue {
+		body, err := json.Marshal(p)
+		if err != nil {
+			log.Printf("webhook: marshal %s payload: %v", p.Event, err)
+			continue
+		}
+		for _, hook := range h.hooks {
+			if !hook.Subscribed(p.Event) {
+				continue
+			}
+			if err := h.deliver(hook, p.Event, body); err != nil {
+				log.Printf("webhook '%s': %s: %v", hook.Name, p.Event, err)
+			}
+		}
+	}
+}
+
+// deliver posts body to the hook, retrying network errors, 429s, and 5xx
+// responses with exponential backoff.
+func (h *MissionHandler) deliver(hook config.Webhook, event string, body []byte) error {
+	deliveryID := fmt.Sprintf("%d-%d", time.Now().UnixNano(), atomic.AddUint64(&h.deliveries, 1))
+	maxRetries := hook.GetMaxRetries()
+
+	var lastErr error
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(retryBaseDelay << (attempt - 1))
+		}
+		retry, err := h.post(hook, event, deliveryID, body)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if !retry {
+			break
+		}
+	}
+	return lastErr
+}
+
+// post makes a single delivery attempt. The bool reports whether a failure
+// is worth retrying.
+func (h *MissionHandler) post(hook config.Webhook, event, deliveryID string, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range hook.Headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set(HeaderEvent, event)
+	req.Header.Set(HeaderDelivery, deliveryID)
+	if hook.Secret != "" {
+		req.Header.Set(HeaderSignature, Sign(hook.Secret, body))
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, fmt.Errorf("POST %s: %s", hook.URL, resp.Status)
+}
+
+// Sign returns the X-Squadron-Signature value for body: "sha256=" followed by
+// the hex HMAC-SHA256 of body keyed by secret. Receivers should recompute it
+// over the raw request body and compare with hmac.Equal.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
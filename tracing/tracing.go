@@ -0,0 +1,216 @@
+// Package tracing exports OpenTelemetry spans for missions, tasks,
+// iterations, agents, LLM calls, and tool calls.
+//
+// Instrumented code calls Start and End unconditionally. Until Configure
+// installs an exporter, spans come from a no-op provider and cost nothing.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// Supported exporters
+const (
+	ExporterOTLPGRPC = "otlp_grpc"
+	ExporterOTLPHTTP = "otlp_http"
+	ExporterFile     = "file"
+)
+
+const instrumentationName = "squadron"
+
+// Options selects where spans are exported. The zero value disables tracing.
+type Options struct {
+	Exporter    string            // otlp_grpc, otlp_http, or file; empty disables tracing
+	Endpoint    string            // collector host:port (OTLP); empty uses the OTEL_EXPORTER_OTLP_* env vars
+	Insecure    bool              // plain-text connection to the collector (OTLP)
+	Headers     map[string]string // extra request headers, e.g. for auth (OTLP)
+	Path        string            // output file for the file exporter (JSON lines)
+	ServiceName string            // service.name resource attribute (default "squadron")
+	SampleRatio float64           // fraction of new traces recorded (0 means 1)
+}
+
+var (
+	mu       sync.Mutex
+	current  Options
+	provider *sdktrace.TracerProvider
+	closer   func() error // closes the file exporter's output, if any
+
+	tracer trace.Tracer = noop.NewTracerProvider().Tracer(instrumentationName)
+)
+
+// propagator carries trace context across process boundaries (plugins).
+var propagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+
+// Configure installs the exporter described by opts, replacing any previous
+// one. Calling it again with the same options is a no-op, so every mission
+// run can call it with its config.
+func Configure(ctx context.Context, opts Options) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if provider != nil && reflect.DeepEqual(opts, current) {
+		return nil
+	}
+	if provider == nil && opts.Exporter == "" {
+		return nil
+	}
+	shutdownLocked(ctx)
+	if opts.Exporter == "" {
+		return nil
+	}
+
+	exporter, closeFn, err := newExporter(ctx, opts)
+	if err != nil {
+		return err
+	}
+	serviceName := opts.ServiceName
+	if serviceName == "" {
+		serviceName = "squadron"
+	}
+	ratio := opts.SampleRatio
+	if ratio <= 0 {
+		ratio = 1
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(attribute.String("service.name", serviceName)))
+	if err != nil {
+		return fmt.Errorf("tracing: resource: %w", err)
+	}
+
+	provider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+	)
+	closer = closeFn
+	current = opts
+	tracer = provider.Tracer(instrumentationName)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagator)
+	return nil
+}
+
+func newExporter(ctx context.Context, opts Options) (sdktrace.SpanExporter, func() error, error) {
+	switch opts.Exporter {
+	case ExporterOTLPGRPC:
+		var o []otlptracegrpc.Option
+		if opts.Endpoint != "" {
+			o = append(o, otlptracegrpc.WithEndpoint(opts.Endpoint))
+		}
+		if opts.Insecure {
+			o = append(o, otlptracegrpc.WithInsecure())
+		}
+		if len(opts.Headers) > 0 {
+			o = append(o, otlptracegrpc.WithHeaders(opts.Headers))
+		}
+		exp, err := otlptracegrpc.New(ctx, o...)
+		if err != nil {
+			return nil, nil, fmt.Errorf("tracing: otlp_grpc exporter: %w", err)
+		}
+		return exp, nil, nil
+	case ExporterOTLPHTTP:
+		var o []otlptracehttp.Option
+		if opts.Endpoint != "" {
+			o = append(o, otlptracehttp.WithEndpoint(opts.Endpoint))
+		}
+		if opts.Insecure {
+			o = append(o, otlptracehttp.WithInsecure())
+		}
+		if len(opts.Headers) > 0 {
+			o = append(o, otlptracehttp.WithHeaders(opts.Headers))
+		}
+		exp, err := otlptracehttp.New(ctx, o...)
+		if err != nil {
+			return nil, nil, fmt.Errorf("tracing: otlp_http exporter: %w", err)
+		}
+		return exp, nil, nil
+	case ExporterFile:
+		f, err := os.OpenFile(opts.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, nil, fmt.Errorf("tracing: file exporter: %w", err)
+		}
+		exp, err := stdouttrace.New(stdouttrace.WithWriter(f))
+		if err != nil {
+			f.Close()
+			return nil, nil, fmt.Errorf("tracing: file exporter: %w", err)
+		}
+		return exp, f.Close, nil
+	}
+	return nil, nil, fmt.Errorf("tracing: unknown exporter '%s'", opts.Exporter)
+}
+
+// shutdownLocked flushes and removes the installed provider. mu must be held.
+func shutdownLocked(ctx context.Context) {
+	if provider == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+	_ = provider.Shutdown(ctx)
+	if closer != nil {
+		_ = closer()
+	}
+	provider, closer, current = nil, nil, Options{}
+	tracer = noop.NewTracerProvider().Tracer(instrumentationName)
+	otel.SetTracerProvider(noop.NewTracerProvider())
+}
+
+// Shutdown flushes pending spans and disables tracing.
+func Shutdown(ctx context.Context) {
+	mu.Lock()
+	defer mu.Unlock()
+	shutdownLocked(ctx)
+}
+
+// Flush exports spans that are still buffered. Call it when a mission ends
+// so short-lived processes don't exit with spans unsent.
+func Flush(ctx context.Context) {
+	mu.Lock()
+	p := provider
+	mu.Unlock()
+	if p == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+	_ = p.ForceFlush(ctx)
+}
+
+// Start starts a span as a child of any span in ctx.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	mu.Lock()
+	t := tracer
+	mu.Unlock()
+	return t.Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records err on the span, if any, and ends it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Inject writes ctx's trace context into carrier, for calls that cross a
+// process boundary.
+func Inject(ctx context.Context, carrier propagation.TextMapCarrier) {
+	propagator.Inject(ctx, carrier)
+}
@@ -0,0 +1,98 @@
+package tracing
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// exportedSpan is the subset of the file exporter's JSON we check.
+type exportedSpan struct {
+	Name        string
+	SpanContext struct{ TraceID, SpanID string }
+	Parent      struct{ SpanID string }
+	Status      struct{ Code string }
+}
+
+func readSpans(t *testing.T, path string) map[string]exportedSpan {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	spans := make(map[string]exportedSpan)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 1<<20), 1<<20)
+	for scanner.Scan() {
+		var s exportedSpan
+		if err := json.Unmarshal(scanner.Bytes(), &s); err != nil {
+			t.Fatalf("bad span line %q: %v", scanner.Text(), err)
+		}
+		spans[s.Name] = s
+	}
+	return spans
+}
+
+func TestFileExporterNestsSpans(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "traces.jsonl")
+	ctx := context.Background()
+	if err := Configure(ctx, Options{Exporter: ExporterFile, Path: path}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { Shutdown(ctx) })
+
+	missionCtx, mission := Start(ctx, "mission m")
+	_, task := Start(missionCtx, "task t")
+	End(task, errors.New("boom"))
+	End(mission, nil)
+	Flush(ctx)
+
+	spans := readSpans(t, path)
+	m, ok := spans["mission m"]
+	if !ok {
+		t.Fatalf("mission span not exported: %v", spans)
+	}
+	tk, ok := spans["task t"]
+	if !ok {
+		t.Fatalf("task span not exported: %v", spans)
+	}
+	if tk.SpanContext.TraceID != m.SpanContext.TraceID || tk.Parent.SpanID != m.SpanContext.SpanID {
+		t.Fatalf("task span should be a child of the mission span: mission=%+v task=%+v", m, tk)
+	}
+	if tk.Status.Code != "Error" {
+		t.Fatalf("failed task span should have error status, got %q", tk.Status.Code)
+	}
+}
+
+func TestConfigureDisables(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "traces.jsonl")
+	ctx := context.Background()
+	if err := Configure(ctx, Options{Exporter: ExporterFile, Path: path}); err != nil {
+		t.Fatal(err)
+	}
+	// Same options again keep the running provider
+	if err := Configure(ctx, Options{Exporter: ExporterFile, Path: path}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Configure(ctx, Options{}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, span := Start(ctx, "after disable")
+	if span.IsRecording() {
+		t.Fatal("spans should not record once tracing is disabled")
+	}
+	End(span, nil)
+}
+
+func TestConfigureRejectsUnknownExporter(t *testing.T) {
+	if err := Configure(context.Background(), Options{Exporter: "zipkin"}); err == nil {
+		t.Fatal("expected an error for an unknown exporter")
+	}
+}
@@ -198,7 +198,7 @@ func ConfigToInstanceConfig(cfg *config.Config) protocol.InstanceConfig {
 		}
 		for _, toolName := range tools {
 			ref := "builtins." + namespace + "." + toolName
-			if tool := config.GetBuiltinTool(ref, nil, nil); tool != nil {
+			if tool := config.GetBuiltinTool(ref, config.BuiltinSettings{}, nil, nil); tool != nil {
 				ti := aitoolToProtocolToolInfo(tool)
 				ti.Name = toolName // Use config-level name, not legacy ToolName()
 				pi.Tools = append(pi.Tools, ti)
@@ -313,7 +313,9 @@ func convertMissionInput(inp config.MissionInput) protocol.MissionInputInfo {
 		info.Items = &items
 	}
 	for _, prop := range inp.Properties {
-		info.Properties = append(info.Properties, convertMissionInput(prop))
+		p := convertMissionInput(prop)
+		p.Required = prop.Required // properties are required only when declared so
+		info.Properties = append(info.Properties, p)
 	}
 	return info
 }
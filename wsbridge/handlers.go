@@ -3,6 +3,7 @@ package wsbridge
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -255,7 +256,11 @@ func (c *Client) handleResumeMission(env *protocol.Envelope) (*protocol.Envelope
 		if err != nil {
 			log.Printf("Resumed mission %q failed: %v", payload.MissionName, err)
 			status := "failed"
-			if missionCtx.Err() != nil {
+			if errors.Is(err, mission.ErrMissionPaused) {
+				status = "paused"
+			} else if errors.Is(err, mission.ErrMissionCancelled) {
+				status = "cancelled"
+			} else if missionCtx.Err() != nil {
 				status = "stopped"
 			}
 			c.cancelOpenHumanInputsForMission(mid, "[cancelled: mission "+status+"]")
@@ -1248,7 +1253,11 @@ func (c *Client) runMissionChain(ctx context.Context, cancel context.CancelFunc,
 		if err != nil {
 			log.Printf("Mission %q failed: %v", missionName, err)
 			status := "failed"
-			if ctx.Err() != nil {
+			if errors.Is(err, mission.ErrMissionPaused) {
+				status = "paused"
+			} else if errors.Is(err, mission.ErrMissionCancelled) {
+				status = "cancelled"
+			} else if ctx.Err() != nil {
 				status = "stopped"
 			}
 			c.cancelOpenHumanInputsForMission(mid, "[cancelled: mission "+status+"]")
@@ -1326,6 +1335,11 @@ func (c *Client) ResumeOrphanedMissions() {
 	}
 
 	for _, r := range records {
+		// A pause was requested but the process exited before acting on it
+		if r.Status == string(mission.MissionPausing) {
+			c.stores.Missions.UpdateMissionStatus(r.ID, string(mission.MissionPaused))
+			continue
+		}
 		if r.Status != "running" {
 			continue
 		}
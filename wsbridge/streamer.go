@@ -239,6 +239,36 @@ func (h *WSMissionHandler) MissionIssue(data streamers.MissionIssueData) {
 	h.sendEvent(streamers.EventMissionIssue, data)
 }
 
+// TaskApprovalRequested implements streamers.ApprovalHandler.
+func (h *WSMissionHandler) TaskApprovalRequested(data streamers.TaskApprovalRequestedData) {
+	h.sendEvent(streamers.EventTaskApprovalRequested, data)
+}
+
+// TaskApprovalResolved implements streamers.ApprovalHandler.
+func (h *WSMissionHandler) TaskApprovalResolved(data streamers.TaskApprovalResolvedData) {
+	h.sendEvent(streamers.EventTaskApprovalResolved, data)
+}
+
+// TaskCancelled implements streamers.CancelHandler.
+func (h *WSMissionHandler) TaskCancelled(data streamers.TaskCancelledData) {
+	h.sendEvent(streamers.EventTaskCancelled, data)
+}
+
+// MissionCancelled implements streamers.CancelHandler.
+func (h *WSMissionHandler) MissionCancelled(data streamers.MissionCancelledData) {
+	h.sendEvent(streamers.EventMissionCancelled, data)
+}
+
+// TaskSkipped implements streamers.TaskSkipHandler.
+func (h *WSMissionHandler) TaskSkipped(data streamers.TaskSkippedData) {
+	h.sendEvent(streamers.EventTaskSkipped, data)
+}
+
+// IterationProgress implements streamers.IterationProgressHandler.
+func (h *WSMissionHandler) IterationProgress(data streamers.IterationProgressData) {
+	h.sendEvent(streamers.EventIterationProgress, data)
+}
+
 func (h *WSMissionHandler) AgentStarted(taskName string, agentName string, instruction string) {
 	h.sendEvent(protocol.EventAgentStarted, protocol.AgentStartedData{
 		TaskName:    taskName,
@@ -324,6 +354,17 @@ func (c *wsChatHandler) ToolComplete(toolCallId string, toolName string, result
 	})
 }
 
+// ToolProgress implements streamers.ToolProgressHandler.
+func (c *wsChatHandler) ToolProgress(toolCallId string, toolName string, content string) {
+	c.parent.sendEvent(streamers.EventAgentToolProgress, streamers.AgentToolProgressData{
+		TaskName:   c.taskName,
+		AgentName:  c.agentName,
+		ToolCallId: toolCallId,
+		ToolName:   toolName,
+		Content:    content,
+	})
+}
+
 func (c *wsChatHandler) PublishReasoningChunk(chunk string) {
 	c.reasoningBuf.WriteString(chunk)
 }
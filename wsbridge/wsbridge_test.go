@@ -395,9 +395,9 @@ func TestConfigConversion(t *testing.T) {
 		t.Errorf("expected agent 'agent1', got %q", ic.Missions[0].Tasks[0].Agent)
 	}
 
-	// 3 builtin tool namespaces (http, utils, human) + 1 external plugin
-	if len(ic.Plugins) != 4 {
-		t.Fatalf("expected 4 plugins, got %d", len(ic.Plugins))
+	// 5 builtin tool namespaces (http, utils, human, shell, sql) + 1 external plugin
+	if len(ic.Plugins) != 6 {
+		t.Fatalf("expected 6 plugins, got %d", len(ic.Plugins))
 	}
 
 	if len(ic.Variables) != 2 {